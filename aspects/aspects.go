@@ -130,6 +130,12 @@ type Schema interface {
 	Validate(data []byte) error
 }
 
+// valueAtValidator is implemented by schemas that can validate a single value
+// at a path, without the rest of the document.
+type valueAtValidator interface {
+	ValidateValueAt(path []string, raw []byte) error
+}
+
 // Bundle holds a series of related aspects.
 type Bundle struct {
 	Account string
@@ -202,11 +208,20 @@ func newAspect(bundle *Bundle, name string, aspectPatterns []map[string]string)
 			readRequests[request] = true
 		}
 
-		accPattern, err := newAccessPattern(request, storage, aspectPattern["access"])
+		accPattern, err := newAccessPattern(request, storage, aspectPattern["access"], aspectPattern["transform"])
 		if err != nil {
 			return nil, err
 		}
 
+		if schema, ok := bundle.schema.(*StorageSchema); ok && accPattern.transform != nil {
+			// the transform must store values the storage accepts
+			for _, storageSchema := range schema.schemasAt(strings.Split(storage, ".")) {
+				if err := accPattern.transform.checkStorageSchema(storageSchema); err != nil {
+					return nil, fmt.Errorf(`cannot transform values stored in %q: %w`, storage, err)
+				}
+			}
+		}
+
 		aspect.accessPatterns = append(aspect.accessPatterns, accPattern)
 	}
 
@@ -340,7 +355,7 @@ func (a *Aspect) Set(databag DataBag, request string, value interface{}) error {
 			return err
 		}
 
-		matches = append(matches, requestMatch{storagePath: path, suffixParts: suffixParts})
+		matches = append(matches, requestMatch{storagePath: path, suffixParts: suffixParts, transform: accessPatt.transform})
 	}
 
 	if len(matches) == 0 {
@@ -367,10 +382,36 @@ func (a *Aspect) Set(databag DataBag, request string, value interface{}) error {
 			}
 		}
 
+		// nil values unset the entry so there's nothing to transform
+		if match.transform != nil && nestedValue != nil {
+			var err error
+			nestedValue, err = match.transform.toStorage(nestedValue)
+			if err != nil {
+				return badRequestErrorFrom(a, "set", request, `cannot transform value: %v`, err)
+			}
+		}
+
+		pathValidator, ok := a.bundle.schema.(valueAtValidator)
+		if ok {
+			rawValue, err := json.Marshal(nestedValue)
+			if err != nil {
+				return err
+			}
+
+			if err := pathValidator.ValidateValueAt(strings.Split(match.storagePath, "."), rawValue); err != nil {
+				return fmt.Errorf(`cannot write data: %w`, err)
+			}
+		}
+
 		if err := databag.Set(match.storagePath, nestedValue); err != nil {
 			return err
 		}
 
+		// transactions validate the whole document when committed
+		if _, isTx := databag.(*Transaction); isTx && pathValidator != nil {
+			continue
+		}
+
 		data, err := databag.Data()
 		if err != nil {
 			return err
@@ -386,9 +427,17 @@ func (a *Aspect) Set(databag DataBag, request string, value interface{}) error {
 
 // namespaceResult creates a nested namespace around the result that corresponds
 // to the unmatched entry parts. Unmatched placeholders are filled in using maps
-// of all the matching values in the databag.
-func namespaceResult(res interface{}, suffixParts []string) (interface{}, error) {
+// of all the matching values in the databag. If the entry has a transform, it's
+// applied to the values the entry maps to.
+func namespaceResult(res interface{}, suffixParts []string, transform valueTransform) (interface{}, error) {
 	if len(suffixParts) == 0 {
+		if transform != nil {
+			val, err := transform.fromStorage(res)
+			if err != nil {
+				return nil, fmt.Errorf("cannot transform stored value: %w", err)
+			}
+			return val, nil
+		}
 		return res, nil
 	}
 
@@ -403,7 +452,7 @@ func namespaceResult(res interface{}, suffixParts []string) (interface{}, error)
 
 		level := make(map[string]interface{}, len(values))
 		for k, v := range values {
-			nested, err := namespaceResult(v, suffixParts[1:])
+			nested, err := namespaceResult(v, suffixParts[1:], transform)
 			if err != nil {
 				return nil, err
 			}
@@ -414,7 +463,7 @@ func namespaceResult(res interface{}, suffixParts []string) (interface{}, error)
 		return level, nil
 	}
 
-	nested, err := namespaceResult(res, suffixParts[1:])
+	nested, err := namespaceResult(res, suffixParts[1:], transform)
 	if err != nil {
 		return nil, err
 	}
@@ -445,7 +494,7 @@ func (a *Aspect) Get(databag DataBag, request string) (map[string]interface{}, e
 		}
 
 		// build a namespace around the result based on the unmatched suffix parts
-		val, err = namespaceResult(val, match.suffixParts)
+		val, err = namespaceResult(val, match.suffixParts, match.transform)
 		if err != nil {
 			return nil, err
 		}
@@ -505,6 +554,10 @@ type requestMatch struct {
 	// suffixParts contains the nested suffix of the entry's request that wasn't
 	// matched by the request.
 	suffixParts []string
+
+	// transform converts the values of the entry between their request and
+	// storage forms, if set.
+	transform valueTransform
 }
 
 // matchGetRequest either returns the first exact match for the request or, if
@@ -527,7 +580,7 @@ func (a *Aspect) matchGetRequest(request string) (matches []requestMatch, err er
 			continue
 		}
 
-		m := requestMatch{storagePath: path, suffixParts: restSuffix}
+		m := requestMatch{storagePath: path, suffixParts: restSuffix, transform: accessPatt.transform}
 		matches = append(matches, m)
 	}
 
@@ -554,12 +607,51 @@ func (a *Aspect) matchGetRequest(request string) (matches []requestMatch, err er
 	return matches, nil
 }
 
-func newAccessPattern(request, storage, accesstype string) (*accessPattern, error) {
+// CompleteRequest returns the requests that complete the last subkey of the
+// partial dotted request, for use in interactive completion. The subkeys are
+// derived from the aspect's access patterns and, under the storage paths they
+// map to, from the keys defined in the schema.
+func (a *Aspect) CompleteRequest(partial string) ([]string, error) {
+	subkeys := strings.Split(partial, ".")
+	complete, prefix := subkeys[:len(subkeys)-1], subkeys[len(subkeys)-1]
+	if len(complete) > 0 {
+		if err := validateAspectDottedPath(strings.Join(complete, "."), nil); err != nil {
+			return nil, badRequestErrorFrom(a, "complete", partial, err.Error())
+		}
+	}
+
+	schema, _ := a.bundle.schema.(*StorageSchema)
+	candidates := make(map[string]bool)
+	for _, accessPatt := range a.accessPatterns {
+		for _, key := range accessPatt.nextSubkeys(complete, schema) {
+			if strings.HasPrefix(key, prefix) {
+				candidates[key] = true
+			}
+		}
+	}
+
+	completions := make([]string, 0, len(candidates))
+	for key := range candidates {
+		completions = append(completions, strings.Join(append(complete[:len(complete):len(complete)], key), "."))
+	}
+	sort.Strings(completions)
+	return completions, nil
+}
+
+func newAccessPattern(request, storage, accesstype, transform string) (*accessPattern, error) {
 	accType, err := newAccessType(accesstype)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create aspect pattern: %w", err)
 	}
 
+	var valTransform valueTransform
+	if transform != "" {
+		valTransform, err = newValueTransform(transform)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create aspect pattern: %w", err)
+		}
+	}
+
 	requestSubkeys := strings.Split(request, ".")
 	requestMatchers := make([]requestMatcher, 0, len(requestSubkeys))
 	for _, subkey := range requestSubkeys {
@@ -591,6 +683,7 @@ func newAccessPattern(request, storage, accesstype string) (*accessPattern, erro
 		request:         requestMatchers,
 		storage:         pathWriters,
 		access:          accType,
+		transform:       valTransform,
 	}, nil
 }
 
@@ -606,6 +699,7 @@ type accessPattern struct {
 	request         []requestMatcher
 	storage         []storageWriter
 	access          accessType
+	transform       valueTransform
 }
 
 // match returns true if the subkeys match the pattern exactly or as a prefix.
@@ -630,6 +724,65 @@ func (p *accessPattern) match(reqSubkeys []string) (placeholders map[string]stri
 	return placeholders, restSuffix, true
 }
 
+// nextSubkeys returns the subkeys that can follow the complete subkeys of a
+// request matching the pattern. Placeholders in the pattern, and the subkeys
+// nested under its storage path, are filled in from the schema, if it's set.
+func (p *accessPattern) nextSubkeys(complete []string, schema *StorageSchema) []string {
+	placeholders := make(map[string]string)
+	for i, subkey := range complete {
+		if i == len(p.request) {
+			break
+		}
+		if !p.request[i].match(subkey, placeholders) {
+			return nil
+		}
+	}
+
+	if len(complete) < len(p.request) {
+		next := p.request[len(complete)]
+		if lit, ok := next.(literal); ok {
+			return []string{string(lit)}
+		}
+
+		// complete the placeholder with the keys under the storage path
+		// leading up to it
+		if schema == nil {
+			return nil
+		}
+		var storagePath []string
+		for _, subkey := range p.storage {
+			switch subkey := subkey.(type) {
+			case literal:
+				storagePath = append(storagePath, string(subkey))
+			case placeholder:
+				if subkey == next {
+					return schema.keysAt(storagePath)
+				}
+
+				value, ok := placeholders[string(subkey)]
+				if !ok {
+					return nil
+				}
+				storagePath = append(storagePath, value)
+			}
+		}
+		return nil
+	}
+
+	// the request is nested under the pattern's storage, whose structure is
+	// unknown if its values are transformed
+	if schema == nil || p.transform != nil {
+		return nil
+	}
+
+	storagePath, err := p.storagePath(placeholders)
+	if err != nil {
+		return nil
+	}
+	path := append(strings.Split(storagePath, "."), complete[len(p.request):]...)
+	return schema.keysAt(path)
+}
+
 // storagePath takes a map of placeholders to their values in the aspect name and
 // returns the path with its placeholder values filled in with the map's values.
 func (p *accessPattern) storagePath(placeholders map[string]string) (string, error) {
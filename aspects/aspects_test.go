@@ -1285,3 +1285,418 @@ func (s *aspectSuite) TestSetOverwriteValueWithNewLevel(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(data, DeepEquals, map[string]interface{}{"bar": "baz"})
 }
+
+func (s *aspectSuite) TestTransformMap(c *C) {
+	schema, err := aspects.ParseSchema([]byte(`{
+	"schema": {
+		"wifi": {
+			"schema": {
+				"security": {
+					"type": "int",
+					"choices": [0, 2, 3]
+				}
+			}
+		}
+	}
+}`))
+	c.Assert(err, IsNil)
+
+	databag := aspects.NewJSONDataBag()
+	aspectBundle, err := aspects.NewAspectBundle("acc", "bundle", map[string]interface{}{
+		"foo": []map[string]string{
+			{"request": "security", "storage": "wifi.security", "transform": "map:open=0,wpa2=2,wpa3=3"},
+			{"request": "all", "storage": "wifi", "access": "read"},
+		},
+	}, schema)
+	c.Assert(err, IsNil)
+
+	asp := aspectBundle.Aspect("foo")
+	err = asp.Set(databag, "security", "wpa2")
+	c.Assert(err, IsNil)
+
+	value, err := databag.Get("wifi.security")
+	c.Assert(err, IsNil)
+	c.Check(value, Equals, float64(2))
+
+	res, err := asp.Get(databag, "security")
+	c.Assert(err, IsNil)
+	c.Check(res, DeepEquals, map[string]interface{}{"security": "wpa2"})
+
+	// the rules without transforms expose the stored values
+	res, err = asp.Get(databag, "all")
+	c.Assert(err, IsNil)
+	c.Check(res, DeepEquals, map[string]interface{}{"all": map[string]interface{}{"security": float64(2)}})
+
+	err = asp.Set(databag, "security", "wep")
+	c.Assert(err, ErrorMatches, `cannot set "security" in aspect acc/bundle/foo: cannot transform value: value wep is not one of the mapped values`)
+
+	// stored values must be mapped to be read
+	c.Assert(databag.Set("wifi.security", 1), IsNil)
+	_, err = asp.Get(databag, "security")
+	c.Assert(err, ErrorMatches, `cannot transform stored value: stored value 1 is not one of the mapped values`)
+
+	// unsetting doesn't transform
+	err = asp.Set(databag, "security", nil)
+	c.Assert(err, IsNil)
+	_, err = databag.Get("wifi.security")
+	c.Assert(err, testutil.ErrorIs, aspects.PathError(""))
+}
+
+func (s *aspectSuite) TestSetValidatesValueAtStoragePath(c *C) {
+	schema, err := aspects.ParseSchema([]byte(`{
+	"schema": {
+		"snaps": {
+			"values": {
+				"schema": {
+					"name": "string",
+					"revision": "int"
+				},
+				"required": ["name"]
+			},
+			"max-entries": 1
+		}
+	}
+}`))
+	c.Assert(err, IsNil)
+
+	aspectBundle, err := aspects.NewAspectBundle("acc", "bundle", map[string]interface{}{
+		"foo": []map[string]string{
+			{"request": "snaps.{snap}.name", "storage": "snaps.{snap}.name"},
+			{"request": "snaps.{snap}.revision", "storage": "snaps.{snap}.revision"},
+		},
+	}, schema)
+	c.Assert(err, IsNil)
+	asp := aspectBundle.Aspect("foo")
+
+	witness := &witnessReadWriter{bag: aspects.NewJSONDataBag()}
+	tx, err := aspects.NewTransaction(witness.read, witness.write, schema)
+	c.Assert(err, IsNil)
+
+	err = asp.Set(tx, "snaps.core.revision", "1")
+	c.Assert(err, ErrorMatches, `cannot write data: cannot accept element in "snaps.core.revision": expected int type but got string`)
+
+	err = asp.Set(tx, "snaps.core.name", nil)
+	c.Assert(err, ErrorMatches, `cannot write data: cannot accept element in "snaps.core": cannot unset required key "name"`)
+
+	// constraints involving other entries are checked when committing
+	c.Assert(asp.Set(tx, "snaps.core.name", "core"), IsNil)
+	c.Assert(asp.Set(tx, "snaps.snapd.name", "snapd"), IsNil)
+
+	err = tx.Commit()
+	c.Assert(err, ErrorMatches, `cannot accept element in "snaps": cannot accept map with more than 1 entries`)
+	c.Check(witness.writeCalled, Equals, 0)
+}
+
+func (s *aspectSuite) TestTransformValidatedAgainstStorageSchema(c *C) {
+	schema, err := aspects.ParseSchema([]byte(`{
+	"schema": {
+		"security": {
+			"type": "int",
+			"choices": [0, 2, 3]
+		},
+		"name": "string",
+		"timeout": ["string", "int"],
+		"services": {
+			"values": {
+				"schema": {
+					"enabled": "bool"
+				}
+			}
+		}
+	}
+}`))
+	c.Assert(err, IsNil)
+
+	for _, tc := range []struct {
+		request   string
+		storage   string
+		transform string
+		err       string
+	}{
+		{storage: "security", transform: `map:open=0,wpa2=2,wpa3=3`},
+		{storage: "name", transform: `map:a="0",b=b`},
+		{storage: "timeout", transform: "iso-duration"},
+		{request: "enabled.{name}", storage: "services.{name}.enabled", transform: `map:yes=true,no=false`},
+		// values unknown to the schema are unchecked
+		{storage: "other", transform: "iso-duration"},
+		{
+			// string to int
+			storage:   "security",
+			transform: `map:open=0,wpa2=2,"2"=four`,
+			err:       `cannot transform values stored in "security": cannot map to storage value "four": expected int type but got string`,
+		},
+		{
+			storage:   "security",
+			transform: `map:open=0,wpa2=1`,
+			err:       `cannot transform values stored in "security": cannot map to storage value 1: 1 is not one of the allowed choices`,
+		},
+		{
+			// int to string
+			storage:   "name",
+			transform: `map:a=1,b=b`,
+			err:       `cannot transform values stored in "name": cannot map to storage value 1: expected string type but got number`,
+		},
+		{
+			request:   "enabled.{name}",
+			storage:   "services.{name}.enabled",
+			transform: `map:yes=1,no=0`,
+			err:       `cannot transform values stored in "services.{name}.enabled": cannot map to storage value 0: expected bool type but got number`,
+		},
+		{
+			storage:   "name",
+			transform: "iso-duration",
+			err:       `cannot transform values stored in "name": "iso-duration" transform must store an integer number of seconds`,
+		},
+	} {
+		if tc.request == "" {
+			tc.request = "foo"
+		}
+		_, err := aspects.NewAspectBundle("acc", "bundle", map[string]interface{}{
+			"foo": []map[string]string{
+				{"request": tc.request, "storage": tc.storage, "transform": tc.transform},
+			},
+		}, schema)
+		if tc.err == "" {
+			c.Check(err, IsNil, Commentf("%s: %s", tc.storage, tc.transform))
+		} else {
+			c.Assert(err, NotNil, Commentf("%s: %s", tc.storage, tc.transform))
+			c.Check(err.Error(), Equals, `cannot define aspect "foo": `+tc.err)
+		}
+	}
+}
+
+func (s *aspectSuite) TestTransformMapWithPlaceholders(c *C) {
+	databag := aspects.NewJSONDataBag()
+	aspectBundle, err := aspects.NewAspectBundle("acc", "bundle", map[string]interface{}{
+		"foo": []map[string]string{
+			{"request": "enabled.{name}", "storage": "services.{name}.enabled", "transform": "map:true=yes,false=no"},
+		},
+	}, aspects.NewJSONSchema())
+	c.Assert(err, IsNil)
+
+	asp := aspectBundle.Aspect("foo")
+	c.Assert(asp.Set(databag, "enabled.ssh", true), IsNil)
+	c.Assert(asp.Set(databag, "enabled.cups", false), IsNil)
+
+	value, err := databag.Get("services.ssh.enabled")
+	c.Assert(err, IsNil)
+	c.Check(value, Equals, "yes")
+
+	res, err := asp.Get(databag, "enabled")
+	c.Assert(err, IsNil)
+	c.Check(res, DeepEquals, map[string]interface{}{
+		"enabled": map[string]interface{}{"ssh": true, "cups": false},
+	})
+}
+
+func (s *aspectSuite) TestTransformISODuration(c *C) {
+	databag := aspects.NewJSONDataBag()
+	aspectBundle, err := aspects.NewAspectBundle("acc", "bundle", map[string]interface{}{
+		"foo": []map[string]string{
+			{"request": "timeout", "storage": "timeout-secs", "transform": "iso-duration"},
+		},
+	}, aspects.NewJSONSchema())
+	c.Assert(err, IsNil)
+
+	asp := aspectBundle.Aspect("foo")
+
+	type testcase struct {
+		duration string
+		seconds  float64
+		readBack string
+	}
+
+	for _, tc := range []testcase{
+		{duration: "PT0S", seconds: 0},
+		{duration: "PT45S", seconds: 45},
+		{duration: "PT1H30M", seconds: 5400},
+		{duration: "PT90M", seconds: 5400, readBack: "PT1H30M"},
+		{duration: "P1D", seconds: 86400},
+		{duration: "P2DT3H4M5S", seconds: 183845},
+	} {
+		cmt := Commentf("duration: %s", tc.duration)
+		c.Assert(asp.Set(databag, "timeout", tc.duration), IsNil, cmt)
+
+		value, err := databag.Get("timeout-secs")
+		c.Assert(err, IsNil, cmt)
+		c.Check(value, Equals, tc.seconds, cmt)
+
+		if tc.readBack == "" {
+			tc.readBack = tc.duration
+		}
+		res, err := asp.Get(databag, "timeout")
+		c.Assert(err, IsNil, cmt)
+		c.Check(res, DeepEquals, map[string]interface{}{"timeout": tc.readBack}, cmt)
+	}
+
+	for _, bad := range []string{"", "P", "PT", "P1H", "1H", "PT1.5S", "PT-1S", "P1DT"} {
+		err := asp.Set(databag, "timeout", bad)
+		c.Check(err, ErrorMatches, fmt.Sprintf(`cannot set "timeout" in aspect acc/bundle/foo: cannot transform value: cannot parse %q as an ISO 8601 duration`, bad))
+	}
+
+	err = asp.Set(databag, "timeout", 10)
+	c.Check(err, ErrorMatches, `cannot set "timeout" in aspect acc/bundle/foo: cannot transform value: expected ISO 8601 duration string but got int`)
+
+	for _, stored := range []interface{}{-1, 1.5} {
+		c.Assert(databag.Set("timeout-secs", stored), IsNil)
+		_, err = asp.Get(databag, "timeout")
+		c.Check(err, ErrorMatches, `cannot transform stored value: stored value .* is not a non-negative integer number of seconds`)
+	}
+
+	c.Assert(databag.Set("timeout-secs", "10"), IsNil)
+	_, err = asp.Get(databag, "timeout")
+	c.Check(err, ErrorMatches, `cannot transform stored value: expected stored number of seconds but got string`)
+}
+
+func (s *aspectSuite) TestTransformDefinitionErrors(c *C) {
+	for _, tc := range []struct {
+		transform string
+		err       string
+	}{
+		{transform: "foo", err: `unknown transform "foo"`},
+		{transform: "map:", err: `cannot have empty "map" transform`},
+		{transform: "map:a=1,b", err: `cannot parse "map" transform entry "b": must be <request>=<storage>`},
+		{transform: "map:a=", err: `cannot parse "map" transform entry "a=": must be <request>=<storage>`},
+		{transform: "map:a=1,a=2", err: `cannot map request value "a" more than once`},
+		{transform: "map:a=1,b=1.0", err: `cannot map storage value 1 more than once`},
+	} {
+		_, err := aspects.NewAspectBundle("acc", "bundle", map[string]interface{}{
+			"foo": []map[string]string{
+				{"request": "a", "storage": "b", "transform": tc.transform},
+			},
+		}, aspects.NewJSONSchema())
+		c.Check(err, ErrorMatches, `cannot define aspect "foo": cannot create aspect pattern: `+tc.err, Commentf("transform: %s", tc.transform))
+	}
+}
+
+func (s *aspectSuite) TestCompleteRequest(c *C) {
+	schema, err := aspects.ParseSchema([]byte(`{
+	"types": {
+		"network": {
+			"schema": {
+				"ssid": "string",
+				"security": {
+					"type": "string",
+					"choices": ["open", "wpa2"]
+				},
+				"options": {
+					"keys": {
+						"type": "string",
+						"choices": ["hidden", "metered"]
+					},
+					"values": "bool"
+				}
+			}
+		}
+	},
+	"schema": {
+		"wifi": {
+			"schema": {
+				"networks": {
+					"keys": "string",
+					"values": "$network"
+				},
+				"default": "$network",
+				"status": "string"
+			}
+		},
+		"timeout": "int"
+	}
+}`))
+	c.Assert(err, IsNil)
+
+	aspectBundle, err := aspects.NewAspectBundle("acc", "bundle", map[string]interface{}{
+		"foo": []map[string]string{
+			{"request": "networks.{name}", "storage": "wifi.networks.{name}"},
+			{"request": "default", "storage": "wifi.default"},
+			{"request": "default-ssid", "storage": "wifi.default.ssid", "access": "write"},
+			{"request": "status", "storage": "wifi.status", "access": "read"},
+			{"request": "settings.{key}", "storage": "wifi.{key}"},
+			{"request": "timeout", "storage": "timeout", "transform": "iso-duration"},
+		},
+	}, schema)
+	c.Assert(err, IsNil)
+	asp := aspectBundle.Aspect("foo")
+
+	type testcase struct {
+		partial     string
+		completions []string
+	}
+
+	for _, tc := range []testcase{
+		{
+			partial:     "",
+			completions: []string{"default", "default-ssid", "networks", "settings", "status", "timeout"},
+		},
+		{
+			partial:     "def",
+			completions: []string{"default", "default-ssid"},
+		},
+		{
+			partial:     "default.",
+			completions: []string{"default.options", "default.security", "default.ssid"},
+		},
+		{
+			partial:     "default.s",
+			completions: []string{"default.security", "default.ssid"},
+		},
+		{
+			partial:     "default.options.",
+			completions: []string{"default.options.hidden", "default.options.metered"},
+		},
+		{
+			// placeholders are completed from the storage schema
+			partial:     "settings.",
+			completions: []string{"settings.default", "settings.networks", "settings.status"},
+		},
+		{
+			// but the keys of maps aren't known
+			partial:     "networks.",
+			completions: []string{},
+		},
+		{
+			partial:     "networks.home.",
+			completions: []string{"networks.home.options", "networks.home.security", "networks.home.ssid"},
+		},
+		{
+			partial:     "settings.default.opt",
+			completions: []string{"settings.default.options"},
+		},
+		{
+			partial:     "status.",
+			completions: []string{},
+		},
+		{
+			partial:     "foo.",
+			completions: []string{},
+		},
+	} {
+		completions, err := asp.CompleteRequest(tc.partial)
+		c.Assert(err, IsNil, Commentf("partial: %q", tc.partial))
+		c.Check(completions, DeepEquals, tc.completions, Commentf("partial: %q", tc.partial))
+	}
+
+	_, err = asp.CompleteRequest("foo..bar")
+	c.Assert(err, ErrorMatches, `cannot complete "foo..bar" in aspect acc/bundle/foo: cannot have empty subkeys`)
+	c.Assert(err, testutil.ErrorIs, &aspects.BadRequestError{})
+}
+
+func (s *aspectSuite) TestCompleteRequestWithoutStorageSchema(c *C) {
+	aspectBundle, err := aspects.NewAspectBundle("acc", "bundle", map[string]interface{}{
+		"foo": []map[string]string{
+			{"request": "ssid", "storage": "wifi.ssid"},
+			{"request": "private.{key}", "storage": "wifi.{key}"},
+			{"request": "private.status", "storage": "wifi.status"},
+		},
+	}, aspects.NewJSONSchema())
+	c.Assert(err, IsNil)
+	asp := aspectBundle.Aspect("foo")
+
+	completions, err := asp.CompleteRequest("")
+	c.Assert(err, IsNil)
+	c.Check(completions, DeepEquals, []string{"private", "ssid"})
+
+	completions, err = asp.CompleteRequest("private.")
+	c.Assert(err, IsNil)
+	c.Check(completions, DeepEquals, []string{"private.status"})
+}
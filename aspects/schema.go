@@ -20,11 +20,18 @@
 package aspects
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/snapcore/snapd/strutil"
 )
@@ -66,50 +73,247 @@ func ParseSchema(raw []byte) (*StorageSchema, error) {
 	}
 
 	schema := new(StorageSchema)
+	if err := schema.parseVersion(schemaDef); err != nil {
+		return nil, err
+	}
+
 	if val, ok := schemaDef["types"]; ok {
 		var userTypes map[string]json.RawMessage
 		if err := json.Unmarshal(val, &userTypes); err != nil {
 			return nil, fmt.Errorf(`cannot parse user-defined types map: %w`, err)
 		}
 
-		// TODO: if we want to allow user types to refer to others, this must be handled
-		// explicitly since userTypes will not preserve any order in the serialized JSON
+		// user types can refer to each other and to themselves, so they're
+		// only parsed once all of them are known
+		names := make([]string, 0, len(userTypes))
 		schema.userTypes = make(map[string]*userTypeRefParser, len(userTypes))
 		for userTypeName, typeDef := range userTypes {
 			if !validUserType.Match([]byte(userTypeName)) {
 				return nil, fmt.Errorf(`cannot parse user-defined type name %q: must match %s`, userTypeName, validUserType)
 			}
 
-			userTypeSchema, err := schema.parse(typeDef)
+			params, def, err := parseTypeParameters(typeDef)
 			if err != nil {
 				return nil, fmt.Errorf(`cannot parse user-defined type %q: %w`, userTypeName, err)
 			}
 
-			schema.userTypes[userTypeName] = newUserTypeRefParser(userTypeSchema)
+			names = append(names, userTypeName)
+			schema.userTypes[userTypeName] = &userTypeRefParser{name: userTypeName, def: def, params: params}
+		}
+
+		// parse in a fixed order so errors are consistent
+		sort.Strings(names)
+		for _, name := range names {
+			userType := schema.userTypes[name]
+			if len(userType.params) > 0 {
+				// parsed for each instance binding its parameters
+				continue
+			}
+			if err := schema.resolveUserType(userType); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, name := range names {
+			userType := schema.userTypes[name]
+			if userType.parser != nil && referencesWithoutNesting(userType.parser, userType, make(map[*userTypeRefParser]bool)) {
+				return nil, fmt.Errorf(`cannot parse user-defined type %q: recursive reference must be nested in a map or array`, name)
+			}
 		}
 	}
 
-	schema.topLevel, err = schema.parse(raw)
+	// the top level entries aren't constraints of the top level map
+	for _, key := range []string{"schema-version", "min-compatible-version", "types"} {
+		delete(schemaDef, key)
+	}
+	topLevelDef, err := json.Marshal(schemaDef)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse top level schema: %w", err)
+	}
+
+	schema.topLevel, err = schema.parse(topLevelDef)
 	if err != nil {
 		return nil, err
 	}
 
+	// examples can only be validated once all the types they may use are
+	// fully parsed
+	for _, example := range schema.examples {
+		if err := example.schema.Validate(example.value); err != nil {
+			err = fmt.Errorf(`cannot parse %s: %w`, example.desc, err)
+			if example.userType != "" {
+				err = fmt.Errorf(`cannot parse user-defined type %q: %w`, example.userType, err)
+			}
+			return nil, err
+		}
+	}
+
 	return schema, nil
 }
 
+// schemaVersion is the latest version of the schema format that can be parsed.
+const schemaVersion = 1
+
+// parseVersion parses the optional "schema-version" and "min-compatible-version"
+// entries of the top level schema. A schema declaring a version that is
+// supported must only use known constraints, while a schema declaring a newer
+// version is parsed ignoring unknown constraints, as long as its minimum
+// compatible version is supported. Schemas that don't declare a version keep
+// ignoring unknown constraints.
+func (s *StorageSchema) parseVersion(schemaDef map[string]json.RawMessage) error {
+	var version, minCompatible int
+	for _, entry := range []struct {
+		name  string
+		value *int
+	}{{"schema-version", &version}, {"min-compatible-version", &minCompatible}} {
+		rawVal, ok := schemaDef[entry.name]
+		if !ok {
+			continue
+		}
+
+		if err := json.Unmarshal(rawVal, entry.value); err != nil {
+			return fmt.Errorf(`cannot parse top level schema's %q entry: %w`, entry.name, err)
+		}
+
+		if *entry.value <= 0 {
+			return fmt.Errorf(`cannot parse top level schema: %q must be a positive integer`, entry.name)
+		}
+	}
+
+	if minCompatible != 0 {
+		if version == 0 {
+			return fmt.Errorf(`cannot parse top level schema: cannot have "min-compatible-version" without "schema-version"`)
+		}
+
+		if minCompatible > version {
+			return fmt.Errorf(`cannot parse top level schema: "min-compatible-version" %d is greater than "schema-version" %d`, minCompatible, version)
+		}
+
+		if minCompatible > schemaVersion {
+			return fmt.Errorf(`cannot parse top level schema: requires schema version %d but only up to %d is supported`, minCompatible, schemaVersion)
+		}
+	}
+
+	s.rejectUnknown = version != 0 && version <= schemaVersion
+	return nil
+}
+
+// commonConstraints are the constraints that can be used with any type.
+var commonConstraints = []string{"type", "default", "sensitive", "nullable", "example", "examples"}
+
+// knownConstraints returns the constraints that the parser understands, other
+// than the common ones.
+func knownConstraints(p parser) []string {
+	switch p := p.(type) {
+	case *userTypeRefParser:
+		if p.parser == nil {
+			// a recursive reference to a type still being parsed
+			return nil
+		}
+		return knownConstraints(p.parser)
+	case *sensitiveSchema:
+		return knownConstraints(p.parser)
+	case *defaultSchema:
+		return knownConstraints(p.parser)
+	case *nullableSchema:
+		return knownConstraints(p.parser)
+	case *mapSchema:
+		return []string{"schema", "required", "additional-keys", "keys", "values", "min-entries", "max-entries"}
+	case *stringSchema:
+		return []string{"choices", "pattern", "format", "min-length", "max-length", "const"}
+	case *intSchema, *numberSchema:
+		return []string{"choices", "min", "max", "exclusive-min", "exclusive-max", "multiple-of", "const"}
+	case *decimalSchema:
+		return []string{"precision", "scale", "min", "max"}
+	case *datetimeSchema, *durationSchema:
+		return []string{"min", "max"}
+	case *enumSchema:
+		return []string{"choices"}
+	case *booleanSchema:
+		return []string{"const"}
+	case *arraySchema:
+		return []string{"values", "items", "unique", "min-len", "max-len"}
+	case *altSchema:
+		return []string{"alternatives"}
+	default:
+		return nil
+	}
+}
+
+// checkUnknownConstraints returns an error if the type definition has a
+// constraint that its type doesn't understand.
+func checkUnknownConstraints(typ string, p parser, schemaDef map[string]json.RawMessage) error {
+	known := knownConstraints(p)
+	names := make([]string, 0, len(schemaDef))
+	for name := range schemaDef {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !strutil.ListContains(commonConstraints, name) && !strutil.ListContains(known, name) {
+			return fmt.Errorf(`cannot parse %q: unknown constraint %q`, typ, name)
+		}
+	}
+	return nil
+}
+
+// maxRecursiveDepth is the maximum nesting depth of the values validated by
+// schemas with recursive user-defined types.
+const maxRecursiveDepth = 64
+
 // userTypeRefParser parses references to user-defined types (e.g., $my-type).
 type userTypeRefParser struct {
 	parser
 
+	name string
+	// def is the definition of the type. It's parsed when the type is first
+	// referenced, so types can be referenced before they're defined.
+	def json.RawMessage
+	// resolving is true while the definition is being parsed.
+	resolving bool
+	// params are the placeholders of a parameterized type. Such a type is
+	// only used through instances (e.g., $my-type[string]) which bind them
+	// to types.
+	params []string
+
 	stringBased bool
 }
 
-func newUserTypeRefParser(p parser) *userTypeRefParser {
-	_, ok := p.(*stringSchema)
-	return &userTypeRefParser{
-		parser:      p,
-		stringBased: ok,
+func (u *userTypeRefParser) setParser(p parser) {
+	base := p
+	if sensitive, ok := base.(*sensitiveSchema); ok {
+		base = sensitive.parser
+	}
+	if withDefault, ok := base.(*defaultSchema); ok {
+		base = withDefault.parser
+	}
+	if nullable, ok := base.(*nullableSchema); ok {
+		base = nullable.parser
+	}
+	_, ok := base.(*stringSchema)
+	u.parser = p
+	u.stringBased = ok
+}
+
+// Validate validates raw against the referenced user-defined type.
+func (u *userTypeRefParser) Validate(raw []byte) error {
+	if u.parser == nil {
+		// only possible from within the type's own definition
+		return fmt.Errorf(`cannot use user-defined type %q within its own definition before it is defined`, u.name)
+	}
+	return u.parser.Validate(raw)
+}
+
+// parseConstraints parses the constraints of a type definition referring to
+// the user-defined type.
+func (u *userTypeRefParser) parseConstraints(constraints map[string]json.RawMessage) error {
+	if u.parser == nil {
+		// a recursive reference, the type's own constraints are still
+		// being parsed
+		return nil
 	}
+	return u.parser.parseConstraints(constraints)
 }
 
 // expectsConstraints return false because a reference to user type doesn't
@@ -131,653 +335,2487 @@ type StorageSchema struct {
 
 	// userTypes contains schemas that can validate types defined by the user.
 	userTypes map[string]*userTypeRefParser
+
+	// examples holds the example values of the types in the schema.
+	examples []schemaExample
+	// resolvingTypes holds the names of the user-defined types whose
+	// definitions are being parsed, innermost last.
+	resolvingTypes []string
+
+	// rejectUnknown is true if type definitions must only use known
+	// constraints, because the schema declares a supported version.
+	rejectUnknown bool
+
+	// recursive is true if some user-defined type references itself.
+	recursive bool
+
+	// instances holds the instances of parameterized user-defined types by
+	// reference (e.g., my-type[string]).
+	instances map[string]*userTypeRefParser
+	// instantiating is the number of nested instances being parsed.
+	instantiating int
 }
 
 // Validate validates the provided JSON object.
 func (s *StorageSchema) Validate(raw []byte) error {
-	return s.topLevel.Validate(raw)
-}
-
-func (s *StorageSchema) parse(raw json.RawMessage) (parser, error) {
-	var typ string
-	var schemaDef map[string]json.RawMessage
-	if err := json.Unmarshal(raw, &schemaDef); err != nil {
-		var typeErr *json.UnmarshalTypeError
-		if !errors.As(err, &typeErr) {
-			return nil, fmt.Errorf(`cannot parse aspect schema: %w`, err)
-		}
-
-		if err := json.Unmarshal(raw, &typ); err != nil {
-			return nil, fmt.Errorf(`cannot parse aspect schema: types constraint must be expressed as maps or strings: %w`, err)
+	if s.recursive {
+		// recursive types are only bounded by the depth of the value
+		depth, err := nestingDepth(raw)
+		if err != nil {
+			return validationErrorFrom(err)
 		}
-	} else {
-		rawType, ok := schemaDef["type"]
-		if !ok {
-			typ = "map"
-		} else {
-			if err := json.Unmarshal(rawType, &typ); err != nil {
-				return nil, fmt.Errorf(`cannot parse "type" constraint in type definition: %w`, err)
-			}
+		if depth > maxRecursiveDepth {
+			return validationErrorf(`cannot accept value nested deeper than %d levels`, maxRecursiveDepth)
 		}
 	}
+	return s.topLevel.Validate(raw)
+}
 
-	schema, err := s.newTypeSchema(typ)
-	if err != nil {
-		return nil, err
-	}
-
-	// only parse the schema if it's a schema definition w/ constraints
-	if schemaDef != nil {
-		if err := schema.parseConstraints(schemaDef); err != nil {
-			return nil, err
+// ValidateValueAt validates a JSON value to be stored at the path, without
+// the rest of the document. The value is validated against the type at the
+// path, the keys along the path must be allowed by their maps and a null value,
+// which unsets the entry, can't remove a key that its map requires. Constraints
+// that depend on other entries (e.g., "min-entries") are only checked when the
+// whole document is validated.
+func (s *StorageSchema) ValidateValueAt(path []string, raw []byte) error {
+	if s.recursive {
+		depth, err := nestingDepth(raw)
+		if err != nil {
+			return validationErrorFrom(err)
+		}
+		if depth+len(path) > maxRecursiveDepth {
+			return validationErrorf(`cannot accept value nested deeper than %d levels`, maxRecursiveDepth)
 		}
-	} else if schema.expectsConstraints() {
-		return nil, fmt.Errorf(`cannot parse %q: must be schema definition with constraints`, typ)
 	}
-
-	return schema, nil
+	return validateValueAt(s.topLevel, path, raw, 0)
 }
 
-func (s *StorageSchema) newTypeSchema(typ string) (parser, error) {
-	switch typ {
-	case "map":
-		return &mapSchema{topSchema: s}, nil
-	case "string":
-		return &stringSchema{}, nil
-	case "int":
-		return &intSchema{}, nil
-	case "any":
-		return &anySchema{}, nil
-	case "number":
-		return &numberSchema{}, nil
-	case "bool":
-		return &booleanSchema{}, nil
-	case "array":
-		return &arraySchema{topSchema: s}, nil
-	default:
-		if typ != "" && typ[0] == '$' {
-			return s.getUserType(typ[1:])
+func validateValueAt(schema Schema, path []string, raw []byte, depth int) error {
+	if len(path) == 0 {
+		if isNull(raw) {
+			// unsetting the entry, which its map checks isn't required
+			return nil
 		}
-
-		return nil, fmt.Errorf("cannot parse unknown type %q", typ)
+		return schema.Validate(raw)
 	}
-}
 
-func (s *StorageSchema) getUserType(ref string) (*userTypeRefParser, error) {
-	if userType, ok := s.userTypes[ref]; ok {
-		return userType, nil
+	// alternatives of recursive types may not consume the path
+	if depth > maxRecursiveDepth {
+		return validationErrorf(`cannot accept value nested deeper than %d levels`, maxRecursiveDepth)
 	}
 
-	return nil, fmt.Errorf("cannot find user-defined type %q", ref)
-}
-
-type mapSchema struct {
-	// topSchema is the schema for the top-level schema which contains the user types.
-	topSchema *StorageSchema
+	switch sch := schema.(type) {
+	case *userTypeRefParser:
+		return validateValueAt(sch.parser, path, raw, depth+1)
+	case *defaultSchema:
+		return validateValueAt(sch.parser, path, raw, depth+1)
+	case *sensitiveSchema:
+		return validateValueAt(sch.parser, path, raw, depth+1)
+	case *nullableSchema:
+		return validateValueAt(sch.parser, path, raw, depth+1)
+	case *anySchema:
+		return nil
+	case *altSchema:
+		errs := make([]string, 0, len(sch.alternatives))
+		for _, alt := range sch.alternatives {
+			err := validateValueAt(alt, path, raw, depth+1)
+			if err == nil {
+				return nil
+			}
 
-	// entrySchemas maps keys to their expected types. Alternatively, the schema
-	// can constrain key and/or value types.
-	entrySchemas map[string]Schema
+			var vErr *ValidationError
+			if errors.As(err, &vErr) && len(vErr.Path) == 0 {
+				err = vErr.Err
+			}
+			errs = append(errs, err.Error())
+		}
+		return validationErrorf("no matching alternative type: %s", strings.Join(errs, "; "))
+	case *mapSchema:
+		key := path[0]
+		if !validSubkey.MatchString(key) {
+			return validationErrorf(`key %q doesn't conform to required format`, key)
+		}
 
-	// valueSchema validates that the map's values match a certain type.
-	valueSchema Schema
+		entrySchema, ok := sch.entrySchemas[key]
+		if !ok {
+			if sch.entrySchemas != nil && !sch.additionalKeys {
+				return validationErrorf(`map contains unexpected key %q`, key)
+			}
+			if err := sch.validateKey(key); err != nil {
+				return err
+			}
 
-	// keySchema validates that the map's key match a certain type.
-	keySchema Schema
+			entrySchema = sch.valueSchema
+			if sch.entrySchemas != nil {
+				entrySchema = sch.additionalSchema
+			}
+		}
 
-	// requiredCombs holds combinations of keys that an instance of the map is
-	// allowed to have.
-	requiredCombs [][]string
-}
+		if len(path) == 1 && isNull(raw) && sch.requiresKey(key) {
+			return validationErrorf(`cannot unset required key %q`, key)
+		}
 
-// Validate that raw is a valid aspect map and meets the constraints set by the
-// aspect schema.
-func (v *mapSchema) Validate(raw []byte) error {
-	var mapValue map[string]json.RawMessage
-	if err := json.Unmarshal(raw, &mapValue); err != nil {
-		typeErr := &json.UnmarshalTypeError{}
-		if errors.As(err, &typeErr) {
-			return validationErrorf("expected map type but got %s", typeErr.Value)
+		if entrySchema == nil {
+			return nil
 		}
-		return validationErrorFrom(err)
-	}
 
-	if mapValue == nil {
-		return validationErrorf(`cannot accept null value for "map" type`)
+		err := validateValueAt(entrySchema, path[1:], raw, depth+1)
+		var valErr *ValidationError
+		if errors.As(err, &valErr) {
+			valErr.Path = append([]interface{}{key}, valErr.Path...)
+		}
+		return err
+	default:
+		if isNull(raw) {
+			// nothing to unset under a non-map value
+			return nil
+		}
+		return validationErrorf(`cannot set key %q under a non-map type`, path[0])
 	}
+}
 
-	if err := validMapKeys(mapValue); err != nil {
-		return validationErrorFrom(err)
-	}
+// nestingDepth returns the maximum nesting depth of maps and arrays in a JSON
+// value.
+func nestingDepth(raw []byte) (int, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	var depth, maxDepth int
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return maxDepth, nil
+		}
+		if err != nil {
+			return 0, err
+		}
 
-	if v.entrySchemas != nil {
-		for key := range mapValue {
-			if _, ok := v.entrySchemas[key]; !ok {
-				return validationErrorf(`map contains unexpected key %q`, key)
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+			if depth > maxDepth {
+				maxDepth = depth
 			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
 		}
 	}
+}
 
-	var missing bool
-	for _, required := range v.requiredCombs {
-		missing = false
-		for _, key := range required {
-			if _, ok := mapValue[key]; !ok {
-				missing = true
-				break
-			}
+// referencesWithoutNesting returns true if the schema references the target
+// user-defined type other than through a map or array. Such a reference would
+// validate values against the type itself endlessly.
+func referencesWithoutNesting(schema Schema, target *userTypeRefParser, seen map[*userTypeRefParser]bool) bool {
+	switch sch := schema.(type) {
+	case *userTypeRefParser:
+		if sch == target {
+			return true
 		}
-
-		if !missing {
-			// matched possible combination of required keys so we can stop
-			break
+		if seen[sch] {
+			return false
+		}
+		seen[sch] = true
+		return referencesWithoutNesting(sch.parser, target, seen)
+	case *sensitiveSchema:
+		return referencesWithoutNesting(sch.parser, target, seen)
+	case *defaultSchema:
+		return referencesWithoutNesting(sch.parser, target, seen)
+	case *nullableSchema:
+		return referencesWithoutNesting(sch.parser, target, seen)
+	case *altSchema:
+		for _, alt := range sch.alternatives {
+			if referencesWithoutNesting(alt, target, seen) {
+				return true
+			}
 		}
 	}
+	return false
+}
 
-	if missing {
-		return validationErrorf(`cannot find required combinations of keys`)
+// SensitiveValues returns the values in the provided JSON object that are
+// stored under types marked as sensitive in the schema. Only strings and
+// numbers are returned, in their JSON representation without quotes.
+func (s *StorageSchema) SensitiveValues(raw []byte) ([]string, error) {
+	var values []string
+	if err := collectSensitiveValues(s.topLevel, raw, &values); err != nil {
+		return nil, err
 	}
+	return values, nil
+}
 
-	if v.entrySchemas != nil {
-		for key, val := range mapValue {
-			if validator, ok := v.entrySchemas[key]; ok {
-				if err := validator.Validate(val); err != nil {
-					var valErr *ValidationError
-					if errors.As(err, &valErr) {
-						valErr.Path = append([]interface{}{key}, valErr.Path...)
-					}
-					return err
+func collectSensitiveValues(schema Schema, raw json.RawMessage, values *[]string) error {
+	switch sch := schema.(type) {
+	case *sensitiveSchema:
+		var value interface{}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		collectScalars(value, values)
+	case *userTypeRefParser:
+		return collectSensitiveValues(sch.parser, raw, values)
+	case *defaultSchema:
+		return collectSensitiveValues(sch.parser, raw, values)
+	case *nullableSchema:
+		if isNull(raw) {
+			return nil
+		}
+		return collectSensitiveValues(sch.parser, raw, values)
+	case *mapSchema:
+		var entries map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return err
+		}
+		for key, value := range entries {
+			entrySchema := sch.valueSchema
+			if sch.entrySchemas != nil {
+				var ok bool
+				if entrySchema, ok = sch.entrySchemas[key]; !ok {
+					entrySchema = sch.additionalSchema
 				}
 			}
-		}
-
-		// all required entries are present and validated
-		return nil
-	}
-
-	if v.keySchema != nil {
-		for k := range mapValue {
-			rawKey, err := json.Marshal(k)
-			if err != nil {
-				return fmt.Errorf("internal error: %w", err)
+			if entrySchema == nil {
+				continue
 			}
-
-			if err := v.keySchema.Validate(rawKey); err != nil {
-				var valErr *ValidationError
-				if errors.As(err, &valErr) {
-					valErr.Path = append([]interface{}{k}, valErr.Path...)
-				}
+			if err := collectSensitiveValues(entrySchema, value, values); err != nil {
 				return err
 			}
 		}
-	}
-
-	if v.valueSchema != nil {
-		for k, val := range mapValue {
-			if err := v.valueSchema.Validate(val); err != nil {
-				var valErr *ValidationError
-				if errors.As(err, &valErr) {
-					valErr.Path = append([]interface{}{k}, valErr.Path...)
-				}
+	case *altSchema:
+		// values are collected according to the first matching alternative,
+		// as that is the one they were validated against
+		for _, alt := range sch.alternatives {
+			if alt.Validate(raw) == nil {
+				return collectSensitiveValues(alt, raw, values)
+			}
+		}
+	case *arraySchema:
+		var elements []json.RawMessage
+		if err := json.Unmarshal(raw, &elements); err != nil {
+			return err
+		}
+		if sch.items != nil && len(elements) != len(sch.items) {
+			// not a valid tuple, nothing to collect
+			return nil
+		}
+		for i, element := range elements {
+			if err := collectSensitiveValues(sch.elementSchema(i), element, values); err != nil {
 				return err
 			}
 		}
 	}
-
 	return nil
 }
 
-func validMapKeys(v map[string]json.RawMessage) error {
-	for k := range v {
-		if !validSubkey.Match([]byte(k)) {
-			return fmt.Errorf(`key %q doesn't conform to required format`, k)
-		}
-	}
+// keysAt returns the keys that the schema allows under the path, if these can
+// be enumerated (i.e., the keys of maps with a defined set of entries or keys
+// with a fixed set of choices).
+func (s *StorageSchema) keysAt(path []string) []string {
+	keys := make(map[string]bool)
+	collectKeys(s.topLevel, path, keys, 0)
 
-	return nil
+	list := make([]string, 0, len(keys))
+	for key := range keys {
+		list = append(list, key)
+	}
+	sort.Strings(list)
+	return list
 }
 
-func (v *mapSchema) parseConstraints(constraints map[string]json.RawMessage) error {
-	err := checkExclusiveMapConstraints(constraints)
+func collectKeys(schema Schema, path []string, keys map[string]bool, depth int) {
+	// alternatives of recursive types may not consume the path
+	if depth > maxRecursiveDepth {
+		return
+	}
+
+	switch sch := schema.(type) {
+	case *userTypeRefParser:
+		collectKeys(sch.parser, path, keys, depth+1)
+	case *defaultSchema:
+		collectKeys(sch.parser, path, keys, depth+1)
+	case *sensitiveSchema:
+		collectKeys(sch.parser, path, keys, depth+1)
+	case *nullableSchema:
+		collectKeys(sch.parser, path, keys, depth+1)
+	case *altSchema:
+		for _, alt := range sch.alternatives {
+			collectKeys(alt, path, keys, depth+1)
+		}
+	case *mapSchema:
+		if len(path) == 0 {
+			for key := range sch.entrySchemas {
+				keys[key] = true
+			}
+			if keySchema, ok := sch.keySchema.(*stringSchema); ok {
+				for _, choice := range keySchema.choices {
+					keys[choice] = true
+				}
+				if keySchema.constant != nil {
+					keys[*keySchema.constant] = true
+				}
+			}
+			return
+		}
+
+		entrySchema := sch.valueSchema
+		if sch.entrySchemas != nil {
+			var ok bool
+			if entrySchema, ok = sch.entrySchemas[path[0]]; !ok {
+				entrySchema = sch.additionalSchema
+			}
+		}
+		if entrySchema != nil {
+			collectKeys(entrySchema, path[1:], keys, depth+1)
+		}
+	}
+}
+
+// schemasAt returns the schemas of the values the path can lead to. Placeholders
+// in the path (e.g., "{name}") match any key of the maps along it. No schemas
+// are returned for values under "any" types, which can hold anything.
+func (s *StorageSchema) schemasAt(path []string) []Schema {
+	var schemas []Schema
+	collectSchemas(s.topLevel, path, &schemas, 0)
+	return schemas
+}
+
+func collectSchemas(schema Schema, path []string, schemas *[]Schema, depth int) {
+	if len(path) == 0 {
+		*schemas = append(*schemas, schema)
+		return
+	}
+
+	// alternatives of recursive types may not consume the path
+	if depth > maxRecursiveDepth {
+		return
+	}
+
+	switch sch := schema.(type) {
+	case *userTypeRefParser:
+		collectSchemas(sch.parser, path, schemas, depth+1)
+	case *defaultSchema:
+		collectSchemas(sch.parser, path, schemas, depth+1)
+	case *sensitiveSchema:
+		collectSchemas(sch.parser, path, schemas, depth+1)
+	case *nullableSchema:
+		collectSchemas(sch.parser, path, schemas, depth+1)
+	case *altSchema:
+		for _, alt := range sch.alternatives {
+			collectSchemas(alt, path, schemas, depth+1)
+		}
+	case *mapSchema:
+		var entrySchemas []Schema
+		if isPlaceholder(path[0]) {
+			for _, entrySchema := range sch.entrySchemas {
+				entrySchemas = append(entrySchemas, entrySchema)
+			}
+			if sch.entrySchemas == nil {
+				entrySchemas = append(entrySchemas, sch.valueSchema)
+			} else if sch.additionalKeys {
+				entrySchemas = append(entrySchemas, sch.additionalSchema)
+			}
+		} else if sch.entrySchemas == nil {
+			entrySchemas = append(entrySchemas, sch.valueSchema)
+		} else if entrySchema, ok := sch.entrySchemas[path[0]]; ok {
+			entrySchemas = append(entrySchemas, entrySchema)
+		} else if sch.additionalKeys {
+			entrySchemas = append(entrySchemas, sch.additionalSchema)
+		}
+
+		for _, entrySchema := range entrySchemas {
+			if entrySchema != nil {
+				collectSchemas(entrySchema, path[1:], schemas, depth+1)
+			}
+		}
+	}
+}
+
+// acceptsInt returns whether the schema can accept integer values.
+func acceptsInt(schema Schema, depth int) bool {
+	if depth > maxRecursiveDepth {
+		return false
+	}
+
+	switch sch := schema.(type) {
+	case *userTypeRefParser:
+		return acceptsInt(sch.parser, depth+1)
+	case *defaultSchema:
+		return acceptsInt(sch.parser, depth+1)
+	case *sensitiveSchema:
+		return acceptsInt(sch.parser, depth+1)
+	case *nullableSchema:
+		return acceptsInt(sch.parser, depth+1)
+	case *altSchema:
+		for _, alt := range sch.alternatives {
+			if acceptsInt(alt, depth+1) {
+				return true
+			}
+		}
+		return false
+	case *intSchema, *numberSchema, *anySchema:
+		return true
+	default:
+		return false
+	}
+}
+
+// ApplyDefaults returns the provided JSON object with the default values
+// defined in the schema filled in for the missing entries. The resulting
+// object is validated against the schema.
+func (s *StorageSchema) ApplyDefaults(raw []byte) ([]byte, error) {
+	withDefaults, err := applyDefaults(s.topLevel, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Validate(withDefaults); err != nil {
+		return nil, err
+	}
+	return withDefaults, nil
+}
+
+func applyDefaults(schema Schema, raw json.RawMessage) (json.RawMessage, error) {
+	switch sch := schema.(type) {
+	case *sensitiveSchema:
+		return applyDefaults(sch.parser, raw)
+	case *userTypeRefParser:
+		return applyDefaults(sch.parser, raw)
+	case *defaultSchema:
+		return applyDefaults(sch.parser, raw)
+	case *nullableSchema:
+		if isNull(raw) {
+			return raw, nil
+		}
+		return applyDefaults(sch.parser, raw)
+	case *altSchema:
+		// defaults are applied according to the first matching alternative
+		for _, alt := range sch.alternatives {
+			if alt.Validate(raw) == nil {
+				return applyDefaults(alt, raw)
+			}
+		}
+	case *mapSchema:
+		var entries map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &entries); err != nil || entries == nil {
+			// leave it to validation to report
+			return raw, nil
+		}
+		for key, entrySchema := range sch.entrySchemas {
+			value, ok := entries[key]
+			if !ok {
+				// defaults may be set for the entries of the default value too
+				if value = defaultValue(entrySchema); value == nil {
+					continue
+				}
+			}
+
+			value, err := applyDefaults(entrySchema, value)
+			if err != nil {
+				return nil, err
+			}
+			entries[key] = value
+		}
+		if sch.additionalSchema != nil {
+			for key, value := range entries {
+				if _, ok := sch.entrySchemas[key]; ok {
+					continue
+				}
+				value, err := applyDefaults(sch.additionalSchema, value)
+				if err != nil {
+					return nil, err
+				}
+				entries[key] = value
+			}
+		}
+		if sch.valueSchema != nil {
+			for key, value := range entries {
+				value, err := applyDefaults(sch.valueSchema, value)
+				if err != nil {
+					return nil, err
+				}
+				entries[key] = value
+			}
+		}
+		return json.Marshal(entries)
+	case *arraySchema:
+		var elements []json.RawMessage
+		if err := json.Unmarshal(raw, &elements); err != nil || elements == nil {
+			// leave it to validation to report
+			return raw, nil
+		}
+		if sch.items != nil && len(elements) != len(sch.items) {
+			// leave it to validation to report
+			return raw, nil
+		}
+		for i, element := range elements {
+			element, err := applyDefaults(sch.elementSchema(i), element)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = element
+		}
+		return json.Marshal(elements)
+	}
+	return raw, nil
+}
+
+// defaultValue returns the default value of a type, if it has one.
+func defaultValue(schema Schema) json.RawMessage {
+	switch sch := schema.(type) {
+	case *sensitiveSchema:
+		return defaultValue(sch.parser)
+	case *userTypeRefParser:
+		return defaultValue(sch.parser)
+	case *nullableSchema:
+		return defaultValue(sch.parser)
+	case *defaultSchema:
+		return sch.value
+	}
+	return nil
+}
+
+func collectScalars(value interface{}, values *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, elem := range v {
+			collectScalars(elem, values)
+		}
+	case []interface{}:
+		for _, elem := range v {
+			collectScalars(elem, values)
+		}
+	case string:
+		if v != "" {
+			*values = append(*values, v)
+		}
+	case json.Number:
+		*values = append(*values, v.String())
+	}
+}
+
+func (s *StorageSchema) parse(raw json.RawMessage) (parser, error) {
+	var typ string
+	var schemaDef map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &schemaDef); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return nil, fmt.Errorf(`cannot parse aspect schema: %w`, err)
+		}
+
+		// a list of type definitions is shorthand for the "alt" type
+		var alternatives []json.RawMessage
+		if err := json.Unmarshal(raw, &alternatives); err == nil {
+			schema := &altSchema{topSchema: s}
+			if err := schema.parseAlternatives(alternatives); err != nil {
+				return nil, err
+			}
+			return schema, nil
+		}
+
+		if err := json.Unmarshal(raw, &typ); err != nil {
+			return nil, fmt.Errorf(`cannot parse aspect schema: types constraint must be expressed as maps, strings or lists: %w`, err)
+		}
+	} else {
+		rawType, ok := schemaDef["type"]
+		if !ok {
+			typ = "map"
+		} else {
+			if err := json.Unmarshal(rawType, &typ); err != nil {
+				return nil, fmt.Errorf(`cannot parse "type" constraint in type definition: %w`, err)
+			}
+		}
+	}
+
+	schema, err := s.newTypeSchema(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	// only parse the schema if it's a schema definition w/ constraints
+	if schemaDef != nil {
+		if s.rejectUnknown {
+			if err := checkUnknownConstraints(typ, schema, schemaDef); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := schema.parseConstraints(schemaDef); err != nil {
+			return nil, err
+		}
+	} else if schema.expectsConstraints() {
+		return nil, fmt.Errorf(`cannot parse %q: must be schema definition with constraints`, typ)
+	}
+
+	if rawNullable, ok := schemaDef["nullable"]; ok {
+		var nullable bool
+		if err := json.Unmarshal(rawNullable, &nullable); err != nil {
+			return nil, fmt.Errorf(`cannot parse "nullable" constraint: %w`, err)
+		}
+
+		if nullable {
+			schema = &nullableSchema{parser: schema}
+		}
+	}
+
+	if err := s.collectExamples(schema, schemaDef); err != nil {
+		return nil, err
+	}
+
+	if rawDefault, ok := schemaDef["default"]; ok {
+		if err := schema.Validate(rawDefault); err != nil {
+			return nil, fmt.Errorf(`cannot parse "default" value: %w`, err)
+		}
+
+		schema = &defaultSchema{parser: schema, value: rawDefault}
+	}
+
+	if rawSensitive, ok := schemaDef["sensitive"]; ok {
+		var sensitive bool
+		if err := json.Unmarshal(rawSensitive, &sensitive); err != nil {
+			return nil, fmt.Errorf(`cannot parse "sensitive" marker: %w`, err)
+		}
+
+		if sensitive {
+			return &sensitiveSchema{parser: schema}, nil
+		}
+	}
+
+	return schema, nil
+}
+
+// schemaExample is an example value from the "example" or "examples"
+// annotations of a type definition.
+type schemaExample struct {
+	schema Schema
+	value  json.RawMessage
+	// desc describes the annotation the example is from.
+	desc string
+	// userType is the name of the user-defined type whose definition has the
+	// example, if any.
+	userType string
+}
+
+// collectExamples records the examples of a type definition, so they can be
+// validated against the type once the schema is parsed.
+func (s *StorageSchema) collectExamples(schema Schema, schemaDef map[string]json.RawMessage) error {
+	var userType string
+	if len(s.resolvingTypes) > 0 {
+		userType = s.resolvingTypes[len(s.resolvingTypes)-1]
+	}
+
+	if rawExample, ok := schemaDef["example"]; ok {
+		s.examples = append(s.examples, schemaExample{
+			schema:   schema,
+			value:    rawExample,
+			desc:     `"example" value`,
+			userType: userType,
+		})
+	}
+
+	if rawExamples, ok := schemaDef["examples"]; ok {
+		var examples []json.RawMessage
+		if err := json.Unmarshal(rawExamples, &examples); err != nil {
+			return fmt.Errorf(`cannot parse "examples": %w`, err)
+		}
+
+		if len(examples) == 0 {
+			return fmt.Errorf(`cannot have an empty "examples" list`)
+		}
+
+		for i, example := range examples {
+			s.examples = append(s.examples, schemaExample{
+				schema:   schema,
+				value:    example,
+				desc:     fmt.Sprintf(`"examples" value %d`, i),
+				userType: userType,
+			})
+		}
+	}
+
+	return nil
+}
+
+// sensitiveSchema marks the values of a type as sensitive, so they can be
+// kept out of logs and reports. It validates values like the type it wraps.
+type sensitiveSchema struct {
+	parser
+}
+
+// nullableSchema accepts null values, which mark an entry as explicitly unset,
+// and otherwise validates values like the type it wraps.
+type nullableSchema struct {
+	parser
+}
+
+// Validate that raw is null or valid according to the wrapped type.
+func (v *nullableSchema) Validate(raw []byte) error {
+	if isNull(raw) {
+		return nil
+	}
+	return v.parser.Validate(raw)
+}
+
+// nullSchema validates null values. It's meant to be used as one of several
+// alternative types (e.g., ["string", "null"]).
+type nullSchema struct{}
+
+func (v *nullSchema) Validate(raw []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return validationErrorFrom(err)
+	}
+
+	if value != nil {
+		return validationErrorf(`expected null type but got %s`, jsonTypeName(value))
+	}
+	return nil
+}
+
+func (v *nullSchema) parseConstraints(map[string]json.RawMessage) error {
+	// no error because we're not explicitly rejecting unsupported keywords (for now)
+	return nil
+}
+
+func (v *nullSchema) expectsConstraints() bool { return false }
+
+func isNull(raw []byte) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// defaultSchema holds the value used for a type when it's missing from
+// a map. It validates values like the type it wraps.
+type defaultSchema struct {
+	parser
+
+	// value is the JSON encoded default value.
+	value json.RawMessage
+}
+
+func (s *StorageSchema) newTypeSchema(typ string) (parser, error) {
+	switch typ {
+	case "map":
+		return &mapSchema{topSchema: s}, nil
+	case "string":
+		return &stringSchema{}, nil
+	case "int":
+		return &intSchema{}, nil
+	case "any":
+		return &anySchema{}, nil
+	case "number":
+		return &numberSchema{}, nil
+	case "bool":
+		return &booleanSchema{}, nil
+	case "decimal":
+		return &decimalSchema{}, nil
+	case "datetime":
+		return &datetimeSchema{}, nil
+	case "duration":
+		return &durationSchema{}, nil
+	case "enum":
+		return &enumSchema{}, nil
+	case "null":
+		return &nullSchema{}, nil
+	case "array":
+		return &arraySchema{topSchema: s}, nil
+	case "alt":
+		return &altSchema{topSchema: s}, nil
+	default:
+		if typ != "" && typ[0] == '$' {
+			return s.getUserType(typ[1:])
+		}
+
+		return nil, fmt.Errorf("cannot parse unknown type %q", typ)
+	}
+}
+
+func (s *StorageSchema) getUserType(ref string) (*userTypeRefParser, error) {
+	name, args, err := parseTypeRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	userType, ok := s.userTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("cannot find user-defined type %q", name)
+	}
+
+	if args != nil {
+		return s.instantiateUserType(userType, args)
+	}
+	if len(userType.params) > 0 {
+		return nil, fmt.Errorf(`cannot use user-defined type %q without type parameters`, name)
+	}
+
+	if userType.resolving {
+		// the type is referenced from its own definition, the reference is
+		// usable once the definition is parsed
+		s.recursive = true
+		return userType, nil
+	}
+
+	if err := s.resolveUserType(userType); err != nil {
+		return nil, err
+	}
+	return userType, nil
+}
+
+// resolveUserType parses the definition of a user-defined type, if it wasn't
+// parsed yet.
+func (s *StorageSchema) resolveUserType(userType *userTypeRefParser) error {
+	if userType.parser != nil {
+		return nil
+	}
+
+	userType.resolving = true
+	s.resolvingTypes = append(s.resolvingTypes, userType.name)
+	p, err := s.parse(userType.def)
+	s.resolvingTypes = s.resolvingTypes[:len(s.resolvingTypes)-1]
+	userType.resolving = false
+	if err != nil {
+		return fmt.Errorf(`cannot parse user-defined type %q: %w`, userType.name, err)
+	}
+
+	userType.setParser(p)
+	return nil
+}
+
+// maxInstantiationDepth is the maximum number of nested instances of
+// parameterized user-defined types, which bounds types that reference
+// themselves with ever growing parameters.
+const maxInstantiationDepth = 16
+
+// instantiateUserType returns the instance of a parameterized user-defined
+// type binding its parameters to the given types.
+func (s *StorageSchema) instantiateUserType(userType *userTypeRefParser, args []string) (*userTypeRefParser, error) {
+	if len(userType.params) == 0 {
+		return nil, fmt.Errorf(`user-defined type %q does not take type parameters`, userType.name)
+	}
+	if len(args) != len(userType.params) {
+		return nil, fmt.Errorf(`user-defined type %q expects %d type parameters but got %d`, userType.name, len(userType.params), len(args))
+	}
+
+	ref := userType.name + "[" + strings.Join(args, ",") + "]"
+	if instance, ok := s.instances[ref]; ok {
+		if instance.resolving {
+			s.recursive = true
+		}
+		return instance, nil
+	}
+
+	if s.instantiating >= maxInstantiationDepth {
+		return nil, fmt.Errorf(`cannot use user-defined type %q: type parameters nested deeper than %d levels`, ref, maxInstantiationDepth)
+	}
+
+	bindings := make(map[string]string, len(args))
+	for i, param := range userType.params {
+		bindings[param] = args[i]
+	}
+	def, err := substituteTypeParams(userType.def, bindings)
+	if err != nil {
+		return nil, fmt.Errorf(`cannot parse user-defined type %q: %w`, ref, err)
+	}
+
+	if s.instances == nil {
+		s.instances = make(map[string]*userTypeRefParser)
+	}
+	instance := &userTypeRefParser{name: ref, def: def}
+	s.instances[ref] = instance
+
+	s.instantiating++
+	err = s.resolveUserType(instance)
+	s.instantiating--
+	if err != nil {
+		delete(s.instances, ref)
+		return nil, err
+	}
+
+	if referencesWithoutNesting(instance.parser, instance, make(map[*userTypeRefParser]bool)) {
+		return nil, fmt.Errorf(`cannot parse user-defined type %q: recursive reference must be nested in a map or array`, ref)
+	}
+	return instance, nil
+}
+
+// parseTypeParameters extracts the "parameters" of a user-defined type's
+// definition, returning the definition without them.
+func parseTypeParameters(def json.RawMessage) ([]string, json.RawMessage, error) {
+	var constraints map[string]json.RawMessage
+	if err := json.Unmarshal(def, &constraints); err != nil {
+		// not a definition with constraints, errors are reported on parsing
+		return nil, def, nil
+	}
+
+	rawParams, ok := constraints["parameters"]
+	if !ok {
+		return nil, def, nil
+	}
+
+	var params []string
+	if err := json.Unmarshal(rawParams, &params); err != nil {
+		return nil, nil, fmt.Errorf(`cannot parse "parameters" constraint: %w`, err)
+	}
+	if len(params) == 0 {
+		return nil, nil, fmt.Errorf(`cannot parse "parameters" constraint: must have at least one parameter`)
+	}
+
+	seen := make(map[string]bool, len(params))
+	for _, param := range params {
+		if !validUserType.Match([]byte(param)) {
+			return nil, nil, fmt.Errorf(`cannot parse type parameter name %q: must match %s`, param, validUserType)
+		}
+		if seen[param] {
+			return nil, nil, fmt.Errorf(`cannot parse "parameters" constraint: duplicate parameter %q`, param)
+		}
+		seen[param] = true
+	}
+
+	delete(constraints, "parameters")
+	def, err := json.Marshal(constraints)
+	if err != nil {
+		return nil, nil, err
+	}
+	return params, def, nil
+}
+
+// parseTypeRef parses a reference to a user-defined type, which may bind the
+// parameters of a parameterized type (e.g., my-type[string,$other-type]).
+// The arguments are nil if the reference has no parameters.
+func parseTypeRef(ref string) (name string, args []string, err error) {
+	open := strings.IndexByte(ref, '[')
+	if open < 0 {
+		return ref, nil, nil
+	}
+	if !strings.HasSuffix(ref, "]") {
+		return "", nil, fmt.Errorf(`cannot parse type parameters of %q: must end with "]"`, ref)
+	}
+
+	name = ref[:open]
+	inner := ref[open+1 : len(ref)-1]
+	var depth, start int
+	for i, r := range inner {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth < 0 {
+				return "", nil, fmt.Errorf(`cannot parse type parameters of %q: unbalanced brackets`, ref)
+			}
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(inner[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return "", nil, fmt.Errorf(`cannot parse type parameters of %q: unbalanced brackets`, ref)
+	}
+	args = append(args, strings.TrimSpace(inner[start:]))
+
+	for _, arg := range args {
+		if arg == "" {
+			return "", nil, fmt.Errorf(`cannot parse type parameters of %q: empty type parameter`, ref)
+		}
+	}
+	return name, args, nil
+}
+
+// substituteTypeName replaces the references to parameters in a type name
+// with the types they're bound to.
+func substituteTypeName(typ string, bindings map[string]string) string {
+	if !strings.HasPrefix(typ, "$") {
+		return typ
+	}
+
+	name, args, err := parseTypeRef(typ[1:])
+	if err != nil {
+		// reported when the type is parsed
+		return typ
+	}
+	if args == nil {
+		if bound, ok := bindings[name]; ok {
+			return bound
+		}
+		return typ
+	}
+
+	for i, arg := range args {
+		args[i] = substituteTypeName(arg, bindings)
+	}
+	return "$" + name + "[" + strings.Join(args, ",") + "]"
+}
+
+// substituteTypeParams replaces the references to parameters in the places
+// of a type definition that hold types.
+func substituteTypeParams(raw json.RawMessage, bindings map[string]string) (json.RawMessage, error) {
+	var typ string
+	if err := json.Unmarshal(raw, &typ); err == nil {
+		return json.Marshal(substituteTypeName(typ, bindings))
+	}
+
+	var types []json.RawMessage
+	if err := json.Unmarshal(raw, &types); err == nil {
+		for i, t := range types {
+			if types[i], err = substituteTypeParams(t, bindings); err != nil {
+				return nil, err
+			}
+		}
+		return json.Marshal(types)
+	}
+
+	var constraints map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &constraints); err != nil {
+		// reported when the type is parsed
+		return raw, nil
+	}
+
+	for key, value := range constraints {
+		var err error
+		switch key {
+		case "type", "keys", "values", "additional-keys", "items", "alternatives":
+			constraints[key], err = substituteTypeParams(value, bindings)
+		case "schema":
+			var entries map[string]json.RawMessage
+			if json.Unmarshal(value, &entries) != nil {
+				continue
+			}
+			for entry, entryType := range entries {
+				if entries[entry], err = substituteTypeParams(entryType, bindings); err != nil {
+					return nil, err
+				}
+			}
+			constraints[key], err = json.Marshal(entries)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(constraints)
+}
+
+type mapSchema struct {
+	// topSchema is the schema for the top-level schema which contains the user types.
+	topSchema *StorageSchema
+
+	// entrySchemas maps keys to their expected types. Alternatively, or for
+	// the keys without an entry, the schema can constrain key and/or value types.
+	entrySchemas map[string]Schema
+
+	// valueSchema validates that the map's values match a certain type.
+	valueSchema Schema
+
+	// keySchema validates that the map's key match a certain type. If the map
+	// has entrySchemas, it only validates the additional keys.
+	keySchema Schema
+
+	// requiredCombs holds combinations of keys that an instance of the map is
+	// allowed to have.
+	requiredCombs [][]string
+
+	// additionalKeys is true if the map can have keys other than the ones in
+	// entrySchemas. Their values are validated against additionalSchema, if set,
+	// which comes from "additional-keys" or "values".
+	additionalKeys   bool
+	additionalSchema Schema
+
+	// minEntries and maxEntries bound the number of entries in a map
+	// constrained by "keys"/"values" or in a "schema" map that allows
+	// additional keys, counting both its schema entries and additional keys.
+	minEntries *int
+	maxEntries *int
+}
+
+// Validate that raw is a valid aspect map and meets the constraints set by the
+// aspect schema.
+func (v *mapSchema) Validate(raw []byte) error {
+	var mapValue map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &mapValue); err != nil {
+		typeErr := &json.UnmarshalTypeError{}
+		if errors.As(err, &typeErr) {
+			return validationErrorf("expected map type but got %s", typeErr.Value)
+		}
+		return validationErrorFrom(err)
+	}
+
+	if mapValue == nil {
+		return validationErrorf(`cannot accept null value for "map" type`)
+	}
+
+	if err := validMapKeys(mapValue); err != nil {
+		return validationErrorFrom(err)
+	}
+
+	if v.minEntries != nil && len(mapValue) < *v.minEntries {
+		return validationErrorf(`cannot accept map with fewer than %d entries`, *v.minEntries)
+	}
+
+	if v.maxEntries != nil && len(mapValue) > *v.maxEntries {
+		return validationErrorf(`cannot accept map with more than %d entries`, *v.maxEntries)
+	}
+
+	if v.entrySchemas != nil && !v.additionalKeys {
+		for key := range mapValue {
+			if _, ok := v.entrySchemas[key]; !ok {
+				return validationErrorf(`map contains unexpected key %q`, key)
+			}
+		}
+	}
+
+	var missing bool
+	for _, required := range v.requiredCombs {
+		missing = false
+		for _, key := range required {
+			if _, ok := mapValue[key]; !ok {
+				missing = true
+				break
+			}
+		}
+
+		if !missing {
+			// matched possible combination of required keys so we can stop
+			break
+		}
+	}
+
+	if missing {
+		return validationErrorf(`cannot find required combinations of keys`)
+	}
+
+	if v.entrySchemas != nil {
+		for key, val := range mapValue {
+			validator, ok := v.entrySchemas[key]
+			if !ok {
+				if err := v.validateKey(key); err != nil {
+					return err
+				}
+				validator = v.additionalSchema
+			}
+			if validator != nil {
+				if err := validator.Validate(val); err != nil {
+					var valErr *ValidationError
+					if errors.As(err, &valErr) {
+						valErr.Path = append([]interface{}{key}, valErr.Path...)
+					}
+					return err
+				}
+			}
+		}
+
+		// all required entries are present and validated
+		return nil
+	}
+
+	for k := range mapValue {
+		if err := v.validateKey(k); err != nil {
+			return err
+		}
+	}
+
+	if v.valueSchema != nil {
+		for k, val := range mapValue {
+			if err := v.valueSchema.Validate(val); err != nil {
+				var valErr *ValidationError
+				if errors.As(err, &valErr) {
+					valErr.Path = append([]interface{}{k}, valErr.Path...)
+				}
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// requiresKey returns true if the key is in every combination of keys that the
+// map requires.
+func (v *mapSchema) requiresKey(key string) bool {
+	if len(v.requiredCombs) == 0 {
+		return false
+	}
+
+	for _, comb := range v.requiredCombs {
+		if !strutil.ListContains(comb, key) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateKey validates the key against the map's key schema, if set.
+func (v *mapSchema) validateKey(key string) error {
+	if v.keySchema == nil {
+		return nil
+	}
+
+	rawKey, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("internal error: %w", err)
+	}
+
+	if err := v.keySchema.Validate(rawKey); err != nil {
+		var valErr *ValidationError
+		if errors.As(err, &valErr) {
+			valErr.Path = append([]interface{}{key}, valErr.Path...)
+		}
+		return err
+	}
+	return nil
+}
+
+func validMapKeys(v map[string]json.RawMessage) error {
+	for k := range v {
+		if !validSubkey.Match([]byte(k)) {
+			return fmt.Errorf(`key %q doesn't conform to required format`, k)
+		}
+	}
+
+	return nil
+}
+
+func (v *mapSchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	err := checkExclusiveMapConstraints(constraints)
+	if err != nil {
+		return fmt.Errorf(`cannot parse map: %w`, err)
+	}
+
+	// maps can be "schemas" with types for specific entries and optional "required" constraints
+	if rawEntries, ok := constraints["schema"]; ok {
+		var entries map[string]json.RawMessage
+		if err := json.Unmarshal(rawEntries, &entries); err != nil {
+			return fmt.Errorf(`cannot parse map's "schema" constraint: %v`, err)
+		}
+
+		if err := validMapKeys(entries); err != nil {
+			return fmt.Errorf(`cannot parse map: %w`, err)
+		}
+
+		v.entrySchemas = make(map[string]Schema, len(entries))
+		for key, value := range entries {
+			entrySchema, err := v.topSchema.parse(value)
+			if err != nil {
+				return err
+			}
+
+			v.entrySchemas[key] = entrySchema
+		}
+
+		// "required" can be a list of keys or many lists of alternative combinations
+		if rawRequired, ok := constraints["required"]; ok {
+			var requiredCombs [][]string
+			if err := json.Unmarshal(rawRequired, &requiredCombs); err != nil {
+				var typeErr *json.UnmarshalTypeError
+				if !errors.As(err, &typeErr) {
+					return fmt.Errorf(`cannot parse map's "required" constraint: %v`, err)
+				}
+
+				var required []string
+				if err := json.Unmarshal(rawRequired, &required); err != nil {
+					return fmt.Errorf(`cannot parse map's "required" constraint: %v`, err)
+				}
+
+				v.requiredCombs = [][]string{required}
+			} else {
+				v.requiredCombs = requiredCombs
+			}
+
+			for _, requiredComb := range v.requiredCombs {
+				for _, required := range requiredComb {
+					if _, ok := v.entrySchemas[required]; !ok {
+						return fmt.Errorf(`cannot parse map's "required" constraint: required key %q must have schema entry`, required)
+					}
+				}
+			}
+		}
+
+		if rawAdditional, ok := constraints["additional-keys"]; ok {
+			if err := v.parseAdditionalKeys(rawAdditional); err != nil {
+				return fmt.Errorf(`cannot parse map's "additional-keys" constraint: %w`, err)
+			}
+		}
+
+		// "keys" and "values" constrain the entries without a schema entry
+		keySchema, valueSchema, err := v.parseKeysAndValues(constraints)
+		if err != nil {
+			return err
+		}
+		if keySchema != nil || valueSchema != nil {
+			v.keySchema = keySchema
+			v.additionalKeys = true
+			v.additionalSchema = valueSchema
+		}
+
+		_, hasMin := constraints["min-entries"]
+		_, hasMax := constraints["max-entries"]
+		if (hasMin || hasMax) && !v.additionalKeys {
+			// the number of entries is already bounded by the schema entries
+			return fmt.Errorf(`cannot parse map: cannot use "min-entries" or "max-entries" with "schema" constraint unless the map allows additional keys`)
+		}
+		return v.parseEntriesConstraints(constraints)
+	}
+
+	// map can not specify "schemas" and constrain the type of keys and values instead
+	v.keySchema, v.valueSchema, err = v.parseKeysAndValues(constraints)
+	if err != nil {
+		return err
+	}
+
+	if v.entrySchemas == nil && v.keySchema == nil && v.valueSchema == nil {
+		return fmt.Errorf(`cannot parse map: must have "schema" or "keys"/"values" constraint`)
+	}
+
+	return v.parseEntriesConstraints(constraints)
+}
+
+// parseEntriesConstraints parses the "min-entries" and "max-entries"
+// constraints, if set.
+func (v *mapSchema) parseEntriesConstraints(constraints map[string]json.RawMessage) error {
+	if rawMinEntries, ok := constraints["min-entries"]; ok {
+		var minEntries int
+		if err := json.Unmarshal(rawMinEntries, &minEntries); err != nil {
+			return fmt.Errorf(`cannot parse map's "min-entries" constraint: %v`, err)
+		}
+
+		if minEntries < 0 {
+			return fmt.Errorf(`cannot have negative map "min-entries" constraint`)
+		}
+		v.minEntries = &minEntries
+	}
+
+	if rawMaxEntries, ok := constraints["max-entries"]; ok {
+		var maxEntries int
+		if err := json.Unmarshal(rawMaxEntries, &maxEntries); err != nil {
+			return fmt.Errorf(`cannot parse map's "max-entries" constraint: %v`, err)
+		}
+
+		if maxEntries < 0 {
+			return fmt.Errorf(`cannot have negative map "max-entries" constraint`)
+		}
+		v.maxEntries = &maxEntries
+	}
+
+	if v.minEntries != nil && v.maxEntries != nil && *v.minEntries > *v.maxEntries {
+		return fmt.Errorf(`cannot have map "min-entries" constraint with value greater than "max-entries"`)
+	}
+
+	return nil
+}
+
+// parseKeysAndValues parses the "keys" and "values" constraints, if set.
+func (v *mapSchema) parseKeysAndValues(constraints map[string]json.RawMessage) (keySchema, valueSchema Schema, err error) {
+	if rawKeyDef, ok := constraints["keys"]; ok {
+		if keySchema, err = v.parseMapKeyType(rawKeyDef); err != nil {
+			return nil, nil, fmt.Errorf(`cannot parse "keys" constraint: %w`, err)
+		}
+	}
+
+	if rawValuesDef, ok := constraints["values"]; ok {
+		if valueSchema, err = v.topSchema.parse(rawValuesDef); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return keySchema, valueSchema, nil
+}
+
+// parseAdditionalKeys parses the "additional-keys" constraint which is either
+// a boolean or the type of the values of the additional keys.
+func (v *mapSchema) parseAdditionalKeys(raw json.RawMessage) error {
+	var additional bool
+	if err := json.Unmarshal(raw, &additional); err == nil {
+		v.additionalKeys = additional
+		return nil
+	}
+
+	schema, err := v.topSchema.parse(raw)
+	if err != nil {
+		return err
+	}
+	v.additionalKeys = true
+	v.additionalSchema = schema
+	return nil
+}
+
+// checkExclusiveMapConstraints checks if the map contains mutually exclusive constraints.
+func checkExclusiveMapConstraints(obj map[string]json.RawMessage) error {
+	has := func(k string) bool {
+		_, ok := obj[k]
+		return ok
+	}
+
+	if has("required") && !has("schema") {
+		return fmt.Errorf(`cannot use "required" without "schema" constraint`)
+	}
+	if has("additional-keys") && !has("schema") {
+		return fmt.Errorf(`cannot use "additional-keys" without "schema" constraint`)
+	}
+	if has("additional-keys") && (has("keys") || has("values")) {
+		return fmt.Errorf(`cannot use "additional-keys" with "keys" or "values" constraints`)
+	}
+	return nil
+}
+
+func (v *mapSchema) parseMapKeyType(raw json.RawMessage) (Schema, error) {
+	var typ string
+	if err := json.Unmarshal(raw, &typ); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return nil, err
+		}
+
+		var schemaDef map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &schemaDef); err != nil {
+			return nil, err
+		}
+
+		if rawType, ok := schemaDef["type"]; ok {
+			if err := json.Unmarshal(rawType, &typ); err != nil {
+				return nil, err
+			}
+
+			if typ != "string" {
+				return nil, fmt.Errorf(`must be based on string but got %q`, typ)
+			}
+		}
+
+		schema := &stringSchema{}
+		if err := schema.parseConstraints(schemaDef); err != nil {
+			return nil, err
+		}
+
+		return schema, nil
+	}
+
+	if typ == "string" {
+		return &stringSchema{}, nil
+	}
+
+	if typ != "" && typ[0] == '$' {
+		userType, err := v.topSchema.getUserType(typ[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		if !userType.isStringBased() {
+			return nil, fmt.Errorf(`key type %q must be based on string`, typ[1:])
+		}
+
+		return userType, nil
+	}
+
+	return nil, fmt.Errorf(`keys must be based on string but got %q`, typ)
+}
+
+func (v *mapSchema) expectsConstraints() bool { return true }
+
+type stringSchema struct {
+	// constant is the only value the string can take, if set.
+	constant *string
+
+	// pattern is a regex pattern that the string must match.
+	pattern *regexp.Regexp
+
+	// choices holds the possible values the string can take, if non-empty.
+	choices []string
+
+	// format is the name of a well-known format the string must conform to.
+	format string
+
+	// minLength and maxLength bound the number of characters in the string.
+	minLength *int
+	maxLength *int
+}
+
+// stringFormats maps the formats that can be used in a string's "format"
+// constraint to the functions that check them.
+var stringFormats = map[string]func(string) bool{
+	// hostnames are checked as hostnamectl would (see hostname_is_valid in systemd)
+	"hostname": func(s string) bool {
+		return len(s) <= 64 && validHostname.MatchString(s)
+	},
+	"timezone": validTimezone.MatchString,
+	"locale":   validLocale.MatchString,
+}
+
+var (
+	validHostname = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-]{0,62}(\.[a-zA-Z0-9-]{1,63})*$`)
+	validTimezone = regexp.MustCompile(`^[a-zA-Z0-9+_-]+(/[a-zA-Z0-9+_-]+)?(/[a-zA-Z0-9+_-]+)?$`)
+	validLocale   = regexp.MustCompile(`^(C|POSIX|[a-z]{2,3}(_[A-Z]{2})?)(\.[a-zA-Z0-9-]+)?(@[a-zA-Z0-9]+)?$`)
+)
+
+// Validate that raw is a valid aspect string and meets the schema's constraints.
+func (v *stringSchema) Validate(raw []byte) (err error) {
+	defer func() {
+		if err != nil {
+			err = validationErrorFrom(err)
+		}
+	}()
+
+	var value *string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		typeErr := &json.UnmarshalTypeError{}
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("expected string type but got %s", typeErr.Value)
+		}
+		return err
+	}
+
+	if value == nil {
+		return fmt.Errorf(`cannot accept null value for "string" type`)
+	}
+
+	if v.constant != nil && *value != *v.constant {
+		return fmt.Errorf(`string %q is not the required constant %q`, *value, *v.constant)
+	}
+
+	if len(v.choices) != 0 && !strutil.ListContains(v.choices, *value) {
+		return fmt.Errorf(`string %q is not one of the allowed choices`, *value)
+	}
+
+	if v.pattern != nil && !v.pattern.Match([]byte(*value)) {
+		return fmt.Errorf(`string %q doesn't match schema pattern %s`, *value, v.pattern.String())
+	}
+
+	if v.format != "" && !stringFormats[v.format](*value) {
+		return fmt.Errorf(`string %q is not a valid %s`, *value, v.format)
+	}
+
+	length := utf8.RuneCountInString(*value)
+	if v.minLength != nil && length < *v.minLength {
+		return fmt.Errorf(`string %q is shorter than the allowed minimum length %d`, *value, *v.minLength)
+	}
+
+	if v.maxLength != nil && length > *v.maxLength {
+		return fmt.Errorf(`string %q is longer than the allowed maximum length %d`, *value, *v.maxLength)
+	}
+
+	return nil
+}
+
+func (v *stringSchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	constant, err := parseConstConstraint[string](constraints, "choices", "pattern", "format", "min-length", "max-length")
 	if err != nil {
-		return fmt.Errorf(`cannot parse map: %w`, err)
+		return err
+	}
+	if constant != nil {
+		v.constant = constant
+	}
+
+	if rawChoices, ok := constraints["choices"]; ok {
+		var choices []string
+		if err := json.Unmarshal(rawChoices, &choices); err != nil {
+			return fmt.Errorf(`cannot parse "choices" constraint: %w`, err)
+		}
+
+		if len(choices) == 0 {
+			return fmt.Errorf(`cannot have a "choices" constraint with an empty list`)
+		}
+
+		v.choices = choices
+	}
+
+	if rawPattern, ok := constraints["pattern"]; ok {
+		if v.choices != nil {
+			return fmt.Errorf(`cannot use "choices" and "pattern" constraints in same schema`)
+		}
+
+		var patt string
+		err := json.Unmarshal(rawPattern, &patt)
+		if err != nil {
+			return fmt.Errorf(`cannot parse "pattern" constraint: %w`, err)
+		}
+
+		if v.pattern, err = regexp.Compile(patt); err != nil {
+			return fmt.Errorf(`cannot parse "pattern" constraint: %w`, err)
+		}
+	}
+
+	if rawFormat, ok := constraints["format"]; ok {
+		if v.choices != nil {
+			return fmt.Errorf(`cannot use "choices" and "format" constraints in same schema`)
+		}
+
+		var format string
+		if err := json.Unmarshal(rawFormat, &format); err != nil {
+			return fmt.Errorf(`cannot parse "format" constraint: %w`, err)
+		}
+
+		if _, ok := stringFormats[format]; !ok {
+			return fmt.Errorf(`cannot parse "format" constraint: unknown format %q`, format)
+		}
+		v.format = format
+	}
+
+	if rawMinLen, ok := constraints["min-length"]; ok {
+		if v.choices != nil {
+			return fmt.Errorf(`cannot use "choices" and "min-length" constraints in same schema`)
+		}
+
+		minLen, err := parseLengthConstraint("min-length", rawMinLen)
+		if err != nil {
+			return err
+		}
+		v.minLength = &minLen
+	}
+
+	if rawMaxLen, ok := constraints["max-length"]; ok {
+		if v.choices != nil {
+			return fmt.Errorf(`cannot use "choices" and "max-length" constraints in same schema`)
+		}
+
+		maxLen, err := parseLengthConstraint("max-length", rawMaxLen)
+		if err != nil {
+			return err
+		}
+		v.maxLength = &maxLen
+	}
+
+	if v.minLength != nil && v.maxLength != nil && *v.minLength > *v.maxLength {
+		return fmt.Errorf(`cannot have "min-length" constraint with value greater than "max-length"`)
+	}
+
+	return nil
+}
+
+// parseConstConstraint parses the "const" constraint, if any, which pins a
+// value to a single one and so can't be used with the other constraints listed.
+func parseConstConstraint[T any](constraints map[string]json.RawMessage, others ...string) (*T, error) {
+	rawConst, ok := constraints["const"]
+	if !ok {
+		return nil, nil
+	}
+
+	for _, other := range others {
+		if _, ok := constraints[other]; ok {
+			return nil, fmt.Errorf(`cannot use "const" and %q constraints in same schema`, other)
+		}
+	}
+
+	var constant *T
+	if err := json.Unmarshal(rawConst, &constant); err != nil {
+		return nil, fmt.Errorf(`cannot parse "const" constraint: %w`, err)
+	}
+
+	if constant == nil {
+		return nil, fmt.Errorf(`cannot parse "const" constraint: cannot be null`)
+	}
+	return constant, nil
+}
+
+func parseLengthConstraint(name string, raw json.RawMessage) (int, error) {
+	var length int
+	if err := json.Unmarshal(raw, &length); err != nil {
+		return 0, fmt.Errorf(`cannot parse %q constraint: %w`, name, err)
+	}
+
+	if length < 0 {
+		return 0, fmt.Errorf(`cannot have negative %q constraint`, name)
+	}
+	return length, nil
+}
+
+func (v *stringSchema) expectsConstraints() bool { return false }
+
+type intSchema struct {
+	min      *int64
+	max      *int64
+	choices  []int64
+	constant *int64
+
+	// exclusiveMin and exclusiveMax bound the value without including the
+	// bounds themselves.
+	exclusiveMin *int64
+	exclusiveMax *int64
+
+	// multipleOf is a number the value must be a multiple of.
+	multipleOf *int64
+}
+
+// Validate that raw is a valid integer and meets the schema's constraints.
+func (v *intSchema) Validate(raw []byte) (err error) {
+	defer func() {
+		if err != nil {
+			err = validationErrorFrom(err)
+		}
+	}()
+
+	var num *int64
+	if err := json.Unmarshal(raw, &num); err != nil {
+		typeErr := &json.UnmarshalTypeError{}
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("expected int type but got %s", typeErr.Value)
+		}
+		return err
+	}
+
+	if num == nil {
+		return fmt.Errorf(`cannot accept null value for "int" type`)
+	}
+
+	if err := validateNumber(*num, v.constant, v.choices, v.min, v.max, v.exclusiveMin, v.exclusiveMax); err != nil {
+		return err
+	}
+
+	if v.multipleOf != nil && *num%*v.multipleOf != 0 {
+		return fmt.Errorf(`%v is not a multiple of %v`, *num, *v.multipleOf)
+	}
+
+	return nil
+}
+
+func (v *intSchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	constant, err := parseConstConstraint[int64](constraints, "choices", "min", "max", "exclusive-min", "exclusive-max", "multiple-of")
+	if err != nil {
+		return err
+	}
+	if constant != nil {
+		v.constant = constant
+	}
+
+	if rawChoices, ok := constraints["choices"]; ok {
+		var choices []int64
+		err := json.Unmarshal(rawChoices, &choices)
+		if err != nil {
+			return fmt.Errorf(`cannot parse "choices" constraint: %v`, err)
+		}
+
+		if len(choices) == 0 {
+			return fmt.Errorf(`cannot have "choices" constraint with empty list`)
+		}
+
+		v.choices = choices
+	}
+
+	if rawMin, ok := constraints["min"]; ok {
+		if v.choices != nil {
+			return fmt.Errorf(`cannot have "choices" and "min" constraints`)
+		}
+
+		var min int64
+		if err := json.Unmarshal(rawMin, &min); err != nil {
+			return fmt.Errorf(`cannot parse "min" constraint: %v`, err)
+		}
+		v.min = &min
+	}
+
+	if rawMax, ok := constraints["max"]; ok {
+		if v.choices != nil {
+			return fmt.Errorf(`cannot have "choices" and "max" constraints`)
+		}
+
+		var max int64
+		if err := json.Unmarshal(rawMax, &max); err != nil {
+			return fmt.Errorf(`cannot parse "max" constraint: %v`, err)
+		}
+		v.max = &max
+	}
+
+	if v.min != nil && v.max != nil && *v.min > *v.max {
+		return fmt.Errorf(`cannot have "min" constraint with value greater than "max"`)
+	}
+
+	exclusiveMin, exclusiveMax, err := parseExclusiveBounds(constraints, v.choices != nil, v.min, v.max)
+	if err != nil {
+		return err
+	}
+	if exclusiveMin != nil {
+		v.exclusiveMin = exclusiveMin
+	}
+	if exclusiveMax != nil {
+		v.exclusiveMax = exclusiveMax
+	}
+
+	multipleOf, err := parseMultipleOf[int64](constraints, v.choices != nil)
+	if err != nil {
+		return err
+	}
+	if multipleOf != nil {
+		v.multipleOf = multipleOf
+	}
+
+	return nil
+}
+
+func (v *intSchema) expectsConstraints() bool { return false }
+
+type anySchema struct{}
+
+func (v *anySchema) Validate(raw []byte) (err error) {
+	defer func() {
+		if err != nil {
+			err = validationErrorFrom(err)
+		}
+	}()
+
+	var val interface{}
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+
+	if val == nil {
+		return fmt.Errorf(`cannot accept null value for "any" type`)
 	}
+	return nil
+}
 
-	// maps can be "schemas" with types for specific entries and optional "required" constraints
-	if rawEntries, ok := constraints["schema"]; ok {
-		var entries map[string]json.RawMessage
-		if err := json.Unmarshal(rawEntries, &entries); err != nil {
-			return fmt.Errorf(`cannot parse map's "schema" constraint: %v`, err)
+func (v *anySchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	// no error because we're not explicitly rejecting unsupported keywords (for now)
+	return nil
+}
+
+func (v *anySchema) expectsConstraints() bool { return false }
+
+type numberSchema struct {
+	min      *float64
+	max      *float64
+	choices  []float64
+	constant *float64
+
+	// exclusiveMin and exclusiveMax bound the value without including the
+	// bounds themselves.
+	exclusiveMin *float64
+	exclusiveMax *float64
+
+	// multipleOf is a number the value must be a multiple of.
+	multipleOf *float64
+}
+
+// Validate that raw is a valid number and meets the schema's constraints.
+func (v *numberSchema) Validate(raw []byte) (err error) {
+	defer func() {
+		if err != nil {
+			err = validationErrorFrom(err)
 		}
+	}()
 
-		if err := validMapKeys(entries); err != nil {
-			return fmt.Errorf(`cannot parse map: %w`, err)
+	var num *float64
+	if err := json.Unmarshal(raw, &num); err != nil {
+		typeErr := &json.UnmarshalTypeError{}
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("expected number type but got %s", typeErr.Value)
 		}
+		return err
+	}
 
-		v.entrySchemas = make(map[string]Schema, len(entries))
-		for key, value := range entries {
-			entrySchema, err := v.topSchema.parse(value)
-			if err != nil {
-				return err
-			}
+	if num == nil {
+		return fmt.Errorf(`cannot accept null value for "number" type`)
+	}
 
-			v.entrySchemas[key] = entrySchema
-		}
+	if err := validateNumber(*num, v.constant, v.choices, v.min, v.max, v.exclusiveMin, v.exclusiveMax); err != nil {
+		return err
+	}
 
-		// "required" can be a list of keys or many lists of alternative combinations
-		if rawRequired, ok := constraints["required"]; ok {
-			var requiredCombs [][]string
-			if err := json.Unmarshal(rawRequired, &requiredCombs); err != nil {
-				var typeErr *json.UnmarshalTypeError
-				if !errors.As(err, &typeErr) {
-					return fmt.Errorf(`cannot parse map's "required" constraint: %v`, err)
-				}
+	if v.multipleOf != nil && !isMultipleOf(*num, *v.multipleOf) {
+		return fmt.Errorf(`%v is not a multiple of %v`, *num, *v.multipleOf)
+	}
 
-				var required []string
-				if err := json.Unmarshal(rawRequired, &required); err != nil {
-					return fmt.Errorf(`cannot parse map's "required" constraint: %v`, err)
-				}
+	return nil
+}
 
-				v.requiredCombs = [][]string{required}
-			} else {
-				v.requiredCombs = requiredCombs
-			}
+// isMultipleOf returns true if num is a multiple of divisor, allowing for the
+// imprecision of floating-point division (e.g., 0.3 is a multiple of 0.1).
+func isMultipleOf(num, divisor float64) bool {
+	quotient := num / divisor
+	return math.Abs(quotient-math.Round(quotient)) <= 1e-9*math.Max(1, math.Abs(quotient))
+}
 
-			for _, requiredComb := range v.requiredCombs {
-				for _, required := range requiredComb {
-					if _, ok := v.entrySchemas[required]; !ok {
-						return fmt.Errorf(`cannot parse map's "required" constraint: required key %q must have schema entry`, required)
-					}
-				}
+func validateNumber[Num ~int64 | ~float64](num Num, constant *Num, choices []Num, min, max, exclusiveMin, exclusiveMax *Num) error {
+	if constant != nil && num != *constant {
+		return fmt.Errorf(`%v is not the required constant %v`, num, *constant)
+	}
+
+	if len(choices) != 0 {
+		var found bool
+		for _, choice := range choices {
+			if num == choice {
+				found = true
+				break
 			}
 		}
 
-		return nil
+		if !found {
+			return fmt.Errorf(`%v is not one of the allowed choices`, num)
+		}
 	}
 
-	// map can not specify "schemas" and constrain the type of keys and values instead
-	rawKeyDef, ok := constraints["keys"]
-	if ok {
-		if v.keySchema, err = v.parseMapKeyType(rawKeyDef); err != nil {
-			return fmt.Errorf(`cannot parse "keys" constraint: %w`, err)
-		}
+	// these comparisons are susceptible to floating-point errors but given that
+	// this won't be used for general storage it should be precise enough
+	if min != nil && num < *min {
+		return fmt.Errorf(`%v is less than the allowed minimum %v`, num, *min)
 	}
 
-	rawValuesDef, ok := constraints["values"]
-	if ok {
-		v.valueSchema, err = v.topSchema.parse(rawValuesDef)
-		if err != nil {
-			return err
-		}
+	if max != nil && num > *max {
+		return fmt.Errorf(`%v is greater than the allowed maximum %v`, num, *max)
 	}
 
-	if v.entrySchemas == nil && v.keySchema == nil && v.valueSchema == nil {
-		return fmt.Errorf(`cannot parse map: must have "schema" or "keys"/"values" constraint`)
+	if exclusiveMin != nil && num <= *exclusiveMin {
+		return fmt.Errorf(`%v is not greater than the exclusive minimum %v`, num, *exclusiveMin)
+	}
+
+	if exclusiveMax != nil && num >= *exclusiveMax {
+		return fmt.Errorf(`%v is not less than the exclusive maximum %v`, num, *exclusiveMax)
 	}
 
 	return nil
 }
 
-// checkExclusiveMapConstraints checks if the map contains mutually exclusive constraints.
-func checkExclusiveMapConstraints(obj map[string]json.RawMessage) error {
-	has := func(k string) bool {
-		_, ok := obj[k]
-		return ok
+// parseExclusiveBounds parses the "exclusive-min" and "exclusive-max"
+// constraints of a numeric type, checking that they can be used along with
+// its other constraints and that they leave some values to be accepted.
+func parseExclusiveBounds[Num ~int64 | ~float64](constraints map[string]json.RawMessage, hasChoices bool, min, max *Num) (exclusiveMin, exclusiveMax *Num, err error) {
+	parseBound := func(name, inclusive string, inclusiveBound *Num) (*Num, error) {
+		rawBound, ok := constraints[name]
+		if !ok {
+			return nil, nil
+		}
+
+		if hasChoices {
+			return nil, fmt.Errorf(`cannot have "choices" and %q constraints`, name)
+		}
+
+		if inclusiveBound != nil {
+			return nil, fmt.Errorf(`cannot have %q and %q constraints`, inclusive, name)
+		}
+
+		var bound Num
+		if err := json.Unmarshal(rawBound, &bound); err != nil {
+			return nil, fmt.Errorf(`cannot parse %q constraint: %v`, name, err)
+		}
+		return &bound, nil
 	}
 
-	if has("required") && !has("schema") {
-		return fmt.Errorf(`cannot use "required" without "schema" constraint`)
+	if exclusiveMin, err = parseBound("exclusive-min", "min", min); err != nil {
+		return nil, nil, err
 	}
-	if has("schema") && has("keys") {
-		return fmt.Errorf(`cannot use "schema" and "keys" constraints simultaneously`)
+	if exclusiveMax, err = parseBound("exclusive-max", "max", max); err != nil {
+		return nil, nil, err
 	}
-	if has("schema") && has("values") {
-		return fmt.Errorf(`cannot use "schema" and "values" constraints simultaneously`)
+
+	switch {
+	case exclusiveMin != nil && exclusiveMax != nil && *exclusiveMin >= *exclusiveMax:
+		return nil, nil, fmt.Errorf(`cannot have "exclusive-min" constraint with value greater than or equal to "exclusive-max"`)
+	case exclusiveMin != nil && max != nil && *exclusiveMin >= *max:
+		return nil, nil, fmt.Errorf(`cannot have "exclusive-min" constraint with value greater than or equal to "max"`)
+	case min != nil && exclusiveMax != nil && *min >= *exclusiveMax:
+		return nil, nil, fmt.Errorf(`cannot have "min" constraint with value greater than or equal to "exclusive-max"`)
 	}
 
-	return nil
+	return exclusiveMin, exclusiveMax, nil
 }
 
-func (v *mapSchema) parseMapKeyType(raw json.RawMessage) (Schema, error) {
-	var typ string
-	if err := json.Unmarshal(raw, &typ); err != nil {
-		var typeErr *json.UnmarshalTypeError
-		if !errors.As(err, &typeErr) {
-			return nil, err
-		}
+// parseMultipleOf parses the "multiple-of" constraint of a numeric type.
+func parseMultipleOf[Num ~int64 | ~float64](constraints map[string]json.RawMessage, hasChoices bool) (*Num, error) {
+	rawMultipleOf, ok := constraints["multiple-of"]
+	if !ok {
+		return nil, nil
+	}
 
-		var schemaDef map[string]json.RawMessage
-		if err := json.Unmarshal(raw, &schemaDef); err != nil {
-			return nil, err
-		}
+	if hasChoices {
+		return nil, fmt.Errorf(`cannot have "choices" and "multiple-of" constraints`)
+	}
 
-		if rawType, ok := schemaDef["type"]; ok {
-			if err := json.Unmarshal(rawType, &typ); err != nil {
-				return nil, err
-			}
+	var multipleOf Num
+	if err := json.Unmarshal(rawMultipleOf, &multipleOf); err != nil {
+		return nil, fmt.Errorf(`cannot parse "multiple-of" constraint: %v`, err)
+	}
 
-			if typ != "string" {
-				return nil, fmt.Errorf(`must be based on string but got %q`, typ)
-			}
+	if multipleOf <= 0 {
+		return nil, fmt.Errorf(`cannot have non-positive "multiple-of" constraint`)
+	}
+	return &multipleOf, nil
+}
+
+func (v *numberSchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	constant, err := parseConstConstraint[float64](constraints, "choices", "min", "max", "exclusive-min", "exclusive-max", "multiple-of")
+	if err != nil {
+		return err
+	}
+	if constant != nil {
+		v.constant = constant
+	}
+
+	if rawChoices, ok := constraints["choices"]; ok {
+		var choices []float64
+		err := json.Unmarshal(rawChoices, &choices)
+		if err != nil {
+			return fmt.Errorf(`cannot parse "choices" constraint: %v`, err)
 		}
 
-		schema := &stringSchema{}
-		if err := schema.parseConstraints(schemaDef); err != nil {
-			return nil, err
+		if len(choices) == 0 {
+			return fmt.Errorf(`cannot have "choices" constraint with empty list`)
 		}
 
-		return schema, nil
+		v.choices = choices
 	}
 
-	if typ == "string" {
-		return &stringSchema{}, nil
+	if rawMin, ok := constraints["min"]; ok {
+		if v.choices != nil {
+			return fmt.Errorf(`cannot have "choices" and "min" constraints`)
+		}
+
+		var min float64
+		if err := json.Unmarshal(rawMin, &min); err != nil {
+			return fmt.Errorf(`cannot parse "min" constraint: %v`, err)
+		}
+		v.min = &min
 	}
 
-	if typ != "" && typ[0] == '$' {
-		userType, err := v.topSchema.getUserType(typ[1:])
-		if err != nil {
-			return nil, err
+	if rawMax, ok := constraints["max"]; ok {
+		if v.choices != nil {
+			return fmt.Errorf(`cannot have "choices" and "max" constraints`)
 		}
 
-		if !userType.isStringBased() {
-			return nil, fmt.Errorf(`key type %q must be based on string`, typ[1:])
+		var max float64
+		if err := json.Unmarshal(rawMax, &max); err != nil {
+			return fmt.Errorf(`cannot parse "max" constraint: %v`, err)
 		}
+		v.max = &max
+	}
 
-		return userType, nil
+	if v.min != nil && v.max != nil && *v.min > *v.max {
+		return fmt.Errorf(`cannot have "min" constraint with value greater than "max"`)
 	}
 
-	return nil, fmt.Errorf(`keys must be based on string but got %q`, typ)
+	exclusiveMin, exclusiveMax, err := parseExclusiveBounds(constraints, v.choices != nil, v.min, v.max)
+	if err != nil {
+		return err
+	}
+	if exclusiveMin != nil {
+		v.exclusiveMin = exclusiveMin
+	}
+	if exclusiveMax != nil {
+		v.exclusiveMax = exclusiveMax
+	}
+
+	multipleOf, err := parseMultipleOf[float64](constraints, v.choices != nil)
+	if err != nil {
+		return err
+	}
+	if multipleOf != nil {
+		v.multipleOf = multipleOf
+	}
+
+	return nil
 }
 
-func (v *mapSchema) expectsConstraints() bool { return true }
+func (v *numberSchema) expectsConstraints() bool { return false }
 
-type stringSchema struct {
-	// pattern is a regex pattern that the string must match.
-	pattern *regexp.Regexp
+// decimalSchema validates decimal numbers encoded as strings (e.g., "12.50"),
+// which are compared exactly rather than as floating-point numbers.
+type decimalSchema struct {
+	// precision is the maximum number of significant digits, if set.
+	precision *int
+	// scale is the maximum number of digits after the decimal point, if set.
+	scale *int
+
+	// min and max bound the value. minStr and maxStr hold them as they were
+	// defined, for error messages.
+	min    *big.Rat
+	max    *big.Rat
+	minStr string
+	maxStr string
+}
 
-	// choices holds the possible values the string can take, if non-empty.
-	choices []string
+var validDecimal = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.([0-9]+))?$`)
+
+// parseDecimal parses a decimal number returning its value along with its
+// numbers of significant digits and of digits after the decimal point.
+func parseDecimal(str string) (value *big.Rat, precision, scale int, err error) {
+	groups := validDecimal.FindStringSubmatch(str)
+	if groups == nil {
+		return nil, 0, 0, fmt.Errorf(`string %q is not a valid decimal number`, str)
+	}
+
+	value, ok := new(big.Rat).SetString(str)
+	if !ok {
+		return nil, 0, 0, fmt.Errorf(`string %q is not a valid decimal number`, str)
+	}
+
+	intDigits, fracDigits := groups[1], groups[3]
+	precision = len(fracDigits)
+	if intDigits != "0" {
+		precision += len(intDigits)
+	}
+	return value, precision, len(fracDigits), nil
 }
 
-// Validate that raw is a valid aspect string and meets the schema's constraints.
-func (v *stringSchema) Validate(raw []byte) (err error) {
+// Validate that raw is a valid decimal string and meets the schema's constraints.
+func (v *decimalSchema) Validate(raw []byte) (err error) {
 	defer func() {
 		if err != nil {
 			err = validationErrorFrom(err)
 		}
 	}()
 
-	var value *string
-	if err := json.Unmarshal(raw, &value); err != nil {
+	var str *string
+	if err := json.Unmarshal(raw, &str); err != nil {
 		typeErr := &json.UnmarshalTypeError{}
 		if errors.As(err, &typeErr) {
-			return fmt.Errorf("expected string type but got %s", typeErr.Value)
+			return fmt.Errorf("expected decimal string but got %s", typeErr.Value)
 		}
 		return err
 	}
 
-	if value == nil {
-		return fmt.Errorf(`cannot accept null value for "string" type`)
+	if str == nil {
+		return fmt.Errorf(`cannot accept null value for "decimal" type`)
 	}
 
-	if len(v.choices) != 0 && !strutil.ListContains(v.choices, *value) {
-		return fmt.Errorf(`string %q is not one of the allowed choices`, *value)
+	value, precision, scale, err := parseDecimal(*str)
+	if err != nil {
+		return err
 	}
 
-	if v.pattern != nil && !v.pattern.Match([]byte(*value)) {
-		return fmt.Errorf(`string %q doesn't match schema pattern %s`, *value, v.pattern.String())
+	if v.scale != nil && scale > *v.scale {
+		return fmt.Errorf(`decimal %q has more than %d digits after the decimal point`, *str, *v.scale)
+	}
+
+	if v.precision != nil && precision > *v.precision {
+		return fmt.Errorf(`decimal %q has more than %d significant digits`, *str, *v.precision)
+	}
+
+	if v.min != nil && value.Cmp(v.min) < 0 {
+		return fmt.Errorf(`decimal %q is less than the allowed minimum %s`, *str, v.minStr)
+	}
+
+	if v.max != nil && value.Cmp(v.max) > 0 {
+		return fmt.Errorf(`decimal %q is greater than the allowed maximum %s`, *str, v.maxStr)
 	}
 
 	return nil
 }
 
-func (v *stringSchema) parseConstraints(constraints map[string]json.RawMessage) error {
-	if rawChoices, ok := constraints["choices"]; ok {
-		var choices []string
-		if err := json.Unmarshal(rawChoices, &choices); err != nil {
-			return fmt.Errorf(`cannot parse "choices" constraint: %w`, err)
+func (v *decimalSchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	for _, name := range []string{"precision", "scale"} {
+		rawDigits, ok := constraints[name]
+		if !ok {
+			continue
 		}
 
-		if len(choices) == 0 {
-			return fmt.Errorf(`cannot have a "choices" constraint with an empty list`)
+		var digits int
+		if err := json.Unmarshal(rawDigits, &digits); err != nil {
+			return fmt.Errorf(`cannot parse %q constraint: %v`, name, err)
 		}
 
-		v.choices = choices
+		if name == "precision" {
+			if digits <= 0 {
+				return fmt.Errorf(`cannot have non-positive "precision" constraint`)
+			}
+			v.precision = &digits
+		} else {
+			if digits < 0 {
+				return fmt.Errorf(`cannot have negative "scale" constraint`)
+			}
+			v.scale = &digits
+		}
 	}
 
-	if rawPattern, ok := constraints["pattern"]; ok {
-		if v.choices != nil {
-			return fmt.Errorf(`cannot use "choices" and "pattern" constraints in same schema`)
+	if v.precision != nil && v.scale != nil && *v.scale > *v.precision {
+		return fmt.Errorf(`cannot have "scale" constraint with value greater than "precision"`)
+	}
+
+	for _, name := range []string{"min", "max"} {
+		rawBound, ok := constraints[name]
+		if !ok {
+			continue
 		}
 
-		var patt string
-		err := json.Unmarshal(rawPattern, &patt)
+		var str string
+		if err := json.Unmarshal(rawBound, &str); err != nil {
+			return fmt.Errorf(`cannot parse %q constraint: %v`, name, err)
+		}
+
+		bound, _, _, err := parseDecimal(str)
 		if err != nil {
-			return fmt.Errorf(`cannot parse "pattern" constraint: %w`, err)
+			return fmt.Errorf(`cannot parse %q constraint: %v`, name, err)
 		}
 
-		if v.pattern, err = regexp.Compile(patt); err != nil {
-			return fmt.Errorf(`cannot parse "pattern" constraint: %w`, err)
+		if name == "min" {
+			v.min, v.minStr = bound, str
+		} else {
+			v.max, v.maxStr = bound, str
 		}
 	}
 
+	if v.min != nil && v.max != nil && v.min.Cmp(v.max) > 0 {
+		return fmt.Errorf(`cannot have "min" constraint with value greater than "max"`)
+	}
+
 	return nil
 }
 
-func (v *stringSchema) expectsConstraints() bool { return false }
+func (v *decimalSchema) expectsConstraints() bool { return false }
 
-type intSchema struct {
-	min     *int64
-	max     *int64
-	choices []int64
+// datetimeSchema validates RFC 3339 timestamps (e.g., "2024-03-01T12:00:00Z").
+type datetimeSchema struct {
+	// min and max bound the value. minStr and maxStr hold them as they were
+	// defined, for error messages.
+	min    *time.Time
+	max    *time.Time
+	minStr string
+	maxStr string
 }
 
-// Validate that raw is a valid integer and meets the schema's constraints.
-func (v *intSchema) Validate(raw []byte) (err error) {
+func parseDatetime(str string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, str)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(`string %q is not a valid RFC 3339 timestamp`, str)
+	}
+	return t, nil
+}
+
+// Validate that raw is a valid RFC 3339 timestamp and meets the schema's
+// constraints.
+func (v *datetimeSchema) Validate(raw []byte) (err error) {
 	defer func() {
 		if err != nil {
 			err = validationErrorFrom(err)
 		}
 	}()
 
-	var num *int64
-	if err := json.Unmarshal(raw, &num); err != nil {
+	var str *string
+	if err := json.Unmarshal(raw, &str); err != nil {
 		typeErr := &json.UnmarshalTypeError{}
 		if errors.As(err, &typeErr) {
-			return fmt.Errorf("expected int type but got %s", typeErr.Value)
+			return fmt.Errorf("expected datetime string but got %s", typeErr.Value)
 		}
 		return err
 	}
 
-	if num == nil {
-		return fmt.Errorf(`cannot accept null value for "int" type`)
+	if str == nil {
+		return fmt.Errorf(`cannot accept null value for "datetime" type`)
 	}
 
-	return validateNumber(*num, v.choices, v.min, v.max)
-}
-
-func (v *intSchema) parseConstraints(constraints map[string]json.RawMessage) error {
-	if rawChoices, ok := constraints["choices"]; ok {
-		var choices []int64
-		err := json.Unmarshal(rawChoices, &choices)
-		if err != nil {
-			return fmt.Errorf(`cannot parse "choices" constraint: %v`, err)
-		}
+	value, err := parseDatetime(*str)
+	if err != nil {
+		return err
+	}
 
-		if len(choices) == 0 {
-			return fmt.Errorf(`cannot have "choices" constraint with empty list`)
-		}
+	if v.min != nil && value.Before(*v.min) {
+		return fmt.Errorf(`datetime %q is before the allowed minimum %s`, *str, v.minStr)
+	}
 
-		v.choices = choices
+	if v.max != nil && value.After(*v.max) {
+		return fmt.Errorf(`datetime %q is after the allowed maximum %s`, *str, v.maxStr)
 	}
 
-	if rawMin, ok := constraints["min"]; ok {
-		if v.choices != nil {
-			return fmt.Errorf(`cannot have "choices" and "min" constraints`)
+	return nil
+}
+
+func (v *datetimeSchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	for _, name := range []string{"min", "max"} {
+		rawBound, ok := constraints[name]
+		if !ok {
+			continue
 		}
 
-		var min int64
-		if err := json.Unmarshal(rawMin, &min); err != nil {
-			return fmt.Errorf(`cannot parse "min" constraint: %v`, err)
+		var str string
+		if err := json.Unmarshal(rawBound, &str); err != nil {
+			return fmt.Errorf(`cannot parse %q constraint: %v`, name, err)
 		}
-		v.min = &min
-	}
 
-	if rawMax, ok := constraints["max"]; ok {
-		if v.choices != nil {
-			return fmt.Errorf(`cannot have "choices" and "max" constraints`)
+		bound, err := parseDatetime(str)
+		if err != nil {
+			return fmt.Errorf(`cannot parse %q constraint: %v`, name, err)
 		}
 
-		var max int64
-		if err := json.Unmarshal(rawMax, &max); err != nil {
-			return fmt.Errorf(`cannot parse "max" constraint: %v`, err)
+		if name == "min" {
+			v.min, v.minStr = &bound, str
+		} else {
+			v.max, v.maxStr = &bound, str
 		}
-		v.max = &max
 	}
 
-	if v.min != nil && v.max != nil && *v.min > *v.max {
+	if v.min != nil && v.max != nil && v.min.After(*v.max) {
 		return fmt.Errorf(`cannot have "min" constraint with value greater than "max"`)
 	}
 
 	return nil
 }
 
-func (v *intSchema) expectsConstraints() bool { return false }
+func (v *datetimeSchema) expectsConstraints() bool { return false }
 
-type anySchema struct{}
+// durationSchema validates Go-style duration strings (e.g., "2h45m").
+type durationSchema struct {
+	// min and max bound the value. minStr and maxStr hold them as they were
+	// defined, for error messages.
+	min    *time.Duration
+	max    *time.Duration
+	minStr string
+	maxStr string
+}
 
-func (v *anySchema) Validate(raw []byte) (err error) {
+func parseDuration(str string) (time.Duration, error) {
+	d, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, fmt.Errorf(`string %q is not a valid duration`, str)
+	}
+	return d, nil
+}
+
+// Validate that raw is a valid duration string and meets the schema's
+// constraints.
+func (v *durationSchema) Validate(raw []byte) (err error) {
 	defer func() {
 		if err != nil {
 			err = validationErrorFrom(err)
 		}
 	}()
 
-	var val interface{}
-	if err := json.Unmarshal(raw, &val); err != nil {
+	var str *string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		typeErr := &json.UnmarshalTypeError{}
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("expected duration string but got %s", typeErr.Value)
+		}
 		return err
 	}
 
-	if val == nil {
-		return fmt.Errorf(`cannot accept null value for "any" type`)
+	if str == nil {
+		return fmt.Errorf(`cannot accept null value for "duration" type`)
+	}
+
+	value, err := parseDuration(*str)
+	if err != nil {
+		return err
+	}
+
+	if v.min != nil && value < *v.min {
+		return fmt.Errorf(`duration %q is less than the allowed minimum %s`, *str, v.minStr)
+	}
+
+	if v.max != nil && value > *v.max {
+		return fmt.Errorf(`duration %q is greater than the allowed maximum %s`, *str, v.maxStr)
 	}
-	return nil
-}
 
-func (v *anySchema) parseConstraints(constraints map[string]json.RawMessage) error {
-	// no error because we're not explicitly rejecting unsupported keywords (for now)
 	return nil
 }
 
-func (v *anySchema) expectsConstraints() bool { return false }
+func (v *durationSchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	for _, name := range []string{"min", "max"} {
+		rawBound, ok := constraints[name]
+		if !ok {
+			continue
+		}
 
-type numberSchema struct {
-	min     *float64
-	max     *float64
-	choices []float64
-}
+		var str string
+		if err := json.Unmarshal(rawBound, &str); err != nil {
+			return fmt.Errorf(`cannot parse %q constraint: %v`, name, err)
+		}
 
-// Validate that raw is a valid number and meets the schema's constraints.
-func (v *numberSchema) Validate(raw []byte) (err error) {
-	defer func() {
+		bound, err := parseDuration(str)
 		if err != nil {
-			err = validationErrorFrom(err)
+			return fmt.Errorf(`cannot parse %q constraint: %v`, name, err)
 		}
-	}()
 
-	var num *float64
-	if err := json.Unmarshal(raw, &num); err != nil {
-		typeErr := &json.UnmarshalTypeError{}
-		if errors.As(err, &typeErr) {
-			return fmt.Errorf("expected number type but got %s", typeErr.Value)
+		if name == "min" {
+			v.min, v.minStr = &bound, str
+		} else {
+			v.max, v.maxStr = &bound, str
 		}
-		return err
 	}
 
-	if num == nil {
-		return fmt.Errorf(`cannot accept null value for "number" type`)
+	if v.min != nil && v.max != nil && *v.min > *v.max {
+		return fmt.Errorf(`cannot have "min" constraint with value greater than "max"`)
 	}
 
-	return validateNumber(*num, v.choices, v.min, v.max)
+	return nil
 }
 
-func validateNumber[Num ~int64 | ~float64](num Num, choices []Num, min, max *Num) error {
-	if len(choices) != 0 {
-		var found bool
-		for _, choice := range choices {
-			if num == choice {
-				found = true
-				break
-			}
-		}
+func (v *durationSchema) expectsConstraints() bool { return false }
 
-		if !found {
-			return fmt.Errorf(`%v is not one of the allowed choices`, num)
+// enumSchema validates values that are one of a set of choices, which can mix
+// strings, numbers and booleans (e.g., ["auto", 0, false]).
+type enumSchema struct {
+	choices []interface{}
+}
+
+// Validate that raw is one of the enum's choices.
+func (v *enumSchema) Validate(raw []byte) (err error) {
+	defer func() {
+		if err != nil {
+			err = validationErrorFrom(err)
 		}
+	}()
+
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
 	}
 
-	// these comparisons are susceptible to floating-point errors but given that
-	// this won't be used for general storage it should be precise enough
-	if min != nil && num < *min {
-		return fmt.Errorf(`%v is less than the allowed minimum %v`, num, *min)
+	switch value.(type) {
+	case nil:
+		return fmt.Errorf(`cannot accept null value for "enum" type`)
+	case string, float64, bool:
+	default:
+		return fmt.Errorf(`expected string, number or bool type but got %s`, jsonTypeName(value))
 	}
 
-	if max != nil && num > *max {
-		return fmt.Errorf(`%v is greater than the allowed maximum %v`, num, *max)
+	for _, choice := range v.choices {
+		if choice == value {
+			return nil
+		}
 	}
 
-	return nil
+	// value was decoded from raw so it can be re-encoded
+	data, _ := json.Marshal(value)
+	return fmt.Errorf(`%s is not one of the allowed choices`, data)
 }
 
-func (v *numberSchema) parseConstraints(constraints map[string]json.RawMessage) error {
-	if rawChoices, ok := constraints["choices"]; ok {
-		var choices []float64
-		err := json.Unmarshal(rawChoices, &choices)
-		if err != nil {
-			return fmt.Errorf(`cannot parse "choices" constraint: %v`, err)
-		}
-
-		if len(choices) == 0 {
-			return fmt.Errorf(`cannot have "choices" constraint with empty list`)
-		}
+// jsonTypeName returns the name of the JSON type of a decoded value.
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return "null"
+	}
+}
 
-		v.choices = choices
+func (v *enumSchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	rawChoices, ok := constraints["choices"]
+	if !ok {
+		return fmt.Errorf(`cannot parse "enum": must have a "choices" constraint`)
 	}
 
-	if rawMin, ok := constraints["min"]; ok {
-		if v.choices != nil {
-			return fmt.Errorf(`cannot have "choices" and "min" constraints`)
-		}
+	var choices []interface{}
+	if err := json.Unmarshal(rawChoices, &choices); err != nil {
+		return fmt.Errorf(`cannot parse "choices" constraint: %v`, err)
+	}
 
-		var min float64
-		if err := json.Unmarshal(rawMin, &min); err != nil {
-			return fmt.Errorf(`cannot parse "min" constraint: %v`, err)
-		}
-		v.min = &min
+	if len(choices) == 0 {
+		return fmt.Errorf(`cannot have "choices" constraint with empty list`)
 	}
 
-	if rawMax, ok := constraints["max"]; ok {
-		if v.choices != nil {
-			return fmt.Errorf(`cannot have "choices" and "max" constraints`)
+	for i, choice := range choices {
+		switch choice.(type) {
+		case string, float64, bool:
+		default:
+			return fmt.Errorf(`cannot parse "choices" constraint: expected choice %d to be a string, number or bool but got %s`, i, jsonTypeName(choice))
 		}
 
-		var max float64
-		if err := json.Unmarshal(rawMax, &max); err != nil {
-			return fmt.Errorf(`cannot parse "max" constraint: %v`, err)
+		for _, prev := range choices[:i] {
+			if prev == choice {
+				data, _ := json.Marshal(choice)
+				return fmt.Errorf(`cannot parse "choices" constraint: duplicate choice %s`, data)
+			}
 		}
-		v.max = &max
-	}
-
-	if v.min != nil && v.max != nil && *v.min > *v.max {
-		return fmt.Errorf(`cannot have "min" constraint with value greater than "max"`)
 	}
 
+	v.choices = choices
 	return nil
 }
 
-func (v *numberSchema) expectsConstraints() bool { return false }
+func (v *enumSchema) expectsConstraints() bool { return true }
 
-type booleanSchema struct{}
+type booleanSchema struct {
+	constant *bool
+}
 
 func (v *booleanSchema) Validate(raw []byte) (err error) {
 	defer func() {
@@ -799,10 +2837,22 @@ func (v *booleanSchema) Validate(raw []byte) (err error) {
 		return fmt.Errorf(`cannot accept null value for "bool" type`)
 	}
 
+	if v.constant != nil && *val != *v.constant {
+		return fmt.Errorf(`%t is not the required constant %t`, *val, *v.constant)
+	}
+
 	return nil
 }
 
-func (v *booleanSchema) parseConstraints(map[string]json.RawMessage) error {
+func (v *booleanSchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	constant, err := parseConstConstraint[bool](constraints)
+	if err != nil {
+		return err
+	}
+	if constant != nil {
+		v.constant = constant
+	}
+
 	// no error because we're not explicitly rejecting unsupported keywords (for now)
 	return nil
 }
@@ -817,8 +2867,16 @@ type arraySchema struct {
 	// validate them.
 	elementType Schema
 
+	// items holds the types of the elements of a tuple array, by position,
+	// and is used instead of elementType if set.
+	items []Schema
+
 	// unique is true if the array should not contain duplicates.
 	unique bool
+
+	// minLen and maxLen bound the number of elements in the array.
+	minLen *int
+	maxLen *int
 }
 
 func (v *arraySchema) Validate(raw []byte) error {
@@ -835,8 +2893,20 @@ func (v *arraySchema) Validate(raw []byte) error {
 		return validationErrorf(`cannot accept null value for "array" type`)
 	}
 
+	if v.items != nil && len(*array) != len(v.items) {
+		return validationErrorf(`cannot accept array with %d elements, expected %d`, len(*array), len(v.items))
+	}
+
+	if v.minLen != nil && len(*array) < *v.minLen {
+		return validationErrorf(`cannot accept array with fewer than %d elements`, *v.minLen)
+	}
+
+	if v.maxLen != nil && len(*array) > *v.maxLen {
+		return validationErrorf(`cannot accept array with more than %d elements`, *v.maxLen)
+	}
+
 	for e, val := range *array {
-		if err := v.elementType.Validate([]byte(val)); err != nil {
+		if err := v.elementSchema(e).Validate([]byte(val)); err != nil {
 			var vErr *ValidationError
 			if errors.As(err, &vErr) {
 				vErr.Path = append([]interface{}{e}, vErr.Path...)
@@ -860,19 +2930,36 @@ func (v *arraySchema) Validate(raw []byte) error {
 	return nil
 }
 
-func (v *arraySchema) parseConstraints(constraints map[string]json.RawMessage) error {
-	rawValues, ok := constraints["values"]
-	if !ok {
-		return fmt.Errorf(`cannot parse "array": must have "values" constraint`)
+// elementSchema returns the schema of the element at the given position.
+func (v *arraySchema) elementSchema(pos int) Schema {
+	if v.items != nil {
+		return v.items[pos]
 	}
+	return v.elementType
+}
 
-	typ, err := v.topSchema.parse(rawValues)
-	if err != nil {
-		return fmt.Errorf(`cannot parse "array" values type: %v`, err)
+func (v *arraySchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	rawValues, hasValues := constraints["values"]
+	rawItems, hasItems := constraints["items"]
+	switch {
+	case hasValues && hasItems:
+		return fmt.Errorf(`cannot parse "array": cannot have both "values" and "items" constraints`)
+	case hasItems:
+		items, err := v.parseItems(rawItems)
+		if err != nil {
+			return err
+		}
+		v.items = items
+	case hasValues:
+		typ, err := v.topSchema.parse(rawValues)
+		if err != nil {
+			return fmt.Errorf(`cannot parse "array" values type: %v`, err)
+		}
+		v.elementType = typ
+	default:
+		return fmt.Errorf(`cannot parse "array": must have "values" or "items" constraint`)
 	}
 
-	v.elementType = typ
-
 	if rawUnique, ok := constraints["unique"]; ok {
 		var unique bool
 		if err := json.Unmarshal(rawUnique, &unique); err != nil {
@@ -882,11 +2969,128 @@ func (v *arraySchema) parseConstraints(constraints map[string]json.RawMessage) e
 		v.unique = unique
 	}
 
+	if rawMinLen, ok := constraints["min-len"]; ok {
+		var minLen int
+		if err := json.Unmarshal(rawMinLen, &minLen); err != nil {
+			return fmt.Errorf(`cannot parse array's "min-len" constraint: %v`, err)
+		}
+
+		if minLen < 0 {
+			return fmt.Errorf(`cannot have negative array "min-len" constraint`)
+		}
+		v.minLen = &minLen
+	}
+
+	if rawMaxLen, ok := constraints["max-len"]; ok {
+		var maxLen int
+		if err := json.Unmarshal(rawMaxLen, &maxLen); err != nil {
+			return fmt.Errorf(`cannot parse array's "max-len" constraint: %v`, err)
+		}
+
+		if maxLen < 0 {
+			return fmt.Errorf(`cannot have negative array "max-len" constraint`)
+		}
+		v.maxLen = &maxLen
+	}
+
+	if v.minLen != nil && v.maxLen != nil && *v.minLen > *v.maxLen {
+		return fmt.Errorf(`cannot have array "min-len" constraint with value greater than "max-len"`)
+	}
+
+	if v.items != nil && (v.minLen != nil || v.maxLen != nil) {
+		return fmt.Errorf(`cannot have "items" and "min-len" or "max-len" constraints: the length of the array is fixed`)
+	}
+
 	return nil
 }
 
+// parseItems parses the ordered list of types of the elements of a tuple
+// array.
+func (v *arraySchema) parseItems(raw json.RawMessage) ([]Schema, error) {
+	var rawItems []json.RawMessage
+	if err := json.Unmarshal(raw, &rawItems); err != nil {
+		return nil, fmt.Errorf(`cannot parse "array" items: %v`, err)
+	}
+
+	if len(rawItems) == 0 {
+		return nil, fmt.Errorf(`cannot parse "array" items: must have at least one type`)
+	}
+
+	items := make([]Schema, 0, len(rawItems))
+	for i, rawItem := range rawItems {
+		typ, err := v.topSchema.parse(rawItem)
+		if err != nil {
+			return nil, fmt.Errorf(`cannot parse "array" item %d type: %v`, i, err)
+		}
+		items = append(items, typ)
+	}
+	return items, nil
+}
+
 func (v *arraySchema) expectsConstraints() bool { return true }
 
+// altSchema validates values that match at least one of several types.
+type altSchema struct {
+	// topSchema is the schema for the top-level schema which contains the user types.
+	topSchema *StorageSchema
+
+	// alternatives holds the types a value may match, in order.
+	alternatives []Schema
+}
+
+// Validate that raw matches at least one of the alternative types.
+func (v *altSchema) Validate(raw []byte) error {
+	errs := make([]string, 0, len(v.alternatives))
+	for _, alt := range v.alternatives {
+		err := alt.Validate(raw)
+		if err == nil {
+			return nil
+		}
+
+		var vErr *ValidationError
+		if errors.As(err, &vErr) && len(vErr.Path) == 0 {
+			// the path is the same as the alternative's, so only keep the reason
+			err = vErr.Err
+		}
+		errs = append(errs, err.Error())
+	}
+
+	return validationErrorf("no matching alternative type: %s", strings.Join(errs, "; "))
+}
+
+func (v *altSchema) parseConstraints(constraints map[string]json.RawMessage) error {
+	rawAlternatives, ok := constraints["alternatives"]
+	if !ok {
+		return fmt.Errorf(`cannot parse "alt": must have "alternatives" constraint`)
+	}
+
+	var alternatives []json.RawMessage
+	if err := json.Unmarshal(rawAlternatives, &alternatives); err != nil {
+		return fmt.Errorf(`cannot parse "alternatives" constraint: %w`, err)
+	}
+
+	return v.parseAlternatives(alternatives)
+}
+
+func (v *altSchema) parseAlternatives(alternatives []json.RawMessage) error {
+	if len(alternatives) == 0 {
+		return fmt.Errorf(`cannot parse alternative types: must have at least one type`)
+	}
+
+	v.alternatives = make([]Schema, 0, len(alternatives))
+	for _, rawAlt := range alternatives {
+		alt, err := v.topSchema.parse(rawAlt)
+		if err != nil {
+			return fmt.Errorf(`cannot parse alternative type: %w`, err)
+		}
+		v.alternatives = append(v.alternatives, alt)
+	}
+
+	return nil
+}
+
+func (v *altSchema) expectsConstraints() bool { return true }
+
 type ValidationError struct {
 	Path []interface{}
 	Err  error
@@ -22,8 +22,10 @@ package aspects_test
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/snapcore/snapd/aspects"
+	"github.com/snapcore/snapd/testutil"
 	. "gopkg.in/check.v1"
 )
 
@@ -156,6 +158,343 @@ func (*schemaSuite) TestMapWithUnexpectedKey(c *C) {
 	err = schema.Validate(input)
 	c.Assert(err, ErrorMatches, `cannot accept element in "snaps": map contains unexpected key "bar"`)
 }
+
+func (*schemaSuite) TestMapWithAdditionalKeys(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"snaps": {
+			"schema": {
+				"foo": "int"
+			},
+			"additional-keys": true
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"snaps": {"foo": 1, "bar": "cba", "baz": {"a": [1]}}}`))
+	c.Assert(err, IsNil)
+
+	// the known keys are still validated
+	err = schema.Validate([]byte(`{"snaps": {"foo": "abc", "bar": "cba"}}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "snaps.foo": expected int type but got string`)
+}
+
+func (*schemaSuite) TestMapWithAdditionalKeysFalse(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"snaps": {
+			"schema": {
+				"foo": "string"
+			},
+			"additional-keys": false
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"snaps": {"foo": "abc", "bar": "cba"}}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "snaps": map contains unexpected key "bar"`)
+}
+
+func (*schemaSuite) TestMapWithAdditionalKeysSchema(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"secret": {
+			"type": "string",
+			"sensitive": true
+		}
+	},
+	"schema": {
+		"snaps": {
+			"schema": {
+				"foo": "int"
+			},
+			"additional-keys": {
+				"schema": {
+					"token": "$secret",
+					"enabled": {
+						"type": "bool",
+						"default": true
+					}
+				}
+			}
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{"snaps": {"foo": 1, "bar": {"token": "abc"}}}`)
+	c.Assert(schema.Validate(input), IsNil)
+
+	err = schema.Validate([]byte(`{"snaps": {"foo": 1, "bar": {"token": 1}}}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "snaps.bar.token": expected string type but got number`)
+
+	err = schema.Validate([]byte(`{"snaps": {"foo": 1, "bar": "abc"}}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "snaps.bar": expected map type but got string`)
+
+	values, err := schema.SensitiveValues(input)
+	c.Assert(err, IsNil)
+	c.Check(values, DeepEquals, []string{"abc"})
+
+	withDefaults, err := schema.ApplyDefaults(input)
+	c.Assert(err, IsNil)
+	c.Check(string(withDefaults), Equals, `{"snaps":{"bar":{"enabled":true,"token":"abc"},"foo":1}}`)
+}
+
+func (*schemaSuite) TestMapAdditionalKeysBadDefinitions(c *C) {
+	type testcase struct {
+		schema string
+		err    string
+	}
+
+	for _, tc := range []testcase{
+		{
+			schema: `{"keys": "string", "additional-keys": true}`,
+			err:    `cannot parse map: cannot use "additional-keys" without "schema" constraint`,
+		},
+		{
+			schema: `{"schema": {"foo": "int"}, "additional-keys": "foo"}`,
+			err:    `cannot parse map's "additional-keys" constraint: cannot parse unknown type "foo"`,
+		},
+		{
+			schema: `{"schema": {"foo": "int"}, "additional-keys": 1}`,
+			err:    `cannot parse map's "additional-keys" constraint: .*`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{"schema": {"snaps": %s}}`, tc.schema))
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("schema: %s", tc.schema))
+	}
+}
+
+func (*schemaSuite) TestMapWithSchemaAndKeysValues(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"settings": {
+			"schema": {
+				"brightness": {
+					"type": "int",
+					"default": 50
+				},
+				"theme": "string"
+			},
+			"required": ["theme"],
+			"keys": {
+				"type": "string",
+				"pattern": "^x-"
+			},
+			"values": {
+				"type": "string",
+				"sensitive": true
+			}
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{"settings": {"theme": "dark", "x-vendor": "abc"}}`)
+	c.Assert(schema.Validate(input), IsNil)
+
+	// the known keys are validated against their own schemas
+	err = schema.Validate([]byte(`{"settings": {"theme": "dark", "brightness": "high"}}`))
+	c.Check(err, ErrorMatches, `cannot accept element in "settings.brightness": expected int type but got string`)
+
+	err = schema.Validate([]byte(`{"settings": {"brightness": 10}}`))
+	c.Check(err, ErrorMatches, `cannot accept element in "settings": cannot find required combinations of keys`)
+
+	// and the others against "keys" and "values"
+	err = schema.Validate([]byte(`{"settings": {"theme": "dark", "vendor": "abc"}}`))
+	c.Check(err, ErrorMatches, `cannot accept element in "settings.vendor": string "vendor" doesn't match schema pattern .*`)
+
+	err = schema.Validate([]byte(`{"settings": {"theme": "dark", "x-vendor": 1}}`))
+	c.Check(err, ErrorMatches, `cannot accept element in "settings.x-vendor": expected string type but got number`)
+
+	values, err := schema.SensitiveValues(input)
+	c.Assert(err, IsNil)
+	c.Check(values, DeepEquals, []string{"abc"})
+
+	withDefaults, err := schema.ApplyDefaults(input)
+	c.Assert(err, IsNil)
+	c.Check(string(withDefaults), Equals, `{"settings":{"brightness":50,"theme":"dark","x-vendor":"abc"}}`)
+}
+
+func (*schemaSuite) TestMapWithSchemaAndOnlyKeysOrValues(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"schema": {
+				"a": "int"
+			},
+			"keys": {
+				"type": "string",
+				"choices": ["b", "c"]
+			}
+		},
+		"bar": {
+			"schema": {
+				"a": "int"
+			},
+			"values": "bool"
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	c.Check(schema.Validate([]byte(`{"foo": {"a": 1, "b": "anything"}, "bar": {"a": 1, "baz": true}}`)), IsNil)
+
+	err = schema.Validate([]byte(`{"foo": {"a": 1, "d": 1}}`))
+	c.Check(err, ErrorMatches, `cannot accept element in "foo.d": string "d" is not one of the allowed choices`)
+
+	err = schema.Validate([]byte(`{"bar": {"a": true}}`))
+	c.Check(err, ErrorMatches, `cannot accept element in "bar.a": expected int type but got bool`)
+}
+
+func (*schemaSuite) TestMapEntriesConstraints(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"wifi-networks": {
+			"keys": "string",
+			"values": "string",
+			"min-entries": 1,
+			"max-entries": 2
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	type testcase struct {
+		input string
+		err   string
+	}
+
+	for _, tc := range []testcase{
+		{
+			input: `{"wifi-networks": {"home": "psk"}}`,
+		},
+		{
+			input: `{"wifi-networks": {"home": "psk", "work": "eap"}}`,
+		},
+		{
+			input: `{"wifi-networks": {}}`,
+			err:   `cannot accept element in "wifi-networks": cannot accept map with fewer than 1 entries`,
+		},
+		{
+			input: `{"wifi-networks": {"home": "psk", "work": "eap", "cafe": "none"}}`,
+			err:   `cannot accept element in "wifi-networks": cannot accept map with more than 2 entries`,
+		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		if tc.err == "" {
+			c.Check(err, IsNil, Commentf("input: %s", tc.input))
+		} else {
+			c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+		}
+	}
+}
+
+func (*schemaSuite) TestMapSchemaWithAdditionalKeysEntriesConstraints(c *C) {
+	for _, additional := range []string{`"additional-keys": "string"`, `"values": "string"`} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"wifi": {
+			"schema": {
+				"default": "string"
+			},
+			%s,
+			"min-entries": 2,
+			"max-entries": 3
+		}
+	}
+}`, additional))
+
+		schema, err := aspects.ParseSchema(schemaStr)
+		c.Assert(err, IsNil, Commentf("%s", additional))
+
+		for _, tc := range []struct {
+			input string
+			err   string
+		}{
+			{input: `{"wifi": {"default": "home", "home": "psk"}}`},
+			{input: `{"wifi": {"home": "psk", "work": "eap", "cafe": "none"}}`},
+			{
+				// schema entries count as well
+				input: `{"wifi": {"default": "home"}}`,
+				err:   `cannot accept element in "wifi": cannot accept map with fewer than 2 entries`,
+			},
+			{
+				input: `{"wifi": {"default": "home", "home": "psk", "work": "eap", "cafe": "none"}}`,
+				err:   `cannot accept element in "wifi": cannot accept map with more than 3 entries`,
+			},
+		} {
+			err = schema.Validate([]byte(tc.input))
+			if tc.err == "" {
+				c.Check(err, IsNil, Commentf("input: %s", tc.input))
+			} else {
+				c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+			}
+		}
+	}
+}
+
+func (*schemaSuite) TestMapBadEntriesConstraints(c *C) {
+	type testcase struct {
+		schema string
+		err    string
+	}
+
+	for _, tc := range []testcase{
+		{
+			schema: `{"values": "string", "min-entries": "1"}`,
+			err:    `cannot parse map's "min-entries" constraint: json: cannot unmarshal string into Go value of type int`,
+		},
+		{
+			schema: `{"values": "string", "max-entries": true}`,
+			err:    `cannot parse map's "max-entries" constraint: json: cannot unmarshal bool into Go value of type int`,
+		},
+		{
+			schema: `{"values": "string", "min-entries": -1}`,
+			err:    `cannot have negative map "min-entries" constraint`,
+		},
+		{
+			schema: `{"keys": "string", "max-entries": -1}`,
+			err:    `cannot have negative map "max-entries" constraint`,
+		},
+		{
+			schema: `{"values": "string", "min-entries": 2, "max-entries": 1}`,
+			err:    `cannot have map "min-entries" constraint with value greater than "max-entries"`,
+		},
+		{
+			schema: `{"schema": {"foo": "int"}, "max-entries": 1}`,
+			err:    `cannot parse map: cannot use "min-entries" or "max-entries" with "schema" constraint unless the map allows additional keys`,
+		},
+		{
+			schema: `{"schema": {"foo": "int"}, "additional-keys": false, "min-entries": 1}`,
+			err:    `cannot parse map: cannot use "min-entries" or "max-entries" with "schema" constraint unless the map allows additional keys`,
+		},
+		{
+			schema: `{"schema": {"foo": "int"}, "values": "int", "min-entries": -1}`,
+			err:    `cannot have negative map "min-entries" constraint`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{"schema": {"snaps": %s}}`, tc.schema))
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("schema: %s", tc.schema))
+	}
+}
+
 func (*schemaSuite) TestMapWithKeysStringConstraintHappy(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
@@ -478,20 +817,22 @@ func (*schemaSuite) TestMapInvalidConstraintCombos(c *C) {
 
 	tcs := []testcase{
 		{
-			name: "schema and keys",
+			name: "additional-keys and keys",
 			snippet: `{
-	"schema": { "foo": "bar" },
+	"schema": { "foo": "string" },
+	"additional-keys": true,
 	"keys": "string"
 }`,
-			err: `cannot parse map: cannot use "schema" and "keys" constraints simultaneously`,
+			err: `cannot parse map: cannot use "additional-keys" with "keys" or "values" constraints`,
 		},
 		{
-			name: "schema and values",
+			name: "additional-keys and values",
 			snippet: `{
-	"schema": { "foo": "bar" },
+	"schema": { "foo": "string" },
+	"additional-keys": "int",
 	"values": "string"
 }`,
-			err: `cannot parse map: cannot use "schema" and "values" constraints simultaneously`,
+			err: `cannot parse map: cannot use "additional-keys" with "keys" or "values" constraints`,
 		},
 		{
 			name: "required w/o schema",
@@ -701,43 +1042,20 @@ func (*schemaSuite) TestStringChoicesWrongFormat(c *C) {
 	c.Assert(err, ErrorMatches, `cannot parse "choices" constraint:.*`)
 }
 
-func (*schemaSuite) TestStringBasedUserType(c *C) {
+func (*schemaSuite) TestStringFormatHappy(c *C) {
 	schemaStr := []byte(`{
-	"types": {
-		"snap-name": {
+	"schema": {
+		"hostname": {
 			"type": "string",
-			"pattern": "^[a-z0-9-]*[a-z][a-z0-9-]*$"
+			"format": "hostname"
 		},
-		"status": {
+		"timezone": {
 			"type": "string",
-			"choices": ["active", "inactive"]
-		}
-	},
-	"schema": {
-		"snaps": {
-			"keys": "$snap-name",
-			"values": {
-				"schema": {
-					"name": "$snap-name",
-					"version": "string",
-					"status": "$status"
-				}
-			}
-		}
-	}
-}`)
-
-	input := []byte(`{
-	"snaps": {
-		"core20": {
-			"name": "core20",
-			"version": "20230503",
-			"status": "active"
+			"format": "timezone"
 		},
-		"snapd": {
-			"name": "snapd",
-			"version": "2.59.5+git948.gb447044",
-			"status": "inactive"
+		"locale": {
+			"type": "string",
+			"format": "locale"
 		}
 	}
 }`)
@@ -745,92 +1063,235 @@ func (*schemaSuite) TestStringBasedUserType(c *C) {
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	err = schema.Validate(input)
-	c.Assert(err, IsNil)
+	for _, input := range []string{
+		`{"hostname": "my-device.local", "timezone": "America/Argentina/Cordoba", "locale": "en_US.UTF-8"}`,
+		`{"hostname": "ubuntu", "timezone": "UTC", "locale": "C"}`,
+		`{"timezone": "Etc/GMT+1", "locale": "sr_RS.UTF-8@latin"}`,
+	} {
+		err = schema.Validate([]byte(input))
+		c.Check(err, IsNil, Commentf("input: %s", input))
+	}
 }
 
-func (*schemaSuite) TestMapKeyMustBeStringUserType(c *C) {
+func (*schemaSuite) TestStringFormatNoMatch(c *C) {
 	schemaStr := []byte(`{
-	"types": {
-		"key-type": {
-			"type": "map",
-			"schema": {}
-		}
-	},
 	"schema": {
-		"snaps": {
-			"keys": "$key-type"
+		"hostname": {
+			"type": "string",
+			"format": "hostname"
+		},
+		"timezone": {
+			"type": "string",
+			"format": "timezone"
+		},
+		"locale": {
+			"type": "string",
+			"format": "locale"
 		}
 	}
 }`)
 
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse "keys" constraint: key type "key-type" must be based on string`)
-}
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
 
-func (*schemaSuite) TestUserDefinedTypesWrongFormat(c *C) {
-	schemaStr := []byte(`{
-	"types": ["foo"],
-	"schema": {}
-}`)
+	type testcase struct {
+		input string
+		err   string
+	}
 
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse user-defined types map: json: cannot unmarshal.*`)
+	for _, tc := range []testcase{
+		{
+			input: `{"hostname": "-foo"}`,
+			err:   `cannot accept element in "hostname": string "-foo" is not a valid hostname`,
+		},
+		{
+			input: fmt.Sprintf(`{"hostname": "%s"}`, strings.Repeat("x", 65)),
+			err:   `cannot accept element in "hostname": string "x+" is not a valid hostname`,
+		},
+		{
+			input: `{"timezone": "no/triple/slash/"}`,
+			err:   `cannot accept element in "timezone": string "no/triple/slash/" is not a valid timezone`,
+		},
+		{
+			input: `{"locale": "en_us"}`,
+			err:   `cannot accept element in "locale": string "en_us" is not a valid locale`,
+		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+	}
 }
 
-func (*schemaSuite) TestBadUserDefinedType(c *C) {
-	schemaStr := []byte(`{
-	"types": {
-		"mytype": {
-			"type": "bad-type"
+func (*schemaSuite) TestStringFormatFail(c *C) {
+	type testcase struct {
+		constraints string
+		err         string
+	}
+
+	for _, tc := range []testcase{
+		{
+			constraints: `"format": "email"`,
+			err:         `cannot parse "format" constraint: unknown format "email"`,
+		},
+		{
+			constraints: `"format": 1`,
+			err:         `cannot parse "format" constraint: .*`,
+		},
+		{
+			constraints: `"format": "hostname", "choices": ["foo"]`,
+			err:         `cannot use "choices" and "format" constraints in same schema`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": "string",
+			%s
 		}
-	},
-	"schema": {}
-}`)
+	}
+}`, tc.constraints))
 
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse user-defined type "mytype": cannot parse unknown type "bad-type"`)
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("constraints: %s", tc.constraints))
+	}
 }
 
-func (*schemaSuite) TestUnknownUserDefinedType(c *C) {
+func (*schemaSuite) TestStringLengthHappy(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
-		"snaps": {
-			"values": "$foo"
+		"name": {
+			"type": "string",
+			"min-length": 2,
+			"max-length": 4
+		},
+		"serial": {
+			"type": "string",
+			"max-length": 0
 		}
 	}
 }`)
 
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot find user-defined type "foo"`)
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, input := range []string{
+		`{"name": "ab"}`,
+		`{"name": "abcd"}`,
+		// lengths are in characters, not bytes
+		`{"name": "ñañá"}`,
+		`{"serial": ""}`,
+	} {
+		err = schema.Validate([]byte(input))
+		c.Check(err, IsNil, Commentf("input: %s", input))
+	}
 }
 
-func (*schemaSuite) TestUnknownUserDefinedTypeInKeys(c *C) {
+func (*schemaSuite) TestStringLengthNoMatch(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
-		"snaps": {
-			"keys": "$foo"
+		"name": {
+			"type": "string",
+			"min-length": 2,
+			"max-length": 4
 		}
 	}
 }`)
 
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse "keys" constraint: cannot find user-defined type "foo"`)
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	type testcase struct {
+		input string
+		err   string
+	}
+
+	for _, tc := range []testcase{
+		{
+			input: `{"name": "a"}`,
+			err:   `cannot accept element in "name": string "a" is shorter than the allowed minimum length 2`,
+		},
+		{
+			input: `{"name": "abcde"}`,
+			err:   `cannot accept element in "name": string "abcde" is longer than the allowed maximum length 4`,
+		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+	}
 }
 
-func (*schemaSuite) TestMapBasedUserDefinedTypeHappy(c *C) {
+func (*schemaSuite) TestStringLengthFail(c *C) {
+	type testcase struct {
+		constraints string
+		err         string
+	}
+
+	for _, tc := range []testcase{
+		{
+			constraints: `"min-length": "1"`,
+			err:         `cannot parse "min-length" constraint: .*`,
+		},
+		{
+			constraints: `"max-length": 1.5`,
+			err:         `cannot parse "max-length" constraint: .*`,
+		},
+		{
+			constraints: `"min-length": -1`,
+			err:         `cannot have negative "min-length" constraint`,
+		},
+		{
+			constraints: `"max-length": -1`,
+			err:         `cannot have negative "max-length" constraint`,
+		},
+		{
+			constraints: `"min-length": 3, "max-length": 2`,
+			err:         `cannot have "min-length" constraint with value greater than "max-length"`,
+		},
+		{
+			constraints: `"min-length": 1, "choices": ["foo"]`,
+			err:         `cannot use "choices" and "min-length" constraints in same schema`,
+		},
+		{
+			constraints: `"max-length": 1, "choices": ["foo"]`,
+			err:         `cannot use "choices" and "max-length" constraints in same schema`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": "string",
+			%s
+		}
+	}
+}`, tc.constraints))
+
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("constraints: %s", tc.constraints))
+	}
+}
+
+func (*schemaSuite) TestStringBasedUserType(c *C) {
 	schemaStr := []byte(`{
 	"types": {
-		"snap": {
-			"schema": {
-				"name": "string",
-				"status": "string"
-			}
+		"snap-name": {
+			"type": "string",
+			"pattern": "^[a-z0-9-]*[a-z][a-z0-9-]*$"
+		},
+		"status": {
+			"type": "string",
+			"choices": ["active", "inactive"]
 		}
 	},
 	"schema": {
 		"snaps": {
-			"values": "$snap"
+			"keys": "$snap-name",
+			"values": {
+				"schema": {
+					"name": "$snap-name",
+					"version": "string",
+					"status": "$status"
+				}
+			}
 		}
 	}
 }`)
@@ -839,10 +1300,12 @@ func (*schemaSuite) TestMapBasedUserDefinedTypeHappy(c *C) {
 	"snaps": {
 		"core20": {
 			"name": "core20",
+			"version": "20230503",
 			"status": "active"
 		},
 		"snapd": {
 			"name": "snapd",
+			"version": "2.59.5+git948.gb447044",
 			"status": "inactive"
 		}
 	}
@@ -855,338 +1318,423 @@ func (*schemaSuite) TestMapBasedUserDefinedTypeHappy(c *C) {
 	c.Assert(err, IsNil)
 }
 
-func (*schemaSuite) TestUserTypeReferenceDoesntRequireConstraints(c *C) {
-	// references to user-defined types don't require need constraints
+func (*schemaSuite) TestMapKeyMustBeStringUserType(c *C) {
 	schemaStr := []byte(`{
 	"types": {
-		"my-type": {
-			"schema": {
-				"foo": "string"
-			}
+		"key-type": {
+			"type": "map",
+			"schema": {}
 		}
 	},
 	"schema": {
-		"a": "$my-type"
+		"snaps": {
+			"keys": "$key-type"
+		}
 	}
 }`)
 
 	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, IsNil)
+	c.Assert(err, ErrorMatches, `cannot parse "keys" constraint: key type "key-type" must be based on string`)
+}
 
+func (*schemaSuite) TestUserDefinedTypesWrongFormat(c *C) {
+	schemaStr := []byte(`{
+	"types": ["foo"],
+	"schema": {}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse user-defined types map: json: cannot unmarshal.*`)
 }
 
-func (*schemaSuite) TestMapInUserTypeRequiresConstraints(c *C) {
-	// maps still require constraints even within user-defined types
+func (*schemaSuite) TestBadUserDefinedType(c *C) {
 	schemaStr := []byte(`{
 	"types": {
-		"my-type": "map"
+		"mytype": {
+			"type": "bad-type"
+		}
 	},
+	"schema": {}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse user-defined type "mytype": cannot parse unknown type "bad-type"`)
+}
+
+func (*schemaSuite) TestUnknownUserDefinedType(c *C) {
+	schemaStr := []byte(`{
 	"schema": {
-		"a": "$my-type"
+		"snaps": {
+			"values": "$foo"
+		}
 	}
 }`)
 
 	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse user-defined type "my-type": cannot parse "map": must be schema definition with constraints`)
+	c.Assert(err, ErrorMatches, `cannot find user-defined type "foo"`)
 }
 
-func (*schemaSuite) TestMapBasedUserDefinedTypeFail(c *C) {
+func (*schemaSuite) TestUnknownUserDefinedTypeInKeys(c *C) {
 	schemaStr := []byte(`{
-	"types": {
-		"snap": {
-			"schema": {
-				"name": "string",
-				"version": "string"
-			}
-		}
-	},
 	"schema": {
 		"snaps": {
-			"values": "$snap"
+			"keys": "$foo"
 		}
 	}
 }`)
 
-	input := []byte(`{
-	"snaps": {
-		"core20": {
-			"name": "core20",
-			"version": 123
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse "keys" constraint: cannot find user-defined type "foo"`)
+}
+
+func (*schemaSuite) TestUserTypesReferenceEachOther(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"interfaces": {
+			"type": "array",
+			"values": "$iface"
+		},
+		"iface": {
+			"schema": {
+				"name": "$name",
+				"mtu": "int"
+			}
+		},
+		"name": {
+			"type": "string",
+			"pattern": "^[a-z0-9]+$"
 		}
+	},
+	"schema": {
+		"ifaces": "$interfaces"
 	}
 }`)
 
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	err = schema.Validate(input)
-	c.Assert(err, ErrorMatches, `cannot accept element in "snaps.core20.version": expected string type but got number`)
+	err = schema.Validate([]byte(`{"ifaces": [{"name": "eth0", "mtu": 1500}]}`))
+	c.Check(err, IsNil)
+
+	err = schema.Validate([]byte(`{"ifaces": [{"name": "ETH0", "mtu": 1500}]}`))
+	c.Check(err, ErrorMatches, `cannot accept element in "ifaces\[0\].name": string "ETH0" doesn't match schema pattern .*`)
 }
 
-func (*schemaSuite) TestBadUserDefinedTypeName(c *C) {
+func (*schemaSuite) TestUserTypeReferencesLaterType(c *C) {
 	schemaStr := []byte(`{
 	"types": {
-		"-foo": {
+		"server": {
 			"schema": {
-				"name": "string",
-				"version": "string"
+				"address": "$address",
+				"backup": "$address"
+			},
+			"required": ["address"]
+		},
+		"address": {
+			"schema": {
+				"host": "string",
+				"port": "int"
 			}
 		}
 	},
 	"schema": {
-		"snaps": {
-			"values": "$-foo"
+		"servers": {
+			"values": "$server"
 		}
 	}
 }`)
 
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, NotNil)
-	c.Assert(err.Error(), Equals, `cannot parse user-defined type name "-foo": must match ^(?:[a-z0-9]+-?)*[a-z](?:-?[a-z0-9])*$`)
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"servers": {"main": {"address": {"host": "example.com", "port": 443}}}}`))
+	c.Check(err, IsNil)
+
+	err = schema.Validate([]byte(`{"servers": {"main": {"address": {"host": "example.com", "port": "443"}}}}`))
+	c.Check(err, ErrorMatches, `cannot accept element in "servers.main.address.port": expected int type but got string`)
 }
 
-func (*schemaSuite) TestIntegerHappy(c *C) {
+func (*schemaSuite) TestUserTypeReferencesUndefinedType(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"server": {
+			"schema": {
+				"address": "$address"
+			}
+		}
+	},
 	"schema": {
-		"foo": "int"
+		"servers": {
+			"values": "$server"
+		}
 	}
 }`)
 
-	schema, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, IsNil)
-
-	input := []byte(`{
-	"foo": 1
-}`)
-	err = schema.Validate(input)
-	c.Assert(err, IsNil)
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse user-defined type "server": cannot find user-defined type "address"`)
 }
 
-func (*schemaSuite) TestIntegerMustMatchChoices(c *C) {
+func (*schemaSuite) TestRecursiveUserType(c *C) {
 	schemaStr := []byte(`{
-	"schema": {
-		"foo": {
-			"type": "int",
-			"choices": [1,	3]
+	"types": {
+		"node": {
+			"schema": {
+				"name": "string",
+				"children": {
+					"type": "array",
+					"values": "$node"
+				}
+			},
+			"required": ["name"]
 		}
+	},
+	"schema": {
+		"tree": "$node"
 	}
 }`)
 
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	for _, num := range []int{0, 1, 2, 3, 4} {
-		input := []byte(fmt.Sprintf(`{
-	"foo": %d
-}`, num))
+	type testcase struct {
+		input string
+		err   string
+	}
 
-		err := schema.Validate(input)
-		if num == 1 || num == 3 {
-			c.Assert(err, IsNil)
-		} else {
-			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %d is not one of the allowed choices`, num))
-		}
-	}
-}
-
-func (*schemaSuite) TestIntegerMustMatchMinMax(c *C) {
-	min, max := 1, 3
-	schemaStr := []byte(fmt.Sprintf(`{
-	"schema": {
-		"foo": {
-			"type": "int",
-			"min": %d,
-			"max": %d
-		}
-	}
-}`, min, max))
-
-	schema, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, IsNil)
-
-	for _, num := range []int{0, 1, 2, 3, 4} {
-		input := []byte(fmt.Sprintf(`{
-	"foo": %d
-}`, num))
-
-		err := schema.Validate(input)
-		if num < min {
-			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %d is less than the allowed minimum %d`, num, min))
-		} else if num > max {
-			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %d is greater than the allowed maximum %d`, num, max))
+	for _, tc := range []testcase{
+		{
+			input: `{"tree": {"name": "root"}}`,
+		},
+		{
+			input: `{"tree": {"name": "root", "children": [{"name": "a", "children": [{"name": "b"}]}, {"name": "c"}]}}`,
+		},
+		{
+			input: `{"tree": {"name": "root", "children": [{"name": "a", "children": [{"name": 1}]}]}}`,
+			err:   `cannot accept element in "tree.children\[0\].children\[0\].name": expected string type but got number`,
+		},
+		{
+			input: `{"tree": {"name": "root", "children": [{"children": []}]}}`,
+			err:   `cannot accept element in "tree.children\[0\]": cannot find required combinations of keys`,
+		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		if tc.err == "" {
+			c.Check(err, IsNil, Commentf("input: %s", tc.input))
 		} else {
-			c.Assert(err, IsNil)
+			c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
 		}
 	}
 }
 
-func (*schemaSuite) TestIntegerChoicesAndMinMaxFail(c *C) {
+func (*schemaSuite) TestMutuallyRecursiveUserTypes(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"dir": {
+			"values": "$entry"
+		},
+		"entry": ["string", "$dir"]
+	},
 	"schema": {
-		"foo": {
-			"type": "int",
-			"min": 0,
-			"choices": [0]
-		}
+		"root": "$dir"
 	}
 }`)
 
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot have "choices" and "min" constraints`)
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
 
-	schemaStr = []byte(`{
-	"schema": {
-		"foo": {
-			"type": "int",
-			"max": 0,
-			"choices": [0]
-		}
-	}
-}`)
+	err = schema.Validate([]byte(`{"root": {"a": "file", "b": {"c": "file", "d": {}}}}`))
+	c.Check(err, IsNil)
 
-	_, err = aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot have "choices" and "max" constraints`)
+	err = schema.Validate([]byte(`{"root": {"a": "file", "b": {"c": 1}}}`))
+	c.Check(err, NotNil)
 }
 
-func (*schemaSuite) TestIntegerEmptyChoicesFail(c *C) {
+func (*schemaSuite) TestRecursiveUserTypeDepthLimit(c *C) {
 	schemaStr := []byte(`{
-	"schema": {
-		"foo": {
-			"type": "int",
-			"choices": []
+	"types": {
+		"list": {
+			"schema": {
+				"next": "$list"
+			}
 		}
+	},
+	"schema": {
+		"list": "$list"
 	}
 }`)
 
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot have "choices" constraint with empty list`)
-}
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
 
-func (*schemaSuite) TestIntegerBadChoicesConstraint(c *C) {
-	schemaStr := []byte(`{
-	"schema": {
-		"foo": {
-			"type": "int",
-			"choices": 5
-		}
+	nested := func(depth int) []byte {
+		// the top level map and the "list" entry make up two levels
+		value := strings.Repeat(`{"next": `, depth-2) + "{}" + strings.Repeat("}", depth-2)
+		return []byte(`{"list": ` + value + `}`)
 	}
-}`)
 
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse "choices" constraint: json: cannot unmarshal number into Go value of type \[\]int64`)
+	c.Check(schema.Validate(nested(64)), IsNil)
+	c.Check(schema.Validate(nested(65)), ErrorMatches, `cannot accept top level element: cannot accept value nested deeper than 64 levels`)
 }
 
-func (*schemaSuite) TestIntegerBadMinMaxConstraints(c *C) {
-	schemaStr := []byte(`{
-	"schema": {
-		"foo": {
-			"type": "int",
-			"min": "5"
-		}
+func (*schemaSuite) TestRecursiveUserTypeBadDefinitions(c *C) {
+	type testcase struct {
+		types string
+		err   string
 	}
-}`)
-
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse "min" constraint: json: cannot unmarshal string into Go value of type int64`)
 
-	schemaStr = []byte(`{
-	"schema": {
-		"foo": {
-			"type": "int",
-			"max": "5"
-		}
+	for _, tc := range []testcase{
+		{
+			types: `{"foo": "$foo"}`,
+			err:   `cannot parse user-defined type "foo": recursive reference must be nested in a map or array`,
+		},
+		{
+			types: `{"foo": ["string", "$foo"]}`,
+			err:   `cannot parse user-defined type "foo": recursive reference must be nested in a map or array`,
+		},
+		{
+			types: `{"bar": "$foo", "foo": {"type": "$bar", "sensitive": true}}`,
+			err:   `cannot parse user-defined type "bar": recursive reference must be nested in a map or array`,
+		},
+		{
+			types: `{"foo": {"schema": {"bar": {"type": "$foo", "default": {}}}}}`,
+			err:   `cannot parse user-defined type "foo": cannot parse "default" value: cannot use user-defined type "foo" within its own definition before it is defined`,
+		},
+		{
+			types: `{"foo": {"keys": "$foo"}}`,
+			err:   `cannot parse user-defined type "foo": cannot parse "keys" constraint: key type "foo" must be based on string`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{"types": %s, "schema": {"snaps": "string"}}`, tc.types))
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("types: %s", tc.types))
 	}
-}`)
-
-	_, err = aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse "max" constraint: json: cannot unmarshal string into Go value of type int64`)
 }
 
-func (*schemaSuite) TestIntegerMinGreaterThanMaxConstraintFail(c *C) {
+func (*schemaSuite) TestRecursiveUserTypeSensitiveValues(c *C) {
 	schemaStr := []byte(`{
-	"schema": {
-		"foo": {
-			"type": "int",
-			"min": 5,
-			"max": 1
+	"types": {
+		"node": {
+			"schema": {
+				"secret": {
+					"type": "string",
+					"sensitive": true
+				},
+				"children": {
+					"type": "array",
+					"values": "$node"
+				}
+			}
 		}
-	}
-}`)
-
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot have "min" constraint with value greater than "max"`)
-}
-
-func (*schemaSuite) TestIntegerMinMaxOver32Bits(c *C) {
-	schemaStr := []byte(fmt.Sprintf(`{
+	},
 	"schema": {
-		"foo": {
-			"type": "int",
-			"min": %d,
-			"max": %d
-		}
+		"tree": "$node"
 	}
-}`, int64(math.MinInt64), int64(math.MaxInt64)))
+}`)
 
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	input := []byte(fmt.Sprintf(`{
-	"foo": %d
-}`, int64(math.MinInt64)))
-
-	err = schema.Validate(input)
-	c.Assert(err, IsNil)
-}
-
-func (*schemaSuite) TestIntegerChoicesOver32Bits(c *C) {
-	schemaStr := []byte(fmt.Sprintf(`{
-	"schema": {
-		"foo": {
-			"type": "int",
-			"choices": [%d, %d]
-		}
-	}
-}`, int64(math.MinInt64), int64(math.MaxInt64)))
-
-	schema, err := aspects.ParseSchema(schemaStr)
+	values, err := schema.SensitiveValues([]byte(`{"tree": {"secret": "a", "children": [{"secret": "b", "children": [{"secret": "c"}]}]}}`))
 	c.Assert(err, IsNil)
-
-	for _, num := range []int64{math.MinInt64, math.MaxInt64} {
-		input := []byte(fmt.Sprintf(`{
-	"foo": %d
-}`, num))
-
-		err = schema.Validate(input)
-		c.Assert(err, IsNil)
-	}
+	c.Check(values, testutil.DeepUnsortedMatches, []string{"a", "b", "c"})
 }
 
-func (*schemaSuite) TestAnyTypeAcceptsAllTypes(c *C) {
+func (*schemaSuite) TestParameterizedUserType(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"keyed-list": {
+			"parameters": ["item"],
+			"type": "map",
+			"keys": {
+				"type": "string",
+				"pattern": "^[a-z]+$"
+			},
+			"values": {
+				"type": "array",
+				"values": "$item"
+			}
+		},
+		"address": {
+			"schema": {
+				"host": "string",
+				"port": "int"
+			}
+		}
+	},
 	"schema": {
-		"foo": "any"
+		"names": "$keyed-list[string]",
+		"servers": "$keyed-list[$address]",
+		"ports": "$keyed-list[ int ]"
 	}
 }`)
 
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	for _, val := range []string{`"bar"`, `123`, `{ "a": 1, "b": 2 }`, `0.1`, `false`} {
-		input := []byte(fmt.Sprintf(`{
-			"foo": %s
-		}`, val))
+	type testcase struct {
+		input string
+		err   string
+	}
 
-		err = schema.Validate(input)
-		c.Assert(err, IsNil, Commentf(`"any" type didn't accept expected value: %s`, val))
+	for _, tc := range []testcase{
+		{
+			input: `{"names": {"foo": ["a", "b"]}, "servers": {"main": [{"host": "example.com", "port": 443}]}, "ports": {"web": [80, 443]}}`,
+		},
+		{
+			input: `{"names": {"foo": [1]}}`,
+			err:   `cannot accept element in "names.foo\[0\]": expected string type but got number`,
+		},
+		{
+			input: `{"servers": {"main": [{"host": 1}]}}`,
+			err:   `cannot accept element in "servers.main\[0\].host": expected string type but got number`,
+		},
+		{
+			input: `{"ports": {"Web": [80]}}`,
+			err:   `cannot accept element in "ports": key "Web" doesn't conform to required format`,
+		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		if tc.err == "" {
+			c.Check(err, IsNil, Commentf("input: %s", tc.input))
+		} else {
+			c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+		}
 	}
 }
 
-func (*schemaSuite) TestAnyTypeWithMapDefinition(c *C) {
+func (*schemaSuite) TestParameterizedUserTypeNested(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"pair": {
+			"parameters": ["first", "second"],
+			"schema": {
+				"first": "$first",
+				"second": "$second"
+			}
+		},
+		"list": {
+			"parameters": ["item"],
+			"type": "array",
+			"values": "$item"
+		},
+		"tree": {
+			"parameters": ["item"],
+			"schema": {
+				"value": "$item",
+				"children": "$list[$tree[$item]]"
+			}
+		},
+		"id": {
+			"type": "string",
+			"pattern": "^[a-z]+$"
+		}
+	},
 	"schema": {
-		"foo": {
-			"type": "any"
+		"pairs": "$list[$pair[$id, $list[int]]]",
+		"tree": "$tree[$id]",
+		"by-id": {
+			"keys": "$id",
+			"values": ["int", "$pair[bool,string]"]
 		}
 	}
 }`)
@@ -1194,57 +1742,252 @@ func (*schemaSuite) TestAnyTypeWithMapDefinition(c *C) {
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	input := []byte(`{
-			"foo": "string"
-		}`)
-
-	err = schema.Validate(input)
-	c.Assert(err, IsNil)
-}
-
-func (*schemaSuite) TestAnyTypeRejectsBadJSON(c *C) {
-	schemaStr := []byte(`{
-	"schema": {
-		"foo": "any"
-	}
-}`)
+	err = schema.Validate([]byte(`{"pairs": [{"first": "a", "second": [1, 2]}], "tree": {"value": "a", "children": [{"value": "b"}]}, "by-id": {"a": 1, "b": {"first": true, "second": "c"}}}`))
+	c.Check(err, IsNil)
 
-	schema, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, IsNil)
+	err = schema.Validate([]byte(`{"pairs": [{"first": "A"}]}`))
+	c.Check(err, ErrorMatches, `cannot accept element in "pairs\[0\].first": string "A" doesn't match schema pattern .*`)
 
-	input := []byte(`{
-	"foo": .
-}`)
-	err = schema.Validate(input)
-	c.Assert(err, ErrorMatches, `cannot accept top level element: invalid character .*`)
+	err = schema.Validate([]byte(`{"tree": {"value": "a", "children": [{"value": "B"}]}}`))
+	c.Check(err, ErrorMatches, `cannot accept element in "tree.children\[0\].value": string "B" doesn't match schema pattern .*`)
 }
 
-func (*schemaSuite) TestNumberValidFloatAndInt(c *C) {
-	schemaStr := []byte(`{
-	"schema": {
-		"foo": "number",
-		"bar": "number"
+func (*schemaSuite) TestParameterizedUserTypeErrors(c *C) {
+	type testcase struct {
+		types  string
+		schema string
+		err    string
 	}
-}`)
-
-	schema, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, IsNil)
+
+	for _, tc := range []testcase{
+		{
+			types:  `{"list": {"parameters": "item", "type": "array", "values": "$item"}}`,
+			schema: `"string"`,
+			err:    `cannot parse user-defined type "list": cannot parse "parameters" constraint: .*`,
+		},
+		{
+			types:  `{"list": {"parameters": [], "type": "array", "values": "$item"}}`,
+			schema: `"string"`,
+			err:    `cannot parse user-defined type "list": cannot parse "parameters" constraint: must have at least one parameter`,
+		},
+		{
+			types:  `{"list": {"parameters": ["Item"], "type": "array", "values": "$Item"}}`,
+			schema: `"string"`,
+			err:    `cannot parse user-defined type "list": cannot parse type parameter name "Item": must match .*`,
+		},
+		{
+			types:  `{"pair": {"parameters": ["a", "a"], "schema": {"first": "$a"}}}`,
+			schema: `"string"`,
+			err:    `cannot parse user-defined type "pair": cannot parse "parameters" constraint: duplicate parameter "a"`,
+		},
+		{
+			types:  `{"list": {"parameters": ["item"], "type": "array", "values": "$item"}}`,
+			schema: `"$list"`,
+			err:    `cannot use user-defined type "list" without type parameters`,
+		},
+		{
+			types:  `{"id": "string"}`,
+			schema: `"$id[int]"`,
+			err:    `user-defined type "id" does not take type parameters`,
+		},
+		{
+			types:  `{"list": {"parameters": ["item"], "type": "array", "values": "$item"}}`,
+			schema: `"$list[int,string]"`,
+			err:    `user-defined type "list" expects 1 type parameters but got 2`,
+		},
+		{
+			types:  `{"list": {"parameters": ["item"], "type": "array", "values": "$item"}}`,
+			schema: `"$list[int"`,
+			err:    `cannot parse type parameters of "list\[int": must end with "\]"`,
+		},
+		{
+			types:  `{"list": {"parameters": ["item"], "type": "array", "values": "$item"}}`,
+			schema: `"$list[int]]"`,
+			err:    `cannot parse type parameters of "list\[int\]\]": unbalanced brackets`,
+		},
+		{
+			types:  `{"list": {"parameters": ["item"], "type": "array", "values": "$item"}}`,
+			schema: `"$list[]"`,
+			err:    `cannot parse type parameters of "list\[\]": empty type parameter`,
+		},
+		{
+			types:  `{"list": {"parameters": ["item"], "type": "array", "values": "$item"}}`,
+			schema: `"$list[$other]"`,
+			err:    `cannot parse user-defined type "list\[\$other\]": cannot parse "array" values type: cannot find user-defined type "other"`,
+		},
+		{
+			types:  `{"list": {"parameters": ["item"], "type": "array", "values": "$other"}}`,
+			schema: `"$list[int]"`,
+			err:    `cannot parse user-defined type "list\[int\]": cannot parse "array" values type: cannot find user-defined type "other"`,
+		},
+		{
+			types:  `{"self": {"parameters": ["item"], "type": "$self[$item]"}}`,
+			schema: `"$self[int]"`,
+			err:    `cannot parse user-defined type "self\[int\]": recursive reference must be nested in a map or array`,
+		},
+		{
+			// every level of nesting instantiates a new type
+			types:  `{"nest": {"parameters": ["item"], "type": "array", "values": "$nest[$nest[$item]]"}}`,
+			schema: `"$nest[int]"`,
+			err:    `.*type parameters nested deeper than 16 levels`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{"types": %s, "schema": {"snaps": %s}}`, tc.types, tc.schema))
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("types: %s, schema: %s", tc.types, tc.schema))
+	}
+}
+
+func (*schemaSuite) TestMapBasedUserDefinedTypeHappy(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"snap": {
+			"schema": {
+				"name": "string",
+				"status": "string"
+			}
+		}
+	},
+	"schema": {
+		"snaps": {
+			"values": "$snap"
+		}
+	}
+}`)
 
 	input := []byte(`{
-	"foo": 1.2,
-	"bar": 1
+	"snaps": {
+		"core20": {
+			"name": "core20",
+			"status": "active"
+		},
+		"snapd": {
+			"name": "snapd",
+			"status": "inactive"
+		}
+	}
 }`)
 
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
 	err = schema.Validate(input)
 	c.Assert(err, IsNil)
 }
 
-func (*schemaSuite) TestNumberMustMatchChoices(c *C) {
+func (*schemaSuite) TestUserTypeReferenceDoesntRequireConstraints(c *C) {
+	// references to user-defined types don't require need constraints
+	schemaStr := []byte(`{
+	"types": {
+		"my-type": {
+			"schema": {
+				"foo": "string"
+			}
+		}
+	},
+	"schema": {
+		"a": "$my-type"
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+}
+
+func (*schemaSuite) TestMapInUserTypeRequiresConstraints(c *C) {
+	// maps still require constraints even within user-defined types
+	schemaStr := []byte(`{
+	"types": {
+		"my-type": "map"
+	},
+	"schema": {
+		"a": "$my-type"
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse user-defined type "my-type": cannot parse "map": must be schema definition with constraints`)
+}
+
+func (*schemaSuite) TestMapBasedUserDefinedTypeFail(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"snap": {
+			"schema": {
+				"name": "string",
+				"version": "string"
+			}
+		}
+	},
+	"schema": {
+		"snaps": {
+			"values": "$snap"
+		}
+	}
+}`)
+
+	input := []byte(`{
+	"snaps": {
+		"core20": {
+			"name": "core20",
+			"version": 123
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	err = schema.Validate(input)
+	c.Assert(err, ErrorMatches, `cannot accept element in "snaps.core20.version": expected string type but got number`)
+}
+
+func (*schemaSuite) TestBadUserDefinedTypeName(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"-foo": {
+			"schema": {
+				"name": "string",
+				"version": "string"
+			}
+		}
+	},
+	"schema": {
+		"snaps": {
+			"values": "$-foo"
+		}
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, `cannot parse user-defined type name "-foo": must match ^(?:[a-z0-9]+-?)*[a-z](?:-?[a-z0-9])*$`)
+}
+
+func (*schemaSuite) TestIntegerHappy(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": "int"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{
+	"foo": 1
+}`)
+	err = schema.Validate(input)
+	c.Assert(err, IsNil)
+}
+
+func (*schemaSuite) TestIntegerMustMatchChoices(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
 		"foo": {
-			"type": "number",
-			"choices": [1,	3.0]
+			"type": "int",
+			"choices": [1,	3]
 		}
 	}
 }`)
@@ -1252,28 +1995,28 @@ func (*schemaSuite) TestNumberMustMatchChoices(c *C) {
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	for _, num := range []float64{0, 1, 2, 3, 4} {
+	for _, num := range []int{0, 1, 2, 3, 4} {
 		input := []byte(fmt.Sprintf(`{
-	"foo": %f
+	"foo": %d
 }`, num))
 
 		err := schema.Validate(input)
 		if num == 1 || num == 3 {
 			c.Assert(err, IsNil)
 		} else {
-			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %v is not one of the allowed choices`, num))
+			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %d is not one of the allowed choices`, num))
 		}
 	}
 }
 
-func (*schemaSuite) TestNumberMustMatchMinMax(c *C) {
-	min, max := float32(0.1), float32(3)
+func (*schemaSuite) TestIntegerMustMatchMinMax(c *C) {
+	min, max := 1, 3
 	schemaStr := []byte(fmt.Sprintf(`{
 	"schema": {
 		"foo": {
-			"type": "number",
-			"min": %.1f,
-			"max": %f
+			"type": "int",
+			"min": %d,
+			"max": %d
 		}
 	}
 }`, min, max))
@@ -1281,27 +2024,27 @@ func (*schemaSuite) TestNumberMustMatchMinMax(c *C) {
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	for _, num := range []float32{0, 0.1, 2, 3, 4} {
+	for _, num := range []int{0, 1, 2, 3, 4} {
 		input := []byte(fmt.Sprintf(`{
-	"foo": %.25f
+	"foo": %d
 }`, num))
 
 		err := schema.Validate(input)
 		if num < min {
-			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %v is less than the allowed minimum %v`, num, min))
+			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %d is less than the allowed minimum %d`, num, min))
 		} else if num > max {
-			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %v is greater than the allowed maximum %v`, num, max))
+			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %d is greater than the allowed maximum %d`, num, max))
 		} else {
 			c.Assert(err, IsNil)
 		}
 	}
 }
 
-func (*schemaSuite) TestNumberChoicesAndMinMaxFail(c *C) {
+func (*schemaSuite) TestIntegerChoicesAndMinMaxFail(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
 		"foo": {
-			"type": "number",
+			"type": "int",
 			"min": 0,
 			"choices": [0]
 		}
@@ -1314,7 +2057,7 @@ func (*schemaSuite) TestNumberChoicesAndMinMaxFail(c *C) {
 	schemaStr = []byte(`{
 	"schema": {
 		"foo": {
-			"type": "number",
+			"type": "int",
 			"max": 0,
 			"choices": [0]
 		}
@@ -1325,11 +2068,11 @@ func (*schemaSuite) TestNumberChoicesAndMinMaxFail(c *C) {
 	c.Assert(err, ErrorMatches, `cannot have "choices" and "max" constraints`)
 }
 
-func (*schemaSuite) TestNumberEmptyChoicesFail(c *C) {
+func (*schemaSuite) TestIntegerEmptyChoicesFail(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
 		"foo": {
-			"type": "number",
+			"type": "int",
 			"choices": []
 		}
 	}
@@ -1339,51 +2082,51 @@ func (*schemaSuite) TestNumberEmptyChoicesFail(c *C) {
 	c.Assert(err, ErrorMatches, `cannot have "choices" constraint with empty list`)
 }
 
-func (*schemaSuite) TestNumberBadChoicesConstraint(c *C) {
+func (*schemaSuite) TestIntegerBadChoicesConstraint(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
 		"foo": {
-			"type": "number",
+			"type": "int",
 			"choices": 5
 		}
 	}
 }`)
 
 	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse "choices" constraint: json: cannot unmarshal number into Go value of type \[\]float64`)
+	c.Assert(err, ErrorMatches, `cannot parse "choices" constraint: json: cannot unmarshal number into Go value of type \[\]int64`)
 }
 
-func (*schemaSuite) TestNumberBadMinMaxConstraints(c *C) {
+func (*schemaSuite) TestIntegerBadMinMaxConstraints(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
 		"foo": {
-			"type": "number",
+			"type": "int",
 			"min": "5"
 		}
 	}
 }`)
 
 	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse "min" constraint: json: cannot unmarshal string into Go value of type float64`)
+	c.Assert(err, ErrorMatches, `cannot parse "min" constraint: json: cannot unmarshal string into Go value of type int64`)
 
 	schemaStr = []byte(`{
 	"schema": {
 		"foo": {
-			"type": "number",
+			"type": "int",
 			"max": "5"
 		}
 	}
 }`)
 
 	_, err = aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse "max" constraint: json: cannot unmarshal string into Go value of type float64`)
+	c.Assert(err, ErrorMatches, `cannot parse "max" constraint: json: cannot unmarshal string into Go value of type int64`)
 }
 
-func (*schemaSuite) TestNumberMinGreaterThanMaxConstraintFail(c *C) {
+func (*schemaSuite) TestIntegerMinGreaterThanMaxConstraintFail(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
 		"foo": {
-			"type": "number",
+			"type": "int",
 			"min": 5,
 			"max": 1
 		}
@@ -1394,102 +2137,2181 @@ func (*schemaSuite) TestNumberMinGreaterThanMaxConstraintFail(c *C) {
 	c.Assert(err, ErrorMatches, `cannot have "min" constraint with value greater than "max"`)
 }
 
-func (*schemaSuite) TestSimpleTypesRejectNull(c *C) {
-	for _, typ := range []string{"string", "int", "any", "number", "bool"} {
+func (*schemaSuite) TestIntegerMustMatchExclusiveMinMax(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "int",
+			"exclusive-min": 0,
+			"exclusive-max": 3
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, num := range []int{-1, 0, 1, 2, 3, 4} {
+		input := []byte(fmt.Sprintf(`{"foo": %d}`, num))
+
+		err := schema.Validate(input)
+		if num <= 0 {
+			c.Check(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %d is not greater than the exclusive minimum 0`, num))
+		} else if num >= 3 {
+			c.Check(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %d is not less than the exclusive maximum 3`, num))
+		} else {
+			c.Check(err, IsNil)
+		}
+	}
+}
+
+func (*schemaSuite) TestIntegerMixedInclusiveAndExclusiveBounds(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "int",
+			"min": 1,
+			"exclusive-max": 3
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	c.Check(schema.Validate([]byte(`{"foo": 1}`)), IsNil)
+	c.Check(schema.Validate([]byte(`{"foo": 2}`)), IsNil)
+	c.Check(schema.Validate([]byte(`{"foo": 0}`)), ErrorMatches, `cannot accept element in "foo": 0 is less than the allowed minimum 1`)
+	c.Check(schema.Validate([]byte(`{"foo": 3}`)), ErrorMatches, `cannot accept element in "foo": 3 is not less than the exclusive maximum 3`)
+}
+
+func (*schemaSuite) TestExclusiveMinMaxFail(c *C) {
+	type testcase struct {
+		constraints string
+		err         string
+	}
+
+	for _, typ := range []string{"int", "number"} {
+		for _, tc := range []testcase{
+			{
+				constraints: `"exclusive-min": "0"`,
+				err:         `cannot parse "exclusive-min" constraint: .*`,
+			},
+			{
+				constraints: `"exclusive-max": "0"`,
+				err:         `cannot parse "exclusive-max" constraint: .*`,
+			},
+			{
+				constraints: `"choices": [1], "exclusive-min": 0`,
+				err:         `cannot have "choices" and "exclusive-min" constraints`,
+			},
+			{
+				constraints: `"choices": [1], "exclusive-max": 2`,
+				err:         `cannot have "choices" and "exclusive-max" constraints`,
+			},
+			{
+				constraints: `"min": 0, "exclusive-min": 0`,
+				err:         `cannot have "min" and "exclusive-min" constraints`,
+			},
+			{
+				constraints: `"max": 0, "exclusive-max": 0`,
+				err:         `cannot have "max" and "exclusive-max" constraints`,
+			},
+			{
+				constraints: `"const": 1, "exclusive-max": 2`,
+				err:         `cannot use "const" and "exclusive-max" constraints in same schema`,
+			},
+			{
+				constraints: `"exclusive-min": 1, "exclusive-max": 1`,
+				err:         `cannot have "exclusive-min" constraint with value greater than or equal to "exclusive-max"`,
+			},
+			{
+				constraints: `"exclusive-min": 1, "max": 1`,
+				err:         `cannot have "exclusive-min" constraint with value greater than or equal to "max"`,
+			},
+			{
+				constraints: `"min": 2, "exclusive-max": 1`,
+				err:         `cannot have "min" constraint with value greater than or equal to "exclusive-max"`,
+			},
+		} {
+			schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": %q,
+			%s
+		}
+	}
+}`, typ, tc.constraints))
+
+			_, err := aspects.ParseSchema(schemaStr)
+			c.Check(err, ErrorMatches, tc.err, Commentf("%s: %s", typ, tc.constraints))
+		}
+	}
+}
+
+func (*schemaSuite) TestExclusiveMinMaxKeptByUserTypeReferences(c *C) {
+	for _, typ := range []string{"int", "number"} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"types": {
+		"bounded": {
+			"type": %q,
+			"exclusive-min": 0,
+			"exclusive-max": 10
+		}
+	},
+	"schema": {
+		"foo": {"type": "$bounded", "sensitive": true},
+		"bar": {"type": "$bounded", "nullable": true}
+	}
+}`, typ))
+
+		schema, err := aspects.ParseSchema(schemaStr)
+		c.Assert(err, IsNil, Commentf("type: %s", typ))
+
+		err = schema.Validate([]byte(`{"foo": 5, "bar": 5}`))
+		c.Assert(err, IsNil, Commentf("type: %s", typ))
+
+		for _, tc := range []struct {
+			input string
+			err   string
+		}{
+			{`{"foo": 0}`, `cannot accept element in "foo": 0 is not greater than the exclusive minimum 0`},
+			{`{"foo": 10}`, `cannot accept element in "foo": 10 is not less than the exclusive maximum 10`},
+			{`{"bar": -3}`, `cannot accept element in "bar": -3 is not greater than the exclusive minimum 0`},
+			{`{"bar": 12}`, `cannot accept element in "bar": 12 is not less than the exclusive maximum 10`},
+		} {
+			err = schema.Validate([]byte(tc.input))
+			c.Check(err, ErrorMatches, tc.err, Commentf("type: %s, input: %s", typ, tc.input))
+		}
+	}
+}
+
+func (*schemaSuite) TestIntegerMultipleOf(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "int",
+			"min": 0,
+			"multiple-of": 5
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, num := range []int{0, 5, 100} {
+		c.Check(schema.Validate([]byte(fmt.Sprintf(`{"foo": %d}`, num))), IsNil, Commentf("%d", num))
+	}
+
+	for _, num := range []int{1, 7, 99} {
+		err := schema.Validate([]byte(fmt.Sprintf(`{"foo": %d}`, num)))
+		c.Check(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %d is not a multiple of 5`, num))
+	}
+}
+
+func (*schemaSuite) TestMultipleOfKeptByUserTypeReferences(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"even": {"type": "int", "multiple-of": 2},
+		"half": {"type": "number", "multiple-of": 0.5}
+	},
+	"schema": {
+		"foo": {"type": "$even", "sensitive": true},
+		"bar": {"type": "$half", "nullable": true}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"foo": 4, "bar": 1.5}`))
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"foo": 3}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo": 3 is not a multiple of 2`)
+
+	err = schema.Validate([]byte(`{"bar": 1.2}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "bar": 1.2 is not a multiple of 0.5`)
+}
+
+func (*schemaSuite) TestMultipleOfFail(c *C) {
+	type testcase struct {
+		typ         string
+		constraints string
+		err         string
+	}
+
+	for _, tc := range []testcase{
+		{
+			typ:         "int",
+			constraints: `"multiple-of": 1.5`,
+			err:         `cannot parse "multiple-of" constraint: .*`,
+		},
+		{
+			typ:         "number",
+			constraints: `"multiple-of": "1"`,
+			err:         `cannot parse "multiple-of" constraint: .*`,
+		},
+		{
+			typ:         "int",
+			constraints: `"multiple-of": 0`,
+			err:         `cannot have non-positive "multiple-of" constraint`,
+		},
+		{
+			typ:         "number",
+			constraints: `"multiple-of": -0.5`,
+			err:         `cannot have non-positive "multiple-of" constraint`,
+		},
+		{
+			typ:         "int",
+			constraints: `"choices": [2], "multiple-of": 2`,
+			err:         `cannot have "choices" and "multiple-of" constraints`,
+		},
+		{
+			typ:         "number",
+			constraints: `"const": 2, "multiple-of": 2`,
+			err:         `cannot use "const" and "multiple-of" constraints in same schema`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": %q,
+			%s
+		}
+	}
+}`, tc.typ, tc.constraints))
+
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("%s: %s", tc.typ, tc.constraints))
+	}
+}
+
+func (*schemaSuite) TestIntegerMinMaxOver32Bits(c *C) {
+	schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": "int",
+			"min": %d,
+			"max": %d
+		}
+	}
+}`, int64(math.MinInt64), int64(math.MaxInt64)))
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(fmt.Sprintf(`{
+	"foo": %d
+}`, int64(math.MinInt64)))
+
+	err = schema.Validate(input)
+	c.Assert(err, IsNil)
+}
+
+func (*schemaSuite) TestIntegerChoicesOver32Bits(c *C) {
+	schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": "int",
+			"choices": [%d, %d]
+		}
+	}
+}`, int64(math.MinInt64), int64(math.MaxInt64)))
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, num := range []int64{math.MinInt64, math.MaxInt64} {
+		input := []byte(fmt.Sprintf(`{
+	"foo": %d
+}`, num))
+
+		err = schema.Validate(input)
+		c.Assert(err, IsNil)
+	}
+}
+
+func (*schemaSuite) TestAnyTypeAcceptsAllTypes(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": "any"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, val := range []string{`"bar"`, `123`, `{ "a": 1, "b": 2 }`, `0.1`, `false`} {
+		input := []byte(fmt.Sprintf(`{
+			"foo": %s
+		}`, val))
+
+		err = schema.Validate(input)
+		c.Assert(err, IsNil, Commentf(`"any" type didn't accept expected value: %s`, val))
+	}
+}
+
+func (*schemaSuite) TestAnyTypeWithMapDefinition(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "any"
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{
+			"foo": "string"
+		}`)
+
+	err = schema.Validate(input)
+	c.Assert(err, IsNil)
+}
+
+func (*schemaSuite) TestAnyTypeRejectsBadJSON(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": "any"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{
+	"foo": .
+}`)
+	err = schema.Validate(input)
+	c.Assert(err, ErrorMatches, `cannot accept top level element: invalid character .*`)
+}
+
+func (*schemaSuite) TestNumberValidFloatAndInt(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": "number",
+		"bar": "number"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{
+	"foo": 1.2,
+	"bar": 1
+}`)
+
+	err = schema.Validate(input)
+	c.Assert(err, IsNil)
+}
+
+func (*schemaSuite) TestNumberMustMatchChoices(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "number",
+			"choices": [1,	3.0]
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, num := range []float64{0, 1, 2, 3, 4} {
+		input := []byte(fmt.Sprintf(`{
+	"foo": %f
+}`, num))
+
+		err := schema.Validate(input)
+		if num == 1 || num == 3 {
+			c.Assert(err, IsNil)
+		} else {
+			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %v is not one of the allowed choices`, num))
+		}
+	}
+}
+
+func (*schemaSuite) TestNumberMustMatchMinMax(c *C) {
+	min, max := float32(0.1), float32(3)
+	schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": "number",
+			"min": %.1f,
+			"max": %f
+		}
+	}
+}`, min, max))
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, num := range []float32{0, 0.1, 2, 3, 4} {
+		input := []byte(fmt.Sprintf(`{
+	"foo": %.25f
+}`, num))
+
+		err := schema.Validate(input)
+		if num < min {
+			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %v is less than the allowed minimum %v`, num, min))
+		} else if num > max {
+			c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %v is greater than the allowed maximum %v`, num, max))
+		} else {
+			c.Assert(err, IsNil)
+		}
+	}
+}
+
+func (*schemaSuite) TestNumberMustMatchExclusiveMinMax(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "number",
+			"exclusive-min": 0,
+			"exclusive-max": 1
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, num := range []float64{-0.5, 0, 0.001, 0.5, 0.999, 1, 1.5} {
+		input := []byte(fmt.Sprintf(`{"foo": %v}`, num))
+
+		err := schema.Validate(input)
+		if num <= 0 {
+			c.Check(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %v is not greater than the exclusive minimum 0`, num))
+		} else if num >= 1 {
+			c.Check(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %v is not less than the exclusive maximum 1`, num))
+		} else {
+			c.Check(err, IsNil)
+		}
+	}
+}
+
+func (*schemaSuite) TestNumberMultipleOf(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "number",
+			"multiple-of": 0.1
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	// not exact multiples in floating-point
+	for _, num := range []string{"0", "0.3", "0.7", "-1.1", "12345.6"} {
+		c.Check(schema.Validate([]byte(fmt.Sprintf(`{"foo": %s}`, num))), IsNil, Commentf("%s", num))
+	}
+
+	for _, num := range []string{"0.05", "1.01", "-0.15"} {
+		err := schema.Validate([]byte(fmt.Sprintf(`{"foo": %s}`, num)))
+		c.Check(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": %s is not a multiple of 0.1`, num))
+	}
+}
+
+func (*schemaSuite) TestNumberChoicesAndMinMaxFail(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "number",
+			"min": 0,
+			"choices": [0]
+		}
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot have "choices" and "min" constraints`)
+
+	schemaStr = []byte(`{
+	"schema": {
+		"foo": {
+			"type": "number",
+			"max": 0,
+			"choices": [0]
+		}
+	}
+}`)
+
+	_, err = aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot have "choices" and "max" constraints`)
+}
+
+func (*schemaSuite) TestNumberEmptyChoicesFail(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "number",
+			"choices": []
+		}
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot have "choices" constraint with empty list`)
+}
+
+func (*schemaSuite) TestNumberBadChoicesConstraint(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "number",
+			"choices": 5
+		}
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse "choices" constraint: json: cannot unmarshal number into Go value of type \[\]float64`)
+}
+
+func (*schemaSuite) TestNumberBadMinMaxConstraints(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "number",
+			"min": "5"
+		}
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse "min" constraint: json: cannot unmarshal string into Go value of type float64`)
+
+	schemaStr = []byte(`{
+	"schema": {
+		"foo": {
+			"type": "number",
+			"max": "5"
+		}
+	}
+}`)
+
+	_, err = aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse "max" constraint: json: cannot unmarshal string into Go value of type float64`)
+}
+
+func (*schemaSuite) TestNumberMinGreaterThanMaxConstraintFail(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "number",
+			"min": 5,
+			"max": 1
+		}
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot have "min" constraint with value greater than "max"`)
+}
+
+func (*schemaSuite) TestSimpleTypesRejectNull(c *C) {
+	for _, typ := range []string{"string", "int", "any", "number", "bool"} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": %q
+	}
+}`, typ))
+
+		schema, err := aspects.ParseSchema(schemaStr)
+		c.Assert(err, IsNil)
+
+		err = schema.Validate([]byte(`{"foo": null}`))
+		c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": cannot accept null value for %q type`, typ))
+	}
+}
+
+func (*schemaSuite) TestMapTypeRejectsNull(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"schema": {
+				"a": "int"
+			}
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"foo": null}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo": cannot accept null value for "map" type`)
+}
+
+func (*schemaSuite) TestUserDefinedTypeRejectsNull(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"mytype": {
+			"type": "string"
+		}
+	},
+	"schema": {
+		"foo": "$mytype"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"foo": null}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo": cannot accept null value for "string" type`)
+}
+
+func (*schemaSuite) TestArrayRejectsNull(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "array",
+			"values": "int"
+		}
+	}
+}`)
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{"foo": null}`)
+	err = schema.Validate(input)
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo": cannot accept null value for "array" type`)
+}
+
+func (*schemaSuite) TestDecimalHappy(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"price": {
+			"type": "decimal",
+			"precision": 6,
+			"scale": 2,
+			"min": "0",
+			"max": "1000.00"
+		},
+		"factor": "decimal"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, input := range []string{
+		`{"price": "0"}`,
+		`{"price": "0.01"}`,
+		`{"price": "12.5"}`,
+		`{"price": "1000.00"}`,
+		`{"factor": "-0.000000000000000000001"}`,
+		`{"factor": "123456789012345678901234567890.123456789"}`,
+	} {
+		c.Check(schema.Validate([]byte(input)), IsNil, Commentf("input: %s", input))
+	}
+}
+
+func (*schemaSuite) TestDecimalNoMatch(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"price": {
+			"type": "decimal",
+			"precision": 6,
+			"scale": 2,
+			"min": "0.10",
+			"max": "1000"
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	type testcase struct {
+		input string
+		err   string
+	}
+
+	for _, tc := range []testcase{
+		{
+			input: `{"price": 12.5}`,
+			err:   `cannot accept element in "price": expected decimal string but got number`,
+		},
+		{
+			input: `{"price": null}`,
+			err:   `cannot accept element in "price": cannot accept null value for "decimal" type`,
+		},
+		{
+			input: `{"price": "1e3"}`,
+			err:   `cannot accept element in "price": string "1e3" is not a valid decimal number`,
+		},
+		{
+			input: `{"price": "01.5"}`,
+			err:   `cannot accept element in "price": string "01.5" is not a valid decimal number`,
+		},
+		{
+			input: `{"price": ".5"}`,
+			err:   `cannot accept element in "price": string ".5" is not a valid decimal number`,
+		},
+		{
+			input: `{"price": "1."}`,
+			err:   `cannot accept element in "price": string "1." is not a valid decimal number`,
+		},
+		{
+			input: `{"price": "1.005"}`,
+			err:   `cannot accept element in "price": decimal "1.005" has more than 2 digits after the decimal point`,
+		},
+		{
+			input: `{"price": "12345.67"}`,
+			err:   `cannot accept element in "price": decimal "12345.67" has more than 6 significant digits`,
+		},
+		{
+			// compared exactly, unlike 0.1 and 0.09999999999999999999 as float64
+			input: `{"price": "0.09"}`,
+			err:   `cannot accept element in "price": decimal "0.09" is less than the allowed minimum 0.10`,
+		},
+		{
+			input: `{"price": "1000.01"}`,
+			err:   `cannot accept element in "price": decimal "1000.01" is greater than the allowed maximum 1000`,
+		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+	}
+}
+
+func (*schemaSuite) TestDecimalExactComparison(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "decimal",
+			"max": "0.1"
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	// equal as float64 but greater than the maximum
+	err = schema.Validate([]byte(`{"foo": "0.10000000000000000001"}`))
+	c.Check(err, ErrorMatches, `cannot accept element in "foo": decimal "0.10000000000000000001" is greater than the allowed maximum 0.1`)
+}
+
+func (*schemaSuite) TestDecimalFail(c *C) {
+	type testcase struct {
+		constraints string
+		err         string
+	}
+
+	for _, tc := range []testcase{
+		{
+			constraints: `"precision": "2"`,
+			err:         `cannot parse "precision" constraint: .*`,
+		},
+		{
+			constraints: `"precision": 0`,
+			err:         `cannot have non-positive "precision" constraint`,
+		},
+		{
+			constraints: `"scale": 1.5`,
+			err:         `cannot parse "scale" constraint: .*`,
+		},
+		{
+			constraints: `"scale": -1`,
+			err:         `cannot have negative "scale" constraint`,
+		},
+		{
+			constraints: `"precision": 2, "scale": 3`,
+			err:         `cannot have "scale" constraint with value greater than "precision"`,
+		},
+		{
+			constraints: `"min": 1`,
+			err:         `cannot parse "min" constraint: .*`,
+		},
+		{
+			constraints: `"max": "1e2"`,
+			err:         `cannot parse "max" constraint: string "1e2" is not a valid decimal number`,
+		},
+		{
+			constraints: `"min": "1.01", "max": "1.0"`,
+			err:         `cannot have "min" constraint with value greater than "max"`,
+		},
+		{
+			constraints: `"scale": 1, "default": "1.25"`,
+			err:         `cannot parse "default" value: .* decimal "1.25" has more than 1 digits after the decimal point`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": "decimal",
+			%s
+		}
+	}
+}`, tc.constraints))
+
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("constraints: %s", tc.constraints))
+	}
+}
+
+func (*schemaSuite) TestDatetimeHappy(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"expiry": {
+			"type": "datetime",
+			"min": "2024-01-01T00:00:00Z",
+			"max": "2025-01-01T00:00:00Z"
+		},
+		"schedule": "datetime"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, input := range []string{
+		`{"expiry": "2024-01-01T00:00:00Z"}`,
+		`{"expiry": "2024-06-15T10:30:00.123456+02:00"}`,
+		`{"expiry": "2025-01-01T01:00:00+01:00"}`,
+		`{"schedule": "1970-01-01T00:00:00-08:00"}`,
+	} {
+		c.Check(schema.Validate([]byte(input)), IsNil, Commentf("input: %s", input))
+	}
+}
+
+func (*schemaSuite) TestDatetimeNoMatch(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"expiry": {
+			"type": "datetime",
+			"min": "2024-01-01T00:00:00Z",
+			"max": "2025-01-01T00:00:00Z"
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	type testcase struct {
+		input string
+		err   string
+	}
+
+	for _, tc := range []testcase{
+		{
+			input: `{"expiry": 1700000000}`,
+			err:   `cannot accept element in "expiry": expected datetime string but got number`,
+		},
+		{
+			input: `{"expiry": null}`,
+			err:   `cannot accept element in "expiry": cannot accept null value for "datetime" type`,
+		},
+		{
+			input: `{"expiry": "2024-06-15"}`,
+			err:   `cannot accept element in "expiry": string "2024-06-15" is not a valid RFC 3339 timestamp`,
+		},
+		{
+			input: `{"expiry": "2024-06-15T10:30:00"}`,
+			err:   `cannot accept element in "expiry": string "2024-06-15T10:30:00" is not a valid RFC 3339 timestamp`,
+		},
+		{
+			input: `{"expiry": "2024-13-01T00:00:00Z"}`,
+			err:   `cannot accept element in "expiry": string "2024-13-01T00:00:00Z" is not a valid RFC 3339 timestamp`,
+		},
+		{
+			// the same instant as the minimum's previous second in UTC
+			input: `{"expiry": "2024-01-01T00:59:59+01:00"}`,
+			err:   `cannot accept element in "expiry": datetime "2024-01-01T00:59:59\+01:00" is before the allowed minimum 2024-01-01T00:00:00Z`,
+		},
+		{
+			input: `{"expiry": "2025-01-01T00:00:00.5Z"}`,
+			err:   `cannot accept element in "expiry": datetime "2025-01-01T00:00:00.5Z" is after the allowed maximum 2025-01-01T00:00:00Z`,
+		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+	}
+}
+
+func (*schemaSuite) TestDatetimeFail(c *C) {
+	type testcase struct {
+		constraints string
+		err         string
+	}
+
+	for _, tc := range []testcase{
+		{
+			constraints: `"min": 0`,
+			err:         `cannot parse "min" constraint: .*`,
+		},
+		{
+			constraints: `"max": "tomorrow"`,
+			err:         `cannot parse "max" constraint: string "tomorrow" is not a valid RFC 3339 timestamp`,
+		},
+		{
+			constraints: `"min": "2025-01-01T00:00:00Z", "max": "2024-01-01T00:00:00Z"`,
+			err:         `cannot have "min" constraint with value greater than "max"`,
+		},
+		{
+			constraints: `"min": "2025-01-01T00:00:00Z", "default": "2024-01-01T00:00:00Z"`,
+			err:         `cannot parse "default" value: .* datetime "2024-01-01T00:00:00Z" is before the allowed minimum 2025-01-01T00:00:00Z`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": "datetime",
+			%s
+		}
+	}
+}`, tc.constraints))
+
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("constraints: %s", tc.constraints))
+	}
+}
+
+func (*schemaSuite) TestDurationHappy(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"timeout": {
+			"type": "duration",
+			"min": "1s",
+			"max": "2h"
+		},
+		"interval": "duration"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, input := range []string{
+		`{"timeout": "1s"}`,
+		`{"timeout": "30s"}`,
+		`{"timeout": "1h45m30.5s"}`,
+		`{"timeout": "120m"}`,
+		`{"interval": "-1.5h"}`,
+		`{"interval": "0"}`,
+	} {
+		c.Check(schema.Validate([]byte(input)), IsNil, Commentf("input: %s", input))
+	}
+}
+
+func (*schemaSuite) TestDurationNoMatch(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"timeout": {
+			"type": "duration",
+			"min": "1s",
+			"max": "2h"
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	type testcase struct {
+		input string
+		err   string
+	}
+
+	for _, tc := range []testcase{
+		{
+			input: `{"timeout": 30}`,
+			err:   `cannot accept element in "timeout": expected duration string but got number`,
+		},
+		{
+			input: `{"timeout": null}`,
+			err:   `cannot accept element in "timeout": cannot accept null value for "duration" type`,
+		},
+		{
+			input: `{"timeout": "30"}`,
+			err:   `cannot accept element in "timeout": string "30" is not a valid duration`,
+		},
+		{
+			input: `{"timeout": "1d"}`,
+			err:   `cannot accept element in "timeout": string "1d" is not a valid duration`,
+		},
+		{
+			input: `{"timeout": "999ms"}`,
+			err:   `cannot accept element in "timeout": duration "999ms" is less than the allowed minimum 1s`,
+		},
+		{
+			input: `{"timeout": "2h0m1s"}`,
+			err:   `cannot accept element in "timeout": duration "2h0m1s" is greater than the allowed maximum 2h`,
+		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+	}
+}
+
+func (*schemaSuite) TestDurationFail(c *C) {
+	type testcase struct {
+		constraints string
+		err         string
+	}
+
+	for _, tc := range []testcase{
+		{
+			constraints: `"min": 1`,
+			err:         `cannot parse "min" constraint: .*`,
+		},
+		{
+			constraints: `"max": "forever"`,
+			err:         `cannot parse "max" constraint: string "forever" is not a valid duration`,
+		},
+		{
+			constraints: `"min": "1m", "max": "59s"`,
+			err:         `cannot have "min" constraint with value greater than "max"`,
+		},
+		{
+			constraints: `"max": "1m", "default": "90s"`,
+			err:         `cannot parse "default" value: .* duration "90s" is greater than the allowed maximum 1m`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": "duration",
+			%s
+		}
+	}
+}`, tc.constraints))
+
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("constraints: %s", tc.constraints))
+	}
+}
+
+func (*schemaSuite) TestEnumHappy(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"brightness": {
+			"type": "enum",
+			"choices": ["auto", 0, 0.5, 100, false]
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, value := range []string{`"auto"`, `0`, `0.0`, `0.5`, `100`, `1e2`, `false`} {
+		input := fmt.Sprintf(`{"brightness": %s}`, value)
+		c.Check(schema.Validate([]byte(input)), IsNil, Commentf("input: %s", input))
+	}
+}
+
+func (*schemaSuite) TestEnumNoMatch(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"brightness": {
+			"type": "enum",
+			"choices": ["auto", 0, false]
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	type testcase struct {
+		value string
+		err   string
+	}
+
+	for _, tc := range []testcase{
+		{value: `"manual"`, err: `"manual" is not one of the allowed choices`},
+		// types are not converted
+		{value: `"0"`, err: `"0" is not one of the allowed choices`},
+		{value: `"false"`, err: `"false" is not one of the allowed choices`},
+		{value: `1`, err: `1 is not one of the allowed choices`},
+		{value: `true`, err: `true is not one of the allowed choices`},
+		{value: `null`, err: `cannot accept null value for "enum" type`},
+		{value: `["auto"]`, err: `expected string, number or bool type but got array`},
+		{value: `{"a": 0}`, err: `expected string, number or bool type but got object`},
+	} {
+		input := fmt.Sprintf(`{"brightness": %s}`, tc.value)
+		err := schema.Validate([]byte(input))
+		c.Check(err, ErrorMatches, `cannot accept element in "brightness": `+tc.err, Commentf("input: %s", input))
+	}
+}
+
+func (*schemaSuite) TestEnumFail(c *C) {
+	type testcase struct {
+		schema string
+		err    string
+	}
+
+	for _, tc := range []testcase{
+		{
+			schema: `"enum"`,
+			err:    `cannot parse "enum": must be schema definition with constraints`,
+		},
+		{
+			schema: `{"type": "enum"}`,
+			err:    `cannot parse "enum": must have a "choices" constraint`,
+		},
+		{
+			schema: `{"type": "enum", "choices": "auto"}`,
+			err:    `cannot parse "choices" constraint: .*`,
+		},
+		{
+			schema: `{"type": "enum", "choices": []}`,
+			err:    `cannot have "choices" constraint with empty list`,
+		},
+		{
+			schema: `{"type": "enum", "choices": ["auto", null]}`,
+			err:    `cannot parse "choices" constraint: expected choice 1 to be a string, number or bool but got null`,
+		},
+		{
+			schema: `{"type": "enum", "choices": [[0]]}`,
+			err:    `cannot parse "choices" constraint: expected choice 0 to be a string, number or bool but got array`,
+		},
+		{
+			schema: `{"type": "enum", "choices": ["auto", 1, 1.0]}`,
+			err:    `cannot parse "choices" constraint: duplicate choice 1`,
+		},
+		{
+			schema: `{"type": "enum", "choices": ["auto", 0], "default": "off"}`,
+			err:    `cannot parse "default" value: .* "off" is not one of the allowed choices`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{"schema": {"foo": %s}}`, tc.schema))
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("schema: %s", tc.schema))
+	}
+}
+
+func (*schemaSuite) TestBooleanHappy(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": "bool",
+		"bar": "bool"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{
+	"foo": true,
+	"bar": false
+}`)
+
+	err = schema.Validate(input)
+	c.Assert(err, IsNil)
+}
+
+func (*schemaSuite) TestConstHappy(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"kind": {
+			"type": "string",
+			"const": "wifi"
+		},
+		"version": {
+			"type": "int",
+			"const": 2
+		},
+		"ratio": {
+			"type": "number",
+			"const": 1.5
+		},
+		"enabled": {
+			"type": "bool",
+			"const": false
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"kind": "wifi", "version": 2, "ratio": 1.5, "enabled": false}`))
+	c.Assert(err, IsNil)
+
+	type testcase struct {
+		input string
+		err   string
+	}
+
+	for _, tc := range []testcase{
+		{
+			input: `{"kind": "ethernet"}`,
+			err:   `cannot accept element in "kind": string "ethernet" is not the required constant "wifi"`,
+		},
+		{
+			input: `{"version": 3}`,
+			err:   `cannot accept element in "version": 3 is not the required constant 2`,
+		},
+		{
+			input: `{"ratio": 1}`,
+			err:   `cannot accept element in "ratio": 1 is not the required constant 1.5`,
+		},
+		{
+			input: `{"enabled": true}`,
+			err:   `cannot accept element in "enabled": true is not the required constant false`,
+		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+	}
+}
+
+func (*schemaSuite) TestConstDiscriminator(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"wifi": {
+			"schema": {
+				"kind": {"type": "string", "const": "wifi"},
+				"ssid": "string"
+			}
+		},
+		"ethernet": {
+			"schema": {
+				"kind": {"type": "string", "const": "ethernet"},
+				"mtu": "int"
+			}
+		}
+	},
+	"schema": {
+		"connections": {
+			"type": "array",
+			"values": ["$wifi", "$ethernet"]
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"connections": [{"kind": "wifi", "ssid": "home"}, {"kind": "ethernet", "mtu": 1500}]}`))
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"connections": [{"kind": "ethernet", "ssid": "home"}]}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "connections\[0\]": no matching alternative type: cannot accept element in "kind": string "ethernet" is not the required constant "wifi"; map contains unexpected key "ssid"`)
+}
+
+func (*schemaSuite) TestConstKeptByUserTypeReferences(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"version": {"type": "string", "const": "v1"},
+		"port": {"type": "int", "const": 80},
+		"ratio": {"type": "number", "const": 0.5},
+		"enabled": {"type": "bool", "const": true}
+	},
+	"schema": {
+		"version": {"type": "$version", "sensitive": true},
+		"port": {"type": "$port", "nullable": true},
+		"ratio": {"type": "$ratio", "sensitive": true},
+		"enabled": {"type": "$enabled", "nullable": true},
+		"other-version": "$version"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"version": "v1", "port": 80, "ratio": 0.5, "enabled": true, "other-version": "v1"}`))
+	c.Assert(err, IsNil)
+
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{`{"version": "other"}`, `cannot accept element in "version": string "other" is not the required constant "v1"`},
+		{`{"other-version": "other"}`, `cannot accept element in "other-version": string "other" is not the required constant "v1"`},
+		{`{"port": 8080}`, `cannot accept element in "port": .*`},
+		{`{"ratio": 1}`, `cannot accept element in "ratio": .*`},
+		{`{"enabled": false}`, `cannot accept element in "enabled": .*`},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+	}
+}
+
+func (*schemaSuite) TestConstFail(c *C) {
+	type testcase struct {
+		typ         string
+		constraints string
+		err         string
+	}
+
+	for _, tc := range []testcase{
+		{
+			typ:         "string",
+			constraints: `"const": 1`,
+			err:         `cannot parse "const" constraint: .*`,
+		},
+		{
+			typ:         "int",
+			constraints: `"const": 1.5`,
+			err:         `cannot parse "const" constraint: .*`,
+		},
+		{
+			typ:         "number",
+			constraints: `"const": "1"`,
+			err:         `cannot parse "const" constraint: .*`,
+		},
+		{
+			typ:         "bool",
+			constraints: `"const": "true"`,
+			err:         `cannot parse "const" constraint: .*`,
+		},
+		{
+			typ:         "bool",
+			constraints: `"const": null`,
+			err:         `cannot parse "const" constraint: cannot be null`,
+		},
+		{
+			typ:         "string",
+			constraints: `"const": "foo", "choices": ["foo"]`,
+			err:         `cannot use "const" and "choices" constraints in same schema`,
+		},
+		{
+			typ:         "string",
+			constraints: `"const": "foo", "pattern": "^foo$"`,
+			err:         `cannot use "const" and "pattern" constraints in same schema`,
+		},
+		{
+			typ:         "int",
+			constraints: `"const": 1, "min": 0`,
+			err:         `cannot use "const" and "min" constraints in same schema`,
+		},
+		{
+			typ:         "number",
+			constraints: `"const": 1, "max": 2`,
+			err:         `cannot use "const" and "max" constraints in same schema`,
+		},
+		{
+			typ:         "int",
+			constraints: `"const": 1, "default": 2`,
+			err:         `cannot parse "default" value: cannot accept top level element: 2 is not the required constant 1`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": %q,
+			%s
+		}
+	}
+}`, tc.typ, tc.constraints))
+
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("%s: %s", tc.typ, tc.constraints))
+	}
+}
+
+func (*schemaSuite) TestArrayHappy(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "array",
+			"values": "string"
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{
+	"foo": ["a", "b"]
+}`)
+
+	err = schema.Validate(input)
+	c.Assert(err, IsNil)
+}
+
+func (*schemaSuite) TestArrayHappyWithUserDefinedType(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"my-type": "string"
+	},
+	"schema": {
+		"foo": {
+			"type": "array",
+			"values": "$my-type"
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{
+	"foo": ["a", "b"]
+}`)
+
+	err = schema.Validate(input)
+	c.Assert(err, IsNil)
+}
+
+func (*schemaSuite) TestArrayRequireConstraints(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": "array"
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse "array": must be schema definition with constraints`)
+}
+
+func (*schemaSuite) TestArrayRequireValueConstraint(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "array"
+		}
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse "array": must have "values" or "items" constraint`)
+}
+
+func (*schemaSuite) TestArrayFailsWithBadElementType(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "array",
+			"values": "foo"
+		}
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse "array" values type: cannot parse unknown type "foo"`)
+}
+
+func (*schemaSuite) TestArrayEnforcesOnlyOneType(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "array",
+			"values": "string"
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{
+	"foo": ["a", 1]
+}`)
+
+	err = schema.Validate(input)
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo\[1\]": expected string type but got number`)
+}
+
+func (*schemaSuite) TestArrayWithUniqueRejectsDuplicates(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "array",
+			"values": "string",
+			"unique": true
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{
+	"foo": ["a", "a"]
+}`)
+
+	err = schema.Validate(input)
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo": cannot accept duplicate values for array with "unique" constraint`)
+}
+
+func (*schemaSuite) TestArrayWithoutUniqueAcceptsDuplicates(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "array",
+			"values": "string",
+			"unique": false
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{
+	"foo": ["a", "b"]
+}`)
+
+	err = schema.Validate(input)
+	c.Assert(err, IsNil)
+}
+
+func (*schemaSuite) TestArrayFailsWithBadUniqueType(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "array",
+			"values": "string",
+			"unique": "true"
+		}
+	}
+}`)
+
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse array's "unique" constraint: json: cannot unmarshal string into Go value of type bool`)
+}
+
+func (*schemaSuite) TestArrayLengthConstraints(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"dns": {
+			"type": "array",
+			"values": "string",
+			"min-len": 1,
+			"max-len": 3
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	type testcase struct {
+		input string
+		err   string
+	}
+
+	for _, tc := range []testcase{
+		{
+			input: `{"dns": ["1.1.1.1"]}`,
+		},
+		{
+			input: `{"dns": ["1.1.1.1", "8.8.8.8", "9.9.9.9"]}`,
+		},
+		{
+			input: `{"dns": []}`,
+			err:   `cannot accept element in "dns": cannot accept array with fewer than 1 elements`,
+		},
+		{
+			input: `{"dns": ["1.1.1.1", "8.8.8.8", "9.9.9.9", "1.0.0.1"]}`,
+			err:   `cannot accept element in "dns": cannot accept array with more than 3 elements`,
+		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		if tc.err == "" {
+			c.Check(err, IsNil, Commentf("input: %s", tc.input))
+		} else {
+			c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+		}
+	}
+}
+
+func (*schemaSuite) TestArrayBadLengthConstraints(c *C) {
+	type testcase struct {
+		constraints string
+		err         string
+	}
+
+	for _, tc := range []testcase{
+		{
+			constraints: `"min-len": "1"`,
+			err:         `cannot parse array's "min-len" constraint: json: cannot unmarshal string into Go value of type int`,
+		},
+		{
+			constraints: `"max-len": true`,
+			err:         `cannot parse array's "max-len" constraint: json: cannot unmarshal bool into Go value of type int`,
+		},
+		{
+			constraints: `"min-len": -1`,
+			err:         `cannot have negative array "min-len" constraint`,
+		},
+		{
+			constraints: `"max-len": -1`,
+			err:         `cannot have negative array "max-len" constraint`,
+		},
+		{
+			constraints: `"min-len": 2, "max-len": 1`,
+			err:         `cannot have array "min-len" constraint with value greater than "max-len"`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": "array",
+			"values": "string",
+			%s
+		}
+	}
+}`, tc.constraints))
+
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("constraints: %s", tc.constraints))
+	}
+}
+
+func (*schemaSuite) TestArrayTupleHappy(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"port": {
+			"type": "int",
+			"min": 1,
+			"max": 65535
+		}
+	},
+	"schema": {
+		"endpoint": {
+			"type": "array",
+			"items": ["string", "$port"]
+		},
+		"endpoints": {
+			"type": "array",
+			"values": {
+				"type": "array",
+				"items": ["string", "$port"]
+			}
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{
+	"endpoint": ["localhost", 8080],
+	"endpoints": [["a.example.com", 80], ["b.example.com", 443]]
+}`)
+	c.Assert(schema.Validate(input), IsNil)
+}
+
+func (*schemaSuite) TestArrayTupleNoMatch(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"endpoint": {
+			"type": "array",
+			"items": ["string", "int"]
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	type testcase struct {
+		input string
+		err   string
+	}
+
+	for _, tc := range []testcase{
+		{
+			input: `{"endpoint": ["localhost"]}`,
+			err:   `cannot accept element in "endpoint": cannot accept array with 1 elements, expected 2`,
+		},
+		{
+			input: `{"endpoint": ["localhost", 80, 443]}`,
+			err:   `cannot accept element in "endpoint": cannot accept array with 3 elements, expected 2`,
+		},
+		{
+			input: `{"endpoint": [80, "localhost"]}`,
+			err:   `cannot accept element in "endpoint\[0\]": expected string type but got number`,
+		},
+		{
+			input: `{"endpoint": ["localhost", "80"]}`,
+			err:   `cannot accept element in "endpoint\[1\]": expected int type but got string`,
+		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
+	}
+}
+
+func (*schemaSuite) TestArrayTupleBadDefinitions(c *C) {
+	type testcase struct {
+		constraints string
+		err         string
+	}
+
+	for _, tc := range []testcase{
+		{
+			constraints: `"items": ["string"], "values": "string"`,
+			err:         `cannot parse "array": cannot have both "values" and "items" constraints`,
+		},
+		{
+			constraints: `"items": []`,
+			err:         `cannot parse "array" items: must have at least one type`,
+		},
+		{
+			constraints: `"items": "string"`,
+			err:         `cannot parse "array" items: json: cannot unmarshal string .*`,
+		},
+		{
+			constraints: `"items": ["string", "foo"]`,
+			err:         `cannot parse "array" item 1 type: cannot parse unknown type "foo"`,
+		},
+		{
+			constraints: `"items": ["string", "int"], "max-len": 2`,
+			err:         `cannot have "items" and "min-len" or "max-len" constraints: the length of the array is fixed`,
+		},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{
+	"schema": {
+		"foo": {
+			"type": "array",
+			%s
+		}
+	}
+}`, tc.constraints))
+
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("constraints: %s", tc.constraints))
+	}
+}
+
+func (*schemaSuite) TestArrayTupleSensitiveValuesAndDefaults(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"login": {
+			"type": "array",
+			"items": [
+				"string",
+				{
+					"type": "string",
+					"sensitive": true
+				},
+				{
+					"schema": {
+						"port": {
+							"type": "int",
+							"default": 22
+						}
+					}
+				}
+			]
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{"login": ["user", "hunter2", {}]}`)
+	values, err := schema.SensitiveValues(input)
+	c.Assert(err, IsNil)
+	c.Check(values, DeepEquals, []string{"hunter2"})
+
+	withDefaults, err := schema.ApplyDefaults(input)
+	c.Assert(err, IsNil)
+	c.Check(string(withDefaults), Equals, `{"login":["user","hunter2",{"port":22}]}`)
+}
+
+func (*schemaSuite) TestErrorContainsPathPrefixes(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"schema": {
+				"bar": {
+					"schema": {
+						"baz": "string"
+					}
+				}
+			}
+		}
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	type testcase struct {
+		name  string
+		input []byte
+		err   string
+	}
+
+	testcases := []testcase{
+		{
+			name:  "top level",
+			input: []byte(`{"bar": 1}`),
+			err:   `cannot accept top level element: map contains unexpected key "bar"`,
+		},
+		{
+			name:  "1 level of nesting",
+			input: []byte(`{"foo": {"baz": 1}}`),
+			err:   `cannot accept element in "foo": map contains unexpected key "baz"`,
+		},
+		{
+			name:  "2 levels of nesting",
+			input: []byte(`{"foo": {"bar": {"boo": 1}}}`),
+			err:   `cannot accept element in "foo.bar": map contains unexpected key "boo"`,
+		},
+	}
+
+	for _, tc := range testcases {
+		err = schema.Validate(tc.input)
+		c.Assert(err, ErrorMatches, tc.err, Commentf("test case %q failed", tc.name))
+	}
+}
+
+func (*schemaSuite) TestPathPrefixWithMapUnderUserType(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"my-type": {
+			"schema": {
+				"bar": {
+					"type": "int",
+					"min": 0
+				}
+			}
+		}
+	},
+	"schema": {
+		"foo": "$my-type"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{"foo": {"bar": -1}}`)
+	err = schema.Validate(input)
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo.bar": -1 is less than the allowed minimum 0`)
+}
+
+func (*schemaSuite) TestPathPrefixWithArrayUnderUserType(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"my-type": {
+			"type": "int",
+			"min": 0
+		}
+	},
+	"schema": {
+		"foo": {
+			"type": "array",
+			"values": "$my-type"
+		}
+	}
+}`)
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{"foo": [-1]}`)
+	err = schema.Validate(input)
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo\[0\]": -1 is less than the allowed minimum 0`)
+}
+
+func (*schemaSuite) TestPathPrefixWithArrayUnderUserWithAContainerElementType(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"my-type": {
+			"type": "array",
+			"values": {
+				"schema": {
+					"bar": {
+						"type": "int",
+						"min": 0
+					}
+				}
+			}
+		}
+	},
+	"schema": {
+		"foo": "$my-type"
+	}
+}`)
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{"foo": [{"bar": 1}, {"bar": -1}]}`)
+	err = schema.Validate(input)
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo\[1\].bar": -1 is less than the allowed minimum 0`)
+}
+
+func (*schemaSuite) TestPathPrefixWithKeyOrValueConstraints(c *C) {
+	schemaStr := []byte(`{
+	"schema": {
+		"foo": {
+			"type": "map",
+			"keys": {
+				"type": "string",
+				"choices": ["my-key"]
+			},
+			"values": {
+				"type": "int",
+				"min": 0
+			}
+		}
+	}
+}`)
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{"foo": {"other-key": 1}}`)
+	err = schema.Validate(input)
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo.other-key": string "other-key" is not one of the allowed choices`)
+
+	input = []byte(`{"foo": {"my-key": -1}}`)
+	err = schema.Validate(input)
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo.my-key": -1 is less than the allowed minimum 0`)
+}
+
+func (*schemaSuite) TestPathManyUserDefinedTypeReferences(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"my-type": {
+			"type": "map",
+			"values": {
+				"type": "int",
+				"min": 0
+			}
+		}
+	},
+	"schema": {
+		"foo": "$my-type",
+		"bar": "$my-type"
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	input := []byte(`{"foo": { "one": 1 }, "bar": { "two": -1 } }`)
+	err = schema.Validate(input)
+	c.Assert(err, ErrorMatches, `cannot accept element in "bar.two": -1 is less than the allowed minimum 0`)
+}
+
+func (*schemaSuite) TestValidationError(c *C) {
+	type testcase struct {
+		path     []interface{}
+		expected string
+	}
+
+	cases := []testcase{
+		{
+			path:     []interface{}{"foo", "bar"},
+			expected: "foo.bar",
+		},
+		{
+			path:     []interface{}{"foo", 1, "bar"},
+			expected: "foo[1].bar",
+		},
+		{
+			path:     []interface{}{"foo", 1, 2, "bar"},
+			expected: "foo[1][2].bar",
+		},
+		{
+			path:     []interface{}{"foo", 2.9, 1},
+			expected: "foo.<n/a>[1]",
+		},
+	}
+
+	for _, tc := range cases {
+		err := &aspects.ValidationError{
+			Path: tc.path,
+			Err:  fmt.Errorf("base error"),
+		}
+
+		c.Assert(err.Error(), Equals, fmt.Sprintf(`cannot accept element in %q: base error`, tc.expected))
+	}
+}
+
+func (*schemaSuite) TestUnexpectedTypes(c *C) {
+	type testcase struct {
+		schemaType   string
+		expectedType string
+		testValue    interface{}
+	}
+
+	tcs := []testcase{
+		{
+			schemaType:   `{"type": "array", "values": "any"}`,
+			expectedType: "array",
+			testValue:    true,
+		},
+		{
+			schemaType:   `{"type": "map", "values": "any"}`,
+			expectedType: "map",
+			testValue:    true,
+		},
+		{
+			schemaType:   `"int"`,
+			expectedType: "int",
+			testValue:    true,
+		},
+		{
+			schemaType:   `"number"`,
+			expectedType: "number",
+			testValue:    true,
+		},
+		{
+			schemaType:   `"string"`,
+			expectedType: "string",
+			testValue:    true,
+		},
+		{
+			schemaType:   `"bool"`,
+			expectedType: "bool",
+			testValue:    `"bar"`,
+		},
+	}
+
+	for _, tc := range tcs {
 		schemaStr := []byte(fmt.Sprintf(`{
 	"schema": {
-		"foo": %q
+		"foo": %s
 	}
-}`, typ))
-
+}`, tc.schemaType))
 		schema, err := aspects.ParseSchema(schemaStr)
 		c.Assert(err, IsNil)
 
-		err = schema.Validate([]byte(`{"foo": null}`))
-		c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": cannot accept null value for %q type`, typ))
+		input := []byte(fmt.Sprintf(`{"foo": %v}`, tc.testValue))
+		err = schema.Validate(input)
+		c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": expected %s type but got %T`, tc.expectedType, tc.testValue))
 	}
 }
 
-func (*schemaSuite) TestMapTypeRejectsNull(c *C) {
+func (*schemaSuite) TestSensitiveValues(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"secret": {
+			"type": "string",
+			"sensitive": true
+		}
+	},
 	"schema": {
-		"foo": {
-			"schema": {
-				"a": "int"
-			}
+		"user": "string",
+		"password": "$secret",
+		"pin": {
+			"type": "int",
+			"sensitive": true
+		},
+		"tokens": {
+			"type": "array",
+			"values": "$secret"
+		},
+		"keys": {
+			"type": "map",
+			"values": "any",
+			"sensitive": true
+		},
+		"public": {
+			"type": "map",
+			"values": "string",
+			"sensitive": false
 		}
 	}
 }`)
-
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	err = schema.Validate([]byte(`{"foo": null}`))
-	c.Assert(err, ErrorMatches, `cannot accept element in "foo": cannot accept null value for "map" type`)
+	input := []byte(`{
+	"user": "foo",
+	"password": "hunter2",
+	"pin": 1234,
+	"tokens": ["abc", "def"],
+	"keys": {"a": {"b": "nested"}, "c": [true, "listed"]},
+	"public": {"a": "b"}
+}`)
+	c.Assert(schema.Validate(input), IsNil)
+
+	values, err := schema.SensitiveValues(input)
+	c.Assert(err, IsNil)
+	c.Check(values, testutil.DeepUnsortedMatches, []string{"hunter2", "1234", "abc", "def", "nested", "listed"})
 }
 
-func (*schemaSuite) TestUserDefinedTypeRejectsNull(c *C) {
-	schemaStr := []byte(`{
-	"types": {
-		"mytype": {
-			"type": "string"
-		}
-	},
-	"schema": {
-		"foo": "$mytype"
-	}
-}`)
+func (*schemaSuite) TestSensitiveValuesNone(c *C) {
+	schema, err := aspects.ParseSchema([]byte(`{"schema": {"foo": "string"}}`))
+	c.Assert(err, IsNil)
 
-	schema, err := aspects.ParseSchema(schemaStr)
+	values, err := schema.SensitiveValues([]byte(`{"foo": "bar"}`))
 	c.Assert(err, IsNil)
+	c.Check(values, HasLen, 0)
+}
 
-	err = schema.Validate([]byte(`{"foo": null}`))
-	c.Assert(err, ErrorMatches, `cannot accept element in "foo": cannot accept null value for "string" type`)
+func (*schemaSuite) TestSensitiveMarkerBadValue(c *C) {
+	_, err := aspects.ParseSchema([]byte(`{"schema": {"foo": {"type": "string", "sensitive": "yes"}}}`))
+	c.Assert(err, ErrorMatches, `cannot parse "sensitive" marker: .*`)
 }
 
-func (*schemaSuite) TestArrayRejectsNull(c *C) {
+func (*schemaSuite) TestSensitiveUserTypeIsStringBased(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"secret": {
+			"type": "string",
+			"sensitive": true
+		}
+	},
 	"schema": {
 		"foo": {
-			"type": "array",
-			"values": "int"
+			"keys": "$secret",
+			"values": "string"
 		}
 	}
 }`)
-	schema, err := aspects.ParseSchema(schemaStr)
+	_, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
-
-	input := []byte(`{"foo": null}`)
-	err = schema.Validate(input)
-	c.Assert(err, ErrorMatches, `cannot accept element in "foo": cannot accept null value for "array" type`)
 }
 
-func (*schemaSuite) TestBooleanHappy(c *C) {
+func (*schemaSuite) TestAltHappy(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
-		"foo": "bool",
-		"bar": "bool"
+		"foo": {
+			"type": "alt",
+			"alternatives": [
+				"string",
+				{
+					"type": "map",
+					"values": "int"
+				}
+			]
+		},
+		"bar": ["int", "bool"]
 	}
 }`)
 
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	input := []byte(`{
-	"foo": true,
-	"bar": false
-}`)
-
-	err = schema.Validate(input)
-	c.Assert(err, IsNil)
+	for _, input := range []string{
+		`{"foo": "a", "bar": 1}`,
+		`{"foo": {"a": 1}, "bar": true}`,
+	} {
+		c.Check(schema.Validate([]byte(input)), IsNil, Commentf("input: %s", input))
+	}
 }
 
-func (*schemaSuite) TestArrayHappy(c *C) {
+func (*schemaSuite) TestAltNoMatch(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
 		"foo": {
+			"type": "alt",
+			"alternatives": [
+				"string",
+				{
+					"type": "map",
+					"values": "int"
+				}
+			]
+		},
+		"bar": {
 			"type": "array",
-			"values": "string"
+			"values": ["int", "bool"]
 		}
 	}
 }`)
@@ -1497,82 +4319,163 @@ func (*schemaSuite) TestArrayHappy(c *C) {
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	input := []byte(`{
-	"foo": ["a", "b"]
-}`)
+	err = schema.Validate([]byte(`{"foo": 1}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo": no matching alternative type: expected string type but got number; expected map type but got number`)
 
-	err = schema.Validate(input)
-	c.Assert(err, IsNil)
+	err = schema.Validate([]byte(`{"foo": {"a": "b"}}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "foo": no matching alternative type: expected string type but got object; cannot accept element in "a": expected int type but got string`)
+
+	err = schema.Validate([]byte(`{"bar": [1, true, "a"]}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "bar\[2\]": no matching alternative type: expected int type but got string; expected bool type but got string`)
 }
 
-func (*schemaSuite) TestArrayHappyWithUserDefinedType(c *C) {
+func (*schemaSuite) TestAltWithUserDefinedType(c *C) {
 	schemaStr := []byte(`{
 	"types": {
-		"my-type": "string"
+		"my-type": {
+			"type": "string",
+			"choices": ["a", "b"]
+		}
 	},
 	"schema": {
-		"foo": {
-			"type": "array",
-			"values": "$my-type"
-		}
+		"foo": ["$my-type", "int"]
 	}
 }`)
 
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	input := []byte(`{
-	"foo": ["a", "b"]
-}`)
-
-	err = schema.Validate(input)
-	c.Assert(err, IsNil)
+	c.Check(schema.Validate([]byte(`{"foo": "a"}`)), IsNil)
+	c.Check(schema.Validate([]byte(`{"foo": 1}`)), IsNil)
+	c.Check(schema.Validate([]byte(`{"foo": "c"}`)), ErrorMatches, `cannot accept element in "foo": no matching alternative type: string "c" is not one of the allowed choices; expected int type but got string`)
 }
 
-func (*schemaSuite) TestArrayRequireConstraints(c *C) {
-	schemaStr := []byte(`{
-	"schema": {
-		"foo": "array"
+func (*schemaSuite) TestAltBadDefinitions(c *C) {
+	for _, tc := range []struct {
+		schema string
+		err    string
+	}{
+		{`"alt"`, `cannot parse "alt": must be schema definition with constraints`},
+		{`{"type": "alt"}`, `cannot parse "alt": must have "alternatives" constraint`},
+		{`{"type": "alt", "alternatives": "string"}`, `cannot parse "alternatives" constraint: .*`},
+		{`{"type": "alt", "alternatives": []}`, `cannot parse alternative types: must have at least one type`},
+		{`[]`, `cannot parse alternative types: must have at least one type`},
+		{`["string", "foo"]`, `cannot parse alternative type: cannot parse unknown type "foo"`},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{"schema": {"foo": %s}}`, tc.schema))
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("schema: %s", tc.schema))
 	}
-}`)
-
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse "array": must be schema definition with constraints`)
 }
 
-func (*schemaSuite) TestArrayRequireValueConstraint(c *C) {
+func (*schemaSuite) TestAltSensitiveValues(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"secret": {
+			"type": "string",
+			"sensitive": true
+		}
+	},
 	"schema": {
-		"foo": {
-			"type": "array"
+		"foo": ["int", "$secret"],
+		"bar": {
+			"type": "alt",
+			"alternatives": ["string", "int"],
+			"sensitive": true
 		}
 	}
 }`)
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
 
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse "array": must have "values" constraint`)
+	values, err := schema.SensitiveValues([]byte(`{"foo": "hunter2", "bar": 1234}`))
+	c.Assert(err, IsNil)
+	c.Check(values, testutil.DeepUnsortedMatches, []string{"hunter2", "1234"})
+
+	values, err = schema.SensitiveValues([]byte(`{"foo": 1}`))
+	c.Assert(err, IsNil)
+	c.Check(values, HasLen, 0)
 }
 
-func (*schemaSuite) TestArrayFailsWithBadElementType(c *C) {
+func (*schemaSuite) TestApplyDefaults(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"port": {
+			"type": "int",
+			"default": 8080
+		}
+	},
 	"schema": {
-		"foo": {
+		"name": {
+			"type": "string",
+			"default": "foo"
+		},
+		"port": "$port",
+		"ratio": {
+			"type": "number",
+			"default": 0.5
+		},
+		"enabled": {
+			"type": "bool",
+			"default": true
+		},
+		"tags": {
 			"type": "array",
-			"values": "foo"
-		}
+			"values": "string",
+			"default": ["a", "b"]
+		},
+		"nested": {
+			"schema": {
+				"mode": {
+					"type": "string",
+					"choices": ["fast", "slow"],
+					"default": "slow"
+				},
+				"other": "string"
+			},
+			"default": {}
+		},
+		"servers": {
+			"type": "array",
+			"values": {
+				"schema": {
+					"host": "string",
+					"port": "$port"
+				}
+			}
+		},
+		"optional": "string"
 	}
 }`)
 
-	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse "array" values type: cannot parse unknown type "foo"`)
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    `{}`,
+			expected: `{"enabled":true,"name":"foo","nested":{"mode":"slow"},"port":8080,"ratio":0.5,"tags":["a","b"]}`,
+		},
+		{
+			input:    `{"name": "bar", "port": 1, "nested": {"other": "baz"}, "servers": [{"host": "a"}, {"host": "b", "port": 2}]}`,
+			expected: `{"enabled":true,"name":"bar","nested":{"mode":"slow","other":"baz"},"port":1,"ratio":0.5,"servers":[{"host":"a","port":8080},{"host":"b","port":2}],"tags":["a","b"]}`,
+		},
+	} {
+		out, err := schema.ApplyDefaults([]byte(tc.input))
+		c.Assert(err, IsNil, Commentf("input: %s", tc.input))
+		c.Check(string(out), Equals, tc.expected, Commentf("input: %s", tc.input))
+	}
 }
 
-func (*schemaSuite) TestArrayEnforcesOnlyOneType(c *C) {
+func (*schemaSuite) TestApplyDefaultsValidates(c *C) {
 	schemaStr := []byte(`{
 	"schema": {
-		"foo": {
-			"type": "array",
-			"values": "string"
+		"name": {
+			"type": "string",
+			"default": "foo"
 		}
 	}
 }`)
@@ -1580,84 +4483,161 @@ func (*schemaSuite) TestArrayEnforcesOnlyOneType(c *C) {
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	input := []byte(`{
-	"foo": ["a", 1]
-}`)
+	_, err = schema.ApplyDefaults([]byte(`{"name": 1}`))
+	c.Assert(err, ErrorMatches, `cannot accept element in "name": expected string type but got number`)
 
-	err = schema.Validate(input)
-	c.Assert(err, ErrorMatches, `cannot accept element in "foo\[1\]": expected string type but got number`)
+	_, err = schema.ApplyDefaults([]byte(`[]`))
+	c.Assert(err, ErrorMatches, `cannot accept top level element: expected map type but got array`)
 }
 
-func (*schemaSuite) TestArrayWithUniqueRejectsDuplicates(c *C) {
+func (*schemaSuite) TestDefaultMustMatchType(c *C) {
+	for _, tc := range []struct {
+		schema string
+		err    string
+	}{
+		{`{"type": "string", "default": 1}`, `cannot parse "default" value: cannot accept top level element: expected string type but got number`},
+		{`{"type": "string", "choices": ["a"], "default": "b"}`, `cannot parse "default" value: .* string "b" is not one of the allowed choices`},
+		{`{"type": "int", "max": 1, "default": 2}`, `cannot parse "default" value: .*`},
+		{`{"type": "bool", "default": "true"}`, `cannot parse "default" value: .*`},
+		{`{"type": "array", "values": "int", "default": ["a"]}`, `cannot parse "default" value: cannot accept element in "\[0\]": expected int type but got string`},
+		{`{"schema": {"a": "int"}, "default": {"b": 1}}`, `cannot parse "default" value: .* map contains unexpected key "b"`},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{"schema": {"foo": %s}}`, tc.schema))
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("schema: %s", tc.schema))
+	}
+}
+
+func (*schemaSuite) TestDefaultKeepsUserTypeStringBased(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"key": {
+			"type": "string",
+			"default": "foo"
+		}
+	},
 	"schema": {
 		"foo": {
-			"type": "array",
-			"values": "string",
-			"unique": true
+			"keys": "$key",
+			"values": "string"
 		}
 	}
 }`)
-
-	schema, err := aspects.ParseSchema(schemaStr)
+	_, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
-
-	input := []byte(`{
-	"foo": ["a", "a"]
-}`)
-
-	err = schema.Validate(input)
-	c.Assert(err, ErrorMatches, `cannot accept element in "foo": cannot accept duplicate values for array with "unique" constraint`)
 }
 
-func (*schemaSuite) TestArrayWithoutUniqueAcceptsDuplicates(c *C) {
+func (*schemaSuite) TestExamplesHappy(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"tree": {
+			"schema": {
+				"value": "int",
+				"children": {
+					"type": "array",
+					"values": "$tree"
+				}
+			},
+			"example": {"value": 1, "children": [{"value": 2, "children": []}]}
+		},
+		"port": {
+			"type": "int",
+			"min": 1,
+			"max": 65535,
+			"examples": [22, 443]
+		}
+	},
 	"schema": {
-		"foo": {
+		"tree": "$tree",
+		"ports": {
 			"type": "array",
-			"values": "string",
-			"unique": false
+			"values": "$port",
+			"example": [80, 8080]
+		},
+		"host": {
+			"type": "string",
+			"format": "hostname",
+			"example": "ubuntu",
+			"examples": ["foo", "foo.example.com"]
 		}
 	}
 }`)
-
-	schema, err := aspects.ParseSchema(schemaStr)
+	_, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
+}
 
-	input := []byte(`{
-	"foo": ["a", "b"]
-}`)
-
-	err = schema.Validate(input)
-	c.Assert(err, IsNil)
+func (*schemaSuite) TestExamplesMustMatchType(c *C) {
+	for _, tc := range []struct {
+		schema string
+		err    string
+	}{
+		{`{"type": "string", "example": 1}`, `cannot parse "example" value: cannot accept top level element: expected string type but got number`},
+		{`{"type": "string", "examples": ["a", 1]}`, `cannot parse "examples" value 1: cannot accept top level element: expected string type but got number`},
+		{`{"type": "int", "max": 1, "examples": [2]}`, `cannot parse "examples" value 0: cannot accept top level element: 2 is greater than the allowed maximum 1`},
+		{`{"type": "array", "values": "int", "example": ["a"]}`, `cannot parse "example" value: cannot accept element in "\[0\]": expected int type but got string`},
+		{`{"schema": {"a": "int"}, "example": {"b": 1}}`, `cannot parse "example" value: .* map contains unexpected key "b"`},
+		{`{"type": "string", "examples": "a"}`, `cannot parse "examples": .*`},
+		{`{"type": "string", "examples": []}`, `cannot have an empty "examples" list`},
+	} {
+		schemaStr := []byte(fmt.Sprintf(`{"schema": {"foo": %s}}`, tc.schema))
+		_, err := aspects.ParseSchema(schemaStr)
+		c.Check(err, ErrorMatches, tc.err, Commentf("schema: %s", tc.schema))
+	}
 }
 
-func (*schemaSuite) TestArrayFailsWithBadUniqueType(c *C) {
+func (*schemaSuite) TestExamplesOfUserTypeMustMatchType(c *C) {
 	schemaStr := []byte(`{
-	"schema": {
-		"foo": {
-			"type": "array",
-			"values": "string",
-			"unique": "true"
+	"types": {
+		"tree": {
+			"schema": {
+				"value": "int",
+				"children": {
+					"type": "array",
+					"values": "$tree"
+				}
+			},
+			"examples": [{"value": 1}, {"value": 1, "children": [{"value": "2"}]}]
 		}
+	},
+	"schema": {
+		"tree": "$tree"
 	}
 }`)
-
 	_, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, ErrorMatches, `cannot parse array's "unique" constraint: json: cannot unmarshal string into Go value of type bool`)
+	c.Assert(err, ErrorMatches, `cannot parse user-defined type "tree": cannot parse "examples" value 1: cannot accept element in "children\[0\].value": expected int type but got string`)
 }
 
-func (*schemaSuite) TestErrorContainsPathPrefixes(c *C) {
+func (*schemaSuite) TestNullable(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"name": {
+			"type": "string",
+			"nullable": true
+		}
+	},
 	"schema": {
-		"foo": {
+		"str": {
+			"type": "string",
+			"nullable": true
+		},
+		"num": {
+			"type": "int",
+			"min": 1,
+			"nullable": true
+		},
+		"map": {
 			"schema": {
-				"bar": {
-					"schema": {
-						"baz": "string"
-					}
-				}
-			}
+				"a": "int"
+			},
+			"nullable": true
+		},
+		"list": {
+			"type": "array",
+			"values": "$name"
+		},
+		"not-nullable": {
+			"type": "string",
+			"nullable": false
 		}
 	}
 }`)
@@ -1665,250 +4645,418 @@ func (*schemaSuite) TestErrorContainsPathPrefixes(c *C) {
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
+	err = schema.Validate([]byte(`{"str": null, "num": null, "map": null, "list": ["a", null]}`))
+	c.Assert(err, IsNil)
+
+	err = schema.Validate([]byte(`{"str": "a", "num": 2, "map": {"a": 1}, "list": []}`))
+	c.Assert(err, IsNil)
+
 	type testcase struct {
-		name  string
-		input []byte
+		input string
 		err   string
 	}
 
-	testcases := []testcase{
+	for _, tc := range []testcase{
 		{
-			name:  "top level",
-			input: []byte(`{"bar": 1}`),
-			err:   `cannot accept top level element: map contains unexpected key "bar"`,
+			input: `{"num": 0}`,
+			err:   `cannot accept element in "num": 0 is less than the allowed minimum 1`,
 		},
 		{
-			name:  "1 level of nesting",
-			input: []byte(`{"foo": {"baz": 1}}`),
-			err:   `cannot accept element in "foo": map contains unexpected key "baz"`,
+			input: `{"map": {"a": null}}`,
+			err:   `cannot accept element in "map.a": cannot accept null value for "int" type`,
 		},
 		{
-			name:  "2 levels of nesting",
-			input: []byte(`{"foo": {"bar": {"boo": 1}}}`),
-			err:   `cannot accept element in "foo.bar": map contains unexpected key "boo"`,
+			input: `{"not-nullable": null}`,
+			err:   `cannot accept element in "not-nullable": cannot accept null value for "string" type`,
 		},
+	} {
+		err = schema.Validate([]byte(tc.input))
+		c.Check(err, ErrorMatches, tc.err, Commentf("input: %s", tc.input))
 	}
+}
 
-	for _, tc := range testcases {
-		err = schema.Validate(tc.input)
-		c.Assert(err, ErrorMatches, tc.err, Commentf("test case %q failed", tc.name))
+func (*schemaSuite) TestNullableKeepsUserTypeStringBased(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"key": {
+			"type": "string",
+			"nullable": true
+		}
+	},
+	"schema": {
+		"foo": {
+			"keys": "$key",
+			"values": "string"
+		}
 	}
+}`)
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
 }
 
-func (*schemaSuite) TestPathPrefixWithMapUnderUserType(c *C) {
+func (*schemaSuite) TestNullableWithDefaultsAndSensitiveValues(c *C) {
 	schemaStr := []byte(`{
-	"types": {
-		"my-type": {
+	"schema": {
+		"token": {
+			"type": "string",
+			"nullable": true,
+			"sensitive": true
+		},
+		"options": {
 			"schema": {
-				"bar": {
+				"level": {
 					"type": "int",
-					"min": 0
+					"default": 1
 				}
-			}
+			},
+			"nullable": true,
+			"default": null
 		}
-	},
+	}
+}`)
+
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	values, err := schema.SensitiveValues([]byte(`{"token": null}`))
+	c.Assert(err, IsNil)
+	c.Check(values, HasLen, 0)
+
+	values, err = schema.SensitiveValues([]byte(`{"token": "hunter2"}`))
+	c.Assert(err, IsNil)
+	c.Check(values, DeepEquals, []string{"hunter2"})
+
+	withDefaults, err := schema.ApplyDefaults([]byte(`{}`))
+	c.Assert(err, IsNil)
+	c.Check(string(withDefaults), Equals, `{"options":null}`)
+
+	withDefaults, err = schema.ApplyDefaults([]byte(`{"options": {}}`))
+	c.Assert(err, IsNil)
+	c.Check(string(withDefaults), Equals, `{"options":{"level":1}}`)
+}
+
+func (*schemaSuite) TestNullInAlternatives(c *C) {
+	schemaStr := []byte(`{
 	"schema": {
-		"foo": "$my-type"
+		"foo": ["string", "null"],
+		"bar": "null"
 	}
 }`)
 
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	input := []byte(`{"foo": {"bar": -1}}`)
-	err = schema.Validate(input)
-	c.Assert(err, ErrorMatches, `cannot accept element in "foo.bar": -1 is less than the allowed minimum 0`)
+	c.Check(schema.Validate([]byte(`{"foo": "a", "bar": null}`)), IsNil)
+	c.Check(schema.Validate([]byte(`{"foo": null}`)), IsNil)
+	c.Check(schema.Validate([]byte(`{"foo": 1}`)), ErrorMatches, `cannot accept element in "foo": no matching alternative type: expected string type but got number; expected null type but got number`)
+	c.Check(schema.Validate([]byte(`{"bar": false}`)), ErrorMatches, `cannot accept element in "bar": expected null type but got bool`)
 }
 
-func (*schemaSuite) TestPathPrefixWithArrayUnderUserType(c *C) {
+func (*schemaSuite) TestNullableFail(c *C) {
 	schemaStr := []byte(`{
-	"types": {
-		"my-type": {
-			"type": "int",
-			"min": 0
-		}
-	},
 	"schema": {
 		"foo": {
-			"type": "array",
-			"values": "$my-type"
+			"type": "string",
+			"nullable": "yes"
 		}
 	}
 }`)
-	schema, err := aspects.ParseSchema(schemaStr)
-	c.Assert(err, IsNil)
 
-	input := []byte(`{"foo": [-1]}`)
-	err = schema.Validate(input)
-	c.Assert(err, ErrorMatches, `cannot accept element in "foo\[0\]": -1 is less than the allowed minimum 0`)
+	_, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, ErrorMatches, `cannot parse "nullable" constraint: .*`)
 }
 
-func (*schemaSuite) TestPathPrefixWithArrayUnderUserWithAContainerElementType(c *C) {
+func (*schemaSuite) TestSchemaVersionRejectsUnknownConstraints(c *C) {
+	for _, tc := range []struct {
+		schema string
+		err    string
+	}{{
+		schema: `{"schema-version": 1, "schema": {"foo": {"type": "string", "max-size": 3}}}`,
+		err:    `cannot parse "string": unknown constraint "max-size"`,
+	}, {
+		schema: `{"schema-version": 1, "schema": {"foo": "int"}, "description": "my schema"}`,
+		err:    `cannot parse "map": unknown constraint "description"`,
+	}, {
+		schema: `{"schema-version": 1, "schema": {"foo": {"type": "array", "values": "int", "unique": true, "max-items": 2}}}`,
+		err:    `cannot parse "array": unknown constraint "max-items"`,
+	}, {
+		schema: `{"schema-version": 1, "types": {"bar": {"type": "int", "step": 2}}, "schema": {"foo": "$bar"}}`,
+		err:    `cannot parse user-defined type "bar": cannot parse "int": unknown constraint "step"`,
+	}, {
+		schema: `{"schema-version": 1, "types": {"bar": {"type": "int", "min": 1}}, "schema": {"foo": {"type": "$bar", "pattern": "a"}}}`,
+		err:    `cannot parse "\$bar": unknown constraint "pattern"`,
+	}} {
+		_, err := aspects.ParseSchema([]byte(tc.schema))
+		c.Check(err, ErrorMatches, tc.err, Commentf("schema: %s", tc.schema))
+	}
+}
+
+func (*schemaSuite) TestSchemaVersionKnownConstraints(c *C) {
 	schemaStr := []byte(`{
+	"schema-version": 1,
+	"min-compatible-version": 1,
 	"types": {
-		"my-type": {
-			"type": "array",
-			"values": {
-				"schema": {
-					"bar": {
-						"type": "int",
-						"min": 0
-					}
-				}
-			}
+		"port": {
+			"type": "int",
+			"min": 1,
+			"max": 65535,
+			"example": 8080
 		}
 	},
 	"schema": {
-		"foo": "$my-type"
+		"port": {
+			"type": "$port",
+			"default": 80
+		},
+		"name": {
+			"type": "string",
+			"pattern": "^[a-z]+$",
+			"nullable": true,
+			"sensitive": true
+		},
+		"tags": {
+			"type": "array",
+			"values": "string",
+			"unique": true
+		}
 	}
 }`)
+
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
+	c.Check(schema.Validate([]byte(`{"port": 443, "name": null, "tags": ["a"]}`)), IsNil)
+}
 
-	input := []byte(`{"foo": [{"bar": 1}, {"bar": -1}]}`)
-	err = schema.Validate(input)
-	c.Assert(err, ErrorMatches, `cannot accept element in "foo\[1\].bar": -1 is less than the allowed minimum 0`)
+func (*schemaSuite) TestSchemaVersionIgnoresUnknownConstraints(c *C) {
+	for _, schemaStr := range []string{
+		// no declared version
+		`{"schema": {"foo": {"type": "string", "max-size": 3}}}`,
+		// a newer version compatible with the supported one
+		`{"schema-version": 2, "schema": {"foo": {"type": "string", "max-size": 3}}}`,
+		`{"schema-version": 2, "min-compatible-version": 1, "schema": {"foo": {"type": "string", "max-size": 3}}}`,
+	} {
+		schema, err := aspects.ParseSchema([]byte(schemaStr))
+		c.Assert(err, IsNil, Commentf("schema: %s", schemaStr))
+		c.Check(schema.Validate([]byte(`{"foo": "abcd"}`)), IsNil)
+	}
 }
 
-func (*schemaSuite) TestPathPrefixWithKeyOrValueConstraints(c *C) {
+func (*schemaSuite) TestSchemaVersionFail(c *C) {
+	for _, tc := range []struct {
+		schema string
+		err    string
+	}{{
+		schema: `{"schema-version": "1", "schema": {}}`,
+		err:    `cannot parse top level schema's "schema-version" entry: .*`,
+	}, {
+		schema: `{"schema-version": 0, "schema": {}}`,
+		err:    `cannot parse top level schema: "schema-version" must be a positive integer`,
+	}, {
+		schema: `{"schema-version": 1, "min-compatible-version": -1, "schema": {}}`,
+		err:    `cannot parse top level schema: "min-compatible-version" must be a positive integer`,
+	}, {
+		schema: `{"min-compatible-version": 1, "schema": {}}`,
+		err:    `cannot parse top level schema: cannot have "min-compatible-version" without "schema-version"`,
+	}, {
+		schema: `{"schema-version": 1, "min-compatible-version": 2, "schema": {}}`,
+		err:    `cannot parse top level schema: "min-compatible-version" 2 is greater than "schema-version" 1`,
+	}, {
+		schema: `{"schema-version": 3, "min-compatible-version": 2, "schema": {}}`,
+		err:    `cannot parse top level schema: requires schema version 2 but only up to 1 is supported`,
+	}} {
+		_, err := aspects.ParseSchema([]byte(tc.schema))
+		c.Check(err, ErrorMatches, tc.err, Commentf("schema: %s", tc.schema))
+	}
+}
+
+func (*schemaSuite) TestValidateValueAt(c *C) {
 	schemaStr := []byte(`{
+	"types": {
+		"snap": {
+			"schema": {
+				"name": "string",
+				"revision": "int"
+			},
+			"required": ["name"]
+		}
+	},
 	"schema": {
-		"foo": {
-			"type": "map",
+		"snaps": {
 			"keys": {
 				"type": "string",
-				"choices": ["my-key"]
+				"pattern": "^[a-z]+$"
 			},
-			"values": {
-				"type": "int",
-				"min": 0
-			}
-		}
+			"values": "$snap"
+		},
+		"settings": {
+			"schema": {
+				"mode": {
+					"type": "string",
+					"choices": ["auto", "manual"]
+				}
+			},
+			"values": "int"
+		},
+		"extra": "any",
+		"port": ["int", {"schema": {"number": "int"}}],
+		"name": "string"
 	}
 }`)
+
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	input := []byte(`{"foo": {"other-key": 1}}`)
-	err = schema.Validate(input)
-	c.Assert(err, ErrorMatches, `cannot accept element in "foo.other-key": string "other-key" is not one of the allowed choices`)
-
-	input = []byte(`{"foo": {"my-key": -1}}`)
-	err = schema.Validate(input)
-	c.Assert(err, ErrorMatches, `cannot accept element in "foo.my-key": -1 is less than the allowed minimum 0`)
+	for _, tc := range []struct {
+		path  string
+		value string
+	}{
+		{"name", `"foo"`},
+		{"snaps", `{"foo": {"name": "foo"}}`},
+		{"snaps.foo", `{"name": "foo", "revision": 1}`},
+		{"snaps.foo.revision", `2`},
+		{"snaps.foo.revision", `null`},
+		{"settings.mode", `"auto"`},
+		{"settings.retries", `3`},
+		{"extra.foo.bar", `[1, "a"]`},
+		{"port", `80`},
+		{"port.number", `80`},
+		{"name", `null`},
+		{"name.foo", `null`},
+	} {
+		err := schema.ValidateValueAt(strings.Split(tc.path, "."), []byte(tc.value))
+		c.Check(err, IsNil, Commentf("path: %s", tc.path))
+	}
 }
 
-func (*schemaSuite) TestPathManyUserDefinedTypeReferences(c *C) {
+func (*schemaSuite) TestValidateValueAtFail(c *C) {
 	schemaStr := []byte(`{
 	"types": {
-		"my-type": {
-			"type": "map",
-			"values": {
-				"type": "int",
-				"min": 0
-			}
+		"snap": {
+			"schema": {
+				"name": "string",
+				"revision": "int"
+			},
+			"required": ["name"]
 		}
 	},
 	"schema": {
-		"foo": "$my-type",
-		"bar": "$my-type"
+		"snaps": {
+			"keys": {
+				"type": "string",
+				"pattern": "^[a-z]+$"
+			},
+			"values": "$snap"
+		},
+		"settings": {
+			"schema": {
+				"mode": {
+					"type": "string",
+					"choices": ["auto", "manual"]
+				}
+			},
+			"values": "int"
+		},
+		"port": ["int", {"schema": {"number": "int"}}],
+		"name": "string"
 	}
 }`)
 
 	schema, err := aspects.ParseSchema(schemaStr)
 	c.Assert(err, IsNil)
 
-	input := []byte(`{"foo": { "one": 1 }, "bar": { "two": -1 } }`)
-	err = schema.Validate(input)
-	c.Assert(err, ErrorMatches, `cannot accept element in "bar.two": -1 is less than the allowed minimum 0`)
-}
-
-func (*schemaSuite) TestValidationError(c *C) {
-	type testcase struct {
-		path     []interface{}
-		expected string
-	}
-
-	cases := []testcase{
+	for _, tc := range []struct {
+		path  string
+		value string
+		err   string
+	}{
 		{
-			path:     []interface{}{"foo", "bar"},
-			expected: "foo.bar",
+			path:  "name",
+			value: `1`,
+			err:   `cannot accept element in "name": expected string type but got number`,
 		},
 		{
-			path:     []interface{}{"foo", 1, "bar"},
-			expected: "foo[1].bar",
+			path:  "other",
+			value: `1`,
+			err:   `cannot accept top level element: map contains unexpected key "other"`,
 		},
 		{
-			path:     []interface{}{"foo", 1, 2, "bar"},
-			expected: "foo[1][2].bar",
+			path:  "snaps.Foo",
+			value: `{"name": "foo"}`,
+			err:   `cannot accept element in "snaps": key "Foo" doesn't conform to required format`,
 		},
 		{
-			path:     []interface{}{"foo", 2.9, 1},
-			expected: "foo.<n/a>[1]",
+			path:  "snaps.foo-bar",
+			value: `{"name": "foo"}`,
+			err:   `cannot accept element in "snaps.foo-bar": string "foo-bar" doesn't match schema pattern .*`,
 		},
-	}
-
-	for _, tc := range cases {
-		err := &aspects.ValidationError{
-			Path: tc.path,
-			Err:  fmt.Errorf("base error"),
-		}
-
-		c.Assert(err.Error(), Equals, fmt.Sprintf(`cannot accept element in %q: base error`, tc.expected))
-	}
-}
-
-func (*schemaSuite) TestUnexpectedTypes(c *C) {
-	type testcase struct {
-		schemaType   string
-		expectedType string
-		testValue    interface{}
-	}
-
-	tcs := []testcase{
 		{
-			schemaType:   `{"type": "array", "values": "any"}`,
-			expectedType: "array",
-			testValue:    true,
+			path:  "snaps.foo",
+			value: `{"revision": 1}`,
+			err:   `cannot accept element in "snaps.foo": cannot find required combinations of keys`,
 		},
 		{
-			schemaType:   `{"type": "map", "values": "any"}`,
-			expectedType: "map",
-			testValue:    true,
+			path:  "snaps.foo.name",
+			value: `null`,
+			err:   `cannot accept element in "snaps.foo": cannot unset required key "name"`,
 		},
 		{
-			schemaType:   `"int"`,
-			expectedType: "int",
-			testValue:    true,
+			path:  "snaps.foo.revision",
+			value: `"1"`,
+			err:   `cannot accept element in "snaps.foo.revision": expected int type but got string`,
 		},
 		{
-			schemaType:   `"number"`,
-			expectedType: "number",
-			testValue:    true,
+			path:  "settings.mode",
+			value: `"off"`,
+			err:   `cannot accept element in "settings.mode": string "off" is not one of the allowed choices`,
 		},
 		{
-			schemaType:   `"string"`,
-			expectedType: "string",
-			testValue:    true,
+			path:  "settings.retries",
+			value: `"3"`,
+			err:   `cannot accept element in "settings.retries": expected int type but got string`,
 		},
 		{
-			schemaType:   `"bool"`,
-			expectedType: "bool",
-			testValue:    `"bar"`,
+			path:  "name.foo",
+			value: `"bar"`,
+			err:   `cannot accept element in "name": cannot set key "foo" under a non-map type`,
 		},
+		{
+			path:  "port.number",
+			value: `"80"`,
+			err:   `cannot accept element in "port": no matching alternative type: cannot set key "number" under a non-map type; cannot accept element in "number": expected int type but got string`,
+		},
+	} {
+		err := schema.ValidateValueAt(strings.Split(tc.path, "."), []byte(tc.value))
+		c.Check(err, ErrorMatches, tc.err, Commentf("path: %s", tc.path))
 	}
+}
 
-	for _, tc := range tcs {
-		schemaStr := []byte(fmt.Sprintf(`{
+func (*schemaSuite) TestValidateValueAtRecursiveDepth(c *C) {
+	schemaStr := []byte(`{
+	"types": {
+		"node": {
+			"schema": {
+				"next": "$node",
+				"value": "int"
+			}
+		}
+	},
 	"schema": {
-		"foo": %s
+		"list": "$node"
 	}
-}`, tc.schemaType))
-		schema, err := aspects.ParseSchema(schemaStr)
-		c.Assert(err, IsNil)
+}`)
 
-		input := []byte(fmt.Sprintf(`{"foo": %v}`, tc.testValue))
-		err = schema.Validate(input)
-		c.Assert(err, ErrorMatches, fmt.Sprintf(`cannot accept element in "foo": expected %s type but got %T`, tc.expectedType, tc.testValue))
+	schema, err := aspects.ParseSchema(schemaStr)
+	c.Assert(err, IsNil)
+
+	path := []string{"list"}
+	for i := 0; i < 10; i++ {
+		path = append(path, "next")
+	}
+	c.Check(schema.ValidateValueAt(append(path, "value"), []byte(`1`)), IsNil)
+
+	for len(path) <= 64 {
+		path = append(path, "next")
 	}
+	err = schema.ValidateValueAt(append(path, "value"), []byte(`1`))
+	c.Check(err, ErrorMatches, `cannot accept top level element: cannot accept value nested deeper than 64 levels`)
 }
@@ -0,0 +1,247 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package aspects
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// valueTransform converts the values of an access pattern between the form
+// they take in requests and the one they're stored in.
+type valueTransform interface {
+	// toStorage converts a value from a request into its stored form.
+	toStorage(value interface{}) (interface{}, error)
+
+	// fromStorage converts a stored value into the form exposed in requests.
+	fromStorage(value interface{}) (interface{}, error)
+
+	// checkStorageSchema checks that the values the transform stores are
+	// accepted by the schema of the storage.
+	checkStorageSchema(schema Schema) error
+}
+
+// newValueTransform parses the "transform" field of an access pattern, which
+// can be one of:
+//   - "map:<request>=<storage>,...", which maps between scalar values (e.g.,
+//     "map:open=0,wpa2=2"). Values are parsed as JSON if possible (so "2" is a
+//     number and "\"2\"" a string) or are taken as plain strings otherwise.
+//   - "iso-duration", which exposes a number of seconds in storage as an ISO
+//     8601 duration (e.g., "PT1H30M").
+func newValueTransform(transform string) (valueTransform, error) {
+	if transform == "iso-duration" {
+		return isoDurationTransform{}, nil
+	}
+
+	if strings.HasPrefix(transform, "map:") {
+		return newMapTransform(strings.TrimPrefix(transform, "map:"))
+	}
+
+	return nil, fmt.Errorf(`unknown transform %q`, transform)
+}
+
+// mapTransform maps between a fixed set of scalar request and storage values.
+type mapTransform struct {
+	// requestToStorage and storageToRequest are keyed by the JSON encoding of
+	// the values so they can be compared regardless of their Go type.
+	requestToStorage map[string]interface{}
+	storageToRequest map[string]interface{}
+}
+
+func newMapTransform(mapping string) (*mapTransform, error) {
+	if mapping == "" {
+		return nil, errors.New(`cannot have empty "map" transform`)
+	}
+
+	t := &mapTransform{
+		requestToStorage: make(map[string]interface{}),
+		storageToRequest: make(map[string]interface{}),
+	}
+	for _, pair := range strings.Split(mapping, ",") {
+		rawReq, rawStorage, ok := strings.Cut(pair, "=")
+		if !ok || rawReq == "" || rawStorage == "" {
+			return nil, fmt.Errorf(`cannot parse "map" transform entry %q: must be <request>=<storage>`, pair)
+		}
+
+		reqValue, reqKey := parseMappedValue(rawReq)
+		storageValue, storageKey := parseMappedValue(rawStorage)
+		if _, ok := t.requestToStorage[reqKey]; ok {
+			return nil, fmt.Errorf(`cannot map request value %s more than once`, reqKey)
+		}
+		if _, ok := t.storageToRequest[storageKey]; ok {
+			return nil, fmt.Errorf(`cannot map storage value %s more than once`, storageKey)
+		}
+
+		t.requestToStorage[reqKey] = storageValue
+		t.storageToRequest[storageKey] = reqValue
+	}
+
+	return t, nil
+}
+
+// parseMappedValue parses a value in a "map" transform, returning it along with
+// its JSON encoding.
+func parseMappedValue(raw string) (value interface{}, key string) {
+	if err := json.Unmarshal([]byte(raw), &value); err == nil {
+		switch value.(type) {
+		case string, float64, bool:
+			return value, mappingKey(value)
+		}
+	}
+
+	return raw, mappingKey(raw)
+}
+
+func mappingKey(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}, nil:
+		return ""
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func (t *mapTransform) toStorage(value interface{}) (interface{}, error) {
+	if stored, ok := t.requestToStorage[mappingKey(value)]; ok {
+		return stored, nil
+	}
+	return nil, fmt.Errorf(`value %v is not one of the mapped values`, value)
+}
+
+func (t *mapTransform) fromStorage(value interface{}) (interface{}, error) {
+	if req, ok := t.storageToRequest[mappingKey(value)]; ok {
+		return req, nil
+	}
+	return nil, fmt.Errorf(`stored value %v is not one of the mapped values`, value)
+}
+
+func (t *mapTransform) checkStorageSchema(schema Schema) error {
+	keys := make([]string, 0, len(t.storageToRequest))
+	for key := range t.storageToRequest {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := schema.Validate([]byte(key)); err != nil {
+			var vErr *ValidationError
+			if errors.As(err, &vErr) && len(vErr.Path) == 0 {
+				err = vErr.Err
+			}
+			return fmt.Errorf(`cannot map to storage value %s: %w`, key, err)
+		}
+	}
+	return nil
+}
+
+// isoDurationTransform exposes a stored number of seconds as an ISO 8601
+// duration made of days, hours, minutes and seconds (e.g., "P1DT2H30M").
+type isoDurationTransform struct{}
+
+var isoDuration = regexp.MustCompile(`^P(?:([0-9]+)D)?(?:T(?:([0-9]+)H)?(?:([0-9]+)M)?(?:([0-9]+)S)?)?$`)
+
+func (isoDurationTransform) toStorage(value interface{}) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf(`expected ISO 8601 duration string but got %T`, value)
+	}
+
+	groups := isoDuration.FindStringSubmatch(str)
+	if groups == nil || str == "P" || strings.HasSuffix(str, "T") {
+		return nil, fmt.Errorf(`cannot parse %q as an ISO 8601 duration`, str)
+	}
+
+	var seconds int64
+	for i, unit := range []int64{24 * 60 * 60, 60 * 60, 60, 1} {
+		if groups[i+1] == "" {
+			continue
+		}
+
+		n, err := strconv.ParseInt(groups[i+1], 10, 64)
+		if err != nil || n > (math.MaxInt64-seconds)/unit {
+			return nil, fmt.Errorf(`cannot parse %q as an ISO 8601 duration: out of range`, str)
+		}
+		seconds += n * unit
+	}
+
+	return seconds, nil
+}
+
+func (isoDurationTransform) fromStorage(value interface{}) (interface{}, error) {
+	var seconds int64
+	switch v := value.(type) {
+	case float64:
+		if v < 0 || v != math.Trunc(v) || v > math.MaxInt64 {
+			return nil, fmt.Errorf(`stored value %v is not a non-negative integer number of seconds`, v)
+		}
+		seconds = int64(v)
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf(`stored value %v is not a non-negative integer number of seconds`, v)
+		}
+		seconds = n
+	default:
+		return nil, fmt.Errorf(`expected stored number of seconds but got %T`, value)
+	}
+
+	if seconds == 0 {
+		return "PT0S", nil
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString("P")
+	if days := seconds / (24 * 60 * 60); days > 0 {
+		fmt.Fprintf(sb, "%dD", days)
+	}
+
+	seconds %= 24 * 60 * 60
+	if seconds > 0 {
+		sb.WriteString("T")
+	}
+	for _, unit := range []struct {
+		secs   int64
+		suffix string
+	}{{60 * 60, "H"}, {60, "M"}, {1, "S"}} {
+		if n := seconds / unit.secs; n > 0 {
+			fmt.Fprintf(sb, "%d%s", n, unit.suffix)
+		}
+		seconds %= unit.secs
+	}
+
+	return sb.String(), nil
+}
+
+func (isoDurationTransform) checkStorageSchema(schema Schema) error {
+	if !acceptsInt(schema, 0) {
+		return errors.New(`"iso-duration" transform must store an integer number of seconds`)
+	}
+	return nil
+}
@@ -1341,3 +1341,24 @@ func SignatureCheck(assert Assertion, pubKey PublicKey) error {
 	}
 	return nil
 }
+
+// SignContent signs arbitrary content that is not an assertion with the
+// given private key and returns the encoded signature. Useful to sign
+// payloads with the device key, see ContentSignatureCheck.
+func SignContent(content []byte, privKey PrivateKey) ([]byte, error) {
+	return signContent(content, privKey)
+}
+
+// ContentSignatureCheck checks the encoded signature of content against
+// the given public key.
+func ContentSignatureCheck(content, encodedSig []byte, pubKey PublicKey) error {
+	sig, err := decodeSignature(encodedSig)
+	if err != nil {
+		return err
+	}
+	err = pubKey.verify(content, sig)
+	if err != nil {
+		return fmt.Errorf("failed signature verification: %v", err)
+	}
+	return nil
+}
@@ -1186,6 +1186,22 @@ func (ss *serialSuite) TestSignatureCheckError(c *C) {
 	c.Check(err, ErrorMatches, `failed signature verification:.*`)
 }
 
+func (ss *serialSuite) TestSignContent(c *C) {
+	content := []byte(`{"type":"registered"}`)
+	sig, err := asserts.SignContent(content, testPrivKey1)
+	c.Assert(err, IsNil)
+
+	err = asserts.ContentSignatureCheck(content, sig, testPrivKey1.PublicKey())
+	c.Check(err, IsNil)
+
+	err = asserts.ContentSignatureCheck([]byte(`{"type":"other"}`), sig, testPrivKey1.PublicKey())
+	c.Check(err, ErrorMatches, `failed signature verification:.*`)
+	err = asserts.ContentSignatureCheck(content, sig, testPrivKey2.PublicKey())
+	c.Check(err, ErrorMatches, `failed signature verification:.*`)
+	err = asserts.ContentSignatureCheck(content, []byte("garbage"), testPrivKey1.PublicKey())
+	c.Check(err, ErrorMatches, `cannot decode signature:.*`)
+}
+
 func (as *assertsSuite) TestWithAuthority(c *C) {
 	withAuthority := []string{
 		"account",
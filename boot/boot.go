@@ -24,6 +24,7 @@ import (
 	"fmt"
 
 	"github.com/snapcore/snapd/bootloader"
+	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/snap"
 )
 
@@ -470,7 +471,7 @@ func updateCmdlineVars(tbl bootloader.TrustedAssetsBootloader, gadgetSnapOrDir,
 		return err
 	}
 
-	cmdlineVars, err := bootVarsForTrustedCommandLineFromGadget(gadgetSnapOrDir, cmdlineAppend, defaultCmdLine, dev.Model())
+	cmdlineVars, provenance, err := bootVarsForTrustedCommandLineFromGadget(gadgetSnapOrDir, cmdlineAppend, defaultCmdLine, dev.Model())
 	if err != nil {
 		return fmt.Errorf("cannot prepare bootloader variables for kernel command line: %v", err)
 	}
@@ -479,6 +480,9 @@ func updateCmdlineVars(tbl bootloader.TrustedAssetsBootloader, gadgetSnapOrDir,
 		return fmt.Errorf("cannot set run system kernel command line arguments: %v", err)
 	}
 
+	if err := provenance.writeTo(dirs.GlobalRootDir); err != nil {
+		return fmt.Errorf("cannot record kernel command line provenance: %v", err)
+	}
 	return nil
 }
 
@@ -35,6 +35,7 @@ import (
 	"github.com/snapcore/snapd/bootloader"
 	"github.com/snapcore/snapd/bootloader/bootloadertest"
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil/kcmdline"
 	"github.com/snapcore/snapd/release"
 	"github.com/snapcore/snapd/secboot"
@@ -2948,6 +2949,57 @@ func (s *bootenv20Suite) TestMarkBootSuccessful20CommandLineUpdatedHappy(c *C) {
 	})
 }
 
+func (s *bootenv20Suite) testMarkBootSuccessful20CommandLineUnchanged(c *C, bootedWith, expectedLog string) {
+	logbuf, restore := logger.MockLogger()
+	defer restore()
+	s.mockCmdline(c, bootedWith)
+	tab := s.bootloaderWithTrustedAssets(c, []string{"asset"})
+	coreDev := boottest.MockUC20Device("", nil)
+	c.Assert(coreDev.HasModeenv(), Equals, true)
+	m := s.setupMarkBootSuccessful20CommandLine(c, coreDev.Model(), "run", boot.BootCommandLines{
+		"snapd_recovery_mode=run panic=-1",
+	})
+	r := setupUC20Bootenv(
+		c,
+		tab.MockBootloader,
+		&bootenv20Setup{
+			modeenv:    m,
+			kern:       s.kern1,
+			kernStatus: boot.DefaultStatus,
+		},
+	)
+	defer r()
+
+	// mark successful
+	err := boot.MarkBootSuccessful(coreDev)
+	c.Assert(err, IsNil)
+
+	// modeenv is unchanged either way
+	m2, err := boot.ReadModeenv("")
+	c.Assert(err, IsNil)
+	c.Check(m2.CurrentKernelCommandLines, DeepEquals, boot.BootCommandLines{
+		"snapd_recovery_mode=run panic=-1",
+	})
+	if expectedLog == "" {
+		c.Check(logbuf.String(), Not(testutil.Contains), "WARNING")
+	} else {
+		c.Check(logbuf.String(), testutil.Contains, expectedLog)
+	}
+}
+
+func (s *bootenv20Suite) TestMarkBootSuccessful20CommandLineUnchangedHappy(c *C) {
+	s.testMarkBootSuccessful20CommandLineUnchanged(c, "snapd_recovery_mode=run panic=-1", "")
+}
+
+func (s *bootenv20Suite) TestMarkBootSuccessful20CommandLineUnchangedTryingKernel(c *C) {
+	s.testMarkBootSuccessful20CommandLineUnchanged(c, "snapd_recovery_mode=run panic=-1 kernel_status=trying", "")
+}
+
+func (s *bootenv20Suite) TestMarkBootSuccessful20CommandLineUnchangedTampered(c *C) {
+	s.testMarkBootSuccessful20CommandLineUnchanged(c, "snapd_recovery_mode=run panic=-1 init=/bin/sh",
+		`WARNING: kernel command line "snapd_recovery_mode=run panic=-1 init=/bin/sh" does not match the expected "snapd_recovery_mode=run panic=-1", it was modified outside of snapd`)
+}
+
 func (s *bootenv20Suite) TestMarkBootSuccessful20CommandLineUpdatedOld(c *C) {
 	s.mockCmdline(c, "snapd_recovery_mode=run panic=-1")
 	tab := s.bootloaderWithTrustedAssets(c, []string{"asset"})
@@ -3907,6 +3959,9 @@ volumes:
 		"snapd_extra_cmdline_args": "",
 		"snapd_full_cmdline_args":  "static mocked panic=-1 args from gadget",
 	})
+	// and the sources of the arguments were recorded
+	c.Check(dirs.KernelCmdlineProvenanceFileUnder(dirs.GlobalRootDir), testutil.FileEquals,
+		`{"bootloader":"static mocked panic=-1","gadget":"args from gadget"}`)
 }
 
 func (s *bootKernelCommandLineSuite) TestCommandLineUpdateUC20ArgsSwitch(c *C) {
@@ -20,14 +20,19 @@
 package boot
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/snapcore/snapd/asserts"
 	"github.com/snapcore/snapd/bootloader"
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/gadget"
 	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/osutil/kcmdline"
 	"github.com/snapcore/snapd/strutil"
 )
@@ -106,20 +111,25 @@ func getBootloaderManagingItsAssets(where string, opts *bootloader.Options) (boo
 
 // bootVarsForTrustedCommandLineFromGadget returns a set of boot
 // variables that carry the command line arguments defined by the
-// gadget and some system options (cmdlineApped). This is only useful
-// if snapd is managing the boot config.
-func bootVarsForTrustedCommandLineFromGadget(gadgetDirOrSnapPath, cmdlineAppend string, defaultCmdline string, model gadget.Model) (map[string]string, error) {
-	extraOrFull, full, removeArgs, err := gadget.KernelCommandLineFromGadget(gadgetDirOrSnapPath, model)
+// gadget and some system options (cmdlineApped), along with the
+// sources of those arguments. This is only useful if snapd is managing
+// the boot config.
+func bootVarsForTrustedCommandLineFromGadget(gadgetDirOrSnapPath, cmdlineAppend string, defaultCmdline string, model gadget.Model) (map[string]string, *commandLineProvenance, error) {
+	fromGadget, full, removeArgs, err := gadget.KernelCommandLineFromGadget(gadgetDirOrSnapPath, model)
 	if err != nil {
-		return nil, fmt.Errorf("cannot use kernel command line from gadget: %v", err)
+		return nil, nil, fmt.Errorf("cannot use kernel command line from gadget: %v", err)
 	}
 	logger.Debugf("trusted command line: from gadget: %q, from options: %q",
-		extraOrFull, cmdlineAppend)
+		fromGadget, cmdlineAppend)
 
-	extraOrFull = strutil.JoinNonEmpty([]string{extraOrFull, cmdlineAppend}, " ")
+	extraOrFull := strutil.JoinNonEmpty([]string{fromGadget, cmdlineAppend}, " ")
 
 	keepDefaultArgs := kcmdline.RemoveMatchingFilter(defaultCmdline, removeArgs)
 
+	provenance := &commandLineProvenance{
+		Gadget:        fromGadget,
+		SystemOptions: cmdlineAppend,
+	}
 	// gadget has the kernel command line
 	args := map[string]string{
 		"snapd_extra_cmdline_args": "",
@@ -129,13 +139,161 @@ func bootVarsForTrustedCommandLineFromGadget(gadgetDirOrSnapPath, cmdlineAppend
 		args["snapd_full_cmdline_args"] = extraOrFull
 	} else {
 		args["snapd_full_cmdline_args"] = strutil.JoinNonEmpty(append(keepDefaultArgs, extraOrFull), " ")
+		provenance.Bootloader = strings.Join(keepDefaultArgs, " ")
 	}
 	if len(args["snapd_full_cmdline_args"]) == 0 {
 		// grub.cfg tests if snapd_full_cmdline_args is set by looking if it is not empty.
 		// Here, it should be set, but empty. So adding a space will force grub.cfg to use it.
 		args["snapd_full_cmdline_args"] = " "
 	}
-	return args, nil
+	return args, provenance, nil
+}
+
+// CommandLineSource is the source of an argument of the kernel command
+// line.
+type CommandLineSource string
+
+const (
+	// CommandLineSourceSnapd is used for the arguments set by snapd
+	// when booting, which select the mode and recovery system or
+	// indicate a kernel being tried.
+	CommandLineSourceSnapd CommandLineSource = "snapd"
+	// CommandLineSourceBootloader is used for the built-in arguments
+	// of the bootloader.
+	CommandLineSourceBootloader CommandLineSource = "bootloader"
+	// CommandLineSourceGadget is used for the arguments coming from the
+	// gadget.
+	CommandLineSourceGadget CommandLineSource = "gadget"
+	// CommandLineSourceSystemOptions is used for the arguments coming
+	// from the system.kernel.*cmdline-append system options.
+	CommandLineSourceSystemOptions CommandLineSource = "system-options"
+	// CommandLineSourceUnknown is used for the arguments that snapd did
+	// not set, which indicates that the command line was modified
+	// outside of snapd.
+	CommandLineSourceUnknown CommandLineSource = "unknown"
+)
+
+// snapdBootArgs are the arguments of the kernel command line set when
+// booting rather than recorded in the boot variables.
+var snapdBootArgs = []string{"snapd_recovery_mode", "snapd_recovery_system", "kernel_status"}
+
+// commandLineProvenance records the sources of the arguments that snapd
+// set for the run mode kernel command line.
+type commandLineProvenance struct {
+	Bootloader    string `json:"bootloader,omitempty"`
+	Gadget        string `json:"gadget,omitempty"`
+	SystemOptions string `json:"system-options,omitempty"`
+}
+
+func (p *commandLineProvenance) writeTo(rootdir string) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	provenanceFile := dirs.KernelCmdlineProvenanceFileUnder(rootdir)
+	if err := os.MkdirAll(filepath.Dir(provenanceFile), 0755); err != nil {
+		return err
+	}
+	return osutil.AtomicWriteFile(provenanceFile, b, 0644, 0)
+}
+
+func readCommandLineProvenance(rootdir string) (*commandLineProvenance, error) {
+	b, err := os.ReadFile(dirs.KernelCmdlineProvenanceFileUnder(rootdir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &commandLineProvenance{}, nil
+		}
+		return nil, err
+	}
+	var p commandLineProvenance
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("cannot read kernel command line provenance: %v", err)
+	}
+	return &p, nil
+}
+
+// CommandLineArgument is an argument of the kernel command line along
+// with its source.
+type CommandLineArgument struct {
+	Arg    string
+	Source CommandLineSource
+}
+
+// KernelCommandLineProvenance returns the arguments of the kernel command
+// line the system booted with, annotated with their sources as recorded
+// when snapd last set the run mode kernel command line.
+func KernelCommandLineProvenance() ([]CommandLineArgument, error) {
+	cmdline, err := kcmdline.KernelCommandLine()
+	if err != nil {
+		return nil, err
+	}
+	p, err := readCommandLineProvenance(dirs.GlobalRootDir)
+	if err != nil {
+		return nil, err
+	}
+	return p.annotate(cmdline)
+}
+
+func (p *commandLineProvenance) annotate(cmdline string) ([]CommandLineArgument, error) {
+	var expected []CommandLineArgument
+	for _, part := range []struct {
+		args   string
+		source CommandLineSource
+	}{
+		{p.Bootloader, CommandLineSourceBootloader},
+		{p.Gadget, CommandLineSourceGadget},
+		{p.SystemOptions, CommandLineSourceSystemOptions},
+	} {
+		args, err := kcmdline.Split(part.args)
+		if err != nil {
+			return nil, err
+		}
+		for _, arg := range args {
+			expected = append(expected, CommandLineArgument{Arg: arg, Source: part.source})
+		}
+	}
+
+	args, err := kcmdline.Split(cmdline)
+	if err != nil {
+		return nil, err
+	}
+	used := make([]bool, len(expected))
+	out := make([]CommandLineArgument, 0, len(args))
+	for _, arg := range args {
+		source := CommandLineSourceUnknown
+		if strutil.ListContains(snapdBootArgs, strings.SplitN(arg, "=", 2)[0]) {
+			source = CommandLineSourceSnapd
+		} else {
+			// the same argument may appear more than once, pick
+			// the first source not claimed yet
+			for i, e := range expected {
+				if !used[i] && e.Arg == arg {
+					used[i] = true
+					source = e.Source
+					break
+				}
+			}
+		}
+		out = append(out, CommandLineArgument{Arg: arg, Source: source})
+	}
+	return out, nil
+}
+
+// withoutTransientArgs drops the arguments indicating a kernel being
+// tried, which are not part of the expected kernel command lines.
+func withoutTransientArgs(cmdline string) string {
+	args, err := kcmdline.Split(cmdline)
+	if err != nil {
+		return cmdline
+	}
+	kept := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "kernel_status=") {
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return strings.Join(kept, " ")
 }
 
 const (
@@ -255,13 +413,31 @@ func observeSuccessfulCommandLine(model *asserts.Model, m *Modeenv) (*Modeenv, e
 		// snapd
 		return observeSuccessfulCommandLineCompatBoot(model, m)
 	case 1:
-		// no command line update
+		// no command line update, but check that what we booted
+		// with is what snapd set up
+		warnOnUnexpectedCommandLine(m.CurrentKernelCommandLines[0])
 		return m, nil
 	default:
 		return observeSuccessfulCommandLineUpdate(m)
 	}
 }
 
+// warnOnUnexpectedCommandLine logs a warning if the kernel command line
+// of the running system does not match the expected one, which means it
+// was modified outside of snapd, for instance by editing it in the
+// bootloader.
+func warnOnUnexpectedCommandLine(expected string) {
+	cmdlineBootedWith, err := kcmdline.KernelCommandLine()
+	if err != nil {
+		logger.Noticef("cannot check the kernel command line: %v", err)
+		return
+	}
+	if withoutTransientArgs(cmdlineBootedWith) != expected {
+		logger.Noticef("WARNING: kernel command line %q does not match the expected %q, it was modified outside of snapd",
+			cmdlineBootedWith, expected)
+	}
+}
+
 // observeSuccessfulCommandLineUpdate observes a successful boot with a command
 // line which is expected to be listed among the current kernel command line
 // entries carried in the modeenv. One of those entries must match the current
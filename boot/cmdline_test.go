@@ -30,6 +30,7 @@ import (
 	"github.com/snapcore/snapd/boot/boottest"
 	"github.com/snapcore/snapd/bootloader"
 	"github.com/snapcore/snapd/bootloader/bootloadertest"
+	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/gadget/gadgettest"
 	"github.com/snapcore/snapd/osutil/kcmdline"
 	"github.com/snapcore/snapd/snap/snaptest"
@@ -499,7 +500,7 @@ volumes:
 			{"meta/snap.yaml", gadgetSnapYaml},
 			{"meta/gadget.yaml", gadgetYaml},
 		}, tc.files...))
-		vars, err := boot.BootVarsForTrustedCommandLineFromGadget(sf, tc.cmdlineAppend, "default", model)
+		vars, _, err := boot.BootVarsForTrustedCommandLineFromGadget(sf, tc.cmdlineAppend, "default", model)
 		if tc.errMsg == "" {
 			c.Assert(err, IsNil)
 			c.Assert(vars, DeepEquals, tc.expectedVars)
@@ -508,3 +509,42 @@ volumes:
 		}
 	}
 }
+
+func (s *kernelCommandLineSuite) TestKernelCommandLineProvenance(c *C) {
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+
+	provenanceFile := dirs.KernelCmdlineProvenanceFileUnder(dirs.GlobalRootDir)
+	c.Assert(os.MkdirAll(filepath.Dir(provenanceFile), 0755), IsNil)
+	err := os.WriteFile(provenanceFile, []byte(`{"bootloader":"console=ttyS0 panic=-1","gadget":"quiet splash","system-options":"opt=1 quiet"}`), 0644)
+	c.Assert(err, IsNil)
+
+	s.mockProcCmdlineContent(c, "snapd_recovery_mode=run console=ttyS0 panic=-1 quiet splash opt=1 quiet quiet init=/bin/sh kernel_status=trying")
+	args, err := boot.KernelCommandLineProvenance()
+	c.Assert(err, IsNil)
+	c.Check(args, DeepEquals, []boot.CommandLineArgument{
+		{Arg: "snapd_recovery_mode=run", Source: boot.CommandLineSourceSnapd},
+		{Arg: "console=ttyS0", Source: boot.CommandLineSourceBootloader},
+		{Arg: "panic=-1", Source: boot.CommandLineSourceBootloader},
+		{Arg: "quiet", Source: boot.CommandLineSourceGadget},
+		{Arg: "splash", Source: boot.CommandLineSourceGadget},
+		{Arg: "opt=1", Source: boot.CommandLineSourceSystemOptions},
+		{Arg: "quiet", Source: boot.CommandLineSourceSystemOptions},
+		{Arg: "quiet", Source: boot.CommandLineSourceUnknown},
+		{Arg: "init=/bin/sh", Source: boot.CommandLineSourceUnknown},
+		{Arg: "kernel_status=trying", Source: boot.CommandLineSourceSnapd},
+	})
+}
+
+func (s *kernelCommandLineSuite) TestKernelCommandLineProvenanceNotRecorded(c *C) {
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+
+	s.mockProcCmdlineContent(c, "snapd_recovery_mode=run panic=-1")
+	args, err := boot.KernelCommandLineProvenance()
+	c.Assert(err, IsNil)
+	c.Check(args, DeepEquals, []boot.CommandLineArgument{
+		{Arg: "snapd_recovery_mode=run", Source: boot.CommandLineSourceSnapd},
+		{Arg: "panic=-1", Source: boot.CommandLineSourceUnknown},
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/snapcore/snapd/bootloader"
 	"github.com/snapcore/snapd/dirs"
@@ -91,6 +92,21 @@ func DebugDumpBootVars(w io.Writer, dir string, uc20 bool) error {
 	return nil
 }
 
+// DebugDumpKernelCommandLine writes the arguments of the current kernel
+// command line along with their sources to the given writer
+func DebugDumpKernelCommandLine(w io.Writer) error {
+	args, err := KernelCommandLineProvenance()
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 5, 3, 2, ' ', 0)
+	fmt.Fprintln(tw, "Argument\tSource")
+	for _, arg := range args {
+		fmt.Fprintf(tw, "%s\t%s\n", arg.Arg, arg.Source)
+	}
+	return tw.Flush()
+}
+
 // DebugSetBootVars is a debug helper that takes a list of <var>=<value> entries
 // and sets them for the configured bootloader.
 func DebugSetBootVars(dir string, recoveryBootloader bool, varEqVal []string) error {
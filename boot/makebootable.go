@@ -312,7 +312,7 @@ func MakeRecoverySystemBootable(model *asserts.Model, rootdir string, relativeRe
 			return err
 		}
 		// to set cmdlineAppend.
-		recoveryCmdlineArgs, err := bootVarsForTrustedCommandLineFromGadget(bootWith.GadgetSnapOrDir, cmdlineAppend, defaultCmdLine, model)
+		recoveryCmdlineArgs, _, err := bootVarsForTrustedCommandLineFromGadget(bootWith.GadgetSnapOrDir, cmdlineAppend, defaultCmdLine, model)
 		if err != nil {
 			return fmt.Errorf("cannot obtain recovery system command line: %v", err)
 		}
@@ -517,13 +517,16 @@ func makeRunnableSystem(model *asserts.Model, bootWith *BootableSet, sealer *Tru
 			return err
 		}
 
-		cmdlineVars, err := bootVarsForTrustedCommandLineFromGadget(bootWith.UnpackedGadgetDir, cmdlineAppend, defaultCmdLine, model)
+		cmdlineVars, provenance, err := bootVarsForTrustedCommandLineFromGadget(bootWith.UnpackedGadgetDir, cmdlineAppend, defaultCmdLine, model)
 		if err != nil {
 			return fmt.Errorf("cannot prepare bootloader variables for kernel command line: %v", err)
 		}
 		if err := bl.SetBootVars(cmdlineVars); err != nil {
 			return fmt.Errorf("cannot set run system kernel command line arguments: %v", err)
 		}
+		if err := provenance.writeTo(InstallHostWritableDir(model)); err != nil {
+			return fmt.Errorf("cannot record kernel command line provenance: %v", err)
+		}
 	}
 
 	// all fields that needed to be set in the modeenv must have been set by
@@ -324,6 +324,7 @@ var (
 		newAndroidBoot,
 		newLk,
 		newPiboot,
+		newSdboot,
 	}
 )
 
@@ -320,6 +320,10 @@ func LayoutKernelAssetsToDir(b Bootloader, snapf snap.Container, dstDir string)
 	return p.layoutKernelAssetsToDir(snapf, dstDir)
 }
 
+func NewSdboot(rootdir string, opts *Options) RecoveryAwareBootloader {
+	return newSdboot(rootdir, opts).(RecoveryAwareBootloader)
+}
+
 var (
 	EditionFromDiskConfigAsset           = editionFromDiskConfigAsset
 	EditionFromConfigAsset               = editionFromConfigAsset
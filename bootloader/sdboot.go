@@ -0,0 +1,554 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package bootloader
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/snapcore/snapd/arch"
+	"github.com/snapcore/snapd/bootloader/grubenv"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/osutil/kcmdline"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
+)
+
+// ensure sdboot implements the required interfaces
+var (
+	_ Bootloader                             = (*sdboot)(nil)
+	_ RecoveryAwareBootloader                = (*sdboot)(nil)
+	_ ExtractedRecoveryKernelImageBootloader = (*sdboot)(nil)
+	_ NotScriptableBootloader                = (*sdboot)(nil)
+	_ TrustedAssetsBootloader                = (*sdboot)(nil)
+)
+
+// systemd-boot cannot be scripted, so snapd keeps its variables in an
+// environment file and generates the boot loader entries out of them
+// whenever they change. The layout is as follows:
+//
+// ubuntu-seed (the ESP):
+//
+//	EFI/boot/bootx64.efi                    shim
+//	EFI/systemd/systemd-bootx64.efi         systemd-boot
+//	EFI/ubuntu/sdbootenv                    recovery variables
+//	loader/loader.conf                      managed by snapd
+//	loader/entries/snapd-recovery.conf      install/recover/... modes
+//	systems/<label>/kernel/kernel.efi       extracted recovery kernels
+//
+// ubuntu-boot (an XBOOTLDR partition, so that systemd-boot from the ESP
+// picks up its entries):
+//
+//	EFI/ubuntu/sdbootenv                    run mode variables
+//	EFI/ubuntu/<kernel>.snap/kernel.efi     extracted run mode kernels
+//	loader/entries/snapd-run.conf           run mode
+//	loader/entries/snapd-try+1.conf         run mode, trying a new kernel
+//
+// The entries carry sort keys so that the recovery entry, if present,
+// comes first, then the try entry, then the run mode one. loader.conf
+// selects the first of them by default. The try entry uses the boot
+// counting of systemd-boot: after a boot attempt it is marked as bad,
+// moving it to the end of the list, which means that unless snapd
+// removes it once the new kernel is known to be good, the following
+// boot falls back to the run mode entry.
+const (
+	sdbootEnvFile    = "EFI/ubuntu/sdbootenv"
+	sdbootLoaderConf = "loader/loader.conf"
+	sdbootEntriesDir = "loader/entries"
+
+	sdbootRecoveryEntry = "snapd-recovery.conf"
+	sdbootRunEntry      = "snapd-run.conf"
+	// a single boot attempt, see above
+	sdbootTryEntry        = "snapd-try+1.conf"
+	sdbootTryEntryPattern = "snapd-try+*.conf"
+
+	sdbootLoaderConfContent = `# This file is managed by snapd, do not edit
+default snapd-*
+timeout 0
+editor no
+`
+
+	// sdbootStaticCommandLine is the default kernel command line, it
+	// matches the one of the grub boot config assets
+	sdbootStaticCommandLine = "console=ttyS0 console=tty1 panic=-1"
+)
+
+type sdboot struct {
+	rootdir string
+
+	recovery         bool
+	prepareImageTime bool
+}
+
+// newSdboot creates a new systemd-boot bootloader object
+func newSdboot(rootdir string, opts *Options) Bootloader {
+	s := &sdboot{rootdir: rootdir}
+	if opts != nil {
+		s.recovery = opts.Role == RoleRecovery
+		s.prepareImageTime = opts.PrepareImageTime
+	}
+	return s
+}
+
+func (s *sdboot) Name() string {
+	return "systemd-boot"
+}
+
+func (s *sdboot) dir() string {
+	if s.rootdir == "" {
+		panic("internal error: unset rootdir")
+	}
+	return s.rootdir
+}
+
+func (s *sdboot) envFile() string {
+	return filepath.Join(s.dir(), sdbootEnvFile)
+}
+
+func (s *sdboot) entriesDir() string {
+	return filepath.Join(s.dir(), sdbootEntriesDir)
+}
+
+// Present returns whether the systemd-boot environment file managed by
+// snapd exists.
+func (s *sdboot) Present() (bool, error) {
+	return osutil.FileExists(s.envFile()), nil
+}
+
+func (s *sdboot) InstallBootConfig(gadgetDir string, opts *Options) error {
+	if opts == nil || opts.Role == RoleSole {
+		return fmt.Errorf("cannot use systemd-boot without a run mode and a recovery bootloader")
+	}
+	if err := os.MkdirAll(s.entriesDir(), 0755); err != nil {
+		return err
+	}
+	if opts.Role == RoleRecovery {
+		// only the ESP carries the loader configuration
+		loaderConf := filepath.Join(s.dir(), sdbootLoaderConf)
+		if err := osutil.AtomicWriteFile(loaderConf, []byte(sdbootLoaderConfContent), 0644, 0); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(s.envFile()), 0755); err != nil {
+		return err
+	}
+	return grubenv.NewEnv(s.envFile()).Save()
+}
+
+func loadSdbootEnv(path string) (*grubenv.Env, error) {
+	env := grubenv.NewEnv(path)
+	if err := env.Load(); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return env, nil
+}
+
+func (s *sdboot) GetBootVars(names ...string) (map[string]string, error) {
+	env, err := loadSdbootEnv(s.envFile())
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]string, len(names))
+	for _, name := range names {
+		out[name] = env.Get(name)
+	}
+	return out, nil
+}
+
+func (s *sdboot) setBootVars(values map[string]string) (env *grubenv.Env, changed bool, err error) {
+	env, err = loadSdbootEnv(s.envFile())
+	if err != nil {
+		return nil, false, err
+	}
+	for k, v := range values {
+		if env.Get(k) == v {
+			continue
+		}
+		env.Set(k, v)
+		changed = true
+	}
+	if changed {
+		if err := os.MkdirAll(filepath.Dir(s.envFile()), 0755); err != nil {
+			return nil, false, err
+		}
+		if err := env.Save(); err != nil {
+			return nil, false, err
+		}
+	}
+	return env, changed, nil
+}
+
+// SetBootVars sets the given variables and regenerates the boot loader
+// entries to reflect them.
+func (s *sdboot) SetBootVars(values map[string]string) error {
+	env, changed, err := s.setBootVars(values)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	if s.recovery {
+		return s.writeRecoveryEntry(env)
+	}
+	return s.writeRunModeEntries(env)
+}
+
+// SetBootVarsFromInitramfs sets the given variables without touching the
+// boot loader entries, so that the initramfs can record the outcome of a
+// try boot.
+func (s *sdboot) SetBootVarsFromInitramfs(values map[string]string) error {
+	_, _, err := s.setBootVars(values)
+	return err
+}
+
+func (s *sdboot) recoveryEnvFile(recoverySystemDir string) string {
+	return filepath.Join(s.dir(), recoverySystemDir, "sdbootenv")
+}
+
+func (s *sdboot) SetRecoverySystemEnv(recoverySystemDir string, values map[string]string) error {
+	if recoverySystemDir == "" {
+		return fmt.Errorf("internal error: recoverySystemDir unset")
+	}
+	envFile := s.recoveryEnvFile(recoverySystemDir)
+	if err := os.MkdirAll(filepath.Dir(envFile), 0755); err != nil {
+		return err
+	}
+	env := grubenv.NewEnv(envFile)
+	for k, v := range values {
+		env.Set(k, v)
+	}
+	return env.Save()
+}
+
+func (s *sdboot) GetRecoverySystemEnv(recoverySystemDir string, key string) (string, error) {
+	if recoverySystemDir == "" {
+		return "", fmt.Errorf("internal error: recoverySystemDir unset")
+	}
+	env := grubenv.NewEnv(s.recoveryEnvFile(recoverySystemDir))
+	if err := env.Load(); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return env.Get(key), nil
+}
+
+func (s *sdboot) writeEntry(name, title, efi, options string) error {
+	// the sort key is derived from the name, see the layout description
+	// above
+	var sortKey string
+	switch name {
+	case sdbootRecoveryEntry:
+		sortKey = "snapd-0-recovery"
+	case sdbootTryEntry:
+		sortKey = "snapd-1-try"
+	default:
+		sortKey = "snapd-2-run"
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# This file is managed by snapd, do not edit\n")
+	fmt.Fprintf(&buf, "title %s\n", title)
+	fmt.Fprintf(&buf, "sort-key %s\n", sortKey)
+	fmt.Fprintf(&buf, "efi %s\n", efi)
+	fmt.Fprintf(&buf, "options %s\n", options)
+
+	if err := os.MkdirAll(s.entriesDir(), 0755); err != nil {
+		return err
+	}
+	return osutil.AtomicWriteFile(filepath.Join(s.entriesDir(), name), buf.Bytes(), 0644, 0)
+}
+
+func (s *sdboot) removeEntries(pattern string) error {
+	matches, err := filepath.Glob(filepath.Join(s.entriesDir(), pattern))
+	if err != nil {
+		return err
+	}
+	for _, m := range matches {
+		if err := os.Remove(m); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// argsFromEnv returns the command line arguments following the snapd
+// ones, as carried by the snapd_{extra,full}_cmdline_args variables.
+func argsFromEnv(env *grubenv.Env) CommandLineComponents {
+	// note that like with grub a set but empty full command line is
+	// represented by a single space
+	if full := env.Get("snapd_full_cmdline_args"); full != "" {
+		return CommandLineComponents{FullArgs: full}
+	}
+	return CommandLineComponents{ExtraArgs: env.Get("snapd_extra_cmdline_args")}
+}
+
+func (s *sdboot) writeRunModeEntries(env *grubenv.Env) error {
+	pieces := argsFromEnv(env)
+	pieces.ModeArg = "snapd_recovery_mode=run"
+
+	kernel := env.Get("snap_kernel")
+	if kernel != "" {
+		options, err := s.commandLine(pieces)
+		if err != nil {
+			return err
+		}
+		efi := "/" + filepath.Join("EFI/ubuntu", kernel, "kernel.efi")
+		if err := s.writeEntry(sdbootRunEntry, "Ubuntu Core", efi, options); err != nil {
+			return err
+		}
+	}
+
+	// always start over with a fresh boot counter
+	if err := s.removeEntries(sdbootTryEntryPattern); err != nil {
+		return err
+	}
+	tryKernel := env.Get("snap_try_kernel")
+	if env.Get("kernel_status") != "try" || tryKernel == "" {
+		return nil
+	}
+	// signal the initramfs that we are trying the new kernel
+	pieces.ModeArg = "snapd_recovery_mode=run kernel_status=trying"
+	options, err := s.commandLine(pieces)
+	if err != nil {
+		return err
+	}
+	efi := "/" + filepath.Join("EFI/ubuntu", tryKernel, "kernel.efi")
+	logger.Debugf("enabling systemd-boot try entry for %s", tryKernel)
+	return s.writeEntry(sdbootTryEntry, "Ubuntu Core (trying new kernel)", efi, options)
+}
+
+func (s *sdboot) writeRecoveryEntry(env *grubenv.Env) error {
+	mode := env.Get("snapd_recovery_mode")
+	system := env.Get("snapd_recovery_system")
+	if mode == "" || mode == "run" || system == "" {
+		// boot into run mode through the entries of ubuntu-boot
+		return s.removeEntries(sdbootRecoveryEntry)
+	}
+
+	// TODO: support trying recovery systems, which grub does through
+	// the recovery_system_status and try_recovery_system variables
+	recoverySystemDir := filepath.Join("/systems", system)
+	recoveryEnv, err := loadSdbootEnv(s.recoveryEnvFile(recoverySystemDir))
+	if err != nil {
+		return err
+	}
+	pieces := argsFromEnv(recoveryEnv)
+	pieces.ModeArg = "snapd_recovery_mode=" + mode
+	pieces.SystemArg = "snapd_recovery_system=" + system
+	options, err := s.commandLine(pieces)
+	if err != nil {
+		return err
+	}
+	efi := filepath.Join(recoverySystemDir, "kernel", "kernel.efi")
+	title := fmt.Sprintf("Ubuntu Core %s (%s)", system, mode)
+	return s.writeEntry(sdbootRecoveryEntry, title, efi, options)
+}
+
+func (s *sdboot) ExtractKernelAssets(sn snap.PlaceInfo, snapf snap.Container) error {
+	if s.recovery {
+		// recovery kernels are extracted into the recovery system
+		// directory by ExtractRecoveryKernelAssets
+		return nil
+	}
+	return extractKernelAssetsToBootDir(
+		filepath.Join(s.dir(), "EFI/ubuntu", sn.Filename()),
+		snapf,
+		[]string{"kernel.efi"},
+	)
+}
+
+func (s *sdboot) ExtractRecoveryKernelAssets(recoverySystemDir string, sn snap.PlaceInfo, snapf snap.Container) error {
+	if recoverySystemDir == "" {
+		return fmt.Errorf("internal error: recoverySystemDir unset")
+	}
+	return extractKernelAssetsToBootDir(
+		filepath.Join(s.dir(), recoverySystemDir, "kernel"),
+		snapf,
+		[]string{"kernel.efi"},
+	)
+}
+
+func (s *sdboot) RemoveKernelAssets(sn snap.PlaceInfo) error {
+	return removeKernelAssetsFromBootDir(filepath.Join(s.dir(), "EFI/ubuntu"), sn)
+}
+
+// ManagedAssets returns a list relative paths to boot assets inside the root
+// directory of the filesystem.
+//
+// Implements TrustedAssetsBootloader for the systemd-boot bootloader.
+func (s *sdboot) ManagedAssets() []string {
+	if s.recovery {
+		return []string{sdbootLoaderConf}
+	}
+	return nil
+}
+
+// UpdateBootConfig is a noop, loader.conf is not edition based.
+//
+// Implements TrustedAssetsBootloader for the systemd-boot bootloader.
+func (s *sdboot) UpdateBootConfig() (bool, error) {
+	return false, nil
+}
+
+func (s *sdboot) commandLine(pieces CommandLineComponents) (string, error) {
+	if err := pieces.Validate(); err != nil {
+		return "", err
+	}
+
+	var nonSnapdCmdline string
+	if pieces.FullArgs == "" {
+		keepDefaultArgs := kcmdline.RemoveMatchingFilter(sdbootStaticCommandLine, pieces.RemoveArgs)
+		nonSnapdCmdline = strutil.JoinNonEmpty(append(keepDefaultArgs, pieces.ExtraArgs), " ")
+	} else {
+		nonSnapdCmdline = pieces.FullArgs
+	}
+	args, err := kcmdline.Split(nonSnapdCmdline)
+	if err != nil {
+		return "", fmt.Errorf("cannot use badly formatted kernel command line: %v", err)
+	}
+	snapdArgs := make([]string, 0, 2)
+	if pieces.ModeArg != "" {
+		snapdArgs = append(snapdArgs, pieces.ModeArg)
+	}
+	if pieces.SystemArg != "" {
+		snapdArgs = append(snapdArgs, pieces.SystemArg)
+	}
+	return strings.Join(append(snapdArgs, args...), " "), nil
+}
+
+// CommandLine returns the kernel command line composed of mode and
+// system arguments, followed by either the static arguments and any
+// extra arguments or a separate set of arguments provided in the
+// components.
+//
+// Implements TrustedAssetsBootloader for the systemd-boot bootloader.
+func (s *sdboot) CommandLine(pieces CommandLineComponents) (string, error) {
+	return s.commandLine(pieces)
+}
+
+// CandidateCommandLine is the same as CommandLine as the static
+// arguments do not depend on the boot config.
+//
+// Implements TrustedAssetsBootloader for the systemd-boot bootloader.
+func (s *sdboot) CandidateCommandLine(pieces CommandLineComponents) (string, error) {
+	return s.commandLine(pieces)
+}
+
+// DefaultCommandLine returns the default kernel command-line used by
+// the bootloader excluding the recovery mode and system parameters.
+func (s *sdboot) DefaultCommandLine(candidate bool) (string, error) {
+	return sdbootStaticCommandLine, nil
+}
+
+// sdbootBootAssetPath contains the paths for assets in the boot chain.
+type sdbootBootAssetPath struct {
+	shimBinary   string
+	sdbootBinary string
+}
+
+// sdbootBootAssetsForArch contains the paths for assets for different
+// architectures in a map
+var sdbootBootAssetsForArch = map[string]sdbootBootAssetPath{
+	"amd64": {
+		shimBinary:   filepath.Join("EFI/boot/", "bootx64.efi"),
+		sdbootBinary: filepath.Join("EFI/systemd/", "systemd-bootx64.efi")},
+	"arm64": {
+		shimBinary:   filepath.Join("EFI/boot/", "bootaa64.efi"),
+		sdbootBinary: filepath.Join("EFI/systemd/", "systemd-bootaa64.efi")},
+}
+
+// getRecoveryModeTrustedAssets returns the assets loaded from the seed
+// partition, which are shim and systemd-boot.
+func (s *sdboot) getRecoveryModeTrustedAssets() ([]string, error) {
+	if s.prepareImageTime {
+		return nil, fmt.Errorf("internal error: retrieving boot assets at prepare image time")
+	}
+	archi := arch.DpkgArchitecture()
+	assets, ok := sdbootBootAssetsForArch[archi]
+	if !ok {
+		return nil, fmt.Errorf("cannot find systemd-boot assets for %q", archi)
+	}
+	return []string{assets.shimBinary, assets.sdbootBinary}, nil
+}
+
+// TrustedAssets returns the list of relative paths to assets inside
+// the bootloader's rootdir that are measured in the boot process in the
+// order of loading during the boot. The run mode entries are loaded by
+// systemd-boot from the seed partition, so only the recovery
+// bootloader has trusted assets.
+func (s *sdboot) TrustedAssets() ([]string, error) {
+	if !s.recovery {
+		return nil, nil
+	}
+	return s.getRecoveryModeTrustedAssets()
+}
+
+// RecoveryBootChain returns the load chain for recovery modes.
+// It should be called on a RoleRecovery bootloader.
+func (s *sdboot) RecoveryBootChain(kernelPath string) ([]BootFile, error) {
+	if !s.recovery {
+		return nil, fmt.Errorf("not a recovery bootloader")
+	}
+
+	assets, err := s.getRecoveryModeTrustedAssets()
+	if err != nil {
+		return nil, err
+	}
+	chain := make([]BootFile, 0, len(assets)+1)
+	for _, ta := range assets {
+		chain = append(chain, NewBootFile("", ta, RoleRecovery))
+	}
+	chain = append(chain, NewBootFile(kernelPath, "kernel.efi", RoleRecovery))
+
+	return chain, nil
+}
+
+// BootChain returns the load chain for run mode.
+// It should be called on a RoleRecovery bootloader passing the
+// RoleRunMode bootloader.
+func (s *sdboot) BootChain(runBl Bootloader, kernelPath string) ([]BootFile, error) {
+	if !s.recovery {
+		return nil, fmt.Errorf("not a recovery bootloader")
+	}
+	if runBl.Name() != "systemd-boot" {
+		return nil, fmt.Errorf("run mode bootloader must be systemd-boot")
+	}
+
+	assets, err := s.getRecoveryModeTrustedAssets()
+	if err != nil {
+		return nil, err
+	}
+	chain := make([]BootFile, 0, len(assets)+1)
+	for _, ta := range assets {
+		chain = append(chain, NewBootFile("", ta, RoleRecovery))
+	}
+	// systemd-boot from the seed partition loads the run mode kernel
+	// directly
+	chain = append(chain, NewBootFile(kernelPath, "kernel.efi", RoleRunMode))
+
+	return chain, nil
+}
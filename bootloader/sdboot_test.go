@@ -0,0 +1,320 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package bootloader_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/arch/archtest"
+	"github.com/snapcore/snapd/bootloader"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snapfile"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type sdbootTestSuite struct {
+	baseBootenvTestSuite
+}
+
+var _ = Suite(&sdbootTestSuite{})
+
+func (s *sdbootTestSuite) SetUpTest(c *C) {
+	s.baseBootenvTestSuite.SetUpTest(c)
+	s.AddCleanup(archtest.MockArchitecture("amd64"))
+}
+
+var (
+	sdbootRunOpts      = &bootloader.Options{Role: bootloader.RoleRunMode, NoSlashBoot: true}
+	sdbootRecoveryOpts = &bootloader.Options{Role: bootloader.RoleRecovery}
+)
+
+func (s *sdbootTestSuite) TestNewSdboot(c *C) {
+	b := bootloader.NewSdboot(s.rootdir, sdbootRecoveryOpts)
+	c.Assert(b, NotNil)
+	c.Check(b.Name(), Equals, "systemd-boot")
+
+	present, err := b.Present()
+	c.Assert(err, IsNil)
+	c.Check(present, Equals, false)
+
+	err = b.InstallBootConfig(c.MkDir(), sdbootRecoveryOpts)
+	c.Assert(err, IsNil)
+	present, err = b.Present()
+	c.Assert(err, IsNil)
+	c.Check(present, Equals, true)
+
+	c.Check(filepath.Join(s.rootdir, "loader/loader.conf"), testutil.FileEquals, `# This file is managed by snapd, do not edit
+default snapd-*
+timeout 0
+editor no
+`)
+	c.Check(filepath.Join(s.rootdir, "loader/entries"), testutil.FilePresent)
+
+	found, err := bootloader.Find(s.rootdir, sdbootRecoveryOpts)
+	c.Assert(err, IsNil)
+	c.Check(found.Name(), Equals, "systemd-boot")
+}
+
+func (s *sdbootTestSuite) TestInstallBootConfigRunMode(c *C) {
+	b := bootloader.NewSdboot(s.rootdir, sdbootRunOpts)
+	err := b.InstallBootConfig(c.MkDir(), sdbootRunOpts)
+	c.Assert(err, IsNil)
+
+	// only the ESP carries loader.conf
+	c.Check(filepath.Join(s.rootdir, "loader/loader.conf"), testutil.FileAbsent)
+	c.Check(filepath.Join(s.rootdir, "EFI/ubuntu/sdbootenv"), testutil.FilePresent)
+}
+
+func (s *sdbootTestSuite) TestInstallBootConfigRoleSole(c *C) {
+	b := bootloader.NewSdboot(s.rootdir, nil)
+	err := b.InstallBootConfig(c.MkDir(), nil)
+	c.Assert(err, ErrorMatches, "cannot use systemd-boot without a run mode and a recovery bootloader")
+}
+
+func (s *sdbootTestSuite) TestForGadget(c *C) {
+	gadgetDir := c.MkDir()
+	err := os.WriteFile(filepath.Join(gadgetDir, "systemd-boot.conf"), nil, 0644)
+	c.Assert(err, IsNil)
+
+	b, err := bootloader.ForGadget(gadgetDir, s.rootdir, sdbootRecoveryOpts)
+	c.Assert(err, IsNil)
+	c.Check(b.Name(), Equals, "systemd-boot")
+}
+
+func (s *sdbootTestSuite) TestRunModeEntries(c *C) {
+	b := bootloader.NewSdboot(s.rootdir, sdbootRunOpts)
+	err := b.InstallBootConfig(c.MkDir(), sdbootRunOpts)
+	c.Assert(err, IsNil)
+
+	runEntry := filepath.Join(s.rootdir, "loader/entries/snapd-run.conf")
+	tryEntry := filepath.Join(s.rootdir, "loader/entries/snapd-try+1.conf")
+
+	err = b.SetBootVars(map[string]string{
+		"snap_kernel":              "pc-kernel_1.snap",
+		"snapd_extra_cmdline_args": "foo=bar",
+	})
+	c.Assert(err, IsNil)
+	c.Check(runEntry, testutil.FileEquals, `# This file is managed by snapd, do not edit
+title Ubuntu Core
+sort-key snapd-2-run
+efi /EFI/ubuntu/pc-kernel_1.snap/kernel.efi
+options snapd_recovery_mode=run console=ttyS0 console=tty1 panic=-1 foo=bar
+`)
+	c.Check(tryEntry, testutil.FileAbsent)
+
+	// trying a new kernel
+	err = b.SetBootVars(map[string]string{
+		"snap_try_kernel": "pc-kernel_2.snap",
+		"kernel_status":   "try",
+	})
+	c.Assert(err, IsNil)
+	c.Check(tryEntry, testutil.FileEquals, `# This file is managed by snapd, do not edit
+title Ubuntu Core (trying new kernel)
+sort-key snapd-1-try
+efi /EFI/ubuntu/pc-kernel_2.snap/kernel.efi
+options snapd_recovery_mode=run kernel_status=trying console=ttyS0 console=tty1 panic=-1 foo=bar
+`)
+
+	// the initramfs records the try boot, entries are left alone
+	nsb, ok := b.(bootloader.NotScriptableBootloader)
+	c.Assert(ok, Equals, true)
+	// pretend systemd-boot accounted for the boot attempt
+	err = os.Rename(tryEntry, filepath.Join(s.rootdir, "loader/entries/snapd-try+0-1.conf"))
+	c.Assert(err, IsNil)
+	err = nsb.SetBootVarsFromInitramfs(map[string]string{"kernel_status": "trying"})
+	c.Assert(err, IsNil)
+	m, err := b.GetBootVars("kernel_status")
+	c.Assert(err, IsNil)
+	c.Check(m, DeepEquals, map[string]string{"kernel_status": "trying"})
+	c.Check(filepath.Join(s.rootdir, "loader/entries/snapd-try+0-1.conf"), testutil.FilePresent)
+
+	// the new kernel is good
+	err = b.SetBootVars(map[string]string{
+		"snap_kernel":     "pc-kernel_2.snap",
+		"snap_try_kernel": "",
+		"kernel_status":   "",
+	})
+	c.Assert(err, IsNil)
+	c.Check(runEntry, testutil.FileContains, "efi /EFI/ubuntu/pc-kernel_2.snap/kernel.efi\n")
+	matches, err := filepath.Glob(filepath.Join(s.rootdir, "loader/entries/snapd-try*"))
+	c.Assert(err, IsNil)
+	c.Check(matches, HasLen, 0)
+}
+
+func (s *sdbootTestSuite) TestRunModeEntriesFullCmdline(c *C) {
+	b := bootloader.NewSdboot(s.rootdir, sdbootRunOpts)
+
+	err := b.SetBootVars(map[string]string{
+		"snap_kernel":              "pc-kernel_1.snap",
+		"snapd_extra_cmdline_args": "foo=bar",
+		"snapd_full_cmdline_args":  "console=ttyS1 quiet",
+	})
+	c.Assert(err, IsNil)
+	c.Check(filepath.Join(s.rootdir, "loader/entries/snapd-run.conf"), testutil.FileContains,
+		"options snapd_recovery_mode=run console=ttyS1 quiet\n")
+
+	// set but empty
+	err = b.SetBootVars(map[string]string{
+		"snapd_full_cmdline_args": " ",
+	})
+	c.Assert(err, IsNil)
+	c.Check(filepath.Join(s.rootdir, "loader/entries/snapd-run.conf"), testutil.FileContains,
+		"options snapd_recovery_mode=run\n")
+}
+
+func (s *sdbootTestSuite) TestRecoveryEntry(c *C) {
+	b := bootloader.NewSdboot(s.rootdir, sdbootRecoveryOpts)
+	err := b.InstallBootConfig(c.MkDir(), sdbootRecoveryOpts)
+	c.Assert(err, IsNil)
+
+	err = b.SetRecoverySystemEnv("/systems/20260101", map[string]string{
+		"snapd_extra_cmdline_args": "foo=bar",
+	})
+	c.Assert(err, IsNil)
+	v, err := b.GetRecoverySystemEnv("/systems/20260101", "snapd_extra_cmdline_args")
+	c.Assert(err, IsNil)
+	c.Check(v, Equals, "foo=bar")
+
+	recoveryEntry := filepath.Join(s.rootdir, "loader/entries/snapd-recovery.conf")
+	err = b.SetBootVars(map[string]string{
+		"snapd_recovery_mode":   "install",
+		"snapd_recovery_system": "20260101",
+	})
+	c.Assert(err, IsNil)
+	c.Check(recoveryEntry, testutil.FileEquals, `# This file is managed by snapd, do not edit
+title Ubuntu Core 20260101 (install)
+sort-key snapd-0-recovery
+efi /systems/20260101/kernel/kernel.efi
+options snapd_recovery_mode=install snapd_recovery_system=20260101 console=ttyS0 console=tty1 panic=-1 foo=bar
+`)
+
+	// booting into run mode goes through the ubuntu-boot entries
+	err = b.SetBootVars(map[string]string{
+		"snapd_recovery_mode": "run",
+	})
+	c.Assert(err, IsNil)
+	c.Check(recoveryEntry, testutil.FileAbsent)
+}
+
+func (s *sdbootTestSuite) TestExtractKernelAssets(c *C) {
+	files := [][]string{
+		{"kernel.efi", "I'm a kernel.efi"},
+		{"kernel.img", "I'm a kernel"},
+		{"meta/kernel.yaml", "version: 4.2"},
+	}
+	fn := snaptest.MakeTestSnapWithFiles(c, packageKernel, files)
+	snapf, err := snapfile.Open(fn)
+	c.Assert(err, IsNil)
+	sn := snap.MinimalPlaceInfo("pc-kernel", snap.R(42))
+
+	b := bootloader.NewSdboot(s.rootdir, sdbootRunOpts)
+	err = b.ExtractKernelAssets(sn, snapf)
+	c.Assert(err, IsNil)
+	kernelDir := filepath.Join(s.rootdir, "EFI/ubuntu/pc-kernel_42.snap")
+	c.Check(filepath.Join(kernelDir, "kernel.efi"), testutil.FileEquals, "I'm a kernel.efi")
+	c.Check(osutil.FileExists(filepath.Join(kernelDir, "kernel.img")), Equals, false)
+
+	err = b.RemoveKernelAssets(sn)
+	c.Assert(err, IsNil)
+	c.Check(kernelDir, testutil.FileAbsent)
+
+	rb := bootloader.NewSdboot(s.rootdir, sdbootRecoveryOpts)
+	erkbl, ok := rb.(bootloader.ExtractedRecoveryKernelImageBootloader)
+	c.Assert(ok, Equals, true)
+	err = erkbl.ExtractRecoveryKernelAssets("/systems/20260101", sn, snapf)
+	c.Assert(err, IsNil)
+	c.Check(filepath.Join(s.rootdir, "systems/20260101/kernel/kernel.efi"), testutil.FileEquals, "I'm a kernel.efi")
+}
+
+func (s *sdbootTestSuite) TestCommandLine(c *C) {
+	b := bootloader.NewSdboot(s.rootdir, sdbootRecoveryOpts)
+	tab, ok := b.(bootloader.TrustedAssetsBootloader)
+	c.Assert(ok, Equals, true)
+
+	cmdline, err := tab.CommandLine(bootloader.CommandLineComponents{
+		ModeArg:   "snapd_recovery_mode=recover",
+		SystemArg: "snapd_recovery_system=20260101",
+		ExtraArgs: "foo=bar",
+	})
+	c.Assert(err, IsNil)
+	c.Check(cmdline, Equals, "snapd_recovery_mode=recover snapd_recovery_system=20260101 console=ttyS0 console=tty1 panic=-1 foo=bar")
+
+	cmdline, err = tab.CandidateCommandLine(bootloader.CommandLineComponents{
+		ModeArg:  "snapd_recovery_mode=run",
+		FullArgs: "quiet",
+	})
+	c.Assert(err, IsNil)
+	c.Check(cmdline, Equals, "snapd_recovery_mode=run quiet")
+
+	_, err = tab.CommandLine(bootloader.CommandLineComponents{
+		ExtraArgs: "foo",
+		FullArgs:  "bar",
+	})
+	c.Assert(err, ErrorMatches, "cannot use both full and extra components of command line")
+
+	dflt, err := tab.DefaultCommandLine(true)
+	c.Assert(err, IsNil)
+	c.Check(dflt, Equals, "console=ttyS0 console=tty1 panic=-1")
+}
+
+func (s *sdbootTestSuite) TestTrustedAssetsAndBootChains(c *C) {
+	rb := bootloader.NewSdboot(s.rootdir, sdbootRecoveryOpts).(bootloader.TrustedAssetsBootloader)
+	runBl := bootloader.NewSdboot(s.rootdir, sdbootRunOpts).(bootloader.TrustedAssetsBootloader)
+
+	ta, err := rb.TrustedAssets()
+	c.Assert(err, IsNil)
+	c.Check(ta, DeepEquals, []string{"EFI/boot/bootx64.efi", "EFI/systemd/systemd-bootx64.efi"})
+	ta, err = runBl.TrustedAssets()
+	c.Assert(err, IsNil)
+	c.Check(ta, HasLen, 0)
+
+	c.Check(rb.ManagedAssets(), DeepEquals, []string{"loader/loader.conf"})
+	c.Check(runBl.ManagedAssets(), HasLen, 0)
+
+	chain, err := rb.RecoveryBootChain("kernel.snap")
+	c.Assert(err, IsNil)
+	c.Check(chain, DeepEquals, []bootloader.BootFile{
+		{Path: "EFI/boot/bootx64.efi", Role: bootloader.RoleRecovery},
+		{Path: "EFI/systemd/systemd-bootx64.efi", Role: bootloader.RoleRecovery},
+		{Snap: "kernel.snap", Path: "kernel.efi", Role: bootloader.RoleRecovery},
+	})
+
+	chain, err = rb.BootChain(runBl, "kernel.snap")
+	c.Assert(err, IsNil)
+	c.Check(chain, DeepEquals, []bootloader.BootFile{
+		{Path: "EFI/boot/bootx64.efi", Role: bootloader.RoleRecovery},
+		{Path: "EFI/systemd/systemd-bootx64.efi", Role: bootloader.RoleRecovery},
+		{Snap: "kernel.snap", Path: "kernel.efi", Role: bootloader.RoleRunMode},
+	})
+
+	_, err = runBl.RecoveryBootChain("kernel.snap")
+	c.Assert(err, ErrorMatches, "not a recovery bootloader")
+	_, err = rb.BootChain(bootloader.NewGrub(s.rootdir, sdbootRunOpts), "kernel.snap")
+	c.Assert(err, ErrorMatches, "run mode bootloader must be systemd-boot")
+
+	s.AddCleanup(archtest.MockArchitecture("riscv64"))
+	_, err = rb.TrustedAssets()
+	c.Assert(err, ErrorMatches, `cannot find systemd-boot assets for "riscv64"`)
+}
@@ -65,6 +65,9 @@ func (client *Client) AssertionTypes() ([]string, error) {
 type KnownOptions struct {
 	// If Remote is true, the store is queried to find the assertion
 	Remote bool
+	// If Prerequisites is true, together with Remote, the prerequisites
+	// of the assertion are retrieved as well
+	Prerequisites bool
 }
 
 // Known queries assertions with type assertTypeName and matching assertion headers.
@@ -84,6 +87,9 @@ func (client *Client) Known(assertTypeName string, headers map[string]string, op
 	if opts.Remote {
 		q.Set("remote", "true")
 	}
+	if opts.Prerequisites {
+		q.Set("prerequisites", "true")
+	}
 
 	response, cancel, err := client.rawWithTimeout(context.Background(), "GET", path, q, nil, nil, nil)
 	if err != nil {
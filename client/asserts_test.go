@@ -75,6 +75,14 @@ func (cs *clientSuite) TestClientAssertsOptsCallsEndpoint(c *C) {
 	c.Check(cs.req.URL.Query()["remote"], DeepEquals, []string{"true"})
 }
 
+func (cs *clientSuite) TestClientAssertsPrerequisitesCallsEndpoint(c *C) {
+	_, _ = cs.cli.Known("snap-revision", nil, &client.KnownOptions{Remote: true, Prerequisites: true})
+	c.Check(cs.req.Method, Equals, "GET")
+	c.Check(cs.req.URL.Path, Equals, "/v2/assertions/snap-revision")
+	c.Check(cs.req.URL.Query()["remote"], DeepEquals, []string{"true"})
+	c.Check(cs.req.URL.Query()["prerequisites"], DeepEquals, []string{"true"})
+}
+
 func (cs *clientSuite) TestClientAssertsCallsEndpointWithFilter(c *C) {
 	_, _ = cs.cli.Known("snap-revision", map[string]string{
 		"snap-id":       "snap-id-1",
@@ -0,0 +1,98 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// CacheEntry describes an item of the snap download cache.
+type CacheEntry struct {
+	Digest   string    `json:"digest"`
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"last-used"`
+	// Referenced is set if the content is also linked from elsewhere,
+	// removing it from the cache then reclaims no space.
+	Referenced bool `json:"referenced,omitempty"`
+	// Snaps lists the installed snap revisions whose blob is the cached
+	// content, as <instance-name>_<revision>.
+	Snaps []string `json:"snaps,omitempty"`
+}
+
+// CacheAction is the instruction sent to the cache endpoint.
+type CacheAction struct {
+	Action    string `json:"action"`
+	OlderThan string `json:"older-than,omitempty"`
+	MaxSize   int64  `json:"max-size,omitempty"`
+}
+
+// CacheCleanOptions selects the entries CleanCache removes. Entries that
+// are still referenced are never removed. Without any option all the
+// other entries are removed.
+type CacheCleanOptions struct {
+	// OlderThan removes the entries not used for that long.
+	OlderThan time.Duration
+	// MaxSize removes the least recently used entries until the
+	// unreferenced ones take at most that many bytes.
+	MaxSize int64
+}
+
+// CacheCleanResult holds the outcome of CleanCache.
+type CacheCleanResult struct {
+	Removed   []CacheEntry `json:"removed"`
+	Reclaimed int64        `json:"reclaimed"`
+}
+
+// CacheEntries lists the content of the snap download cache, from the
+// least to the most recently used entry.
+func (client *Client) CacheEntries() ([]CacheEntry, error) {
+	var entries []CacheEntry
+	if _, err := client.doSync("GET", "/v2/cache", nil, nil, nil, &entries); err != nil {
+		return nil, xerrors.Errorf("cannot list cache entries: %w", err)
+	}
+	return entries, nil
+}
+
+// CleanCache removes the unreferenced entries of the snap download cache
+// selected by the options.
+func (client *Client) CleanCache(opts *CacheCleanOptions) (*CacheCleanResult, error) {
+	if opts == nil {
+		opts = &CacheCleanOptions{}
+	}
+	action := CacheAction{Action: "clean", MaxSize: opts.MaxSize}
+	if opts.OlderThan != 0 {
+		action.OlderThan = opts.OlderThan.String()
+	}
+	data, err := json.Marshal(&action)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal cache action: %v", err)
+	}
+
+	var res CacheCleanResult
+	if _, err := client.doSync("POST", "/v2/cache", nil, nil, bytes.NewReader(data), &res); err != nil {
+		return nil, xerrors.Errorf("cannot clean cache: %w", err)
+	}
+	return &res, nil
+}
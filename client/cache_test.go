@@ -0,0 +1,104 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+)
+
+func (cs *clientSuite) TestClientCacheEntries(c *check.C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": [
+			{"digest": "abc", "size": 42, "last-used": "2026-01-02T03:04:05Z", "referenced": true, "snaps": ["foo_1"]},
+			{"digest": "def", "size": 7, "last-used": "2026-01-03T03:04:05Z"}
+		]
+	}`
+	entries, err := cs.cli.CacheEntries()
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/cache")
+	c.Check(entries, check.DeepEquals, []client.CacheEntry{{
+		Digest:     "abc",
+		Size:       42,
+		LastUsed:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Referenced: true,
+		Snaps:      []string{"foo_1"},
+	}, {
+		Digest:   "def",
+		Size:     7,
+		LastUsed: time.Date(2026, 1, 3, 3, 4, 5, 0, time.UTC),
+	}})
+}
+
+func (cs *clientSuite) TestClientCleanCache(c *check.C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": {"removed": [{"digest": "def", "size": 7, "last-used": "2026-01-03T03:04:05Z"}], "reclaimed": 7}
+	}`
+	res, err := cs.cli.CleanCache(&client.CacheCleanOptions{
+		OlderThan: 48 * time.Hour,
+		MaxSize:   1000,
+	})
+	c.Assert(err, check.IsNil)
+	c.Check(res, check.DeepEquals, &client.CacheCleanResult{
+		Removed: []client.CacheEntry{{
+			Digest:   "def",
+			Size:     7,
+			LastUsed: time.Date(2026, 1, 3, 3, 4, 5, 0, time.UTC),
+		}},
+		Reclaimed: 7,
+	})
+
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/cache")
+	body, err := ioutil.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var jsonBody map[string]interface{}
+	c.Assert(json.Unmarshal(body, &jsonBody), check.IsNil)
+	c.Check(jsonBody, check.DeepEquals, map[string]interface{}{
+		"action":     "clean",
+		"older-than": "48h0m0s",
+		"max-size":   1000.0,
+	})
+}
+
+func (cs *clientSuite) TestClientCleanCacheNoOptions(c *check.C) {
+	cs.rsp = `{"type": "sync", "status-code": 200, "result": {"removed": [], "reclaimed": 0}}`
+	_, err := cs.cli.CleanCache(nil)
+	c.Assert(err, check.IsNil)
+	body, err := ioutil.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	c.Check(string(body), check.Equals, `{"action":"clean"}`)
+}
+
+func (cs *clientSuite) TestClientCleanCacheError(c *check.C) {
+	cs.rsp = `{"type": "error", "status-code": 403, "result": {"message": "access denied"}}`
+	_, err := cs.cli.CleanCache(nil)
+	c.Check(err, check.ErrorMatches, "cannot clean cache: access denied")
+}
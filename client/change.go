@@ -71,6 +71,14 @@ type TaskProgress struct {
 	Label string `json:"label"`
 	Done  int    `json:"done"`
 	Total int    `json:"total"`
+
+	// Phase names what the task is currently doing, e.g. "download",
+	// "unpack" or "copy-data", if known.
+	Phase string `json:"phase,omitempty"`
+	// Unit is the unit of Done and Total, "bytes" or "steps", if known.
+	Unit string `json:"unit,omitempty"`
+	// ETA is the estimated time of completion, if known.
+	ETA time.Time `json:"eta,omitempty"`
 }
 
 type changeAndData struct {
@@ -61,6 +61,29 @@ func (cs *clientSuite) TestClientChange(c *check.C) {
 	})
 }
 
+func (cs *clientSuite) TestClientChangeProgressDetails(c *check.C) {
+	cs.rsp = `{"type": "sync", "result": {
+  "id":   "uno",
+  "kind": "foo",
+  "summary": "...",
+  "status": "Doing",
+  "ready": false,
+  "tasks": [{"kind": "bar", "summary": "...", "status": "Doing", "progress": {"label": "Download snap", "done": 1024, "total": 4096, "phase": "download", "unit": "bytes", "eta": "2016-04-21T01:02:13Z"}}]
+}}`
+
+	chg, err := cs.cli.Change("uno")
+	c.Assert(err, check.IsNil)
+	c.Assert(chg.Tasks, check.HasLen, 1)
+	c.Check(chg.Tasks[0].Progress, check.DeepEquals, client.TaskProgress{
+		Label: "Download snap",
+		Done:  1024,
+		Total: 4096,
+		Phase: "download",
+		Unit:  "bytes",
+		ETA:   time.Date(2016, 04, 21, 1, 2, 13, 0, time.UTC),
+	})
+}
+
 func (cs *clientSuite) TestClientChangeData(c *check.C) {
 	cs.rsp = `{"type": "sync", "result": {
   "id":   "uno",
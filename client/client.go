@@ -77,6 +77,10 @@ type Config struct {
 
 	// User-Agent to sent to the snapd daemon
 	UserAgent string
+
+	// Token is an API token to send as Authorization header instead
+	// of the auth.json data.
+	Token string
 }
 
 // A Client knows how to talk to the snappy daemon.
@@ -94,6 +98,8 @@ type Client struct {
 
 	userAgent string
 
+	token string
+
 	// SetMayLogBody controls whether a request or response's body may be logged
 	// if the appropriate environment variable is set
 	SetMayLogBody func(bool)
@@ -137,6 +143,7 @@ func New(config *Config) *Client {
 		disableAuth: config.DisableAuth,
 		interactive: config.Interactive,
 		userAgent:   config.UserAgent,
+		token:       config.Token,
 		SetMayLogBody: func(logBody bool) {
 			transport.MayLogBody = logBody
 		},
@@ -168,6 +175,11 @@ func (client *Client) WhoAmI() (string, error) {
 }
 
 func (client *Client) setAuthorization(req *http.Request) error {
+	if client.token != "" {
+		req.Header.Set("Authorization", "Bearer "+client.token)
+		return nil
+	}
+
 	user, err := readAuthData()
 	if os.IsNotExist(err) {
 		return nil
@@ -688,6 +700,76 @@ type SysInfo struct {
 	Refresh         RefreshInfo         `json:"refresh,omitempty"`
 	Confinement     string              `json:"confinement"`
 	SandboxFeatures map[string][]string `json:"sandbox-features,omitempty"`
+
+	PrintServer string `json:"print-server,omitempty"`
+
+	Readiness *Readiness `json:"readiness,omitempty"`
+
+	// Container is the container manager snapd runs under, if any.
+	Container string `json:"container,omitempty"`
+	// DisabledFeatures lists the behaviors of snapd disabled because the
+	// container lacks the capabilities they require.
+	DisabledFeatures []DisabledFeature `json:"disabled-features,omitempty"`
+
+	// WSL describes the Windows Subsystem for Linux instance snapd runs
+	// in, if any.
+	WSL *WSLInfo `json:"wsl,omitempty"`
+
+	// Features lists the features of snapd that can be toggled with
+	// the experimental.<name> system options.
+	Features []Feature `json:"features,omitempty"`
+}
+
+// Feature describes a feature of snapd that can be toggled.
+type Feature struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Maturity is one of "experimental", "beta" or "stable".
+	Maturity string `json:"maturity"`
+	Enabled  bool   `json:"enabled"`
+	// RequiresRestart is set if the snap applications need to be
+	// restarted for a change of the feature to take effect.
+	RequiresRestart bool `json:"requires-restart,omitempty"`
+	// RequiresReboot is set if the system needs to be rebooted for a
+	// change of the feature to take effect.
+	RequiresReboot bool `json:"requires-reboot,omitempty"`
+	// Prerequisites are the features that must be enabled for the
+	// feature to be enabled.
+	Prerequisites []string `json:"prerequisites,omitempty"`
+}
+
+// WSLInfo describes the features of a Windows Subsystem for Linux instance.
+type WSLInfo struct {
+	Version int `json:"version"`
+	// Interop is set if Windows executables can be invoked.
+	Interop bool `json:"interop"`
+	// UserSessions is set if systemd manages user sessions, which the
+	// user daemons of snaps need.
+	UserSessions bool `json:"user-sessions"`
+}
+
+// DisabledFeature describes a behavior of snapd that is disabled on the
+// system.
+type DisabledFeature struct {
+	Feature string `json:"feature"`
+	Reason  string `json:"reason"`
+}
+
+// Readiness describes how far the initialization of snapd progressed.
+type Readiness struct {
+	// Ready is set once all managers are initialized.
+	Ready    bool               `json:"ready"`
+	Managers []ManagerReadiness `json:"managers"`
+}
+
+// ManagerReadiness describes the initialization of a manager of snapd.
+type ManagerReadiness struct {
+	Name string `json:"name"`
+	// Lazy is set for managers initialized only once snapd serves its
+	// API.
+	Lazy   bool   `json:"lazy,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
 }
 
 func (rsp *response) err(cli *Client, statusCode int) error {
@@ -799,3 +881,47 @@ func (c *Client) MigrateSnapHome(snaps []string) (changeID string, err error) {
 
 	return c.doAsync("POST", "/v2/debug", nil, nil, bytes.NewReader(body))
 }
+
+// SyscallProfiling starts or stops profiling the system calls used by the
+// given snap.
+func (c *Client) SyscallProfiling(snapName string, enable bool) (changeID string, err error) {
+	action := "stop-syscall-profiling"
+	if enable {
+		action = "start-syscall-profiling"
+	}
+	body, err := json.Marshal(struct {
+		Action string   `json:"action"`
+		Snaps  []string `json:"snaps"`
+	}{
+		Action: action,
+		Snaps:  []string{snapName},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return c.doAsync("POST", "/v2/debug", nil, nil, bytes.NewReader(body))
+}
+
+// RelocateSnapData moves the system data of the given snap to the given
+// directory, usually on another volume, or back to /var/snap if location is
+// empty.
+func (c *Client) RelocateSnapData(snapName, location string) (changeID string, err error) {
+	type params struct {
+		DataLocation string `json:"data-location"`
+	}
+	body, err := json.Marshal(struct {
+		Action string   `json:"action"`
+		Snaps  []string `json:"snaps"`
+		Params params   `json:"params"`
+	}{
+		Action: "relocate-data",
+		Snaps:  []string{snapName},
+		Params: params{DataLocation: location},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return c.doAsync("POST", "/v2/debug", nil, nil, bytes.NewReader(body))
+}
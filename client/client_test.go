@@ -291,6 +291,25 @@ func (cs *clientSuite) TestClientSetsAuthorization(c *C) {
 	c.Check(authorization, Equals, `Macaroon root="macaroon", discharge="discharge"`)
 }
 
+func (cs *clientSuite) TestClientSetsAuthorizationFromToken(c *C) {
+	os.Setenv(client.TestAuthFileEnvKey, filepath.Join(c.MkDir(), "json"))
+	defer os.Unsetenv(client.TestAuthFileEnvKey)
+
+	mockUserData := client.User{
+		Macaroon:   "macaroon",
+		Discharges: []string{"discharge"},
+	}
+	err := client.TestWriteAuth(mockUserData)
+	c.Assert(err, IsNil)
+
+	var v string
+	cli := client.New(&client.Config{Token: "secret"})
+	cli.SetDoer(cs)
+	_, _ = cli.Do("GET", "/this", nil, nil, &v, nil)
+	authorization := cs.req.Header.Get("Authorization")
+	c.Check(authorization, Equals, "Bearer secret")
+}
+
 func (cs *clientSuite) TestClientHonorsDisableAuth(c *C) {
 	os.Setenv(client.TestAuthFileEnvKey, filepath.Join(c.MkDir(), "json"))
 	defer os.Unsetenv(client.TestAuthFileEnvKey)
@@ -360,7 +379,8 @@ func (cs *clientSuite) TestClientSysInfo(c *C) {
                       "confinement": "strict",
                       "architecture": "TI-99/4A",
                       "virtualization": "MESS",
-                      "sandbox-features": {"backend": ["feature-1", "feature-2"]}}}`
+                      "sandbox-features": {"backend": ["feature-1", "feature-2"]},
+                      "print-server": "cups"}}`
 	sysInfo, err := cs.cli.SysInfo()
 	c.Check(err, IsNil)
 	c.Check(sysInfo, DeepEquals, &client.SysInfo{
@@ -378,6 +398,71 @@ func (cs *clientSuite) TestClientSysInfo(c *C) {
 		BuildID:        "1234",
 		Architecture:   "TI-99/4A",
 		Virtualization: "MESS",
+		PrintServer:    "cups",
+	})
+}
+
+func (cs *clientSuite) TestClientSysInfoContainer(c *C) {
+	cs.rsp = `{"type": "sync", "result":
+                     {"series": "16",
+                      "version": "2",
+                      "confinement": "partial",
+                      "container": "docker",
+                      "disabled-features": [
+                        {"feature": "strict-confinement", "reason": "AppArmor is not fully available in the container: apparmor not enabled"},
+                        {"feature": "quota-groups", "reason": "the cgroup hierarchy is not delegated to the container"}
+                      ]}}`
+	sysInfo, err := cs.cli.SysInfo()
+	c.Check(err, IsNil)
+	c.Check(sysInfo, DeepEquals, &client.SysInfo{
+		Version:     "2",
+		Series:      "16",
+		Confinement: "partial",
+		Container:   "docker",
+		DisabledFeatures: []client.DisabledFeature{
+			{Feature: "strict-confinement", Reason: "AppArmor is not fully available in the container: apparmor not enabled"},
+			{Feature: "quota-groups", Reason: "the cgroup hierarchy is not delegated to the container"},
+		},
+	})
+}
+
+func (cs *clientSuite) TestClientSysInfoWSL(c *C) {
+	cs.rsp = `{"type": "sync", "result":
+                     {"series": "16",
+                      "version": "2",
+                      "confinement": "partial",
+                      "wsl": {"version": 2, "interop": true, "user-sessions": false}}}`
+	sysInfo, err := cs.cli.SysInfo()
+	c.Check(err, IsNil)
+	c.Check(sysInfo, DeepEquals, &client.SysInfo{
+		Version:     "2",
+		Series:      "16",
+		Confinement: "partial",
+		WSL:         &client.WSLInfo{Version: 2, Interop: true},
+	})
+}
+
+func (cs *clientSuite) TestClientSysInfoFeatures(c *C) {
+	cs.rsp = `{"type": "sync", "result":
+                     {"series": "16",
+                      "version": "2",
+                      "confinement": "strict",
+                      "features": [
+                        {"name": "layouts", "description": "Support for layouts in snaps", "maturity": "stable", "enabled": true},
+                        {"name": "mounts-generator", "description": "Generate the mount units of snaps at boot", "maturity": "experimental", "enabled": false, "requires-reboot": true},
+                        {"name": "refresh-app-awareness-ux", "description": "Notify users about refreshes of running snaps", "maturity": "experimental", "enabled": false, "prerequisites": ["refresh-app-awareness"]}
+                      ]}}`
+	sysInfo, err := cs.cli.SysInfo()
+	c.Check(err, IsNil)
+	c.Check(sysInfo, DeepEquals, &client.SysInfo{
+		Version:     "2",
+		Series:      "16",
+		Confinement: "strict",
+		Features: []client.Feature{
+			{Name: "layouts", Description: "Support for layouts in snaps", Maturity: "stable", Enabled: true},
+			{Name: "mounts-generator", Description: "Generate the mount units of snaps at boot", Maturity: "experimental", RequiresReboot: true},
+			{Name: "refresh-app-awareness-ux", Description: "Notify users about refreshes of running snaps", Maturity: "experimental", Prerequisites: []string{"refresh-app-awareness"}},
+		},
 	})
 }
 
@@ -648,6 +733,44 @@ func (cs *clientSuite) TestDebugMigrateHome(c *C) {
 	c.Check(string(data), Equals, `{"action":"migrate-home","snaps":["foo","bar"]}`)
 }
 
+func (cs *clientSuite) TestDebugSyscallProfiling(c *C) {
+	cs.status = 202
+	cs.rsp = `{"type": "async", "status-code": 202, "change": "123"}`
+
+	changeID, err := cs.cli.SyscallProfiling("foo", true)
+	c.Check(err, IsNil)
+	c.Check(changeID, Equals, "123")
+
+	changeID, err = cs.cli.SyscallProfiling("foo", false)
+	c.Check(err, IsNil)
+	c.Check(changeID, Equals, "123")
+
+	c.Assert(cs.reqs, HasLen, 2)
+	for i, action := range []string{"start-syscall-profiling", "stop-syscall-profiling"} {
+		c.Check(cs.reqs[i].Method, Equals, "POST")
+		c.Check(cs.reqs[i].URL.Path, Equals, "/v2/debug")
+		data, err := ioutil.ReadAll(cs.reqs[i].Body)
+		c.Assert(err, IsNil)
+		c.Check(string(data), Equals, `{"action":"`+action+`","snaps":["foo"]}`)
+	}
+}
+
+func (cs *clientSuite) TestDebugRelocateSnapData(c *C) {
+	cs.status = 202
+	cs.rsp = `{"type": "async", "status-code": 202, "change": "123"}`
+
+	changeID, err := cs.cli.RelocateSnapData("foo", "/mnt/data")
+	c.Check(err, IsNil)
+	c.Check(changeID, Equals, "123")
+
+	c.Assert(cs.reqs, HasLen, 1)
+	c.Check(cs.reqs[0].Method, Equals, "POST")
+	c.Check(cs.reqs[0].URL.Path, Equals, "/v2/debug")
+	data, err := ioutil.ReadAll(cs.reqs[0].Body)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, `{"action":"relocate-data","snaps":["foo"],"params":{"data-location":"/mnt/data"}}`)
+}
+
 type integrationSuite struct{}
 
 var _ = Suite(&integrationSuite{})
@@ -57,35 +57,36 @@ func ClientSnapFromSnapInfo(snapInfo *snap.Info, decorator StatusDecorator) (*cl
 
 	apps, err := ClientAppInfosFromSnapAppInfos(snapapps, decorator)
 	result := &client.Snap{
-		Description: snapInfo.Description(),
-		Developer:   snapInfo.Publisher.Username,
-		Publisher:   publisher,
-		Icon:        snapInfo.Media.IconURL(),
-		ID:          snapInfo.ID(),
-		InstallDate: snapInfo.InstallDate(),
-		Name:        snapInfo.InstanceName(),
-		Revision:    snapInfo.Revision,
-		Summary:     snapInfo.Summary(),
-		Type:        string(snapInfo.Type()),
-		Base:        snapInfo.Base,
-		Version:     snapInfo.Version,
-		Channel:     snapInfo.Channel,
-		Private:     snapInfo.Private,
-		Confinement: string(confinement),
-		Apps:        apps,
-		Broken:      snapInfo.Broken,
-		Title:       snapInfo.Title(),
-		License:     snapInfo.License,
-		Media:       snapInfo.Media,
-		Prices:      snapInfo.Prices,
-		Channels:    snapInfo.Channels,
-		Tracks:      snapInfo.Tracks,
-		CommonIDs:   snapInfo.CommonIDs,
-		Links:       snapInfo.Links(),
-		Contact:     snapInfo.Contact(),
-		Website:     snapInfo.Website(),
-		StoreURL:    snapInfo.StoreURL,
-		Categories:  snapInfo.Categories,
+		Description:  snapInfo.Description(),
+		Developer:    snapInfo.Publisher.Username,
+		Publisher:    publisher,
+		Icon:         snapInfo.Media.IconURL(),
+		ID:           snapInfo.ID(),
+		InstallDate:  snapInfo.InstallDate(),
+		Name:         snapInfo.InstanceName(),
+		Revision:     snapInfo.Revision,
+		Summary:      snapInfo.Summary(),
+		Type:         string(snapInfo.Type()),
+		Base:         snapInfo.Base,
+		Version:      snapInfo.Version,
+		Channel:      snapInfo.Channel,
+		Private:      snapInfo.Private,
+		Confinement:  string(confinement),
+		Apps:         apps,
+		Broken:       snapInfo.Broken,
+		Title:        snapInfo.Title(),
+		License:      snapInfo.License,
+		Media:        snapInfo.Media,
+		Prices:       snapInfo.Prices,
+		Channels:     snapInfo.Channels,
+		Tracks:       snapInfo.Tracks,
+		CommonIDs:    snapInfo.CommonIDs,
+		Links:        snapInfo.Links(),
+		Contact:      snapInfo.Contact(),
+		Website:      snapInfo.Website(),
+		StoreURL:     snapInfo.StoreURL,
+		Categories:   snapInfo.Categories,
+		ReleaseNotes: snapInfo.ReleaseNotes,
 	}
 
 	return result, err
@@ -86,9 +86,10 @@ func (*cmdSuite) TestClientSnapFromSnapInfo(c *C) {
 			{Type: "screenshot", URL: "https://dashboard.snapcraft.io/site_media/appmedia/2018/01/Thingy_01.png"},
 			{Type: "screenshot", URL: "https://dashboard.snapcraft.io/site_media/appmedia/2018/01/Thingy_02.png", Width: 600, Height: 200},
 		},
-		CommonIDs: []string{"org.thingy"},
-		StoreURL:  "https://snapcraft.io/thingy",
-		Broken:    "broken",
+		CommonIDs:    []string{"org.thingy"},
+		StoreURL:     "https://snapcraft.io/thingy",
+		Broken:       "broken",
+		ReleaseNotes: "the-release-notes",
 		Categories: []snap.CategoryInfo{
 			{Featured: true, Name: "featured"},
 			{Featured: false, Name: "productivity"},
@@ -120,6 +121,11 @@ func (*cmdSuite) TestClientSnapFromSnapInfo(c *C) {
 		"MountedFrom",
 		"Hold",
 		"GatingHold",
+		"Quarantined",
+		"Pinned",
+		"PublisherChange",
+		"Verity",
+		"FallbackChannel",
 	}
 	var checker func(string, reflect.Value)
 	checker = func(pfx string, x reflect.Value) {
@@ -153,6 +159,7 @@ func (*cmdSuite) TestClientSnapFromSnapInfo(c *C) {
 	c.Check(ci.Title, Equals, "the-title")
 	c.Check(ci.Summary, Equals, "the-summary")
 	c.Check(ci.Description, Equals, "the-description")
+	c.Check(ci.ReleaseNotes, Equals, "the-release-notes")
 	c.Check(ci.Icon, Equals, si.Media.IconURL())
 	c.Check(ci.Links, DeepEquals, si.Links())
 	c.Check(ci.Links, DeepEquals, si.EditedLinks)
@@ -28,8 +28,9 @@ import (
 )
 
 type CohortAction struct {
-	Action string   `json:"action"`
-	Snaps  []string `json:"snaps"`
+	Action    string   `json:"action"`
+	Snaps     []string `json:"snaps"`
+	CohortKey string   `json:"cohort-key,omitempty"`
 }
 
 func (client *Client) CreateCohorts(snaps []string) (map[string]string, error) {
@@ -48,3 +49,28 @@ func (client *Client) CreateCohorts(snaps []string) (map[string]string, error) {
 	return cohorts, nil
 
 }
+
+// JoinCohort makes the snaps members of a cohort. If no cohort key is
+// given, a new cohort is created for each snap. The cohort is used by the
+// following refreshes of the snaps.
+func (client *Client) JoinCohort(snaps []string, cohortKey string) (changeID string, err error) {
+	return client.doCohortAction(&CohortAction{Action: "join", Snaps: snaps, CohortKey: cohortKey})
+}
+
+// LeaveCohort makes the snaps leave the cohorts they are members of.
+func (client *Client) LeaveCohort(snaps []string) (changeID string, err error) {
+	return client.doCohortAction(&CohortAction{Action: "leave", Snaps: snaps})
+}
+
+func (client *Client) doCohortAction(action *CohortAction) (changeID string, err error) {
+	data, err := json.Marshal(action)
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal cohort action: %v", err)
+	}
+
+	changeID, err = client.doAsync("POST", "/v2/cohorts", nil, nil, bytes.NewReader(data))
+	if err != nil {
+		return "", xerrors.Errorf("cannot %s cohort: %w", action.Action, err)
+	}
+	return changeID, nil
+}
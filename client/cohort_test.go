@@ -74,3 +74,56 @@ func (cs *clientSuite) TestClientCreateCohortsErrIsWrapped(c *check.C) {
 	var e xerrors.Wrapper
 	c.Assert(err, check.Implements, &e)
 }
+
+func (cs *clientSuite) TestClientJoinCohort(c *check.C) {
+	cs.status = 202
+	cs.rsp = `{
+		"type": "async",
+		"status-code": 202,
+		"change": "42"
+	}`
+	id, err := cs.cli.JoinCohort([]string{"foo"}, "some-cohort-key")
+	c.Assert(err, check.IsNil)
+	c.Check(id, check.Equals, "42")
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/cohorts")
+
+	body, err := ioutil.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var jsonBody map[string]interface{}
+	err = json.Unmarshal(body, &jsonBody)
+	c.Assert(err, check.IsNil)
+	c.Check(jsonBody, check.DeepEquals, map[string]interface{}{
+		"action":     "join",
+		"snaps":      []interface{}{"foo"},
+		"cohort-key": "some-cohort-key",
+	})
+}
+
+func (cs *clientSuite) TestClientLeaveCohort(c *check.C) {
+	cs.status = 202
+	cs.rsp = `{
+		"type": "async",
+		"status-code": 202,
+		"change": "42"
+	}`
+	id, err := cs.cli.LeaveCohort([]string{"foo", "bar"})
+	c.Assert(err, check.IsNil)
+	c.Check(id, check.Equals, "42")
+
+	body, err := ioutil.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var jsonBody map[string]interface{}
+	err = json.Unmarshal(body, &jsonBody)
+	c.Assert(err, check.IsNil)
+	c.Check(jsonBody, check.DeepEquals, map[string]interface{}{
+		"action": "leave",
+		"snaps":  []interface{}{"foo", "bar"},
+	})
+}
+
+func (cs *clientSuite) TestClientJoinCohortError(c *check.C) {
+	cs.err = errors.New("boom")
+	_, err := cs.cli.JoinCohort([]string{"foo"}, "")
+	c.Check(err, check.ErrorMatches, "cannot join cohort: .*boom")
+}
@@ -0,0 +1,63 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"golang.org/x/xerrors"
+
+	"github.com/snapcore/snapd/snap"
+)
+
+// SuperPrivilegedConnection is a manual connection of a plug of a
+// super-privileged interface, one that the base declaration does not
+// grant to snaps unless their snap-declaration allows it.
+type SuperPrivilegedConnection struct {
+	Interface string  `json:"interface"`
+	Plug      PlugRef `json:"plug"`
+	Slot      SlotRef `json:"slot"`
+}
+
+// SnapConfinement summarizes the confinement of an installed snap.
+type SnapConfinement struct {
+	Name     string        `json:"name"`
+	Revision snap.Revision `json:"revision"`
+	// Confinement is the confinement declared by the snap.
+	Confinement snap.ConfinementType `json:"confinement"`
+	// Flags lists the flags relaxing or bypassing the confinement
+	// or the policy checks of the snap, like devmode or classic.
+	Flags []string `json:"flags,omitempty"`
+	// Unasserted is set for snaps installed without assertions.
+	Unasserted                 bool                        `json:"unasserted,omitempty"`
+	SuperPrivilegedConnections []SuperPrivilegedConnection `json:"super-privileged-connections,omitempty"`
+}
+
+// ConfinementReport summarizes the confinement of all installed snaps.
+type ConfinementReport struct {
+	Snaps []SnapConfinement `json:"snaps"`
+}
+
+// ConfinementReport returns the confinement report of the system.
+func (client *Client) ConfinementReport() (*ConfinementReport, error) {
+	var report ConfinementReport
+	if _, err := client.doSync("GET", "/v2/confinement-report", nil, nil, nil, &report); err != nil {
+		return nil, xerrors.Errorf("cannot get confinement report: %w", err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,69 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/snap"
+)
+
+func (cs *clientSuite) TestClientConfinementReport(c *check.C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": {"snaps": [
+			{"name": "baz", "revision": "x1", "confinement": "devmode", "flags": ["devmode"], "unasserted": true},
+			{"name": "foo", "revision": "10", "confinement": "strict", "super-privileged-connections": [
+				{"interface": "snapd-control", "plug": {"snap": "foo", "plug": "snapd-control"}, "slot": {"snap": "core", "slot": "snapd-control"}}
+			]}
+		]}
+	}`
+	report, err := cs.cli.ConfinementReport()
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/confinement-report")
+	c.Check(report, check.DeepEquals, &client.ConfinementReport{
+		Snaps: []client.SnapConfinement{{
+			Name:        "baz",
+			Revision:    snap.R(-1),
+			Confinement: snap.DevModeConfinement,
+			Flags:       []string{"devmode"},
+			Unasserted:  true,
+		}, {
+			Name:        "foo",
+			Revision:    snap.R(10),
+			Confinement: snap.StrictConfinement,
+			SuperPrivilegedConnections: []client.SuperPrivilegedConnection{{
+				Interface: "snapd-control",
+				Plug:      client.PlugRef{Snap: "foo", Name: "snapd-control"},
+				Slot:      client.SlotRef{Snap: "core", Name: "snapd-control"},
+			}},
+		}},
+	})
+}
+
+func (cs *clientSuite) TestClientConfinementReportError(c *check.C) {
+	cs.status = 500
+	cs.rsp = `{"type": "error", "result": {"message": "boom"}}`
+	_, err := cs.cli.ConfinementReport()
+	c.Check(err, check.ErrorMatches, "cannot get confinement report: boom")
+}
@@ -21,6 +21,7 @@ package client
 
 import (
 	"net/url"
+	"time"
 )
 
 // Connection describes a connection between a plug and a slot.
@@ -32,10 +33,19 @@ type Connection struct {
 	Manual bool `json:"manual"`
 	// Gadget is set for connections that were enabled by the gadget snap.
 	Gadget bool `json:"gadget"`
+	// AutoReason explains why the slot of an auto-connection was chosen
+	// among several candidates.
+	AutoReason string `json:"auto-reason,omitempty"`
 	// SlotAttrs is the list of attributes of the slot side of the connection.
 	SlotAttrs map[string]interface{} `json:"slot-attrs,omitempty"`
 	// PlugAttrs is the list of attributes of the plug side of the connection.
 	PlugAttrs map[string]interface{} `json:"plug-attrs,omitempty"`
+	// PlugLastUsed is when an app or hook bound to the plug was last
+	// seen running, it is zero if this never happened.
+	PlugLastUsed time.Time `json:"plug-last-used,omitempty"`
+	// SlotLastUsed is when an app or hook bound to the slot was last
+	// seen running, it is zero if this never happened.
+	SlotLastUsed time.Time `json:"slot-last-used,omitempty"`
 }
 
 // Connections contains information about connections, as well as related plugs
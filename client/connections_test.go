@@ -21,6 +21,7 @@ package client_test
 
 import (
 	"net/url"
+	"time"
 
 	"gopkg.in/check.v1"
 
@@ -112,6 +113,27 @@ func (cs *clientSuite) TestClientConnectionsDefault(c *check.C) {
 	})
 }
 
+func (cs *clientSuite) TestClientConnectionsLastUsed(c *check.C) {
+	cs.rsp = `{
+		"type": "sync",
+		"result": {
+			"established": [
+				{
+					"slot": {"snap": "core", "slot": "network"},
+					"plug": {"snap": "foo", "plug": "network"},
+					"interface": "network",
+					"plug-last-used": "2026-01-02T03:04:05Z"
+				}
+			]
+		}
+	}`
+	conns, err := cs.cli.Connections(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(conns.Established, check.HasLen, 1)
+	c.Check(conns.Established[0].PlugLastUsed, check.DeepEquals, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	c.Check(conns.Established[0].SlotLastUsed.IsZero(), check.Equals, true)
+}
+
 func (cs *clientSuite) TestClientConnectionsAll(c *check.C) {
 	cs.rsp = `{
 		"type": "sync",
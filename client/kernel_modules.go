@@ -0,0 +1,81 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/xerrors"
+)
+
+// KernelModule describes a kernel module shipped by the kernel snap of the
+// device or by one of its components.
+type KernelModule struct {
+	Name string `json:"name"`
+	// Component is the kernel snap component shipping the module, if
+	// not the kernel snap itself.
+	Component string `json:"component,omitempty"`
+	// Disabled is set if loading the module was disabled.
+	Disabled bool `json:"disabled,omitempty"`
+	// LoadedBy lists the snaps loading the module on boot through their
+	// interface connections.
+	LoadedBy []string `json:"loaded-by,omitempty"`
+}
+
+// KernelModulesAction is the instruction sent to the kernel-modules
+// endpoint.
+type KernelModulesAction struct {
+	Action  string   `json:"action"`
+	Modules []string `json:"modules"`
+}
+
+// KernelModules lists the kernel modules shipped by the kernel snap of the
+// device and by its components.
+func (client *Client) KernelModules() ([]KernelModule, error) {
+	var modules []KernelModule
+	if _, err := client.doSync("GET", "/v2/kernel-modules", nil, nil, nil, &modules); err != nil {
+		return nil, xerrors.Errorf("cannot list kernel modules: %w", err)
+	}
+	return modules, nil
+}
+
+// EnableKernelModules enables back the loading of the given kernel modules.
+func (client *Client) EnableKernelModules(modules []string) error {
+	return client.kernelModulesAction("enable", modules)
+}
+
+// DisableKernelModules persistently prevents the given kernel modules from
+// being loaded, for the current revision of the kernel snap.
+func (client *Client) DisableKernelModules(modules []string) error {
+	return client.kernelModulesAction("disable", modules)
+}
+
+func (client *Client) kernelModulesAction(action string, modules []string) error {
+	data, err := json.Marshal(&KernelModulesAction{Action: action, Modules: modules})
+	if err != nil {
+		return fmt.Errorf("cannot marshal kernel modules action: %v", err)
+	}
+	if _, err := client.doSync("POST", "/v2/kernel-modules", nil, nil, bytes.NewReader(data), nil); err != nil {
+		return xerrors.Errorf("cannot %s kernel modules: %w", action, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+)
+
+func (cs *clientSuite) TestClientKernelModules(c *check.C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": [
+			{"name": "btrfs", "loaded-by": ["foo"]},
+			{"name": "iwlwifi", "component": "wifi", "disabled": true}
+		]
+	}`
+	modules, err := cs.cli.KernelModules()
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/kernel-modules")
+	c.Check(modules, check.DeepEquals, []client.KernelModule{
+		{Name: "btrfs", LoadedBy: []string{"foo"}},
+		{Name: "iwlwifi", Component: "wifi", Disabled: true},
+	})
+}
+
+func (cs *clientSuite) TestClientDisableEnableKernelModules(c *check.C) {
+	for _, action := range []string{"disable", "enable"} {
+		cs.rsp = `{"type": "sync", "status-code": 200, "result": null}`
+		var err error
+		if action == "disable" {
+			err = cs.cli.DisableKernelModules([]string{"btrfs", "e1000e"})
+		} else {
+			err = cs.cli.EnableKernelModules([]string{"btrfs", "e1000e"})
+		}
+		c.Assert(err, check.IsNil)
+		c.Check(cs.req.Method, check.Equals, "POST")
+		c.Check(cs.req.URL.Path, check.Equals, "/v2/kernel-modules")
+		body, err := ioutil.ReadAll(cs.req.Body)
+		c.Assert(err, check.IsNil)
+		var req map[string]interface{}
+		c.Assert(json.Unmarshal(body, &req), check.IsNil)
+		c.Check(req, check.DeepEquals, map[string]interface{}{
+			"action":  action,
+			"modules": []interface{}{"btrfs", "e1000e"},
+		})
+	}
+}
+
+func (cs *clientSuite) TestClientDisableKernelModulesError(c *check.C) {
+	cs.rsp = `{"type": "error", "status-code": 400, "result": {"message": "kernel snap \"pc-kernel\" does not ship module \"nvidia\""}}`
+	err := cs.cli.DisableKernelModules([]string{"nvidia"})
+	c.Check(err, check.ErrorMatches, `cannot disable kernel modules: kernel snap "pc-kernel" does not ship module "nvidia"`)
+}
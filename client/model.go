@@ -38,6 +38,7 @@ import (
 
 type remodelData struct {
 	NewModel string `json:"new-model"`
+	Action   string `json:"action,omitempty"`
 }
 
 // Remodel tries to remodel the system with the given assertion data
@@ -55,6 +56,61 @@ func (client *Client) Remodel(b []byte) (changeID string, err error) {
 	return client.doAsync("POST", "/v2/model", nil, headers, bytes.NewReader(data))
 }
 
+// ReissueModel accepts the given model assertion data as a reissue of the
+// current model, that is a newer revision of it differing only in metadata
+// or in the key used to sign it, without a full remodel.
+func (client *Client) ReissueModel(b []byte) (changeID string, err error) {
+	data, err := json.Marshal(&remodelData{
+		NewModel: string(b),
+		Action:   "reissue",
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal reissue data: %v", err)
+	}
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+
+	return client.doAsync("POST", "/v2/model", nil, headers, bytes.NewReader(data))
+}
+
+// ModelDifference describes a header that differs between the current model
+// and a new model assertion.
+type ModelDifference struct {
+	Header string      `json:"header"`
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+}
+
+// ModelReissuePreview holds how a new model assertion differs from the
+// current model.
+type ModelReissuePreview struct {
+	Differences []ModelDifference `json:"differences"`
+	// Error is set if the new model cannot be accepted with ReissueModel
+	Error string `json:"error,omitempty"`
+}
+
+// PreviewModelReissue compares the given model assertion data with the
+// current model without changing anything.
+func (client *Client) PreviewModelReissue(b []byte) (*ModelReissuePreview, error) {
+	data, err := json.Marshal(&remodelData{
+		NewModel: string(b),
+		Action:   "preview-reissue",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal reissue data: %v", err)
+	}
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+
+	var preview ModelReissuePreview
+	if _, err := client.doSync("POST", "/v2/model", nil, headers, bytes.NewReader(data), &preview); err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
 // RemodelOffline tries to remodel the system with the given model assertion
 // and local snaps and assertion files.
 func (client *Client) RemodelOffline(
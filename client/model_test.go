@@ -33,6 +33,7 @@ import (
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/dirs"
 )
 
@@ -134,6 +135,64 @@ func (cs *clientSuite) TestClientRemodel(c *C) {
 	c.Check(jsonBody["new-model"], Equals, string(remodelJsonData))
 }
 
+func (cs *clientSuite) TestClientReissueModel(c *C) {
+	cs.status = 202
+	cs.rsp = `{
+		"type": "async",
+		"status-code": 202,
+		"result": {},
+		"change": "d729"
+	}`
+	id, err := cs.cli.ReissueModel([]byte("some-model"))
+	c.Assert(err, IsNil)
+	c.Check(id, Equals, "d729")
+	c.Check(cs.req.Method, Equals, "POST")
+	c.Check(cs.req.URL.Path, Equals, "/v2/model")
+	c.Assert(cs.req.Header.Get("Content-Type"), Equals, "application/json")
+
+	body, err := ioutil.ReadAll(cs.req.Body)
+	c.Assert(err, IsNil)
+	var jsonBody map[string]string
+	c.Assert(json.Unmarshal(body, &jsonBody), IsNil)
+	c.Check(jsonBody, DeepEquals, map[string]string{
+		"new-model": "some-model",
+		"action":    "reissue",
+	})
+}
+
+func (cs *clientSuite) TestClientPreviewModelReissue(c *C) {
+	cs.status = 200
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": {
+			"differences": [
+				{"header": "display-name", "new": "My Model"},
+				{"header": "kernel", "old": "pc-kernel", "new": "other-kernel"}
+			],
+			"error": "cannot reissue model"
+		}
+	}`
+	preview, err := cs.cli.PreviewModelReissue([]byte("some-model"))
+	c.Assert(err, IsNil)
+	c.Check(preview, DeepEquals, &client.ModelReissuePreview{
+		Differences: []client.ModelDifference{
+			{Header: "display-name", New: "My Model"},
+			{Header: "kernel", Old: "pc-kernel", New: "other-kernel"},
+		},
+		Error: "cannot reissue model",
+	})
+
+	body, err := ioutil.ReadAll(cs.req.Body)
+	c.Assert(err, IsNil)
+	var jsonBody map[string]string
+	c.Assert(json.Unmarshal(body, &jsonBody), IsNil)
+	c.Check(jsonBody, DeepEquals, map[string]string{
+		"new-model": "some-model",
+		"action":    "preview-reissue",
+	})
+}
+
 func (cs *clientSuite) TestClientGetModelHappy(c *C) {
 	cs.status = 200
 	cs.rsp = happyModelAssertionResponse
@@ -65,6 +65,18 @@ type Snap struct {
 	CommonIDs        []string      `json:"common-ids,omitempty"`
 	MountedFrom      string        `json:"mounted-from,omitempty"`
 	CohortKey        string        `json:"cohort-key,omitempty"`
+	Quarantined      bool          `json:"quarantined,omitempty"`
+	Pinned           bool          `json:"pinned,omitempty"`
+	PublisherChange  string        `json:"publisher-change,omitempty"`
+	// Verity tells how the snap is protected by dm-verity when mounted,
+	// "shipped" or "generated" after where its dm-verity data comes from.
+	Verity string `json:"verity,omitempty"`
+	// FallbackChannel is the more stable channel the snap falls back to
+	// because its tracking channel has no revision available.
+	FallbackChannel string `json:"fallback-channel,omitempty"`
+	// ReleaseNotes are the store-provided release notes of the
+	// revision, as shown for pending refreshes.
+	ReleaseNotes string `json:"release-notes,omitempty"`
 
 	Links map[string][]string `json:"links,omitempy"`
 
@@ -137,6 +149,9 @@ type FindOptions struct {
 	Section string
 	Private bool
 	Scope   string
+	// Sort is the order of the results, either "recent" or "popular",
+	// by relevance if unset.
+	Sort string
 
 	Refresh bool
 }
@@ -237,6 +252,9 @@ func (client *Client) Find(opts *FindOptions) ([]*Snap, *ResultInfo, error) {
 	if opts.Scope != "" {
 		q.Set("scope", opts.Scope)
 	}
+	if opts.Sort != "" {
+		q.Set("sort", opts.Sort)
+	}
 
 	return client.snapsFromPath("/v2/find", q)
 }
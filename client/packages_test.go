@@ -119,6 +119,19 @@ func (cs *clientSuite) TestClientFindWithScopeSetsQuery(c *check.C) {
 	})
 }
 
+func (cs *clientSuite) TestClientFindWithSortSetsQuery(c *check.C) {
+	_, _, _ = cs.cli.Find(&client.FindOptions{
+		Category: "mycategory",
+		Sort:     "popular",
+	})
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/find")
+	c.Check(cs.req.URL.Query(), check.DeepEquals, url.Values{
+		"category": []string{"mycategory"},
+		"sort":     []string{"popular"},
+	})
+}
+
 func (cs *clientSuite) TestClientSnapsInvalidSnapsJSON(c *check.C) {
 	cs.rsp = `{
 		"type": "sync",
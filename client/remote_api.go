@@ -0,0 +1,82 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// RemoteAPIToken describes a token granting access to the remote API.
+type RemoteAPIToken struct {
+	ID string `json:"id"`
+	// Scope is either "read", allowing only GET requests, or "manage".
+	Scope   string    `json:"scope"`
+	Created time.Time `json:"created"`
+	// Token is the secret to present as bearer token, it is only
+	// returned when the token is created.
+	Token string `json:"token,omitempty"`
+}
+
+type remoteAPITokenAction struct {
+	Action string `json:"action"`
+	Scope  string `json:"scope,omitempty"`
+	ID     string `json:"id,omitempty"`
+}
+
+// RemoteAPITokens lists the tokens granting access to the remote API.
+func (client *Client) RemoteAPITokens() ([]RemoteAPIToken, error) {
+	var tokens []RemoteAPIToken
+	if _, err := client.doSync("GET", "/v2/remote-api/tokens", nil, nil, nil, &tokens); err != nil {
+		return nil, xerrors.Errorf("cannot list remote API tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func (client *Client) doRemoteAPITokenAction(action *remoteAPITokenAction, result interface{}) error {
+	data, err := json.Marshal(action)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"Content-Type": "application/json"}
+	_, err = client.doSync("POST", "/v2/remote-api/tokens", nil, headers, bytes.NewReader(data), result)
+	return err
+}
+
+// CreateRemoteAPIToken creates a token with the given scope granting
+// access to the remote API.
+func (client *Client) CreateRemoteAPIToken(scope string) (*RemoteAPIToken, error) {
+	var token RemoteAPIToken
+	if err := client.doRemoteAPITokenAction(&remoteAPITokenAction{Action: "create", Scope: scope}, &token); err != nil {
+		return nil, xerrors.Errorf("cannot create remote API token: %w", err)
+	}
+	return &token, nil
+}
+
+// RevokeRemoteAPIToken revokes the remote API token with the given ID.
+func (client *Client) RevokeRemoteAPIToken(id string) error {
+	if err := client.doRemoteAPITokenAction(&remoteAPITokenAction{Action: "revoke", ID: id}, nil); err != nil {
+		return xerrors.Errorf("cannot revoke remote API token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+)
+
+func (cs *clientSuite) TestClientRemoteAPITokens(c *check.C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": [{"id": "abcd", "scope": "read", "created": "2026-10-01T10:00:00Z"}]
+	}`
+	tokens, err := cs.cli.RemoteAPITokens()
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/remote-api/tokens")
+	c.Check(tokens, check.DeepEquals, []client.RemoteAPIToken{{
+		ID:      "abcd",
+		Scope:   "read",
+		Created: time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC),
+	}})
+}
+
+func (cs *clientSuite) TestClientCreateRemoteAPIToken(c *check.C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": {"id": "abcd", "scope": "manage", "created": "2026-10-01T10:00:00Z", "token": "secret"}
+	}`
+	token, err := cs.cli.CreateRemoteAPIToken("manage")
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/remote-api/tokens")
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var action map[string]interface{}
+	c.Assert(json.Unmarshal(body, &action), check.IsNil)
+	c.Check(action, check.DeepEquals, map[string]interface{}{
+		"action": "create",
+		"scope":  "manage",
+	})
+	c.Check(token, check.DeepEquals, &client.RemoteAPIToken{
+		ID:      "abcd",
+		Scope:   "manage",
+		Created: time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC),
+		Token:   "secret",
+	})
+}
+
+func (cs *clientSuite) TestClientRevokeRemoteAPIToken(c *check.C) {
+	cs.rsp = `{"type": "sync", "status-code": 200, "result": null}`
+	err := cs.cli.RevokeRemoteAPIToken("abcd")
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "POST")
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var action map[string]interface{}
+	c.Assert(json.Unmarshal(body, &action), check.IsNil)
+	c.Check(action, check.DeepEquals, map[string]interface{}{
+		"action": "revoke",
+		"id":     "abcd",
+	})
+}
+
+func (cs *clientSuite) TestClientRevokeRemoteAPITokenError(c *check.C) {
+	cs.status = 404
+	cs.rsp = `{"type": "error", "result": {"message": "remote API token \"abcd\" not found"}}`
+	err := cs.cli.RevokeRemoteAPIToken("abcd")
+	c.Check(err, check.ErrorMatches, `cannot revoke remote API token: remote API token "abcd" not found`)
+}
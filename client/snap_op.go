@@ -62,6 +62,9 @@ type SnapOptions struct {
 	ValidationSets   []string        `json:"validation-sets,omitempty"`
 	Time             string          `json:"time,omitempty"`
 	HoldLevel        string          `json:"hold-level,omitempty"`
+	BlockRevision    string          `json:"block-revision,omitempty"`
+	BlockUntil       string          `json:"block-until,omitempty"`
+	Pin              bool            `json:"pin,omitempty"`
 
 	Users []string `json:"users,omitempty"`
 }
@@ -181,6 +184,11 @@ func (client *Client) UnholdRefreshesMany(names []string, options *SnapOptions)
 	return client.doMultiSnapAction("unhold", names, options)
 }
 
+// Unpin lets refreshes move a pinned snap away from its revision.
+func (client *Client) Unpin(name string, options *SnapOptions) (changeID string, err error) {
+	return client.doSnapAction("unpin", name, options)
+}
+
 func (client *Client) Enable(name string, options *SnapOptions) (changeID string, err error) {
 	return client.doSnapAction("enable", name, options)
 }
@@ -189,6 +197,12 @@ func (client *Client) Disable(name string, options *SnapOptions) (changeID strin
 	return client.doSnapAction("disable", name, options)
 }
 
+// ReleaseQuarantine releases a quarantined snap, connecting its
+// interfaces and starting its services
+func (client *Client) ReleaseQuarantine(name string, options *SnapOptions) (changeID string, err error) {
+	return client.doSnapAction("release-quarantine", name, options)
+}
+
 // Revert rolls the snap back to the previous on-disk state
 func (client *Client) Revert(name string, options *SnapOptions) (changeID string, err error) {
 	return client.doSnapAction("revert", name, options)
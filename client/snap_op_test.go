@@ -52,6 +52,8 @@ var ops = []struct {
 	{(*client.Client).Switch, "switch"},
 	{(*client.Client).HoldRefreshes, "hold"},
 	{(*client.Client).UnholdRefreshes, "unhold"},
+	{(*client.Client).Unpin, "unpin"},
+	{(*client.Client).ReleaseQuarantine, "release-quarantine"},
 }
 
 var multiOps = []struct {
@@ -42,14 +42,28 @@ const SnapshotExportMediaType = "application/x.snapd.snapshot"
 var (
 	ErrSnapshotSetNotFound   = errors.New("no snapshot set with the given ID")
 	ErrSnapshotSnapsNotFound = errors.New("no snapshot for the requested snaps found in the set with the given ID")
+	ErrSnapshotFileNotFound  = errors.New("no file with the given path found in the snapshot")
 )
 
 // A snapshotAction is used to request an operation on a snapshot.
 type snapshotAction struct {
-	SetID  uint64   `json:"set"`
-	Action string   `json:"action"`
-	Snaps  []string `json:"snaps,omitempty"`
-	Users  []string `json:"users,omitempty"`
+	SetID  uint64                 `json:"set"`
+	Action string                 `json:"action"`
+	Snaps  []string               `json:"snaps,omitempty"`
+	Users  []string               `json:"users,omitempty"`
+	Target *SnapshotRestoreTarget `json:"target,omitempty"`
+}
+
+// A SnapshotRestoreTarget redirects the restore of a snapshot to a
+// different snap instance and/or a different user than the ones the
+// snapshot was taken from.
+type SnapshotRestoreTarget struct {
+	// Instance is the name of the instance of the same snap whose data
+	// gets replaced, e.g. "app_staging" for a snapshot of "app".
+	Instance string `json:"instance,omitempty"`
+	// User is the user whose data gets replaced (and who will own it)
+	// with the data of the single user the restore is limited to.
+	User string `json:"user,omitempty"`
 }
 
 // A Snapshot is a collection of archives with a simple metadata json file
@@ -77,6 +91,8 @@ type Snapshot struct {
 	SHA3_384 map[string]string `json:"sha3-384"`
 	// the sum of the archive sizes
 	Size int64 `json:"size,omitempty"`
+	// the compression of the archives, either "zstd" or empty for gzip
+	Compression string `json:"compression,omitempty"`
 
 	// dynamic snapshot options
 	Options *snap.SnapshotOptions `json:"options,omitempty"`
@@ -113,6 +129,19 @@ func (sh *Snapshot) ContentHash() ([]byte, error) {
 	return h.Sum(nil), nil
 }
 
+// A SnapshotFile is a regular file stored in a snapshot.
+type SnapshotFile struct {
+	// Snap is the name of the snap whose snapshot holds the file.
+	Snap string `json:"snap"`
+	// User is the user whose data holds the file, empty for system data.
+	User string `json:"user,omitempty"`
+	// Path is the path of the file relative to the snap's data directory,
+	// starting with either the revision or "common".
+	Path  string    `json:"path"`
+	Size  int64     `json:"size"`
+	Mtime time.Time `json:"mtime"`
+}
+
 // A SnapshotSet is a set of snapshots created by a single "snap save".
 type SnapshotSet struct {
 	ID        uint64      `json:"id"`
@@ -210,11 +239,18 @@ func (client *Client) CheckSnapshots(setID uint64, snaps []string, users []strin
 // If snaps or users are non-empty, limit to checking only those
 // archives of the snapshot.
 func (client *Client) RestoreSnapshots(setID uint64, snaps []string, users []string) (changeID string, err error) {
+	return client.RestoreSnapshotsTo(setID, snaps, users, nil)
+}
+
+// RestoreSnapshotsTo extracts the given snapshot set like RestoreSnapshots,
+// but into the snap instance and/or for the user given by target.
+func (client *Client) RestoreSnapshotsTo(setID uint64, snaps []string, users []string, target *SnapshotRestoreTarget) (changeID string, err error) {
 	return client.snapshotAction(&snapshotAction{
 		SetID:  setID,
 		Action: "restore",
 		Snaps:  snaps,
 		Users:  users,
+		Target: target,
 	})
 }
 
@@ -260,6 +296,52 @@ func (client *Client) SnapshotExport(setID uint64) (stream io.ReadCloser, conten
 	return rsp.Body, rsp.ContentLength, nil
 }
 
+// SnapshotFiles lists the files stored in the given snapshot set, limited
+// to the snapshots of the given snaps (if non-empty).
+func (client *Client) SnapshotFiles(setID uint64, snapNames []string) ([]SnapshotFile, error) {
+	q := make(url.Values)
+	if len(snapNames) > 0 {
+		q.Add("snaps", strings.Join(snapNames, ","))
+	}
+
+	var files []SnapshotFile
+	_, err := client.doSync("GET", fmt.Sprintf("/v2/snapshots/%v/files", setID), q, nil, nil, &files)
+	return files, err
+}
+
+// SnapshotFile streams the content of a single file stored in the snapshot
+// of the given snap in the given set. The file belongs to the data of the
+// given user, or to the system data if user is empty, and its path is as
+// returned by SnapshotFiles.
+func (client *Client) SnapshotFile(setID uint64, snapName, user, path string) (stream io.ReadCloser, contentLength int64, err error) {
+	q := url.Values{
+		"snap": []string{snapName},
+		"path": []string{path},
+	}
+	if user != "" {
+		q.Set("user", user)
+	}
+	rsp, err := client.raw(context.Background(), "GET", fmt.Sprintf("/v2/snapshots/%v/files", setID), q, nil, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if rsp.StatusCode != 200 {
+		defer rsp.Body.Close()
+
+		var r response
+		dec := json.NewDecoder(rsp.Body)
+		if err := dec.Decode(&r); err == nil {
+			specificErr := r.err(client, rsp.StatusCode)
+			if specificErr != nil {
+				return nil, 0, specificErr
+			}
+		}
+		return nil, 0, fmt.Errorf("unexpected status code: %v", rsp.Status)
+	}
+
+	return rsp.Body, rsp.ContentLength, nil
+}
+
 // SnapshotImportSet is a snapshot import created by a "snap import-snapshot".
 type SnapshotImportSet struct {
 	ID    uint64   `json:"set-id"`
@@ -117,6 +117,7 @@ func (cs *clientSuite) testClientSnapshotActionFull(c *check.C, action string, u
 	c.Check(act.Action, check.Equals, action)
 	c.Check(act.Snaps, check.DeepEquals, []string{"asnap", "bsnap"})
 	c.Check(act.Users, check.DeepEquals, users)
+	c.Check(act.Target, check.IsNil)
 
 	c.Check(cs.req.Method, check.Equals, "POST")
 	c.Check(cs.req.URL.Path, check.Equals, "/v2/snapshots")
@@ -143,6 +144,27 @@ func (cs *clientSuite) TestClientRestoreSnapshots(c *check.C) {
 	cs.testClientSnapshotAction(c, "restore", cs.cli.RestoreSnapshots)
 }
 
+func (cs *clientSuite) TestClientRestoreSnapshotsTo(c *check.C) {
+	cs.status = 202
+	cs.rsp = `{
+		"status-code": 202,
+		"type": "async",
+		"change": "1too3"
+	}`
+	target := &client.SnapshotRestoreTarget{Instance: "asnap_staging", User: "buser"}
+	id, err := cs.cli.RestoreSnapshotsTo(42, []string{"asnap"}, []string{"auser"}, target)
+	c.Assert(err, check.IsNil)
+	c.Check(id, check.Equals, "1too3")
+
+	act, err := client.UnmarshalSnapshotAction(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	c.Check(act.SetID, check.Equals, uint64(42))
+	c.Check(act.Action, check.Equals, "restore")
+	c.Check(act.Snaps, check.DeepEquals, []string{"asnap"})
+	c.Check(act.Users, check.DeepEquals, []string{"auser"})
+	c.Check(act.Target, check.DeepEquals, target)
+}
+
 func (cs *clientSuite) TestClientExportSnapshotSpecificErr(c *check.C) {
 	content := `{"type":"error","status-code":400,"result":{"message":"boom","kind":"err-kind","value":"err-value"}}`
 	cs.contentLength = int64(len(content))
@@ -192,6 +214,55 @@ func (cs *clientSuite) TestClientExportSnapshot(c *check.C) {
 	}
 }
 
+func (cs *clientSuite) TestClientSnapshotFiles(c *check.C) {
+	cs.rsp = `{
+		"type": "sync",
+		"result": [{"snap": "foo", "path": "common/foo.conf", "size": 3}, {"snap": "foo", "user": "bob", "path": "42/bar", "size": 5}]
+}`
+	files, err := cs.cli.SnapshotFiles(42, []string{"foo"})
+	c.Assert(err, check.IsNil)
+	c.Check(files, check.DeepEquals, []client.SnapshotFile{
+		{Snap: "foo", Path: "common/foo.conf", Size: 3},
+		{Snap: "foo", User: "bob", Path: "42/bar", Size: 5},
+	})
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/snapshots/42/files")
+	c.Check(cs.req.URL.Query(), check.DeepEquals, url.Values{
+		"snaps": []string{"foo"},
+	})
+}
+
+func (cs *clientSuite) TestClientSnapshotFile(c *check.C) {
+	cs.contentLength = 5
+	cs.header = http.Header{"Content-Type": []string{"application/octet-stream"}}
+	cs.rsp = "hello"
+
+	r, size, err := cs.cli.SnapshotFile(42, "foo", "bob", "common/foo.conf")
+	c.Assert(err, check.IsNil)
+	c.Check(size, check.Equals, int64(5))
+	buf, err := ioutil.ReadAll(r)
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "hello")
+
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/snapshots/42/files")
+	c.Check(cs.req.URL.Query(), check.DeepEquals, url.Values{
+		"snap": []string{"foo"},
+		"user": []string{"bob"},
+		"path": []string{"common/foo.conf"},
+	})
+}
+
+func (cs *clientSuite) TestClientSnapshotFileErr(c *check.C) {
+	content := `{"type":"error","status-code":404,"result":{"message":"no file with the given path found in the snapshot"}}`
+	cs.contentLength = int64(len(content))
+	cs.rsp = content
+	cs.status = 404
+	cs.header = http.Header{"Content-Type": []string{"application/json"}}
+	_, _, err := cs.cli.SnapshotFile(42, "foo", "", "common/other")
+	c.Check(err, check.ErrorMatches, "no file with the given path found in the snapshot")
+}
+
 func (cs *clientSuite) TestClientSnapshotImport(c *check.C) {
 	type tableT struct {
 		rsp    string
@@ -23,6 +23,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"golang.org/x/xerrors"
 
@@ -140,6 +141,65 @@ func (client *Client) RebootToSystem(systemLabel, mode string) error {
 	return nil
 }
 
+// RecoveryRequest describes a request to boot into a recovery system
+// and its outcome.
+type RecoveryRequest struct {
+	// System is the label of the requested recovery system
+	System string `json:"system"`
+	// Mode is the requested mode
+	Mode string `json:"mode"`
+	// Reason is the reason code given with the request
+	Reason string `json:"reason"`
+	// Status is one of "pending", "completed" or "not-entered"
+	Status string `json:"status"`
+
+	RequestedAt time.Time `json:"requested-at"`
+	EnteredAt   time.Time `json:"entered-at,omitempty"`
+	CompletedAt time.Time `json:"completed-at,omitempty"`
+}
+
+// RequestRecovery issues a request to boot into the system with the
+// given label in the given mode on the next reboot, recording the given
+// reason code. The system is not rebooted.
+func (client *Client) RequestRecovery(systemLabel, mode, reason string) error {
+	if systemLabel == "" {
+		return fmt.Errorf("cannot request recovery without the system")
+	}
+	// deeper verification is done by the backend
+
+	req := struct {
+		Action string `json:"action"`
+		Mode   string `json:"mode"`
+		Reason string `json:"reason"`
+	}{
+		Action: "request-recovery",
+		Mode:   mode,
+		Reason: reason,
+	}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(&req); err != nil {
+		return err
+	}
+	if _, err := client.doSync("POST", "/v2/systems/"+systemLabel, nil, nil, &body, nil); err != nil {
+		return xerrors.Errorf("cannot request recovery into %q: %v", systemLabel, err)
+	}
+	return nil
+}
+
+// RecoveryRequest returns the last request to boot into a recovery
+// system, or nil if there was none.
+func (client *Client) RecoveryRequest() (*RecoveryRequest, error) {
+	var rsp struct {
+		RecoveryRequest *RecoveryRequest `json:"recovery-request,omitempty"`
+	}
+
+	if _, err := client.doSync("GET", "/v2/systems", nil, nil, nil, &rsp); err != nil {
+		return nil, xerrors.Errorf("cannot get recovery request: %v", err)
+	}
+	return rsp.RecoveryRequest, nil
+}
+
 type StorageEncryptionSupport string
 
 const (
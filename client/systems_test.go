@@ -22,6 +22,7 @@ package client_test
 import (
 	"encoding/json"
 	"io/ioutil"
+	"time"
 
 	"gopkg.in/check.v1"
 
@@ -217,6 +218,84 @@ func (cs *clientSuite) TestRequestSystemRebootErrorWithSystem(c *check.C) {
 	c.Check(cs.req.URL.Path, check.Equals, "/v2/systems/1234")
 }
 
+func (cs *clientSuite) TestRequestRecoveryHappy(c *check.C) {
+	cs.rsp = `{
+	    "type": "sync",
+	    "status-code": 200,
+	    "result": {}
+	}`
+	err := cs.cli.RequestRecovery("20201212", "recover", "disk-check")
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/systems/20201212")
+
+	body, err := ioutil.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var req map[string]interface{}
+	err = json.Unmarshal(body, &req)
+	c.Assert(err, check.IsNil)
+	c.Assert(req, check.DeepEquals, map[string]interface{}{
+		"action": "request-recovery",
+		"mode":   "recover",
+		"reason": "disk-check",
+	})
+}
+
+func (cs *clientSuite) TestRequestRecoveryError(c *check.C) {
+	cs.rsp = `{
+	    "type": "error",
+	    "status-code": 500,
+	    "result": {"message": "failed"}
+	}`
+	err := cs.cli.RequestRecovery("1234", "recover", "disk-check")
+	c.Assert(err, check.ErrorMatches, `cannot request recovery into "1234": failed`)
+
+	err = cs.cli.RequestRecovery("", "recover", "disk-check")
+	c.Assert(err, check.ErrorMatches, `cannot request recovery without the system`)
+}
+
+func (cs *clientSuite) TestRecoveryRequest(c *check.C) {
+	cs.rsp = `{
+	    "type": "sync",
+	    "status-code": 200,
+	    "result": {
+	        "recovery-request": {
+	            "system": "1234",
+	            "mode": "recover",
+	            "reason": "disk-check",
+	            "status": "completed",
+	            "requested-at": "2026-01-02T03:04:05Z",
+	            "entered-at": "2026-01-02T03:05:05Z",
+	            "completed-at": "2026-01-02T03:15:05Z"
+	        }
+	    }
+	}`
+	req, err := cs.cli.RecoveryRequest()
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/systems")
+	c.Check(req, check.DeepEquals, &client.RecoveryRequest{
+		System:      "1234",
+		Mode:        "recover",
+		Reason:      "disk-check",
+		Status:      "completed",
+		RequestedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		EnteredAt:   time.Date(2026, 1, 2, 3, 5, 5, 0, time.UTC),
+		CompletedAt: time.Date(2026, 1, 2, 3, 15, 5, 0, time.UTC),
+	})
+}
+
+func (cs *clientSuite) TestRecoveryRequestNone(c *check.C) {
+	cs.rsp = `{
+	    "type": "sync",
+	    "status-code": 200,
+	    "result": {}
+	}`
+	req, err := cs.cli.RecoveryRequest()
+	c.Assert(err, check.IsNil)
+	c.Check(req, check.IsNil)
+}
+
 func (cs *clientSuite) TestSystemDetailsNone(c *check.C) {
 	cs.rsp = `{
 	    "type": "sync",
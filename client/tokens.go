@@ -0,0 +1,86 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// APIToken describes a token allowing a subset of the snap operations,
+// like refreshing a given snap, without further authorization.
+type APIToken struct {
+	ID string `json:"id"`
+	// Allow lists the allowed operations as <action> or
+	// <action>:<snap>, e.g. refresh:some-snap.
+	Allow   []string  `json:"allow"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+	// Token is the secret to present as bearer token, it is only
+	// returned when the token is created.
+	Token string `json:"token,omitempty"`
+}
+
+type apiTokenAction struct {
+	Action  string     `json:"action"`
+	Allow   []string   `json:"allow,omitempty"`
+	Expires *time.Time `json:"expires,omitempty"`
+	ID      string     `json:"id,omitempty"`
+}
+
+// APITokens lists the API tokens.
+func (client *Client) APITokens() ([]APIToken, error) {
+	var tokens []APIToken
+	if _, err := client.doSync("GET", "/v2/tokens", nil, nil, nil, &tokens); err != nil {
+		return nil, xerrors.Errorf("cannot list tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func (client *Client) doAPITokenAction(action *apiTokenAction, result interface{}) error {
+	data, err := json.Marshal(action)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"Content-Type": "application/json"}
+	_, err = client.doSync("POST", "/v2/tokens", nil, headers, bytes.NewReader(data), result)
+	return err
+}
+
+// CreateAPIToken creates a token allowing the given operations until
+// it expires.
+func (client *Client) CreateAPIToken(allow []string, expires time.Time) (*APIToken, error) {
+	var token APIToken
+	if err := client.doAPITokenAction(&apiTokenAction{Action: "create", Allow: allow, Expires: &expires}, &token); err != nil {
+		return nil, xerrors.Errorf("cannot create token: %w", err)
+	}
+	return &token, nil
+}
+
+// RevokeAPIToken revokes the API token with the given ID.
+func (client *Client) RevokeAPIToken(id string) error {
+	if err := client.doAPITokenAction(&apiTokenAction{Action: "revoke", ID: id}, nil); err != nil {
+		return xerrors.Errorf("cannot revoke token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+)
+
+func (cs *clientSuite) TestClientAPITokens(c *check.C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": [{"id": "abcd", "allow": ["refresh:foo"], "created": "2026-10-01T10:00:00Z", "expires": "2026-10-31T10:00:00Z"}]
+	}`
+	tokens, err := cs.cli.APITokens()
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/tokens")
+	c.Check(tokens, check.DeepEquals, []client.APIToken{{
+		ID:      "abcd",
+		Allow:   []string{"refresh:foo"},
+		Created: time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC),
+		Expires: time.Date(2026, 10, 31, 10, 0, 0, 0, time.UTC),
+	}})
+}
+
+func (cs *clientSuite) TestClientCreateAPIToken(c *check.C) {
+	cs.rsp = `{
+		"type": "sync",
+		"status-code": 200,
+		"result": {"id": "abcd", "allow": ["refresh:foo"], "created": "2026-10-01T10:00:00Z", "expires": "2026-10-31T10:00:00Z", "token": "secret"}
+	}`
+	expires := time.Date(2026, 10, 31, 10, 0, 0, 0, time.UTC)
+	token, err := cs.cli.CreateAPIToken([]string{"refresh:foo"}, expires)
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/tokens")
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var action map[string]interface{}
+	c.Assert(json.Unmarshal(body, &action), check.IsNil)
+	c.Check(action, check.DeepEquals, map[string]interface{}{
+		"action":  "create",
+		"allow":   []interface{}{"refresh:foo"},
+		"expires": "2026-10-31T10:00:00Z",
+	})
+	c.Check(token, check.DeepEquals, &client.APIToken{
+		ID:      "abcd",
+		Allow:   []string{"refresh:foo"},
+		Created: time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC),
+		Expires: expires,
+		Token:   "secret",
+	})
+}
+
+func (cs *clientSuite) TestClientRevokeAPIToken(c *check.C) {
+	cs.rsp = `{"type": "sync", "status-code": 200, "result": null}`
+	err := cs.cli.RevokeAPIToken("abcd")
+	c.Assert(err, check.IsNil)
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var action map[string]interface{}
+	c.Assert(json.Unmarshal(body, &action), check.IsNil)
+	c.Check(action, check.DeepEquals, map[string]interface{}{
+		"action": "revoke",
+		"id":     "abcd",
+	})
+}
+
+func (cs *clientSuite) TestClientCreateAPITokenError(c *check.C) {
+	cs.status = 400
+	cs.rsp = `{"type": "error", "result": {"message": "cannot allow unknown operation \"frobnicate\""}}`
+	_, err := cs.cli.CreateAPIToken([]string{"frobnicate"}, time.Now())
+	c.Check(err, check.ErrorMatches, `cannot create token: cannot allow unknown operation "frobnicate"`)
+}
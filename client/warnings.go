@@ -37,6 +37,8 @@ type Warning struct {
 	LastShown   time.Time     `json:"last-shown,omitempty"`
 	ExpireAfter time.Duration `json:"expire-after,omitempty"`
 	RepeatAfter time.Duration `json:"repeat-after,omitempty"`
+	// Severity is one of "info", "warning" or "critical".
+	Severity string `json:"severity,omitempty"`
 }
 
 type jsonWarning struct {
@@ -48,8 +50,10 @@ type jsonWarning struct {
 // WarningsOptions contains options for querying snapd for warnings
 // supported options:
 // - All: return all warnings, instead of only the un-okayed ones.
+// - Severity: return only warnings of at least this severity.
 type WarningsOptions struct {
-	All bool
+	All      bool
+	Severity string
 }
 
 // Warnings returns the list of un-okayed warnings.
@@ -59,6 +63,9 @@ func (client *Client) Warnings(opts WarningsOptions) ([]*Warning, error) {
 	if opts.All {
 		q.Add("select", "all")
 	}
+	if opts.Severity != "" {
+		q.Add("severity", opts.Severity)
+	}
 	_, err := client.doSync("GET", "/v2/warnings", q, nil, nil, &jws)
 
 	ws := make([]*Warning, len(jws))
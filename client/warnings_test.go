@@ -21,6 +21,7 @@ package client_test
 
 import (
 	"encoding/json"
+	"net/url"
 	"time"
 
 	"gopkg.in/check.v1"
@@ -97,6 +98,33 @@ func (cs *clientSuite) TestWarnings(c *check.C) {
 	cs.testWarnings(c, false)
 }
 
+func (cs *clientSuite) TestWarningsSeverity(c *check.C) {
+	cs.rsp = `{
+		"result": [
+		    {
+			"expire-after": "2016h0m0s",
+			"first-added": "2018-09-19T12:41:18.505007495Z",
+			"last-added": "2018-09-19T12:41:18.505007495Z",
+			"message": "the sky is falling",
+			"repeat-after": "4h0m0s",
+			"severity": "critical"
+		    }
+		],
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	ws, err := cs.cli.Warnings(client.WarningsOptions{Severity: "critical"})
+	c.Assert(err, check.IsNil)
+	c.Assert(ws, check.HasLen, 1)
+	c.Check(ws[0].Message, check.Equals, "the sky is falling")
+	c.Check(ws[0].Severity, check.Equals, "critical")
+	c.Check(ws[0].ExpireAfter, check.Equals, 2016*time.Hour)
+	c.Check(ws[0].RepeatAfter, check.Equals, 4*time.Hour)
+	c.Check(cs.req.URL.Query(), check.DeepEquals, url.Values{"severity": {"critical"}})
+}
+
 func (cs *clientSuite) TestOkay(c *check.C) {
 	cs.rsp = `{
 		"type": "sync",
@@ -24,6 +24,8 @@ var (
 	SeccompResolver   = seccompResolver
 	VersionInfo       = versionInfo
 	GoSeccompFeatures = goSeccompFeatures
+
+	AuditArchToScmpArch = auditArchToScmpArch
 )
 
 func MockArchDpkgArchitecture(f func() string) (restore func()) {
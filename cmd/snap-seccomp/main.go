@@ -748,7 +748,7 @@ func addSecondaryArches(secFilter *seccomp.ScmpFilter) error {
 	return nil
 }
 
-func preprocess(content []byte) (unrestricted, complain bool) {
+func preprocess(content []byte) (unrestricted, complain, profile bool) {
 	scanner := bufio.NewScanner(bytes.NewBuffer(content))
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -757,9 +757,11 @@ func preprocess(content []byte) (unrestricted, complain bool) {
 			unrestricted = true
 		case "@complain":
 			complain = true
+		case "@profile":
+			profile = true
 		}
 	}
-	return unrestricted, complain
+	return unrestricted, complain, profile
 }
 
 // With golang-seccomp <= 0.9.0, seccomp.ActLog is not available so guess
@@ -793,10 +795,19 @@ func compile(content []byte, out string) error {
 	var err error
 	var secFilter *seccomp.ScmpFilter
 
-	unrestricted, complain := preprocess(content)
+	unrestricted, complain, profile := preprocess(content)
 	switch {
 	case unrestricted:
 		return osutil.AtomicWrite(out, bytes.NewBufferString("@unrestricted\n"), 0644, 0)
+	case profile:
+		// When profiling, every system call is allowed and logged so
+		// that the ones actually used can be compared with the policy
+		// afterwards, hence no rules are added.
+		if !actLogSupported() {
+			return fmt.Errorf("cannot profile system calls without support for the log action")
+		}
+		secFilter, err = seccomp.NewFilter(actLog)
+		unrestricted = true
 	case complain:
 		var complainAct seccomp.ScmpAction = complainAction()
 
@@ -898,6 +909,52 @@ func showSeccompLibraryVersion() error {
 	return nil
 }
 
+// auditArchToScmpArch takes the audit architecture of logged system calls, in
+// hexadecimal, and converts it to the seccomp.ScmpArch as used in the
+// libseccomp-golang library
+func auditArchToScmpArch(auditArch string) seccomp.ScmpArch {
+	switch auditArch {
+	case "c000003e":
+		return seccomp.ArchAMD64
+	case "c00000b7":
+		return seccomp.ArchARM64
+	case "40000028":
+		return seccomp.ArchARM
+	case "40000003":
+		return seccomp.ArchX86
+	case "14":
+		return seccomp.ArchPPC
+	case "80000015":
+		return seccomp.ArchPPC64
+	case "c0000015":
+		return seccomp.ArchPPC64LE
+	case "80000016":
+		return seccomp.ArchS390X
+	}
+	return seccomp.ArchInvalid
+}
+
+// showSyscallNames prints the names of the system calls with the given
+// numbers on the given audit architecture, one per line.
+func showSyscallNames(auditArch string, numbers []string) error {
+	scmpArch := auditArchToScmpArch(auditArch)
+	if scmpArch == seccomp.ArchInvalid {
+		return fmt.Errorf("unsupported audit architecture %q", auditArch)
+	}
+	for _, number := range numbers {
+		nr, err := strconv.Atoi(number)
+		if err != nil {
+			return fmt.Errorf("invalid system call number %q", number)
+		}
+		name, err := seccomp.ScmpSyscall(nr).GetNameByArch(scmpArch)
+		if err != nil {
+			return fmt.Errorf("cannot resolve system call %d: %v", nr, err)
+		}
+		fmt.Fprintf(os.Stdout, "%s\n", name)
+	}
+	return nil
+}
+
 func main() {
 	var err error
 	var content []byte
@@ -923,6 +980,12 @@ func main() {
 		err = showSeccompLibraryVersion()
 	case "version-info":
 		err = showVersionInfo()
+	case "syscall-names":
+		if len(os.Args) < 3 {
+			fmt.Println("syscall-names needs an audit architecture")
+			os.Exit(1)
+		}
+		err = showSyscallNames(os.Args[2], os.Args[3:])
 	default:
 		err = fmt.Errorf("unsupported argument %q", cmd)
 	}
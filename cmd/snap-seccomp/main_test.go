@@ -365,6 +365,23 @@ func (s *snapSeccompSuite) TestUnrestricted(c *C) {
 	c.Check(outPath, testutil.FileEquals, inp)
 }
 
+func (s *snapSeccompSuite) TestAuditArchToScmpArch(c *C) {
+	for _, t := range []struct {
+		auditArch string
+		scmpArch  seccomp.ScmpArch
+	}{
+		{"c000003e", seccomp.ArchAMD64},
+		{"c00000b7", seccomp.ArchARM64},
+		{"40000028", seccomp.ArchARM},
+		{"40000003", seccomp.ArchX86},
+		{"c0000015", seccomp.ArchPPC64LE},
+		{"80000016", seccomp.ArchS390X},
+		{"deadbeef", seccomp.ArchInvalid},
+	} {
+		c.Check(main.AuditArchToScmpArch(t.auditArch), Equals, t.scmpArch, Commentf("%s", t.auditArch))
+	}
+}
+
 // TestCompile iterates over a range of textual seccomp whitelist rules and
 // mocked kernel syscall input. For each rule, the test consists of compiling
 // the rule into a bpf program and then running that program on a virtual bpf
@@ -389,6 +406,8 @@ func (s *snapSeccompSuite) TestCompile(c *C) {
 	}{
 		// special
 		{"@complain", "execve", Allow},
+		{"@profile", "execve", Allow},
+		{"@profile\n~ioctl", "ioctl", Allow},
 
 		// trivial allow
 		{"read", "read", Allow},
@@ -28,6 +28,7 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/osutil/mount"
@@ -250,6 +251,27 @@ func (c *Change) ensureSource(as *Assumptions) ([]*Change, error) {
 	return changes, err
 }
 
+// ensureSymlinkTarget checks that the target of a layout symlink pointing
+// into the read-only snap mount directory exists. Such a symlink would be
+// dangling forever and is a packaging error worth reporting clearly. Targets
+// in writable locations, like $SNAP_DATA, may be created later by the snap.
+func (c *Change) ensureSymlinkTarget() error {
+	if c.Entry.XSnapdOrigin() != "layout" {
+		return nil
+	}
+	target := c.Entry.XSnapdSymlink()
+	if !strings.HasPrefix(target, dirs.CoreSnapMountDir+"/") {
+		return nil
+	}
+	if _, err := osLstat(target); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("cannot create layout symlink %q: target %q does not exist in the snap", c.Entry.Dir, target)
+		}
+		return fmt.Errorf("cannot inspect layout symlink target %q: %v", target, err)
+	}
+	return nil
+}
+
 // changePerformImpl is the real implementation of Change.Perform
 func changePerformImpl(c *Change, as *Assumptions) (changes []*Change, err error) {
 	if c.Action == Mount {
@@ -262,6 +284,9 @@ func changePerformImpl(c *Change, as *Assumptions) (changes []*Change, err error
 		// As a result of this ensure call we may need to make the medium writable
 		// and that's why we may return more changes as a result of performing this
 		// one.
+		if err := c.ensureSymlinkTarget(); err != nil {
+			return nil, err
+		}
 		changesTarget, err = c.ensureTarget(as)
 		// NOTE: we are collecting changes even if things fail. This is so that
 		// upper layers can perform undo correctly.
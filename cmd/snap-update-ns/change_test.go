@@ -2118,6 +2118,52 @@ func (s *changeSuite) TestPerformCreateSymlink(c *C) {
 	})
 }
 
+// Change.Perform wants to create a layout symlink pointing into the snap.
+func (s *changeSuite) TestPerformCreateLayoutSymlinkIntoSnap(c *C) {
+	defer s.as.MockUnrestrictedPaths("/")() // Treat test path as unrestricted.
+	s.sys.InsertOsLstatResult(`lstat "/snap/foo/1/oldname"`, testutil.FileInfoFile)
+	s.sys.InsertFault(`lstat "/name"`, syscall.ENOENT)
+	chg := &update.Change{Action: update.Mount, Entry: osutil.MountEntry{Name: "unused", Dir: "/name", Options: []string{"x-snapd.kind=symlink", "x-snapd.symlink=/snap/foo/1/oldname", "x-snapd.origin=layout"}}}
+	synth, err := chg.Perform(s.as)
+	c.Assert(err, IsNil)
+	c.Assert(synth, HasLen, 0)
+	c.Assert(s.sys.RCalls(), testutil.SyscallsEqual, []testutil.CallResultError{
+		{C: `lstat "/snap/foo/1/oldname"`, R: testutil.FileInfoFile},
+		{C: `lstat "/name"`, E: syscall.ENOENT},
+		{C: `open "/" O_NOFOLLOW|O_CLOEXEC|O_DIRECTORY 0`, R: 3},
+		{C: `symlinkat "/snap/foo/1/oldname" 3 "name"`},
+		{C: `close 3`},
+	})
+}
+
+// Change.Perform wants to create a layout symlink pointing to a missing file in the snap.
+func (s *changeSuite) TestPerformCreateLayoutSymlinkIntoSnapMissingTarget(c *C) {
+	s.sys.InsertFault(`lstat "/snap/foo/1/oldname"`, syscall.ENOENT)
+	chg := &update.Change{Action: update.Mount, Entry: osutil.MountEntry{Name: "unused", Dir: "/name", Options: []string{"x-snapd.kind=symlink", "x-snapd.symlink=/snap/foo/1/oldname", "x-snapd.origin=layout"}}}
+	synth, err := chg.Perform(s.as)
+	c.Assert(err, ErrorMatches, `cannot create layout symlink "/name": target "/snap/foo/1/oldname" does not exist in the snap`)
+	c.Assert(synth, HasLen, 0)
+	c.Assert(s.sys.RCalls(), testutil.SyscallsEqual, []testutil.CallResultError{
+		{C: `lstat "/snap/foo/1/oldname"`, E: syscall.ENOENT},
+	})
+}
+
+// Change.Perform wants to create a layout symlink pointing to a missing file outside of the snap.
+func (s *changeSuite) TestPerformCreateLayoutSymlinkIntoSnapData(c *C) {
+	defer s.as.MockUnrestrictedPaths("/")() // Treat test path as unrestricted.
+	s.sys.InsertFault(`lstat "/name"`, syscall.ENOENT)
+	chg := &update.Change{Action: update.Mount, Entry: osutil.MountEntry{Name: "unused", Dir: "/name", Options: []string{"x-snapd.kind=symlink", "x-snapd.symlink=/var/snap/foo/1/oldname", "x-snapd.origin=layout"}}}
+	synth, err := chg.Perform(s.as)
+	c.Assert(err, IsNil)
+	c.Assert(synth, HasLen, 0)
+	c.Assert(s.sys.RCalls(), testutil.SyscallsEqual, []testutil.CallResultError{
+		{C: `lstat "/name"`, E: syscall.ENOENT},
+		{C: `open "/" O_NOFOLLOW|O_CLOEXEC|O_DIRECTORY 0`, R: 3},
+		{C: `symlinkat "/var/snap/foo/1/oldname" 3 "name"`},
+		{C: `close 3`},
+	})
+}
+
 // Change.Perform wants to create a symlink but it fails.
 func (s *changeSuite) TestPerformCreateSymlinkWithError(c *C) {
 	defer s.as.MockUnrestrictedPaths("/")() // Treat test path as unrestricted.
@@ -0,0 +1,192 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdAuth struct{}
+
+var shortAuthHelp = i18n.G("Manage API tokens")
+var longAuthHelp = i18n.G(`
+The auth command contains sub-commands to manage API tokens.
+
+An API token allows some operations, like refreshing a given snap, without
+further authorization, which makes it a safer alternative to root access for
+automation like CI agents. The token is passed to snap via the
+SNAPD_API_TOKEN environment variable.
+`)
+
+var shortAuthCreateTokenHelp = i18n.G("Create an API token")
+var longAuthCreateTokenHelp = i18n.G(`
+The create-token command creates an API token allowing the given operations
+and prints its secret.
+
+Operations are given as <action> or <action>:<snap>, for example
+--allow=refresh:some-snap allows only refreshing some-snap, while
+--allow=refresh allows refreshing any snap.
+`)
+
+var shortAuthTokensHelp = i18n.G("List API tokens")
+var longAuthTokensHelp = i18n.G(`
+The tokens command lists the API tokens and the operations they allow.
+`)
+
+var shortAuthRevokeTokenHelp = i18n.G("Revoke an API token")
+var longAuthRevokeTokenHelp = i18n.G(`
+The revoke-token command revokes the API token with the given ID.
+`)
+
+type cmdAuthCreateToken struct {
+	clientMixin
+
+	Allow   []string `long:"allow" required:"yes"`
+	Expires string   `long:"expires" default:"30d"`
+}
+
+type cmdAuthTokens struct {
+	clientMixin
+	timeMixin
+}
+
+type cmdAuthRevokeToken struct {
+	clientMixin
+
+	Positional struct {
+		ID string `positional-arg-name:"<token-id>"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func init() {
+	addSubCommand(&authCommands, "create-token", shortAuthCreateTokenHelp, longAuthCreateTokenHelp, func() flags.Commander {
+		return &cmdAuthCreateToken{}
+	}, map[string]string{
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"allow": i18n.G("Allow the given operation, as <action> or <action>:<snap>"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"expires": i18n.G("Expire the token after the given duration, e.g. 12h or 30d"),
+	}, nil)
+	addSubCommand(&authCommands, "tokens", shortAuthTokensHelp, longAuthTokensHelp, func() flags.Commander {
+		return &cmdAuthTokens{}
+	}, timeDescs, nil)
+	addSubCommand(&authCommands, "revoke-token", shortAuthRevokeTokenHelp, longAuthRevokeTokenHelp, func() flags.Commander {
+		return &cmdAuthRevokeToken{}
+	}, nil, []argDesc{{
+		// TRANSLATORS: This needs to begin with < and end with >
+		name: i18n.G("<token-id>"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		desc: i18n.G("ID of the token to revoke"),
+	}})
+}
+
+// parseDuration parses durations as understood by time.ParseDuration,
+// with the addition of days, e.g. 30d. Only positive durations are
+// accepted.
+func parseDuration(s string) (time.Duration, error) {
+	var d time.Duration
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		n, err := strconv.ParseUint(days, 10, 16)
+		if err != nil {
+			return 0, err
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		d, err = time.ParseDuration(s)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	return d, nil
+}
+
+// parseTokenExpiry parses the expiry of a token as a duration, see
+// parseDuration.
+func parseTokenExpiry(s string) (time.Duration, error) {
+	d, err := parseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf(i18n.G("invalid token expiry %q"), s)
+	}
+	return d, nil
+}
+
+func (x *cmdAuthCreateToken) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	d, err := parseTokenExpiry(x.Expires)
+	if err != nil {
+		return err
+	}
+	tok, err := x.client.CreateAPIToken(x.Allow, timeNow().Add(d))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(Stderr, i18n.G("Created token %s expiring %s\n"), tok.ID, tok.Expires.Format(time.RFC3339))
+	fmt.Fprintln(Stdout, tok.Token)
+	return nil
+}
+
+func (x *cmdAuthTokens) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	tokens, err := x.client.APITokens()
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No API tokens."))
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+	fmt.Fprintln(w, i18n.G("ID\tCreated\tExpires\tAllow"))
+	for _, tok := range tokens {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", tok.ID, x.fmtTime(tok.Created), x.fmtTime(tok.Expires), strings.Join(tok.Allow, ","))
+	}
+	return nil
+}
+
+func (x *cmdAuthRevokeToken) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	if err := x.client.RevokeAPIToken(x.Positional.ID); err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, i18n.G("Token %s revoked\n"), x.Positional.ID)
+	return nil
+}
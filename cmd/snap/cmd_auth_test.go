@@ -0,0 +1,122 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) TestAuthCreateToken(c *check.C) {
+	now := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	defer snap.MockTimeNow(func() time.Time { return now })()
+
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "POST")
+			c.Check(r.URL.Path, check.Equals, "/v2/tokens")
+			c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+				"action":  "create",
+				"allow":   []interface{}{"refresh:foo", "hold"},
+				"expires": "2026-10-01T22:00:00Z",
+			})
+			fmt.Fprintln(w, `{"type": "sync", "result": {"id": "abcd", "allow": ["refresh:foo", "hold"], "created": "2026-10-01T10:00:00Z", "expires": "2026-10-01T22:00:00Z", "token": "secret"}}`)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"auth", "create-token", "--allow=refresh:foo", "--allow=hold", "--expires=12h"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, "secret\n")
+	c.Check(s.Stderr(), check.Equals, "Created token abcd expiring 2026-10-01T22:00:00Z\n")
+}
+
+func (s *SnapSuite) TestAuthCreateTokenDefaultExpiry(c *check.C) {
+	now := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	defer snap.MockTimeNow(func() time.Time { return now })()
+
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(DecodedRequestBody(c, r)["expires"], check.Equals, "2026-10-31T10:00:00Z")
+		fmt.Fprintln(w, `{"type": "sync", "result": {"id": "abcd", "allow": ["refresh"], "created": "2026-10-01T10:00:00Z", "expires": "2026-10-31T10:00:00Z", "token": "secret"}}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"auth", "create-token", "--allow=refresh"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "secret\n")
+}
+
+func (s *SnapSuite) TestAuthCreateTokenInvalidExpiry(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Fatalf("unexpected request")
+	})
+	for _, expires := range []string{"0d", "-1h", "xd", "soon"} {
+		_, err := snap.Parser(snap.Client()).ParseArgs([]string{"auth", "create-token", "--allow=refresh", "--expires=" + expires})
+		c.Check(err, check.ErrorMatches, fmt.Sprintf("invalid token expiry %q", expires))
+	}
+}
+
+func (s *SnapSuite) TestAuthTokens(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, check.Equals, "GET")
+		c.Check(r.URL.Path, check.Equals, "/v2/tokens")
+		fmt.Fprintln(w, `{"type": "sync", "result": [{"id": "abcd", "allow": ["refresh:foo", "hold"], "created": "2026-10-01T10:00:00Z", "expires": "2026-10-31T10:00:00Z"}]}`)
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"auth", "tokens", "--abs-time"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `
+ID    Created               Expires               Allow
+abcd  2026-10-01T10:00:00Z  2026-10-31T10:00:00Z  refresh:foo,hold
+`[1:])
+}
+
+func (s *SnapSuite) TestAuthTokensNone(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": []}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"auth", "tokens"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "No API tokens.\n")
+}
+
+func (s *SnapSuite) TestAuthRevokeToken(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, check.Equals, "POST")
+		c.Check(r.URL.Path, check.Equals, "/v2/tokens")
+		c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+			"action": "revoke",
+			"id":     "abcd",
+		})
+		fmt.Fprintln(w, `{"type": "sync", "result": null}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"auth", "revoke-token", "abcd"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "Token abcd revoked\n")
+}
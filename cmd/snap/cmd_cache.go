@@ -0,0 +1,167 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/strutil"
+)
+
+type cmdCache struct{}
+
+var shortCacheHelp = i18n.G("Manage the snap download cache")
+var longCacheHelp = i18n.G(`
+The cache command contains sub-commands to inspect and clean the cache of
+downloaded snaps.
+
+Downloaded snaps are kept in the cache so that reverting to or reinstalling
+a revision does not download it again. Cache entries that are still the
+blob of an installed snap revision take no additional space.
+`)
+
+var shortCacheListHelp = i18n.G("List the snap download cache")
+var longCacheListHelp = i18n.G(`
+The list command lists the entries of the snap download cache, from the
+least to the most recently used one, with their size and the installed snap
+revisions that use them.
+`)
+
+var shortCacheCleanHelp = i18n.G("Remove entries of the snap download cache")
+var longCacheCleanHelp = i18n.G(`
+The clean command removes the entries of the snap download cache that are
+not used by an installed snap revision.
+
+Without options, all such entries are removed. With --older-than, only the
+entries not used for the given duration are removed. With --max-size, the
+least recently used entries are removed until the remaining ones take at
+most the given size.
+`)
+
+type cmdCacheList struct {
+	clientMixin
+	timeMixin
+}
+
+type cmdCacheClean struct {
+	clientMixin
+
+	OlderThan string `long:"older-than"`
+	MaxSize   string `long:"max-size"`
+}
+
+func init() {
+	addSubCommand(&cacheCommands, "list", shortCacheListHelp, longCacheListHelp, func() flags.Commander {
+		return &cmdCacheList{}
+	}, timeDescs, nil)
+	addSubCommand(&cacheCommands, "clean", shortCacheCleanHelp, longCacheCleanHelp, func() flags.Commander {
+		return &cmdCacheClean{}
+	}, map[string]string{
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"older-than": i18n.G("Remove only entries not used for the given duration, e.g. 12h or 30d"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"max-size": i18n.G("Remove the least recently used entries until the cache takes at most the given size, e.g. 500MB"),
+	}, nil)
+}
+
+// shortDigest abbreviates the digest of a cache entry for display.
+func shortDigest(digest string) string {
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
+
+func (x *cmdCacheList) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	entries, err := x.client.CacheEntries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("The snap download cache is empty."))
+		return nil
+	}
+
+	var total, reclaimable int64
+	w := tabWriter()
+	fmt.Fprintln(w, i18n.G("Digest\tSize\tLast used\tUsed by"))
+	for _, e := range entries {
+		total += e.Size
+		usedBy := "-"
+		switch {
+		case len(e.Snaps) > 0:
+			usedBy = strings.Join(e.Snaps, ",")
+		case e.Referenced:
+			// linked from elsewhere, but not by an installed snap
+			usedBy = i18n.G("other")
+		default:
+			reclaimable += e.Size
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", shortDigest(e.Digest), fmtSize(e.Size), x.fmtTime(e.LastUsed), usedBy)
+	}
+	w.Flush()
+	fmt.Fprintf(Stdout, i18n.G("\nTotal %s, %s can be reclaimed with 'snap cache clean'.\n"), strings.TrimSpace(fmtSize(total)), strings.TrimSpace(fmtSize(reclaimable)))
+	return nil
+}
+
+func (x *cmdCacheClean) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	opts := &client.CacheCleanOptions{}
+	if x.OlderThan != "" {
+		d, err := parseDuration(x.OlderThan)
+		if err != nil {
+			return fmt.Errorf(i18n.G("invalid duration %q for --older-than"), x.OlderThan)
+		}
+		opts.OlderThan = d
+	}
+	if x.MaxSize != "" {
+		size, err := strutil.ParseByteSize(x.MaxSize)
+		if err != nil {
+			return fmt.Errorf(i18n.G("cannot parse --max-size: %v"), err)
+		}
+		if size <= 0 {
+			return fmt.Errorf(i18n.G("--max-size must be positive"))
+		}
+		opts.MaxSize = size
+	}
+
+	res, err := x.client.CleanCache(opts)
+	if err != nil {
+		return err
+	}
+	if len(res.Removed) == 0 {
+		fmt.Fprintln(Stdout, i18n.G("No cache entries removed."))
+		return nil
+	}
+	fmt.Fprintf(Stdout, i18n.NG("Removed %d cache entry, reclaimed %s.\n", "Removed %d cache entries, reclaimed %s.\n", len(res.Removed)), len(res.Removed), strings.TrimSpace(fmtSize(res.Reclaimed)))
+	return nil
+}
@@ -0,0 +1,122 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) TestCacheList(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, check.Equals, "GET")
+		c.Check(r.URL.Path, check.Equals, "/v2/cache")
+		fmt.Fprintln(w, `{"type": "sync", "result": [
+			{"digest": "0123456789abcdef", "size": 2000, "last-used": "2026-10-01T10:00:00Z"},
+			{"digest": "fedcba9876543210", "size": 5000, "last-used": "2026-10-02T10:00:00Z", "referenced": true, "snaps": ["bar_3", "foo_1"]},
+			{"digest": "abc", "size": 100, "last-used": "2026-10-03T10:00:00Z", "referenced": true}
+		]}`)
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"cache", "list", "--abs-time"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `
+Digest        Size    Last used             Used by
+0123456789ab   2000B  2026-10-01T10:00:00Z  -
+fedcba987654   5000B  2026-10-02T10:00:00Z  bar_3,foo_1
+abc             100B  2026-10-03T10:00:00Z  other
+
+Total 7.10kB, 2000B can be reclaimed with 'snap cache clean'.
+`[1:])
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestCacheListEmpty(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": []}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"cache", "list"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "The snap download cache is empty.\n")
+}
+
+func (s *SnapSuite) TestCacheClean(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "POST")
+			c.Check(r.URL.Path, check.Equals, "/v2/cache")
+			c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+				"action":     "clean",
+				"older-than": "720h0m0s",
+				"max-size":   json.Number("500000000"),
+			})
+			fmt.Fprintln(w, `{"type": "sync", "result": {"removed": [
+				{"digest": "abc", "size": 2000, "last-used": "2026-10-01T10:00:00Z"},
+				{"digest": "def", "size": 3000, "last-used": "2026-10-01T10:00:00Z"}
+			], "reclaimed": 5000}}`)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"cache", "clean", "--older-than=30d", "--max-size=500MB"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, "Removed 2 cache entries, reclaimed 5000B.\n")
+}
+
+func (s *SnapSuite) TestCacheCleanNothing(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+			"action": "clean",
+		})
+		fmt.Fprintln(w, `{"type": "sync", "result": {"removed": [], "reclaimed": 0}}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"cache", "clean"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "No cache entries removed.\n")
+}
+
+func (s *SnapSuite) TestCacheCleanInvalidOptions(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Fatalf("unexpected request")
+	})
+	for _, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--older-than=soon"}, `invalid duration "soon" for --older-than`},
+		{[]string{"--older-than=-1h"}, `invalid duration "-1h" for --older-than`},
+		{[]string{"--max-size=lots"}, `cannot parse --max-size: .*`},
+		{[]string{"--max-size=0B"}, `--max-size must be positive`},
+	} {
+		_, err := snap.Parser(snap.Client()).ParseArgs(append([]string{"cache", "clean"}, t.args...))
+		c.Check(err, check.ErrorMatches, t.err, check.Commentf("%v", t.args))
+	}
+}
@@ -0,0 +1,148 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/strutil"
+)
+
+type cmdCohort struct{}
+
+var shortCohortHelp = i18n.G("Manage snap cohorts")
+var longCohortHelp = i18n.G(`
+The cohort command contains sub-commands to manage the cohorts of snaps.
+
+A cohort fixes the revisions of a snap across the systems that are members of
+it, which makes it possible to stage the rollout of new revisions.
+`)
+
+var shortCohortCreateHelp = i18n.G("Create cohort keys for snaps")
+var longCohortCreateHelp = i18n.G(`
+The create command creates a cohort key for each of the given snaps, which
+can then be used to join the cohort on other systems.
+`)
+
+var shortCohortJoinHelp = i18n.G("Make installed snaps members of a cohort")
+var longCohortJoinHelp = i18n.G(`
+The join command makes the given snaps members of a cohort. Unless a cohort
+key is specified, a new cohort is created for each snap.
+
+The following refreshes of the snaps use the revisions of their cohort.
+`)
+
+var shortCohortLeaveHelp = i18n.G("Make installed snaps leave their cohort")
+var longCohortLeaveHelp = i18n.G(`
+The leave command makes the given snaps leave the cohorts they are members of.
+
+The following refreshes of the snaps use the latest revisions of their
+channel.
+`)
+
+type cmdCohortJoin struct {
+	waitMixin
+
+	CohortKey string `long:"cohort-key"`
+
+	Positional struct {
+		Snaps []installedSnapName `positional-arg-name:"<snap>" required:"1"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+type cmdCohortLeave struct {
+	waitMixin
+
+	Positional struct {
+		Snaps []installedSnapName `positional-arg-name:"<snap>" required:"1"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func init() {
+	addSubCommand(&cohortCommands, "create", shortCohortCreateHelp, longCohortCreateHelp, func() flags.Commander {
+		return &cmdCreateCohort{}
+	}, nil, nil)
+	addSubCommand(&cohortCommands, "join", shortCohortJoinHelp, longCohortJoinHelp, func() flags.Commander {
+		return &cmdCohortJoin{}
+	}, waitDescs.also(map[string]string{
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"cohort-key": i18n.G("Join the cohort with the given key instead of creating one"),
+	}), nil)
+	addSubCommand(&cohortCommands, "leave", shortCohortLeaveHelp, longCohortLeaveHelp, func() flags.Commander {
+		return &cmdCohortLeave{}
+	}, waitDescs, nil)
+}
+
+func (x *cmdCohortJoin) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	names := installedSnapNames(x.Positional.Snaps)
+	if x.CohortKey != "" && len(names) != 1 {
+		return fmt.Errorf(i18n.G("cannot use --cohort-key with more than one snap"))
+	}
+
+	changeID, err := x.client.JoinCohort(names, x.CohortKey)
+	if err != nil {
+		return err
+	}
+	return x.showCohortChange(changeID, names, "join")
+}
+
+func (x *cmdCohortLeave) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	names := installedSnapNames(x.Positional.Snaps)
+	changeID, err := x.client.LeaveCohort(names)
+	if err != nil {
+		return err
+	}
+	return x.showCohortChange(changeID, names, "leave")
+}
+
+func (x *waitMixin) showCohortChange(changeID string, names []string, op string) error {
+	if _, err := x.wait(changeID); err != nil {
+		if err == noWait {
+			return nil
+		}
+		return err
+	}
+
+	snaps, err := x.client.List(names, nil)
+	if err != nil {
+		return err
+	}
+	for _, snap := range snaps {
+		if op == "join" {
+			// TRANSLATORS: the first %q will be the (quoted) snap name, the second an ellipted cohort string
+			fmt.Fprintf(Stdout, i18n.G("%q joined the %q cohort\n"), snap.Name, strutil.ElliptLeft(snap.CohortKey, 10))
+		} else {
+			// TRANSLATORS: the %q will be the (quoted) snap name
+			fmt.Fprintf(Stdout, i18n.G("%q left the cohort\n"), snap.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapOpSuite) mockCohortServer(c *check.C, expectedBody map[string]interface{}, cohortKey string) *int {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "POST")
+			c.Check(r.URL.Path, check.Equals, "/v2/cohorts")
+			c.Check(DecodedRequestBody(c, r), check.DeepEquals, expectedBody)
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type":"async", "change": "42", "status-code": 202}`)
+		case 1:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/changes/42")
+			fmt.Fprintln(w, `{"type": "sync", "result": {"ready": true, "status": "Done"}}`)
+		case 2:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/snaps")
+			c.Check(r.URL.Query().Get("snaps"), check.Equals, "foo,bar")
+			fmt.Fprintf(w, `{"type": "sync", "result": [{"name": "foo", "status": "active", "revision": 42, "cohort-key": %[1]q}, {"name": "bar", "status": "active", "revision": 7, "cohort-key": %[1]q}]}`+"\n", cohortKey)
+		default:
+			c.Fatalf("expected to get 3 requests, now on %d", n+1)
+		}
+		n++
+	})
+	return &n
+}
+
+func (s *SnapOpSuite) TestCohortJoin(c *check.C) {
+	n := s.mockCohortServer(c, map[string]interface{}{
+		"action": "join",
+		"snaps":  []interface{}{"foo", "bar"},
+	}, "what")
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"cohort", "join", "foo", "bar"})
+	c.Assert(err, check.IsNil)
+	c.Check(rest, check.HasLen, 0)
+	c.Check(s.Stdout(), check.Equals, `"foo" joined the "what" cohort
+"bar" joined the "what" cohort
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(*n, check.Equals, 3)
+}
+
+func (s *SnapOpSuite) TestCohortJoinWithKey(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, check.Equals, "POST")
+		c.Check(r.URL.Path, check.Equals, "/v2/cohorts")
+		c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+			"action":     "join",
+			"snaps":      []interface{}{"foo"},
+			"cohort-key": "what",
+		})
+		w.WriteHeader(202)
+		fmt.Fprintln(w, `{"type":"async", "change": "42", "status-code": 202}`)
+		n++
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"cohort", "join", "--no-wait", "--cohort-key=what", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Check(rest, check.HasLen, 0)
+	c.Check(s.Stdout(), check.Equals, "42\n")
+	c.Check(n, check.Equals, 1)
+}
+
+func (s *SnapOpSuite) TestCohortJoinWithKeyManySnaps(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Fatalf("unexpected request")
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"cohort", "join", "--cohort-key=what", "foo", "bar"})
+	c.Check(err, check.ErrorMatches, "cannot use --cohort-key with more than one snap")
+}
+
+func (s *SnapOpSuite) TestCohortLeave(c *check.C) {
+	n := s.mockCohortServer(c, map[string]interface{}{
+		"action": "leave",
+		"snaps":  []interface{}{"foo", "bar"},
+	}, "")
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"cohort", "leave", "foo", "bar"})
+	c.Assert(err, check.IsNil)
+	c.Check(rest, check.HasLen, 0)
+	c.Check(s.Stdout(), check.Equals, `"foo" left the cohort
+"bar" left the cohort
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(*n, check.Equals, 3)
+}
+
+func (s *SnapOpSuite) TestCohortLeaveNoSnaps(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"cohort", "leave"})
+	c.Check(err, check.ErrorMatches, "the required argument .* was not provided")
+}
+
+func (s *SnapOpSuite) TestCohortCreate(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, check.Equals, "/v2/cohorts")
+		fmt.Fprintln(w, `{"type": "sync", "status-code": 200, "status": "OK", "result": {"foo": "what"}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"cohort", "create", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "cohorts:\n  foo:\n    cohort-key: what\n")
+}
@@ -0,0 +1,66 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdConfinementReport struct {
+	clientMixin
+}
+
+var shortConfinementReportHelp = i18n.G("Report the confinement of installed snaps")
+var longConfinementReportHelp = i18n.G(`
+The confinement-report command prints, in JSON format, a summary of the
+confinement of every installed snap for auditing purposes: the confinement
+declared by the snap, the flags relaxing it (like devmode or classic),
+whether the snap was installed without assertions, and the manual
+connections of super-privileged interfaces, those that snaps can only use
+if granted by their snap-declaration.
+`)
+
+func init() {
+	addCommand("confinement-report", shortConfinementReportHelp, longConfinementReportHelp, func() flags.Commander {
+		return &cmdConfinementReport{}
+	}, nil, nil)
+}
+
+func (x *cmdConfinementReport) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	report, err := x.client.ConfinementReport()
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, "%s\n", out)
+	return nil
+}
@@ -0,0 +1,91 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) TestConfinementReport(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/confinement-report")
+			fmt.Fprintln(w, `{"type": "sync", "result": {"snaps": [
+{"name": "baz", "revision": "x1", "confinement": "devmode", "flags": ["devmode"], "unasserted": true},
+{"name": "foo", "revision": "10", "confinement": "strict", "super-privileged-connections": [
+  {"interface": "snapd-control", "plug": {"snap": "foo", "plug": "snapd-control"}, "slot": {"snap": "core", "slot": "snapd-control"}}
+]}
+]}}`)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"confinement-report"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `{
+  "snaps": [
+    {
+      "name": "baz",
+      "revision": "x1",
+      "confinement": "devmode",
+      "flags": [
+        "devmode"
+      ],
+      "unasserted": true
+    },
+    {
+      "name": "foo",
+      "revision": "10",
+      "confinement": "strict",
+      "super-privileged-connections": [
+        {
+          "interface": "snapd-control",
+          "plug": {
+            "snap": "foo",
+            "plug": "snapd-control"
+          },
+          "slot": {
+            "snap": "core",
+            "slot": "snapd-control"
+          }
+        }
+      ]
+    }
+  ]
+}
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestConfinementReportExtraArgs(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"confinement-report", "foo"})
+	c.Assert(err, check.Equals, snap.ErrExtraArgs)
+}
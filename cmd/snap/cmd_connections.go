@@ -33,6 +33,7 @@ import (
 type cmdConnections struct {
 	clientMixin
 	All         bool `long:"all"`
+	Why         bool `long:"why"`
 	Positionals struct {
 		Snap installedSnapName
 	} `positional-args:"true"`
@@ -47,10 +48,28 @@ Unless <snap> is provided, the listing is for connected plugs and
 slots for all snaps in the system. In this mode, pass --all to also
 list unconnected plugs and slots.
 
+With --why, the listing explains how each connection was established. When
+several slots could satisfy a plug that connects to only one, the slot is
+chosen automatically by comparing, in order, whether the slot snap is the
+default-provider of the plug, the gadget of the device, from the same
+publisher as the plug snap, or from the brand of the device. No connection
+is made if that does not single out one slot.
+
 $ snap connections <snap>
 
 Lists connected and unconnected plugs and slots for the specified
 snap.
+
+$ snap connections export [<file>]
+
+Writes the manual connections and disconnections in the system as a
+profile document, to the given file or to standard output.
+
+$ snap connections apply <file>
+
+Establishes the connections and disconnections listed in a profile
+document, reporting the result for each entry. Entries that already
+match the system state are left unchanged.
 `)
 
 func init() {
@@ -58,6 +77,8 @@ func init() {
 		return &cmdConnections{}
 	}, map[string]string{
 		"all": i18n.G("Show connected and unconnected plugs and slots"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"why": i18n.G("Explain how connections were established"),
 	}, []argDesc{{
 		// TRANSLATORS: This needs to be wrapped in <>s.
 		name: "<snap>",
@@ -84,6 +105,7 @@ type connection struct {
 	interfaceDeterminant string
 	manual               bool
 	gadget               bool
+	autoReason           string
 }
 
 func (cn connection) String() string {
@@ -100,6 +122,21 @@ func (cn connection) String() string {
 	return strings.Join(opts, ",")
 }
 
+// why explains how the connection was established.
+func (cn connection) why() string {
+	switch {
+	case cn.plug == "-" || cn.slot == "-":
+		return "-"
+	case cn.manual:
+		return i18n.G("connected manually")
+	case cn.gadget:
+		return i18n.G("connected by the gadget")
+	case cn.autoReason != "":
+		return fmt.Sprintf(i18n.G("auto-connected: %s"), cn.autoReason)
+	}
+	return i18n.G("auto-connected")
+}
+
 type byConnectionData []connection
 
 func (b byConnectionData) Len() int      { return len(b) }
@@ -132,6 +169,20 @@ func interfaceDeterminant(conn *client.Connection) string {
 }
 
 func (x *cmdConnections) Execute(args []string) error {
+	switch x.Positionals.Snap {
+	case "export", "apply":
+		if x.All {
+			return fmt.Errorf(i18n.G("cannot use --all with %s"), x.Positionals.Snap)
+		}
+		if x.Why {
+			return fmt.Errorf(i18n.G("cannot use --why with %s"), x.Positionals.Snap)
+		}
+		if x.Positionals.Snap == "export" {
+			return x.exportProfile(args)
+		}
+		return x.applyProfile(args)
+	}
+
 	if len(args) > 0 {
 		return ErrExtraArgs
 	}
@@ -169,13 +220,18 @@ func (x *cmdConnections) Execute(args []string) error {
 			slot:                 endpoint(conn.Slot.Snap, conn.Slot.Name),
 			manual:               conn.Manual,
 			gadget:               conn.Gadget,
+			autoReason:           conn.AutoReason,
 			interfaceName:        conn.Interface,
 			interfaceDeterminant: interfaceDeterminant(&conn),
 		})
 	}
 
 	w := tabWriter()
-	fmt.Fprintln(w, i18n.G("Interface\tPlug\tSlot\tNotes"))
+	if x.Why {
+		fmt.Fprintln(w, i18n.G("Interface\tPlug\tSlot\tNotes\tWhy"))
+	} else {
+		fmt.Fprintln(w, i18n.G("Interface\tPlug\tSlot\tNotes"))
+	}
 
 	for _, plug := range connections.Plugs {
 		if len(plug.Connections) == 0 && x.All {
@@ -204,6 +260,10 @@ func (x *cmdConnections) Execute(args []string) error {
 	sort.Sort(byConnectionData(annotatedConns))
 
 	for _, note := range annotatedConns {
+		if x.Why {
+			fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\t%s\n", note.interfaceName, note.interfaceDeterminant, note.plug, note.slot, note, note.why())
+			continue
+		}
 		fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\n", note.interfaceName, note.interfaceDeterminant, note.plug, note.slot, note)
 	}
 
@@ -0,0 +1,182 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+)
+
+// connectionsProfile is the declarative document written by
+// "snap connections export" and consumed by "snap connections apply".
+type connectionsProfile struct {
+	Connections    []profileEntry `yaml:"connections,omitempty"`
+	Disconnections []profileEntry `yaml:"disconnections,omitempty"`
+}
+
+type profileEntry struct {
+	Plug string `yaml:"plug"`
+	Slot string `yaml:"slot"`
+}
+
+func (e profileEntry) parse() (plug, slot SnapAndNameStrict, err error) {
+	if err := plug.UnmarshalFlag(e.Plug); err != nil {
+		return plug, slot, fmt.Errorf(i18n.G("invalid plug in profile: %v"), err)
+	}
+	if err := slot.UnmarshalFlag(e.Slot); err != nil {
+		return plug, slot, fmt.Errorf(i18n.G("invalid slot in profile: %v"), err)
+	}
+	return plug, slot, nil
+}
+
+func connectionKey(plugSnap, plugName, slotSnap, slotName string) string {
+	return endpoint(plugSnap, plugName) + " " + endpoint(slotSnap, slotName)
+}
+
+func (x *cmdConnections) allConnections() (client.Connections, error) {
+	return x.client.Connections(&client.ConnectionOptions{All: true})
+}
+
+func (x *cmdConnections) exportProfile(args []string) error {
+	if len(args) > 1 {
+		return ErrExtraArgs
+	}
+
+	conns, err := x.allConnections()
+	if err != nil {
+		return err
+	}
+
+	var profile connectionsProfile
+	for _, conn := range conns.Established {
+		if !conn.Manual {
+			continue
+		}
+		profile.Connections = append(profile.Connections, profileEntry{
+			Plug: endpoint(conn.Plug.Snap, conn.Plug.Name),
+			Slot: endpoint(conn.Slot.Snap, conn.Slot.Name),
+		})
+	}
+	for _, conn := range conns.Undesired {
+		profile.Disconnections = append(profile.Disconnections, profileEntry{
+			Plug: endpoint(conn.Plug.Snap, conn.Plug.Name),
+			Slot: endpoint(conn.Slot.Snap, conn.Slot.Name),
+		})
+	}
+
+	out, err := yaml.Marshal(&profile)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 || args[0] == "-" {
+		_, err = Stdout.Write(out)
+		return err
+	}
+	return os.WriteFile(args[0], out, 0644)
+}
+
+func (x *cmdConnections) applyProfile(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(i18n.G("the profile to apply must be provided"))
+	}
+	if len(args) > 1 {
+		return ErrExtraArgs
+	}
+
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf(i18n.G("cannot read profile: %v"), err)
+	}
+	var profile connectionsProfile
+	if err := yaml.UnmarshalStrict(data, &profile); err != nil {
+		return fmt.Errorf(i18n.G("cannot parse profile: %v"), err)
+	}
+	// validate the whole document before changing anything
+	for _, entries := range [][]profileEntry{profile.Connections, profile.Disconnections} {
+		for _, e := range entries {
+			if _, _, err := e.parse(); err != nil {
+				return err
+			}
+		}
+	}
+
+	conns, err := x.allConnections()
+	if err != nil {
+		return err
+	}
+	established := make(map[string]bool, len(conns.Established))
+	for _, conn := range conns.Established {
+		established[connectionKey(conn.Plug.Snap, conn.Plug.Name, conn.Slot.Snap, conn.Slot.Name)] = true
+	}
+
+	wmx := waitMixin{clientMixin: x.clientMixin}
+	failed := 0
+	w := tabWriter()
+	fmt.Fprintln(w, i18n.G("Plug\tSlot\tResult"))
+	report := func(e profileEntry, result string, err error) {
+		if err != nil {
+			failed++
+			result = fmt.Sprintf(i18n.G("error: %v"), err)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.Plug, e.Slot, result)
+	}
+
+	for _, e := range profile.Connections {
+		plug, slot, _ := e.parse()
+		if established[connectionKey(plug.Snap, plug.Name, slot.Snap, slot.Name)] {
+			report(e, i18n.G("unchanged"), nil)
+			continue
+		}
+		id, err := x.client.Connect(plug.Snap, plug.Name, slot.Snap, slot.Name)
+		if err == nil {
+			_, err = wmx.wait(id)
+		}
+		report(e, i18n.G("connected"), err)
+	}
+	for _, e := range profile.Disconnections {
+		plug, slot, _ := e.parse()
+		if !established[connectionKey(plug.Snap, plug.Name, slot.Snap, slot.Name)] {
+			report(e, i18n.G("unchanged"), nil)
+			continue
+		}
+		id, err := x.client.Disconnect(plug.Snap, plug.Name, slot.Snap, slot.Name, nil)
+		if err == nil {
+			_, err = wmx.wait(id)
+		}
+		report(e, i18n.G("disconnected"), err)
+	}
+	w.Flush()
+
+	if failed > 0 {
+		return fmt.Errorf(i18n.NG("cannot apply %d entry of the profile", "cannot apply %d entries of the profile", failed), failed)
+	}
+	return nil
+}
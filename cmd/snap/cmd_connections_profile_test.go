@@ -0,0 +1,202 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	. "github.com/snapcore/snapd/cmd/snap"
+)
+
+var profileConnections = client.Connections{
+	Established: []client.Connection{
+		{
+			Plug:      client.PlugRef{Snap: "foo", Name: "camera"},
+			Slot:      client.SlotRef{Snap: "core", Name: "camera"},
+			Interface: "camera",
+			Manual:    true,
+		}, {
+			Plug:      client.PlugRef{Snap: "foo", Name: "network"},
+			Slot:      client.SlotRef{Snap: "core", Name: "network"},
+			Interface: "network",
+		}, {
+			Plug:      client.PlugRef{Snap: "foo", Name: "home"},
+			Slot:      client.SlotRef{Snap: "core", Name: "home"},
+			Interface: "home",
+			Manual:    true,
+		},
+	},
+	Undesired: []client.Connection{
+		{
+			Plug:      client.PlugRef{Snap: "foo", Name: "audio-record"},
+			Slot:      client.SlotRef{Snap: "core", Name: "audio-record"},
+			Interface: "audio-record",
+		},
+	},
+}
+
+func (s *SnapSuite) mockConnectionsForProfile(c *C, actions *[]map[string]interface{}) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/connections":
+			c.Check(r.Method, Equals, "GET")
+			c.Check(r.URL.Query().Get("select"), Equals, "all")
+			EncodeResponseBody(c, w, map[string]interface{}{
+				"type":   "sync",
+				"result": profileConnections,
+			})
+		case "/v2/interfaces":
+			c.Check(r.Method, Equals, "POST")
+			var body map[string]interface{}
+			c.Assert(json.NewDecoder(r.Body).Decode(&body), IsNil)
+			*actions = append(*actions, body)
+			plug := body["plugs"].([]interface{})[0].(map[string]interface{})
+			if plug["plug"] == "broken" {
+				w.WriteHeader(400)
+				fmt.Fprintln(w, `{"type":"error", "status-code": 400, "result": {"message": "cannot connect"}}`)
+				return
+			}
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type":"async", "status-code": 202, "change": "zzz"}`)
+		case "/v2/changes/zzz":
+			c.Check(r.Method, Equals, "GET")
+			fmt.Fprintln(w, `{"type":"sync", "result":{"ready": true, "status": "Done"}}`)
+		default:
+			c.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	})
+}
+
+func (s *SnapSuite) TestConnectionsExport(c *C) {
+	var actions []map[string]interface{}
+	s.mockConnectionsForProfile(c, &actions)
+
+	rest, err := Parser(Client()).ParseArgs([]string{"connections", "export"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, HasLen, 0)
+	c.Check(s.Stdout(), Equals, `connections:
+- plug: foo:camera
+  slot: :camera
+- plug: foo:home
+  slot: :home
+disconnections:
+- plug: foo:audio-record
+  slot: :audio-record
+`)
+	c.Check(s.Stderr(), Equals, "")
+	c.Check(actions, HasLen, 0)
+}
+
+func (s *SnapSuite) TestConnectionsExportToFile(c *C) {
+	var actions []map[string]interface{}
+	s.mockConnectionsForProfile(c, &actions)
+
+	fn := filepath.Join(c.MkDir(), "profile.yaml")
+	_, err := Parser(Client()).ParseArgs([]string{"connections", "export", fn})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "")
+	data, err := os.ReadFile(fn)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Matches, `(?s)connections:\n- plug: foo:camera\n.*`)
+}
+
+func (s *SnapSuite) TestConnectionsApply(c *C) {
+	var actions []map[string]interface{}
+	s.mockConnectionsForProfile(c, &actions)
+
+	fn := filepath.Join(c.MkDir(), "profile.yaml")
+	err := os.WriteFile(fn, []byte(`connections:
+- plug: foo:camera
+  slot: :camera
+- plug: bar:camera
+  slot: :camera
+disconnections:
+- plug: foo:home
+  slot: :home
+- plug: foo:audio-record
+  slot: :audio-record
+`), 0644)
+	c.Assert(err, IsNil)
+
+	_, err = Parser(Client()).ParseArgs([]string{"connections", "apply", fn})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, `Plug              Slot           Result
+foo:camera        :camera        unchanged
+bar:camera        :camera        connected
+foo:home          :home          disconnected
+foo:audio-record  :audio-record  unchanged
+`)
+	c.Assert(actions, HasLen, 2)
+	c.Check(actions[0]["action"], Equals, "connect")
+	c.Check(actions[0]["plugs"], DeepEquals, []interface{}{map[string]interface{}{"snap": "bar", "plug": "camera"}})
+	c.Check(actions[0]["slots"], DeepEquals, []interface{}{map[string]interface{}{"snap": "", "slot": "camera"}})
+	c.Check(actions[1]["action"], Equals, "disconnect")
+	c.Check(actions[1]["plugs"], DeepEquals, []interface{}{map[string]interface{}{"snap": "foo", "plug": "home"}})
+}
+
+func (s *SnapSuite) TestConnectionsApplyReportsErrors(c *C) {
+	var actions []map[string]interface{}
+	s.mockConnectionsForProfile(c, &actions)
+
+	fn := filepath.Join(c.MkDir(), "profile.yaml")
+	err := os.WriteFile(fn, []byte(`connections:
+- plug: bar:broken
+  slot: :camera
+- plug: bar:camera
+  slot: :camera
+`), 0644)
+	c.Assert(err, IsNil)
+
+	_, err = Parser(Client()).ParseArgs([]string{"connections", "apply", fn})
+	c.Assert(err, ErrorMatches, `cannot apply 1 entry of the profile`)
+	c.Check(s.Stdout(), Equals, `Plug        Slot     Result
+bar:broken  :camera  error: cannot connect
+bar:camera  :camera  connected
+`)
+	c.Check(actions, HasLen, 2)
+}
+
+func (s *SnapSuite) TestConnectionsApplyInvalidProfile(c *C) {
+	var actions []map[string]interface{}
+	s.mockConnectionsForProfile(c, &actions)
+
+	fn := filepath.Join(c.MkDir(), "profile.yaml")
+	err := os.WriteFile(fn, []byte(`connections:
+- plug: bar
+  slot: :camera
+`), 0644)
+	c.Assert(err, IsNil)
+
+	_, err = Parser(Client()).ParseArgs([]string{"connections", "apply", fn})
+	c.Assert(err, ErrorMatches, `invalid plug in profile: invalid value: "bar" \(want snap:name or :name\)`)
+	c.Check(actions, HasLen, 0)
+
+	_, err = Parser(Client()).ParseArgs([]string{"connections", "apply"})
+	c.Assert(err, ErrorMatches, `the profile to apply must be provided`)
+
+	_, err = Parser(Client()).ParseArgs([]string{"connections", "--all", "export"})
+	c.Assert(err, ErrorMatches, `cannot use --all with export`)
+}
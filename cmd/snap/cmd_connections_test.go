@@ -277,6 +277,75 @@ func (s *SnapSuite) TestConnectionsSomeConnected(c *C) {
 	c.Assert(s.Stderr(), Equals, "")
 }
 
+func (s *SnapSuite) TestConnectionsWhy(c *C) {
+	result := client.Connections{
+		Established: []client.Connection{
+			{
+				Plug:      client.PlugRef{Snap: "keyboard-lights", Name: "capslock"},
+				Slot:      client.SlotRef{Snap: "leds-provider", Name: "capslock-led"},
+				Interface: "leds",
+				Gadget:    true,
+			}, {
+				Plug:      client.PlugRef{Snap: "keyboard-lights", Name: "numlock"},
+				Slot:      client.SlotRef{Snap: "core", Name: "numlock-led"},
+				Interface: "leds",
+				Manual:    true,
+			}, {
+				Plug:      client.PlugRef{Snap: "keyboard-lights", Name: "scrollock"},
+				Slot:      client.SlotRef{Snap: "core", Name: "scrollock-led"},
+				Interface: "leds",
+			}, {
+				Plug:       client.PlugRef{Snap: "keyboard-lights", Name: "themes"},
+				Slot:       client.SlotRef{Snap: "gtk-themes", Name: "themes"},
+				Interface:  "content",
+				AutoReason: "default-provider, preferred over other-themes:themes",
+			},
+		},
+		Plugs: []client.Plug{
+			{
+				Snap:        "keyboard-lights",
+				Name:        "capslock",
+				Interface:   "leds",
+				Connections: []client.SlotRef{{Snap: "leds-provider", Name: "capslock-led"}},
+			}, {
+				Snap:        "keyboard-lights",
+				Name:        "numlock",
+				Interface:   "leds",
+				Connections: []client.SlotRef{{Snap: "core", Name: "numlock-led"}},
+			}, {
+				Snap:        "keyboard-lights",
+				Name:        "scrollock",
+				Interface:   "leds",
+				Connections: []client.SlotRef{{Snap: "core", Name: "scrollock-led"}},
+			}, {
+				Snap:        "keyboard-lights",
+				Name:        "themes",
+				Interface:   "content",
+				Connections: []client.SlotRef{{Snap: "gtk-themes", Name: "themes"}},
+			},
+		},
+	}
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/connections")
+		EncodeResponseBody(c, w, map[string]interface{}{
+			"type":   "sync",
+			"result": result,
+		})
+	})
+	rest, err := Parser(Client()).ParseArgs([]string{"connections", "--why"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	expectedStdout := "" +
+		"Interface  Plug                       Slot                        Notes   Why\n" +
+		"content    keyboard-lights:themes     gtk-themes:themes           -       auto-connected: default-provider, preferred over other-themes:themes\n" +
+		"leds       keyboard-lights:capslock   leds-provider:capslock-led  gadget  connected by the gadget\n" +
+		"leds       keyboard-lights:numlock    :numlock-led                manual  connected manually\n" +
+		"leds       keyboard-lights:scrollock  :scrollock-led              -       auto-connected\n"
+	c.Assert(s.Stdout(), Equals, expectedStdout)
+	c.Assert(s.Stderr(), Equals, "")
+}
+
 func (s *SnapSuite) TestConnectionsSomeDisconnected(c *C) {
 	result := client.Connections{
 		Established: []client.Connection{
@@ -32,6 +32,7 @@ import (
 type cmdBootvarsGet struct {
 	UC20    bool   `long:"uc20"`
 	RootDir string `long:"root-dir"`
+	Cmdline bool   `long:"cmdline"`
 }
 
 type cmdBootvarsSet struct {
@@ -51,6 +52,7 @@ func init() {
 		}, map[string]string{
 			"uc20":     i18n.G("Whether to use UC20+ boot vars or not"),
 			"root-dir": i18n.G("Root directory to look for boot variables in"),
+			"cmdline":  i18n.G("Show the sources of the arguments of the current kernel command line"),
 		}, nil)
 
 	cmdSet := addDebugCommand("set-boot-vars",
@@ -73,6 +75,12 @@ func (x *cmdBootvarsGet) Execute(args []string) error {
 	if release.OnClassic {
 		return errors.New(`the "boot-vars" command is not available on classic systems`)
 	}
+	if x.Cmdline {
+		if x.RootDir != "" || x.UC20 {
+			return errors.New(`cannot use --cmdline with --root-dir or --uc20`)
+		}
+		return boot.DebugDumpKernelCommandLine(Stdout)
+	}
 	return boot.DebugDumpBootVars(Stdout, x.RootDir, x.UC20)
 }
 
@@ -20,12 +20,17 @@
 package main_test
 
 import (
+	"os"
+	"path/filepath"
+
 	"gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/boot"
 	"github.com/snapcore/snapd/bootloader"
 	"github.com/snapcore/snapd/bootloader/bootloadertest"
 	snap "github.com/snapcore/snapd/cmd/snap"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil/kcmdline"
 	"github.com/snapcore/snapd/release"
 )
 
@@ -155,3 +160,36 @@ snapd_full_cmdline_args=
 	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"debug", "set-boot-vars", "--recovery", "--root-dir", boot.InitramfsUbuntuBootDir, "foo=recovery"})
 	c.Assert(err, check.ErrorMatches, "cannot use run bootloader root-dir with a recovery flag")
 }
+
+func (s *SnapSuite) TestDebugBootvarsCmdline(c *check.C) {
+	restore := release.MockOnClassic(false)
+	defer restore()
+	mockCmdline := filepath.Join(c.MkDir(), "cmdline")
+	err := os.WriteFile(mockCmdline, []byte("snapd_recovery_mode=run panic=-1 quiet init=/bin/sh"), 0644)
+	c.Assert(err, check.IsNil)
+	defer kcmdline.MockProcCmdline(mockCmdline)()
+
+	provenanceFile := dirs.KernelCmdlineProvenanceFileUnder(dirs.GlobalRootDir)
+	c.Assert(os.MkdirAll(filepath.Dir(provenanceFile), 0755), check.IsNil)
+	err = os.WriteFile(provenanceFile, []byte(`{"bootloader":"panic=-1","gadget":"quiet"}`), 0644)
+	c.Assert(err, check.IsNil)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "boot-vars", "--cmdline"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.HasLen, 0)
+	c.Check(s.Stdout(), check.Equals, `
+Argument                 Source
+snapd_recovery_mode=run  snapd
+panic=-1                 bootloader
+quiet                    gadget
+init=/bin/sh             unknown
+`[1:])
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestDebugBootvarsCmdlineWithRootDir(c *check.C) {
+	restore := release.MockOnClassic(false)
+	defer restore()
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "boot-vars", "--cmdline", "--root-dir", "/foo"})
+	c.Assert(err, check.ErrorMatches, `cannot use --cmdline with --root-dir or --uc20`)
+}
@@ -0,0 +1,100 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+)
+
+type cmdDebugCloudInit struct {
+	clientMixin
+}
+
+func init() {
+	cmd := addDebugCommand("cloud-init",
+		"(internal) obtain cloud-init status and filtering details",
+		"(internal) obtain cloud-init status and details about the cloud-init config filtered at install time",
+		func() flags.Commander {
+			return &cmdDebugCloudInit{}
+		}, nil, nil)
+	cmd.hidden = true
+}
+
+func (x *cmdDebugCloudInit) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	var resp struct {
+		Status      string `json:"status"`
+		StatusError string `json:"status-error,omitempty"`
+		Filtered    *struct {
+			AllowedDatasources []string `json:"allowed-datasources"`
+			AllowedModules     []string `json:"allowed-modules,omitempty"`
+			Files              []struct {
+				Name               string   `json:"name"`
+				DroppedDatasources []string `json:"dropped-datasources,omitempty"`
+				DroppedKeys        []string `json:"dropped-keys,omitempty"`
+				Skipped            bool     `json:"skipped,omitempty"`
+			} `json:"files,omitempty"`
+		} `json:"filtered,omitempty"`
+	}
+	if err := x.client.DebugGet("cloud-init", &resp, nil); err != nil {
+		return err
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintf(w, "status:\t%s\n", resp.Status)
+	if resp.StatusError != "" {
+		fmt.Fprintf(w, "status-error:\t%s\n", resp.StatusError)
+	}
+	if resp.Filtered == nil {
+		fmt.Fprintf(w, "filtered:\t-\n")
+		return nil
+	}
+	fmt.Fprintf(w, "allowed-datasources:\t%s\n", strings.Join(resp.Filtered.AllowedDatasources, ", "))
+	if len(resp.Filtered.AllowedModules) != 0 {
+		fmt.Fprintf(w, "allowed-modules:\t%s\n", strings.Join(resp.Filtered.AllowedModules, ", "))
+	}
+	if len(resp.Filtered.Files) == 0 {
+		fmt.Fprintf(w, "filtered:\t-\n")
+		return nil
+	}
+	fmt.Fprintf(w, "filtered:\n")
+	for _, f := range resp.Filtered.Files {
+		var details []string
+		if f.Skipped {
+			details = append(details, "not installed")
+		}
+		if len(f.DroppedDatasources) != 0 {
+			details = append(details, "dropped datasources: "+strings.Join(f.DroppedDatasources, ", "))
+		}
+		if len(f.DroppedKeys) != 0 {
+			details = append(details, "dropped keys: "+strings.Join(f.DroppedKeys, ", "))
+		}
+		fmt.Fprintf(w, "  %s:\t%s\n", f.Name, strings.Join(details, "; "))
+	}
+
+	return nil
+}
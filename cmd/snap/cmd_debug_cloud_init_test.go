@@ -0,0 +1,79 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) TestDebugCloudInit(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/debug")
+			c.Check(r.URL.RawQuery, check.Equals, "aspect=cloud-init")
+			fmt.Fprintln(w, `{"type": "sync", "result": {
+"status": "restricted",
+"filtered": {
+  "allowed-datasources": ["MAAS"],
+  "allowed-modules": ["users"],
+  "files": [
+    {"name": "50-foo.cfg", "dropped-datasources": ["GCE"], "dropped-keys": ["runcmd"]},
+    {"name": "60-bar.cfg", "dropped-keys": ["snappy"], "skipped": true}
+  ]
+}}}`)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "cloud-init"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `status:               restricted
+allowed-datasources:  MAAS
+allowed-modules:      users
+filtered:
+  50-foo.cfg:  dropped datasources: GCE; dropped keys: runcmd
+  60-bar.cfg:  not installed; dropped keys: snappy
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(n, check.Equals, 1)
+}
+
+func (s *SnapSuite) TestDebugCloudInitNothingFiltered(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": {"status": "error", "status-error": "cloud-init errored: boom"}}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "cloud-init"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, `status:        error
+status-error:  cloud-init errored: boom
+filtered:      -
+`)
+}
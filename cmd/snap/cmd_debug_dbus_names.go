@@ -0,0 +1,72 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdDebugDBusNames struct {
+	clientMixin
+}
+
+func init() {
+	addDebugCommand("dbus-names",
+		i18n.G("Show the D-Bus names snaps are activated on"),
+		i18n.G(`
+The dbus-names command shows the D-Bus names the applications of the active
+snaps are activated on, for both the session and the system bus.
+`),
+		func() flags.Commander {
+			return &cmdDebugDBusNames{}
+		}, nil, nil)
+}
+
+func (x *cmdDebugDBusNames) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	var names []struct {
+		Name string `json:"name"`
+		Bus  string `json:"bus"`
+		Snap string `json:"snap"`
+		App  string `json:"app"`
+	}
+	if err := x.client.DebugGet("dbus-names", &names, nil); err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No D-Bus names are provided by snaps."))
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Bus\tName\tSnap\tApp"))
+	for _, n := range names {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", n.Bus, n.Name, n.Snap, n.App)
+	}
+	return nil
+}
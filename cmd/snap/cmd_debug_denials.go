@@ -0,0 +1,97 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdDebugDenials struct {
+	clientMixin
+
+	Positional struct {
+		Snap installedSnapName `positional-arg-name:"<snap>" required:"yes"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func init() {
+	addDebugCommand("denials",
+		i18n.G("Show the confinement denials of a snap"),
+		i18n.G(`
+The denials command shows the AppArmor and seccomp denials of the given snap
+that were logged since the system booted, along with the interfaces that
+would grant the denied access, if any are known.
+`),
+		func() flags.Commander {
+			return &cmdDebugDenials{}
+		}, nil, []argDesc{{
+			// TRANSLATORS: This needs to begin with < and end with >
+			name: i18n.G("<snap>"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			desc: i18n.G("The snap to show the denials of"),
+		}})
+}
+
+type denialSummary struct {
+	Kind        string   `json:"kind"`
+	Operation   string   `json:"operation"`
+	Target      string   `json:"target"`
+	Mask        string   `json:"mask,omitempty"`
+	Count       int      `json:"count"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+func (x *cmdDebugDenials) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	snapName := string(x.Positional.Snap)
+	var summaries []denialSummary
+	if err := x.client.DebugGet("denials", &summaries, map[string]string{"snap": snapName}); err != nil {
+		return err
+	}
+	if len(summaries) == 0 {
+		fmt.Fprintf(Stderr, i18n.G("No denials of snap %q found.\n"), snapName)
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Kind\tOperation\tTarget\tCount\tSuggested interfaces"))
+	for _, s := range summaries {
+		target := s.Target
+		if s.Mask != "" {
+			target += " (" + s.Mask + ")"
+		}
+		suggestions := "-"
+		if len(s.Suggestions) > 0 {
+			suggestions = strings.Join(s.Suggestions, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", s.Kind, s.Operation, target, s.Count, suggestions)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) TestDebugDenials(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/debug")
+			c.Check(r.URL.Query().Get("aspect"), check.Equals, "denials")
+			c.Check(r.URL.Query().Get("snap"), check.Equals, "foo")
+			fmt.Fprintln(w, `{"type": "sync", "result": [
+{"kind": "apparmor", "snap": "foo", "profile": "snap.foo.app", "operation": "open", "target": "/dev/video0", "mask": "r", "count": 3, "suggestions": ["camera"]},
+{"kind": "seccomp", "snap": "foo", "profile": "snap.foo.app", "operation": "syscall", "target": "mount", "count": 1, "suggestions": ["mount-control"]},
+{"kind": "apparmor", "snap": "foo", "profile": "snap.foo.app", "operation": "open", "target": "/etc/shadow", "mask": "r", "count": 1}
+]}`)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "denials", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `Kind      Operation  Target           Count  Suggested interfaces
+apparmor  open       /dev/video0 (r)  3      camera
+seccomp   syscall    mount            1      mount-control
+apparmor  open       /etc/shadow (r)  1      -
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestDebugDenialsNone(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": []}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "denials", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "No denials of snap \"foo\" found.\n")
+}
+
+func (s *SnapSuite) TestDebugDenialsNoSnap(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "denials"})
+	c.Assert(err, check.ErrorMatches, "the required argument `<snap>` was not provided")
+}
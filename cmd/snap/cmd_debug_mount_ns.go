@@ -0,0 +1,144 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdDebugMountNs struct {
+	clientMixin
+
+	Positional struct {
+		Snap installedSnapName `positional-arg-name:"<snap>" required:"yes"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func init() {
+	addDebugCommand("mount-ns",
+		i18n.G("Show the mount namespace of a snap"),
+		i18n.G(`
+The mount-ns command shows the mount table inside the preserved mount
+namespace of the given snap, along with the layouts and content connections
+of the snap and whether they are in effect there.
+`),
+		func() flags.Commander {
+			return &cmdDebugMountNs{}
+		}, nil, []argDesc{{
+			// TRANSLATORS: This needs to begin with < and end with >
+			name: i18n.G("<snap>"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			desc: i18n.G("The snap to show the mount namespace of"),
+		}})
+}
+
+type mountNsInfo struct {
+	Snap      string `json:"snap"`
+	Preserved bool   `json:"preserved"`
+	Mounts    []struct {
+		MountID     int               `json:"mount-id"`
+		ParentID    int               `json:"parent-id"`
+		Root        string            `json:"root"`
+		MountDir    string            `json:"mount-dir"`
+		FsType      string            `json:"fs-type"`
+		MountSource string            `json:"mount-source"`
+		Options     map[string]string `json:"options"`
+	} `json:"mounts"`
+	Layouts []struct {
+		Path     string `json:"path"`
+		Bind     string `json:"bind"`
+		BindFile string `json:"bind-file"`
+		Type     string `json:"type"`
+		Symlink  string `json:"symlink"`
+		Applied  bool   `json:"applied"`
+	} `json:"layouts"`
+	Content []struct {
+		Plug    string   `json:"plug"`
+		Slot    string   `json:"slot"`
+		Target  string   `json:"target"`
+		Read    []string `json:"read"`
+		Write   []string `json:"write"`
+		Mounted bool     `json:"mounted"`
+	} `json:"content"`
+}
+
+func yesNo(b bool) string {
+	if b {
+		return i18n.G("yes")
+	}
+	return i18n.G("no")
+}
+
+func (x *cmdDebugMountNs) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	snapName := string(x.Positional.Snap)
+	var info mountNsInfo
+	if err := x.client.DebugGet("mount-ns", &info, map[string]string{"snap": snapName}); err != nil {
+		return err
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintf(w, "snap:\t%s\n", info.Snap)
+	fmt.Fprintf(w, "preserved:\t%s\n", yesNo(info.Preserved))
+	if len(info.Mounts) > 0 {
+		fmt.Fprintln(w, "mounts:")
+		fmt.Fprintln(w, i18n.G("  ID\tParent\tMount point\tType\tSource\tRoot"))
+		for _, m := range info.Mounts {
+			fmt.Fprintf(w, "  %d\t%d\t%s\t%s\t%s\t%s\n", m.MountID, m.ParentID, m.MountDir, m.FsType, m.MountSource, m.Root)
+		}
+	}
+	if len(info.Layouts) > 0 {
+		fmt.Fprintln(w, "layouts:")
+		fmt.Fprintln(w, i18n.G("  Path\tKind\tSource\tApplied"))
+		for _, l := range info.Layouts {
+			kind, source := "bind", l.Bind
+			switch {
+			case l.BindFile != "":
+				kind, source = "bind-file", l.BindFile
+			case l.Symlink != "":
+				kind, source = "symlink", l.Symlink
+			case l.Type != "":
+				kind, source = "type", l.Type
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", l.Path, kind, source, yesNo(l.Applied))
+		}
+	}
+	if len(info.Content) > 0 {
+		fmt.Fprintln(w, "content:")
+		fmt.Fprintln(w, i18n.G("  Plug\tSlot\tTarget\tSources\tMounted"))
+		for _, cnt := range info.Content {
+			sources := "-"
+			if all := append(cnt.Read, cnt.Write...); len(all) > 0 {
+				sources = strings.Join(all, ",")
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", cnt.Plug, cnt.Slot, cnt.Target, sources, yesNo(cnt.Mounted))
+		}
+	}
+	return nil
+}
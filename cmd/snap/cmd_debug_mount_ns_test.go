@@ -0,0 +1,95 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) TestDebugMountNs(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/debug")
+			c.Check(r.URL.Query().Get("aspect"), check.Equals, "mount-ns")
+			c.Check(r.URL.Query().Get("snap"), check.Equals, "foo")
+			fmt.Fprintln(w, `{"type": "sync", "result": {
+"snap": "foo",
+"preserved": true,
+"mounts": [
+ {"mount-id": 2, "parent-id": 1, "root": "/", "mount-dir": "/", "fs-type": "squashfs", "mount-source": "/dev/loop1"},
+ {"mount-id": 30, "parent-id": 2, "root": "/share/themes", "mount-dir": "/snap/foo/1/themes", "fs-type": "squashfs", "mount-source": "/dev/loop2"}
+],
+"layouts": [
+ {"path": "/etc/bar", "bind-file": "$SNAP/etc/bar", "applied": false},
+ {"path": "/usr/share/foo", "bind": "$SNAP/usr/share/foo", "applied": true}
+],
+"content": [
+ {"plug": "themes", "slot": "gtk-common-themes:themes", "target": "$SNAP/themes", "read": ["$SNAP/share/themes"], "mounted": true}
+]
+}}`)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "mount-ns", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `snap:       foo
+preserved:  yes
+mounts:
+  ID  Parent  Mount point         Type      Source      Root
+  2   1       /                   squashfs  /dev/loop1  /
+  30  2       /snap/foo/1/themes  squashfs  /dev/loop2  /share/themes
+layouts:
+  Path            Kind       Source               Applied
+  /etc/bar        bind-file  $SNAP/etc/bar        no
+  /usr/share/foo  bind       $SNAP/usr/share/foo  yes
+content:
+  Plug    Slot                      Target        Sources             Mounted
+  themes  gtk-common-themes:themes  $SNAP/themes  $SNAP/share/themes  yes
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestDebugMountNsNotPreserved(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": {"snap": "foo", "preserved": false, "mounts": [], "layouts": [], "content": []}}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "mount-ns", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, `snap:       foo
+preserved:  no
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestDebugMountNsNoSnap(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "mount-ns"})
+	c.Assert(err, check.ErrorMatches, "the required argument `<snap>` was not provided")
+}
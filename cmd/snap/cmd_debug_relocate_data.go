@@ -0,0 +1,88 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdDebugRelocateData struct {
+	waitMixin
+
+	Positional struct {
+		Snap     installedSnapName `positional-arg-name:"<snap>" required:"yes"`
+		Location string            `positional-arg-name:"<location>"`
+	} `positional-args:"yes"`
+}
+
+func init() {
+	addDebugCommand("relocate-data",
+		i18n.G("Move the data of a snap to another location"),
+		i18n.G(`
+The relocate-data command moves the system data of the given snap, that is
+SNAP_DATA and SNAP_COMMON, to the given directory, usually on another volume.
+The data is then bind mounted onto /var/snap/<snap>. Without a location, the
+data is moved back to /var/snap.
+
+The services of the snap are stopped while its data is moved.
+`),
+		func() flags.Commander {
+			return &cmdDebugRelocateData{}
+		}, waitDescs, []argDesc{{
+			// TRANSLATORS: This needs to begin with < and end with >
+			name: i18n.G("<snap>"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			desc: i18n.G("The snap to move the data of"),
+		}, {
+			// TRANSLATORS: This needs to begin with < and end with >
+			name: i18n.G("<location>"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			desc: i18n.G("The directory to move the data to"),
+		}})
+}
+
+func (x *cmdDebugRelocateData) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	snapName := string(x.Positional.Snap)
+	chgID, err := x.client.RelocateSnapData(snapName, x.Positional.Location)
+	if err != nil {
+		return err
+	}
+	if _, err := x.wait(chgID); err != nil {
+		if err == noWait {
+			return nil
+		}
+		return err
+	}
+
+	if x.Positional.Location == "" {
+		fmt.Fprintf(Stdout, i18n.G("Moved data of snap %q back to /var/snap.\n"), snapName)
+	} else {
+		fmt.Fprintf(Stdout, i18n.G("Moved data of snap %q to %s.\n"), snapName, x.Positional.Location)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) TestDebugRelocateData(c *check.C) {
+	for _, tc := range []struct {
+		args     []string
+		location string
+		output   string
+	}{
+		{[]string{"foo", "/mnt/data"}, "/mnt/data", "Moved data of snap \"foo\" to /mnt/data.\n"},
+		{[]string{"foo"}, "", "Moved data of snap \"foo\" back to /var/snap.\n"},
+	} {
+		s.ResetStdStreams()
+		n := 0
+		s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+			switch n {
+			case 0:
+				c.Check(r.Method, check.Equals, "POST")
+				c.Check(r.URL.Path, check.Equals, "/v2/debug")
+				c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+					"action": "relocate-data",
+					"snaps":  []interface{}{"foo"},
+					"params": map[string]interface{}{"data-location": tc.location},
+				})
+				w.WriteHeader(202)
+				fmt.Fprintln(w, `{"type": "async", "status-code": 202, "result": {}, "change": "12"}`)
+			case 1:
+				c.Check(r.Method, check.Equals, "GET")
+				c.Check(r.URL.Path, check.Equals, "/v2/changes/12")
+				fmt.Fprintln(w, `{"type": "sync", "result": {"ready": true, "status": "Done"}}`)
+			default:
+				c.Fatalf("expected to get 2 requests, now on %d", n+1)
+			}
+
+			n++
+		})
+		rest, err := snap.Parser(snap.Client()).ParseArgs(append([]string{"debug", "relocate-data"}, tc.args...))
+		c.Assert(err, check.IsNil)
+		c.Assert(rest, check.DeepEquals, []string{})
+		c.Check(s.Stdout(), check.Equals, tc.output)
+		c.Check(n, check.Equals, 2)
+	}
+}
@@ -20,6 +20,7 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -36,10 +37,12 @@ import (
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/overlord/ifacestate/schema"
 	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/overlord/state/statequery"
 	"github.com/snapcore/snapd/strutil"
 )
 
 type cmdDebugState struct {
+	clientMixin
 	timeMixin
 
 	Changes  bool   `long:"changes"`
@@ -52,6 +55,8 @@ type cmdDebugState struct {
 
 	IsSeeded bool `long:"is-seeded"`
 
+	Query string `long:"query"`
+
 	// flags for --change=N output
 	DotOutput bool `long:"dot"` // XXX: mildly useful (too crowded in many cases), but let's have it just in case
 	// When inspecting errors/undone tasks, those in Hold state are usually irrelevant, make it possible to ignore them
@@ -63,7 +68,19 @@ type cmdDebugState struct {
 }
 
 var cmdDebugStateShortHelp = i18n.G("Inspect a snapd state file.")
-var cmdDebugStateLongHelp = i18n.G("Inspect a snapd state file, bypassing snapd API.")
+var cmdDebugStateLongHelp = i18n.G(`
+Inspect a snapd state file, bypassing snapd API.
+
+With --query, the values matching the given query are shown as JSON. A query
+descends into the state through field names separated by dots, or "*" for all
+fields, and filters the elements of objects and lists with [field=value],
+[field!=value], [field~substring] or [field], for example:
+
+    snap debug state --query 'changes[kind=auto-refresh][status=Error].summary'
+
+When no state file is given the query is evaluated by snapd over its current
+state, which requires root.
+`)
 
 type byChangeSpawnTime []*state.Change
 
@@ -98,6 +115,7 @@ func init() {
 		"connection":  i18n.G("Show details of the matching connections (snap or snap:plug,snap:slot or snap:plug-or-slot"),
 		"is-seeded":   i18n.G("Output seeding status (true or false)"),
 		"check":       i18n.G("Check change consistency"),
+		"query":       i18n.G("Show the values of the state matching the query"),
 	}), nil)
 }
 
@@ -497,7 +515,42 @@ func (c *cmdDebugState) showTask(st *state.State, taskID string) error {
 	return nil
 }
 
+func (c *cmdDebugState) runQuery() error {
+	if c.Changes || c.ChangeID != "" || c.TaskID != "" || c.IsSeeded || c.Connections || c.Connection != "" || c.DotOutput || c.NoHoldState || c.Check {
+		return fmt.Errorf("cannot use --query with other options")
+	}
+
+	var res []interface{}
+	if c.Positional.StateFilePath == "" {
+		if err := c.client.DebugGet("state", &res, map[string]string{"query": c.Query}); err != nil {
+			return err
+		}
+	} else {
+		st, err := loadState(c.Positional.StateFilePath)
+		if err != nil {
+			return err
+		}
+		st.Lock()
+		res, err = statequery.Run(st, c.Query)
+		st.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	out, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, "%s\n", out)
+	return nil
+}
+
 func (c *cmdDebugState) Execute(args []string) error {
+	if c.Query != "" {
+		return c.runQuery()
+	}
+
 	st, err := loadState(c.Positional.StateFilePath)
 	if err != nil {
 		return err
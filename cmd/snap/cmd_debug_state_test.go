@@ -21,6 +21,8 @@ package main_test
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"time"
@@ -550,3 +552,46 @@ func (s *SnapSuite) TestDebugConnectionDetailsManySlotSide(c *C) {
 			"undesired: false\n"+
 			"\n")
 }
+
+func (s *SnapSuite) TestDebugStateQueryFile(c *C) {
+	dir := c.MkDir()
+	stateFile := filepath.Join(dir, "test-state.json")
+	c.Assert(os.WriteFile(stateFile, stateJSON, 0644), IsNil)
+
+	rest, err := main.Parser(main.Client()).ParseArgs([]string{"debug", "state", "--query", "changes[status=Done].summary", stateFile})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, "[\n  \"revert c snap\"\n]\n")
+	c.Check(s.Stderr(), Equals, "")
+
+	s.ResetStdStreams()
+	_, err = main.Parser(main.Client()).ParseArgs([]string{"debug", "state", "--query", "data.seeded", stateFile})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "[\n  true\n]\n")
+
+	_, err = main.Parser(main.Client()).ParseArgs([]string{"debug", "state", "--query", "changes[", stateFile})
+	c.Check(err, ErrorMatches, `cannot parse query "changes\[": expected a field name .*`)
+
+	_, err = main.Parser(main.Client()).ParseArgs([]string{"debug", "state", "--query", "data", "--changes", stateFile})
+	c.Check(err, ErrorMatches, "cannot use --query with other options")
+}
+
+func (s *SnapSuite) TestDebugStateQueryDaemon(c *C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/debug")
+		c.Check(r.URL.Query(), DeepEquals, url.Values{
+			"aspect": {"state"},
+			"query":  {"changes[kind=auto-refresh][status=Error].id"},
+		})
+		fmt.Fprintln(w, `{"type": "sync", "result": ["3", "7"]}`)
+	})
+
+	rest, err := main.Parser(main.Client()).ParseArgs([]string{"debug", "state", "--query", "changes[kind=auto-refresh][status=Error].id"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, "[\n  \"3\",\n  \"7\"\n]\n")
+	c.Check(n, Equals, 1)
+}
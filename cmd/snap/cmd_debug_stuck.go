@@ -0,0 +1,140 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdDebugStuck struct {
+	clientMixin
+	Threshold string `long:"threshold"`
+	Abort     bool   `long:"abort"`
+}
+
+func init() {
+	addDebugCommand("stuck",
+		i18n.G("Diagnose changes that are stuck in progress"),
+		i18n.G(`
+The stuck command reports the changes that have been in progress for longer
+than a threshold, one hour by default, along with the task and the resource
+they are blocked on: a pending reboot, a task being retried or a conflicting
+change for the same snaps.
+
+A remediation is suggested for each change. With --abort, the changes for
+which aborting is safe are aborted, snapd then undoes what they did so far.
+`),
+		func() flags.Commander {
+			return &cmdDebugStuck{}
+		}, map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"threshold": i18n.G("Report changes in progress for longer than this duration (e.g. 30m)"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"abort": i18n.G("Abort the stuck changes for which it is safe"),
+		}, nil)
+}
+
+type stuckChange struct {
+	ID           string    `json:"id"`
+	Kind         string    `json:"kind"`
+	Summary      string    `json:"summary"`
+	Status       string    `json:"status"`
+	SpawnTime    time.Time `json:"spawn-time"`
+	BlockingTask *struct {
+		ID      string     `json:"id"`
+		Kind    string     `json:"kind"`
+		Summary string     `json:"summary"`
+		Status  string     `json:"status"`
+		LastLog string     `json:"last-log"`
+		RetryAt *time.Time `json:"retry-at"`
+	} `json:"blocking-task"`
+	BlockedOn     string   `json:"blocked-on"`
+	ConflictsWith []string `json:"conflicts-with"`
+	Remediation   string   `json:"remediation"`
+}
+
+func (x *cmdDebugStuck) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	var params map[string]string
+	if x.Threshold != "" {
+		params = map[string]string{"threshold": x.Threshold}
+	}
+	var stuck []stuckChange
+	if err := x.client.DebugGet("stuck-changes", &stuck, params); err != nil {
+		return err
+	}
+	if len(stuck) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No stuck changes."))
+		return nil
+	}
+
+	now := timeNow()
+	for i, chg := range stuck {
+		if i > 0 {
+			fmt.Fprintln(Stdout)
+		}
+		fmt.Fprintf(Stdout, i18n.G("Change %s (%s) %q is %s since %s\n"), chg.ID, chg.Kind, chg.Summary, chg.Status, now.Sub(chg.SpawnTime).Round(time.Second))
+		if t := chg.BlockingTask; t != nil {
+			fmt.Fprintf(Stdout, i18n.G("  blocking task: %s (%s) %q is %s\n"), t.ID, t.Kind, t.Summary, t.Status)
+			if t.RetryAt != nil {
+				fmt.Fprintf(Stdout, i18n.G("  retrying at: %s\n"), t.RetryAt.Format(time.RFC3339))
+			}
+			if t.LastLog != "" {
+				fmt.Fprintf(Stdout, i18n.G("  last log: %s\n"), t.LastLog)
+			}
+		}
+		switch chg.BlockedOn {
+		case "reboot":
+			fmt.Fprintln(Stdout, i18n.G("  blocked on: a pending reboot"))
+		case "retry":
+			fmt.Fprintln(Stdout, i18n.G("  blocked on: a task being retried"))
+		case "conflict":
+			fmt.Fprintln(Stdout, i18n.G("  blocked on: conflicting changes"))
+		}
+		if len(chg.ConflictsWith) > 0 {
+			fmt.Fprintf(Stdout, i18n.G("  conflicts with: %s\n"), strings.Join(chg.ConflictsWith, ", "))
+		}
+
+		switch chg.Remediation {
+		case "abort":
+			if !x.Abort {
+				fmt.Fprintf(Stdout, i18n.G("  suggestion: abort it with 'snap abort %s'\n"), chg.ID)
+				break
+			}
+			if _, err := x.client.Abort(chg.ID); err != nil {
+				return err
+			}
+			fmt.Fprintln(Stdout, i18n.G("  aborted"))
+		case "reboot":
+			fmt.Fprintln(Stdout, i18n.G("  suggestion: reboot the system for the change to proceed"))
+		case "wait":
+			fmt.Fprintln(Stdout, i18n.G("  suggestion: wait for the change to be undone"))
+		}
+	}
+	return nil
+}
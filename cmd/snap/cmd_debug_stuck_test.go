@@ -0,0 +1,115 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+const stuckChangesJSON = `{"type": "sync", "result": [
+{"id": "1", "kind": "refresh-snap", "summary": "Refresh \"foo\" snap", "status": "Doing", "spawn-time": "2026-03-04T09:00:00Z",
+ "blocking-task": {"id": "2", "kind": "link-snap", "summary": "Make snap \"foo\" available", "status": "Doing", "last-log": "2026-03-04T11:59:00Z INFO snap \"foo\" has running apps", "retry-at": "2026-03-04T12:01:00Z"},
+ "blocked-on": "retry", "conflicts-with": ["3"], "remediation": "abort"},
+{"id": "4", "kind": "auto-refresh", "summary": "Auto-refresh snap \"pc-kernel\"", "status": "Wait", "spawn-time": "2026-03-04T10:30:00Z",
+ "blocking-task": {"id": "5", "kind": "auto-connect", "summary": "Automatically connect eligible plugs and slots", "status": "Wait"},
+ "blocked-on": "reboot", "remediation": "reboot"},
+{"id": "6", "kind": "remove-snap", "summary": "Remove \"baz\" snap", "status": "Undoing", "spawn-time": "2026-03-04T11:00:00Z", "remediation": "wait"}
+]}`
+
+func (s *SnapSuite) mockStuckChanges(c *check.C, expectedQuery string, abort bool) *int {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/debug")
+			c.Check(r.URL.RawQuery, check.Equals, expectedQuery)
+			fmt.Fprintln(w, stuckChangesJSON)
+		case 1:
+			c.Assert(abort, check.Equals, true)
+			c.Check(r.Method, check.Equals, "POST")
+			c.Check(r.URL.Path, check.Equals, "/v2/changes/1")
+			body, err := io.ReadAll(r.Body)
+			c.Assert(err, check.IsNil)
+			c.Check(string(body), check.Equals, `{"action":"abort"}`+"\n")
+			fmt.Fprintln(w, `{"type": "sync", "result": {"id": "1", "kind": "refresh-snap", "status": "Abort"}}`)
+		default:
+			c.Fatalf("unexpected request %d", n+1)
+		}
+		n++
+	})
+	s.AddCleanup(snap.MockTimeNow(func() time.Time {
+		return time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	}))
+	return &n
+}
+
+func (s *SnapSuite) TestDebugStuck(c *check.C) {
+	n := s.mockStuckChanges(c, "aspect=stuck-changes&threshold=30m", false)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "stuck", "--threshold=30m"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `Change 1 (refresh-snap) "Refresh \"foo\" snap" is Doing since 3h0m0s
+  blocking task: 2 (link-snap) "Make snap \"foo\" available" is Doing
+  retrying at: 2026-03-04T12:01:00Z
+  last log: 2026-03-04T11:59:00Z INFO snap "foo" has running apps
+  blocked on: a task being retried
+  conflicts with: 3
+  suggestion: abort it with 'snap abort 1'
+
+Change 4 (auto-refresh) "Auto-refresh snap \"pc-kernel\"" is Wait since 1h30m0s
+  blocking task: 5 (auto-connect) "Automatically connect eligible plugs and slots" is Wait
+  blocked on: a pending reboot
+  suggestion: reboot the system for the change to proceed
+
+Change 6 (remove-snap) "Remove \"baz\" snap" is Undoing since 1h0m0s
+  suggestion: wait for the change to be undone
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(*n, check.Equals, 1)
+}
+
+func (s *SnapSuite) TestDebugStuckAbort(c *check.C) {
+	n := s.mockStuckChanges(c, "aspect=stuck-changes", true)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "stuck", "--abort"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Matches, `(?s)Change 1 .*  conflicts with: 3\n  aborted\n\nChange 4 .*`)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(*n, check.Equals, 2)
+}
+
+func (s *SnapSuite) TestDebugStuckNone(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": []}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "stuck"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "No stuck changes.\n")
+}
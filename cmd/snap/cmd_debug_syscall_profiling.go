@@ -0,0 +1,148 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdDebugSyscallProfiling struct {
+	waitMixin
+
+	Start  bool `long:"start"`
+	Stop   bool `long:"stop"`
+	Unused bool `long:"unused"`
+
+	Positional struct {
+		Snap installedSnapName `positional-arg-name:"<snap>" required:"yes"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func init() {
+	addDebugCommand("syscall-profiling",
+		i18n.G("Profile the system calls used by a snap"),
+		i18n.G(`
+The syscall-profiling command helps tightening the seccomp policy of a snap.
+
+With --start, the seccomp filters of the given snap allow and log all system
+calls until profiling is stopped with --stop. Otherwise, it shows the system
+calls the snap used since profiling started, whether its policy grants them
+and, with --unused, the granted system calls it did not use.
+`),
+		func() flags.Commander {
+			return &cmdDebugSyscallProfiling{}
+		}, waitDescs.also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"start": i18n.G("Start profiling the system calls of the snap"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"stop": i18n.G("Stop profiling the system calls of the snap"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"unused": i18n.G("Also show the granted system calls that were not used"),
+		}), []argDesc{{
+			// TRANSLATORS: This needs to begin with < and end with >
+			name: i18n.G("<snap>"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			desc: i18n.G("The snap to profile the system calls of"),
+		}})
+}
+
+type syscallProfile struct {
+	SecurityTag string         `json:"security-tag"`
+	Used        map[string]int `json:"used"`
+	Unused      []string       `json:"unused"`
+	Ungranted   []string       `json:"ungranted"`
+}
+
+type syscallProfilingReport struct {
+	Snap     string            `json:"snap"`
+	Profiles []*syscallProfile `json:"profiles"`
+}
+
+func (x *cmdDebugSyscallProfiling) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	if x.Start && x.Stop {
+		return fmt.Errorf(i18n.G("cannot use --start and --stop together"))
+	}
+
+	snapName := string(x.Positional.Snap)
+	if x.Start || x.Stop {
+		chgID, err := x.client.SyscallProfiling(snapName, x.Start)
+		if err != nil {
+			return err
+		}
+		if _, err := x.wait(chgID); err != nil {
+			if err == noWait {
+				return nil
+			}
+			return err
+		}
+		if x.Start {
+			fmt.Fprintf(Stdout, i18n.G("Profiling system calls of snap %q.\n"), snapName)
+		} else {
+			fmt.Fprintf(Stdout, i18n.G("Stopped profiling system calls of snap %q.\n"), snapName)
+		}
+		return nil
+	}
+
+	var report syscallProfilingReport
+	if err := x.client.DebugGet("syscall-profiling", &report, map[string]string{"snap": snapName}); err != nil {
+		return err
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Profile\tSystem call\tCount\tGranted"))
+	for _, p := range report.Profiles {
+		profile := p.SecurityTag
+		if profile == "" {
+			profile = "-"
+		}
+		used := make([]string, 0, len(p.Used))
+		for name := range p.Used {
+			used = append(used, name)
+		}
+		sort.Strings(used)
+		ungranted := make(map[string]bool, len(p.Ungranted))
+		for _, name := range p.Ungranted {
+			ungranted[name] = true
+		}
+		for _, name := range used {
+			granted := i18n.G("yes")
+			if ungranted[name] {
+				granted = i18n.G("no")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", profile, name, p.Used[name], granted)
+		}
+		if x.Unused {
+			for _, name := range p.Unused {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", profile, name, 0, i18n.G("yes"))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+const syscallProfilingReport = `{"type": "sync", "result": {"snap": "foo", "since": "2026-10-15T10:00:00Z", "profiles": [
+{"security-tag": "snap.foo.app", "used": {"read": 2, "mount": 1}, "unused": ["openat", "write"], "ungranted": ["mount"]},
+{"security-tag": "", "used": {"write": 1}}
+]}}`
+
+func (s *SnapSuite) TestDebugSyscallProfilingReport(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/debug")
+			c.Check(r.URL.Query().Get("aspect"), check.Equals, "syscall-profiling")
+			c.Check(r.URL.Query().Get("snap"), check.Equals, "foo")
+			fmt.Fprintln(w, syscallProfilingReport)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "syscall-profiling", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `Profile       System call  Count  Granted
+snap.foo.app  mount        1      no
+snap.foo.app  read         2      yes
+-             write        1      yes
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestDebugSyscallProfilingReportUnused(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, syscallProfilingReport)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "syscall-profiling", "--unused", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, `Profile       System call  Count  Granted
+snap.foo.app  mount        1      no
+snap.foo.app  read         2      yes
+snap.foo.app  openat       0      yes
+snap.foo.app  write        0      yes
+-             write        1      yes
+`)
+}
+
+func (s *SnapSuite) TestDebugSyscallProfilingStartStop(c *check.C) {
+	for _, tc := range []struct {
+		flag   string
+		action string
+		output string
+	}{
+		{"--start", "start-syscall-profiling", "Profiling system calls of snap \"foo\".\n"},
+		{"--stop", "stop-syscall-profiling", "Stopped profiling system calls of snap \"foo\".\n"},
+	} {
+		s.ResetStdStreams()
+		n := 0
+		s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+			switch n {
+			case 0:
+				c.Check(r.Method, check.Equals, "POST")
+				c.Check(r.URL.Path, check.Equals, "/v2/debug")
+				c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+					"action": tc.action,
+					"snaps":  []interface{}{"foo"},
+				})
+				w.WriteHeader(202)
+				fmt.Fprintln(w, `{"type": "async", "status-code": 202, "result": {}, "change": "12"}`)
+			case 1:
+				c.Check(r.Method, check.Equals, "GET")
+				c.Check(r.URL.Path, check.Equals, "/v2/changes/12")
+				fmt.Fprintln(w, `{"type": "sync", "result": {"ready": true, "status": "Done"}}`)
+			default:
+				c.Fatalf("expected to get 2 requests, now on %d", n+1)
+			}
+
+			n++
+		})
+		rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "syscall-profiling", tc.flag, "foo"})
+		c.Assert(err, check.IsNil)
+		c.Assert(rest, check.DeepEquals, []string{})
+		c.Check(s.Stdout(), check.Equals, tc.output)
+		c.Check(n, check.Equals, 2)
+	}
+}
+
+func (s *SnapSuite) TestDebugSyscallProfilingStartAndStop(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "syscall-profiling", "--start", "--stop", "foo"})
+	c.Assert(err, check.ErrorMatches, "cannot use --start and --stop together")
+}
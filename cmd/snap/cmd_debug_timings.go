@@ -29,6 +29,7 @@ import (
 	"github.com/jessevdk/go-flags"
 
 	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/timings"
 )
 
 type cmdChangeTimings struct {
@@ -37,6 +38,9 @@ type cmdChangeTimings struct {
 	All        bool   `long:"all"`
 	StartupTag string `long:"startup" choice:"load-state" choice:"ifacemgr"`
 	Verbose    bool   `long:"verbose"`
+	Aggregate  bool   `long:"aggregate"`
+	GroupBy    string `long:"group-by"`
+	Flame      bool   `long:"flame"`
 }
 
 func init() {
@@ -51,6 +55,12 @@ func init() {
 			"startup": i18n.G("Show timings for the startup of given subsystem (one of: load-state, ifacemgr)"),
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"verbose": i18n.G("Show more information"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"aggregate": i18n.G("Show timings aggregated across all the activities and changes that have them"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"group-by": i18n.G("Group aggregated timings by the given tag (e.g. task-kind or snap-size)"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"flame": i18n.G("Output aggregated timings as folded stacks for flamegraph tools"),
 		}), changeIDMixinArgDesc)
 }
 
@@ -224,6 +234,46 @@ func (x *cmdChangeTimings) checkConflictingFlags() error {
 	if x.All && (x.Positional.ID != "" || x.LastChangeType != "") {
 		return fmt.Errorf("cannot use 'all' with change id or 'last'")
 	}
+
+	if x.Aggregate {
+		if x.Positional.ID != "" || x.LastChangeType != "" || x.StartupTag != "" || x.EnsureTag != "" || x.All {
+			return fmt.Errorf("cannot use 'aggregate' with change id, 'last', 'startup', 'ensure' or 'all'")
+		}
+	} else if x.GroupBy != "" || x.Flame {
+		return fmt.Errorf("cannot use 'group-by' or 'flame' without 'aggregate'")
+	}
+	return nil
+}
+
+func (x *cmdChangeTimings) printAggregatedTimings() error {
+	var aggs []*timings.Aggregate
+	if err := x.client.DebugGet("aggregated-timings", &aggs, map[string]string{"group-by": x.GroupBy}); err != nil {
+		return err
+	}
+
+	if x.Flame {
+		for _, line := range timings.FoldedStacks(aggs) {
+			fmt.Fprintln(Stdout, line)
+		}
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	if x.GroupBy != "" {
+		fmt.Fprintf(w, "Group\t")
+	}
+	fmt.Fprintf(w, "Label\tCount\t%11s\t%11s\t%11s\t%11s\n", "Average", "Min", "Max", "Total")
+	for _, agg := range aggs {
+		if x.GroupBy != "" {
+			fmt.Fprintf(w, "%s\t", agg.Group)
+		}
+		nestLevel := len(agg.Labels) - 1
+		label := strings.Repeat(" ", 2*nestLevel) + agg.Labels[nestLevel]
+		fmt.Fprintf(w, "%s\t%d\t%11s\t%11s\t%11s\t%11s\n", label, agg.Count,
+			formatDuration(agg.Average()), formatDuration(agg.Min), formatDuration(agg.Max), formatDuration(agg.Total))
+	}
 	return nil
 }
 
@@ -236,6 +286,10 @@ func (x *cmdChangeTimings) Execute(args []string) error {
 		return err
 	}
 
+	if x.Aggregate {
+		return x.printAggregatedTimings()
+	}
+
 	var chgid string
 	var err error
 
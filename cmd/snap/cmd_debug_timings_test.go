@@ -132,6 +132,31 @@ var timingsTests = []timingsCmdArgs{{
 		" ^                        8ms            -    baz summary\n" +
 		"ifacemgr                  9ms            -  \n" +
 		" ^                        9ms            -    baz summary\n\n",
+}, {
+	args:  "debug timings --aggregate 9",
+	error: "cannot use 'aggregate' with change id, 'last', 'startup', 'ensure' or 'all'",
+}, {
+	args:  "debug timings --aggregate --ensure=seed",
+	error: "cannot use 'aggregate' with change id, 'last', 'startup', 'ensure' or 'all'",
+}, {
+	args:  "debug timings --flame 9",
+	error: "cannot use 'group-by' or 'flame' without 'aggregate'",
+}, {
+	args: "debug timings --aggregate",
+	stdout: "Label         Count      Average          Min          Max        Total\n" +
+		"mount-snap    3             43ms         10ms        100ms        130ms\n" +
+		"  setup-snap  3             35ms          6ms         90ms        106ms\n" +
+		"    unpack    2              6ms          4ms          8ms         12ms\n",
+}, {
+	args: "debug timings --aggregate --group-by=snap-size",
+	stdout: "Group  Label       Count      Average          Min          Max        Total\n" +
+		"<16MB  mount-snap  2             15ms         10ms         20ms         30ms\n" +
+		"<16MB      unpack  2              6ms          4ms          8ms         12ms\n",
+}, {
+	args: "debug timings --aggregate --flame",
+	stdout: "mount-snap 24000\n" +
+		"mount-snap;setup-snap 94000\n" +
+		"mount-snap;setup-snap;unpack 12000\n",
 }, {
 	args: "debug timings 2",
 	stdout: "ID   Status        Doing      Undoing  Summary\n" +
@@ -169,6 +194,24 @@ func (s *SnapSuite) mockCmdTimingsAPI(c *C) {
 		if r.URL.Path == "/v2/debug" {
 			q := r.URL.Query()
 			aspect := q.Get("aspect")
+			if aspect == "aggregated-timings" {
+				switch q.Get("group-by") {
+				case "":
+					fmt.Fprintln(w, `{"type":"sync","status-code":200,"status":"OK","result":[
+						{"labels":["mount-snap"], "count": 3, "total": 130000000, "min": 10000000, "max": 100000000},
+						{"labels":["mount-snap", "setup-snap"], "count": 3, "total": 106000000, "min": 6000000, "max": 90000000},
+						{"labels":["mount-snap", "setup-snap", "unpack"], "count": 2, "total": 12000000, "min": 4000000, "max": 8000000}
+					]}`)
+				case "snap-size":
+					fmt.Fprintln(w, `{"type":"sync","status-code":200,"status":"OK","result":[
+						{"group":"<16MB", "labels":["mount-snap"], "count": 2, "total": 30000000, "min": 10000000, "max": 20000000},
+						{"group":"<16MB", "labels":["mount-snap", "setup-snap", "unpack"], "count": 2, "total": 12000000, "min": 4000000, "max": 8000000}
+					]}`)
+				default:
+					c.Errorf("unexpected group-by %q", q.Get("group-by"))
+				}
+				return
+			}
 			c.Assert(aspect, Equals, "change-timings")
 
 			changeID := q.Get("change-id")
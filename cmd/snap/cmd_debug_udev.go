@@ -0,0 +1,105 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdDebugUdev struct {
+	clientMixin
+
+	Positional struct {
+		Snap installedSnapName `positional-arg-name:"<snap>" required:"yes"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func init() {
+	addDebugCommand("udev",
+		i18n.G("Show the udev rules and tagged devices of a snap"),
+		i18n.G(`
+The udev command shows the udev rules in effect for the given snap, along
+with the devices udev currently tags for its apps and hooks.
+`),
+		func() flags.Commander {
+			return &cmdDebugUdev{}
+		}, nil, []argDesc{{
+			// TRANSLATORS: This needs to begin with < and end with >
+			name: i18n.G("<snap>"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			desc: i18n.G("The snap to show the udev rules of"),
+		}})
+}
+
+type udevInfo struct {
+	Snap      string `json:"snap"`
+	RulesFile string `json:"rules-file"`
+	Rules     string `json:"rules"`
+	Devices   []struct {
+		Tag     string `json:"tag"`
+		ID      string `json:"id"`
+		SysPath string `json:"sys-path"`
+	} `json:"devices"`
+}
+
+func (x *cmdDebugUdev) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	snapName := string(x.Positional.Snap)
+	var info udevInfo
+	if err := x.client.DebugGet("udev", &info, map[string]string{"snap": snapName}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(Stdout, "snap: %s\n", info.Snap)
+	if info.RulesFile == "" {
+		fmt.Fprintln(Stdout, "rules: -")
+	} else {
+		fmt.Fprintf(Stdout, "rules-file: %s\n", info.RulesFile)
+		fmt.Fprintln(Stdout, "rules:")
+		for _, line := range strings.Split(strings.TrimSuffix(info.Rules, "\n"), "\n") {
+			fmt.Fprintf(Stdout, "  %s\n", line)
+		}
+	}
+	if len(info.Devices) == 0 {
+		fmt.Fprintln(Stdout, "devices: -")
+		return nil
+	}
+
+	fmt.Fprintln(Stdout, "devices:")
+	w := tabWriter()
+	defer w.Flush()
+	fmt.Fprintln(w, i18n.G("  Tag\tID\tPath"))
+	for _, dev := range info.Devices {
+		path := dev.SysPath
+		if path == "" {
+			path = "-"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", dev.Tag, dev.ID, path)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) TestDebugUdev(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/debug")
+			c.Check(r.URL.Query().Get("aspect"), check.Equals, "udev")
+			c.Check(r.URL.Query().Get("snap"), check.Equals, "foo")
+			fmt.Fprintln(w, `{"type": "sync", "result": {
+"snap": "foo",
+"rules-file": "/etc/udev/rules.d/70-snap.foo.rules",
+"rules": "# This file is automatically generated.\n# serial-port\nSUBSYSTEM==\"tty\", KERNEL==\"ttyS0\", TAG+=\"snap_foo_app\"\n",
+"devices": [
+ {"tag": "snap_foo_app", "id": "+usb:1-1"},
+ {"tag": "snap_foo_app", "id": "c4:64", "sys-path": "/sys/devices/platform/serial8250/tty/ttyS0"}
+]
+}}`)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "udev", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `snap: foo
+rules-file: /etc/udev/rules.d/70-snap.foo.rules
+rules:
+  # This file is automatically generated.
+  # serial-port
+  SUBSYSTEM=="tty", KERNEL=="ttyS0", TAG+="snap_foo_app"
+devices:
+  Tag           ID        Path
+  snap_foo_app  +usb:1-1  -
+  snap_foo_app  c4:64     /sys/devices/platform/serial8250/tty/ttyS0
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestDebugUdevNothing(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": {"snap": "foo", "devices": []}}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "udev", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "snap: foo\nrules: -\ndevices: -\n")
+}
@@ -0,0 +1,90 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) TestDebugVerifySeedFailed(c *C) {
+	seedDir := c.MkDir()
+	err := os.WriteFile(filepath.Join(seedDir, "seed.yaml"), []byte(`
+snaps:
+ -
+   name: core
+   channel: stable
+   file: core_6673.snap
+`), 0644)
+	c.Assert(err, IsNil)
+
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"debug", "verify-seed", seedDir})
+	c.Assert(err, ErrorMatches, "seed verification failed")
+	c.Check(s.Stdout(), Equals, `{
+  "ok": false,
+  "systems": [
+    {
+      "ok": false,
+      "checks": [
+        {
+          "name": "assertions",
+          "status": "failed",
+          "errors": [
+            "no seed assertions"
+          ]
+        },
+        {
+          "name": "essential-snaps",
+          "status": "skipped"
+        },
+        {
+          "name": "snaps",
+          "status": "skipped"
+        },
+        {
+          "name": "snap-metadata",
+          "status": "skipped"
+        },
+        {
+          "name": "channels",
+          "status": "skipped"
+        },
+        {
+          "name": "bases-and-providers",
+          "status": "skipped"
+        }
+      ]
+    }
+  ]
+}
+`)
+}
+
+func (s *SnapSuite) TestDebugVerifySeedNoSystems(c *C) {
+	seedDir := c.MkDir()
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "verify-seed", seedDir})
+	c.Assert(err, ErrorMatches, `cannot find any seed system in ".*"`)
+	c.Check(s.Stdout(), Equals, "")
+}
@@ -0,0 +1,125 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdDNS struct {
+	clientMixin
+	Positionals struct {
+		Snap installedSnapName
+	} `positional-args:"true"`
+}
+
+var shortDNSHelp = i18n.G("List snaps overriding the system DNS configuration")
+var longDNSHelp = i18n.G(`
+The dns command lists the snaps whose name resolution does not follow the
+system's configuration, along with the name servers they use instead or
+whether their name resolution is disabled.
+
+The DNS settings of a snap are set by the system administrator with, for
+example:
+
+$ snap set system dns.snaps.<snap>.nameservers=10.0.0.53,10.0.0.54
+$ snap set system dns.snaps.<snap>.disabled=true
+
+and are applied to the mount namespace of the snap.
+`)
+
+func init() {
+	addCommand("dns", shortDNSHelp, longDNSHelp, func() flags.Commander {
+		return &cmdDNS{}
+	}, nil, []argDesc{{
+		// TRANSLATORS: This needs to be wrapped in <>s.
+		name: "<snap>",
+		// TRANSLATORS: This should not start with a lowercase letter.
+		desc: i18n.G("Constrain listing to a specific snap"),
+	}})
+}
+
+// snapDNS returns the name servers of the snap as configured, or "disabled"
+// if its name resolution is disabled, or "" if it follows the system's
+// configuration.
+func snapDNS(conf interface{}) string {
+	settings, _ := conf.(map[string]interface{})
+	switch settings["disabled"] {
+	case true, "true":
+		return i18n.G("disabled")
+	}
+	nameservers, _ := settings["nameservers"].(string)
+	return strings.Join(strings.FieldsFunc(nameservers, func(r rune) bool {
+		return r == ',' || r == ' '
+	}), ",")
+}
+
+func (x *cmdDNS) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	conf, err := x.client.Conf("system", []string{"dns.snaps"})
+	var cerr *client.Error
+	if errors.As(err, &cerr) && cerr.Kind == client.ErrorKindConfigNoSuchOption {
+		conf, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	overrides, _ := conf["dns.snaps"].(map[string]interface{})
+	snapName := string(x.Positionals.Snap)
+	names := make([]string, 0, len(overrides))
+	for name, settings := range overrides {
+		if snapDNS(settings) == "" {
+			continue
+		}
+		if snapName != "" && name != snapName {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		if snapName != "" {
+			fmt.Fprintf(Stderr, i18n.G("Snap %q follows the system DNS configuration.\n"), snapName)
+		} else {
+			fmt.Fprintln(Stderr, i18n.G("No snaps override the system DNS configuration."))
+		}
+		return nil
+	}
+	sort.Strings(names)
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Snap\tName servers"))
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%s\n", name, snapDNS(overrides[name]))
+	}
+	return nil
+}
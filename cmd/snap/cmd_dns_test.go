@@ -0,0 +1,91 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+const dnsConfJSON = `{"type": "sync", "status-code": 200, "result": {"dns.snaps": {
+	"foo": {"nameservers": "10.0.0.1, 10.0.0.2"},
+	"bar": {"disabled": true},
+	"baz": {"disabled": false}
+}}}`
+
+func (s *SnapSuite) mockDNSConf(c *C, body string) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/snaps/system/conf")
+		c.Check(r.URL.Query().Get("keys"), Equals, "dns.snaps")
+		fmt.Fprintln(w, body)
+	})
+}
+
+func (s *SnapSuite) TestDNS(c *C) {
+	s.mockDNSConf(c, dnsConfJSON)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"dns"})
+	c.Assert(err, IsNil)
+	c.Check(rest, HasLen, 0)
+	c.Check(s.Stdout(), Equals, `Snap  Name servers
+bar   disabled
+foo   10.0.0.1,10.0.0.2
+`)
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestDNSSnap(c *C) {
+	s.mockDNSConf(c, dnsConfJSON)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"dns", "foo"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, `Snap  Name servers
+foo   10.0.0.1,10.0.0.2
+`)
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestDNSSnapFollowsSystem(c *C) {
+	s.mockDNSConf(c, dnsConfJSON)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"dns", "baz"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "")
+	c.Check(s.Stderr(), Equals, "Snap \"baz\" follows the system DNS configuration.\n")
+}
+
+func (s *SnapSuite) TestDNSNoOverrides(c *C) {
+	s.mockDNSConf(c, `{"type": "error", "status-code": 400, "result": {"kind": "option-not-found", "message": "snap \"core\" has no \"dns.snaps\" configuration option"}}`)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"dns"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "")
+	c.Check(s.Stderr(), Equals, "No snaps override the system DNS configuration.\n")
+}
+
+func (s *SnapSuite) TestDNSExtraArgs(c *C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"dns", "foo", "bar"})
+	c.Assert(err, ErrorMatches, "too many arguments for command")
+}
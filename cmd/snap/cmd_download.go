@@ -41,8 +41,10 @@ type cmdDownload struct {
 	Basename  string `long:"basename"`
 	TargetDir string `long:"target-directory"`
 
-	CohortKey  string `long:"cohort"`
-	Positional struct {
+	CohortKey    string `long:"cohort"`
+	Architecture string `long:"arch"`
+	Series       string `long:"series"`
+	Positional   struct {
 		Snap remoteSnapName
 	} `positional-args:"true" required:"true"`
 }
@@ -51,6 +53,9 @@ var shortDownloadHelp = i18n.G("Download the given snap")
 var longDownloadHelp = i18n.G(`
 The download command downloads the given snap and its supporting assertions
 to the current directory with .snap and .assert file extensions, respectively.
+
+With --arch the snap is downloaded for the given architecture rather than the
+one of the system, e.g. to build images for other architectures.
 `)
 
 func init() {
@@ -65,6 +70,10 @@ func init() {
 		"basename": i18n.G("Use this basename for the snap and assertion files (defaults to <snap>_<revision>)"),
 		// TRANSLATORS: This should not start with a lowercase letter.
 		"target-directory": i18n.G("Download to this directory (defaults to the current directory)"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"arch": i18n.G("Download the snap for the given architecture (defaults to the architecture of the system)"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"series": i18n.G("Download the snap for the given series"),
 	}), []argDesc{{
 		name: "<snap>",
 		// TRANSLATORS: This should not start with a lowercase letter.
@@ -117,8 +126,8 @@ func printInstallHint(assertPath, snapPath string) {
 // for testing
 var downloadDirect = downloadDirectImpl
 
-func downloadDirectImpl(snapName string, revision snap.Revision, dlOpts tooling.DownloadSnapOptions) error {
-	tsto, err := tooling.NewToolingStore()
+func downloadDirectImpl(snapName string, revision snap.Revision, dlOpts tooling.DownloadSnapOptions, architecture, series string) error {
+	tsto, err := tooling.NewToolingStoreForArchitecture(architecture, series)
 	if err != nil {
 		return err
 	}
@@ -149,7 +158,7 @@ func (x *cmdDownload) downloadFromStore(snapName string, revision snap.Revision)
 		// if something goes wrong, don't force it to start over again
 		LeavePartialOnError: true,
 	}
-	return downloadDirect(snapName, revision, dlOpts)
+	return downloadDirect(snapName, revision, dlOpts, x.Architecture, x.Series)
 }
 
 func (x *cmdDownload) Execute(args []string) error {
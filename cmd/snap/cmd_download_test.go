@@ -87,13 +87,15 @@ func (s *SnapSuite) TestPrintInstalHint(c *check.C) {
 
 func (s *SnapSuite) TestDownloadDirect(c *check.C) {
 	var n int
-	restore := snapCmd.MockDownloadDirect(func(snapName string, revision snap.Revision, dlOpts tooling.DownloadSnapOptions) error {
+	restore := snapCmd.MockDownloadDirect(func(snapName string, revision snap.Revision, dlOpts tooling.DownloadSnapOptions, architecture, series string) error {
 		c.Check(snapName, check.Equals, "a-snap")
 		c.Check(revision, check.Equals, snap.R(0))
 		c.Check(dlOpts.Basename, check.Equals, "some-base-name")
 		c.Check(dlOpts.TargetDir, check.Equals, "some-target-dir")
 		c.Check(dlOpts.Channel, check.Equals, "some-channel")
 		c.Check(dlOpts.CohortKey, check.Equals, "some-cohort")
+		c.Check(architecture, check.Equals, "")
+		c.Check(series, check.Equals, "")
 		n++
 		return nil
 	})
@@ -112,9 +114,30 @@ func (s *SnapSuite) TestDownloadDirect(c *check.C) {
 	c.Check(n, check.Equals, 1)
 }
 
+func (s *SnapSuite) TestDownloadDirectForeignArchitecture(c *check.C) {
+	var n int
+	restore := snapCmd.MockDownloadDirect(func(snapName string, revision snap.Revision, dlOpts tooling.DownloadSnapOptions, architecture, series string) error {
+		c.Check(snapName, check.Equals, "a-snap")
+		c.Check(architecture, check.Equals, "riscv64")
+		c.Check(series, check.Equals, "16")
+		n++
+		return nil
+	})
+	defer restore()
+
+	_, err := snapCmd.Parser(snapCmd.Client()).ParseArgs([]string{
+		"download",
+		"--arch=riscv64",
+		"--series=16",
+		"a-snap"},
+	)
+	c.Assert(err, check.IsNil)
+	c.Check(n, check.Equals, 1)
+}
+
 func (s *SnapSuite) TestDownloadDirectErrors(c *check.C) {
 	var n int
-	restore := snapCmd.MockDownloadDirect(func(snapName string, revision snap.Revision, dlOpts tooling.DownloadSnapOptions) error {
+	restore := snapCmd.MockDownloadDirect(func(snapName string, revision snap.Revision, dlOpts tooling.DownloadSnapOptions, architecture, series string) error {
 		n++
 		return fmt.Errorf("some-error")
 	})
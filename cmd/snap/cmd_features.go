@@ -0,0 +1,185 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/strutil"
+)
+
+var shortFeaturesHelp = i18n.G("List and toggle experimental features")
+var longFeaturesHelp = i18n.G(`
+The features command lists the features of snapd that can be toggled, along
+with their maturity and whether they are enabled.
+
+The notes indicate whether the applications of snaps need to be restarted
+(restart) or the system rebooted (reboot) for a change of the feature to take
+effect, and which other features it requires to be enabled.
+
+With --enable or --disable, the given features are toggled instead. A feature
+cannot be enabled unless the features it requires are, or are enabled along
+with it.
+`)
+
+type cmdFeatures struct {
+	waitMixin
+	Enable  []string `long:"enable" arg-name:"<feature>"`
+	Disable []string `long:"disable" arg-name:"<feature>"`
+}
+
+func init() {
+	addCommand("features", shortFeaturesHelp, longFeaturesHelp, func() flags.Commander {
+		return &cmdFeatures{}
+	}, waitDescs.also(map[string]string{
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"enable": i18n.G("Enable the given feature"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"disable": i18n.G("Disable the given feature"),
+	}), nil)
+}
+
+func (x *cmdFeatures) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	sysInfo, err := x.client.SysInfo()
+	if err != nil {
+		return err
+	}
+	if len(sysInfo.Features) == 0 {
+		return fmt.Errorf(i18n.G("cannot list features: not supported by snapd"))
+	}
+
+	if len(x.Enable) == 0 && len(x.Disable) == 0 {
+		x.showFeatures(sysInfo.Features)
+		return nil
+	}
+	return x.toggleFeatures(sysInfo.Features)
+}
+
+func featureNotes(f *client.Feature) string {
+	var notes []string
+	if f.RequiresRestart {
+		notes = append(notes, "restart")
+	}
+	if f.RequiresReboot {
+		notes = append(notes, "reboot")
+	}
+	for _, prereq := range f.Prerequisites {
+		notes = append(notes, "requires:"+prereq)
+	}
+	if len(notes) == 0 {
+		return "-"
+	}
+	return strings.Join(notes, ",")
+}
+
+func (x *cmdFeatures) showFeatures(features []client.Feature) {
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Feature\tMaturity\tStatus\tNotes\tDescription"))
+	for i := range features {
+		f := &features[i]
+		status := i18n.G("disabled")
+		if f.Enabled {
+			status = i18n.G("enabled")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", f.Name, f.Maturity, status, featureNotes(f), f.Description)
+	}
+}
+
+// toggleFeatures enables and disables the requested features, after
+// checking that the prerequisites of the enabled features are met.
+func (x *cmdFeatures) toggleFeatures(features []client.Feature) error {
+	byName := make(map[string]*client.Feature, len(features))
+	enabled := make(map[string]bool, len(features))
+	for i := range features {
+		f := &features[i]
+		byName[f.Name] = f
+		enabled[f.Name] = f.Enabled
+	}
+
+	patch := make(map[string]interface{}, len(x.Enable)+len(x.Disable))
+	for _, name := range x.Enable {
+		if strutil.ListContains(x.Disable, name) {
+			return fmt.Errorf(i18n.G("cannot both enable and disable feature %q"), name)
+		}
+		patch["experimental."+name] = true
+		enabled[name] = true
+	}
+	for _, name := range x.Disable {
+		patch["experimental."+name] = false
+		enabled[name] = false
+	}
+	for _, name := range append(x.Enable, x.Disable...) {
+		if byName[name] == nil {
+			return fmt.Errorf(i18n.G("unknown feature %q"), name)
+		}
+	}
+
+	for _, f := range features {
+		if !enabled[f.Name] {
+			continue
+		}
+		for _, prereq := range f.Prerequisites {
+			if enabled[prereq] {
+				continue
+			}
+			if strutil.ListContains(x.Disable, prereq) {
+				return fmt.Errorf(i18n.G("cannot disable feature %q: required by enabled feature %q"), prereq, f.Name)
+			}
+			if strutil.ListContains(x.Enable, f.Name) {
+				return fmt.Errorf(i18n.G("cannot enable feature %q: requires feature %q to be enabled"), f.Name, prereq)
+			}
+		}
+	}
+
+	id, err := x.client.SetConf("system", patch)
+	if err != nil {
+		return err
+	}
+	if _, err := x.wait(id); err != nil {
+		if err == noWait {
+			return nil
+		}
+		return err
+	}
+
+	for _, name := range append(x.Enable, x.Disable...) {
+		f := byName[name]
+		switch {
+		case f.Enabled == enabled[name]:
+			continue
+		case f.RequiresReboot:
+			fmt.Fprintf(Stdout, i18n.G("Reboot the system for the change of feature %q to take effect.\n"), name)
+		case f.RequiresRestart:
+			fmt.Fprintf(Stdout, i18n.G("Restart the applications of snaps for the change of feature %q to take effect.\n"), name)
+		}
+	}
+	return nil
+}
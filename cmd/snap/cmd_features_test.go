@@ -0,0 +1,150 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+const mockFeaturesSysInfo = `{"type": "sync", "result": {"features": [
+{"name": "hotplug", "description": "Create slots for hardware devices as they are plugged in", "maturity": "experimental", "enabled": false},
+{"name": "layouts", "description": "Support for layouts in snaps", "maturity": "stable", "enabled": true},
+{"name": "mounts-generator", "description": "Generate the mount units of snaps at boot", "maturity": "experimental", "enabled": false, "requires-reboot": true},
+{"name": "per-user-mount-namespace", "description": "Persist the mount namespaces of snaps for each user", "maturity": "experimental", "enabled": true, "requires-restart": true},
+{"name": "refresh-app-awareness", "description": "Postpone refreshes of snaps with running applications", "maturity": "stable", "enabled": %t},
+{"name": "refresh-app-awareness-ux", "description": "Notify users about refreshes of running snaps", "maturity": "experimental", "enabled": %t, "prerequisites": ["refresh-app-awareness"]}
+]}}`
+
+func (s *SnapSuite) mockFeaturesServer(c *C, awareness, awarenessUX bool, expectedConf map[string]interface{}) *int {
+	confCalls := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/system-info":
+			c.Check(r.Method, Equals, "GET")
+			fmt.Fprintf(w, mockFeaturesSysInfo, awareness, awarenessUX)
+		case "/v2/snaps/system/conf":
+			c.Check(r.Method, Equals, "PUT")
+			c.Check(DecodedRequestBody(c, r), DeepEquals, expectedConf)
+			confCalls++
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type":"async", "status-code": 202, "change": "42"}`)
+		case "/v2/changes/42":
+			c.Check(r.Method, Equals, "GET")
+			fmt.Fprintln(w, `{"type":"sync", "result":{"ready": true, "status": "Done"}}`)
+		default:
+			c.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	})
+	return &confCalls
+}
+
+func (s *SnapSuite) TestFeatures(c *C) {
+	s.mockFeaturesServer(c, true, false, nil)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"features"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, HasLen, 0)
+	c.Check(s.Stdout(), Equals, `
+Feature                   Maturity      Status    Notes                           Description
+hotplug                   experimental  disabled  -                               Create slots for hardware devices as they are plugged in
+layouts                   stable        enabled   -                               Support for layouts in snaps
+mounts-generator          experimental  disabled  reboot                          Generate the mount units of snaps at boot
+per-user-mount-namespace  experimental  enabled   restart                         Persist the mount namespaces of snaps for each user
+refresh-app-awareness     stable        enabled   -                               Postpone refreshes of snaps with running applications
+refresh-app-awareness-ux  experimental  disabled  requires:refresh-app-awareness  Notify users about refreshes of running snaps
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestFeaturesNotSupported(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": {"series": "16"}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"features"})
+	c.Assert(err, ErrorMatches, `cannot list features: not supported by snapd`)
+}
+
+func (s *SnapSuite) TestFeaturesToggle(c *C) {
+	confCalls := s.mockFeaturesServer(c, true, false, map[string]interface{}{
+		"experimental.hotplug":                  true,
+		"experimental.mounts-generator":         true,
+		"experimental.per-user-mount-namespace": false,
+		"experimental.layouts":                  true,
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"features",
+		"--enable=hotplug", "--enable=mounts-generator", "--enable=layouts", "--disable=per-user-mount-namespace"})
+	c.Assert(err, IsNil)
+	c.Check(*confCalls, Equals, 1)
+	// layouts was already enabled
+	c.Check(s.Stdout(), Equals, `
+Reboot the system for the change of feature "mounts-generator" to take effect.
+Restart the applications of snaps for the change of feature "per-user-mount-namespace" to take effect.
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestFeaturesToggleWithPrerequisites(c *C) {
+	confCalls := s.mockFeaturesServer(c, false, false, map[string]interface{}{
+		"experimental.refresh-app-awareness":    true,
+		"experimental.refresh-app-awareness-ux": true,
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"features",
+		"--enable=refresh-app-awareness-ux", "--enable=refresh-app-awareness"})
+	c.Assert(err, IsNil)
+	c.Check(*confCalls, Equals, 1)
+}
+
+func (s *SnapSuite) TestFeaturesToggleErrors(c *C) {
+	for _, tc := range []struct {
+		args                   []string
+		awareness, awarenessUX bool
+		err                    string
+	}{{
+		args: []string{"--enable=foo"},
+		err:  `unknown feature "foo"`,
+	}, {
+		args: []string{"--disable=foo"},
+		err:  `unknown feature "foo"`,
+	}, {
+		args: []string{"--enable=hotplug", "--disable=hotplug"},
+		err:  `cannot both enable and disable feature "hotplug"`,
+	}, {
+		args: []string{"--enable=refresh-app-awareness-ux"},
+		err:  `cannot enable feature "refresh-app-awareness-ux": requires feature "refresh-app-awareness" to be enabled`,
+	}, {
+		args:        []string{"--disable=refresh-app-awareness"},
+		awareness:   true,
+		awarenessUX: true,
+		err:         `cannot disable feature "refresh-app-awareness": required by enabled feature "refresh-app-awareness-ux"`,
+	}} {
+		confCalls := s.mockFeaturesServer(c, tc.awareness, tc.awarenessUX, nil)
+		_, err := snap.Parser(snap.Client()).ParseArgs(append([]string{"features"}, tc.args...))
+		c.Check(err, ErrorMatches, tc.err, Commentf("%v", tc.args))
+		c.Check(*confCalls, Equals, 0)
+	}
+}
@@ -21,6 +21,7 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -33,6 +34,7 @@ import (
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/i18n"
 	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/strutil"
 )
 
@@ -45,6 +47,9 @@ With the --private flag, which requires the user to be logged-in to the store
 has developer access to, either directly or through the store's collaboration
 feature.
 
+The --category flag restricts the search to a store category, and the
+results can be sorted by their release date or popularity with --sort.
+
 A green check mark (given color and unicode support) after a publisher name
 indicates that the publisher has been verified.
 `)
@@ -154,6 +159,9 @@ type cmdFind struct {
 	Private    bool        `long:"private"`
 	Narrow     bool        `long:"narrow"`
 	Section    SectionName `long:"section" optional:"true" optional-value:"show-all-sections-please" default:"no-section-specified" default-mask:"-"`
+	Category   string      `long:"category"`
+	Sort       string      `long:"sort" choice:"recent" choice:"popular"`
+	JSON       bool        `long:"json"`
 	Positional struct {
 		Query []string
 	} `positional-args:"yes"`
@@ -170,6 +178,12 @@ func init() {
 		"narrow": i18n.G("Only search for snaps in “stable”."),
 		// TRANSLATORS: This should not start with a lowercase letter.
 		"section": i18n.G("Restrict the search to a given section."),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"category": i18n.G("Restrict the search to a given store category."),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"sort": i18n.G("Sort the results by most recent or most popular instead of relevance."),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"json": i18n.G("Output results in JSON format"),
 	}), []argDesc{{
 		// TRANSLATORS: This needs to begin with < and end with >
 		name: i18n.G("<query>"),
@@ -200,8 +214,12 @@ func (x *cmdFind) Execute(args []string) error {
 		x.Section = ""
 	}
 
+	if x.Section != "" && x.Category != "" {
+		return errors.New(i18n.G("cannot use --section and --category together"))
+	}
+
 	// magic! `snap find` returns the featured snaps
-	showFeatured := (query == "" && x.Section == "")
+	showFeatured := (query == "" && x.Section == "" && x.Category == "")
 	if showFeatured {
 		x.Section = "featured"
 	}
@@ -224,10 +242,18 @@ func (x *cmdFind) Execute(args []string) error {
 		}
 	}
 
+	if x.Category != "" {
+		if err := x.checkCategory(); err != nil {
+			return err
+		}
+	}
+
 	opts := &client.FindOptions{
-		Query:   query,
-		Section: string(x.Section),
-		Private: x.Private,
+		Query:    query,
+		Section:  string(x.Section),
+		Category: x.Category,
+		Private:  x.Private,
+		Sort:     x.Sort,
 	}
 
 	if !x.Narrow {
@@ -242,8 +268,16 @@ func (x *cmdFind) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
+	if x.JSON {
+		return outputFindJSON(snaps)
+	}
 	if len(snaps) == 0 {
-		if x.Section == "" {
+		if x.Category != "" {
+			// TRANSLATORS: the first %q is the (quoted) query, the
+			// second %q is the (quoted) name of the category the
+			// user entered
+			fmt.Fprintf(Stderr, i18n.G("No matching snaps for %q in category %q\n"), opts.Query, x.Category)
+		} else if x.Section == "" {
 			// TRANSLATORS: the %q is the (quoted) query the user entered
 			fmt.Fprintf(Stderr, i18n.G("No matching snaps for %q\n"), opts.Query)
 		} else {
@@ -273,3 +307,59 @@ func (x *cmdFind) Execute(args []string) error {
 	}
 	return nil
 }
+
+// checkCategory checks that the category to search in exists in the store.
+func (x *cmdFind) checkCategory() error {
+	categories, err := x.client.Categories()
+	if err != nil {
+		return err
+	}
+	for _, category := range categories {
+		if category.Name == x.Category {
+			return nil
+		}
+	}
+	// TRANSLATORS: the %q is the (quoted) name of the category the user entered
+	return fmt.Errorf(i18n.G("No matching category %q"), x.Category)
+}
+
+// findResult is a snap found in the store, as output with --json.
+type findResult struct {
+	Name        string              `json:"name"`
+	Title       string              `json:"title,omitempty"`
+	Version     string              `json:"version"`
+	Publisher   string              `json:"publisher,omitempty"`
+	Summary     string              `json:"summary"`
+	License     string              `json:"license,omitempty"`
+	Confinement string              `json:"confinement"`
+	Categories  []snap.CategoryInfo `json:"categories,omitempty"`
+	Media       snap.MediaInfos     `json:"media,omitempty"`
+	StoreURL    string              `json:"store-url,omitempty"`
+}
+
+func outputFindJSON(snaps []*client.Snap) error {
+	results := make([]findResult, 0, len(snaps))
+	for _, snp := range snaps {
+		res := findResult{
+			Name:        snp.Name,
+			Title:       snp.Title,
+			Version:     snp.Version,
+			Summary:     snp.Summary,
+			License:     snp.License,
+			Confinement: snp.Confinement,
+			Categories:  snp.Categories,
+			Media:       snp.Media,
+			StoreURL:    snp.StoreURL,
+		}
+		if snp.Publisher != nil {
+			res.Publisher = snp.Publisher.Username
+		}
+		results = append(results, res)
+	}
+	out, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, "%s\n", out)
+	return nil
+}
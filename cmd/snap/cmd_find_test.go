@@ -698,3 +698,162 @@ Please try 'snap find --section=<selected section>'
 	s.ResetStdStreams()
 	c.Check(numHits, check.Equals, 1)
 }
+
+const findMediaJSON = `
+{
+  "type": "sync",
+  "status-code": 200,
+  "status": "OK",
+  "result": [
+    {
+      "confinement": "strict",
+      "developer": "canonical",
+      "publisher": {
+         "id": "canonical",
+         "username": "canonical",
+         "display-name": "Canonical",
+         "validation": "verified"
+      },
+      "id": "mVyGrEwiqSi5PugCwyH7WgpoQLemtTd6",
+      "name": "hello",
+      "title": "Hello",
+      "summary": "GNU Hello, the \"hello world\" snap",
+      "type": "app",
+      "version": "2.10",
+      "license": "GPL-3.0",
+      "categories": [{"name": "utilities", "featured": true}],
+      "media": [{"type": "icon", "url": "https://example.com/icon.png", "width": 256, "height": 256}],
+      "store-url": "https://snapcraft.io/hello"
+    }
+  ],
+  "sources": [
+    "store"
+  ],
+  "suggested-currency": "GBP"
+}
+`
+
+func (s *SnapSuite) mockFindCategory(c *check.C, expectedFind url.Values, findJSON string) *int {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/categories")
+			EncodeResponseBody(c, w, map[string]interface{}{
+				"type":   "sync",
+				"result": []map[string]string{{"name": "featured"}, {"name": "utilities"}},
+			})
+		case 1:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/find")
+			c.Check(r.URL.Query(), check.DeepEquals, expectedFind)
+			fmt.Fprint(w, findJSON)
+		default:
+			c.Fatalf("expected to get 2 requests, now on %d", n+1)
+		}
+		n++
+	})
+	return &n
+}
+
+func (s *SnapSuite) TestFindCategorySorted(c *check.C) {
+	n := s.mockFindCategory(c, url.Values{
+		"category": []string{"utilities"},
+		"sort":     []string{"popular"},
+		"scope":    []string{"wide"},
+	}, findMediaJSON)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"find", "--category=utilities", "--sort=popular"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Matches, `Name +Version +Publisher +Notes +Summary
+hello +2.10 +canonical\*\* +- +GNU Hello, the "hello world" snap
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(*n, check.Equals, 2)
+}
+
+func (s *SnapSuite) TestFindJSON(c *check.C) {
+	n := s.mockFindCategory(c, url.Values{
+		"q":        []string{"hello"},
+		"category": []string{"utilities"},
+		"scope":    []string{"wide"},
+	}, findMediaJSON)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"find", "--category=utilities", "--json", "hello"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, `[
+  {
+    "name": "hello",
+    "title": "Hello",
+    "version": "2.10",
+    "publisher": "canonical",
+    "summary": "GNU Hello, the \"hello world\" snap",
+    "license": "GPL-3.0",
+    "confinement": "strict",
+    "categories": [
+      {
+        "name": "utilities",
+        "featured": true
+      }
+    ],
+    "media": [
+      {
+        "type": "icon",
+        "url": "https://example.com/icon.png",
+        "width": 256,
+        "height": 256
+      }
+    ],
+    "store-url": "https://snapcraft.io/hello"
+  }
+]
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(*n, check.Equals, 2)
+}
+
+func (s *SnapSuite) TestFindJSONNothingFound(c *check.C) {
+	s.mockFindCategory(c, url.Values{
+		"q":        []string{"hello"},
+		"category": []string{"utilities"},
+		"scope":    []string{"wide"},
+	}, `{"type": "sync", "result": []}`)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"find", "--category=utilities", "--json", "hello"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "[]\n")
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestFindSnapNotFoundInCategory(c *check.C) {
+	s.mockFindCategory(c, url.Values{
+		"q":        []string{"hello"},
+		"category": []string{"utilities"},
+		"scope":    []string{"wide"},
+	}, `{"type": "sync", "result": []}`)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"find", "--category=utilities", "hello"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "No matching snaps for \"hello\" in category \"utilities\"\n")
+}
+
+func (s *SnapSuite) TestFindSnapInvalidCategory(c *check.C) {
+	n := s.mockFindCategory(c, nil, "")
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"find", "--category=foobar", "hello"})
+	c.Assert(err, check.ErrorMatches, `No matching category "foobar"`)
+	c.Check(*n, check.Equals, 1)
+}
+
+func (s *SnapSuite) TestFindSectionAndCategory(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"find", "--section=foo", "--category=bar", "hello"})
+	c.Assert(err, check.ErrorMatches, `cannot use --section and --category together`)
+}
+
+func (s *SnapSuite) TestFindInvalidSort(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"find", "--sort=oldest", "hello"})
+	c.Assert(err, check.ErrorMatches, `Invalid value .oldest. for option .--sort.. Allowed values are: recent or popular`)
+}
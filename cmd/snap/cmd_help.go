@@ -207,7 +207,7 @@ var helpCategories = []helpCategory{
 	}, {
 		Label:       i18n.G("...more"),
 		Description: i18n.G("slightly more advanced snap management"),
-		Commands:    []string{"refresh", "revert", "switch", "disable", "enable", "create-cohort"},
+		Commands:    []string{"refresh", "revert", "switch", "disable", "enable", "release-quarantine", "create-cohort", "cohort"},
 	}, {
 		Label:       i18n.G("History"),
 		Description: i18n.G("manage system change transactions"),
@@ -217,13 +217,15 @@ var helpCategories = []helpCategory{
 		Description: i18n.G("manage services"),
 		Commands:    []string{"services", "start", "stop", "restart", "logs"},
 	}, {
-		Label:       i18n.G("Permissions"),
-		Description: i18n.G("manage permissions"),
-		Commands:    []string{"connections", "interface", "connect", "disconnect"},
+		Label:           i18n.G("Permissions"),
+		Description:     i18n.G("manage permissions"),
+		Commands:        []string{"connections", "interface", "connect", "disconnect"},
+		AllOnlyCommands: []string{"confinement-report", "auth"},
 	}, {
-		Label:       i18n.G("Configuration"),
-		Description: i18n.G("system administration and configuration"),
-		Commands:    []string{"get", "set", "unset", "wait"},
+		Label:           i18n.G("Configuration"),
+		Description:     i18n.G("system administration and configuration"),
+		Commands:        []string{"get", "set", "unset", "wait"},
+		AllOnlyCommands: []string{"dns", "cache", "kernel-modules", "features"},
 	}, {
 		Label:       i18n.G("App Aliases"),
 		Description: i18n.G("manage aliases"),
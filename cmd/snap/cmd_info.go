@@ -20,6 +20,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -50,6 +51,7 @@ type infoCmd struct {
 	timeMixin
 
 	Verbose    bool `long:"verbose"`
+	JSON       bool `long:"json"`
 	Positional struct {
 		Snaps []anySnapName `positional-arg-name:"<snap>" required:"1"`
 	} `positional-args:"yes" required:"yes"`
@@ -63,6 +65,10 @@ The snaps can be specified by name or by path; names are looked for both in the
 store and in the installed snaps; paths can refer to a .snap file, or to a
 directory that contains an unpacked snap suitable for 'snap try' (an example
 of this would be the 'prime' directory snapcraft produces).
+
+For paths, the --verbose flag also shows the apps, hooks, plugs and slots with
+their attributes, layouts and registry views declared by the snap, and --json
+shows all of its metadata in JSON format.
 `)
 
 func init() {
@@ -74,25 +80,33 @@ func init() {
 		}, colorDescs.also(timeDescs).also(map[string]string{
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"verbose": i18n.G("Include more details on the snap (expanded notes, base, etc.)"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"json": i18n.G("Output the metadata of snap files in JSON format"),
 		}), nil)
 }
 
 func clientSnapFromPath(path string) (*client.Snap, error) {
+	_, direct, err := snapFromPath(path)
+	return direct, err
+}
+
+// snapFromPath reads the info of the snap file or directory at path.
+func snapFromPath(path string) (*snap.Info, *client.Snap, error) {
 	snapf, err := snapfile.Open(path)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	info, err := snap.ReadInfoFromSnapFile(snapf, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	direct, err := clientutil.ClientSnapFromSnapInfo(info, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return direct, nil
+	return info, direct, nil
 }
 
 func norm(path string) string {
@@ -152,6 +166,7 @@ type infoWriter struct {
 	// fields that are set every iteration
 	theSnap    *client.Snap
 	diskSnap   *client.Snap
+	diskInfo   *snap.Info
 	localSnap  *client.Snap
 	remoteSnap *client.Snap
 	resInfo    *client.ResultInfo
@@ -168,12 +183,12 @@ type infoWriter struct {
 func (iw *infoWriter) setupDiskSnap(path string, diskSnap *client.Snap) {
 	iw.localSnap, iw.remoteSnap, iw.resInfo = nil, nil, nil
 	iw.path = path
-	iw.diskSnap = diskSnap
+	iw.diskSnap, iw.diskInfo = diskSnap, nil
 	iw.theSnap = diskSnap
 }
 
 func (iw *infoWriter) setupSnap(localSnap, remoteSnap *client.Snap, resInfo *client.ResultInfo) {
-	iw.path, iw.diskSnap = "", nil
+	iw.path, iw.diskSnap, iw.diskInfo = "", nil, nil
 	iw.localSnap = localSnap
 	iw.remoteSnap = remoteSnap
 	iw.resInfo = resInfo
@@ -258,6 +273,9 @@ func (iw *infoWriter) maybePrintTrackingChannel() {
 		return
 	}
 	fmt.Fprintf(iw, "tracking:\t%s\n", iw.localSnap.TrackingChannel)
+	if iw.localSnap.FallbackChannel != "" {
+		fmt.Fprintf(iw, "fallback:\t%s\n", iw.localSnap.FallbackChannel)
+	}
 }
 
 func (iw *infoWriter) maybePrintRefreshInfo() {
@@ -500,9 +518,6 @@ func (iw *infoWriter) maybePrintNotes() {
 }
 
 func (iw *infoWriter) maybePrintCohortKey() {
-	if !iw.verbose {
-		return
-	}
 	if iw.localSnap == nil {
 		return
 	}
@@ -517,6 +532,13 @@ func (iw *infoWriter) maybePrintCohortKey() {
 	fmt.Fprintf(iw, "cohort:\t%s\n", coh)
 }
 
+func (iw *infoWriter) maybePrintDiskMetadata() {
+	if !iw.verbose || iw.diskInfo == nil {
+		return
+	}
+	newDiskSnapMetadata(iw.path, iw.diskInfo).print(iw)
+}
+
 func (iw *infoWriter) maybePrintSum() {
 	if !iw.verbose {
 		return
@@ -617,7 +639,34 @@ func (chInfos *channelInfos) dump(w io.Writer) {
 	}
 }
 
+// printDiskSnapsJSON prints the metadata of the given snap files or
+// directories in JSON format.
+func printDiskSnapsJSON(paths []string) error {
+	mds := make([]*diskSnapMetadata, 0, len(paths))
+	for _, path := range paths {
+		info, _, err := snapFromPath(path)
+		if err != nil {
+			return fmt.Errorf(i18n.G("cannot read snap file %q: %v"), path, err)
+		}
+		mds = append(mds, newDiskSnapMetadata(norm(path), info))
+	}
+	out, err := json.MarshalIndent(mds, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, "%s\n", out)
+	return nil
+}
+
 func (x *infoCmd) Execute([]string) error {
+	if x.JSON {
+		paths := make([]string, 0, len(x.Positional.Snaps))
+		for _, snapName := range x.Positional.Snaps {
+			paths = append(paths, string(snapName))
+		}
+		return printDiskSnapsJSON(paths)
+	}
+
 	termWidth, _ := termSize()
 	termWidth -= 3
 	if termWidth > 100 {
@@ -647,8 +696,9 @@ func (x *infoCmd) Execute([]string) error {
 			continue
 		}
 
-		if diskSnap, err := clientSnapFromPath(snapName); err == nil {
+		if diskInfo, diskSnap, err := snapFromPath(snapName); err == nil {
 			iw.setupDiskSnap(norm(snapName), diskSnap)
+			iw.diskInfo = diskInfo
 		} else {
 			remoteSnap, resInfo, _ := x.client.FindOne(snap.InstanceSnap(snapName))
 			localSnap, _, _ := x.client.Snap(snapName)
@@ -686,6 +736,7 @@ func (x *infoCmd) Execute([]string) error {
 		iw.Flush()
 		iw.maybePrintType()
 		iw.maybePrintBase()
+		iw.maybePrintDiskMetadata()
 		iw.maybePrintSum()
 		iw.maybePrintID()
 		iw.maybePrintCohortKey()
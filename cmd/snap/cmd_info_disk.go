@@ -0,0 +1,280 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/snapcore/snapd/snap"
+)
+
+// registryInterface is the interface of the plugs through which snaps
+// declare the registry views they access.
+const registryInterface = "registry"
+
+// diskSnapApp is an app of a snap file, as shown by snap info.
+type diskSnapApp struct {
+	Name    string `json:"name"`
+	Command string `json:"command,omitempty"`
+	// Daemon is the type of the daemon if the app is a service.
+	Daemon string   `json:"daemon,omitempty"`
+	Plugs  []string `json:"plugs,omitempty"`
+	Slots  []string `json:"slots,omitempty"`
+}
+
+// diskSnapHook is a hook of a snap file, as shown by snap info.
+type diskSnapHook struct {
+	Name  string   `json:"name"`
+	Plugs []string `json:"plugs,omitempty"`
+	Slots []string `json:"slots,omitempty"`
+}
+
+// diskSnapInterface is a plug or slot of a snap file, as shown by snap info.
+type diskSnapInterface struct {
+	Name      string                 `json:"name"`
+	Interface string                 `json:"interface"`
+	Attrs     map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// diskSnapLayout is a layout of a snap file, as shown by snap info.
+type diskSnapLayout struct {
+	Path string `json:"path"`
+	// Kind is the kind of the layout, one of "bind", "bind-file",
+	// "symlink" or "type".
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+}
+
+// diskSnapRegistryView is a registry view declared by a snap file through a
+// plug of the registry interface.
+type diskSnapRegistryView struct {
+	Plug    string `json:"plug"`
+	Account string `json:"account"`
+	View    string `json:"view"`
+}
+
+// diskSnapMetadata is the metadata of a snap file or directory, read
+// directly from the container.
+type diskSnapMetadata struct {
+	Path          string                 `json:"path"`
+	Name          string                 `json:"name"`
+	Version       string                 `json:"version"`
+	Summary       string                 `json:"summary,omitempty"`
+	Description   string                 `json:"description,omitempty"`
+	Type          string                 `json:"type"`
+	Base          string                 `json:"base,omitempty"`
+	Epoch         string                 `json:"epoch"`
+	Confinement   string                 `json:"confinement"`
+	License       string                 `json:"license,omitempty"`
+	Apps          []diskSnapApp          `json:"apps,omitempty"`
+	Hooks         []diskSnapHook         `json:"hooks,omitempty"`
+	Plugs         []diskSnapInterface    `json:"plugs,omitempty"`
+	Slots         []diskSnapInterface    `json:"slots,omitempty"`
+	Layouts       []diskSnapLayout       `json:"layouts,omitempty"`
+	RegistryViews []diskSnapRegistryView `json:"registry-views,omitempty"`
+}
+
+func sortedPlugNames(plugs map[string]*snap.PlugInfo) []string {
+	names := make([]string, 0, len(plugs))
+	for name := range plugs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedSlotNames(slots map[string]*snap.SlotInfo) []string {
+	names := make([]string, 0, len(slots))
+	for name := range slots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func layoutTarget(l *snap.Layout) string {
+	switch {
+	case l.Bind != "":
+		return l.Bind
+	case l.BindFile != "":
+		return l.BindFile
+	case l.Symlink != "":
+		return l.Symlink
+	}
+	return l.Type
+}
+
+// newDiskSnapMetadata collects the metadata of the snap file or directory
+// at path from its info.
+func newDiskSnapMetadata(path string, info *snap.Info) *diskSnapMetadata {
+	md := &diskSnapMetadata{
+		Path:        path,
+		Name:        info.InstanceName(),
+		Version:     info.Version,
+		Summary:     info.Summary(),
+		Description: info.Description(),
+		Type:        string(info.Type()),
+		Base:        info.Base,
+		Epoch:       info.Epoch.String(),
+		Confinement: string(info.Confinement),
+		License:     info.License,
+	}
+
+	for _, app := range info.Apps {
+		md.Apps = append(md.Apps, diskSnapApp{
+			Name:    app.Name,
+			Command: app.Command,
+			Daemon:  app.Daemon,
+			Plugs:   sortedPlugNames(app.Plugs),
+			Slots:   sortedSlotNames(app.Slots),
+		})
+	}
+	sort.Slice(md.Apps, func(i, j int) bool { return md.Apps[i].Name < md.Apps[j].Name })
+
+	for _, hook := range info.Hooks {
+		md.Hooks = append(md.Hooks, diskSnapHook{
+			Name:  hook.Name,
+			Plugs: sortedPlugNames(hook.Plugs),
+			Slots: sortedSlotNames(hook.Slots),
+		})
+	}
+	sort.Slice(md.Hooks, func(i, j int) bool { return md.Hooks[i].Name < md.Hooks[j].Name })
+
+	for _, name := range sortedPlugNames(info.Plugs) {
+		plug := info.Plugs[name]
+		md.Plugs = append(md.Plugs, diskSnapInterface{
+			Name:      plug.Name,
+			Interface: plug.Interface,
+			Attrs:     plug.Attrs,
+		})
+		if plug.Interface != registryInterface {
+			continue
+		}
+		view := diskSnapRegistryView{Plug: plug.Name}
+		// the attributes are validated when the snap is installed
+		view.Account, _ = plug.Attrs["account"].(string)
+		view.View, _ = plug.Attrs["view"].(string)
+		md.RegistryViews = append(md.RegistryViews, view)
+	}
+
+	for _, name := range sortedSlotNames(info.Slots) {
+		slot := info.Slots[name]
+		md.Slots = append(md.Slots, diskSnapInterface{
+			Name:      slot.Name,
+			Interface: slot.Interface,
+			Attrs:     slot.Attrs,
+		})
+	}
+
+	for _, layout := range info.Layout {
+		md.Layouts = append(md.Layouts, diskSnapLayout{
+			Path:   layout.Path,
+			Kind:   layout.Kind(),
+			Target: layoutTarget(layout),
+		})
+	}
+	sort.Slice(md.Layouts, func(i, j int) bool { return md.Layouts[i].Path < md.Layouts[j].Path })
+
+	return md
+}
+
+// formatAttr formats the value of an interface attribute as shown by snap
+// info, strings as they are and anything else as JSON.
+func formatAttr(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(b)
+}
+
+func printDiskSnapInterfaces(w io.Writer, header string, ifaces []diskSnapInterface) {
+	if len(ifaces) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s:\n", header)
+	for _, iface := range ifaces {
+		if iface.Name == iface.Interface {
+			fmt.Fprintf(w, "  %s:\n", iface.Name)
+		} else {
+			fmt.Fprintf(w, "  %s:\t%s\n", iface.Name, iface.Interface)
+		}
+		attrNames := make([]string, 0, len(iface.Attrs))
+		for name := range iface.Attrs {
+			attrNames = append(attrNames, name)
+		}
+		sort.Strings(attrNames)
+		for _, name := range attrNames {
+			fmt.Fprintf(w, "    %s:\t%s\n", name, formatAttr(iface.Attrs[name]))
+		}
+	}
+}
+
+// print writes the parts of the metadata that snap info does not show for
+// all snaps.
+func (md *diskSnapMetadata) print(w io.Writer) {
+	fmt.Fprintf(w, "epoch:\t%s\n", md.Epoch)
+
+	if len(md.Apps) > 0 {
+		fmt.Fprintln(w, "apps:")
+		for _, app := range md.Apps {
+			kind := "command"
+			if app.Daemon != "" {
+				kind = app.Daemon + " daemon"
+			}
+			fmt.Fprintf(w, "  %s:\t%s\n", snap.JoinSnapApp(md.Name, app.Name), kind)
+		}
+	}
+
+	if len(md.Hooks) > 0 {
+		fmt.Fprintln(w, "hooks:")
+		for _, hook := range md.Hooks {
+			if len(hook.Plugs) == 0 {
+				fmt.Fprintf(w, "  - %s\n", hook.Name)
+			} else {
+				fmt.Fprintf(w, "  - %s (plugs: %s)\n", hook.Name, strings.Join(hook.Plugs, ", "))
+			}
+		}
+	}
+
+	printDiskSnapInterfaces(w, "plugs", md.Plugs)
+	printDiskSnapInterfaces(w, "slots", md.Slots)
+
+	if len(md.Layouts) > 0 {
+		fmt.Fprintln(w, "layouts:")
+		for _, layout := range md.Layouts {
+			fmt.Fprintf(w, "  %s:\t%s %s\n", layout.Path, layout.Kind, layout.Target)
+		}
+	}
+
+	if len(md.RegistryViews) > 0 {
+		fmt.Fprintln(w, "registry-views:")
+		for _, view := range md.RegistryViews {
+			fmt.Fprintf(w, "  %s:\t%s/%s\n", view.Plug, view.Account, view.View)
+		}
+	}
+}
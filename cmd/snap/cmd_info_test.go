@@ -21,6 +21,7 @@ package main_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -34,6 +35,7 @@ import (
 	snaplib "github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/snap/squashfs"
+	"github.com/snapcore/snapd/testutil"
 	"github.com/snapcore/snapd/timeutil"
 )
 
@@ -1061,6 +1063,25 @@ func (infoSuite) TestDescr(c *check.C) {
 	}
 }
 
+func (infoSuite) TestMaybePrintTrackingChannel(c *check.C) {
+	var buf flushBuffer
+	iw := snap.NewInfoWriter(&buf)
+	for i, t := range []struct {
+		snap     *client.Snap
+		expected string
+	}{
+		{snap: nil, expected: ""},
+		{snap: &client.Snap{}, expected: ""},
+		{snap: &client.Snap{TrackingChannel: "latest/candidate"}, expected: "tracking:\tlatest/candidate\n"},
+		{snap: &client.Snap{TrackingChannel: "latest/candidate", FallbackChannel: "latest/stable"}, expected: "tracking:\tlatest/candidate\nfallback:\tlatest/stable\n"},
+	} {
+		buf.Reset()
+		snap.SetupSnap(iw, t.snap, nil, nil)
+		snap.MaybePrintTrackingChannel(iw)
+		c.Check(buf.String(), check.Equals, t.expected, check.Commentf("%d", i))
+	}
+}
+
 func (infoSuite) TestMaybePrintCohortKey(c *check.C) {
 	type T struct {
 		snap     *client.Snap
@@ -1073,7 +1094,7 @@ func (infoSuite) TestMaybePrintCohortKey(c *check.C) {
 		{snap: nil, verbose: true, expected: ""},
 		{snap: &client.Snap{}, verbose: false, expected: ""},
 		{snap: &client.Snap{}, verbose: true, expected: ""},
-		{snap: &client.Snap{CohortKey: "some-cohort-key"}, verbose: false, expected: ""},
+		{snap: &client.Snap{CohortKey: "some-cohort-key"}, verbose: false, expected: "cohort:\t…-key\n"},
 		{snap: &client.Snap{CohortKey: "some-cohort-key"}, verbose: true, expected: "cohort:\t…-key\n"},
 	}
 
@@ -1088,7 +1109,8 @@ func (infoSuite) TestMaybePrintCohortKey(c *check.C) {
 		snap.MaybePrintCohortKey(iw)
 		c.Check(buf.String(), check.Equals, t.expected, check.Commentf("tty:true/%d", i))
 	}
-	// now the same but without a tty -> the last test should no longer ellipt
+	// now the same but without a tty -> the last tests should no longer ellipt
+	tests[len(tests)-2].expected = "cohort:\tsome-cohort-key\n"
 	tests[len(tests)-1].expected = "cohort:\tsome-cohort-key\n"
 	snap.MockIsStdoutTTY(false)
 	for i, t := range tests {
@@ -1339,3 +1361,158 @@ installed:     2.10            (100)  1kB disabled
 `, refreshDate))
 	c.Check(s.Stderr(), check.Equals, "")
 }
+
+const diskSnapYaml = `name: some-snap
+version: 1.0
+summary: some summary
+description: some description
+base: core22
+epoch: 1*
+license: GPL-3.0
+apps:
+  app:
+    command: bin/app
+    plugs: [home]
+  svc:
+    command: bin/svc
+    daemon: notify
+    slots: [dbus-svc]
+hooks:
+  configure:
+    plugs: [network]
+  install:
+plugs:
+  home:
+    read: all
+  network:
+  etc-config:
+    interface: registry
+    account: acme
+    view: network/wifi
+slots:
+  dbus-svc:
+    interface: dbus
+    bus: system
+    name: org.example.Svc
+layout:
+  /etc/some-snap:
+    bind: $SNAP_DATA/etc
+  /usr/share/some-snap:
+    symlink: $SNAP/usr/share/some-snap
+`
+
+func (s *infoSuite) mockDiskSnapDir(c *check.C) string {
+	dir := c.MkDir()
+	c.Assert(os.MkdirAll(filepath.Join(dir, "meta"), 0755), check.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(dir, "meta", "snap.yaml"), []byte(diskSnapYaml), 0644), check.IsNil)
+	return dir
+}
+
+func (s *infoSuite) TestInfoDiskSnapVerbose(c *check.C) {
+	dir := s.mockDiskSnapDir(c)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"info", "--verbose", dir})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, fmt.Sprintf(`path:    "%s/"
+name:    some-snap
+summary: some summary
+version: 1.0 -
+license: GPL-3.0
+description: |
+  some description
+commands:
+  - some-snap.app
+services:
+  some-snap.svc: notify, disabled, inactive
+notes:           
+  private:       false
+  confinement:   strict
+base:  core22
+epoch: 1*
+apps:
+  some-snap.app: command
+  some-snap.svc: notify daemon
+hooks:
+  - configure (plugs: etc-config, network)
+  - install (plugs: etc-config)
+plugs:
+  etc-config: registry
+    account:  acme
+    view:     network/wifi
+  home:
+    read: all
+  network:
+slots:
+  dbus-svc: dbus
+    bus:    system
+    name:   org.example.Svc
+layouts:
+  /etc/some-snap:       bind $SNAP_DATA/etc
+  /usr/share/some-snap: symlink $SNAP/usr/share/some-snap
+registry-views:
+  etc-config: acme/network/wifi
+`, dir))
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *infoSuite) TestInfoDiskSnapNotVerbose(c *check.C) {
+	dir := s.mockDiskSnapDir(c)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"info", dir})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Not(testutil.Contains), "hooks:")
+	c.Check(s.Stdout(), check.Not(testutil.Contains), "layouts:")
+}
+
+func (s *infoSuite) TestInfoDiskSnapJSON(c *check.C) {
+	dir := s.mockDiskSnapDir(c)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"info", "--json", dir})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stderr(), check.Equals, "")
+
+	var mds []map[string]interface{}
+	c.Assert(json.Unmarshal([]byte(s.Stdout()), &mds), check.IsNil)
+	c.Assert(mds, check.HasLen, 1)
+	c.Check(mds[0], check.DeepEquals, map[string]interface{}{
+		"path":        dir + "/",
+		"name":        "some-snap",
+		"version":     "1.0",
+		"summary":     "some summary",
+		"description": "some description",
+		"type":        "app",
+		"base":        "core22",
+		"epoch":       "1*",
+		"confinement": "strict",
+		"license":     "GPL-3.0",
+		"apps": []interface{}{
+			map[string]interface{}{"name": "app", "command": "bin/app", "plugs": []interface{}{"etc-config", "home"}},
+			map[string]interface{}{"name": "svc", "command": "bin/svc", "daemon": "notify", "plugs": []interface{}{"etc-config"}, "slots": []interface{}{"dbus-svc"}},
+		},
+		"hooks": []interface{}{
+			map[string]interface{}{"name": "configure", "plugs": []interface{}{"etc-config", "network"}},
+			map[string]interface{}{"name": "install", "plugs": []interface{}{"etc-config"}},
+		},
+		"plugs": []interface{}{
+			map[string]interface{}{"name": "etc-config", "interface": "registry", "attributes": map[string]interface{}{"account": "acme", "view": "network/wifi"}},
+			map[string]interface{}{"name": "home", "interface": "home", "attributes": map[string]interface{}{"read": "all"}},
+			map[string]interface{}{"name": "network", "interface": "network"},
+		},
+		"slots": []interface{}{
+			map[string]interface{}{"name": "dbus-svc", "interface": "dbus", "attributes": map[string]interface{}{"bus": "system", "name": "org.example.Svc"}},
+		},
+		"layouts": []interface{}{
+			map[string]interface{}{"path": "/etc/some-snap", "kind": "bind", "target": "$SNAP_DATA/etc"},
+			map[string]interface{}{"path": "/usr/share/some-snap", "kind": "symlink", "target": "$SNAP/usr/share/some-snap"},
+		},
+		"registry-views": []interface{}{
+			map[string]interface{}{"plug": "etc-config", "account": "acme", "view": "network/wifi"},
+		},
+	})
+}
+
+func (s *infoSuite) TestInfoJSONNotSnapFile(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"info", "--json", "hello"})
+	c.Assert(err, check.ErrorMatches, `cannot read snap file "hello": .*`)
+}
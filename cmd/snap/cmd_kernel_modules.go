@@ -0,0 +1,159 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdKernelModules struct{}
+
+var shortKernelModulesHelp = i18n.G("Manage the kernel modules of the kernel snap")
+var longKernelModulesHelp = i18n.G(`
+The kernel-modules command contains sub-commands to list the kernel modules
+shipped by the kernel snap and its components, and to control whether they
+can be loaded on this device.
+`)
+
+var shortKernelModulesListHelp = i18n.G("List the kernel modules of the kernel snap")
+var longKernelModulesListHelp = i18n.G(`
+The list command lists the kernel modules shipped by the current revision of
+the kernel snap and its components, whether their loading was disabled, and
+the snaps loading them on boot through their interface connections.
+`)
+
+var shortKernelModulesDisableHelp = i18n.G("Prevent kernel modules from being loaded")
+var longKernelModulesDisableHelp = i18n.G(`
+The disable command persistently prevents the given kernel modules from being
+loaded. Modules that are already loaded are not unloaded.
+
+The setting is recorded for the current revision of the kernel snap: a new
+revision of the kernel inherits it, and reverting the kernel also reverts it.
+Modules loaded on boot by snaps through their interface connections cannot be
+disabled.
+`)
+
+var shortKernelModulesEnableHelp = i18n.G("Allow kernel modules to be loaded again")
+var longKernelModulesEnableHelp = i18n.G(`
+The enable command allows loading the given kernel modules again, after they
+were disabled with 'snap kernel-modules disable'.
+`)
+
+type cmdKernelModulesList struct {
+	clientMixin
+}
+
+type cmdKernelModulesDisable struct {
+	clientMixin
+	Positional struct {
+		Modules []string `required:"1"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+type cmdKernelModulesEnable struct {
+	clientMixin
+	Positional struct {
+		Modules []string `required:"1"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func init() {
+	modulesArgDescs := []argDesc{{
+		// TRANSLATORS: This needs to begin with < and end with >
+		name: i18n.G("<module>"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		desc: i18n.G("Kernel module name"),
+	}}
+	addSubCommand(&kernelModulesCommands, "list", shortKernelModulesListHelp, longKernelModulesListHelp, func() flags.Commander {
+		return &cmdKernelModulesList{}
+	}, nil, nil)
+	addSubCommand(&kernelModulesCommands, "disable", shortKernelModulesDisableHelp, longKernelModulesDisableHelp, func() flags.Commander {
+		return &cmdKernelModulesDisable{}
+	}, nil, modulesArgDescs)
+	addSubCommand(&kernelModulesCommands, "enable", shortKernelModulesEnableHelp, longKernelModulesEnableHelp, func() flags.Commander {
+		return &cmdKernelModulesEnable{}
+	}, nil, modulesArgDescs)
+}
+
+func (x *cmdKernelModulesList) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	modules, err := x.client.KernelModules()
+	if err != nil {
+		return err
+	}
+	if len(modules) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("The kernel snap ships no kernel modules."))
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+	fmt.Fprintln(w, i18n.G("Module\tComponent\tStatus\tLoaded by"))
+	for _, mod := range modules {
+		component := mod.Component
+		if component == "" {
+			component = "-"
+		}
+		status := i18n.G("enabled")
+		if mod.Disabled {
+			status = i18n.G("disabled")
+		}
+		loadedBy := "-"
+		if len(mod.LoadedBy) > 0 {
+			loadedBy = strings.Join(mod.LoadedBy, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", mod.Name, component, status, loadedBy)
+	}
+	return nil
+}
+
+func (x *cmdKernelModulesDisable) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	if err := x.client.DisableKernelModules(x.Positional.Modules); err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, i18n.NG("Disabled kernel module %s.\n", "Disabled kernel modules %s.\n", len(x.Positional.Modules)),
+		strings.Join(x.Positional.Modules, ", "))
+	return nil
+}
+
+func (x *cmdKernelModulesEnable) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	if err := x.client.EnableKernelModules(x.Positional.Modules); err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, i18n.NG("Enabled kernel module %s.\n", "Enabled kernel modules %s.\n", len(x.Positional.Modules)),
+		strings.Join(x.Positional.Modules, ", "))
+	return nil
+}
@@ -0,0 +1,100 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) TestKernelModulesList(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, check.Equals, "GET")
+		c.Check(r.URL.Path, check.Equals, "/v2/kernel-modules")
+		fmt.Fprintln(w, `{"type": "sync", "result": [
+			{"name": "btrfs", "loaded-by": ["bar", "foo"]},
+			{"name": "e1000e"},
+			{"name": "iwlwifi", "component": "wifi", "disabled": true}
+		]}`)
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"kernel-modules", "list"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `
+Module   Component  Status    Loaded by
+btrfs    -          enabled   bar,foo
+e1000e   -          enabled   -
+iwlwifi  wifi       disabled  -
+`[1:])
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestKernelModulesListEmpty(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": []}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"kernel-modules", "list"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "The kernel snap ships no kernel modules.\n")
+}
+
+func (s *SnapSuite) TestKernelModulesDisableEnable(c *check.C) {
+	var action string
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, check.Equals, "POST")
+		c.Check(r.URL.Path, check.Equals, "/v2/kernel-modules")
+		c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+			"action":  action,
+			"modules": []interface{}{"btrfs", "e1000e"},
+		})
+		fmt.Fprintln(w, `{"type": "sync", "result": null}`)
+	})
+
+	action = "disable"
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"kernel-modules", "disable", "btrfs", "e1000e"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "Disabled kernel modules btrfs, e1000e.\n")
+
+	s.ResetStdStreams()
+	action = "enable"
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"kernel-modules", "enable", "btrfs", "e1000e"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "Enabled kernel modules btrfs, e1000e.\n")
+}
+
+func (s *SnapSuite) TestKernelModulesDisableError(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		fmt.Fprintln(w, `{"type": "error", "status-code": 400, "result": {"message": "kernel snap \"pc-kernel\" does not ship module \"nvidia\""}}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"kernel-modules", "disable", "nvidia"})
+	c.Check(err, check.ErrorMatches, `cannot disable kernel modules: kernel snap "pc-kernel" does not ship module "nvidia"`)
+	c.Check(s.Stdout(), check.Equals, "")
+}
+
+func (s *SnapSuite) TestKernelModulesDisableNoModules(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"kernel-modules", "disable"})
+	c.Check(err, check.ErrorMatches, `the required argument .* was not provided`)
+}
@@ -41,8 +41,9 @@ type cmdKnown struct {
 		HeaderFilters  []string       `required:"0"`
 	} `positional-args:"true" required:"true"`
 
-	Remote bool `long:"remote"`
-	Direct bool `long:"direct"`
+	Remote            bool `long:"remote"`
+	Direct            bool `long:"direct"`
+	WithPrerequisites bool `long:"with-prerequisites"`
 }
 
 var shortKnownHelp = i18n.G("Show known assertions of the provided type")
@@ -50,6 +51,12 @@ var longKnownHelp = i18n.G(`
 The known command shows known assertions of the provided type.
 If header=value pairs are provided after the assertion type, the assertions
 shown must also have the specified headers matching the provided values.
+
+With --remote or --direct the headers must identify a single assertion;
+the sequence of sequence-forming assertions can be omitted to get the latest
+one. With --with-prerequisites, the prerequisites of the assertion, such as
+the account and account-key of its signer, are resolved and output before it,
+ready to be acknowledged offline.
 `)
 
 func init() {
@@ -60,6 +67,8 @@ func init() {
 		"remote": i18n.G("Query the store for the assertion, via snapd if possible"),
 		// TRANSLATORS: This should not start with a lowercase letter.
 		"direct": i18n.G("Query the store for the assertion, without attempting to go via snapd"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"with-prerequisites": i18n.G("Also output the prerequisites of the assertion queried from the store"),
 	}, []argDesc{
 		{
 			// TRANSLATORS: This needs to begin with < and end with >
@@ -77,7 +86,7 @@ func init() {
 
 var storeNew = store.New
 
-func downloadAssertion(typeName string, headers map[string]string) ([]asserts.Assertion, error) {
+func downloadAssertion(typeName string, headers map[string]string, withPrerequisites bool) ([]asserts.Assertion, error) {
 	var user *auth.UserState
 
 	// FIXME: set auth context
@@ -87,24 +96,18 @@ func downloadAssertion(typeName string, headers map[string]string) ([]asserts.As
 	if at == nil {
 		return nil, fmt.Errorf("cannot find assertion type %q", typeName)
 	}
-	primaryKeys, err := asserts.PrimaryKeyFromHeaders(at, headers)
-	if err != nil {
-		return nil, fmt.Errorf("cannot query remote assertion: %v", err)
-	}
 
 	sto := storeNew(nil, storeCtx)
-	as, err := sto.Assertion(at, primaryKeys, user)
-	if err != nil {
-		return nil, err
-	}
-
-	return []asserts.Assertion{as}, nil
+	return store.QueryAssertion(sto, at, headers, withPrerequisites, user)
 }
 
 func (x *cmdKnown) Execute(args []string) error {
 	if len(args) > 0 {
 		return ErrExtraArgs
 	}
+	if x.WithPrerequisites && !x.Remote && !x.Direct {
+		return fmt.Errorf(i18n.G("--with-prerequisites can only be used together with --remote or --direct"))
+	}
 
 	// TODO: share this kind of parsing once it's clearer how often is used in snap
 	headers := map[string]string{}
@@ -121,15 +124,18 @@ func (x *cmdKnown) Execute(args []string) error {
 	switch {
 	case x.Remote && !x.Direct:
 		// --remote will query snapd
-		assertions, err = x.client.Known(string(x.KnownOptions.AssertTypeName), headers, &client.KnownOptions{Remote: true})
+		assertions, err = x.client.Known(string(x.KnownOptions.AssertTypeName), headers, &client.KnownOptions{
+			Remote:        true,
+			Prerequisites: x.WithPrerequisites,
+		})
 		// if snapd is unavailable automatically fallback
 		var connErr client.ConnectionError
 		if xerrors.As(err, &connErr) {
-			assertions, err = downloadAssertion(string(x.KnownOptions.AssertTypeName), headers)
+			assertions, err = downloadAssertion(string(x.KnownOptions.AssertTypeName), headers, x.WithPrerequisites)
 		}
 	case x.Direct:
 		// --direct implies remote
-		assertions, err = downloadAssertion(string(x.KnownOptions.AssertTypeName), headers)
+		assertions, err = downloadAssertion(string(x.KnownOptions.AssertTypeName), headers, x.WithPrerequisites)
 	default:
 		// default is to look only local
 		assertions, err = x.client.Known(string(x.KnownOptions.AssertTypeName), headers, nil)
@@ -118,6 +118,40 @@ func (s *SnapSuite) TestKnownRemoteViaSnapd(c *check.C) {
 	c.Check(n, check.Equals, 1)
 }
 
+func (s *SnapSuite) TestKnownRemoteViaSnapdWithPrerequisites(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.URL.Path, check.Equals, "/v2/assertions/model")
+			c.Check(r.URL.Query(), check.DeepEquals, url.Values{
+				"series":        []string{"16"},
+				"brand-id":      []string{"canonical"},
+				"model":         []string{"pi99"},
+				"remote":        []string{"true"},
+				"prerequisites": []string{"true"},
+			})
+			w.Header().Set("X-Ubuntu-Assertions-Count", "1")
+			fmt.Fprint(w, mockModelAssertion)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+		n++
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"known", "--remote", "--with-prerequisites", "model", "series=16", "brand-id=canonical", "model=pi99"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, mockModelAssertion)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(n, check.Equals, 1)
+}
+
+func (s *SnapSuite) TestKnownWithPrerequisitesNotRemote(c *check.C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"known", "--with-prerequisites", "model", "series=16", "brand-id=canonical", "model=pi99"})
+	c.Assert(err, check.ErrorMatches, `--with-prerequisites can only be used together with --remote or --direct`)
+}
+
 func (s *SnapSuite) TestKnownRemoteDirect(c *check.C) {
 	var server *httptest.Server
 
@@ -152,7 +152,7 @@ func (x *cmdSign) Execute(args []string) error {
 // call this function in a way that is guaranteed to specify a unique assertion
 // (i.e. with a header specifying a value for the assertion's primary key)
 func mustGetOneAssert(assertType string, headers map[string]string) (asserts.Assertion, error) {
-	asserts, err := downloadAssertion(assertType, headers)
+	asserts, err := downloadAssertion(assertType, headers, false)
 	if err != nil {
 		return nil, err
 	}
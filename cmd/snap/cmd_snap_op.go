@@ -67,7 +67,9 @@ its 'snap info' output.
 
 When --revision is used, a later refresh will typically undo the revision
 override, taking the snap back to the current revision of the channel it's
-tracking.
+tracking. Adding --pin keeps the snap at the given revision instead: refreshes
+leave it alone unless a revision is explicitly requested, until it is unpinned
+with 'snap refresh --unpin'.
 
 Use --name to set the instance name when installing from snap file.
 `)
@@ -93,7 +95,9 @@ channel they're tracking, preserving their confinement options. All available
 channels of a snap are listed in its 'snap info' output.
 
 When --revision is used, a later refresh will typically undo the revision
-override.
+override, unless --pin is also given. A pinned snap stays at its revision
+until it is refreshed to another explicitly requested revision or unpinned
+with --unpin. Unlike holds, pins do not expire.
 
 Hold (--hold) is used to postpone snap refresh updates for all snaps when no
 snaps are specified, or for the specified snaps.
@@ -105,6 +109,20 @@ requests from 'snap refresh target-snap'.
 When snaps are specified --hold is effective on both their auto-refreshes
 and general refresh requests from 'snap refresh'. However, specific snap
 requests from 'snap refresh target-snap' remain unblocked and will proceed.
+
+When refreshes are postponed because applications of a snap are running,
+--ignore-running makes the refresh of the specified snaps proceed right away.
+The override is recorded along with who requested it, and the affected
+sessions are notified that the snap is updating.
+
+Revisions known to be broken can be avoided with --block-revision, after which
+refreshes of the snap skip that revision even if the tracked channel points to
+it. The block lasts until the duration given with --block-for elapses, or
+forever if none is given. It is kept even if no refresh happens at that time.
+Refreshing to a revision explicitly requested with --revision is unaffected.
+
+The release notes published in the store for the pending refreshes of all snaps,
+or of the specified snaps, can be reviewed with --changelog before refreshing.
 `)
 
 var longTryHelp = i18n.G(`
@@ -128,6 +146,14 @@ snap will no longer be available, but all the data is still available
 and the snap can easily be enabled again.
 `)
 
+var shortReleaseQuarantineHelp = i18n.G("Release a quarantined snap")
+var longReleaseQuarantineHelp = i18n.G(`
+The release-quarantine command releases a snap that was installed into
+quarantine because it is unasserted and the unasserted-snaps system option
+is set to 'quarantine'. Once released, the snap interfaces are
+automatically connected and its services are started.
+`)
+
 type cmdRemove struct {
 	waitMixin
 
@@ -509,6 +535,7 @@ type cmdInstall struct {
 
 	Unaliased bool `long:"unaliased"`
 	Prefer    bool `long:"prefer"`
+	Pin       bool `long:"pin"`
 
 	Name string `long:"name"`
 
@@ -532,6 +559,9 @@ func (x *cmdInstall) installOne(nameOrPath, desiredName string, opts *client.Sna
 		// don't log the request's body because the encoded snap is large.
 		x.client.SetMayLogBody(false)
 		path = nameOrPath
+		if opts.Pin {
+			return errors.New(i18n.G("cannot pin a snap installed from a file"))
+		}
 		changeID, err = x.client.InstallPath(path, x.Name, opts)
 	} else {
 		snapName = nameOrPath
@@ -670,8 +700,12 @@ func (x *cmdInstall) Execute([]string) error {
 		Transaction:      x.Transaction,
 		QuotaGroupName:   x.QuotaGroupName,
 		Prefer:           x.Prefer,
+		Pin:              x.Pin,
 	}
 	x.setModes(opts)
+	if x.Pin && x.Revision == "" {
+		return errors.New(i18n.G("--pin requires --revision"))
+	}
 
 	names := remoteSnapNames(x.Positional.Snaps)
 	for _, name := range names {
@@ -693,6 +727,9 @@ func (x *cmdInstall) Execute([]string) error {
 	if x.Prefer {
 		return errors.New(i18n.G("a single snap name is needed to specify the prefer flag"))
 	}
+	if x.Pin {
+		return errors.New(i18n.G("a single snap name must be specified when pinning a revision"))
+	}
 
 	if x.Name != "" {
 		return errors.New(i18n.G("cannot use instance name when installing multiple snaps"))
@@ -712,12 +749,17 @@ type cmdRefresh struct {
 	Cohort           string                 `long:"cohort"`
 	LeaveCohort      bool                   `long:"leave-cohort"`
 	List             bool                   `long:"list"`
+	Changelog        bool                   `long:"changelog"`
 	Time             bool                   `long:"time"`
 	IgnoreValidation bool                   `long:"ignore-validation"`
-	IgnoreRunning    bool                   `long:"ignore-running" hidden:"yes"`
+	IgnoreRunning    bool                   `long:"ignore-running"`
 	Transaction      client.TransactionType `long:"transaction" default:"per-snap" choice:"all-snaps" choice:"per-snap"`
 	Hold             string                 `long:"hold" optional:"yes" optional-value:"forever"`
 	Unhold           bool                   `long:"unhold"`
+	BlockRevision    string                 `long:"block-revision"`
+	BlockFor         string                 `long:"block-for"`
+	Pin              bool                   `long:"pin"`
+	Unpin            bool                   `long:"unpin"`
 	Positional       struct {
 		Snaps []installedSnapName `positional-arg-name:"<snap>"`
 	} `positional-args:"yes"`
@@ -856,6 +898,51 @@ func (x *cmdRefresh) listRefresh() error {
 	return nil
 }
 
+func (x *cmdRefresh) showChangelog() error {
+	snaps, _, err := x.client.Find(&client.FindOptions{
+		Refresh: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	names := installedSnapNames(x.Positional.Snaps)
+	if len(names) > 0 {
+		pending := snaps[:0]
+		for _, snap := range snaps {
+			if strutil.ListContains(names, snap.Name) {
+				pending = append(pending, snap)
+			}
+		}
+		snaps = pending
+	}
+	if len(snaps) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("All snaps up to date."))
+		return nil
+	}
+
+	sort.Sort(snapsByName(snaps))
+
+	esc := x.getEscapes()
+	for i, snap := range snaps {
+		if i > 0 {
+			fmt.Fprintln(Stdout)
+		}
+		// TRANSLATORS: the first %s is a snap name, the second a version, the third a revision and the last a publisher
+		fmt.Fprintf(Stdout, i18n.G("%s %s (%s) from %s\n"), snap.Name, snap.Version, snap.Revision, longPublisher(esc, snap.Publisher))
+		notes := strings.TrimSpace(snap.ReleaseNotes)
+		if notes == "" {
+			fmt.Fprintf(Stdout, "  %s\n", i18n.G("(no release notes)"))
+			continue
+		}
+		for _, line := range strings.Split(notes, "\n") {
+			fmt.Fprintf(Stdout, "  %s\n", line)
+		}
+	}
+
+	return nil
+}
+
 func (x *cmdRefresh) Execute([]string) error {
 	if err := x.setChannelFromCommandline(); err != nil {
 		return err
@@ -879,26 +966,44 @@ func (x *cmdRefresh) Execute([]string) error {
 		return x.listRefresh()
 	}
 
+	if x.Changelog {
+		if x.asksForMode() || x.asksForChannel() {
+			return errors.New(i18n.G("--changelog does not take mode or channel flags"))
+		}
+
+		return x.showChangelog()
+	}
+
 	if len(x.Positional.Snaps) == 0 && os.Getenv("SNAP_REFRESH_FROM_TIMER") == "1" {
 		fmt.Fprintf(Stdout, "Ignoring `snap refresh` from the systemd timer")
 		return nil
 	}
 
 	otherFlags := x.Amend || x.Revision != "" || x.Cohort != "" ||
-		x.LeaveCohort || x.List || x.Time || x.IgnoreValidation || x.IgnoreRunning ||
-		x.Transaction != client.TransactionPerSnap
+		x.LeaveCohort || x.List || x.Changelog || x.Time || x.IgnoreValidation || x.IgnoreRunning ||
+		x.BlockRevision != "" || x.Pin || x.Transaction != client.TransactionPerSnap
 
-	if x.Hold != "" && (x.Unhold || otherFlags) {
+	if x.Hold != "" && (x.Unhold || x.Unpin || otherFlags) {
 		return errors.New(i18n.G("cannot use --hold with other flags"))
-	} else if x.Unhold && (x.Hold != "" || otherFlags) {
+	} else if x.Unhold && (x.Hold != "" || x.Unpin || otherFlags) {
 		return errors.New(i18n.G("cannot use --unhold with other flags"))
+	} else if x.Unpin && (x.Hold != "" || x.Unhold || otherFlags) {
+		return errors.New(i18n.G("cannot use --unpin with other flags"))
 	} else if x.Hold != "" {
 		return x.holdRefreshes()
 	} else if x.Unhold {
 		return x.unholdRefreshes()
+	} else if x.Unpin {
+		return x.unpin()
 	}
 
 	names := installedSnapNames(x.Positional.Snaps)
+	if x.BlockFor != "" && x.BlockRevision == "" {
+		return errors.New(i18n.G("--block-for requires --block-revision"))
+	}
+	if x.Pin && x.Revision == "" {
+		return errors.New(i18n.G("--pin requires --revision"))
+	}
 	if len(names) == 1 {
 		opts := &client.SnapOptions{
 			Amend:            x.Amend,
@@ -909,10 +1014,22 @@ func (x *cmdRefresh) Execute([]string) error {
 			CohortKey:        x.Cohort,
 			LeaveCohort:      x.LeaveCohort,
 			Transaction:      x.Transaction,
+			BlockRevision:    x.BlockRevision,
+			Pin:              x.Pin,
+		}
+		if x.BlockFor != "" {
+			dur, err := time.ParseDuration(x.BlockFor)
+			if err != nil {
+				return fmt.Errorf(i18n.G("block duration must be a number of hours, minutes or seconds: %v"), err)
+			}
+			opts.BlockUntil = timeNow().Add(dur).Format(time.RFC3339)
 		}
 		x.setModes(opts)
 		return x.refreshOne(names[0], opts)
 	}
+	if x.BlockRevision != "" {
+		return errors.New(i18n.G("a single snap name must be specified when blocking a revision"))
+	}
 	// transaction flag and ignore-running flags are the only ones with meaning when
 	// refreshing many snaps
 	opts := &client.SnapOptions{
@@ -986,6 +1103,28 @@ func (x *cmdRefresh) holdRefreshes() (err error) {
 	return nil
 }
 
+func (x *cmdRefresh) unpin() error {
+	names := installedSnapNames(x.Positional.Snaps)
+	if len(names) != 1 {
+		return errors.New(i18n.G("a single snap name must be specified when unpinning"))
+	}
+
+	changeID, err := x.client.Unpin(names[0], nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := x.wait(changeID); err != nil {
+		if err == noWait {
+			return nil
+		}
+		return err
+	}
+
+	fmt.Fprintf(Stdout, i18n.G("Unpinned %q, refreshes may move it to other revisions\n"), names[0])
+	return nil
+}
+
 func (x *cmdRefresh) unholdRefreshes() (err error) {
 	names := installedSnapNames(x.Positional.Snaps)
 	var changeID string
@@ -1161,6 +1300,32 @@ func (x *cmdDisable) Execute([]string) error {
 	return nil
 }
 
+type cmdReleaseQuarantine struct {
+	waitMixin
+
+	Positional struct {
+		Snap installedSnapName `positional-arg-name:"<snap>"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+func (x *cmdReleaseQuarantine) Execute([]string) error {
+	name := string(x.Positional.Snap)
+	changeID, err := x.client.ReleaseQuarantine(name, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := x.wait(changeID); err != nil {
+		if err == noWait {
+			return nil
+		}
+		return err
+	}
+
+	fmt.Fprintf(Stdout, i18n.G("%s released from quarantine\n"), name)
+	return nil
+}
+
 type cmdRevert struct {
 	waitMixin
 
@@ -1307,6 +1472,8 @@ func init() {
 			"quota-group": i18n.G("Add the snap to a quota group on install"),
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"prefer": i18n.G("Enable all aliases of the given snap in preference to conflicting aliases of other snaps"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"pin": i18n.G("Keep the snap at the given revision until unpinned"),
 		}), nil)
 	addCommand("refresh", shortRefreshHelp, longRefreshHelp, func() flags.Commander { return &cmdRefresh{} },
 		colorDescs.also(waitDescs).also(channelDescs).also(modeDescs).also(timeDescs).also(map[string]string{
@@ -1317,6 +1484,8 @@ func init() {
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"list": i18n.G("Show the new versions of snaps that would be updated with the next refresh"),
 			// TRANSLATORS: This should not start with a lowercase letter.
+			"changelog": i18n.G("Show the release notes of the snaps that would be updated with the next refresh"),
+			// TRANSLATORS: This should not start with a lowercase letter.
 			"time": i18n.G("Show auto refresh information but do not perform a refresh"),
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"ignore-validation": i18n.G("Ignore validation by other snaps blocking the refresh"),
@@ -1332,10 +1501,19 @@ func init() {
 			"hold": i18n.G("Hold refreshes for a specified duration (or forever, if no value is specified)"),
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"unhold": i18n.G("Remove refresh hold"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"block-revision": i18n.G("Never refresh the snap to the given revision"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"block-for": i18n.G("Block the revision only for the specified duration"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"pin": i18n.G("Keep the snap at the given revision until unpinned"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"unpin": i18n.G("Let refreshes move the snap away from its pinned revision"),
 		}), nil)
 	addCommand("try", shortTryHelp, longTryHelp, func() flags.Commander { return &cmdTry{} }, waitDescs.also(modeDescs), nil)
 	addCommand("enable", shortEnableHelp, longEnableHelp, func() flags.Commander { return &cmdEnable{} }, waitDescs, nil)
 	addCommand("disable", shortDisableHelp, longDisableHelp, func() flags.Commander { return &cmdDisable{} }, waitDescs, nil)
+	addCommand("release-quarantine", shortReleaseQuarantineHelp, longReleaseQuarantineHelp, func() flags.Commander { return &cmdReleaseQuarantine{} }, waitDescs, nil)
 	addCommand("revert", shortRevertHelp, longRevertHelp, func() flags.Commander { return &cmdRevert{} }, waitDescs.also(modeDescs).also(map[string]string{
 		// TRANSLATORS: This should not start with a lowercase letter.
 		"revision": i18n.G("Revert to the given revision"),
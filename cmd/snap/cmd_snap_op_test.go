@@ -1459,6 +1459,71 @@ foo +4.2update1 +17 +436MB +bar +-.*
 	c.Check(n, check.Equals, 1)
 }
 
+func (s *SnapSuite) TestRefreshChangelog(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/find")
+			c.Check(r.URL.Query().Get("select"), check.Equals, "refresh")
+			fmt.Fprintln(w, `{"type": "sync", "result": [
+{"name": "foo", "status": "active", "version": "4.2update1", "publisher": {"id": "bar-id", "username": "bar", "display-name": "Bar", "validation": "unproven"}, "revision":17, "release-notes": "Fixed the frobnicator.\nFaster startup."},
+{"name": "baz", "status": "active", "version": "1.0", "publisher": {"id": "bar-id", "username": "bar", "display-name": "Bar", "validation": "unproven"}, "revision":3}
+]}`)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--changelog"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `baz 1.0 (3) from Bar
+  (no release notes)
+
+foo 4.2update1 (17) from Bar
+  Fixed the frobnicator.
+  Faster startup.
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(n, check.Equals, 1)
+}
+
+func (s *SnapSuite) TestRefreshChangelogSomeSnaps(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, check.Equals, "/v2/find")
+		fmt.Fprintln(w, `{"type": "sync", "result": [
+{"name": "foo", "status": "active", "version": "4.2update1", "publisher": {"id": "bar-id", "username": "bar", "display-name": "Bar", "validation": "unproven"}, "revision":17, "release-notes": "Fixed the frobnicator."},
+{"name": "baz", "status": "active", "version": "1.0", "publisher": {"id": "bar-id", "username": "bar", "display-name": "Bar", "validation": "unproven"}, "revision":3}
+]}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--changelog", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, `foo 4.2update1 (17) from Bar
+  Fixed the frobnicator.
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+
+	s.ResetStdStreams()
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--changelog", "other"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "All snaps up to date.\n")
+}
+
+func (s *SnapSuite) TestRefreshChangelogLessOptions(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Fatal("expected to get 0 requests")
+	})
+
+	for _, flag := range []string{"--beta", "--channel=potato", "--classic"} {
+		_, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--changelog", flag})
+		c.Assert(err, check.ErrorMatches, "--changelog does not take mode or channel flags")
+	}
+}
+
 func (s *SnapSuite) TestRefreshLegacyTime(c *check.C) {
 	n := 0
 	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
@@ -1823,6 +1888,40 @@ func (s *SnapSuite) TestRefreshUnholdManySnaps(c *check.C) {
 	c.Check(s.Stderr(), check.Equals, "")
 }
 
+func (s *SnapSuite) TestRefreshUnpin(c *check.C) {
+	var n int
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "POST")
+			c.Check(r.URL.Path, check.Equals, "/v2/snaps/foo")
+			c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+				"action": "unpin",
+			})
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type": "async", "change": "42", "status-code": 202}`)
+
+		case 1:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/changes/42")
+			w.WriteHeader(200)
+			fmt.Fprintln(w, `{"type": "sync", "result": {"ready": true, "status": "Done"}}`)
+
+		default:
+			c.Errorf("expected to get 2 requests, now on %d", n+1)
+			fmt.Fprintln(w, `{"type": "error", "result": {"message": "received too many requests"}, "status-code": 500}`)
+		}
+
+		n++
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--unpin", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, "Unpinned \"foo\", refreshes may move it to other revisions\n")
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
 func (s *SnapSuite) TestRefreshHoldAndUnholdFailWithOtherFlags(c *check.C) {
 	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
 		c.Errorf("unexpected request")
@@ -2191,6 +2290,74 @@ func (s *SnapOpSuite) TestRefreshOneIgnoreRunning(c *check.C) {
 	c.Assert(err, check.IsNil)
 }
 
+func (s *SnapOpSuite) TestRefreshOneBlockRevision(c *check.C) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	defer snap.MockTimeNow(func() time.Time { return now })()
+
+	s.RedirectClientToTestServer(s.srv.handle)
+	s.srv.checker = func(r *http.Request) {
+		c.Check(r.Method, check.Equals, "POST")
+		c.Check(r.URL.Path, check.Equals, "/v2/snaps/one")
+		c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+			"action":         "refresh",
+			"block-revision": "123",
+			"block-until":    "2026-10-03T12:00:00Z",
+			"transaction":    "per-snap",
+		})
+	}
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--block-revision=123", "--block-for=48h", "one"})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *SnapOpSuite) TestRefreshBlockRevisionErrors(c *check.C) {
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"refresh", "--block-revision=123", "one", "two"}, "a single snap name must be specified when blocking a revision"},
+		{[]string{"refresh", "--block-for=1h", "one"}, "--block-for requires --block-revision"},
+		{[]string{"refresh", "--block-revision=123", "--block-for=tomorrow", "one"}, "block duration must be a number of hours, minutes or seconds: .*"},
+		{[]string{"refresh", "--block-revision=123", "--hold", "one"}, "cannot use --hold with other flags"},
+	} {
+		_, err := snap.Parser(snap.Client()).ParseArgs(tc.args)
+		c.Check(err, check.ErrorMatches, tc.err, check.Commentf("%v", tc.args))
+	}
+}
+
+func (s *SnapOpSuite) TestRefreshOnePin(c *check.C) {
+	s.RedirectClientToTestServer(s.srv.handle)
+	s.srv.checker = func(r *http.Request) {
+		c.Check(r.Method, check.Equals, "POST")
+		c.Check(r.URL.Path, check.Equals, "/v2/snaps/one")
+		c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+			"action":      "refresh",
+			"revision":    "42",
+			"pin":         true,
+			"transaction": "per-snap",
+		})
+	}
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--revision=42", "--pin", "one"})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *SnapOpSuite) TestPinErrors(c *check.C) {
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"install", "--pin", "one"}, "--pin requires --revision"},
+		{[]string{"install", "--revision=42", "--pin", "one", "two"}, "a single snap name must be specified when pinning a revision"},
+		{[]string{"install", "--revision=42", "--pin", "./one.snap"}, "cannot pin a snap installed from a file"},
+		{[]string{"refresh", "--pin", "one"}, "--pin requires --revision"},
+		{[]string{"refresh", "--unpin"}, "a single snap name must be specified when unpinning"},
+		{[]string{"refresh", "--unpin", "--revision=42", "one"}, "cannot use --unpin with other flags"},
+		{[]string{"refresh", "--unhold", "--unpin", "one"}, "cannot use --unhold with other flags"},
+	} {
+		_, err := snap.Parser(snap.Client()).ParseArgs(tc.args)
+		c.Check(err, check.ErrorMatches, tc.err, check.Commentf("%v", tc.args))
+	}
+}
+
 func (s *SnapOpSuite) TestRefreshManyIgnoreRunning(c *check.C) {
 	s.RedirectClientToTestServer(s.srv.handle)
 	s.srv.checker = func(r *http.Request) {
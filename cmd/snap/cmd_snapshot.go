@@ -20,6 +20,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -27,6 +28,7 @@ import (
 	"strings"
 
 	"github.com/jessevdk/go-flags"
+	"github.com/snapcore/snapd/client"
 
 	"github.com/snapcore/snapd/i18n"
 	"github.com/snapcore/snapd/strutil"
@@ -50,6 +52,13 @@ var (
 var longSavedHelp = i18n.G(`
 The saved command displays a list of snapshots that have been created
 previously with the 'save' command.
+
+With --files, the saved command instead lists the files stored in the given
+snapshot set, optionally only for the given snaps. Adding --extract writes the
+content of a single file of the given snap to standard output, without
+restoring the snapshot. Its path is given as listed by --files, and --user
+selects the user data holding it; otherwise the file is taken from the system
+data.
 `)
 var longSaveHelp = i18n.G(`
 The save command creates a snapshot of the current user, system and
@@ -100,6 +109,12 @@ for which users, or a combination of these.
 If a snap is included in a restore operation, excluding its system and
 configuration data from the restore is not currently possible. This
 restriction may be lifted in the future.
+
+The data of a single snap can also be restored into a different instance
+of the same snap with --into, for example from 'app' into 'app_staging'.
+Likewise, the data of a single user can be restored onto a different user
+with --to-user, in which case only user data is restored and the restored
+files are owned by that user.
 `)
 
 var longExportSnapshotHelp = i18n.G(`
@@ -115,12 +130,31 @@ type savedCmd struct {
 	clientMixin
 	durationMixin
 	ID         snapshotID `long:"id"`
+	Files      snapshotID `long:"files"`
+	Extract    string     `long:"extract"`
+	User       string     `long:"user"`
 	Positional struct {
 		Snaps []installedSnapName `positional-arg-name:"<snap>"`
 	} `positional-args:"yes"`
 }
 
 func (x *savedCmd) Execute([]string) error {
+	if x.Files != "" {
+		if x.ID != "" {
+			return errors.New(i18n.G("cannot use --files with --id"))
+		}
+		if x.Extract != "" {
+			return x.extractFile()
+		}
+		if x.User != "" {
+			return errors.New(i18n.G("--user requires --extract"))
+		}
+		return x.listFiles()
+	}
+	if x.Extract != "" || x.User != "" {
+		return errors.New(i18n.G("--extract and --user require --files"))
+	}
+
 	var setID uint64
 	var err error
 	if x.ID != "" {
@@ -175,6 +209,67 @@ func (x *savedCmd) Execute([]string) error {
 	return nil
 }
 
+func (x *savedCmd) listFiles() error {
+	setID, err := x.Files.ToUint()
+	if err != nil {
+		return err
+	}
+	snaps := installedSnapNames(x.Positional.Snaps)
+	files, err := x.client.SnapshotFiles(setID, snaps)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(Stdout, i18n.G("No files found."))
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		"Snap",
+		i18n.G("User"),
+		i18n.G("Path"),
+		i18n.G("Size"),
+		// TRANSLATORS: 'Age' as in how long ago the file was modified
+		i18n.G("Age"))
+	for _, f := range files {
+		user := f.User
+		if user == "" {
+			user = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", f.Snap, user, f.Path, fmtSize(f.Size), x.fmtDuration(f.Mtime))
+	}
+	return nil
+}
+
+func (x *savedCmd) extractFile() error {
+	setID, err := x.Files.ToUint()
+	if err != nil {
+		return err
+	}
+	snaps := installedSnapNames(x.Positional.Snaps)
+	if len(snaps) != 1 {
+		return errors.New(i18n.G("a single snap name must be specified to extract a file"))
+	}
+
+	r, size, err := x.client.SnapshotFile(setID, snaps[0], x.User, x.Extract)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	n, err := io.Copy(Stdout, r)
+	if err != nil {
+		return err
+	}
+	if size >= 0 && n != size {
+		return fmt.Errorf(i18n.G("unexpected size, got: %v but wanted %v"), n, size)
+	}
+	return nil
+}
+
 type saveCmd struct {
 	waitMixin
 	durationMixin
@@ -283,6 +378,8 @@ func (x *checkSnapshotCmd) Execute([]string) error {
 type restoreCmd struct {
 	waitMixin
 	Users      string `long:"users"`
+	Into       string `long:"into"`
+	ToUser     string `long:"to-user"`
 	Positional struct {
 		ID    snapshotID          `positional-arg-name:"<id>"`
 		Snaps []installedSnapName `positional-arg-name:"<snap>"`
@@ -296,7 +393,17 @@ func (x *restoreCmd) Execute([]string) error {
 	}
 	snaps := installedSnapNames(x.Positional.Snaps)
 	users := strutil.CommaSeparatedList(x.Users)
-	changeID, err := x.client.RestoreSnapshots(setID, snaps, users)
+	var target *client.SnapshotRestoreTarget
+	if x.Into != "" || x.ToUser != "" {
+		if x.Into != "" && len(snaps) != 1 {
+			return errors.New(i18n.G("a single snap name must be specified when restoring into another instance"))
+		}
+		if x.ToUser != "" && len(users) != 1 {
+			return errors.New(i18n.G("a single user must be specified with --users when restoring onto another user"))
+		}
+		target = &client.SnapshotRestoreTarget{Instance: x.Into, User: x.ToUser}
+	}
+	changeID, err := x.client.RestoreSnapshotsTo(setID, snaps, users, target)
 	if err != nil {
 		return err
 	}
@@ -309,7 +416,18 @@ func (x *restoreCmd) Execute([]string) error {
 	}
 
 	// TODO: also mention the home archives that were actually restored
-	if len(snaps) > 0 {
+	if target != nil {
+		var into string
+		if target.Instance != "" {
+			into = fmt.Sprintf(i18n.G(" into %q"), target.Instance)
+		}
+		if target.User != "" {
+			into += fmt.Sprintf(i18n.G(" for user %q"), target.User)
+		}
+		// TRANSLATORS: the first %s is the snapshot set id, the second
+		// describes the target instance and/or user
+		fmt.Fprintf(Stdout, i18n.G("Restored snapshot #%s%s.\n"), x.Positional.ID, into)
+	} else if len(snaps) > 0 {
 		// TRANSLATORS: the %s is a comma-separated list of quoted snap names
 		fmt.Fprintf(Stdout, i18n.G("Restored snapshot #%s of snaps %s.\n"),
 			x.Positional.ID, strutil.Quoted(snaps))
@@ -329,6 +447,12 @@ func init() {
 		durationDescs.also(map[string]string{
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"id": i18n.G("Show only a specific snapshot."),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"files": i18n.G("List the files stored in the given snapshot set."),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"extract": i18n.G("Write the content of the given file to standard output."),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"user": i18n.G("Extract the file from the data of the given user."),
 		}),
 		nil)
 
@@ -350,6 +474,10 @@ func init() {
 		}, waitDescs.also(map[string]string{
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"users": i18n.G("Restore data of only specific users (comma-separated) (default: all users)"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"into": i18n.G("Restore the data of the given snap into this instance of it"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"to-user": i18n.G("Restore the data of the given user onto this user"),
 		}), []argDesc{
 			{
 				name: "<id>",
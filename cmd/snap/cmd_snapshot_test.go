@@ -20,8 +20,10 @@
 package main_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -47,6 +49,30 @@ var snapshotsTests = []getCmdArgs{{
 }, {
 	args:   "saved",
 	stdout: "Set  Snap  Age    Version  Rev   Size    Notes\n1    htop  .*  2        1168      1B  -\n",
+}, {
+	args:  "saved --files=x",
+	error: `invalid argument for snapshot set id: expected a non-negative integer argument \(see 'snap help saved'\)`,
+}, {
+	args:  "saved --files=1 --id=1",
+	error: "cannot use --files with --id",
+}, {
+	args:  "saved --extract=foo",
+	error: "--extract and --user require --files",
+}, {
+	args:  "saved --files=1 --user=alice",
+	error: "--user requires --extract",
+}, {
+	args:  "saved --files=1 --extract=foo",
+	error: "a single snap name must be specified to extract a file",
+}, {
+	args:   "saved --files=1",
+	stdout: "Snap  User   Path               Size  Age\nhtop  -      etc/htoprc           5B  .*\nhtop  alice  .config/htop/rc    12B  .*\n",
+}, {
+	args:   "saved --files=2",
+	stdout: "No files found.\n",
+}, {
+	args:   "saved --files=1 --extract=.config/htop/rc --user=alice htop",
+	stdout: "Hello World!",
 }, {
 	args:  "forget x",
 	error: `invalid argument for snapshot set id: expected a non-negative integer argument \(see 'snap help saved'\)`,
@@ -56,6 +82,18 @@ var snapshotsTests = []getCmdArgs{{
 }, {
 	args:   "restore 1",
 	stdout: "Restored snapshot #1.\n",
+}, {
+	args:  "restore 1 --into=htop_staging",
+	error: "a single snap name must be specified when restoring into another instance",
+}, {
+	args:  "restore 1 htop --to-user=bob",
+	error: "a single user must be specified with --users when restoring onto another user",
+}, {
+	args:   "restore 1 htop --into=htop_staging",
+	stdout: "Restored snapshot #1 into \"htop_staging\".\n",
+}, {
+	args:   "restore 1 htop --users=alice --to-user=bob",
+	stdout: "Restored snapshot #1 for user \"bob\".\n",
 }, {
 	args:   "forget 2",
 	stdout: "Snapshot #2 forgotten.\n",
@@ -113,6 +151,38 @@ func (s *SnapSuite) TestSnapshotExportHappy(c *C) {
 	c.Check(exportedSnapshotPath+".part", testutil.FileAbsent)
 }
 
+func (s *SnapSuite) TestSnapshotRestoreTo(c *C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, Equals, "POST")
+			c.Check(r.URL.Path, Equals, "/v2/snapshots")
+			c.Check(DecodedRequestBody(c, r), DeepEquals, map[string]interface{}{
+				"set":    json.Number("42"),
+				"action": "restore",
+				"snaps":  []interface{}{"htop"},
+				"users":  []interface{}{"alice"},
+				"target": map[string]interface{}{"instance": "htop_staging", "user": "bob"},
+			})
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type":"async", "status-code": 202, "change": "9"}`)
+		case 1:
+			c.Check(r.URL.Path, Equals, "/v2/changes/9")
+			fmt.Fprintln(w, `{"type": "sync", "result": {"ready": true, "status": "Done", "data": {}}}`)
+		default:
+			c.Fatalf("unexpected request %v", r)
+		}
+		n++
+	})
+
+	_, err := main.Parser(main.Client()).ParseArgs([]string{"restore", "42", "htop", "--users=alice", "--into=htop_staging", "--to-user=bob"})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 2)
+	c.Check(s.Stdout(), Equals, "Restored snapshot #42 into \"htop_staging\" for user \"bob\".\n")
+	c.Check(s.Stderr(), Equals, "")
+}
+
 func (s *SnapSuite) mockSnapshotsServer(c *C) {
 	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
@@ -137,6 +207,19 @@ func (s *SnapSuite) mockSnapshotsServer(c *C) {
 			}
 		case "/v2/changes/9":
 			fmt.Fprintln(w, `{"type": "sync", "result": {"ready": true, "status": "Done", "data": {}}}`)
+		case "/v2/snapshots/1/files":
+			c.Check(r.Method, Equals, "GET")
+			if r.URL.Query().Get("path") != "" {
+				c.Check(r.URL.Query(), DeepEquals, url.Values{"snap": {"htop"}, "user": {"alice"}, "path": {".config/htop/rc"}})
+				w.Header().Set("Content-Type", "application/octet-stream")
+				fmt.Fprint(w, "Hello World!")
+				return
+			}
+			// simulate files modified a month ago
+			mtime := time.Now().AddDate(0, -1, 0).Format(time.RFC3339)
+			fmt.Fprintf(w, `{"type":"sync","status-code":200,"status":"OK","result":[{"snap":"htop","path":"etc/htoprc","size":5,"mtime":%[1]q},{"snap":"htop","user":"alice","path":".config/htop/rc","size":12,"mtime":%[1]q}]}`, mtime)
+		case "/v2/snapshots/2/files":
+			fmt.Fprintln(w, `{"type":"sync","status-code":200,"status":"OK","result":[]}`)
 		case "/v2/snapshots/1/export":
 			w.Header().Set("Content-Type", client.SnapshotExportMediaType)
 			fmt.Fprint(w, "Hello World!")
@@ -0,0 +1,173 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/usersession/userlocal"
+)
+
+var (
+	userlocalInstall   = userlocal.Install
+	userlocalRemove    = userlocal.Remove
+	userlocalInstalled = userlocal.Installed
+)
+
+type cmdUserLocal struct{}
+
+var shortUserLocalHelp = i18n.G("Manage snaps installed for the user only")
+var longUserLocalHelp = i18n.G(`
+The user-local command contains sub-commands to manage snaps installed by an
+unprivileged user into ~/snap-managed, without snapd.
+
+This is an experimental feature, enabled with the experimental.user-local-snaps
+option. Only snaps without services, layouts or slots, and plugging only
+interfaces that are connected automatically, can be installed. Their apps are
+run from ~/snap-managed/bin.
+`)
+
+var shortUserLocalInstallHelp = i18n.G("Install a snap file for the user only")
+var longUserLocalInstallHelp = i18n.G(`
+The install command installs the given snap file into ~/snap-managed,
+replacing the revision installed before, if any. The snap is mounted with
+squashfuse by the systemd instance of the user.
+`)
+
+var shortUserLocalRemoveHelp = i18n.G("Remove a snap installed for the user only")
+var longUserLocalRemoveHelp = i18n.G(`
+The remove command removes the given user-local snap, with its data.
+`)
+
+var shortUserLocalListHelp = i18n.G("List the snaps installed for the user only")
+var longUserLocalListHelp = i18n.G(`
+The list command lists the user-local snaps and their apps.
+`)
+
+type cmdUserLocalInstall struct {
+	Positional struct {
+		Snap flags.Filename `positional-arg-name:"<snap-file>" required:"yes"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+type cmdUserLocalRemove struct {
+	Positional struct {
+		Snap string `positional-arg-name:"<snap>" required:"yes"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+type cmdUserLocalList struct{}
+
+func init() {
+	addSubCommand(&userLocalCommands, "install", shortUserLocalInstallHelp, longUserLocalInstallHelp, func() flags.Commander {
+		return &cmdUserLocalInstall{}
+	}, nil, []argDesc{{
+		// TRANSLATORS: This needs to begin with < and end with >
+		name: i18n.G("<snap-file>"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		desc: i18n.G("The snap file to install"),
+	}})
+	addSubCommand(&userLocalCommands, "remove", shortUserLocalRemoveHelp, longUserLocalRemoveHelp, func() flags.Commander {
+		return &cmdUserLocalRemove{}
+	}, nil, []argDesc{{
+		// TRANSLATORS: This needs to begin with < and end with >
+		name: i18n.G("<snap>"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		desc: i18n.G("The user-local snap to remove"),
+	}})
+	addSubCommand(&userLocalCommands, "list", shortUserLocalListHelp, longUserLocalListHelp, func() flags.Commander {
+		return &cmdUserLocalList{}
+	}, nil, nil)
+}
+
+func userHome() (string, error) {
+	usr, err := userCurrent()
+	if err != nil {
+		return "", err
+	}
+	return usr.HomeDir, nil
+}
+
+func (x *cmdUserLocalInstall) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	home, err := userHome()
+	if err != nil {
+		return err
+	}
+	info, err := userlocalInstall(home, string(x.Positional.Snap))
+	if err != nil {
+		return err
+	}
+	// TRANSLATORS: the first %s is a snap name, the second its version, the third its revision and the last a directory
+	fmt.Fprintf(Stdout, i18n.G("%s %s (%s) installed for the user, apps are in %s\n"), info.SnapName(), info.Version, info.Revision, userlocal.BinDir(home))
+	return nil
+}
+
+func (x *cmdUserLocalRemove) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	home, err := userHome()
+	if err != nil {
+		return err
+	}
+	if err := userlocalRemove(home, x.Positional.Snap); err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, i18n.G("%s removed\n"), x.Positional.Snap)
+	return nil
+}
+
+func (x *cmdUserLocalList) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	home, err := userHome()
+	if err != nil {
+		return err
+	}
+	snaps, err := userlocalInstalled(home)
+	if err != nil {
+		return err
+	}
+	if len(snaps) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No user-local snaps are installed."))
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Name\tVersion\tRev\tApps"))
+	for _, snap := range snaps {
+		apps := "-"
+		if len(snap.Apps) > 0 {
+			apps = strings.Join(snap.Apps, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", snap.Name, snap.Version, snap.Revision, apps)
+	}
+	return nil
+}
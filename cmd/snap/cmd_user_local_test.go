@@ -0,0 +1,114 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"errors"
+	"os/user"
+
+	"gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+	snapinfo "github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/usersession/userlocal"
+)
+
+func (s *SnapSuite) mockUserLocalHome(c *check.C) string {
+	home := c.MkDir()
+	s.AddCleanup(snap.MockUserCurrent(func() (*user.User, error) {
+		return &user.User{Username: "user", HomeDir: home}, nil
+	}))
+	return home
+}
+
+func (s *SnapSuite) TestUserLocalInstall(c *check.C) {
+	home := s.mockUserLocalHome(c)
+	installed := false
+	s.AddCleanup(snap.MockUserLocal(func(h, snapPath string) (*snapinfo.Info, error) {
+		c.Check(h, check.Equals, home)
+		c.Check(snapPath, check.Equals, "foo_1.0_amd64.snap")
+		installed = true
+		return &snapinfo.Info{
+			SideInfo: snapinfo.SideInfo{RealName: "foo", Revision: snapinfo.R(-1)},
+			Version:  "1.0",
+		}, nil
+	}, nil, nil))
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"user-local", "install", "foo_1.0_amd64.snap"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(installed, check.Equals, true)
+	c.Check(s.Stdout(), check.Equals, "foo 1.0 (x1) installed for the user, apps are in "+home+"/snap-managed/bin\n")
+}
+
+func (s *SnapSuite) TestUserLocalInstallError(c *check.C) {
+	s.mockUserLocalHome(c)
+	s.AddCleanup(snap.MockUserLocal(func(h, snapPath string) (*snapinfo.Info, error) {
+		return nil, errors.New(`cannot install snap "foo" locally: app "svc" is a service`)
+	}, nil, nil))
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"user-local", "install", "foo_1.0_amd64.snap"})
+	c.Assert(err, check.ErrorMatches, `cannot install snap "foo" locally: app "svc" is a service`)
+}
+
+func (s *SnapSuite) TestUserLocalRemove(c *check.C) {
+	home := s.mockUserLocalHome(c)
+	s.AddCleanup(snap.MockUserLocal(nil, func(h, name string) error {
+		c.Check(h, check.Equals, home)
+		c.Check(name, check.Equals, "foo")
+		return nil
+	}, nil))
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"user-local", "remove", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, "foo removed\n")
+}
+
+func (s *SnapSuite) TestUserLocalList(c *check.C) {
+	home := s.mockUserLocalHome(c)
+	s.AddCleanup(snap.MockUserLocal(nil, nil, func(h string) ([]*userlocal.LocalSnap, error) {
+		c.Check(h, check.Equals, home)
+		return []*userlocal.LocalSnap{
+			{Name: "bar", Revision: snapinfo.R(-3), Version: "2"},
+			{Name: "foo", Revision: snapinfo.R(-1), Version: "1.0", Apps: []string{"foo", "foo.tool"}},
+		}, nil
+	}))
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"user-local", "list"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `Name  Version  Rev  Apps
+bar   2        x3   -
+foo   1.0      x1   foo,foo.tool
+`)
+}
+
+func (s *SnapSuite) TestUserLocalListEmpty(c *check.C) {
+	s.mockUserLocalHome(c)
+	s.AddCleanup(snap.MockUserLocal(nil, nil, func(h string) ([]*userlocal.LocalSnap, error) {
+		return nil, nil
+	}))
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"user-local", "list"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "No user-local snaps are installed.\n")
+}
@@ -39,8 +39,10 @@ type cmdWarnings struct {
 	clientMixin
 	timeMixin
 	unicodeMixin
-	All     bool `long:"all"`
-	Verbose bool `long:"verbose"`
+	All      bool   `long:"all"`
+	Verbose  bool   `long:"verbose"`
+	JSON     bool   `long:"json"`
+	Severity string `long:"severity" choice:"info" choice:"warning" choice:"critical"`
 }
 
 type cmdOkay struct{ clientMixin }
@@ -53,7 +55,12 @@ Once warnings have been listed with 'snap warnings', 'snap okay' may be used to
 silence them. A warning that's been silenced in this way will not be listed
 again unless it happens again, _and_ a cooldown time has passed.
 
-Warnings expire automatically, and once expired they are forgotten.
+Warnings expire automatically, and once expired they are forgotten. How long
+warnings are kept, and how soon silenced warnings are listed again when they
+happen again, depends on their severity: critical warnings are kept the longest
+and repeated the soonest.
+
+Warnings are silenced for the user running 'snap okay' only.
 `)
 
 var shortOkayHelp = i18n.G("Acknowledge warnings")
@@ -70,6 +77,10 @@ func init() {
 		"all": i18n.G("Show all warnings"),
 		// TRANSLATORS: This should not start with a lowercase letter.
 		"verbose": i18n.G("Show more information"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"json": i18n.G("Output results in JSON format"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"severity": i18n.G("Show only warnings of at least the given severity"),
 	}), nil)
 	addCommand("okay", shortOkayHelp, longOkayHelp, func() flags.Commander { return &cmdOkay{} }, nil, nil)
 }
@@ -80,10 +91,21 @@ func (cmd *cmdWarnings) Execute(args []string) error {
 	}
 	now := time.Now()
 
-	warnings, err := cmd.client.Warnings(client.WarningsOptions{All: cmd.All})
+	warnings, err := cmd.client.Warnings(client.WarningsOptions{
+		All:      cmd.All,
+		Severity: cmd.Severity,
+	})
 	if err != nil {
 		return err
 	}
+	if cmd.JSON {
+		if len(warnings) > 0 {
+			if err := writeWarningTimestamp(now); err != nil {
+				return err
+			}
+		}
+		return outputWarningsJSON(warnings)
+	}
 	if len(warnings) == 0 {
 		if t, _ := lastWarningTimestamp(); t.IsZero() {
 			fmt.Fprintln(Stdout, i18n.G("No warnings."))
@@ -109,6 +131,10 @@ func (cmd *cmdWarnings) Execute(args []string) error {
 		if i > 0 {
 			fmt.Fprintln(w, "---")
 		}
+		// regular warnings only show their severity when verbose
+		if warning.Severity != "" && (cmd.Verbose || warning.Severity != "warning") {
+			fmt.Fprintf(w, "severity:\t%s\n", warning.Severity)
+		}
 		if cmd.Verbose {
 			fmt.Fprintf(w, "first-occurrence:\t%s\n", cmd.fmtTime(warning.FirstAdded))
 		}
@@ -131,6 +157,39 @@ func (cmd *cmdWarnings) Execute(args []string) error {
 	return nil
 }
 
+type jsonWarning struct {
+	Message     string     `json:"message"`
+	Severity    string     `json:"severity,omitempty"`
+	FirstAdded  time.Time  `json:"first-added"`
+	LastAdded   time.Time  `json:"last-added"`
+	LastShown   *time.Time `json:"last-shown,omitempty"`
+	ExpireAfter string     `json:"expire-after,omitempty"`
+	RepeatAfter string     `json:"repeat-after,omitempty"`
+}
+
+func outputWarningsJSON(warnings []*client.Warning) error {
+	jws := make([]jsonWarning, len(warnings))
+	for i, warning := range warnings {
+		jws[i] = jsonWarning{
+			Message:     warning.Message,
+			Severity:    warning.Severity,
+			FirstAdded:  warning.FirstAdded,
+			LastAdded:   warning.LastAdded,
+			ExpireAfter: warning.ExpireAfter.String(),
+			RepeatAfter: warning.RepeatAfter.String(),
+		}
+		if !warning.LastShown.IsZero() {
+			jws[i].LastShown = &warnings[i].LastShown
+		}
+	}
+	obj, err := json.Marshal(jws)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, "%s\n", obj)
+	return nil
+}
+
 func (cmd *cmdOkay) Execute(args []string) error {
 	if len(args) > 0 {
 		return ErrExtraArgs
@@ -143,6 +143,89 @@ warning: |
 `[1:])
 }
 
+const warningsWithSeverities = `{
+			"result": [
+			    {
+				"expire-after": "672h0m0s",
+				"first-added": "2018-09-19T12:41:18.505007495Z",
+				"last-added": "2018-09-19T12:41:18.505007495Z",
+				"message": "hello world number one",
+				"repeat-after": "24h0m0s",
+				"severity": "warning"
+			    },
+			    {
+				"expire-after": "2016h0m0s",
+				"first-added": "2018-09-19T12:44:19.680362867Z",
+				"last-added": "2018-09-19T12:44:19.680362867Z",
+				"last-shown": "2018-09-19T13:00:00Z",
+				"message": "the sky is falling",
+				"repeat-after": "4h0m0s",
+				"severity": "critical"
+			    }
+			],
+			"status": "OK",
+			"status-code": 200,
+			"type": "sync"
+		}`
+
+func (s *warningSuite) TestWarningsWithSeverities(c *check.C) {
+	s.RedirectClientToTestServer(mkWarningsFakeHandler(c, warningsWithSeverities))
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"warnings", "--abs-time", "--unicode=never"})
+	c.Assert(err, check.IsNil)
+	c.Check(rest, check.HasLen, 0)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(s.Stdout(), check.Equals, `
+last-occurrence:  2018-09-19T12:41:18Z
+warning: |
+  hello world number one
+---
+severity:         critical
+last-occurrence:  2018-09-19T12:44:19Z
+warning: |
+  the sky is falling
+`[1:])
+}
+
+func (s *warningSuite) TestWarningsSeverityFilter(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, check.Equals, "/v2/warnings")
+		c.Check(r.URL.Query().Get("severity"), check.Equals, "critical")
+		fmt.Fprintln(w, `{"type": "sync", "status-code": 200, "result": []}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"warnings", "--severity=critical"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "No warnings.\n")
+
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"warnings", "--severity=meh"})
+	c.Assert(err, check.ErrorMatches, `Invalid value .meh. for option .--severity.*`)
+}
+
+func (s *warningSuite) TestWarningsJSON(c *check.C) {
+	s.RedirectClientToTestServer(mkWarningsFakeHandler(c, warningsWithSeverities))
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"warnings", "--json"})
+	c.Assert(err, check.IsNil)
+	c.Check(rest, check.HasLen, 0)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(s.Stdout(), check.Equals, `[{"message":"hello world number one","severity":"warning","first-added":"2018-09-19T12:41:18.505007495Z","last-added":"2018-09-19T12:41:18.505007495Z","expire-after":"672h0m0s","repeat-after":"24h0m0s"},`+
+		`{"message":"the sky is falling","severity":"critical","first-added":"2018-09-19T12:44:19.680362867Z","last-added":"2018-09-19T12:44:19.680362867Z","last-shown":"2018-09-19T13:00:00Z","expire-after":"2016h0m0s","repeat-after":"4h0m0s"}]`+"\n")
+
+	// listing the warnings as JSON allows to okay them
+	t, err := snap.LastWarningTimestamp()
+	c.Assert(err, check.IsNil)
+	c.Check(t.IsZero(), check.Equals, false)
+}
+
+func (s *warningSuite) TestWarningsJSONEmpty(c *check.C) {
+	s.RedirectClientToTestServer(mkWarningsFakeHandler(c, `{"type": "sync", "status-code": 200, "result": []}`))
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"warnings", "--json"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "[]\n")
+}
+
 func (s *warningSuite) TestOkay(c *check.C) {
 	t0 := time.Now()
 	snap.WriteWarningTimestamp(t0)
@@ -37,6 +37,7 @@ import (
 	"github.com/snapcore/snapd/store/tooling"
 	"github.com/snapcore/snapd/testutil"
 	usersessionclient "github.com/snapcore/snapd/usersession/client"
+	"github.com/snapcore/snapd/usersession/userlocal"
 )
 
 var RunMain = run
@@ -71,6 +72,7 @@ var (
 	ReadRpc = readRpc
 
 	WriteWarningTimestamp = writeWarningTimestamp
+	LastWarningTimestamp  = lastWarningTimestamp
 	MaybePresentWarnings  = maybePresentWarnings
 
 	LongSnapDescription     = longSnapDescription
@@ -149,6 +151,7 @@ var (
 	MaybePrintCohortKey                           = (*infoWriter).maybePrintCohortKey
 	MaybePrintHealth                              = (*infoWriter).maybePrintHealth
 	MaybePrintRefreshInfo                         = (*infoWriter).maybePrintRefreshInfo
+	MaybePrintTrackingChannel                     = (*infoWriter).maybePrintTrackingChannel
 	WaitInhibitUnlock                             = waitInhibitUnlock
 	WaitWhileInhibited                            = waitWhileInhibited
 	IsLocked                                      = isLocked
@@ -388,7 +391,7 @@ func MockIoutilTempDir(f func(string, string) (string, error)) (restore func())
 	}
 }
 
-func MockDownloadDirect(f func(snapName string, revision snap.Revision, dlOpts tooling.DownloadSnapOptions) error) (restore func()) {
+func MockDownloadDirect(f func(snapName string, revision snap.Revision, dlOpts tooling.DownloadSnapOptions, architecture, series string) error) (restore func()) {
 	old := downloadDirect
 	downloadDirect = f
 	return func() {
@@ -496,3 +499,11 @@ func MockSeedWriterReadManifest(f func(manifestFile string) (*seedwriter.Manifes
 	seedwriterReadManifest = f
 	return restore
 }
+
+func MockUserLocal(install func(home, snapPath string) (*snap.Info, error), remove func(home, name string) error, installed func(home string) ([]*userlocal.LocalSnap, error)) (restore func()) {
+	oldInstall, oldRemove, oldInstalled := userlocalInstall, userlocalRemove, userlocalInstalled
+	userlocalInstall, userlocalRemove, userlocalInstalled = install, remove, installed
+	return func() {
+		userlocalInstall, userlocalRemove, userlocalInstalled = oldInstall, oldRemove, oldInstalled
+	}
+}
@@ -101,6 +101,21 @@ var debugCommands []*cmdInfo
 // routineCommands holds information about all internal commands.
 var routineCommands []*cmdInfo
 
+// cohortCommands holds information about all cohort commands.
+var cohortCommands []*cmdInfo
+
+// authCommands holds information about all auth commands.
+var authCommands []*cmdInfo
+
+// userLocalCommands holds information about all user-local commands.
+var userLocalCommands []*cmdInfo
+
+// cacheCommands holds information about all cache commands.
+var cacheCommands []*cmdInfo
+
+// kernelModulesCommands holds information about all kernel-modules commands.
+var kernelModulesCommands []*cmdInfo
+
 // addCommand replaces parser.addCommand() in a way that is compatible with
 // re-constructing a pristine parser.
 func addCommand(name, shortHelp, longHelp string, builder func() flags.Commander, optDescs map[string]string, argDescs []argDesc) *cmdInfo {
@@ -132,6 +147,23 @@ func addDebugCommand(name, shortHelp, longHelp string, builder func() flags.Comm
 	return info
 }
 
+// addSubCommand replaces parser.addCommand() in a way that is compatible
+// with re-constructing a pristine parser. It is meant for adding the
+// sub-commands of a command grouping them, like "snap cohort", to the
+// given group.
+func addSubCommand(group *[]*cmdInfo, name, shortHelp, longHelp string, builder func() flags.Commander, optDescs map[string]string, argDescs []argDesc) *cmdInfo {
+	info := &cmdInfo{
+		name:      name,
+		shortHelp: shortHelp,
+		longHelp:  longHelp,
+		builder:   builder,
+		optDescs:  optDescs,
+		argDescs:  argDescs,
+	}
+	*group = append(*group, info)
+	return info
+}
+
 // addRoutineCommand replaces parser.addCommand() in a way that is
 // compatible with re-constructing a pristine parser. It is meant for
 // adding "snap routine" commands.
@@ -330,7 +362,30 @@ func Parser(cli *client.Client) *flags.Parser {
 	// add --help like what go-flags would do for us, but hidden
 	addHelp(parser)
 
-	seen := make(map[string]bool, len(commands)+len(debugCommands)+len(routineCommands))
+	// commands grouping sub-commands
+	groups := []struct {
+		name                string
+		shortHelp, longHelp string
+		data                interface{}
+		hidden              bool
+		commands            []*cmdInfo
+	}{
+		{"debug", shortDebugHelp, longDebugHelp, &cmdDebug{}, false, debugCommands},
+		{"cohort", shortCohortHelp, longCohortHelp, &cmdCohort{}, false, cohortCommands},
+		{"auth", shortAuthHelp, longAuthHelp, &cmdAuth{}, false, authCommands},
+		// hidden while experimental
+		{"user-local", shortUserLocalHelp, longUserLocalHelp, &cmdUserLocal{}, true, userLocalCommands},
+		{"cache", shortCacheHelp, longCacheHelp, &cmdCache{}, false, cacheCommands},
+		{"kernel-modules", shortKernelModulesHelp, longKernelModulesHelp, &cmdKernelModules{}, false, kernelModulesCommands},
+		// internal
+		{"routine", shortRoutineHelp, longRoutineHelp, &cmdRoutine{}, true, routineCommands},
+	}
+
+	numCommands := len(commands)
+	for _, group := range groups {
+		numCommands += len(group.commands)
+	}
+	seen := make(map[string]bool, numCommands)
 	checkUnique := func(ci *cmdInfo, kind string) {
 		if seen[ci.shortHelp] && ci.shortHelp != "Internal" && ci.shortHelp != "Deprecated (hidden)" {
 			logger.Panicf(`%scommand %q has an already employed description != "Internal"|"Deprecated (hidden)": %s`, kind, ci.name, ci.shortHelp)
@@ -342,25 +397,19 @@ func Parser(cli *client.Client) *flags.Parser {
 	registerCommands(cli, parser, parser.Command, commands, func(ci *cmdInfo) {
 		checkUnique(ci, "")
 	})
-	// Add the debug command
-	debugCommand, err := parser.AddCommand("debug", shortDebugHelp, longDebugHelp, &cmdDebug{})
-	if err != nil {
-		logger.Panicf("cannot add command %q: %v", "debug", err)
-	}
-	// Add all the sub-commands of the debug command
-	registerCommands(cli, parser, debugCommand, debugCommands, func(ci *cmdInfo) {
-		checkUnique(ci, "debug ")
-	})
-	// Add the internal command
-	routineCommand, err := parser.AddCommand("routine", shortRoutineHelp, longRoutineHelp, &cmdRoutine{})
-	routineCommand.Hidden = true
-	if err != nil {
-		logger.Panicf("cannot add command %q: %v", "internal", err)
+	for _, group := range groups {
+		// Add the command grouping sub-commands
+		groupCommand, err := parser.AddCommand(group.name, group.shortHelp, group.longHelp, group.data)
+		if err != nil {
+			logger.Panicf("cannot add command %q: %v", group.name, err)
+		}
+		groupCommand.Hidden = group.hidden
+		// Add all of its sub-commands
+		kind := group.name + " "
+		registerCommands(cli, parser, groupCommand, group.commands, func(ci *cmdInfo) {
+			checkUnique(ci, kind)
+		})
 	}
-	// Add all the sub-commands of the routine command
-	registerCommands(cli, parser, routineCommand, routineCommands, func(ci *cmdInfo) {
-		checkUnique(ci, "routine ")
-	})
 	return parser
 }
 
@@ -381,6 +430,9 @@ func mkClient() *client.Client {
 	// Set client user-agent when talking to the snapd daemon to the
 	// same value as when talking to the store.
 	cfg.UserAgent = snapdenv.UserAgent()
+	// API tokens allow automation to perform some operations
+	// without further authorization
+	cfg.Token = os.Getenv("SNAPD_API_TOKEN")
 
 	cli := client.New(cfg)
 	goos := runtime.GOOS
@@ -64,6 +64,9 @@ type Notes struct {
 	Health           string
 	Price            string
 	Held             bool
+	Pinned           bool
+	Quarantined      bool
+	PublisherChange  string
 }
 
 func NotesFromChannelSnapInfo(ref *snap.ChannelSnapInfo) *Notes {
@@ -105,6 +108,9 @@ func NotesFromLocal(snp *client.Snap) *Notes {
 		InCohort:         snp.CohortKey != "",
 		Health:           health,
 		Held:             snp.Hold != nil && snp.Hold.After(timeNow()),
+		Pinned:           snp.Pinned,
+		Quarantined:      snp.Quarantined,
+		PublisherChange:  snp.PublisherChange,
 	}
 }
 
@@ -172,6 +178,20 @@ func (n *Notes) String() string {
 		ns = append(ns, i18n.G("held"))
 	}
 
+	if n.Pinned {
+		// TRANSLATORS: if possible, a single short word
+		ns = append(ns, i18n.G("pinned"))
+	}
+
+	if n.Quarantined {
+		// TRANSLATORS: if possible, a single short word
+		ns = append(ns, i18n.G("quarantined"))
+	}
+
+	if n.PublisherChange != "" {
+		ns = append(ns, n.PublisherChange)
+	}
+
 	if len(ns) == 0 {
 		return "-"
 	}
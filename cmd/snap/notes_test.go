@@ -102,6 +102,24 @@ func (notesSuite) TestNotesHeld(c *check.C) {
 	}).String(), check.Equals, "held")
 }
 
+func (notesSuite) TestNotesPinned(c *check.C) {
+	c.Check((&snap.Notes{
+		Pinned: true,
+	}).String(), check.Equals, "pinned")
+}
+
+func (notesSuite) TestNotesQuarantined(c *check.C) {
+	c.Check((&snap.Notes{
+		Quarantined: true,
+	}).String(), check.Equals, "quarantined")
+}
+
+func (notesSuite) TestNotesPublisherChange(c *check.C) {
+	c.Check((&snap.Notes{
+		PublisherChange: "publisher-downgraded",
+	}).String(), check.Equals, "publisher-downgraded")
+}
+
 func (notesSuite) TestNotesNothing(c *check.C) {
 	c.Check((&snap.Notes{}).String(), check.Equals, "-")
 }
@@ -122,6 +140,9 @@ func (notesSuite) TestNotesFromLocal(c *check.C) {
 	c.Check(snap.NotesFromLocal(&client.Snap{CohortKey: ""}).InCohort, check.Equals, false)
 	c.Check(snap.NotesFromLocal(&client.Snap{CohortKey: "123"}).InCohort, check.Equals, true)
 	c.Check(snap.NotesFromLocal(&client.Snap{Health: &client.SnapHealth{Status: "blocked"}}).Health, check.Equals, "blocked")
+	c.Check(snap.NotesFromLocal(&client.Snap{Quarantined: true}).Quarantined, check.Equals, true)
+	c.Check(snap.NotesFromLocal(&client.Snap{Pinned: true}).Pinned, check.Equals, true)
+	c.Check(snap.NotesFromLocal(&client.Snap{PublisherChange: "publisher-changed"}).PublisherChange, check.Equals, "publisher-changed")
 }
 
 func (notesSuite) TestHeldNoteFromLocal(c *check.C) {
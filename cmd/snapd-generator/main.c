@@ -166,28 +166,74 @@ static bool is_snap_try_snap_unit(const char *units_dir,
 	return stat(what, &st) == 0 && (st.st_mode & S_IFMT) == S_IFDIR;
 }
 
-static int ensure_fusesquashfs_inside_container(const char *normal_dir)
+// Mount units of snaps kept by snapd when the mounts-generator feature is
+// enabled. They are produced at boot by this generator instead of being
+// installed and enabled in /etc/systemd/system.
+#define SNAPD_MOUNT_UNITS_DIR "/var/lib/snapd/mount-units"
+
+static int ensure_snap_mount_units(const char *normal_dir)
 {
-	// check if we are running inside a container, systemd
-	// provides this file all the way back to trusty if run in a
-	// container
-	if (!file_exists("/run/systemd/container")) {
-		return 0;
+	DIR *units_dir SC_CLEANUP(sc_cleanup_closedir) = NULL;
+	units_dir = opendir(SNAPD_MOUNT_UNITS_DIR);
+	if (units_dir == NULL) {
+		if (errno == ENOENT) {
+			// the feature is not in use
+			return 0;
+		}
+		fprintf(stderr, "cannot open %s directory: %m\n",
+			SNAPD_MOUNT_UNITS_DIR);
+		return 1;
 	}
 
-	const char *fstype;
-	if (executable_exists("squashfuse")) {
-		fstype = "fuse.squashfuse";
-	} else if (executable_exists("snapfuse")) {
-		fstype = "fuse.snapfuse";
-	} else {
-		fprintf(stderr,
-			"cannot find squashfuse or snapfuse executable\n");
-		return 2;
+	char wants_dir[PATH_MAX + 1] = { 0 };
+	sc_must_snprintf(wants_dir, sizeof wants_dir,
+			 "%s/snapd.mounts.target.wants", normal_dir);
+
+	char target[PATH_MAX + 1] = { 0 };
+	char fname[PATH_MAX + 1] = { 0 };
+	int status = 0;
+
+	struct dirent *ent;
+	while ((ent = readdir(units_dir))) {
+		if (!sc_endswith(ent->d_name, ".mount")) {
+			continue;
+		}
+		if (mkdir(wants_dir, 0755) != 0 && errno != EEXIST) {
+			fprintf(stderr, "cannot create %s directory: %m\n",
+				wants_dir);
+			return 1;
+		}
+		sc_must_snprintf(target, sizeof target, "%s/%s",
+				 SNAPD_MOUNT_UNITS_DIR, ent->d_name);
+
+		// Make the unit known to systemd.
+		sc_must_snprintf(fname, sizeof fname, "%s/%s", normal_dir,
+				 ent->d_name);
+		if (symlink(target, fname) != 0 && errno != EEXIST) {
+			fprintf(stderr, "cannot create symlink %s: %m\n",
+				fname);
+			status = 1;
+			continue;
+		}
+		// And pull it in like enabling it would.
+		sc_must_snprintf(fname, sizeof fname, "%s/%s", wants_dir,
+				 ent->d_name);
+		if (symlink(target, fname) != 0 && errno != EEXIST) {
+			fprintf(stderr, "cannot create symlink %s: %m\n",
+				fname);
+			status = 1;
+		}
 	}
 
+	return status;
+}
+
+static int write_fusesquashfs_dropins(const char *units_dir_path,
+				      const char *normal_dir,
+				      const char *fstype)
+{
 	DIR *units_dir SC_CLEANUP(sc_cleanup_closedir) = NULL;
-	units_dir = opendir("/etc/systemd/system");
+	units_dir = opendir(units_dir_path);
 	if (units_dir == NULL) {
 		// nothing to do
 		return 0;
@@ -206,7 +252,7 @@ static int ensure_fusesquashfs_inside_container(const char *normal_dir)
 		      || sc_startswith(ent->d_name, "var-lib-snapd-snap-"))) {
 			continue;
 		}
-		if (is_snap_try_snap_unit("/etc/systemd/system", ent->d_name)) {
+		if (is_snap_try_snap_unit(units_dir_path, ent->d_name)) {
 			continue;
 		}
 		sc_must_snprintf(fname, sizeof fname,
@@ -238,6 +284,34 @@ static int ensure_fusesquashfs_inside_container(const char *normal_dir)
 	return 0;
 }
 
+static int ensure_fusesquashfs_inside_container(const char *normal_dir)
+{
+	// check if we are running inside a container, systemd
+	// provides this file all the way back to trusty if run in a
+	// container
+	if (!file_exists("/run/systemd/container")) {
+		return 0;
+	}
+
+	const char *fstype;
+	if (executable_exists("squashfuse")) {
+		fstype = "fuse.squashfuse";
+	} else if (executable_exists("snapfuse")) {
+		fstype = "fuse.snapfuse";
+	} else {
+		fprintf(stderr,
+			"cannot find squashfuse or snapfuse executable\n");
+		return 2;
+	}
+
+	int status = 0;
+	status = write_fusesquashfs_dropins("/etc/systemd/system", normal_dir,
+					    fstype);
+	status |= write_fusesquashfs_dropins(SNAPD_MOUNT_UNITS_DIR, normal_dir,
+					     fstype);
+	return status;
+}
+
 int main(int argc, char **argv)
 {
 	if (argc != 4) {
@@ -252,6 +326,7 @@ int main(int argc, char **argv)
 
 	int status = 0;
 	status = ensure_root_fs_shared(normal_dir);
+	status |= ensure_snap_mount_units(normal_dir);
 	status |= ensure_fusesquashfs_inside_container(normal_dir);
 
 	return status;
@@ -96,7 +96,8 @@ func (ac openAccess) CheckAccess(d *Daemon, r *http.Request, ucred *ucrednet, us
 // provided they were not received on snapd-snap.socket
 //
 // A user is considered authenticated if they provide a macaroon, are
-// the root user according to peer credentials, or granted access by
+// the root user according to peer credentials, provide a bearer API
+// token allowing the requested snap operation, or granted access by
 // Polkit.
 type authenticatedAccess struct {
 	Polkit string
@@ -115,6 +116,11 @@ func (ac authenticatedAccess) CheckAccess(d *Daemon, r *http.Request, ucred *ucr
 		return nil
 	}
 
+	// tokens minted by the administrator may allow some operations
+	if apiTokenAllows(d, r) {
+		return nil
+	}
+
 	// We check polkit last because it may result in the user
 	// being prompted for authorisation. This should be avoided if
 	// access is otherwise granted.
@@ -27,6 +27,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/snapcore/snapd/i18n"
 	"github.com/snapcore/snapd/overlord/aspectstate"
 	"github.com/snapcore/snapd/overlord/assertstate"
 	"github.com/snapcore/snapd/overlord/auth"
@@ -68,7 +69,12 @@ var api = []*Command{
 	debugCmd,
 	snapshotCmd,
 	snapshotExportCmd,
+	snapshotFilesCmd,
 	connectionsCmd,
+	confinementReportCmd,
+	desktopEntriesCmd,
+	remoteAPITokensCmd,
+	apiTokensCmd,
 	modelCmd,
 	cohortsCmd,
 	serialModelCmd,
@@ -80,11 +86,18 @@ var api = []*Command{
 	validationSetsCmd,
 	routineConsoleConfStartCmd,
 	systemRecoveryKeysCmd,
+	systemAttestationCmd,
+	systemRebootCmd,
+	problemReportsCmd,
+	problemReportCmd,
+	measurementsCmd,
 	quotaGroupsCmd,
 	quotaGroupInfoCmd,
 	aspectsCmd,
 	noticesCmd,
 	noticeCmd,
+	cacheCmd,
+	kernelModulesCmd,
 }
 
 const (
@@ -126,7 +139,22 @@ func userFromRequest(st *state.State, req *http.Request) (*auth.UserState, error
 	return user, err
 }
 
-var muxVars = mux.Vars
+// requestLocale returns the locale negotiated from the Accept-Language
+// header of the request for the user-facing messages, or nil if there is
+// no header or no translations match it.
+func requestLocale(req *http.Request) *i18n.Locale {
+	acceptLanguage := req.Header.Get("Accept-Language")
+	if acceptLanguage == "" {
+		return nil
+	}
+	return i18nNegotiateLocale(acceptLanguage)
+}
+
+var (
+	muxVars = mux.Vars
+
+	i18nNegotiateLocale = i18n.NegotiateLocale
+)
 
 func storeFrom(d *Daemon) snapstate.StoreService {
 	st := d.overlord.State()
@@ -155,6 +183,9 @@ var (
 	snapstateHoldRefreshesBySystem          = snapstate.HoldRefreshesBySystem
 	snapstateLongestGatingHold              = snapstate.LongestGatingHold
 	snapstateSystemHold                     = snapstate.SystemHold
+	snapstateOverrideRefreshInhibition      = snapstate.OverrideRefreshInhibition
+	snapstateBlockRevision                  = snapstate.BlockRevision
+	snapstateUnpin                          = snapstate.Unpin
 
 	configstateConfigureInstalled = configstate.ConfigureInstalled
 
@@ -163,6 +194,10 @@ var (
 
 	aspectstateGetAspect = aspectstate.GetAspect
 	aspectstateSetAspect = aspectstate.SetAspect
+
+	aspectstateCompleteAspect = aspectstate.CompleteAspect
+
+	aspectstateCommitTransaction = aspectstate.CommitTransaction
 )
 
 func ensureStateSoonImpl(st *state.State) {
@@ -27,6 +27,7 @@ import (
 	"github.com/snapcore/snapd/aspects"
 	"github.com/snapcore/snapd/overlord/aspectstate"
 	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/strutil"
 )
 
@@ -43,7 +44,11 @@ var (
 func getAspect(c *Command, r *http.Request, _ *auth.UserState) Response {
 	vars := muxVars(r)
 	account, bundleName, aspect := vars["account"], vars["bundle"], vars["aspect"]
-	fields := strutil.CommaSeparatedList(r.URL.Query().Get("fields"))
+	query := r.URL.Query()
+	if query.Has("complete") {
+		return completeAspect(account, bundleName, aspect, query.Get("complete"))
+	}
+	fields := strutil.CommaSeparatedList(query.Get("fields"))
 
 	if len(fields) == 0 {
 		return BadRequest("missing aspect fields")
@@ -82,6 +87,17 @@ func getAspect(c *Command, r *http.Request, _ *auth.UserState) Response {
 	return SyncResponse(results)
 }
 
+// completeAspect returns the requests that complete the partial request, for
+// use in interactive completion.
+func completeAspect(account, bundleName, aspect, partial string) Response {
+	completions, err := aspectstateCompleteAspect(account, bundleName, aspect, partial)
+	if err != nil {
+		return toAPIError(err)
+	}
+
+	return SyncResponse(completions)
+}
+
 func setAspect(c *Command, r *http.Request, _ *auth.UserState) Response {
 	vars := muxVars(r)
 	account, bundleName, aspect := vars["account"], vars["bundle"], vars["aspect"]
@@ -108,13 +124,21 @@ func setAspect(c *Command, r *http.Request, _ *auth.UserState) Response {
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
+	// committing may require changes to be applied to the system (e.g.,
+	// netplan settings for system/network)
+	ts, err := aspectstateCommitTransaction(st, account, bundleName, tx)
+	if err != nil {
 		return toAPIError(err)
 	}
 
+	var tss []*state.TaskSet
+	if ts != nil {
+		tss = append(tss, ts)
+	}
+
 	// NOTE: could be sync but this is closer to the final version and the conf API
 	summary := fmt.Sprintf("Set aspect %s/%s/%s", account, bundleName, aspect)
-	chg := newChange(st, "set-aspect", summary, nil, nil)
+	chg := newChange(st, "set-aspect", summary, tss, nil)
 	ensureStateSoon(st)
 
 	return AsyncResponse(nil, chg.ID())
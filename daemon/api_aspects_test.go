@@ -303,6 +303,64 @@ func (s *aspectsSuite) TestGetAspectError(c *C) {
 	}
 }
 
+func (s *aspectsSuite) TestCompleteAspect(c *C) {
+	var calls int
+	restore := daemon.MockAspectstateComplete(func(acc, bundleName, aspect, partial string) ([]string, error) {
+		calls++
+		c.Check(acc, Equals, "system")
+		c.Check(bundleName, Equals, "network")
+		c.Check(aspect, Equals, "wifi-setup")
+		c.Check(partial, Equals, "ss")
+		return []string{"ssid", "ssids"}, nil
+	})
+	defer restore()
+
+	req, err := http.NewRequest("GET", "/v2/aspects/system/network/wifi-setup?complete=ss", nil)
+	c.Assert(err, IsNil)
+
+	rspe := s.syncReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 200)
+	c.Check(rspe.Result, DeepEquals, []string{"ssid", "ssids"})
+	c.Check(calls, Equals, 1)
+}
+
+func (s *aspectsSuite) TestCompleteAspectEmptyPartial(c *C) {
+	restore := daemon.MockAspectstateComplete(func(_, _, _, partial string) ([]string, error) {
+		c.Check(partial, Equals, "")
+		return []string{}, nil
+	})
+	defer restore()
+
+	req, err := http.NewRequest("GET", "/v2/aspects/system/network/wifi-setup?complete=", nil)
+	c.Assert(err, IsNil)
+
+	rspe := s.syncReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 200)
+	c.Check(rspe.Result, DeepEquals, []string{})
+}
+
+func (s *aspectsSuite) TestCompleteAspectError(c *C) {
+	for _, t := range []struct {
+		err  error
+		code int
+	}{
+		{err: &aspects.NotFoundError{}, code: 404},
+		{err: &aspects.BadRequestError{}, code: 400},
+		{err: errors.New("internal"), code: 500},
+	} {
+		restore := daemon.MockAspectstateComplete(func(_, _, _, _ string) ([]string, error) {
+			return nil, t.err
+		})
+
+		req, err := http.NewRequest("GET", "/v2/aspects/system/network/wifi-setup?complete=foo", nil)
+		c.Assert(err, IsNil)
+
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, Equals, t.code, Commentf("%v", t.err))
+		restore()
+	}
+}
+
 func (s *aspectsSuite) TestGetAspectMissingField(c *C) {
 	req, err := http.NewRequest("GET", "/v2/aspects/system/network/wifi-setup", nil)
 	c.Assert(err, IsNil)
@@ -422,6 +480,63 @@ func (s *aspectsSuite) TestUnsetAspect(c *C) {
 	c.Check(chg.Summary(), check.Equals, `Set aspect system/network/wifi-setup`)
 }
 
+func (s *aspectsSuite) TestSetAspectAppliesSystemChanges(c *C) {
+	restore := daemon.MockAspectstateSet(func(bag aspects.DataBag, _, _, _, _ string, value interface{}) error {
+		return bag.Set("netplan.network.renderer", value)
+	})
+	defer restore()
+
+	restore = daemon.MockAspectstateCommitTransaction(func(st *state.State, account, bundleName string, tx *aspects.Transaction) (*state.TaskSet, error) {
+		c.Check(account, Equals, "system")
+		c.Check(bundleName, Equals, "network")
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return state.NewTaskSet(st.NewTask("run-hook", "apply netplan")), nil
+	})
+	defer restore()
+
+	buf := bytes.NewBufferString(`{"renderer": "networkd"}`)
+	req, err := http.NewRequest("PUT", "/v2/aspects/system/network/netplan", buf)
+	c.Assert(err, IsNil)
+	req.Header.Set("Content-Type", "application/json")
+
+	rspe := s.asyncReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 202)
+
+	st := s.d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+
+	chg := st.Change(rspe.Change)
+	c.Check(chg.Kind(), Equals, "set-aspect")
+	c.Check(chg.Summary(), Equals, `Set aspect system/network/netplan`)
+	tasks := chg.Tasks()
+	c.Assert(tasks, HasLen, 1)
+	c.Check(tasks[0].Summary(), Equals, "apply netplan")
+}
+
+func (s *aspectsSuite) TestSetAspectCommitError(c *C) {
+	restore := daemon.MockAspectstateSet(func(aspects.DataBag, string, string, string, string, interface{}) error {
+		return nil
+	})
+	defer restore()
+
+	restore = daemon.MockAspectstateCommitTransaction(func(*state.State, string, string, *aspects.Transaction) (*state.TaskSet, error) {
+		return nil, errors.New("cannot get system netplan configuration: boom")
+	})
+	defer restore()
+
+	buf := bytes.NewBufferString(`{"renderer": "networkd"}`)
+	req, err := http.NewRequest("PUT", "/v2/aspects/system/network/netplan", buf)
+	c.Assert(err, IsNil)
+	req.Header.Set("Content-Type", "application/json")
+
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 500)
+	c.Check(rspe.Message, Equals, "cannot get system netplan configuration: boom")
+}
+
 func (s *aspectsSuite) TestSetAspectError(c *C) {
 	type test struct {
 		name string
@@ -21,7 +21,6 @@ package daemon
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -29,6 +28,7 @@ import (
 	"github.com/snapcore/snapd/asserts"
 	"github.com/snapcore/snapd/overlord/assertstate"
 	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/store"
 )
 
 var (
@@ -52,10 +52,11 @@ var (
 // such endpoints that can either do JSON or assertion depending on the value
 // of the the URL query parameters
 type daemonAssertOptions struct {
-	jsonResult  bool
-	headersOnly bool
-	remote      bool
-	headers     map[string]string
+	jsonResult    bool
+	headersOnly   bool
+	remote        bool
+	prerequisites bool
+	headers       map[string]string
 }
 
 // helper for parsing url query options into formatting option vars
@@ -72,6 +73,13 @@ func parseHeadersFormatOptionsFromURL(q url.Values) (*daemonAssertOptions, error
 			default:
 				return nil, errors.New(`"remote" query parameter when used must be set to "true" or "false" or left unset`)
 			}
+		case "prerequisites":
+			switch v {
+			case "true", "false":
+				res.prerequisites, _ = strconv.ParseBool(v)
+			default:
+				return nil, errors.New(`"prerequisites" query parameter when used must be set to "true" or "false" or left unset`)
+			}
 		case "json":
 			switch v {
 			case "false":
@@ -119,18 +127,8 @@ func doAssert(c *Command, r *http.Request, user *auth.UserState) Response {
 	return SyncResponse(nil)
 }
 
-func assertsFindOneRemote(c *Command, at *asserts.AssertionType, headers map[string]string, user *auth.UserState) ([]asserts.Assertion, error) {
-	primaryKeys, err := asserts.PrimaryKeyFromHeaders(at, headers)
-	if err != nil {
-		return nil, fmt.Errorf("cannot query remote assertion: %v", err)
-	}
-	sto := storeFrom(c.d)
-	as, err := sto.Assertion(at, primaryKeys, user)
-	if err != nil {
-		return nil, err
-	}
-
-	return []asserts.Assertion{as}, nil
+func assertsFindOneRemote(c *Command, at *asserts.AssertionType, opts *daemonAssertOptions, user *auth.UserState) ([]asserts.Assertion, error) {
+	return store.QueryAssertion(storeFrom(c.d), at, opts.headers, opts.prerequisites, user)
 }
 
 func assertsFindManyInState(c *Command, at *asserts.AssertionType, headers map[string]string, opts *daemonAssertOptions) ([]asserts.Assertion, error) {
@@ -153,9 +151,13 @@ func assertsFindMany(c *Command, r *http.Request, user *auth.UserState) Response
 		return BadRequest(err.Error())
 	}
 
+	if opts.prerequisites && !opts.remote {
+		return BadRequest(`"prerequisites" query parameter can only be used together with "remote"`)
+	}
+
 	var assertions []asserts.Assertion
 	if opts.remote {
-		assertions, err = assertsFindOneRemote(c, assertType, opts.headers, user)
+		assertions, err = assertsFindOneRemote(c, assertType, opts, user)
 	} else {
 		assertions, err = assertsFindManyInState(c, assertType, opts.headers, opts)
 	}
@@ -22,11 +22,13 @@ package daemon_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"sort"
 	"strconv"
+	"time"
 
 	"gopkg.in/check.v1"
 
@@ -471,3 +473,75 @@ validation: unproven
 `)
 
 }
+
+func (s *assertsSuite) TestAssertsFindManyRemoteWithPrerequisites(c *check.C) {
+	devAcct := assertstest.NewAccount(s.StoreSigning, "developer1", map[string]interface{}{
+		"account-id": "developer1id",
+	}, "")
+	snapDecl, err := s.StoreSigning.Sign(asserts.SnapDeclarationType, map[string]interface{}{
+		"series":       "16",
+		"snap-id":      "foo-id",
+		"snap-name":    "foo",
+		"publisher-id": "developer1id",
+		"timestamp":    time.Now().Format(time.RFC3339),
+	}, nil, "")
+	c.Assert(err, check.IsNil)
+
+	var queried []string
+	s.mockAssertionFn = func(at *asserts.AssertionType, headers []string, user *auth.UserState) (asserts.Assertion, error) {
+		queried = append(queried, at.Name)
+		switch at {
+		case asserts.SnapDeclarationType:
+			c.Check(headers, check.DeepEquals, []string{"16", "foo-id"})
+			return snapDecl, nil
+		case asserts.AccountType:
+			c.Check(headers, check.DeepEquals, []string{"developer1id"})
+			return devAcct, nil
+		case asserts.AccountKeyType:
+			return s.StoreSigning.StoreAccountKey(""), nil
+		}
+		return nil, fmt.Errorf("unexpected assertion type %q", at.Name)
+	}
+
+	// Execute
+	req, err := http.NewRequest("GET", "/v2/assertions/snap-declaration?remote=true&prerequisites=true&series=16&snap-id=foo-id", nil)
+	c.Assert(err, check.IsNil)
+	s.asUserAuth(c, req)
+
+	rec := httptest.NewRecorder()
+	s.serveHTTP(c, rec, req)
+	// Verify
+	c.Check(rec.Code, check.Equals, 200, check.Commentf("body %q", rec.Body))
+	c.Check(rec.Header().Get("X-Ubuntu-Assertions-Count"), check.Equals, "3")
+	c.Check(queried, check.DeepEquals, []string{"snap-declaration", "account", "account-key"})
+
+	dec := asserts.NewDecoder(rec.Body)
+	var got []string
+	for {
+		a, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, check.IsNil)
+		got = append(got, a.Type().Name)
+	}
+	// prerequisites come first
+	c.Check(got, check.DeepEquals, []string{"account-key", "account", "snap-declaration"})
+}
+
+func (s *assertsSuite) TestAssertsFindManyPrerequisitesWithoutRemote(c *check.C) {
+	// Execute
+	req, err := http.NewRequest("GET", "/v2/assertions/account?prerequisites=true&account-id=can0nical", nil)
+	c.Assert(err, check.IsNil)
+	s.asUserAuth(c, req)
+
+	rec := httptest.NewRecorder()
+	s.serveHTTP(c, rec, req)
+	// Verify
+	c.Check(rec.Code, check.Equals, 400, check.Commentf("body %q", rec.Body))
+	var rsp daemon.RespJSON
+	c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), check.IsNil)
+	c.Check(rsp.Result, check.DeepEquals, map[string]interface{}{
+		"message": `"prerequisites" query parameter can only be used together with "remote"`,
+	})
+}
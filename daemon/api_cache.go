@@ -0,0 +1,176 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/store"
+)
+
+var cacheCmd = &Command{
+	Path:        "/v2/cache",
+	GET:         getCache,
+	POST:        postCache,
+	ReadAccess:  openAccess{},
+	WriteAccess: rootAccess{},
+}
+
+var newCacheManager = func() *store.CacheManager {
+	// the maximum number of items only matters when adding to the cache
+	return store.NewCacheManager(dirs.SnapDownloadCacheDir, 0)
+}
+
+func getCache(c *Command, r *http.Request, user *auth.UserState) Response {
+	cm := newCacheManager()
+	entries, err := cm.Entries()
+	if err != nil {
+		return InternalError("cannot list cache entries: %v", err)
+	}
+
+	blobs, err := installedBlobs(c.d.overlord.State())
+	if err != nil {
+		return InternalError("cannot list installed snaps: %v", err)
+	}
+
+	result := make([]client.CacheEntry, 0, len(entries))
+	for _, e := range entries {
+		ce := cacheEntryFromStore(&e)
+		if ce.Referenced {
+			ce.Snaps = blobsUsing(cm.GetPath(e.Key), blobs)
+		}
+		result = append(result, ce)
+	}
+	return SyncResponse(result)
+}
+
+func postCache(c *Command, r *http.Request, user *auth.UserState) Response {
+	var action client.CacheAction
+	dec := json.NewDecoder(r.Body)
+	if err := dec.Decode(&action); err != nil {
+		return BadRequest("cannot decode request body into cache action: %v", err)
+	}
+	if dec.More() {
+		return BadRequest("spurious content after cache action")
+	}
+	if action.Action != "clean" {
+		return BadRequest("unknown cache action %q", action.Action)
+	}
+
+	opts := &store.CachePruneOptions{}
+	if action.OlderThan != "" {
+		olderThan, err := time.ParseDuration(action.OlderThan)
+		if err != nil {
+			return BadRequest("invalid older-than duration %q: %v", action.OlderThan, err)
+		}
+		if olderThan <= 0 {
+			return BadRequest("older-than duration must be positive")
+		}
+		opts.OlderThan = time.Now().Add(-olderThan)
+	}
+	if action.MaxSize < 0 {
+		return BadRequest("max-size cannot be negative")
+	}
+	opts.MaxSize = action.MaxSize
+
+	removed, err := newCacheManager().Prune(opts)
+	// report what was removed even if some entries could not be
+	if err != nil && len(removed) == 0 {
+		return InternalError("cannot clean cache: %v", err)
+	}
+
+	res := client.CacheCleanResult{Removed: make([]client.CacheEntry, 0, len(removed))}
+	for _, e := range removed {
+		res.Removed = append(res.Removed, cacheEntryFromStore(&e))
+		res.Reclaimed += e.Size
+	}
+	return SyncResponse(&res)
+}
+
+func cacheEntryFromStore(e *store.CacheEntry) client.CacheEntry {
+	return client.CacheEntry{
+		Digest:     e.Key,
+		Size:       e.Size,
+		LastUsed:   e.ModTime,
+		Referenced: e.Links > 1,
+	}
+}
+
+type installedBlob struct {
+	name string
+	fi   os.FileInfo
+}
+
+// installedBlobs returns the blobs of all the installed snap revisions.
+func installedBlobs(st *state.State) ([]installedBlob, error) {
+	st.Lock()
+	all, err := snapstate.All(st)
+	st.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var blobs []installedBlob
+	for name, snapst := range all {
+		for _, si := range snapst.Sequence.Revisions {
+			pi := snap.MinimalPlaceInfo(name, si.Snap.Revision)
+			fi, err := os.Stat(pi.MountFile())
+			if err != nil {
+				// nothing to match against
+				continue
+			}
+			blobs = append(blobs, installedBlob{
+				name: name + "_" + si.Snap.Revision.String(),
+				fi:   fi,
+			})
+		}
+	}
+	return blobs, nil
+}
+
+// blobsUsing returns the names of the installed revisions whose blob is
+// the file at path.
+func blobsUsing(path string, blobs []installedBlob) []string {
+	if path == "" {
+		return nil
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, b := range blobs {
+		if os.SameFile(fi, b.fi) {
+			names = append(names, b.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
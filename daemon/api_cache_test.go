@@ -0,0 +1,169 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
+)
+
+var _ = check.Suite(&apiCacheSuite{})
+
+type apiCacheSuite struct {
+	apiBaseSuite
+}
+
+func (s *apiCacheSuite) SetUpTest(c *check.C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectReadAccess(daemon.OpenAccess{})
+	s.expectWriteAccess(daemon.RootAccess{})
+
+	c.Assert(os.MkdirAll(dirs.SnapDownloadCacheDir, 0700), check.IsNil)
+}
+
+func (s *apiCacheSuite) mockCacheEntry(c *check.C, key string, content []byte, age time.Duration) {
+	p := filepath.Join(dirs.SnapDownloadCacheDir, key)
+	c.Assert(os.WriteFile(p, content, 0600), check.IsNil)
+	mtime := time.Now().Add(-age)
+	c.Assert(os.Chtimes(p, mtime, mtime), check.IsNil)
+}
+
+func (s *apiCacheSuite) mockInstalledCacheEntry(c *check.C, key string, info *snap.Info, age time.Duration) {
+	p := filepath.Join(dirs.SnapDownloadCacheDir, key)
+	c.Assert(os.Link(info.MountFile(), p), check.IsNil)
+	mtime := time.Now().Add(-age)
+	c.Assert(os.Chtimes(p, mtime, mtime), check.IsNil)
+}
+
+func (s *apiCacheSuite) TestListCache(c *check.C) {
+	d := s.daemon(c)
+	foo := s.mkInstalledInState(c, d, "foo", "", "v1", snap.R(1), true, "")
+
+	s.mockCacheEntry(c, "old-digest", []byte("old content"), 48*time.Hour)
+	s.mockInstalledCacheEntry(c, "foo-digest", foo, time.Hour)
+
+	req, err := http.NewRequest("GET", "/v2/cache", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	entries := rsp.Result.([]client.CacheEntry)
+	c.Assert(entries, check.HasLen, 2)
+
+	c.Check(entries[0].Digest, check.Equals, "old-digest")
+	c.Check(entries[0].Size, check.Equals, int64(len("old content")))
+	c.Check(entries[0].Referenced, check.Equals, false)
+	c.Check(entries[0].Snaps, check.HasLen, 0)
+
+	c.Check(entries[1].Digest, check.Equals, "foo-digest")
+	c.Check(entries[1].Referenced, check.Equals, true)
+	c.Check(entries[1].Snaps, check.DeepEquals, []string{"foo_1"})
+	c.Check(entries[0].LastUsed.Before(entries[1].LastUsed), check.Equals, true)
+}
+
+func (s *apiCacheSuite) TestListCacheEmpty(c *check.C) {
+	s.daemon(c)
+	c.Assert(os.RemoveAll(dirs.SnapDownloadCacheDir), check.IsNil)
+
+	req, err := http.NewRequest("GET", "/v2/cache", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.HasLen, 0)
+}
+
+func (s *apiCacheSuite) TestCleanCache(c *check.C) {
+	d := s.daemon(c)
+	foo := s.mkInstalledInState(c, d, "foo", "", "v1", snap.R(1), true, "")
+
+	s.mockCacheEntry(c, "old-digest", []byte("old content"), 48*time.Hour)
+	s.mockCacheEntry(c, "new-digest", []byte("new"), time.Hour)
+	s.mockInstalledCacheEntry(c, "foo-digest", foo, 72*time.Hour)
+
+	req, err := http.NewRequest("POST", "/v2/cache", bytes.NewBufferString(`{"action": "clean", "older-than": "24h"}`))
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	res := rsp.Result.(*client.CacheCleanResult)
+	c.Assert(res.Removed, check.HasLen, 1)
+	c.Check(res.Removed[0].Digest, check.Equals, "old-digest")
+	c.Check(res.Reclaimed, check.Equals, int64(len("old content")))
+
+	c.Check(filepath.Join(dirs.SnapDownloadCacheDir, "old-digest"), testutil.FileAbsent)
+	c.Check(filepath.Join(dirs.SnapDownloadCacheDir, "new-digest"), testutil.FilePresent)
+	// still used by the installed revision
+	c.Check(filepath.Join(dirs.SnapDownloadCacheDir, "foo-digest"), testutil.FilePresent)
+
+	req, err = http.NewRequest("POST", "/v2/cache", bytes.NewBufferString(`{"action": "clean"}`))
+	c.Assert(err, check.IsNil)
+	rsp = s.syncReq(c, req, nil)
+	res = rsp.Result.(*client.CacheCleanResult)
+	c.Assert(res.Removed, check.HasLen, 1)
+	c.Check(res.Removed[0].Digest, check.Equals, "new-digest")
+	c.Check(filepath.Join(dirs.SnapDownloadCacheDir, "foo-digest"), testutil.FilePresent)
+}
+
+func (s *apiCacheSuite) TestCleanCacheMaxSize(c *check.C) {
+	s.daemon(c)
+
+	s.mockCacheEntry(c, "a-digest", bytes.Repeat([]byte("a"), 30), 3*time.Hour)
+	s.mockCacheEntry(c, "b-digest", bytes.Repeat([]byte("b"), 20), 2*time.Hour)
+	s.mockCacheEntry(c, "c-digest", bytes.Repeat([]byte("c"), 10), time.Hour)
+
+	req, err := http.NewRequest("POST", "/v2/cache", bytes.NewBufferString(`{"action": "clean", "max-size": 25}`))
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	res := rsp.Result.(*client.CacheCleanResult)
+	c.Assert(res.Removed, check.HasLen, 2)
+	c.Check(res.Removed[0].Digest, check.Equals, "a-digest")
+	c.Check(res.Removed[1].Digest, check.Equals, "b-digest")
+	c.Check(res.Reclaimed, check.Equals, int64(50))
+	c.Check(filepath.Join(dirs.SnapDownloadCacheDir, "c-digest"), testutil.FilePresent)
+}
+
+func (s *apiCacheSuite) TestCleanCacheErrors(c *check.C) {
+	s.daemon(c)
+
+	for _, t := range []struct {
+		body string
+		msg  string
+	}{
+		{`{"action": "frobnicate"}`, `unknown cache action "frobnicate"`},
+		{`{"action": "clean", "older-than": "soon"}`, `invalid older-than duration "soon": .*`},
+		{`{"action": "clean", "older-than": "-1h"}`, `older-than duration must be positive`},
+		{`{"action": "clean", "max-size": -1}`, `max-size cannot be negative`},
+		{`{"action": "clean"}{}`, `spurious content after cache action`},
+		{`not json`, `cannot decode request body into cache action: .*`},
+	} {
+		req, err := http.NewRequest("POST", "/v2/cache", bytes.NewBufferString(t.body))
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, 400, check.Commentf(t.body))
+		c.Check(rspe.Message, check.Matches, t.msg, check.Commentf(t.body))
+	}
+}
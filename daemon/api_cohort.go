@@ -22,10 +22,15 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
 	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/strutil"
 )
 
 var cohortsCmd = &Command{
@@ -44,10 +49,19 @@ func postCohorts(c *Command, r *http.Request, user *auth.UserState) Response {
 		return BadRequest("spurious content after cohort instruction")
 	}
 
-	if inst.Action != "create" {
+	switch inst.Action {
+	case "create":
+		// handled below
+	case "join", "leave":
+		return changeCohorts(c, &inst)
+	default:
 		return BadRequest("unknown cohort action %q", inst.Action)
 	}
 
+	if inst.CohortKey != "" {
+		return BadRequest("cohort-key can only be specified for join")
+	}
+
 	if len(inst.Snaps) == 0 {
 		// nothing to do ¯\_(ツ)_/¯
 		return SyncResponse(map[string]string{})
@@ -59,3 +73,70 @@ func postCohorts(c *Command, r *http.Request, user *auth.UserState) Response {
 	}
 	return SyncResponse(cohorts)
 }
+
+// changeCohorts makes the snaps join or leave cohorts. The cohort membership
+// is recorded in the state of the snaps and used by their next refreshes.
+func changeCohorts(c *Command, inst *client.CohortAction) Response {
+	if len(inst.Snaps) == 0 {
+		return BadRequest("cannot %s cohort: no snaps given", inst.Action)
+	}
+	if inst.CohortKey != "" {
+		if inst.Action != "join" {
+			return BadRequest("cohort-key can only be specified for join")
+		}
+		if len(inst.Snaps) != 1 {
+			return BadRequest("cohort-key can only be specified for a single snap")
+		}
+	}
+
+	cohorts := make(map[string]string, len(inst.Snaps))
+	if inst.Action == "join" {
+		if inst.CohortKey != "" {
+			cohorts[inst.Snaps[0]] = inst.CohortKey
+		} else {
+			var err error
+			cohorts, err = storeFrom(c.d).CreateCohorts(context.TODO(), inst.Snaps)
+			if err != nil {
+				return InternalError(err.Error())
+			}
+		}
+	}
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	tss := make([]*state.TaskSet, 0, len(inst.Snaps))
+	for _, name := range inst.Snaps {
+		opts := &snapstate.RevisionOptions{LeaveCohort: inst.Action == "leave"}
+		if inst.Action == "join" {
+			opts.CohortKey = cohorts[name]
+			if opts.CohortKey == "" {
+				return InternalError("cannot join cohort: no cohort created for snap %q", name)
+			}
+		}
+
+		ts, err := snapstateSwitch(st, name, opts)
+		if err != nil {
+			return errToResponse(err, []string{name}, BadRequest, "cannot "+inst.Action+" cohort: %v")
+		}
+		tss = append(tss, ts)
+	}
+
+	var msg string
+	switch {
+	case inst.Action == "join" && len(inst.Snaps) == 1:
+		msg = fmt.Sprintf(i18n.G("Switch %q snap to cohort %q"), inst.Snaps[0], strutil.ElliptLeft(cohorts[inst.Snaps[0]], 10))
+	case inst.Action == "join":
+		msg = fmt.Sprintf(i18n.G("Switch snaps %s to new cohorts"), strutil.Quoted(inst.Snaps))
+	case len(inst.Snaps) == 1:
+		msg = fmt.Sprintf(i18n.G("Switch %q snap away from cohort"), inst.Snaps[0])
+	default:
+		msg = fmt.Sprintf(i18n.G("Switch snaps %s away from their cohorts"), strutil.Quoted(inst.Snaps))
+	}
+
+	chg := newChange(st, inst.Action+"-cohort", msg, tss, inst.Snaps)
+	ensureStateSoon(st)
+
+	return AsyncResponse(nil, chg.ID())
+}
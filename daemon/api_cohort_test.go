@@ -26,6 +26,12 @@ import (
 	"strings"
 
 	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
 )
 
 var _ = check.Suite(&cohortSuite{})
@@ -51,6 +57,9 @@ func (s *cohortSuite) SetUpTest(c *check.C) {
 	s.coh = nil
 
 	s.daemonWithStore(c, s)
+
+	_, restore := daemon.MockEnsureStateSoon(func(*state.State) {})
+	s.AddCleanup(restore)
 }
 
 func (s *cohortSuite) TestCreateCohort(c *check.C) {
@@ -113,3 +122,116 @@ func (s *cohortSuite) TestCreateBadBody2(c *check.C) {
 	c.Check(rspe.Status, check.Equals, 400)
 	c.Check(rspe.Message, check.Equals, `spurious content after cohort instruction`)
 }
+
+func (s *cohortSuite) mockSnapstateSwitch(c *check.C) map[string]*snapstate.RevisionOptions {
+	switched := make(map[string]*snapstate.RevisionOptions)
+	s.AddCleanup(daemon.MockSnapstateSwitch(func(st *state.State, name string, opts *snapstate.RevisionOptions) (*state.TaskSet, error) {
+		switched[name] = opts
+		return state.NewTaskSet(st.NewTask("fake-switch", name)), nil
+	}))
+	return switched
+}
+
+func (s *cohortSuite) TestJoinCohortCreatesCohorts(c *check.C) {
+	switched := s.mockSnapstateSwitch(c)
+	s.coh = map[string]string{
+		"foo": "cohort for foo",
+		"bar": "cohort for bar",
+	}
+
+	req, err := http.NewRequest("POST", "/v2/cohorts", strings.NewReader(`{"action": "join", "snaps": ["foo","bar"]}`))
+	c.Assert(err, check.IsNil)
+
+	rsp := s.asyncReq(c, req, nil)
+	c.Check(s.snaps, check.DeepEquals, []string{"foo", "bar"})
+	c.Check(switched, check.DeepEquals, map[string]*snapstate.RevisionOptions{
+		"foo": {CohortKey: "cohort for foo"},
+		"bar": {CohortKey: "cohort for bar"},
+	})
+
+	st := s.d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	c.Assert(chg, check.NotNil)
+	c.Check(chg.Kind(), check.Equals, "join-cohort")
+	c.Check(chg.Summary(), check.Equals, `Switch snaps "foo", "bar" to new cohorts`)
+	c.Check(chg.Tasks(), check.HasLen, 2)
+}
+
+func (s *cohortSuite) TestJoinCohortWithKey(c *check.C) {
+	switched := s.mockSnapstateSwitch(c)
+
+	req, err := http.NewRequest("POST", "/v2/cohorts", strings.NewReader(`{"action": "join", "snaps": ["foo"], "cohort-key": "some-cohort-key"}`))
+	c.Assert(err, check.IsNil)
+
+	rsp := s.asyncReq(c, req, nil)
+	// no cohort was created
+	c.Check(s.snaps, check.IsNil)
+	c.Check(switched, check.DeepEquals, map[string]*snapstate.RevisionOptions{
+		"foo": {CohortKey: "some-cohort-key"},
+	})
+
+	st := s.d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	c.Assert(chg, check.NotNil)
+	c.Check(chg.Summary(), check.Equals, `Switch "foo" snap to cohort "…ohort-key"`)
+}
+
+func (s *cohortSuite) TestLeaveCohort(c *check.C) {
+	switched := s.mockSnapstateSwitch(c)
+
+	req, err := http.NewRequest("POST", "/v2/cohorts", strings.NewReader(`{"action": "leave", "snaps": ["foo"]}`))
+	c.Assert(err, check.IsNil)
+
+	rsp := s.asyncReq(c, req, nil)
+	c.Check(switched, check.DeepEquals, map[string]*snapstate.RevisionOptions{
+		"foo": {LeaveCohort: true},
+	})
+
+	st := s.d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	c.Assert(chg, check.NotNil)
+	c.Check(chg.Kind(), check.Equals, "leave-cohort")
+	c.Check(chg.Summary(), check.Equals, `Switch "foo" snap away from cohort`)
+}
+
+func (s *cohortSuite) TestChangeCohortErrors(c *check.C) {
+	s.mockSnapstateSwitch(c)
+
+	for _, tc := range []struct {
+		body   string
+		status int
+		errMsg string
+	}{
+		{`{"action": "join"}`, 400, `cannot join cohort: no snaps given`},
+		{`{"action": "leave", "snaps": ["foo"], "cohort-key": "key"}`, 400, `cohort-key can only be specified for join`},
+		{`{"action": "create", "snaps": ["foo"], "cohort-key": "key"}`, 400, `cohort-key can only be specified for join`},
+		{`{"action": "join", "snaps": ["foo", "bar"], "cohort-key": "key"}`, 400, `cohort-key can only be specified for a single snap`},
+	} {
+		req, err := http.NewRequest("POST", "/v2/cohorts", strings.NewReader(tc.body))
+		c.Assert(err, check.IsNil)
+
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, tc.status, check.Commentf(tc.body))
+		c.Check(rspe.Message, check.Equals, tc.errMsg, check.Commentf(tc.body))
+	}
+}
+
+func (s *cohortSuite) TestJoinCohortSwitchError(c *check.C) {
+	s.AddCleanup(daemon.MockSnapstateSwitch(func(st *state.State, name string, opts *snapstate.RevisionOptions) (*state.TaskSet, error) {
+		return nil, &snap.NotInstalledError{Snap: name}
+	}))
+
+	req, err := http.NewRequest("POST", "/v2/cohorts", strings.NewReader(`{"action": "join", "snaps": ["foo"], "cohort-key": "key"}`))
+	c.Assert(err, check.IsNil)
+
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Kind, check.Equals, client.ErrorKindSnapNotInstalled)
+	c.Check(rspe.Message, check.Equals, `snap "foo" is not installed`)
+}
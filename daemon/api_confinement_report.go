@@ -0,0 +1,130 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/policy"
+	"github.com/snapcore/snapd/overlord/assertstate"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+)
+
+var confinementReportCmd = &Command{
+	Path:       "/v2/confinement-report",
+	GET:        getConfinementReport,
+	ReadAccess: openAccess{},
+}
+
+func getConfinementReport(c *Command, r *http.Request, user *auth.UserState) Response {
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	snapStates, err := snapstate.All(st)
+	if err != nil {
+		return InternalError("cannot list installed snaps: %v", err)
+	}
+	connStates, err := ifacestate.ConnectionStates(st)
+	if err != nil {
+		return InternalError("cannot list connections: %v", err)
+	}
+	baseDecl, err := assertstate.BaseDeclaration(st)
+	if err != nil {
+		return InternalError("cannot find base declaration: %v", err)
+	}
+
+	superPrivileged := make(map[string][]client.SuperPrivilegedConnection)
+	for crefStr, cstate := range connStates {
+		if cstate.Auto || cstate.Undesired || cstate.HotplugGone {
+			continue
+		}
+		if !policy.IsSuperPrivileged(baseDecl, cstate.Interface) {
+			continue
+		}
+		cref, err := interfaces.ParseConnRef(crefStr)
+		if err != nil {
+			return InternalError("%v", err)
+		}
+		plugSnap := cref.PlugRef.Snap
+		superPrivileged[plugSnap] = append(superPrivileged[plugSnap], client.SuperPrivilegedConnection{
+			Interface: cstate.Interface,
+			Plug:      client.PlugRef{Snap: plugSnap, Name: cref.PlugRef.Name},
+			Slot:      client.SlotRef{Snap: cref.SlotRef.Snap, Name: cref.SlotRef.Name},
+		})
+	}
+
+	report := client.ConfinementReport{
+		Snaps: make([]client.SnapConfinement, 0, len(snapStates)),
+	}
+	for name, snapst := range snapStates {
+		info, err := snapst.CurrentInfo()
+		if err != nil {
+			return InternalError("cannot read snap info of %q: %v", name, err)
+		}
+
+		var flags []string
+		if snapst.DevMode {
+			flags = append(flags, "devmode")
+		}
+		if snapst.JailMode {
+			flags = append(flags, "jailmode")
+		}
+		if snapst.Classic {
+			flags = append(flags, "classic")
+		}
+		if snapst.TryMode {
+			flags = append(flags, "trymode")
+		}
+		if snapst.IgnoreValidation {
+			flags = append(flags, "ignore-validation")
+		}
+
+		conns := superPrivileged[name]
+		sort.Slice(conns, func(i, j int) bool {
+			if conns[i].Plug.Name != conns[j].Plug.Name {
+				return conns[i].Plug.Name < conns[j].Plug.Name
+			}
+			if conns[i].Slot.Snap != conns[j].Slot.Snap {
+				return conns[i].Slot.Snap < conns[j].Slot.Snap
+			}
+			return conns[i].Slot.Name < conns[j].Slot.Name
+		})
+
+		report.Snaps = append(report.Snaps, client.SnapConfinement{
+			Name:                       name,
+			Revision:                   snapst.Current,
+			Confinement:                info.Confinement,
+			Flags:                      flags,
+			Unasserted:                 snapst.CurrentSideInfo().SnapID == "",
+			SuperPrivilegedConnections: conns,
+		})
+	}
+	sort.Slice(report.Snaps, func(i, j int) bool {
+		return report.Snaps[i].Name < report.Snaps[j].Name
+	})
+
+	return SyncResponse(&report)
+}
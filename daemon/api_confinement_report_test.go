@@ -0,0 +1,113 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/snap"
+)
+
+var _ = Suite(&confinementReportSuite{})
+
+type confinementReportSuite struct {
+	apiBaseSuite
+}
+
+func (s *confinementReportSuite) TestConfinementReport(c *C) {
+	d := s.daemon(c)
+
+	s.mkInstalledInState(c, d, "foo", "bar", "v1", snap.R(10), true, "confinement: strict")
+	s.mkInstalledInState(c, d, "baz", "", "v2", snap.R(-1), true, "confinement: devmode")
+	s.mkInstalledInState(c, d, "qux", "bar", "v3", snap.R(3), true, "confinement: classic")
+
+	st := d.Overlord().State()
+	st.Lock()
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(st, "baz", &snapst), IsNil)
+	snapst.DevMode = true
+	snapst.IgnoreValidation = true
+	snapstate.Set(st, "baz", &snapst)
+	c.Assert(snapstate.Get(st, "qux", &snapst), IsNil)
+	snapst.Classic = true
+	snapstate.Set(st, "qux", &snapst)
+
+	st.Set("conns", map[string]interface{}{
+		// manual and super-privileged
+		"foo:snapd-control core:snapd-control": map[string]interface{}{
+			"interface": "snapd-control",
+		},
+		"foo:docker-support core:docker-support": map[string]interface{}{
+			"interface": "docker-support",
+		},
+		// auto-connected
+		"baz:kernel-module-control core:kernel-module-control": map[string]interface{}{
+			"interface": "kernel-module-control",
+			"auto":      true,
+		},
+		// explicitly disconnected
+		"baz:snapd-control core:snapd-control": map[string]interface{}{
+			"interface": "snapd-control",
+			"auto":      true,
+			"undesired": true,
+		},
+		// not super-privileged
+		"qux:network core:network": map[string]interface{}{
+			"interface": "network",
+		},
+	})
+	st.Unlock()
+
+	req, err := http.NewRequest("GET", "/v2/confinement-report", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Assert(rsp.Status, Equals, 200)
+	c.Check(rsp.Result, DeepEquals, &client.ConfinementReport{
+		Snaps: []client.SnapConfinement{{
+			Name:        "baz",
+			Revision:    snap.R(-1),
+			Confinement: snap.DevModeConfinement,
+			Flags:       []string{"devmode", "ignore-validation"},
+			Unasserted:  true,
+		}, {
+			Name:        "foo",
+			Revision:    snap.R(10),
+			Confinement: snap.StrictConfinement,
+			SuperPrivilegedConnections: []client.SuperPrivilegedConnection{{
+				Interface: "docker-support",
+				Plug:      client.PlugRef{Snap: "foo", Name: "docker-support"},
+				Slot:      client.SlotRef{Snap: "core", Name: "docker-support"},
+			}, {
+				Interface: "snapd-control",
+				Plug:      client.PlugRef{Snap: "foo", Name: "snapd-control"},
+				Slot:      client.SlotRef{Snap: "core", Name: "snapd-control"},
+			}},
+		}, {
+			Name:        "qux",
+			Revision:    snap.R(3),
+			Confinement: snap.ClassicConfinement,
+			Flags:       []string{"classic"},
+		}},
+	})
+}
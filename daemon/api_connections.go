@@ -166,6 +166,16 @@ func collectConnections(ifaceMgr *ifacestate.InterfaceManager, filter collectFil
 			Interface: cstate.Interface,
 			PlugAttrs: mergeAttrs(cstate.StaticPlugAttrs, cstate.DynamicPlugAttrs),
 			SlotAttrs: mergeAttrs(cstate.StaticSlotAttrs, cstate.DynamicSlotAttrs),
+
+			AutoReason: cstate.AutoReason,
+		}
+		if !cstate.PlugLastUsed.IsZero() {
+			plugLastUsed := cstate.PlugLastUsed
+			cj.PlugLastUsed = &plugLastUsed
+		}
+		if !cstate.SlotLastUsed.IsZero() {
+			slotLastUsed := cstate.SlotLastUsed
+			cj.SlotLastUsed = &slotLastUsed
 		}
 		if cstate.Undesired {
 			// explicitly disconnected are always manual
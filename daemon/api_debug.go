@@ -52,6 +52,8 @@ type debugAction struct {
 		ChgID string `json:"chg-id"`
 
 		RecoverySystemLabel string `json:"recovery-system-label"`
+
+		DataLocation string `json:"data-location"`
 	} `json:"params"`
 	Snaps []string `json:"snaps"`
 }
@@ -270,6 +272,14 @@ func getChangeTimings(st *state.State, changeID, ensureTag, startupTag string, a
 	return SyncResponse(responseData)
 }
 
+func getAggregatedTimings(st *state.State, groupBy string) Response {
+	stateTimings, err := timings.Get(st, -1, func(tags map[string]string) bool { return true })
+	if err != nil {
+		return InternalError("cannot get timings: %v", err)
+	}
+	return SyncResponse(timings.AggregateTimings(stateTimings, groupBy))
+}
+
 func getGadgetDiskMapping(st *state.State) Response {
 	deviceCtx, err := devicestate.DeviceCtx(st, nil, nil)
 	if err != nil {
@@ -324,6 +334,17 @@ func getDisks(st *state.State) Response {
 	return SyncResponse(vols)
 }
 
+func getDBusNames(st *state.State) Response {
+	activations, err := snapstate.DBusActivations(st)
+	if err != nil {
+		return InternalError("cannot get D-Bus names: %v", err)
+	}
+	if activations == nil {
+		activations = []*snapstate.DBusActivation{}
+	}
+	return SyncResponse(activations)
+}
+
 func createRecovery(st *state.State, label string) Response {
 	if label == "" {
 		return BadRequest("cannot create a recovery system with no label")
@@ -339,6 +360,28 @@ func createRecovery(st *state.State, label string) Response {
 func getDebug(c *Command, r *http.Request, user *auth.UserState) Response {
 	query := r.URL.Query()
 	aspect := query.Get("aspect")
+	if aspect == "denials" {
+		// reading the logs doesn't need the state
+		return getDenials(query.Get("snap"))
+	}
+	if aspect == "mount-ns" {
+		// inspecting the namespace locks the state only as needed
+		return getMountNs(c.d.overlord, query.Get("snap"))
+	}
+	if aspect == "udev" {
+		// reading the rules and the udev database locks the state only
+		// as needed
+		return getUdev(c.d.overlord, query.Get("snap"))
+	}
+	if aspect == "syscall-profiling" {
+		// reading the logs locks the state only as needed
+		return getSyscallProfiling(c.d.overlord, query.Get("snap"))
+	}
+	if aspect == "state" {
+		// querying checks the user before locking the state
+		return getStateQuery(c.d.overlord.State(), r, query.Get("query"))
+	}
+
 	st := c.d.overlord.State()
 	st.Lock()
 	defer st.Unlock()
@@ -362,12 +405,20 @@ func getDebug(c *Command, r *http.Request, user *auth.UserState) Response {
 		startupTag := query.Get("startup")
 		all := query.Get("all")
 		return getChangeTimings(st, chgID, ensureTag, startupTag, all == "true")
+	case "aggregated-timings":
+		return getAggregatedTimings(st, query.Get("group-by"))
 	case "seeding":
 		return getSeedingInfo(st)
 	case "gadget-disk-mapping":
 		return getGadgetDiskMapping(st)
 	case "disks":
 		return getDisks(st)
+	case "cloud-init":
+		return getCloudInitInfo()
+	case "dbus-names":
+		return getDBusNames(st)
+	case "stuck-changes":
+		return getStuckChanges(st, query.Get("threshold"))
 	default:
 		return BadRequest("unknown debug aspect %q", aspect)
 	}
@@ -409,6 +460,12 @@ func postDebug(c *Command, r *http.Request, user *auth.UserState) Response {
 		return createRecovery(st, a.Params.RecoverySystemLabel)
 	case "migrate-home":
 		return migrateHome(st, a.Snaps)
+	case "start-syscall-profiling":
+		return syscallProfiling(st, a.Snaps, true)
+	case "stop-syscall-profiling":
+		return syscallProfiling(st, a.Snaps, false)
+	case "relocate-data":
+		return relocateData(st, a.Snaps, a.Params.DataLocation)
 	default:
 		return BadRequest("unknown debug action: %v", a.Action)
 	}
@@ -0,0 +1,58 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"github.com/snapcore/snapd/sysconfig"
+)
+
+var (
+	sysconfigCloudInitStatus           = sysconfig.CloudInitStatus
+	sysconfigReadCloudInitFilterReport = sysconfig.ReadCloudInitFilterReport
+)
+
+type cloudInitInfo struct {
+	// Status is the current status of cloud-init.
+	Status string `json:"status"`
+
+	// StatusError is set if the status of cloud-init could not be
+	// determined cleanly.
+	StatusError string `json:"status-error,omitempty"`
+
+	// Filtered describes how the cloud-init config from ubuntu-seed was
+	// filtered during install, if it was.
+	Filtered *sysconfig.CloudInitFilterReport `json:"filtered,omitempty"`
+}
+
+func getCloudInitInfo() Response {
+	report, err := sysconfigReadCloudInitFilterReport()
+	if err != nil {
+		return InternalError("cannot read cloud-init filter report: %v", err)
+	}
+
+	data := &cloudInitInfo{Filtered: report}
+	status, err := sysconfigCloudInitStatus()
+	data.Status = status.String()
+	if err != nil {
+		data.StatusError = err.Error()
+	}
+
+	return SyncResponse(data)
+}
@@ -0,0 +1,83 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/sysconfig"
+)
+
+var _ = Suite(&cloudInitDebugSuite{})
+
+type cloudInitDebugSuite struct {
+	apiBaseSuite
+}
+
+func (s *cloudInitDebugSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+	s.daemonWithOverlordMock()
+}
+
+func (s *cloudInitDebugSuite) getCloudInitDebug(c *C) interface{} {
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=cloud-init", nil)
+	c.Assert(err, IsNil)
+
+	rsp := s.syncReq(c, req, nil)
+	c.Assert(rsp.Type, Equals, daemon.ResponseTypeSync)
+	return rsp.Result
+}
+
+func (s *cloudInitDebugSuite) TestNoReport(c *C) {
+	s.AddCleanup(daemon.MockSysconfigCloudInitStatus(func() (sysconfig.CloudInitState, error) {
+		return sysconfig.CloudInitDisabledPermanently, nil
+	}))
+
+	data := s.getCloudInitDebug(c)
+	c.Check(data, DeepEquals, &daemon.CloudInitInfo{Status: "disabled"})
+}
+
+func (s *cloudInitDebugSuite) TestReport(c *C) {
+	s.AddCleanup(daemon.MockSysconfigCloudInitStatus(func() (sysconfig.CloudInitState, error) {
+		return sysconfig.CloudInitErrored, errors.New("boom")
+	}))
+
+	reportFile := dirs.CloudInitFilterReportFileUnder(dirs.GlobalRootDir)
+	c.Assert(os.MkdirAll(dirs.SnapdStateDir(dirs.GlobalRootDir), 0755), IsNil)
+	c.Assert(os.WriteFile(reportFile, []byte(`{"allowed-datasources":["MAAS"],"files":[{"name":"foo.cfg","dropped-keys":["runcmd"]}]}`), 0644), IsNil)
+
+	data := s.getCloudInitDebug(c)
+	c.Check(data, DeepEquals, &daemon.CloudInitInfo{
+		Status:      "error",
+		StatusError: "boom",
+		Filtered: &sysconfig.CloudInitFilterReport{
+			AllowedDatasources: []string{"MAAS"},
+			Files: []sysconfig.CloudInitFilteredFile{
+				{Name: "foo.cfg", DroppedKeys: []string{"runcmd"}},
+			},
+		},
+	})
+}
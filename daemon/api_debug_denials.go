@@ -0,0 +1,56 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"time"
+
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/snap"
+)
+
+var denialsRead = denials.Read
+
+// getDenials returns the aggregated confinement denials of the snap logged
+// during the current boot, or of all snaps if no snap is given.
+func getDenials(snapName string) Response {
+	if snapName != "" {
+		if err := snap.ValidateInstanceName(snapName); err != nil {
+			return BadRequest("invalid snap name: %v", err)
+		}
+	}
+
+	all, err := denialsRead(time.Time{})
+	if err != nil {
+		return InternalError("cannot read denials: %v", err)
+	}
+
+	snapDenials := make([]*denials.Denial, 0, len(all))
+	for _, d := range all {
+		if snapName == "" || d.Snap == snapName {
+			snapDenials = append(snapDenials, d)
+		}
+	}
+	summaries := denials.Aggregate(snapDenials)
+	if summaries == nil {
+		summaries = []*denials.Summary{}
+	}
+	return SyncResponse(summaries)
+}
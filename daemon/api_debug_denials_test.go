@@ -0,0 +1,110 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/interfaces/denials"
+)
+
+var _ = Suite(&denialsDebugSuite{})
+
+type denialsDebugSuite struct {
+	apiBaseSuite
+}
+
+func (s *denialsDebugSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+	s.daemonWithOverlordMock()
+}
+
+func (s *denialsDebugSuite) mockDenials(c *C) {
+	camera := &denials.Denial{Kind: "apparmor", Snap: "foo", Profile: "snap.foo.app", Operation: "open", Target: "/dev/video0", Mask: "r"}
+	mount := &denials.Denial{Kind: "seccomp", Snap: "foo", Profile: "snap.foo.app", Operation: "syscall", Target: "mount"}
+	other := &denials.Denial{Kind: "apparmor", Snap: "bar", Profile: "snap.bar.app", Operation: "capable", Target: "net_admin"}
+	s.AddCleanup(daemon.MockDenialsRead(func(since time.Time) ([]*denials.Denial, error) {
+		c.Check(since.IsZero(), Equals, true)
+		return []*denials.Denial{camera, other, mount, camera}, nil
+	}))
+}
+
+func (s *denialsDebugSuite) TestGetDenialsOfSnap(c *C) {
+	s.mockDenials(c)
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=denials&snap=foo", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, DeepEquals, []*denials.Summary{
+		{
+			Denial:      denials.Denial{Kind: "apparmor", Snap: "foo", Profile: "snap.foo.app", Operation: "open", Target: "/dev/video0", Mask: "r"},
+			Count:       2,
+			Suggestions: []string{"camera"},
+		},
+		{
+			Denial:      denials.Denial{Kind: "seccomp", Snap: "foo", Profile: "snap.foo.app", Operation: "syscall", Target: "mount"},
+			Count:       1,
+			Suggestions: []string{"mount-control"},
+		},
+	})
+}
+
+func (s *denialsDebugSuite) TestGetDenialsAllSnaps(c *C) {
+	s.mockDenials(c)
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=denials", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	summaries := rsp.Result.([]*denials.Summary)
+	c.Assert(summaries, HasLen, 3)
+	c.Check(summaries[2].Snap, Equals, "foo")
+	c.Check(summaries[1].Snap, Equals, "bar")
+}
+
+func (s *denialsDebugSuite) TestGetDenialsNone(c *C) {
+	s.mockDenials(c)
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=denials&snap=baz", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, DeepEquals, []*denials.Summary{})
+}
+
+func (s *denialsDebugSuite) TestGetDenialsErrors(c *C) {
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=denials&snap=-invalid-", nil)
+	c.Assert(err, IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 400)
+	c.Check(rspe.Message, Matches, "invalid snap name: .*")
+
+	s.AddCleanup(daemon.MockDenialsRead(func(since time.Time) ([]*denials.Denial, error) {
+		return nil, errors.New("boom")
+	}))
+	req, err = http.NewRequest("GET", "/v2/debug?aspect=denials&snap=foo", nil)
+	c.Assert(err, IsNil)
+	rspe = s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 500)
+	c.Check(rspe.Message, Equals, "cannot read denials: boom")
+}
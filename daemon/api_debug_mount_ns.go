@@ -0,0 +1,194 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package daemon
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/mount"
+	"github.com/snapcore/snapd/overlord"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/snap"
+)
+
+var namespaceMountInfo = mount.NamespaceMountInfo
+
+type mountNsEntry struct {
+	MountID     int               `json:"mount-id"`
+	ParentID    int               `json:"parent-id"`
+	Root        string            `json:"root"`
+	MountDir    string            `json:"mount-dir"`
+	FsType      string            `json:"fs-type"`
+	MountSource string            `json:"mount-source"`
+	Options     map[string]string `json:"options,omitempty"`
+}
+
+type mountNsLayout struct {
+	Path     string `json:"path"`
+	Bind     string `json:"bind,omitempty"`
+	BindFile string `json:"bind-file,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Symlink  string `json:"symlink,omitempty"`
+	// Applied is true if the layout path is mounted in the namespace.
+	Applied bool `json:"applied"`
+}
+
+type mountNsContent struct {
+	Plug   string   `json:"plug"`
+	Slot   string   `json:"slot"`
+	Target string   `json:"target,omitempty"`
+	Read   []string `json:"read,omitempty"`
+	Write  []string `json:"write,omitempty"`
+	// Mounted is true if the content target is mounted in the namespace.
+	Mounted bool `json:"mounted"`
+}
+
+type mountNsInfo struct {
+	Snap      string           `json:"snap"`
+	Preserved bool             `json:"preserved"`
+	Mounts    []mountNsEntry   `json:"mounts"`
+	Layouts   []mountNsLayout  `json:"layouts"`
+	Content   []mountNsContent `json:"content"`
+}
+
+// getMountNs describes the preserved mount namespace of the snap, that is
+// the mount table inside of it, along with the layouts and content
+// connections of the snap and whether they are mounted there.
+func getMountNs(o *overlord.Overlord, snapName string) Response {
+	if err := snap.ValidateInstanceName(snapName); err != nil {
+		return BadRequest("invalid snap name: %v", err)
+	}
+
+	st := o.State()
+	st.Lock()
+	info, err := snapstate.CurrentInfo(st, snapName)
+	st.Unlock()
+	if err != nil {
+		return SnapNotFound(snapName, err)
+	}
+
+	// inspecting the namespace runs a helper, don't hold the state lock
+	entries, err := namespaceMountInfo(snapName)
+	if err != nil {
+		return InternalError("%v", err)
+	}
+
+	res := &mountNsInfo{
+		Snap:      snapName,
+		Preserved: entries != nil,
+		Mounts:    make([]mountNsEntry, 0, len(entries)),
+		Layouts:   []mountNsLayout{},
+		Content:   []mountNsContent{},
+	}
+	mountDirs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		res.Mounts = append(res.Mounts, mountNsEntry{
+			MountID:     e.MountID,
+			ParentID:    e.ParentID,
+			Root:        e.Root,
+			MountDir:    e.MountDir,
+			FsType:      e.FsType,
+			MountSource: e.MountSource,
+			Options:     e.MountOptions,
+		})
+		mountDirs = append(mountDirs, e.MountDir)
+	}
+
+	for _, l := range info.Layout {
+		res.Layouts = append(res.Layouts, mountNsLayout{
+			Path:     l.Path,
+			Bind:     l.Bind,
+			BindFile: l.BindFile,
+			Type:     l.Type,
+			Symlink:  l.Symlink,
+			Applied:  isMountedAt(mountDirs, l.Path),
+		})
+	}
+	sort.Slice(res.Layouts, func(i, j int) bool {
+		return res.Layouts[i].Path < res.Layouts[j].Path
+	})
+
+	repo := o.InterfaceManager().Repository()
+	connRefs, err := repo.Connections(snapName)
+	if err != nil {
+		return InternalError("cannot get connections of snap %q: %v", snapName, err)
+	}
+	for _, cref := range connRefs {
+		if cref.PlugRef.Snap != snapName {
+			continue
+		}
+		conn, err := repo.Connection(cref)
+		if err != nil {
+			return InternalError("cannot get connection %s: %v", cref.ID(), err)
+		}
+		if conn.Plug.Interface() != "content" {
+			continue
+		}
+		content := mountNsContent{
+			Plug: conn.Plug.Name(),
+			Slot: conn.Slot.Snap().InstanceName() + ":" + conn.Slot.Name(),
+		}
+		conn.Plug.Attr("target", &content.Target)
+		content.Read = stringsAttr(conn.Slot, "read")
+		content.Write = stringsAttr(conn.Slot, "write")
+		if content.Target != "" {
+			content.Mounted = isMountedAt(mountDirs, contentTargetPath(info, content.Target))
+		}
+		res.Content = append(res.Content, content)
+	}
+
+	return SyncResponse(res)
+}
+
+// isMountedAt returns whether something is mounted at the given path or
+// below it.
+func isMountedAt(mountDirs []string, path string) bool {
+	for _, dir := range mountDirs {
+		if dir == path || strings.HasPrefix(dir, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// contentTargetPath resolves the target of a content plug the same way the
+// content interface does when mounting it.
+func contentTargetPath(info *snap.Info, target string) string {
+	if !strings.HasPrefix(target, "$") {
+		target = filepath.Join("$SNAP", target)
+	}
+	return info.ExpandSnapVariables(target)
+}
+
+func stringsAttr(attrs interfaces.Attrer, name string) []string {
+	var values []interface{}
+	if err := attrs.Attr(name, &values); err != nil {
+		return nil
+	}
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
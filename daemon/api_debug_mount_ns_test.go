@@ -0,0 +1,162 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package daemon_test
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/osutil"
+)
+
+var _ = Suite(&mountNsDebugSuite{})
+
+type mountNsDebugSuite struct {
+	apiBaseSuite
+}
+
+const mountNsConsumerYaml = `
+name: consumer
+version: 1
+apps:
+ app:
+plugs:
+ themes:
+  interface: content
+  content: themes
+  target: $SNAP/data-dir/themes
+layout:
+ /usr/share/foo:
+  bind: $SNAP/usr/share/foo
+ /etc/bar:
+  bind-file: $SNAP/etc/bar
+`
+
+const mountNsProducerYaml = `
+name: producer
+version: 1
+slots:
+ themes:
+  interface: content
+  content: themes
+  read:
+   - $SNAP/share/themes
+`
+
+func (s *mountNsDebugSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+	s.daemon(c)
+}
+
+func (s *mountNsDebugSuite) mockSnaps(c *C) {
+	s.mockSnap(c, mountNsConsumerYaml)
+	s.mockSnap(c, mountNsProducerYaml)
+
+	repo := s.d.Overlord().InterfaceManager().Repository()
+	connRef := interfaces.NewConnRef(repo.Plug("consumer", "themes"), repo.Slot("producer", "themes"))
+	_, err := repo.Connect(connRef, nil, nil, nil, nil, nil)
+	c.Assert(err, IsNil)
+}
+
+func (s *mountNsDebugSuite) TestGetMountNs(c *C) {
+	s.mockSnaps(c)
+
+	snapDir := filepath.Join(dirs.CoreSnapMountDir, "consumer/1")
+	s.AddCleanup(daemon.MockNamespaceMountInfo(func(snapName string) ([]*osutil.MountInfoEntry, error) {
+		c.Check(snapName, Equals, "consumer")
+		return []*osutil.MountInfoEntry{
+			{MountID: 2, ParentID: 1, Root: "/", MountDir: "/", FsType: "squashfs", MountSource: "/dev/loop1", MountOptions: map[string]string{"ro": ""}},
+			{MountID: 3, ParentID: 2, Root: "/share/themes", MountDir: snapDir + "/data-dir/themes", FsType: "squashfs", MountSource: "/dev/loop2"},
+			{MountID: 4, ParentID: 2, Root: "/usr/share/foo", MountDir: "/usr/share/foo", FsType: "squashfs", MountSource: "/dev/loop1"},
+		}, nil
+	}))
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=mount-ns&snap=consumer", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, DeepEquals, &daemon.MountNsInfo{
+		Snap:      "consumer",
+		Preserved: true,
+		Mounts: []daemon.MountNsEntry{
+			{MountID: 2, ParentID: 1, Root: "/", MountDir: "/", FsType: "squashfs", MountSource: "/dev/loop1", Options: map[string]string{"ro": ""}},
+			{MountID: 3, ParentID: 2, Root: "/share/themes", MountDir: snapDir + "/data-dir/themes", FsType: "squashfs", MountSource: "/dev/loop2"},
+			{MountID: 4, ParentID: 2, Root: "/usr/share/foo", MountDir: "/usr/share/foo", FsType: "squashfs", MountSource: "/dev/loop1"},
+		},
+		Layouts: []daemon.MountNsLayout{
+			{Path: "/etc/bar", BindFile: "$SNAP/etc/bar", Applied: false},
+			{Path: "/usr/share/foo", Bind: "$SNAP/usr/share/foo", Applied: true},
+		},
+		Content: []daemon.MountNsContent{{
+			Plug:    "themes",
+			Slot:    "producer:themes",
+			Target:  "$SNAP/data-dir/themes",
+			Read:    []string{"$SNAP/share/themes"},
+			Mounted: true,
+		}},
+	})
+}
+
+func (s *mountNsDebugSuite) TestGetMountNsNotPreserved(c *C) {
+	s.mockSnaps(c)
+
+	s.AddCleanup(daemon.MockNamespaceMountInfo(func(snapName string) ([]*osutil.MountInfoEntry, error) {
+		return nil, nil
+	}))
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=mount-ns&snap=consumer", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	info := rsp.Result.(*daemon.MountNsInfo)
+	c.Check(info.Preserved, Equals, false)
+	c.Check(info.Mounts, HasLen, 0)
+	c.Assert(info.Layouts, HasLen, 2)
+	c.Check(info.Layouts[0].Applied, Equals, false)
+	c.Check(info.Layouts[1].Applied, Equals, false)
+	c.Assert(info.Content, HasLen, 1)
+	c.Check(info.Content[0].Mounted, Equals, false)
+}
+
+func (s *mountNsDebugSuite) TestGetMountNsErrors(c *C) {
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=mount-ns&snap=-invalid-", nil)
+	c.Assert(err, IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 400)
+	c.Check(rspe.Message, Matches, "invalid snap name: .*")
+
+	req, err = http.NewRequest("GET", "/v2/debug?aspect=mount-ns&snap=unknown", nil)
+	c.Assert(err, IsNil)
+	rspe = s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 404)
+
+	s.mockSnaps(c)
+	s.AddCleanup(daemon.MockNamespaceMountInfo(func(snapName string) ([]*osutil.MountInfoEntry, error) {
+		return nil, errors.New("boom")
+	}))
+	req, err = http.NewRequest("GET", "/v2/debug?aspect=mount-ns&snap=consumer", nil)
+	c.Assert(err, IsNil)
+	rspe = s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 500)
+	c.Check(rspe.Message, Equals, "boom")
+}
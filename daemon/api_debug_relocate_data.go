@@ -0,0 +1,54 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+var snapstateRelocateData = snapstate.RelocateData
+
+// relocateData moves the system data of the snap to the given location, or
+// back to /var/snap if it is empty.
+func relocateData(st *state.State, snaps []string, location string) Response {
+	if len(snaps) != 1 {
+		return BadRequest("data relocation needs exactly one snap")
+	}
+	snapName := snaps[0]
+
+	ts, err := snapstateRelocateData(st, snapName, location)
+	if err != nil {
+		return errToResponse(err, snaps, BadRequest, "%v")
+	}
+
+	summary := fmt.Sprintf("Move data of snap %q to %s", snapName, location)
+	if location == "" {
+		summary = fmt.Sprintf("Move data of snap %q back to /var/snap", snapName)
+	}
+	chg := st.NewChange("relocate-data", summary)
+	chg.AddAll(ts)
+	chg.Set("api-data", map[string][]string{"snap-names": snaps})
+
+	ensureStateSoon(st)
+	return AsyncResponse(nil, chg.ID())
+}
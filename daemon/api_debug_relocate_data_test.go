@@ -0,0 +1,103 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+var _ = Suite(&relocateDataDebugSuite{})
+
+type relocateDataDebugSuite struct {
+	apiBaseSuite
+}
+
+func (s *relocateDataDebugSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+	s.daemon(c)
+	s.expectWriteAccess(daemon.RootAccess{})
+
+	_, restore := daemon.MockEnsureStateSoon(func(*state.State) {})
+	s.AddCleanup(restore)
+}
+
+func (s *relocateDataDebugSuite) TestPostRelocateData(c *C) {
+	for _, tc := range []struct {
+		location string
+		summary  string
+	}{
+		{"/mnt/data", `Move data of snap "foo" to /mnt/data`},
+		{"", `Move data of snap "foo" back to /var/snap`},
+	} {
+		s.AddCleanup(daemon.MockSnapstateRelocateData(func(st *state.State, instanceName, location string) (*state.TaskSet, error) {
+			c.Check(instanceName, Equals, "foo")
+			c.Check(location, Equals, tc.location)
+			return state.NewTaskSet(st.NewTask("relocate-snap-data", "...")), nil
+		}))
+
+		body := bytes.NewBufferString(`{"action": "relocate-data", "snaps": ["foo"], "params": {"data-location": "` + tc.location + `"}}`)
+		req, err := http.NewRequest("POST", "/v2/debug", body)
+		c.Assert(err, IsNil)
+		rsp := s.asyncReq(c, req, nil)
+
+		st := s.d.Overlord().State()
+		st.Lock()
+		chg := st.Change(rsp.Change)
+		c.Assert(chg, NotNil)
+		c.Check(chg.Kind(), Equals, "relocate-data")
+		c.Check(chg.Summary(), Equals, tc.summary)
+		c.Check(chg.Tasks(), HasLen, 1)
+		var data map[string][]string
+		c.Check(chg.Get("api-data", &data), IsNil)
+		c.Check(data, DeepEquals, map[string][]string{"snap-names": {"foo"}})
+		st.Unlock()
+	}
+}
+
+func (s *relocateDataDebugSuite) TestPostRelocateDataErrors(c *C) {
+	for _, tc := range []struct {
+		body    string
+		err     error
+		message string
+	}{
+		{`{"action": "relocate-data"}`, nil, "data relocation needs exactly one snap"},
+		{`{"action": "relocate-data", "snaps": ["foo", "bar"]}`, nil, "data relocation needs exactly one snap"},
+		{`{"action": "relocate-data", "snaps": ["foo"]}`, &snap.NotInstalledError{Snap: "foo"}, `snap "foo" is not installed`},
+		{`{"action": "relocate-data", "snaps": ["foo"], "params": {"data-location": "srv"}}`, errors.New(`cannot relocate data of snap "foo": location "srv" is not absolute`), `cannot relocate data of snap "foo": location "srv" is not absolute`},
+	} {
+		s.AddCleanup(daemon.MockSnapstateRelocateData(func(st *state.State, instanceName, location string) (*state.TaskSet, error) {
+			return nil, tc.err
+		}))
+
+		req, err := http.NewRequest("POST", "/v2/debug", bytes.NewBufferString(tc.body))
+		c.Assert(err, IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, Equals, 400, Commentf(tc.body))
+		c.Check(rspe.Message, Equals, tc.message, Commentf(tc.body))
+	}
+}
@@ -0,0 +1,48 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"net/http"
+
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/overlord/state/statequery"
+)
+
+// getStateQuery evaluates a state query, see the statequery package, over
+// the state of the daemon, sparing the download of the whole state when
+// debugging. As the state holds credentials, only root can query it.
+func getStateQuery(st *state.State, r *http.Request, query string) Response {
+	uid, err := uidFromRequest(r)
+	if err != nil || uid != 0 {
+		return Forbidden("only root can query the state")
+	}
+	if query == "" {
+		return BadRequest("missing state query")
+	}
+
+	st.Lock()
+	defer st.Unlock()
+	res, err := statequery.Run(st, query)
+	if err != nil {
+		return BadRequest("%v", err)
+	}
+	return SyncResponse(res)
+}
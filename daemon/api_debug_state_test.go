@@ -0,0 +1,83 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"net/http"
+	"net/url"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+var _ = Suite(&stateQueryDebugSuite{})
+
+type stateQueryDebugSuite struct {
+	apiBaseSuite
+}
+
+func (s *stateQueryDebugSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+	d := s.daemon(c)
+
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	chg := st.NewChange("auto-refresh", "Auto-refresh snap \"foo\"")
+	t := st.NewTask("download-snap", "Download foo")
+	chg.AddTask(t)
+	t.SetStatus(state.ErrorStatus)
+	st.NewChange("install-snap", "Install \"bar\" snap")
+}
+
+func (s *stateQueryDebugSuite) request(c *C, query string, uid string) *http.Request {
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=state&query="+url.QueryEscape(query), nil)
+	c.Assert(err, IsNil)
+	req.RemoteAddr = "pid=100;uid=" + uid + ";socket=;"
+	return req
+}
+
+func (s *stateQueryDebugSuite) TestStateQuery(c *C) {
+	rsp := s.syncReq(c, s.request(c, "changes[kind=auto-refresh].summary", "0"), nil)
+	c.Check(rsp.Result, DeepEquals, []interface{}{`Auto-refresh snap "foo"`})
+
+	rsp = s.syncReq(c, s.request(c, "changes[status=Error].kind", "0"), nil)
+	c.Check(rsp.Result, DeepEquals, []interface{}{"auto-refresh"})
+
+	rsp = s.syncReq(c, s.request(c, "changes[kind=remove-snap].summary", "0"), nil)
+	c.Check(rsp.Result, DeepEquals, []interface{}{})
+}
+
+func (s *stateQueryDebugSuite) TestStateQueryErrors(c *C) {
+	rspe := s.errorReq(c, s.request(c, "changes[", "0"), nil)
+	c.Check(rspe.Status, Equals, 400)
+	c.Check(rspe.Message, Matches, `cannot parse query "changes\[": expected a field name .*`)
+
+	rspe = s.errorReq(c, s.request(c, "", "0"), nil)
+	c.Check(rspe.Status, Equals, 400)
+	c.Check(rspe.Message, Equals, "missing state query")
+}
+
+func (s *stateQueryDebugSuite) TestStateQueryNotRoot(c *C) {
+	rspe := s.errorReq(c, s.request(c, "data", "1000"), nil)
+	c.Check(rspe.Status, Equals, 403)
+	c.Check(rspe.Message, Equals, "only root can query the state")
+}
@@ -0,0 +1,208 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"sort"
+	"time"
+
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/strutil"
+)
+
+// defaultStuckThreshold is how long a change must have been in progress to
+// be considered stuck, unless specified otherwise.
+const defaultStuckThreshold = time.Hour
+
+// What a stuck change is blocked on.
+const (
+	// stuckOnReboot is a task waiting for a system restart.
+	stuckOnReboot = "reboot"
+	// stuckOnRetry is a task that is retried, e.g. because the snap it
+	// operates on is busy.
+	stuckOnRetry = "retry"
+	// stuckOnConflict is another change in progress for the same snaps.
+	stuckOnConflict = "conflict"
+)
+
+// Remediations for stuck changes.
+const (
+	// remedyAbort is aborting the change, which is safe as snapd undoes
+	// what was done so far.
+	remedyAbort = "abort"
+	// remedyReboot is rebooting the system for the change to proceed.
+	remedyReboot = "reboot"
+	// remedyWait is waiting as the change is already being undone.
+	remedyWait = "wait"
+)
+
+type stuckTaskInfo struct {
+	ID      string `json:"id"`
+	Kind    string `json:"kind"`
+	Summary string `json:"summary"`
+	Status  string `json:"status"`
+	// LastLog is the last message logged by the task, if any.
+	LastLog string     `json:"last-log,omitempty"`
+	RetryAt *time.Time `json:"retry-at,omitempty"`
+}
+
+type stuckChangeInfo struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Summary   string    `json:"summary"`
+	Status    string    `json:"status"`
+	SpawnTime time.Time `json:"spawn-time"`
+
+	// BlockingTask is the task the change is waiting on, if known.
+	BlockingTask *stuckTaskInfo `json:"blocking-task,omitempty"`
+	// BlockedOn is what the change is waiting for, if known.
+	BlockedOn string `json:"blocked-on,omitempty"`
+	// ConflictsWith lists the other changes in progress for the same
+	// snaps.
+	ConflictsWith []string `json:"conflicts-with,omitempty"`
+	Remediation   string   `json:"remediation"`
+}
+
+var timeNow = time.Now
+
+// getStuckChanges returns the changes that have been in progress for longer
+// than the given threshold, along with what they are blocked on and how to
+// get them unstuck.
+func getStuckChanges(st *state.State, thresholdStr string) Response {
+	threshold := defaultStuckThreshold
+	if thresholdStr != "" {
+		var err error
+		threshold, err = time.ParseDuration(thresholdStr)
+		if err != nil || threshold < 0 {
+			return BadRequest("invalid threshold %q", thresholdStr)
+		}
+	}
+
+	var inProgress []*state.Change
+	for _, chg := range st.Changes() {
+		if !chg.Status().Ready() {
+			inProgress = append(inProgress, chg)
+		}
+	}
+	sort.Slice(inProgress, func(i, j int) bool {
+		return inProgress[i].SpawnTime().Before(inProgress[j].SpawnTime())
+	})
+
+	now := timeNow()
+	stuck := []*stuckChangeInfo{}
+	for _, chg := range inProgress {
+		if now.Sub(chg.SpawnTime()) < threshold {
+			continue
+		}
+		stuck = append(stuck, diagnoseStuckChange(chg, inProgress))
+	}
+	return SyncResponse(stuck)
+}
+
+func diagnoseStuckChange(chg *state.Change, inProgress []*state.Change) *stuckChangeInfo {
+	status := chg.Status()
+	info := &stuckChangeInfo{
+		ID:        chg.ID(),
+		Kind:      chg.Kind(),
+		Summary:   chg.Summary(),
+		Status:    status.String(),
+		SpawnTime: chg.SpawnTime(),
+	}
+
+	if t := blockingTask(chg); t != nil {
+		info.BlockingTask = &stuckTaskInfo{
+			ID:      t.ID(),
+			Kind:    t.Kind(),
+			Summary: t.Summary(),
+			Status:  t.Status().String(),
+		}
+		if log := t.Log(); len(log) > 0 {
+			info.BlockingTask.LastLog = log[len(log)-1]
+		}
+		switch {
+		case t.Status() == state.WaitStatus:
+			info.BlockedOn = stuckOnReboot
+		case !t.AtTime().IsZero():
+			info.BlockedOn = stuckOnRetry
+			retryAt := t.AtTime()
+			info.BlockingTask.RetryAt = &retryAt
+		}
+	}
+
+	var snapNames []string
+	chg.Get("snap-names", &snapNames)
+	for _, other := range inProgress {
+		if other == chg || len(snapNames) == 0 {
+			continue
+		}
+		var otherSnapNames []string
+		other.Get("snap-names", &otherSnapNames)
+		for _, name := range otherSnapNames {
+			if strutil.ListContains(snapNames, name) {
+				info.ConflictsWith = append(info.ConflictsWith, other.ID())
+				break
+			}
+		}
+	}
+	if info.BlockedOn == "" && len(info.ConflictsWith) > 0 {
+		info.BlockedOn = stuckOnConflict
+	}
+
+	switch {
+	case info.BlockedOn == stuckOnReboot:
+		info.Remediation = remedyReboot
+	case status == state.UndoingStatus || status == state.AbortStatus:
+		// aborting again would not make a difference
+		info.Remediation = remedyWait
+	default:
+		info.Remediation = remedyAbort
+	}
+	return info
+}
+
+// blockingTask returns the task a change in progress is waiting on: one
+// that is running, waiting or scheduled to be retried, in this order of
+// preference.
+func blockingTask(chg *state.Change) *state.Task {
+	var waiting, retrying *state.Task
+	for _, t := range chg.Tasks() {
+		switch t.Status() {
+		case state.DoingStatus, state.UndoingStatus:
+			if t.AtTime().IsZero() {
+				return t
+			}
+			if retrying == nil {
+				retrying = t
+			}
+		case state.WaitStatus:
+			if waiting == nil {
+				waiting = t
+			}
+		case state.DoStatus, state.UndoStatus:
+			if !t.AtTime().IsZero() && retrying == nil {
+				retrying = t
+			}
+		}
+	}
+	if waiting != nil {
+		return waiting
+	}
+	return retrying
+}
@@ -0,0 +1,161 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+var _ = Suite(&stuckChangesDebugSuite{})
+
+type stuckChangesDebugSuite struct {
+	apiBaseSuite
+
+	st *state.State
+}
+
+var stuckNow = time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+func (s *stuckChangesDebugSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+	d := s.daemonWithOverlordMock()
+	s.st = d.Overlord().State()
+
+	s.AddCleanup(daemon.MockTimeNow(func() time.Time { return stuckNow }))
+}
+
+func (s *stuckChangesDebugSuite) newChange(c *C, kind string, spawnTime time.Time, snapNames ...string) *state.Change {
+	restore := state.MockTime(spawnTime)
+	defer restore()
+	chg := s.st.NewChange(kind, "summary of "+kind)
+	if len(snapNames) > 0 {
+		chg.Set("snap-names", snapNames)
+	}
+	return chg
+}
+
+func (s *stuckChangesDebugSuite) newTask(c *C, chg *state.Change, kind string, status state.Status) *state.Task {
+	t := s.st.NewTask(kind, "summary of "+kind)
+	chg.AddTask(t)
+	t.SetStatus(status)
+	return t
+}
+
+func (s *stuckChangesDebugSuite) getStuckChanges(c *C, query string) []map[string]interface{} {
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=stuck-changes"+query, nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+
+	// go through JSON as the API clients do
+	b, err := json.Marshal(rsp.Result)
+	c.Assert(err, IsNil)
+	var stuck []map[string]interface{}
+	c.Assert(json.Unmarshal(b, &stuck), IsNil)
+	return stuck
+}
+
+func (s *stuckChangesDebugSuite) TestStuckChanges(c *C) {
+	longAgo := stuckNow.Add(-3 * time.Hour)
+
+	s.st.Lock()
+	// retried as the snap is busy
+	chg1 := s.newChange(c, "refresh-snap", longAgo, "foo")
+	s.newTask(c, chg1, "prerequisites", state.DoneStatus)
+	t1 := s.newTask(c, chg1, "link-snap", state.DoingStatus)
+	t1.Logf("snap \"foo\" has running apps")
+	t1.At(stuckNow.Add(time.Minute))
+	// waiting on the refresh above
+	chg2 := s.newChange(c, "install-snap", longAgo.Add(time.Minute), "foo", "bar")
+	s.newTask(c, chg2, "download-snap", state.DoStatus)
+	// waiting for a reboot
+	chg3 := s.newChange(c, "auto-refresh", longAgo.Add(2*time.Minute), "pc-kernel")
+	s.newTask(c, chg3, "link-snap", state.DoneStatus)
+	t3 := s.newTask(c, chg3, "auto-connect", state.DoingStatus)
+	t3.SetToWait(state.DoneStatus)
+	// being undone
+	chg4 := s.newChange(c, "remove-snap", longAgo.Add(3*time.Minute), "baz")
+	t4 := s.newTask(c, chg4, "unlink-snap", state.UndoingStatus)
+	// recent
+	s.newTask(c, s.newChange(c, "install-snap", stuckNow.Add(-time.Minute), "qux"), "download-snap", state.DoingStatus)
+	// done
+	s.newTask(c, s.newChange(c, "install-snap", longAgo, "foo"), "download-snap", state.DoneStatus)
+	s.st.Unlock()
+
+	stuck := s.getStuckChanges(c, "")
+	c.Assert(stuck, HasLen, 4)
+
+	c.Check(stuck[0]["id"], Equals, chg1.ID())
+	c.Check(stuck[0]["status"], Equals, "Doing")
+	c.Check(stuck[0]["spawn-time"], Equals, longAgo.Format(time.RFC3339))
+	c.Check(stuck[0]["blocked-on"], Equals, "retry")
+	c.Check(stuck[0]["conflicts-with"], DeepEquals, []interface{}{chg2.ID()})
+	c.Check(stuck[0]["remediation"], Equals, "abort")
+	blocking := stuck[0]["blocking-task"].(map[string]interface{})
+	c.Check(blocking["id"], Equals, t1.ID())
+	c.Check(blocking["kind"], Equals, "link-snap")
+	c.Check(blocking["status"], Equals, "Doing")
+	c.Check(blocking["last-log"], Matches, `.* INFO snap "foo" has running apps`)
+	c.Check(blocking["retry-at"], Equals, stuckNow.Add(time.Minute).Format(time.RFC3339))
+
+	c.Check(stuck[1]["id"], Equals, chg2.ID())
+	c.Check(stuck[1]["status"], Equals, "Do")
+	c.Check(stuck[1]["blocking-task"], IsNil)
+	c.Check(stuck[1]["blocked-on"], Equals, "conflict")
+	c.Check(stuck[1]["conflicts-with"], DeepEquals, []interface{}{chg1.ID()})
+	c.Check(stuck[1]["remediation"], Equals, "abort")
+
+	c.Check(stuck[2]["id"], Equals, chg3.ID())
+	c.Check(stuck[2]["status"], Equals, "Wait")
+	c.Check(stuck[2]["blocking-task"].(map[string]interface{})["id"], Equals, t3.ID())
+	c.Check(stuck[2]["blocked-on"], Equals, "reboot")
+	c.Check(stuck[2]["conflicts-with"], IsNil)
+	c.Check(stuck[2]["remediation"], Equals, "reboot")
+
+	c.Check(stuck[3]["id"], Equals, chg4.ID())
+	c.Check(stuck[3]["status"], Equals, "Undoing")
+	c.Check(stuck[3]["blocking-task"].(map[string]interface{})["id"], Equals, t4.ID())
+	c.Check(stuck[3]["blocked-on"], IsNil)
+	c.Check(stuck[3]["remediation"], Equals, "wait")
+
+	// with a longer threshold
+	stuck = s.getStuckChanges(c, "&threshold=4h")
+	c.Check(stuck, HasLen, 0)
+
+	// with a shorter one
+	stuck = s.getStuckChanges(c, "&threshold=30s")
+	c.Check(stuck, HasLen, 5)
+}
+
+func (s *stuckChangesDebugSuite) TestStuckChangesBadThreshold(c *C) {
+	for _, threshold := range []string{"foo", "-1h"} {
+		req, err := http.NewRequest("GET", "/v2/debug?aspect=stuck-changes&threshold="+threshold, nil)
+		c.Assert(err, IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, Equals, 400)
+		c.Check(rspe.Message, Equals, `invalid threshold "`+threshold+`"`)
+	}
+}
@@ -0,0 +1,91 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/snapcore/snapd/interfaces/seccomp"
+	"github.com/snapcore/snapd/overlord"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+var (
+	snapstateSyscallProfiling     = snapstate.SyscallProfiling
+	seccompReportSyscallProfiling = seccomp.ReportSyscallProfiling
+)
+
+// getSyscallProfiling returns the report comparing the system calls used by
+// the snap since profiling started with the ones its policy grants.
+func getSyscallProfiling(o *overlord.Overlord, snapName string) Response {
+	if err := snap.ValidateInstanceName(snapName); err != nil {
+		return BadRequest("invalid snap name: %v", err)
+	}
+
+	st := o.State()
+	st.Lock()
+	info, err := snapstate.CurrentInfo(st, snapName)
+	if err != nil {
+		st.Unlock()
+		return SnapNotFound(snapName, err)
+	}
+	since, err := snapstate.SyscallProfilingSince(st, snapName)
+	st.Unlock()
+	if err != nil {
+		return InternalError("%v", err)
+	}
+	if since.IsZero() {
+		return BadRequest("system calls of snap %q are not being profiled", snapName)
+	}
+
+	// reading the logs doesn't need the state
+	report, err := seccompReportSyscallProfiling(info, since)
+	if err != nil {
+		return InternalError("cannot report system calls of snap %q: %v", snapName, err)
+	}
+	return SyncResponse(report)
+}
+
+// syscallProfiling starts or stops profiling the system calls used by the
+// snap.
+func syscallProfiling(st *state.State, snaps []string, enable bool) Response {
+	if len(snaps) != 1 {
+		return BadRequest("syscall profiling needs exactly one snap")
+	}
+	snapName := snaps[0]
+
+	ts, err := snapstateSyscallProfiling(st, snapName, enable)
+	if err != nil {
+		return errToResponse(err, snaps, BadRequest, "%v")
+	}
+
+	kind, summary := "start-syscall-profiling", fmt.Sprintf("Start profiling system calls of snap %q", snapName)
+	if !enable {
+		kind, summary = "stop-syscall-profiling", fmt.Sprintf("Stop profiling system calls of snap %q", snapName)
+	}
+	chg := st.NewChange(kind, summary)
+	chg.AddAll(ts)
+	chg.Set("api-data", map[string][]string{"snap-names": snaps})
+
+	ensureStateSoon(st)
+	return AsyncResponse(nil, chg.ID())
+}
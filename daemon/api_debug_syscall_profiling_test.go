@@ -0,0 +1,173 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/interfaces/seccomp"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+var _ = Suite(&syscallProfilingDebugSuite{})
+
+type syscallProfilingDebugSuite struct {
+	apiBaseSuite
+}
+
+func (s *syscallProfilingDebugSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+	s.daemon(c)
+	s.expectWriteAccess(daemon.RootAccess{})
+
+	_, restore := daemon.MockEnsureStateSoon(func(*state.State) {})
+	s.AddCleanup(restore)
+}
+
+func (s *syscallProfilingDebugSuite) setProfilingSince(c *C, since time.Time) {
+	st := s.d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(st, "foo", &snapst), IsNil)
+	snapst.SyscallProfilingSince = &since
+	snapstate.Set(st, "foo", &snapst)
+}
+
+func (s *syscallProfilingDebugSuite) TestGetSyscallProfiling(c *C) {
+	s.mockSnap(c, "name: foo\nversion: 1\napps:\n app:\n")
+	since := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	s.setProfilingSince(c, since)
+
+	report := &seccomp.ProfilingReport{
+		Snap:  "foo",
+		Since: since,
+		Profiles: []*seccomp.SyscallProfile{{
+			SecurityTag: "snap.foo.app",
+			Used:        map[string]int{"read": 2},
+			Unused:      []string{"write"},
+		}},
+	}
+	s.AddCleanup(daemon.MockSeccompReportSyscallProfiling(func(info *snap.Info, t time.Time) (*seccomp.ProfilingReport, error) {
+		c.Check(info.InstanceName(), Equals, "foo")
+		c.Check(t.Equal(since), Equals, true)
+		return report, nil
+	}))
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=syscall-profiling&snap=foo", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, Equals, report)
+}
+
+func (s *syscallProfilingDebugSuite) TestGetSyscallProfilingErrors(c *C) {
+	s.AddCleanup(daemon.MockSeccompReportSyscallProfiling(func(info *snap.Info, t time.Time) (*seccomp.ProfilingReport, error) {
+		return nil, errors.New("cannot read journal: boom")
+	}))
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=syscall-profiling&snap=f*o", nil)
+	c.Assert(err, IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 400)
+	c.Check(rspe.Message, Matches, "invalid snap name: .*")
+
+	req, err = http.NewRequest("GET", "/v2/debug?aspect=syscall-profiling&snap=foo", nil)
+	c.Assert(err, IsNil)
+	rspe = s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 404)
+
+	s.mockSnap(c, "name: foo\nversion: 1\napps:\n app:\n")
+	rspe = s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 400)
+	c.Check(rspe.Message, Equals, `system calls of snap "foo" are not being profiled`)
+
+	s.setProfilingSince(c, time.Now())
+	rspe = s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 500)
+	c.Check(rspe.Message, Equals, `cannot report system calls of snap "foo": cannot read journal: boom`)
+}
+
+func (s *syscallProfilingDebugSuite) TestPostSyscallProfiling(c *C) {
+	for _, tc := range []struct {
+		action  string
+		enable  bool
+		kind    string
+		summary string
+	}{
+		{"start-syscall-profiling", true, "start-syscall-profiling", `Start profiling system calls of snap "foo"`},
+		{"stop-syscall-profiling", false, "stop-syscall-profiling", `Stop profiling system calls of snap "foo"`},
+	} {
+		s.AddCleanup(daemon.MockSnapstateSyscallProfiling(func(st *state.State, instanceName string, enable bool) (*state.TaskSet, error) {
+			c.Check(instanceName, Equals, "foo")
+			c.Check(enable, Equals, tc.enable)
+			return state.NewTaskSet(st.NewTask("setup-profiles", "...")), nil
+		}))
+
+		body := bytes.NewBufferString(`{"action": "` + tc.action + `", "snaps": ["foo"]}`)
+		req, err := http.NewRequest("POST", "/v2/debug", body)
+		c.Assert(err, IsNil)
+		rsp := s.asyncReq(c, req, nil)
+
+		st := s.d.Overlord().State()
+		st.Lock()
+		chg := st.Change(rsp.Change)
+		c.Assert(chg, NotNil)
+		c.Check(chg.Kind(), Equals, tc.kind)
+		c.Check(chg.Summary(), Equals, tc.summary)
+		c.Check(chg.Tasks(), HasLen, 1)
+		var data map[string][]string
+		c.Check(chg.Get("api-data", &data), IsNil)
+		c.Check(data, DeepEquals, map[string][]string{"snap-names": {"foo"}})
+		st.Unlock()
+	}
+}
+
+func (s *syscallProfilingDebugSuite) TestPostSyscallProfilingErrors(c *C) {
+	for _, tc := range []struct {
+		body    string
+		err     error
+		status  int
+		message string
+	}{
+		{`{"action": "start-syscall-profiling"}`, nil, 400, "syscall profiling needs exactly one snap"},
+		{`{"action": "start-syscall-profiling", "snaps": ["foo", "bar"]}`, nil, 400, "syscall profiling needs exactly one snap"},
+		{`{"action": "start-syscall-profiling", "snaps": ["foo"]}`, &snap.NotInstalledError{Snap: "foo"}, 400, `snap "foo" is not installed`},
+		{`{"action": "start-syscall-profiling", "snaps": ["foo"]}`, errors.New(`cannot profile system calls of classic snap "foo"`), 400, `cannot profile system calls of classic snap "foo"`},
+	} {
+		s.AddCleanup(daemon.MockSnapstateSyscallProfiling(func(st *state.State, instanceName string, enable bool) (*state.TaskSet, error) {
+			return nil, tc.err
+		}))
+
+		req, err := http.NewRequest("POST", "/v2/debug", bytes.NewBufferString(tc.body))
+		c.Assert(err, IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, Equals, tc.status, Commentf(tc.body))
+		c.Check(rspe.Message, Equals, tc.message, Commentf(tc.body))
+	}
+}
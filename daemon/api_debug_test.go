@@ -23,6 +23,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -30,6 +31,7 @@ import (
 
 	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/testutil"
@@ -209,6 +211,36 @@ func (s *postDebugSuite) TestGetDebugTimingsEnsureAll(c *check.C) {
 	c.Check(tmData["total-duration"], check.NotNil)
 }
 
+func (s *postDebugSuite) TestGetDebugAggregatedTimings(c *check.C) {
+	dataJSON := s.getDebugTimings(c, "/v2/debug?aspect=aggregated-timings")
+
+	var found []string
+	for _, d := range dataJSON {
+		agg := d.(map[string]interface{})
+		c.Check(agg["group"], check.IsNil)
+		c.Check(agg["total"], check.NotNil)
+		found = append(found, fmt.Sprintf("%v:%v", agg["labels"], agg["count"]))
+	}
+	c.Check(found, check.DeepEquals, []string{
+		"[bar]:1",
+		"[bar span]:1",
+		"[ensure:bar]:1",
+		"[ensure:foo]:2",
+		"[ensure:foo span]:2",
+	})
+}
+
+func (s *postDebugSuite) TestGetDebugAggregatedTimingsGrouped(c *check.C) {
+	dataJSON := s.getDebugTimings(c, "/v2/debug?aspect=aggregated-timings&group-by=task-kind")
+
+	c.Assert(dataJSON, check.HasLen, 2)
+	for i, labels := range [][]interface{}{{"bar"}, {"bar", "span"}} {
+		agg := dataJSON[i].(map[string]interface{})
+		c.Check(agg["group"], check.Equals, "bar")
+		c.Check(agg["labels"], check.DeepEquals, labels)
+	}
+}
+
 func (s *postDebugSuite) TestGetDebugTimingsError(c *check.C) {
 	s.daemonWithOverlordMock()
 
@@ -336,3 +368,33 @@ func (s *postDebugSuite) TestMigrateHomeInternalError(c *check.C) {
 	c.Check(apiErr.Status, check.Equals, 500)
 	c.Check(apiErr.Message, check.Equals, `boom`)
 }
+
+func (s *postDebugSuite) TestGetDebugDBusNames(c *check.C) {
+	d := s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=dbus-names", nil)
+	c.Assert(err, check.IsNil)
+
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.DeepEquals, []*snapstate.DBusActivation{})
+
+	st := d.Overlord().State()
+	st.Lock()
+	st.Set("dbus-activations", map[string][]*snapstate.DBusActivation{
+		"foo": {
+			{Name: "org.example.Foo", Bus: "system", Snap: "foo", App: "svc"},
+			{Name: "org.example.Foo", Bus: "session", Snap: "foo", App: "agent"},
+		},
+		"bar": {
+			{Name: "org.example.Bar", Bus: "system", Snap: "bar", App: "daemon"},
+		},
+	})
+	st.Unlock()
+
+	rsp = s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.DeepEquals, []*snapstate.DBusActivation{
+		{Name: "org.example.Foo", Bus: "session", Snap: "foo", App: "agent"},
+		{Name: "org.example.Bar", Bus: "system", Snap: "bar", App: "daemon"},
+		{Name: "org.example.Foo", Bus: "system", Snap: "foo", App: "svc"},
+	})
+}
@@ -0,0 +1,88 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"os"
+
+	"github.com/snapcore/snapd/interfaces/udev"
+	"github.com/snapcore/snapd/overlord"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/snap"
+)
+
+var udevTaggedDevices = udev.TaggedDevices
+
+type udevDevice struct {
+	Tag     string `json:"tag"`
+	ID      string `json:"id"`
+	SysPath string `json:"sys-path,omitempty"`
+}
+
+type udevInfo struct {
+	Snap      string       `json:"snap"`
+	RulesFile string       `json:"rules-file,omitempty"`
+	Rules     string       `json:"rules,omitempty"`
+	Devices   []udevDevice `json:"devices"`
+}
+
+// getUdev describes the udev rules in effect for the snap, along with the
+// devices currently tagged for its apps and hooks.
+func getUdev(o *overlord.Overlord, snapName string) Response {
+	if err := snap.ValidateInstanceName(snapName); err != nil {
+		return BadRequest("invalid snap name: %v", err)
+	}
+
+	st := o.State()
+	st.Lock()
+	info, err := snapstate.CurrentInfo(st, snapName)
+	st.Unlock()
+	if err != nil {
+		return SnapNotFound(snapName, err)
+	}
+
+	res := &udevInfo{
+		Snap:    snapName,
+		Devices: []udevDevice{},
+	}
+	rulesFile := udev.RulesFile(snapName)
+	rules, err := os.ReadFile(rulesFile)
+	if err != nil && !os.IsNotExist(err) {
+		return InternalError("cannot read udev rules of snap %q: %v", snapName, err)
+	}
+	if err == nil {
+		res.RulesFile = rulesFile
+		res.Rules = string(rules)
+	}
+
+	devices, err := udevTaggedDevices(info)
+	if err != nil {
+		return InternalError("cannot get devices tagged for snap %q: %v", snapName, err)
+	}
+	for _, dev := range devices {
+		res.Devices = append(res.Devices, udevDevice{
+			Tag:     dev.Tag,
+			ID:      dev.ID,
+			SysPath: dev.SysPath,
+		})
+	}
+
+	return SyncResponse(res)
+}
@@ -0,0 +1,107 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces/udev"
+	"github.com/snapcore/snapd/snap"
+)
+
+var _ = Suite(&udevDebugSuite{})
+
+type udevDebugSuite struct {
+	apiBaseSuite
+}
+
+func (s *udevDebugSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+	s.daemon(c)
+	s.mockSnap(c, "name: foo\nversion: 1\napps:\n app:\n")
+}
+
+func (s *udevDebugSuite) TestGetUdev(c *C) {
+	rulesFile := filepath.Join(dirs.SnapUdevRulesDir, "70-snap.foo.rules")
+	c.Assert(os.MkdirAll(dirs.SnapUdevRulesDir, 0755), IsNil)
+	c.Assert(os.WriteFile(rulesFile, []byte("# serial-port\nSUBSYSTEM==\"tty\", TAG+=\"snap_foo_app\"\n"), 0644), IsNil)
+	s.AddCleanup(daemon.MockUdevTaggedDevices(func(info *snap.Info) ([]udev.TaggedDevice, error) {
+		c.Check(info.InstanceName(), Equals, "foo")
+		return []udev.TaggedDevice{
+			{Tag: "snap_foo_app", ID: "+usb:1-1"},
+			{Tag: "snap_foo_app", ID: "c4:64", SysPath: "/sys/devices/platform/serial8250/tty/ttyS0"},
+		}, nil
+	}))
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=udev&snap=foo", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, DeepEquals, &daemon.UdevInfo{
+		Snap:      "foo",
+		RulesFile: rulesFile,
+		Rules:     "# serial-port\nSUBSYSTEM==\"tty\", TAG+=\"snap_foo_app\"\n",
+		Devices: []daemon.UdevDevice{
+			{Tag: "snap_foo_app", ID: "+usb:1-1"},
+			{Tag: "snap_foo_app", ID: "c4:64", SysPath: "/sys/devices/platform/serial8250/tty/ttyS0"},
+		},
+	})
+}
+
+func (s *udevDebugSuite) TestGetUdevNoRules(c *C) {
+	s.AddCleanup(daemon.MockUdevTaggedDevices(func(info *snap.Info) ([]udev.TaggedDevice, error) {
+		return nil, nil
+	}))
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=udev&snap=foo", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, DeepEquals, &daemon.UdevInfo{
+		Snap:    "foo",
+		Devices: []daemon.UdevDevice{},
+	})
+}
+
+func (s *udevDebugSuite) TestGetUdevErrors(c *C) {
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=udev&snap=bar", nil)
+	c.Assert(err, IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 404)
+
+	req, err = http.NewRequest("GET", "/v2/debug?aspect=udev&snap=-", nil)
+	c.Assert(err, IsNil)
+	rspe = s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 400)
+
+	s.AddCleanup(daemon.MockUdevTaggedDevices(func(info *snap.Info) ([]udev.TaggedDevice, error) {
+		return nil, errors.New("boom")
+	}))
+	req, err = http.NewRequest("GET", "/v2/debug?aspect=udev&snap=foo", nil)
+	c.Assert(err, IsNil)
+	rspe = s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 500)
+	c.Check(rspe.Message, Equals, `cannot get devices tagged for snap "foo": boom`)
+}
@@ -0,0 +1,106 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package daemon
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+
+	"github.com/snapcore/snapd/desktop/desktopentry"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/strutil"
+	"github.com/snapcore/snapd/wrappers"
+)
+
+var desktopEntriesCmd = &Command{
+	Path:       "/v2/desktop-entries",
+	GET:        getDesktopEntries,
+	ReadAccess: openAccess{},
+}
+
+// desktopEntryJSON describes a desktop entry installed for a snap, as
+// consumed by desktop shells.
+type desktopEntryJSON struct {
+	Snap        string   `json:"snap"`
+	DesktopFile string   `json:"desktop-file"`
+	Name        string   `json:"name,omitempty"`
+	Icon        string   `json:"icon,omitempty"`
+	Exec        string   `json:"exec,omitempty"`
+	Actions     []string `json:"actions,omitempty"`
+}
+
+func getDesktopEntries(c *Command, r *http.Request, user *auth.UserState) Response {
+	names := strutil.CommaSeparatedList(r.URL.Query().Get("snaps"))
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	snaps, err := allLocalSnapInfos(c.d.overlord.State(), false, wanted)
+	if err != nil {
+		return InternalError("cannot list local snaps: %v", err)
+	}
+	found := make(map[string]bool, len(snaps))
+	for _, about := range snaps {
+		found[about.info.InstanceName()] = true
+	}
+	for _, name := range names {
+		if !found[name] {
+			return SnapNotFound(name, fmt.Errorf("snap %q not found", name))
+		}
+	}
+
+	entries := []desktopEntryJSON{}
+	for _, about := range snaps {
+		desktopFiles, err := wrappers.InstalledSnapDesktopFiles(about.info)
+		if err != nil {
+			return InternalError("%v", err)
+		}
+		for _, desktopFile := range desktopFiles {
+			de, err := desktopentry.Read(desktopFile)
+			if err != nil {
+				logger.Noticef("cannot read desktop file %q: %v", desktopFile, err)
+				continue
+			}
+			entry := desktopEntryJSON{
+				Snap:        about.info.InstanceName(),
+				DesktopFile: filepath.Base(desktopFile),
+				Name:        de.Name,
+				Icon:        de.Icon,
+				Exec:        de.Exec,
+			}
+			for action := range de.Actions {
+				entry.Actions = append(entry.Actions, action)
+			}
+			sort.Strings(entry.Actions)
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Snap != entries[j].Snap {
+			return entries[i].Snap < entries[j].Snap
+		}
+		return entries[i].DesktopFile < entries[j].DesktopFile
+	})
+
+	return SyncResponse(entries)
+}
@@ -0,0 +1,115 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package daemon_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/snap"
+)
+
+var _ = check.Suite(&desktopEntriesSuite{})
+
+type desktopEntriesSuite struct {
+	apiBaseSuite
+}
+
+func (s *desktopEntriesSuite) mockDesktopFile(c *check.C, name, content string) {
+	c.Assert(os.MkdirAll(dirs.SnapDesktopFilesDir, 0755), check.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(dirs.SnapDesktopFilesDir, name), []byte(content), 0644), check.IsNil)
+}
+
+func (s *desktopEntriesSuite) TestDesktopEntries(c *check.C) {
+	d := s.daemon(c)
+
+	s.mkInstalledInState(c, d, "foo", "bar", "v1", snap.R(10), true, "")
+	s.mkInstalledInState(c, d, "baz", "bar", "v1", snap.R(10), true, "")
+
+	s.mockDesktopFile(c, "foo_foo.desktop", `[Desktop Entry]
+X-SnapInstanceName=foo
+Name=Foo
+Icon=/snap/foo/10/foo.png
+Exec=env BAMF_DESKTOP_FILE_HINT=foo_foo.desktop /snap/bin/foo
+Actions=new-window;private;
+
+[Desktop Action private]
+Name=Private
+Exec=/snap/bin/foo --private
+
+[Desktop Action new-window]
+Name=New Window
+Exec=/snap/bin/foo --new-window
+`)
+	s.mockDesktopFile(c, "baz_baz.desktop", "[Desktop Entry]\nName=Baz\n")
+	// broken files are skipped
+	s.mockDesktopFile(c, "baz_broken.desktop", "[Desktop Entry]\nbroken\n")
+	// as are desktop files of other snaps
+	s.mockDesktopFile(c, "other_other.desktop", "[Desktop Entry]\nName=Other\n")
+
+	req, err := http.NewRequest("GET", "/v2/desktop-entries", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.DeepEquals, []daemon.DesktopEntryJSON{
+		{
+			Snap:        "baz",
+			DesktopFile: "baz_baz.desktop",
+			Name:        "Baz",
+		}, {
+			Snap:        "foo",
+			DesktopFile: "foo_foo.desktop",
+			Name:        "Foo",
+			Icon:        "/snap/foo/10/foo.png",
+			Exec:        "env BAMF_DESKTOP_FILE_HINT=foo_foo.desktop /snap/bin/foo",
+			Actions:     []string{"new-window", "private"},
+		},
+	})
+
+	req, err = http.NewRequest("GET", "/v2/desktop-entries?snaps=foo", nil)
+	c.Assert(err, check.IsNil)
+	rsp = s.syncReq(c, req, nil)
+	c.Assert(rsp.Result, check.HasLen, 1)
+	c.Check(rsp.Result.([]daemon.DesktopEntryJSON)[0].Snap, check.Equals, "foo")
+}
+
+func (s *desktopEntriesSuite) TestDesktopEntriesNone(c *check.C) {
+	d := s.daemon(c)
+
+	s.mkInstalledInState(c, d, "foo", "bar", "v1", snap.R(10), true, "")
+
+	req, err := http.NewRequest("GET", "/v2/desktop-entries", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.DeepEquals, []daemon.DesktopEntryJSON{})
+}
+
+func (s *desktopEntriesSuite) TestDesktopEntriesSnapNotFound(c *check.C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/desktop-entries?snaps=foo", nil)
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 404)
+	c.Check(rspe.Message, check.Equals, `snap "foo" not found`)
+}
@@ -56,6 +56,7 @@ func searchStore(c *Command, r *http.Request, user *auth.UserState) Response {
 	category := query.Get("category")
 	name := query.Get("name")
 	scope := query.Get("scope")
+	sort := query.Get("sort")
 	private := false
 	prefix := false
 
@@ -113,12 +114,15 @@ func searchStore(c *Command, r *http.Request, user *auth.UserState) Response {
 		Category: category,
 		Private:  private,
 		Scope:    scope,
+		Sort:     sort,
 	}, user)
 	switch err {
 	case nil:
 		// pass
 	case store.ErrBadQuery:
 		return BadQuery()
+	case store.ErrInvalidSort:
+		return BadRequest("invalid sort order %q", sort)
 	case store.ErrUnauthenticated, store.ErrInvalidCredentials:
 		return Unauthorized(err.Error())
 	default:
@@ -90,6 +90,7 @@ func (s *findSuite) TestFindRefreshes(c *check.C) {
 			DisplayName: "Foo",
 			Validation:  "unproven",
 		},
+		ReleaseNotes: "store got better",
 	}}
 	s.mockSnap(c, "name: store\nversion: 1.0")
 
@@ -101,6 +102,7 @@ func (s *findSuite) TestFindRefreshes(c *check.C) {
 	snaps := snapList(rsp.Result)
 	c.Assert(snaps, check.HasLen, 1)
 	c.Assert(snaps[0]["name"], check.Equals, "store")
+	c.Check(snaps[0]["release-notes"], check.Equals, "store got better")
 	c.Check(s.currentSnaps, check.HasLen, 1)
 	c.Check(s.actions, check.HasLen, 1)
 }
@@ -261,6 +263,34 @@ func (s *findSuite) TestFindScope(c *check.C) {
 	})
 }
 
+func (s *findSuite) TestFindSort(c *check.C) {
+	s.daemon(c)
+
+	s.rsnaps = []*snap.Info{}
+
+	req, err := http.NewRequest("GET", "/v2/find?category=bar&sort=recent", nil)
+	c.Assert(err, check.IsNil)
+
+	_ = s.syncReq(c, req, nil)
+
+	c.Check(s.storeSearch, check.DeepEquals, store.Search{
+		Category: "bar",
+		Sort:     "recent",
+	})
+}
+
+func (s *findSuite) TestFindInvalidSort(c *check.C) {
+	s.daemon(c)
+
+	s.err = store.ErrInvalidSort
+	req, err := http.NewRequest("GET", "/v2/find?q=foo&sort=oldest", nil)
+	c.Assert(err, check.IsNil)
+
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, `invalid sort order "oldest"`)
+}
+
 func (s *findSuite) TestFindCommonID(c *check.C) {
 	s.daemon(c)
 
@@ -30,14 +30,17 @@ import (
 	"github.com/snapcore/snapd/arch"
 	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/features"
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/devicestate"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/release"
 	"github.com/snapcore/snapd/sandbox"
+	"github.com/snapcore/snapd/sandbox/wsl"
 	"github.com/snapcore/snapd/snap"
 )
 
@@ -53,6 +56,8 @@ var (
 		Path:       "/v2/system-info",
 		GET:        sysInfo,
 		ReadAccess: openAccess{},
+
+		ServedDuringStartUp: true,
 	}
 
 	stateChangeCmd = &Command{
@@ -100,6 +105,60 @@ func tbd(c *Command, r *http.Request, user *auth.UserState) Response {
 }
 
 func sysInfo(c *Command, r *http.Request, user *auth.UserState) Response {
+	m := map[string]interface{}{
+		"series":         release.Series,
+		"version":        c.d.Version,
+		"build-id":       buildID,
+		"os-release":     release.ReleaseInfo,
+		"on-classic":     release.OnClassic,
+		"kernel-version": osutil.KernelVersion(),
+		"locations": map[string]interface{}{
+			"snap-mount-dir": dirs.SnapMountDir,
+			"snap-bin-dir":   dirs.SnapBinariesDir,
+		},
+		"architecture": arch.DpkgArchitecture(),
+		"readiness":    readiness(c.d),
+	}
+	if systemdVirt != "" {
+		m["virtualization"] = systemdVirt
+	}
+
+	// NOTE: Right now we don't have a good way to differentiate if we
+	// only have partial confinement (ala AppArmor disabled and Seccomp
+	// enabled) or no confinement at all. Once we have a better system
+	// in place how we can dynamically retrieve these information from
+	// snapd we will use this here.
+	if sandbox.ForceDevMode() {
+		m["confinement"] = "partial"
+	} else {
+		m["confinement"] = "strict"
+	}
+
+	// Running in a container lacking some capabilities disables the
+	// behaviors requiring them, list which and why.
+	if container := sandbox.Container(); container != "" {
+		m["container"] = container
+		disabled := []client.DisabledFeature{}
+		for _, d := range sandbox.DegradedFeatures() {
+			disabled = append(disabled, client.DisabledFeature{Feature: d.Feature, Reason: d.Reason})
+		}
+		m["disabled-features"] = disabled
+	}
+
+	if info := wsl.Probe(); info != nil {
+		m["wsl"] = &client.WSLInfo{
+			Version:      info.Version,
+			Interop:      info.Interop,
+			UserSessions: info.UserSessions,
+		}
+	}
+
+	if c.d.startingUp() {
+		// the managers are still being initialized and the state
+		// may be locked for a while, report what is known already
+		return SyncResponse(m)
+	}
+
 	st := c.d.overlord.State()
 	snapMgr := c.d.overlord.SnapManager()
 	deviceMgr := c.d.overlord.DeviceManager()
@@ -128,45 +187,101 @@ func sysInfo(c *Command, r *http.Request, user *auth.UserState) Response {
 		refreshInfo.Schedule = refreshScheduleStr
 	}
 
-	m := map[string]interface{}{
-		"series":         release.Series,
-		"version":        c.d.Version,
-		"build-id":       buildID,
-		"os-release":     release.ReleaseInfo,
-		"on-classic":     release.OnClassic,
-		"managed":        len(users) > 0,
-		"kernel-version": osutil.KernelVersion(),
-		"locations": map[string]interface{}{
-			"snap-mount-dir": dirs.SnapMountDir,
-			"snap-bin-dir":   dirs.SnapBinariesDir,
-		},
-		"refresh":      refreshInfo,
-		"architecture": arch.DpkgArchitecture(),
-		"system-mode":  deviceMgr.SystemMode(devicestate.SysAny),
-	}
-	if systemdVirt != "" {
-		m["virtualization"] = systemdVirt
-	}
+	m["managed"] = len(users) > 0
+	m["refresh"] = refreshInfo
+	m["system-mode"] = deviceMgr.SystemMode(devicestate.SysAny)
 
-	// NOTE: Right now we don't have a good way to differentiate if we
-	// only have partial confinement (ala AppArmor disabled and Seccomp
-	// enabled) or no confinement at all. Once we have a better system
-	// in place how we can dynamically retrieve these information from
-	// snapd we will use this here.
-	if sandbox.ForceDevMode() {
-		m["confinement"] = "partial"
-	} else {
-		m["confinement"] = "strict"
+	featuresInfo, err := experimentalFeatures(config.NewTransaction(st))
+	if err != nil {
+		return InternalError("cannot get experimental features: %v", err)
 	}
+	m["features"] = featuresInfo
 
+	repo := c.d.overlord.InterfaceManager().Repository()
 	// Convey richer information about features of available security backends.
-	if features := sandboxFeatures(c.d.overlord.InterfaceManager().Repository().Backends()); features != nil {
+	if features := sandboxFeatures(repo.Backends()); features != nil {
 		m["sandbox-features"] = features
 	}
 
+	if server := printServer(repo); server != "" {
+		m["print-server"] = server
+	}
+
 	return SyncResponse(m)
 }
 
+// readiness returns the status of the initialization of the managers of the
+// daemon.
+func readiness(d *Daemon) *client.Readiness {
+	ready := &client.Readiness{
+		Ready:    !d.startingUp(),
+		Managers: []client.ManagerReadiness{},
+	}
+	for _, status := range d.overlord.StartUpStatus() {
+		mgr := client.ManagerReadiness{
+			Name:   status.Manager,
+			Lazy:   status.Lazy,
+			Status: status.Status,
+		}
+		if status.Err != nil {
+			mgr.Error = status.Err.Error()
+		}
+		ready.Managers = append(ready.Managers, mgr)
+	}
+	return ready
+}
+
+// printServer returns the name of the snap acting as the print server of the
+// system, that is the snap providing a cups slot which exports the socket of
+// its cupsd, or "" if there is none. A snap with connected clients is
+// preferred if several snaps qualify.
+func printServer(repo *interfaces.Repository) string {
+	var server string
+	for _, slot := range repo.AllSlots("cups") {
+		if _, ok := slot.Lookup("cups-socket-directory"); !ok {
+			continue
+		}
+		name := slot.Snap.InstanceName()
+		if conns, err := repo.Connected(name, slot.Name); err == nil && len(conns) > 0 {
+			return name
+		}
+		if server == "" {
+			server = name
+		}
+	}
+	return server
+}
+
+// experimentalFeatures lists all the known features of snapd, along with
+// whether they are enabled.
+func experimentalFeatures(tr *config.Transaction) ([]client.Feature, error) {
+	known := features.KnownFeatures()
+	result := make([]client.Feature, 0, len(known))
+	for _, f := range known {
+		enabled, err := features.Flag(tr, f)
+		if err != nil {
+			return nil, err
+		}
+		var prereqs []string
+		for _, prereq := range f.Prerequisites() {
+			prereqs = append(prereqs, prereq.String())
+		}
+		result = append(result, client.Feature{
+			Name:            f.String(),
+			Description:     f.Description(),
+			Maturity:        string(f.Maturity()),
+			Enabled:         enabled,
+			RequiresRestart: f.RequiresRestart(),
+			RequiresReboot:  f.RequiresReboot(),
+			Prerequisites:   prereqs,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
 func formatRefreshTime(t time.Time) string {
 	if t.IsZero() {
 		return ""
@@ -338,6 +453,10 @@ type taskInfoProgress struct {
 	Label string `json:"label"`
 	Done  int    `json:"done"`
 	Total int    `json:"total"`
+
+	Phase string     `json:"phase,omitempty"`
+	Unit  string     `json:"unit,omitempty"`
+	ETA   *time.Time `json:"eta,omitempty"`
 }
 
 func change2changeInfo(chg *state.Change) *changeInfo {
@@ -363,6 +482,7 @@ func change2changeInfo(chg *state.Change) *changeInfo {
 	taskInfos := make([]*taskInfo, len(tasks))
 	for j, t := range tasks {
 		label, done, total := t.Progress()
+		details := t.ProgressDetails()
 
 		taskInfo := &taskInfo{
 			ID:      t.ID(),
@@ -374,9 +494,14 @@ func change2changeInfo(chg *state.Change) *changeInfo {
 				Label: label,
 				Done:  done,
 				Total: total,
+				Phase: details.Phase,
+				Unit:  string(details.Unit),
 			},
 			SpawnTime: t.SpawnTime(),
 		}
+		if !details.ETA.IsZero() {
+			taskInfo.Progress.ETA = &details.ETA
+		}
 		readyTime := t.ReadyTime()
 		if !readyTime.IsZero() {
 			taskInfo.ReadyTime = &readyTime
@@ -394,11 +519,22 @@ func change2changeInfo(chg *state.Change) *changeInfo {
 }
 
 var (
-	stateOkayWarnings    = (*state.State).OkayWarnings
+	stateOkayWarnings    = (*state.State).OkayUserWarnings
 	stateAllWarnings     = (*state.State).AllWarnings
-	statePendingWarnings = (*state.State).PendingWarnings
+	statePendingWarnings = (*state.State).PendingUserWarnings
 )
 
+// warningsUserID returns the ID of the user making the request, by which
+// pending warnings are looked up and acknowledged. When it cannot be
+// determined warnings are handled for all users.
+func warningsUserID(r *http.Request) *uint32 {
+	uid, err := uidFromRequest(r)
+	if err != nil {
+		return nil
+	}
+	return &uid
+}
+
 func getWarnings(c *Command, r *http.Request, _ *auth.UserState) Response {
 	query := r.URL.Query()
 	var all bool
@@ -411,6 +547,13 @@ func getWarnings(c *Command, r *http.Request, _ *auth.UserState) Response {
 	default:
 		return BadRequest("invalid select parameter: %q", sel)
 	}
+	var severity state.WarningSeverity
+	if sev := query.Get("severity"); sev != "" {
+		severity = state.WarningSeverity(sev)
+		if !severity.Valid() {
+			return BadRequest("invalid severity parameter: %q", sev)
+		}
+	}
 
 	st := c.d.overlord.State()
 	st.Lock()
@@ -420,7 +563,16 @@ func getWarnings(c *Command, r *http.Request, _ *auth.UserState) Response {
 	if all {
 		ws = stateAllWarnings(st)
 	} else {
-		ws, _ = statePendingWarnings(st)
+		ws, _ = statePendingWarnings(st, warningsUserID(r))
+	}
+	if severity != "" {
+		filtered := make([]*state.Warning, 0, len(ws))
+		for _, w := range ws {
+			if w.Severity().AtLeast(severity) {
+				filtered = append(filtered, w)
+			}
+		}
+		ws = filtered
 	}
 	if len(ws) == 0 {
 		// no need to confuse the issue
@@ -446,7 +598,7 @@ func ackWarnings(c *Command, r *http.Request, _ *auth.UserState) Response {
 	st := c.d.overlord.State()
 	st.Lock()
 	defer st.Unlock()
-	n := stateOkayWarnings(st, op.Timestamp)
+	n := stateOkayWarnings(st, warningsUserID(r), op.Timestamp)
 
 	return SyncResponse(n)
 }
@@ -27,14 +27,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
 	"time"
 
 	"gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/arch"
 	"github.com/snapcore/snapd/boot"
+	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/daemon"
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/features"
 	"github.com/snapcore/snapd/interfaces/ifacetest"
 	"github.com/snapcore/snapd/overlord/auth"
 	"github.com/snapcore/snapd/overlord/configstate/config"
@@ -70,6 +75,49 @@ func (s *generalSuite) TestRoot(c *check.C) {
 	c.Check(rsp.Result, check.DeepEquals, expected)
 }
 
+var expectedReadiness = map[string]interface{}{
+	"ready": true,
+	"managers": []interface{}{
+		map[string]interface{}{"name": "restart", "status": "done"},
+		map[string]interface{}{"name": "snapstate", "status": "done"},
+		map[string]interface{}{"name": "devicestate", "status": "done"},
+		map[string]interface{}{"name": "snapshotstate", "status": "done"},
+		map[string]interface{}{"name": "ifacestate", "lazy": true, "status": "done"},
+	},
+}
+
+// expectedFeatures returns the features listed in the system information
+// when only the given ones are explicitly enabled.
+func expectedFeatures(enabled map[string]bool) []interface{} {
+	var result []interface{}
+	for _, f := range features.KnownFeatures() {
+		feature := map[string]interface{}{
+			"name":        f.String(),
+			"description": f.Description(),
+			"maturity":    string(f.Maturity()),
+			"enabled":     f.IsEnabledWhenUnset() || enabled[f.String()],
+		}
+		if f.RequiresRestart() {
+			feature["requires-restart"] = true
+		}
+		if f.RequiresReboot() {
+			feature["requires-reboot"] = true
+		}
+		if prereqs := f.Prerequisites(); len(prereqs) > 0 {
+			var names []interface{}
+			for _, prereq := range prereqs {
+				names = append(names, prereq.String())
+			}
+			feature["prerequisites"] = names
+		}
+		result = append(result, feature)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].(map[string]interface{})["name"].(string) < result[j].(map[string]interface{})["name"].(string)
+	})
+	return result
+}
+
 func (s *generalSuite) TestSysInfo(c *check.C) {
 	req, err := http.NewRequest("GET", "/v2/system-info", nil)
 	c.Assert(err, check.IsNil)
@@ -132,6 +180,8 @@ func (s *generalSuite) TestSysInfo(c *check.C) {
 		"architecture":     arch.DpkgArchitecture(),
 		"virtualization":   "magic",
 		"system-mode":      "run",
+		"readiness":        expectedReadiness,
+		"features":         expectedFeatures(nil),
 	}
 	var rsp daemon.RespJSON
 	c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), check.IsNil)
@@ -144,6 +194,100 @@ func (s *generalSuite) TestSysInfo(c *check.C) {
 	c.Check(rsp.Result, check.DeepEquals, expected)
 }
 
+func (s *generalSuite) TestSysInfoFeatures(c *check.C) {
+	d := s.daemon(c)
+
+	st := d.Overlord().State()
+	st.Lock()
+	tr := config.NewTransaction(st)
+	tr.Set("core", "experimental.hotplug", true)
+	tr.Set("core", "experimental.layouts", false)
+	tr.Commit()
+	st.Unlock()
+
+	req, err := http.NewRequest("GET", "/v2/system-info", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+
+	featuresInfo, ok := rsp.Result.(map[string]interface{})["features"].([]client.Feature)
+	c.Assert(ok, check.Equals, true)
+	c.Check(featuresInfo, check.HasLen, len(features.KnownFeatures()))
+	byName := make(map[string]client.Feature, len(featuresInfo))
+	for _, f := range featuresInfo {
+		byName[f.Name] = f
+	}
+	c.Check(byName["hotplug"], check.DeepEquals, client.Feature{
+		Name:        "hotplug",
+		Description: "Create slots for hardware devices as they are plugged in",
+		Maturity:    "experimental",
+		Enabled:     true,
+	})
+	c.Check(byName["layouts"].Enabled, check.Equals, false)
+	c.Check(byName["layouts"].Maturity, check.Equals, "stable")
+	c.Check(byName["mounts-generator"].RequiresReboot, check.Equals, true)
+	c.Check(byName["per-user-mount-namespace"].RequiresRestart, check.Equals, true)
+	c.Check(byName["refresh-app-awareness-ux"].Prerequisites, check.DeepEquals, []string{"refresh-app-awareness"})
+}
+
+func (s *generalSuite) TestSysInfoStartingUp(c *check.C) {
+	req, err := http.NewRequest("GET", "/v2/system-info", nil)
+	c.Assert(err, check.IsNil)
+
+	d := s.daemon(c)
+	d.Version = "42b1"
+	done := d.MockStartingUp()
+	defer done()
+
+	// the lazy startup of the managers holds the state lock
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+
+	restore := release.MockReleaseInfo(&release.OS{ID: "distro-id", VersionID: "1.2"})
+	defer restore()
+	restore = release.MockOnClassic(true)
+	defer restore()
+	restore = sandbox.MockForceDevMode(false)
+	defer restore()
+	restore = daemon.MockSystemdVirt("")
+	defer restore()
+	buildID := "this-is-my-build-id"
+	restore = daemon.MockBuildID(buildID)
+	defer restore()
+
+	rec := httptest.NewRecorder()
+	s.req(c, req, nil).ServeHTTP(rec, req)
+	c.Check(rec.Code, check.Equals, 200)
+
+	var rsp daemon.RespJSON
+	c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), check.IsNil)
+	c.Check(rsp.Status, check.Equals, 200)
+	result := rsp.Result.(map[string]interface{})
+	c.Check(result["kernel-version"], check.Not(check.Equals), "")
+	delete(result, "kernel-version")
+	// only what does not need the state is reported
+	c.Check(result, check.DeepEquals, map[string]interface{}{
+		"series":  "16",
+		"version": "42b1",
+		"os-release": map[string]interface{}{
+			"id":         "distro-id",
+			"version-id": "1.2",
+		},
+		"build-id":   buildID,
+		"on-classic": true,
+		"locations": map[string]interface{}{
+			"snap-mount-dir": dirs.SnapMountDir,
+			"snap-bin-dir":   dirs.SnapBinariesDir,
+		},
+		"confinement":  "strict",
+		"architecture": arch.DpkgArchitecture(),
+		"readiness": map[string]interface{}{
+			"ready":    false,
+			"managers": expectedReadiness["managers"],
+		},
+	})
+}
+
 func (s *generalSuite) TestSysInfoLegacyRefresh(c *check.C) {
 	req, err := http.NewRequest("GET", "/v2/system-info", nil)
 	c.Assert(err, check.IsNil)
@@ -213,6 +357,8 @@ func (s *generalSuite) TestSysInfoLegacyRefresh(c *check.C) {
 		"architecture":   arch.DpkgArchitecture(),
 		"virtualization": "kvm",
 		"system-mode":    "run",
+		"readiness":      expectedReadiness,
+		"features":       expectedFeatures(nil),
 	}
 	var rsp daemon.RespJSON
 	c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), check.IsNil)
@@ -291,6 +437,8 @@ func (s *generalSuite) testSysInfoSystemMode(c *check.C, mode string) {
 		},
 		"architecture": arch.DpkgArchitecture(),
 		"system-mode":  mode,
+		"readiness":    expectedReadiness,
+		"features":     expectedFeatures(nil),
 	}
 	var rsp daemon.RespJSON
 	c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), check.IsNil)
@@ -345,6 +493,95 @@ func (s *generalSuite) TestSysInfoWorksDegraded(c *check.C) {
 	c.Check(rsp.Status, check.Equals, 200)
 }
 
+func (s *generalSuite) TestSysInfoContainer(c *check.C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/system-info", nil)
+	c.Assert(err, check.IsNil)
+
+	// not in a container
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result.(map[string]interface{})["container"], check.IsNil)
+	c.Check(rsp.Result.(map[string]interface{})["disabled-features"], check.IsNil)
+
+	containerFile := filepath.Join(dirs.GlobalRootDir, "/run/systemd/container")
+	c.Assert(os.MkdirAll(filepath.Dir(containerFile), 0755), check.IsNil)
+	c.Assert(os.WriteFile(containerFile, []byte("lxc\n"), 0644), check.IsNil)
+	restore := sandbox.MockDegradedFeatures(nil)
+	defer restore()
+
+	// a container with all capabilities
+	rsp = s.syncReq(c, req, nil)
+	c.Check(rsp.Result.(map[string]interface{})["container"], check.Equals, "lxc")
+	c.Check(rsp.Result.(map[string]interface{})["disabled-features"], check.DeepEquals, []client.DisabledFeature{})
+
+	restore = sandbox.MockDegradedFeatures([]sandbox.DegradedFeature{{
+		Feature: sandbox.FeatureQuotaGroups,
+		Reason:  "the cgroup hierarchy is not delegated to the container",
+	}})
+	defer restore()
+
+	rsp = s.syncReq(c, req, nil)
+	c.Check(rsp.Result.(map[string]interface{})["container"], check.Equals, "lxc")
+	c.Check(rsp.Result.(map[string]interface{})["disabled-features"], check.DeepEquals, []client.DisabledFeature{{
+		Feature: "quota-groups",
+		Reason:  "the cgroup hierarchy is not delegated to the container",
+	}})
+}
+
+func (s *generalSuite) TestSysInfoWSL(c *check.C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/system-info", nil)
+	c.Assert(err, check.IsNil)
+
+	restore := release.MockWSLVersion(0)
+	defer restore()
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result.(map[string]interface{})["wsl"], check.IsNil)
+
+	restore = release.MockWSLVersion(2)
+	defer restore()
+	interop := filepath.Join(dirs.GlobalRootDir, "/proc/sys/fs/binfmt_misc/WSLInterop")
+	c.Assert(os.MkdirAll(filepath.Dir(interop), 0755), check.IsNil)
+	c.Assert(os.WriteFile(interop, []byte("enabled\ninterpreter /init\n"), 0644), check.IsNil)
+
+	rsp = s.syncReq(c, req, nil)
+	c.Check(rsp.Result.(map[string]interface{})["wsl"], check.DeepEquals, &client.WSLInfo{
+		Version: 2,
+		Interop: true,
+	})
+}
+
+func (s *generalSuite) TestSysInfoPrintServer(c *check.C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/system-info", nil)
+	c.Assert(err, check.IsNil)
+
+	// no print server
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result.(map[string]interface{})["print-server"], check.IsNil)
+
+	// legacy providers are not print servers
+	s.mockSnap(c, `name: legacy-cups
+version: 1
+slots:
+  cups: {}
+`)
+	rsp = s.syncReq(c, req, nil)
+	c.Check(rsp.Result.(map[string]interface{})["print-server"], check.IsNil)
+
+	s.mockSnap(c, `name: cups
+version: 1
+slots:
+  cups:
+    cups-socket-directory: $SNAP_COMMON/run
+`)
+	rsp = s.syncReq(c, req, nil)
+	c.Check(rsp.Result.(map[string]interface{})["print-server"], check.Equals, "cups")
+}
+
 func setupChanges(st *state.State) []string {
 	chg1 := st.NewChange("install", "install...")
 	chg1.Set("snap-names", []string{"funky-snap-name"})
@@ -599,6 +836,58 @@ func (s *generalSuite) TestStateChange(c *check.C) {
 	})
 }
 
+func (s *generalSuite) TestStateChangeProgressDetails(c *check.C) {
+	restore := state.MockTime(time.Date(2016, 04, 21, 1, 2, 3, 0, time.UTC))
+	defer restore()
+
+	// Setup
+	d := s.daemon(c)
+	st := d.Overlord().State()
+	st.Lock()
+	ids := setupChanges(st)
+	st.Task(ids[2]).SetProgressWithDetails("Download snap", 1024, 4096, state.ProgressDetails{
+		Phase: "download",
+		Unit:  state.ProgressBytes,
+		ETA:   time.Date(2016, 04, 21, 1, 2, 13, 0, time.UTC),
+	})
+	st.Task(ids[3]).SetProgressWithDetails("Copy snap data", 1, 2, state.ProgressDetails{
+		Phase: "copy-data",
+		Unit:  state.ProgressSteps,
+	})
+	st.Unlock()
+
+	// Execute
+	req, err := http.NewRequest("GET", "/v2/changes/"+ids[0], nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	rec := httptest.NewRecorder()
+	rsp.ServeHTTP(rec, req)
+
+	// Verify
+	c.Check(rec.Code, check.Equals, 200)
+
+	var body map[string]interface{}
+	err = json.Unmarshal(rec.Body.Bytes(), &body)
+	c.Check(err, check.IsNil)
+	tasks := body["result"].(map[string]interface{})["tasks"].([]interface{})
+	c.Assert(tasks, check.HasLen, 2)
+	c.Check(tasks[0].(map[string]interface{})["progress"], check.DeepEquals, map[string]interface{}{
+		"label": "Download snap",
+		"done":  1024.,
+		"total": 4096.,
+		"phase": "download",
+		"unit":  "bytes",
+		"eta":   "2016-04-21T01:02:13Z",
+	})
+	c.Check(tasks[1].(map[string]interface{})["progress"], check.DeepEquals, map[string]interface{}{
+		"label": "Copy snap data",
+		"done":  1.,
+		"total": 2.,
+		"phase": "copy-data",
+		"unit":  "steps",
+	})
+}
+
 func (s *generalSuite) expectManageAccess() {
 	s.expectWriteAccess(daemon.AuthenticatedAccess{Polkit: "io.snapcraft.snapd.manage"})
 }
@@ -714,9 +1003,17 @@ func (s *generalSuite) testWarnings(c *check.C, all bool, body io.Reader) (calls
 
 	s.expectManageAccess()
 
-	okayWarns := func(*state.State, time.Time) int { calls += "ok"; return 0 }
+	okayWarns := func(_ *state.State, userID *uint32, _ time.Time) int {
+		c.Check(userID, check.IsNil)
+		calls += "ok"
+		return 0
+	}
 	allWarns := func(*state.State) []*state.Warning { calls += "all"; return nil }
-	pendingWarns := func(*state.State) ([]*state.Warning, time.Time) { calls += "show"; return nil, time.Time{} }
+	pendingWarns := func(_ *state.State, userID *uint32) ([]*state.Warning, time.Time) {
+		c.Check(userID, check.IsNil)
+		calls += "show"
+		return nil, time.Time{}
+	}
 	restore := daemon.MockWarningsAccessors(okayWarns, allWarns, pendingWarns)
 	defer restore()
 
@@ -755,3 +1052,75 @@ func (s *generalSuite) TestAckWarnings(c *check.C) {
 	c.Check(calls, check.Equals, "ok")
 	c.Check(result, check.DeepEquals, 0)
 }
+
+func (s *generalSuite) TestWarningsPerUser(c *check.C) {
+	d := s.daemon(c)
+
+	st := d.Overlord().State()
+	st.Lock()
+	st.Warnf("hello")
+	st.Unlock()
+
+	get := func(uid int) []*state.Warning {
+		req, err := http.NewRequest("GET", "/v2/warnings", nil)
+		c.Assert(err, check.IsNil)
+		req.RemoteAddr = fmt.Sprintf("pid=100;uid=%d;socket=;", uid)
+		rsp := s.syncReq(c, req, nil)
+		c.Assert(rsp.Status, check.Equals, 200)
+		if ws, ok := rsp.Result.([]*state.Warning); ok {
+			return ws
+		}
+		return nil
+	}
+	c.Check(get(1000), check.HasLen, 1)
+	c.Check(get(1001), check.HasLen, 1)
+
+	s.expectManageAccess()
+	req, err := http.NewRequest("POST", "/v2/warnings", bytes.NewReader([]byte(`{"action": "okay", "timestamp": "`+time.Now().UTC().Format(time.RFC3339Nano)+`"}`)))
+	c.Assert(err, check.IsNil)
+	req.RemoteAddr = "pid=100;uid=1000;socket=;"
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.Equals, 1)
+
+	// acknowledged by the first user only
+	c.Check(get(1000), check.HasLen, 0)
+	c.Check(get(1001), check.HasLen, 1)
+}
+
+func (s *generalSuite) TestWarningsSeverity(c *check.C) {
+	d := s.daemon(c)
+
+	st := d.Overlord().State()
+	st.Lock()
+	st.WarnfWithSeverity(state.WarningSeverityInfo, "fyi")
+	st.Warnf("hello")
+	st.WarnfWithSeverity(state.WarningSeverityCritical, "danger")
+	st.Unlock()
+
+	for _, t := range []struct {
+		severity string
+		expected []string
+	}{
+		{"", []string{"fyi", "hello", "danger"}},
+		{"info", []string{"fyi", "hello", "danger"}},
+		{"warning", []string{"hello", "danger"}},
+		{"critical", []string{"danger"}},
+	} {
+		req, err := http.NewRequest("GET", "/v2/warnings?select=all&severity="+t.severity, nil)
+		c.Assert(err, check.IsNil)
+		rsp := s.syncReq(c, req, nil)
+		ws, ok := rsp.Result.([]*state.Warning)
+		c.Assert(ok, check.Equals, true)
+		var msgs []string
+		for _, w := range ws {
+			msgs = append(msgs, w.String())
+		}
+		c.Check(msgs, check.DeepEquals, t.expected, check.Commentf("severity %q", t.severity))
+	}
+
+	req, err := http.NewRequest("GET", "/v2/warnings?severity=meh", nil)
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, `invalid severity parameter: "meh"`)
+}
@@ -20,6 +20,8 @@
 package daemon
 
 import (
+	"time"
+
 	"github.com/snapcore/snapd/interfaces"
 )
 
@@ -74,6 +76,13 @@ type connectionJSON struct {
 	Gadget    bool                   `json:"gadget,omitempty"`
 	SlotAttrs map[string]interface{} `json:"slot-attrs,omitempty"`
 	PlugAttrs map[string]interface{} `json:"plug-attrs,omitempty"`
+	// AutoReason explains why the slot was chosen among several
+	// auto-connection candidates
+	AutoReason string `json:"auto-reason,omitempty"`
+	// PlugLastUsed and SlotLastUsed are when an app or hook bound to
+	// the plug or the slot was last seen running
+	PlugLastUsed *time.Time `json:"plug-last-used,omitempty"`
+	SlotLastUsed *time.Time `json:"slot-last-used,omitempty"`
 }
 
 // legacyConnectionsJSON aids in marshaling legacy connections into JSON.
@@ -0,0 +1,104 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/devicestate"
+)
+
+var kernelModulesCmd = &Command{
+	Path:        "/v2/kernel-modules",
+	GET:         getKernelModules,
+	POST:        postKernelModules,
+	ReadAccess:  openAccess{},
+	WriteAccess: rootAccess{},
+}
+
+var (
+	devicestateKernelModules            = devicestate.KernelModules
+	devicestateSetKernelModulesDisabled = devicestate.SetKernelModulesDisabled
+)
+
+func getKernelModules(c *Command, r *http.Request, user *auth.UserState) Response {
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	modules, err := devicestateKernelModules(st)
+	if err != nil {
+		if errors.Is(err, devicestate.ErrNoKernelSnap) {
+			return BadRequest("cannot list kernel modules: %v", err)
+		}
+		return InternalError("cannot list kernel modules: %v", err)
+	}
+
+	result := make([]client.KernelModule, 0, len(modules))
+	for _, mod := range modules {
+		result = append(result, client.KernelModule{
+			Name:      mod.Name,
+			Component: mod.Component,
+			Disabled:  mod.Disabled,
+			LoadedBy:  mod.LoadedBy,
+		})
+	}
+	return SyncResponse(result)
+}
+
+func postKernelModules(c *Command, r *http.Request, user *auth.UserState) Response {
+	var action client.KernelModulesAction
+	dec := json.NewDecoder(r.Body)
+	if err := dec.Decode(&action); err != nil {
+		return BadRequest("cannot decode request body into kernel modules action: %v", err)
+	}
+	if dec.More() {
+		return BadRequest("spurious content after kernel modules action")
+	}
+
+	var disabled bool
+	switch action.Action {
+	case "enable":
+	case "disable":
+		disabled = true
+	default:
+		return BadRequest("unknown kernel modules action %q", action.Action)
+	}
+	if len(action.Modules) == 0 {
+		return BadRequest("no kernel modules to %s", action.Action)
+	}
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	if err := devicestateSetKernelModulesDisabled(st, action.Modules, disabled); err != nil {
+		var kmErr *devicestate.KernelModuleError
+		if errors.Is(err, devicestate.ErrNoKernelSnap) || errors.As(err, &kmErr) {
+			return BadRequest("cannot %s kernel modules: %v", action.Action, err)
+		}
+		return InternalError("cannot %s kernel modules: %v", action.Action, err)
+	}
+	return SyncResponse(nil)
+}
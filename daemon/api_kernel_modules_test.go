@@ -0,0 +1,140 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+var _ = check.Suite(&apiKernelModulesSuite{})
+
+type apiKernelModulesSuite struct {
+	apiBaseSuite
+}
+
+func (s *apiKernelModulesSuite) SetUpTest(c *check.C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectReadAccess(daemon.OpenAccess{})
+	s.expectWriteAccess(daemon.RootAccess{})
+}
+
+func (s *apiKernelModulesSuite) TestListKernelModules(c *check.C) {
+	s.daemon(c)
+	s.AddCleanup(daemon.MockDevicestateKernelModules(func(st *state.State) ([]*devicestate.KernelModule, error) {
+		return []*devicestate.KernelModule{
+			{Name: "btrfs", LoadedBy: []string{"foo"}},
+			{Name: "iwlwifi", Component: "wifi", Disabled: true},
+		}, nil
+	}))
+
+	req, err := http.NewRequest("GET", "/v2/kernel-modules", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.DeepEquals, []client.KernelModule{
+		{Name: "btrfs", LoadedBy: []string{"foo"}},
+		{Name: "iwlwifi", Component: "wifi", Disabled: true},
+	})
+}
+
+func (s *apiKernelModulesSuite) TestListKernelModulesErrors(c *check.C) {
+	s.daemon(c)
+
+	var listErr error
+	s.AddCleanup(daemon.MockDevicestateKernelModules(func(st *state.State) ([]*devicestate.KernelModule, error) {
+		return nil, listErr
+	}))
+
+	for _, t := range []struct {
+		err    error
+		status int
+		msg    string
+	}{
+		{devicestate.ErrNoKernelSnap, 400, "cannot list kernel modules: device has no kernel snap"},
+		{errors.New("boom"), 500, "cannot list kernel modules: boom"},
+	} {
+		listErr = t.err
+		req, err := http.NewRequest("GET", "/v2/kernel-modules", nil)
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, t.status)
+		c.Check(rspe.Message, check.Equals, t.msg)
+	}
+}
+
+func (s *apiKernelModulesSuite) TestDisableEnableKernelModules(c *check.C) {
+	s.daemon(c)
+
+	var calls []string
+	s.AddCleanup(daemon.MockDevicestateSetKernelModulesDisabled(func(st *state.State, names []string, disabled bool) error {
+		calls = append(calls, fmt.Sprintf("%v %v", names, disabled))
+		return nil
+	}))
+
+	for _, action := range []string{"disable", "enable"} {
+		body := fmt.Sprintf(`{"action": %q, "modules": ["btrfs", "e1000e"]}`, action)
+		req, err := http.NewRequest("POST", "/v2/kernel-modules", bytes.NewBufferString(body))
+		c.Assert(err, check.IsNil)
+		rsp := s.syncReq(c, req, nil)
+		c.Check(rsp.Result, check.IsNil)
+	}
+	c.Check(calls, check.DeepEquals, []string{"[btrfs e1000e] true", "[btrfs e1000e] false"})
+}
+
+func (s *apiKernelModulesSuite) TestPostKernelModulesErrors(c *check.C) {
+	s.daemon(c)
+
+	var setErr error
+	s.AddCleanup(daemon.MockDevicestateSetKernelModulesDisabled(func(st *state.State, names []string, disabled bool) error {
+		return setErr
+	}))
+
+	for _, t := range []struct {
+		body   string
+		err    error
+		status int
+		msg    string
+	}{
+		{`{"action": "frobnicate", "modules": ["btrfs"]}`, nil, 400, `unknown kernel modules action "frobnicate"`},
+		{`{"action": "disable"}`, nil, 400, `no kernel modules to disable`},
+		{`{"action": "disable", "modules": ["btrfs"]}{}`, nil, 400, `spurious content after kernel modules action`},
+		{`not json`, nil, 400, `cannot decode request body into kernel modules action: .*`},
+		{`{"action": "disable", "modules": ["nvidia"]}`, &devicestate.KernelModuleError{Module: "nvidia", Message: "no nvidia here"}, 400, `cannot disable kernel modules: no nvidia here`},
+		{`{"action": "enable", "modules": ["btrfs"]}`, devicestate.ErrNoKernelSnap, 400, `cannot enable kernel modules: device has no kernel snap`},
+		{`{"action": "enable", "modules": ["btrfs"]}`, errors.New("boom"), 500, `cannot enable kernel modules: boom`},
+	} {
+		setErr = t.err
+		req, err := http.NewRequest("POST", "/v2/kernel-modules", bytes.NewBufferString(t.body))
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, t.status, check.Commentf(t.body))
+		c.Check(rspe.Message, check.Matches, t.msg, check.Commentf(t.body))
+	}
+}
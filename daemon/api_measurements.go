@@ -0,0 +1,46 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"net/http"
+
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/measurestate"
+	"github.com/snapcore/snapd/strutil"
+)
+
+var measurementsCmd = &Command{
+	Path:       "/v2/measurements",
+	GET:        getMeasurements,
+	ReadAccess: rootAccess{},
+}
+
+func getMeasurements(c *Command, r *http.Request, user *auth.UserState) Response {
+	names := strutil.CommaSeparatedList(r.URL.Query().Get("snaps"))
+	entries, err := measurestate.Measurements(names)
+	if err != nil {
+		return InternalError("cannot list measurements: %v", err)
+	}
+	if entries == nil {
+		entries = []*measurestate.Entry{}
+	}
+	return SyncResponse(entries)
+}
@@ -0,0 +1,100 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/measurestate"
+	"github.com/snapcore/snapd/snap"
+)
+
+var _ = Suite(&measurementsSuite{})
+
+type measurementsSuite struct {
+	apiBaseSuite
+}
+
+func (s *measurementsSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectRootAccess()
+}
+
+func (s *measurementsSuite) TestGetMeasurements(c *C) {
+	s.daemon(c)
+
+	t0 := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	entry1 := &measurestate.Entry{
+		Time:     t0,
+		Kind:     measurestate.KindSnapBlob,
+		Snap:     "foo",
+		Revision: snap.R(7),
+		Path:     "/var/lib/snapd/snaps/foo_7.snap",
+		Digest:   "sha256:1234",
+		PCR:      23,
+	}
+	entry2 := &measurestate.Entry{
+		Time:     t0,
+		Kind:     measurestate.KindSecurityProfile,
+		Snap:     "bar",
+		Revision: snap.R(3),
+		Path:     "/var/lib/snapd/apparmor/profiles/snap.bar.app",
+		Digest:   "sha256:5678",
+		PCR:      23,
+	}
+	c.Assert(os.MkdirAll(filepath.Dir(dirs.SnapMeasurementsLog), 0755), IsNil)
+	var data []byte
+	for _, entry := range []*measurestate.Entry{entry1, entry2} {
+		line, err := json.Marshal(entry)
+		c.Assert(err, IsNil)
+		data = append(data, append(line, '\n')...)
+	}
+	c.Assert(os.WriteFile(dirs.SnapMeasurementsLog, data, 0600), IsNil)
+
+	req, err := http.NewRequest("GET", "/v2/measurements", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Assert(rsp.Status, Equals, 200)
+	c.Check(rsp.Result, DeepEquals, []*measurestate.Entry{entry1, entry2})
+
+	req, err = http.NewRequest("GET", "/v2/measurements?snaps=bar", nil)
+	c.Assert(err, IsNil)
+	rsp = s.syncReq(c, req, nil)
+	c.Assert(rsp.Status, Equals, 200)
+	c.Check(rsp.Result, DeepEquals, []*measurestate.Entry{entry2})
+}
+
+func (s *measurementsSuite) TestGetMeasurementsNone(c *C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/measurements", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Assert(rsp.Status, Equals, 200)
+	c.Check(rsp.Result, DeepEquals, []*measurestate.Entry{})
+}
@@ -60,10 +60,22 @@ var (
 var (
 	devicestateRemodel = devicestate.Remodel
 	sideloadSnapsInfo  = sideloadInfo
+
+	devicestateReissueModel        = devicestate.ReissueModel
+	devicestatePreviewModelReissue = devicestate.PreviewModelReissue
 )
 
 type postModelData struct {
 	NewModel string `json:"new-model"`
+	// Action is empty for a remodel, "reissue" to accept a reissue of
+	// the current model or "preview-reissue" to only compare it with
+	// the current model
+	Action string `json:"action,omitempty"`
+}
+
+type modelReissuePreview struct {
+	Differences []devicestate.ModelDifference `json:"differences"`
+	Error       string                        `json:"error,omitempty"`
 }
 
 func postModel(c *Command, r *http.Request, _ *auth.UserState) Response {
@@ -116,15 +128,48 @@ func storeRemodel(c *Command, r *http.Request) Response {
 	st.Lock()
 	defer st.Unlock()
 
-	chg, err := devicestateRemodel(st, newModel, nil, nil)
-	if err != nil {
-		return BadRequest("cannot remodel device: %v", err)
+	var chg *state.Change
+	switch data.Action {
+	case "":
+		chg, err = devicestateRemodel(st, newModel, nil, nil)
+		if err != nil {
+			return BadRequest("cannot remodel device: %v", err)
+		}
+	case "reissue":
+		chg, err = devicestateReissueModel(st, newModel)
+		if err != nil {
+			return BadRequest("cannot reissue model: %v", err)
+		}
+	case "preview-reissue":
+		return previewModelReissue(st, newModel)
+	default:
+		return BadRequest("unknown model action %q", data.Action)
 	}
 	ensureStateSoon(st)
 
 	return AsyncResponse(nil, chg.ID())
 }
 
+func previewModelReissue(st *state.State, newModel *asserts.Model) Response {
+	preview, err := devicestatePreviewModelReissue(st, newModel)
+	if errors.Is(err, state.ErrNoState) {
+		return BadRequest("cannot preview model reissue: no model assertion yet")
+	}
+	if err != nil {
+		return InternalError("cannot preview model reissue: %v", err)
+	}
+	rsp := modelReissuePreview{
+		Differences: preview.Differences,
+	}
+	if rsp.Differences == nil {
+		rsp.Differences = []devicestate.ModelDifference{}
+	}
+	if preview.Err != nil {
+		rsp.Error = preview.Err.Error()
+	}
+	return SyncResponse(rsp)
+}
+
 func readOfflineRemodelForm(form *Form) (*asserts.Model, []*uploadedSnap, *asserts.Batch, *apiError) {
 	// New model
 	model := form.Values["new-model"]
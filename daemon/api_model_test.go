@@ -157,6 +157,158 @@ func (s *modelSuite) TestPostRemodel(c *check.C) {
 	c.Assert(soon, check.Equals, 1)
 }
 
+func (s *modelSuite) TestPostModelReissue(c *check.C) {
+	s.expectRootAccess()
+
+	newModel := s.Brands.Model("my-brand", "my-model", modelDefaults, map[string]interface{}{
+		"revision": "2",
+	})
+
+	d := s.daemonWithOverlordMockAndStore()
+	st := d.Overlord().State()
+
+	soon := 0
+	var origEnsureStateSoon func(*state.State)
+	origEnsureStateSoon, restore := daemon.MockEnsureStateSoon(func(st *state.State) {
+		soon++
+		origEnsureStateSoon(st)
+	})
+	defer restore()
+
+	var gotModel *asserts.Model
+	defer daemon.MockDevicestateReissueModel(func(st *state.State, nm *asserts.Model) (*state.Change, error) {
+		gotModel = nm
+		return st.NewChange("reissue-model", "..."), nil
+	})()
+	defer daemon.MockDevicestateRemodel(func(st *state.State, nm *asserts.Model, sis []*snap.SideInfo, paths []string) (*state.Change, error) {
+		c.Fatalf("unexpected remodel")
+		return nil, nil
+	})()
+
+	data, err := json.Marshal(daemon.PostModelData{
+		NewModel: string(asserts.Encode(newModel)),
+		Action:   "reissue",
+	})
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest("POST", "/v2/model", bytes.NewBuffer(data))
+	c.Assert(err, check.IsNil)
+	rsp := s.asyncReq(c, req, nil)
+	c.Assert(rsp.Status, check.Equals, 202)
+	c.Check(gotModel, check.DeepEquals, newModel)
+
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	c.Assert(chg, check.NotNil)
+	c.Check(chg.Kind(), check.Equals, "reissue-model")
+	c.Check(soon, check.Equals, 1)
+}
+
+func (s *modelSuite) TestPostModelReissueError(c *check.C) {
+	s.expectRootAccess()
+
+	newModel := s.Brands.Model("my-brand", "my-model", modelDefaults)
+
+	s.daemonWithOverlordMockAndStore()
+	defer daemon.MockDevicestateReissueModel(func(st *state.State, nm *asserts.Model) (*state.Change, error) {
+		return nil, errors.New("boom")
+	})()
+
+	data, err := json.Marshal(daemon.PostModelData{
+		NewModel: string(asserts.Encode(newModel)),
+		Action:   "reissue",
+	})
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest("POST", "/v2/model", bytes.NewBuffer(data))
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, "cannot reissue model: boom")
+}
+
+func (s *modelSuite) TestPostModelPreviewReissue(c *check.C) {
+	s.expectRootAccess()
+
+	oldModel := s.Brands.Model("my-brand", "my-model", modelDefaults)
+	newModel := s.Brands.Model("my-brand", "my-model", modelDefaults, map[string]interface{}{
+		"revision":     "2",
+		"display-name": "My Model",
+	})
+	otherModel := s.Brands.Model("my-brand", "my-model", modelDefaults, map[string]interface{}{
+		"revision": "2",
+		"kernel":   "other-kernel",
+	})
+
+	d := s.daemonWithOverlordMockAndStore()
+	st := d.Overlord().State()
+	st.Lock()
+	assertstatetest.AddMany(st, s.StoreSigning.StoreAccountKey(""))
+	assertstatetest.AddMany(st, s.Brands.AccountsAndKeys("my-brand")...)
+	s.mockModel(st, oldModel)
+	st.Unlock()
+
+	for _, tc := range []struct {
+		model    *asserts.Model
+		expected map[string]interface{}
+	}{{
+		model: newModel,
+		expected: map[string]interface{}{
+			"differences": []interface{}{
+				map[string]interface{}{"header": "display-name", "new": "My Model"},
+				map[string]interface{}{"header": "revision", "new": "2"},
+			},
+		},
+	}, {
+		model: otherModel,
+		expected: map[string]interface{}{
+			"differences": []interface{}{
+				map[string]interface{}{"header": "kernel", "old": "kernel", "new": "other-kernel"},
+				map[string]interface{}{"header": "revision", "new": "2"},
+			},
+			"error": `cannot reissue model my-brand/my-model with a different "kernel" header, a remodel is required`,
+		},
+	}} {
+		data, err := json.Marshal(daemon.PostModelData{
+			NewModel: string(asserts.Encode(tc.model)),
+			Action:   "preview-reissue",
+		})
+		c.Assert(err, check.IsNil)
+		req, err := http.NewRequest("POST", "/v2/model", bytes.NewBuffer(data))
+		c.Assert(err, check.IsNil)
+		rsp := s.syncReq(c, req, nil)
+		c.Check(rsp.Status, check.Equals, 200)
+
+		// check what the client sees
+		var result map[string]interface{}
+		b, err := json.Marshal(rsp.Result)
+		c.Assert(err, check.IsNil)
+		c.Assert(json.Unmarshal(b, &result), check.IsNil)
+		c.Check(result, check.DeepEquals, tc.expected)
+	}
+
+	st.Lock()
+	defer st.Unlock()
+	c.Check(st.Changes(), check.HasLen, 0)
+}
+
+func (s *modelSuite) TestPostModelUnknownAction(c *check.C) {
+	s.expectRootAccess()
+
+	newModel := s.Brands.Model("my-brand", "my-model", modelDefaults)
+	s.daemonWithOverlordMockAndStore()
+
+	data, err := json.Marshal(daemon.PostModelData{
+		NewModel: string(asserts.Encode(newModel)),
+		Action:   "foo",
+	})
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest("POST", "/v2/model", bytes.NewBuffer(data))
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, `unknown model action "foo"`)
+}
+
 func (s *modelSuite) TestPostRemodelWrongBody(c *check.C) {
 	s.expectRootAccess()
 
@@ -85,6 +85,8 @@ func getNotices(c *Command, r *http.Request, user *auth.UserState) Response {
 
 	keys := strutil.MultiCommaSeparatedList(query["keys"])
 
+	snaps := strutil.MultiCommaSeparatedList(query["snaps"])
+
 	after, err := parseOptionalTime(query.Get("after"))
 	if err != nil {
 		return BadRequest(`invalid "after" timestamp: %v`, err)
@@ -94,6 +96,7 @@ func getNotices(c *Command, r *http.Request, user *auth.UserState) Response {
 		UserID: userID,
 		Types:  types,
 		Keys:   keys,
+		Snaps:  snaps,
 		After:  after,
 	}
 
@@ -175,6 +175,36 @@ func (s *noticesSuite) TestNoticesFilterMultipleKeys(c *C) {
 	c.Assert(n["key"], Equals, "danger")
 }
 
+func (s *noticesSuite) TestNoticesFilterSnaps(c *C) {
+	s.daemon(c)
+
+	st := s.d.Overlord().State()
+	st.Lock()
+	addNotice(c, st, nil, state.SnapNotice, "foo/ready", nil)
+	time.Sleep(time.Microsecond)
+	addNotice(c, st, nil, state.SnapNotice, "bar/ready", nil)
+	time.Sleep(time.Microsecond)
+	addNotice(c, st, nil, state.WarningNotice, "danger", nil)
+	time.Sleep(time.Microsecond)
+	addNotice(c, st, nil, state.SnapNotice, "baz/done", nil)
+	st.Unlock()
+
+	req, err := http.NewRequest("GET", "/v2/notices?types=snap&snaps=foo,baz", nil)
+	c.Assert(err, IsNil)
+	req.RemoteAddr = "pid=100;uid=1000;socket=;"
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Status, Equals, 200)
+
+	notices, ok := rsp.Result.([]*state.Notice)
+	c.Assert(ok, Equals, true)
+	c.Assert(notices, HasLen, 2)
+	n := noticeToMap(c, notices[0])
+	c.Assert(n["type"], Equals, "snap")
+	c.Assert(n["key"], Equals, "foo/ready")
+	n = noticeToMap(c, notices[1])
+	c.Assert(n["key"], Equals, "baz/done")
+}
+
 func (s *noticesSuite) TestNoticesFilterInvalidTypes(c *C) {
 	s.daemon(c)
 
@@ -0,0 +1,62 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/reportstate"
+)
+
+var (
+	problemReportsCmd = &Command{
+		Path:       "/v2/problem-reports",
+		GET:        getProblemReports,
+		ReadAccess: rootAccess{},
+	}
+
+	problemReportCmd = &Command{
+		Path:       "/v2/problem-reports/{id}",
+		GET:        getProblemReport,
+		ReadAccess: rootAccess{},
+	}
+)
+
+func getProblemReports(c *Command, r *http.Request, user *auth.UserState) Response {
+	reports, err := reportstate.Reports()
+	if err != nil {
+		return InternalError("cannot list problem reports: %v", err)
+	}
+	return SyncResponse(reports)
+}
+
+func getProblemReport(c *Command, r *http.Request, user *auth.UserState) Response {
+	id := muxVars(r)["id"]
+	report, err := reportstate.ReportByID(id)
+	if err != nil {
+		if errors.Is(err, reportstate.ErrReportNotFound) {
+			return NotFound("cannot find problem report with id %q", id)
+		}
+		return InternalError("cannot export problem report: %v", err)
+	}
+	return SyncResponse(report)
+}
@@ -0,0 +1,137 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/overlord/reportstate"
+)
+
+var _ = Suite(&problemReportsSuite{})
+
+type problemReportsSuite struct {
+	apiBaseSuite
+}
+
+func (s *problemReportsSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectRootAccess()
+}
+
+func (s *problemReportsSuite) mockReports(c *C, reports ...*reportstate.Report) {
+	c.Assert(os.MkdirAll(dirs.SnapProblemReportsDir, 0700), IsNil)
+	for _, report := range reports {
+		data, err := json.Marshal(report)
+		c.Assert(err, IsNil)
+		err = os.WriteFile(filepath.Join(dirs.SnapProblemReportsDir, report.ID+".json"), data, 0600)
+		c.Assert(err, IsNil)
+	}
+}
+
+func (s *problemReportsSuite) TestGetProblemReports(c *C) {
+	s.daemon(c)
+
+	t0 := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	report1 := &reportstate.Report{
+		ID:   "2",
+		Time: t0,
+		Change: reportstate.ChangeInfo{
+			ID:      "2",
+			Kind:    "install-snap",
+			Summary: `Install "foo" snap`,
+			Status:  "Error",
+		},
+	}
+	report2 := &reportstate.Report{
+		ID:   "1",
+		Time: t0.Add(time.Hour),
+		Change: reportstate.ChangeInfo{
+			ID:      "1",
+			Kind:    "refresh-snap",
+			Summary: `Refresh "foo" snap`,
+			Status:  "Error",
+		},
+	}
+	s.mockReports(c, report1, report2)
+
+	req, err := http.NewRequest("GET", "/v2/problem-reports", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Assert(rsp.Status, Equals, 200)
+	c.Check(rsp.Result, DeepEquals, []*reportstate.Report{report1, report2})
+}
+
+func (s *problemReportsSuite) TestGetProblemReportsNone(c *C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/problem-reports", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Assert(rsp.Status, Equals, 200)
+	c.Check(rsp.Result, HasLen, 0)
+}
+
+func (s *problemReportsSuite) TestGetProblemReport(c *C) {
+	s.daemon(c)
+
+	report := &reportstate.Report{
+		ID:   "7",
+		Time: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		Change: reportstate.ChangeInfo{
+			ID:     "7",
+			Kind:   "install-snap",
+			Status: "Error",
+			Tasks: []reportstate.TaskInfo{
+				{Kind: "link-snap", Status: "Error", Log: []string{"ERROR cannot link snap"}},
+			},
+		},
+		Denials: []*denials.Summary{{
+			Denial: denials.Denial{Kind: "apparmor", Snap: "foo", Operation: "open", Target: "/etc/shadow"},
+			Count:  1,
+		}},
+	}
+	s.mockReports(c, report)
+
+	req, err := http.NewRequest("GET", "/v2/problem-reports/7", nil)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Assert(rsp.Status, Equals, 200)
+	c.Check(rsp.Result, DeepEquals, report)
+}
+
+func (s *problemReportsSuite) TestGetProblemReportNotFound(c *C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/problem-reports/42", nil)
+	c.Assert(err, IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 404)
+	c.Check(rspe.Message, Equals, `cannot find problem report with id "42"`)
+}
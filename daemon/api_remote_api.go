@@ -0,0 +1,119 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/overlord/auth"
+)
+
+var remoteAPITokensCmd = &Command{
+	Path:        "/v2/remote-api/tokens",
+	GET:         getRemoteAPITokens,
+	POST:        postRemoteAPITokens,
+	ReadAccess:  rootAccess{},
+	WriteAccess: rootAccess{},
+}
+
+func clientRemoteAPIToken(tok *remoteAPIToken) client.RemoteAPIToken {
+	return client.RemoteAPIToken{
+		ID:      tok.ID,
+		Scope:   tok.Scope,
+		Created: tok.Created,
+	}
+}
+
+func getRemoteAPITokens(c *Command, r *http.Request, user *auth.UserState) Response {
+	if r.TLS != nil {
+		// tokens can only be managed locally
+		return Forbidden("access denied")
+	}
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	tokens, err := remoteAPITokens(st)
+	if err != nil {
+		return InternalError("cannot list remote API tokens: %v", err)
+	}
+	res := make([]client.RemoteAPIToken, 0, len(tokens))
+	for _, tok := range tokens {
+		res = append(res, clientRemoteAPIToken(tok))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+	return SyncResponse(res)
+}
+
+type remoteAPITokenAction struct {
+	Action string `json:"action"`
+	Scope  string `json:"scope"`
+	ID     string `json:"id"`
+}
+
+func postRemoteAPITokens(c *Command, r *http.Request, user *auth.UserState) Response {
+	if r.TLS != nil {
+		// tokens can only be managed locally
+		return Forbidden("access denied")
+	}
+
+	var action remoteAPITokenAction
+	dec := json.NewDecoder(r.Body)
+	if err := dec.Decode(&action); err != nil {
+		return BadRequest("cannot decode request body into remote API token action: %v", err)
+	}
+	if dec.More() {
+		return BadRequest("spurious content after remote API token action")
+	}
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	switch action.Action {
+	case "create":
+		switch action.Scope {
+		case remoteAPIScopeRead, remoteAPIScopeManage:
+			// valid
+		default:
+			return BadRequest("invalid remote API token scope %q", action.Scope)
+		}
+		tok, secret, err := addRemoteAPIToken(st, action.Scope)
+		if err != nil {
+			return InternalError("cannot create remote API token: %v", err)
+		}
+		res := clientRemoteAPIToken(tok)
+		res.Token = secret
+		return SyncResponse(res)
+	case "revoke":
+		if err := removeRemoteAPIToken(st, action.ID); err != nil {
+			return NotFound(err.Error())
+		}
+		return SyncResponse(nil)
+	default:
+		return BadRequest("unknown remote API token action %q", action.Action)
+	}
+}
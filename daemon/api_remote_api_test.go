@@ -0,0 +1,326 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+)
+
+var _ = check.Suite(&remoteAPISuite{})
+
+type remoteAPISuite struct {
+	apiBaseSuite
+}
+
+func (s *remoteAPISuite) SetUpTest(c *check.C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectRootAccess()
+}
+
+func (s *remoteAPISuite) TestCreateListRevokeTokens(c *check.C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("POST", "/v2/remote-api/tokens", bytes.NewBufferString(`{"action": "create", "scope": "read"}`))
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	created := rsp.Result.(client.RemoteAPIToken)
+	c.Check(created.ID, check.Not(check.Equals), "")
+	c.Check(created.Scope, check.Equals, "read")
+	c.Check(created.Token, check.Not(check.Equals), "")
+
+	req, err = http.NewRequest("GET", "/v2/remote-api/tokens", nil)
+	c.Assert(err, check.IsNil)
+	rsp = s.syncReq(c, req, nil)
+	tokens := rsp.Result.([]client.RemoteAPIToken)
+	c.Assert(tokens, check.HasLen, 1)
+	c.Check(tokens[0].ID, check.Equals, created.ID)
+	c.Check(tokens[0].Scope, check.Equals, "read")
+	c.Check(tokens[0].Created.Equal(created.Created), check.Equals, true)
+	// the secret is not listed
+	c.Check(tokens[0].Token, check.Equals, "")
+
+	req, err = http.NewRequest("POST", "/v2/remote-api/tokens", bytes.NewBufferString(`{"action": "revoke", "id": "`+created.ID+`"}`))
+	c.Assert(err, check.IsNil)
+	s.syncReq(c, req, nil)
+
+	req, err = http.NewRequest("GET", "/v2/remote-api/tokens", nil)
+	c.Assert(err, check.IsNil)
+	rsp = s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.HasLen, 0)
+}
+
+func (s *remoteAPISuite) TestTokensErrors(c *check.C) {
+	s.daemon(c)
+
+	for _, t := range []struct {
+		body   string
+		status int
+		msg    string
+	}{
+		{`{"action": "create", "scope": "all"}`, 400, `invalid remote API token scope "all"`},
+		{`{"action": "frobnicate"}`, 400, `unknown remote API token action "frobnicate"`},
+		{`{"action": "revoke", "id": "missing"}`, 404, `remote API token "missing" not found`},
+		{`{"action": "create"}{}`, 400, `spurious content after remote API token action`},
+	} {
+		req, err := http.NewRequest("POST", "/v2/remote-api/tokens", bytes.NewBufferString(t.body))
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, t.status, check.Commentf(t.body))
+		c.Check(rspe.Message, check.Equals, t.msg, check.Commentf(t.body))
+	}
+}
+
+func (s *remoteAPISuite) TestTokensNotManagedRemotely(c *check.C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/remote-api/tokens", nil)
+	c.Assert(err, check.IsNil)
+	req.TLS = &tls.ConnectionState{}
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 403)
+}
+
+func (s *remoteAPISuite) remoteRequest(c *check.C, method, token string) *http.Request {
+	return s.remoteRequestTo(c, method, "/v2/snaps", token)
+}
+
+func (s *remoteAPISuite) remoteRequestTo(c *check.C, method, path, token string) *http.Request {
+	req, err := http.NewRequest(method, path, nil)
+	c.Assert(err, check.IsNil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func (s *remoteAPISuite) TestRemoteAccess(c *check.C) {
+	d := s.daemon(c)
+
+	st := d.Overlord().State()
+	st.Lock()
+	readToken, err := daemon.AddRemoteAPIToken(st, "read")
+	c.Assert(err, check.IsNil)
+	manageToken, err := daemon.AddRemoteAPIToken(st, "manage")
+	c.Assert(err, check.IsNil)
+	st.Unlock()
+
+	ac := daemon.NewRemoteAccess(daemon.AuthenticatedAccess{})
+
+	// read tokens only allow GET requests
+	c.Check(ac.CheckAccess(d, s.remoteRequest(c, "GET", readToken), nil, nil), check.IsNil)
+	c.Check(ac.CheckAccess(d, s.remoteRequest(c, "POST", readToken), nil, nil), check.DeepEquals, daemon.Forbidden("access denied"))
+	// manage tokens allow all requests
+	c.Check(ac.CheckAccess(d, s.remoteRequest(c, "GET", manageToken), nil, nil), check.IsNil)
+	c.Check(ac.CheckAccess(d, s.remoteRequest(c, "POST", manageToken), nil, nil), check.IsNil)
+
+	// missing or unknown tokens
+	c.Check(ac.CheckAccess(d, s.remoteRequest(c, "GET", ""), nil, nil), check.DeepEquals, daemon.Unauthorized("access denied"))
+	c.Check(ac.CheckAccess(d, s.remoteRequest(c, "GET", "unknown"), nil, nil), check.DeepEquals, daemon.Unauthorized("access denied"))
+
+	// no verified client certificate
+	req := s.remoteRequest(c, "GET", manageToken)
+	req.TLS.VerifiedChains = nil
+	c.Check(ac.CheckAccess(d, req, nil, nil), check.DeepEquals, daemon.Forbidden("access denied"))
+
+	// root only APIs require a manage token, even for reading
+	ac = daemon.NewRemoteAccess(daemon.RootAccess{})
+	c.Check(ac.CheckAccess(d, s.remoteRequest(c, "GET", readToken), nil, nil), check.DeepEquals, daemon.Forbidden("access denied"))
+	c.Check(ac.CheckAccess(d, s.remoteRequest(c, "GET", manageToken), nil, nil), check.IsNil)
+
+	// snap only APIs are never served remotely
+	ac = daemon.NewRemoteAccess(daemon.SnapAccess{})
+	c.Check(ac.CheckAccess(d, s.remoteRequest(c, "GET", manageToken), nil, nil), check.DeepEquals, daemon.Forbidden("access denied"))
+}
+
+func (s *remoteAPISuite) TestServeHTTPRemoteRequests(c *check.C) {
+	d := s.daemon(c)
+
+	st := d.Overlord().State()
+	st.Lock()
+	readToken, err := daemon.AddRemoteAPIToken(st, "read")
+	c.Assert(err, check.IsNil)
+	st.Unlock()
+
+	rec := httptest.NewRecorder()
+	s.serveHTTP(c, rec, s.remoteRequest(c, "GET", ""))
+	c.Check(rec.Code, check.Equals, 401)
+
+	rec = httptest.NewRecorder()
+	s.serveHTTP(c, rec, s.remoteRequest(c, "GET", readToken))
+	c.Check(rec.Code, check.Equals, 200)
+
+	rec = httptest.NewRecorder()
+	s.serveHTTP(c, rec, s.remoteRequest(c, "POST", readToken))
+	c.Check(rec.Code, check.Equals, 403)
+}
+
+func (s *remoteAPISuite) TestServeHTTPRemoteReadTokenRootOnly(c *check.C) {
+	d := s.daemon(c)
+
+	st := d.Overlord().State()
+	st.Lock()
+	readToken, err := daemon.AddRemoteAPIToken(st, "read")
+	c.Assert(err, check.IsNil)
+	st.Unlock()
+
+	for _, path := range []string{"/v2/system-recovery-keys", "/v2/problem-reports"} {
+		rec := httptest.NewRecorder()
+		s.serveHTTP(c, rec, s.remoteRequestTo(c, "GET", path, readToken))
+		c.Check(rec.Code, check.Equals, 403, check.Commentf(path))
+	}
+}
+
+func (s *remoteAPISuite) setListenAddress(c *check.C, d *daemon.Daemon, addr string) {
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	tr := config.NewTransaction(st)
+	c.Assert(tr.Set("core", "remote-api.listen-address", addr), check.IsNil)
+	tr.Commit()
+}
+
+func (s *remoteAPISuite) mockGradedModel(d *daemon.Daemon) {
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	s.mockModel(st, s.Brands.Model("can0nical", "pc-20", map[string]interface{}{
+		"architecture": "amd64",
+		"grade":        "signed",
+		"base":         "core20",
+		"snaps": []interface{}{
+			map[string]interface{}{
+				"name":            "pc-kernel",
+				"id":              "pckernelidididididididididididid",
+				"type":            "kernel",
+				"default-channel": "20",
+			},
+			map[string]interface{}{
+				"name":            "pc",
+				"id":              "pcididididididididididididididid",
+				"type":            "gadget",
+				"default-channel": "20",
+			},
+		},
+	}))
+}
+
+func writeTestCertificate(c *check.C, certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	c.Assert(err, check.IsNil)
+	c.Assert(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644), check.IsNil)
+	if keyFile != "" {
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		c.Assert(err, check.IsNil)
+		c.Assert(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600), check.IsNil)
+	}
+}
+
+func (s *remoteAPISuite) TestInitRemoteAPIDisabledByDefault(c *check.C) {
+	d := s.daemon(c)
+	defer daemon.MockRemoteAPIListen(func(network, addr string) (net.Listener, error) {
+		c.Fatal("unexpected listen")
+		return nil, nil
+	})()
+
+	c.Assert(d.InitRemoteAPI(), check.IsNil)
+	c.Check(d.RemoteListener(), check.IsNil)
+}
+
+func (s *remoteAPISuite) TestInitRemoteAPINoGrade(c *check.C) {
+	d := s.daemon(c)
+	s.setListenAddress(c, d, "127.0.0.1:0")
+
+	st := d.Overlord().State()
+	st.Lock()
+	s.mockModel(st, nil)
+	st.Unlock()
+
+	c.Check(d.InitRemoteAPI(), check.ErrorMatches, `model can0nical/pc does not allow the remote API`)
+	c.Check(d.RemoteListener(), check.IsNil)
+}
+
+func (s *remoteAPISuite) TestInitRemoteAPINoCertificates(c *check.C) {
+	d := s.daemon(c)
+	s.setListenAddress(c, d, "127.0.0.1:0")
+	s.mockGradedModel(d)
+
+	c.Check(d.InitRemoteAPI(), check.ErrorMatches, `cannot load server certificate: .*`)
+	c.Check(d.RemoteListener(), check.IsNil)
+}
+
+func (s *remoteAPISuite) TestInitRemoteAPIHappy(c *check.C) {
+	d := s.daemon(c)
+	s.setListenAddress(c, d, "127.0.0.1:0")
+	s.mockGradedModel(d)
+
+	c.Assert(os.MkdirAll(dirs.SnapRemoteAPIDir, 0700), check.IsNil)
+	writeTestCertificate(c, filepath.Join(dirs.SnapRemoteAPIDir, "server.crt"), filepath.Join(dirs.SnapRemoteAPIDir, "server.key"))
+	writeTestCertificate(c, filepath.Join(dirs.SnapRemoteAPIDir, "client-ca.crt"), "")
+
+	var listenAddr string
+	defer daemon.MockRemoteAPIListen(func(network, addr string) (net.Listener, error) {
+		c.Check(network, check.Equals, "tcp")
+		listenAddr = addr
+		return net.Listen(network, addr)
+	})()
+
+	c.Assert(d.InitRemoteAPI(), check.IsNil)
+	c.Assert(d.RemoteListener(), check.NotNil)
+	defer d.RemoteListener().Close()
+	c.Check(listenAddr, check.Equals, "127.0.0.1:0")
+
+	// the remote API keeps the daemon from going into standby
+	opinion, ok := d.RemoteListener().(interface{ CanStandby() bool })
+	c.Assert(ok, check.Equals, true)
+	c.Check(opinion.CanStandby(), check.Equals, false)
+}
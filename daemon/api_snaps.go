@@ -128,9 +128,7 @@ func postSnap(c *Command, r *http.Request, user *auth.UserState) Response {
 	st.Lock()
 	defer st.Unlock()
 
-	if user != nil {
-		inst.userID = user.ID
-	}
+	inst.setRequester(r, user)
 
 	vars := muxVars(r)
 	inst.Snaps = []string{vars["name"]}
@@ -213,10 +211,56 @@ type snapInstruction struct {
 	QuotaGroupName         string                           `json:"quota-group"`
 	Time                   string                           `json:"time"`
 	HoldLevel              string                           `json:"hold-level"`
+	BlockRevision          snap.Revision                    `json:"block-revision"`
+	BlockUntil             string                           `json:"block-until"`
+	Pin                    bool                             `json:"pin"`
 
 	// The fields below should not be unmarshalled into. Do not export them.
 	userID int
 	ctx    context.Context
+
+	// uid and username identify the requester, for auditing
+	uid      uint32
+	username string
+
+	// locale is the locale negotiated with the requester for the
+	// user-facing messages, nil if none
+	locale *i18n.Locale
+}
+
+// setRequester records who requested the instruction.
+func (inst *snapInstruction) setRequester(r *http.Request, user *auth.UserState) {
+	if user != nil {
+		inst.userID = user.ID
+		inst.username = user.Username
+	}
+	inst.uid = ucrednetNobody
+	if ucred, err := ucrednetGet(r.RemoteAddr); err == nil {
+		inst.uid = ucred.Uid
+	}
+	inst.locale = requestLocale(r)
+}
+
+// localize translates a message marked with i18n.N into the locale of the
+// requester.
+func (inst *snapInstruction) localize(msg string) string {
+	return inst.locale.Translate(msg)
+}
+
+// overrideRefreshInhibition records that refreshing the given snaps while
+// ignoring running applications overrides their inhibited pending refresh.
+func (inst *snapInstruction) overrideRefreshInhibition(st *state.State, names []string) error {
+	for _, name := range names {
+		_, err := snapstateOverrideRefreshInhibition(st, &snapstate.RefreshInhibitionOverride{
+			Snap: name,
+			User: inst.username,
+			UID:  inst.uid,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (inst *snapInstruction) revnoOpts() *snapstate.RevisionOptions {
@@ -249,6 +293,9 @@ func (inst *snapInstruction) installFlags() (snapstate.Flags, error) {
 	if inst.Prefer {
 		flags.Prefer = true
 	}
+	if inst.Pin {
+		flags.Pinned = true
+	}
 	flags.QuotaGroupName = inst.QuotaGroupName
 
 	return flags, nil
@@ -359,6 +406,27 @@ func (inst *snapInstruction) validate() error {
 		}
 	}
 
+	if !inst.BlockRevision.Unset() && inst.Action != "refresh" {
+		return errors.New(`block-revision can only be specified for the "refresh" action`)
+	}
+	if inst.BlockUntil != "" {
+		if inst.BlockRevision.Unset() {
+			return errors.New("block-until requires block-revision")
+		}
+		if _, err := time.Parse(time.RFC3339, inst.BlockUntil); err != nil {
+			return fmt.Errorf("block-until must be in RFC3339 format: %v", err)
+		}
+	}
+
+	if inst.Pin {
+		if inst.Action != "install" && inst.Action != "refresh" {
+			return errors.New(`pin can only be specified for the "install" or "refresh" actions`)
+		}
+		if inst.Revision.Unset() {
+			return errors.New("pin requires a revision")
+		}
+	}
+
 	if inst.Unaliased && inst.Prefer {
 		return errUnaliasedPreferConflict
 	}
@@ -411,7 +479,7 @@ func modeFlags(devMode, jailMode, classic bool) (snapstate.Flags, error) {
 
 func snapInstall(inst *snapInstruction, st *state.State) (string, []*state.TaskSet, error) {
 	if len(inst.Snaps[0]) == 0 {
-		return "", nil, fmt.Errorf(i18n.G("cannot install snap with empty name"))
+		return "", nil, errors.New(inst.localize(i18n.N("cannot install snap with empty name")))
 	}
 
 	flags, err := inst.installFlags()
@@ -431,7 +499,7 @@ func snapInstall(inst *snapInstruction, st *state.State) (string, []*state.TaskS
 		return "", nil, err
 	}
 
-	msg := fmt.Sprintf(i18n.G("Install %q snap"), inst.Snaps[0])
+	msg := fmt.Sprintf(inst.localize(i18n.N("Install %q snap")), inst.Snaps[0])
 	if inst.Channel != "stable" && inst.Channel != "" {
 		msg += fmt.Sprintf(" from %q channel", inst.Channel)
 	}
@@ -456,6 +524,21 @@ func snapUpdate(inst *snapInstruction, st *state.State) (string, []*state.TaskSe
 	if inst.Amend {
 		flags.Amend = true
 	}
+	if inst.Pin {
+		flags.Pinned = true
+	}
+
+	if !inst.BlockRevision.Unset() {
+		// the block is kept even if the refresh then finds no update
+		var until time.Time
+		if inst.BlockUntil != "" {
+			// validated already
+			until, _ = time.Parse(time.RFC3339, inst.BlockUntil)
+		}
+		if err := snapstateBlockRevision(st, inst.Snaps[0], inst.BlockRevision, until); err != nil {
+			return "", nil, err
+		}
+	}
 
 	// we need refreshed snap-declarations to enforce refresh-control as best as we can
 	if err = assertstateRefreshSnapAssertions(st, inst.userID, nil); err != nil {
@@ -466,10 +549,15 @@ func snapUpdate(inst *snapInstruction, st *state.State) (string, []*state.TaskSe
 	if err != nil {
 		return "", nil, err
 	}
+	if inst.IgnoreRunning {
+		if err := inst.overrideRefreshInhibition(st, inst.Snaps); err != nil {
+			return "", nil, err
+		}
+	}
 
-	msg := fmt.Sprintf(i18n.G("Refresh %q snap"), inst.Snaps[0])
+	msg := fmt.Sprintf(inst.localize(i18n.N("Refresh %q snap")), inst.Snaps[0])
 	if inst.Channel != "stable" && inst.Channel != "" {
-		msg = fmt.Sprintf(i18n.G("Refresh %q snap from %q channel"), inst.Snaps[0], inst.Channel)
+		msg = fmt.Sprintf(inst.localize(i18n.N("Refresh %q snap from %q channel")), inst.Snaps[0], inst.Channel)
 	}
 
 	return msg, []*state.TaskSet{ts}, nil
@@ -481,7 +569,7 @@ func snapRemove(inst *snapInstruction, st *state.State) (string, []*state.TaskSe
 		return "", nil, err
 	}
 
-	msg := fmt.Sprintf(i18n.G("Remove %q snap"), inst.Snaps[0])
+	msg := fmt.Sprintf(inst.localize(i18n.N("Remove %q snap")), inst.Snaps[0])
 	return msg, []*state.TaskSet{ts}, nil
 }
 
@@ -502,39 +590,52 @@ func snapRevert(inst *snapInstruction, st *state.State) (string, []*state.TaskSe
 		return "", nil, err
 	}
 
-	msg := fmt.Sprintf(i18n.G("Revert %q snap"), inst.Snaps[0])
+	msg := fmt.Sprintf(inst.localize(i18n.N("Revert %q snap")), inst.Snaps[0])
 	return msg, []*state.TaskSet{ts}, nil
 }
 
 func snapEnable(inst *snapInstruction, st *state.State) (string, []*state.TaskSet, error) {
 	if !inst.Revision.Unset() {
-		return "", nil, errors.New("enable takes no revision")
+		return "", nil, errors.New(inst.localize(i18n.N("enable takes no revision")))
 	}
 	ts, err := snapstate.Enable(st, inst.Snaps[0])
 	if err != nil {
 		return "", nil, err
 	}
 
-	msg := fmt.Sprintf(i18n.G("Enable %q snap"), inst.Snaps[0])
+	msg := fmt.Sprintf(inst.localize(i18n.N("Enable %q snap")), inst.Snaps[0])
 	return msg, []*state.TaskSet{ts}, nil
 }
 
 func snapDisable(inst *snapInstruction, st *state.State) (string, []*state.TaskSet, error) {
 	if !inst.Revision.Unset() {
-		return "", nil, errors.New("disable takes no revision")
+		return "", nil, errors.New(inst.localize(i18n.N("disable takes no revision")))
 	}
 	ts, err := snapstate.Disable(st, inst.Snaps[0])
 	if err != nil {
 		return "", nil, err
 	}
 
-	msg := fmt.Sprintf(i18n.G("Disable %q snap"), inst.Snaps[0])
+	msg := fmt.Sprintf(inst.localize(i18n.N("Disable %q snap")), inst.Snaps[0])
+	return msg, []*state.TaskSet{ts}, nil
+}
+
+func snapReleaseQuarantine(inst *snapInstruction, st *state.State) (string, []*state.TaskSet, error) {
+	if !inst.Revision.Unset() {
+		return "", nil, errors.New(inst.localize(i18n.N("release-quarantine takes no revision")))
+	}
+	ts, err := snapstate.ReleaseQuarantine(st, inst.Snaps[0])
+	if err != nil {
+		return "", nil, err
+	}
+
+	msg := fmt.Sprintf(inst.localize(i18n.N("Release %q snap from quarantine")), inst.Snaps[0])
 	return msg, []*state.TaskSet{ts}, nil
 }
 
 func snapSwitch(inst *snapInstruction, st *state.State) (string, []*state.TaskSet, error) {
 	if !inst.Revision.Unset() {
-		return "", nil, errors.New("switch takes no revision")
+		return "", nil, errors.New(inst.localize(i18n.N("switch takes no revision")))
 	}
 	ts, err := snapstateSwitch(st, inst.Snaps[0], inst.revnoOpts())
 	if err != nil {
@@ -544,15 +645,15 @@ func snapSwitch(inst *snapInstruction, st *state.State) (string, []*state.TaskSe
 	var msg string
 	switch {
 	case inst.LeaveCohort && inst.Channel != "":
-		msg = fmt.Sprintf(i18n.G("Switch %q snap to channel %q and away from cohort"), inst.Snaps[0], inst.Channel)
+		msg = fmt.Sprintf(inst.localize(i18n.N("Switch %q snap to channel %q and away from cohort")), inst.Snaps[0], inst.Channel)
 	case inst.LeaveCohort:
-		msg = fmt.Sprintf(i18n.G("Switch %q snap away from cohort"), inst.Snaps[0])
+		msg = fmt.Sprintf(inst.localize(i18n.N("Switch %q snap away from cohort")), inst.Snaps[0])
 	case inst.CohortKey == "" && inst.Channel != "":
-		msg = fmt.Sprintf(i18n.G("Switch %q snap to channel %q"), inst.Snaps[0], inst.Channel)
+		msg = fmt.Sprintf(inst.localize(i18n.N("Switch %q snap to channel %q")), inst.Snaps[0], inst.Channel)
 	case inst.CohortKey != "" && inst.Channel == "":
-		msg = fmt.Sprintf(i18n.G("Switch %q snap to cohort %q"), inst.Snaps[0], strutil.ElliptLeft(inst.CohortKey, 10))
+		msg = fmt.Sprintf(inst.localize(i18n.N("Switch %q snap to cohort %q")), inst.Snaps[0], strutil.ElliptLeft(inst.CohortKey, 10))
 	default:
-		msg = fmt.Sprintf(i18n.G("Switch %q snap to channel %q and cohort %q"), inst.Snaps[0], inst.Channel, strutil.ElliptLeft(inst.CohortKey, 10))
+		msg = fmt.Sprintf(inst.localize(i18n.N("Switch %q snap to channel %q and cohort %q")), inst.Snaps[0], inst.Channel, strutil.ElliptLeft(inst.CohortKey, 10))
 	}
 	return msg, []*state.TaskSet{ts}, nil
 }
@@ -577,6 +678,19 @@ func snapUnhold(inst *snapInstruction, st *state.State) (string, []*state.TaskSe
 	return res.Summary, res.Tasksets, nil
 }
 
+// snapUnpin lets refreshes move a pinned snap away from its revision.
+func snapUnpin(inst *snapInstruction, st *state.State) (string, []*state.TaskSet, error) {
+	if !inst.Revision.Unset() {
+		return "", nil, errors.New(inst.localize(i18n.N("unpin takes no revision")))
+	}
+	if err := snapstateUnpin(st, inst.Snaps[0]); err != nil {
+		return "", nil, err
+	}
+
+	msg := fmt.Sprintf(inst.localize(i18n.N("Unpin %q snap")), inst.Snaps[0])
+	return msg, nil, nil
+}
+
 type snapActionFunc func(*snapInstruction, *state.State) (string, []*state.TaskSet, error)
 
 var snapInstructionDispTable = map[string]snapActionFunc{
@@ -589,6 +703,9 @@ var snapInstructionDispTable = map[string]snapActionFunc{
 	"switch":  snapSwitch,
 	"hold":    snapHold,
 	"unhold":  snapUnhold,
+	"unpin":   snapUnpin,
+
+	"release-quarantine": snapReleaseQuarantine,
 }
 
 func (inst *snapInstruction) dispatch() snapActionFunc {
@@ -600,10 +717,10 @@ func (inst *snapInstruction) dispatch() snapActionFunc {
 
 func (inst *snapInstruction) errToResponse(err error) *apiError {
 	if len(inst.Snaps) == 0 {
-		return errToResponse(err, nil, BadRequest, "cannot %s: %v", inst.Action)
+		return errToResponse(err, nil, BadRequest, inst.localize(i18n.N("cannot %s: %v")), inst.Action)
 	}
 
-	return errToResponse(err, inst.Snaps, BadRequest, "cannot %s %s: %v", inst.Action, strutil.Quoted(inst.Snaps))
+	return errToResponse(err, inst.Snaps, BadRequest, inst.localize(i18n.N("cannot %s %s: %v")), inst.Action, strutil.Quoted(inst.Snaps))
 }
 
 func postSnaps(c *Command, r *http.Request, user *auth.UserState) Response {
@@ -642,7 +759,7 @@ func snapOpMany(c *Command, r *http.Request, user *auth.UserState) Response {
 	}
 
 	// TODO: inst.Amend, etc?
-	if inst.Channel != "" || !inst.Revision.Unset() || inst.DevMode || inst.JailMode || inst.CohortKey != "" || inst.LeaveCohort || inst.Prefer {
+	if inst.Channel != "" || !inst.Revision.Unset() || inst.DevMode || inst.JailMode || inst.CohortKey != "" || inst.LeaveCohort || inst.Prefer || !inst.BlockRevision.Unset() || inst.Pin {
 		return BadRequest("unsupported option provided for multi-snap operation")
 	}
 	if err := inst.validate(); err != nil {
@@ -653,9 +770,7 @@ func snapOpMany(c *Command, r *http.Request, user *auth.UserState) Response {
 	st.Lock()
 	defer st.Unlock()
 
-	if user != nil {
-		inst.userID = user.ID
-	}
+	inst.setRequester(r, user)
 
 	op := inst.dispatchForMany()
 	if op == nil {
@@ -710,7 +825,7 @@ func (inst *snapInstruction) dispatchForMany() (op snapManyActionFunc) {
 func snapInstallMany(inst *snapInstruction, st *state.State) (*snapInstructionResult, error) {
 	for _, name := range inst.Snaps {
 		if len(name) == 0 {
-			return nil, fmt.Errorf(i18n.G("cannot install snap with empty name"))
+			return nil, errors.New(inst.localize(i18n.N("cannot install snap with empty name")))
 		}
 	}
 	transaction := inst.Transaction
@@ -722,13 +837,13 @@ func snapInstallMany(inst *snapInstruction, st *state.State) (*snapInstructionRe
 	var msg string
 	switch len(inst.Snaps) {
 	case 0:
-		return nil, fmt.Errorf("cannot install zero snaps")
+		return nil, errors.New(inst.localize(i18n.N("cannot install zero snaps")))
 	case 1:
-		msg = fmt.Sprintf(i18n.G("Install snap %q"), inst.Snaps[0])
+		msg = fmt.Sprintf(inst.localize(i18n.N("Install snap %q")), inst.Snaps[0])
 	default:
 		quoted := strutil.Quoted(inst.Snaps)
 		// TRANSLATORS: the %s is a comma-separated list of quoted snap names
-		msg = fmt.Sprintf(i18n.G("Install snaps %s"), quoted)
+		msg = fmt.Sprintf(inst.localize(i18n.N("Install snaps %s")), quoted)
 	}
 
 	return &snapInstructionResult{
@@ -764,22 +879,27 @@ func snapUpdateMany(inst *snapInstruction, st *state.State) (*snapInstructionRes
 		}
 		return nil, err
 	}
+	if inst.IgnoreRunning {
+		if err := inst.overrideRefreshInhibition(st, updated); err != nil {
+			return nil, err
+		}
+	}
 
 	var msg string
 	switch len(updated) {
 	case 0:
 		if len(inst.Snaps) != 0 {
 			// TRANSLATORS: the %s is a comma-separated list of quoted snap names
-			msg = fmt.Sprintf(i18n.G("Refresh snaps %s: no updates"), strutil.Quoted(inst.Snaps))
+			msg = fmt.Sprintf(inst.localize(i18n.N("Refresh snaps %s: no updates")), strutil.Quoted(inst.Snaps))
 		} else {
-			msg = i18n.G("Refresh all snaps: no updates")
+			msg = inst.localize(i18n.N("Refresh all snaps: no updates"))
 		}
 	case 1:
-		msg = fmt.Sprintf(i18n.G("Refresh snap %q"), updated[0])
+		msg = fmt.Sprintf(inst.localize(i18n.N("Refresh snap %q")), updated[0])
 	default:
 		quoted := strutil.Quoted(updated)
 		// TRANSLATORS: the %s is a comma-separated list of quoted snap names
-		msg = fmt.Sprintf(i18n.G("Refresh snaps %s"), quoted)
+		msg = fmt.Sprintf(inst.localize(i18n.N("Refresh snaps %s")), quoted)
 	}
 
 	return &snapInstructionResult{
@@ -791,7 +911,7 @@ func snapUpdateMany(inst *snapInstruction, st *state.State) (*snapInstructionRes
 
 func snapEnforceValidationSets(inst *snapInstruction, st *state.State) (*snapInstructionResult, error) {
 	if len(inst.ValidationSets) > 0 && len(inst.Snaps) != 0 {
-		return nil, fmt.Errorf("snap names cannot be specified with validation sets to enforce")
+		return nil, errors.New(inst.localize(i18n.N("snap names cannot be specified with validation sets to enforce")))
 	}
 
 	snaps, ignoreValidationSnaps, err := snapstate.InstalledSnaps(st)
@@ -864,13 +984,13 @@ func snapRemoveMany(inst *snapInstruction, st *state.State) (*snapInstructionRes
 	var msg string
 	switch len(inst.Snaps) {
 	case 0:
-		return nil, fmt.Errorf("cannot remove zero snaps")
+		return nil, errors.New(inst.localize(i18n.N("cannot remove zero snaps")))
 	case 1:
-		msg = fmt.Sprintf(i18n.G("Remove snap %q"), inst.Snaps[0])
+		msg = fmt.Sprintf(inst.localize(i18n.N("Remove snap %q")), inst.Snaps[0])
 	default:
 		quoted := strutil.Quoted(inst.Snaps)
 		// TRANSLATORS: the %s is a comma-separated list of quoted snap names
-		msg = fmt.Sprintf(i18n.G("Remove snaps %s"), quoted)
+		msg = fmt.Sprintf(inst.localize(i18n.N("Remove snaps %s")), quoted)
 	}
 
 	return &snapInstructionResult{
@@ -972,7 +1092,7 @@ func snapHoldMany(inst *snapInstruction, st *state.State) (res *snapInstructionR
 	var tss []*state.TaskSet
 	if len(inst.Snaps) == 0 {
 		if inst.holdLevel() == snapstate.HoldGeneral {
-			return nil, errors.New("holding general refreshes for all snaps is not supported")
+			return nil, errors.New(inst.localize(i18n.N("holding general refreshes for all snaps is not supported")))
 		}
 		patchValues := map[string]interface{}{"refresh.hold": inst.Time}
 		ts, err := configstateConfigureInstalled(st, "core", patchValues, 0)
@@ -981,15 +1101,15 @@ func snapHoldMany(inst *snapInstruction, st *state.State) (res *snapInstructionR
 		}
 
 		tss = []*state.TaskSet{ts}
-		msg = i18n.G("Hold auto-refreshes for all snaps")
+		msg = inst.localize(i18n.N("Hold auto-refreshes for all snaps"))
 	} else {
 		holdLevel := inst.holdLevel()
 		if err := snapstateHoldRefreshesBySystem(st, holdLevel, inst.Time, inst.Snaps); err != nil {
 			return nil, err
 		}
-		msgFmt := i18n.G("Hold general refreshes for %s")
+		msgFmt := inst.localize(i18n.N("Hold general refreshes for %s"))
 		if holdLevel == snapstate.HoldAutoRefresh {
-			msgFmt = i18n.G("Hold auto-refreshes for %s")
+			msgFmt = inst.localize(i18n.N("Hold auto-refreshes for %s"))
 		}
 		msg = fmt.Sprintf(msgFmt, strutil.Quoted(inst.Snaps))
 	}
@@ -1013,13 +1133,13 @@ func snapUnholdMany(inst *snapInstruction, st *state.State) (res *snapInstructio
 		}
 
 		tss = []*state.TaskSet{ts}
-		msg = i18n.G("Remove auto-refresh hold on all snaps")
+		msg = inst.localize(i18n.N("Remove auto-refresh hold on all snaps"))
 	} else {
 		if err := snapstateProceedWithRefresh(st, "system", inst.Snaps); err != nil {
 			return nil, err
 		}
 
-		msg = fmt.Sprintf(i18n.G("Remove refresh hold on %s"), strutil.Quoted(inst.Snaps))
+		msg = fmt.Sprintf(inst.localize(i18n.N("Remove refresh hold on %s")), strutil.Quoted(inst.Snaps))
 	}
 
 	return &snapInstructionResult{
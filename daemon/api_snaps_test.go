@@ -42,6 +42,7 @@ import (
 	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/daemon"
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/i18n"
 	"github.com/snapcore/snapd/overlord/assertstate"
 	"github.com/snapcore/snapd/overlord/auth"
 	"github.com/snapcore/snapd/overlord/healthstate"
@@ -53,6 +54,7 @@ import (
 	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/store"
 	"github.com/snapcore/snapd/strutil"
+	"github.com/snapcore/snapd/systemd"
 	"github.com/snapcore/snapd/testutil"
 )
 
@@ -1086,6 +1088,7 @@ UnitFileState=enabled
 	snapst.TrackingChannel = "beta"
 	snapst.IgnoreValidation = true
 	snapst.CohortKey = "some-long-cohort-key"
+	snapst.Quarantined = true
 	st.Lock()
 	snapstate.Set(st, "foo", &snapst)
 	st.Unlock()
@@ -1201,11 +1204,12 @@ UnitFileState=enabled
 					},
 				},
 			},
-			Broken:    "",
-			Contact:   "",
-			License:   "GPL-3.0",
-			CommonIDs: []string{"org.foo.cmd"},
-			CohortKey: "some-long-cohort-key",
+			Broken:      "",
+			Contact:     "",
+			License:     "GPL-3.0",
+			CommonIDs:   []string{"org.foo.cmd"},
+			CohortKey:   "some-long-cohort-key",
+			Quarantined: true,
 		},
 	}
 
@@ -1383,6 +1387,7 @@ func (s *snapsSuite) TestMapLocalFields(c *check.C) {
 			IgnoreValidation: true,
 			DevMode:          true,
 			JailMode:         true,
+			Pinned:           true,
 		},
 	},
 	)
@@ -1408,6 +1413,7 @@ func (s *snapsSuite) TestMapLocalFields(c *check.C) {
 		IgnoreValidation: true,
 		DevMode:          true,
 		JailMode:         true,
+		Pinned:           true,
 		Private:          true,
 		Broken:           "very",
 		Links: map[string][]string{
@@ -1427,6 +1433,29 @@ func (s *snapsSuite) TestMapLocalFields(c *check.C) {
 	c.Check(daemon.MapLocal(about, nil), check.DeepEquals, expected)
 }
 
+func (s *snapsSuite) TestMapLocalPublisherChange(c *check.C) {
+	info := snap.Info{SideInfo: snap.SideInfo{RealName: "hello", SnapID: "hello-id", Revision: snap.R(1)}}
+	about := daemon.MakeAboutSnap(&info, &snapstate.SnapState{})
+	c.Check(daemon.MapLocal(about, nil).PublisherChange, check.Equals, "")
+
+	_, err := snapstate.RecordPublisher("hello-id", "hello", snap.StoreAccount{ID: "dev-id", Validation: "verified"})
+	c.Assert(err, check.IsNil)
+	_, err = snapstate.RecordPublisher("hello-id", "hello", snap.StoreAccount{ID: "dev-id", Validation: "unproven"})
+	c.Assert(err, check.IsNil)
+	c.Check(daemon.MapLocal(about, nil).PublisherChange, check.Equals, "publisher-downgraded")
+}
+
+func (s *snapsSuite) TestMapLocalVerity(c *check.C) {
+	info := snap.Info{SideInfo: snap.SideInfo{RealName: "hello", Revision: snap.R(1)}}
+	about := daemon.MakeAboutSnap(&info, &snapstate.SnapState{})
+	c.Check(daemon.MapLocal(about, nil).Verity, check.Equals, "")
+
+	unit := systemd.MountUnitPath(dirs.StripRootDir(info.MountDir()))
+	c.Assert(os.MkdirAll(filepath.Dir(unit), 0755), check.IsNil)
+	c.Assert(os.WriteFile(unit, []byte("[Mount]\nOptions=nodev,ro,verity.roothash=abcd,verity.hashdevice=/var/lib/snapd/snaps/hello_1.snap.verity,verity.hashoffset=4096\n"), 0644), check.IsNil)
+	c.Check(daemon.MapLocal(about, nil).Verity, check.Equals, "generated")
+}
+
 func (s *snapsSuite) TestMapLocalOfTryResolvesSymlink(c *check.C) {
 	c.Assert(os.MkdirAll(dirs.SnapBlobDir, 0755), check.IsNil)
 
@@ -1564,6 +1593,67 @@ func (s *snapsSuite) testPostSnap(c *check.C, extraJSON string, checkOpts func(o
 	return summary, systemRestartImmediate
 }
 
+func (s *snapsSuite) TestPostSnapLocalizedSummary(c *check.C) {
+	d := s.daemonWithOverlordMock()
+
+	var acceptLanguages []string
+	defer daemon.MockI18nNegotiateLocale(func(acceptLanguage string) *i18n.Locale {
+		acceptLanguages = append(acceptLanguages, acceptLanguage)
+		return i18n.MockLocale("de", map[string]string{
+			"Install %q snap": "Snap %q installieren",
+		})
+	})()
+	defer daemon.MockSnapstateInstall(func(ctx context.Context, s *state.State, name string, opts *snapstate.RevisionOptions, userID int, flags snapstate.Flags) (*state.TaskSet, error) {
+		t := s.NewTask("fake-install-snap", "Doing a fake install")
+		return state.NewTaskSet(t), nil
+	})()
+
+	for _, acceptLanguage := range []string{"de-CH,de;q=0.9,en;q=0.5", ""} {
+		buf := bytes.NewBufferString(`{"action": "install"}`)
+		req, err := http.NewRequest("POST", "/v2/snaps/foo", buf)
+		c.Assert(err, check.IsNil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+
+		rsp := s.asyncReq(c, req, nil)
+
+		st := d.Overlord().State()
+		st.Lock()
+		chg := st.Change(rsp.Change)
+		c.Assert(chg, check.NotNil)
+		summary := chg.Summary()
+		st.Unlock()
+
+		if acceptLanguage != "" {
+			c.Check(summary, check.Equals, `Snap "foo" installieren`)
+		} else {
+			c.Check(summary, check.Equals, `Install "foo" snap`)
+		}
+	}
+	// no negotiation without a header
+	c.Check(acceptLanguages, check.DeepEquals, []string{"de-CH,de;q=0.9,en;q=0.5"})
+}
+
+func (s *snapsSuite) TestPostSnapsLocalizedError(c *check.C) {
+	s.daemonWithOverlordMock()
+
+	defer daemon.MockI18nNegotiateLocale(func(acceptLanguage string) *i18n.Locale {
+		return i18n.MockLocale("de", map[string]string{
+			"cannot install snap with empty name": "Snap ohne Namen kann nicht installiert werden",
+		})
+	})()
+
+	buf := bytes.NewBufferString(`{"action": "install", "snaps": [""]}`)
+	req, err := http.NewRequest("POST", "/v2/snaps", buf)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "de")
+
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Message, check.Matches, `.*Snap ohne Namen kann nicht installiert werden`)
+}
+
 func (s *snapsSuite) TestPostSnapVerifySnapInstruction(c *check.C) {
 	s.daemonWithOverlordMock()
 
@@ -1700,7 +1790,7 @@ func (s *snapsSuite) TestPostSnapSetsUser(c *check.C) {
 func (s *snapsSuite) TestPostSnapEnableDisableSwitchRevision(c *check.C) {
 	s.daemon(c)
 
-	for _, action := range []string{"enable", "disable", "switch"} {
+	for _, action := range []string{"enable", "disable", "switch", "release-quarantine"} {
 		buf := bytes.NewBufferString(`{"action": "` + action + `", "revision": "42"}`)
 		req, err := http.NewRequest("POST", "/v2/snaps/hello-world", buf)
 		c.Assert(err, check.IsNil)
@@ -1711,6 +1801,66 @@ func (s *snapsSuite) TestPostSnapEnableDisableSwitchRevision(c *check.C) {
 	}
 }
 
+func (s *snapsSuite) TestPostSnapLocalizedOpError(c *check.C) {
+	s.daemon(c)
+
+	defer daemon.MockI18nNegotiateLocale(func(acceptLanguage string) *i18n.Locale {
+		return i18n.MockLocale("de", map[string]string{
+			"cannot %s %s: %v":         "%[1]s von %[2]s nicht möglich: %[3]v",
+			"enable takes no revision": "enable nimmt keine Revision",
+		})
+	})()
+
+	buf := bytes.NewBufferString(`{"action": "enable", "revision": "42"}`)
+	req, err := http.NewRequest("POST", "/v2/snaps/hello-world", buf)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Accept-Language", "de")
+
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, `enable von "hello-world" nicht möglich: enable nimmt keine Revision`)
+}
+
+func (s *snapsSuite) TestPostSnapReleaseQuarantine(c *check.C) {
+	d := s.daemonWithOverlordMock()
+	s.mkInstalledInState(c, d, "foo", "", "v1", snap.R(-1), true, "")
+
+	st := d.Overlord().State()
+	st.Lock()
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(st, "foo", &snapst), check.IsNil)
+	snapst.Quarantined = true
+	snapstate.Set(st, "foo", &snapst)
+	st.Unlock()
+
+	buf := bytes.NewBufferString(`{"action": "release-quarantine"}`)
+	req, err := http.NewRequest("POST", "/v2/snaps/foo", buf)
+	c.Assert(err, check.IsNil)
+
+	rsp := s.asyncReq(c, req, nil)
+
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	c.Assert(chg, check.NotNil)
+	c.Check(chg.Kind(), check.Equals, "release-quarantine-snap")
+	c.Check(chg.Summary(), check.Equals, `Release "foo" snap from quarantine`)
+	c.Check(chg.Tasks()[0].Kind(), check.Equals, "release-quarantine")
+}
+
+func (s *snapsSuite) TestPostSnapReleaseQuarantineNotQuarantined(c *check.C) {
+	d := s.daemonWithOverlordMock()
+	s.mkInstalledInState(c, d, "foo", "", "v1", snap.R(-1), true, "")
+
+	buf := bytes.NewBufferString(`{"action": "release-quarantine"}`)
+	req, err := http.NewRequest("POST", "/v2/snaps/foo", buf)
+	c.Assert(err, check.IsNil)
+
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, `cannot release-quarantine "foo": snap "foo" is not quarantined`)
+}
+
 func (s *snapsSuite) TestPostSnapOptionsUnsupportedAction(c *check.C) {
 	s.daemon(c)
 	const expectedErr = "snapshot-options can only be specified for snapshot action"
@@ -2242,6 +2392,46 @@ func (s *snapsSuite) TestRefreshIgnoreRunning(c *check.C) {
 	c.Check(summary, check.Equals, `Refresh "some-snap" snap`)
 }
 
+func (s *snapsSuite) TestRefreshIgnoreRunningOverridesInhibition(c *check.C) {
+	defer daemon.MockSnapstateUpdate(func(s *state.State, name string, opts *snapstate.RevisionOptions, userID int, flags snapstate.Flags) (*state.TaskSet, error) {
+		c.Check(flags.IgnoreRunning, check.Equals, true)
+		t := s.NewTask("fake-refresh-snap", "Doing a fake install")
+		return state.NewTaskSet(t), nil
+	})()
+	defer daemon.MockAssertstateRefreshSnapAssertions(func(s *state.State, userID int, opts *assertstate.RefreshAssertionsOptions) error {
+		return nil
+	})()
+	var overrides []*snapstate.RefreshInhibitionOverride
+	defer daemon.MockSnapstateOverrideRefreshInhibition(func(st *state.State, override *snapstate.RefreshInhibitionOverride) (bool, error) {
+		overrides = append(overrides, override)
+		return true, nil
+	})()
+
+	s.daemonWithOverlordMock()
+	user := &auth.UserState{ID: 1, Username: "admin"}
+
+	req, err := http.NewRequest("POST", "/v2/snaps/some-snap", strings.NewReader(`{"action": "refresh", "ignore-running": true}`))
+	c.Assert(err, check.IsNil)
+	req.RemoteAddr = "pid=100;uid=1000;socket=;"
+	s.asyncReq(c, req, user)
+
+	c.Check(overrides, check.DeepEquals, []*snapstate.RefreshInhibitionOverride{
+		{Snap: "some-snap", User: "admin", UID: 1000},
+	})
+
+	// without ignore-running the inhibition is respected
+	overrides = nil
+	req, err = http.NewRequest("POST", "/v2/snaps/some-snap", strings.NewReader(`{"action": "refresh"}`))
+	c.Assert(err, check.IsNil)
+	req.RemoteAddr = "pid=100;uid=1000;socket=;"
+	defer daemon.MockSnapstateUpdate(func(s *state.State, name string, opts *snapstate.RevisionOptions, userID int, flags snapstate.Flags) (*state.TaskSet, error) {
+		t := s.NewTask("fake-refresh-snap", "Doing a fake install")
+		return state.NewTaskSet(t), nil
+	})()
+	s.asyncReq(c, req, user)
+	c.Check(overrides, check.HasLen, 0)
+}
+
 func (s *snapsSuite) TestRefreshCohort(c *check.C) {
 	cohort := ""
 
@@ -2272,6 +2462,139 @@ func (s *snapsSuite) TestRefreshCohort(c *check.C) {
 	c.Check(summary, check.Equals, `Refresh "some-snap" snap`)
 }
 
+func (s *snapsSuite) TestRefreshBlockRevision(c *check.C) {
+	defer daemon.MockSnapstateUpdate(func(s *state.State, name string, opts *snapstate.RevisionOptions, userID int, flags snapstate.Flags) (*state.TaskSet, error) {
+		t := s.NewTask("fake-refresh-snap", "Doing a fake install")
+		return state.NewTaskSet(t), nil
+	})()
+	defer daemon.MockAssertstateRefreshSnapAssertions(func(s *state.State, userID int, opts *assertstate.RefreshAssertionsOptions) error {
+		return nil
+	})()
+	var blockedRev snap.Revision
+	var blockedUntil time.Time
+	defer daemon.MockSnapstateBlockRevision(func(st *state.State, name string, rev snap.Revision, until time.Time) error {
+		c.Check(name, check.Equals, "some-snap")
+		blockedRev, blockedUntil = rev, until
+		return nil
+	})()
+
+	d := s.daemon(c)
+	inst := &daemon.SnapInstruction{
+		Action:        "refresh",
+		Snaps:         []string{"some-snap"},
+		BlockRevision: snap.R(123),
+		BlockUntil:    "2026-11-01T10:00:00Z",
+	}
+
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	_, _, err := inst.Dispatch()(inst, st)
+	c.Check(err, check.IsNil)
+
+	c.Check(blockedRev, check.Equals, snap.R(123))
+	c.Check(blockedUntil.Equal(time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)), check.Equals, true)
+}
+
+func (s *snapsSuite) TestBlockRevisionValidation(c *check.C) {
+	s.daemon(c)
+	for _, tc := range []struct {
+		path, body, err string
+	}{
+		{"/v2/snaps/some-snap", `{"action": "install", "block-revision": "123"}`, `block-revision can only be specified for the "refresh" action`},
+		{"/v2/snaps/some-snap", `{"action": "refresh", "block-until": "2026-11-01T10:00:00Z"}`, `block-until requires block-revision`},
+		{"/v2/snaps/some-snap", `{"action": "refresh", "block-revision": "123", "block-until": "tomorrow"}`, `block-until must be in RFC3339 format: .*`},
+		{"/v2/snaps", `{"action": "refresh", "snaps": ["some-snap"], "block-revision": "123"}`, `unsupported option provided for multi-snap operation`},
+	} {
+		req, err := http.NewRequest("POST", tc.path, strings.NewReader(tc.body))
+		c.Assert(err, check.IsNil)
+		req.Header.Set("Content-Type", "application/json")
+
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, 400, check.Commentf(tc.body))
+		c.Check(rspe.Message, check.Matches, tc.err, check.Commentf(tc.body))
+	}
+}
+
+func (s *snapsSuite) TestInstallRefreshPin(c *check.C) {
+	var installFlags, refreshFlags snapstate.Flags
+	defer daemon.MockSnapstateInstall(func(ctx context.Context, s *state.State, name string, opts *snapstate.RevisionOptions, userID int, flags snapstate.Flags) (*state.TaskSet, error) {
+		installFlags = flags
+		t := s.NewTask("fake-install-snap", "Doing a fake install")
+		return state.NewTaskSet(t), nil
+	})()
+	defer daemon.MockSnapstateUpdate(func(s *state.State, name string, opts *snapstate.RevisionOptions, userID int, flags snapstate.Flags) (*state.TaskSet, error) {
+		refreshFlags = flags
+		t := s.NewTask("fake-refresh-snap", "Doing a fake refresh")
+		return state.NewTaskSet(t), nil
+	})()
+	defer daemon.MockAssertstateRefreshSnapAssertions(func(s *state.State, userID int, opts *assertstate.RefreshAssertionsOptions) error {
+		return nil
+	})()
+
+	d := s.daemon(c)
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+
+	for _, action := range []string{"install", "refresh"} {
+		inst := &daemon.SnapInstruction{
+			Action: action,
+			Snaps:  []string{"some-snap"},
+			Pin:    true,
+		}
+		inst.Revision = snap.R(42)
+		_, _, err := inst.Dispatch()(inst, st)
+		c.Check(err, check.IsNil)
+	}
+
+	c.Check(installFlags.Pinned, check.Equals, true)
+	c.Check(refreshFlags.Pinned, check.Equals, true)
+}
+
+func (s *snapsSuite) TestPinValidation(c *check.C) {
+	s.daemon(c)
+	for _, tc := range []struct {
+		path, body, err string
+	}{
+		{"/v2/snaps/some-snap", `{"action": "remove", "revision": "42", "pin": true}`, `pin can only be specified for the "install" or "refresh" actions`},
+		{"/v2/snaps/some-snap", `{"action": "install", "pin": true}`, `pin requires a revision`},
+		{"/v2/snaps", `{"action": "refresh", "snaps": ["some-snap"], "pin": true}`, `unsupported option provided for multi-snap operation`},
+	} {
+		req, err := http.NewRequest("POST", tc.path, strings.NewReader(tc.body))
+		c.Assert(err, check.IsNil)
+		req.Header.Set("Content-Type", "application/json")
+
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, 400, check.Commentf(tc.body))
+		c.Check(rspe.Message, check.Matches, tc.err, check.Commentf(tc.body))
+	}
+}
+
+func (s *snapsSuite) TestUnpin(c *check.C) {
+	var unpinned []string
+	defer daemon.MockSnapstateUnpin(func(st *state.State, name string) error {
+		unpinned = append(unpinned, name)
+		return nil
+	})()
+
+	inst := &daemon.SnapInstruction{
+		Action: "unpin",
+		Snaps:  []string{"some-snap"},
+	}
+
+	d := s.daemon(c)
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+
+	summary, tasksets, err := inst.Dispatch()(inst, st)
+	c.Assert(err, check.IsNil)
+	c.Check(tasksets, check.IsNil)
+	c.Check(summary, check.Equals, `Unpin "some-snap" snap`)
+	c.Check(unpinned, check.DeepEquals, []string{"some-snap"})
+}
+
 func (s *snapsSuite) TestRefreshLeaveCohort(c *check.C) {
 	var leave *bool
 
@@ -51,14 +51,22 @@ var snapshotExportCmd = &Command{
 	ReadAccess: authenticatedAccess{},
 }
 
+var snapshotFilesCmd = &Command{
+	Path:       "/v2/snapshots/{id}/files",
+	GET:        getSnapshotFiles,
+	ReadAccess: authenticatedAccess{},
+}
+
 var (
 	snapshotList    = snapshotstate.List
 	snapshotCheck   = snapshotstate.Check
 	snapshotForget  = snapshotstate.Forget
-	snapshotRestore = snapshotstate.Restore
+	snapshotRestore = snapshotstate.RestoreTo
 	snapshotSave    = snapshotstate.Save
 	snapshotExport  = snapshotstate.Export
 	snapshotImport  = snapshotstate.Import
+	snapshotFiles   = snapshotstate.Files
+	snapshotFile    = snapshotstate.OpenFile
 )
 
 func listSnapshots(c *Command, r *http.Request, user *auth.UserState) Response {
@@ -85,23 +93,33 @@ func listSnapshots(c *Command, r *http.Request, user *auth.UserState) Response {
 // A snapshotAction is used to request an operation on a snapshot
 // keep this in sync with client/snapshotAction...
 type snapshotAction struct {
-	SetID  uint64   `json:"set"`
-	Action string   `json:"action"`
-	Snaps  []string `json:"snaps,omitempty"`
-	Users  []string `json:"users,omitempty"`
+	SetID  uint64                        `json:"set"`
+	Action string                        `json:"action"`
+	Snaps  []string                      `json:"snaps,omitempty"`
+	Users  []string                      `json:"users,omitempty"`
+	Target *client.SnapshotRestoreTarget `json:"target,omitempty"`
 }
 
 func (action snapshotAction) String() string {
-	// verb of snapshot #N [for snaps %q] [for users %q]
+	// verb of snapshot #N [for snaps %q] [for users %q] [into %q] [onto user %q]
 	var snaps string
 	var users string
+	var target string
 	if len(action.Snaps) > 0 {
 		snaps = " for snaps " + strutil.Quoted(action.Snaps)
 	}
 	if len(action.Users) > 0 {
 		users = " for users " + strutil.Quoted(action.Users)
 	}
-	return fmt.Sprintf("%s of snapshot set #%d%s%s", strings.Title(action.Action), action.SetID, snaps, users)
+	if action.Target != nil {
+		if action.Target.Instance != "" {
+			target += fmt.Sprintf(" into %q", action.Target.Instance)
+		}
+		if action.Target.User != "" {
+			target += fmt.Sprintf(" onto user %q", action.Target.User)
+		}
+	}
+	return fmt.Sprintf("%s of snapshot set #%d%s%s%s", strings.Title(action.Action), action.SetID, snaps, users, target)
 }
 
 func changeSnapshots(c *Command, r *http.Request, user *auth.UserState) Response {
@@ -127,6 +145,10 @@ func changeSnapshots(c *Command, r *http.Request, user *auth.UserState) Response
 		return BadRequest("snapshot operation requires action")
 	}
 
+	if action.Target != nil && action.Action != "restore" {
+		return BadRequest("snapshot %q operation cannot specify a target", action.Action)
+	}
+
 	var affected []string
 	var ts *state.TaskSet
 	var err error
@@ -139,7 +161,7 @@ func changeSnapshots(c *Command, r *http.Request, user *auth.UserState) Response
 	case "check":
 		affected, ts, err = snapshotCheck(st, action.SetID, action.Snaps, action.Users)
 	case "restore":
-		affected, ts, err = snapshotRestore(st, action.SetID, action.Snaps, action.Users)
+		affected, ts, err = snapshotRestore(st, action.SetID, action.Snaps, action.Users, action.Target)
 	case "forget":
 		if len(action.Users) != 0 {
 			return BadRequest(`snapshot "forget" operation cannot specify users`)
@@ -196,6 +218,49 @@ func getSnapshotExport(c *Command, r *http.Request, user *auth.UserState) Respon
 	return &snapshotExportResponse{SnapshotExport: export, setID: setID, st: st}
 }
 
+// getSnapshotFiles lists the files stored in a snapshot set, or streams one
+// of them if a path is given.
+func getSnapshotFiles(c *Command, r *http.Request, user *auth.UserState) Response {
+	vars := muxVars(r)
+	sid := vars["id"]
+	setID, err := strconv.ParseUint(sid, 10, 64)
+	if err != nil {
+		return BadRequest("'id' must be a positive base 10 number; got %q", sid)
+	}
+
+	query := r.URL.Query()
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	if path := query.Get("path"); path != "" {
+		snapName := query.Get("snap")
+		if snapName == "" {
+			return BadRequest("cannot get a file from a snapshot set without a snap name")
+		}
+		f, size, err := snapshotFile(st, setID, snapName, query.Get("user"), path)
+		switch err {
+		case nil:
+			return &snapshotFileResponse{ReadCloser: f, size: size, path: path}
+		case client.ErrSnapshotSetNotFound, client.ErrSnapshotSnapsNotFound, client.ErrSnapshotFileNotFound:
+			return NotFound("%v", err)
+		default:
+			return InternalError("%v", err)
+		}
+	}
+
+	files, err := snapshotFiles(context.TODO(), st, setID, strutil.CommaSeparatedList(query.Get("snaps")))
+	switch err {
+	case nil:
+		return SyncResponse(files)
+	case client.ErrSnapshotSetNotFound, client.ErrSnapshotSnapsNotFound:
+		return NotFound("%v", err)
+	default:
+		return InternalError("%v", err)
+	}
+}
+
 func doSnapshotImport(c *Command, r *http.Request, user *auth.UserState) Response {
 	defer r.Body.Close()
 
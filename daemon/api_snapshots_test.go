@@ -27,6 +27,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"strings"
 
@@ -237,6 +238,9 @@ func (s *snapshotSuite) TestChangeSnapshots400(c *check.C) {
 		}, {
 			body:  `{"set": 42, "action": "forget", "users": ["foo"]}`,
 			error: `snapshot "forget" operation cannot specify users`,
+		}, {
+			body:  `{"set": 42, "action": "check", "target": {"user": "foo"}}`,
+			error: `snapshot "check" operation cannot specify a target`,
 		},
 	}
 
@@ -258,7 +262,7 @@ func (s *snapshotSuite) TestChangeSnapshots404(c *check.C) {
 		done = "check"
 		return nil, nil, expectedError
 	})()
-	defer daemon.MockSnapshotRestore(func(*state.State, uint64, []string, []string) ([]string, *state.TaskSet, error) {
+	defer daemon.MockSnapshotRestore(func(*state.State, uint64, []string, []string, *client.SnapshotRestoreTarget) ([]string, *state.TaskSet, error) {
 		done = "restore"
 		return nil, nil, expectedError
 	})()
@@ -289,7 +293,7 @@ func (s *snapshotSuite) TestChangeSnapshots500(c *check.C) {
 		done = "check"
 		return nil, nil, expectedError
 	})()
-	defer daemon.MockSnapshotRestore(func(*state.State, uint64, []string, []string) ([]string, *state.TaskSet, error) {
+	defer daemon.MockSnapshotRestore(func(*state.State, uint64, []string, []string, *client.SnapshotRestoreTarget) ([]string, *state.TaskSet, error) {
 		done = "restore"
 		return nil, nil, expectedError
 	})()
@@ -316,7 +320,7 @@ func (s *snapshotSuite) TestChangeSnapshot(c *check.C) {
 		done = "check"
 		return []string{"foo"}, state.NewTaskSet(), nil
 	})()
-	defer daemon.MockSnapshotRestore(func(*state.State, uint64, []string, []string) ([]string, *state.TaskSet, error) {
+	defer daemon.MockSnapshotRestore(func(*state.State, uint64, []string, []string, *client.SnapshotRestoreTarget) ([]string, *state.TaskSet, error) {
 		done = "restore"
 		return []string{"foo"}, state.NewTaskSet(), nil
 	})()
@@ -356,6 +360,36 @@ func (s *snapshotSuite) TestChangeSnapshot(c *check.C) {
 	}
 }
 
+func (s *snapshotSuite) TestChangeSnapshotRestoreTarget(c *check.C) {
+	defer daemon.MockSnapshotRestore(func(_ *state.State, setID uint64, snaps []string, users []string, target *client.SnapshotRestoreTarget) ([]string, *state.TaskSet, error) {
+		c.Check(setID, check.Equals, uint64(42))
+		c.Check(snaps, check.DeepEquals, []string{"foo"})
+		c.Check(users, check.DeepEquals, []string{"alice"})
+		c.Check(target, check.DeepEquals, &client.SnapshotRestoreTarget{Instance: "foo_staging", User: "bob"})
+		return []string{"foo_staging"}, state.NewTaskSet(), nil
+	})()
+
+	body := `{"set": 42, "action": "restore", "snaps": ["foo"], "users": ["alice"], "target": {"instance": "foo_staging", "user": "bob"}}`
+	req, err := http.NewRequest("POST", "/v2/snapshots", strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+
+	rsp := s.asyncReq(c, req, nil)
+	c.Check(rsp.Status, check.Equals, 202)
+
+	st := s.d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	c.Assert(chg, check.NotNil)
+	c.Check(chg.Kind(), check.Equals, "restore-snapshot")
+	c.Check(chg.Summary(), check.Equals, `Restore of snapshot set #42 for snaps "foo" for users "alice" into "foo_staging" onto user "bob"`)
+	var apiData map[string]interface{}
+	c.Assert(chg.Get("api-data", &apiData), check.IsNil)
+	c.Check(apiData, check.DeepEquals, map[string]interface{}{
+		"snap-names": []interface{}{"foo_staging"},
+	})
+}
+
 func (s *snapshotSuite) TestExportSnapshots(c *check.C) {
 	var snapshotExportCalled int
 
@@ -399,6 +433,96 @@ func (s *snapshotSuite) TestExportSnapshotsBadRequestOnError(c *check.C) {
 	c.Check(snapshotExportCalled, check.Equals, 1)
 }
 
+func (s *snapshotSuite) TestSnapshotFiles(c *check.C) {
+	files := []client.SnapshotFile{
+		{Snap: "foo", Path: "common/foo.conf", Size: 3},
+		{Snap: "foo", User: "bob", Path: "42/bar", Size: 5},
+	}
+	defer daemon.MockSnapshotFiles(func(_ context.Context, _ *state.State, setID uint64, snaps []string) ([]client.SnapshotFile, error) {
+		c.Check(setID, check.Equals, uint64(1))
+		c.Check(snaps, check.DeepEquals, []string{"foo", "bar"})
+		return files, nil
+	})()
+
+	req, err := http.NewRequest("GET", "/v2/snapshots/1/files?snaps=foo,bar", nil)
+	c.Assert(err, check.IsNil)
+
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Status, check.Equals, 200)
+	c.Check(rsp.Result, check.DeepEquals, files)
+}
+
+func (s *snapshotSuite) TestSnapshotFilesErrors(c *check.C) {
+	defer daemon.MockSnapshotFiles(func(_ context.Context, _ *state.State, setID uint64, _ []string) ([]client.SnapshotFile, error) {
+		if setID == 2 {
+			return nil, errors.New("boom")
+		}
+		return nil, client.ErrSnapshotSetNotFound
+	})()
+
+	for _, tc := range []struct {
+		url    string
+		status int
+		msg    string
+	}{
+		{"/v2/snapshots/xxx/files", 400, `'id' must be a positive base 10 number; got "xxx"`},
+		{"/v2/snapshots/1/files", 404, `no snapshot set with the given ID`},
+		{"/v2/snapshots/2/files", 500, `boom`},
+		{"/v2/snapshots/1/files?path=common/foo", 400, `cannot get a file from a snapshot set without a snap name`},
+	} {
+		req, err := http.NewRequest("GET", tc.url, nil)
+		c.Assert(err, check.IsNil)
+
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, tc.status, check.Commentf(tc.url))
+		c.Check(rspe.Message, check.Equals, tc.msg, check.Commentf(tc.url))
+	}
+}
+
+type trackingReadCloser struct {
+	io.Reader
+	closed *bool
+}
+
+func (r *trackingReadCloser) Close() error {
+	*r.closed = true
+	return nil
+}
+
+func (s *snapshotSuite) TestSnapshotFile(c *check.C) {
+	var closed bool
+	defer daemon.MockSnapshotFile(func(_ *state.State, setID uint64, snapName, username, path string) (io.ReadCloser, int64, error) {
+		c.Check(setID, check.Equals, uint64(1))
+		c.Check(snapName, check.Equals, "foo")
+		c.Check(username, check.Equals, "bob")
+		if path != "common/foo.conf" {
+			return nil, -1, client.ErrSnapshotFileNotFound
+		}
+		return &trackingReadCloser{Reader: strings.NewReader("hello"), closed: &closed}, 5, nil
+	})()
+
+	req, err := http.NewRequest("GET", "/v2/snapshots/1/files?snap=foo&user=bob&path=common/foo.conf", nil)
+	c.Assert(err, check.IsNil)
+
+	rsp := s.req(c, req, nil)
+	c.Assert(rsp, check.FitsTypeOf, &daemon.SnapshotFileResponse{})
+	rec := httptest.NewRecorder()
+	rsp.ServeHTTP(rec, req)
+	c.Check(rec.Code, check.Equals, 200)
+	c.Check(rec.Header().Get("Content-Length"), check.Equals, "5")
+	c.Check(rec.Header().Get("Content-Type"), check.Equals, "application/octet-stream")
+	c.Check(rec.Header().Get("Content-Disposition"), check.Equals, "attachment; filename=foo.conf")
+	c.Check(rec.Body.String(), check.Equals, "hello")
+	c.Check(closed, check.Equals, true)
+
+	req, err = http.NewRequest("GET", "/v2/snapshots/1/files?snap=foo&user=bob&path=common/other", nil)
+	c.Assert(err, check.IsNil)
+
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 404)
+	c.Check(rspe.Message, check.Equals, "no file with the given path found in the snapshot")
+}
+
 func (s *snapshotSuite) TestImportSnapshot(c *check.C) {
 	data := []byte("mocked snapshot export data file")
 
@@ -0,0 +1,65 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/devicestate"
+)
+
+var systemAttestationCmd = &Command{
+	Path:        "/v2/system-attestation",
+	POST:        postSystemAttestation,
+	WriteAccess: rootAccess{},
+}
+
+var deviceManagerAttestation = (*devicestate.DeviceManager).Attestation
+
+type postSystemAttestationData struct {
+	Nonce string `json:"nonce"`
+}
+
+func postSystemAttestation(c *Command, r *http.Request, user *auth.UserState) Response {
+	var postData postSystemAttestationData
+
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&postData); err != nil {
+		return BadRequest("cannot decode attestation request from request body: %v", err)
+	}
+	if decoder.More() {
+		return BadRequest("spurious content after attestation request")
+	}
+	if postData.Nonce == "" {
+		return BadRequest("missing attestation nonce")
+	}
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	attestation, err := deviceManagerAttestation(c.d.overlord.DeviceManager(), postData.Nonce)
+	if err != nil {
+		return InternalError(err.Error())
+	}
+	return SyncResponse(attestation)
+}
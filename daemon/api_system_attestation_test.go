@@ -0,0 +1,120 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/overlord/devicestate"
+)
+
+var _ = Suite(&attestationSuite{})
+
+type attestationSuite struct {
+	apiBaseSuite
+}
+
+func (s *attestationSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectWriteAccess(daemon.RootAccess{})
+}
+
+func (s *attestationSuite) TestPostSystemAttestation(c *C) {
+	s.daemon(c)
+
+	signed := &devicestate.SignedAttestation{
+		Document:    []byte(`{"nonce":"some-nonce"}`),
+		Signature:   "signature",
+		DeviceKeyID: "key-id",
+	}
+	var nonces []string
+	defer daemon.MockDeviceManagerAttestation(func(nonce string) (*devicestate.SignedAttestation, error) {
+		nonces = append(nonces, nonce)
+		return signed, nil
+	})()
+
+	buf := bytes.NewBufferString(`{"nonce":"some-nonce"}`)
+	req, err := http.NewRequest("POST", "/v2/system-attestation", buf)
+	c.Assert(err, IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Status, Equals, 200)
+	c.Check(rsp.Result, Equals, signed)
+	c.Check(nonces, DeepEquals, []string{"some-nonce"})
+}
+
+func (s *attestationSuite) TestPostSystemAttestationError(c *C) {
+	s.daemon(c)
+
+	defer daemon.MockDeviceManagerAttestation(func(nonce string) (*devicestate.SignedAttestation, error) {
+		return nil, errors.New("cannot produce attestation: device is not registered yet")
+	})()
+
+	buf := bytes.NewBufferString(`{"nonce":"some-nonce"}`)
+	req, err := http.NewRequest("POST", "/v2/system-attestation", buf)
+	c.Assert(err, IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 500)
+	c.Check(rspe.Message, Equals, "cannot produce attestation: device is not registered yet")
+}
+
+func (s *attestationSuite) TestPostSystemAttestationBadRequest(c *C) {
+	s.daemon(c)
+
+	defer daemon.MockDeviceManagerAttestation(func(nonce string) (*devicestate.SignedAttestation, error) {
+		c.Fatalf("unexpected call")
+		return nil, nil
+	})()
+
+	for _, tc := range []struct {
+		body string
+		err  string
+	}{
+		{`{"nonce":""}`, "missing attestation nonce"},
+		{`{}`, "missing attestation nonce"},
+		{`{"nonce":"x"}{}`, "spurious content after attestation request"},
+		{`nonce`, "cannot decode attestation request from request body: .*"},
+	} {
+		req, err := http.NewRequest("POST", "/v2/system-attestation", bytes.NewBufferString(tc.body))
+		c.Assert(err, IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, Equals, 400)
+		c.Check(rspe.Message, Matches, tc.err)
+	}
+}
+
+func (s *attestationSuite) TestPostSystemAttestationAsUser(c *C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("POST", "/v2/system-attestation", bytes.NewBufferString(`{"nonce":"x"}`))
+	c.Assert(err, IsNil)
+
+	// being properly authorized as user is not enough, needs root
+	s.asUserAuth(c, req)
+	rec := httptest.NewRecorder()
+	s.serveHTTP(c, rec, req)
+	c.Assert(rec.Code, Equals, 403)
+}
@@ -0,0 +1,84 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/devicestate"
+)
+
+var systemRebootCmd = &Command{
+	Path:        "/v2/system-reboot",
+	POST:        postSystemReboot,
+	WriteAccess: rootAccess{},
+}
+
+type postSystemRebootData struct {
+	Action string `json:"action"`
+	// At is the time at which the reboot should happen
+	At time.Time `json:"at"`
+	// AfterChanges delays the reboot until the changes in progress have
+	// completed
+	AfterChanges bool `json:"after-changes"`
+}
+
+func postSystemReboot(c *Command, r *http.Request, user *auth.UserState) Response {
+	var postData postSystemRebootData
+
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&postData); err != nil {
+		return BadRequest("cannot decode system reboot data from request body: %v", err)
+	}
+	if decoder.More() {
+		return BadRequest("spurious content after system reboot data")
+	}
+
+	sr := &devicestate.ScheduledReboot{
+		At:           postData.At,
+		AfterChanges: postData.AfterChanges,
+	}
+	switch postData.Action {
+	case "", "reboot":
+		// plain reboot
+	case "halt":
+		sr.Op = devicestate.RebootHaltOp
+	case "poweroff":
+		sr.Op = devicestate.RebootPoweroffOp
+	default:
+		return BadRequest("unsupported system reboot action %q", postData.Action)
+	}
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	chg, err := devicestate.ScheduleReboot(st, sr)
+	if err != nil {
+		return errToResponse(err, nil, InternalError, "cannot schedule system reboot: %v")
+	}
+
+	st.EnsureBefore(0)
+
+	return AsyncResponse(nil, chg.ID())
+}
@@ -0,0 +1,142 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/overlord/devicestate"
+)
+
+var _ = Suite(&systemRebootSuite{})
+
+type systemRebootSuite struct {
+	apiBaseSuite
+}
+
+func (s *systemRebootSuite) SetUpTest(c *C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectRootAccess()
+}
+
+func (s *systemRebootSuite) TestPostSystemReboot(c *C) {
+	d := s.daemonWithOverlordMock()
+
+	at := time.Date(2026, 10, 15, 22, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		body     string
+		expected devicestate.ScheduledReboot
+		summary  string
+	}{{
+		body:    `{}`,
+		summary: "Reboot the system",
+	}, {
+		body:     `{"action":"reboot","at":"2026-10-15T22:00:00Z"}`,
+		expected: devicestate.ScheduledReboot{At: at},
+		summary:  "Reboot the system at 2026-10-15T22:00:00Z",
+	}, {
+		body:     `{"action":"halt","after-changes":true}`,
+		expected: devicestate.ScheduledReboot{Op: devicestate.RebootHaltOp, AfterChanges: true},
+		summary:  "Halt the system after current changes complete",
+	}, {
+		body:     `{"action":"poweroff"}`,
+		expected: devicestate.ScheduledReboot{Op: devicestate.RebootPoweroffOp},
+		summary:  "Power off the system",
+	}} {
+		req, err := http.NewRequest("POST", "/v2/system-reboot", bytes.NewBufferString(tc.body))
+		c.Assert(err, IsNil)
+		rsp := s.asyncReq(c, req, nil)
+
+		st := d.Overlord().State()
+		st.Lock()
+		chg := st.Change(rsp.Change)
+		c.Assert(chg, NotNil)
+		c.Check(chg.Kind(), Equals, "schedule-reboot")
+		c.Check(chg.Summary(), Equals, tc.summary)
+		var sr devicestate.ScheduledReboot
+		c.Check(chg.Tasks()[0].Get("scheduled-reboot", &sr), IsNil)
+		c.Check(sr.Op, Equals, tc.expected.Op)
+		c.Check(sr.At.Equal(tc.expected.At), Equals, true)
+		c.Check(sr.AfterChanges, Equals, tc.expected.AfterChanges)
+		// let the next request schedule a reboot
+		chg.Abort()
+		st.Unlock()
+	}
+}
+
+func (s *systemRebootSuite) TestPostSystemRebootConflict(c *C) {
+	d := s.daemonWithOverlordMock()
+
+	st := d.Overlord().State()
+	st.Lock()
+	_, err := devicestate.ScheduleReboot(st, &devicestate.ScheduledReboot{})
+	st.Unlock()
+	c.Assert(err, IsNil)
+
+	req, err := http.NewRequest("POST", "/v2/system-reboot", bytes.NewBufferString(`{"action":"poweroff"}`))
+	c.Assert(err, IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 409)
+	c.Check(rspe.Kind, Equals, client.ErrorKindSnapChangeConflict)
+	c.Check(rspe.Message, Equals, "cannot schedule reboot: a system reboot is already scheduled")
+	c.Check(rspe.Value, DeepEquals, map[string]interface{}{
+		"change-kind": "schedule-reboot",
+	})
+}
+
+func (s *systemRebootSuite) TestPostSystemRebootErrors(c *C) {
+	s.daemonWithOverlordMock()
+
+	for _, tc := range []struct {
+		body   string
+		errMsg string
+	}{
+		{`{"action":"reboot"} x`, "spurious content after system reboot data"},
+		{`{"action":"reboot"`, "cannot decode system reboot data from request body: unexpected EOF"},
+		{`{"action":"suspend"}`, `unsupported system reboot action "suspend"`},
+		{`{"at":"tomorrow"}`, `cannot decode system reboot data from request body: parsing time .*`},
+	} {
+		req, err := http.NewRequest("POST", "/v2/system-reboot", bytes.NewBufferString(tc.body))
+		c.Assert(err, IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, Equals, 400, Commentf("%s", tc.body))
+		c.Check(rspe.Message, Matches, tc.errMsg, Commentf("%s", tc.body))
+	}
+}
+
+func (s *systemRebootSuite) TestPostSystemRebootAsUserErrors(c *C) {
+	s.daemonWithOverlordMock()
+
+	req, err := http.NewRequest("POST", "/v2/system-reboot", bytes.NewBufferString(`{"action":"reboot"}`))
+	c.Assert(err, IsNil)
+
+	// being properly authorized as user is not enough, needs root
+	s.asUserAuth(c, req)
+	rec := httptest.NewRecorder()
+	s.serveHTTP(c, rec, req)
+	c.Assert(rec.Code, Equals, 403)
+}
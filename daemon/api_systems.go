@@ -21,6 +21,7 @@ package daemon
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/snapcore/snapd/overlord/auth"
 	"github.com/snapcore/snapd/overlord/devicestate"
 	"github.com/snapcore/snapd/overlord/install"
+	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/snap"
 )
 
@@ -56,12 +58,19 @@ var systemsActionCmd = &Command{
 }
 
 type systemsResponse struct {
-	Systems []client.System `json:"systems,omitempty"`
+	Systems         []client.System         `json:"systems,omitempty"`
+	RecoveryRequest *client.RecoveryRequest `json:"recovery-request,omitempty"`
 }
 
 func getAllSystems(c *Command, r *http.Request, user *auth.UserState) Response {
 	var rsp systemsResponse
 
+	recoveryRequest, err := recoveryRequest(c.d.overlord.State())
+	if err != nil {
+		return InternalError(err.Error())
+	}
+	rsp.RecoveryRequest = recoveryRequest
+
 	seedSystems, err := c.d.overlord.DeviceManager().Systems()
 	if err != nil {
 		if err == devicestate.ErrNoSystems {
@@ -105,6 +114,28 @@ func getAllSystems(c *Command, r *http.Request, user *auth.UserState) Response {
 	return SyncResponse(&rsp)
 }
 
+func recoveryRequest(st *state.State) (*client.RecoveryRequest, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	req, err := devicestate.RecoveryRequestFromState(st)
+	if errors.Is(err, state.ErrNoState) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &client.RecoveryRequest{
+		System:      req.System,
+		Mode:        req.Mode,
+		Reason:      req.Reason,
+		Status:      string(req.Status),
+		RequestedAt: req.RequestedAt,
+		EnteredAt:   req.EnteredAt,
+		CompletedAt: req.CompletedAt,
+	}, nil
+}
+
 // wrapped for unit tests
 var deviceManagerSystemAndGadgetAndEncryptionInfo = func(dm *devicestate.DeviceManager, systemLabel string) (*devicestate.System, *gadget.Info, *install.EncryptionSupportInfo, error) {
 	return dm.SystemAndGadgetAndEncryptionInfo(systemLabel)
@@ -176,6 +207,7 @@ func getSystemDetails(c *Command, r *http.Request, user *auth.UserState) Respons
 
 type systemActionRequest struct {
 	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
 
 	client.SystemAction
 	client.InstallSystemOptions
@@ -199,6 +231,8 @@ func postSystemsAction(c *Command, r *http.Request, user *auth.UserState) Respon
 		return postSystemActionReboot(c, systemLabel, &req)
 	case "install":
 		return postSystemActionInstall(c, systemLabel, &req)
+	case "request-recovery":
+		return postSystemActionRequestRecovery(c, systemLabel, &req)
 	default:
 		return BadRequest("unsupported action %q", req.Action)
 	}
@@ -229,6 +263,29 @@ func postSystemActionReboot(c *Command, systemLabel string, req *systemActionReq
 	return SyncResponse(nil)
 }
 
+// wrapped for unit tests
+var deviceManagerRequestRecovery = func(dm *devicestate.DeviceManager, systemLabel, mode, reason string) error {
+	return dm.RequestRecovery(systemLabel, mode, reason)
+}
+
+func postSystemActionRequestRecovery(c *Command, systemLabel string, req *systemActionRequest) Response {
+	if systemLabel == "" {
+		return BadRequest("recovery request requires the system label to be provided")
+	}
+	if req.Mode == "" {
+		return BadRequest("recovery request requires the mode to be provided")
+	}
+	if err := devicestate.ValidateRecoveryReason(req.Reason); err != nil {
+		return BadRequest("cannot request recovery: %v", err)
+	}
+
+	dm := c.d.overlord.DeviceManager()
+	if err := deviceManagerRequestRecovery(dm, systemLabel, req.Mode, req.Reason); err != nil {
+		return handleSystemActionErr(err, systemLabel)
+	}
+	return SyncResponse(nil)
+}
+
 func postSystemActionDo(c *Command, systemLabel string, req *systemActionRequest) Response {
 	if systemLabel == "" {
 		return BadRequest("system action requires the system label to be provided")
@@ -777,6 +777,125 @@ func (s *systemsSuite) TestSystemRebootUnhappy(c *check.C) {
 	}
 }
 
+func (s *systemsSuite) TestSystemRequestRecoveryHappy(c *check.C) {
+	s.daemon(c)
+
+	called := 0
+	restore := daemon.MockDeviceManagerRequestRecovery(func(dm *devicestate.DeviceManager, systemLabel, mode, reason string) error {
+		called++
+		c.Check(dm, check.NotNil)
+		c.Check(systemLabel, check.Equals, "20200101")
+		c.Check(mode, check.Equals, "recover")
+		c.Check(reason, check.Equals, "disk-check")
+		return nil
+	})
+	defer restore()
+
+	body := `{"action":"request-recovery", "mode":"recover", "reason":"disk-check"}`
+	req, err := http.NewRequest("POST", "/v2/systems/20200101", strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+	s.asRootAuth(req)
+
+	rec := httptest.NewRecorder()
+	s.serveHTTP(c, rec, req)
+	c.Check(rec.Code, check.Equals, 200)
+	c.Check(called, check.Equals, 1)
+}
+
+func (s *systemsSuite) TestSystemRequestRecoveryUnhappy(c *check.C) {
+	s.daemon(c)
+
+	for _, tc := range []struct {
+		url, body        string
+		requestErr       error
+		expectedHttpCode int
+		expectedErr      string
+	}{
+		{"/v2/systems", `{"action":"request-recovery", "mode":"recover", "reason":"disk-check"}`, nil, 400, "recovery request requires the system label to be provided"},
+		{"/v2/systems/20200101", `{"action":"request-recovery", "reason":"disk-check"}`, nil, 400, "recovery request requires the mode to be provided"},
+		{"/v2/systems/20200101", `{"action":"request-recovery", "mode":"recover"}`, nil, 400, "cannot request recovery: recovery reason code cannot be empty"},
+		{"/v2/systems/20200101", `{"action":"request-recovery", "mode":"recover", "reason":"Disk Check"}`, nil, 400, `cannot request recovery: invalid recovery reason code "Disk Check"`},
+		{"/v2/systems/20200101", `{"action":"request-recovery", "mode":"recover", "reason":"disk-check"}`, os.ErrNotExist, 404, `requested seed system "20200101" does not exist`},
+		{"/v2/systems/20200101", `{"action":"request-recovery", "mode":"recover", "reason":"disk-check"}`, devicestate.ErrUnsupportedAction, 400, `requested action is not supported by system "20200101"`},
+	} {
+		called := 0
+		restore := daemon.MockDeviceManagerRequestRecovery(func(dm *devicestate.DeviceManager, systemLabel, mode, reason string) error {
+			called++
+			return tc.requestErr
+		})
+		defer restore()
+
+		req, err := http.NewRequest("POST", tc.url, strings.NewReader(tc.body))
+		c.Assert(err, check.IsNil)
+		s.asRootAuth(req)
+
+		rec := httptest.NewRecorder()
+		s.serveHTTP(c, rec, req)
+		c.Check(rec.Code, check.Equals, tc.expectedHttpCode)
+		if tc.requestErr != nil {
+			c.Check(called, check.Equals, 1)
+		} else {
+			c.Check(called, check.Equals, 0)
+		}
+
+		var rspBody map[string]interface{}
+		err = json.Unmarshal(rec.Body.Bytes(), &rspBody)
+		c.Check(err, check.IsNil)
+		result := rspBody["result"].(map[string]interface{})
+		c.Check(result["message"], check.Equals, tc.expectedErr)
+	}
+}
+
+func (s *systemsSuite) TestSystemsGetRecoveryRequest(c *check.C) {
+	m := boot.Modeenv{
+		Mode: "run",
+	}
+	err := m.WriteTo("")
+	c.Assert(err, check.IsNil)
+
+	d := s.daemonWithOverlordMockAndStore()
+	hookMgr, err := hookstate.Manager(d.Overlord().State(), d.Overlord().TaskRunner())
+	c.Assert(err, check.IsNil)
+	mgr, err := devicestate.Manager(d.Overlord().State(), hookMgr, d.Overlord().TaskRunner(), nil)
+	c.Assert(err, check.IsNil)
+	d.Overlord().AddManager(mgr)
+
+	requestedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	completedAt := requestedAt.Add(time.Hour)
+	st := d.Overlord().State()
+	st.Lock()
+	st.Set("recovery-request", &devicestate.RecoveryRequest{
+		System:      "20200101",
+		Mode:        "recover",
+		Reason:      "disk-check",
+		Status:      devicestate.RecoveryRequestNotEntered,
+		RequestedAt: requestedAt,
+		CompletedAt: completedAt,
+		BootID:      "boot-id",
+	})
+	st.Unlock()
+
+	s.expectAuthenticatedAccess()
+
+	req, err := http.NewRequest("GET", "/v2/systems", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+
+	c.Assert(rsp.Status, check.Equals, 200)
+	sys := rsp.Result.(*daemon.SystemsResponse)
+
+	c.Assert(sys, check.DeepEquals, &daemon.SystemsResponse{
+		RecoveryRequest: &client.RecoveryRequest{
+			System:      "20200101",
+			Mode:        "recover",
+			Reason:      "disk-check",
+			Status:      "not-entered",
+			RequestedAt: requestedAt,
+			CompletedAt: completedAt,
+		},
+	})
+}
+
 // XXX: duplicated from gadget_test.go
 func asOffsetPtr(offs quantity.Offset) *quantity.Offset {
 	goff := offs
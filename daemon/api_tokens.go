@@ -0,0 +1,349 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/randutil"
+	"github.com/snapcore/snapd/snap"
+)
+
+// API tokens grant access to a subset of the snap operations, e.g. only
+// refreshing a given snap, to clients that are otherwise not authorized,
+// like CI agents. Only a digest of the token secrets is kept in state.
+
+var apiTokensCmd = &Command{
+	Path:        "/v2/tokens",
+	GET:         getAPITokens,
+	POST:        postAPITokens,
+	ReadAccess:  rootAccess{},
+	WriteAccess: rootAccess{},
+}
+
+// maxAPITokenRequestBody is the largest request body inspected to decide
+// whether a token allows a request.
+const maxAPITokenRequestBody = 64 * 1024
+
+func tokenDigest(secret string) string {
+	d := sha3.Sum384([]byte(secret))
+	return hex.EncodeToString(d[:])
+}
+
+// bearerToken returns the bearer token of the request, if any.
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	secret := strings.TrimPrefix(header, "Bearer ")
+	if secret == header {
+		return ""
+	}
+	return secret
+}
+
+type apiToken struct {
+	ID      string    `json:"id"`
+	Digest  string    `json:"digest"`
+	Allow   []string  `json:"allow"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+}
+
+func (tok *apiToken) expired(now time.Time) bool {
+	return !now.Before(tok.Expires)
+}
+
+// allows returns whether the token allows the given snap action on all
+// the given snaps, an empty list of snaps meaning all of them.
+func (tok *apiToken) allows(action string, snaps []string) bool {
+	allowed := func(name string) bool {
+		for _, rule := range tok.Allow {
+			ruleAction, ruleSnap, _ := strings.Cut(rule, ":")
+			if ruleAction == action && (ruleSnap == "" || ruleSnap == name) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(snaps) == 0 {
+		return allowed("")
+	}
+	for _, name := range snaps {
+		if !allowed(name) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateAPITokenAllow(allow []string) error {
+	if len(allow) == 0 {
+		return fmt.Errorf("tokens must allow at least one operation")
+	}
+	for _, rule := range allow {
+		action, name, hasSnap := strings.Cut(rule, ":")
+		if _, ok := snapInstructionDispTable[action]; !ok {
+			return fmt.Errorf("cannot allow unknown operation %q", action)
+		}
+		if hasSnap {
+			if err := snap.ValidateInstanceName(name); err != nil {
+				return fmt.Errorf("cannot allow %q: %v", rule, err)
+			}
+		}
+	}
+	return nil
+}
+
+func apiTokens(st *state.State) (map[string]*apiToken, error) {
+	var tokens map[string]*apiToken
+	if err := st.Get("api-tokens", &tokens); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	if tokens == nil {
+		tokens = make(map[string]*apiToken)
+	}
+	return tokens, nil
+}
+
+// addAPIToken creates a new token with the given rules and expiry,
+// returning it together with its secret. Expired tokens are dropped.
+func addAPIToken(st *state.State, allow []string, expires time.Time) (*apiToken, string, error) {
+	tokens, err := apiTokens(st)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	for id, tok := range tokens {
+		if tok.expired(now) {
+			delete(tokens, id)
+		}
+	}
+	secret, err := randutil.CryptoToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	var id string
+	for id == "" || tokens[id] != nil {
+		id = randutil.RandomString(8)
+	}
+	tok := &apiToken{
+		ID:      id,
+		Digest:  tokenDigest(secret),
+		Allow:   allow,
+		Created: now,
+		Expires: expires,
+	}
+	tokens[id] = tok
+	st.Set("api-tokens", tokens)
+	return tok, secret, nil
+}
+
+func removeAPIToken(st *state.State, id string) error {
+	tokens, err := apiTokens(st)
+	if err != nil {
+		return err
+	}
+	if tokens[id] == nil {
+		return fmt.Errorf("token %q not found", id)
+	}
+	delete(tokens, id)
+	st.Set("api-tokens", tokens)
+	return nil
+}
+
+// apiTokenForSecret returns the unexpired token matching the given
+// secret, or nil if there is none.
+func apiTokenForSecret(st *state.State, secret string) (*apiToken, error) {
+	tokens, err := apiTokens(st)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	digest := []byte(tokenDigest(secret))
+	for _, tok := range tokens {
+		if subtle.ConstantTimeCompare(digest, []byte(tok.Digest)) == 1 {
+			if tok.expired(now) {
+				return nil, nil
+			}
+			return tok, nil
+		}
+	}
+	return nil, nil
+}
+
+// apiTokenInstructionFields are the snap instruction fields a request
+// authorized by a token may carry.
+var apiTokenInstructionFields = map[string]bool{
+	"action": true,
+	"snaps":  true,
+	// needed to hold refreshes
+	"time":       true,
+	"hold-level": true,
+}
+
+// apiTokenAllows returns whether the request carries a token allowing
+// the snap operation it requests. The request body is inspected and
+// then restored for the handler.
+func apiTokenAllows(d *Daemon, r *http.Request) bool {
+	secret := bearerToken(r)
+	if secret == "" || r.Method != "POST" {
+		return false
+	}
+
+	var name string
+	switch {
+	case r.URL.Path == "/v2/snaps":
+		// multi-snap operation
+	case strings.HasPrefix(r.URL.Path, "/v2/snaps/") && !strings.Contains(r.URL.Path[len("/v2/snaps/"):], "/"):
+		name = r.URL.Path[len("/v2/snaps/"):]
+	default:
+		return false
+	}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") && r.Header.Get("Content-Type") != "" {
+		// sideloading is never allowed
+		return false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAPITokenRequestBody+1))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if err != nil || len(body) > maxAPITokenRequestBody {
+		return false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return false
+	}
+	for field := range fields {
+		// any other field (devmode, classic, channel, cohort-key,
+		// purge, transaction, ...) changes what the operation does
+		// beyond what the token was issued for
+		if !apiTokenInstructionFields[field] {
+			return false
+		}
+	}
+	var inst struct {
+		Action string   `json:"action"`
+		Snaps  []string `json:"snaps"`
+	}
+	if err := json.Unmarshal(body, &inst); err != nil {
+		return false
+	}
+	snaps := inst.Snaps
+	if name != "" {
+		snaps = []string{name}
+	}
+
+	st := d.state
+	st.Lock()
+	defer st.Unlock()
+	tok, err := apiTokenForSecret(st, secret)
+	if err != nil || tok == nil {
+		return false
+	}
+	return tok.allows(inst.Action, snaps)
+}
+
+func clientAPIToken(tok *apiToken) client.APIToken {
+	return client.APIToken{
+		ID:      tok.ID,
+		Allow:   tok.Allow,
+		Created: tok.Created,
+		Expires: tok.Expires,
+	}
+}
+
+func getAPITokens(c *Command, r *http.Request, user *auth.UserState) Response {
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	tokens, err := apiTokens(st)
+	if err != nil {
+		return InternalError("cannot list tokens: %v", err)
+	}
+	res := make([]client.APIToken, 0, len(tokens))
+	for _, tok := range tokens {
+		res = append(res, clientAPIToken(tok))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+	return SyncResponse(res)
+}
+
+type apiTokenAction struct {
+	Action  string    `json:"action"`
+	Allow   []string  `json:"allow"`
+	Expires time.Time `json:"expires"`
+	ID      string    `json:"id"`
+}
+
+func postAPITokens(c *Command, r *http.Request, user *auth.UserState) Response {
+	var action apiTokenAction
+	dec := json.NewDecoder(r.Body)
+	if err := dec.Decode(&action); err != nil {
+		return BadRequest("cannot decode request body into token action: %v", err)
+	}
+	if dec.More() {
+		return BadRequest("spurious content after token action")
+	}
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	switch action.Action {
+	case "create":
+		if err := validateAPITokenAllow(action.Allow); err != nil {
+			return BadRequest(err.Error())
+		}
+		if !action.Expires.After(time.Now()) {
+			return BadRequest("tokens must expire in the future")
+		}
+		tok, secret, err := addAPIToken(st, action.Allow, action.Expires)
+		if err != nil {
+			return InternalError("cannot create token: %v", err)
+		}
+		res := clientAPIToken(tok)
+		res.Token = secret
+		return SyncResponse(res)
+	case "revoke":
+		if err := removeAPIToken(st, action.ID); err != nil {
+			return NotFound(err.Error())
+		}
+		return SyncResponse(nil)
+	default:
+		return BadRequest("unknown token action %q", action.Action)
+	}
+}
@@ -0,0 +1,205 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/dirs"
+)
+
+var _ = check.Suite(&apiTokensSuite{})
+
+type apiTokensSuite struct {
+	apiBaseSuite
+}
+
+func (s *apiTokensSuite) SetUpTest(c *check.C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectRootAccess()
+}
+
+func (s *apiTokensSuite) TestCreateListRevokeTokens(c *check.C) {
+	s.daemon(c)
+
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	body := fmt.Sprintf(`{"action": "create", "allow": ["refresh:foo", "hold"], "expires": %q}`, expires.Format(time.RFC3339))
+	req, err := http.NewRequest("POST", "/v2/tokens", bytes.NewBufferString(body))
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	created := rsp.Result.(client.APIToken)
+	c.Check(created.ID, check.Not(check.Equals), "")
+	c.Check(created.Allow, check.DeepEquals, []string{"refresh:foo", "hold"})
+	c.Check(created.Expires.Equal(expires), check.Equals, true)
+	c.Check(created.Token, check.Not(check.Equals), "")
+
+	req, err = http.NewRequest("GET", "/v2/tokens", nil)
+	c.Assert(err, check.IsNil)
+	rsp = s.syncReq(c, req, nil)
+	tokens := rsp.Result.([]client.APIToken)
+	c.Assert(tokens, check.HasLen, 1)
+	c.Check(tokens[0].ID, check.Equals, created.ID)
+	c.Check(tokens[0].Allow, check.DeepEquals, []string{"refresh:foo", "hold"})
+	// the secret is not listed
+	c.Check(tokens[0].Token, check.Equals, "")
+
+	req, err = http.NewRequest("POST", "/v2/tokens", bytes.NewBufferString(`{"action": "revoke", "id": "`+created.ID+`"}`))
+	c.Assert(err, check.IsNil)
+	s.syncReq(c, req, nil)
+
+	req, err = http.NewRequest("GET", "/v2/tokens", nil)
+	c.Assert(err, check.IsNil)
+	rsp = s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.HasLen, 0)
+}
+
+func (s *apiTokensSuite) TestTokensErrors(c *check.C) {
+	s.daemon(c)
+
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	for _, t := range []struct {
+		body   string
+		status int
+		msg    string
+	}{
+		{`{"action": "create", "expires": "` + future + `"}`, 400, `tokens must allow at least one operation`},
+		{`{"action": "create", "allow": ["frobnicate"], "expires": "` + future + `"}`, 400, `cannot allow unknown operation "frobnicate"`},
+		{`{"action": "create", "allow": ["refresh:Foo"], "expires": "` + future + `"}`, 400, `cannot allow "refresh:Foo": invalid snap name: "Foo"`},
+		{`{"action": "create", "allow": ["refresh"], "expires": "` + past + `"}`, 400, `tokens must expire in the future`},
+		{`{"action": "create", "allow": ["refresh"]}`, 400, `tokens must expire in the future`},
+		{`{"action": "frobnicate"}`, 400, `unknown token action "frobnicate"`},
+		{`{"action": "revoke", "id": "missing"}`, 404, `token "missing" not found`},
+	} {
+		req, err := http.NewRequest("POST", "/v2/tokens", bytes.NewBufferString(t.body))
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, t.status, check.Commentf(t.body))
+		c.Check(rspe.Message, check.Equals, t.msg, check.Commentf(t.body))
+	}
+}
+
+func (s *apiTokensSuite) TestAuthenticatedAccessWithToken(c *check.C) {
+	d := s.daemon(c)
+
+	st := d.Overlord().State()
+	st.Lock()
+	fooToken, err := daemon.AddAPIToken(st, []string{"refresh:foo", "hold"}, time.Now().Add(time.Hour))
+	c.Assert(err, check.IsNil)
+	expiredToken, err := daemon.AddAPIToken(st, []string{"refresh"}, time.Now().Add(-time.Hour))
+	c.Assert(err, check.IsNil)
+	st.Unlock()
+
+	ac := daemon.AuthenticatedAccess{}
+	ucred := &daemon.Ucrednet{Uid: 42, Pid: 100, Socket: dirs.SnapdSocket}
+
+	request := func(path, token, body string) *http.Request {
+		req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req
+	}
+
+	for _, t := range []struct {
+		path, token, body string
+		allowed           bool
+	}{
+		{"/v2/snaps/foo", fooToken, `{"action": "refresh"}`, true},
+		{"/v2/snaps/bar", fooToken, `{"action": "refresh"}`, false},
+		{"/v2/snaps/foo", fooToken, `{"action": "remove"}`, false},
+		{"/v2/snaps", fooToken, `{"action": "refresh", "snaps": ["foo"]}`, true},
+		{"/v2/snaps", fooToken, `{"action": "refresh", "snaps": ["foo", "bar"]}`, false},
+		// refreshing all snaps needs a rule without snap
+		{"/v2/snaps", fooToken, `{"action": "refresh"}`, false},
+		{"/v2/snaps", fooToken, `{"action": "hold"}`, true},
+		{"/v2/snaps/bar", fooToken, `{"action": "hold"}`, true},
+		// other endpoints are never allowed
+		{"/v2/snaps/foo/conf", fooToken, `{"action": "refresh"}`, false},
+		{"/v2/aliases", fooToken, `{"action": "refresh"}`, false},
+		{"/v2/snaps/foo", "", `{"action": "refresh"}`, false},
+		{"/v2/snaps/foo", "unknown", `{"action": "refresh"}`, false},
+		{"/v2/snaps/foo", expiredToken, `{"action": "refresh"}`, false},
+	} {
+		req := request(t.path, t.token, t.body)
+		rspe := ac.CheckAccess(d, req, ucred, nil)
+		if t.allowed {
+			c.Check(rspe, check.IsNil, check.Commentf("%s %s", t.path, t.body))
+		} else {
+			c.Check(rspe, check.DeepEquals, daemon.Unauthorized("access denied"), check.Commentf("%s %s", t.path, t.body))
+		}
+		// the body is still available to the handler
+		body, err := io.ReadAll(req.Body)
+		c.Assert(err, check.IsNil)
+		c.Check(string(body), check.Equals, t.body)
+	}
+
+	// the plain operation only, any other option is refused
+	for _, field := range []string{
+		`"devmode": true`,
+		`"jailmode": true`,
+		`"classic": true`,
+		`"dangerous": true`,
+		`"ignore-validation": true`,
+		`"ignore-running": true`,
+		`"unaliased": true`,
+		`"prefer": true`,
+		`"purge": true`,
+		`"amend": true`,
+		`"system-restart-immediate": true`,
+		`"transaction": "all-snaps"`,
+		`"channel": "edge"`,
+		`"revision": "42"`,
+		`"cohort-key": "cohort"`,
+		`"leave-cohort": true`,
+		`"users": ["all"]`,
+		`"validation-sets": ["foo/bar"]`,
+		`"quota-group": "group"`,
+		`"snapshot-options": {}`,
+		`"block-revision": "42"`,
+		`"pin": true`,
+	} {
+		for _, path := range []string{"/v2/snaps/foo", "/v2/snaps"} {
+			body := fmt.Sprintf(`{"action": "refresh", "snaps": ["foo"], %s}`, field)
+			req := request(path, fooToken, body)
+			c.Check(ac.CheckAccess(d, req, ucred, nil), check.DeepEquals, daemon.Unauthorized("access denied"), check.Commentf("%s %s", path, body))
+		}
+	}
+
+	// holding can be given a time and level
+	req := request("/v2/snaps", fooToken, `{"action": "hold", "snaps": ["foo"], "time": "forever", "hold-level": "general"}`)
+	c.Check(ac.CheckAccess(d, req, ucred, nil), check.IsNil)
+
+	// sideloading is never allowed
+	req = request("/v2/snaps", fooToken, `{"action": "refresh", "snaps": ["foo"]}`)
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=foo")
+	c.Check(ac.CheckAccess(d, req, ucred, nil), check.DeepEquals, daemon.Unauthorized("access denied"))
+}
@@ -46,6 +46,7 @@ import (
 	"github.com/snapcore/snapd/overlord/restart"
 	"github.com/snapcore/snapd/overlord/standby"
 	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/sandbox"
 	"github.com/snapcore/snapd/snapdenv"
 	"github.com/snapcore/snapd/store"
 	"github.com/snapcore/snapd/systemd"
@@ -70,6 +71,7 @@ type Daemon struct {
 	state           *state.State
 	snapdListener   net.Listener
 	snapListener    net.Listener
+	remoteListener  net.Listener
 	connTracker     *connTracker
 	serve           *http.Server
 	tomb            tomb.Tomb
@@ -88,6 +90,12 @@ type Daemon struct {
 
 	expectedRebootDidNotHappen bool
 
+	// startUpDone is closed once the lazy initialization of the
+	// managers, performed while serving the API, is done
+	startUpDone chan struct{}
+	// startUpErr is set if the lazy initialization failed
+	startUpErr error
+
 	mu sync.Mutex
 }
 
@@ -107,15 +115,38 @@ type Command struct {
 	ReadAccess  accessChecker
 	WriteAccess accessChecker
 
+	// ServedDuringStartUp is set for commands which are served while the
+	// managers are still being initialized. Such commands must not
+	// expect the state to be available, and are served without a user.
+	// Other commands wait for the initialization to be done.
+	ServedDuringStartUp bool
+
 	d *Daemon
 }
 
 func (c *Command) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	startingUp := c.d.startingUp()
+	if startingUp && !c.ServedDuringStartUp {
+		select {
+		case <-c.d.startUpDone:
+		case <-r.Context().Done():
+			return
+		}
+		if c.d.startUpErr != nil {
+			InternalError("cannot start up: %v", c.d.startUpErr).ServeHTTP(w, r)
+			return
+		}
+		startingUp = false
+	}
+
 	st := c.d.state
-	st.Lock()
-	// TODO Look at the error and fail if there's an attempt to authenticate with invalid data.
-	user, _ := userFromRequest(st, r)
-	st.Unlock()
+	var user *auth.UserState
+	if !startingUp {
+		st.Lock()
+		// TODO Look at the error and fail if there's an attempt to authenticate with invalid data.
+		user, _ = userFromRequest(st, r)
+		st.Unlock()
+	}
 
 	// check if we are in degradedMode
 	if c.d.degradedErr != nil && r.Method != "GET" {
@@ -148,6 +179,13 @@ func (c *Command) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		access = c.WriteAccess
 	}
 
+	if r.TLS != nil {
+		// requests received via the remote API are authorized by
+		// the scope of their token only
+		access = remoteAccess{local: access}
+		user = nil
+	}
+
 	if rspf == nil {
 		MethodNotAllowed("method %q not allowed", r.Method).ServeHTTP(w, r)
 		return
@@ -160,7 +198,7 @@ func (c *Command) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	rsp := rspf(c, r, user)
 
-	if srsp, ok := rsp.(StructuredResponse); ok {
+	if srsp, ok := rsp.(StructuredResponse); ok && !startingUp {
 		rjson := srsp.JSON()
 
 		st.Lock()
@@ -169,8 +207,14 @@ func (c *Command) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		rjson.addMaintenanceFromRestartType(rst)
 
 		if rjson.Type != ResponseTypeError {
+			// count only the warnings not yet acknowledged by
+			// the requesting user, when known
+			var userID *uint32
+			if ucred != nil {
+				userID = &ucred.Uid
+			}
 			st.Lock()
-			count, stamp := st.WarningsSummary()
+			count, stamp := st.UserWarningsSummary(userID)
 			st.Unlock()
 			rjson.addWarningCount(count, stamp)
 		}
@@ -245,6 +289,9 @@ func (d *Daemon) Init() error {
 	d.addRoutes()
 
 	logger.Noticef("started %v.", snapdenv.UserAgent())
+	for _, d := range sandbox.DegradedFeatures() {
+		logger.Noticef("%s is disabled: %s", d.Feature, d.Reason)
+	}
 
 	return nil
 }
@@ -311,6 +358,9 @@ func (ct *connTracker) trackConn(conn net.Conn, state http.ConnState) {
 func (d *Daemon) initStandbyHandling() {
 	d.standbyOpinions = standby.New(d.state)
 	d.standbyOpinions.AddOpinion(d.connTracker)
+	if rl, ok := d.remoteListener.(standby.Opinionator); ok {
+		d.standbyOpinions.AddOpinion(rl)
+	}
 	d.standbyOpinions.AddOpinion(d.overlord)
 	d.standbyOpinions.AddOpinion(d.overlord.SnapManager())
 	d.standbyOpinions.AddOpinion(d.overlord.DeviceManager())
@@ -340,11 +390,19 @@ func (d *Daemon) Start() error {
 		logger.Noticef("adjusting startup timeout by %v (%s)", to, reasoning)
 		systemdSdNotify(fmt.Sprintf("EXTEND_TIMEOUT_USEC=%d", us))
 	}
-	// now perform expensive overlord/manages initialization
-	if err := d.overlord.StartUp(); err != nil {
+	// now perform expensive overlord/managers initialization, as far as
+	// needed to serve the API, the remaining one is performed lazily
+	// once serving
+	if err := d.overlord.StartUpCritical(); err != nil {
 		return err
 	}
 
+	// the remote API is opt-in, failing to set it up must not prevent
+	// serving the local sockets
+	if err := d.initRemoteAPI(); err != nil {
+		logger.Noticef("cannot enable remote API: %v", err)
+	}
+
 	d.connTracker = &connTracker{conns: make(map[net.Conn]struct{})}
 	d.serve = &http.Server{
 		Handler:   logit(d.router),
@@ -361,8 +419,24 @@ func (d *Daemon) Start() error {
 		return err
 	}
 
-	// the loop runs in its own goroutine
-	d.overlord.Loop()
+	d.startUpDone = make(chan struct{})
+	d.tomb.Go(func() error {
+		if err := d.overlord.StartUp(); err != nil {
+			d.startUpErr = err
+			close(d.startUpDone)
+			return err
+		}
+		close(d.startUpDone)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		// do not start the loop if the daemon is being stopped
+		if d.tomb.Err() == tomb.ErrStillAlive {
+			// the loop runs in its own goroutine
+			d.overlord.Loop()
+		}
+		return nil
+	})
 
 	d.tomb.Go(func() error {
 		if d.snapListener != nil {
@@ -375,6 +449,16 @@ func (d *Daemon) Start() error {
 			})
 		}
 
+		if d.remoteListener != nil {
+			d.tomb.Go(func() error {
+				if err := d.serve.Serve(d.remoteListener); err != http.ErrServerClosed && d.tomb.Err() == tomb.ErrStillAlive {
+					return err
+				}
+
+				return nil
+			})
+		}
+
 		if err := d.serve.Serve(d.snapdListener); err != http.ErrServerClosed && d.tomb.Err() == tomb.ErrStillAlive {
 			return err
 		}
@@ -387,6 +471,20 @@ func (d *Daemon) Start() error {
 	return nil
 }
 
+// startingUp returns whether the lazy initialization of the managers is
+// still in progress.
+func (d *Daemon) startingUp() bool {
+	if d.startUpDone == nil {
+		return false
+	}
+	select {
+	case <-d.startUpDone:
+		return false
+	default:
+		return true
+	}
+}
+
 // HandleRestart implements overlord.RestartBehavior.
 func (d *Daemon) HandleRestart(t restart.RestartType, rebootInfo *boot.RebootInfo) {
 	d.mu.Lock()
@@ -525,6 +623,10 @@ func (d *Daemon) Stop(sigCh chan<- os.Signal) error {
 	}
 	timeSpent := time.Since(ts)
 
+	if d.remoteListener != nil {
+		d.remoteListener.Close()
+	}
+
 	// When shutting down the snapd listener wait until the rebootNoticeWait
 	// period has passed before snapdListener is closed to allow polling
 	// clients to access the daemon. For testing we disable this unless SNAPD_SHUTDOWN_DELAY
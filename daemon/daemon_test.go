@@ -124,6 +124,106 @@ func (mck *mockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	mck.lastMethod = r.Method
 }
 
+func (s *daemonSuite) TestCommandServedDuringStartUp(c *check.C) {
+	d := s.newTestDaemon(c)
+	// pretend the lazy startup of the managers is in progress
+	d.startUpDone = make(chan struct{})
+
+	// which holds the state lock
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+
+	var served bool
+	cmd := &Command{d: d}
+	cmd.GET = func(innerCmd *Command, req *http.Request, user *auth.UserState) Response {
+		served = true
+		c.Check(user, check.IsNil)
+		return SyncResponse(nil)
+	}
+	cmd.ReadAccess = openAccess{}
+	cmd.ServedDuringStartUp = true
+
+	req, err := http.NewRequest("GET", "", nil)
+	c.Assert(err, check.IsNil)
+	req.RemoteAddr = fmt.Sprintf("pid=100;uid=1001;socket=%s;", dirs.SnapdSocket)
+	rec := httptest.NewRecorder()
+	cmd.ServeHTTP(rec, req)
+	c.Check(rec.Code, check.Equals, 200)
+	c.Check(served, check.Equals, true)
+}
+
+func (s *daemonSuite) TestCommandWaitsForStartUp(c *check.C) {
+	d := s.newTestDaemon(c)
+	// pretend the lazy startup of the managers is in progress
+	d.startUpDone = make(chan struct{})
+
+	cmd := &Command{d: d}
+	cmd.GET = func(innerCmd *Command, req *http.Request, user *auth.UserState) Response {
+		return SyncResponse(nil)
+	}
+	cmd.ReadAccess = openAccess{}
+
+	req, err := http.NewRequest("GET", "", nil)
+	c.Assert(err, check.IsNil)
+	req.RemoteAddr = fmt.Sprintf("pid=100;uid=1001;socket=%s;", dirs.SnapdSocket)
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cmd.ServeHTTP(rec, req)
+	}()
+
+	select {
+	case <-done:
+		c.Fatalf("command served before the startup is done")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(d.startUpDone)
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		c.Fatalf("command not served after the startup is done")
+	}
+	c.Check(rec.Code, check.Equals, 200)
+}
+
+func (s *daemonSuite) TestCommandStartUpError(c *check.C) {
+	d := s.newTestDaemon(c)
+	// pretend the lazy startup of the managers is in progress
+	d.startUpDone = make(chan struct{})
+
+	cmd := &Command{d: d}
+	cmd.GET = func(innerCmd *Command, req *http.Request, user *auth.UserState) Response {
+		c.Errorf("unexpected call")
+		return SyncResponse(nil)
+	}
+	cmd.ReadAccess = openAccess{}
+
+	req, err := http.NewRequest("GET", "", nil)
+	c.Assert(err, check.IsNil)
+	req.RemoteAddr = fmt.Sprintf("pid=100;uid=1001;socket=%s;", dirs.SnapdSocket)
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cmd.ServeHTTP(rec, req)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// the startup fails
+	d.startUpErr = fmt.Errorf("boom")
+	close(d.startUpDone)
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		c.Fatalf("command not served after the startup is done")
+	}
+	c.Check(rec.Code, check.Equals, 500)
+	c.Check(rec.Body.String(), testutil.Contains, "cannot start up: boom")
+}
+
 func (s *daemonSuite) TestCommandMethodDispatch(c *check.C) {
 	d := s.newTestDaemon(c)
 	st := d.Overlord().State()
@@ -1353,6 +1453,8 @@ func (s *daemonSuite) TestRestartIntoSocketModeNoNewChanges(c *check.C) {
 	s.markSeeded(d)
 
 	c.Assert(d.Start(), check.IsNil)
+	// wait for the lazy startup of the managers
+	<-d.startUpDone
 	// pretend some ensure happened
 	for i := 0; i < 5; i++ {
 		c.Check(d.overlord.StateEngine().Ensure(), check.IsNil)
@@ -1383,6 +1485,8 @@ func (s *daemonSuite) TestRestartIntoSocketModePendingChanges(c *check.C) {
 	st := d.overlord.State()
 
 	c.Assert(d.Start(), check.IsNil)
+	// wait for the lazy startup of the managers
+	<-d.startUpDone
 	// pretend some ensure happened
 	for i := 0; i < 5; i++ {
 		c.Check(d.overlord.StateEngine().Ensure(), check.IsNil)
@@ -0,0 +1,44 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"time"
+
+	"github.com/snapcore/snapd/interfaces/seccomp"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+func MockSnapstateSyscallProfiling(f func(st *state.State, instanceName string, enable bool) (*state.TaskSet, error)) (restore func()) {
+	old := snapstateSyscallProfiling
+	snapstateSyscallProfiling = f
+	return func() {
+		snapstateSyscallProfiling = old
+	}
+}
+
+func MockSeccompReportSyscallProfiling(f func(snapInfo *snap.Info, since time.Time) (*seccomp.ProfilingReport, error)) (restore func()) {
+	old := seccompReportSyscallProfiling
+	seccompReportSyscallProfiling = f
+	return func() {
+		seccompReportSyscallProfiling = old
+	}
+}
@@ -39,7 +39,7 @@ func MockSystemdVirt(newVirt string) (restore func()) {
 	return func() { systemdVirt = oldVirt }
 }
 
-func MockWarningsAccessors(okay func(*state.State, time.Time) int, all func(*state.State) []*state.Warning, pending func(*state.State) ([]*state.Warning, time.Time)) (restore func()) {
+func MockWarningsAccessors(okay func(*state.State, *uint32, time.Time) int, all func(*state.State) []*state.Warning, pending func(*state.State, *uint32) ([]*state.Warning, time.Time)) (restore func()) {
 	oldOK := stateOkayWarnings
 	oldAll := stateAllWarnings
 	oldPending := statePendingWarnings
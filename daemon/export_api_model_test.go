@@ -34,6 +34,14 @@ func MockDevicestateRemodel(mock func(*state.State, *asserts.Model, []*snap.Side
 	}
 }
 
+func MockDevicestateReissueModel(mock func(*state.State, *asserts.Model) (*state.Change, error)) (restore func()) {
+	oldDevicestateReissueModel := devicestateReissueModel
+	devicestateReissueModel = mock
+	return func() {
+		devicestateReissueModel = oldDevicestateReissueModel
+	}
+}
+
 func MockDevicestateDeviceManagerUnregister(mock func(*devicestate.DeviceManager, *devicestate.UnregisterOptions) error) (restore func()) {
 	oldDevicestateDeviceManagerUnregister := devicestateDeviceManagerUnregister
 	devicestateDeviceManagerUnregister = mock
@@ -0,0 +1,53 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"net"
+
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+type RemoteAccess = remoteAccess
+
+func NewRemoteAccess(local accessChecker) RemoteAccess {
+	return remoteAccess{local: local}
+}
+
+func MockRemoteAPIListen(f func(network, addr string) (net.Listener, error)) (restore func()) {
+	old := remoteAPIListen
+	remoteAPIListen = f
+	return func() {
+		remoteAPIListen = old
+	}
+}
+
+func AddRemoteAPIToken(st *state.State, scope string) (secret string, err error) {
+	_, secret, err = addRemoteAPIToken(st, scope)
+	return secret, err
+}
+
+func (d *Daemon) InitRemoteAPI() error {
+	return d.initRemoteAPI()
+}
+
+func (d *Daemon) RemoteListener() net.Listener {
+	return d.remoteListener
+}
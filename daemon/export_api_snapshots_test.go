@@ -64,7 +64,7 @@ func MockSnapshotCheck(newCheck func(*state.State, uint64, []string, []string) (
 	}
 }
 
-func MockSnapshotRestore(newRestore func(*state.State, uint64, []string, []string) ([]string, *state.TaskSet, error)) (restore func()) {
+func MockSnapshotRestore(newRestore func(*state.State, uint64, []string, []string, *client.SnapshotRestoreTarget) ([]string, *state.TaskSet, error)) (restore func()) {
 	oldRestore := snapshotRestore
 	snapshotRestore = newRestore
 	return func() {
@@ -88,6 +88,22 @@ func MockSnapshotImport(newImport func(context.Context, *state.State, io.Reader)
 	}
 }
 
+func MockSnapshotFiles(newFiles func(context.Context, *state.State, uint64, []string) ([]client.SnapshotFile, error)) (restore func()) {
+	oldFiles := snapshotFiles
+	snapshotFiles = newFiles
+	return func() {
+		snapshotFiles = oldFiles
+	}
+}
+
+func MockSnapshotFile(newFile func(*state.State, uint64, string, string, string) (io.ReadCloser, int64, error)) (restore func()) {
+	oldFile := snapshotFile
+	snapshotFile = newFile
+	return func() {
+		snapshotFile = oldFile
+	}
+}
+
 func MustUnmarshalSnapInstruction(c *check.C, jinst string) *snapInstruction {
 	var inst snapInstruction
 	if err := json.Unmarshal([]byte(jinst), &inst); err != nil {
@@ -105,3 +121,5 @@ func MustUnmarshalSnapshotAction(c *check.C, jact string) *snapshotAction {
 }
 
 type SnapshotExportResponse = snapshotExportResponse
+
+type SnapshotFileResponse = snapshotFileResponse
@@ -0,0 +1,33 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func MockDeviceManagerAttestation(f func(nonce string) (*devicestate.SignedAttestation, error)) (restore func()) {
+	restore = testutil.Backup(&deviceManagerAttestation)
+	deviceManagerAttestation = func(_ *devicestate.DeviceManager, nonce string) (*devicestate.SignedAttestation, error) {
+		return f(nonce)
+	}
+	return restore
+}
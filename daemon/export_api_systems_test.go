@@ -56,3 +56,9 @@ func MockDevicestateInstallSetupStorageEncryption(f func(*state.State, string, m
 	devicestateInstallSetupStorageEncryption = f
 	return restore
 }
+
+func MockDeviceManagerRequestRecovery(f func(*devicestate.DeviceManager, string, string, string) error) (restore func()) {
+	restore = testutil.Backup(&deviceManagerRequestRecovery)
+	deviceManagerRequestRecovery = f
+	return restore
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/snapcore/snapd/aspects"
 	"github.com/snapcore/snapd/asserts/snapasserts"
 	"github.com/snapcore/snapd/boot"
+	"github.com/snapcore/snapd/i18n"
 	"github.com/snapcore/snapd/overlord"
 	"github.com/snapcore/snapd/overlord/assertstate"
 	"github.com/snapcore/snapd/overlord/restart"
@@ -67,6 +68,15 @@ func (d *Daemon) Overlord() *overlord.Overlord {
 	return d.overlord
 }
 
+// MockStartingUp pretends that the lazy startup of the managers is in
+// progress, until the returned function is called.
+func (d *Daemon) MockStartingUp() (done func()) {
+	d.startUpDone = make(chan struct{})
+	return func() {
+		close(d.startUpDone)
+	}
+}
+
 func (d *Daemon) RequestedRestart() restart.RestartType {
 	return d.requestedRestart
 }
@@ -97,6 +107,22 @@ func MockMuxVars(vars func(*http.Request) map[string]string) (restore func()) {
 	}
 }
 
+func MockI18nNegotiateLocale(f func(acceptLanguage string) *i18n.Locale) (restore func()) {
+	old := i18nNegotiateLocale
+	i18nNegotiateLocale = f
+	return func() {
+		i18nNegotiateLocale = old
+	}
+}
+
+func MockTimeNow(f func() time.Time) (restore func()) {
+	old := timeNow
+	timeNow = f
+	return func() {
+		timeNow = old
+	}
+}
+
 func MockShutdownTimeout(tm time.Duration) (restore func()) {
 	old := shutdownTimeout
 	shutdownTimeout = tm
@@ -159,6 +185,30 @@ func MockSnapstateUpdate(mock func(*state.State, string, *snapstate.RevisionOpti
 	}
 }
 
+func MockSnapstateOverrideRefreshInhibition(mock func(*state.State, *snapstate.RefreshInhibitionOverride) (bool, error)) (restore func()) {
+	old := snapstateOverrideRefreshInhibition
+	snapstateOverrideRefreshInhibition = mock
+	return func() {
+		snapstateOverrideRefreshInhibition = old
+	}
+}
+
+func MockSnapstateBlockRevision(mock func(*state.State, string, snap.Revision, time.Time) error) (restore func()) {
+	old := snapstateBlockRevision
+	snapstateBlockRevision = mock
+	return func() {
+		snapstateBlockRevision = old
+	}
+}
+
+func MockSnapstateUnpin(mock func(*state.State, string) error) (restore func()) {
+	old := snapstateUnpin
+	snapstateUnpin = mock
+	return func() {
+		snapstateUnpin = old
+	}
+}
+
 func MockSnapstateTryPath(mock func(*state.State, string, string, snapstate.Flags) (*state.TaskSet, error)) (restore func()) {
 	oldSnapstateTryPath := snapstateTryPath
 	snapstateTryPath = mock
@@ -367,6 +417,22 @@ func MockAspectstateSet(f func(databag aspects.DataBag, account, bundleName, asp
 	}
 }
 
+func MockAspectstateComplete(f func(account, bundleName, aspect, partial string) ([]string, error)) (restore func()) {
+	old := aspectstateCompleteAspect
+	aspectstateCompleteAspect = f
+	return func() {
+		aspectstateCompleteAspect = old
+	}
+}
+
+func MockAspectstateCommitTransaction(f func(st *state.State, account, bundleName string, tx *aspects.Transaction) (*state.TaskSet, error)) (restore func()) {
+	old := aspectstateCommitTransaction
+	aspectstateCommitTransaction = f
+	return func() {
+		aspectstateCommitTransaction = old
+	}
+}
+
 func MockRebootNoticeWait(d time.Duration) (restore func()) {
 	restore = testutil.Backup(&rebootNoticeWait)
 	rebootNoticeWait = d
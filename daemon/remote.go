@@ -0,0 +1,247 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/randutil"
+)
+
+// The remote API is an opt-in TCP listener secured with mutual TLS. It
+// serves the same handlers as the local sockets, with requests authorized
+// by the scope of the token they present instead of peer credentials.
+
+const (
+	// remoteAPIScopeRead allows only GET requests.
+	remoteAPIScopeRead = "read"
+	// remoteAPIScopeManage allows all requests.
+	remoteAPIScopeManage = "manage"
+)
+
+var remoteAPIListen = net.Listen
+
+// remoteAPIListener is the listener of the remote API, it keeps the
+// daemon from going into socket activation standby as the remote API
+// is not socket activated.
+type remoteAPIListener struct {
+	net.Listener
+}
+
+func (l *remoteAPIListener) CanStandby() bool {
+	return false
+}
+
+func remoteAPITLSConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(dirs.SnapRemoteAPIDir, "server.crt"), filepath.Join(dirs.SnapRemoteAPIDir, "server.key"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot load server certificate: %v", err)
+	}
+	caPEM, err := os.ReadFile(filepath.Join(dirs.SnapRemoteAPIDir, "client-ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot load client CA certificates: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("cannot load client CA certificates: no certificates found")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// initRemoteAPI sets up the remote API listener if an address was
+// configured for it and the device model allows it. The remote API is
+// only available with models that carry a grade.
+func (d *Daemon) initRemoteAPI() error {
+	st := d.state
+	st.Lock()
+	defer st.Unlock()
+
+	var addr string
+	tr := config.NewTransaction(st)
+	if err := tr.GetMaybe("core", "remote-api.listen-address", &addr); err != nil {
+		return err
+	}
+	if addr == "" {
+		return nil
+	}
+
+	model, err := d.overlord.DeviceManager().Model()
+	if err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return fmt.Errorf("device model is not yet known")
+		}
+		return err
+	}
+	if model.Grade() == asserts.ModelGradeUnset {
+		return fmt.Errorf("model %s/%s does not allow the remote API", model.BrandID(), model.Model())
+	}
+
+	tlsConf, err := remoteAPITLSConfig()
+	if err != nil {
+		return err
+	}
+	l, err := remoteAPIListen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	d.remoteListener = &remoteAPIListener{Listener: tls.NewListener(l, tlsConf)}
+	logger.Noticef("remote API listening on %s", l.Addr())
+	return nil
+}
+
+// remoteAPIToken is a token granting access to the remote API with the
+// given scope. Only a digest of the token secret is kept.
+type remoteAPIToken struct {
+	ID      string    `json:"id"`
+	Scope   string    `json:"scope"`
+	Digest  string    `json:"digest"`
+	Created time.Time `json:"created"`
+}
+
+func remoteAPITokens(st *state.State) (map[string]*remoteAPIToken, error) {
+	var tokens map[string]*remoteAPIToken
+	if err := st.Get("remote-api-tokens", &tokens); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	if tokens == nil {
+		tokens = make(map[string]*remoteAPIToken)
+	}
+	return tokens, nil
+}
+
+// addRemoteAPIToken creates a new token with the given scope, returning
+// it together with its secret.
+func addRemoteAPIToken(st *state.State, scope string) (*remoteAPIToken, string, error) {
+	tokens, err := remoteAPITokens(st)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randutil.CryptoToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	var id string
+	for id == "" || tokens[id] != nil {
+		id = randutil.RandomString(8)
+	}
+	tok := &remoteAPIToken{
+		ID:      id,
+		Scope:   scope,
+		Digest:  tokenDigest(secret),
+		Created: time.Now(),
+	}
+	tokens[id] = tok
+	st.Set("remote-api-tokens", tokens)
+	return tok, secret, nil
+}
+
+func removeRemoteAPIToken(st *state.State, id string) error {
+	tokens, err := remoteAPITokens(st)
+	if err != nil {
+		return err
+	}
+	if tokens[id] == nil {
+		return fmt.Errorf("remote API token %q not found", id)
+	}
+	delete(tokens, id)
+	st.Set("remote-api-tokens", tokens)
+	return nil
+}
+
+// remoteAPITokenForSecret returns the token matching the given secret,
+// or nil if there is none.
+func remoteAPITokenForSecret(st *state.State, secret string) (*remoteAPIToken, error) {
+	tokens, err := remoteAPITokens(st)
+	if err != nil {
+		return nil, err
+	}
+	digest := []byte(tokenDigest(secret))
+	for _, tok := range tokens {
+		if subtle.ConstantTimeCompare(digest, []byte(tok.Digest)) == 1 {
+			return tok, nil
+		}
+	}
+	return nil, nil
+}
+
+// remoteAccess authorizes requests received via the remote API by the
+// scope of the token they present. Read tokens only allow GET requests
+// to APIs not restricted to root, while requests meant only for snaps
+// are never allowed.
+type remoteAccess struct {
+	local accessChecker
+}
+
+func (ac remoteAccess) CheckAccess(d *Daemon, r *http.Request, ucred *ucrednet, user *auth.UserState) *apiError {
+	if _, ok := ac.local.(snapAccess); ok {
+		return Forbidden("access denied")
+	}
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return Forbidden("access denied")
+	}
+
+	secret := bearerToken(r)
+	if secret == "" {
+		return Unauthorized("access denied")
+	}
+
+	st := d.state
+	st.Lock()
+	tok, err := remoteAPITokenForSecret(st, secret)
+	st.Unlock()
+	if err != nil {
+		return InternalError("cannot check remote API token: %v", err)
+	}
+	if tok == nil {
+		return Unauthorized("access denied")
+	}
+	if tok.Scope == remoteAPIScopeManage {
+		return nil
+	}
+	// read tokens don't grant access to the APIs restricted to root, even
+	// for reading, as these can expose secrets (e.g. recovery keys)
+	if _, ok := ac.local.(rootAccess); ok {
+		return Forbidden("access denied")
+	}
+	if r.Method != "GET" {
+		return Forbidden("access denied")
+	}
+	return nil
+}
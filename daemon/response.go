@@ -260,6 +260,25 @@ func (s snapshotExportResponse) ServeHTTP(w http.ResponseWriter, r *http.Request
 	snapshotstate.UnsetSnapshotOpInProgress(s.st, s.setID)
 }
 
+// A snapshotFileResponse's ServeHTTP method streams a file stored in a
+// snapshot, closing it when done.
+type snapshotFileResponse struct {
+	io.ReadCloser
+	size int64
+	path string
+}
+
+// ServeHTTP from the Response interface
+func (s *snapshotFileResponse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer s.Close()
+	w.Header().Add("Content-Length", strconv.FormatInt(s.size, 10))
+	w.Header().Add("Content-Type", "application/octet-stream")
+	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filepath.Base(s.path)))
+	if _, err := io.Copy(w, s); err != nil {
+		logger.Debugf("cannot stream snapshot file %q: %v", s.path, err)
+	}
+}
+
 // A fileResponse 's ServeHTTP method serves the file
 type fileResponse string
 
@@ -218,8 +218,14 @@ func mapLocal(about aboutSnap, sd clientutil.StatusDecorator) *client.Snap {
 	}
 
 	result.TrackingChannel = snapst.TrackingChannel
+	result.FallbackChannel = snapst.RiskFallbackChannel
+	if result.PublisherChange, err = snapstate.PublisherChange(localSnap.SnapID, localSnap.InstanceName()); err != nil {
+		logger.Noticef("cannot get publisher change of snap %q: %v", localSnap.InstanceName(), err)
+	}
 	result.IgnoreValidation = snapst.IgnoreValidation
 	result.CohortKey = snapst.CohortKey
+	result.Quarantined = snapst.Quarantined
+	result.Pinned = snapst.Pinned
 	result.DevMode = snapst.DevMode
 	result.TryMode = snapst.TryMode
 	result.JailMode = snapst.JailMode
@@ -232,6 +238,7 @@ func mapLocal(about aboutSnap, sd clientutil.StatusDecorator) *client.Snap {
 		result.MountedFrom, _ = os.Readlink(result.MountedFrom)
 	}
 	result.Health = about.health
+	result.Verity = snapstate.VerityStatus(localSnap)
 
 	if !about.hold.IsZero() {
 		result.Hold = &about.hold
@@ -70,6 +70,8 @@ var (
 
 	SnapAssertsDBDir      string
 	SnapCookieDir         string
+	SnapProxyEnvDir       string
+	SnapDNSDir            string
 	SnapTrustedAccountKey string
 	SnapAssertsSpoolDir   string
 	SnapSeqDir            string
@@ -87,23 +89,38 @@ var (
 
 	SnapRollbackDir string
 
+	SnapProblemReportsDir string
+
+	SnapRemoteAPIDir string
+
+	SnapMeasurementsLog string
+
 	SnapCacheDir        string
 	SnapNamesFile       string
 	SnapSectionsFile    string
 	SnapCommandsDB      string
 	SnapAuxStoreInfoDir string
 
+	SnapProfilesCacheDir string
+
 	SnapBinariesDir        string
 	SnapServicesDir        string
 	SnapRuntimeServicesDir string
+	SnapMountUnitsDir      string
 	SnapUserServicesDir    string
 	SnapSystemdConfDir     string
 	SnapDesktopFilesDir    string
 	SnapDesktopIconsDir    string
 	SnapPolkitPolicyDir    string
+	SnapPolkitRuleDir      string
 	SnapSystemdDir         string
 	SnapSystemdRunDir      string
 
+	// service definitions and scan directory of a daemontools-style
+	// supervisor, used on systems without systemd
+	SnapSupervisedServicesDir string
+	SupervisorScanDir         string
+
 	SnapDBusSessionPolicyDir   string
 	SnapDBusSystemPolicyDir    string
 	SnapDBusSessionServicesDir string
@@ -287,6 +304,18 @@ func SnapSystemParamsUnder(rootdir string) string {
 	return filepath.Join(rootdir, snappyDir, "system-params")
 }
 
+// CloudInitFilterReportFileUnder returns the path to the report about
+// filtered cloud-init config under rootdir.
+func CloudInitFilterReportFileUnder(rootdir string) string {
+	return filepath.Join(rootdir, snappyDir, "cloud-init-filtered.json")
+}
+
+// KernelCmdlineProvenanceFileUnder returns the path to the record of the
+// sources of the run mode kernel command line under rootdir.
+func KernelCmdlineProvenanceFileUnder(rootdir string) string {
+	return filepath.Join(rootdir, snappyDir, "kernel-cmdline.json")
+}
+
 // SnapSystemdConfDirUnder returns the path to the systemd conf dir under
 // rootdir.
 func SnapSystemdConfDirUnder(rootdir string) string {
@@ -396,6 +425,8 @@ func SetRootDir(rootdir string) {
 
 	SnapAssertsDBDir = filepath.Join(rootdir, snappyDir, "assertions")
 	SnapCookieDir = filepath.Join(rootdir, snappyDir, "cookie")
+	SnapProxyEnvDir = filepath.Join(rootdir, snappyDir, "proxy")
+	SnapDNSDir = filepath.Join(rootdir, snappyDir, "dns")
 	SnapAssertsSpoolDir = filepath.Join(rootdir, "run/snapd/auto-import")
 	SnapSeqDir = filepath.Join(rootdir, snappyDir, "sequence")
 
@@ -408,6 +439,7 @@ func SetRootDir(rootdir string) {
 	SnapSectionsFile = filepath.Join(SnapCacheDir, "sections")
 	SnapCommandsDB = filepath.Join(SnapCacheDir, "commands.db")
 	SnapAuxStoreInfoDir = filepath.Join(SnapCacheDir, "aux")
+	SnapProfilesCacheDir = filepath.Join(SnapCacheDir, "profiles")
 
 	SnapSeedDir = SnapSeedDirUnder(rootdir)
 	SnapDeviceDir = SnapDeviceDirUnder(rootdir)
@@ -428,10 +460,22 @@ func SetRootDir(rootdir string) {
 
 	SnapRollbackDir = filepath.Join(rootdir, snappyDir, "rollback")
 
+	SnapProblemReportsDir = filepath.Join(rootdir, snappyDir, "problem-reports")
+
+	SnapRemoteAPIDir = filepath.Join(rootdir, snappyDir, "remote-api")
+
+	// the measurements of snaps live as long as the TPM PCR they extend
+	SnapMeasurementsLog = filepath.Join(SnapRunDir, "measurements.log")
+
 	SnapBinariesDir = filepath.Join(SnapMountDir, "bin")
 	SnapServicesDir = filepath.Join(rootdir, "/etc/systemd/system")
 	SnapRuntimeServicesDir = filepath.Join(rootdir, "/run/systemd/system")
+	// mount units of snaps that snapd-generator hands over to systemd at
+	// boot, when the mounts-generator feature is enabled
+	SnapMountUnitsDir = filepath.Join(rootdir, snappyDir, "mount-units")
 	SnapUserServicesDir = filepath.Join(rootdir, "/etc/systemd/user")
+	SnapSupervisedServicesDir = filepath.Join(rootdir, snappyDir, "supervise")
+	SupervisorScanDir = filepath.Join(rootdir, "/etc/service")
 	SnapSystemdConfDir = SnapSystemdConfDirUnder(rootdir)
 	SnapSystemdDir = filepath.Join(rootdir, "/etc/systemd")
 	SnapSystemdRunDir = filepath.Join(rootdir, "/run/systemd")
@@ -444,6 +488,7 @@ func SetRootDir(rootdir string) {
 	SnapDBusSystemServicesDir = filepath.Join(rootdir, snappyDir, "dbus-1", "system-services")
 
 	SnapPolkitPolicyDir = filepath.Join(rootdir, "/usr/share/polkit-1/actions")
+	SnapPolkitRuleDir = filepath.Join(rootdir, "/etc/polkit-1/rules.d")
 
 	CloudInstanceDataFile = filepath.Join(rootdir, "/run/cloud-init/instance-data.json")
 
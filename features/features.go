@@ -70,6 +70,10 @@ const (
 	QuotaGroups
 	// RefreshAppAwarenessUX enables experimental UX improvements for refresh-app-awareness.
 	RefreshAppAwarenessUX
+	// MountsGenerator controls whether snap mount units are produced at boot by snapd-generator.
+	MountsGenerator
+	// UserLocalSnaps controls whether unprivileged users can install snaps into their home directory.
+	UserLocalSnaps
 
 	// lastFeature is the final known feature, it is only used for testing.
 	lastFeature
@@ -112,6 +116,10 @@ var featureNames = map[SnapdFeature]string{
 	QuotaGroups: "quota-groups",
 
 	RefreshAppAwarenessUX: "refresh-app-awareness-ux",
+
+	MountsGenerator: "mounts-generator",
+
+	UserLocalSnaps: "user-local-snaps",
 }
 
 // featuresEnabledWhenUnset contains a set of features that are enabled when not explicitly configured.
@@ -135,6 +143,129 @@ var featuresExported = map[SnapdFeature]bool{
 	MoveSnapHomeDir:               true,
 
 	RefreshAppAwarenessUX: true,
+
+	MountsGenerator: true,
+
+	UserLocalSnaps: true,
+}
+
+// Maturity is the maturity level of a feature.
+type Maturity string
+
+const (
+	// MaturityExperimental is the level of features under development
+	// whose behavior may still change.
+	MaturityExperimental Maturity = "experimental"
+	// MaturityBeta is the level of features complete enough for wider
+	// testing.
+	MaturityBeta Maturity = "beta"
+	// MaturityStable is the level of features that are enabled by
+	// default.
+	MaturityStable Maturity = "stable"
+)
+
+type featureDetails struct {
+	description string
+	maturity    Maturity
+	// requiresRestart is set if the snap applications need to be
+	// restarted for a change of the feature to take effect.
+	requiresRestart bool
+	// requiresReboot is set if the system needs to be rebooted for a
+	// change of the feature to take effect.
+	requiresReboot bool
+	// prerequisites are the features that must be enabled for the
+	// feature to be enabled.
+	prerequisites []SnapdFeature
+}
+
+// featuresDetails describes the known features for listing them.
+var featuresDetails = map[SnapdFeature]featureDetails{
+	Layouts: {
+		description: "Support for layouts in snaps",
+		maturity:    MaturityStable,
+	},
+	ParallelInstances: {
+		description: "Install the same snap multiple times under different instance names",
+		maturity:    MaturityBeta,
+	},
+	Hotplug: {
+		description: "Create slots for hardware devices as they are plugged in",
+		maturity:    MaturityExperimental,
+	},
+	SnapdSnap: {
+		description: "Install the snapd snap on systems using the core snap",
+		maturity:    MaturityBeta,
+	},
+	PerUserMountNamespace: {
+		description:     "Persist the mount namespaces of snaps for each user",
+		maturity:        MaturityExperimental,
+		requiresRestart: true,
+	},
+	RefreshAppAwareness: {
+		description: "Postpone refreshes of snaps with running applications",
+		maturity:    MaturityStable,
+	},
+	ClassicPreservesXdgRuntimeDir: {
+		description:     "Preserve $XDG_RUNTIME_DIR in snaps with classic confinement",
+		maturity:        MaturityStable,
+		requiresRestart: true,
+	},
+	RobustMountNamespaceUpdates: {
+		description: "Update existing mount namespaces of snaps robustly",
+		maturity:    MaturityStable,
+	},
+	UserDaemons: {
+		description: "Support for services running in user sessions",
+		maturity:    MaturityBeta,
+	},
+	DbusActivation: {
+		description: "Activate the services of snaps via D-Bus",
+		maturity:    MaturityStable,
+	},
+	HiddenSnapDataHomeDir: {
+		description:     "Keep the user data of snaps in ~/.snap/data instead of ~/snap",
+		maturity:        MaturityExperimental,
+		requiresRestart: true,
+	},
+	MoveSnapHomeDir: {
+		description:     "Allow moving the user data of snaps to ~/Snap",
+		maturity:        MaturityExperimental,
+		requiresRestart: true,
+	},
+	CheckDiskSpaceRemove: {
+		description: "Check for free disk space before removing a snap, when a snapshot is created",
+		maturity:    MaturityBeta,
+	},
+	CheckDiskSpaceInstall: {
+		description: "Check for free disk space before installing a snap",
+		maturity:    MaturityBeta,
+	},
+	CheckDiskSpaceRefresh: {
+		description: "Check for free disk space before refreshing a snap",
+		maturity:    MaturityBeta,
+	},
+	GateAutoRefreshHook: {
+		description: "Let snaps hold their auto-refresh with the gate-auto-refresh hook",
+		maturity:    MaturityBeta,
+	},
+	QuotaGroups: {
+		description: "Experimental resources of quota groups, such as journal quotas",
+		maturity:    MaturityExperimental,
+	},
+	RefreshAppAwarenessUX: {
+		description:   "Notify users about refreshes of running snaps",
+		maturity:      MaturityExperimental,
+		prerequisites: []SnapdFeature{RefreshAppAwareness},
+	},
+	MountsGenerator: {
+		description:    "Generate the mount units of snaps at boot",
+		maturity:       MaturityExperimental,
+		requiresReboot: true,
+	},
+	UserLocalSnaps: {
+		description: "Let unprivileged users install snaps into their home directory",
+		maturity:    MaturityExperimental,
+	},
 }
 
 // String returns the name of a snapd feature.
@@ -164,6 +295,37 @@ func (f SnapdFeature) IsExported() bool {
 	return featuresExported[f]
 }
 
+// Description returns a short human readable description of the feature.
+func (f SnapdFeature) Description() string {
+	return featuresDetails[f].description
+}
+
+// Maturity returns the maturity level of the feature.
+func (f SnapdFeature) Maturity() Maturity {
+	if maturity := featuresDetails[f].maturity; maturity != "" {
+		return maturity
+	}
+	return MaturityExperimental
+}
+
+// RequiresRestart returns true if the snap applications need to be
+// restarted for a change of the feature to take effect.
+func (f SnapdFeature) RequiresRestart() bool {
+	return featuresDetails[f].requiresRestart
+}
+
+// RequiresReboot returns true if the system needs to be rebooted for a
+// change of the feature to take effect.
+func (f SnapdFeature) RequiresReboot() bool {
+	return featuresDetails[f].requiresReboot
+}
+
+// Prerequisites returns the features that must be enabled for the feature
+// to be enabled.
+func (f SnapdFeature) Prerequisites() []SnapdFeature {
+	return featuresDetails[f].prerequisites
+}
+
 // ControlFile returns the path of the file controlling the exported feature.
 //
 // Snapd considers the feature enabled if the file is present.
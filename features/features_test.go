@@ -56,6 +56,8 @@ func (*featureSuite) TestName(c *C) {
 	c.Check(features.GateAutoRefreshHook.String(), Equals, "gate-auto-refresh-hook")
 	c.Check(features.QuotaGroups.String(), Equals, "quota-groups")
 	c.Check(features.RefreshAppAwarenessUX.String(), Equals, "refresh-app-awareness-ux")
+	c.Check(features.MountsGenerator.String(), Equals, "mounts-generator")
+	c.Check(features.UserLocalSnaps.String(), Equals, "user-local-snaps")
 	c.Check(func() { _ = features.SnapdFeature(1000).String() }, PanicMatches, "unknown feature flag code 1000")
 }
 
@@ -86,6 +88,8 @@ func (*featureSuite) TestIsExported(c *C) {
 	c.Check(features.CheckDiskSpaceRemove.IsExported(), Equals, false)
 	c.Check(features.GateAutoRefreshHook.IsExported(), Equals, false)
 	c.Check(features.RefreshAppAwarenessUX.IsExported(), Equals, true)
+	c.Check(features.MountsGenerator.IsExported(), Equals, true)
+	c.Check(features.UserLocalSnaps.IsExported(), Equals, true)
 }
 
 func (*featureSuite) TestIsEnabled(c *C) {
@@ -125,6 +129,33 @@ func (*featureSuite) TestIsEnabledWhenUnset(c *C) {
 	c.Check(features.CheckDiskSpaceRemove.IsEnabledWhenUnset(), Equals, false)
 	c.Check(features.GateAutoRefreshHook.IsEnabledWhenUnset(), Equals, false)
 	c.Check(features.RefreshAppAwarenessUX.IsEnabledWhenUnset(), Equals, false)
+	c.Check(features.MountsGenerator.IsEnabledWhenUnset(), Equals, false)
+	c.Check(features.UserLocalSnaps.IsEnabledWhenUnset(), Equals, false)
+}
+
+func (*featureSuite) TestDetails(c *C) {
+	for _, f := range features.KnownFeatures() {
+		c.Check(f.Description(), Not(Equals), "", Commentf("feature: %s", f))
+		// features enabled by default are stable
+		if f.IsEnabledWhenUnset() {
+			c.Check(f.Maturity(), Equals, features.MaturityStable, Commentf("feature: %s", f))
+		} else {
+			c.Check(f.Maturity(), Not(Equals), features.MaturityStable, Commentf("feature: %s", f))
+		}
+	}
+
+	c.Check(features.Hotplug.Maturity(), Equals, features.MaturityExperimental)
+	c.Check(features.ParallelInstances.Maturity(), Equals, features.MaturityBeta)
+
+	c.Check(features.PerUserMountNamespace.RequiresRestart(), Equals, true)
+	c.Check(features.PerUserMountNamespace.RequiresReboot(), Equals, false)
+	c.Check(features.MountsGenerator.RequiresRestart(), Equals, false)
+	c.Check(features.MountsGenerator.RequiresReboot(), Equals, true)
+	c.Check(features.Layouts.RequiresRestart(), Equals, false)
+	c.Check(features.Layouts.RequiresReboot(), Equals, false)
+
+	c.Check(features.RefreshAppAwarenessUX.Prerequisites(), DeepEquals, []features.SnapdFeature{features.RefreshAppAwareness})
+	c.Check(features.RefreshAppAwareness.Prerequisites(), HasLen, 0)
 }
 
 func (*featureSuite) TestControlFile(c *C) {
@@ -135,6 +166,8 @@ func (*featureSuite) TestControlFile(c *C) {
 	c.Check(features.HiddenSnapDataHomeDir.ControlFile(), Equals, "/var/lib/snapd/features/hidden-snap-folder")
 	c.Check(features.MoveSnapHomeDir.ControlFile(), Equals, "/var/lib/snapd/features/move-snap-home-dir")
 	c.Check(features.RefreshAppAwarenessUX.ControlFile(), Equals, "/var/lib/snapd/features/refresh-app-awareness-ux")
+	c.Check(features.MountsGenerator.ControlFile(), Equals, "/var/lib/snapd/features/mounts-generator")
+	c.Check(features.UserLocalSnaps.ControlFile(), Equals, "/var/lib/snapd/features/user-local-snaps")
 	// Features that are not exported don't have a control file.
 	c.Check(features.Layouts.ControlFile, PanicMatches, `cannot compute the control file of feature "layouts" because that feature is not exported`)
 }
@@ -117,6 +117,17 @@ type KernelCmdline struct {
 	Remove []kcmdline.ArgumentPattern `yaml:"remove"`
 }
 
+// CloudInit is the gadget policy for cloud-init configuration.
+type CloudInit struct {
+	// AllowedDatasources restricts the datasources cloud-init may use, both
+	// when filtering config from ubuntu-seed and after first boot.
+	AllowedDatasources []string `yaml:"allowed-datasources,omitempty"`
+	// AllowedModules lists top-level cloud-config keys (i.e. config
+	// modules such as "users" or "ntp") that are kept when filtering config
+	// from ubuntu-seed.
+	AllowedModules []string `yaml:"allowed-modules,omitempty"`
+}
+
 type Info struct {
 	Volumes map[string]*Volume `yaml:"volumes,omitempty"`
 
@@ -126,6 +137,8 @@ type Info struct {
 	Connections []Connection `yaml:"connections"`
 
 	KernelCmdline KernelCmdline `yaml:"kernel-cmdline"`
+
+	CloudInit *CloudInit `yaml:"cloud-init,omitempty"`
 }
 
 // PartialProperty is a gadget property that can be partially defined.
@@ -761,9 +774,35 @@ func AllDiskVolumeDeviceTraits(allVols map[string]*Volume, optsPerVolume map[str
 // "system" indicates a system plug or slot.
 // Fully omitting the slot part indicates a system slot with the same name
 // as the plug.
+//
+// An optional "apply" key controls when the connection is made, see
+// ConnectionApply.
 type Connection struct {
-	Plug ConnectionPlug `yaml:"plug"`
-	Slot ConnectionSlot `yaml:"slot"`
+	Plug  ConnectionPlug  `yaml:"plug"`
+	Slot  ConnectionSlot  `yaml:"slot"`
+	Apply ConnectionApply `yaml:"apply,omitempty"`
+}
+
+// ConnectionApply controls when a gadget connection is made.
+type ConnectionApply string
+
+const (
+	// ConnectionApplySeeding makes the connection only while seeding or
+	// remodeling, this is the default.
+	ConnectionApplySeeding ConnectionApply = "seeding"
+	// ConnectionApplyAlways also makes the connection when any of the
+	// snaps involved is installed or refreshed later, and when the gadget
+	// is refreshed. Connections removed by the user are left alone.
+	ConnectionApplyAlways ConnectionApply = "always"
+	// ConnectionApplyEnforce is like ConnectionApplyAlways but the
+	// connection is also made again if it was removed by the user.
+	ConnectionApplyEnforce ConnectionApply = "enforce"
+)
+
+// BeyondSeeding returns whether the connection is to be made also after
+// the device was seeded.
+func (a ConnectionApply) BeyondSeeding() bool {
+	return a == ConnectionApplyAlways || a == ConnectionApplyEnforce
 }
 
 type ConnectionPlug struct {
@@ -965,6 +1004,17 @@ func InfoFromGadgetYaml(gadgetYaml []byte, model Model) (*Info, error) {
 			gi.Connections[i].Slot.SnapID = "system"
 			gi.Connections[i].Slot.Slot = gconn.Plug.Plug
 		}
+		switch gconn.Apply {
+		case "", ConnectionApplySeeding, ConnectionApplyAlways, ConnectionApplyEnforce:
+		default:
+			return nil, fmt.Errorf("invalid gadget connection apply value %q", gconn.Apply)
+		}
+	}
+
+	if gi.CloudInit != nil {
+		if err := validateCloudInit(gi.CloudInit); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(gi.Volumes) == 0 && classicOrUndetermined(model) {
@@ -1005,13 +1055,13 @@ func InfoFromGadgetYaml(gadgetYaml []byte, model Model) (*Info, error) {
 			// pass
 		case "grub", "u-boot", "android-boot", "lk":
 			bootloadersFound += 1
-		case "piboot":
+		case "piboot", "systemd-boot":
 			if !compatWithPibootOrIndeterminate(model) {
-				return nil, errors.New("piboot bootloader valid only for UC20 onwards")
+				return nil, fmt.Errorf("%s bootloader valid only for UC20 onwards", v.Bootloader)
 			}
 			bootloadersFound += 1
 		default:
-			return nil, errors.New("bootloader must be one of grub, u-boot, android-boot, piboot or lk")
+			return nil, errors.New("bootloader must be one of grub, u-boot, android-boot, piboot, systemd-boot or lk")
 		}
 	}
 	switch {
@@ -1778,6 +1828,22 @@ func isKernelArgumentAllowed(arg string) bool {
 	return true
 }
 
+var validCloudInitName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+func validateCloudInit(ci *CloudInit) error {
+	for _, ds := range ci.AllowedDatasources {
+		if !validCloudInitName.MatchString(ds) {
+			return fmt.Errorf("invalid cloud-init datasource name %q", ds)
+		}
+	}
+	for _, mod := range ci.AllowedModules {
+		if !validCloudInitName.MatchString(mod) {
+			return fmt.Errorf("invalid cloud-init module name %q", mod)
+		}
+	}
+	return nil
+}
+
 // KernelCommandLineFromGadget returns the desired kernel command line provided by the
 // gadget. The full flag indicates whether the gadget provides a full command
 // line or just the extra parameters that will be appended to the static ones.
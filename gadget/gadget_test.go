@@ -65,6 +65,9 @@ connections:
   - plug: snapid3:process-control
   - plug: snapid4:pctl4
     slot: system:process-control
+  - plug: snapid5:plg5
+    slot: snapid2:slot
+    apply: enforce
 
 volumes:
   volumename:
@@ -784,6 +787,38 @@ func (s *gadgetYamlTestSuite) TestReadGadgetDefaultsMultiline(c *C) {
 	})
 }
 
+func (s *gadgetYamlTestSuite) TestReadGadgetCloudInitPolicy(c *C) {
+	err := os.WriteFile(s.gadgetYamlPath, []byte(`cloud-init:
+  allowed-datasources: [MAAS, NoCloud]
+  allowed-modules: [users, ntp]
+`), 0644)
+	c.Assert(err, IsNil)
+
+	ginfo, err := gadget.ReadInfo(s.dir, &gadgettest.ModelCharacteristics{IsClassic: true})
+	c.Assert(err, IsNil)
+	c.Check(ginfo.CloudInit, DeepEquals, &gadget.CloudInit{
+		AllowedDatasources: []string{"MAAS", "NoCloud"},
+		AllowedModules:     []string{"users", "ntp"},
+	})
+}
+
+func (s *gadgetYamlTestSuite) TestReadGadgetCloudInitPolicyInvalid(c *C) {
+	for _, tc := range []struct {
+		yaml string
+		err  string
+	}{
+		{"cloud-init:\n  allowed-datasources: [\"\"]\n", `invalid cloud-init datasource name ""`},
+		{"cloud-init:\n  allowed-datasources: [\"foo bar\"]\n", `invalid cloud-init datasource name "foo bar"`},
+		{"cloud-init:\n  allowed-modules: [\"../x\"]\n", `invalid cloud-init module name "../x"`},
+	} {
+		err := os.WriteFile(s.gadgetYamlPath, []byte(tc.yaml), 0644)
+		c.Assert(err, IsNil)
+
+		_, err = gadget.ReadInfo(s.dir, &gadgettest.ModelCharacteristics{IsClassic: true})
+		c.Check(err, ErrorMatches, tc.err)
+	}
+}
+
 func asOffsetPtr(offs quantity.Offset) *quantity.Offset {
 	goff := offs
 	return &goff
@@ -829,6 +864,7 @@ func (s *gadgetYamlTestSuite) TestReadGadgetYamlValid(c *C) {
 			{Plug: gadget.ConnectionPlug{SnapID: "snapid1", Plug: "plg1"}, Slot: gadget.ConnectionSlot{SnapID: "snapid2", Slot: "slot"}},
 			{Plug: gadget.ConnectionPlug{SnapID: "snapid3", Plug: "process-control"}, Slot: gadget.ConnectionSlot{SnapID: "system", Slot: "process-control"}},
 			{Plug: gadget.ConnectionPlug{SnapID: "snapid4", Plug: "pctl4"}, Slot: gadget.ConnectionSlot{SnapID: "system", Slot: "process-control"}},
+			{Plug: gadget.ConnectionPlug{SnapID: "snapid5", Plug: "plg5"}, Slot: gadget.ConnectionSlot{SnapID: "snapid2", Slot: "slot"}, Apply: gadget.ConnectionApplyEnforce},
 		},
 		Volumes: map[string]*gadget.Volume{
 			"volumename": {
@@ -952,7 +988,20 @@ volumes:
 	c.Assert(err, IsNil)
 
 	_, err = gadget.ReadInfo(s.dir, nil)
-	c.Assert(err, ErrorMatches, "bootloader must be one of grub, u-boot, android-boot, piboot or lk")
+	c.Assert(err, ErrorMatches, "bootloader must be one of grub, u-boot, android-boot, piboot, systemd-boot or lk")
+}
+
+func (s *gadgetYamlTestSuite) TestReadGadgetYamlSystemdBoot(c *C) {
+	yaml := `
+volumes:
+ name:
+  bootloader: systemd-boot
+`
+	_, err := gadget.InfoFromGadgetYaml([]byte(yaml), uc20Mod)
+	c.Check(err, IsNil)
+
+	_, err = gadget.InfoFromGadgetYaml([]byte(yaml), coreMod)
+	c.Check(err, ErrorMatches, "systemd-boot bootloader valid only for UC20 onwards")
 }
 
 func (s *gadgetYamlTestSuite) TestReadGadgetYamlEmptyBootloader(c *C) {
@@ -1006,6 +1055,7 @@ connections:
 		{`plug: ":"`, `.*in gadget connection plug: expected "\(<snap-id>\|system\):name" not ":"`},
 		{`slot: "foo:"`, `.*in gadget connection slot: expected "\(<snap-id>\|system\):name" not "foo:"`},
 		{`slot: foo:bar`, `gadget connection plug cannot be empty`},
+		{"plug: foo:bar\n   apply: sometimes", `invalid gadget connection apply value "sometimes"`},
 	}
 
 	for _, t := range tests {
@@ -25,7 +25,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/snapcore/go-gettext"
 
@@ -39,6 +42,12 @@ var (
 	TEXTDOMAIN   = "snappy"
 	locale       gettext.Catalog
 	translations gettext.Translations
+
+	// textDomainDir is the directory the translations are loaded from
+	textDomainDir string
+	// translationsMu protects the cache of the translations when loading
+	// the catalogs of other locales than the one of the environment
+	translationsMu sync.Mutex
 )
 
 func init() {
@@ -78,6 +87,7 @@ func langpackResolver(baseRoot string, locale string, domain string) string {
 }
 
 func bindTextDomain(domain, dir string) {
+	textDomainDir = dir
 	translations = gettext.NewTranslations(dir, domain, langpackResolver)
 }
 
@@ -116,6 +126,12 @@ func G(msgid string) string {
 	return locale.Gettext(msgid)
 }
 
+// N marks msgid for translation without translating it, for strings
+// translated later for a given locale with Locale.Translate.
+func N(msgid string) string {
+	return msgid
+}
+
 // https://www.gnu.org/software/gettext/manual/html_node/Plural-forms.html
 // (search for 1000)
 func ngn(d int) uint32 {
@@ -133,3 +149,136 @@ func ngn(d int) uint32 {
 func NG(msgid string, msgidPlural string, n int) string {
 	return locale.NGettext(msgid, msgidPlural, ngn(n))
 }
+
+// Locale gives access to the translations of a given locale, independently
+// of the locale of the environment used by G and NG.
+type Locale struct {
+	name    string
+	catalog gettext.Catalog
+}
+
+// ForLocale returns the translations for the given locale, e.g. "de_DE".
+func ForLocale(loc string) *Locale {
+	loc = simplifyLocale(loc)
+
+	translationsMu.Lock()
+	defer translationsMu.Unlock()
+	return &Locale{name: loc, catalog: translations.Locale(loc)}
+}
+
+// Name returns the name of the locale, without encoding or variants.
+func (l *Locale) Name() string {
+	if l == nil {
+		return ""
+	}
+	return l.name
+}
+
+// Translate returns the translation of msgid for the locale. Strings must
+// be marked for translation with N, so that they are not translated for
+// the locale of the environment first. A nil Locale returns msgid
+// unchanged.
+func (l *Locale) Translate(msgid string) string {
+	if l == nil {
+		return msgid
+	}
+	return l.catalog.Gettext(msgid)
+}
+
+// TranslatePlural is the plural counterpart of Translate, for strings
+// marked for translation with NG.
+func (l *Locale) TranslatePlural(msgid string, msgidPlural string, n int) string {
+	if l == nil {
+		if n == 1 {
+			return msgid
+		}
+		return msgidPlural
+	}
+	return l.catalog.NGettext(msgid, msgidPlural, ngn(n))
+}
+
+// NegotiateLocale returns the locale with translations available that
+// best matches the languages of an HTTP Accept-Language header value,
+// e.g. "de-CH,de;q=0.9,en;q=0.5", or nil if there is none.
+func NegotiateLocale(acceptLanguage string) *Locale {
+	for _, lang := range acceptedLanguages(acceptLanguage) {
+		translationsMu.Lock()
+		available := langpackResolver(textDomainDir, lang, TEXTDOMAIN) != ""
+		translationsMu.Unlock()
+		if available {
+			return ForLocale(lang)
+		}
+	}
+	return nil
+}
+
+// acceptedLanguages returns the languages of an HTTP Accept-Language header
+// value as locale names, in decreasing order of preference.
+func acceptedLanguages(acceptLanguage string) []string {
+	type weightedLang struct {
+		lang   string
+		weight float64
+	}
+	var langs []weightedLang
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		lang := strings.TrimSpace(fields[0])
+		if lang == "" || lang == "*" {
+			continue
+		}
+		weight := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(param[len("q="):], 64)
+			if err != nil {
+				q = 0
+			}
+			weight = q
+		}
+		if weight <= 0 {
+			continue
+		}
+		// de-CH is de_CH as a locale name
+		if tag := strings.SplitN(lang, "-", 2); len(tag) == 2 {
+			lang = strings.ToLower(tag[0]) + "_" + strings.ToUpper(tag[1])
+		} else {
+			lang = strings.ToLower(lang)
+		}
+		langs = append(langs, weightedLang{lang: lang, weight: weight})
+	}
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].weight > langs[j].weight
+	})
+
+	names := make([]string, len(langs))
+	for i, l := range langs {
+		names[i] = l.lang
+	}
+	return names
+}
+
+// mockCatalog is a catalog of singular translations for tests.
+type mockCatalog map[string]string
+
+func (c mockCatalog) Gettext(msgid string) string {
+	if msgstr, ok := c[msgid]; ok {
+		return msgstr
+	}
+	return msgid
+}
+
+func (c mockCatalog) NGettext(msgid string, msgidPlural string, n uint32) string {
+	if n == 1 {
+		return c.Gettext(msgid)
+	}
+	return c.Gettext(msgidPlural)
+}
+
+// MockLocale returns a Locale with the given translations, to be used in
+// tests.
+func MockLocale(name string, translations map[string]string) *Locale {
+	return &Locale{name: name, catalog: mockCatalog(translations)}
+}
@@ -159,3 +159,62 @@ func (s *i18nTestSuite) TestLangpackResolverFromCore(c *C) {
 	var Gtest = G
 	c.Assert(Gtest("singular"), Equals, "translated singular", Commentf("test with %q failed", d))
 }
+
+func (s *i18nTestSuite) TestForLocaleTranslate(c *C) {
+	// the environment locale is not used
+	os.Setenv("LANG", "C")
+	setLocale("")
+
+	loc := ForLocale("en_DK.UTF-8")
+	c.Check(loc.Name(), Equals, "en_DK")
+	c.Check(loc.Translate("singular"), Equals, "translated singular")
+	c.Check(loc.TranslatePlural("plural_1", "plural_2", 2), Equals, "translated plural_2")
+	c.Check(loc.Translate("untranslated"), Equals, "untranslated")
+
+	var nilLoc *Locale
+	c.Check(nilLoc.Name(), Equals, "")
+	c.Check(nilLoc.Translate("singular"), Equals, "singular")
+	c.Check(nilLoc.TranslatePlural("plural_1", "plural_2", 1), Equals, "plural_1")
+	c.Check(nilLoc.TranslatePlural("plural_1", "plural_2", 2), Equals, "plural_2")
+}
+
+func (s *i18nTestSuite) TestNMarksOnly(c *C) {
+	// the environment locale is not used either
+	var Ntest = N
+	c.Check(Ntest("singular"), Equals, "singular")
+
+	loc := ForLocale("en_DK")
+	c.Check(loc.Translate(Ntest("singular")), Equals, "translated singular")
+}
+
+func (s *i18nTestSuite) TestNegotiateLocale(c *C) {
+	loc := NegotiateLocale("fr-FR,en-DK;q=0.8,en;q=0.5")
+	c.Assert(loc, NotNil)
+	c.Check(loc.Name(), Equals, "en_DK")
+	c.Check(loc.Translate("singular"), Equals, "translated singular")
+
+	c.Check(NegotiateLocale("fr-FR,de;q=0.5"), IsNil)
+	c.Check(NegotiateLocale("en-DK;q=0"), IsNil)
+	c.Check(NegotiateLocale(""), IsNil)
+}
+
+type acceptLanguageSuite struct{}
+
+var _ = Suite(&acceptLanguageSuite{})
+
+func (s *acceptLanguageSuite) TestAcceptedLanguages(c *C) {
+	for _, tc := range []struct {
+		header string
+		langs  []string
+	}{
+		{"", []string{}},
+		{"de", []string{"de"}},
+		{"de-CH", []string{"de_CH"}},
+		{"de-ch, fr;q=0.9", []string{"de_CH", "fr"}},
+		{"en;q=0.5, de-CH, de;q=0.9, *;q=0.1", []string{"de_CH", "de", "en"}},
+		{"fr;q=0, es;q=bogus, pt", []string{"pt"}},
+		{"da, en-GB;q=0.8, en;q=0.8", []string{"da", "en_GB", "en"}},
+	} {
+		c.Check(acceptedLanguages(tc.header), DeepEquals, tc.langs, Commentf("%q", tc.header))
+	}
+}
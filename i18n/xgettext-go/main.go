@@ -95,11 +95,17 @@ func constructValue(val interface{}) string {
 
 func inspectNodeForTranslations(fset *token.FileSet, f *ast.File, n ast.Node) bool {
 	// FIXME: this assume we always have a "gettext.Gettext" style keyword
-	l := strings.Split(opts.Keyword, ".")
-	gettextSelector := l[0]
-	gettextFuncName := l[1]
+	isKeyword := func(sel *ast.SelectorExpr) bool {
+		for _, keyword := range opts.Keyword {
+			l := strings.Split(keyword, ".")
+			if ident, ok := sel.X.(*ast.Ident); ok && sel.Sel.Name == l[1] && ident.Name == l[0] {
+				return true
+			}
+		}
+		return false
+	}
 
-	l = strings.Split(opts.KeywordPlural, ".")
+	l := strings.Split(opts.KeywordPlural, ".")
 	gettextSelectorPlural := l[0]
 	gettextFuncNamePlural := l[1]
 
@@ -113,7 +119,7 @@ func inspectNodeForTranslations(fset *token.FileSet, f *ast.File, n ast.Node) bo
 				i18nStrPlural = x.Args[1].(*ast.BasicLit).Value
 			}
 
-			if sel.Sel.Name == gettextFuncName && sel.X.(*ast.Ident).Name == gettextSelector {
+			if isKeyword(sel) {
 				i18nStr = constructValue(x.Args[0])
 			}
 
@@ -311,8 +317,8 @@ var opts struct {
 
 	PackageName string `long:"package-name" description:"set package name in output"`
 
-	Keyword       string `short:"k" long:"keyword" default:"gettext.Gettext" description:"look for WORD as the keyword for singular strings"`
-	KeywordPlural string `long:"keyword-plural" default:"gettext.NGettext" description:"look for WORD as the keyword for plural strings"`
+	Keyword       []string `short:"k" long:"keyword" default:"gettext.Gettext" description:"look for WORD as a keyword for singular strings, can be repeated"`
+	KeywordPlural string   `long:"keyword-plural" default:"gettext.NGettext" description:"look for WORD as the keyword for plural strings"`
 }
 
 func main() {
@@ -52,7 +52,7 @@ func (s *xgettextTestSuite) SetUpTest(c *C) {
 	// our test defaults
 	opts.NoLocation = false
 	opts.AddCommentsTag = "TRANSLATORS:"
-	opts.Keyword = "i18n.G"
+	opts.Keyword = []string{"i18n.G", "i18n.N"}
 	opts.KeywordPlural = "i18n.NG"
 	opts.SortOutput = true
 	opts.PackageName = "snappy"
@@ -102,6 +102,34 @@ func main() {
 	})
 }
 
+func (s *xgettextTestSuite) TestProcessFilesMultipleKeywords(c *C) {
+	fname := makeGoSourceFile(c, []byte(`package main
+
+func main() {
+    i18n.G("foo")
+    i18n.N("bar")
+    rev.x.N("baz")
+}
+`))
+	err := processFiles([]string{fname})
+	c.Assert(err, IsNil)
+
+	c.Assert(msgIDs, DeepEquals, map[string][]msgID{
+		"foo": {
+			{
+				fname: fname,
+				line:  4,
+			},
+		},
+		"bar": {
+			{
+				fname: fname,
+				line:  5,
+			},
+		},
+	})
+}
+
 func (s *xgettextTestSuite) TestProcessFilesMultiple(c *C) {
 	fname := makeGoSourceFile(c, []byte(`package main
 
@@ -47,9 +47,11 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/profilecache"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/release"
@@ -66,6 +68,7 @@ var (
 	parserFeatures        = apparmor_sandbox.ParserFeatures
 	loadProfiles          = apparmor_sandbox.LoadProfiles
 	removeCachedProfiles  = apparmor_sandbox.RemoveCachedProfiles
+	staleProfiles         = apparmor_sandbox.StaleProfiles
 
 	// make sure that apparmor profile fulfills the late discarding backend
 	// interface
@@ -432,6 +435,132 @@ func (b *Backend) prepareProfiles(snapInfo *snap.Info, opts interfaces.Confineme
 	return &profilePathsResults{changed: changedPaths, removed: removedPaths, unchanged: unchangedPaths}, nil
 }
 
+// compiledCacheSize is the maximum size of the cache of compiled profiles.
+const compiledCacheSize = 64 * 1024 * 1024
+
+func compiledProfilesCache() *profilecache.Store {
+	return profilecache.New(filepath.Join(dirs.SnapProfilesCacheDir, "apparmor"), compiledCacheSize)
+}
+
+// parserCacheDirs returns the directories of the apparmor_parser cache
+// holding compiled profiles. AppArmor 2.13 and higher has a cache forest,
+// with a directory per set of features, while 2.12 and lower uses the cache
+// directory directly.
+func parserCacheDirs(cacheDir string) []string {
+	if li, err := filepath.Glob(filepath.Join(cacheDir, "*/.features")); err == nil && len(li) > 0 {
+		parserDirs := make([]string, len(li))
+		for i, features := range li {
+			parserDirs[i] = filepath.Dir(features)
+		}
+		return parserDirs
+	}
+	return []string{cacheDir}
+}
+
+// compiledProfileKey returns the key of the compiled form of the profile at
+// the given path, for the set of features of the given apparmor_parser cache
+// directory.
+func compiledProfileKey(parserCacheDir, profilePath string) (string, error) {
+	features, err := os.ReadFile(filepath.Join(parserCacheDir, ".features"))
+	if err != nil {
+		return "", err
+	}
+	profile, err := os.ReadFile(profilePath)
+	if err != nil {
+		return "", err
+	}
+	return profilecache.Key(features, profile), nil
+}
+
+// restoreCompiledProfiles restores into the apparmor_parser cache the
+// compiled form of those of the given profiles which were compiled before,
+// e.g. because the snap was removed and installed again or reverted. Those
+// profiles can be loaded from the cache and are returned as cached, the
+// remaining ones need to be compiled.
+func restoreCompiledProfiles(paths []string, cacheDir string) (cached, uncached []string) {
+	if len(paths) == 0 {
+		return nil, paths
+	}
+	cache := compiledProfilesCache()
+	parserDirs := parserCacheDirs(cacheDir)
+	for _, path := range paths {
+		var restoredDirs []string
+		for _, parserDir := range parserDirs {
+			key, err := compiledProfileKey(parserDir, path)
+			if err != nil {
+				continue
+			}
+			ok, err := cache.Restore(key, filepath.Join(parserDir, filepath.Base(path)))
+			if err != nil {
+				logger.Debugf("cannot use cached compiled apparmor profile %s: %v", path, err)
+			}
+			if ok {
+				restoredDirs = append(restoredDirs, parserDir)
+			}
+		}
+		if len(restoredDirs) == 0 {
+			uncached = append(uncached, path)
+			continue
+		}
+		// make sure that a stale compiled profile is not used for
+		// other sets of features
+		for _, parserDir := range parserDirs {
+			if !strutil.ListContains(restoredDirs, parserDir) {
+				os.Remove(filepath.Join(parserDir, filepath.Base(path)))
+			}
+		}
+		cached = append(cached, path)
+	}
+	return cached, uncached
+}
+
+// loadAndStoreCompiledProfiles loads the given profiles, and on success,
+// stores their compiled form written by apparmor_parser to its cache, so
+// that it can be reused should the same profiles be loaded again.
+func loadAndStoreCompiledProfiles(paths []string, cacheDir string, flags apparmor_sandbox.AaParserFlags) error {
+	parserDirs := parserCacheDirs(cacheDir)
+	// only consider what gets written by apparmor_parser, that is the
+	// compiled profiles which are new or were modified
+	before := make(map[string]time.Time)
+	for _, path := range paths {
+		for _, parserDir := range parserDirs {
+			compiled := filepath.Join(parserDir, filepath.Base(path))
+			if fi, err := os.Stat(compiled); err == nil {
+				before[compiled] = fi.ModTime()
+			}
+		}
+	}
+
+	if err := loadProfiles(paths, cacheDir, flags); err != nil {
+		return err
+	}
+
+	var cache *profilecache.Store
+	for _, path := range paths {
+		for _, parserDir := range parserDirs {
+			compiled := filepath.Join(parserDir, filepath.Base(path))
+			fi, err := os.Stat(compiled)
+			if err != nil {
+				continue
+			}
+			if mtime, ok := before[compiled]; ok && mtime.Equal(fi.ModTime()) {
+				continue
+			}
+			key, err := compiledProfileKey(parserDir, path)
+			if err != nil {
+				continue
+			}
+			if cache == nil {
+				cache = compiledProfilesCache()
+			}
+			if err := cache.Add(key, compiled); err != nil {
+				logger.Debugf("cannot cache compiled apparmor profile %s: %v", path, err)
+			}
+		}
+	}
+	return nil
+}
+
 // Setup creates and loads apparmor profiles specific to a given snap.
 // The snap can be in developer mode to make security violations non-fatal to
 // the offending application process.
@@ -453,20 +582,24 @@ func (b *Backend) Setup(snapInfo *snap.Info, opts interfaces.ConfinementOptions,
 	if b.preseed {
 		aaFlags |= apparmor_sandbox.SkipKernelLoad
 	}
+	// Changed profiles which were compiled before are restored into the
+	// apparmor cache and loaded along with the unchanged ones.
+	cached, uncached := restoreCompiledProfiles(prof.changed, apparmor_sandbox.CacheDir)
 	timings.Run(tm, "load-profiles[changed]", fmt.Sprintf("load changed security profiles of snap %q", snapInfo.InstanceName()), func(nesttm timings.Measurer) {
-		errReloadChanged = loadProfiles(prof.changed, apparmor_sandbox.CacheDir, aaFlags)
+		errReloadChanged = loadAndStoreCompiledProfiles(uncached, apparmor_sandbox.CacheDir, aaFlags)
 	})
 
-	// Load all unchanged profiles anyway. This ensures those are correct in
-	// the kernel even if the files on disk were not changed. We rely on
-	// apparmor cache to make this performant.
+	// Load the unchanged profiles unless they are known to be loaded
+	// already. This ensures those are correct in the kernel even if the
+	// files on disk were not changed. We rely on apparmor cache to make this
+	// performant.
 	var errReloadOther error
 	aaFlags = 0
 	if b.preseed {
 		aaFlags |= apparmor_sandbox.SkipKernelLoad
 	}
 	timings.Run(tm, "load-profiles[unchanged]", fmt.Sprintf("load unchanged security profiles of snap %q", snapInfo.InstanceName()), func(nesttm timings.Measurer) {
-		errReloadOther = loadProfiles(prof.unchanged, apparmor_sandbox.CacheDir, aaFlags)
+		errReloadOther = loadProfiles(append(cached, staleProfiles(prof.unchanged)...), apparmor_sandbox.CacheDir, aaFlags)
 	})
 	errRemoveCached := removeCachedProfiles(prof.removed, apparmor_sandbox.CacheDir)
 	if errReloadChanged != nil {
@@ -501,31 +634,42 @@ func (b *Backend) SetupMany(snaps []*snap.Info, confinement func(snapName string
 	}
 
 	if !fallback {
-		aaFlags := apparmor_sandbox.SkipReadCache | apparmor_sandbox.ConserveCPU
+		aaFlags := apparmor_sandbox.ConserveCPU
 		if b.preseed {
 			aaFlags |= apparmor_sandbox.SkipKernelLoad
 		}
-		var errReloadChanged error
-		timings.Run(tm, "load-profiles[changed-many]", fmt.Sprintf("load changed security profiles of %d snaps", len(snaps)), func(nesttm timings.Measurer) {
-			errReloadChanged = loadProfiles(allChangedPaths, apparmor_sandbox.CacheDir, aaFlags)
-		})
-
-		aaFlags = apparmor_sandbox.ConserveCPU
-		if b.preseed {
-			aaFlags |= apparmor_sandbox.SkipKernelLoad
+		// Changed profiles which were compiled before are restored into
+		// the apparmor cache, while the remaining ones are dropped from
+		// it so that they are compiled again regardless of the time and
+		// date settings. This way changed and unchanged profiles are
+		// loaded with a single invocation of apparmor_parser, skipping
+		// the unchanged profiles which are known to be loaded already.
+		cached, uncached := restoreCompiledProfiles(allChangedPaths, apparmor_sandbox.CacheDir)
+		var errRemoveStale error
+		if len(uncached) > 0 {
+			names := make([]string, len(uncached))
+			for i, path := range uncached {
+				names[i] = filepath.Base(path)
+			}
+			errRemoveStale = removeCachedProfiles(names, apparmor_sandbox.CacheDir)
+		}
+		paths := append(uncached, cached...)
+		paths = append(paths, staleProfiles(allUnchangedPaths)...)
+
+		var errReload error
+		if errRemoveStale == nil {
+			timings.Run(tm, "load-profiles[many]", fmt.Sprintf("load security profiles of %d snaps", len(snaps)), func(nesttm timings.Measurer) {
+				errReload = loadAndStoreCompiledProfiles(paths, apparmor_sandbox.CacheDir, aaFlags)
+			})
 		}
-		var errReloadOther error
-		timings.Run(tm, "load-profiles[unchanged-many]", fmt.Sprintf("load unchanged security profiles %d snaps", len(snaps)), func(nesttm timings.Measurer) {
-			errReloadOther = loadProfiles(allUnchangedPaths, apparmor_sandbox.CacheDir, aaFlags)
-		})
 
 		errRemoveCached := removeCachedProfiles(allRemovedPaths, apparmor_sandbox.CacheDir)
-		if errReloadChanged != nil {
-			logger.Noticef("failed to batch-reload changed profiles: %s", errReloadChanged)
+		if errRemoveStale != nil {
+			logger.Noticef("failed to batch-remove stale cached profiles: %s", errRemoveStale)
 			fallback = true
 		}
-		if errReloadOther != nil {
-			logger.Noticef("failed to batch-reload unchanged profiles: %s", errReloadOther)
+		if errReload != nil {
+			logger.Noticef("failed to batch-reload profiles: %s", errReload)
 			fallback = true
 		}
 		if errRemoveCached != nil {
@@ -899,11 +1043,12 @@ func (b *Backend) addContent(securityTag string, snapInfo *snap.Info, cmdName st
 				// ignoring all apparmor snippets as they may conflict with the
 				// super-broad template we are starting with.
 			} else {
-				// Check if NFS is mounted at or under $HOME. Because NFS is not
-				// transparent to apparmor we must alter the profile to counter that and
-				// allow access to SNAP_USER_* files.
+				// Check if NFS, or another remote filesystem, is mounted at or
+				// under $HOME. Because NFS is not transparent to apparmor we must
+				// alter the profile to counter that and allow access to
+				// SNAP_USER_* files.
 				tagSnippets = snippetForTag
-				if nfs, _ := osutil.IsHomeUsingNFS(); nfs {
+				if nfs, _ := apparmor_sandbox.IsHomeUsingRemoteFS(); nfs {
 					tagSnippets += apparmor_sandbox.NfsSnippet
 				}
 
@@ -945,6 +1090,19 @@ func (b *Backend) addContent(securityTag string, snapInfo *snap.Info, cmdName st
 				if len(snapInfo.SystemUsernames) > 0 {
 					tagSnippets += privDropAndChownRules
 				}
+
+				// Add the restrictions configured by the
+				// administrator last, as deny rules they take
+				// precedence over what interfaces grant
+				if opts.Hardening.NoPtrace {
+					tagSnippets += hardeningNoPtraceSnippet
+				}
+				if opts.Hardening.NoCoreDumps {
+					tagSnippets += hardeningNoCoreDumpsSnippet
+				}
+				if opts.Hardening.NoMemfdExec {
+					tagSnippets += hardeningNoMemfdExecSnippet
+				}
 			}
 
 			return tagSnippets
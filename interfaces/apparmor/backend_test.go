@@ -575,21 +575,88 @@ func (s *backendSuite) TestSetupManyProfilesWithChanged(c *C) {
 		c.Assert(os.WriteFile(snap1AAprofile, []byte("# an outdated profile"), 0644), IsNil)
 		c.Assert(os.WriteFile(snap2AAprofile, []byte("# an outdated profile"), 0644), IsNil)
 
+		s.removeCachedProfilesCalls = nil
 		setupManyInterface, ok := s.Backend.(interfaces.SecurityBackendSetupMany)
 		c.Assert(ok, Equals, true)
 		err := setupManyInterface.SetupMany([]*snap.Info{snapInfo1, snapInfo2}, func(snapName string) interfaces.ConfinementOptions { return opts }, s.Repo, s.meas)
 		c.Assert(err, IsNil)
 
-		// expect two batch executions - one for changed profiles, second for unchanged profiles.
+		// expect a single batch execution for changed and unchanged
+		// profiles, the changed ones being dropped from the cache first
+		c.Check(s.removeCachedProfilesCalls[0], DeepEquals, removeCachedProfilesParams{
+			[]string{"snap.samba.smbd", "snap.some-snap.someapp"}, fmt.Sprintf("%s/var/cache/apparmor", s.RootDir),
+		})
 		c.Check(s.loadProfilesCalls, DeepEquals, []loadProfilesParams{
-			{[]string{snap1AAprofile, snap2AAprofile}, fmt.Sprintf("%s/var/cache/apparmor", s.RootDir), apparmor_sandbox.SkipReadCache | apparmor_sandbox.ConserveCPU},
-			{[]string{snap1nsProfile, snap2nsProfile}, fmt.Sprintf("%s/var/cache/apparmor", s.RootDir), apparmor_sandbox.ConserveCPU},
+			{[]string{snap1AAprofile, snap2AAprofile, snap1nsProfile, snap2nsProfile}, fmt.Sprintf("%s/var/cache/apparmor", s.RootDir), apparmor_sandbox.ConserveCPU},
 		})
 		s.RemoveSnap(c, snapInfo1)
 		s.RemoveSnap(c, snapInfo2)
 	}
 }
 
+func (s *backendSuite) TestSetupManySkipsLoadedProfiles(c *C) {
+	snap1nsProfile := filepath.Join(dirs.SnapAppArmorDir, "snap-update-ns.samba")
+	snap1AAprofile := filepath.Join(dirs.SnapAppArmorDir, "snap.samba.smbd")
+	snap2nsProfile := filepath.Join(dirs.SnapAppArmorDir, "snap-update-ns.some-snap")
+	snap2AAprofile := filepath.Join(dirs.SnapAppArmorDir, "snap.some-snap.someapp")
+
+	var staleCalls [][]string
+	restore := apparmor.MockStaleProfiles(func(fnames []string) []string {
+		staleCalls = append(staleCalls, fnames)
+		// only the profile of snap-update-ns for samba needs loading
+		var stale []string
+		for _, fname := range fnames {
+			if fname == snap1nsProfile {
+				stale = append(stale, fname)
+			}
+		}
+		return stale
+	})
+	defer restore()
+
+	for _, opts := range testedConfinementOpts {
+		snapInfo1 := s.InstallSnap(c, opts, "", ifacetest.SambaYamlV1, 1)
+		snapInfo2 := s.InstallSnap(c, opts, "", ifacetest.SomeSnapYamlV1, 1)
+		s.loadProfilesCalls = nil
+		staleCalls = nil
+
+		setupManyInterface, ok := s.Backend.(interfaces.SecurityBackendSetupMany)
+		c.Assert(ok, Equals, true)
+		err := setupManyInterface.SetupMany([]*snap.Info{snapInfo1, snapInfo2}, func(snapName string) interfaces.ConfinementOptions { return opts }, s.Repo, s.meas)
+		c.Assert(err, IsNil)
+
+		c.Check(staleCalls, DeepEquals, [][]string{{snap1nsProfile, snap1AAprofile, snap2nsProfile, snap2AAprofile}})
+		c.Check(s.loadProfilesCalls, DeepEquals, []loadProfilesParams{
+			{[]string{snap1nsProfile}, fmt.Sprintf("%s/var/cache/apparmor", s.RootDir), apparmor_sandbox.ConserveCPU},
+		})
+
+		// nothing is loaded when all profiles are loaded already
+		s.loadProfilesCalls = nil
+		r := apparmor.MockStaleProfiles(func(fnames []string) []string { return nil })
+		err = setupManyInterface.SetupMany([]*snap.Info{snapInfo1, snapInfo2}, func(snapName string) interfaces.ConfinementOptions { return opts }, s.Repo, s.meas)
+		r()
+		c.Assert(err, IsNil)
+		c.Check(s.loadProfilesCalls, HasLen, 0)
+
+		s.RemoveSnap(c, snapInfo1)
+		s.RemoveSnap(c, snapInfo2)
+	}
+}
+
+func (s *backendSuite) TestSetupSkipsLoadedProfiles(c *C) {
+	restore := apparmor.MockStaleProfiles(func(fnames []string) []string { return nil })
+	defer restore()
+
+	for _, opts := range testedConfinementOpts {
+		snapInfo := s.InstallSnap(c, opts, "", ifacetest.SambaYamlV1, 1)
+		s.loadProfilesCalls = nil
+		err := s.Backend.Setup(snapInfo, opts, s.Repo, s.meas)
+		c.Assert(err, IsNil)
+		c.Check(s.loadProfilesCalls, HasLen, 0)
+		s.RemoveSnap(c, snapInfo)
+	}
+}
+
 // helper for checking for apparmor parser calls where batch run is expected to fail and is followed by two separate runs for individual snaps.
 func (s *backendSuite) checkSetupManyCallsWithFallback(c *C, invocations []loadProfilesParams) {
 	snap1nsProfile := filepath.Join(dirs.SnapAppArmorDir, "snap-update-ns.samba")
@@ -631,7 +698,7 @@ func (s *backendSuite) TestSetupManyApparmorBatchProcessingPermanentError(c *C)
 		c.Assert(errs, HasLen, 2)
 		c.Check(errs[0], ErrorMatches, ".*cannot setup profiles for snap \"samba\": apparmor_parser crash")
 		c.Check(errs[1], ErrorMatches, ".*cannot setup profiles for snap \"some-snap\": apparmor_parser crash")
-		c.Check(log.String(), Matches, ".*failed to batch-reload unchanged profiles: apparmor_parser crash\n")
+		c.Check(log.String(), Matches, ".*failed to batch-reload profiles: apparmor_parser crash\n")
 
 		s.RemoveSnap(c, snapInfo1)
 		s.RemoveSnap(c, snapInfo2)
@@ -672,7 +739,7 @@ func (s *backendSuite) TestSetupManyApparmorBatchProcessingErrorWithFallbackOK(c
 		// note, tnis scenario is unlikely to happen in real life, because if a profile failed in a batch, it would fail when parsed alone too. It is
 		// tested here just to exercise various execution paths.
 		c.Assert(errs, HasLen, 0)
-		c.Check(log.String(), Matches, ".*failed to batch-reload unchanged profiles: some error\n")
+		c.Check(log.String(), Matches, ".*failed to batch-reload profiles: some error\n")
 
 		s.RemoveSnap(c, snapInfo1)
 		s.RemoveSnap(c, snapInfo2)
@@ -714,7 +781,7 @@ func (s *backendSuite) TestSetupManyApparmorBatchProcessingErrorWithFallbackPart
 		s.checkSetupManyCallsWithFallback(c, s.loadProfilesCalls)
 
 		// the batch reload fails because of snap.samba.smbd profile failing
-		c.Check(log.String(), Matches, ".* failed to batch-reload unchanged profiles: fail on samba\n")
+		c.Check(log.String(), Matches, ".* failed to batch-reload profiles: fail on samba\n")
 		// and we also fail when running that profile in fallback mode
 		c.Assert(errs, HasLen, 1)
 		c.Assert(errs[0], ErrorMatches, "cannot setup profiles for snap \"samba\": fail on samba")
@@ -2385,6 +2452,62 @@ func (s *backendSuite) TestPtraceTraceRule(c *C) {
 	}
 }
 
+func (s *backendSuite) TestHardeningRules(c *C) {
+	restoreTemplate := apparmor.MockTemplate("template\n###SNIPPETS###\n")
+	defer restoreTemplate()
+	restore := apparmor_sandbox.MockLevel(apparmor_sandbox.Full)
+	defer restore()
+	restore = osutil.MockIsHomeUsingNFS(func() (bool, error) { return false, nil })
+	defer restore()
+
+	for _, tc := range []struct {
+		opts     interfaces.ConfinementOptions
+		expected []string
+		absent   []string
+	}{
+		{
+			opts:   interfaces.ConfinementOptions{},
+			absent: []string{"deny ptrace,", "set rlimit core <= 0,", "deny /memfd:* mx,"},
+		},
+		{
+			opts:     interfaces.ConfinementOptions{Hardening: interfaces.HardeningOptions{NoPtrace: true}},
+			expected: []string{"deny ptrace,", "deny capability sys_ptrace,"},
+			absent:   []string{"set rlimit core <= 0,", "deny /memfd:* mx,"},
+		},
+		{
+			opts:     interfaces.ConfinementOptions{Hardening: interfaces.HardeningOptions{NoCoreDumps: true, NoMemfdExec: true}},
+			expected: []string{"set rlimit core <= 0,", "deny /memfd:* mx,"},
+			absent:   []string{"deny ptrace,"},
+		},
+		{
+			opts:     interfaces.ConfinementOptions{DevMode: true, Hardening: interfaces.HardeningOptions{NoPtrace: true}},
+			expected: []string{"deny ptrace,"},
+		},
+		// classic confinement ignores the hardening options
+		{
+			opts:   interfaces.ConfinementOptions{Classic: true, Hardening: interfaces.HardeningOptions{NoPtrace: true, NoCoreDumps: true, NoMemfdExec: true}},
+			absent: []string{"deny ptrace,", "set rlimit core <= 0,", "deny /memfd:* mx,"},
+		},
+		{
+			opts:     interfaces.ConfinementOptions{Classic: true, JailMode: true, Hardening: interfaces.HardeningOptions{NoPtrace: true}},
+			expected: []string{"deny ptrace,"},
+		},
+	} {
+		snapInfo := s.InstallSnap(c, tc.opts, "", ifacetest.SambaYamlV1, 1)
+
+		profile := filepath.Join(dirs.SnapAppArmorDir, "snap.samba.smbd")
+		data, err := ioutil.ReadFile(profile)
+		c.Assert(err, IsNil)
+		for _, needle := range tc.expected {
+			c.Check(string(data), testutil.Contains, needle, Commentf("%+v", tc.opts))
+		}
+		for _, needle := range tc.absent {
+			c.Check(string(data), Not(testutil.Contains), needle, Commentf("%+v", tc.opts))
+		}
+		s.RemoveSnap(c, snapInfo)
+	}
+}
+
 func (s *backendSuite) TestHomeIxRule(c *C) {
 	restoreTemplate := apparmor.MockTemplate("template\n###SNIPPETS###\nneedle rwkl###HOME_IX###,\n")
 	defer restoreTemplate()
@@ -2584,15 +2707,10 @@ func (s *backendSuite) TestSetupManyInPreseedMode(c *C) {
 		err := setupManyInterface.SetupMany([]*snap.Info{snapInfo1, snapInfo2}, func(snapName string) interfaces.ConfinementOptions { return opts }, s.Repo, s.meas)
 		c.Assert(err, IsNil)
 
-		// expect two batch executions - one for changed profiles, second for unchanged profiles.
+		// expect a single batch execution for changed and unchanged profiles
 		c.Check(s.loadProfilesCalls, DeepEquals, []loadProfilesParams{
 			{
-				[]string{snap1AAprofile, snap2AAprofile},
-				fmt.Sprintf("%s/var/cache/apparmor", s.RootDir),
-				apparmor_sandbox.SkipReadCache | apparmor_sandbox.ConserveCPU | apparmor_sandbox.SkipKernelLoad,
-			},
-			{
-				[]string{snap1nsProfile, snap2nsProfile},
+				[]string{snap1AAprofile, snap2AAprofile, snap1nsProfile, snap2nsProfile},
 				fmt.Sprintf("%s/var/cache/apparmor", s.RootDir),
 				apparmor_sandbox.ConserveCPU | apparmor_sandbox.SkipKernelLoad,
 			},
@@ -2685,3 +2803,54 @@ func (s *backendSuite) TestRemoveAllSnapAppArmorProfiles(c *C) {
 		c.Check(os.IsNotExist(err), Equals, true)
 	}
 }
+
+func (s *backendSuite) TestReinstallingSnapUsesCompiledProfiles(c *C) {
+	forestDir := filepath.Join(apparmor_sandbox.CacheDir, "deadbeef.0")
+	c.Assert(os.MkdirAll(forestDir, 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(forestDir, ".features"), []byte("features"), 0644), IsNil)
+
+	restore := apparmor.MockLoadProfiles(func(fnames []string, cacheDir string, flags apparmor_sandbox.AaParserFlags) error {
+		if len(fnames) == 0 {
+			return nil
+		}
+		s.loadProfilesCalls = append(s.loadProfilesCalls, loadProfilesParams{fnames, cacheDir, flags})
+		if flags&apparmor_sandbox.SkipReadCache != 0 {
+			// pretend that apparmor_parser compiled the profiles
+			for _, fname := range fnames {
+				err := os.WriteFile(filepath.Join(forestDir, filepath.Base(fname)), []byte("compiled "+filepath.Base(fname)), 0644)
+				c.Assert(err, IsNil)
+			}
+		}
+		return nil
+	})
+	defer restore()
+
+	updateNSProfile := filepath.Join(dirs.SnapAppArmorDir, "snap-update-ns.samba")
+	profile := filepath.Join(dirs.SnapAppArmorDir, "snap.samba.smbd")
+	cacheDir := fmt.Sprintf("%s/var/cache/apparmor", s.RootDir)
+
+	snapInfo := s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 1)
+	c.Check(s.loadProfilesCalls, DeepEquals, []loadProfilesParams{
+		{[]string{updateNSProfile, profile}, cacheDir, apparmor_sandbox.SkipReadCache},
+	})
+	s.RemoveSnap(c, snapInfo)
+	// pretend that the parser cache was cleaned up
+	c.Assert(os.Remove(filepath.Join(forestDir, "snap.samba.smbd")), IsNil)
+	c.Assert(os.Remove(filepath.Join(forestDir, "snap-update-ns.samba")), IsNil)
+	s.loadProfilesCalls = nil
+
+	// the compiled profiles are restored and loaded from the cache
+	s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 1)
+	c.Check(s.loadProfilesCalls, DeepEquals, []loadProfilesParams{
+		{[]string{updateNSProfile, profile}, cacheDir, 0},
+	})
+	c.Check(filepath.Join(forestDir, "snap.samba.smbd"), testutil.FileEquals, "compiled snap.samba.smbd")
+	s.loadProfilesCalls = nil
+
+	// a different profile is compiled
+	s.UpdateSnap(c, snapInfo, interfaces.ConfinementOptions{DevMode: true}, ifacetest.SambaYamlV1, 1)
+	c.Check(s.loadProfilesCalls, DeepEquals, []loadProfilesParams{
+		{[]string{profile}, cacheDir, apparmor_sandbox.SkipReadCache},
+		{[]string{updateNSProfile}, cacheDir, 0},
+	})
+}
@@ -47,6 +47,12 @@ func MockRemoveCachedProfiles(f func(fnames []string, cacheDir string) error) (r
 	return r
 }
 
+func MockStaleProfiles(f func(fnames []string) []string) (restore func()) {
+	r := testutil.Backup(&staleProfiles)
+	staleProfiles = f
+	return r
+}
+
 // MockProcSelfExe mocks the location of /proc/self/exe read by setupSnapConfineGeneratedPolicy.
 func MockProcSelfExe(symlink string) (restore func()) {
 	old := procSelfExe
@@ -920,6 +920,30 @@ var sysModuleCapabilityDenySnippet = `
 deny capability sys_module,
 `
 
+// hardeningNoPtraceSnippet denies tracing any process, including those of
+// the snap itself, regardless of what interfaces grant.
+var hardeningNoPtraceSnippet = `
+# Hardening: tracing processes was disabled by the administrator.
+deny ptrace,
+deny capability sys_ptrace,
+`
+
+// hardeningNoCoreDumpsSnippet prevents dumping core by capping the hard
+// limit of core files to zero.
+var hardeningNoCoreDumpsSnippet = `
+# Hardening: core dumps were disabled by the administrator.
+set rlimit core <= 0,
+`
+
+// hardeningNoMemfdExecSnippet denies mapping executable code from memory file
+// descriptors or executing them, which is a common way of running code that
+// never touches the disk.
+var hardeningNoMemfdExecSnippet = `
+# Hardening: executing memory file descriptors was disabled by the
+# administrator.
+deny /memfd:* mx,
+`
+
 // updateNSTemplate defines the apparmor profile for per-snap snap-update-ns.
 //
 // The per-snap snap-update-ns profiles are composed via a template and
@@ -69,6 +69,36 @@ type ConfinementOptions struct {
 	// as systemd provides a mount namespace which will clash with the
 	// one snapd sets up.
 	ExtraLayouts []snap.Layout
+	// DeferNamespaceUpdate flag indicates that the preserved mount
+	// namespace of the snap does not need to be updated right away,
+	// as this will be done later in the same change. The mount
+	// profiles are still written.
+	DeferNamespaceUpdate bool
+	// DeferDeviceRulesReload flag indicates that udev does not need to
+	// reload the device rules of the snap right away, as this will be
+	// done later in the same change. The rules are still written.
+	DeferDeviceRulesReload bool
+	// SyscallProfiling flag switches the seccomp filter to allowing and
+	// logging all system calls, so that the ones used by the snap can
+	// be compared with the ones its policy grants.
+	SyscallProfiling bool
+	// Hardening holds the restrictions applied on top of the regular
+	// confinement of the snap.
+	Hardening HardeningOptions
+}
+
+// HardeningOptions holds restrictions configured by the administrator on
+// top of the regular confinement of a snap, for high-security deployments.
+// They take precedence over what interfaces grant but have no effect on
+// snaps using classic confinement.
+type HardeningOptions struct {
+	// NoPtrace denies tracing any process, including those of the
+	// snap itself.
+	NoPtrace bool
+	// NoCoreDumps prevents the processes of the snap from dumping core.
+	NoCoreDumps bool
+	// NoMemfdExec denies executing code from memory file descriptors.
+	NoMemfdExec bool
 }
 
 // SecurityBackendOptions carries extra flags that affect initialization of the
@@ -85,35 +85,35 @@ func (iface *cupsInterface) AppArmorConnectedSlot(spec *apparmor.Specification,
 	return nil
 }
 
-func validateCupsSocketDirSlotAttr(a interfaces.Attrer, snapInfo *snap.Info) (string, error) {
+func validateCupsDirSlotAttr(a interfaces.Attrer, snapInfo *snap.Info, attr string) (string, error) {
 	// Allow an empty specification for the slot, in which case we don't perform
 	// any mounts, etc. This is mainly to prevent errors in systems which still
 	// have the old cups snap installed that haven't been updated to use the new
 	// snap with the new slot declaration
-	if _, ok := a.Lookup("cups-socket-directory"); !ok {
+	if _, ok := a.Lookup(attr); !ok {
 		return "", nil
 	}
 
 	var cupsdSocketSourceDir string
-	if err := a.Attr("cups-socket-directory", &cupsdSocketSourceDir); err != nil {
+	if err := a.Attr(attr, &cupsdSocketSourceDir); err != nil {
 		return "", err
 	}
 
 	// make sure that the cups socket dir is not an AppArmor Regular expression
 	if err := apparmor.ValidateNoAppArmorRegexp(cupsdSocketSourceDir); err != nil {
-		return "", fmt.Errorf("cups-socket-directory is not usable: %v", err)
+		return "", fmt.Errorf("%s is not usable: %v", attr, err)
 	}
 
 	if !cleanSubPath(cupsdSocketSourceDir) {
-		return "", fmt.Errorf("cups-socket-directory is not clean: %q", cupsdSocketSourceDir)
+		return "", fmt.Errorf("%s is not clean: %q", attr, cupsdSocketSourceDir)
 	}
 
-	// validate that the setting for cups-socket-directory is in $SNAP_DATA or
+	// validate that the setting for the directory is in $SNAP_DATA or
 	// $SNAP_COMMON, we don't allow any other directories for the slot socket
 	// dir
 	// TODO: should we also allow /run/$SNAP_INSTANCE_NAME/ too ?
 	if !strings.HasPrefix(cupsdSocketSourceDir, "$SNAP_COMMON") && !strings.HasPrefix(cupsdSocketSourceDir, "$SNAP_DATA") {
-		return "", fmt.Errorf("cups-socket-directory must be a directory of $SNAP_COMMON or $SNAP_DATA")
+		return "", fmt.Errorf("%s must be a directory of $SNAP_COMMON or $SNAP_DATA", attr)
 	}
 	// otherwise it must have a prefix of either SNAP_COMMON or SNAP_DATA,
 	// validate that it has no other variables in it
@@ -130,11 +130,26 @@ func validateCupsSocketDirSlotAttr(a interfaces.Attrer, snapInfo *snap.Info) (st
 	return snapInfo.ExpandSnapVariables(cupsdSocketSourceDir), nil
 }
 
+func validateCupsSocketDirSlotAttr(a interfaces.Attrer, snapInfo *snap.Info) (string, error) {
+	return validateCupsDirSlotAttr(a, snapInfo, "cups-socket-directory")
+}
+
+// validateCupsBackendDirSlotAttr validates the optional directory in which
+// the print server discovers backends at runtime. Snaps providing backends,
+// such as printer applications, create their sockets in that directory which
+// is mounted at /var/cups-backends/ in their mount namespace.
+func validateCupsBackendDirSlotAttr(a interfaces.Attrer, snapInfo *snap.Info) (string, error) {
+	return validateCupsDirSlotAttr(a, snapInfo, "cups-backend-directory")
+}
+
 func (iface *cupsInterface) BeforePrepareSlot(slot *snap.SlotInfo) error {
 	// verify that the snap has a cups-socket-directory interface attribute, which is
 	// needed to identify where to find the cups socket is located in the snap
 	// providing the cups socket
-	_, err := validateCupsSocketDirSlotAttr(slot, slot.Snap)
+	if _, err := validateCupsSocketDirSlotAttr(slot, slot.Snap); err != nil {
+		return err
+	}
+	_, err := validateCupsBackendDirSlotAttr(slot, slot.Snap)
 	return err
 }
 
@@ -182,6 +197,28 @@ func (iface *cupsInterface) AppArmorConnectedPlug(spec *apparmor.Specification,
 	apparmor.GenWritableProfile(emit, cupsdSocketSourceDir, 1)
 	apparmor.GenWritableProfile(emit, "/var/cups", 1)
 
+	backendDir, err := validateCupsBackendDirSlotAttr(slot, slot.Snap())
+	if err != nil {
+		return err
+	}
+	if backendDir == "" {
+		return nil
+	}
+
+	spec.AddSnippet(fmt.Sprintf(`
+# Allow providing backends to the print server by creating sockets in the
+# directory where the print server discovers them.
+/var/cups-backends/ r,
+/var/cups-backends/*.sock rwk,
+"%s/*.sock" rwk,`, backendDir))
+
+	emit("  # Mount cups backend socket directory from cups snap to client snap\n")
+	emit("  mount options=(rw bind) \"%s/\" -> /var/cups-backends/,\n", backendDir)
+	emit("  umount /var/cups-backends/,\n")
+
+	apparmor.GenWritableProfile(emit, backendDir, 1)
+	apparmor.GenWritableProfile(emit, "/var/cups-backends", 1)
+
 	return nil
 }
 
@@ -198,13 +235,37 @@ func (iface *cupsInterface) MountConnectedPlug(spec *mount.Specification, plug *
 	}
 
 	// add a bind mount of the cups-socket-directory to /var/cups of the plugging snap
-	return spec.AddMountEntry(osutil.MountEntry{
+	if err := spec.AddMountEntry(osutil.MountEntry{
 		Name:    cupsdSocketSourceDir,
 		Dir:     "/var/cups/",
 		Options: []string{"bind", "rw"},
+	}); err != nil {
+		return err
+	}
+
+	backendDir, err := validateCupsBackendDirSlotAttr(slot, slot.Snap())
+	if err != nil {
+		return err
+	}
+	if backendDir == "" {
+		return nil
+	}
+	return spec.AddMountEntry(osutil.MountEntry{
+		Name:    backendDir,
+		Dir:     "/var/cups-backends/",
+		Options: []string{"bind", "rw"},
 	})
 }
 
+func (iface *cupsInterface) AutoConnect(plug *snap.PlugInfo, slot *snap.SlotInfo) bool {
+	// Only auto-connect to print servers which mediate access, as
+	// indicated by exporting their socket directory; legacy providers
+	// without it need to be connected manually. Whether the provider is
+	// allowed to auto-connect at all is decided by its snap declaration.
+	_, ok := slot.Lookup("cups-socket-directory")
+	return ok
+}
+
 func init() {
 	registerIface(&cupsInterface{
 		commonInterface: commonInterface{
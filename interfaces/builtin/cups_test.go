@@ -245,6 +245,61 @@ func (s *cupsSuite) TestMountSpec(c *C) {
 	c.Assert(specLegacy.UserMountEntries(), HasLen, 0)
 }
 
+const cupsProviderBackendsYaml = `name: provider
+version: 0
+slots:
+  cups-socket:
+    interface: cups
+    cups-socket-directory: $SNAP_COMMON/foo-subdir
+    cups-backend-directory: $SNAP_COMMON/backends
+apps:
+ app:
+  slots: [cups-socket]
+`
+
+func (s *cupsSuite) TestSanitizeSlotBackendDirectory(c *C) {
+	slot := MockSlot(c, cupsProviderBackendsYaml, nil, "cups-socket")
+	c.Assert(interfaces.BeforePrepareSlot(s.iface, slot), IsNil)
+
+	slot = MockSlot(c, strings.Replace(cupsProviderBackendsYaml, "$SNAP_COMMON/backends", "$SNAP/backends", 1), nil, "cups-socket")
+	c.Assert(interfaces.BeforePrepareSlot(s.iface, slot), ErrorMatches, `cups-backend-directory must be a directory of \$SNAP_COMMON or \$SNAP_DATA`)
+}
+
+func (s *cupsSuite) TestAppArmorSpecBackendDirectory(c *C) {
+	slot, _ := MockConnectedSlot(c, cupsProviderBackendsYaml, nil, "cups-socket")
+	spec := &apparmor.Specification{}
+	c.Assert(spec.AddConnectedPlug(s.iface, s.plug, slot), IsNil)
+	c.Check(spec.SnippetForTag("snap.consumer.app"), testutil.Contains, "/var/cups-backends/*.sock rwk,\n")
+	c.Check(spec.SnippetForTag("snap.consumer.app"), testutil.Contains, `"/var/snap/provider/common/backends/*.sock" rwk,`)
+	updateNS := strings.Join(spec.UpdateNS(), "")
+	c.Check(updateNS, testutil.Contains, "  mount options=(rw bind) \"/var/snap/provider/common/backends/\" -> /var/cups-backends/,\n")
+	c.Check(updateNS, testutil.Contains, "  umount /var/cups-backends/,\n")
+}
+
+func (s *cupsSuite) TestMountSpecBackendDirectory(c *C) {
+	slot, _ := MockConnectedSlot(c, cupsProviderBackendsYaml, nil, "cups-socket")
+	spec := &mount.Specification{}
+	c.Assert(spec.AddConnectedPlug(s.iface, s.plug, slot), IsNil)
+	c.Assert(spec.MountEntries(), DeepEquals, []osutil.MountEntry{
+		{
+			Name:    "/var/snap/provider/common/foo-subdir",
+			Dir:     "/var/cups/",
+			Options: []string{"bind", "rw"},
+		},
+		{
+			Name:    "/var/snap/provider/common/backends",
+			Dir:     "/var/cups-backends/",
+			Options: []string{"bind", "rw"},
+		},
+	})
+}
+
+func (s *cupsSuite) TestAutoConnect(c *C) {
+	c.Check(s.iface.AutoConnect(s.plugInfo, s.providerSlotInfo), Equals, true)
+	// legacy providers do not mediate access
+	c.Check(s.iface.AutoConnect(s.plugInfo, s.providerLegacySlotInfo), Equals, false)
+}
+
 func (s *cupsSuite) TestStaticInfo(c *C) {
 	si := interfaces.StaticInfoOf(s.iface)
 	c.Assert(si.ImplicitOnCore, Equals, false)
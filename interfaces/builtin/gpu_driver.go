@@ -0,0 +1,213 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package builtin
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/mount"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
+)
+
+const gpuDriverSummary = `allows using the userspace stack of a GPU driver provided by another snap`
+
+// gpu-driver slots are provided by kernel or gadget snaps that ship the
+// userspace part of the driver matching the kernel modules of the device.
+// Vendor snaps may provide them too but need a store declaration.
+const gpuDriverBaseDeclarationSlots = `
+  gpu-driver:
+    allow-installation:
+      slot-snap-type:
+        - kernel
+        - gadget
+    allow-connection:
+      plug-attributes:
+        api: $SLOT(api)
+    allow-auto-connection:
+      plug-attributes:
+        api: $SLOT(api)
+`
+
+// gpuDriverInterface allows a snap providing the userspace stack of a GPU
+// driver, such as the NVIDIA or ROCm libraries, to export it to application
+// snaps. Application snaps state which versions of the stack they can work
+// with, and only get a compatible stack mounted, instead of relying on
+// libraries found on the host.
+type gpuDriverInterface struct{}
+
+var gpuDriverAPIPattern = regexp.MustCompile(`^[a-z0-9](?:-?[a-z0-9])*$`)
+
+func (iface *gpuDriverInterface) Name() string {
+	return "gpu-driver"
+}
+
+func (iface *gpuDriverInterface) StaticInfo() interfaces.StaticInfo {
+	return interfaces.StaticInfo{
+		Summary:              gpuDriverSummary,
+		BaseDeclarationSlots: gpuDriverBaseDeclarationSlots,
+
+		AffectsPlugOnRefresh: true,
+	}
+}
+
+func validateGPUDriverAPI(attrs interfaces.Attrer) error {
+	var api string
+	if err := attrs.Attr("api", &api); err != nil || api == "" {
+		return fmt.Errorf(`gpu-driver "api" attribute must be a non-empty string`)
+	}
+	if !gpuDriverAPIPattern.MatchString(api) {
+		return fmt.Errorf(`gpu-driver "api" attribute is invalid: %q`, api)
+	}
+	return nil
+}
+
+func validateGPUDriverVersion(attrs interfaces.Attrer, name string, required bool) error {
+	value, ok := attrs.Lookup(name)
+	if !ok && !required {
+		return nil
+	}
+	version, ok := value.(string)
+	if !ok || version == "" {
+		return fmt.Errorf(`gpu-driver %q attribute must be a non-empty string`, name)
+	}
+	if _, err := strutil.VersionCompare(version, version); err != nil {
+		return fmt.Errorf(`gpu-driver %q attribute is invalid: %v`, name, err)
+	}
+	return nil
+}
+
+func validateGPUDriverPath(attrs interfaces.Attrer, name string) error {
+	var path string
+	if err := attrs.Attr(name, &path); err != nil || path == "" {
+		return fmt.Errorf(`gpu-driver %q attribute must be a non-empty string`, name)
+	}
+	if err := validatePath(path); err != nil {
+		return fmt.Errorf(`gpu-driver %q attribute is invalid: %v`, name, err)
+	}
+	return nil
+}
+
+func (iface *gpuDriverInterface) BeforePrepareSlot(slot *snap.SlotInfo) error {
+	if err := validateGPUDriverAPI(slot); err != nil {
+		return err
+	}
+	if err := validateGPUDriverVersion(slot, "version", true); err != nil {
+		return err
+	}
+	return validateGPUDriverPath(slot, "source")
+}
+
+func (iface *gpuDriverInterface) BeforePreparePlug(plug *snap.PlugInfo) error {
+	if err := validateGPUDriverAPI(plug); err != nil {
+		return err
+	}
+	for _, name := range []string{"min-version", "max-version"} {
+		if err := validateGPUDriverVersion(plug, name, false); err != nil {
+			return err
+		}
+	}
+	return validateGPUDriverPath(plug, "target")
+}
+
+// gpuDriverCompatible checks that the version of the driver stack provided
+// by the slot is within the range of versions supported by the plug.
+func gpuDriverCompatible(plug, slot interfaces.Attrer) error {
+	var version, minVersion, maxVersion string
+	if err := slot.Attr("version", &version); err != nil {
+		return err
+	}
+	// Both are optional and were validated in BeforePreparePlug.
+	_ = plug.Attr("min-version", &minVersion)
+	_ = plug.Attr("max-version", &maxVersion)
+
+	if minVersion != "" {
+		if res, err := strutil.VersionCompare(version, minVersion); err != nil {
+			return err
+		} else if res < 0 {
+			return fmt.Errorf("driver version %s is older than the minimum version %s", version, minVersion)
+		}
+	}
+	if maxVersion != "" {
+		if res, err := strutil.VersionCompare(version, maxVersion); err != nil {
+			return err
+		} else if res > 0 {
+			return fmt.Errorf("driver version %s is newer than the maximum version %s", version, maxVersion)
+		}
+	}
+	return nil
+}
+
+func gpuDriverSourceTarget(plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) (string, string) {
+	var source, target string
+	// Both attributes were validated in BeforePrepare{Plug,Slot}.
+	_ = slot.Attr("source", &source)
+	_ = plug.Attr("target", &target)
+	return resolveSpecialVariable(source, slot.Snap()), resolveSpecialVariable(target, plug.Snap())
+}
+
+func (iface *gpuDriverInterface) AppArmorConnectedPlug(spec *apparmor.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	var api string
+	_ = slot.Attr("api", &api)
+	source, target := gpuDriverSourceTarget(plug, slot)
+
+	var snippet bytes.Buffer
+	fmt.Fprintf(&snippet, `
+# Description: Allow using the %s driver stack provided by %s.
+"%s/**" mrkix,
+`, api, slot.Snap().InstanceName(), source)
+	spec.AddSnippet(snippet.String())
+
+	emit := spec.AddUpdateNSf
+	emit("  # GPU driver stack %s -> %s\n", slot.Ref(), plug.Ref())
+	emit("  mount options=(bind) \"%s/\" -> \"%s/\",\n", source, target)
+	emit("  remount options=(bind, ro) \"%s/\",\n", target)
+	emit("  mount options=(rprivate) -> \"%s/\",\n", target)
+	emit("  umount \"%s/\",\n", target)
+	apparmor.GenWritableProfile(emit, source, 1)
+	apparmor.GenWritableProfile(emit, target, 1)
+	return nil
+}
+
+func (iface *gpuDriverInterface) MountConnectedPlug(spec *mount.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
+	if err := gpuDriverCompatible(plug, slot); err != nil {
+		return fmt.Errorf("cannot use %s with %s: %v", slot.Ref(), plug.Ref(), err)
+	}
+	source, target := gpuDriverSourceTarget(plug, slot)
+	return spec.AddMountEntry(osutil.MountEntry{
+		Name:    source,
+		Dir:     target,
+		Options: []string{"bind", "ro"},
+	})
+}
+
+func (iface *gpuDriverInterface) AutoConnect(plug *snap.PlugInfo, slot *snap.SlotInfo) bool {
+	// only auto-connect to a driver stack the plug can work with
+	return gpuDriverCompatible(plug, slot) == nil
+}
+
+func init() {
+	registerIface(&gpuDriverInterface{})
+}
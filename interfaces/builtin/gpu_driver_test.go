@@ -0,0 +1,186 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package builtin_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/mount"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type gpuDriverSuite struct {
+	iface    interfaces.Interface
+	slotInfo *snap.SlotInfo
+	slot     *interfaces.ConnectedSlot
+	plugInfo *snap.PlugInfo
+	plug     *interfaces.ConnectedPlug
+}
+
+var _ = Suite(&gpuDriverSuite{iface: builtin.MustInterface("gpu-driver")})
+
+const gpuDriverConsumerYaml = `name: consumer
+version: 0
+plugs:
+  gpu-driver:
+    api: nvidia
+    min-version: "535"
+    max-version: "550.999"
+    target: $SNAP/gpu
+apps:
+  app:
+    plugs: [gpu-driver]
+`
+
+const gpuDriverProviderYaml = `name: provider
+version: 0
+type: kernel
+slots:
+  gpu-driver:
+    api: nvidia
+    version: "550.54"
+    source: $SNAP/nvidia
+`
+
+func (s *gpuDriverSuite) SetUpTest(c *C) {
+	s.plug, s.plugInfo = MockConnectedPlug(c, gpuDriverConsumerYaml, &snap.SideInfo{Revision: snap.R(1)}, "gpu-driver")
+	s.slot, s.slotInfo = MockConnectedSlot(c, gpuDriverProviderYaml, &snap.SideInfo{Revision: snap.R(2)}, "gpu-driver")
+}
+
+func (s *gpuDriverSuite) TestName(c *C) {
+	c.Assert(s.iface.Name(), Equals, "gpu-driver")
+}
+
+func (s *gpuDriverSuite) TestSanitizeSlot(c *C) {
+	c.Assert(interfaces.BeforePrepareSlot(s.iface, s.slotInfo), IsNil)
+}
+
+func (s *gpuDriverSuite) TestSanitizeSlotErrors(c *C) {
+	for _, t := range []struct {
+		attrs string
+		err   string
+	}{
+		{"version: '1'\n    source: lib", `gpu-driver "api" attribute must be a non-empty string`},
+		{"api: NVidia\n    version: '1'\n    source: lib", `gpu-driver "api" attribute is invalid: "NVidia"`},
+		{"api: nvidia\n    source: lib", `gpu-driver "version" attribute must be a non-empty string`},
+		{"api: nvidia\n    version: '1:'\n    source: lib", `gpu-driver "version" attribute is invalid: .*`},
+		{"api: nvidia\n    version: '1'", `gpu-driver "source" attribute must be a non-empty string`},
+		{"api: nvidia\n    version: '1'\n    source: ../lib", `gpu-driver "source" attribute is invalid: content interface path is not clean: "../lib"`},
+	} {
+		slot := MockSlot(c, "name: provider\nversion: 0\ntype: kernel\nslots:\n  gpu-driver:\n    "+t.attrs+"\n", nil, "gpu-driver")
+		c.Check(interfaces.BeforePrepareSlot(s.iface, slot), ErrorMatches, t.err, Commentf("%s", t.attrs))
+	}
+}
+
+func (s *gpuDriverSuite) TestSanitizePlug(c *C) {
+	c.Assert(interfaces.BeforePreparePlug(s.iface, s.plugInfo), IsNil)
+}
+
+func (s *gpuDriverSuite) TestSanitizePlugErrors(c *C) {
+	for _, t := range []struct {
+		attrs string
+		err   string
+	}{
+		{"target: gpu", `gpu-driver "api" attribute must be a non-empty string`},
+		{"api: nvidia\n    min-version: 1\n    target: gpu", `gpu-driver "min-version" attribute must be a non-empty string`},
+		{"api: nvidia\n    max-version: '1:'\n    target: gpu", `gpu-driver "max-version" attribute is invalid: .*`},
+		{"api: nvidia", `gpu-driver "target" attribute must be a non-empty string`},
+		{"api: nvidia\n    target: gpu/[a]", `gpu-driver "target" attribute is invalid: content interface path is invalid: .*`},
+	} {
+		plug := MockPlug(c, "name: consumer\nversion: 0\nplugs:\n  gpu-driver:\n    "+t.attrs+"\n", nil, "gpu-driver")
+		c.Check(interfaces.BeforePreparePlug(s.iface, plug), ErrorMatches, t.err, Commentf("%s", t.attrs))
+	}
+}
+
+func (s *gpuDriverSuite) TestAutoConnect(c *C) {
+	c.Check(s.iface.AutoConnect(s.plugInfo, s.slotInfo), Equals, true)
+
+	for _, version := range []string{"530", "551"} {
+		slotInfo := MockSlot(c, `name: provider
+version: 0
+type: kernel
+slots:
+  gpu-driver:
+    api: nvidia
+    version: "`+version+`"
+    source: $SNAP/nvidia
+`, nil, "gpu-driver")
+		c.Check(s.iface.AutoConnect(s.plugInfo, slotInfo), Equals, false, Commentf("%s", version))
+	}
+
+	// without constraints any version is fine
+	plugInfo := MockPlug(c, `name: consumer
+version: 0
+plugs:
+  gpu-driver:
+    api: nvidia
+    target: $SNAP/gpu
+`, nil, "gpu-driver")
+	c.Check(s.iface.AutoConnect(plugInfo, s.slotInfo), Equals, true)
+}
+
+func (s *gpuDriverSuite) TestMountSpec(c *C) {
+	spec := &mount.Specification{}
+	c.Assert(spec.AddConnectedPlug(s.iface, s.plug, s.slot), IsNil)
+	c.Check(spec.MountEntries(), DeepEquals, []osutil.MountEntry{{
+		Name:    "/snap/provider/2/nvidia",
+		Dir:     "/snap/consumer/1/gpu",
+		Options: []string{"bind", "ro"},
+	}})
+}
+
+func (s *gpuDriverSuite) TestMountSpecIncompatibleVersion(c *C) {
+	slot, _ := MockConnectedSlot(c, `name: provider
+version: 0
+type: kernel
+slots:
+  gpu-driver:
+    api: nvidia
+    version: "470.1"
+    source: $SNAP/nvidia
+`, nil, "gpu-driver")
+	spec := &mount.Specification{}
+	err := spec.AddConnectedPlug(s.iface, s.plug, slot)
+	c.Check(err, ErrorMatches, `cannot use provider:gpu-driver with consumer:gpu-driver: driver version 470.1 is older than the minimum version 535`)
+	c.Check(spec.MountEntries(), HasLen, 0)
+}
+
+func (s *gpuDriverSuite) TestAppArmorSpec(c *C) {
+	spec := &apparmor.Specification{}
+	c.Assert(spec.AddConnectedPlug(s.iface, s.plug, s.slot), IsNil)
+	c.Assert(spec.SecurityTags(), DeepEquals, []string{"snap.consumer.app"})
+	c.Check(spec.SnippetForTag("snap.consumer.app"), testutil.Contains, "# Description: Allow using the nvidia driver stack provided by provider.\n")
+	c.Check(spec.SnippetForTag("snap.consumer.app"), testutil.Contains, "\"/snap/provider/2/nvidia/**\" mrkix,\n")
+
+	updateNS := spec.UpdateNS()
+	c.Check(updateNS, testutil.Contains, "  # GPU driver stack provider:gpu-driver -> consumer:gpu-driver\n")
+	c.Check(updateNS, testutil.Contains, "  mount options=(bind) \"/snap/provider/2/nvidia/\" -> \"/snap/consumer/1/gpu/\",\n")
+	c.Check(updateNS, testutil.Contains, "  remount options=(bind, ro) \"/snap/consumer/1/gpu/\",\n")
+	c.Check(updateNS, testutil.Contains, "  umount \"/snap/consumer/1/gpu/\",\n")
+}
+
+func (s *gpuDriverSuite) TestInterfaces(c *C) {
+	c.Check(builtin.Interfaces(), testutil.DeepContains, s.iface)
+}
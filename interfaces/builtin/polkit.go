@@ -21,10 +21,17 @@ package builtin
 
 import (
 	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/sha3"
 
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/polkit"
@@ -116,18 +123,103 @@ func loadPolkitPolicy(filename, actionPrefix string) (polkit.Policy, error) {
 	return polkit.Policy(content), nil
 }
 
+// polkitRule describes a polkit rule file shipped by a snap in meta/polkit/
+// together with the expected digest of its content. Since the digests are
+// part of the plug attributes, a snap declaration allowing the plug can pin
+// the exact rules a snap is allowed to install.
+type polkitRule struct {
+	Name     string
+	SHA3_384 []byte
+}
+
+var polkitRuleNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*\.rules$`)
+
+// maxPolkitRuleSize is the maximum size of a polkit rule file shipped by a
+// snap.
+const maxPolkitRuleSize = 64 * 1024
+
+func (iface *polkitInterface) getInstallRules(plug interfaces.Attrer, plugName string) ([]polkitRule, error) {
+	value, ok := plug.Lookup("install-rules")
+	if !ok {
+		return nil, nil
+	}
+	entries, ok := value.([]interface{})
+	if !ok || len(entries) == 0 {
+		return nil, fmt.Errorf(`"install-rules" attribute must be a non-empty list`)
+	}
+	rules := make([]polkitRule, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		m, ok := entry.(map[string]interface{})
+		if !ok || len(m) != 2 {
+			return nil, fmt.Errorf(`"install-rules" entries must be maps with "name" and "sha3-384" keys`)
+		}
+		name, ok := m["name"].(string)
+		if !ok || !polkitRuleNamePattern.MatchString(name) || !strings.HasPrefix(name, plugName+".") {
+			return nil, fmt.Errorf(`"install-rules" entry has invalid name %q, expected %q`, m["name"], plugName+".<name>.rules")
+		}
+		if seen[name] {
+			return nil, fmt.Errorf(`"install-rules" entry %q is duplicated`, name)
+		}
+		seen[name] = true
+		encoded, ok := m["sha3-384"].(string)
+		if !ok {
+			return nil, fmt.Errorf(`"install-rules" entry %q must have a "sha3-384" string`, name)
+		}
+		digest, err := base64.RawURLEncoding.DecodeString(encoded)
+		if err != nil || len(digest) != 48 {
+			return nil, fmt.Errorf(`"install-rules" entry %q has invalid sha3-384 digest %q`, name, encoded)
+		}
+		rules = append(rules, polkitRule{Name: name, SHA3_384: digest})
+	}
+	return rules, nil
+}
+
+func loadPolkitRule(filename string, expectedDigest []byte) (polkit.Rule, error) {
+	fi, err := os.Lstat(filename)
+	if err != nil {
+		return nil, fmt.Errorf(`cannot read file %q: %v`, filename, err)
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, fmt.Errorf(`rule file %q is not a regular file`, filename)
+	}
+	if fi.Size() > maxPolkitRuleSize {
+		return nil, fmt.Errorf(`rule file %q is too big (%d bytes, max %d)`, filename, fi.Size(), maxPolkitRuleSize)
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf(`cannot read file %q: %v`, filename, err)
+	}
+
+	digest := sha3.Sum384(content)
+	if subtle.ConstantTimeCompare(digest[:], expectedDigest) != 1 {
+		return nil, fmt.Errorf(`rule file %q does not match the expected sha3-384 digest`, filename)
+	}
+	// polkitd parses the rules as JavaScript, only accept text
+	if !utf8.Valid(content) || bytes.IndexByte(content, 0) >= 0 {
+		return nil, fmt.Errorf(`rule file %q is not valid UTF-8 text`, filename)
+	}
+
+	return polkit.Rule(content), nil
+}
+
 func (iface *polkitInterface) PolkitConnectedPlug(spec *polkit.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
 	actionPrefix, err := iface.getActionPrefix(plug)
 	if err != nil {
 		return err
 	}
 
+	rules, err := iface.getInstallRules(plug, plug.Name())
+	if err != nil {
+		return err
+	}
+
 	mountDir := plug.Snap().MountDir()
 	policyFiles, err := filepath.Glob(filepath.Join(mountDir, "meta", "polkit", plug.Name()+".*.policy"))
 	if err != nil {
 		return err
 	}
-	if len(policyFiles) == 0 {
+	if len(policyFiles) == 0 && len(rules) == 0 {
 		return fmt.Errorf("cannot find any policy files for plug %q", plug.Name())
 	}
 	for _, filename := range policyFiles {
@@ -140,11 +232,23 @@ func (iface *polkitInterface) PolkitConnectedPlug(spec *polkit.Specification, pl
 			return err
 		}
 	}
+	for _, r := range rules {
+		rule, err := loadPolkitRule(filepath.Join(mountDir, "meta", "polkit", r.Name), r.SHA3_384)
+		if err != nil {
+			return err
+		}
+		if err := spec.AddRule(strings.TrimSuffix(r.Name, ".rules"), rule); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (iface *polkitInterface) BeforePreparePlug(plug *snap.PlugInfo) error {
-	_, err := iface.getActionPrefix(plug)
+	if _, err := iface.getActionPrefix(plug); err != nil {
+		return err
+	}
+	_, err := iface.getInstallRules(plug, plug.Name)
 	return err
 }
 
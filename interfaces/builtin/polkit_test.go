@@ -20,10 +20,13 @@
 package builtin_test
 
 import (
+	"encoding/base64"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"golang.org/x/crypto/sha3"
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/dirs"
@@ -191,6 +194,90 @@ func (s *polkitInterfaceSuite) TestConnectedPlugPolkitBadImplies(c *C) {
 	c.Check(err, ErrorMatches, `policy file ".*/meta/polkit/polkit.foo.policy" contains unexpected action ID "org.freedesktop.systemd1.manage-units"`)
 }
 
+const samplePolkitRule = `polkit.addRule(function(action, subject) {
+    if (action.id == "org.example.foo.some-action" && subject.isInGroup("sudo")) {
+        return polkit.Result.YES;
+    }
+});
+`
+
+func polkitRuleDigest(content string) string {
+	digest := sha3.Sum384([]byte(content))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+func (s *polkitInterfaceSuite) mockPlugWithRules(c *C, rules string) {
+	plugSnap := snaptest.MockSnap(c, fmt.Sprintf(`name: other
+version: 1.0
+plugs:
+ polkit:
+  action-prefix: org.example.foo
+  install-rules:
+%s
+apps:
+ app:
+  command: foo
+  plugs: [polkit]
+`, rules), &snap.SideInfo{
+		RealName: "other",
+		Revision: snap.R(1),
+	})
+	s.plugInfo = plugSnap.Plugs["polkit"]
+	s.plug = interfaces.NewConnectedPlug(s.plugInfo, nil, nil)
+	c.Assert(os.MkdirAll(filepath.Join(s.plugInfo.Snap.MountDir(), "meta/polkit"), 0755), IsNil)
+}
+
+func (s *polkitInterfaceSuite) TestConnectedPlugPolkitRules(c *C) {
+	s.mockPlugWithRules(c, fmt.Sprintf("   - name: polkit.foo.rules\n     sha3-384: %s", polkitRuleDigest(samplePolkitRule)))
+	rulePath := filepath.Join(s.plugInfo.Snap.MountDir(), "meta/polkit/polkit.foo.rules")
+	c.Assert(os.WriteFile(rulePath, []byte(samplePolkitRule), 0644), IsNil)
+
+	polkitSpec := &polkit.Specification{}
+	err := polkitSpec.AddConnectedPlug(s.iface, s.plug, s.slot)
+	c.Assert(err, IsNil)
+
+	// rules can be shipped without policy files
+	c.Check(polkitSpec.Policies(), HasLen, 0)
+	c.Check(polkitSpec.Rules(), DeepEquals, map[string]polkit.Rule{
+		"polkit.foo": polkit.Rule(samplePolkitRule),
+	})
+}
+
+func (s *polkitInterfaceSuite) TestConnectedPlugPolkitRulesDigestMismatch(c *C) {
+	s.mockPlugWithRules(c, fmt.Sprintf("   - name: polkit.foo.rules\n     sha3-384: %s", polkitRuleDigest("something else")))
+	rulePath := filepath.Join(s.plugInfo.Snap.MountDir(), "meta/polkit/polkit.foo.rules")
+	c.Assert(os.WriteFile(rulePath, []byte(samplePolkitRule), 0644), IsNil)
+
+	polkitSpec := &polkit.Specification{}
+	err := polkitSpec.AddConnectedPlug(s.iface, s.plug, s.slot)
+	c.Check(err, ErrorMatches, `rule file ".*/meta/polkit/polkit.foo.rules" does not match the expected sha3-384 digest`)
+}
+
+func (s *polkitInterfaceSuite) TestConnectedPlugPolkitRulesBadContent(c *C) {
+	const badRule = "polkit.addRule(\x00);"
+	s.mockPlugWithRules(c, fmt.Sprintf("   - name: polkit.foo.rules\n     sha3-384: %s", polkitRuleDigest(badRule)))
+	rulePath := filepath.Join(s.plugInfo.Snap.MountDir(), "meta/polkit/polkit.foo.rules")
+	c.Assert(os.WriteFile(rulePath, []byte(badRule), 0644), IsNil)
+
+	polkitSpec := &polkit.Specification{}
+	err := polkitSpec.AddConnectedPlug(s.iface, s.plug, s.slot)
+	c.Check(err, ErrorMatches, `rule file ".*/meta/polkit/polkit.foo.rules" is not valid UTF-8 text`)
+}
+
+func (s *polkitInterfaceSuite) TestConnectedPlugPolkitRulesNotFile(c *C) {
+	s.mockPlugWithRules(c, fmt.Sprintf("   - name: polkit.foo.rules\n     sha3-384: %s", polkitRuleDigest(samplePolkitRule)))
+	rulePath := filepath.Join(s.plugInfo.Snap.MountDir(), "meta/polkit/polkit.foo.rules")
+	c.Assert(os.Symlink("/etc/shadow", rulePath), IsNil)
+
+	polkitSpec := &polkit.Specification{}
+	err := polkitSpec.AddConnectedPlug(s.iface, s.plug, s.slot)
+	c.Check(err, ErrorMatches, `rule file ".*/meta/polkit/polkit.foo.rules" is not a regular file`)
+
+	c.Assert(os.Remove(rulePath), IsNil)
+	err = polkitSpec.AddConnectedPlug(s.iface, s.plug, s.slot)
+	c.Check(err, ErrorMatches, `cannot read file ".*/meta/polkit/polkit.foo.rules": .* no such file or directory`)
+}
+
 func (s *polkitInterfaceSuite) TestSanitizeSlot(c *C) {
 	c.Assert(interfaces.BeforePrepareSlot(s.iface, s.slotInfo), IsNil)
 }
@@ -209,6 +296,13 @@ plugs:
 	info := snaptest.MockInfo(c, mockSnapYaml, nil)
 	plug := info.Plugs["polkit"]
 	c.Assert(interfaces.BeforePreparePlug(s.iface, plug), IsNil)
+
+	info = snaptest.MockInfo(c, mockSnapYaml+fmt.Sprintf(`  install-rules:
+   - name: polkit.bar.rules
+     sha3-384: %s
+`, polkitRuleDigest(samplePolkitRule)), nil)
+	plug = info.Plugs["polkit"]
+	c.Assert(interfaces.BeforePreparePlug(s.iface, plug), IsNil)
 }
 
 func (s *polkitInterfaceSuite) TestSanitizePlugUnhappy(c *C) {
@@ -234,6 +328,16 @@ plugs:
 		{`action-prefix: "org.example\n"`, `plug has invalid action-prefix: "org.example\\n"`},
 		{`action-prefix: "com.example "`, `plug has invalid action-prefix: "com.example "`},
 		{`action-prefix: "123.foo.bar"`, `plug has invalid action-prefix: "123.foo.bar"`},
+
+		{"action-prefix: org.example.foo\n  install-rules: foo", `"install-rules" attribute must be a non-empty list`},
+		{"action-prefix: org.example.foo\n  install-rules: []", `"install-rules" attribute must be a non-empty list`},
+		{"action-prefix: org.example.foo\n  install-rules: [foo]", `"install-rules" entries must be maps with "name" and "sha3-384" keys`},
+		{"action-prefix: org.example.foo\n  install-rules:\n   - name: polkit.foo.rules", `"install-rules" entries must be maps with "name" and "sha3-384" keys`},
+		{"action-prefix: org.example.foo\n  install-rules:\n   - name: other.foo.rules\n     sha3-384: abc", `"install-rules" entry has invalid name "other.foo.rules", expected "polkit.<name>.rules"`},
+		{"action-prefix: org.example.foo\n  install-rules:\n   - name: polkit.foo.js\n     sha3-384: abc", `"install-rules" entry has invalid name "polkit.foo.js", expected "polkit.<name>.rules"`},
+		{"action-prefix: org.example.foo\n  install-rules:\n   - name: polkit.foo.rules\n     sha3-384: 42", `"install-rules" entry "polkit.foo.rules" must have a "sha3-384" string`},
+		{"action-prefix: org.example.foo\n  install-rules:\n   - name: polkit.foo.rules\n     sha3-384: abc", `"install-rules" entry "polkit.foo.rules" has invalid sha3-384 digest "abc"`},
+		{fmt.Sprintf("action-prefix: org.example.foo\n  install-rules:\n   - name: polkit.foo.rules\n     sha3-384: %[1]s\n   - name: polkit.foo.rules\n     sha3-384: %[1]s", polkitRuleDigest("")), `"install-rules" entry "polkit.foo.rules" is duplicated`},
 	}
 
 	for _, t := range testCases {
@@ -0,0 +1,62 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package builtin
+
+const wslInteropSummary = `allows invoking Windows executables in the Windows Subsystem for Linux`
+
+const wslInteropBaseDeclarationSlots = `
+  wsl-interop:
+    allow-installation:
+      slot-snap-type:
+        - core
+    deny-auto-connection: true
+`
+
+// Windows executables are run by the interpreter WSL registers with
+// binfmt_misc, which forwards the invocation to the Windows host through a
+// socket under /run/WSL. The executable itself runs outside of any
+// confinement, with the privileges of the Windows user.
+const wslInteropConnectedPlugAppArmor = `
+# Description: Can invoke Windows executables found on the Windows drives
+# through the interoperability layer of the Windows Subsystem for Linux.
+# This gives access to the Windows host with the privileges of the Windows
+# user and is therefore reserved to trusted snaps.
+
+/proc/sys/fs/binfmt_misc/WSLInterop r,
+
+# Windows executables on the Windows drives mounted by WSL
+/mnt/[a-z]/ r,
+/mnt/[a-z]/**/ r,
+/mnt/[a-z]/**.{exe,EXE,com,COM} ixr,
+
+# The sockets used to reach the Windows host
+/run/WSL/ r,
+/run/WSL/*_interop rw,
+`
+
+func init() {
+	registerIface(&commonInterface{
+		name:                  "wsl-interop",
+		summary:               wslInteropSummary,
+		implicitOnClassic:     true,
+		baseDeclarationSlots:  wslInteropBaseDeclarationSlots,
+		connectedPlugAppArmor: wslInteropConnectedPlugAppArmor,
+	})
+}
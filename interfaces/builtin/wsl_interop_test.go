@@ -0,0 +1,98 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package builtin_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type WSLInteropInterfaceSuite struct {
+	iface    interfaces.Interface
+	slotInfo *snap.SlotInfo
+	slot     *interfaces.ConnectedSlot
+	plugInfo *snap.PlugInfo
+	plug     *interfaces.ConnectedPlug
+}
+
+var _ = Suite(&WSLInteropInterfaceSuite{
+	iface: builtin.MustInterface("wsl-interop"),
+})
+
+const wslInteropConsumerYaml = `name: consumer
+version: 0
+apps:
+ app:
+   plugs: [wsl-interop]
+`
+
+const wslInteropCoreYaml = `name: core
+version: 0
+type: os
+slots:
+  wsl-interop:
+`
+
+func (s *WSLInteropInterfaceSuite) SetUpTest(c *C) {
+	s.plug, s.plugInfo = MockConnectedPlug(c, wslInteropConsumerYaml, nil, "wsl-interop")
+	s.slot, s.slotInfo = MockConnectedSlot(c, wslInteropCoreYaml, nil, "wsl-interop")
+}
+
+func (s *WSLInteropInterfaceSuite) TestName(c *C) {
+	c.Assert(s.iface.Name(), Equals, "wsl-interop")
+}
+
+func (s *WSLInteropInterfaceSuite) TestSanitizeSlot(c *C) {
+	c.Assert(interfaces.BeforePrepareSlot(s.iface, s.slotInfo), IsNil)
+}
+
+func (s *WSLInteropInterfaceSuite) TestSanitizePlug(c *C) {
+	c.Assert(interfaces.BeforePreparePlug(s.iface, s.plugInfo), IsNil)
+}
+
+func (s *WSLInteropInterfaceSuite) TestAppArmorSpec(c *C) {
+	spec := &apparmor.Specification{}
+	c.Assert(spec.AddConnectedPlug(s.iface, s.plug, s.slot), IsNil)
+	c.Assert(spec.SecurityTags(), DeepEquals, []string{"snap.consumer.app"})
+	c.Check(spec.SnippetForTag("snap.consumer.app"), testutil.Contains, `/proc/sys/fs/binfmt_misc/WSLInterop r,`)
+	c.Check(spec.SnippetForTag("snap.consumer.app"), testutil.Contains, `/mnt/[a-z]/**.{exe,EXE,com,COM} ixr,`)
+	c.Check(spec.SnippetForTag("snap.consumer.app"), testutil.Contains, `/run/WSL/*_interop rw,`)
+}
+
+func (s *WSLInteropInterfaceSuite) TestStaticInfo(c *C) {
+	si := interfaces.StaticInfoOf(s.iface)
+	c.Assert(si.ImplicitOnCore, Equals, false)
+	c.Assert(si.ImplicitOnClassic, Equals, true)
+	c.Assert(si.Summary, Equals, `allows invoking Windows executables in the Windows Subsystem for Linux`)
+	c.Assert(si.BaseDeclarationSlots, testutil.Contains, "wsl-interop")
+}
+
+func (s *WSLInteropInterfaceSuite) TestAutoConnect(c *C) {
+	c.Assert(s.iface.AutoConnect(s.plugInfo, s.slotInfo), Equals, true)
+}
+
+func (s *WSLInteropInterfaceSuite) TestInterfaces(c *C) {
+	c.Check(builtin.Interfaces(), testutil.DeepContains, s.iface)
+}
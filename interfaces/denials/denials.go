@@ -0,0 +1,414 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package denials parses the AppArmor and seccomp denials of snaps logged
+// by the kernel or the dbus daemon, aggregates them and suggests the
+// interfaces that would grant the denied access.
+package denials
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/snapcore/snapd/dirs"
+)
+
+const (
+	// KindAppArmor is the kind of denials logged by AppArmor.
+	KindAppArmor = "apparmor"
+	// KindSeccomp is the kind of denials logged by seccomp.
+	KindSeccomp = "seccomp"
+)
+
+// Denial is an AppArmor or seccomp denial of a snap.
+type Denial struct {
+	Kind string `json:"kind"`
+	Snap string `json:"snap"`
+	// Profile is the AppArmor profile or the seccomp filter of the
+	// application that was denied, e.g. snap.foo.app.
+	Profile string `json:"profile,omitempty"`
+	// Operation is the denied AppArmor operation, e.g. open or capable,
+	// or "syscall" for seccomp denials.
+	Operation string `json:"operation"`
+	// Target is what was denied: a path, a capability, a network family,
+	// a D-Bus destination or a system call.
+	Target string `json:"target"`
+	// Mask is the denied AppArmor access mask, if any.
+	Mask string `json:"mask,omitempty"`
+}
+
+var fieldRx = regexp.MustCompile(`([a-z_]+)=("[^"]*"|\S+)`)
+
+func parseFields(line string) map[string]string {
+	fields := make(map[string]string)
+	for _, m := range fieldRx.FindAllStringSubmatch(line, -1) {
+		fields[m[1]] = strings.Trim(m[2], `"`)
+	}
+	return fields
+}
+
+// snapFromLabel returns the name of the snap from its AppArmor label or
+// seccomp filter name.
+func snapFromLabel(label string) string {
+	// drop the mode, e.g. "snap.foo.app (enforce)"
+	label = strings.Fields(label + " ")[0]
+	switch {
+	case strings.HasPrefix(label, "snap."):
+		return strings.SplitN(label, ".", 3)[1]
+	case strings.HasPrefix(label, "snap-update-ns."):
+		return strings.TrimPrefix(label, "snap-update-ns.")
+	}
+	return ""
+}
+
+// snapFromExe returns the name of the snap the executable belongs to.
+func snapFromExe(exe string) string {
+	prefix := dirs.StripRootDir(dirs.SnapMountDir) + "/"
+	if !strings.HasPrefix(exe, prefix) {
+		return ""
+	}
+	return strings.SplitN(strings.TrimPrefix(exe, prefix), "/", 2)[0]
+}
+
+// Parse parses a log line and returns the denial it describes, or nil if
+// it isn't the denial of a snap.
+func Parse(line string) *Denial {
+	switch {
+	case strings.Contains(line, `apparmor="DENIED"`):
+		return parseAppArmor(parseFields(line))
+	case strings.Contains(line, "type=1326") || strings.Contains(line, "type=SECCOMP"):
+		return parseSeccomp(parseFields(line))
+	}
+	return nil
+}
+
+func parseAppArmor(fields map[string]string) *Denial {
+	label := fields["profile"]
+	if label == "" {
+		// D-Bus denials use label
+		label = fields["label"]
+	}
+	snapName := snapFromLabel(label)
+	if snapName == "" {
+		return nil
+	}
+
+	d := &Denial{
+		Kind:      KindAppArmor,
+		Snap:      snapName,
+		Profile:   strings.Fields(label + " ")[0],
+		Operation: fields["operation"],
+		Mask:      fields["denied_mask"],
+	}
+	switch {
+	case fields["capname"] != "":
+		d.Target = fields["capname"]
+	case fields["family"] != "":
+		d.Target = fields["family"]
+		if sockType := fields["sock_type"]; sockType != "" {
+			d.Target += " " + sockType
+		}
+	case strings.HasPrefix(d.Operation, "dbus_"):
+		d.Target = fields["name"]
+		if iface := fields["interface"]; iface != "" {
+			d.Target = iface
+		}
+		if member := fields["member"]; member != "" {
+			d.Target += "." + member
+		}
+		d.Mask = fields["mask"]
+	default:
+		d.Target = fields["name"]
+	}
+	return d
+}
+
+func parseSeccomp(fields map[string]string) *Denial {
+	sc := parseSyscall(fields)
+	if sc == nil {
+		return nil
+	}
+
+	return &Denial{
+		Kind:      KindSeccomp,
+		Snap:      sc.Snap,
+		Profile:   sc.Profile,
+		Operation: "syscall",
+		Target:    syscallName(fields["arch"], fields["syscall"]),
+	}
+}
+
+// Syscall is a system call of a snap logged by seccomp, either because it
+// was denied or because the seccomp filter logs the system calls it allows.
+type Syscall struct {
+	Snap string `json:"snap"`
+	// Profile is the seccomp filter of the application that made the
+	// system call, e.g. snap.foo.app, it is only known if AppArmor is
+	// in use.
+	Profile string `json:"profile,omitempty"`
+	// Arch is the audit architecture of the system call in hexadecimal,
+	// e.g. c000003e for x86_64.
+	Arch string `json:"arch"`
+	// Number is the number of the system call on its architecture.
+	Number int `json:"number"`
+}
+
+// ParseSyscall parses a log line and returns the system call it describes,
+// or nil if it isn't a system call of a snap logged by seccomp.
+func ParseSyscall(line string) *Syscall {
+	if !strings.Contains(line, "type=1326") && !strings.Contains(line, "type=SECCOMP") {
+		return nil
+	}
+	return parseSyscall(parseFields(line))
+}
+
+func parseSyscall(fields map[string]string) *Syscall {
+	profile := strings.Fields(fields["subj"] + " ")[0]
+	snapName := snapFromLabel(profile)
+	if snapName == "" {
+		// without AppArmor the filter isn't known
+		profile = ""
+		snapName = snapFromExe(fields["exe"])
+	}
+	if snapName == "" {
+		return nil
+	}
+	number, err := strconv.Atoi(fields["syscall"])
+	if err != nil {
+		return nil
+	}
+
+	return &Syscall{
+		Snap:    snapName,
+		Profile: profile,
+		Arch:    fields["arch"],
+		Number:  number,
+	}
+}
+
+// syscalls maps the audit architecture and number of the system calls that
+// interfaces can grant to their names.
+var syscalls = map[string]map[int]string{
+	// x86_64
+	"c000003e": {
+		101: "ptrace",
+		140: "getpriority",
+		141: "setpriority",
+		159: "adjtimex",
+		165: "mount",
+		166: "umount2",
+		169: "reboot",
+		175: "init_module",
+		176: "delete_module",
+		227: "clock_settime",
+		313: "finit_module",
+	},
+	// aarch64
+	"c00000b7": {
+		39:  "umount2",
+		40:  "mount",
+		105: "init_module",
+		106: "delete_module",
+		112: "clock_settime",
+		117: "ptrace",
+		140: "setpriority",
+		141: "getpriority",
+		142: "reboot",
+		171: "adjtimex",
+		273: "finit_module",
+	},
+}
+
+func syscallName(arch, number string) string {
+	if n, err := strconv.Atoi(number); err == nil {
+		if name, ok := syscalls[arch][n]; ok {
+			return name
+		}
+	}
+	return "syscall " + number
+}
+
+// suggestion maps the targets matching a regular expression to the
+// interfaces that grant access to them.
+type suggestion struct {
+	rx         *regexp.Regexp
+	interfaces []string
+}
+
+func rule(rx string, interfaces ...string) suggestion {
+	return suggestion{rx: regexp.MustCompile(rx), interfaces: interfaces}
+}
+
+var (
+	// pathSuggestions is ordered from the more specific rules to the more
+	// generic ones as only the first match is used.
+	pathSuggestions = []suggestion{
+		rule(`^/home/[^/]+/\.ssh/`, "ssh-keys"),
+		rule(`^/(home|root)/`, "home"),
+		rule(`^/(media|run/media|mnt)/`, "removable-media"),
+		rule(`^/dev/video`, "camera"),
+		rule(`^/dev/snd/`, "alsa"),
+		rule(`^/run/user/[0-9]+/pulse/`, "audio-playback"),
+		rule(`^/dev/input/js`, "joystick"),
+		rule(`^/dev/input/`, "raw-input"),
+		rule(`^/dev/tty(USB|ACM|S)[0-9]+`, "serial-port"),
+		rule(`^/dev/bus/usb/`, "raw-usb"),
+		rule(`^/dev/(dri/|nvidia)`, "opengl"),
+		rule(`^/dev/tpm`, "tpm"),
+		rule(`^/dev/kvm$`, "kvm"),
+		rule(`^/dev/fuse$`, "fuse-support"),
+		rule(`^/(sys/class/net|proc/net|proc/[0-9]+/net)/`, "network-observe"),
+		rule(`^/proc/[0-9]+/(mounts|mountinfo)$`, "mount-observe"),
+		rule(`^/(proc/[0-9]+|sys/fs/cgroup)/`, "system-observe"),
+		rule(`^/(var/log|run/log/journal)/`, "log-observe"),
+		rule(`^/sys/(class|bus|devices)/`, "hardware-observe"),
+		rule(`^/etc/(hostname|machine-info)$`, "hostname-control"),
+		rule(`^/etc/(localtime|timezone)$`, "timezone-control"),
+		rule(`^/etc/default/locale$`, "locale-control"),
+		rule(`^/(etc|run)/NetworkManager/`, "network-manager"),
+	}
+
+	// dbusSuggestions is ordered like pathSuggestions.
+	dbusSuggestions = []suggestion{
+		rule(`^org\.freedesktop\.NetworkManager`, "network-manager"),
+		rule(`^org\.freedesktop\.login1\.Manager\.(PowerOff|Reboot|Halt|Suspend)$`, "shutdown"),
+		rule(`^org\.freedesktop\.login1`, "login-session-observe"),
+		rule(`^org\.freedesktop\.UPower`, "upower-observe"),
+		rule(`^org\.bluez`, "bluez"),
+		rule(`^org\.freedesktop\.(Notifications|portal\.)`, "desktop"),
+		rule(`^org\.freedesktop\.ModemManager1`, "modem-manager"),
+		rule(`^org\.freedesktop\.hostname1`, "hostname-control"),
+		rule(`^org\.freedesktop\.timedate1`, "timezone-control", "time-control"),
+		rule(`^org\.freedesktop\.locale1`, "locale-control"),
+		rule(`^org\.freedesktop\.secrets`, "password-manager-service"),
+		rule(`^org\.freedesktop\.Avahi`, "avahi-observe"),
+		rule(`^org\.freedesktop\.Accounts`, "accounts-service"),
+	}
+
+	capabilitySuggestions = map[string][]string{
+		"net_admin":        {"network-control"},
+		"net_raw":          {"network-control", "network-observe"},
+		"net_bind_service": {"network-bind"},
+		"sys_ptrace":       {"system-observe", "process-control"},
+		"sys_time":         {"time-control"},
+		"sys_module":       {"kernel-module-control"},
+		"sys_boot":         {"shutdown"},
+		"sys_nice":         {"process-control"},
+		"sys_resource":     {"process-control"},
+		"kill":             {"process-control"},
+		"syslog":           {"log-observe"},
+		"sys_admin":        {"mount-control"},
+	}
+
+	networkSuggestions = map[string][]string{
+		"inet":      {"network"},
+		"inet6":     {"network"},
+		"netlink":   {"network-observe"},
+		"packet":    {"network-control"},
+		"bluetooth": {"bluez", "bluetooth-control"},
+		"can":       {"can-bus"},
+	}
+
+	syscallSuggestions = map[string][]string{
+		"ptrace":        {"process-control"},
+		"setpriority":   {"process-control"},
+		"getpriority":   {"process-control"},
+		"adjtimex":      {"time-control"},
+		"clock_settime": {"time-control"},
+		"mount":         {"mount-control"},
+		"umount2":       {"mount-control"},
+		"reboot":        {"shutdown"},
+		"init_module":   {"kernel-module-control"},
+		"finit_module":  {"kernel-module-control"},
+		"delete_module": {"kernel-module-control"},
+	}
+)
+
+func matchSuggestions(rules []suggestion, target string) []string {
+	for _, rule := range rules {
+		if rule.rx.MatchString(target) {
+			return rule.interfaces
+		}
+	}
+	return nil
+}
+
+// Suggest returns the interfaces that could grant the access that was
+// denied, or nil if there are no known interfaces for it.
+func Suggest(d *Denial) []string {
+	if d.Kind == KindSeccomp {
+		return syscallSuggestions[d.Target]
+	}
+
+	switch {
+	case d.Operation == "capable":
+		return capabilitySuggestions[d.Target]
+	case strings.HasPrefix(d.Operation, "dbus_"):
+		return matchSuggestions(dbusSuggestions, d.Target)
+	case d.Operation == "bind" || d.Operation == "listen":
+		return []string{"network-bind"}
+	case d.Operation == "create" || d.Operation == "connect":
+		family := strings.Fields(d.Target + " ")[0]
+		if strings.HasSuffix(d.Target, " raw") {
+			return []string{"network-control"}
+		}
+		if ifaces, ok := networkSuggestions[family]; ok {
+			return ifaces
+		}
+	}
+	if strings.HasPrefix(d.Target, "/") {
+		return matchSuggestions(pathSuggestions, d.Target)
+	}
+	return nil
+}
+
+// Summary aggregates identical denials.
+type Summary struct {
+	Denial
+	Count int `json:"count"`
+	// Suggestions holds the interfaces that could grant the access.
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// Aggregate groups identical denials, sorted by how often they happened.
+// The order of denials that happened as often is the one of their first
+// occurrence.
+func Aggregate(denials []*Denial) []*Summary {
+	var summaries []*Summary
+	seen := make(map[Denial]*Summary)
+	for _, d := range denials {
+		if s, ok := seen[*d]; ok {
+			s.Count++
+			continue
+		}
+		s := &Summary{
+			Denial:      *d,
+			Count:       1,
+			Suggestions: Suggest(d),
+		}
+		seen[*d] = s
+		summaries = append(summaries, s)
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Count > summaries[j].Count
+	})
+	return summaries
+}
@@ -0,0 +1,197 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces/denials"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type denialsSuite struct{}
+
+var _ = Suite(&denialsSuite{})
+
+func (s *denialsSuite) TestParse(c *C) {
+	for _, tc := range []struct {
+		line     string
+		expected *denials.Denial
+	}{{
+		line: `audit: type=1400 audit(1760000000.123:45): apparmor="DENIED" operation="open" class="file" profile="snap.foo.app" name="/dev/video0" pid=42 comm="app" requested_mask="wr" denied_mask="wr" fsuid=1000 ouid=0`,
+		expected: &denials.Denial{
+			Kind:      "apparmor",
+			Snap:      "foo",
+			Profile:   "snap.foo.app",
+			Operation: "open",
+			Target:    "/dev/video0",
+			Mask:      "wr",
+		},
+	}, {
+		line: `type=AVC msg=audit(1760000000.123:45): apparmor="DENIED" operation="capable" class="cap" profile="snap.foo_bar.hook.configure" pid=42 comm="app" capability=12 capname="net_admin"`,
+		expected: &denials.Denial{
+			Kind:      "apparmor",
+			Snap:      "foo_bar",
+			Profile:   "snap.foo_bar.hook.configure",
+			Operation: "capable",
+			Target:    "net_admin",
+		},
+	}, {
+		line: `audit: type=1400 audit(1760000000.123:45): apparmor="DENIED" operation="create" class="net" profile="snap.foo.app" pid=42 comm="app" family="inet" sock_type="raw" protocol=1 requested_mask="create" denied_mask="create"`,
+		expected: &denials.Denial{
+			Kind:      "apparmor",
+			Snap:      "foo",
+			Profile:   "snap.foo.app",
+			Operation: "create",
+			Target:    "inet raw",
+			Mask:      "create",
+		},
+	}, {
+		line: `apparmor="DENIED" operation="dbus_method_call" bus="system" path="/org/freedesktop/login1" interface="org.freedesktop.login1.Manager" member="Reboot" mask="send" name="org.freedesktop.login1" pid=42 label="snap.foo.app" peer_pid=1 peer_label="unconfined"`,
+		expected: &denials.Denial{
+			Kind:      "apparmor",
+			Snap:      "foo",
+			Profile:   "snap.foo.app",
+			Operation: "dbus_method_call",
+			Target:    "org.freedesktop.login1.Manager.Reboot",
+			Mask:      "send",
+		},
+	}, {
+		line: `audit: type=1400 audit(1760000000.123:45): apparmor="DENIED" operation="mount" profile="snap-update-ns.foo" name="/usr/share/" pid=42 comm="5"`,
+		expected: &denials.Denial{
+			Kind:      "apparmor",
+			Snap:      "foo",
+			Profile:   "snap-update-ns.foo",
+			Operation: "mount",
+			Target:    "/usr/share/",
+		},
+	}, {
+		line: `audit: type=1326 audit(1760000000.123:45): auid=1000 uid=1000 gid=1000 ses=2 subj=snap.foo.app (enforce) pid=42 comm="app" exe="/snap/foo/x1/bin/app" sig=0 arch=c000003e syscall=165 compat=0 ip=0x7f code=0x50000`,
+		expected: &denials.Denial{
+			Kind:      "seccomp",
+			Snap:      "foo",
+			Profile:   "snap.foo.app",
+			Operation: "syscall",
+			Target:    "mount",
+		},
+	}, {
+		line: fmt.Sprintf(`type=SECCOMP msg=audit(1760000000.123:45): auid=1000 uid=1000 gid=1000 ses=2 subj=unconfined pid=42 comm="app" exe="%s/foo/x1/bin/app" sig=0 arch=c00000b7 syscall=999 compat=0 ip=0x7f code=0x50000`, dirs.StripRootDir(dirs.SnapMountDir)),
+		expected: &denials.Denial{
+			Kind:      "seccomp",
+			Snap:      "foo",
+			Operation: "syscall",
+			Target:    "syscall 999",
+		},
+	}, {
+		// not a snap
+		line: `audit: type=1400 audit(1760000000.123:45): apparmor="DENIED" operation="open" profile="/usr/bin/man" name="/etc/shadow" pid=42 comm="man" requested_mask="r" denied_mask="r"`,
+	}, {
+		line: `audit: type=1326 audit(1760000000.123:45): auid=1000 uid=1000 gid=1000 ses=2 subj=unconfined pid=42 comm="app" exe="/usr/bin/app" sig=0 arch=c000003e syscall=165`,
+	}, {
+		line: `audit: type=1400 audit(1760000000.123:45): apparmor="ALLOWED" operation="open" profile="snap.foo.app" name="/etc/shadow"`,
+	}, {
+		line: `unrelated message`,
+	}} {
+		c.Check(denials.Parse(tc.line), DeepEquals, tc.expected, Commentf("%s", tc.line))
+	}
+}
+
+func (s *denialsSuite) TestParseSyscall(c *C) {
+	for _, tc := range []struct {
+		line     string
+		expected *denials.Syscall
+	}{{
+		line: `audit: type=1326 audit(1760000000.123:45): auid=1000 uid=1000 gid=1000 ses=2 subj=snap.foo.app (enforce) pid=42 comm="app" exe="/snap/foo/x1/bin/app" sig=0 arch=c000003e syscall=257 compat=0 ip=0x7f code=0x7ffc0000`,
+		expected: &denials.Syscall{
+			Snap:    "foo",
+			Profile: "snap.foo.app",
+			Arch:    "c000003e",
+			Number:  257,
+		},
+	}, {
+		line: fmt.Sprintf(`type=SECCOMP msg=audit(1760000000.123:45): auid=1000 uid=1000 gid=1000 ses=2 subj=unconfined pid=42 comm="app" exe="%s/foo/x1/bin/app" sig=0 arch=c00000b7 syscall=56 compat=0 ip=0x7f code=0x7ffc0000`, dirs.StripRootDir(dirs.SnapMountDir)),
+		expected: &denials.Syscall{
+			Snap:   "foo",
+			Arch:   "c00000b7",
+			Number: 56,
+		},
+	}, {
+		// not a snap
+		line: `audit: type=1326 audit(1760000000.123:45): auid=1000 uid=1000 gid=1000 ses=2 subj=unconfined pid=42 comm="app" exe="/usr/bin/app" sig=0 arch=c000003e syscall=165`,
+	}, {
+		// not a system call
+		line: `audit: type=1400 audit(1760000000.123:45): apparmor="DENIED" operation="open" profile="snap.foo.app" name="/dev/video0" pid=42 comm="app" requested_mask="r" denied_mask="r"`,
+	}, {
+		line: `audit: type=1326 audit(1760000000.123:45): subj=snap.foo.app (enforce) pid=42 arch=c000003e syscall=bad`,
+	}} {
+		c.Check(denials.ParseSyscall(tc.line), DeepEquals, tc.expected, Commentf("%s", tc.line))
+	}
+}
+
+func (s *denialsSuite) TestSuggest(c *C) {
+	for _, tc := range []struct {
+		denial   denials.Denial
+		expected []string
+	}{
+		{denials.Denial{Kind: "apparmor", Operation: "open", Target: "/dev/video0"}, []string{"camera"}},
+		{denials.Denial{Kind: "apparmor", Operation: "open", Target: "/home/user/.ssh/id_rsa"}, []string{"ssh-keys"}},
+		{denials.Denial{Kind: "apparmor", Operation: "open", Target: "/home/user/file"}, []string{"home"}},
+		{denials.Denial{Kind: "apparmor", Operation: "open", Target: "/media/usb/file"}, []string{"removable-media"}},
+		{denials.Denial{Kind: "apparmor", Operation: "open", Target: "/proc/42/mountinfo"}, []string{"mount-observe"}},
+		{denials.Denial{Kind: "apparmor", Operation: "open", Target: "/proc/42/net/dev"}, []string{"network-observe"}},
+		{denials.Denial{Kind: "apparmor", Operation: "open", Target: "/proc/42/status"}, []string{"system-observe"}},
+		{denials.Denial{Kind: "apparmor", Operation: "open", Target: "/etc/shadow"}, nil},
+		{denials.Denial{Kind: "apparmor", Operation: "capable", Target: "net_admin"}, []string{"network-control"}},
+		{denials.Denial{Kind: "apparmor", Operation: "capable", Target: "dac_override"}, nil},
+		{denials.Denial{Kind: "apparmor", Operation: "create", Target: "inet6 stream"}, []string{"network"}},
+		{denials.Denial{Kind: "apparmor", Operation: "create", Target: "inet raw"}, []string{"network-control"}},
+		{denials.Denial{Kind: "apparmor", Operation: "create", Target: "bluetooth seqpacket"}, []string{"bluez", "bluetooth-control"}},
+		{denials.Denial{Kind: "apparmor", Operation: "bind", Target: "inet stream"}, []string{"network-bind"}},
+		{denials.Denial{Kind: "apparmor", Operation: "dbus_method_call", Target: "org.freedesktop.login1.Manager.Reboot"}, []string{"shutdown"}},
+		{denials.Denial{Kind: "apparmor", Operation: "dbus_method_call", Target: "org.freedesktop.login1.Manager.ListSessions"}, []string{"login-session-observe"}},
+		{denials.Denial{Kind: "apparmor", Operation: "dbus_signal", Target: "org.example.Foo.Bar"}, nil},
+		{denials.Denial{Kind: "seccomp", Operation: "syscall", Target: "mount"}, []string{"mount-control"}},
+		{denials.Denial{Kind: "seccomp", Operation: "syscall", Target: "syscall 999"}, nil},
+	} {
+		c.Check(denials.Suggest(&tc.denial), DeepEquals, tc.expected, Commentf("%+v", tc.denial))
+	}
+}
+
+func (s *denialsSuite) TestAggregate(c *C) {
+	camera := &denials.Denial{Kind: "apparmor", Snap: "foo", Profile: "snap.foo.app", Operation: "open", Target: "/dev/video0", Mask: "r"}
+	shadow := &denials.Denial{Kind: "apparmor", Snap: "foo", Profile: "snap.foo.app", Operation: "open", Target: "/etc/shadow", Mask: "r"}
+	mount := &denials.Denial{Kind: "seccomp", Snap: "foo", Profile: "snap.foo.app", Operation: "syscall", Target: "mount"}
+
+	cameraCopy := *camera
+	mountCopy := *mount
+	summaries := denials.Aggregate([]*denials.Denial{shadow, camera, mount, &cameraCopy, &mountCopy})
+	c.Check(summaries, DeepEquals, []*denials.Summary{
+		{Denial: *camera, Count: 2, Suggestions: []string{"camera"}},
+		{Denial: *mount, Count: 2, Suggestions: []string{"mount-control"}},
+		{Denial: *shadow, Count: 1},
+	})
+
+	c.Check(denials.Aggregate(nil), HasLen, 0)
+}
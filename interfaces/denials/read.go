@@ -0,0 +1,133 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil"
+)
+
+// auditTimeRx matches the time stamp of audit records.
+var auditTimeRx = regexp.MustCompile(`audit\(([0-9]+)\.[0-9]+:[0-9]+\)`)
+
+func auditTime(line string) (t time.Time, ok bool) {
+	m := auditTimeRx.FindStringSubmatch(line)
+	if m == nil {
+		return time.Time{}, false
+	}
+	secs, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(secs, 0), true
+}
+
+func readLines(r io.Reader, since time.Time, handle func(line string)) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if t, ok := auditTime(line); ok && t.Before(since) {
+			continue
+		}
+		handle(line)
+	}
+	return scanner.Err()
+}
+
+// readLogs passes the lines logged since the given time, or during the
+// current boot if the time is zero, by the kernel and the dbus daemon to
+// handle. Those are read from the journal and from the audit log if auditd
+// is in use.
+func readLogs(since time.Time, handle func(line string)) error {
+	args := []string{"--no-pager", "--output=cat"}
+	if since.IsZero() {
+		args = append(args, "--boot")
+	} else {
+		args = append(args, fmt.Sprintf("--since=@%d", since.Unix()))
+	}
+	args = append(args, "_TRANSPORT=kernel", "+", "_TRANSPORT=audit", "+", "_COMM=dbus-daemon")
+	output, err := exec.Command("journalctl", args...).Output()
+	if err != nil {
+		var stderr []byte
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = exitErr.Stderr
+		}
+		return fmt.Errorf("cannot read journal: %v", osutil.OutputErr(stderr, err))
+	}
+	if err := readLines(bytes.NewReader(output), since, handle); err != nil {
+		return err
+	}
+
+	f, err := os.Open(filepath.Join(dirs.GlobalRootDir, "/var/log/audit/audit.log"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := readLines(f, since, handle); err != nil {
+		return fmt.Errorf("cannot read audit log: %v", err)
+	}
+	return nil
+}
+
+// Read returns the denials of snaps that were logged since the given time,
+// or during the current boot if the time is zero. Denials are read from the
+// journal, which holds the ones logged by the kernel and the dbus daemon,
+// and from the audit log if auditd is in use.
+func Read(since time.Time) ([]*Denial, error) {
+	var denials []*Denial
+	err := readLogs(since, func(line string) {
+		if d := Parse(line); d != nil {
+			denials = append(denials, d)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return denials, nil
+}
+
+// ReadSyscalls returns the system calls of snaps that were logged by seccomp
+// since the given time, or during the current boot if the time is zero.
+func ReadSyscalls(since time.Time) ([]*Syscall, error) {
+	var syscalls []*Syscall
+	err := readLogs(since, func(line string) {
+		if sc := ParseSyscall(line); sc != nil {
+			syscalls = append(syscalls, sc)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return syscalls, nil
+}
@@ -0,0 +1,127 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type readSuite struct{}
+
+var _ = Suite(&readSuite{})
+
+func (s *readSuite) SetUpTest(c *C) {
+	dirs.SetRootDir(c.MkDir())
+}
+
+func (s *readSuite) TearDownTest(c *C) {
+	dirs.SetRootDir("")
+}
+
+const journalOutput = `audit: type=1400 audit(1760000000.123:45): apparmor="DENIED" operation="open" profile="snap.foo.app" name="/dev/video0" pid=42 comm="app" requested_mask="r" denied_mask="r"
+unrelated message
+apparmor="DENIED" operation="dbus_method_call" bus="system" path="/org/bluez" interface="org.bluez.Adapter1" member="StartDiscovery" mask="send" name=":1.4" pid=42 label="snap.bar.app" peer_pid=1 peer_label="unconfined"
+`
+
+func (s *readSuite) TestReadCurrentBoot(c *C) {
+	journalctl := testutil.MockCommand(c, "journalctl", "cat <<'EOF'\n"+journalOutput+"EOF\n")
+	defer journalctl.Restore()
+
+	auditLog := filepath.Join(dirs.GlobalRootDir, "/var/log/audit/audit.log")
+	c.Assert(os.MkdirAll(filepath.Dir(auditLog), 0755), IsNil)
+	err := os.WriteFile(auditLog, []byte(`type=AVC msg=audit(1760000000.123:46): apparmor="DENIED" operation="capable" profile="snap.foo.app" pid=42 comm="app" capability=12 capname="net_admin"
+type=SYSCALL msg=audit(1760000000.123:46): arch=c000003e syscall=54 success=no
+`), 0644)
+	c.Assert(err, IsNil)
+
+	ds, err := denials.Read(time.Time{})
+	c.Assert(err, IsNil)
+	c.Check(ds, DeepEquals, []*denials.Denial{
+		{Kind: "apparmor", Snap: "foo", Profile: "snap.foo.app", Operation: "open", Target: "/dev/video0", Mask: "r"},
+		{Kind: "apparmor", Snap: "bar", Profile: "snap.bar.app", Operation: "dbus_method_call", Target: "org.bluez.Adapter1.StartDiscovery", Mask: "send"},
+		{Kind: "apparmor", Snap: "foo", Profile: "snap.foo.app", Operation: "capable", Target: "net_admin"},
+	})
+	c.Check(journalctl.Calls(), DeepEquals, [][]string{
+		{"journalctl", "--no-pager", "--output=cat", "--boot", "_TRANSPORT=kernel", "+", "_TRANSPORT=audit", "+", "_COMM=dbus-daemon"},
+	})
+}
+
+func (s *readSuite) TestReadSince(c *C) {
+	journalctl := testutil.MockCommand(c, "journalctl", "")
+	defer journalctl.Restore()
+
+	auditLog := filepath.Join(dirs.GlobalRootDir, "/var/log/audit/audit.log")
+	c.Assert(os.MkdirAll(filepath.Dir(auditLog), 0755), IsNil)
+	err := os.WriteFile(auditLog, []byte(`type=AVC msg=audit(1760000000.123:46): apparmor="DENIED" operation="capable" profile="snap.foo.app" pid=42 comm="app" capability=12 capname="net_admin"
+type=AVC msg=audit(1760000100.123:47): apparmor="DENIED" operation="capable" profile="snap.foo.app" pid=42 comm="app" capability=25 capname="sys_time"
+`), 0644)
+	c.Assert(err, IsNil)
+
+	ds, err := denials.Read(time.Unix(1760000050, 0))
+	c.Assert(err, IsNil)
+	c.Check(ds, DeepEquals, []*denials.Denial{
+		{Kind: "apparmor", Snap: "foo", Profile: "snap.foo.app", Operation: "capable", Target: "sys_time"},
+	})
+	c.Check(journalctl.Calls(), DeepEquals, [][]string{
+		{"journalctl", "--no-pager", "--output=cat", "--since=@1760000050", "_TRANSPORT=kernel", "+", "_TRANSPORT=audit", "+", "_COMM=dbus-daemon"},
+	})
+}
+
+func (s *readSuite) TestReadSyscalls(c *C) {
+	journalctl := testutil.MockCommand(c, "journalctl", `cat <<'EOF'
+audit: type=1326 audit(1760000100.123:45): auid=1000 uid=1000 gid=1000 ses=2 subj=snap.foo.app (enforce) pid=42 comm="app" exe="/snap/foo/x1/bin/app" sig=0 arch=c000003e syscall=257 compat=0 ip=0x7f code=0x7ffc0000
+audit: type=1400 audit(1760000100.123:46): apparmor="DENIED" operation="open" profile="snap.foo.app" name="/dev/video0" pid=42 comm="app" requested_mask="r" denied_mask="r"
+EOF
+`)
+	defer journalctl.Restore()
+
+	auditLog := filepath.Join(dirs.GlobalRootDir, "/var/log/audit/audit.log")
+	c.Assert(os.MkdirAll(filepath.Dir(auditLog), 0755), IsNil)
+	err := os.WriteFile(auditLog, []byte(`type=SECCOMP msg=audit(1760000000.123:40): subj=snap.foo.app (enforce) pid=42 comm="app" exe="/snap/foo/x1/bin/app" sig=0 arch=c000003e syscall=0 compat=0 ip=0x7f code=0x7ffc0000
+type=SECCOMP msg=audit(1760000100.123:47): subj=snap.bar.app (enforce) pid=43 comm="app" exe="/snap/bar/x1/bin/app" sig=0 arch=c000003e syscall=1 compat=0 ip=0x7f code=0x7ffc0000
+`), 0644)
+	c.Assert(err, IsNil)
+
+	syscalls, err := denials.ReadSyscalls(time.Unix(1760000050, 0))
+	c.Assert(err, IsNil)
+	c.Check(syscalls, DeepEquals, []*denials.Syscall{
+		{Snap: "foo", Profile: "snap.foo.app", Arch: "c000003e", Number: 257},
+		{Snap: "bar", Profile: "snap.bar.app", Arch: "c000003e", Number: 1},
+	})
+	c.Check(journalctl.Calls(), DeepEquals, [][]string{
+		{"journalctl", "--no-pager", "--output=cat", "--since=@1760000050", "_TRANSPORT=kernel", "+", "_TRANSPORT=audit", "+", "_COMM=dbus-daemon"},
+	})
+}
+
+func (s *readSuite) TestReadJournalError(c *C) {
+	journalctl := testutil.MockCommand(c, "journalctl", "echo boom >&2; exit 1")
+	defer journalctl.Restore()
+
+	_, err := denials.Read(time.Time{})
+	c.Check(err, ErrorMatches, "cannot read journal: boom")
+}
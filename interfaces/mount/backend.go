@@ -79,6 +79,10 @@ func (b *Backend) Setup(snapInfo *snap.Info, confinement interfaces.ConfinementO
 	if _, _, err := osutil.EnsureDirState(dir, glob, content); err != nil {
 		return fmt.Errorf("cannot synchronize mount configuration files for snap %q: %s", snapName, err)
 	}
+	if confinement.DeferNamespaceUpdate {
+		logger.Debugf("deferring update of mount namespace of snap %q", snapName)
+		return nil
+	}
 	if err := UpdateSnapNamespace(snapName); err != nil {
 		// try to discard the mount namespace but only if there aren't enduring daemons in the snap
 		for _, app := range snapInfo.Apps {
@@ -303,6 +303,30 @@ func (s *backendSuite) TestSetupUpdates(c *C) {
 	c.Check(got, testutil.DeepUnsortedMatches, expected)
 }
 
+func (s *backendSuite) TestSetupDeferNamespaceUpdate(c *C) {
+	fsEntry := osutil.MountEntry{Name: "/src-1", Dir: "/dst-1", Type: "none", Options: []string{"bind", "ro"}}
+	s.Iface.MountPermanentPlugCallback = func(spec *mount.Specification, plug *snap.PlugInfo) error {
+		return spec.AddMountEntry(fsEntry)
+	}
+
+	cmd := testutil.MockCommand(c, "snap-update-ns", "")
+	defer cmd.Restore()
+	dirs.DistroLibExecDir = cmd.BinDir()
+
+	snapInfo := s.InstallSnap(c, interfaces.ConfinementOptions{}, "", mockSnapYaml, 0)
+
+	// ensure .mnt file
+	mntFile := filepath.Join(dirs.SnapRunNsDir, "snap-name.mnt")
+	c.Assert(os.WriteFile(mntFile, []byte(""), 0644), IsNil)
+
+	s.UpdateSnap(c, snapInfo, interfaces.ConfinementOptions{DeferNamespaceUpdate: true}, mockSnapYaml, 1)
+
+	// the profile was written but snap-update-ns was not invoked
+	fn := filepath.Join(dirs.SnapMountPolicyDir, "snap.snap-name.fstab")
+	c.Check(fn, testutil.FileEquals, fsEntry.String()+"\n")
+	c.Check(cmd.Calls(), HasLen, 0)
+}
+
 func (s *backendSuite) TestSetupEndureUpdatesError(c *C) {
 	fsEntry1 := osutil.MountEntry{Name: "/src-1", Dir: "/dst-1", Type: "none", Options: []string{"bind", "ro"}, DumpFrequency: 0, CheckPassNumber: 0}
 	fsEntry2 := osutil.MountEntry{Name: "/src-2", Dir: "/dst-2", Type: "none", Options: []string{"bind", "ro"}, DumpFrequency: 0, CheckPassNumber: 0}
@@ -20,6 +20,7 @@
 package mount
 
 import (
+	"bytes"
 	"fmt"
 	"os/exec"
 	"path/filepath"
@@ -67,3 +68,28 @@ func UpdateSnapNamespace(snapName string) error {
 	}
 	return nil
 }
+
+// NamespaceMountInfo returns the mount table as seen from inside the
+// preserved mount namespace of a given snap. If the snap has no preserved
+// mount namespace then nil is returned.
+func NamespaceMountInfo(snapName string) ([]*osutil.MountInfoEntry, error) {
+	mntFile := mountNsPath(snapName)
+	if !osutil.FileExists(mntFile) {
+		return nil, nil
+	}
+	// Only the mount namespace is entered and nothing from the snap is
+	// executed there, the table is read by the host cat.
+	cmd := exec.Command("nsenter", "--mount="+mntFile, "cat", "/proc/self/mountinfo")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			output = exitErr.Stderr
+		}
+		return nil, fmt.Errorf("cannot inspect preserved namespace of snap %q: %s", snapName, osutil.OutputErr(output, err))
+	}
+	entries, err := osutil.ReadMountInfo(bytes.NewReader(output))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse mount table of snap %q: %v", snapName, err)
+	}
+	return entries, nil
+}
@@ -141,3 +141,49 @@ func (s *nsSuite) TestUpdateNamespaceMnt(c *C) {
 		}
 	}
 }
+
+func (s *nsSuite) TestNamespaceMountInfo(c *C) {
+	cmd := testutil.MockCommand(c, "nsenter", `
+cat <<'MOUNTINFO'
+2 1 0:2 / / rw,relatime - squashfs /dev/loop1 ro
+3 2 0:3 /usr/share/foo /snap/foo/x1/share rw - ext4 /dev/sda1 rw
+MOUNTINFO
+`)
+	defer cmd.Restore()
+
+	c.Assert(os.MkdirAll(dirs.SnapRunNsDir, 0755), IsNil)
+	mntFile := filepath.Join(dirs.SnapRunNsDir, "snap-name.mnt")
+	c.Assert(os.WriteFile(mntFile, nil, 0644), IsNil)
+
+	entries, err := mount.NamespaceMountInfo("snap-name")
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 2)
+	c.Check(entries[0].MountDir, Equals, "/")
+	c.Check(entries[0].FsType, Equals, "squashfs")
+	c.Check(entries[1].Root, Equals, "/usr/share/foo")
+	c.Check(entries[1].MountDir, Equals, "/snap/foo/x1/share")
+	c.Check(cmd.Calls(), DeepEquals, [][]string{
+		{"nsenter", "--mount=" + mntFile, "cat", "/proc/self/mountinfo"},
+	})
+}
+
+func (s *nsSuite) TestNamespaceMountInfoNoNamespace(c *C) {
+	cmd := testutil.MockCommand(c, "nsenter", "")
+	defer cmd.Restore()
+
+	entries, err := mount.NamespaceMountInfo("snap-name")
+	c.Assert(err, IsNil)
+	c.Check(entries, IsNil)
+	c.Check(cmd.Calls(), HasLen, 0)
+}
+
+func (s *nsSuite) TestNamespaceMountInfoError(c *C) {
+	cmd := testutil.MockCommand(c, "nsenter", "echo 'cannot open namespace' >&2; exit 1")
+	defer cmd.Restore()
+
+	c.Assert(os.MkdirAll(dirs.SnapRunNsDir, 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(dirs.SnapRunNsDir, "snap-name.mnt"), nil, 0644), IsNil)
+
+	_, err := mount.NamespaceMountInfo("snap-name")
+	c.Check(err, ErrorMatches, `cannot inspect preserved namespace of snap "snap-name": cannot open namespace`)
+}
@@ -87,6 +87,9 @@ func mountEntryFromLayout(layout *snap.Layout) osutil.MountEntry {
 	if layout.Type == "tmpfs" {
 		entry.Type = "tmpfs"
 		entry.Name = "tmpfs"
+		if layout.Size != 0 {
+			entry.Options = append(entry.Options, fmt.Sprintf("size=%d", layout.Size))
+		}
 	}
 
 	if layout.Symlink != "" {
@@ -155,6 +155,7 @@ layout:
   /lib/mytmp:
     type: tmpfs
     mode: 1777
+    size: 16MB
   /lib/mylink:
     symlink: $SNAP/link/target
   /etc/foo.conf:
@@ -168,7 +169,7 @@ func (s *specSuite) TestMountEntryFromLayout(c *C) {
 		// Layout result is sorted by mount path.
 		{Dir: "/etc/foo.conf", Name: "/snap/vanguard/42/foo.conf", Options: []string{"bind", "rw", "x-snapd.kind=file", "x-snapd.origin=layout"}},
 		{Dir: "/lib/mylink", Options: []string{"x-snapd.kind=symlink", "x-snapd.symlink=/snap/vanguard/42/link/target", "x-snapd.origin=layout"}},
-		{Dir: "/lib/mytmp", Name: "tmpfs", Type: "tmpfs", Options: []string{"x-snapd.mode=01777", "x-snapd.origin=layout"}},
+		{Dir: "/lib/mytmp", Name: "tmpfs", Type: "tmpfs", Options: []string{"size=16000000", "x-snapd.mode=01777", "x-snapd.origin=layout"}},
 		{Dir: "/usr", Name: "/snap/vanguard/42/usr", Options: []string{"rbind", "rw", "x-snapd.origin=layout"}},
 	})
 }
@@ -200,7 +201,7 @@ func (s *specSuite) TestParallelInstanceMountEntryFromLayout(c *C) {
 		// Layout result is sorted by mount path.
 		{Dir: "/etc/foo.conf", Name: "/snap/vanguard/42/foo.conf", Options: []string{"bind", "rw", "x-snapd.kind=file", "x-snapd.origin=layout"}},
 		{Dir: "/lib/mylink", Options: []string{"x-snapd.kind=symlink", "x-snapd.symlink=/snap/vanguard/42/link/target", "x-snapd.origin=layout"}},
-		{Dir: "/lib/mytmp", Name: "tmpfs", Type: "tmpfs", Options: []string{"x-snapd.mode=01777", "x-snapd.origin=layout"}},
+		{Dir: "/lib/mytmp", Name: "tmpfs", Type: "tmpfs", Options: []string{"size=16000000", "x-snapd.mode=01777", "x-snapd.origin=layout"}},
 		{Dir: "/usr", Name: "/snap/vanguard/42/usr", Options: []string{"rbind", "rw", "x-snapd.origin=layout"}},
 	})
 }
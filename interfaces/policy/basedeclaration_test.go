@@ -229,9 +229,13 @@ func (s *baseDeclSuite) TestAutoConnectPlugSlot(c *C) {
 	snowflakes := map[string]bool{
 		"classic-support": true,
 		"content":         true,
-		"cups-control":    true,
-		"home":            true,
-		"lxd-support":     true,
+		// cups needs the slot to provide a mediated socket
+		"cups":         true,
+		"cups-control": true,
+		// gpu-driver checks the versions of the driver stack
+		"gpu-driver":  true,
+		"home":        true,
+		"lxd-support": true,
 		// netlink-driver needs the family-name attributes to match
 		"netlink-driver": true,
 	}
@@ -386,6 +390,43 @@ plugs:
 	c.Check(err, NotNil)
 }
 
+func (s *baseDeclSuite) TestAutoConnectionGPUDriver(c *C) {
+	// the api of the driver stack must match
+	cand := s.connectCand(c, "gpu-driver", `
+name: slot-snap
+version: 0
+type: kernel
+slots:
+  gpu-driver:
+    api: nvidia
+`, `
+name: plug-snap
+version: 0
+plugs:
+  gpu-driver:
+    api: nvidia
+`)
+	_, err := cand.CheckAutoConnect()
+	c.Check(err, IsNil)
+
+	cand = s.connectCand(c, "gpu-driver", `
+name: slot-snap
+version: 0
+type: kernel
+slots:
+  gpu-driver:
+    api: nvidia
+`, `
+name: plug-snap
+version: 0
+plugs:
+  gpu-driver:
+    api: rocm
+`)
+	_, err = cand.CheckAutoConnect()
+	c.Check(err, ErrorMatches, `auto-connection not allowed by slot rule of interface "gpu-driver"`)
+}
+
 func (s *baseDeclSuite) TestAutoConnectionSharedMemory(c *C) {
 	// random snaps cannot connect with shared-memory
 	// (Sanitize* will now also block this)
@@ -812,6 +853,7 @@ var (
 		"fwupd":                     {"app", "core"},
 		"gpio":                      {"core", "gadget"},
 		"gpio-control":              {"core"},
+		"gpu-driver":                {"gadget", "kernel"},
 		"greengrass-support":        {"core"},
 		"hidraw":                    {"core", "gadget"},
 		"i2c":                       {"core", "gadget"},
@@ -1072,6 +1114,17 @@ func (s *baseDeclSuite) TestPlugInstallation(c *C) {
 	}
 }
 
+func (s *baseDeclSuite) TestIsSuperPrivileged(c *C) {
+	for _, iface := range []string{"snapd-control", "docker-support", "kernel-module-control", "system-files", "personal-files"} {
+		c.Check(policy.IsSuperPrivileged(s.baseDecl, iface), Equals, true, Commentf("%s", iface))
+	}
+	for _, iface := range []string{"network", "home", "removable-media", "content"} {
+		c.Check(policy.IsSuperPrivileged(s.baseDecl, iface), Equals, false, Commentf("%s", iface))
+	}
+	// unknown interfaces have no rules
+	c.Check(policy.IsSuperPrivileged(s.baseDecl, "unknown"), Equals, false)
+}
+
 func (s *baseDeclSuite) TestConnection(c *C) {
 	all := builtin.Interfaces()
 
@@ -1083,6 +1136,7 @@ func (s *baseDeclSuite) TestConnection(c *C) {
 		"custom-device":             true,
 		"docker":                    true,
 		"fwupd":                     true,
+		"gpu-driver":                true,
 		"location-control":          true,
 		"location-observe":          true,
 		"lxd":                       true,
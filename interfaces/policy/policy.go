@@ -323,3 +323,21 @@ func (ic *InstallCandidateMinimalCheck) Check() error {
 
 	return nil
 }
+
+// IsSuperPrivileged returns whether plugs of the given interface are
+// super-privileged according to the base declaration, that is an app
+// snap cannot be installed with them unless its snap-declaration
+// grants them.
+func IsSuperPrivileged(baseDecl *asserts.BaseDeclaration, iface string) bool {
+	rule := baseDecl.PlugRule(iface)
+	if rule == nil {
+		return false
+	}
+	plug := &snap.PlugInfo{
+		Snap:      &snap.Info{SnapType: snap.TypeApp},
+		Name:      iface,
+		Interface: iface,
+	}
+	ic := &InstallCandidate{BaseDeclaration: baseDecl}
+	return ic.checkPlugRule(plug, rule, false) != nil
+}
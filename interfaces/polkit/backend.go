@@ -25,6 +25,10 @@
 //
 // The policy files are XML files whose format is described here:
 // https://www.freedesktop.org/software/polkit/docs/latest/polkit.8.html#polkit-declaring-actions
+//
+// Snapd also installs polkitd authorization rules shipped by snaps,
+// whose format is described here:
+// https://www.freedesktop.org/software/polkit/docs/latest/polkit.8.html#polkit-rules
 package polkit
 
 import (
@@ -42,7 +46,14 @@ func polkitPolicyName(snapName, nameSuffix string) string {
 	return snap.ScopedSecurityTag(snapName, "interface", nameSuffix) + ".policy"
 }
 
-// Backend is responsible for maintaining polkitd policy files.
+func polkitRuleName(snapName, nameSuffix string) string {
+	// polkitd evaluates rules in lexical order of the file names and
+	// the first rule returning a result wins, use a prefix that lets
+	// rules added by the system administrator take precedence.
+	return "70-" + snap.ScopedSecurityTag(snapName, "interface", nameSuffix) + ".rules"
+}
+
+// Backend is responsible for maintaining polkitd policy and rule files.
 type Backend struct{}
 
 // Initialize does nothing.
@@ -55,7 +66,7 @@ func (b *Backend) Name() interfaces.SecuritySystem {
 	return interfaces.SecurityPolkit
 }
 
-// Setup installs the polkit policy files specific to a given snap.
+// Setup installs the polkit policy and rule files specific to a given snap.
 //
 // Polkit has no concept of a complain mode so confinment type is ignored.
 func (b *Backend) Setup(snapInfo *snap.Info, opts interfaces.ConfinementOptions, repo *interfaces.Repository, tm timings.Measurer) error {
@@ -67,25 +78,29 @@ func (b *Backend) Setup(snapInfo *snap.Info, opts interfaces.ConfinementOptions,
 	}
 
 	// Get the files that this snap should have
-	glob := polkitPolicyName(snapName, "*")
-	content := deriveContent(spec.(*Specification), snapInfo)
-	dir := dirs.SnapPolkitPolicyDir
+	policies, rules := deriveContent(spec.(*Specification), snapInfo)
+	if err := ensureDirState(dirs.SnapPolkitPolicyDir, polkitPolicyName(snapName, "*"), policies); err != nil {
+		return fmt.Errorf("cannot synchronize polkit policy files for snap %q: %s", snapName, err)
+	}
+	if err := ensureDirState(dirs.SnapPolkitRuleDir, polkitRuleName(snapName, "*"), rules); err != nil {
+		return fmt.Errorf("cannot synchronize polkit rule files for snap %q: %s", snapName, err)
+	}
+	return nil
+}
 
+func ensureDirState(dir, glob string, content map[string]osutil.FileState) error {
 	// If we do not have any content to write, there is no point
 	// ensuring the directory exists.
 	if content != nil {
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("cannot create directory for polkit policy files %q: %s", dir, err)
+			return fmt.Errorf("cannot create directory %q: %s", dir, err)
 		}
 	}
-	_, _, err = osutil.EnsureDirState(dir, glob, content)
-	if err != nil {
-		return fmt.Errorf("cannot synchronize polkit policy files for snap %q: %s", snapName, err)
-	}
-	return nil
+	_, _, err := osutil.EnsureDirState(dir, glob, content)
+	return err
 }
 
-// Remove removes polkit policy files of a given snap.
+// Remove removes polkit policy and rule files of a given snap.
 //
 // This method should be called after removing a snap.
 func (b *Backend) Remove(snapName string) error {
@@ -94,25 +109,39 @@ func (b *Backend) Remove(snapName string) error {
 	if err != nil {
 		return fmt.Errorf("cannot synchronize polkit files for snap %q: %s", snapName, err)
 	}
+	glob = polkitRuleName(snapName, "*")
+	_, _, err = osutil.EnsureDirState(dirs.SnapPolkitRuleDir, glob, nil)
+	if err != nil {
+		return fmt.Errorf("cannot synchronize polkit files for snap %q: %s", snapName, err)
+	}
 	return nil
 }
 
 // deriveContent combines security snippets collected from all the interfaces
-// affecting a given snap into a content map applicable to EnsureDirState.
-func deriveContent(spec *Specification, snapInfo *snap.Info) map[string]osutil.FileState {
-	policies := spec.Policies()
-	if len(policies) == 0 {
-		return nil
+// affecting a given snap into content maps applicable to EnsureDirState, one
+// for policy files and one for rule files.
+func deriveContent(spec *Specification, snapInfo *snap.Info) (policies, rules map[string]osutil.FileState) {
+	if specPolicies := spec.Policies(); len(specPolicies) > 0 {
+		policies = make(map[string]osutil.FileState, len(specPolicies))
+		for nameSuffix, policyContent := range specPolicies {
+			filename := polkitPolicyName(snapInfo.InstanceName(), nameSuffix)
+			policies[filename] = &osutil.MemoryFileState{
+				Content: policyContent,
+				Mode:    0644,
+			}
+		}
 	}
-	content := make(map[string]osutil.FileState, len(policies)+1)
-	for nameSuffix, policyContent := range policies {
-		filename := polkitPolicyName(snapInfo.InstanceName(), nameSuffix)
-		content[filename] = &osutil.MemoryFileState{
-			Content: policyContent,
-			Mode:    0644,
+	if specRules := spec.Rules(); len(specRules) > 0 {
+		rules = make(map[string]osutil.FileState, len(specRules))
+		for nameSuffix, ruleContent := range specRules {
+			filename := polkitRuleName(snapInfo.InstanceName(), nameSuffix)
+			rules[filename] = &osutil.MemoryFileState{
+				Content: ruleContent,
+				Mode:    0644,
+			}
 		}
 	}
-	return content
+	return policies, rules
 }
 
 func (b *Backend) NewSpecification() interfaces.Specification {
@@ -123,6 +123,37 @@ func (s *backendSuite) TestUnexpectedPolicyFilesremoved(c *C) {
 	}
 }
 
+func (s *backendSuite) TestInstallingSnapWritesRuleFiles(c *C) {
+	s.Iface.PolkitPermanentSlotCallback = func(spec *polkit.Specification, slot *snap.SlotInfo) error {
+		return spec.AddRule("foo", polkit.Rule("polkit.addRule(function(action, subject) {});"))
+	}
+	for _, opts := range testedConfinementOpts {
+		snapInfo := s.InstallSnap(c, opts, "", ifacetest.SambaYamlV1, 0)
+		rule := filepath.Join(dirs.SnapPolkitRuleDir, "70-snap.samba.interface.foo.rules")
+		c.Check(rule, testutil.FileEquals, "polkit.addRule(function(action, subject) {});")
+		// no policy files were written
+		c.Check(dirs.SnapPolkitPolicyDir, testutil.FileAbsent)
+		s.RemoveSnap(c, snapInfo)
+		c.Check(rule, testutil.FileAbsent)
+	}
+}
+
+func (s *backendSuite) TestUpdatingSnapRemovesStaleRuleFiles(c *C) {
+	s.Iface.PolkitPermanentSlotCallback = func(spec *polkit.Specification, slot *snap.SlotInfo) error {
+		return spec.AddRule("foo", polkit.Rule("polkit.addRule(function(action, subject) {});"))
+	}
+	snapInfo := s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 0)
+	rule := filepath.Join(dirs.SnapPolkitRuleDir, "70-snap.samba.interface.foo.rules")
+	c.Check(rule, testutil.FilePresent)
+
+	// the rule is gone once the interface no longer provides it, as
+	// happens on disconnect
+	s.Iface.PolkitPermanentSlotCallback = nil
+	snapInfo = s.UpdateSnap(c, snapInfo, interfaces.ConfinementOptions{}, ifacetest.SambaYamlV1, 0)
+	c.Check(rule, testutil.FileAbsent)
+	s.RemoveSnap(c, snapInfo)
+}
+
 func (s *backendSuite) TestSandboxFeatures(c *C) {
 	c.Assert(s.Backend.SandboxFeatures(), HasLen, 0)
 }
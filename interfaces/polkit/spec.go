@@ -29,9 +29,12 @@ import (
 
 type Policy []byte
 
-// Specification keeps all the polkit policies.
+type Rule []byte
+
+// Specification keeps all the polkit policies and rules.
 type Specification struct {
 	policyFiles map[string]Policy
+	ruleFiles   map[string]Rule
 }
 
 // AddPolicy adds a polkit policy file to install.
@@ -59,6 +62,31 @@ func (spec *Specification) Policies() map[string]Policy {
 	return result
 }
 
+// AddRule adds a polkit rule file to install.
+func (spec *Specification) AddRule(nameSuffix string, content Rule) error {
+	if old, ok := spec.ruleFiles[nameSuffix]; ok && !bytes.Equal(old, content) {
+		return fmt.Errorf("internal error: polkit rule content for %q re-defined with different content", nameSuffix)
+	}
+	if spec.ruleFiles == nil {
+		spec.ruleFiles = make(map[string]Rule)
+	}
+	spec.ruleFiles[nameSuffix] = content
+	return nil
+}
+
+// Rules returns a map of polkit rules added to the Specification.
+func (spec *Specification) Rules() map[string]Rule {
+	if spec.ruleFiles == nil {
+		return nil
+	}
+	result := make(map[string]Rule, len(spec.ruleFiles))
+	for k, v := range spec.ruleFiles {
+		result[k] = make(Rule, len(v))
+		copy(result[k], v)
+	}
+	return result
+}
+
 // Implementation of methods required by interfaces.Specification
 
 // AddConnectedPlug records polkit-specific side-effects of having a connected plug.
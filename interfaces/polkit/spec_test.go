@@ -97,3 +97,14 @@ func (s *specSuite) TestSpecificationIface(c *C) {
 		"permanent-slot": polkit.Policy("policy-permanent-slot"),
 	})
 }
+
+func (s *specSuite) TestAddRule(c *C) {
+	c.Assert(s.spec.Rules(), IsNil)
+	c.Assert(s.spec.AddRule("foo", polkit.Rule("rule-foo")), IsNil)
+	// adding the same content again is fine
+	c.Assert(s.spec.AddRule("foo", polkit.Rule("rule-foo")), IsNil)
+	c.Check(s.spec.AddRule("foo", polkit.Rule("rule-bar")), ErrorMatches, `internal error: polkit rule content for "foo" re-defined with different content`)
+	c.Check(s.spec.Rules(), DeepEquals, map[string]polkit.Rule{
+		"foo": polkit.Rule("rule-foo"),
+	})
+}
@@ -0,0 +1,151 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package profilecache implements a store of compiled security profiles,
+// keyed by the content of the profile source and the features of the system
+// the profile was compiled for. It allows reusing the compiled form of a
+// profile which was seen before, e.g. when a snap is removed and installed
+// again, or reverted to a previous revision, instead of compiling it again.
+package profilecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/snapcore/snapd/osutil"
+)
+
+// Key returns the key identifying a compiled profile, derived from the
+// given parts, typically the features of the system and the content of the
+// profile source.
+func Key(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		// length prefix each part so that the key is unambiguous
+		fmt.Fprintf(h, "%d:", len(part))
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Store is a directory holding compiled profiles, keyed by the key returned
+// by Key. The total size of the store is bounded, least recently used
+// entries are removed first when the bound is exceeded.
+type Store struct {
+	dir     string
+	maxSize int64
+
+	// addLock serializes adding entries to the store
+	addLock sync.Mutex
+}
+
+// New returns a store of compiled profiles in the given directory, holding
+// at most maxSize bytes of compiled profiles.
+func New(dir string, maxSize int64) *Store {
+	return &Store{dir: dir, maxSize: maxSize}
+}
+
+func (s *Store) entryPath(key string) string {
+	return filepath.Join(s.dir, key)
+}
+
+// Has returns whether a compiled profile is stored for the given key.
+func (s *Store) Has(key string) bool {
+	return osutil.FileExists(s.entryPath(key))
+}
+
+// Restore writes the compiled profile stored for the given key to target.
+// The modification time of target is the current time. Restore returns false
+// if there is no compiled profile stored for the key.
+func (s *Store) Restore(key, target string) (bool, error) {
+	entry := s.entryPath(key)
+	if !osutil.FileExists(entry) {
+		return false, nil
+	}
+	if err := osutil.AtomicWriteFileCopy(target, entry, 0); err != nil {
+		return false, err
+	}
+	now := time.Now()
+	if err := os.Chtimes(target, now, now); err != nil {
+		return false, err
+	}
+	// mark the entry as recently used
+	os.Chtimes(entry, now, now)
+	return true, nil
+}
+
+// Add stores a copy of the compiled profile at source under the given key,
+// removing least recently used entries if the size of the store is exceeded.
+func (s *Store) Add(key, source string) error {
+	s.addLock.Lock()
+	defer s.addLock.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	entry := s.entryPath(key)
+	if err := osutil.AtomicWriteFileCopy(entry, source, 0); err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := os.Chtimes(entry, now, now); err != nil {
+		return err
+	}
+	return s.prune()
+}
+
+func (s *Store) prune() error {
+	d, err := os.Open(s.dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	infos, err := d.Readdir(-1)
+	if err != nil {
+		return err
+	}
+	var size int64
+	entries := make([]os.FileInfo, 0, len(infos))
+	for _, fi := range infos {
+		if !fi.Mode().IsRegular() {
+			continue
+		}
+		entries = append(entries, fi)
+		size += fi.Size()
+	}
+	// most recently used first
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ModTime().After(entries[j].ModTime())
+	})
+	for len(entries) > 0 && size > s.maxSize {
+		oldest := entries[len(entries)-1]
+		if err := os.Remove(filepath.Join(s.dir, oldest.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		size -= oldest.Size()
+		entries = entries[:len(entries)-1]
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package profilecache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces/profilecache"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type profileCacheSuite struct {
+	dir string
+}
+
+var _ = Suite(&profileCacheSuite{})
+
+func (s *profileCacheSuite) SetUpTest(c *C) {
+	s.dir = c.MkDir()
+}
+
+func (s *profileCacheSuite) TestKey(c *C) {
+	k := profilecache.Key([]byte("features"), []byte("profile"))
+	c.Check(k, HasLen, 64)
+	c.Check(profilecache.Key([]byte("features"), []byte("profile")), Equals, k)
+	c.Check(profilecache.Key([]byte("features"), []byte("other profile")), Not(Equals), k)
+	// parts are not simply concatenated
+	c.Check(profilecache.Key([]byte("feature"), []byte("sprofile")), Not(Equals), k)
+}
+
+func (s *profileCacheSuite) TestAddRestore(c *C) {
+	store := profilecache.New(filepath.Join(s.dir, "cache"), 1024)
+	key := profilecache.Key([]byte("profile"))
+
+	target := filepath.Join(s.dir, "profile.bin")
+	ok, err := store.Restore(key, target)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+	c.Check(store.Has(key), Equals, false)
+	c.Check(target, testutil.FileAbsent)
+
+	source := filepath.Join(s.dir, "compiled")
+	c.Assert(os.WriteFile(source, []byte("compiled profile"), 0644), IsNil)
+	c.Assert(store.Add(key, source), IsNil)
+	c.Check(store.Has(key), Equals, true)
+
+	// restored file is fresh
+	old := time.Now().Add(-time.Hour)
+	c.Assert(os.Chtimes(source, old, old), IsNil)
+	ok, err = store.Restore(key, target)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+	c.Check(target, testutil.FileEquals, "compiled profile")
+	fi, err := os.Stat(target)
+	c.Assert(err, IsNil)
+	c.Check(fi.ModTime().After(old.Add(time.Minute)), Equals, true)
+}
+
+func (s *profileCacheSuite) TestAddPrunesLeastRecentlyUsed(c *C) {
+	cacheDir := filepath.Join(s.dir, "cache")
+	store := profilecache.New(cacheDir, 25)
+
+	source := filepath.Join(s.dir, "compiled")
+	c.Assert(os.WriteFile(source, []byte("0123456789"), 0644), IsNil)
+
+	keys := []string{"one", "two", "three"}
+	for i, key := range keys[:2] {
+		c.Assert(store.Add(key, source), IsNil)
+		// make the order of use deterministic
+		t := time.Now().Add(time.Duration(i-10) * time.Minute)
+		c.Assert(os.Chtimes(filepath.Join(cacheDir, key), t, t), IsNil)
+	}
+	// using the first entry makes it the most recently used one
+	ok, err := store.Restore("one", filepath.Join(s.dir, "target"))
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+
+	c.Assert(store.Add("three", source), IsNil)
+	c.Check(store.Has("one"), Equals, true)
+	c.Check(store.Has("two"), Equals, false)
+	c.Check(store.Has("three"), Equals, true)
+}
+
+func (s *profileCacheSuite) TestAddMissingSource(c *C) {
+	store := profilecache.New(filepath.Join(s.dir, "cache"), 1024)
+	err := store.Add("key", filepath.Join(s.dir, "missing"))
+	c.Check(err, ErrorMatches, "unable to open source file .*/missing: .*")
+	c.Check(store.Has("key"), Equals, false)
+}
@@ -26,7 +26,9 @@
 // kernel for the duration of the execution of the process.
 //
 // There is no binary cache for seccomp, each time the launcher starts an
-// application the profile is parsed and re-compiled.
+// application the profile is parsed and re-compiled. Compiled profiles are
+// however kept by snapd keyed by the content of their source, so that
+// identical profiles, e.g. of a reinstalled snap, are not compiled again.
 //
 // The actual profiles are stored in /var/lib/snappy/seccomp/bpf/*.{src,bin}.
 // This directory is hard-coded in snap-confine.
@@ -44,6 +46,8 @@ import (
 	"github.com/snapcore/snapd/arch"
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/profilecache"
+	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/release"
 	"github.com/snapcore/snapd/sandbox/apparmor"
@@ -63,6 +67,9 @@ var (
 
 	snapSeccompVersionInfo = snapSeccompVersionInfoImpl
 	seccompCompilerLookup  = snapdtool.InternalToolPath
+
+	runtimeNumCPU           = runtime.NumCPU
+	osutilTotalUsableMemory = osutil.TotalUsableMemory
 )
 
 func snapSeccompVersionInfoImpl(c Compiler) (seccomp.VersionInfo, error) {
@@ -152,73 +159,128 @@ func bpfBinPath(srcName string) string {
 	return filepath.Join(dirs.SnapSeccompDir, strings.TrimSuffix(srcName, ".src")+".bin")
 }
 
-func parallelCompile(compiler Compiler, profiles []string) error {
-	if len(profiles) == 0 {
-		// no profiles, nothing to do
-		return nil
-	}
+// compileJobMemory is a rough estimate of the memory needed by a single
+// snap-seccomp compilation job.
+const compileJobMemory = 64 * 1024 * 1024
 
-	profilesQueue := make(chan string, len(profiles))
-	numWorkers := runtime.NumCPU()
+// numCompileWorkers returns the number of workers compiling the given number
+// of profiles in parallel. The workers are bounded by the number of CPUs,
+// sparing one, and by the memory of the system.
+func numCompileWorkers(profiles int) int {
+	numWorkers := runtimeNumCPU()
 	if numWorkers >= 2 {
 		numWorkers -= 1
 	}
-	if numWorkers > len(profiles) {
-		numWorkers = len(profiles)
+	if mem, err := osutilTotalUsableMemory(); err == nil {
+		if memWorkers := int(mem / compileJobMemory); memWorkers < numWorkers {
+			numWorkers = memWorkers
+		}
+	}
+	if numWorkers > profiles {
+		numWorkers = profiles
+	}
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+	return numWorkers
+}
+
+// compiledCacheSize is the maximum size of the cache of compiled profiles.
+const compiledCacheSize = 16 * 1024 * 1024
+
+// compileProfile compiles a single profile. If a profile with identical
+// source was compiled before, its compiled form is taken from the cache
+// instead. As the source of the profile carries the version information of
+// snap-seccomp, which includes the seccomp features of the system, the
+// content of the source is enough to identify the compiled profile.
+func compileProfile(compiler Compiler, cache *profilecache.Store, profile string) error {
+	in := bpfSrcPath(profile)
+	out := bpfBinPath(profile)
+	// remove the old profile first so that we are not loading it
+	// accidentally should the compilation fail
+	if err := os.Remove(out); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var key string
+	if src, err := os.ReadFile(in); err == nil {
+		key = profilecache.Key(src)
+		if ok, err := cache.Restore(key, out); ok {
+			return nil
+		} else if err != nil {
+			logger.Debugf("cannot use cached compiled seccomp profile %s: %v", out, err)
+		}
+	}
+
+	// snap-seccomp uses AtomicWriteFile internally, on failure the output
+	// file is unlinked
+	if err := compiler.Compile(in, out); err != nil {
+		return fmt.Errorf("cannot compile %s: %v", in, err)
+	}
+	if key != "" {
+		if err := cache.Add(key, out); err != nil {
+			logger.Debugf("cannot cache compiled seccomp profile %s: %v", out, err)
+		}
+	}
+	return nil
+}
+
+// compileProfiles compiles the given profiles using a pool of workers and
+// returns the result of the compilation of each of them, in the same order.
+func compileProfiles(compiler Compiler, profiles []string) []error {
+	type result struct {
+		idx int
+		err error
 	}
+
+	errs := make([]error, len(profiles))
+	if len(profiles) == 0 {
+		// no profiles, nothing to do
+		return errs
+	}
+
+	profilesQueue := make(chan int, len(profiles))
+	numWorkers := numCompileWorkers(len(profiles))
 	resultsBufferSize := numWorkers * 2
 	if resultsBufferSize > len(profiles) {
 		resultsBufferSize = len(profiles)
 	}
-	res := make(chan error, resultsBufferSize)
+	res := make(chan result, resultsBufferSize)
+	cache := profilecache.New(filepath.Join(dirs.SnapProfilesCacheDir, "seccomp"), compiledCacheSize)
 
-	// launch as many workers as we have CPUs
 	for i := 0; i < numWorkers; i++ {
 		go func() {
-			for {
-				profile, ok := <-profilesQueue
-				if !ok {
-					break
-				}
-				in := bpfSrcPath(profile)
-				out := bpfBinPath(profile)
-				// remove the old profile first so that we are
-				// not loading it accidentally should the
-				// compilation fail
-				if err := os.Remove(out); err != nil && !os.IsNotExist(err) {
-					res <- err
-					continue
-				}
-
-				// snap-seccomp uses AtomicWriteFile internally, on failure the
-				// output file is unlinked
-				if err := compiler.Compile(in, out); err != nil {
-					res <- fmt.Errorf("cannot compile %s: %v", in, err)
-				} else {
-					res <- nil
-				}
+			for idx := range profilesQueue {
+				res <- result{idx, compileProfile(compiler, cache, profiles[idx])}
 			}
 		}()
 	}
 
-	for _, p := range profiles {
-		profilesQueue <- p
+	for idx := range profiles {
+		profilesQueue <- idx
 	}
 	// signal workers to exit
 	close(profilesQueue)
 
-	var firstErr error
 	for i := 0; i < len(profiles); i++ {
-		maybeErr := <-res
-		if maybeErr != nil && firstErr == nil {
-			firstErr = maybeErr
-		}
-
+		r := <-res
+		errs[r.idx] = r.err
 	}
 
 	// not expecting any more results
 	close(res)
 
+	return errs
+}
+
+func parallelCompile(compiler Compiler, profiles []string) error {
+	var firstErr error
+	for _, err := range compileProfiles(compiler, profiles) {
+		if err != nil {
+			firstErr = err
+			break
+		}
+	}
 	if firstErr != nil {
 		for _, p := range profiles {
 			out := bpfBinPath(p)
@@ -226,7 +288,6 @@ func parallelCompile(compiler Compiler, profiles []string) error {
 			// compiled
 			os.Remove(out)
 		}
-
 	}
 	return firstErr
 }
@@ -238,23 +299,85 @@ func parallelCompile(compiler Compiler, profiles []string) error {
 // This method should be called after changing plug, slots, connections between
 // them or application present in the snap.
 func (b *Backend) Setup(snapInfo *snap.Info, opts interfaces.ConfinementOptions, repo *interfaces.Repository, tm timings.Measurer) error {
+	changed, err := b.prepareProfiles(snapInfo, opts, repo)
+	if err != nil {
+		return err
+	}
+	return parallelCompile(b.snapSeccomp, changed)
+}
+
+// SetupMany creates seccomp profiles of multiple snaps. The profiles of all
+// the snaps are compiled by a single pool of workers, rather than one snap
+// after the other, which matters when many snaps are set up at once, e.g.
+// during seeding.
+//
+// SetupMany tries to set up all the snaps without interrupting on errors, but
+// collects and returns them all, in the order of the given snaps. The
+// compiled profiles of a snap for which an error occurred are removed.
+func (b *Backend) SetupMany(snaps []*snap.Info, confinement func(snapName string) interfaces.ConfinementOptions, repo *interfaces.Repository, tm timings.Measurer) []error {
+	snapErrs := make([]error, len(snaps))
+	var profiles []string
+	// index of the snap each of the profiles belongs to
+	var profileSnaps []int
+	for i, snapInfo := range snaps {
+		changed, err := b.prepareProfiles(snapInfo, confinement(snapInfo.InstanceName()), repo)
+		if err != nil {
+			snapErrs[i] = err
+			continue
+		}
+		for _, profile := range changed {
+			profiles = append(profiles, profile)
+			profileSnaps = append(profileSnaps, i)
+		}
+	}
+
+	var compileErrs []error
+	timings.Run(tm, "compile-profiles[many]", fmt.Sprintf("compile seccomp profiles of %d snaps", len(snaps)), func(nesttm timings.Measurer) {
+		compileErrs = compileProfiles(b.snapSeccomp, profiles)
+	})
+	for i, err := range compileErrs {
+		if err != nil && snapErrs[profileSnaps[i]] == nil {
+			snapErrs[profileSnaps[i]] = err
+		}
+	}
+
+	var errors []error
+	for i, err := range snapErrs {
+		if err == nil {
+			continue
+		}
+		for j, profile := range profiles {
+			if profileSnaps[j] == i {
+				// unlink all profiles of the snap that could
+				// have been successfully compiled
+				os.Remove(bpfBinPath(profile))
+			}
+		}
+		errors = append(errors, fmt.Errorf("cannot setup profiles for snap %q: %s", snaps[i].InstanceName(), err))
+	}
+	return errors
+}
+
+// prepareProfiles writes the seccomp profile sources of a given snap and
+// returns the ones that changed and need to be compiled.
+func (b *Backend) prepareProfiles(snapInfo *snap.Info, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (changed []string, err error) {
 	snapName := snapInfo.InstanceName()
 	// Get the snippets that apply to this snap
 	spec, err := repo.SnapSpecification(b.Name(), snapName)
 	if err != nil {
-		return fmt.Errorf("cannot obtain seccomp specification for snap %q: %s", snapName, err)
+		return nil, fmt.Errorf("cannot obtain seccomp specification for snap %q: %s", snapName, err)
 	}
 
 	// Get the snippets that apply to this snap
 	content, err := b.deriveContent(spec.(*Specification), opts, snapInfo)
 	if err != nil {
-		return fmt.Errorf("cannot obtain expected security files for snap %q: %s", snapName, err)
+		return nil, fmt.Errorf("cannot obtain expected security files for snap %q: %s", snapName, err)
 	}
 
 	glob := interfaces.SecurityTagGlob(snapName) + ".src"
 	dir := dirs.SnapSeccompDir
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("cannot create directory for seccomp profiles %q: %s", dir, err)
+		return nil, fmt.Errorf("cannot create directory for seccomp profiles %q: %s", dir, err)
 	}
 	// There is a delicate interaction between `snap run`, `snap-confine`
 	// and compilation of profiles:
@@ -267,16 +390,16 @@ func (b *Backend) Setup(snapInfo *snap.Info, opts interfaces.ConfinementOptions,
 	//   appear in that time, `snap-confine` will fail and die
 	changed, removed, err := osutil.EnsureDirState(dir, glob, content)
 	if err != nil {
-		return fmt.Errorf("cannot synchronize security files for snap %q: %s", snapName, err)
+		return nil, fmt.Errorf("cannot synchronize security files for snap %q: %s", snapName, err)
 	}
 	for _, c := range removed {
 		err := os.Remove(bpfBinPath(c))
 		if err != nil && !os.IsNotExist(err) {
-			return err
+			return nil, err
 		}
 	}
 
-	return parallelCompile(b.snapSeccomp, changed)
+	return changed, nil
 }
 
 // Remove removes seccomp profiles of a given snap.
@@ -362,6 +485,10 @@ func generateContent(opts interfaces.ConfinementOptions, snippetForTag string, a
 			buffer.WriteString("# complain mode logging unavailable\n")
 		}
 	}
+	if opts.SyscallProfiling {
+		// NOTE: This is understood by snap-seccomp
+		buffer.WriteString("@profile\n")
+	}
 
 	buffer.Write(defaultTemplate)
 	buffer.WriteString(snippetForTag)
@@ -378,6 +505,30 @@ func generateContent(opts interfaces.ConfinementOptions, snippetForTag string, a
 		buffer.WriteString(socketcallSyscallDeprecated)
 	}
 
+	if opts.Hardening.NoPtrace && (!opts.Classic || opts.JailMode) {
+		// the administrator asked for ptrace to be unavailable to the
+		// snap, regardless of what its connected interfaces grant
+		return withoutSyscalls(buffer.Bytes(), ptraceSyscalls)
+	}
+
+	return buffer.Bytes()
+}
+
+// ptraceSyscalls are the system calls which allow inspecting or modifying
+// the memory of another process.
+var ptraceSyscalls = []string{"ptrace", "process_vm_readv", "process_vm_writev"}
+
+// withoutSyscalls removes the rules for the given system calls from a
+// seccomp profile source.
+func withoutSyscalls(content []byte, syscalls []string) []byte {
+	var buffer bytes.Buffer
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && strutil.ListContains(syscalls, fields[0]) {
+			continue
+		}
+		buffer.WriteString(line)
+	}
 	return buffer.Bytes()
 }
 
@@ -308,6 +308,18 @@ var combineSnippetsScenarios = []combineSnippetsScenario{{
 	opts:    interfaces.ConfinementOptions{DevMode: true},
 	snippet: "snippet",
 	content: "@complain\ndefault\nsnippet\n",
+}, {
+	opts:    interfaces.ConfinementOptions{SyscallProfiling: true},
+	snippet: "snippet",
+	content: "@profile\ndefault\nsnippet\n",
+}, {
+	opts:    interfaces.ConfinementOptions{Hardening: interfaces.HardeningOptions{NoPtrace: true}},
+	snippet: "snippet\nptrace\nprocess_vm_readv\nptraceme",
+	content: "default\nsnippet\nptraceme\n",
+}, {
+	opts:    interfaces.ConfinementOptions{Classic: true, Hardening: interfaces.HardeningOptions{NoPtrace: true}},
+	snippet: "snippet\nptrace",
+	content: "@unrestricted\ndefault\nsnippet\nptrace\n",
 }, {
 	opts:    interfaces.ConfinementOptions{Classic: true},
 	snippet: "snippet",
@@ -970,3 +982,108 @@ func (s *backendSuite) TestParallelCompileRemovesFirst(c *C) {
 	err = seccomp.ParallelCompile(&m, []string{"profile-001"})
 	c.Assert(err, ErrorMatches, "remove .*/profile-001.bin: permission denied")
 }
+
+func (s *backendSuite) TestNumCompileWorkers(c *C) {
+	restore := seccomp.MockRuntimeNumCPU(func() int { return 8 })
+	defer restore()
+	mem := uint64(16 * 1024 * 1024 * 1024)
+	var memErr error
+	restore = seccomp.MockTotalUsableMemory(func() (uint64, error) { return mem, memErr })
+	defer restore()
+
+	// one CPU is spared
+	c.Check(seccomp.NumCompileWorkers(100), Equals, 7)
+	// no more workers than profiles
+	c.Check(seccomp.NumCompileWorkers(3), Equals, 3)
+	c.Check(seccomp.NumCompileWorkers(0), Equals, 1)
+
+	// bounded by memory
+	mem = 256 * 1024 * 1024
+	c.Check(seccomp.NumCompileWorkers(100), Equals, 4)
+	mem = 16 * 1024 * 1024
+	c.Check(seccomp.NumCompileWorkers(100), Equals, 1)
+
+	// memory cannot be determined
+	memErr = fmt.Errorf("boom")
+	c.Check(seccomp.NumCompileWorkers(100), Equals, 7)
+
+	restore = seccomp.MockRuntimeNumCPU(func() int { return 1 })
+	defer restore()
+	c.Check(seccomp.NumCompileWorkers(100), Equals, 1)
+}
+
+func (s *backendSuite) TestSetupMany(c *C) {
+	snapInfo1 := snaptest.MockInfo(c, ifacetest.SambaYamlV1, nil)
+	snapInfo2 := snaptest.MockInfo(c, ifacetest.SomeSnapYamlV1, nil)
+	setupManyInterface, ok := s.Backend.(interfaces.SecurityBackendSetupMany)
+	c.Assert(ok, Equals, true)
+	errs := setupManyInterface.SetupMany([]*snap.Info{snapInfo1, snapInfo2}, func(snapName string) interfaces.ConfinementOptions {
+		return interfaces.ConfinementOptions{}
+	}, s.Repo, s.meas)
+	c.Assert(errs, HasLen, 0)
+
+	var compiled []string
+	for _, call := range s.snapSeccomp.Calls() {
+		c.Assert(call, HasLen, 4)
+		c.Check(call[1], Equals, "compile")
+		compiled = append(compiled, filepath.Base(call[2]))
+	}
+	sort.Strings(compiled)
+	c.Check(compiled, DeepEquals, []string{
+		"snap.samba.smbd.src",
+		"snap.some-snap.someapp.src",
+	})
+}
+
+func (s *backendSuite) TestSetupManyCompileError(c *C) {
+	snapInfo1 := snaptest.MockInfo(c, ifacetest.SambaYamlV1, nil)
+	snapInfo2 := snaptest.MockInfo(c, ifacetest.SomeSnapYamlV1, nil)
+
+	// compiling the profiles of the first snap fails
+	s.snapSeccomp.Restore()
+	s.snapSeccomp = testutil.MockLockedCommand(c, filepath.Join(dirs.DistroLibExecDir, "snap-seccomp"), `
+case "$2" in
+    *snap.samba.*)
+        echo "failed"
+        exit 1
+        ;;
+esac
+touch "$3"
+`)
+
+	setupManyInterface := s.Backend.(interfaces.SecurityBackendSetupMany)
+	errs := setupManyInterface.SetupMany([]*snap.Info{snapInfo1, snapInfo2}, func(snapName string) interfaces.ConfinementOptions {
+		return interfaces.ConfinementOptions{}
+	}, s.Repo, s.meas)
+	c.Assert(errs, HasLen, 1)
+	c.Check(errs[0], ErrorMatches, `cannot setup profiles for snap "samba": cannot compile .*/snap.samba.smbd.src: failed`)
+
+	c.Check(filepath.Join(dirs.SnapSeccompDir, "snap.samba.smbd.bin"), testutil.FileAbsent)
+	c.Check(filepath.Join(dirs.SnapSeccompDir, "snap.some-snap.someapp.bin"), testutil.FilePresent)
+}
+
+func (s *backendSuite) TestReinstallingSnapUsesCachedProfiles(c *C) {
+	s.snapSeccomp.Restore()
+	s.snapSeccomp = testutil.MockLockedCommand(c, filepath.Join(dirs.DistroLibExecDir, "snap-seccomp"), `touch "$3"`)
+
+	snapInfo := s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 0)
+	profile := filepath.Join(dirs.SnapSeccompDir, "snap.samba.smbd")
+	c.Check(s.snapSeccomp.Calls(), DeepEquals, [][]string{
+		{"snap-seccomp", "compile", profile + ".src", profile + ".bin"},
+	})
+	s.RemoveSnap(c, snapInfo)
+	c.Check(profile+".bin", testutil.FileAbsent)
+	s.snapSeccomp.ForgetCalls()
+
+	// the compiled profile is taken from the cache
+	s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 0)
+	c.Check(s.snapSeccomp.Calls(), HasLen, 0)
+	c.Check(profile+".bin", testutil.FilePresent)
+
+	// but a different profile is compiled
+	s.snapSeccomp.ForgetCalls()
+	s.UpdateSnap(c, snapInfo, interfaces.ConfinementOptions{DevMode: true}, ifacetest.SambaYamlV1, 0)
+	c.Check(s.snapSeccomp.Calls(), DeepEquals, [][]string{
+		{"snap-seccomp", "compile", profile + ".src", profile + ".bin"},
+	})
+}
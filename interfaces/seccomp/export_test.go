@@ -20,6 +20,9 @@
 package seccomp
 
 import (
+	"time"
+
+	"github.com/snapcore/snapd/interfaces/denials"
 	seccomp_compiler "github.com/snapcore/snapd/sandbox/seccomp"
 )
 
@@ -86,6 +89,22 @@ func MockSeccompCompilerLookup(f func(string) (string, error)) (restore func())
 	}
 }
 
+func MockRuntimeNumCPU(f func() int) (restore func()) {
+	old := runtimeNumCPU
+	runtimeNumCPU = f
+	return func() {
+		runtimeNumCPU = old
+	}
+}
+
+func MockTotalUsableMemory(f func() (uint64, error)) (restore func()) {
+	old := osutilTotalUsableMemory
+	osutilTotalUsableMemory = f
+	return func() {
+		osutilTotalUsableMemory = old
+	}
+}
+
 func (b *Backend) VersionInfo() seccomp_compiler.VersionInfo {
 	return b.versionInfo
 }
@@ -96,5 +115,22 @@ var (
 	GlobalProfileLE = globalProfileLE
 	GlobalProfileBE = globalProfileBE
 
-	ParallelCompile = parallelCompile
+	ParallelCompile   = parallelCompile
+	NumCompileWorkers = numCompileWorkers
 )
+
+func MockDenialsReadSyscalls(f func(since time.Time) ([]*denials.Syscall, error)) (restore func()) {
+	old := denialsReadSyscalls
+	denialsReadSyscalls = f
+	return func() {
+		denialsReadSyscalls = old
+	}
+}
+
+func MockSeccompSyscallNames(f func(auditArch string, numbers []int) ([]string, error)) (restore func()) {
+	old := seccompSyscallNames
+	seccompSyscallNames = f
+	return func() {
+		seccompSyscallNames = old
+	}
+}
@@ -0,0 +1,209 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package seccomp
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/sandbox/seccomp"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
+)
+
+var (
+	denialsReadSyscalls = denials.ReadSyscalls
+	seccompSyscallNames = syscallNamesImpl
+)
+
+func syscallNamesImpl(auditArch string, numbers []int) ([]string, error) {
+	compiler, err := seccomp.NewCompiler(seccompCompilerLookup)
+	if err != nil {
+		return nil, err
+	}
+	return compiler.SyscallNames(auditArch, numbers)
+}
+
+// GrantedSyscalls returns the sorted names of the system calls allowed by the
+// seccomp profile of the given security tag. Explicitly denied system calls
+// are left out.
+func GrantedSyscalls(securityTag string) ([]string, error) {
+	f, err := os.Open(filepath.Join(dirs.SnapSeccompDir, securityTag+".src"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var syscalls []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "@") || strings.HasPrefix(line, "~") {
+			continue
+		}
+		syscalls = append(syscalls, strings.Fields(line)[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	syscalls = strutil.Deduplicate(syscalls)
+	sort.Strings(syscalls)
+	return syscalls, nil
+}
+
+// SyscallProfile compares the system calls used by an application or hook
+// of a snap while profiling with the ones its seccomp profile grants.
+type SyscallProfile struct {
+	// SecurityTag identifies the application or hook, e.g. snap.foo.app.
+	// It is empty for the system calls that could not be attributed to
+	// an application or hook, which happens when AppArmor is not in use.
+	SecurityTag string `json:"security-tag"`
+	// Used maps the names of the used system calls to how many times
+	// their use was logged.
+	Used map[string]int `json:"used,omitempty"`
+	// Unused lists the granted system calls that were not used.
+	Unused []string `json:"unused,omitempty"`
+	// Ungranted lists the used system calls that are not granted.
+	Ungranted []string `json:"ungranted,omitempty"`
+}
+
+// ProfilingReport summarizes the system calls used by a snap while
+// profiling.
+type ProfilingReport struct {
+	Snap     string            `json:"snap"`
+	Since    time.Time         `json:"since"`
+	Profiles []*SyscallProfile `json:"profiles"`
+}
+
+// newSyscallProfile returns the profile of the system calls used out of the
+// granted ones, which must be sorted.
+func newSyscallProfile(securityTag string, granted []string, used map[string]int) *SyscallProfile {
+	profile := &SyscallProfile{
+		SecurityTag: securityTag,
+		Used:        used,
+	}
+	for _, name := range granted {
+		if used[name] == 0 {
+			profile.Unused = append(profile.Unused, name)
+		}
+	}
+	for name := range used {
+		if !strutil.SortedListContains(granted, name) {
+			profile.Ungranted = append(profile.Ungranted, name)
+		}
+	}
+	sort.Strings(profile.Ungranted)
+	return profile
+}
+
+// ReportSyscallProfiling returns the report comparing the system calls that the given snap
+// used since profiling started, as logged by its seccomp filters, with the
+// ones granted by the profiles of its applications and hooks.
+func ReportSyscallProfiling(snapInfo *snap.Info, since time.Time) (*ProfilingReport, error) {
+	var securityTags []string
+	for _, app := range snapInfo.Apps {
+		securityTags = append(securityTags, app.SecurityTag())
+	}
+	for _, hook := range snapInfo.Hooks {
+		securityTags = append(securityTags, hook.SecurityTag())
+	}
+	sort.Strings(securityTags)
+
+	granted := make(map[string][]string, len(securityTags))
+	var allGranted []string
+	for _, securityTag := range securityTags {
+		syscalls, err := GrantedSyscalls(securityTag)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read seccomp profile of %q: %v", securityTag, err)
+		}
+		granted[securityTag] = syscalls
+		allGranted = strutil.SortedListsUniqueMerge(allGranted, syscalls)
+	}
+
+	logged, err := denialsReadSyscalls(since)
+	if err != nil {
+		return nil, err
+	}
+	numbers := make(map[string][]int)
+	for _, sc := range logged {
+		if sc.Snap != snapInfo.InstanceName() {
+			continue
+		}
+		numbers[sc.Arch] = append(numbers[sc.Arch], sc.Number)
+	}
+	names := make(map[string]map[int]string, len(numbers))
+	for arch, nrs := range numbers {
+		sort.Ints(nrs)
+		nrs = uniqueInts(nrs)
+		resolved, err := seccompSyscallNames(arch, nrs)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve system calls of architecture %s: %v", arch, err)
+		}
+		names[arch] = make(map[int]string, len(nrs))
+		for i, nr := range nrs {
+			names[arch][nr] = resolved[i]
+		}
+	}
+
+	used := make(map[string]map[string]int)
+	for _, sc := range logged {
+		if sc.Snap != snapInfo.InstanceName() {
+			continue
+		}
+		securityTag := sc.Profile
+		if _, ok := granted[securityTag]; !ok {
+			securityTag = ""
+		}
+		if used[securityTag] == nil {
+			used[securityTag] = make(map[string]int)
+		}
+		used[securityTag][names[sc.Arch][sc.Number]]++
+	}
+
+	report := &ProfilingReport{
+		Snap:     snapInfo.InstanceName(),
+		Since:    since,
+		Profiles: make([]*SyscallProfile, 0, len(securityTags)+1),
+	}
+	for _, securityTag := range securityTags {
+		report.Profiles = append(report.Profiles, newSyscallProfile(securityTag, granted[securityTag], used[securityTag]))
+	}
+	if unattributed := used[""]; unattributed != nil {
+		report.Profiles = append(report.Profiles, newSyscallProfile("", allGranted, unattributed))
+	}
+	return report, nil
+}
+
+func uniqueInts(sorted []int) []int {
+	unique := sorted[:0]
+	for i, n := range sorted {
+		if i == 0 || n != sorted[i-1] {
+			unique = append(unique, n)
+		}
+	}
+	return unique
+}
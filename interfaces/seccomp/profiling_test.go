@@ -0,0 +1,177 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package seccomp_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/interfaces/seccomp"
+	"github.com/snapcore/snapd/snap/snaptest"
+)
+
+type profilingSuite struct{}
+
+var _ = Suite(&profilingSuite{})
+
+func (s *profilingSuite) SetUpTest(c *C) {
+	dirs.SetRootDir(c.MkDir())
+}
+
+func (s *profilingSuite) TearDownTest(c *C) {
+	dirs.SetRootDir("")
+}
+
+func (s *profilingSuite) TestGrantedSyscalls(c *C) {
+	c.Assert(os.MkdirAll(dirs.SnapSeccompDir, 0755), IsNil)
+	profile := filepath.Join(dirs.SnapSeccompDir, "snap.foo.app.src")
+	content := `# snap-seccomp version information:
+# 1234 2.5.4 abcd bpf-actlog
+@complain
+read
+write
+
+# comment
+socket AF_INET
+socket AF_UNIX
+~ioctl - TIOCSTI
+accept
+`
+	c.Assert(os.WriteFile(profile, []byte(content), 0644), IsNil)
+
+	syscalls, err := seccomp.GrantedSyscalls("snap.foo.app")
+	c.Assert(err, IsNil)
+	c.Check(syscalls, DeepEquals, []string{"accept", "read", "socket", "write"})
+
+	_, err = seccomp.GrantedSyscalls("snap.foo.other")
+	c.Check(os.IsNotExist(err), Equals, true)
+}
+
+const profilingSnapYaml = `name: foo
+version: 1
+apps:
+  app:
+  daemon:
+    daemon: simple
+hooks:
+  configure:
+`
+
+func (s *profilingSuite) writeProfile(c *C, securityTag, content string) {
+	c.Assert(os.MkdirAll(dirs.SnapSeccompDir, 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(dirs.SnapSeccompDir, securityTag+".src"), []byte(content), 0644), IsNil)
+}
+
+func (s *profilingSuite) TestReportSyscallProfiling(c *C) {
+	info := snaptest.MockInfo(c, profilingSnapYaml, nil)
+	s.writeProfile(c, "snap.foo.app", "@profile\nread\nwrite\nopenat\n")
+	s.writeProfile(c, "snap.foo.daemon", "@profile\nread\nwrite\nsocket AF_INET\n")
+	s.writeProfile(c, "snap.foo.hook.configure", "@profile\nread\n")
+
+	since := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	restore := seccomp.MockDenialsReadSyscalls(func(t time.Time) ([]*denials.Syscall, error) {
+		c.Check(t.Equal(since), Equals, true)
+		return []*denials.Syscall{
+			{Snap: "foo", Profile: "snap.foo.app", Arch: "c000003e", Number: 0},
+			{Snap: "foo", Profile: "snap.foo.app", Arch: "c000003e", Number: 0},
+			{Snap: "foo", Profile: "snap.foo.app", Arch: "c000003e", Number: 165},
+			{Snap: "bar", Profile: "snap.bar.app", Arch: "c000003e", Number: 1},
+			{Snap: "foo", Profile: "snap.foo.daemon", Arch: "c000003e", Number: 1},
+			{Snap: "foo", Profile: "snap.foo.daemon", Arch: "c000003e", Number: 41},
+			{Snap: "foo", Arch: "40000003", Number: 3},
+		}, nil
+	})
+	defer restore()
+	var calls [][]int
+	restore = seccomp.MockSeccompSyscallNames(func(auditArch string, numbers []int) ([]string, error) {
+		calls = append(calls, numbers)
+		names := map[string]map[int]string{
+			"c000003e": {0: "read", 1: "write", 41: "socket", 165: "mount"},
+			"40000003": {3: "read"},
+		}
+		var resolved []string
+		for _, nr := range numbers {
+			resolved = append(resolved, names[auditArch][nr])
+		}
+		return resolved, nil
+	})
+	defer restore()
+
+	report, err := seccomp.ReportSyscallProfiling(info, since)
+	c.Assert(err, IsNil)
+	c.Check(calls, HasLen, 2)
+	c.Check(report, DeepEquals, &seccomp.ProfilingReport{
+		Snap:  "foo",
+		Since: since,
+		Profiles: []*seccomp.SyscallProfile{{
+			SecurityTag: "snap.foo.app",
+			Used:        map[string]int{"read": 2, "mount": 1},
+			Unused:      []string{"openat", "write"},
+			Ungranted:   []string{"mount"},
+		}, {
+			SecurityTag: "snap.foo.daemon",
+			Used:        map[string]int{"write": 1, "socket": 1},
+			Unused:      []string{"read"},
+		}, {
+			SecurityTag: "snap.foo.hook.configure",
+			Unused:      []string{"read"},
+		}, {
+			// without AppArmor, compared with all granted system calls
+			SecurityTag: "",
+			Used:        map[string]int{"read": 1},
+			Unused:      []string{"openat", "socket", "write"},
+		}},
+	})
+}
+
+func (s *profilingSuite) TestReportSyscallProfilingErrors(c *C) {
+	info := snaptest.MockInfo(c, profilingSnapYaml, nil)
+
+	_, err := seccomp.ReportSyscallProfiling(info, time.Time{})
+	c.Check(err, ErrorMatches, `cannot read seccomp profile of "snap.foo.app": open .*: no such file or directory`)
+
+	s.writeProfile(c, "snap.foo.app", "read\n")
+	s.writeProfile(c, "snap.foo.daemon", "read\n")
+	s.writeProfile(c, "snap.foo.hook.configure", "read\n")
+
+	restore := seccomp.MockDenialsReadSyscalls(func(time.Time) ([]*denials.Syscall, error) {
+		return nil, errors.New("cannot read journal: boom")
+	})
+	defer restore()
+	_, err = seccomp.ReportSyscallProfiling(info, time.Time{})
+	c.Check(err, ErrorMatches, "cannot read journal: boom")
+
+	restore = seccomp.MockDenialsReadSyscalls(func(time.Time) ([]*denials.Syscall, error) {
+		return []*denials.Syscall{{Snap: "foo", Profile: "snap.foo.app", Arch: "deadbeef", Number: 0}}, nil
+	})
+	defer restore()
+	restore = seccomp.MockSeccompSyscallNames(func(auditArch string, numbers []int) ([]string, error) {
+		return nil, errors.New(`unsupported audit architecture "deadbeef"`)
+	})
+	defer restore()
+	_, err = seccomp.ReportSyscallProfiling(info, time.Time{})
+	c.Check(err, ErrorMatches, `cannot resolve system calls of architecture deadbeef: unsupported audit architecture "deadbeef"`)
+}
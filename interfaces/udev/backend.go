@@ -30,18 +30,27 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/sandbox/cgroup"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
 	"github.com/snapcore/snapd/timings"
 )
 
 // Backend is responsible for maintaining udev rules.
 type Backend struct {
 	preseed bool
+
+	// deferred holds the subsystems to trigger for the snaps whose
+	// changed rules were not reloaded yet, as this is to be done later
+	// in the same change.
+	deferredMu sync.Mutex
+	deferred   map[string][]string
 }
 
 // Initialize does nothing.
@@ -70,17 +79,52 @@ func snapRulesFilePath(snapName string) string {
 //
 // If the method fails it should be re-tried (with a sensible strategy) by the caller.
 func (b *Backend) Setup(snapInfo *snap.Info, opts interfaces.ConfinementOptions, repo *interfaces.Repository, tm timings.Measurer) error {
+	changed, subsystemTriggers, err := b.writeRules(snapInfo, opts, repo)
+	if err != nil {
+		return err
+	}
+	var reload reloadBatch
+	b.addToReload(&reload, snapInfo.InstanceName(), changed, subsystemTriggers, opts)
+	return b.reload(&reload)
+}
+
+// SetupMany creates udev rules for multiple snaps, reloading the udev
+// database at most once for all of them.
+func (b *Backend) SetupMany(snaps []*snap.Info, confinement func(snapName string) interfaces.ConfinementOptions, repo *interfaces.Repository, tm timings.Measurer) []error {
+	var errs []error
+	var reload reloadBatch
+	for _, snapInfo := range snaps {
+		opts := confinement(snapInfo.InstanceName())
+		changed, subsystemTriggers, err := b.writeRules(snapInfo, opts, repo)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot setup udev rules for snap %q: %s", snapInfo.InstanceName(), err))
+			continue
+		}
+		b.addToReload(&reload, snapInfo.InstanceName(), changed, subsystemTriggers, opts)
+	}
+	if err := b.reload(&reload); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// writeRules writes the udev rules of the given snap and returns whether
+// they changed, along with the subsystems to trigger.
+func (b *Backend) writeRules(snapInfo *snap.Info, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (changed bool, subsystemTriggers []string, err error) {
 	snapName := snapInfo.InstanceName()
 	spec, err := repo.SnapSpecification(b.Name(), snapName)
 	if err != nil {
-		return fmt.Errorf("cannot obtain udev specification for snap %q: %s", snapName, err)
+		return false, nil, fmt.Errorf("cannot obtain udev specification for snap %q: %s", snapName, err)
 	}
 	content := b.deriveContent(spec.(*Specification), snapInfo)
-	subsystemTriggers := spec.(*Specification).TriggeredSubsystems()
+	// FIXME: somehow detect the interfaces that were disconnected and set
+	// subsystemTriggers appropriately. ATM, it is always going to be empty
+	// on disconnect.
+	subsystemTriggers = spec.(*Specification).TriggeredSubsystems()
 
 	dir := dirs.SnapUdevRulesDir
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("cannot create directory for udev rules %q: %s", dir, err)
+		return false, nil, fmt.Errorf("cannot create directory for udev rules %q: %s", dir, err)
 	}
 
 	rulesFilePath := snapRulesFilePath(snapInfo.InstanceName())
@@ -90,14 +134,9 @@ func (b *Backend) Setup(snapInfo *snap.Info, opts interfaces.ConfinementOptions,
 		// content and exists.
 		err = os.Remove(rulesFilePath)
 		if err != nil && !os.IsNotExist(err) {
-			return err
-		} else if err == nil {
-			// FIXME: somehow detect the interfaces that were
-			// disconnected and set subsystemTriggers appropriately.
-			// ATM, it is always going to be empty on disconnect.
-			return b.reloadRules(subsystemTriggers)
+			return false, nil, err
 		}
-		return nil
+		return err == nil, subsystemTriggers, nil
 	}
 
 	var buffer bytes.Buffer
@@ -124,15 +163,53 @@ func (b *Backend) Setup(snapInfo *snap.Info, opts interfaces.ConfinementOptions,
 	// udev rules when not needed.
 	err = osutil.EnsureFileState(rulesFilePath, rulesFileState)
 	if err == osutil.ErrSameState {
-		return nil
+		return false, subsystemTriggers, nil
 	} else if err != nil {
-		return err
+		return false, nil, err
 	}
+	return true, subsystemTriggers, nil
+}
 
-	// FIXME: somehow detect the interfaces that were disconnected and set
-	// subsystemTriggers appropriately. ATM, it is always going to be empty
-	// on disconnect.
-	return b.reloadRules(subsystemTriggers)
+// reloadBatch collects the subsystems to trigger when reloading the udev
+// database once for a number of snaps.
+type reloadBatch struct {
+	needed            bool
+	subsystemTriggers []string
+}
+
+// addToReload adds the snap to the batch of snaps to reload the rules of,
+// unless its rules did not change or their reload is deferred. Rules whose
+// reload was deferred earlier are reloaded now.
+func (b *Backend) addToReload(reload *reloadBatch, snapName string, changed bool, subsystemTriggers []string, opts interfaces.ConfinementOptions) {
+	b.deferredMu.Lock()
+	defer b.deferredMu.Unlock()
+
+	if opts.DeferDeviceRulesReload {
+		if changed {
+			if b.deferred == nil {
+				b.deferred = make(map[string][]string)
+			}
+			logger.Debugf("deferring reload of udev rules of snap %q", snapName)
+			b.deferred[snapName] = append(b.deferred[snapName], subsystemTriggers...)
+		}
+		return
+	}
+	if deferred, ok := b.deferred[snapName]; ok {
+		changed = true
+		subsystemTriggers = append(subsystemTriggers, deferred...)
+		delete(b.deferred, snapName)
+	}
+	if changed {
+		reload.needed = true
+		reload.subsystemTriggers = append(reload.subsystemTriggers, subsystemTriggers...)
+	}
+}
+
+func (b *Backend) reload(reload *reloadBatch) error {
+	if !reload.needed {
+		return nil
+	}
+	return b.reloadRules(strutil.Deduplicate(reload.subsystemTriggers))
 }
 
 // Remove removes udev rules specific to a given snap.
@@ -142,6 +219,10 @@ func (b *Backend) Setup(snapInfo *snap.Info, opts interfaces.ConfinementOptions,
 //
 // If the method fails it should be re-tried (with a sensible strategy) by the caller.
 func (b *Backend) Remove(snapName string) error {
+	b.deferredMu.Lock()
+	delete(b.deferred, snapName)
+	b.deferredMu.Unlock()
+
 	rulesFilePath := snapRulesFilePath(snapName)
 	err := os.Remove(rulesFilePath)
 	if os.IsNotExist(err) {
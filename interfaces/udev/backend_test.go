@@ -30,6 +30,7 @@ import (
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/ifacetest"
 	"github.com/snapcore/snapd/interfaces/udev"
+	"github.com/snapcore/snapd/sandbox"
 	"github.com/snapcore/snapd/sandbox/cgroup"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/testutil"
@@ -114,6 +115,25 @@ func (s *backendSuite) TestInstallingSnapWritesAndLoadsRules(c *C) {
 	}
 }
 
+func (s *backendSuite) TestInstallingSnapWithoutUdevWritesRules(c *C) {
+	restore := sandbox.MockDegradedFeatures([]sandbox.DegradedFeature{{
+		Feature: sandbox.FeatureDeviceAccess,
+		Reason:  "udev is not running in the container",
+	}})
+	defer restore()
+
+	s.Iface.UDevPermanentSlotCallback = func(spec *udev.Specification, slot *snap.SlotInfo) error {
+		spec.AddSnippet("sample")
+		return nil
+	}
+	snapInfo := s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 0)
+	c.Check(filepath.Join(dirs.SnapUdevRulesDir, "70-snap.samba.rules"), testutil.FilePresent)
+	// without udev there is nothing to reload
+	c.Check(s.udevadmCmd.Calls(), HasLen, 0)
+	s.RemoveSnap(c, snapInfo)
+	c.Check(s.udevadmCmd.Calls(), HasLen, 0)
+}
+
 func (s *backendSuite) TestInstallingSnapWithHookWritesAndLoadsRules(c *C) {
 	// NOTE: Hand out a permanent snippet so that .rules file is generated.
 	s.Iface.UDevPermanentSlotCallback = func(spec *udev.Specification, slot *snap.SlotInfo) error {
@@ -189,6 +209,69 @@ func (s *backendSuite) TestRemovingSnapRemovesAndReloadsRules(c *C) {
 	}
 }
 
+var udevadmReloadCalls = [][]string{
+	{"udevadm", "control", "--reload-rules"},
+	{"udevadm", "trigger", "--subsystem-nomatch=input"},
+	{"udevadm", "trigger", "--property-match=ID_INPUT_JOYSTICK=1"},
+	{"udevadm", "settle", "--timeout=10"},
+}
+
+func (s *backendSuite) TestSetupManyReloadsOnce(c *C) {
+	snippet := "sample"
+	s.Iface.UDevPermanentSlotCallback = func(spec *udev.Specification, slot *snap.SlotInfo) error {
+		spec.AddSnippet(snippet)
+		return nil
+	}
+	snapInfo1 := s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 0)
+	snapInfo2 := s.InstallSnap(c, interfaces.ConfinementOptions{}, "samba_foo", ifacetest.SambaYamlV1, 0)
+
+	setupMany, ok := s.Backend.(interfaces.SecurityBackendSetupMany)
+	c.Assert(ok, Equals, true)
+	opts := func(snapName string) interfaces.ConfinementOptions { return interfaces.ConfinementOptions{} }
+
+	// nothing changed, nothing to reload
+	s.udevadmCmd.ForgetCalls()
+	errs := setupMany.SetupMany([]*snap.Info{snapInfo1, snapInfo2}, opts, s.Repo, s.meas)
+	c.Assert(errs, HasLen, 0)
+	c.Check(s.udevadmCmd.Calls(), HasLen, 0)
+
+	// the rules of both snaps changed, they are reloaded once
+	snippet = "other"
+	errs = setupMany.SetupMany([]*snap.Info{snapInfo1, snapInfo2}, opts, s.Repo, s.meas)
+	c.Assert(errs, HasLen, 0)
+	c.Check(filepath.Join(dirs.SnapUdevRulesDir, "70-snap.samba.rules"), testutil.FileContains, "other")
+	c.Check(filepath.Join(dirs.SnapUdevRulesDir, "70-snap.samba_foo.rules"), testutil.FileContains, "other")
+	c.Check(s.udevadmCmd.Calls(), DeepEquals, udevadmReloadCalls)
+}
+
+func (s *backendSuite) TestSetupDeferredReload(c *C) {
+	snippet := "sample"
+	s.Iface.UDevPermanentSlotCallback = func(spec *udev.Specification, slot *snap.SlotInfo) error {
+		spec.AddSnippet(snippet)
+		return nil
+	}
+	snapInfo := s.InstallSnap(c, interfaces.ConfinementOptions{}, "", ifacetest.SambaYamlV1, 0)
+
+	// the rules are written but not reloaded
+	s.udevadmCmd.ForgetCalls()
+	snippet = "other"
+	err := s.Backend.Setup(snapInfo, interfaces.ConfinementOptions{DeferDeviceRulesReload: true}, s.Repo, s.meas)
+	c.Assert(err, IsNil)
+	c.Check(filepath.Join(dirs.SnapUdevRulesDir, "70-snap.samba.rules"), testutil.FileContains, "other")
+	c.Check(s.udevadmCmd.Calls(), HasLen, 0)
+
+	// and reloaded by the next setup, even if the rules did not change
+	err = s.Backend.Setup(snapInfo, interfaces.ConfinementOptions{}, s.Repo, s.meas)
+	c.Assert(err, IsNil)
+	c.Check(s.udevadmCmd.Calls(), DeepEquals, udevadmReloadCalls)
+
+	// but only once
+	s.udevadmCmd.ForgetCalls()
+	err = s.Backend.Setup(snapInfo, interfaces.ConfinementOptions{}, s.Repo, s.meas)
+	c.Assert(err, IsNil)
+	c.Check(s.udevadmCmd.Calls(), HasLen, 0)
+}
+
 func (s *backendSuite) TestUpdatingSnapToOneWithMoreApps(c *C) {
 	// NOTE: Hand out a permanent snippet so that .rules file is generated.
 	s.Iface.UDevPermanentSlotCallback = func(spec *udev.Specification, slot *snap.SlotInfo) error {
@@ -0,0 +1,99 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package udev
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/snap"
+)
+
+// RulesFile returns the path of the udev rules file of the given snap.
+func RulesFile(snapName string) string {
+	return snapRulesFilePath(snapName)
+}
+
+// TaggedDevice is a device tagged by udev for an app or hook of a snap.
+type TaggedDevice struct {
+	// Tag is the udev tag of the app or hook.
+	Tag string
+	// ID is the identifier of the device in the udev database, e.g.
+	// c4:64 for the character device 4:64.
+	ID string
+	// SysPath is the path of character and block devices in sysfs.
+	SysPath string
+}
+
+// TaggedDevices returns the devices currently tagged by udev for the apps and
+// hooks of the given snap, as recorded in the udev database.
+func TaggedDevices(snapInfo *snap.Info) ([]TaggedDevice, error) {
+	var tags []string
+	for _, app := range snapInfo.Apps {
+		tags = append(tags, udevTag(app.SecurityTag()))
+	}
+	for _, hook := range snapInfo.Hooks {
+		tags = append(tags, udevTag(hook.SecurityTag()))
+	}
+	sort.Strings(tags)
+
+	var devices []TaggedDevice
+	for _, tag := range tags {
+		entries, err := os.ReadDir(filepath.Join(dirs.GlobalRootDir, "/run/udev/tags", tag))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			devices = append(devices, TaggedDevice{
+				Tag:     tag,
+				ID:      entry.Name(),
+				SysPath: deviceSysPath(entry.Name()),
+			})
+		}
+	}
+	return devices, nil
+}
+
+// deviceSysPath returns the path in sysfs of the device with the given udev
+// database identifier, if it is a character or block device.
+func deviceSysPath(id string) string {
+	var devDir string
+	switch {
+	case len(id) > 1 && id[0] == 'c':
+		devDir = "/sys/dev/char"
+	case len(id) > 1 && id[0] == 'b':
+		devDir = "/sys/dev/block"
+	default:
+		return ""
+	}
+	target, err := os.Readlink(filepath.Join(dirs.GlobalRootDir, devDir, id[1:]))
+	if err != nil {
+		return ""
+	}
+	if filepath.IsAbs(target) {
+		return target
+	}
+	return filepath.Join(devDir, target)
+}
@@ -0,0 +1,84 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package udev_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces/udev"
+	"github.com/snapcore/snapd/snap/snaptest"
+)
+
+type inspectSuite struct{}
+
+var _ = Suite(&inspectSuite{})
+
+func (s *inspectSuite) SetUpTest(c *C) {
+	dirs.SetRootDir(c.MkDir())
+}
+
+func (s *inspectSuite) TearDownTest(c *C) {
+	dirs.SetRootDir("")
+}
+
+func (s *inspectSuite) TestRulesFile(c *C) {
+	c.Check(udev.RulesFile("foo_bar"), Equals, filepath.Join(dirs.SnapUdevRulesDir, "70-snap.foo_bar.rules"))
+}
+
+func (s *inspectSuite) TestTaggedDevices(c *C) {
+	info := snaptest.MockInfo(c, `name: foo
+version: 1
+apps:
+ app:
+ other:
+hooks:
+ configure:
+`, nil)
+
+	for _, dev := range []string{
+		"snap_foo_app/c4:64",
+		"snap_foo_app/+usb:1-1",
+		"snap_foo_hook_configure/b8:0",
+		"snap_foobar_app/c4:65",
+	} {
+		c.Assert(os.MkdirAll(filepath.Join(dirs.GlobalRootDir, "/run/udev/tags", dev), 0755), IsNil)
+	}
+	c.Assert(os.MkdirAll(filepath.Join(dirs.GlobalRootDir, "/sys/dev/char"), 0755), IsNil)
+	c.Assert(os.Symlink("../../devices/platform/serial8250/tty/ttyS0", filepath.Join(dirs.GlobalRootDir, "/sys/dev/char/4:64")), IsNil)
+
+	devices, err := udev.TaggedDevices(info)
+	c.Assert(err, IsNil)
+	c.Check(devices, DeepEquals, []udev.TaggedDevice{
+		{Tag: "snap_foo_app", ID: "+usb:1-1"},
+		{Tag: "snap_foo_app", ID: "c4:64", SysPath: "/sys/devices/platform/serial8250/tty/ttyS0"},
+		{Tag: "snap_foo_hook_configure", ID: "b8:0"},
+	})
+}
+
+func (s *inspectSuite) TestTaggedDevicesNone(c *C) {
+	info := snaptest.MockInfo(c, "name: foo\nversion: 1\napps:\n app:\n", nil)
+	devices, err := udev.TaggedDevices(info)
+	c.Assert(err, IsNil)
+	c.Check(devices, HasLen, 0)
+}
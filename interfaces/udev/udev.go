@@ -22,6 +22,9 @@ package udev
 import (
 	"fmt"
 	"os/exec"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/sandbox"
 )
 
 // udevadmTrigger runs "udevadm trigger" but ignores an non-zero exit codes.
@@ -64,6 +67,11 @@ func (b *Backend) reloadRules(subsystemTriggers []string) error {
 	if b.preseed {
 		return nil
 	}
+	if reason, degraded := sandbox.Degraded(sandbox.FeatureDeviceAccess); degraded {
+		// the rules are picked up if udev ever starts
+		logger.Debugf("not reloading udev rules: %s", reason)
+		return nil
+	}
 
 	output, err := exec.Command("udevadm", "control", "--reload-rules").CombinedOutput()
 	if err != nil {
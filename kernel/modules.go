@@ -0,0 +1,97 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package kernel
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Module describes a loadable kernel module shipped by a kernel snap or by
+// one of its components.
+type Module struct {
+	// Name is the name of the module as known to modprobe, with dashes
+	// replaced by underscores.
+	Name string
+	// Path is the path of the module file, relative to the modules
+	// directory of the snap or component.
+	Path string
+}
+
+// moduleSuffixes are the file name suffixes of possibly compressed kernel
+// modules.
+var moduleSuffixes = []string{".ko", ".ko.xz", ".ko.zst", ".ko.gz"}
+
+// ModuleName returns the name of a kernel module as known to modprobe,
+// which treats dashes and underscores in module names alike.
+func ModuleName(name string) string {
+	return strings.Replace(name, "-", "_", -1)
+}
+
+func moduleNameFromFile(fname string) (string, bool) {
+	for _, suffix := range moduleSuffixes {
+		if strings.HasSuffix(fname, suffix) {
+			return ModuleName(strings.TrimSuffix(fname, suffix)), true
+		}
+	}
+	return "", false
+}
+
+// ListModules returns the kernel modules shipped in the modules directory of
+// the kernel snap or component mounted at rootDir, sorted by name. It
+// returns no modules if the directory does not exist.
+func ListModules(rootDir string) ([]Module, error) {
+	modulesDir := filepath.Join(rootDir, "modules")
+	var modules []Module
+	err := filepath.WalkDir(modulesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == modulesDir && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		name, ok := moduleNameFromFile(d.Name())
+		if !ok {
+			return nil
+		}
+		rel, err := filepath.Rel(modulesDir, path)
+		if err != nil {
+			return err
+		}
+		modules = append(modules, Module{Name: name, Path: rel})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		if modules[i].Name != modules[j].Name {
+			return modules[i].Name < modules[j].Name
+		}
+		return modules[i].Path < modules[j].Path
+	})
+	return modules, nil
+}
@@ -0,0 +1,63 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package kernel_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/kernel"
+)
+
+type modulesSuite struct{}
+
+var _ = Suite(&modulesSuite{})
+
+func (s *modulesSuite) TestListModules(c *C) {
+	rootDir := makeMockKernel(c, "", map[string]string{
+		"modules/6.8.0-1-generic/kernel/drivers/net/e1000e.ko.zst":   "",
+		"modules/6.8.0-1-generic/kernel/drivers/usb/usb-storage.ko":  "",
+		"modules/6.8.0-1-generic/kernel/fs/btrfs/btrfs.ko.xz":        "",
+		"modules/6.8.0-1-generic/modules.dep":                        "",
+		"modules/6.8.0-1-generic/kernel/drivers/gpu/drm/README":      "",
+		"modules/6.8.0-2-generic/kernel/drivers/net/e1000e.ko.gz":    "",
+		"firmware/e1000e/e1000e.ko":                                  "",
+		"modules/6.8.0-1-generic/kernel/drivers/misc/not-a-module.o": "",
+	})
+
+	modules, err := kernel.ListModules(rootDir)
+	c.Assert(err, IsNil)
+	c.Check(modules, DeepEquals, []kernel.Module{
+		{Name: "btrfs", Path: "6.8.0-1-generic/kernel/fs/btrfs/btrfs.ko.xz"},
+		{Name: "e1000e", Path: "6.8.0-1-generic/kernel/drivers/net/e1000e.ko.zst"},
+		{Name: "e1000e", Path: "6.8.0-2-generic/kernel/drivers/net/e1000e.ko.gz"},
+		{Name: "usb_storage", Path: "6.8.0-1-generic/kernel/drivers/usb/usb-storage.ko"},
+	})
+}
+
+func (s *modulesSuite) TestListModulesNoModulesDir(c *C) {
+	modules, err := kernel.ListModules(c.MkDir())
+	c.Assert(err, IsNil)
+	c.Check(modules, HasLen, 0)
+}
+
+func (s *modulesSuite) TestModuleName(c *C) {
+	c.Check(kernel.ModuleName("usb-storage"), Equals, "usb_storage")
+	c.Check(kernel.ModuleName("btrfs"), Equals, "btrfs")
+}
@@ -39,11 +39,15 @@ const (
 	CopyFlagOverwrite
 	// CopyFlagPreserveAll preserves mode,owner,time attributes
 	CopyFlagPreserveAll
+	// CopyFlagReflink shares the data of src with dst on filesystems
+	// supporting it (e.g. btrfs, xfs), falling back to a regular copy
+	CopyFlagReflink
 )
 
 var (
-	openfile = doOpenFile
-	copyfile = doCopyFile
+	openfile  = doOpenFile
+	copyfile  = doCopyFile
+	clonefile = doCloneFile
 )
 
 type fileish interface {
@@ -65,7 +69,11 @@ func CopyFile(src, dst string, flags CopyFlag) (err error) {
 		// Our native copy code does not preserve all attributes
 		// (yet). If the user needs this functionality we just
 		// fallback to use the system's "cp" binary to do the copy.
-		if err := runCpPreserveAll(src, dst, "copy all"); err != nil {
+		runCp := runCpPreserveAll
+		if flags&CopyFlagReflink != 0 {
+			runCp = runCpPreserveAllReflink
+		}
+		if err := runCp(src, dst, "copy all"); err != nil {
 			return err
 		}
 		if flags&CopyFlagSync != 0 {
@@ -104,8 +112,12 @@ func CopyFile(src, dst string, flags CopyFlag) (err error) {
 		}
 	}()
 
-	if err := copyfile(fin, fout, fi); err != nil {
-		return fmt.Errorf("unable to copy %s to %s: %v", src, dst, err)
+	// cloning fails when the filesystem does not support it, or when
+	// crossing filesystems, in which case the data is copied
+	if flags&CopyFlagReflink == 0 || clonefile(fin, fout) != nil {
+		if err := copyfile(fin, fout, fi); err != nil {
+			return fmt.Errorf("unable to copy %s to %s: %v", src, dst, err)
+		}
 	}
 
 	if flags&CopyFlagSync != 0 {
@@ -187,6 +199,10 @@ func runCpPreserveAll(path, dest, errdesc string) error {
 	return runCmd(exec.Command("cp", "-av", path, dest), errdesc)
 }
 
+func runCpPreserveAllReflink(path, dest, errdesc string) error {
+	return runCmd(exec.Command("cp", "-av", "--reflink=auto", path, dest), errdesc)
+}
+
 // CopySpecialFile is used to copy all the things that are not files
 // (like device nodes, named pipes etc)
 func CopySpecialFile(path, dest string) error {
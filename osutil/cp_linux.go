@@ -22,6 +22,8 @@ package osutil
 import (
 	"os"
 	"syscall"
+
+	"golang.org/x/sys/unix"
 )
 
 const maxint = int64(^uint(0) >> 1)
@@ -46,3 +48,7 @@ func doCopyFile(fin, fout fileish, fi os.FileInfo) error {
 
 	return nil
 }
+
+func doCloneFile(fin, fout fileish) error {
+	return unix.IoctlFileClone(int(fout.Fd()), int(fin.Fd()))
+}
@@ -51,6 +51,10 @@ func (s *cpSuite) mockCopyFile(fin, fout osutil.Fileish, fi os.FileInfo) error {
 	return s.µ("copyfile")
 }
 
+func (s *cpSuite) mockCloneFile(fin, fout osutil.Fileish) error {
+	return s.µ("clonefile")
+}
+
 func (s *cpSuite) mockOpenFile(name string, flag int, perm os.FileMode) (osutil.Fileish, error) {
 	return &mockfile{s}, s.µ("open")
 }
@@ -116,6 +120,30 @@ func (s *cpSuite) TestCpSync(c *C) {
 	c.Check(strings.Join(s.log, ":"), Matches, `(.*:)?sync(:.*)?`)
 }
 
+func (s *cpSuite) TestCpReflink(c *C) {
+	s.mock()
+	s.AddCleanup(osutil.MockCloneFile(s.mockCloneFile))
+
+	c.Check(osutil.CopyFile(s.f1, s.f2, osutil.CopyFlagDefault), IsNil)
+	c.Check(s.log, Not(testutil.Contains), "clonefile")
+
+	s.log = nil
+	c.Check(osutil.CopyFile(s.f1, s.f2, osutil.CopyFlagReflink), IsNil)
+	c.Check(s.log, testutil.Contains, "clonefile")
+	c.Check(s.log, Not(testutil.Contains), "copyfile")
+}
+
+func (s *cpSuite) TestCpReflinkFallsBackToCopy(c *C) {
+	s.mock()
+	s.AddCleanup(osutil.MockCloneFile(func(fin, fout osutil.Fileish) error {
+		s.log = append(s.log, "clonefile")
+		return syscall.EOPNOTSUPP
+	}))
+
+	c.Check(osutil.CopyFile(s.f1, s.f2, osutil.CopyFlagReflink), IsNil)
+	c.Check(strings.Join(s.log, ":"), Matches, `.*:clonefile:copyfile(:.*)?`)
+}
+
 func (s *cpSuite) TestCpCantOpen(c *C) {
 	s.mock()
 	s.errs = []error{errors.New("xyzzy"), nil}
@@ -259,6 +287,25 @@ func (s *cpSuite) TestCopyPreserveAllSync(c *C) {
 	})
 }
 
+func (s *cpSuite) TestCopyPreserveAllReflink(c *C) {
+	dir := c.MkDir()
+	mocked := testutil.MockCommand(c, "cp", "")
+	defer mocked.Restore()
+
+	src := filepath.Join(dir, "meep")
+	dst := filepath.Join(dir, "copied-meep")
+
+	err := os.WriteFile(src, []byte(nil), 0644)
+	c.Assert(err, IsNil)
+
+	err = osutil.CopyFile(src, dst, osutil.CopyFlagPreserveAll|osutil.CopyFlagReflink)
+	c.Assert(err, IsNil)
+
+	c.Check(mocked.Calls(), DeepEquals, [][]string{
+		{"cp", "-av", "--reflink=auto", src, dst},
+	})
+}
+
 func (s *cpSuite) TestCopyPreserveAllSyncCpFailure(c *C) {
 	dir := c.MkDir()
 	mocked := testutil.MockCommand(c, "cp", "echo OUCH: cp failed.;exit 42").Also("sync", "")
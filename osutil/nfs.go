@@ -33,3 +33,26 @@ func MockIsHomeUsingNFS(new func() (bool, error)) (restore func()) {
 		isHomeUsingNFS = old
 	}
 }
+
+// IsUsingRemoteFilesystem returns true if the filesystem holding the given
+// absolute path, or any filesystem mounted beneath it, is a network
+// filesystem or autofs.
+func IsUsingRemoteFilesystem(path string) (bool, error) {
+	return isUsingRemoteFilesystem(path)
+}
+
+// MountedFilesystemType returns the type of the filesystem holding the given
+// absolute path, such as the home directory of a user, as currently mounted.
+func MountedFilesystemType(path string) (string, error) {
+	return mountedFilesystemType(path)
+}
+
+// MockMountedFilesystemType mocks the real implementation of
+// osutil.MountedFilesystemType.
+func MockMountedFilesystemType(new func(path string) (string, error)) (restore func()) {
+	old := mountedFilesystemType
+	mountedFilesystemType = new
+	return func() {
+		mountedFilesystemType = old
+	}
+}
@@ -23,3 +23,18 @@ package osutil
 var isHomeUsingNFS = func() (bool, error) {
 	return false, ErrDarwin
 }
+
+// mountedFilesystemType is not implemented on darwin
+var mountedFilesystemType = func(path string) (string, error) {
+	return "", ErrDarwin
+}
+
+// isUsingRemoteFilesystem is not implemented on darwin
+var isUsingRemoteFilesystem = func(path string) (bool, error) {
+	return false, ErrDarwin
+}
+
+// IsRemoteFilesystem is not implemented on darwin
+func IsRemoteFilesystem(fsType string) bool {
+	return false
+}
@@ -21,15 +21,38 @@ package osutil
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 )
 
 var etcFstab = "/etc/fstab"
 
-// isHomeUsingNFS returns true if NFS mounts are defined or mounted under /home.
+// remoteFilesystems lists the types of filesystems that are not local, and
+// are therefore not transparent to AppArmor. autofs is included as it is
+// usually used to mount home directories from the network on demand.
+var remoteFilesystems = map[string]bool{
+	"nfs":    true,
+	"nfs4":   true,
+	"autofs": true,
+	"cifs":   true,
+	"smb3":   true,
+}
+
+// IsRemoteFilesystem returns true if the given filesystem type is that of a
+// network filesystem, or of autofs.
+func IsRemoteFilesystem(fsType string) bool {
+	return remoteFilesystems[fsType]
+}
+
+func isUnderHome(dir string) bool {
+	return strings.HasPrefix(dir, "/home/") || dir == "/home"
+}
+
+// isHomeUsingNFS returns true if NFS, CIFS or autofs mounts are defined or
+// mounted under /home.
 //
 // Internally /proc/self/mountinfo and /etc/fstab are interrogated (for current
-// and possible mounted filesystems).  If either of those describes NFS
+// and possible mounted filesystems).  If either of those describes a remote
 // filesystem mounted under or beneath /home/ then the return value is true.
 var isHomeUsingNFS = func() (bool, error) {
 	mountinfo, err := LoadMountInfo()
@@ -37,7 +60,7 @@ var isHomeUsingNFS = func() (bool, error) {
 		return false, fmt.Errorf("cannot parse mountinfo: %s", err)
 	}
 	for _, entry := range mountinfo {
-		if (entry.FsType == "nfs4" || entry.FsType == "nfs" || entry.FsType == "autofs") && (strings.HasPrefix(entry.MountDir, "/home/") || entry.MountDir == "/home") {
+		if IsRemoteFilesystem(entry.FsType) && isUnderHome(entry.MountDir) {
 			return true, nil
 		}
 	}
@@ -46,9 +69,58 @@ var isHomeUsingNFS = func() (bool, error) {
 		return false, fmt.Errorf("cannot parse %s: %s", etcFstab, err)
 	}
 	for _, entry := range fstab.Entries {
-		if (entry.Type == "nfs4" || entry.Type == "nfs") && (strings.HasPrefix(entry.Dir, "/home/") || entry.Dir == "/home") {
+		if entry.Type != "autofs" && IsRemoteFilesystem(entry.Type) && isUnderHome(entry.Dir) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isUsingRemoteFilesystem returns true if the filesystem holding the given
+// absolute path, or any filesystem mounted beneath it, is remote.
+var isUsingRemoteFilesystem = func(path string) (bool, error) {
+	fsType, err := mountedFilesystemType(path)
+	if err != nil {
+		return false, err
+	}
+	if IsRemoteFilesystem(fsType) {
+		return true, nil
+	}
+	mountinfo, err := LoadMountInfo()
+	if err != nil {
+		return false, fmt.Errorf("cannot parse mountinfo: %s", err)
+	}
+	path = filepath.Clean(path)
+	for _, entry := range mountinfo {
+		if IsRemoteFilesystem(entry.FsType) && strings.HasPrefix(entry.MountDir, path+"/") {
 			return true, nil
 		}
 	}
 	return false, nil
 }
+
+// mountedFilesystemType returns the type of the filesystem holding the given
+// absolute path, as currently mounted.
+var mountedFilesystemType = func(path string) (string, error) {
+	mountinfo, err := LoadMountInfo()
+	if err != nil {
+		return "", fmt.Errorf("cannot parse mountinfo: %s", err)
+	}
+	path = filepath.Clean(path)
+	var fsType string
+	longest := -1
+	for _, entry := range mountinfo {
+		dir := entry.MountDir
+		if dir != "/" && dir != path && !strings.HasPrefix(path, dir+"/") {
+			continue
+		}
+		// later mounts over the same directory shadow earlier ones
+		if len(dir) >= longest {
+			fsType, longest = entry.FsType, len(dir)
+		}
+	}
+	if longest < 0 {
+		return "", fmt.Errorf("cannot find the filesystem of %q", path)
+	}
+	return fsType, nil
+}
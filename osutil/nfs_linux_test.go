@@ -80,6 +80,15 @@ func (s *nfsSuite) TestIsHomeUsingNFS(c *C) {
 		// autofs that is mounted at /home.
 		mountinfo: "137 29 0:50 / /home rw,relatime shared:87 - autofs /etc/auto.master.d/home rw,fd=7,pgrp=22588,timeout=300,minproto=5,maxproto=5,indirect,pipe_ino=173399",
 		nfs:       true,
+	}, {
+		// CIFS currently mounted or that may be mounted under /home is recognized.
+		mountinfo: "1074 28 0:59 / /home/zyga rw,relatime shared:342 - cifs //server/zyga rw,vers=3.1.1,cache=strict,username=zyga,uid=1000",
+		nfs:       true,
+	}, {
+		fstab: "//server/homes /home cifs credentials=/etc/cifs 0 0",
+		nfs:   true,
+	}, {
+		fstab: "//server/homes /mnt/homes smb3 credentials=/etc/cifs 0 0",
 	}}
 	for _, tc := range cases {
 		restore := osutil.MockMountInfo(tc.mountinfo)
@@ -96,3 +105,73 @@ func (s *nfsSuite) TestIsHomeUsingNFS(c *C) {
 		c.Assert(nfs, Equals, tc.nfs)
 	}
 }
+
+func (s *nfsSuite) TestIsRemoteFilesystem(c *C) {
+	for _, fsType := range []string{"nfs", "nfs4", "autofs", "cifs", "smb3"} {
+		c.Check(osutil.IsRemoteFilesystem(fsType), Equals, true, Commentf(fsType))
+	}
+	for _, fsType := range []string{"ext4", "btrfs", "tmpfs", ""} {
+		c.Check(osutil.IsRemoteFilesystem(fsType), Equals, false, Commentf(fsType))
+	}
+}
+
+func (s *nfsSuite) TestMountedFilesystemType(c *C) {
+	restore := osutil.MockMountInfo(`26 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+137 26 0:50 / /home rw,relatime shared:87 - autofs /etc/auto.master.d/home rw,fd=7
+1074 137 0:59 / /home/zyga rw,relatime shared:342 - nfs4 localhost:/srv/zyga rw,vers=4.2
+1075 26 0:60 / /home2 rw,relatime shared:343 - ext4 /dev/sdb1 rw
+1076 1075 0:61 / /home2 rw,relatime shared:344 - cifs //server/homes rw`)
+	defer restore()
+
+	for _, tc := range []struct {
+		path, fsType string
+	}{
+		{"/home/zyga", "nfs4"},
+		{"/home/zyga/", "nfs4"},
+		{"/home/zyga/nfs", "nfs4"},
+		{"/home/zygaz", "autofs"},
+		{"/home", "autofs"},
+		{"/home2/other", "cifs"},
+		{"/root", "ext4"},
+	} {
+		fsType, err := osutil.MountedFilesystemType(tc.path)
+		c.Assert(err, IsNil)
+		c.Check(fsType, Equals, tc.fsType, Commentf(tc.path))
+	}
+}
+
+func (s *nfsSuite) TestIsUsingRemoteFilesystem(c *C) {
+	restore := osutil.MockMountInfo(`26 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+1074 26 0:59 / /srv/homes/zyga rw,relatime shared:342 - nfs4 localhost:/srv/zyga rw,vers=4.2
+1075 26 0:60 / /home2 rw,relatime shared:343 - cifs //server/homes rw`)
+	defer restore()
+
+	for _, tc := range []struct {
+		path   string
+		remote bool
+	}{
+		{"/srv/homes", true},
+		{"/srv/homes/zyga", true},
+		{"/srv/homes/zygaz", false},
+		{"/home2", true},
+		{"/home2/zyga", true},
+		{"/home", false},
+		{"/srv/home", false},
+	} {
+		remote, err := osutil.IsUsingRemoteFilesystem(tc.path)
+		c.Assert(err, IsNil)
+		c.Check(remote, Equals, tc.remote, Commentf(tc.path))
+	}
+}
+
+func (s *nfsSuite) TestMountedFilesystemTypeErrors(c *C) {
+	restore := osutil.MockMountInfo("bad syntax")
+	defer restore()
+	_, err := osutil.MountedFilesystemType("/home/zyga")
+	c.Check(err, ErrorMatches, "cannot parse mountinfo:.*")
+
+	restore = osutil.MockMountInfo("1074 28 0:59 / /mnt rw,relatime shared:342 - ext4 /dev/sdb1 rw")
+	defer restore()
+	_, err = osutil.MountedFilesystemType("/home/zyga")
+	c.Check(err, ErrorMatches, `cannot find the filesystem of "/home/zyga"`)
+}
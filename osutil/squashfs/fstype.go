@@ -23,7 +23,9 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/strutil"
 )
 
 var needsFuseImpl = func() bool {
@@ -62,6 +64,37 @@ func NeedsFuse() bool {
 	return needsFuseImpl()
 }
 
+var supportsZstdImpl = func() bool {
+	if NeedsFuse() {
+		// squashfuse handles the decompression itself
+		return true
+	}
+	// zstd support for squashfs was added in Linux 4.14; a kernel
+	// version looks like "5.15.0-91-generic"
+	kver := strings.SplitN(osutil.KernelVersion(), "-", 2)[0]
+	cmp, err := strutil.VersionCompare(kver, "4.14")
+	if err != nil {
+		logger.Noticef("cannot check kernel support for zstd compressed squashfs: %v", err)
+		return false
+	}
+	return cmp >= 0
+}
+
+// MockSupportsZstd is exported so SupportsZstd can be overridden by testing.
+func MockSupportsZstd(r bool) func() {
+	oldSupportsZstdImpl := supportsZstdImpl
+	supportsZstdImpl = func() bool {
+		return r
+	}
+	return func() { supportsZstdImpl = oldSupportsZstdImpl }
+}
+
+// SupportsZstd returns true if snaps compressed with zstd can be mounted
+// on the given system
+func SupportsZstd() bool {
+	return supportsZstdImpl()
+}
+
 // StandardOptions returns base squashfs options.
 func StandardOptions() []string {
 	return []string{"ro", "x-gdu.hide", "x-gvfs-hide"}
@@ -20,17 +20,19 @@ package aspectstate
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/snapcore/snapd/aspects"
-	"github.com/snapcore/snapd/overlord/aspectstate/aspecttest"
 	"github.com/snapcore/snapd/overlord/state"
 )
 
 // SetAspect finds the aspect identified by the account, bundleName and aspect
 // and sets the specified field to the supplied value in the provided matching databag.
 func SetAspect(databag aspects.DataBag, account, bundleName, aspect, field string, value interface{}) error {
-	accPatterns := aspecttest.MockWifiSetupAspect()
-	schema := aspects.NewJSONSchema()
+	accPatterns, schema, err := bundleDefinition(account, bundleName)
+	if err != nil {
+		return err
+	}
 
 	aspectBundle, err := aspects.NewAspectBundle(account, bundleName, accPatterns, schema)
 	if err != nil {
@@ -60,8 +62,10 @@ func SetAspect(databag aspects.DataBag, account, bundleName, aspect, field strin
 // and returns the specified field value from the provided matching databag
 // through the value output parameter.
 func GetAspect(databag aspects.DataBag, account, bundleName, aspect, field string) (interface{}, error) {
-	accPatterns := aspecttest.MockWifiSetupAspect()
-	schema := aspects.NewJSONSchema()
+	accPatterns, schema, err := bundleDefinition(account, bundleName)
+	if err != nil {
+		return nil, err
+	}
 
 	aspectBundle, err := aspects.NewAspectBundle(account, bundleName, accPatterns, schema)
 	if err != nil {
@@ -88,14 +92,50 @@ func GetAspect(databag aspects.DataBag, account, bundleName, aspect, field strin
 	return result, nil
 }
 
+// CompleteAspect finds the aspect identified by the account, bundleName and
+// aspect and returns the requests that complete the partial dotted request.
+func CompleteAspect(account, bundleName, aspect, partial string) ([]string, error) {
+	accPatterns, schema, err := bundleDefinition(account, bundleName)
+	if err != nil {
+		return nil, err
+	}
+
+	aspectBundle, err := aspects.NewAspectBundle(account, bundleName, accPatterns, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	asp := aspectBundle.Aspect(aspect)
+	if asp == nil {
+		return nil, &aspects.NotFoundError{
+			Account:    account,
+			BundleName: bundleName,
+			Aspect:     aspect,
+			Operation:  "complete",
+			Request:    partial,
+			Cause:      "aspect not found",
+		}
+	}
+
+	return asp.CompleteRequest(partial)
+}
+
 // NewTransaction returns a transaction configured to read and write databags
 // from state as needed.
 func NewTransaction(st *state.State, account, bundleName string) (*aspects.Transaction, error) {
-	schema := aspects.NewJSONSchema()
+	_, schema, err := bundleDefinition(account, bundleName)
+	if err != nil {
+		return nil, err
+	}
+
 	getter := bagGetter(st, account, bundleName)
 	setter := func(bag aspects.JSONDataBag) error {
 		return updateDatabags(st, account, bundleName, bag)
 	}
+	if sysBundle := getSystemBundle(account, bundleName); sysBundle != nil {
+		getter = systemBagGetter(st, bundleName, sysBundle)
+		setter = systemBagSetter(st, bundleName, sysBundle)
+	}
 
 	tx, err := aspects.NewTransaction(getter, setter, schema)
 	if err != nil {
@@ -105,6 +145,66 @@ func NewTransaction(st *state.State, account, bundleName string) (*aspects.Trans
 	return tx, nil
 }
 
+// SensitiveValues returns the values stored in the aspect databags that the
+// schemas of their bundles mark as sensitive.
+func SensitiveValues(st *state.State) ([]string, error) {
+	var databags map[string]map[string]aspects.JSONDataBag
+	if err := st.Get("aspect-databags", &databags); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+
+	bundles := make(map[string][]string)
+	for account, accountBags := range databags {
+		for bundleName := range accountBags {
+			bundles[account] = append(bundles[account], bundleName)
+		}
+	}
+	// system bundles are backed by the system's configuration even if
+	// nothing was ever stored in their databags
+	for bundleName := range systemBundles {
+		if databags[SystemAccount][bundleName] == nil {
+			bundles[SystemAccount] = append(bundles[SystemAccount], bundleName)
+		}
+	}
+
+	var values []string
+	for account, bundleNames := range bundles {
+		for _, bundleName := range bundleNames {
+			_, schema, err := bundleDefinition(account, bundleName)
+			if err != nil {
+				return nil, err
+			}
+
+			sensitiveSchema, ok := schema.(interface {
+				SensitiveValues(raw []byte) ([]string, error)
+			})
+			if !ok {
+				continue
+			}
+
+			getter := bagGetter(st, account, bundleName)
+			if sysBundle := getSystemBundle(account, bundleName); sysBundle != nil {
+				getter = systemBagGetter(st, bundleName, sysBundle)
+			}
+			databag, err := getter()
+			if err != nil {
+				return nil, err
+			}
+			data, err := databag.Data()
+			if err != nil {
+				return nil, err
+			}
+
+			bundleValues, err := sensitiveSchema.SensitiveValues(data)
+			if err != nil {
+				return nil, fmt.Errorf("cannot get sensitive values of %s/%s: %v", account, bundleName, err)
+			}
+			values = append(values, bundleValues...)
+		}
+	}
+	return values, nil
+}
+
 func bagGetter(st *state.State, account, bundleName string) aspects.DatabagRead {
 	return func() (aspects.JSONDataBag, error) {
 		databag, err := getDatabag(st, account, bundleName)
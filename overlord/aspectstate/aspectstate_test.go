@@ -27,9 +27,12 @@ import (
 	"github.com/snapcore/snapd/overlord"
 	"github.com/snapcore/snapd/overlord/aspectstate"
 	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/testutil"
 )
 
 type aspectTestSuite struct {
+	testutil.BaseTest
+
 	state *state.State
 }
 
@@ -37,8 +40,13 @@ var _ = Suite(&aspectTestSuite{})
 
 func Test(t *testing.T) { TestingT(t) }
 
-func (s *aspectTestSuite) SetUpTest(_ *C) {
+func (s *aspectTestSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
 	s.state = overlord.Mock().State()
+
+	s.AddCleanup(aspectstate.MockSystemConfig(func(*state.State, string) (interface{}, error) {
+		return nil, nil
+	}))
 }
 
 func (s *aspectTestSuite) TestGetAspect(c *C) {
@@ -91,6 +99,20 @@ func (s *aspectTestSuite) TestSetNotFound(c *C) {
 	c.Assert(err, ErrorMatches, `cannot set "foo" in aspect system/network/other-aspect: aspect not found`)
 }
 
+func (s *aspectTestSuite) TestCompleteAspect(c *C) {
+	completions, err := aspectstate.CompleteAspect("system", "network", "wifi-setup", "ss")
+	c.Assert(err, IsNil)
+	c.Check(completions, DeepEquals, []string{"ssid", "ssids"})
+
+	completions, err = aspectstate.CompleteAspect("system", "settings", "hostname", "")
+	c.Assert(err, IsNil)
+	c.Check(completions, DeepEquals, []string{"hostname"})
+
+	_, err = aspectstate.CompleteAspect("system", "network", "other-aspect", "ss")
+	c.Assert(err, FitsTypeOf, &aspects.NotFoundError{})
+	c.Assert(err, ErrorMatches, `cannot complete "ss" in aspect system/network/other-aspect: aspect not found`)
+}
+
 func (s *aspectTestSuite) TestUnsetAspect(c *C) {
 	databag := aspects.NewJSONDataBag()
 	err := aspectstate.SetAspect(databag, "system", "network", "wifi-setup", "ssid", "foo")
@@ -183,3 +205,293 @@ func (s *aspectTestSuite) TestNewTransactionNoState(c *C) {
 		c.Assert(value, Equals, "bar")
 	}
 }
+
+func (s *aspectTestSuite) mockSystemConfig(options map[string]interface{}) {
+	s.AddCleanup(aspectstate.MockSystemConfig(func(_ *state.State, option string) (interface{}, error) {
+		return options[option], nil
+	}))
+}
+
+func (s *aspectTestSuite) mockSystemNetplan(cfg map[string]interface{}) {
+	s.mockSystemConfig(map[string]interface{}{"system.network.netplan": cfg})
+}
+
+func (s *aspectTestSuite) TestGetNetplanFromSystem(c *C) {
+	s.mockSystemNetplan(map[string]interface{}{
+		"network": map[string]interface{}{
+			"version": 2,
+			"ethernets": map[string]interface{}{
+				"eth0": map[string]interface{}{"dhcp4": true},
+			},
+		},
+	})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tx, err := aspectstate.NewTransaction(s.state, "system", "network")
+	c.Assert(err, IsNil)
+
+	res, err := aspectstate.GetAspect(tx, "system", "network", "netplan", "ethernets.eth0")
+	c.Assert(err, IsNil)
+	c.Check(res, DeepEquals, map[string]interface{}{
+		"ethernets.eth0": map[string]interface{}{"dhcp4": true},
+	})
+
+	res, err = aspectstate.GetAspect(tx, "system", "network", "netplan", "version")
+	c.Assert(err, IsNil)
+	c.Check(res, DeepEquals, map[string]interface{}{"version": float64(2)})
+}
+
+func (s *aspectTestSuite) TestCommitTransactionAppliesNetplan(c *C) {
+	s.mockSystemNetplan(map[string]interface{}{
+		"network": map[string]interface{}{
+			"ethernets": map[string]interface{}{
+				"eth0": map[string]interface{}{"dhcp4": true},
+			},
+		},
+	})
+
+	var patch map[string]interface{}
+	s.AddCleanup(aspectstate.MockConfigstateConfigure(func(st *state.State, snapName string, p map[string]interface{}, flags int) *state.TaskSet {
+		c.Check(snapName, Equals, "core")
+		c.Check(flags, Equals, 0)
+		patch = p
+		return state.NewTaskSet(st.NewTask("run-hook", "mock configure"))
+	}))
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tx, err := aspectstate.NewTransaction(s.state, "system", "network")
+	c.Assert(err, IsNil)
+
+	err = aspectstate.SetAspect(tx, "system", "network", "netplan", "ethernets.eth1", map[string]interface{}{"dhcp4": false})
+	c.Assert(err, IsNil)
+
+	ts, err := aspectstate.CommitTransaction(s.state, "system", "network", tx)
+	c.Assert(err, IsNil)
+	c.Assert(ts, NotNil)
+	c.Check(ts.Tasks(), HasLen, 1)
+	c.Check(patch, DeepEquals, map[string]interface{}{
+		"system.network.netplan": map[string]interface{}{
+			"network": map[string]interface{}{
+				"version": 2,
+				"ethernets": map[string]interface{}{
+					"eth0": map[string]interface{}{"dhcp4": true},
+					"eth1": map[string]interface{}{"dhcp4": false},
+				},
+			},
+		},
+	})
+
+	// the netplan configuration isn't duplicated in the stored databag
+	var databags map[string]map[string]aspects.JSONDataBag
+	err = s.state.Get("aspect-databags", &databags)
+	c.Assert(err, IsNil)
+	_, err = databags["system"]["network"].Get("netplan")
+	c.Check(err, FitsTypeOf, aspects.PathError(""))
+}
+
+func (s *aspectTestSuite) TestCommitTransactionNoNetplanChange(c *C) {
+	s.mockSystemNetplan(map[string]interface{}{
+		"network": map[string]interface{}{
+			"version":  2,
+			"renderer": "networkd",
+		},
+	})
+	s.AddCleanup(aspectstate.MockConfigstateConfigure(func(*state.State, string, map[string]interface{}, int) *state.TaskSet {
+		c.Fatalf("unexpected call to configure")
+		return nil
+	}))
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tx, err := aspectstate.NewTransaction(s.state, "system", "network")
+	c.Assert(err, IsNil)
+
+	err = aspectstate.SetAspect(tx, "system", "network", "wifi-setup", "ssid", "foo")
+	c.Assert(err, IsNil)
+	err = aspectstate.SetAspect(tx, "system", "network", "netplan", "renderer", "networkd")
+	c.Assert(err, IsNil)
+
+	ts, err := aspectstate.CommitTransaction(s.state, "system", "network", tx)
+	c.Assert(err, IsNil)
+	c.Check(ts, IsNil)
+
+	var databags map[string]map[string]aspects.JSONDataBag
+	err = s.state.Get("aspect-databags", &databags)
+	c.Assert(err, IsNil)
+	value, err := databags["system"]["network"].Get("wifi.ssid")
+	c.Assert(err, IsNil)
+	c.Check(value, Equals, "foo")
+}
+
+func (s *aspectTestSuite) TestCommitTransactionInvalidNetplan(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tx, err := aspectstate.NewTransaction(s.state, "system", "network")
+	c.Assert(err, IsNil)
+
+	err = aspectstate.SetAspect(tx, "system", "network", "netplan", "renderer", "foo")
+	c.Assert(err, IsNil)
+
+	ts, err := aspectstate.CommitTransaction(s.state, "system", "network", tx)
+	c.Assert(err, ErrorMatches, `cannot accept netplan configuration: .*string "foo" is not one of the allowed choices`)
+	c.Check(ts, IsNil)
+}
+
+func (s *aspectTestSuite) TestCommitTransactionOtherBundle(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tx, err := aspectstate.NewTransaction(s.state, "acme", "network")
+	c.Assert(err, IsNil)
+
+	err = aspectstate.SetAspect(tx, "acme", "network", "wifi-setup", "ssid", "foo")
+	c.Assert(err, IsNil)
+
+	ts, err := aspectstate.CommitTransaction(s.state, "acme", "network", tx)
+	c.Assert(err, IsNil)
+	c.Check(ts, IsNil)
+
+	var databags map[string]map[string]aspects.JSONDataBag
+	err = s.state.Get("aspect-databags", &databags)
+	c.Assert(err, IsNil)
+	value, err := databags["acme"]["network"].Get("wifi.ssid")
+	c.Assert(err, IsNil)
+	c.Check(value, Equals, "foo")
+}
+
+func (s *aspectTestSuite) TestGetSettingsFromSystem(c *C) {
+	s.mockSystemConfig(map[string]interface{}{
+		"system.hostname": "my-device",
+		"system.timezone": "Europe/Lisbon",
+		"system.locale":   "pt_PT.UTF-8",
+	})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tx, err := aspectstate.NewTransaction(s.state, "system", "settings")
+	c.Assert(err, IsNil)
+
+	for setting, value := range map[string]string{
+		"hostname": "my-device",
+		"timezone": "Europe/Lisbon",
+		"locale":   "pt_PT.UTF-8",
+	} {
+		res, err := aspectstate.GetAspect(tx, "system", "settings", setting, setting)
+		c.Assert(err, IsNil)
+		c.Check(res, DeepEquals, map[string]interface{}{setting: value})
+	}
+}
+
+func (s *aspectTestSuite) TestCommitTransactionAppliesSettings(c *C) {
+	s.mockSystemConfig(map[string]interface{}{
+		"system.hostname": "my-device",
+		"system.timezone": "UTC",
+		"system.locale":   "C.UTF-8",
+	})
+
+	var patch map[string]interface{}
+	s.AddCleanup(aspectstate.MockConfigstateConfigure(func(st *state.State, snapName string, p map[string]interface{}, flags int) *state.TaskSet {
+		c.Check(snapName, Equals, "core")
+		patch = p
+		return state.NewTaskSet(st.NewTask("run-hook", "mock configure"))
+	}))
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tx, err := aspectstate.NewTransaction(s.state, "system", "settings")
+	c.Assert(err, IsNil)
+
+	err = aspectstate.SetAspect(tx, "system", "settings", "hostname", "hostname", "my-device")
+	c.Assert(err, IsNil)
+	err = aspectstate.SetAspect(tx, "system", "settings", "timezone", "timezone", "Europe/Berlin")
+	c.Assert(err, IsNil)
+	err = aspectstate.SetAspect(tx, "system", "settings", "locale", "locale", "de_DE.UTF-8")
+	c.Assert(err, IsNil)
+
+	ts, err := aspectstate.CommitTransaction(s.state, "system", "settings", tx)
+	c.Assert(err, IsNil)
+	c.Assert(ts, NotNil)
+	// only the changed settings are applied
+	c.Check(patch, DeepEquals, map[string]interface{}{
+		"system.timezone": "Europe/Berlin",
+		"system.locale":   "de_DE.UTF-8",
+	})
+}
+
+func (s *aspectTestSuite) TestCommitTransactionInvalidSettings(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	for _, tc := range []struct {
+		setting string
+		value   string
+		err     string
+	}{
+		{"hostname", "-foo", `.*string "-foo" is not a valid hostname`},
+		{"timezone", "no/triple/slash/", `.*string "no/triple/slash/" is not a valid timezone`},
+		{"locale", "en_us", `.*string "en_us" is not a valid locale`},
+	} {
+		tx, err := aspectstate.NewTransaction(s.state, "system", "settings")
+		c.Assert(err, IsNil)
+
+		// the value is validated when written through the aspect
+		err = aspectstate.SetAspect(tx, "system", "settings", tc.setting, tc.setting, tc.value)
+		c.Check(err, ErrorMatches, tc.err)
+
+		// and the whole databag when the transaction is committed
+		err = tx.Set(tc.setting, tc.value)
+		c.Assert(err, IsNil)
+
+		ts, err := aspectstate.CommitTransaction(s.state, "system", "settings", tx)
+		c.Check(err, ErrorMatches, tc.err)
+		c.Check(ts, IsNil)
+	}
+}
+
+func (s *aspectTestSuite) TestIsSystemBundle(c *C) {
+	c.Check(aspectstate.IsSystemBundle("system", "network"), Equals, true)
+	c.Check(aspectstate.IsSystemBundle("system", "settings"), Equals, true)
+	c.Check(aspectstate.IsSystemBundle("system", "other"), Equals, false)
+	c.Check(aspectstate.IsSystemBundle("acme", "network"), Equals, false)
+}
+
+func (s *aspectTestSuite) TestSensitiveValues(c *C) {
+	s.mockSystemNetplan(map[string]interface{}{
+		"network": map[string]interface{}{
+			"version": 2,
+			"ethernets": map[string]interface{}{
+				"eth0": map[string]interface{}{"dhcp4": true},
+			},
+			"wifis": map[string]interface{}{
+				"wlan0": map[string]interface{}{
+					"access-points": map[string]interface{}{
+						"home": map[string]interface{}{"password": "hunter2"},
+					},
+				},
+			},
+		},
+	})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	// bundles without sensitive entries are skipped
+	tx, err := aspectstate.NewTransaction(s.state, "acc", "bundle")
+	c.Assert(err, IsNil)
+	err = aspectstate.SetAspect(tx, "acc", "bundle", "wifi-setup", "ssid", "foo")
+	c.Assert(err, IsNil)
+	_, err = aspectstate.CommitTransaction(s.state, "acc", "bundle", tx)
+	c.Assert(err, IsNil)
+
+	values, err := aspectstate.SensitiveValues(s.state)
+	c.Assert(err, IsNil)
+	c.Check(values, DeepEquals, []string{"hunter2"})
+}
@@ -0,0 +1,35 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package aspectstate
+
+import (
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+func MockSystemConfig(f func(st *state.State, option string) (interface{}, error)) (restore func()) {
+	old := systemConfig
+	systemConfig = f
+	return func() { systemConfig = old }
+}
+
+func MockConfigstateConfigure(f func(st *state.State, snapName string, patch map[string]interface{}, flags int) *state.TaskSet) (restore func()) {
+	old := configstateConfigure
+	configstateConfigure = f
+	return func() { configstateConfigure = old }
+}
@@ -0,0 +1,370 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package aspectstate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/snapcore/snapd/aspects"
+	"github.com/snapcore/snapd/overlord/aspectstate/aspecttest"
+	"github.com/snapcore/snapd/overlord/configstate"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+const (
+	// SystemAccount is the account of the aspect bundles built into snapd.
+	SystemAccount = "system"
+	// NetworkBundle is the built-in bundle that exposes the system's
+	// network configuration.
+	NetworkBundle = "network"
+	// SettingsBundle is the built-in bundle that exposes the system's
+	// hostname, timezone and locale.
+	SettingsBundle = "settings"
+)
+
+// netplanSchemaDef constrains the netplan settings that can be written through
+// the system/network bundle. The settings of each interface are validated by
+// netplan itself when they are applied.
+const netplanSchemaDef = `{
+	"schema": {
+		"network": {
+			"schema": {
+				"version": "int",
+				"renderer": {
+					"type": "string",
+					"choices": ["networkd", "NetworkManager"]
+				},
+				"ethernets": {"type": "map", "values": "any"},
+				"wifis": {"type": "map", "values": "any", "sensitive": true},
+				"bridges": {"type": "map", "values": "any"},
+				"bonds": {"type": "map", "values": "any"},
+				"vlans": {"type": "map", "values": "any"},
+				"tunnels": {"type": "map", "values": "any"},
+				"vrfs": {"type": "map", "values": "any"},
+				"modems": {"type": "map", "values": "any"},
+				"nm-devices": {"type": "map", "values": "any"}
+			}
+		}
+	}
+}`
+
+// settingsSchemaDef constrains the values of the system/settings bundle.
+const settingsSchemaDef = `{
+	"schema": {
+		"hostname": {"type": "string", "format": "hostname"},
+		"timezone": {"type": "string", "format": "timezone"},
+		"locale": {"type": "string", "format": "locale"}
+	}
+}`
+
+// netplanVersion is the netplan configuration format version written by snapd.
+const netplanVersion = 2
+
+var configstateConfigure = configstate.Configure
+
+// systemConfig returns the value of the core configuration option as
+// currently used by the system, or nil if it isn't set.
+var systemConfig = func(st *state.State, option string) (interface{}, error) {
+	var value interface{}
+	tr := config.NewTransaction(st)
+	if err := tr.Get("core", option, &value); err != nil && !config.IsNoOption(err) {
+		return nil, err
+	}
+	return value, nil
+}
+
+// systemBundle is a bundle built into snapd whose entries are backed by the
+// system's core configuration.
+type systemBundle struct {
+	aspects func() map[string]interface{}
+	schema  func() (aspects.Schema, error)
+
+	// options maps the top-level databag entries to the core configuration
+	// options backing them.
+	options map[string]string
+
+	// complete fills in any values that must be set when an entry is
+	// written to the system.
+	complete func(entry string, value interface{})
+}
+
+var systemBundles = map[string]*systemBundle{
+	NetworkBundle: {
+		aspects: systemNetworkAspects,
+		schema: func() (aspects.Schema, error) {
+			return newEntrySchema("netplan", netplanSchemaDef)
+		},
+		options:  map[string]string{"netplan": "system.network.netplan"},
+		complete: completeNetplanConfig,
+	},
+	SettingsBundle: {
+		aspects: systemSettingsAspects,
+		schema: func() (aspects.Schema, error) {
+			return aspects.ParseSchema([]byte(settingsSchemaDef))
+		},
+		options: map[string]string{
+			"hostname": "system.hostname",
+			"timezone": "system.timezone",
+			"locale":   "system.locale",
+		},
+	},
+}
+
+// IsSystemBundle returns whether the bundle is built into snapd and backed by
+// the system's configuration.
+func IsSystemBundle(account, bundleName string) bool {
+	return getSystemBundle(account, bundleName) != nil
+}
+
+func getSystemBundle(account, bundleName string) *systemBundle {
+	if account != SystemAccount {
+		return nil
+	}
+	return systemBundles[bundleName]
+}
+
+// systemNetworkAspects returns the access patterns of the system/network
+// bundle. The netplan aspect maps onto the "netplan" entry of the databag,
+// which is backed by the system's netplan configuration.
+func systemNetworkAspects() map[string]interface{} {
+	accPatterns := aspecttest.MockWifiSetupAspect()
+
+	var netplanPatterns []map[string]string
+	for _, section := range []string{"ethernets", "wifis", "bridges", "bonds", "vlans", "tunnels", "vrfs", "modems", "nm-devices"} {
+		netplanPatterns = append(netplanPatterns, map[string]string{
+			"request": section + ".{name}",
+			"storage": "netplan.network." + section + ".{name}",
+		})
+	}
+	netplanPatterns = append(netplanPatterns,
+		map[string]string{"request": "renderer", "storage": "netplan.network.renderer"},
+		map[string]string{"request": "version", "storage": "netplan.network.version", "access": "read"},
+	)
+	accPatterns["netplan"] = netplanPatterns
+
+	return accPatterns
+}
+
+// systemSettingsAspects returns the access patterns of the system/settings
+// bundle, with one aspect per setting.
+func systemSettingsAspects() map[string]interface{} {
+	accPatterns := make(map[string]interface{})
+	for _, setting := range []string{"hostname", "timezone", "locale"} {
+		accPatterns[setting] = []map[string]string{
+			{"request": setting, "storage": setting},
+		}
+	}
+	return accPatterns
+}
+
+// completeNetplanConfig sets the netplan format version if the configuration
+// doesn't specify one.
+func completeNetplanConfig(entry string, value interface{}) {
+	cfg, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if network, ok := cfg["network"].(map[string]interface{}); ok {
+		if _, ok := network["version"]; !ok {
+			network["version"] = netplanVersion
+		}
+	}
+}
+
+// entrySchema validates one entry of a databag, leaving any other entries
+// unconstrained.
+type entrySchema struct {
+	entry  string
+	schema *aspects.StorageSchema
+}
+
+func newEntrySchema(entry, schemaDef string) (*entrySchema, error) {
+	schema, err := aspects.ParseSchema([]byte(schemaDef))
+	if err != nil {
+		return nil, err
+	}
+	return &entrySchema{entry: entry, schema: schema}, nil
+}
+
+// SensitiveValues returns the values of the entry that are marked as
+// sensitive by its schema.
+func (s *entrySchema) SensitiveValues(raw []byte) ([]string, error) {
+	var bag map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &bag); err != nil {
+		return nil, err
+	}
+
+	value, ok := bag[s.entry]
+	if !ok {
+		return nil, nil
+	}
+	return s.schema.SensitiveValues(value)
+}
+
+func (s *entrySchema) Validate(raw []byte) error {
+	var bag map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &bag); err != nil {
+		return err
+	}
+
+	value, ok := bag[s.entry]
+	if !ok {
+		return nil
+	}
+
+	if err := s.schema.Validate(value); err != nil {
+		return fmt.Errorf("cannot accept %s configuration: %w", s.entry, err)
+	}
+	return nil
+}
+
+// bundleDefinition returns the access patterns and schema for the named
+// bundle.
+func bundleDefinition(account, bundleName string) (map[string]interface{}, aspects.Schema, error) {
+	sysBundle := getSystemBundle(account, bundleName)
+	if sysBundle == nil {
+		return aspecttest.MockWifiSetupAspect(), aspects.NewJSONSchema(), nil
+	}
+
+	schema, err := sysBundle.schema()
+	if err != nil {
+		return nil, nil, fmt.Errorf("internal error: cannot parse schema of %s/%s: %v", account, bundleName, err)
+	}
+	return sysBundle.aspects(), schema, nil
+}
+
+// systemBagGetter reads the databag of a system bundle from state and fills
+// in the entries backed by the system's configuration.
+func systemBagGetter(st *state.State, bundleName string, sysBundle *systemBundle) aspects.DatabagRead {
+	getBag := bagGetter(st, SystemAccount, bundleName)
+	return func() (aspects.JSONDataBag, error) {
+		databag, err := getBag()
+		if err != nil {
+			return nil, err
+		}
+
+		for entry, option := range sysBundle.options {
+			value, err := systemConfig(st, option)
+			if err != nil {
+				return nil, fmt.Errorf("cannot get system configuration %q: %v", option, err)
+			}
+
+			// unset values are omitted
+			if value == nil || value == "" {
+				continue
+			}
+
+			if err := databag.Set(entry, value); err != nil {
+				return nil, err
+			}
+		}
+		return databag, nil
+	}
+}
+
+// systemBagSetter stores the databag of a system bundle in state. The entries
+// backed by the system's configuration are not stored since the system is
+// their source of truth.
+func systemBagSetter(st *state.State, bundleName string, sysBundle *systemBundle) aspects.DatabagWrite {
+	return func(bag aspects.JSONDataBag) error {
+		bag = bag.Copy()
+		for entry := range sysBundle.options {
+			if _, err := bag.Get(entry); err != nil {
+				continue
+			}
+			if err := bag.Set(entry, nil); err != nil {
+				return err
+			}
+		}
+		return updateDatabags(st, SystemAccount, bundleName, bag)
+	}
+}
+
+// CommitTransaction commits the changes in the transaction for the given
+// bundle. For bundles backed by the system's configuration, it also returns
+// the tasks that apply the committed changes to the system, or nil if there
+// is nothing to apply. The changes are applied through the core
+// configuration, e.g., netplan changes are tried and rolled back if the store
+// stops being reachable.
+func CommitTransaction(st *state.State, account, bundleName string, tx *aspects.Transaction) (*state.TaskSet, error) {
+	sysBundle := getSystemBundle(account, bundleName)
+	if sysBundle == nil {
+		return nil, tx.Commit()
+	}
+
+	newValues := make(map[string]interface{}, len(sysBundle.options))
+	oldValues := make(map[string]interface{}, len(sysBundle.options))
+	for entry, option := range sysBundle.options {
+		value, err := tx.Get(entry)
+		if err != nil && !errors.Is(err, aspects.PathError("")) {
+			return nil, err
+		}
+		newValues[entry] = value
+
+		oldValues[entry], err = systemConfig(st, option)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get system configuration %q: %v", option, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	patch := make(map[string]interface{})
+	for entry, option := range sysBundle.options {
+		newValue := newValues[entry]
+		if sysBundle.complete != nil {
+			sysBundle.complete(entry, newValue)
+		}
+
+		changed, err := configChanged(oldValues[entry], newValue)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			patch[option] = newValue
+		}
+	}
+
+	if len(patch) == 0 {
+		return nil, nil
+	}
+	return configstateConfigure(st, "core", patch, 0), nil
+}
+
+func configChanged(oldValue, newValue interface{}) (bool, error) {
+	if oldValue == nil || newValue == nil {
+		return oldValue != nil || newValue != nil, nil
+	}
+
+	oldData, err := json.Marshal(oldValue)
+	if err != nil {
+		return false, err
+	}
+	newData, err := json.Marshal(newValue)
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(oldData, newData), nil
+}
@@ -65,7 +65,51 @@ type RefreshAssertionsOptions struct {
 }
 
 // RefreshSnapDeclarations refetches all the current snap declarations and their prerequisites.
+// It warns about security-relevant changes of the publishers of the snaps.
 func RefreshSnapDeclarations(s *state.State, userID int, opts *RefreshAssertionsOptions) error {
+	if err := refreshSnapDeclarations(s, userID, opts); err != nil {
+		return err
+	}
+	return checkPublisherChanges(s)
+}
+
+// checkPublisherChanges records the publishers of the installed snaps and
+// warns if a snap changed publisher or if the validation status of its
+// publisher was downgraded.
+func checkPublisherChanges(s *state.State) error {
+	snapStates, err := snapstate.All(s)
+	if err != nil {
+		return err
+	}
+	for instanceName, snapst := range snapStates {
+		snapID := snapst.CurrentSideInfo().SnapID
+		if snapID == "" {
+			continue
+		}
+		publisher, err := PublisherStoreAccount(s, snapID)
+		if err != nil {
+			logger.Noticef("cannot check publisher of snap %q: %v", instanceName, err)
+			continue
+		}
+		change, err := snapstate.RecordPublisher(snapID, instanceName, publisher)
+		if err != nil {
+			return err
+		}
+		switch change {
+		case snapstate.PublisherChanged:
+			s.WarnfWithSeverity(state.WarningSeverityCritical, "snap %q changed publisher to %q", instanceName, publisher.Username)
+		case snapstate.PublisherDowngraded:
+			status := publisher.Validation
+			if status == "" {
+				status = "unproven"
+			}
+			s.WarnfWithSeverity(state.WarningSeverityCritical, "publisher %q of snap %q was downgraded to %s", publisher.Username, instanceName, status)
+		}
+	}
+	return nil
+}
+
+func refreshSnapDeclarations(s *state.State, userID int, opts *RefreshAssertionsOptions) error {
 	if opts == nil {
 		opts = &RefreshAssertionsOptions{}
 	}
@@ -1385,6 +1385,55 @@ func (s *assertMgrSuite) TestRefreshSnapDeclarationsNoStore(c *C) {
 	c.Check(a.(*asserts.SnapDeclaration).Revision(), Equals, 1)
 }
 
+func (s *assertMgrSuite) TestRefreshSnapDeclarationsPublisherDowngraded(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setModel(sysdb.GenericClassicModel())
+
+	snapDeclFoo := s.snapDecl(c, "foo", nil)
+	s.stateFromDecl(c, snapDeclFoo, "", snap.R(7))
+
+	err := assertstate.Add(s.state, s.storeSigning.StoreAccountKey(""))
+	c.Assert(err, IsNil)
+	err = assertstate.Add(s.state, s.dev1Acct)
+	c.Assert(err, IsNil)
+	err = assertstate.Add(s.state, snapDeclFoo)
+	c.Assert(err, IsNil)
+
+	updateAccount := func(revision, validation string) {
+		headers := s.dev1Acct.Headers()
+		headers["validation"] = validation
+		headers["revision"] = revision
+		acct, err := s.storeSigning.Sign(asserts.AccountType, headers, nil, "")
+		c.Assert(err, IsNil)
+		c.Assert(s.storeSigning.Add(acct), IsNil)
+	}
+
+	// the publisher is recorded when first seen
+	err = assertstate.RefreshSnapDeclarations(s.state, 0, nil)
+	c.Assert(err, IsNil)
+	c.Check(s.state.AllWarnings(), HasLen, 0)
+
+	// upgrades are fine
+	updateAccount("1", "starred")
+	err = assertstate.RefreshSnapDeclarations(s.state, 0, nil)
+	c.Assert(err, IsNil)
+	c.Check(s.state.AllWarnings(), HasLen, 0)
+
+	updateAccount("2", "verified")
+	err = assertstate.RefreshSnapDeclarations(s.state, 0, nil)
+	c.Assert(err, IsNil)
+	warnings := s.state.AllWarnings()
+	c.Assert(warnings, HasLen, 1)
+	c.Check(warnings[0].String(), Equals, `publisher "developer1" of snap "foo" was downgraded to verified`)
+	c.Check(warnings[0].Severity(), Equals, state.WarningSeverityCritical)
+
+	change, err := snapstate.PublisherChange("foo-id", "foo")
+	c.Assert(err, IsNil)
+	c.Check(change, Equals, snapstate.PublisherDowngraded)
+}
+
 func (s *assertMgrSuite) TestRefreshSnapDeclarationsChangingKey(c *C) {
 	s.state.Lock()
 	defer s.state.Unlock()
@@ -0,0 +1,208 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//go:build !nomanagers
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/naming"
+)
+
+const snapDNSPrefix = "core.dns.snaps."
+
+var snapDNSKeys = map[string]bool{
+	"nameservers": true,
+	"disabled":    true,
+}
+
+// validSnapDNSOption returns whether the option is either
+// core.dns.snaps.<snap> or core.dns.snaps.<snap>.<key>.
+func validSnapDNSOption(option string) bool {
+	parts := strings.Split(strings.TrimPrefix(option, snapDNSPrefix), ".")
+	switch len(parts) {
+	case 1:
+		return naming.ValidateInstance(parts[0]) == nil
+	case 2:
+		return naming.ValidateInstance(parts[0]) == nil && snapDNSKeys[parts[1]]
+	}
+	return false
+}
+
+// changedSnapDNS returns the names of the snaps whose DNS settings are
+// modified in the transaction.
+func changedSnapDNS(tr RunTransaction) []string {
+	var instanceNames []string
+	seen := make(map[string]bool)
+	for _, name := range tr.Changes() {
+		if !strings.HasPrefix(name, snapDNSPrefix) {
+			continue
+		}
+		instanceName := strings.Split(strings.TrimPrefix(name, snapDNSPrefix), ".")[0]
+		if !seen[instanceName] {
+			seen[instanceName] = true
+			instanceNames = append(instanceNames, instanceName)
+		}
+	}
+	return instanceNames
+}
+
+// snapDNSSettings returns the DNS settings configured for the snap, or nil
+// if the snap uses the system's name resolution configuration.
+func snapDNSSettings(tr RunTransaction, instanceName string) (*snapstate.DNSSettings, error) {
+	nameservers, err := coreCfg(tr, fmt.Sprintf("dns.snaps.%s.nameservers", instanceName))
+	if err != nil {
+		return nil, err
+	}
+	disabled, err := coreCfg(tr, fmt.Sprintf("dns.snaps.%s.disabled", instanceName))
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &snapstate.DNSSettings{}
+	switch disabled {
+	case "", "false":
+	case "true":
+		settings.Disabled = true
+	default:
+		return nil, fmt.Errorf("cannot set DNS for snap %q: disabled can only be set to 'true' or 'false'", instanceName)
+	}
+	for _, ns := range strings.Split(nameservers, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" {
+			continue
+		}
+		if net.ParseIP(ns) == nil {
+			return nil, fmt.Errorf("cannot set DNS for snap %q: invalid name server address %q", instanceName, ns)
+		}
+		settings.Nameservers = append(settings.Nameservers, ns)
+	}
+
+	if !settings.Disabled && len(settings.Nameservers) == 0 {
+		return nil, nil
+	}
+	if settings.Disabled && len(settings.Nameservers) > 0 {
+		return nil, fmt.Errorf("cannot set DNS for snap %q: name servers cannot be set when DNS is disabled", instanceName)
+	}
+	return settings, nil
+}
+
+func validateSnapDNSSettings(tr RunTransaction) error {
+	instanceNames := changedSnapDNS(tr)
+	if len(instanceNames) == 0 {
+		return nil
+	}
+
+	st := tr.State()
+	st.Lock()
+	defer st.Unlock()
+
+	for _, instanceName := range instanceNames {
+		settings, err := snapDNSSettings(tr, instanceName)
+		if err != nil {
+			return err
+		}
+		// snaps that are not installed can always go back to the
+		// system's configuration
+		if settings == nil {
+			continue
+		}
+		var snapst snapstate.SnapState
+		err = snapstate.Get(st, instanceName, &snapst)
+		if errors.Is(err, state.ErrNoState) {
+			return fmt.Errorf("cannot set DNS for snap %q: snap is not installed", instanceName)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func handleSnapDNSConfiguration(tr RunTransaction, opts *fsOnlyContext) error {
+	instanceNames := changedSnapDNS(tr)
+	if len(instanceNames) == 0 {
+		return nil
+	}
+
+	st := tr.State()
+	st.Lock()
+	defer st.Unlock()
+
+	ts := state.NewTaskSet()
+	for _, instanceName := range instanceNames {
+		settings, err := snapDNSSettings(tr, instanceName)
+		if err != nil {
+			return err
+		}
+		var snapst snapstate.SnapState
+		err = snapstate.Get(st, instanceName, &snapst)
+		if errors.Is(err, state.ErrNoState) && settings == nil {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(snapst.DNSOverride, settings) {
+			continue
+		}
+		if err := snapstate.SetDNSOverride(st, instanceName, settings); err != nil {
+			return err
+		}
+		if !snapst.Active {
+			// the profiles are set up with the settings once the
+			// snap is enabled again
+			continue
+		}
+
+		// the mount namespace of the snap is updated along with its
+		// security profiles
+		addSetupProfilesTask(st, ts, instanceName, &snapst)
+	}
+	if len(ts.Tasks()) > 0 && tr.Task() != nil {
+		snapstate.InjectTasks(tr.Task(), ts)
+	}
+	return nil
+}
+
+// addSetupProfilesTask adds to the task set a task setting up again the
+// security profiles of the current revision of the snap, after the tasks
+// already in the set.
+func addSetupProfilesTask(st *state.State, ts *state.TaskSet, instanceName string, snapst *snapstate.SnapState) {
+	si := snapst.CurrentSideInfo()
+	setupProfiles := st.NewTask("setup-profiles", fmt.Sprintf(i18n.G("Update snap %q (%s) security profiles"), instanceName, si.Revision))
+	setupProfiles.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo:    si,
+		InstanceKey: snapst.InstanceKey,
+		Type:        snap.Type(snapst.SnapType),
+	})
+	if tasks := ts.Tasks(); len(tasks) > 0 {
+		setupProfiles.WaitFor(tasks[len(tasks)-1])
+	}
+	ts.AddTask(setupProfiles)
+}
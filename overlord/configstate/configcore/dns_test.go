@@ -0,0 +1,198 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//go:build !nomanagers
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type dnsSuite struct {
+	configcoreSuite
+}
+
+var _ = Suite(&dnsSuite{})
+
+func (s *dnsSuite) mockInstalledSnap(c *C, instanceName string, active bool) {
+	s.state.Lock()
+	defer s.state.Unlock()
+	si := &snap.SideInfo{RealName: instanceName, Revision: snap.R(1)}
+	snapstate.Set(s.state, instanceName, &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  snap.R(1),
+		Active:   active,
+		SnapType: "app",
+	})
+}
+
+func (s *dnsSuite) snapDNSOverride(c *C, instanceName string) *snapstate.DNSSettings {
+	s.state.Lock()
+	defer s.state.Unlock()
+	settings, err := snapstate.DNSOverride(s.state, instanceName)
+	c.Assert(err, IsNil)
+	return settings
+}
+
+func (s *dnsSuite) configureTask() *state.Task {
+	s.state.Lock()
+	defer s.state.Unlock()
+	t := s.state.NewTask("run-hook", "configure")
+	s.state.NewChange("configure", "...").AddTask(t)
+	return t
+}
+
+func (s *dnsSuite) TestConfigureSnapDNS(c *C) {
+	s.mockInstalledSnap(c, "test-snap", true)
+	s.mockInstalledSnap(c, "other-snap", true)
+	t := s.configureTask()
+
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		task:  t,
+		changes: map[string]interface{}{
+			"dns.snaps.test-snap.nameservers": "10.0.0.53, fd00::53",
+			"dns.snaps.other-snap.disabled":   true,
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.snapDNSOverride(c, "test-snap"), DeepEquals, &snapstate.DNSSettings{
+		Nameservers: []string{"10.0.0.53", "fd00::53"},
+	})
+	c.Check(snapstate.DNSResolvConfFile("test-snap"), testutil.FileEquals, `# Generated by snapd, do not edit
+nameserver 10.0.0.53
+nameserver fd00::53
+`)
+	c.Check(s.snapDNSOverride(c, "other-snap"), DeepEquals, &snapstate.DNSSettings{Disabled: true})
+
+	// the security profiles of both snaps are set up again
+	s.state.Lock()
+	defer s.state.Unlock()
+	tasks := t.Change().Tasks()
+	c.Assert(tasks, HasLen, 3)
+	var setupSnaps []string
+	for _, st := range tasks[1:] {
+		c.Check(st.Kind(), Equals, "setup-profiles")
+		c.Check(st.WaitTasks(), testutil.Contains, t)
+		snapsup, err := snapstate.TaskSnapSetup(st)
+		c.Assert(err, IsNil)
+		c.Check(snapsup.Revision(), Equals, snap.R(1))
+		setupSnaps = append(setupSnaps, snapsup.InstanceName())
+	}
+	c.Check(setupSnaps, testutil.DeepUnsortedMatches, []string{"test-snap", "other-snap"})
+}
+
+func (s *dnsSuite) TestConfigureSnapDNSInactive(c *C) {
+	s.mockInstalledSnap(c, "test-snap", false)
+	t := s.configureTask()
+
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		task:  t,
+		changes: map[string]interface{}{
+			"dns.snaps.test-snap.disabled": true,
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.snapDNSOverride(c, "test-snap"), DeepEquals, &snapstate.DNSSettings{Disabled: true})
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Check(t.Change().Tasks(), HasLen, 1)
+}
+
+func (s *dnsSuite) TestConfigureSnapDNSUnset(c *C) {
+	s.mockInstalledSnap(c, "test-snap", true)
+
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"dns.snaps.test-snap.nameservers": "10.0.0.53",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(snapstate.DNSResolvConfFile("test-snap"), testutil.FilePresent)
+
+	err = configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"dns.snaps.test-snap.nameservers": "",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.snapDNSOverride(c, "test-snap"), IsNil)
+	c.Check(snapstate.DNSResolvConfFile("test-snap"), testutil.FileAbsent)
+
+	// unsetting the DNS of a snap that isn't installed is fine
+	err = configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"dns.snaps.other-snap.nameservers": "",
+		},
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *dnsSuite) TestConfigureSnapDNSErrors(c *C) {
+	s.mockInstalledSnap(c, "test-snap", true)
+
+	for _, tc := range []struct {
+		changes map[string]interface{}
+		err     string
+	}{
+		{
+			changes: map[string]interface{}{"dns.snaps.other-snap.nameservers": "10.0.0.53"},
+			err:     `cannot set DNS for snap "other-snap": snap is not installed`,
+		}, {
+			changes: map[string]interface{}{"dns.snaps.test-snap.nameservers": "dns.example.com"},
+			err:     `cannot set DNS for snap "test-snap": invalid name server address "dns.example.com"`,
+		}, {
+			changes: map[string]interface{}{"dns.snaps.test-snap.disabled": "maybe"},
+			err:     `cannot set DNS for snap "test-snap": disabled can only be set to 'true' or 'false'`,
+		}, {
+			changes: map[string]interface{}{
+				"dns.snaps.test-snap.disabled":    true,
+				"dns.snaps.test-snap.nameservers": "10.0.0.53",
+			},
+			err: `cannot set DNS for snap "test-snap": name servers cannot be set when DNS is disabled`,
+		}, {
+			changes: map[string]interface{}{"dns.snaps.test-snap.search": "example.com"},
+			err:     `cannot set "core.dns.snaps.test-snap.search": unsupported system option`,
+		}, {
+			changes: map[string]interface{}{"dns.snaps.Bad_Name.disabled": true},
+			err:     `cannot set "core.dns.snaps.Bad_Name.disabled": unsupported system option`,
+		},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state:   s.state,
+			changes: tc.changes,
+		})
+		c.Check(err, ErrorMatches, tc.err)
+	}
+	c.Check(s.snapDNSOverride(c, "test-snap"), IsNil)
+}
@@ -20,6 +20,7 @@
 package configcore
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
@@ -53,6 +54,43 @@ func validateExperimentalSettings(tr ConfGetter) error {
 			return err
 		}
 	}
+	return validateExperimentalPrerequisites(tr)
+}
+
+// validateExperimentalPrerequisites checks that the prerequisites of the
+// features being enabled are enabled, and that prerequisites of enabled
+// features are not being disabled. Only changes are checked so that the
+// features enabled before prerequisites were introduced keep working.
+func validateExperimentalPrerequisites(tr ConfGetter) error {
+	for _, feature := range features.KnownFeatures() {
+		for _, prereq := range feature.Prerequisites() {
+			enabled, err := features.Flag(tr, feature)
+			if err != nil {
+				return err
+			}
+			prereqEnabled, err := features.Flag(tr, prereq)
+			if err != nil {
+				return err
+			}
+			if !enabled || prereqEnabled {
+				continue
+			}
+			wasEnabled, err := features.Flag(pristineConf{tr}, feature)
+			if err != nil {
+				return err
+			}
+			prereqWasEnabled, err := features.Flag(pristineConf{tr}, prereq)
+			if err != nil {
+				return err
+			}
+			switch {
+			case !wasEnabled:
+				return fmt.Errorf("cannot enable experimental feature %q: requires %q to be enabled", feature, prereq)
+			case prereqWasEnabled:
+				return fmt.Errorf("cannot disable experimental feature %q: required by enabled %q", prereq, feature)
+			}
+		}
+	}
 	return nil
 }
 
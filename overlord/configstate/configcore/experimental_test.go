@@ -96,3 +96,46 @@ func (s *experimentalSuite) TestFilesystemOnlyApplyValidationFails(c *C) {
 	tmpDir := c.MkDir()
 	c.Assert(configcore.FilesystemOnlyApply(classicDev, tmpDir, conf), ErrorMatches, `experimental.refresh-app-awareness can only be set to 'true' or 'false'`)
 }
+
+func (s *experimentalSuite) TestConfigureExperimentalPrerequisites(c *C) {
+	for _, tc := range []struct {
+		conf    map[string]interface{}
+		changes map[string]interface{}
+		err     string
+	}{{
+		// enabling with the prerequisite enabled by default
+		changes: map[string]interface{}{"experimental.refresh-app-awareness-ux": true},
+	}, {
+		// enabling along with the prerequisite
+		conf:    map[string]interface{}{"experimental.refresh-app-awareness": false},
+		changes: map[string]interface{}{"experimental.refresh-app-awareness-ux": true, "experimental.refresh-app-awareness": true},
+	}, {
+		conf:    map[string]interface{}{"experimental.refresh-app-awareness": false},
+		changes: map[string]interface{}{"experimental.refresh-app-awareness-ux": true},
+		err:     `cannot enable experimental feature "refresh-app-awareness-ux": requires "refresh-app-awareness" to be enabled`,
+	}, {
+		conf:    map[string]interface{}{"experimental.refresh-app-awareness-ux": true},
+		changes: map[string]interface{}{"experimental.refresh-app-awareness": false},
+		err:     `cannot disable experimental feature "refresh-app-awareness": required by enabled "refresh-app-awareness-ux"`,
+	}, {
+		// disabling both
+		conf:    map[string]interface{}{"experimental.refresh-app-awareness-ux": true},
+		changes: map[string]interface{}{"experimental.refresh-app-awareness": false, "experimental.refresh-app-awareness-ux": false},
+	}, {
+		// configuration predating the prerequisite is left alone
+		conf:    map[string]interface{}{"experimental.refresh-app-awareness-ux": true, "experimental.refresh-app-awareness": false},
+		changes: map[string]interface{}{"experimental.hotplug": true},
+	}} {
+		conf := &mockConf{
+			state:   s.state,
+			conf:    tc.conf,
+			changes: tc.changes,
+		}
+		err := configcore.FilesystemOnlyRun(classicDev, conf)
+		if tc.err == "" {
+			c.Check(err, IsNil, Commentf("%v", tc.changes))
+		} else {
+			c.Check(err, ErrorMatches, tc.err, Commentf("%v", tc.changes))
+		}
+	}
+}
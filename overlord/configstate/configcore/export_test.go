@@ -33,6 +33,7 @@ var (
 	UpdateKeyValueStream = updateKeyValueStream
 	AddFSOnlyHandler     = addFSOnlyHandler
 	FilesystemOnlyApply  = filesystemOnlyApply
+	GetLocaleFromSystem  = getLocaleFromSystem
 )
 
 type PlainCoreConfig = plainCoreConfig
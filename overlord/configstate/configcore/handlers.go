@@ -72,6 +72,9 @@ func init() {
 	// Export experimental.* flags to a place easily accessible from snapd helpers.
 	addFSOnlyHandler(validateExperimentalSettings, doExportExperimentalFlags, &flags{earlyConfigFilter: earlyExperimentalSettingsFilter})
 
+	// experimental.mounts-generator
+	addFSOnlyHandler(nil, handleMountsGeneratorConfiguration, nil)
+
 	// network.disable-ipv6
 	addFSOnlyHandler(validateNetworkSettings, handleNetworkConfiguration, coreOnly)
 
@@ -102,6 +105,9 @@ func init() {
 	// system.timezone
 	addFSOnlyHandler(validateTimezoneSettings, handleTimezoneConfiguration, coreOnly)
 
+	// system.locale
+	addFSOnlyHandler(validateLocaleSettings, handleLocaleConfiguration, coreOnly)
+
 	// system.hostname - note that the validation is done via hostnamectl
 	// when applying so there is no validation handler, see LP:1952740
 	addFSOnlyHandler(nil, handleHostnameConfiguration, coreOnly)
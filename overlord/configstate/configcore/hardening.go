@@ -0,0 +1,185 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//go:build !nomanagers
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap/naming"
+)
+
+const snapHardeningPrefix = "core.hardening.snaps."
+
+// snapHardeningKeys are the features of a snap that an administrator can
+// disable by setting the respective option to false.
+var snapHardeningKeys = map[string]bool{
+	"ptrace":     true,
+	"core-dumps": true,
+	"memfd-exec": true,
+}
+
+// validSnapHardeningOption returns whether the option is either
+// core.hardening.snaps.<snap> or core.hardening.snaps.<snap>.<key>.
+func validSnapHardeningOption(option string) bool {
+	parts := strings.Split(strings.TrimPrefix(option, snapHardeningPrefix), ".")
+	switch len(parts) {
+	case 1:
+		return naming.ValidateInstance(parts[0]) == nil
+	case 2:
+		return naming.ValidateInstance(parts[0]) == nil && snapHardeningKeys[parts[1]]
+	}
+	return false
+}
+
+// changedSnapHardening returns the names of the snaps whose hardening
+// settings are modified in the transaction.
+func changedSnapHardening(tr RunTransaction) []string {
+	var instanceNames []string
+	seen := make(map[string]bool)
+	for _, name := range tr.Changes() {
+		if !strings.HasPrefix(name, snapHardeningPrefix) {
+			continue
+		}
+		instanceName := strings.Split(strings.TrimPrefix(name, snapHardeningPrefix), ".")[0]
+		if !seen[instanceName] {
+			seen[instanceName] = true
+			instanceNames = append(instanceNames, instanceName)
+		}
+	}
+	return instanceNames
+}
+
+// snapHardeningSettings returns the hardening settings configured for the
+// snap, or nil if none of its features is disabled.
+func snapHardeningSettings(tr RunTransaction, instanceName string) (*snapstate.HardeningSettings, error) {
+	disabled := func(key string) (bool, error) {
+		value, err := coreCfg(tr, fmt.Sprintf("hardening.snaps.%s.%s", instanceName, key))
+		if err != nil {
+			return false, err
+		}
+		switch value {
+		case "", "true":
+			return false, nil
+		case "false":
+			return true, nil
+		}
+		return false, fmt.Errorf("cannot set hardening for snap %q: %s can only be set to 'true' or 'false'", instanceName, key)
+	}
+
+	settings := &snapstate.HardeningSettings{}
+	var err error
+	if settings.NoPtrace, err = disabled("ptrace"); err != nil {
+		return nil, err
+	}
+	if settings.NoCoreDumps, err = disabled("core-dumps"); err != nil {
+		return nil, err
+	}
+	if settings.NoMemfdExec, err = disabled("memfd-exec"); err != nil {
+		return nil, err
+	}
+	if settings.IsZero() {
+		return nil, nil
+	}
+	return settings, nil
+}
+
+func validateSnapHardeningSettings(tr RunTransaction) error {
+	instanceNames := changedSnapHardening(tr)
+	if len(instanceNames) == 0 {
+		return nil
+	}
+
+	st := tr.State()
+	st.Lock()
+	defer st.Unlock()
+
+	for _, instanceName := range instanceNames {
+		settings, err := snapHardeningSettings(tr, instanceName)
+		if err != nil {
+			return err
+		}
+		// snaps that are not installed can always go back to the
+		// default confinement
+		if settings == nil {
+			continue
+		}
+		var snapst snapstate.SnapState
+		err = snapstate.Get(st, instanceName, &snapst)
+		if errors.Is(err, state.ErrNoState) {
+			return fmt.Errorf("cannot set hardening for snap %q: snap is not installed", instanceName)
+		}
+		if err != nil {
+			return err
+		}
+		if snapst.Classic && !snapst.JailMode {
+			return fmt.Errorf("cannot set hardening for snap %q: snap uses classic confinement", instanceName)
+		}
+	}
+	return nil
+}
+
+func handleSnapHardeningConfiguration(tr RunTransaction, opts *fsOnlyContext) error {
+	instanceNames := changedSnapHardening(tr)
+	if len(instanceNames) == 0 {
+		return nil
+	}
+
+	st := tr.State()
+	st.Lock()
+	defer st.Unlock()
+
+	ts := state.NewTaskSet()
+	for _, instanceName := range instanceNames {
+		settings, err := snapHardeningSettings(tr, instanceName)
+		if err != nil {
+			return err
+		}
+		var snapst snapstate.SnapState
+		err = snapstate.Get(st, instanceName, &snapst)
+		if errors.Is(err, state.ErrNoState) && settings == nil {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(snapst.Hardening, settings) {
+			continue
+		}
+		if err := snapstate.SetHardening(st, instanceName, settings); err != nil {
+			return err
+		}
+		if !snapst.Active {
+			// the profiles are set up with the settings once the
+			// snap is enabled again
+			continue
+		}
+		addSetupProfilesTask(st, ts, instanceName, &snapst)
+	}
+	if len(ts.Tasks()) > 0 && tr.Task() != nil {
+		snapstate.InjectTasks(tr.Task(), ts)
+	}
+	return nil
+}
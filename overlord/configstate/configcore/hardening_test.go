@@ -0,0 +1,191 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//go:build !nomanagers
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type hardeningSuite struct {
+	configcoreSuite
+}
+
+var _ = Suite(&hardeningSuite{})
+
+func (s *hardeningSuite) mockInstalledSnap(c *C, instanceName string, active bool, flags snapstate.Flags) {
+	s.state.Lock()
+	defer s.state.Unlock()
+	si := &snap.SideInfo{RealName: instanceName, Revision: snap.R(1)}
+	snapstate.Set(s.state, instanceName, &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  snap.R(1),
+		Active:   active,
+		SnapType: "app",
+		Flags:    flags,
+	})
+}
+
+func (s *hardeningSuite) snapHardening(c *C, instanceName string) *snapstate.HardeningSettings {
+	s.state.Lock()
+	defer s.state.Unlock()
+	settings, err := snapstate.Hardening(s.state, instanceName)
+	c.Assert(err, IsNil)
+	return settings
+}
+
+func (s *hardeningSuite) configureTask() *state.Task {
+	s.state.Lock()
+	defer s.state.Unlock()
+	t := s.state.NewTask("run-hook", "configure")
+	s.state.NewChange("configure", "...").AddTask(t)
+	return t
+}
+
+func (s *hardeningSuite) TestConfigureSnapHardening(c *C) {
+	s.mockInstalledSnap(c, "test-snap", true, snapstate.Flags{})
+	s.mockInstalledSnap(c, "other-snap", true, snapstate.Flags{Classic: true, JailMode: true})
+	t := s.configureTask()
+
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		task:  t,
+		changes: map[string]interface{}{
+			"hardening.snaps.test-snap.ptrace":      false,
+			"hardening.snaps.test-snap.core-dumps":  "false",
+			"hardening.snaps.other-snap.memfd-exec": false,
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.snapHardening(c, "test-snap"), DeepEquals, &snapstate.HardeningSettings{
+		NoPtrace:    true,
+		NoCoreDumps: true,
+	})
+	c.Check(s.snapHardening(c, "other-snap"), DeepEquals, &snapstate.HardeningSettings{NoMemfdExec: true})
+
+	// the security profiles of both snaps are set up again
+	s.state.Lock()
+	defer s.state.Unlock()
+	tasks := t.Change().Tasks()
+	c.Assert(tasks, HasLen, 3)
+	var setupSnaps []string
+	for _, st := range tasks[1:] {
+		c.Check(st.Kind(), Equals, "setup-profiles")
+		c.Check(st.WaitTasks(), testutil.Contains, t)
+		snapsup, err := snapstate.TaskSnapSetup(st)
+		c.Assert(err, IsNil)
+		c.Check(snapsup.Revision(), Equals, snap.R(1))
+		setupSnaps = append(setupSnaps, snapsup.InstanceName())
+	}
+	c.Check(setupSnaps, testutil.DeepUnsortedMatches, []string{"test-snap", "other-snap"})
+}
+
+func (s *hardeningSuite) TestConfigureSnapHardeningInactive(c *C) {
+	s.mockInstalledSnap(c, "test-snap", false, snapstate.Flags{})
+	t := s.configureTask()
+
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		task:  t,
+		changes: map[string]interface{}{
+			"hardening.snaps.test-snap.ptrace": false,
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.snapHardening(c, "test-snap"), DeepEquals, &snapstate.HardeningSettings{NoPtrace: true})
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Check(t.Change().Tasks(), HasLen, 1)
+}
+
+func (s *hardeningSuite) TestConfigureSnapHardeningUnset(c *C) {
+	s.mockInstalledSnap(c, "test-snap", true, snapstate.Flags{})
+
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"hardening.snaps.test-snap.ptrace": false,
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.snapHardening(c, "test-snap"), NotNil)
+
+	err = configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"hardening.snaps.test-snap.ptrace": true,
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(s.snapHardening(c, "test-snap"), IsNil)
+
+	// unsetting the hardening of a snap that isn't installed is fine
+	err = configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"hardening.snaps.other-snap.ptrace": "",
+		},
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *hardeningSuite) TestConfigureSnapHardeningErrors(c *C) {
+	s.mockInstalledSnap(c, "test-snap", true, snapstate.Flags{})
+	s.mockInstalledSnap(c, "classic-snap", true, snapstate.Flags{Classic: true})
+
+	for _, tc := range []struct {
+		changes map[string]interface{}
+		err     string
+	}{
+		{
+			changes: map[string]interface{}{"hardening.snaps.other-snap.ptrace": false},
+			err:     `cannot set hardening for snap "other-snap": snap is not installed`,
+		}, {
+			changes: map[string]interface{}{"hardening.snaps.classic-snap.ptrace": false},
+			err:     `cannot set hardening for snap "classic-snap": snap uses classic confinement`,
+		}, {
+			changes: map[string]interface{}{"hardening.snaps.test-snap.core-dumps": "maybe"},
+			err:     `cannot set hardening for snap "test-snap": core-dumps can only be set to 'true' or 'false'`,
+		}, {
+			changes: map[string]interface{}{"hardening.snaps.test-snap.mlock": false},
+			err:     `cannot set "core.hardening.snaps.test-snap.mlock": unsupported system option`,
+		}, {
+			changes: map[string]interface{}{"hardening.snaps.Bad_Name.ptrace": false},
+			err:     `cannot set "core.hardening.snaps.Bad_Name.ptrace": unsupported system option`,
+		},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state:   s.state,
+			changes: tc.changes,
+		})
+		c.Check(err, ErrorMatches, tc.err)
+	}
+	c.Check(s.snapHardening(c, "test-snap"), IsNil)
+	c.Check(s.snapHardening(c, "classic-snap"), IsNil)
+}
@@ -0,0 +1,129 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/sysconfig"
+)
+
+func init() {
+	// add supported configuration of this module
+	supportedConfigurations["core.system.locale"] = true
+	// and register it as a external config
+	config.RegisterExternalConfig("core", "system.locale", getLocaleFromSystemVC)
+}
+
+// validLocale matches locale names like "C", "POSIX", "en_US.UTF-8" or
+// "sr_RS.UTF-8@latin".
+var validLocale = regexp.MustCompile(`^(C|POSIX|[a-z]{2,3}(_[A-Z]{2})?)(\.[a-zA-Z0-9-]+)?(@[a-zA-Z0-9]+)?$`).MatchString
+
+func validateLocaleSettings(tr ConfGetter) error {
+	locale, err := coreCfg(tr, "system.locale")
+	if err != nil {
+		return err
+	}
+	if locale == "" {
+		return nil
+	}
+	if !validLocale(locale) {
+		return fmt.Errorf("cannot set locale %q: name not valid", locale)
+	}
+
+	return nil
+}
+
+func handleLocaleConfiguration(_ sysconfig.Device, tr ConfGetter, opts *fsOnlyContext) error {
+	locale, err := coreCfg(tr, "system.locale")
+	if err != nil {
+		return err
+	}
+	// nothing to do
+	if locale == "" {
+		return nil
+	}
+	// runtime system
+	if opts == nil {
+		// see if anything has changed
+		currentLocale, err := getLocaleFromSystem()
+		if err != nil {
+			return err
+		}
+		if locale == currentLocale {
+			return nil
+		}
+
+		output, err := exec.Command("localectl", "set-locale", "LANG="+locale).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("cannot set locale: %v", osutil.OutputErr(output, err))
+		}
+	} else {
+		localePath := filepath.Join(opts.RootDir, "/etc/default/locale")
+		if err := os.MkdirAll(filepath.Dir(localePath), 0755); err != nil {
+			return err
+		}
+		if err := osutil.AtomicWriteFile(localePath, []byte(fmt.Sprintf("LANG=%s\n", locale)), 0644, 0); err != nil {
+			return fmt.Errorf("cannot write locale: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func getLocaleFromSystemVC(key string) (interface{}, error) {
+	return getLocaleFromSystem()
+}
+
+func getLocaleFromSystem() (string, error) {
+	f, err := os.Open(filepath.Join(dirs.GlobalRootDir, "/etc/default/locale"))
+	// see locale(7), without any settings the "C" locale is used
+	if os.IsNotExist(err) {
+		return "C", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		value := strings.TrimPrefix(line, "LANG=")
+		if value == line {
+			continue
+		}
+		return strings.Trim(value, `"`), nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("cannot read locale: %v", err)
+	}
+
+	return "C", nil
+}
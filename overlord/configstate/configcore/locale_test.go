@@ -0,0 +1,121 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type localeSuite struct {
+	configcoreSuite
+}
+
+var _ = Suite(&localeSuite{})
+
+func (s *localeSuite) SetUpTest(c *C) {
+	s.configcoreSuite.SetUpTest(c)
+
+	err := os.MkdirAll(filepath.Join(dirs.GlobalRootDir, "/etc/default"), 0755)
+	c.Assert(err, IsNil)
+	err = os.WriteFile(filepath.Join(dirs.GlobalRootDir, "/etc/default/locale"), []byte("LANG=\"C.UTF-8\"\n"), 0644)
+	c.Assert(err, IsNil)
+}
+
+func (s *localeSuite) TestConfigureLocaleInvalid(c *C) {
+	invalidLocales := []string{
+		"no-#", "no-ä", "en_us", "en_US.UTF-8 foo", "/etc/passwd",
+	}
+
+	for _, locale := range invalidLocales {
+		err := configcore.FilesystemOnlyRun(coreDev, &mockConf{
+			state: s.state,
+			conf: map[string]interface{}{
+				"system.locale": locale,
+			},
+		})
+		c.Assert(err, ErrorMatches, `cannot set locale.*`, Commentf("tested locale: %v", locale))
+	}
+}
+
+func (s *localeSuite) TestConfigureLocaleIntegration(c *C) {
+	mockedLocalectl := testutil.MockCommand(c, "localectl", "")
+	defer mockedLocalectl.Restore()
+
+	validLocales := []string{
+		"C", "POSIX", "en_US.UTF-8", "de_DE", "pt_BR.utf8", "sr_RS.UTF-8@latin", "ast_ES.UTF-8",
+	}
+
+	for _, locale := range validLocales {
+		err := configcore.FilesystemOnlyRun(coreDev, &mockConf{
+			state: s.state,
+			conf: map[string]interface{}{
+				"system.locale": locale,
+			},
+		})
+		c.Assert(err, IsNil)
+		c.Check(mockedLocalectl.Calls(), DeepEquals, [][]string{
+			{"localectl", "set-locale", "LANG=" + locale},
+		}, Commentf("tested locale: %v", locale))
+		mockedLocalectl.ForgetCalls()
+	}
+}
+
+func (s *localeSuite) TestConfigureLocaleUnchanged(c *C) {
+	mockedLocalectl := testutil.MockCommand(c, "localectl", "")
+	defer mockedLocalectl.Restore()
+
+	err := configcore.FilesystemOnlyRun(coreDev, &mockConf{
+		state: s.state,
+		conf: map[string]interface{}{
+			"system.locale": "C.UTF-8",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(mockedLocalectl.Calls(), HasLen, 0)
+}
+
+func (s *localeSuite) TestFilesystemOnlyApply(c *C) {
+	conf := configcore.PlainCoreConfig(map[string]interface{}{
+		"system.locale": "en_GB.UTF-8",
+	})
+	tmpDir := c.MkDir()
+	c.Assert(configcore.FilesystemOnlyApply(coreDev, tmpDir, conf), IsNil)
+
+	c.Check(filepath.Join(tmpDir, "/etc/default/locale"), testutil.FileEquals, "LANG=en_GB.UTF-8\n")
+}
+
+func (s *localeSuite) TestGetLocaleFromSystem(c *C) {
+	locale, err := configcore.GetLocaleFromSystem()
+	c.Assert(err, IsNil)
+	c.Check(locale, Equals, "C.UTF-8")
+
+	err = os.Remove(filepath.Join(dirs.GlobalRootDir, "/etc/default/locale"))
+	c.Assert(err, IsNil)
+	locale, err = configcore.GetLocaleFromSystem()
+	c.Assert(err, IsNil)
+	c.Check(locale, Equals, "C")
+}
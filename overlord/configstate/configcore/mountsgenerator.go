@@ -0,0 +1,64 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/features"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/sysconfig"
+	"github.com/snapcore/snapd/systemd"
+)
+
+// pristineConf gives access to the configuration as it was before the
+// current transaction.
+type pristineConf struct {
+	tr ConfGetter
+}
+
+func (p pristineConf) GetMaybe(snapName, key string, result interface{}) error {
+	if err := p.tr.GetPristine(snapName, key, result); err != nil && !config.IsNoOption(err) {
+		return err
+	}
+	return nil
+}
+
+// handleMountsGeneratorConfiguration moves the mount units of snaps to or
+// from the place snapd-generator produces them from at boot, as the
+// experimental.mounts-generator flag gets toggled.
+func handleMountsGeneratorConfiguration(_ sysconfig.Device, tr ConfGetter, opts *fsOnlyContext) error {
+	if opts != nil {
+		// the units are migrated once the system runs
+		return nil
+	}
+	wasEnabled, err := features.Flag(pristineConf{tr}, features.MountsGenerator)
+	if err != nil {
+		return err
+	}
+	enabled, err := features.Flag(tr, features.MountsGenerator)
+	if err != nil {
+		return err
+	}
+	if wasEnabled == enabled {
+		return nil
+	}
+	sysd := systemd.NewUnderRoot(dirs.GlobalRootDir, systemd.SystemMode, nil)
+	return systemd.MigrateMountUnits(sysd, enabled)
+}
@@ -0,0 +1,126 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type mountsGeneratorSuite struct {
+	configcoreSuite
+
+	unit string
+}
+
+var _ = Suite(&mountsGeneratorSuite{})
+
+const mockSnapMountUnit = `[Unit]
+Description=Mount unit for foo, revision 1
+After=snapd.mounts-pre.target
+Before=snapd.mounts.target
+Before=local-fs.target
+
+[Mount]
+What=/var/lib/snapd/snaps/foo_1.snap
+Where=/snap/foo/1
+Type=squashfs
+Options=nodev,ro,x-gdu.hide,x-gvfs-hide
+LazyUnmount=yes
+
+[Install]
+WantedBy=snapd.mounts.target
+WantedBy=multi-user.target
+`
+
+func (s *mountsGeneratorSuite) SetUpTest(c *C) {
+	s.configcoreSuite.SetUpTest(c)
+
+	c.Assert(os.MkdirAll(dirs.SnapServicesDir, 0755), IsNil)
+	s.unit = "snap-foo-1.mount"
+	c.Assert(os.WriteFile(filepath.Join(dirs.SnapServicesDir, s.unit), []byte(mockSnapMountUnit), 0644), IsNil)
+}
+
+func (s *mountsGeneratorSuite) TestEnableMigratesMountUnits(c *C) {
+	err := configcore.FilesystemOnlyRun(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"experimental.mounts-generator": true,
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(filepath.Join(dirs.SnapServicesDir, s.unit), testutil.FileAbsent)
+	c.Check(filepath.Join(dirs.SnapMountUnitsDir, s.unit), testutil.FileEquals, mockSnapMountUnit)
+	c.Check(s.systemctlArgs, DeepEquals, [][]string{
+		{"--root", dirs.GlobalRootDir, "disable", s.unit},
+		{"daemon-reload"},
+	})
+
+	// and back when disabled again
+	s.systemctlArgs = nil
+	err = configcore.FilesystemOnlyRun(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]interface{}{
+			"experimental.mounts-generator": true,
+		},
+		changes: map[string]interface{}{
+			"experimental.mounts-generator": false,
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(filepath.Join(dirs.SnapMountUnitsDir, s.unit), testutil.FileAbsent)
+	c.Check(filepath.Join(dirs.SnapServicesDir, s.unit), testutil.FileEquals, mockSnapMountUnit)
+	c.Check(s.systemctlArgs, DeepEquals, [][]string{
+		{"--root", dirs.GlobalRootDir, "enable", s.unit},
+		{"daemon-reload"},
+	})
+}
+
+func (s *mountsGeneratorSuite) TestUnchangedDoesNothing(c *C) {
+	err := configcore.FilesystemOnlyRun(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]interface{}{
+			"experimental.mounts-generator": "true",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(filepath.Join(dirs.SnapServicesDir, s.unit), testutil.FilePresent)
+	c.Check(s.systemctlArgs, HasLen, 0)
+}
+
+func (s *mountsGeneratorSuite) TestPreseedingDoesNothing(c *C) {
+	rootDir := c.MkDir()
+	err := configcore.FilesystemOnlyApply(classicDev, rootDir, map[string]interface{}{
+		"experimental.mounts-generator": true,
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(filepath.Join(dirs.SnapServicesDir, s.unit), testutil.FilePresent)
+	c.Check(s.systemctlArgs, HasLen, 0)
+}
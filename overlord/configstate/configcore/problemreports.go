@@ -0,0 +1,50 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//go:build !nomanagers
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"fmt"
+	"net/url"
+)
+
+func init() {
+	// add supported configuration of this module
+	supportedConfigurations["core.problem-reports.enabled"] = true
+	supportedConfigurations["core.problem-reports.endpoint"] = true
+}
+
+func validateProblemReportsSettings(tr RunTransaction) error {
+	if err := validateBoolFlag(tr, "problem-reports.enabled"); err != nil {
+		return err
+	}
+
+	endpoint, err := coreCfg(tr, "problem-reports.endpoint")
+	if err != nil {
+		return err
+	}
+	if endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("problem-reports.endpoint must be an https URL, not %q", endpoint)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//go:build !nomanagers
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+)
+
+type problemReportsSuite struct {
+	configcoreSuite
+}
+
+var _ = Suite(&problemReportsSuite{})
+
+func (s *problemReportsSuite) TestConfigureProblemReportsHappy(c *C) {
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]interface{}{
+			"problem-reports.enabled":  "true",
+			"problem-reports.endpoint": "https://reports.example.com/submit",
+		},
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *problemReportsSuite) TestConfigureProblemReportsInvalid(c *C) {
+	for _, tc := range []struct {
+		conf map[string]interface{}
+		err  string
+	}{
+		{map[string]interface{}{"problem-reports.enabled": "yes"}, `problem-reports.enabled can only be set to 'true' or 'false'`},
+		{map[string]interface{}{"problem-reports.endpoint": "http://reports.example.com"}, `problem-reports.endpoint must be an https URL, not "http://reports.example.com"`},
+		{map[string]interface{}{"problem-reports.endpoint": "https://"}, `problem-reports.endpoint must be an https URL, not "https://"`},
+		{map[string]interface{}{"problem-reports.endpoint": "reports"}, `problem-reports.endpoint must be an https URL, not "reports"`},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state: s.state,
+			conf:  tc.conf,
+		})
+		c.Check(err, ErrorMatches, tc.err, Commentf("%v", tc.conf))
+	}
+}
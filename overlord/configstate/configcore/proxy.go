@@ -23,6 +23,7 @@ package configcore
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -32,6 +33,10 @@ import (
 	"github.com/snapcore/snapd/overlord/assertstate"
 	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/naming"
 )
 
 var (
@@ -166,3 +171,148 @@ func handleProxyStore(tr RunTransaction, opts *fsOnlyContext) error {
 	}
 	return nil
 }
+
+const snapProxyPrefix = "core.proxy.snaps."
+
+var snapProxyKeys = map[string]bool{
+	"http":     true,
+	"https":    true,
+	"ftp":      true,
+	"no-proxy": true,
+	"direct":   true,
+}
+
+// validSnapProxyOption returns whether the option is either
+// core.proxy.snaps.<snap> or core.proxy.snaps.<snap>.<key>.
+func validSnapProxyOption(option string) bool {
+	parts := strings.Split(strings.TrimPrefix(option, snapProxyPrefix), ".")
+	switch len(parts) {
+	case 1:
+		return naming.ValidateInstance(parts[0]) == nil
+	case 2:
+		return naming.ValidateInstance(parts[0]) == nil && snapProxyKeys[parts[1]]
+	}
+	return false
+}
+
+// changedSnapProxies returns the names of the snaps whose proxy settings are
+// modified in the transaction.
+func changedSnapProxies(tr RunTransaction) []string {
+	var instanceNames []string
+	seen := make(map[string]bool)
+	for _, name := range tr.Changes() {
+		if !strings.HasPrefix(name, snapProxyPrefix) {
+			continue
+		}
+		instanceName := strings.Split(strings.TrimPrefix(name, snapProxyPrefix), ".")[0]
+		if !seen[instanceName] {
+			seen[instanceName] = true
+			instanceNames = append(instanceNames, instanceName)
+		}
+	}
+	return instanceNames
+}
+
+// snapProxySettings returns the proxy settings configured for the snap, or
+// nil if the snap uses the system's proxy configuration.
+func snapProxySettings(tr RunTransaction, instanceName string) (*snapstate.ProxySettings, error) {
+	values := make(map[string]string, len(snapProxyKeys))
+	for key := range snapProxyKeys {
+		value, err := coreCfg(tr, fmt.Sprintf("proxy.snaps.%s.%s", instanceName, key))
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+
+	var direct bool
+	switch values["direct"] {
+	case "", "false":
+	case "true":
+		direct = true
+	default:
+		return nil, fmt.Errorf("cannot set proxy for snap %q: direct can only be set to 'true' or 'false'", instanceName)
+	}
+
+	settings := &snapstate.ProxySettings{
+		HTTP:    values["http"],
+		HTTPS:   values["https"],
+		FTP:     values["ftp"],
+		NoProxy: values["no-proxy"],
+		Direct:  direct,
+	}
+	if *settings == (snapstate.ProxySettings{}) {
+		return nil, nil
+	}
+	if direct && (settings.HTTP != "" || settings.HTTPS != "" || settings.FTP != "" || settings.NoProxy != "") {
+		return nil, fmt.Errorf("cannot set proxy for snap %q: direct access cannot be combined with proxy settings", instanceName)
+	}
+	for _, proxy := range []string{settings.HTTP, settings.HTTPS, settings.FTP} {
+		if proxy == "" {
+			continue
+		}
+		if u, err := url.Parse(proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("cannot set proxy for snap %q: invalid proxy URL %q", instanceName, proxy)
+		}
+	}
+	return settings, nil
+}
+
+func validateSnapProxySettings(tr RunTransaction) error {
+	instanceNames := changedSnapProxies(tr)
+	if len(instanceNames) == 0 {
+		return nil
+	}
+
+	st := tr.State()
+	st.Lock()
+	defer st.Unlock()
+
+	for _, instanceName := range instanceNames {
+		settings, err := snapProxySettings(tr, instanceName)
+		if err != nil {
+			return err
+		}
+		// snaps that are not installed can always go back to the
+		// system's proxy configuration
+		if settings == nil {
+			continue
+		}
+		var snapst snapstate.SnapState
+		err = snapstate.Get(st, instanceName, &snapst)
+		if errors.Is(err, state.ErrNoState) {
+			return fmt.Errorf("cannot set proxy for snap %q: snap is not installed", instanceName)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func handleSnapProxyConfiguration(tr RunTransaction, opts *fsOnlyContext) error {
+	instanceNames := changedSnapProxies(tr)
+	if len(instanceNames) == 0 {
+		return nil
+	}
+
+	st := tr.State()
+	st.Lock()
+	defer st.Unlock()
+
+	for _, instanceName := range instanceNames {
+		settings, err := snapProxySettings(tr, instanceName)
+		if err != nil {
+			return err
+		}
+		err = snapstate.SetProxyOverride(st, instanceName, settings)
+		var notInstalled *snap.NotInstalledError
+		if settings == nil && errors.As(err, &notInstalled) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
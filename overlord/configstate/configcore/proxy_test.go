@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"time"
 
 	. "gopkg.in/check.v1"
@@ -34,7 +35,11 @@ import (
 	"github.com/snapcore/snapd/overlord/assertstate"
 	"github.com/snapcore/snapd/overlord/assertstate/assertstatetest"
 	"github.com/snapcore/snapd/overlord/configstate/configcore"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
 	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snapenv"
 	"github.com/snapcore/snapd/testutil"
 )
 
@@ -233,3 +238,141 @@ func (s *proxySuite) TestConfigureProxyStoreNoURL(c *C) {
 	err = configcore.Run(coreDev, conf)
 	c.Check(err, ErrorMatches, `cannot set proxy.store to "foo" with a matching store assertion with url unset`)
 }
+
+func (s *proxySuite) mockInstalledSnap(c *C, instanceName string) {
+	s.state.Lock()
+	defer s.state.Unlock()
+	si := &snap.SideInfo{RealName: instanceName, Revision: snap.R(1)}
+	snapstate.Set(s.state, instanceName, &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  snap.R(1),
+		Active:   true,
+		SnapType: "app",
+	})
+}
+
+func (s *proxySuite) snapProxyOverride(c *C, instanceName string) *snapstate.ProxySettings {
+	s.state.Lock()
+	defer s.state.Unlock()
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, instanceName, &snapst), IsNil)
+	return snapst.ProxyOverride
+}
+
+func (s *proxySuite) TestConfigureSnapProxy(c *C) {
+	s.mockInstalledSnap(c, "test-snap")
+
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"proxy.snaps.test-snap.http":     "http://proxy.example.com:3128",
+			"proxy.snaps.test-snap.https":    "http://proxy.example.com:3129",
+			"proxy.snaps.test-snap.no-proxy": "localhost",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.snapProxyOverride(c, "test-snap"), DeepEquals, &snapstate.ProxySettings{
+		HTTP:    "http://proxy.example.com:3128",
+		HTTPS:   "http://proxy.example.com:3129",
+		NoProxy: "localhost",
+	})
+	c.Check(snapenv.ProxyEnvFile("test-snap"), testutil.FileEquals, `http_proxy=http://proxy.example.com:3128
+HTTP_PROXY=http://proxy.example.com:3128
+https_proxy=http://proxy.example.com:3129
+HTTPS_PROXY=http://proxy.example.com:3129
+ftp_proxy=
+FTP_PROXY=
+no_proxy=localhost
+NO_PROXY=localhost
+`)
+}
+
+func (s *proxySuite) TestConfigureSnapProxyDirect(c *C) {
+	s.mockInstalledSnap(c, "test-snap")
+
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"proxy.snaps.test-snap.direct": true,
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.snapProxyOverride(c, "test-snap"), DeepEquals, &snapstate.ProxySettings{Direct: true})
+	c.Check(snapenv.ProxyEnvFile("test-snap"), testutil.FileContains, "http_proxy=\n")
+	c.Check(snapenv.ProxyEnvFile("test-snap"), testutil.FileContains, "no_proxy=*\n")
+}
+
+func (s *proxySuite) TestConfigureSnapProxyUnset(c *C) {
+	s.mockInstalledSnap(c, "test-snap")
+
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"proxy.snaps.test-snap.http": "http://proxy.example.com:3128",
+		},
+	})
+	c.Assert(err, IsNil)
+	c.Check(snapenv.ProxyEnvFile("test-snap"), testutil.FilePresent)
+
+	err = configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"proxy.snaps.test-snap.http": "",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	c.Check(s.snapProxyOverride(c, "test-snap"), IsNil)
+	c.Check(snapenv.ProxyEnvFile("test-snap"), testutil.FileAbsent)
+
+	// unsetting the proxy of a snap that isn't installed is fine
+	err = configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		changes: map[string]interface{}{
+			"proxy.snaps.other-snap.http": "",
+		},
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *proxySuite) TestConfigureSnapProxyErrors(c *C) {
+	s.mockInstalledSnap(c, "test-snap")
+
+	for _, tc := range []struct {
+		changes map[string]interface{}
+		err     string
+	}{
+		{
+			changes: map[string]interface{}{"proxy.snaps.other-snap.http": "http://proxy.example.com"},
+			err:     `cannot set proxy for snap "other-snap": snap is not installed`,
+		}, {
+			changes: map[string]interface{}{"proxy.snaps.test-snap.http": "proxy.example.com"},
+			err:     `cannot set proxy for snap "test-snap": invalid proxy URL "proxy.example.com"`,
+		}, {
+			changes: map[string]interface{}{"proxy.snaps.test-snap.direct": "maybe"},
+			err:     `cannot set proxy for snap "test-snap": direct can only be set to 'true' or 'false'`,
+		}, {
+			changes: map[string]interface{}{
+				"proxy.snaps.test-snap.direct": true,
+				"proxy.snaps.test-snap.http":   "http://proxy.example.com",
+			},
+			err: `cannot set proxy for snap "test-snap": direct access cannot be combined with proxy settings`,
+		}, {
+			changes: map[string]interface{}{"proxy.snaps.test-snap.socks": "socks://proxy.example.com"},
+			err:     `cannot set "core.proxy.snaps.test-snap.socks": unsupported system option`,
+		}, {
+			changes: map[string]interface{}{"proxy.snaps.Bad_Name.http": "http://proxy.example.com"},
+			err:     `cannot set "core.proxy.snaps.Bad_Name.http": unsupported system option`,
+		},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state:   s.state,
+			changes: tc.changes,
+		})
+		c.Check(err, ErrorMatches, regexp.QuoteMeta(tc.err))
+	}
+
+	c.Check(s.snapProxyOverride(c, "test-snap"), IsNil)
+}
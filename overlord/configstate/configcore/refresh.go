@@ -23,9 +23,11 @@ package configcore
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/snap/naming"
 	"github.com/snapcore/snapd/strutil"
 	"github.com/snapcore/snapd/timeutil"
 )
@@ -37,6 +39,10 @@ func init() {
 	supportedConfigurations["core.refresh.metered"] = true
 	supportedConfigurations["core.refresh.retain"] = true
 	supportedConfigurations["core.refresh.rate-limit"] = true
+	supportedConfigurations["core.refresh.snapd.cohort"] = true
+	supportedConfigurations["core.refresh.snapd.delay-days"] = true
+	supportedConfigurations["core.refresh.snapd.healthy-boots"] = true
+	supportedConfigurations["core.refresh.risk-fallback"] = true
 }
 
 func reportOrIgnoreInvalidManageRefreshes(tr RunTransaction, optName string) error {
@@ -145,3 +151,45 @@ func validateRefreshRateLimit(tr RunTransaction) error {
 	}
 	return nil
 }
+
+// validateSnapdRollout validates the policy for the staggered rollout of
+// snapd updates.
+func validateSnapdRollout(tr RunTransaction) error {
+	for _, opt := range []struct {
+		name string
+		max  uint64
+	}{
+		{"refresh.snapd.delay-days", 90},
+		{"refresh.snapd.healthy-boots", 100},
+	} {
+		value, err := coreCfg(tr, opt.name)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			continue
+		}
+		if n, err := strconv.ParseUint(value, 10, 8); err != nil || n > opt.max {
+			return fmt.Errorf("%s must be a number between 0 and %d, not %q", opt.name, opt.max, value)
+		}
+	}
+	return nil
+}
+
+// validateRiskFallback validates the comma separated list of snaps following
+// the risk fallback policy.
+func validateRiskFallback(tr RunTransaction) error {
+	snaps, err := coreCfg(tr, "refresh.risk-fallback")
+	if err != nil {
+		return err
+	}
+	if snaps == "" {
+		return nil
+	}
+	for _, name := range strings.Split(snaps, ",") {
+		if err := naming.ValidateInstance(strings.TrimSpace(name)); err != nil {
+			return fmt.Errorf("refresh.risk-fallback must be a comma separated list of snap names: %v", err)
+		}
+	}
+	return nil
+}
@@ -220,3 +220,54 @@ func (s *refreshSuite) TestConfigureRefreshRetainInvalid(c *C) {
 	})
 	c.Assert(err, ErrorMatches, `retain must be a number between 2 and 20, not "invalid"`)
 }
+
+func (s *refreshSuite) TestConfigureSnapdRolloutHappy(c *C) {
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]interface{}{
+			"refresh.snapd.cohort":        "MSBzxzSmdmSfrlxZ6cmGaRzpOBDHGM",
+			"refresh.snapd.delay-days":    7,
+			"refresh.snapd.healthy-boots": "3",
+		},
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *refreshSuite) TestConfigureSnapdRolloutRejected(c *C) {
+	for _, tc := range []struct {
+		opt   string
+		value interface{}
+		err   string
+	}{
+		{"refresh.snapd.delay-days", "a week", `refresh.snapd.delay-days must be a number between 0 and 90, not "a week"`},
+		{"refresh.snapd.delay-days", 91, `refresh.snapd.delay-days must be a number between 0 and 90, not "91"`},
+		{"refresh.snapd.delay-days", -1, `refresh.snapd.delay-days must be a number between 0 and 90, not "-1"`},
+		{"refresh.snapd.healthy-boots", 101, `refresh.snapd.healthy-boots must be a number between 0 and 100, not "101"`},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state: s.state,
+			conf: map[string]interface{}{
+				tc.opt: tc.value,
+			},
+		})
+		c.Check(err, ErrorMatches, tc.err)
+	}
+}
+
+func (s *refreshSuite) TestConfigureRiskFallback(c *C) {
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]interface{}{
+			"refresh.risk-fallback": "some-snap, other-snap_foo",
+		},
+	})
+	c.Assert(err, IsNil)
+
+	err = configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]interface{}{
+			"refresh.risk-fallback": "some-snap,,Invalid",
+		},
+	})
+	c.Assert(err, ErrorMatches, `refresh.risk-fallback must be a comma separated list of snap names: .*`)
+}
@@ -0,0 +1,50 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+func init() {
+	// add supported configuration of this module
+	supportedConfigurations["core.remote-api.listen-address"] = true
+}
+
+func validateRemoteAPISettings(tr RunTransaction) error {
+	addr, err := coreCfg(tr, "remote-api.listen-address")
+	if err != nil {
+		return err
+	}
+	if addr == "" {
+		return nil
+	}
+
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("cannot parse remote-api.listen-address: %v", err)
+	}
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return fmt.Errorf("cannot use %q as remote-api.listen-address port", port)
+	}
+	return nil
+}
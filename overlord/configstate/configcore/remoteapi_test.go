@@ -0,0 +1,63 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+)
+
+type remoteAPISuite struct {
+	configcoreSuite
+}
+
+var _ = Suite(&remoteAPISuite{})
+
+func (s *remoteAPISuite) TestConfigureRemoteAPIListenAddressHappy(c *C) {
+	for _, addr := range []string{"", ":8443", "0.0.0.0:443", "[::1]:8443"} {
+		err := configcore.Run(classicDev, &mockConf{
+			state: s.state,
+			conf: map[string]interface{}{
+				"remote-api.listen-address": addr,
+			},
+		})
+		c.Check(err, IsNil, Commentf("%q", addr))
+	}
+}
+
+func (s *remoteAPISuite) TestConfigureRemoteAPIListenAddressInvalid(c *C) {
+	for _, t := range []struct {
+		addr, err string
+	}{
+		{"localhost", `cannot parse remote-api.listen-address: address localhost: missing port in address`},
+		{":https", `cannot use "https" as remote-api.listen-address port`},
+		{":0", `cannot use "0" as remote-api.listen-address port`},
+		{":70000", `cannot use "70000" as remote-api.listen-address port`},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state: s.state,
+			conf: map[string]interface{}{
+				"remote-api.listen-address": t.addr,
+			},
+		})
+		c.Check(err, ErrorMatches, t.err, Commentf("%q", t.addr))
+	}
+}
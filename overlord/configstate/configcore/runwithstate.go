@@ -44,6 +44,12 @@ func init() {
 	addWithStateHandler(nil, handleProxyConfiguration, coreOnly)
 	// proxy.store
 	addWithStateHandler(validateProxyStore, handleProxyStore, nil)
+	// proxy.snaps.<snap>.{http,https,ftp,no-proxy,direct}
+	addWithStateHandler(validateSnapProxySettings, handleSnapProxyConfiguration, nil)
+	// dns.snaps.<snap>.{nameservers,disabled}
+	addWithStateHandler(validateSnapDNSSettings, handleSnapDNSConfiguration, nil)
+	// hardening.snaps.<snap>.{ptrace,core-dumps,memfd-exec}
+	addWithStateHandler(validateSnapHardeningSettings, handleSnapHardeningConfiguration, nil)
 
 	// resilience.vitality-hint
 	addWithStateHandler(validateVitalitySettings, handleVitalityConfiguration, nil)
@@ -59,7 +65,15 @@ func init() {
 	validateOnly := &flags{validatedOnlyStateConfig: true}
 	addWithStateHandler(validateRefreshSchedule, nil, validateOnly)
 	addWithStateHandler(validateRefreshRateLimit, nil, validateOnly)
+	addWithStateHandler(validateSnapdRollout, nil, validateOnly)
+	addWithStateHandler(validateRiskFallback, nil, validateOnly)
 	addWithStateHandler(validateAutomaticSnapshotsExpiration, nil, validateOnly)
+	addWithStateHandler(validateSnapshotsCompression, nil, validateOnly)
+	addWithStateHandler(validateProblemReportsSettings, nil, validateOnly)
+	addWithStateHandler(validateMeasurementsSettings, nil, validateOnly)
+	addWithStateHandler(validateUnassertedSnapsSettings, nil, validateOnly)
+	addWithStateHandler(validateRemoteAPISettings, nil, validateOnly)
+	addWithStateHandler(validateTimingsRetention, nil, validateOnly)
 
 	// netplan.*
 	addWithStateHandler(validateNetplanSettings, handleNetplanConfiguration, coreOnly)
@@ -156,6 +170,18 @@ func applyHandlers(dev sysconfig.Device, cfg RunTransaction, handlers []configHa
 			if !validCertOption(k) {
 				return fmt.Errorf("cannot set store ssl certificate under name %q: name must only contain word characters or a dash", k)
 			}
+		case strings.HasPrefix(k, snapProxyPrefix):
+			if !validSnapProxyOption(k) {
+				return fmt.Errorf("cannot set %q: unsupported system option", k)
+			}
+		case strings.HasPrefix(k, snapDNSPrefix):
+			if !validSnapDNSOption(k) {
+				return fmt.Errorf("cannot set %q: unsupported system option", k)
+			}
+		case strings.HasPrefix(k, snapHardeningPrefix):
+			if !validSnapHardeningOption(k) {
+				return fmt.Errorf("cannot set %q: unsupported system option", k)
+			}
 		case isNetplanChange(k):
 			if release.OnClassic {
 				return fmt.Errorf("cannot set netplan configuration on classic")
@@ -28,6 +28,7 @@ import (
 func init() {
 	// add supported configuration of this module
 	supportedConfigurations["core.snapshots.automatic.retention"] = true
+	supportedConfigurations["core.snapshots.compression"] = true
 }
 
 func validateAutomaticSnapshotsExpiration(tr RunTransaction) error {
@@ -46,3 +47,16 @@ func validateAutomaticSnapshotsExpiration(tr RunTransaction) error {
 	}
 	return nil
 }
+
+func validateSnapshotsCompression(tr RunTransaction) error {
+	compression, err := coreCfg(tr, "snapshots.compression")
+	if err != nil {
+		return err
+	}
+	switch compression {
+	case "", "gzip", "zstd":
+		return nil
+	default:
+		return fmt.Errorf("snapshots.compression must be one of \"gzip\" or \"zstd\", not %q", compression)
+	}
+}
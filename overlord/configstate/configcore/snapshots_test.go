@@ -71,3 +71,25 @@ func (s *refreshSuite) TestConfigureAutomaticSnapshotsExpirationInvalid(c *C) {
 	})
 	c.Assert(err, ErrorMatches, `snapshots.automatic.retention cannot be parsed:.*`)
 }
+
+func (s *snapshotsSuite) TestConfigureSnapshotsCompression(c *C) {
+	for _, comp := range []string{"gzip", "zstd"} {
+		err := configcore.Run(classicDev, &mockConf{
+			state: s.state,
+			conf: map[string]interface{}{
+				"snapshots.compression": comp,
+			},
+		})
+		c.Check(err, IsNil)
+	}
+}
+
+func (s *snapshotsSuite) TestConfigureSnapshotsCompressionInvalid(c *C) {
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]interface{}{
+			"snapshots.compression": "xz",
+		},
+	})
+	c.Assert(err, ErrorMatches, `snapshots.compression must be one of "gzip" or "zstd", not "xz"`)
+}
@@ -0,0 +1,47 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"fmt"
+	"time"
+)
+
+func init() {
+	// add supported configuration of this module
+	supportedConfigurations["core.debug.timings.retention"] = true
+}
+
+func validateTimingsRetention(tr RunTransaction) error {
+	retentionStr, err := coreCfg(tr, "debug.timings.retention")
+	if err != nil {
+		return err
+	}
+	if retentionStr != "" {
+		dur, err := time.ParseDuration(retentionStr)
+		if err != nil {
+			return fmt.Errorf("debug.timings.retention cannot be parsed: %v", err)
+		}
+		if dur < time.Hour*24 {
+			return fmt.Errorf("debug.timings.retention must be a value of at least 24 hours")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+)
+
+type timingsSuite struct {
+	configcoreSuite
+}
+
+var _ = Suite(&timingsSuite{})
+
+func (s *timingsSuite) TestConfigureTimingsRetentionHappy(c *C) {
+	for _, retention := range []string{"24h", "168h", ""} {
+		err := configcore.Run(classicDev, &mockConf{
+			state: s.state,
+			conf: map[string]interface{}{
+				"debug.timings.retention": retention,
+			},
+		})
+		c.Check(err, IsNil, Commentf("%q", retention))
+	}
+}
+
+func (s *timingsSuite) TestConfigureTimingsRetentionErrors(c *C) {
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]interface{}{
+			"debug.timings.retention": "10h",
+		},
+	})
+	c.Check(err, ErrorMatches, `debug.timings.retention must be a value of at least 24 hours`)
+
+	err = configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]interface{}{
+			"debug.timings.retention": "7 days",
+		},
+	})
+	c.Check(err, ErrorMatches, `debug.timings.retention cannot be parsed:.*`)
+}
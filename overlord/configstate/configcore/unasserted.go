@@ -0,0 +1,42 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore
+
+import (
+	"fmt"
+)
+
+func init() {
+	// add supported configuration of this module
+	supportedConfigurations["core.unasserted-snaps"] = true
+}
+
+func validateUnassertedSnapsSettings(tr RunTransaction) error {
+	policy, err := coreCfg(tr, "unasserted-snaps")
+	if err != nil {
+		return err
+	}
+	switch policy {
+	case "", "allow", "quarantine":
+		return nil
+	default:
+		return fmt.Errorf("unasserted-snaps can only be set to 'allow' or 'quarantine', not %q", policy)
+	}
+}
@@ -0,0 +1,54 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package configcore_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/configstate/configcore"
+)
+
+type unassertedSnapsSuite struct {
+	configcoreSuite
+}
+
+var _ = Suite(&unassertedSnapsSuite{})
+
+func (s *unassertedSnapsSuite) TestConfigureUnassertedSnapsHappy(c *C) {
+	for _, policy := range []string{"", "allow", "quarantine"} {
+		err := configcore.Run(classicDev, &mockConf{
+			state: s.state,
+			conf: map[string]interface{}{
+				"unasserted-snaps": policy,
+			},
+		})
+		c.Check(err, IsNil, Commentf("%q", policy))
+	}
+}
+
+func (s *unassertedSnapsSuite) TestConfigureUnassertedSnapsInvalid(c *C) {
+	err := configcore.Run(classicDev, &mockConf{
+		state: s.state,
+		conf: map[string]interface{}{
+			"unasserted-snaps": "deny",
+		},
+	})
+	c.Check(err, ErrorMatches, `unasserted-snaps can only be set to 'allow' or 'quarantine', not "deny"`)
+}
@@ -0,0 +1,172 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package devicestate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/overlord/measurestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/secboot"
+	"github.com/snapcore/snapd/snap"
+)
+
+// attestationPCRs are the PCRs quoted in attestation documents: the secure
+// boot policy, the snap model and system epoch measured at boot, and the
+// measurements of installed snaps.
+var attestationPCRs = []int{7, 12, secboot.SnapMeasurementsPCR}
+
+var (
+	secbootQuoteWhenPossible = secboot.QuoteWhenPossible
+	measurestateMeasurements = measurestate.Measurements
+)
+
+// AttestationDocument is the evidence about the integrity of the device
+// that is signed with the device key for attestation servers.
+type AttestationDocument struct {
+	// Nonce is the nonce provided by the attestation server, the quote
+	// is qualified with its SHA-256 digest.
+	Nonce string    `json:"nonce"`
+	Time  time.Time `json:"time"`
+
+	BrandID string `json:"brand-id"`
+	Model   string `json:"model"`
+	Grade   string `json:"grade,omitempty"`
+	Serial  string `json:"serial"`
+
+	Snaps []AttestedSnap `json:"snaps"`
+	// Measurements are the measurements of the snaps extended into
+	// the snap measurements PCR since boot.
+	Measurements []*measurestate.Entry `json:"measurements,omitempty"`
+	// Quote is unset if the device has no TPM.
+	Quote *secboot.Quote `json:"quote,omitempty"`
+}
+
+// AttestedSnap is the revision of an installed snap in an attestation
+// document.
+type AttestedSnap struct {
+	Name     string        `json:"name"`
+	SnapID   string        `json:"snap-id,omitempty"`
+	Revision snap.Revision `json:"revision"`
+	Type     snap.Type     `json:"type"`
+}
+
+// SignedAttestation is an attestation document with its signature by the
+// device key, the public part of which is carried by the serial assertion
+// of the device.
+type SignedAttestation struct {
+	// Document is the JSON encoded AttestationDocument that was signed.
+	Document json.RawMessage `json:"document"`
+	// Signature is the encoded signature of Document as checked by
+	// asserts.ContentSignatureCheck.
+	Signature string `json:"signature"`
+	// DeviceKeyID is the SHA3-384 ID of the device key.
+	DeviceKeyID string `json:"device-key-sha3-384"`
+}
+
+// Attestation produces an attestation document for the given nonce and
+// signs it with the device key. The device must be registered.
+func (m *DeviceManager) Attestation(nonce string) (*SignedAttestation, error) {
+	if nonce == "" {
+		return nil, fmt.Errorf("cannot produce attestation without a nonce")
+	}
+
+	device, err := m.device()
+	if err != nil {
+		return nil, err
+	}
+	if device.Serial == "" {
+		return nil, fmt.Errorf("cannot produce attestation: device is not registered yet")
+	}
+	privKey, err := m.keyPair()
+	if errors.Is(err, state.ErrNoState) {
+		return nil, fmt.Errorf("internal error: inconsistent state with serial but no device key")
+	}
+	if err != nil {
+		return nil, err
+	}
+	model, err := m.Model()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &AttestationDocument{
+		Nonce:   nonce,
+		Time:    timeNow().UTC(),
+		BrandID: device.Brand,
+		Model:   device.Model,
+		Serial:  device.Serial,
+	}
+	if model.Grade() != asserts.ModelGradeUnset {
+		doc.Grade = string(model.Grade())
+	}
+
+	allStates, err := snapstate.All(m.state)
+	if err != nil {
+		return nil, err
+	}
+	doc.Snaps = make([]AttestedSnap, 0, len(allStates))
+	for name, snapst := range allStates {
+		info, err := snapst.CurrentInfo()
+		if err != nil {
+			return nil, err
+		}
+		doc.Snaps = append(doc.Snaps, AttestedSnap{
+			Name:     name,
+			SnapID:   info.SnapID,
+			Revision: info.Revision,
+			Type:     info.Type(),
+		})
+	}
+	sort.Slice(doc.Snaps, func(i, j int) bool { return doc.Snaps[i].Name < doc.Snaps[j].Name })
+
+	doc.Measurements, err = measurestateMeasurements(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	qualifyingData := sha256.Sum256([]byte(nonce))
+	doc.Quote, err = secbootQuoteWhenPossible(qualifyingData[:], attestationPCRs)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := asserts.SignContent(body, privKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign attestation: %v", err)
+	}
+	return &SignedAttestation{
+		Document:    body,
+		Signature:   string(bytes.ReplaceAll(sig, []byte("\n"), nil)),
+		DeviceKeyID: privKey.PublicKey().ID(),
+	}, nil
+}
@@ -114,6 +114,8 @@ type DeviceManager struct {
 
 	ensureTriedRecoverySystemRan bool
 
+	ensureRecoveryRequestRan bool
+
 	cloudInitAlreadyRestricted           bool
 	cloudInitErrorAttemptStart           *time.Time
 	cloudInitEnabledInactiveAttemptStart *time.Time
@@ -124,6 +126,9 @@ type DeviceManager struct {
 	reg                          chan struct{}
 	noRegister                   bool
 
+	lastDeviceEventsFailure time.Time
+	deviceEventsBackoff     time.Duration
+
 	preseed            bool
 	preseedSystemLabel string
 
@@ -190,6 +195,8 @@ func Manager(s *state.State, hookManager *hookstate.HookManager, runner *state.T
 	// this *must* always run last and finalizes a remodel
 	runner.AddHandler("set-model", m.doSetModel, nil)
 	runner.AddCleanup("set-model", m.cleanupRemodel)
+	// accepts a reissue of the current model outside of a remodel
+	runner.AddHandler("reissue-model", m.doReissueModel, nil)
 	// There is no undo for successful gadget updates. The system is
 	// rebooted during update, if it boots up to the point where snapd runs
 	// we deem the new assets (be it bootloader or firmware) functional. The
@@ -212,6 +219,8 @@ func Manager(s *state.State, hookManager *hookstate.HookManager, runner *state.T
 	runner.AddHandler("install-finish", m.doInstallFinish, nil)
 	runner.AddHandler("install-setup-storage-encryption", m.doInstallSetupStorageEncryption, nil)
 
+	runner.AddHandler("scheduled-reboot", m.doScheduledReboot, m.undoScheduledReboot)
+
 	runner.AddBlocked(gadgetUpdateBlocked)
 
 	// wire FDE kernel hook support into boot
@@ -1100,6 +1109,30 @@ func (m *DeviceManager) ensureBootOk() error {
 	return nil
 }
 
+// gadgetCloudInitPolicy returns the cloud-init policy declared by the
+// gadget, or nil if there is no gadget or it declares none.
+func (m *DeviceManager) gadgetCloudInitPolicy() (*gadget.CloudInit, error) {
+	deviceCtx, err := DeviceCtx(m.state, nil, nil)
+	if err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	gadgetInfo, err := snapstate.GadgetInfo(m.state, deviceCtx)
+	if err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ginfo, err := gadget.ReadInfo(gadgetInfo.MountDir(), deviceCtx.Model())
+	if err != nil {
+		return nil, err
+	}
+	return ginfo.CloudInit, nil
+}
+
 func (m *DeviceManager) ensureCloudInitRestricted() error {
 	m.state.Lock()
 	defer m.state.Unlock()
@@ -1240,6 +1273,15 @@ func (m *DeviceManager) ensureCloudInitRestricted() error {
 		opts.DisableAfterLocalDatasourcesRun = true
 	}
 
+	// the gadget can restrict which datasources may keep being used after
+	// first boot, without a policy cloud-init is restricted as usual
+	cloudInitPolicy, err := m.gadgetCloudInitPolicy()
+	if err != nil {
+		logger.Noticef("cannot read gadget cloud-init policy, ignoring it: %v", err)
+	} else if cloudInitPolicy != nil {
+		opts.AllowedDatasources = cloudInitPolicy.AllowedDatasources
+	}
+
 	// now restrict/disable cloud-init
 	res, err := restrictCloudInit(cloudInitStatus, opts)
 	if err != nil {
@@ -1656,7 +1698,14 @@ func (m *DeviceManager) ensurePostFactoryReset() error {
 		}
 	}
 
-	return os.Remove(factoryResetMarker)
+	if err := os.Remove(factoryResetMarker); err != nil {
+		return err
+	}
+
+	emitDeviceEvent(m.state, DeviceEventFactoryReset, map[string]interface{}{
+		"encrypted": encrypted,
+	})
+	return nil
 }
 
 // ensureExpiredUsersRemoved is periodically called as a part of Ensure()
@@ -1763,6 +1812,10 @@ func (m *DeviceManager) Ensure() error {
 			errs = append(errs, err)
 		}
 
+		if err := m.ensureRecoveryRequest(); err != nil {
+			errs = append(errs, err)
+		}
+
 		if err := m.ensureFactoryReset(); err != nil {
 			errs = append(errs, err)
 		}
@@ -1774,6 +1827,10 @@ func (m *DeviceManager) Ensure() error {
 		if err := m.ensureExpiredUsersRemoved(); err != nil {
 			errs = append(errs, err)
 		}
+
+		if err := m.ensureDeviceEventsDelivered(); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	if len(errs) > 0 {
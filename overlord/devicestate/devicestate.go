@@ -264,6 +264,7 @@ func delayedCrossMgrInit() {
 		snapstate.AddCheckSnapCallback(checkGadgetOrKernel)
 		snapstate.AddCheckSnapCallback(checkGadgetValid)
 		snapstate.AddCheckSnapCallback(checkGadgetRemodelCompatible)
+		snapstate.AddLinkSnapParticipant(snapstate.LinkSnapParticipantFunc(kernelModulesOnLinkageChanged))
 	})
 	snapstate.CanAutoRefresh = canAutoRefresh
 	snapstate.CanManageRefreshes = CanManageRefreshes
@@ -0,0 +1,178 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package devicestate_test
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/asserts/assertstest"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/overlord/devicestate/devicestatetest"
+	"github.com/snapcore/snapd/overlord/measurestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/secboot"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+)
+
+type deviceMgrAttestationSuite struct {
+	deviceMgrBaseSuite
+
+	devKey asserts.PrivateKey
+	now    time.Time
+}
+
+var _ = Suite(&deviceMgrAttestationSuite{})
+
+func (s *deviceMgrAttestationSuite) SetUpTest(c *C) {
+	classic := false
+	s.setupBaseTest(c, classic)
+
+	s.now = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.AddCleanup(devicestate.MockTimeNow(func() time.Time { return s.now }))
+
+	s.devKey, _ = assertstest.GenerateKey(testKeyLength)
+	s.state.Lock()
+	defer s.state.Unlock()
+	s.makeModelAssertionInState(c, "canonical", "pc", map[string]interface{}{
+		"architecture": "amd64",
+		"kernel":       "pc-kernel",
+		"gadget":       "pc",
+		"base":         "core20",
+	})
+	devicestatetest.SetDevice(s.state, &auth.DeviceState{
+		Brand:  "canonical",
+		Model:  "pc",
+		Serial: "serialserialserial",
+		KeyID:  s.devKey.PublicKey().ID(),
+	})
+	devicestate.KeypairManager(s.mgr).Put(s.devKey)
+
+	for _, sn := range []struct {
+		name, yaml string
+		rev        int
+	}{
+		{"pc", "name: pc\ntype: gadget\nversion: 1\n", 3},
+		{"core20", "name: core20\ntype: base\nversion: 1\n", 12},
+	} {
+		si := &snap.SideInfo{RealName: sn.name, SnapID: snaptest.AssertedSnapID(sn.name), Revision: snap.R(sn.rev)}
+		info := snaptest.MockSnap(c, sn.yaml, si)
+		snapstate.Set(s.state, sn.name, &snapstate.SnapState{
+			Active:   true,
+			Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+			Current:  si.Revision,
+			SnapType: string(info.Type()),
+		})
+	}
+}
+
+func (s *deviceMgrAttestationSuite) TestAttestation(c *C) {
+	measurements := []*measurestate.Entry{{
+		Time:     s.now,
+		Kind:     measurestate.KindSnapBlob,
+		Snap:     "pc",
+		Revision: snap.R(3),
+		Path:     "/var/lib/snapd/snaps/pc_3.snap",
+		Digest:   "sha256:1234",
+		PCR:      23,
+	}}
+	restore := devicestate.MockMeasurestateMeasurements(func(names []string) ([]*measurestate.Entry, error) {
+		c.Check(names, IsNil)
+		return measurements, nil
+	})
+	defer restore()
+	quote := &secboot.Quote{
+		Attest:         []byte("attest"),
+		Signature:      []byte("signature"),
+		AttestationKey: []byte("key"),
+		PCRs:           []int{7, 12, 23},
+	}
+	restore = devicestate.MockSecbootQuoteWhenPossible(func(qualifyingData []byte, pcrs []int) (*secboot.Quote, error) {
+		nonceDigest := sha256.Sum256([]byte("some-nonce"))
+		c.Check(qualifyingData, DeepEquals, nonceDigest[:])
+		c.Check(pcrs, DeepEquals, []int{7, 12, 23})
+		return quote, nil
+	})
+	defer restore()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	signed, err := s.mgr.Attestation("some-nonce")
+	c.Assert(err, IsNil)
+	c.Check(signed.DeviceKeyID, Equals, s.devKey.PublicKey().ID())
+	c.Check(signed.Signature, Not(Matches), "(?s).*\n.*")
+	c.Check(asserts.ContentSignatureCheck(signed.Document, []byte(signed.Signature), s.devKey.PublicKey()), IsNil)
+
+	var doc devicestate.AttestationDocument
+	c.Assert(json.Unmarshal(signed.Document, &doc), IsNil)
+	c.Check(doc, DeepEquals, devicestate.AttestationDocument{
+		Nonce:   "some-nonce",
+		Time:    s.now,
+		BrandID: "canonical",
+		Model:   "pc",
+		Serial:  "serialserialserial",
+		Snaps: []devicestate.AttestedSnap{
+			{Name: "core20", SnapID: snaptest.AssertedSnapID("core20"), Revision: snap.R(12), Type: snap.TypeBase},
+			{Name: "pc", SnapID: snaptest.AssertedSnapID("pc"), Revision: snap.R(3), Type: snap.TypeGadget},
+		},
+		Measurements: measurements,
+		Quote:        quote,
+	})
+}
+
+func (s *deviceMgrAttestationSuite) TestAttestationNoTPM(c *C) {
+	restore := devicestate.MockSecbootQuoteWhenPossible(func(qualifyingData []byte, pcrs []int) (*secboot.Quote, error) {
+		return nil, nil
+	})
+	defer restore()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	signed, err := s.mgr.Attestation("some-nonce")
+	c.Assert(err, IsNil)
+	var doc map[string]interface{}
+	c.Assert(json.Unmarshal(signed.Document, &doc), IsNil)
+	c.Check(doc["quote"], IsNil)
+	c.Check(doc["measurements"], IsNil)
+}
+
+func (s *deviceMgrAttestationSuite) TestAttestationErrors(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	_, err := s.mgr.Attestation("")
+	c.Check(err, ErrorMatches, "cannot produce attestation without a nonce")
+
+	devicestatetest.SetDevice(s.state, &auth.DeviceState{
+		Brand: "canonical",
+		Model: "pc",
+	})
+	_, err = s.mgr.Attestation("some-nonce")
+	c.Check(err, ErrorMatches, "cannot produce attestation: device is not registered yet")
+}
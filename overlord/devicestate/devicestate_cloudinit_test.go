@@ -15,7 +15,11 @@ import (
 	"github.com/snapcore/snapd/overlord/auth"
 	"github.com/snapcore/snapd/overlord/devicestate"
 	"github.com/snapcore/snapd/overlord/devicestate/devicestatetest"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
 	"github.com/snapcore/snapd/release"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/sysconfig"
 	"github.com/snapcore/snapd/testutil"
 )
@@ -137,6 +141,100 @@ func (s *cloudInitUC20Suite) TestCloudInitUC20CloudGadgetNoDisable(c *C) {
 	c.Assert(strings.TrimSpace(s.logbuf.String()), Matches, `.*System initialized, cloud-init reported to be done, set datasource_list to \[ GCE \].*`)
 }
 
+func (s *cloudInitUC20Suite) TestCloudInitUC20GadgetAllowedDatasources(c *C) {
+	s.state.Lock()
+	si := &snap.SideInfo{
+		RealName: "pc",
+		Revision: snap.R(1),
+		SnapID:   "pcididididididididididididididid",
+	}
+	snapstate.Set(s.state, "pc", &snapstate.SnapState{
+		SnapType: "gadget",
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  si.Revision,
+		Active:   true,
+	})
+	snaptest.MockSnapWithFiles(c, pcGadgetSnapYaml, si, [][]string{
+		{"meta/gadget.yaml", uc20gadgetYaml + `
+cloud-init:
+  allowed-datasources: [MAAS]
+`},
+	})
+	s.state.Unlock()
+
+	r := devicestate.MockCloudInitStatus(func() (sysconfig.CloudInitState, error) {
+		return sysconfig.CloudInitDone, nil
+	})
+	defer r()
+
+	restrictCalls := 0
+	r = devicestate.MockRestrictCloudInit(func(state sysconfig.CloudInitState, opts *sysconfig.CloudInitRestrictOptions) (sysconfig.CloudInitRestrictionResult, error) {
+		restrictCalls++
+		c.Assert(opts, DeepEquals, &sysconfig.CloudInitRestrictOptions{
+			DisableAfterLocalDatasourcesRun: true,
+			AllowedDatasources:              []string{"MAAS"},
+		})
+		// the datasource was not allowed by the gadget
+		return sysconfig.CloudInitRestrictionResult{
+			Action:     "disable",
+			DataSource: "GCE",
+		}, nil
+	})
+	defer r()
+
+	err := devicestate.EnsureCloudInitRestricted(s.mgr)
+	c.Assert(err, IsNil)
+	c.Assert(restrictCalls, Equals, 1)
+	c.Assert(strings.TrimSpace(s.logbuf.String()), Matches, `.*System initialized, cloud-init reported to be done, disabled permanently.*`)
+}
+
+func (s *cloudInitUC20Suite) TestCloudInitUC20GadgetReadErrorIgnored(c *C) {
+	s.state.Lock()
+	si := &snap.SideInfo{
+		RealName: "pc",
+		Revision: snap.R(1),
+		SnapID:   "pcididididididididididididididid",
+	}
+	snapstate.Set(s.state, "pc", &snapstate.SnapState{
+		SnapType: "gadget",
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  si.Revision,
+		Active:   true,
+	})
+	snaptest.MockSnapWithFiles(c, pcGadgetSnapYaml, si, [][]string{
+		{"meta/gadget.yaml", uc20gadgetYaml + `
+cloud-init:
+  allowed-datasources: ["-bad-"]
+`},
+	})
+	s.state.Unlock()
+
+	r := devicestate.MockCloudInitStatus(func() (sysconfig.CloudInitState, error) {
+		return sysconfig.CloudInitDone, nil
+	})
+	defer r()
+
+	restrictCalls := 0
+	r = devicestate.MockRestrictCloudInit(func(state sysconfig.CloudInitState, opts *sysconfig.CloudInitRestrictOptions) (sysconfig.CloudInitRestrictionResult, error) {
+		restrictCalls++
+		// cloud-init is restricted as without a gadget policy
+		c.Assert(opts, DeepEquals, &sysconfig.CloudInitRestrictOptions{
+			DisableAfterLocalDatasourcesRun: true,
+		})
+		return sysconfig.CloudInitRestrictionResult{
+			Action:     "restrict",
+			DataSource: "GCE",
+		}, nil
+	})
+	defer r()
+
+	err := devicestate.EnsureCloudInitRestricted(s.mgr)
+	c.Assert(err, IsNil)
+	c.Assert(restrictCalls, Equals, 1)
+	c.Check(s.logbuf.String(), testutil.Contains, "cannot read gadget cloud-init policy, ignoring it: ")
+	c.Check(s.logbuf.String(), Matches, `(?s).*System initialized, cloud-init reported to be done, set datasource_list to \[ GCE \].*`)
+}
+
 func (s *cloudInitUC20Suite) TestCloudInitUC20NoCloudGadgetDisables(c *C) {
 	// pretend that cloud-init never ran
 	statusCalls := 0
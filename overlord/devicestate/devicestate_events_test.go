@@ -0,0 +1,289 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package devicestate_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/asserts/assertstest"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/overlord/devicestate/devicestatetest"
+)
+
+type deviceMgrEventsSuite struct {
+	deviceMgrBaseSuite
+
+	devKey asserts.PrivateKey
+
+	received []*devicestate.DeviceEvent
+	status   int
+}
+
+var _ = Suite(&deviceMgrEventsSuite{})
+
+func (s *deviceMgrEventsSuite) SetUpTest(c *C) {
+	classic := false
+	s.setupBaseTest(c, classic)
+
+	s.received = nil
+	s.status = 200
+
+	s.devKey, _ = assertstest.GenerateKey(testKeyLength)
+	s.state.Lock()
+	defer s.state.Unlock()
+	s.makeModelAssertionInState(c, "canonical", "pc", map[string]interface{}{
+		"architecture": "amd64",
+		"kernel":       "pc-kernel",
+		"gadget":       "pc",
+	})
+	devicestatetest.SetDevice(s.state, &auth.DeviceState{
+		Brand:  "canonical",
+		Model:  "pc",
+		Serial: "serialserialserial",
+		KeyID:  s.devKey.PublicKey().ID(),
+	})
+	devicestate.KeypairManager(s.mgr).Put(s.devKey)
+}
+
+func (s *deviceMgrEventsSuite) mockEventsServer(c *C) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "POST")
+		c.Check(r.Header.Get("Content-Type"), Equals, "application/json")
+		c.Check(r.Header.Get("Snap-Device-Key-SHA3-384"), Equals, s.devKey.PublicKey().ID())
+		body, err := io.ReadAll(r.Body)
+		c.Assert(err, IsNil)
+		sig := r.Header.Get("Snap-Device-Event-Signature")
+		c.Check(asserts.ContentSignatureCheck(body, []byte(sig), s.devKey.PublicKey()), IsNil)
+
+		var ev devicestate.DeviceEvent
+		c.Assert(json.Unmarshal(body, &ev), IsNil)
+		s.received = append(s.received, &ev)
+		w.WriteHeader(s.status)
+	}))
+	s.AddCleanup(server.Close)
+	return server
+}
+
+func (s *deviceMgrEventsSuite) setEventsURL(c *C, url string) {
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("pc", "device-service.events-url", url), IsNil)
+	tr.Commit()
+}
+
+func (s *deviceMgrEventsSuite) pendingEvents(c *C) []*devicestate.DeviceEvent {
+	var pending []*devicestate.DeviceEvent
+	err := s.state.Get("device-events", &pending)
+	if err != nil {
+		c.Assert(err, ErrorMatches, `no state entry for key "device-events"`)
+	}
+	return pending
+}
+
+func (s *deviceMgrEventsSuite) TestEmitDeviceEventNoEndpoint(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	err := devicestate.EmitDeviceEvent(s.state, devicestate.DeviceEventRegistered, nil)
+	c.Assert(err, IsNil)
+	c.Check(s.pendingEvents(c), HasLen, 0)
+}
+
+func (s *deviceMgrEventsSuite) TestEmitDeviceEventQueued(c *C) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	defer devicestate.MockTimeNow(func() time.Time { return now })()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setEventsURL(c, "https://fleet.example.com/events")
+
+	err := devicestate.EmitDeviceEvent(s.state, devicestate.DeviceEventRemodelFinished, map[string]interface{}{
+		"previous-model": "pc-old",
+	})
+	c.Assert(err, IsNil)
+
+	pending := s.pendingEvents(c)
+	c.Assert(pending, HasLen, 1)
+	c.Check(pending[0].ID, Not(Equals), "")
+	c.Check(pending[0], DeepEquals, &devicestate.DeviceEvent{
+		ID:      pending[0].ID,
+		Type:    devicestate.DeviceEventRemodelFinished,
+		BrandID: "canonical",
+		Model:   "pc",
+		Serial:  "serialserialserial",
+		Time:    now,
+		Data:    map[string]interface{}{"previous-model": "pc-old"},
+	})
+
+	// the oldest events are dropped
+	for i := 0; i < 100; i++ {
+		err := devicestate.EmitDeviceEvent(s.state, devicestate.DeviceEventFactoryReset, nil)
+		c.Assert(err, IsNil)
+	}
+	pending = s.pendingEvents(c)
+	c.Assert(pending, HasLen, 100)
+	for _, ev := range pending {
+		c.Check(ev.Type, Equals, devicestate.DeviceEventFactoryReset)
+	}
+}
+
+func (s *deviceMgrEventsSuite) TestDeliverDeviceEventsHappy(c *C) {
+	server := s.mockEventsServer(c)
+
+	s.state.Lock()
+	s.setEventsURL(c, server.URL)
+	c.Assert(devicestate.EmitDeviceEvent(s.state, devicestate.DeviceEventRegistered, nil), IsNil)
+	c.Assert(devicestate.EmitDeviceEvent(s.state, devicestate.DeviceEventFactoryReset, map[string]interface{}{
+		"encrypted": true,
+	}), IsNil)
+	pending := s.pendingEvents(c)
+	s.state.Unlock()
+
+	err := devicestate.EnsureDeviceEventsDelivered(s.mgr)
+	c.Assert(err, IsNil)
+
+	c.Assert(s.received, HasLen, 2)
+	c.Check(s.received[0].ID, Equals, pending[0].ID)
+	c.Check(s.received[0].Type, Equals, devicestate.DeviceEventRegistered)
+	c.Check(s.received[0].Serial, Equals, "serialserialserial")
+	c.Check(s.received[1].ID, Equals, pending[1].ID)
+	c.Check(s.received[1].Type, Equals, devicestate.DeviceEventFactoryReset)
+	c.Check(s.received[1].Data, DeepEquals, map[string]interface{}{"encrypted": true})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Check(s.pendingEvents(c), HasLen, 0)
+	c.Check(devicestate.DeviceEventsBackoff(s.mgr), Equals, time.Duration(0))
+}
+
+func (s *deviceMgrEventsSuite) TestDeliverDeviceEventsNoDeviceKey(c *C) {
+	server := s.mockEventsServer(c)
+
+	s.state.Lock()
+	s.setEventsURL(c, server.URL)
+	c.Assert(devicestate.EmitDeviceEvent(s.state, devicestate.DeviceEventRegistered, nil), IsNil)
+	devicestatetest.SetDevice(s.state, &auth.DeviceState{
+		Brand: "canonical",
+		Model: "pc",
+	})
+	s.state.Unlock()
+
+	err := devicestate.EnsureDeviceEventsDelivered(s.mgr)
+	c.Assert(err, IsNil)
+	c.Check(s.received, HasLen, 0)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Check(s.pendingEvents(c), HasLen, 1)
+}
+
+func (s *deviceMgrEventsSuite) TestDeliverDeviceEventsFailureBackoff(c *C) {
+	now := time.Now()
+	defer devicestate.MockTimeNow(func() time.Time { return now })()
+
+	server := s.mockEventsServer(c)
+	s.status = 500
+
+	s.state.Lock()
+	s.setEventsURL(c, server.URL)
+	c.Assert(devicestate.EmitDeviceEvent(s.state, devicestate.DeviceEventRegistered, nil), IsNil)
+	c.Assert(devicestate.EmitDeviceEvent(s.state, devicestate.DeviceEventFactoryReset, nil), IsNil)
+	s.state.Unlock()
+
+	err := devicestate.EnsureDeviceEventsDelivered(s.mgr)
+	c.Assert(err, ErrorMatches, "cannot deliver device events: unexpected status 500")
+	// delivery stopped at the first failure
+	c.Check(s.received, HasLen, 1)
+	c.Check(devicestate.DeviceEventsBackoff(s.mgr), Equals, time.Minute)
+
+	s.state.Lock()
+	c.Check(s.pendingEvents(c), HasLen, 2)
+	s.state.Unlock()
+
+	// backing off
+	err = devicestate.EnsureDeviceEventsDelivered(s.mgr)
+	c.Assert(err, IsNil)
+	c.Check(s.received, HasLen, 1)
+
+	// still failing, the backoff grows
+	now = now.Add(2 * time.Minute)
+	err = devicestate.EnsureDeviceEventsDelivered(s.mgr)
+	c.Assert(err, NotNil)
+	c.Check(s.received, HasLen, 2)
+	c.Check(devicestate.DeviceEventsBackoff(s.mgr), Equals, 2*time.Minute)
+
+	// eventually it works
+	s.status = 200
+	now = now.Add(3 * time.Minute)
+	err = devicestate.EnsureDeviceEventsDelivered(s.mgr)
+	c.Assert(err, IsNil)
+	c.Check(s.received, HasLen, 4)
+	c.Check(devicestate.DeviceEventsBackoff(s.mgr), Equals, time.Duration(0))
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Check(s.pendingEvents(c), HasLen, 0)
+}
+
+func (s *deviceMgrEventsSuite) TestDeliverDeviceEventsRejected(c *C) {
+	server := s.mockEventsServer(c)
+	s.status = 400
+
+	s.state.Lock()
+	s.setEventsURL(c, server.URL)
+	c.Assert(devicestate.EmitDeviceEvent(s.state, devicestate.DeviceEventRegistered, nil), IsNil)
+	s.state.Unlock()
+
+	// the event will never be accepted, it is dropped
+	err := devicestate.EnsureDeviceEventsDelivered(s.mgr)
+	c.Assert(err, IsNil)
+	c.Check(s.received, HasLen, 1)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Check(s.pendingEvents(c), HasLen, 0)
+}
+
+func (s *deviceMgrEventsSuite) TestDeliverDeviceEventsEndpointUnset(c *C) {
+	server := s.mockEventsServer(c)
+
+	s.state.Lock()
+	s.setEventsURL(c, server.URL)
+	c.Assert(devicestate.EmitDeviceEvent(s.state, devicestate.DeviceEventRegistered, nil), IsNil)
+	s.setEventsURL(c, "")
+	s.state.Unlock()
+
+	err := devicestate.EnsureDeviceEventsDelivered(s.mgr)
+	c.Assert(err, IsNil)
+	c.Check(s.received, HasLen, 0)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Check(s.pendingEvents(c), HasLen, 0)
+}
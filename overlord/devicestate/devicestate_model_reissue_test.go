@@ -0,0 +1,339 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package devicestate_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/asserts/assertstest"
+	"github.com/snapcore/snapd/overlord/assertstate/assertstatetest"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/overlord/devicestate/devicestatetest"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+type deviceMgrModelReissueSuite struct {
+	deviceMgrBaseSuite
+}
+
+var _ = Suite(&deviceMgrModelReissueSuite{})
+
+func (s *deviceMgrModelReissueSuite) SetUpTest(c *C) {
+	classic := false
+	s.setupBaseTest(c, classic)
+}
+
+func (s *deviceMgrModelReissueSuite) setModelInState(c *C, extras map[string]interface{}) *asserts.Model {
+	s.state.Set("seeded", true)
+	model := s.makeModelAssertionInState(c, "my-brand", "my-model", extras)
+	devicestatetest.SetDevice(s.state, &auth.DeviceState{
+		Brand:  "my-brand",
+		Model:  "my-model",
+		Serial: "serialserialserial",
+	})
+	return model
+}
+
+func (s *deviceMgrModelReissueSuite) TestReissueModelHappy(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setModelInState(c, modelDefaults)
+	newModel := s.brands.Model("my-brand", "my-model", modelDefaults, map[string]interface{}{
+		"revision":     "1",
+		"display-name": "My Model",
+	})
+
+	chg, err := devicestate.ReissueModel(s.state, newModel)
+	c.Assert(err, IsNil)
+	c.Check(chg.Kind(), Equals, "reissue-model")
+	c.Check(chg.Summary(), Equals, "Reissue model assertion my-brand/my-model from revision 0 to 1")
+	tasks := chg.Tasks()
+	c.Assert(tasks, HasLen, 1)
+	c.Check(tasks[0].Kind(), Equals, "reissue-model")
+
+	s.state.Unlock()
+	s.settle(c)
+	s.state.Lock()
+
+	c.Assert(chg.Err(), IsNil)
+	c.Check(chg.Status(), Equals, state.DoneStatus)
+
+	model, err := s.mgr.Model()
+	c.Assert(err, IsNil)
+	c.Check(model.Revision(), Equals, 1)
+	c.Check(model.DisplayName(), Equals, "My Model")
+}
+
+func (s *deviceMgrModelReissueSuite) TestReissueModelRotatedKey(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setModelInState(c, modelDefaults)
+
+	// a new key for the brand
+	rotatedPrivKey, _ := assertstest.GenerateKey(752)
+	rotatedKey := assertstest.NewAccountKey(s.storeSigning, s.brands.Account("my-brand"), map[string]interface{}{
+		"name": "rotated",
+	}, rotatedPrivKey.PublicKey(), "")
+	assertstatetest.AddMany(s.state, rotatedKey)
+
+	headers := map[string]interface{}{
+		"series":    "16",
+		"brand-id":  "my-brand",
+		"model":     "my-model",
+		"revision":  "1",
+		"timestamp": time.Now().Add(time.Hour).Format(time.RFC3339),
+	}
+	for k, v := range modelDefaults {
+		headers[k] = v
+	}
+	a, err := assertstest.NewSigningDB("my-brand", rotatedPrivKey).Sign(asserts.ModelType, headers, nil, "")
+	c.Assert(err, IsNil)
+	newModel := a.(*asserts.Model)
+
+	preview, err := devicestate.PreviewModelReissue(s.state, newModel)
+	c.Assert(err, IsNil)
+	c.Check(preview.Err, IsNil)
+	var headerNames []string
+	for _, diff := range preview.Differences {
+		headerNames = append(headerNames, diff.Header)
+	}
+	c.Check(headerNames, DeepEquals, []string{"revision", "sign-key-sha3-384", "timestamp"})
+
+	chg, err := devicestate.ReissueModel(s.state, newModel)
+	c.Assert(err, IsNil)
+
+	s.state.Unlock()
+	s.settle(c)
+	s.state.Lock()
+
+	c.Assert(chg.Err(), IsNil)
+	model, err := s.mgr.Model()
+	c.Assert(err, IsNil)
+	c.Check(model.Revision(), Equals, 1)
+	c.Check(model.SignKeyID(), Equals, rotatedKey.PublicKeyID())
+}
+
+func (s *deviceMgrModelReissueSuite) TestPreviewModelReissue(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setModelInState(c, modelDefaults)
+	newModel := s.brands.Model("my-brand", "my-model", modelDefaults, map[string]interface{}{
+		"revision":     "2",
+		"display-name": "My Model",
+	})
+
+	preview, err := devicestate.PreviewModelReissue(s.state, newModel)
+	c.Assert(err, IsNil)
+	c.Check(preview.Err, IsNil)
+	c.Check(preview.Differences, DeepEquals, []devicestate.ModelDifference{{
+		Header: "display-name",
+		New:    "My Model",
+	}, {
+		Header: "revision",
+		New:    "2",
+	}})
+}
+
+func (s *deviceMgrModelReissueSuite) TestPreviewModelReissueNeedsRemodel(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setModelInState(c, modelDefaults)
+	newModel := s.brands.Model("my-brand", "my-model", modelDefaults, map[string]interface{}{
+		"revision":       "1",
+		"required-snaps": []interface{}{"required1", "required2"},
+	})
+
+	preview, err := devicestate.PreviewModelReissue(s.state, newModel)
+	c.Assert(err, IsNil)
+	c.Check(preview.Err, ErrorMatches, `cannot reissue model my-brand/my-model with a different "required-snaps" header, a remodel is required`)
+	c.Check(preview.Differences, DeepEquals, []devicestate.ModelDifference{{
+		Header: "required-snaps",
+		Old:    []interface{}{"required1"},
+		New:    []interface{}{"required1", "required2"},
+	}, {
+		Header: "revision",
+		New:    "1",
+	}})
+
+	_, err = devicestate.ReissueModel(s.state, newModel)
+	c.Assert(err, ErrorMatches, `cannot reissue model my-brand/my-model with a different "required-snaps" header, a remodel is required`)
+}
+
+func (s *deviceMgrModelReissueSuite) TestReissueModelErrors(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setModelInState(c, modelDefaults)
+	s.makeModelAssertionInState(c, "my-brand", "my-model", map[string]interface{}{
+		"architecture":   "amd64",
+		"kernel":         "my-brand-kernel",
+		"gadget":         "my-brand-gadget",
+		"store":          "my-brand-store",
+		"required-snaps": []interface{}{"required1"},
+		"revision":       "3",
+	})
+
+	otherPrivKey, _ := assertstest.GenerateKey(752)
+	otherHeaders := map[string]interface{}{
+		"series":    "16",
+		"brand-id":  "my-brand",
+		"model":     "my-model",
+		"revision":  "4",
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+	for k, v := range modelDefaults {
+		otherHeaders[k] = v
+	}
+	a, err := assertstest.NewSigningDB("my-brand", otherPrivKey).Sign(asserts.ModelType, otherHeaders, nil, "")
+	c.Assert(err, IsNil)
+	unknownKeyModel := a.(*asserts.Model)
+
+	for _, tc := range []struct {
+		model *asserts.Model
+		err   string
+	}{{
+		model: s.brands.Model("my-brand", "other-model", modelDefaults, map[string]interface{}{
+			"revision": "4",
+		}),
+		err: `cannot reissue model my-brand/my-model as my-brand/other-model, a remodel is required`,
+	}, {
+		model: s.brands.Model("my-brand", "my-model", modelDefaults, map[string]interface{}{
+			"revision": "3",
+		}),
+		err: `cannot reissue model my-brand/my-model with revision 3 not newer than the current revision 3`,
+	}, {
+		model: s.brands.Model("my-brand", "my-model", modelDefaults, map[string]interface{}{
+			"revision": "2",
+		}),
+		err: `cannot reissue model my-brand/my-model with revision 2 not newer than the current revision 3`,
+	}, {
+		model: unknownKeyModel,
+		err:   `cannot reissue model my-brand/my-model: no matching public key .* for signature by "my-brand"`,
+	}} {
+		_, err := devicestate.ReissueModel(s.state, tc.model)
+		c.Check(err, ErrorMatches, tc.err)
+	}
+	c.Check(s.state.Changes(), HasLen, 0)
+}
+
+func (s *deviceMgrModelReissueSuite) TestReissueModelNotSeeded(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setModelInState(c, modelDefaults)
+	s.state.Set("seeded", false)
+	newModel := s.brands.Model("my-brand", "my-model", modelDefaults, map[string]interface{}{
+		"revision": "1",
+	})
+
+	_, err := devicestate.ReissueModel(s.state, newModel)
+	c.Assert(err, ErrorMatches, "cannot reissue model until fully seeded")
+}
+
+func (s *deviceMgrModelReissueSuite) TestReissueModelUC20DifferentKey(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	uc20Headers := map[string]interface{}{
+		"architecture": "amd64",
+		"grade":        "dangerous",
+		"base":         "core20",
+		"snaps":        mockCore20ModelSnaps,
+	}
+	s.setModelInState(c, uc20Headers)
+
+	rotatedPrivKey, _ := assertstest.GenerateKey(752)
+	assertstatetest.AddMany(s.state, assertstest.NewAccountKey(s.storeSigning, s.brands.Account("my-brand"), map[string]interface{}{
+		"name": "rotated",
+	}, rotatedPrivKey.PublicKey(), ""))
+	headers := map[string]interface{}{
+		"series":    "16",
+		"brand-id":  "my-brand",
+		"model":     "my-model",
+		"revision":  "1",
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+	for k, v := range uc20Headers {
+		headers[k] = v
+	}
+	a, err := assertstest.NewSigningDB("my-brand", rotatedPrivKey).Sign(asserts.ModelType, headers, nil, "")
+	c.Assert(err, IsNil)
+
+	_, err = devicestate.ReissueModel(s.state, a.(*asserts.Model))
+	c.Assert(err, ErrorMatches, `cannot reissue model my-brand/my-model signed with a different key on a UC20\+ system, a remodel is required`)
+}
+
+func (s *deviceMgrModelReissueSuite) TestReissueModelConflicts(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setModelInState(c, modelDefaults)
+	s.makeSerialAssertionInState(c, "my-brand", "my-model", "serialserialserial")
+	newModel := s.brands.Model("my-brand", "my-model", modelDefaults, map[string]interface{}{
+		"revision": "1",
+	})
+
+	chg, err := devicestate.ReissueModel(s.state, newModel)
+	c.Assert(err, IsNil)
+
+	_, err = devicestate.ReissueModel(s.state, newModel)
+	c.Assert(err, ErrorMatches, `other changes in progress \(conflicting change "reissue-model"\), change "reissue-model" not allowed until they are done`)
+	c.Check(err, FitsTypeOf, &snapstate.ChangeConflictError{})
+
+	_, err = devicestate.Remodel(s.state, newModel, nil, nil)
+	c.Assert(err, ErrorMatches, `other changes in progress \(conflicting change "reissue-model"\), change "remodel" not allowed until they are done`)
+
+	chg.Tasks()[0].SetStatus(state.HoldStatus)
+	remodel := s.state.NewChange("remodel", "...")
+	remodelTask := s.state.NewTask("fake-remodel-task", "...")
+	remodel.AddTask(remodelTask)
+	_, err = devicestate.ReissueModel(s.state, newModel)
+	c.Assert(err, ErrorMatches, "remodeling in progress, no other changes allowed until this is done")
+}
+
+func (s *deviceMgrModelReissueSuite) TestReissueModelCheckedAgain(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setModelInState(c, modelDefaults)
+	newModel := s.brands.Model("my-brand", "my-model", modelDefaults, map[string]interface{}{
+		"revision": "1",
+	})
+
+	chg, err := devicestate.ReissueModel(s.state, newModel)
+	c.Assert(err, IsNil)
+	// the current model changes before the change runs
+	assertstatetest.AddMany(s.state, newModel)
+
+	s.state.Unlock()
+	s.settle(c)
+	s.state.Lock()
+
+	c.Check(chg.Err(), ErrorMatches, `(?s).*cannot reissue model my-brand/my-model with revision 1 not newer than the current revision 1.*`)
+}
@@ -0,0 +1,297 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package devicestate_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/overlord/restart"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+type deviceMgrRebootSuite struct {
+	deviceMgrBaseSuite
+}
+
+var _ = Suite(&deviceMgrRebootSuite{})
+
+func (s *deviceMgrRebootSuite) SetUpTest(c *C) {
+	classic := false
+	s.setupBaseTest(c, classic)
+
+	s.state.Lock()
+	s.state.Set("seeded", true)
+	s.state.Unlock()
+
+	s.AddCleanup(devicestate.MockOsutilBootID(func() (string, error) {
+		return "boot-id-0", nil
+	}))
+}
+
+// runScheduledReboot runs the scheduled reboot once, expects the state to be
+// locked
+func (s *deviceMgrRebootSuite) runScheduledReboot(c *C) {
+	s.state.Unlock()
+	defer s.state.Lock()
+	c.Assert(s.se.Ensure(), IsNil)
+	s.se.Wait()
+}
+
+func (s *deviceMgrRebootSuite) mockChangeWaitingForRestart(c *C) *state.Change {
+	chg := s.state.NewChange("refresh-snap", "...")
+	t := s.state.NewTask("link-snap", "...")
+	t.SetStatus(state.DoingStatus)
+	chg.AddTask(t)
+
+	restart.MarkTaskAsRestartBoundary(t, restart.RestartBoundaryDirectionDo)
+	err := restart.FinishTaskWithRestart(t, state.DoneStatus, restart.RestartSystem, "pc-kernel", nil)
+	c.Assert(err, IsNil)
+	c.Assert(chg.Status(), Equals, state.WaitStatus)
+	return chg
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleReboot(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	chg, err := devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{})
+	c.Assert(err, IsNil)
+	c.Check(chg.Kind(), Equals, "schedule-reboot")
+	c.Check(chg.Summary(), Equals, "Reboot the system")
+
+	s.runScheduledReboot(c)
+
+	c.Check(chg.Status(), Equals, state.DoneStatus)
+	c.Check(s.restartRequests, DeepEquals, []restart.RestartType{restart.RestartSystemNow})
+
+	var bootID string
+	c.Assert(chg.Tasks()[0].Get("requested-from-boot-id", &bootID), IsNil)
+	c.Check(bootID, Equals, "boot-id-0")
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleRebootOps(c *C) {
+	for _, tc := range []struct {
+		op      string
+		summary string
+		restart restart.RestartType
+	}{
+		{devicestate.RebootHaltOp, "Halt the system", restart.RestartSystemHaltNow},
+		{devicestate.RebootPoweroffOp, "Power off the system", restart.RestartSystemPoweroffNow},
+	} {
+		s.restartRequests = nil
+
+		s.state.Lock()
+		chg, err := devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{Op: tc.op})
+		c.Check(err, IsNil)
+		if err == nil {
+			c.Check(chg.Summary(), Equals, tc.summary)
+
+			s.runScheduledReboot(c)
+
+			c.Check(chg.Status(), Equals, state.DoneStatus)
+			c.Check(s.restartRequests, DeepEquals, []restart.RestartType{tc.restart})
+		}
+		s.state.Unlock()
+	}
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleRebootUnsupportedOp(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	_, err := devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{Op: "suspend"})
+	c.Assert(err, ErrorMatches, `unsupported reboot operation "suspend"`)
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleRebootIntoRecoveryBadArgs(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	for _, tc := range []struct {
+		sr  devicestate.ScheduledReboot
+		err string
+	}{
+		{devicestate.ScheduledReboot{Op: devicestate.RebootHaltOp, RecoverySystem: "1234", Mode: "recover", Reason: "disk-check"}, `cannot request a recovery system with reboot operation "halt"`},
+		{devicestate.ScheduledReboot{Mode: "recover", Reason: "disk-check"}, `cannot request recovery without a recovery system`},
+		{devicestate.ScheduledReboot{RecoverySystem: "1234", Mode: "recover"}, `recovery reason code cannot be empty`},
+	} {
+		_, err := devicestate.ScheduleReboot(s.state, &tc.sr)
+		c.Check(err, ErrorMatches, tc.err)
+	}
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleRebootIntoRecoveryFailsOutsideOfRunMode(c *C) {
+	devicestate.SetSystemMode(s.mgr, "recover")
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	chg, err := devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{
+		RecoverySystem: "1234",
+		Mode:           "recover",
+		Reason:         "disk-check",
+	})
+	c.Assert(err, IsNil)
+	c.Check(chg.Summary(), Equals, `Reboot the system into recovery system "1234" in "recover" mode`)
+
+	s.runScheduledReboot(c)
+
+	c.Check(chg.Status(), Equals, state.ErrorStatus)
+	c.Check(chg.Err(), ErrorMatches, `(?s).*cannot request recovery outside of run mode.*`)
+	c.Check(s.restartRequests, HasLen, 0)
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleRebootConflict(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	at := time.Now().Add(time.Hour)
+	chg, err := devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{At: at})
+	c.Assert(err, IsNil)
+
+	_, err = devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{})
+	c.Assert(err, ErrorMatches, "cannot schedule reboot: a system reboot is already scheduled")
+
+	chg.Abort()
+	_, err = devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{})
+	c.Assert(err, IsNil)
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleRebootAtLater(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	at := time.Now().Add(time.Hour)
+	chg, err := devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{At: at})
+	c.Assert(err, IsNil)
+	c.Check(chg.Summary(), Equals, "Reboot the system at "+at.Format(time.RFC3339))
+
+	s.runScheduledReboot(c)
+
+	// the reboot waits for the scheduled time
+	t := chg.Tasks()[0]
+	c.Check(t.Status(), Equals, state.DoingStatus)
+	c.Check(t.AtTime().IsZero(), Equals, false)
+	c.Check(t.AtTime().Before(at), Equals, true)
+	c.Check(s.restartRequests, HasLen, 0)
+
+	// meanwhile the restarts needed by other changes are postponed
+	other := s.mockChangeWaitingForRestart(c)
+	c.Check(s.restartRequests, HasLen, 0)
+	c.Check(other.Has("pending-system-restart"), Equals, true)
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleRebootAtPassed(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	chg, err := devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{At: time.Now().Add(-time.Minute)})
+	c.Assert(err, IsNil)
+
+	s.runScheduledReboot(c)
+
+	c.Check(chg.Status(), Equals, state.DoneStatus)
+	c.Check(s.restartRequests, DeepEquals, []restart.RestartType{restart.RestartSystemNow})
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleRebootAfterChanges(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	busy := s.state.NewChange("install-snap", "...")
+	busy.SetStatus(state.DoingStatus)
+
+	chg, err := devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{AfterChanges: true})
+	c.Assert(err, IsNil)
+	c.Check(chg.Summary(), Equals, "Reboot the system after current changes complete")
+
+	s.runScheduledReboot(c)
+
+	c.Check(chg.Tasks()[0].Status(), Equals, state.DoingStatus)
+	c.Check(s.restartRequests, HasLen, 0)
+
+	// a change needing a restart doesn't restart the system on its own
+	other := s.mockChangeWaitingForRestart(c)
+	c.Check(s.restartRequests, HasLen, 0)
+
+	busy.SetStatus(state.DoneStatus)
+	chg.Tasks()[0].At(time.Time{})
+	s.runScheduledReboot(c)
+
+	// the system is restarted once for both changes
+	c.Check(chg.Status(), Equals, state.DoneStatus)
+	c.Check(s.restartRequests, DeepEquals, []restart.RestartType{restart.RestartSystem})
+	c.Check(other.Has("pending-system-restart"), Equals, false)
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleHaltServesPostponedRestarts(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	restart.PostponeSystemRestarts(s.state)
+	other := s.mockChangeWaitingForRestart(c)
+
+	chg, err := devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{Op: devicestate.RebootHaltOp})
+	c.Assert(err, IsNil)
+
+	s.runScheduledReboot(c)
+
+	c.Check(chg.Status(), Equals, state.DoneStatus)
+	c.Check(s.restartRequests, DeepEquals, []restart.RestartType{restart.RestartSystemHaltNow})
+	c.Check(other.Has("pending-system-restart"), Equals, false)
+	c.Check(other.Status(), Equals, state.WaitStatus)
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleRebootAlreadyHappened(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	chg, err := devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{})
+	c.Assert(err, IsNil)
+	chg.Tasks()[0].Set("requested-from-boot-id", "boot-id-before")
+
+	s.runScheduledReboot(c)
+
+	c.Check(chg.Status(), Equals, state.DoneStatus)
+	c.Check(s.restartRequests, HasLen, 0)
+}
+
+func (s *deviceMgrRebootSuite) TestScheduleRebootAbortResumesRestarts(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	chg, err := devicestate.ScheduleReboot(s.state, &devicestate.ScheduledReboot{At: time.Now().Add(time.Hour)})
+	c.Assert(err, IsNil)
+
+	s.runScheduledReboot(c)
+
+	s.mockChangeWaitingForRestart(c)
+	c.Check(s.restartRequests, HasLen, 0)
+
+	chg.Abort()
+	// the next retry is due
+	chg.Tasks()[0].At(time.Time{})
+	s.runScheduledReboot(c)
+
+	c.Check(chg.Status(), Equals, state.UndoneStatus)
+	c.Check(s.restartRequests, DeepEquals, []restart.RestartType{restart.RestartSystem})
+}
@@ -0,0 +1,213 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package devicestate_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/boot"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type deviceMgrRecoveryRequestSuite struct {
+	deviceMgrBaseSuite
+
+	bootID string
+	now    time.Time
+}
+
+var _ = Suite(&deviceMgrRecoveryRequestSuite{})
+
+func (s *deviceMgrRecoveryRequestSuite) SetUpTest(c *C) {
+	classic := false
+	s.setupBaseTest(c, classic)
+
+	s.bootID = "boot-id-1"
+	s.AddCleanup(devicestate.MockOsutilBootID(func() (string, error) {
+		return s.bootID, nil
+	}))
+	s.now = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.AddCleanup(devicestate.MockTimeNow(func() time.Time {
+		return s.now
+	}))
+}
+
+var requestedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (s *deviceMgrRecoveryRequestSuite) mockPendingRequest(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+	s.state.Set("recovery-request", &devicestate.RecoveryRequest{
+		System:      "1234",
+		Mode:        "recover",
+		Reason:      "disk-check",
+		Status:      devicestate.RecoveryRequestPending,
+		RequestedAt: requestedAt,
+		BootID:      "boot-id-0",
+	})
+}
+
+func (s *deviceMgrRecoveryRequestSuite) mockRecoveryEntered(c *C, system, mode string, enteredAt time.Time) string {
+	enteredFile := filepath.Join(dirs.SnapDeviceDir, "recovery-entered")
+	c.Assert(os.MkdirAll(filepath.Dir(enteredFile), 0755), IsNil)
+	content := `{"system":"` + system + `","mode":"` + mode + `","entered-at":"` + enteredAt.Format(time.RFC3339) + `"}`
+	c.Assert(os.WriteFile(enteredFile, []byte(content), 0644), IsNil)
+	return enteredFile
+}
+
+func (s *deviceMgrRecoveryRequestSuite) recoveryRequest(c *C) *devicestate.RecoveryRequest {
+	s.state.Lock()
+	defer s.state.Unlock()
+	req, err := devicestate.RecoveryRequestFromState(s.state)
+	c.Assert(err, IsNil)
+	return req
+}
+
+func (s *deviceMgrRecoveryRequestSuite) TestValidateRecoveryReason(c *C) {
+	for _, valid := range []string{"disk-check", "a", "fleet-rescue-2", "1"} {
+		c.Check(devicestate.ValidateRecoveryReason(valid), IsNil, Commentf("%q", valid))
+	}
+	c.Check(devicestate.ValidateRecoveryReason(""), ErrorMatches, `recovery reason code cannot be empty`)
+	for _, invalid := range []string{"Disk-check", "disk check", "-disk", "disk-", "disk--check", "disk_check", strings.Repeat("a", 65)} {
+		c.Check(devicestate.ValidateRecoveryReason(invalid), ErrorMatches, `invalid recovery reason code .*`, Commentf("%q", invalid))
+	}
+}
+
+func (s *deviceMgrRecoveryRequestSuite) TestRequestRecoveryErrors(c *C) {
+	devicestate.SetSystemMode(s.mgr, "run")
+
+	err := s.mgr.RequestRecovery("", "recover", "disk-check")
+	c.Check(err, ErrorMatches, "internal error: system label is unset")
+	err = s.mgr.RequestRecovery("1234", "recover", "")
+	c.Check(err, ErrorMatches, "recovery reason code cannot be empty")
+	err = s.mgr.RequestRecovery("1234", "run", "disk-check")
+	c.Check(err, ErrorMatches, "cannot request recovery into run mode")
+
+	devicestate.SetSystemMode(s.mgr, "recover")
+	err = s.mgr.RequestRecovery("1234", "recover", "disk-check")
+	c.Check(err, ErrorMatches, "cannot request recovery outside of run mode")
+}
+
+func (s *deviceMgrRecoveryRequestSuite) TestEnsureRecoveryRequestCompleted(c *C) {
+	devicestate.SetSystemMode(s.mgr, "run")
+	s.mockPendingRequest(c)
+	enteredAt := requestedAt.Add(time.Minute)
+	enteredFile := s.mockRecoveryEntered(c, "1234", "recover", enteredAt)
+
+	c.Assert(devicestate.EnsureRecoveryRequest(s.mgr), IsNil)
+
+	c.Check(s.recoveryRequest(c), DeepEquals, &devicestate.RecoveryRequest{
+		System:      "1234",
+		Mode:        "recover",
+		Reason:      "disk-check",
+		Status:      devicestate.RecoveryRequestCompleted,
+		RequestedAt: requestedAt,
+		EnteredAt:   enteredAt,
+		CompletedAt: s.now,
+		BootID:      "boot-id-0",
+	})
+	c.Check(enteredFile, testutil.FileAbsent)
+}
+
+func (s *deviceMgrRecoveryRequestSuite) TestEnsureRecoveryRequestNotEntered(c *C) {
+	devicestate.SetSystemMode(s.mgr, "run")
+
+	for _, tc := range []struct {
+		system, mode string
+		enteredAt    time.Time
+	}{
+		// nothing recorded
+		{},
+		// a different system
+		{"4567", "recover", requestedAt.Add(time.Minute)},
+		// a different mode
+		{"1234", "install", requestedAt.Add(time.Minute)},
+		// left over from before the request
+		{"1234", "recover", requestedAt.Add(-time.Minute)},
+	} {
+		devicestate.SetRecoveryRequestRan(s.mgr, false)
+		s.mockPendingRequest(c)
+		if tc.system != "" {
+			s.mockRecoveryEntered(c, tc.system, tc.mode, tc.enteredAt)
+		}
+
+		c.Assert(devicestate.EnsureRecoveryRequest(s.mgr), IsNil)
+
+		req := s.recoveryRequest(c)
+		c.Check(req.Status, Equals, devicestate.RecoveryRequestNotEntered, Commentf("%+v", tc))
+		c.Check(req.EnteredAt.IsZero(), Equals, true)
+		c.Check(req.CompletedAt, Equals, s.now)
+		c.Check(filepath.Join(dirs.SnapDeviceDir, "recovery-entered"), testutil.FileAbsent)
+	}
+}
+
+func (s *deviceMgrRecoveryRequestSuite) TestEnsureRecoveryRequestNotRebootedYet(c *C) {
+	devicestate.SetSystemMode(s.mgr, "run")
+	s.bootID = "boot-id-0"
+	s.mockPendingRequest(c)
+
+	c.Assert(devicestate.EnsureRecoveryRequest(s.mgr), IsNil)
+
+	c.Check(s.recoveryRequest(c).Status, Equals, devicestate.RecoveryRequestPending)
+}
+
+func (s *deviceMgrRecoveryRequestSuite) TestEnsureRecoveryRequestRecordsEntered(c *C) {
+	s.setUC20PCModelInState(c)
+	devicestate.SetSystemMode(s.mgr, "recover")
+	modeenv := boot.Modeenv{
+		Mode:           "recover",
+		RecoverySystem: "1234",
+	}
+	c.Assert(modeenv.WriteTo(""), IsNil)
+
+	hostWritableDir := filepath.Join(boot.InitramfsHostUbuntuDataDir, "system-data")
+	c.Assert(os.MkdirAll(hostWritableDir, 0755), IsNil)
+
+	c.Assert(devicestate.EnsureRecoveryRequest(s.mgr), IsNil)
+
+	enteredFile := filepath.Join(dirs.SnapDeviceDirUnder(hostWritableDir), "recovery-entered")
+	c.Check(enteredFile, testutil.FileEquals, `{"system":"1234","mode":"recover","entered-at":"2026-01-02T03:04:05Z"}`)
+
+	// only recorded once
+	c.Assert(os.Remove(enteredFile), IsNil)
+	c.Assert(devicestate.EnsureRecoveryRequest(s.mgr), IsNil)
+	c.Check(enteredFile, testutil.FileAbsent)
+}
+
+func (s *deviceMgrRecoveryRequestSuite) TestEnsureRecoveryRequestNoHostData(c *C) {
+	s.setUC20PCModelInState(c)
+	devicestate.SetSystemMode(s.mgr, "recover")
+	modeenv := boot.Modeenv{
+		Mode:           "recover",
+		RecoverySystem: "1234",
+	}
+	c.Assert(modeenv.WriteTo(""), IsNil)
+
+	c.Assert(devicestate.EnsureRecoveryRequest(s.mgr), IsNil)
+
+	c.Check(boot.InitramfsHostUbuntuDataDir, testutil.FileAbsent)
+}
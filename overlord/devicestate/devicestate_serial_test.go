@@ -21,6 +21,7 @@ package devicestate_test
 
 import (
 	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -217,6 +218,64 @@ func (s *deviceMgrSerialSuite) TestFullDeviceRegistrationHappy(c *C) {
 	c.Check(s.mgr.Unregister(nil), ErrorMatches, `cannot currently unregister device if not classic or model brand is not generic or canonical`)
 }
 
+func (s *deviceMgrSerialSuite) TestFullDeviceRegistrationEmitsEvent(c *C) {
+	r1 := devicestate.MockKeyLength(testKeyLength)
+	defer r1()
+
+	mockServer := s.mockServer(c, "REQID-1", nil)
+	defer mockServer.Close()
+
+	r2 := devicestate.MockBaseStoreURL(mockServer.URL)
+	defer r2()
+
+	var received []*devicestate.DeviceEvent
+	eventsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev devicestate.DeviceEvent
+		c.Check(json.NewDecoder(r.Body).Decode(&ev), IsNil)
+		received = append(received, &ev)
+	}))
+	defer eventsServer.Close()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.makeModelAssertionInState(c, "canonical", "pc", map[string]interface{}{
+		"architecture": "amd64",
+		"kernel":       "pc-kernel",
+		"gadget":       "pc",
+	})
+
+	devicestatetest.SetDevice(s.state, &auth.DeviceState{
+		Brand: "canonical",
+		Model: "pc",
+	})
+
+	devicestatetest.MockGadget(c, s.state, "pc", snap.R(2), nil)
+	s.state.Set("seeded", true)
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("pc", "device-service.events-url", eventsServer.URL), IsNil)
+	tr.Commit()
+
+	// runs the whole device registration process and delivers the event
+	s.state.Unlock()
+	s.settle(c)
+	s.state.Lock()
+
+	becomeOperational := s.findBecomeOperationalChange()
+	c.Assert(becomeOperational, NotNil)
+	c.Check(becomeOperational.Err(), IsNil)
+
+	c.Assert(received, HasLen, 1)
+	c.Check(received[0].Type, Equals, devicestate.DeviceEventRegistered)
+	c.Check(received[0].BrandID, Equals, "canonical")
+	c.Check(received[0].Model, Equals, "pc")
+	c.Check(received[0].Serial, Equals, "9999")
+
+	var pending []*devicestate.DeviceEvent
+	c.Check(s.state.Get("device-events", &pending), testutil.ErrorIs, state.ErrNoState)
+}
+
 func (s *deviceMgrSerialSuite) TestFullDeviceRegistrationHappyWithProxy(c *C) {
 	r1 := devicestate.MockKeyLength(testKeyLength)
 	defer r1()
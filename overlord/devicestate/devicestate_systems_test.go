@@ -983,6 +983,58 @@ func (s *deviceMgrSystemsSuite) TestRebootUnhappy(c *C) {
 	c.Check(s.logbuf.String(), Equals, "")
 }
 
+func (s *deviceMgrSystemsSuite) TestRequestRecoveryHappy(c *C) {
+	s.state.Lock()
+	s.state.Set("seeded-systems", []devicestate.SeededSystem{
+		{
+			System:  s.mockedSystemSeeds[0].label,
+			Model:   s.mockedSystemSeeds[0].model.Model(),
+			BrandID: s.mockedSystemSeeds[0].brand.AccountID(),
+		},
+	})
+	s.state.Unlock()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	defer devicestate.MockTimeNow(func() time.Time { return now })()
+	defer devicestate.MockOsutilBootID(func() (string, error) { return "boot-id-0", nil })()
+
+	err := s.mgr.RequestRecovery("20191119", "recover", "disk-check")
+	c.Assert(err, IsNil)
+
+	m, err := s.bootloader.GetBootVars("snapd_recovery_mode", "snapd_recovery_system")
+	c.Assert(err, IsNil)
+	c.Check(m, DeepEquals, map[string]string{
+		"snapd_recovery_system": "20191119",
+		"snapd_recovery_mode":   "recover",
+	})
+	// no reboot is requested
+	c.Check(s.restartRequests, HasLen, 0)
+	c.Check(s.logbuf.String(), Matches, `.*: system "20191119" will be booted in "recover" mode on next reboot, reason: disk-check\n`)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	req, err := devicestate.RecoveryRequestFromState(s.state)
+	c.Assert(err, IsNil)
+	c.Check(req, DeepEquals, &devicestate.RecoveryRequest{
+		System:      "20191119",
+		Mode:        "recover",
+		Reason:      "disk-check",
+		Status:      devicestate.RecoveryRequestPending,
+		RequestedAt: now,
+		BootID:      "boot-id-0",
+	})
+}
+
+func (s *deviceMgrSystemsSuite) TestRequestRecoveryUnsupportedMode(c *C) {
+	err := s.mgr.RequestRecovery("20191119", "unknown-mode", "disk-check")
+	c.Assert(err, Equals, devicestate.ErrUnsupportedAction)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	_, err = devicestate.RecoveryRequestFromState(s.state)
+	c.Check(err, testutil.ErrorIs, state.ErrNoState)
+}
+
 func (s *deviceMgrSystemsSuite) TestDeviceManagerEnsureTriedSystemSuccessfuly(c *C) {
 	err := s.bootloader.SetBootVars(map[string]string{
 		"try_recovery_system":    "1234",
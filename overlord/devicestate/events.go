@@ -0,0 +1,314 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package devicestate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/httputil"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/configstate/proxyconf"
+	"github.com/snapcore/snapd/overlord/devicestate/internal"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/randutil"
+	"github.com/snapcore/snapd/snapdenv"
+)
+
+// DeviceEventType designates a kind of device lifecycle event.
+type DeviceEventType string
+
+const (
+	// the device got its first serial
+	DeviceEventRegistered DeviceEventType = "registered"
+	// the device got a new serial as part of a re-registration remodel
+	DeviceEventSerialAcquired DeviceEventType = "serial-acquired"
+	// a remodel was completed
+	DeviceEventRemodelFinished DeviceEventType = "remodel-finished"
+	// a factory reset was completed
+	DeviceEventFactoryReset DeviceEventType = "factory-reset"
+)
+
+// DeviceEvent is the payload POSTed to the endpoint configured by the
+// gadget via device-service.events-url. The body of the request is
+// signed with the device key, the signature is carried in the
+// Snap-Device-Event-Signature header.
+type DeviceEvent struct {
+	ID      string                 `json:"id"`
+	Type    DeviceEventType        `json:"type"`
+	BrandID string                 `json:"brand-id"`
+	Model   string                 `json:"model"`
+	Serial  string                 `json:"serial,omitempty"`
+	Time    time.Time              `json:"time"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+const (
+	// how many undelivered events are kept, the oldest ones are
+	// dropped first
+	maxPendingDeviceEvents = 100
+
+	deviceEventSignatureHeader = "Snap-Device-Event-Signature"
+	deviceKeyHeader            = "Snap-Device-Key-SHA3-384"
+)
+
+var (
+	deviceEventsMinBackoff = 1 * time.Minute
+	deviceEventsMaxBackoff = 1 * time.Hour
+)
+
+// deviceEventsURL returns the endpoint device events should be
+// delivered to as configured by the gadget, or the empty string if
+// there is none.
+func deviceEventsURL(st *state.State) (string, error) {
+	model, err := findModel(st)
+	if errors.Is(err, state.ErrNoState) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	gadgetName := model.Gadget()
+	// gadget is optional on classic
+	if gadgetName == "" {
+		return "", nil
+	}
+	var eventsURL string
+	tr := config.NewTransaction(st)
+	if err := tr.GetMaybe(gadgetName, "device-service.events-url", &eventsURL); err != nil {
+		return "", err
+	}
+	return eventsURL, nil
+}
+
+// EmitDeviceEvent queues an event of the given type about the current
+// device for delivery to the endpoint configured by the gadget via
+// device-service.events-url. Events are dropped if no endpoint is
+// configured at the time they are emitted.
+// The state must be locked by the caller.
+func EmitDeviceEvent(st *state.State, typ DeviceEventType, data map[string]interface{}) error {
+	eventsURL, err := deviceEventsURL(st)
+	if err != nil {
+		return err
+	}
+	if eventsURL == "" {
+		return nil
+	}
+	device, err := internal.Device(st)
+	if err != nil {
+		return err
+	}
+
+	var pending []*DeviceEvent
+	if err := st.Get("device-events", &pending); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	pending = append(pending, &DeviceEvent{
+		ID:      randutil.RandomString(24),
+		Type:    typ,
+		BrandID: device.Brand,
+		Model:   device.Model,
+		Serial:  device.Serial,
+		Time:    timeNow().UTC(),
+		Data:    data,
+	})
+	if len(pending) > maxPendingDeviceEvents {
+		pending = pending[len(pending)-maxPendingDeviceEvents:]
+	}
+	st.Set("device-events", pending)
+	st.EnsureBefore(0)
+	return nil
+}
+
+// emitDeviceEvent is EmitDeviceEvent for callers that must not fail
+// because of it, errors are only logged.
+func emitDeviceEvent(st *state.State, typ DeviceEventType, data map[string]interface{}) {
+	if err := EmitDeviceEvent(st, typ, data); err != nil {
+		logger.Noticef("cannot emit device %q event: %v", typ, err)
+	}
+}
+
+// ensureDeviceEventsShouldBackoff returns whether we should abstain
+// from delivering device events while the backoff interval after a
+// failed delivery is not expired.
+func (m *DeviceManager) ensureDeviceEventsShouldBackoff(now time.Time) bool {
+	return !m.lastDeviceEventsFailure.IsZero() && m.lastDeviceEventsFailure.Add(m.deviceEventsBackoff).After(now)
+}
+
+func (m *DeviceManager) deviceEventsDeliveryFailed(now time.Time) {
+	if m.deviceEventsBackoff == 0 {
+		m.deviceEventsBackoff = deviceEventsMinBackoff
+	} else {
+		m.deviceEventsBackoff *= 2
+		if m.deviceEventsBackoff > deviceEventsMaxBackoff {
+			m.deviceEventsBackoff = deviceEventsMaxBackoff
+		}
+	}
+	m.lastDeviceEventsFailure = now
+}
+
+// ensureDeviceEventsDelivered tries to deliver the pending device
+// events in order, stopping at the first one that cannot be delivered
+// right now.
+func (m *DeviceManager) ensureDeviceEventsDelivered() error {
+	st := m.state
+	st.Lock()
+	defer st.Unlock()
+
+	var pending []*DeviceEvent
+	if err := st.Get("device-events", &pending); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	eventsURL, err := deviceEventsURL(st)
+	if err != nil {
+		return err
+	}
+	if eventsURL == "" {
+		// the endpoint was unset in the meantime
+		st.Set("device-events", nil)
+		return nil
+	}
+
+	if m.ensureDeviceEventsShouldBackoff(timeNow()) {
+		return nil
+	}
+
+	privKey, err := m.keyPair()
+	if errors.Is(err, state.ErrNoState) {
+		// no device key yet, events cannot be signed
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	proxyConf := proxyconf.New(st)
+	client := httputilNewHTTPClient(&httputil.ClientOptions{
+		Timeout:            30 * time.Second,
+		MayLogBody:         true,
+		Proxy:              proxyConf.Conf,
+		ProxyConnectHeader: http.Header{"User-Agent": []string{snapdenv.UserAgent()}},
+		ExtraSSLCerts: &httputil.ExtraSSLCertsFromDir{
+			Dir: dirs.SnapdStoreSSLCertsDir,
+		},
+	})
+
+	done := make(map[string]bool, len(pending))
+	var deliveryErr error
+	st.Unlock()
+	for _, ev := range pending {
+		if deliveryErr = postDeviceEvent(client, eventsURL, ev, privKey); deliveryErr != nil {
+			var permanent *permanentDeliveryError
+			if !errors.As(deliveryErr, &permanent) {
+				break
+			}
+			// the endpoint will never accept it, drop it
+			logger.Noticef("dropping device %q event %s: %v", ev.Type, ev.ID, deliveryErr)
+			deliveryErr = nil
+		}
+		done[ev.ID] = true
+	}
+	st.Lock()
+
+	if deliveryErr != nil {
+		m.deviceEventsDeliveryFailed(timeNow())
+	} else {
+		m.lastDeviceEventsFailure = time.Time{}
+		m.deviceEventsBackoff = 0
+	}
+
+	// more events might have been emitted in the meantime
+	pending = nil
+	if err := st.Get("device-events", &pending); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	remaining := make([]*DeviceEvent, 0, len(pending))
+	for _, ev := range pending {
+		if !done[ev.ID] {
+			remaining = append(remaining, ev)
+		}
+	}
+	if len(remaining) == 0 {
+		st.Set("device-events", nil)
+	} else {
+		st.Set("device-events", remaining)
+	}
+
+	if deliveryErr != nil {
+		return fmt.Errorf("cannot deliver device events: %v", deliveryErr)
+	}
+	return nil
+}
+
+type permanentDeliveryError struct {
+	status int
+}
+
+func (e *permanentDeliveryError) Error() string {
+	return fmt.Sprintf("rejected with status %d", e.status)
+}
+
+func postDeviceEvent(client *http.Client, eventsURL string, ev *DeviceEvent, privKey asserts.PrivateKey) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	sig, err := asserts.SignContent(body, privKey)
+	if err != nil {
+		return fmt.Errorf("cannot sign device event: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", eventsURL, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", snapdenv.UserAgent())
+	req.Header.Set("Content-Type", "application/json")
+	// the encoded signature is wrapped, header values cannot be
+	req.Header.Set(deviceEventSignatureHeader, string(bytes.ReplaceAll(sig, []byte("\n"), nil)))
+	req.Header.Set(deviceKeyHeader, privKey.PublicKey().ID())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusRequestTimeout:
+		return &permanentDeliveryError{status: resp.StatusCode}
+	}
+	return fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/snapcore/snapd/httputil"
 	"github.com/snapcore/snapd/kernel/fde"
 	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/measurestate"
 	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/overlord/storecontext"
@@ -87,6 +88,14 @@ func MockMaxTentatives(max int) (restore func()) {
 	}
 }
 
+func MockOsutilBootID(f func() (string, error)) (restore func()) {
+	old := osutilBootID
+	osutilBootID = f
+	return func() {
+		osutilBootID = old
+	}
+}
+
 func MockTimeNow(f func() time.Time) (restore func()) {
 	old := timeNow
 	timeNow = f
@@ -236,6 +245,10 @@ func EnsureBootOk(m *DeviceManager) error {
 	return m.ensureBootOk()
 }
 
+func EnsureRecoveryRequest(m *DeviceManager) error {
+	return m.ensureRecoveryRequest()
+}
+
 func SetBootOkRan(m *DeviceManager, b bool) {
 	m.bootOkRan = b
 }
@@ -252,6 +265,10 @@ func SetTriedSystemsRan(m *DeviceManager, b bool) {
 	m.ensureTriedRecoverySystemRan = b
 }
 
+func SetRecoveryRequestRan(m *DeviceManager, b bool) {
+	m.ensureRecoveryRequestRan = b
+}
+
 func SetPostFactoryResetRan(m *DeviceManager, b bool) {
 	m.ensurePostFactoryResetRan = b
 }
@@ -582,3 +599,29 @@ func CleanUpEncryptionSetupDataInCache(st *state.State, label string) {
 	key := encryptionSetupDataKey{label}
 	st.Cache(key, nil)
 }
+
+func EnsureDeviceEventsDelivered(m *DeviceManager) error {
+	return m.ensureDeviceEventsDelivered()
+}
+
+func DeviceEventsBackoff(m *DeviceManager) time.Duration {
+	return m.deviceEventsBackoff
+}
+
+func MockSecbootQuoteWhenPossible(f func(qualifyingData []byte, pcrs []int) (*secboot.Quote, error)) (restore func()) {
+	old := secbootQuoteWhenPossible
+	secbootQuoteWhenPossible = f
+	return func() {
+		secbootQuoteWhenPossible = old
+	}
+}
+
+func MockMeasurestateMeasurements(f func(names []string) ([]*measurestate.Entry, error)) (restore func()) {
+	old := measurestateMeasurements
+	measurestateMeasurements = f
+	return func() {
+		measurestateMeasurements = old
+	}
+}
+
+var KernelModulesOnLinkageChanged = kernelModulesOnLinkageChanged
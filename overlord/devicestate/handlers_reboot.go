@@ -0,0 +1,139 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package devicestate
+
+import (
+	"errors"
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/restart"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+// scheduledRebootRetryInterval is how often a scheduled reboot checks
+// whether its time has come or the changes in progress have completed.
+var scheduledRebootRetryInterval = 30 * time.Second
+
+// otherChangesInProgress returns whether changes other than chg are still
+// running. Changes waiting for a system restart are not considered as in
+// progress since the reboot lets them continue.
+func otherChangesInProgress(st *state.State, chg *state.Change) bool {
+	for _, other := range st.Changes() {
+		if other == chg || other.IsReady() {
+			continue
+		}
+		if other.Status() == state.WaitStatus {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (m *DeviceManager) doScheduledReboot(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var sr ScheduledReboot
+	if err := t.Get("scheduled-reboot", &sr); err != nil {
+		return err
+	}
+
+	curBootID, err := osutilBootID()
+	if err != nil {
+		return err
+	}
+
+	var requestedFromBootID string
+	if err := t.Get("requested-from-boot-id", &requestedFromBootID); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	if requestedFromBootID != "" && requestedFromBootID != curBootID {
+		// the requested reboot happened
+		return nil
+	}
+
+	// until the reboot, the system restarts needed by other changes are
+	// postponed so that the system is rebooted only once
+	restart.PostponeSystemRestarts(st)
+
+	if !sr.At.IsZero() {
+		if wait := sr.At.Sub(timeNow()); wait > 0 {
+			// check back regularly so that aborting the change
+			// isn't held up until the scheduled time
+			if wait > scheduledRebootRetryInterval {
+				wait = scheduledRebootRetryInterval
+			}
+			return &state.Retry{After: wait}
+		}
+	}
+	if sr.AfterChanges && otherChangesInProgress(st, t.Change()) {
+		return &state.Retry{After: scheduledRebootRetryInterval}
+	}
+
+	var rst restart.RestartType
+	switch sr.Op {
+	case RebootHaltOp:
+		rst = restart.RestartSystemHaltNow
+	case RebootPoweroffOp:
+		rst = restart.RestartSystemPoweroffNow
+	default:
+		rst = restart.RestartSystemNow
+	}
+
+	if sr.Mode != "" {
+		st.Unlock()
+		err := m.RequestRecovery(sr.RecoverySystem, sr.Mode, sr.Reason)
+		st.Lock()
+		if err != nil {
+			return err
+		}
+	}
+
+	t.Set("requested-from-boot-id", curBootID)
+	if rst == restart.RestartSystemNow {
+		// requesting a restart postponed for another change serves
+		// the scheduled reboot too
+		restart.ResumeSystemRestarts(st)
+		if pending, _ := restart.Pending(st); pending {
+			t.Logf("System restart already requested")
+			return nil
+		}
+	} else {
+		restart.DropPostponedSystemRestarts(st)
+	}
+
+	logger.Noticef("%s as scheduled", sr.summary())
+	restart.Request(st, rst, nil)
+	return nil
+}
+
+func (m *DeviceManager) undoScheduledReboot(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	defer st.Unlock()
+
+	// carry out the system restarts postponed in the meantime
+	restart.ResumeSystemRestarts(st)
+	return nil
+}
@@ -163,14 +163,26 @@ func (m *DeviceManager) doSetModel(t *state.Task, _ *tomb.Tomb) (err error) {
 	}
 
 	logEverywhere := func(format string, args ...interface{}) {
-		t.Logf(format, args)
-		logger.Noticef(format, args)
+		t.Logf(format, args...)
+		logger.Noticef(format, args...)
 	}
 
+	current := remodCtx.GroundContext().Model()
+
 	// and finish (this will set the new model), note that changes done in
 	// here are not recoverable even if an error occurs
 	if err := remodCtx.Finish(); err != nil {
 		logEverywhere("cannot complete remodel: %v", err)
+	} else {
+		if remodCtx.Kind() == ReregRemodel {
+			emitDeviceEvent(st, DeviceEventSerialAcquired, nil)
+		}
+		emitDeviceEvent(st, DeviceEventRemodelFinished, map[string]interface{}{
+			"previous-brand-id": current.BrandID(),
+			"previous-model":    current.Model(),
+			"previous-revision": current.Revision(),
+			"kind":              remodCtx.Kind().String(),
+		})
 	}
 
 	t.SetStatus(state.DoneStatus)
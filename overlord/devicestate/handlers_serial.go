@@ -233,6 +233,7 @@ func (rc *initialRegistrationContext) FinishRegistration(serial *asserts.Serial)
 		return err
 	}
 	rc.deviceMgr.markRegistered()
+	emitDeviceEvent(rc.deviceMgr.state, DeviceEventRegistered, nil)
 
 	// make sure we timely consider anything that was blocked on
 	// registration
@@ -0,0 +1,360 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package devicestate
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/kernel"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
+)
+
+// KernelModule describes a kernel module shipped by the kernel snap of the
+// device.
+type KernelModule struct {
+	Name string
+	// Component is the name of the kernel snap component shipping the
+	// module, if not the kernel snap itself.
+	Component string
+	// Disabled is set if loading the module was disabled with
+	// SetKernelModulesDisabled.
+	Disabled bool
+	// LoadedBy lists the snaps whose interface connections load the
+	// module on boot.
+	LoadedBy []string
+}
+
+// kernelModulesState is stored under "kernel-modules" in the state.
+type kernelModulesState struct {
+	// Disabled holds the disabled modules by kernel snap revision, so
+	// that reverting the kernel also reverts its modules configuration.
+	Disabled map[string][]string `json:"disabled,omitempty"`
+	// Applied is the kernel snap revision the modprobe.d configuration
+	// was last written for.
+	Applied string `json:"applied,omitempty"`
+}
+
+// kernelModulesConfFile is the modprobe.d fragment managed by snapd, its
+// name does not clash with the per-snap ones written by the kmod backend.
+func kernelModulesConfFile() string {
+	return filepath.Join(dirs.SnapKModModprobeDir, "snapd.kernel-modules.conf")
+}
+
+// ErrNoKernelSnap is returned when managing kernel modules on a device
+// without a kernel snap.
+var ErrNoKernelSnap = errors.New("device has no kernel snap")
+
+// KernelModuleError is returned when the loading of a kernel module cannot
+// be changed as requested.
+type KernelModuleError struct {
+	Module  string
+	Message string
+}
+
+func (e *KernelModuleError) Error() string {
+	return e.Message
+}
+
+func getKernelModulesState(st *state.State) (*kernelModulesState, error) {
+	var kms kernelModulesState
+	if err := st.Get("kernel-modules", &kms); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	if kms.Disabled == nil {
+		kms.Disabled = make(map[string][]string)
+	}
+	return &kms, nil
+}
+
+func currentKernelSnap(st *state.State) (*snapstate.SnapState, *snap.Info, error) {
+	deviceCtx, err := DeviceCtx(st, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	kernelName := deviceCtx.Model().Kernel()
+	if kernelName == "" {
+		return nil, nil, ErrNoKernelSnap
+	}
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(st, kernelName, &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return nil, nil, ErrNoKernelSnap
+		}
+		return nil, nil, err
+	}
+	info, err := snapst.CurrentInfo()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &snapst, info, nil
+}
+
+// shippedKernelModules returns the modules shipped by the current revision of
+// the kernel snap and of its components.
+func shippedKernelModules(snapst *snapstate.SnapState, info *snap.Info) ([]*KernelModule, error) {
+	var modules []*KernelModule
+	add := func(rootDir, component string) error {
+		mods, err := kernel.ListModules(rootDir)
+		if err != nil {
+			return err
+		}
+		for _, mod := range mods {
+			// the same module can be shipped for several kernel
+			// versions
+			if n := len(modules); n > 0 && modules[n-1].Name == mod.Name && modules[n-1].Component == component {
+				continue
+			}
+			modules = append(modules, &KernelModule{Name: mod.Name, Component: component})
+		}
+		return nil
+	}
+
+	if err := add(info.MountDir(), ""); err != nil {
+		return nil, err
+	}
+	if idx := snapst.LastIndex(snapst.Current); idx >= 0 {
+		for _, comp := range snapst.Sequence.Revisions[idx].Components {
+			csi := comp.SideInfo
+			cpi := snap.MinimalComponentContainerPlaceInfo(csi.Component.ComponentName, csi.Revision, info.InstanceName(), info.Revision)
+			if err := add(cpi.MountDir(), csi.Component.ComponentName); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(modules, func(i, j int) bool { return modules[i].Name < modules[j].Name })
+	return modules, nil
+}
+
+// modulesLoadedBySnaps returns the snaps loading each module on boot, as
+// configured by the kmod backend for their interface connections.
+func modulesLoadedBySnaps() (map[string][]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dirs.SnapKModModulesDir, "snap.*.conf"))
+	if err != nil {
+		return nil, err
+	}
+	loadedBy := make(map[string][]string)
+	for _, fname := range matches {
+		snapName := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(fname), "snap."), ".conf")
+		content, err := os.ReadFile(fname)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			mod := kernel.ModuleName(line)
+			loadedBy[mod] = append(loadedBy[mod], snapName)
+		}
+	}
+	for _, snaps := range loadedBy {
+		sort.Strings(snaps)
+	}
+	return loadedBy, nil
+}
+
+// KernelModules returns the kernel modules shipped by the current revision
+// of the kernel snap of the device and of its components.
+func KernelModules(st *state.State) ([]*KernelModule, error) {
+	snapst, info, err := currentKernelSnap(st)
+	if err != nil {
+		return nil, err
+	}
+	modules, err := shippedKernelModules(snapst, info)
+	if err != nil {
+		return nil, err
+	}
+	kms, err := getKernelModulesState(st)
+	if err != nil {
+		return nil, err
+	}
+	loadedBy, err := modulesLoadedBySnaps()
+	if err != nil {
+		return nil, err
+	}
+	disabled := kms.Disabled[info.Revision.String()]
+	for _, mod := range modules {
+		mod.Disabled = strutil.ListContains(disabled, mod.Name)
+		mod.LoadedBy = loadedBy[mod.Name]
+	}
+	return modules, nil
+}
+
+// SetKernelModulesDisabled disables or enables back the loading of the given
+// modules of the kernel snap. The setting is recorded for the current
+// revision of the kernel snap and applied through a modprobe.d configuration
+// file, modules that are already loaded are not unloaded.
+func SetKernelModulesDisabled(st *state.State, names []string, disabled bool) error {
+	snapst, info, err := currentKernelSnap(st)
+	if err != nil {
+		return err
+	}
+	shipped, err := shippedKernelModules(snapst, info)
+	if err != nil {
+		return err
+	}
+	loadedBy, err := modulesLoadedBySnaps()
+	if err != nil {
+		return err
+	}
+	modules := make([]string, 0, len(names))
+	for _, name := range names {
+		name = kernel.ModuleName(name)
+		modules = append(modules, name)
+		found := false
+		for _, mod := range shipped {
+			if mod.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &KernelModuleError{
+				Module:  name,
+				Message: fmt.Sprintf("kernel snap %q does not ship module %q", info.InstanceName(), name),
+			}
+		}
+		if disabled && len(loadedBy[name]) > 0 {
+			return &KernelModuleError{
+				Module: name,
+				Message: fmt.Sprintf("cannot disable kernel module %q: loaded on boot by %s through interface connections",
+					name, strutil.Quoted(loadedBy[name])),
+			}
+		}
+	}
+
+	kms, err := getKernelModulesState(st)
+	if err != nil {
+		return err
+	}
+	rev := info.Revision.String()
+	var current []string
+	for _, name := range kms.Disabled[rev] {
+		if disabled || !strutil.ListContains(modules, name) {
+			current = append(current, name)
+		}
+	}
+	if disabled {
+		for _, name := range modules {
+			if !strutil.ListContains(current, name) {
+				current = append(current, name)
+			}
+		}
+	}
+	sort.Strings(current)
+	if len(current) == 0 {
+		delete(kms.Disabled, rev)
+	} else {
+		kms.Disabled[rev] = current
+	}
+
+	if err := writeKernelModulesConf(current); err != nil {
+		return err
+	}
+	kms.Applied = rev
+	st.Set("kernel-modules", kms)
+	return nil
+}
+
+// writeKernelModulesConf writes the modprobe.d configuration preventing the
+// given modules from being loaded, or removes it if there are none.
+func writeKernelModulesConf(disabled []string) error {
+	if len(disabled) == 0 {
+		if err := os.Remove(kernelModulesConfFile()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(dirs.SnapKModModprobeDir, 0755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by snapd. Do not edit\n\n")
+	for _, mod := range disabled {
+		// blacklist prevents loading the module through its aliases,
+		// install prevents loading it explicitly
+		fmt.Fprintf(&buf, "blacklist %s\ninstall %s /bin/false\n", mod, mod)
+	}
+	return osutil.AtomicWriteFile(kernelModulesConfFile(), buf.Bytes(), 0644, 0)
+}
+
+// kernelModulesOnLinkageChanged applies the kernel modules configuration
+// recorded for the revision of the kernel snap that became current. A
+// revision without a recorded configuration inherits the one of the revision
+// that was current before it.
+func kernelModulesOnLinkageChanged(st *state.State, snapsup *snapstate.SnapSetup) error {
+	if snapsup.Type != snap.TypeKernel {
+		return nil
+	}
+	snapst, info, err := currentKernelSnap(st)
+	if err != nil {
+		if errors.Is(err, ErrNoKernelSnap) {
+			return nil
+		}
+		return err
+	}
+	if info.InstanceName() != snapsup.InstanceName() {
+		return nil
+	}
+	kms, err := getKernelModulesState(st)
+	if err != nil {
+		return err
+	}
+	if len(kms.Disabled) == 0 && kms.Applied == "" {
+		// never configured
+		return nil
+	}
+
+	rev := info.Revision.String()
+	if _, ok := kms.Disabled[rev]; !ok && kms.Applied != rev {
+		if prev, ok := kms.Disabled[kms.Applied]; ok {
+			kms.Disabled[rev] = prev
+		}
+	}
+	// forget about revisions of the kernel that are gone
+	for r := range kms.Disabled {
+		rev, err := snap.ParseRevision(r)
+		if err != nil || snapst.LastIndex(rev) < 0 {
+			delete(kms.Disabled, r)
+		}
+	}
+
+	if err := writeKernelModulesConf(kms.Disabled[rev]); err != nil {
+		return fmt.Errorf("cannot apply kernel modules configuration: %v", err)
+	}
+	kms.Applied = rev
+	st.Set("kernel-modules", kms)
+	return nil
+}
@@ -0,0 +1,225 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package devicestate_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/sequence"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/naming"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type deviceMgrKernelModulesSuite struct {
+	deviceMgrBaseSuite
+}
+
+var _ = Suite(&deviceMgrKernelModulesSuite{})
+
+func (s *deviceMgrKernelModulesSuite) SetUpTest(c *C) {
+	classic := false
+	s.setupBaseTest(c, classic)
+	s.setPCModelInState(c)
+}
+
+func mockFiles(c *C, dir string, files ...string) {
+	for _, f := range files {
+		p := filepath.Join(dir, f)
+		c.Assert(os.MkdirAll(filepath.Dir(p), 0755), IsNil)
+		c.Assert(os.WriteFile(p, nil, 0644), IsNil)
+	}
+}
+
+// mockKernel installs the given revisions of pc-kernel, the last one being
+// current, with a wifi component for the current one.
+func (s *deviceMgrKernelModulesSuite) mockKernel(c *C, revs ...int) {
+	var revsSideInfo []*sequence.RevisionSideState
+	for i, rev := range revs {
+		si := &snap.SideInfo{RealName: "pc-kernel", SnapID: snaptest.AssertedSnapID("pc-kernel"), Revision: snap.R(rev)}
+		info := snaptest.MockSnap(c, "name: pc-kernel\ntype: kernel\nversion: 1\n", si)
+		mockFiles(c, info.MountDir(),
+			"modules/6.8.0-1-generic/kernel/drivers/net/e1000e.ko.zst",
+			"modules/6.8.0-1-generic/kernel/drivers/usb/usb-storage.ko.zst",
+			"modules/6.8.0-1-generic/kernel/fs/btrfs/btrfs.ko.zst",
+		)
+		var comps []*sequence.ComponentState
+		if i == len(revs)-1 {
+			csi := snap.NewComponentSideInfo(naming.NewComponentRef("pc-kernel", "wifi"), snap.R(7))
+			comps = append(comps, sequence.NewComponentState(csi, snap.TestComponent))
+			cpi := snap.MinimalComponentContainerPlaceInfo("wifi", snap.R(7), "pc-kernel", si.Revision)
+			mockFiles(c, cpi.MountDir(), "modules/6.8.0-1-generic/kernel/drivers/net/wireless/iwlwifi.ko.zst")
+		}
+		revsSideInfo = append(revsSideInfo, sequence.NewRevisionSideState(si, comps))
+	}
+	snapstate.Set(s.state, "pc-kernel", &snapstate.SnapState{
+		Active:   true,
+		Sequence: snapstatetest.NewSequenceFromRevisionSideInfos(revsSideInfo),
+		Current:  snap.R(revs[len(revs)-1]),
+		SnapType: "kernel",
+	})
+}
+
+func (s *deviceMgrKernelModulesSuite) confFile() string {
+	return filepath.Join(dirs.SnapKModModprobeDir, "snapd.kernel-modules.conf")
+}
+
+func (s *deviceMgrKernelModulesSuite) TestKernelModules(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockKernel(c, 1)
+	mockFiles(c, dirs.SnapKModModulesDir, "snap.other.conf")
+	c.Assert(os.WriteFile(filepath.Join(dirs.SnapKModModulesDir, "snap.foo.conf"),
+		[]byte("# This file is automatically generated.\nbtrfs\n"), 0644), IsNil)
+
+	modules, err := devicestate.KernelModules(s.state)
+	c.Assert(err, IsNil)
+	c.Check(modules, DeepEquals, []*devicestate.KernelModule{
+		{Name: "btrfs", LoadedBy: []string{"foo"}},
+		{Name: "e1000e"},
+		{Name: "iwlwifi", Component: "wifi"},
+		{Name: "usb_storage"},
+	})
+}
+
+func (s *deviceMgrKernelModulesSuite) TestKernelModulesNoKernel(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	_, err := devicestate.KernelModules(s.state)
+	c.Check(err, ErrorMatches, "device has no kernel snap")
+}
+
+func (s *deviceMgrKernelModulesSuite) TestSetKernelModulesDisabled(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockKernel(c, 1)
+
+	err := devicestate.SetKernelModulesDisabled(s.state, []string{"usb-storage", "iwlwifi"}, true)
+	c.Assert(err, IsNil)
+	c.Check(s.confFile(), testutil.FileEquals, `# Generated by snapd. Do not edit
+
+blacklist iwlwifi
+install iwlwifi /bin/false
+blacklist usb_storage
+install usb_storage /bin/false
+`)
+
+	modules, err := devicestate.KernelModules(s.state)
+	c.Assert(err, IsNil)
+	c.Check(modules, DeepEquals, []*devicestate.KernelModule{
+		{Name: "btrfs"},
+		{Name: "e1000e"},
+		{Name: "iwlwifi", Component: "wifi", Disabled: true},
+		{Name: "usb_storage", Disabled: true},
+	})
+
+	err = devicestate.SetKernelModulesDisabled(s.state, []string{"iwlwifi"}, false)
+	c.Assert(err, IsNil)
+	c.Check(s.confFile(), testutil.FileEquals, `# Generated by snapd. Do not edit
+
+blacklist usb_storage
+install usb_storage /bin/false
+`)
+
+	err = devicestate.SetKernelModulesDisabled(s.state, []string{"usb_storage"}, false)
+	c.Assert(err, IsNil)
+	c.Check(s.confFile(), testutil.FileAbsent)
+}
+
+func (s *deviceMgrKernelModulesSuite) TestSetKernelModulesDisabledErrors(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockKernel(c, 1)
+	c.Assert(os.MkdirAll(dirs.SnapKModModulesDir, 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(dirs.SnapKModModulesDir, "snap.foo.conf"), []byte("btrfs\n"), 0644), IsNil)
+
+	err := devicestate.SetKernelModulesDisabled(s.state, []string{"e1000e", "nvidia"}, true)
+	c.Check(err, ErrorMatches, `kernel snap "pc-kernel" does not ship module "nvidia"`)
+	err = devicestate.SetKernelModulesDisabled(s.state, []string{"btrfs"}, true)
+	c.Check(err, ErrorMatches, `cannot disable kernel module "btrfs": loaded on boot by "foo" through interface connections`)
+	c.Check(s.confFile(), testutil.FileAbsent)
+}
+
+func (s *deviceMgrKernelModulesSuite) TestKernelModulesFollowKernelRevision(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockKernel(c, 1)
+	c.Assert(devicestate.SetKernelModulesDisabled(s.state, []string{"e1000e"}, true), IsNil)
+
+	// refresh to a new revision, which inherits the configuration
+	s.mockKernel(c, 1, 2)
+	snapsup := &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{RealName: "pc-kernel", Revision: snap.R(2)},
+		Type:     snap.TypeKernel,
+	}
+	c.Assert(devicestate.KernelModulesOnLinkageChanged(s.state, snapsup), IsNil)
+	c.Assert(devicestate.SetKernelModulesDisabled(s.state, []string{"btrfs"}, true), IsNil)
+	c.Check(s.confFile(), testutil.FileContains, "blacklist btrfs\n")
+	c.Check(s.confFile(), testutil.FileContains, "blacklist e1000e\n")
+
+	// revert to the old revision and its configuration
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "pc-kernel", &snapst), IsNil)
+	snapst.Current = snap.R(1)
+	snapstate.Set(s.state, "pc-kernel", &snapst)
+	snapsup.SideInfo.Revision = snap.R(1)
+	c.Assert(devicestate.KernelModulesOnLinkageChanged(s.state, snapsup), IsNil)
+	c.Check(s.confFile(), testutil.FileEquals, `# Generated by snapd. Do not edit
+
+blacklist e1000e
+install e1000e /bin/false
+`)
+
+	// other snaps are ignored
+	c.Assert(os.Remove(s.confFile()), IsNil)
+	c.Assert(devicestate.KernelModulesOnLinkageChanged(s.state, &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{RealName: "foo", Revision: snap.R(1)},
+		Type:     snap.TypeApp,
+	}), IsNil)
+	c.Check(s.confFile(), testutil.FileAbsent)
+}
+
+func (s *deviceMgrKernelModulesSuite) TestKernelModulesLinkageNeverConfigured(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockKernel(c, 1)
+	c.Assert(devicestate.KernelModulesOnLinkageChanged(s.state, &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{RealName: "pc-kernel", Revision: snap.R(1)},
+		Type:     snap.TypeKernel,
+	}), IsNil)
+	c.Check(s.confFile(), testutil.FileAbsent)
+	var kms map[string]interface{}
+	c.Check(s.state.Get("kernel-modules", &kms), testutil.ErrorIs, state.ErrNoState)
+}
@@ -0,0 +1,219 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package devicestate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/boot"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/overlord/assertstate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+// modelReissueHeaders are the model headers that are allowed to change when
+// a model assertion is reissued, they do not affect the snaps, the store or
+// the boot setup of the device.
+var modelReissueHeaders = map[string]bool{
+	"revision":          true,
+	"timestamp":         true,
+	"sign-key-sha3-384": true,
+	"display-name":      true,
+	"contacts":          true,
+	"body-length":       true,
+}
+
+// ModelDifference describes a header that differs between two model
+// assertions. The body of the assertions is compared as the "body" header.
+type ModelDifference struct {
+	Header string      `json:"header"`
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+}
+
+func modelDifferences(current, new *asserts.Model) []ModelDifference {
+	oldHeaders := current.Headers()
+	newHeaders := new.Headers()
+	names := make([]string, 0, len(oldHeaders))
+	for name := range oldHeaders {
+		names = append(names, name)
+	}
+	for name := range newHeaders {
+		if _, ok := oldHeaders[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []ModelDifference
+	for _, name := range names {
+		if !reflect.DeepEqual(oldHeaders[name], newHeaders[name]) {
+			diffs = append(diffs, ModelDifference{
+				Header: name,
+				Old:    oldHeaders[name],
+				New:    newHeaders[name],
+			})
+		}
+	}
+	if !bytes.Equal(current.Body(), new.Body()) {
+		diffs = append(diffs, ModelDifference{
+			Header: "body",
+			Old:    string(current.Body()),
+			New:    string(new.Body()),
+		})
+	}
+	return diffs
+}
+
+// checkModelReissue checks that the new model is a reissue of the current
+// one, that is a newer revision of the same model differing only in
+// metadata or in the key used to sign it.
+func checkModelReissue(st *state.State, current, new *asserts.Model, diffs []ModelDifference) error {
+	if current.BrandID() != new.BrandID() || current.Model() != new.Model() {
+		return fmt.Errorf("cannot reissue model %s/%s as %s/%s, a remodel is required", current.BrandID(), current.Model(), new.BrandID(), new.Model())
+	}
+	if new.Revision() <= current.Revision() {
+		return fmt.Errorf("cannot reissue model %s/%s with revision %d not newer than the current revision %d", new.BrandID(), new.Model(), new.Revision(), current.Revision())
+	}
+	for _, diff := range diffs {
+		if diff.Header == "body" || modelReissueHeaders[diff.Header] {
+			continue
+		}
+		return fmt.Errorf("cannot reissue model %s/%s with a different %q header, a remodel is required", new.BrandID(), new.Model(), diff.Header)
+	}
+	// the signing key of the model is part of what the disk encryption
+	// keys are sealed against and the recovery systems carry the model
+	// they were created with, a new recovery system is needed which only
+	// a remodel creates
+	if current.Grade() != asserts.ModelGradeUnset && current.SignKeyID() != new.SignKeyID() {
+		return fmt.Errorf("cannot reissue model %s/%s signed with a different key on a UC20+ system, a remodel is required", new.BrandID(), new.Model())
+	}
+	if err := assertstate.DB(st).Check(new); err != nil {
+		return fmt.Errorf("cannot reissue model %s/%s: %v", new.BrandID(), new.Model(), err)
+	}
+	return nil
+}
+
+// ModelReissuePreview describes how a new model assertion differs from the
+// current one.
+type ModelReissuePreview struct {
+	Differences []ModelDifference
+	// Err is set if the new model cannot be accepted with ReissueModel
+	Err error
+}
+
+// PreviewModelReissue compares the current model with the given new model
+// assertion and reports whether the latter can be accepted as a reissue.
+func PreviewModelReissue(st *state.State, new *asserts.Model) (*ModelReissuePreview, error) {
+	current, err := findModel(st)
+	if err != nil {
+		return nil, err
+	}
+	diffs := modelDifferences(current, new)
+	return &ModelReissuePreview{
+		Differences: diffs,
+		Err:         checkModelReissue(st, current, new, diffs),
+	}, nil
+}
+
+// ReissueModel takes a reissued model assertion, that is a newer revision
+// of the current model differing only in metadata like the display-name or
+// in the key used to sign it, and generates a change that accepts it
+// without going through a full remodel.
+func ReissueModel(st *state.State, new *asserts.Model) (*state.Change, error) {
+	var seeded bool
+	err := st.Get("seeded", &seeded)
+	if err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	if !seeded {
+		return nil, fmt.Errorf("cannot reissue model until fully seeded")
+	}
+
+	current, err := findModel(st)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkModelReissue(st, current, new, modelDifferences(current, new)); err != nil {
+		return nil, err
+	}
+
+	if err := snapstate.CheckChangeConflictRunExclusively(st, "reissue-model"); err != nil {
+		return nil, err
+	}
+
+	msg := fmt.Sprintf(i18n.G("Reissue model assertion %s/%s from revision %v to %v"), new.BrandID(), new.Model(), current.Revision(), new.Revision())
+	chg := st.NewChange("reissue-model", msg)
+	t := st.NewTask("reissue-model", msg)
+	t.Set("new-model", string(asserts.Encode(new)))
+	chg.AddTask(t)
+
+	return chg, nil
+}
+
+func (m *DeviceManager) doReissueModel(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var encNewModel string
+	if err := t.Get("new-model", &encNewModel); err != nil {
+		return err
+	}
+	a, err := asserts.Decode([]byte(encNewModel))
+	if err != nil {
+		return err
+	}
+	new, ok := a.(*asserts.Model)
+	if !ok {
+		return fmt.Errorf("internal error: cannot use a reissued new-model, wrong type")
+	}
+
+	current, err := findModel(st)
+	if err != nil {
+		return err
+	}
+	// the current model might have changed in the meantime
+	if err := checkModelReissue(st, current, new, modelDifferences(current, new)); err != nil {
+		return err
+	}
+
+	// on UC20+ the model is also recorded for booting
+	systemMode := m.SystemMode(SysAny)
+	from := &groundDeviceContext{model: current, systemMode: systemMode}
+	to := &groundDeviceContext{model: new, systemMode: systemMode}
+	if err := boot.DeviceChange(from, to, st.Unlocker()); err != nil {
+		return fmt.Errorf("cannot switch device: %v", err)
+	}
+
+	if err := assertstate.Add(st, new); err != nil && !isSameAssertsRevision(err) {
+		return err
+	}
+
+	return nil
+}
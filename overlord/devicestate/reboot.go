@@ -0,0 +1,113 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package devicestate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+var osutilBootID = osutil.BootID
+
+// ScheduledReboot describes a reboot, halt or power off of the system
+// scheduled with ScheduleReboot.
+type ScheduledReboot struct {
+	// Op is either RebootHaltOp, RebootPoweroffOp or empty for a plain
+	// reboot.
+	Op string `json:"op,omitempty"`
+	// At is the time from which the operation can be carried out. If
+	// unset, the operation is carried out as soon as possible.
+	At time.Time `json:"at,omitempty"`
+	// AfterChanges is set if the operation must wait for the changes in
+	// progress to complete.
+	AfterChanges bool `json:"after-changes,omitempty"`
+	// RecoverySystem, Mode and Reason, if set, request booting into the
+	// given recovery system and mode, see DeviceManager.RequestRecovery.
+	RecoverySystem string `json:"recovery-system,omitempty"`
+	Mode           string `json:"mode,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+func (sr *ScheduledReboot) summary() string {
+	var what string
+	switch sr.Op {
+	case RebootHaltOp:
+		what = "Halt the system"
+	case RebootPoweroffOp:
+		what = "Power off the system"
+	default:
+		what = "Reboot the system"
+		if sr.Mode != "" {
+			what += fmt.Sprintf(" into recovery system %q in %q mode", sr.RecoverySystem, sr.Mode)
+		}
+	}
+	if !sr.At.IsZero() {
+		what += fmt.Sprintf(" at %s", sr.At.Format(time.RFC3339))
+	}
+	if sr.AfterChanges {
+		what += " after current changes complete"
+	}
+	return what
+}
+
+// ScheduleReboot returns a change that reboots, halts or powers off the
+// system as described by sr. While the change waits, the system restarts
+// needed by other changes, e.g. refreshes of the kernel, are postponed so
+// that the scheduled reboot serves them as well and the system is rebooted
+// only once.
+func ScheduleReboot(st *state.State, sr *ScheduledReboot) (*state.Change, error) {
+	switch sr.Op {
+	case "", RebootHaltOp, RebootPoweroffOp:
+	default:
+		return nil, fmt.Errorf("unsupported reboot operation %q", sr.Op)
+	}
+	if sr.Mode != "" {
+		if sr.Op != "" {
+			return nil, fmt.Errorf("cannot request a recovery system with reboot operation %q", sr.Op)
+		}
+		if sr.RecoverySystem == "" {
+			return nil, fmt.Errorf("cannot request recovery without a recovery system")
+		}
+		if err := ValidateRecoveryReason(sr.Reason); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, chg := range st.Changes() {
+		if chg.Kind() == "schedule-reboot" && !chg.IsReady() {
+			return nil, &snapstate.ChangeConflictError{
+				ChangeKind: "schedule-reboot",
+				ChangeID:   chg.ID(),
+				Message:    "cannot schedule reboot: a system reboot is already scheduled",
+			}
+		}
+	}
+
+	summary := sr.summary()
+	t := st.NewTask("scheduled-reboot", summary)
+	t.Set("scheduled-reboot", sr)
+	chg := st.NewChange("schedule-reboot", summary)
+	chg.AddTask(t)
+
+	return chg, nil
+}
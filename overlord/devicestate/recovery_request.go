@@ -0,0 +1,277 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package devicestate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/snapcore/snapd/boot"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/release"
+)
+
+// RecoveryRequestStatus is the status of a request to boot into a recovery
+// system.
+type RecoveryRequestStatus string
+
+const (
+	// RecoveryRequestPending is the status of a request until the system
+	// is back in run mode after the reboot.
+	RecoveryRequestPending RecoveryRequestStatus = "pending"
+	// RecoveryRequestCompleted is the status of a request after the
+	// requested system and mode were entered and the system went back
+	// to run mode.
+	RecoveryRequestCompleted RecoveryRequestStatus = "completed"
+	// RecoveryRequestNotEntered is the status of a request if the system
+	// rebooted into run mode without entering the requested system and
+	// mode.
+	RecoveryRequestNotEntered RecoveryRequestStatus = "not-entered"
+)
+
+// RecoveryRequest is a request to boot into a recovery system in a given
+// mode on the next reboot, along with its reason code and outcome.
+type RecoveryRequest struct {
+	System string                `json:"system"`
+	Mode   string                `json:"mode"`
+	Reason string                `json:"reason"`
+	Status RecoveryRequestStatus `json:"status"`
+
+	RequestedAt time.Time `json:"requested-at"`
+	// EnteredAt is when the requested system and mode were entered.
+	EnteredAt time.Time `json:"entered-at,omitempty"`
+	// CompletedAt is when the outcome of the request was observed back
+	// in run mode.
+	CompletedAt time.Time `json:"completed-at,omitempty"`
+
+	// BootID is the boot in which the request was made.
+	BootID string `json:"boot-id"`
+}
+
+// recoveryEntered is recorded into the host data by snapd running in the
+// requested system and mode.
+type recoveryEntered struct {
+	System    string    `json:"system"`
+	Mode      string    `json:"mode"`
+	EnteredAt time.Time `json:"entered-at"`
+}
+
+var validRecoveryReason = regexp.MustCompile("^[a-z0-9]+(?:-[a-z0-9]+)*$")
+
+// ValidateRecoveryReason checks that reason is a valid reason code for a
+// recovery request.
+func ValidateRecoveryReason(reason string) error {
+	if reason == "" {
+		return fmt.Errorf("recovery reason code cannot be empty")
+	}
+	if len(reason) > 64 || !validRecoveryReason.MatchString(reason) {
+		return fmt.Errorf("invalid recovery reason code %q", reason)
+	}
+	return nil
+}
+
+func recoveryEnteredFile(rootdir string) string {
+	return filepath.Join(dirs.SnapDeviceDirUnder(rootdir), "recovery-entered")
+}
+
+// RequestRecovery sets up the system to boot into the given recovery
+// system and mode on the next reboot, and records the request along with
+// its reason code. The outcome is reported through RecoveryRequestFromState
+// once the system is back in run mode.
+//
+// No reboot is triggered.
+func (m *DeviceManager) RequestRecovery(systemLabel, mode, reason string) error {
+	if systemLabel == "" {
+		return fmt.Errorf("internal error: system label is unset")
+	}
+	if err := ValidateRecoveryReason(reason); err != nil {
+		return err
+	}
+	if mode == "run" {
+		return fmt.Errorf("cannot request recovery into run mode")
+	}
+	if m.SystemMode(SysHasModeenv) != "run" {
+		return fmt.Errorf("cannot request recovery outside of run mode")
+	}
+	bootID, err := osutilBootID()
+	if err != nil {
+		return err
+	}
+
+	nop := func() {}
+	switched := func(systemLabel string, sysAction *SystemAction) {
+		logger.Noticef("system %q will be booted in %q mode on next reboot, reason: %s", systemLabel, sysAction.Mode, reason)
+		m.state.Set("recovery-request", &RecoveryRequest{
+			System:      systemLabel,
+			Mode:        sysAction.Mode,
+			Reason:      reason,
+			Status:      RecoveryRequestPending,
+			RequestedAt: timeNow(),
+			BootID:      bootID,
+		})
+	}
+	return m.switchToSystemAndMode(systemLabel, mode, nop, switched)
+}
+
+// RecoveryRequestFromState returns the last recovery request, or
+// state.ErrNoState if there was none.
+func RecoveryRequestFromState(st *state.State) (*RecoveryRequest, error) {
+	var req RecoveryRequest
+	if err := st.Get("recovery-request", &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// ensureRecoveryRequest records entering a requested recovery system and
+// mode, and reports the outcome of the request once back in run mode.
+func (m *DeviceManager) ensureRecoveryRequest() error {
+	if release.OnClassic || m.ensureRecoveryRequestRan {
+		return nil
+	}
+
+	done := true
+	var err error
+	switch m.SystemMode(SysHasModeenv) {
+	case "recover":
+		done, err = m.recordRecoveryEntered()
+	case "run":
+		err = m.reportRecoveryRequestOutcome()
+	}
+	if err != nil {
+		return err
+	}
+
+	m.ensureRecoveryRequestRan = done
+	return nil
+}
+
+// recordRecoveryEntered records in the data of the host system that the
+// recovery system was entered, for snapd in run mode to report the
+// outcome of the request.
+func (m *DeviceManager) recordRecoveryEntered() (done bool, err error) {
+	modeenv, err := maybeReadModeenv()
+	if err != nil {
+		return false, err
+	}
+	if modeenv == nil {
+		return true, nil
+	}
+
+	m.state.Lock()
+	deviceCtx, err := DeviceCtx(m.state, nil, nil)
+	m.state.Unlock()
+	if errors.Is(err, state.ErrNoState) {
+		// the model is not known until seeded, try again later
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	hostWritableDir := boot.InitramfsHostWritableDir(deviceCtx.Model())
+	if !osutil.IsDirectory(hostWritableDir) {
+		// the host data is not available
+		return true, nil
+	}
+
+	b, err := json.Marshal(&recoveryEntered{
+		System:    modeenv.RecoverySystem,
+		Mode:      modeenv.Mode,
+		EnteredAt: timeNow(),
+	})
+	if err != nil {
+		return false, err
+	}
+	enteredFile := recoveryEnteredFile(hostWritableDir)
+	if err := os.MkdirAll(filepath.Dir(enteredFile), 0755); err != nil {
+		return false, err
+	}
+	if err := osutil.AtomicWriteFile(enteredFile, b, 0644, 0); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reportRecoveryRequestOutcome updates the status of a pending recovery
+// request after the system rebooted.
+func (m *DeviceManager) reportRecoveryRequestOutcome() error {
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	req, err := RecoveryRequestFromState(m.state)
+	if errors.Is(err, state.ErrNoState) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if req.Status != RecoveryRequestPending {
+		return nil
+	}
+
+	bootID, err := osutilBootID()
+	if err != nil {
+		return err
+	}
+	if req.BootID == bootID {
+		// not rebooted yet
+		return nil
+	}
+
+	var entered recoveryEntered
+	enteredFile := recoveryEnteredFile(dirs.GlobalRootDir)
+	b, err := os.ReadFile(enteredFile)
+	switch {
+	case os.IsNotExist(err):
+		// not entered
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(b, &entered); err != nil {
+			logger.Noticef("cannot read recovery system entry record: %v", err)
+		}
+	}
+
+	req.CompletedAt = timeNow()
+	// the record may be left over from entering recovery without a request
+	if entered.System == req.System && entered.Mode == req.Mode && entered.EnteredAt.After(req.RequestedAt) {
+		req.Status = RecoveryRequestCompleted
+		req.EnteredAt = entered.EnteredAt
+		logger.Noticef("requested recovery into system %q in %q mode completed, reason: %s", req.System, req.Mode, req.Reason)
+	} else {
+		req.Status = RecoveryRequestNotEntered
+		logger.Noticef("requested recovery into system %q in %q mode was not entered, reason: %s", req.System, req.Mode, req.Reason)
+	}
+	m.state.Set("recovery-request", req)
+
+	if err := os.Remove(enteredFile); err != nil && !os.IsNotExist(err) {
+		logger.Noticef("cannot remove recovery system entry record: %v", err)
+	}
+	return nil
+}
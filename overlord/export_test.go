@@ -32,7 +32,8 @@ import (
 )
 
 var (
-	LockWithTimeout = lockWithTimeout
+	LockWithTimeout       = lockWithTimeout
+	ApplyTimingsRetention = applyTimingsRetention
 )
 
 // MockEnsureInterval sets the overlord ensure interval for tests.
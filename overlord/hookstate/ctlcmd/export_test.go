@@ -102,8 +102,7 @@ func FormatLongPublisher(snapInfo *snap.Info, storeAccountID string) string {
 }
 
 func FindSerialAssertion(st *state.State, modelAssertion *asserts.Model) (*asserts.Serial, error) {
-	var mc modelCommand
-	return mc.findSerialAssertion(st, modelAssertion)
+	return findSerialAssertion(st, modelAssertion)
 }
 
 type MockCommand struct {
@@ -21,11 +21,14 @@ package ctlcmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/snapcore/snapd/aspects"
 	"github.com/snapcore/snapd/i18n"
 	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/overlord/aspectstate"
 	"github.com/snapcore/snapd/overlord/configstate"
 	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/hookstate"
@@ -44,8 +47,9 @@ type getCommand struct {
 		Keys           []string `positional-arg-name:"<keys>" description:"option keys"`
 	} `positional-args:"yes"`
 
-	Document bool `short:"d" description:"always return document, even with single key"`
-	Typed    bool `short:"t" description:"strict typing with nulls and quoted strings"`
+	Document bool   `short:"d" description:"always return document, even with single key"`
+	Typed    bool   `short:"t" description:"strict typing with nulls and quoted strings"`
+	Aspect   string `long:"aspect" value-name:"<account>/<bundle>/<aspect>" description:"get the values of the given aspect"`
 }
 
 var shortGetHelp = i18n.G("Print either configuration options or interface connection settings")
@@ -82,6 +86,11 @@ with optional --plug and --slot command options:
 
 This requests the "usb-vendor" setting from the slot that is connected to
 "myplug".
+
+The gadget snap may read the values of system aspects, such as the netplan
+settings of the system/network bundle:
+
+    $ snapctl get --aspect system/network/netplan ethernets.eth0
 `)
 
 func init() {
@@ -146,6 +155,17 @@ func (c *getCommand) Execute(args []string) error {
 		return fmt.Errorf("cannot use -d and -t together")
 	}
 
+	if c.Aspect != "" {
+		if c.ForcePlugSide || c.ForceSlotSide {
+			return fmt.Errorf("cannot use --plug or --slot with --aspect")
+		}
+		if c.Positional.PlugOrSlotSpec != "" {
+			c.Positional.Keys = append([]string{c.Positional.PlugOrSlotSpec}, c.Positional.Keys...)
+			c.Positional.PlugOrSlotSpec = ""
+		}
+		return c.getAspectValues(context)
+	}
+
 	if strings.Contains(c.Positional.PlugOrSlotSpec, ":") {
 		parts := strings.SplitN(c.Positional.PlugOrSlotSpec, ":", 2)
 		snap, name := parts[0], parts[1]
@@ -194,6 +214,47 @@ func (c *getCommand) getConfigSetting(context *hookstate.Context) error {
 	})
 }
 
+func (c *getCommand) getAspectValues(context *hookstate.Context) error {
+	account, bundleName, aspect, err := parseAspectID(c.Aspect)
+	if err != nil {
+		return err
+	}
+
+	st := context.State()
+	st.Lock()
+	defer st.Unlock()
+
+	if err := checkAspectAccess(st, context.InstanceName(), account, bundleName); err != nil {
+		return err
+	}
+
+	tx, err := aspectstate.NewTransaction(st, account, bundleName)
+	if err != nil {
+		return err
+	}
+
+	return c.printValues(func(key string) (interface{}, bool, error) {
+		res, err := aspectstate.GetAspect(tx, account, bundleName, aspect, key)
+		if err != nil {
+			if errors.Is(err, &aspects.NotFoundError{}) {
+				var value interface{}
+				if !c.Typed {
+					value = ""
+				}
+				return value, false, nil
+			}
+			return nil, false, err
+		}
+
+		// the result maps the request to its value
+		resMap, ok := res.(map[string]interface{})
+		if !ok {
+			return nil, false, fmt.Errorf("internal error: unexpected aspect result type %T", res)
+		}
+		return resMap[key], true, nil
+	})
+}
+
 type ifaceHookType int
 
 const (
@@ -24,6 +24,7 @@ import (
 
 	. "gopkg.in/check.v1"
 
+	"github.com/snapcore/snapd/aspects"
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/overlord/configstate"
 	"github.com/snapcore/snapd/overlord/configstate/config"
@@ -31,7 +32,9 @@ import (
 	"github.com/snapcore/snapd/overlord/hookstate/ctlcmd"
 	"github.com/snapcore/snapd/overlord/hookstate/hooktest"
 	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/release"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
 )
 
 type getSuite struct {
@@ -436,3 +439,70 @@ func (s *getAttrSuite) TestSlotHookTests(c *C) {
 		}
 	}
 }
+
+type getAspectSuite struct {
+	testutil.BaseTest
+
+	state       *state.State
+	mockContext *hookstate.Context
+}
+
+var _ = Suite(&getAspectSuite{})
+
+func (s *getAspectSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	// netplan isn't queried for its configuration on classic
+	s.AddCleanup(release.MockOnClassic(true))
+
+	s.state = state.New(nil)
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	mockAspectSnaps(s.state)
+
+	bag := aspects.NewJSONDataBag()
+	c.Assert(bag.Set("wifi.ssid", "my-ssid"), IsNil)
+	s.state.Set("aspect-databags", map[string]map[string]aspects.JSONDataBag{
+		"system": {"network": bag},
+	})
+
+	task := s.state.NewTask("run-hook", "my test task")
+	setup := &hookstate.HookSetup{Snap: "pc", Revision: snap.R(1), Hook: "configure"}
+
+	var err error
+	s.mockContext, err = hookstate.NewContext(task, s.state, setup, hooktest.NewMockHandler(), "")
+	c.Assert(err, IsNil)
+}
+
+func (s *getAspectSuite) TestGetAspect(c *C) {
+	stdout, stderr, err := ctlcmd.Run(s.mockContext, []string{"get", "--aspect", "system/network/wifi-setup", "ssid"}, 0)
+	c.Assert(err, IsNil)
+	c.Check(string(stdout), Equals, "my-ssid\n")
+	c.Check(string(stderr), Equals, "")
+
+	stdout, _, err = ctlcmd.Run(s.mockContext, []string{"get", "--aspect", "system/network/wifi-setup", "-d", "ssid", "status"}, 0)
+	c.Assert(err, IsNil)
+	c.Check(string(stdout), Equals, "{\n\t\"ssid\": \"my-ssid\"\n}\n")
+
+	stdout, _, err = ctlcmd.Run(s.mockContext, []string{"get", "--aspect", "system/network/netplan", "-t", "renderer"}, 0)
+	c.Assert(err, IsNil)
+	c.Check(string(stdout), Equals, "null\n")
+}
+
+func (s *getAspectSuite) TestGetAspectErrors(c *C) {
+	_, _, err := ctlcmd.Run(s.mockContext, []string{"get", "--aspect", "system/network/wifi-setup", "--slot", "ssid"}, 0)
+	c.Check(err, ErrorMatches, "cannot use --plug or --slot with --aspect")
+
+	_, _, err = ctlcmd.Run(s.mockContext, []string{"get", "--aspect", "system//wifi-setup", "ssid"}, 0)
+	c.Check(err, ErrorMatches, `invalid aspect "system//wifi-setup": want <account>/<bundle>/<aspect>`)
+
+	s.state.Lock()
+	task := s.state.NewTask("run-hook", "other hook")
+	s.state.Unlock()
+	setup := &hookstate.HookSetup{Snap: "test-snap", Revision: snap.R(1), Hook: "configure"}
+	ctx, err := hookstate.NewContext(task, s.state, setup, hooktest.NewMockHandler(), "")
+	c.Assert(err, IsNil)
+
+	_, _, err = ctlcmd.Run(ctx, []string{"get", "--aspect", "system/network/wifi-setup", "ssid"}, 0)
+	c.Check(err, ErrorMatches, `cannot access aspect bundle system/network: only the gadget snap can access system aspects`)
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/snapcore/snapd/i18n"
 	"github.com/snapcore/snapd/jsonutil"
+	"github.com/snapcore/snapd/overlord/aspectstate"
 	"github.com/snapcore/snapd/overlord/configstate"
 	"github.com/snapcore/snapd/overlord/hookstate"
 	"github.com/snapcore/snapd/overlord/servicestate"
@@ -232,3 +233,36 @@ func getAttribute(snapName string, subkeys []string, pos int, attrs map[string]i
 	}
 	return getAttribute(snapName, subkeys, pos+1, attrsm, result)
 }
+
+// parseAspectID parses an aspect identifier in the <account>/<bundle>/<aspect>
+// format.
+func parseAspectID(id string) (account, bundleName, aspect string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf(i18n.G("invalid aspect %q: want <account>/<bundle>/<aspect>"), id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// checkAspectAccess checks whether the snap can access the aspect bundle.
+// Only the system bundles are accessible through snapctl and only the gadget
+// snap can access them.
+func checkAspectAccess(st *state.State, snapName, account, bundleName string) error {
+	if !aspectstate.IsSystemBundle(account, bundleName) {
+		return fmt.Errorf("cannot access aspect bundle %s/%s: only system bundles are supported", account, bundleName)
+	}
+
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(st, snapName, &snapst); err != nil {
+		return err
+	}
+
+	typ, err := snapst.Type()
+	if err != nil {
+		return err
+	}
+	if typ != snap.TypeGadget {
+		return fmt.Errorf("cannot access aspect bundle %s/%s: only the gadget snap can access system aspects", account, bundleName)
+	}
+	return nil
+}
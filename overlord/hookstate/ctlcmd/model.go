@@ -113,7 +113,7 @@ func (c *modelCommand) reportError(format string, a ...interface{}) {
 
 // hasSnapdControlInterface returns true if the requesting snap has the
 // snapd-control plug and only if it is connected as well.
-func (c *modelCommand) hasSnapdControlInterface(st *state.State, snapName string) (bool, error) {
+func hasSnapdControlInterface(st *state.State, snapName string) (bool, error) {
 	conns, err := ifacestate.ConnectionStates(st)
 	if err != nil {
 		return false, err
@@ -135,7 +135,7 @@ func (c *modelCommand) hasSnapdControlInterface(st *state.State, snapName string
 
 // getSnapInfoWithPublisher is a helper utility to read the snap.Info for the requesting snap
 // which also fills the publisher information.
-func (c *modelCommand) getSnapInfoWithPublisher(st *state.State, snapName string) (*snap.Info, error) {
+func getSnapInfoWithPublisher(st *state.State, snapName string) (*snap.Info, error) {
 	var snapst snapstate.SnapState
 	if err := snapstate.Get(st, snapName, &snapst); err != nil {
 		return nil, fmt.Errorf("failed to get snapstate for snap %s: %v", snapName, err)
@@ -150,23 +150,31 @@ func (c *modelCommand) getSnapInfoWithPublisher(st *state.State, snapName string
 	return snapInfo, err
 }
 
-// checkPermissions verifies that the snap described by snapInfo is allowed to
-// read the model assertion of deviceCtx.
-// We allow the usage of this command if one of the following is true
+// canAccessModel returns whether the snap described by snapInfo is allowed to
+// read the model assertion of deviceCtx and details derived from it.
+// We allow this if one of the following is true
 // 1. The requesting snap must be a gadget
 // 2. Come from the same brand as the device model assertion
 // 3. Have the snapd-control plug
-func (c *modelCommand) checkPermissions(st *state.State, deviceCtx snapstate.DeviceContext, snapInfo *snap.Info) error {
+func canAccessModel(st *state.State, deviceCtx snapstate.DeviceContext, snapInfo *snap.Info) (bool, error) {
 	if snapType := snapInfo.Type(); snapType == snap.TypeGadget {
-		return nil
+		return true, nil
 	}
 	if snapInfo.Publisher.ID == deviceCtx.Model().BrandID() {
-		return nil
+		return true, nil
 	}
-	if conn, err := c.hasSnapdControlInterface(st, snapInfo.SnapName()); err != nil {
-		return fmt.Errorf("cannot check for snapd-control interface: %v", err)
-	} else if conn {
-		return nil
+	conn, err := hasSnapdControlInterface(st, snapInfo.SnapName())
+	if err != nil {
+		return false, fmt.Errorf("cannot check for snapd-control interface: %v", err)
+	}
+	return conn, nil
+}
+
+// checkPermissions verifies that the snap described by snapInfo is allowed to
+// read the model assertion of deviceCtx.
+func (c *modelCommand) checkPermissions(st *state.State, deviceCtx snapstate.DeviceContext, snapInfo *snap.Info) error {
+	if ok, err := canAccessModel(st, deviceCtx, snapInfo); err != nil || ok {
+		return err
 	}
 
 	c.reportError("cannot get model assertion for snap %q: "+
@@ -177,7 +185,7 @@ func (c *modelCommand) checkPermissions(st *state.State, deviceCtx snapstate.Dev
 
 // findSerialAssertion is a helper function to find the newest matching serial assertion
 // for the provided model assertion.
-func (c *modelCommand) findSerialAssertion(st *state.State, modelAssertion *asserts.Model) (*asserts.Serial, error) {
+func findSerialAssertion(st *state.State, modelAssertion *asserts.Model) (*asserts.Serial, error) {
 	assertions, err := assertstate.DB(st).FindMany(asserts.SerialType, map[string]string{
 		"brand-id": modelAssertion.BrandID(),
 		"model":    modelAssertion.Model(),
@@ -225,7 +233,7 @@ func (c *modelCommand) Execute([]string) error {
 	// We only return an error in case we could not the get the snap.Info
 	// structure, and 'ignore' any error that caused us not to get the store
 	// account publisher
-	snapInfo, err := c.getSnapInfoWithPublisher(st, context.InstanceName())
+	snapInfo, err := getSnapInfoWithPublisher(st, context.InstanceName())
 	if snapInfo == nil {
 		return err
 	}
@@ -251,7 +259,7 @@ func (c *modelCommand) Execute([]string) error {
 		Assertion: c.Assertion,
 	}
 
-	serialAssertion, err := c.findSerialAssertion(st, deviceCtx.Model())
+	serialAssertion, err := findSerialAssertion(st, deviceCtx.Model())
 	// Ignore the error in case the serial assertion wasn't found. We will
 	// then use the model assertion instead.
 	if err != nil && !errors.Is(err, &asserts.NotFoundError{}) {
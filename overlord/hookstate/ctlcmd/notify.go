@@ -0,0 +1,114 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ctlcmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+var (
+	shortNotifyHelp = i18n.G("Record a notice of the snap")
+	longNotifyHelp  = i18n.G(`
+The notify command records an occurrence of a notice with the given key for the
+snap. Notices are kept by snapd and can be retrieved, or waited for, by
+management agents through the notices API with the "snap" type. The key is
+namespaced by the snap, that is, notices of a snap "foo" with the key "ready"
+are listed with the key "foo/ready".
+
+Additional data of the occurrence can be given as key=value pairs. When
+--repeat-after is given, repeated occurrences of the notice within that
+duration are counted but do not wake up waiting agents.
+
+Notices are only visible to root.
+
+    $ snapctl notify ready
+    $ snapctl notify --repeat-after=1h backup-done path=/var/snap/foo/common/backup
+`)
+)
+
+func init() {
+	addCommand("notify", shortNotifyHelp, longNotifyHelp, func() command { return &notifyCommand{} })
+}
+
+type notifyCommand struct {
+	baseCommand
+
+	RepeatAfter string `long:"repeat-after" value-name:"<duration>" description:"do not repeat the notice if it last occurred less than this duration ago"`
+	Positional  struct {
+		Key  string   `positional-arg-name:"<key>" required:"yes" description:"key of the notice"`
+		Data []string `positional-arg-name:"<data-key>=<value>" description:"additional data of the occurrence"`
+	} `positional-args:"yes"`
+}
+
+// maxNotifyKeyLength is the maximum length of the key of a notice
+// recorded by a snap, without its snap namespace.
+const maxNotifyKeyLength = 200
+
+var validNotifyKey = regexp.MustCompile(`^[a-z0-9]+(?:[._/-][a-z0-9]+)*$`).MatchString
+
+func (c *notifyCommand) Execute([]string) error {
+	ctx, err := c.ensureContext()
+	if err != nil {
+		return err
+	}
+
+	key := c.Positional.Key
+	if len(key) > maxNotifyKeyLength {
+		return fmt.Errorf("notice key must be at most %d characters long", maxNotifyKeyLength)
+	}
+	if !validNotifyKey(key) {
+		return fmt.Errorf("invalid notice key %q (key must consist of lowercase ASCII letters and numbers, optionally separated by single dots, dashes, underscores or slashes)", key)
+	}
+
+	options := &state.AddNoticeOptions{}
+	if c.RepeatAfter != "" {
+		options.RepeatAfter, err = time.ParseDuration(c.RepeatAfter)
+		if err != nil {
+			return fmt.Errorf("invalid repeat-after duration: %v", err)
+		}
+		if options.RepeatAfter < 0 {
+			return fmt.Errorf("repeat-after duration cannot be negative")
+		}
+	}
+	for _, kv := range c.Positional.Data {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return fmt.Errorf("invalid notice data %q (expected <data-key>=<value>)", kv)
+		}
+		if options.Data == nil {
+			options.Data = make(map[string]string, len(c.Positional.Data))
+		}
+		options.Data[k] = v
+	}
+
+	ctx.Lock()
+	defer ctx.Unlock()
+
+	// notices of snaps are only visible to root
+	rootUID := uint32(0)
+	_, err = ctx.State().AddNotice(&rootUID, state.SnapNotice, ctx.InstanceName()+"/"+key, options)
+	return err
+}
@@ -0,0 +1,130 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ctlcmd_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/hookstate"
+	"github.com/snapcore/snapd/overlord/hookstate/ctlcmd"
+	"github.com/snapcore/snapd/overlord/hookstate/hooktest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type notifySuite struct {
+	testutil.BaseTest
+	st          *state.State
+	mockContext *hookstate.Context
+}
+
+var _ = Suite(&notifySuite{})
+
+func (s *notifySuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("/") })
+
+	s.st = state.New(nil)
+	s.st.Lock()
+	defer s.st.Unlock()
+	setup := &hookstate.HookSetup{Snap: "test-snap_instance", Revision: snap.R(1)}
+	ctx, err := hookstate.NewContext(nil, s.st, setup, hooktest.NewMockHandler(), "")
+	c.Assert(err, IsNil)
+	s.mockContext = ctx
+}
+
+func (s *notifySuite) snapNotices(c *C) []map[string]interface{} {
+	s.st.Lock()
+	defer s.st.Unlock()
+	notices := s.st.Notices(&state.NoticeFilter{Types: []state.NoticeType{state.SnapNotice}})
+	var result []map[string]interface{}
+	for _, n := range notices {
+		buf, err := json.Marshal(n)
+		c.Assert(err, IsNil)
+		var m map[string]interface{}
+		c.Assert(json.Unmarshal(buf, &m), IsNil)
+		result = append(result, m)
+	}
+	return result
+}
+
+func (s *notifySuite) TestNotify(c *C) {
+	stdout, stderr, err := ctlcmd.Run(s.mockContext, []string{"notify", "ready"}, 0)
+	c.Assert(err, IsNil)
+	c.Check(string(stdout), Equals, "")
+	c.Check(string(stderr), Equals, "")
+
+	notices := s.snapNotices(c)
+	c.Assert(notices, HasLen, 1)
+	c.Check(notices[0]["type"], Equals, "snap")
+	c.Check(notices[0]["key"], Equals, "test-snap_instance/ready")
+	c.Check(notices[0]["user-id"], Equals, 0.0)
+	c.Check(notices[0]["occurrences"], Equals, 1.0)
+	c.Check(notices[0]["last-data"], IsNil)
+
+	_, _, err = ctlcmd.Run(s.mockContext, []string{"notify", "--repeat-after=1h", "ready", "path=/a=b", "empty="}, 0)
+	c.Assert(err, IsNil)
+
+	notices = s.snapNotices(c)
+	c.Assert(notices, HasLen, 1)
+	c.Check(notices[0]["occurrences"], Equals, 2.0)
+	c.Check(notices[0]["repeat-after"], Equals, time.Hour.String())
+	c.Check(notices[0]["last-data"], DeepEquals, map[string]interface{}{
+		"path":  "/a=b",
+		"empty": "",
+	})
+}
+
+func (s *notifySuite) TestNotifyErrors(c *C) {
+	for _, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"notify"}, "the required argument `<key>` was not provided"},
+		{[]string{"notify", "Ready"}, `invalid notice key "Ready" .*`},
+		{[]string{"notify", "-ready"}, `unknown flag .*`},
+		{[]string{"notify", "a//b"}, `invalid notice key "a//b" .*`},
+		{[]string{"notify", string(make([]byte, 201))}, `notice key must be at most 200 characters long`},
+		{[]string{"notify", "ready", "foo"}, `invalid notice data "foo" \(expected <data-key>=<value>\)`},
+		{[]string{"notify", "ready", "=foo"}, `invalid notice data "=foo" .*`},
+		{[]string{"notify", "--repeat-after=soon", "ready"}, `invalid repeat-after duration: .*`},
+		{[]string{"notify", "--repeat-after=-1h", "ready"}, `repeat-after duration cannot be negative`},
+	} {
+		_, _, err := ctlcmd.Run(s.mockContext, t.args, 0)
+		c.Check(err, ErrorMatches, t.err, Commentf("%v", t.args))
+	}
+	c.Check(s.snapNotices(c), HasLen, 0)
+}
+
+func (s *notifySuite) TestNotifyNonRoot(c *C) {
+	_, _, err := ctlcmd.Run(s.mockContext, []string{"notify", "ready"}, 1000)
+	c.Check(err, ErrorMatches, `cannot use "notify" with uid 1000, try with sudo`)
+}
+
+func (s *notifySuite) TestNotifyNoContext(c *C) {
+	_, _, err := ctlcmd.Run(nil, []string{"notify", "ready"}, 0)
+	c.Check(err, ErrorMatches, `cannot invoke snapctl operation commands \(here "notify"\) from outside of a snap`)
+}
@@ -20,10 +20,15 @@
 package ctlcmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/snapcore/snapd/i18n"
 	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/overlord/hookstate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
 )
 
 var (
@@ -31,7 +36,11 @@ var (
 	longRebootHelp  = i18n.G(`
 The reboot command can used from allowed hooks to control the reboot behavior of the system.
 
-Currently it can only be invoked from gadget install-device during UC20 install mode. After invoking it from install-device with --halt or --poweroff the device will not reboot into run mode after finishing install mode but will instead either halt or power off. From install-device the effect is therefore not immediate but delayed until the end of installation itself.
+When invoked from gadget install-device during UC20 install mode with --halt or --poweroff the device will not reboot into run mode after finishing install mode but will instead either halt or power off. From install-device the effect is therefore not immediate but delayed until the end of installation itself.
+
+When invoked from other gadget hooks, a reboot, or with --halt or --poweroff a halt or power off, of the system is scheduled to happen once the changes in progress, including the one running the hook, have completed. Any restarts required by those changes are served by the same reboot.
+
+With --system, --mode and --reason the scheduled reboot boots into the given recovery system and mode, recording the reason code. The outcome is reported once the system is back in run mode.
 `)
 )
 
@@ -44,6 +53,10 @@ type rebootCommand struct {
 
 	Halt     bool `long:"halt"`
 	Poweroff bool `long:"poweroff"`
+
+	System string `long:"system"`
+	Mode   string `long:"mode"`
+	Reason string `long:"reason"`
 }
 
 func (c *rebootCommand) Execute([]string) error {
@@ -51,26 +64,53 @@ func (c *rebootCommand) Execute([]string) error {
 	if err != nil {
 		return err
 	}
-	if ctx.HookName() != "install-device" {
-		return fmt.Errorf("cannot use reboot command outside of gadget install-device hook")
+	if c.Halt && c.Poweroff {
+		return fmt.Errorf("cannot specify both --halt and --poweroff")
+	}
+
+	var op string
+	if c.Halt {
+		op = devicestate.RebootHaltOp
+	} else if c.Poweroff {
+		op = devicestate.RebootPoweroffOp
 	}
+
+	recovery := c.System != "" || c.Mode != "" || c.Reason != ""
+	if recovery {
+		if op != "" {
+			return fmt.Errorf("cannot specify --system, --mode or --reason with --halt or --poweroff")
+		}
+		if c.System == "" || c.Mode == "" || c.Reason == "" {
+			return fmt.Errorf("--system, --mode and --reason must be specified together")
+		}
+	}
+
+	if ctx.HookName() == "install-device" {
+		if recovery {
+			return fmt.Errorf("cannot request a recovery system from the install-device hook")
+		}
+		return c.setInstallReboot(ctx, op)
+	}
+	return c.scheduleReboot(ctx, op)
+}
+
+// setInstallReboot sets how the system should reboot at the end of install
+// mode.
+func (c *rebootCommand) setInstallReboot(ctx *hookstate.Context, op string) error {
 	task, ok := ctx.Task()
 	if !ok {
 		return fmt.Errorf("internal error: inside gadget install-device hook but no task")
 	}
-	if !c.Halt && !c.Poweroff {
+	if op == "" {
 		return fmt.Errorf("either --halt or --poweroff must be specified")
 	}
-	if c.Halt && c.Poweroff {
-		return fmt.Errorf("cannot specify both --halt and --poweroff")
-	}
 
 	ctx.Lock()
 	defer ctx.Unlock()
 	st := ctx.State()
 
 	var restartTaskID string
-	err = task.Get("restart-task", &restartTaskID)
+	err := task.Get("restart-task", &restartTaskID)
 	if err != nil {
 		return fmt.Errorf("internal error: cannot get restart-task following install-device hook: %v", err)
 	}
@@ -80,16 +120,43 @@ func (c *rebootCommand) Execute([]string) error {
 		return fmt.Errorf("internal error: tasks are being pruned")
 	}
 
-	var op string
-	if c.Halt {
-		op = devicestate.RebootHaltOp
-	} else if c.Poweroff {
-		op = devicestate.RebootPoweroffOp
-	}
-
 	restartTask.Set("reboot", devicestate.RebootOptions{
 		Op: op,
 	})
 
 	return nil
 }
+
+// scheduleReboot schedules a reboot of the system once the changes in
+// progress, including the one running the hook, have completed.
+func (c *rebootCommand) scheduleReboot(ctx *hookstate.Context, op string) error {
+	if ctx.IsEphemeral() {
+		return fmt.Errorf("cannot use reboot command outside of gadget hooks")
+	}
+
+	ctx.Lock()
+	defer ctx.Unlock()
+	st := ctx.State()
+
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(st, ctx.InstanceName(), &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	if typ, err := snapst.Type(); err != nil || typ != snap.TypeGadget {
+		return fmt.Errorf("cannot use reboot command outside of gadget hooks")
+	}
+
+	_, err := devicestate.ScheduleReboot(st, &devicestate.ScheduledReboot{
+		Op:             op,
+		AfterChanges:   true,
+		RecoverySystem: c.System,
+		Mode:           c.Mode,
+		Reason:         c.Reason,
+	})
+	if err != nil {
+		return err
+	}
+	st.EnsureBefore(0)
+
+	return nil
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/snapcore/snapd/overlord/hookstate"
 	"github.com/snapcore/snapd/overlord/hookstate/ctlcmd"
 	"github.com/snapcore/snapd/overlord/hookstate/hooktest"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/testutil"
@@ -73,7 +75,124 @@ func (s *rebootSuite) TestBadHook(c *C) {
 	c.Assert(err, IsNil)
 
 	_, _, err = ctlcmd.Run(ctx, []string{"reboot", "--halt"}, 0)
-	c.Assert(err, ErrorMatches, `cannot use reboot command outside of gadget install-device hook`)
+	c.Assert(err, ErrorMatches, `cannot use reboot command outside of gadget hooks`)
+}
+
+func (s *rebootSuite) mockGadgetHookContext(c *C, hook string) *hookstate.Context {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	si := &snap.SideInfo{RealName: "pc", Revision: snap.R(1)}
+	snapstate.Set(s.state, "pc", &snapstate.SnapState{
+		Active:   true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  si.Revision,
+		SnapType: "gadget",
+	})
+
+	task := s.state.NewTask("run-hook", "run hook")
+	chg := s.state.NewChange("configure-snap", "...")
+	chg.AddTask(task)
+	setup := &hookstate.HookSetup{Snap: "pc", Revision: snap.R(1), Hook: hook}
+
+	ctx, err := hookstate.NewContext(task, s.state, setup, s.mockHandler, "")
+	c.Assert(err, IsNil)
+	return ctx
+}
+
+func (s *rebootSuite) TestScheduleRebootFromGadgetHook(c *C) {
+	for _, tc := range []struct {
+		args []string
+		op   string
+	}{
+		{[]string{"reboot"}, ""},
+		{[]string{"reboot", "--halt"}, devicestate.RebootHaltOp},
+		{[]string{"reboot", "--poweroff"}, devicestate.RebootPoweroffOp},
+	} {
+		ctx := s.mockGadgetHookContext(c, "configure")
+
+		_, _, err := ctlcmd.Run(ctx, tc.args, 0)
+		c.Assert(err, IsNil)
+
+		s.state.Lock()
+		var scheduled *state.Change
+		for _, chg := range s.state.Changes() {
+			if chg.Kind() == "schedule-reboot" && !chg.IsReady() {
+				scheduled = chg
+			}
+		}
+		c.Assert(scheduled, NotNil)
+		var sr devicestate.ScheduledReboot
+		c.Check(scheduled.Tasks()[0].Get("scheduled-reboot", &sr), IsNil)
+		c.Check(sr, DeepEquals, devicestate.ScheduledReboot{Op: tc.op, AfterChanges: true})
+		// let the next reboot be scheduled
+		scheduled.Abort()
+		s.state.Unlock()
+	}
+}
+
+func (s *rebootSuite) TestScheduleRebootIntoRecoveryFromGadgetHook(c *C) {
+	ctx := s.mockGadgetHookContext(c, "configure")
+
+	_, _, err := ctlcmd.Run(ctx, []string{"reboot", "--system", "20200101", "--mode", "recover", "--reason", "disk-check"}, 0)
+	c.Assert(err, IsNil)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	var scheduled *state.Change
+	for _, chg := range s.state.Changes() {
+		if chg.Kind() == "schedule-reboot" {
+			scheduled = chg
+		}
+	}
+	c.Assert(scheduled, NotNil)
+	c.Check(scheduled.Summary(), Equals, `Reboot the system into recovery system "20200101" in "recover" mode after current changes complete`)
+	var sr devicestate.ScheduledReboot
+	c.Check(scheduled.Tasks()[0].Get("scheduled-reboot", &sr), IsNil)
+	c.Check(sr, DeepEquals, devicestate.ScheduledReboot{
+		AfterChanges:   true,
+		RecoverySystem: "20200101",
+		Mode:           "recover",
+		Reason:         "disk-check",
+	})
+}
+
+func (s *rebootSuite) TestScheduleRebootIntoRecoveryBadArgs(c *C) {
+	ctx := s.mockGadgetHookContext(c, "configure")
+
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"reboot", "--halt", "--system", "20200101", "--mode", "recover", "--reason", "disk-check"}, "cannot specify --system, --mode or --reason with --halt or --poweroff"},
+		{[]string{"reboot", "--system", "20200101", "--mode", "recover"}, "--system, --mode and --reason must be specified together"},
+		{[]string{"reboot", "--mode", "recover", "--reason", "disk-check"}, "--system, --mode and --reason must be specified together"},
+		{[]string{"reboot", "--system", "20200101", "--mode", "recover", "--reason", "Disk Check"}, `invalid recovery reason code "Disk Check"`},
+	} {
+		_, _, err := ctlcmd.Run(ctx, tc.args, 0)
+		c.Check(err, ErrorMatches, tc.err, Commentf("%v", tc.args))
+	}
+
+	_, _, err := ctlcmd.Run(s.mockContext, []string{"reboot", "--system", "20200101", "--mode", "recover", "--reason", "disk-check"}, 0)
+	c.Check(err, ErrorMatches, "cannot request a recovery system from the install-device hook")
+}
+
+func (s *rebootSuite) TestScheduleRebootFromGadgetHookConflict(c *C) {
+	ctx := s.mockGadgetHookContext(c, "configure")
+
+	_, _, err := ctlcmd.Run(ctx, []string{"reboot"}, 0)
+	c.Assert(err, IsNil)
+
+	_, _, err = ctlcmd.Run(ctx, []string{"reboot", "--poweroff"}, 0)
+	c.Assert(err, ErrorMatches, `cannot schedule reboot: a system reboot is already scheduled`)
+}
+
+func (s *rebootSuite) TestScheduleRebootEphemeralContext(c *C) {
+	ctx, err := hookstate.NewContext(nil, s.state, &hookstate.HookSetup{Snap: "pc", Revision: snap.R(1)}, nil, "")
+	c.Assert(err, IsNil)
+
+	_, _, err = ctlcmd.Run(ctx, []string{"reboot"}, 0)
+	c.Assert(err, ErrorMatches, `cannot use reboot command outside of gadget hooks`)
 }
 
 func (s *rebootSuite) TestBadArgs(c *C) {
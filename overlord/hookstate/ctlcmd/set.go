@@ -26,9 +26,11 @@ import (
 
 	"github.com/snapcore/snapd/i18n"
 	"github.com/snapcore/snapd/jsonutil"
+	"github.com/snapcore/snapd/overlord/aspectstate"
 	"github.com/snapcore/snapd/overlord/configstate"
 	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/hookstate"
+	"github.com/snapcore/snapd/overlord/state"
 )
 
 type setCommand struct {
@@ -39,8 +41,9 @@ type setCommand struct {
 		ConfValues     []string `positional-arg-name:"key=value"`
 	} `positional-args:"yes"`
 
-	String bool `short:"s" description:"parse the value as a string"`
-	Typed  bool `short:"t" description:"parse the value strictly as JSON document"`
+	String bool   `short:"s" description:"parse the value as a string"`
+	Typed  bool   `short:"t" description:"parse the value strictly as JSON document"`
+	Aspect string `long:"aspect" value-name:"<account>/<bundle>/<aspect>" description:"set the values of the given aspect"`
 }
 
 var shortSetHelp = i18n.G("Set either configuration options or interface connection settings")
@@ -63,6 +66,11 @@ Plug and slot attributes may be set in the respective prepare and connect hooks
 by naming the respective plug or slot:
 
     $ snapctl set :myplug path=/dev/ttyS0
+
+The gadget snap may set the values of system aspects, such as the netplan
+settings of the system/network bundle, from its hooks:
+
+    $ snapctl set --aspect system/network/netplan ethernets.eth0='{"dhcp4": true}'
 `)
 
 func init() {
@@ -83,6 +91,14 @@ func (s *setCommand) Execute(args []string) error {
 		return fmt.Errorf("cannot use -t and -s together")
 	}
 
+	if s.Aspect != "" {
+		if s.Positional.PlugOrSlotSpec != "" {
+			s.Positional.ConfValues = append([]string{s.Positional.PlugOrSlotSpec}, s.Positional.ConfValues...)
+			s.Positional.PlugOrSlotSpec = ""
+		}
+		return s.setAspectValues(context)
+	}
+
 	// treat PlugOrSlotSpec argument as key=value if it contains '=' or doesn't contain ':' - this is to support
 	// values such as "device-service.url=192.168.0.1:5555" and error out on invalid key=value if only "key" is given.
 	if strings.Contains(s.Positional.PlugOrSlotSpec, "=") || !strings.Contains(s.Positional.PlugOrSlotSpec, ":") {
@@ -108,35 +124,94 @@ func (s *setCommand) setConfigSetting(context *hookstate.Context) error {
 	context.Unlock()
 
 	for _, patchValue := range s.Positional.ConfValues {
-		parts := strings.SplitN(patchValue, "=", 2)
-		if len(parts) == 1 && strings.HasSuffix(patchValue, "!") {
-			key := strings.TrimSuffix(patchValue, "!")
-			tr.Set(s.context().InstanceName(), key, nil)
-			continue
-		}
-		if len(parts) != 2 {
-			return fmt.Errorf(i18n.G("invalid parameter: %q (want key=value)"), patchValue)
+		key, value, err := s.parseConfValue(patchValue)
+		if err != nil {
+			return err
 		}
-		key := parts[0]
 
-		var value interface{}
-		if s.String {
-			value = parts[1]
-		} else {
-			if err := jsonutil.DecodeWithNumber(strings.NewReader(parts[1]), &value); err != nil {
-				if s.Typed {
-					return fmt.Errorf("failed to parse JSON: %w", err)
-				}
-
-				// Not valid JSON-- just save the string as-is.
-				value = parts[1]
+		tr.Set(s.context().InstanceName(), key, value)
+	}
+
+	return nil
+}
+
+// parseConfValue parses a key=value pair, or a key! to unset, returning a nil
+// value for the latter.
+func (s *setCommand) parseConfValue(patchValue string) (key string, value interface{}, err error) {
+	parts := strings.SplitN(patchValue, "=", 2)
+	if len(parts) == 1 && strings.HasSuffix(patchValue, "!") {
+		return strings.TrimSuffix(patchValue, "!"), nil, nil
+	}
+	if len(parts) != 2 {
+		return "", nil, fmt.Errorf(i18n.G("invalid parameter: %q (want key=value)"), patchValue)
+	}
+	key = parts[0]
+
+	if s.String {
+		value = parts[1]
+	} else {
+		if err := jsonutil.DecodeWithNumber(strings.NewReader(parts[1]), &value); err != nil {
+			if s.Typed {
+				return "", nil, fmt.Errorf("failed to parse JSON: %w", err)
 			}
+
+			// Not valid JSON-- just save the string as-is.
+			value = parts[1]
 		}
+	}
 
-		tr.Set(s.context().InstanceName(), key, value)
+	return key, value, nil
+}
+
+func (s *setCommand) setAspectValues(context *hookstate.Context) error {
+	if context.IsEphemeral() {
+		return fmt.Errorf("cannot set aspect values outside of a hook")
 	}
 
-	return nil
+	account, bundleName, aspect, err := parseAspectID(s.Aspect)
+	if err != nil {
+		return err
+	}
+
+	st := context.State()
+	st.Lock()
+	ts, err := s.commitAspectValues(st, context.InstanceName(), account, bundleName, aspect)
+	st.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if ts == nil {
+		return nil
+	}
+	// changes to the system are applied after the hook
+	return queueCommand(context, []*state.TaskSet{ts})
+}
+
+// commitAspectValues sets the requested values in the aspect and commits
+// them, returning the tasks that apply them to the system, if any.
+func (s *setCommand) commitAspectValues(st *state.State, snapName, account, bundleName, aspect string) (*state.TaskSet, error) {
+	if err := checkAspectAccess(st, snapName, account, bundleName); err != nil {
+		return nil, err
+	}
+
+	tx, err := aspectstate.NewTransaction(st, account, bundleName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, patchValue := range s.Positional.ConfValues {
+		key, value, err := s.parseConfValue(patchValue)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := aspectstate.SetAspect(tx, account, bundleName, aspect, key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return aspectstate.CommitTransaction(st, account, bundleName, tx)
 }
 
 func setInterfaceAttribute(context *hookstate.Context, staticAttrs map[string]interface{}, dynamicAttrs map[string]interface{}, key string, value interface{}) error {
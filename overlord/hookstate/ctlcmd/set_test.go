@@ -26,13 +26,19 @@ import (
 
 	. "gopkg.in/check.v1"
 
+	"github.com/snapcore/snapd/aspects"
+	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/hookstate"
 	"github.com/snapcore/snapd/overlord/hookstate/ctlcmd"
 	"github.com/snapcore/snapd/overlord/hookstate/hooktest"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
 	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/release"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
 )
 
 type setSuite struct {
@@ -402,3 +408,147 @@ func (s *setAttrSuite) TestSetCommandFailsOutsideOfValidContext(c *C) {
 	c.Check(string(stdout), Equals, "")
 	c.Check(string(stderr), Equals, "")
 }
+
+type setAspectSuite struct {
+	testutil.BaseTest
+
+	state       *state.State
+	mockContext *hookstate.Context
+}
+
+var _ = Suite(&setAspectSuite{})
+
+func mockAspectSnaps(st *state.State) {
+	for name, typ := range map[string]string{"pc": "gadget", "test-snap": "app"} {
+		snapstate.Set(st, name, &snapstate.SnapState{
+			Active: true,
+			Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+				{RealName: name, Revision: snap.R(1)},
+			}),
+			Current:  snap.R(1),
+			SnapType: typ,
+		})
+	}
+}
+
+func (s *setAspectSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("/") })
+	// netplan isn't queried for its configuration on classic
+	s.AddCleanup(release.MockOnClassic(true))
+
+	s.state = state.New(nil)
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	mockAspectSnaps(s.state)
+
+	chg := s.state.NewChange("install", "install gadget")
+	task := s.state.NewTask("run-hook", "my test task")
+	chg.AddTask(task)
+	setup := &hookstate.HookSetup{Snap: "pc", Revision: snap.R(1), Hook: "install-device"}
+
+	var err error
+	s.mockContext, err = hookstate.NewContext(task, s.state, setup, hooktest.NewMockHandler(), "")
+	c.Assert(err, IsNil)
+}
+
+func (s *setAspectSuite) TestSetAspectQueuesNetplanApply(c *C) {
+	stdout, stderr, err := ctlcmd.Run(s.mockContext, []string{"set", "--aspect", "system/network/netplan", `ethernets.eth0={"dhcp4": true}`, "renderer=networkd"}, 0)
+	c.Assert(err, IsNil)
+	c.Check(string(stdout), Equals, "")
+	c.Check(string(stderr), Equals, "")
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	task, _ := s.mockContext.Task()
+	tasks := task.Change().Tasks()
+	c.Assert(tasks, HasLen, 2)
+	configure := tasks[1]
+	c.Check(configure.Kind(), Equals, "run-hook")
+	c.Check(configure.WaitTasks(), DeepEquals, []*state.Task{task})
+
+	var hooksup hookstate.HookSetup
+	c.Assert(configure.Get("hook-setup", &hooksup), IsNil)
+	c.Check(hooksup.Snap, Equals, "core")
+	c.Check(hooksup.Hook, Equals, "configure")
+
+	var contextData map[string]interface{}
+	c.Assert(configure.Get("hook-context", &contextData), IsNil)
+	c.Check(contextData["patch"], DeepEquals, map[string]interface{}{
+		"system.network.netplan": map[string]interface{}{
+			"network": map[string]interface{}{
+				"version":  float64(2),
+				"renderer": "networkd",
+				"ethernets": map[string]interface{}{
+					"eth0": map[string]interface{}{"dhcp4": true},
+				},
+			},
+		},
+	})
+}
+
+func (s *setAspectSuite) TestSetAspectWifiNoSystemChanges(c *C) {
+	_, _, err := ctlcmd.Run(s.mockContext, []string{"set", "--aspect", "system/network/wifi-setup", "ssid=my-ssid"}, 0)
+	c.Assert(err, IsNil)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	task, _ := s.mockContext.Task()
+	c.Check(task.Change().Tasks(), HasLen, 1)
+
+	var databags map[string]map[string]aspects.JSONDataBag
+	c.Assert(s.state.Get("aspect-databags", &databags), IsNil)
+	value, err := databags["system"]["network"].Get("wifi.ssid")
+	c.Assert(err, IsNil)
+	c.Check(value, Equals, "my-ssid")
+}
+
+func (s *setAspectSuite) TestSetAspectErrors(c *C) {
+	for _, tc := range []struct {
+		snapName string
+		args     []string
+		err      string
+	}{{
+		args: []string{"set", "--aspect", "system/network", "renderer=networkd"},
+		err:  `invalid aspect "system/network": want <account>/<bundle>/<aspect>`,
+	}, {
+		args: []string{"set", "--aspect", "acme/network/netplan", "renderer=networkd"},
+		err:  `cannot access aspect bundle acme/network: only system bundles are supported`,
+	}, {
+		snapName: "test-snap",
+		args:     []string{"set", "--aspect", "system/network/netplan", "renderer=networkd"},
+		err:      `cannot access aspect bundle system/network: only the gadget snap can access system aspects`,
+	}, {
+		args: []string{"set", "--aspect", "system/network/netplan", "version=3"},
+		err:  `cannot set "version" in aspect system/network/netplan: no matching write rule`,
+	}, {
+		args: []string{"set", "--aspect", "system/network/netplan", "renderer=foo"},
+		err:  `cannot accept netplan configuration: .*string "foo" is not one of the allowed choices`,
+	}} {
+		ctx := s.mockContext
+		if tc.snapName != "" {
+			s.state.Lock()
+			task := s.state.NewTask("run-hook", "other hook")
+			s.state.Unlock()
+			setup := &hookstate.HookSetup{Snap: tc.snapName, Revision: snap.R(1), Hook: "configure"}
+			var err error
+			ctx, err = hookstate.NewContext(task, s.state, setup, hooktest.NewMockHandler(), "")
+			c.Assert(err, IsNil)
+		}
+
+		_, _, err := ctlcmd.Run(ctx, tc.args, 0)
+		c.Check(err, ErrorMatches, tc.err, Commentf("%v", tc.args))
+	}
+}
+
+func (s *setAspectSuite) TestSetAspectEphemeralContext(c *C) {
+	ctx, err := hookstate.NewContext(nil, s.state, &hookstate.HookSetup{Snap: "pc", Revision: snap.R(1)}, nil, "")
+	c.Assert(err, IsNil)
+
+	_, _, err = ctlcmd.Run(ctx, []string{"set", "--aspect", "system/network/netplan", "renderer=networkd"}, 0)
+	c.Check(err, ErrorMatches, "cannot set aspect values outside of a hook")
+}
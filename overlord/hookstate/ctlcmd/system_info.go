@@ -0,0 +1,153 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ctlcmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/snapcore/snapd/arch"
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/sandbox"
+)
+
+type systemInfoCommand struct {
+	baseCommand
+}
+
+var shortSystemInfoHelp = i18n.G("Get information about the device and its confinement")
+
+var longSystemInfoHelp = i18n.G(`
+The system-info command returns a curated set of information about the device:
+the identity and grade of its model, whether it has been registered with a
+serial, its architecture and the confinement it supports.
+
+The command is available to the same snaps as the model command: the gadget
+snap, snaps from the same publisher as the model and snaps with the
+snapd-control interface connected.
+
+The output is in YAML format. Example output:
+    $ snapctl system-info
+    brand-id: canonical
+    model: pc
+    grade: signed
+    serial: true
+    architecture: amd64
+    confinement: strict
+    confinement-options:
+    - devmode
+    - strict
+`)
+
+func init() {
+	addCommand("system-info", shortSystemInfoHelp, longSystemInfoHelp, func() command { return &systemInfoCommand{} })
+}
+
+type systemInfoResult struct {
+	BrandID            string   `yaml:"brand-id"`
+	Model              string   `yaml:"model"`
+	Grade              string   `yaml:"grade"`
+	Serial             bool     `yaml:"serial"`
+	Architecture       string   `yaml:"architecture"`
+	Confinement        string   `yaml:"confinement"`
+	ConfinementOptions []string `yaml:"confinement-options"`
+}
+
+// confinementOptions returns the confinement types snaps can use on this
+// device, in the same way as the confinement-options of the system-info API.
+func confinementOptions() []string {
+	options := []string{"devmode"}
+	if !sandbox.ForceDevMode() {
+		options = append(options, "strict")
+	}
+	if dirs.SupportsClassicConfinement() {
+		options = append(options, "classic")
+	}
+	sort.Strings(options)
+	return options
+}
+
+func (c *systemInfoCommand) Execute(args []string) error {
+	context, err := c.ensureContext()
+	if err != nil {
+		return err
+	}
+
+	st := context.State()
+	st.Lock()
+	defer st.Unlock()
+
+	task, _ := context.Task()
+	deviceCtx, err := snapstate.DeviceCtx(st, task, nil)
+	if err != nil {
+		return err
+	}
+
+	// as for the model command, only failing to get the snap.Info is
+	// fatal, missing publisher information just denies access via the
+	// publisher
+	snapInfo, err := getSnapInfoWithPublisher(st, context.InstanceName())
+	if snapInfo == nil {
+		return err
+	}
+	ok, err := canAccessModel(st, deviceCtx, snapInfo)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		c.errorf("cannot get system information for snap %q: "+
+			"must be either a gadget snap, from the same publisher as the model "+
+			"or have the snapd-control interface\n", snapInfo.SnapName())
+		return fmt.Errorf("insufficient permissions to get system information for snap %q", snapInfo.SnapName())
+	}
+
+	model := deviceCtx.Model()
+	serial, err := findSerialAssertion(st, model)
+	if err != nil && !errors.Is(err, &asserts.NotFoundError{}) {
+		return err
+	}
+
+	res := systemInfoResult{
+		BrandID:            model.BrandID(),
+		Model:              model.Model(),
+		Grade:              string(model.Grade()),
+		Serial:             serial != nil,
+		Architecture:       arch.DpkgArchitecture(),
+		Confinement:        "strict",
+		ConfinementOptions: confinementOptions(),
+	}
+	if sandbox.ForceDevMode() {
+		res.Confinement = "partial"
+	}
+
+	b, err := yaml.Marshal(res)
+	if err != nil {
+		return err
+	}
+	c.printf("%s", string(b))
+
+	return nil
+}
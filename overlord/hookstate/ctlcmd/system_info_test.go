@@ -0,0 +1,139 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ctlcmd_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/arch"
+	"github.com/snapcore/snapd/overlord/assertstate"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/devicestate/devicestatetest"
+	"github.com/snapcore/snapd/overlord/hookstate"
+	"github.com/snapcore/snapd/overlord/hookstate/ctlcmd"
+	"github.com/snapcore/snapd/release"
+	"github.com/snapcore/snapd/sandbox"
+	"github.com/snapcore/snapd/snap"
+)
+
+// the system-info command shares the permission checks and the fixture of
+// the model command
+
+func (s *modelSuite) mockSystemInfoContext(c *C, snapName string, withSerial bool) *hookstate.Context {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	headers := map[string]interface{}{
+		"architecture": "amd64",
+		"kernel":       "pc-kernel",
+		"gadget":       "pc",
+		"base":         "core18",
+	}
+	current := s.brands.Model("canonical", "pc-model", headers)
+	c.Assert(assertstate.Add(s.state, current), IsNil)
+	if withSerial {
+		serial := s.signSerial("canonical", "pc-model", "1", time.Now(), headers)
+		c.Assert(assertstate.Add(s.state, serial), IsNil)
+	}
+	devicestatetest.SetDevice(s.state, &auth.DeviceState{
+		Brand: "canonical",
+		Model: "pc-model",
+	})
+
+	task := s.state.NewTask("test-task", "my test task")
+	setup := &hookstate.HookSetup{Snap: snapName, Revision: snap.R(1), Hook: "test-hook"}
+	mockContext, err := hookstate.NewContext(task, s.state, setup, s.mockHandler, "")
+	c.Assert(err, IsNil)
+	return mockContext
+}
+
+func (s *modelSuite) TestSystemInfoCommand(c *C) {
+	defer arch.SetArchitecture(arch.ArchitectureType(arch.DpkgArchitecture()))
+	arch.SetArchitecture("amd64")
+	defer release.MockOnClassic(false)()
+	defer sandbox.MockForceDevMode(false)()
+
+	s.setupBrands()
+	s.addSnapDeclaration(c, "snap1-id", "canonical", "snap1")
+	mockContext := s.mockSystemInfoContext(c, "snap1", true)
+	s.state.Lock()
+	mockInstalledSnap(c, s.state, snapBaseYaml, "")
+	mockInstalledSnap(c, s.state, snapYaml, "")
+	s.state.Unlock()
+
+	stdout, stderr, err := ctlcmd.Run(mockContext, []string{"system-info"}, 0)
+	c.Assert(err, IsNil)
+	c.Check(string(stdout), Equals, `brand-id: canonical
+model: pc-model
+grade: unset
+serial: true
+architecture: amd64
+confinement: strict
+confinement-options:
+- devmode
+- strict
+`)
+	c.Check(string(stderr), Equals, "")
+}
+
+func (s *modelSuite) TestSystemInfoCommandGadgetNoSerialPartialConfinement(c *C) {
+	defer arch.SetArchitecture(arch.ArchitectureType(arch.DpkgArchitecture()))
+	arch.SetArchitecture("arm64")
+	defer release.MockOnClassic(false)()
+	defer sandbox.MockForceDevMode(true)()
+
+	// the gadget is allowed regardless of its publisher
+	s.setupBrands()
+	s.addSnapDeclaration(c, "gadget1-id", "other-brand", "gadget1")
+	mockContext := s.mockSystemInfoContext(c, "gadget1", false)
+	s.state.Lock()
+	mockInstalledSnap(c, s.state, snapGadgetYaml, "")
+	s.state.Unlock()
+
+	stdout, stderr, err := ctlcmd.Run(mockContext, []string{"system-info"}, 0)
+	c.Assert(err, IsNil)
+	c.Check(string(stdout), Equals, `brand-id: canonical
+model: pc-model
+grade: unset
+serial: false
+architecture: arm64
+confinement: partial
+confinement-options:
+- devmode
+`)
+	c.Check(string(stderr), Equals, "")
+}
+
+func (s *modelSuite) TestSystemInfoCommandInsufficientPermissions(c *C) {
+	s.setupBrands()
+	s.addSnapDeclaration(c, "snap1-id", "other-brand", "snap1")
+	mockContext := s.mockSystemInfoContext(c, "snap1", true)
+	s.state.Lock()
+	mockInstalledSnap(c, s.state, snapBaseYaml, "")
+	mockInstalledSnap(c, s.state, snapYaml, "")
+	s.state.Unlock()
+
+	stdout, stderr, err := ctlcmd.Run(mockContext, []string{"system-info"}, 0)
+	c.Check(err, ErrorMatches, `insufficient permissions to get system information for snap "snap1"`)
+	c.Check(string(stdout), Equals, "")
+	c.Check(string(stderr), Equals, "cannot get system information for snap \"snap1\": must be either a gadget snap, from the same publisher as the model or have the snapd-control interface\n")
+}
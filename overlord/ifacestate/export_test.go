@@ -124,6 +124,30 @@ func MockUDevInitRetryTimeout(t time.Duration) (restore func()) {
 	}
 }
 
+func MockCgroupPidsOfSnap(f func(string) (map[string][]int, error)) (restore func()) {
+	old := cgroupPidsOfSnap
+	cgroupPidsOfSnap = f
+	return func() {
+		cgroupPidsOfSnap = old
+	}
+}
+
+func MockTimeNow(f func() time.Time) (restore func()) {
+	old := timeNow
+	timeNow = f
+	return func() {
+		timeNow = old
+	}
+}
+
+func MockConnectionsUsageInterval(d time.Duration) (restore func()) {
+	old := connectionsUsageInterval
+	connectionsUsageInterval = d
+	return func() {
+		connectionsUsageInterval = old
+	}
+}
+
 // UpperCaseConnState returns a canned connection state map.
 // This allows us to keep connState private and still write some tests for it.
 func UpperCaseConnState() map[string]*schema.ConnState {
@@ -193,3 +217,11 @@ func (m *InterfaceManager) TransitionConnectionsCoreMigration(st *state.State, o
 func (m *InterfaceManager) SetupSecurityByBackend(task *state.Task, snaps []*snap.Info, opts []interfaces.ConfinementOptions, tm timings.Measurer) error {
 	return m.setupSecurityByBackend(task, snaps, opts, tm)
 }
+
+func MockDiscardSnapNamespace(f func(snapName string) error) (restore func()) {
+	old := discardSnapNamespace
+	discardSnapNamespace = f
+	return func() {
+		discardSnapNamespace = old
+	}
+}
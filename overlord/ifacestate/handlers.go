@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
@@ -34,6 +35,7 @@ import (
 	"github.com/snapcore/snapd/i18n"
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/hotplug"
+	"github.com/snapcore/snapd/interfaces/mount"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/overlord/hookstate"
 	"github.com/snapcore/snapd/overlord/ifacestate/schema"
@@ -42,10 +44,14 @@ import (
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/quota"
+	"github.com/snapcore/snapd/strutil"
 	"github.com/snapcore/snapd/timings"
 )
 
-var snapstateFinishRestart = snapstate.FinishRestart
+var (
+	snapstateFinishRestart = snapstate.FinishRestart
+	discardSnapNamespace   = mount.DiscardSnapNamespace
+)
 
 // journalQuotaLayout returns the necessary journal quota mount layouts
 // to mimick what systemd does for services with log namespaces.
@@ -64,6 +70,26 @@ func journalQuotaLayout(quotaGroup *quota.Group) []snap.Layout {
 	return layouts
 }
 
+// resolvConfPath returns the path of the system's resolv.conf(5), following
+// symbolic links as those cannot be mounted over.
+var resolvConfPath = func() string {
+	resolved, err := filepath.EvalSymlinks(filepath.Join(dirs.GlobalRootDir, "/etc/resolv.conf"))
+	if err != nil {
+		return "/etc/resolv.conf"
+	}
+	return dirs.StripRootDir(resolved)
+}
+
+// dnsOverrideLayout returns the mount layout replacing the system's
+// resolv.conf(5) with the one conveying the DNS settings of the snap.
+func dnsOverrideLayout(instanceName string) []snap.Layout {
+	return []snap.Layout{{
+		BindFile: snapstate.DNSResolvConfFile(instanceName),
+		Path:     resolvConfPath(),
+		Mode:     0644,
+	}}
+}
+
 // getExtraLayouts helper function to dynamically calculate the extra mount layouts for
 // a snap instance. These are the layouts which can change during the lifetime of a snap
 // like for instance mimicking systemd journal namespace mount layouts.
@@ -78,6 +104,14 @@ func getExtraLayouts(st *state.State, snapInfo *snap.Info) ([]snap.Layout, error
 		extraLayouts = append(extraLayouts, journalQuotaLayout(snapOpts.QuotaGroup)...)
 	}
 
+	dnsSettings, err := snapstate.DNSOverride(st, snapInfo.InstanceName())
+	if err != nil {
+		return nil, err
+	}
+	if dnsSettings != nil {
+		extraLayouts = append(extraLayouts, dnsOverrideLayout(snapInfo.InstanceName())...)
+	}
+
 	return extraLayouts, nil
 }
 
@@ -87,16 +121,36 @@ func buildConfinementOptions(st *state.State, snapInfo *snap.Info, flags snapsta
 		return interfaces.ConfinementOptions{}, fmt.Errorf("cannot get extra mount layouts of snap %q: %s", snapInfo.InstanceName(), err)
 	}
 
-	return interfaces.ConfinementOptions{
-		DevMode:      flags.DevMode,
-		JailMode:     flags.JailMode,
-		Classic:      flags.Classic,
-		ExtraLayouts: extraLayouts,
-	}, nil
+	profilingSince, err := snapstate.SyscallProfilingSince(st, snapInfo.InstanceName())
+	if err != nil {
+		return interfaces.ConfinementOptions{}, err
+	}
+
+	hardening, err := snapstate.Hardening(st, snapInfo.InstanceName())
+	if err != nil {
+		return interfaces.ConfinementOptions{}, err
+	}
+
+	opts := interfaces.ConfinementOptions{
+		DevMode:          flags.DevMode,
+		JailMode:         flags.JailMode,
+		Classic:          flags.Classic,
+		ExtraLayouts:     extraLayouts,
+		SyscallProfiling: !profilingSince.IsZero(),
+	}
+	if hardening != nil {
+		opts.Hardening = interfaces.HardeningOptions{
+			NoPtrace:    hardening.NoPtrace,
+			NoCoreDumps: hardening.NoCoreDumps,
+			NoMemfdExec: hardening.NoMemfdExec,
+		}
+	}
+	return opts, nil
 }
 
 func (m *InterfaceManager) setupAffectedSnaps(task *state.Task, affectingSnap string, affectedSnaps []string, tm timings.Measurer) error {
 	st := task.State()
+	pending := pendingProfileSetups(task)
 
 	// Setup security of the affected snaps.
 	for _, affectedInstanceName := range affectedSnaps {
@@ -120,6 +174,8 @@ func (m *InterfaceManager) setupAffectedSnaps(task *state.Task, affectingSnap st
 		if err != nil {
 			return err
 		}
+		opts.DeferNamespaceUpdate = pending[affectedInstanceName]
+		opts.DeferDeviceRulesReload = pending[affectedInstanceName]
 		if err := m.setupSnapSecurity(task, affectedSnapInfo, opts, tm); err != nil {
 			return err
 		}
@@ -166,12 +222,60 @@ func (m *InterfaceManager) doSetupProfiles(task *state.Task, tomb *tomb.Tomb) er
 	if err != nil {
 		return err
 	}
+	if err := handleLayoutChanges(task, snapInfo); err != nil {
+		return err
+	}
 	if err := m.setupProfilesForSnap(task, tomb, snapInfo, opts, perfTimings); err != nil {
 		return err
 	}
 	return setPendingProfilesSideInfo(task.State(), snapsup.InstanceName(), snapsup.SideInfo)
 }
 
+// handleLayoutChanges reports how the layout of a snap being refreshed
+// changes from the current revision. If any layout changes its kind, the
+// preserved mount namespace of the snap is discarded upfront as it cannot be
+// reliably updated in place.
+func handleLayoutChanges(task *state.Task, snapInfo *snap.Info) error {
+	st := task.State()
+	snapName := snapInfo.InstanceName()
+
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(st, snapName, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	if !snapst.IsInstalled() || snapst.Current == snapInfo.Revision {
+		return nil
+	}
+	oldInfo, err := snapst.CurrentInfo()
+	if err != nil {
+		logger.Noticef("cannot compare layouts of snap %q: %v", snapName, err)
+		return nil
+	}
+
+	var incompatible []string
+	for _, change := range snap.DiffLayouts(oldInfo, snapInfo) {
+		task.Logf("Layout %s", change)
+		if change.Incompatible() {
+			incompatible = append(incompatible, change.Path)
+		}
+	}
+	if len(incompatible) == 0 {
+		return nil
+	}
+
+	for _, app := range snapInfo.Apps {
+		if app.Daemon != "" && app.RefreshMode == "endure" {
+			task.Logf("Cannot discard the mount namespace of snap %q with enduring daemons, updating it in place despite incompatible layout changes of %s",
+				snapName, strutil.Quoted(incompatible))
+			return nil
+		}
+	}
+	task.Logf("Discarding the mount namespace of snap %q due to incompatible layout changes of %s", snapName, strutil.Quoted(incompatible))
+	st.Unlock()
+	defer st.Lock()
+	return discardSnapNamespace(snapName)
+}
+
 // setupPendingProfilesSideInfo helps updating information about any
 // revision for which security profiles are set up while the snap is
 // not yet active.
@@ -267,7 +371,10 @@ func (m *InterfaceManager) setupProfilesForSnap(task *state.Task, _ *tomb.Tomb,
 	// For the snap being setup we know exactly what was requested.
 	affectedSnaps = append(affectedSnaps, snapInfo)
 	confinementOpts = append(confinementOpts, opts)
-	// For remaining snaps we need to interrogate the state.
+	// For remaining snaps we need to interrogate the state. Updating the
+	// mount namespace of those is left to later tasks of the change that
+	// set them up anyway.
+	pending := pendingProfileSetups(task)
 	for _, name := range affectedNames[1:] {
 		var snapst snapstate.SnapState
 		if err := snapstate.Get(st, name, &snapst); err != nil {
@@ -285,6 +392,8 @@ func (m *InterfaceManager) setupProfilesForSnap(task *state.Task, _ *tomb.Tomb,
 		if err != nil {
 			return err
 		}
+		opts.DeferNamespaceUpdate = pending[name]
+		opts.DeferDeviceRulesReload = pending[name]
 
 		affectedSnaps = append(affectedSnaps, snapInfo)
 		confinementOpts = append(confinementOpts, opts)
@@ -502,6 +611,10 @@ func (m *InterfaceManager) doConnect(task *state.Task, _ *tomb.Tomb) (err error)
 	if err := task.Get("by-gadget", &byGadget); err != nil && !errors.Is(err, state.ErrNoState) {
 		return err
 	}
+	var autoReason string
+	if err := task.Get("auto-reason", &autoReason); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
 	var delayedSetupProfiles bool
 	if err := task.Get("delayed-setup-profiles", &delayedSetupProfiles); err != nil && !errors.Is(err, state.ErrNoState) {
 		return err
@@ -591,6 +704,8 @@ func (m *InterfaceManager) doConnect(task *state.Task, _ *tomb.Tomb) (err error)
 	}()
 
 	if !delayedSetupProfiles {
+		pending := pendingProfileSetups(task)
+
 		slotSnapInfo, err := slotSnapst.CurrentInfo()
 		if err != nil {
 			return err
@@ -599,19 +714,28 @@ func (m *InterfaceManager) doConnect(task *state.Task, _ *tomb.Tomb) (err error)
 		if err != nil {
 			return err
 		}
-		if err := m.setupSnapSecurity(task, slot.Snap, slotOpts, perfTimings); err != nil {
-			return err
-		}
+		slotOpts.DeferNamespaceUpdate = pending[slot.Snap.InstanceName()]
+		slotOpts.DeferDeviceRulesReload = pending[slot.Snap.InstanceName()]
+		snaps := []*snap.Info{slot.Snap}
+		opts := []interfaces.ConfinementOptions{slotOpts}
 
-		plugSnapInfo, err := plugSnapst.CurrentInfo()
-		if err != nil {
-			return err
-		}
-		plugOpts, err := buildConfinementOptions(st, plugSnapInfo, plugSnapst.Flags)
-		if err != nil {
-			return err
+		// a snap connected to itself needs to be set up only once
+		if plug.Snap.InstanceName() != slot.Snap.InstanceName() {
+			plugSnapInfo, err := plugSnapst.CurrentInfo()
+			if err != nil {
+				return err
+			}
+			plugOpts, err := buildConfinementOptions(st, plugSnapInfo, plugSnapst.Flags)
+			if err != nil {
+				return err
+			}
+			plugOpts.DeferNamespaceUpdate = pending[plug.Snap.InstanceName()]
+			plugOpts.DeferDeviceRulesReload = pending[plug.Snap.InstanceName()]
+			snaps = append(snaps, plug.Snap)
+			opts = append(opts, plugOpts)
 		}
-		if err := m.setupSnapSecurity(task, plug.Snap, plugOpts, perfTimings); err != nil {
+
+		if err := m.setupSecurityByBackend(task, snaps, opts, perfTimings); err != nil {
 			return err
 		}
 	} else {
@@ -635,6 +759,7 @@ func (m *InterfaceManager) doConnect(task *state.Task, _ *tomb.Tomb) (err error)
 		DynamicSlotAttrs: conn.Slot.DynamicAttrs(),
 		Auto:             autoConnect,
 		ByGadget:         byGadget,
+		AutoReason:       autoReason,
 		HotplugKey:       slot.HotplugKey,
 	}
 	setConns(st, conns)
@@ -706,6 +831,7 @@ func (m *InterfaceManager) doDisconnect(task *state.Task, _ *tomb.Tomb) error {
 		return fmt.Errorf("snapd changed, please retry the operation: %v", err)
 	}
 
+	pending := pendingProfileSetups(task)
 	for _, snapst := range snapStates {
 		snapInfo, err := snapst.CurrentInfo()
 		if err != nil {
@@ -715,6 +841,8 @@ func (m *InterfaceManager) doDisconnect(task *state.Task, _ *tomb.Tomb) error {
 		if err != nil {
 			return err
 		}
+		opts.DeferNamespaceUpdate = pending[snapInfo.InstanceName()]
+		opts.DeferDeviceRulesReload = pending[snapInfo.InstanceName()]
 		if err := m.setupSnapSecurity(task, snapInfo, opts, perfTimings); err != nil {
 			return err
 		}
@@ -1371,6 +1499,17 @@ func (m *InterfaceManager) doAutoConnect(task *state.Task, _ *tomb.Tomb) error {
 		}
 	}
 
+	// Remember why a slot was chosen among several candidates
+	for key, reason := range autochecker.reasons {
+		if _, ok := newconns[key]; !ok || connOpts[key] != nil {
+			continue
+		}
+		if connOpts == nil {
+			connOpts = make(map[string]*connectOpts)
+		}
+		connOpts[key] = &connectOpts{AutoConnect: true, AutoReason: reason}
+	}
+
 	autots, hasInterfaceHooks, err := batchConnectTasks(st, snapsup, newconns, connOpts)
 	if err != nil {
 		return err
@@ -20,11 +20,15 @@
 package ifacestate_test
 
 import (
+	"os"
 	"path"
+	"path/filepath"
+	"time"
 
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/ifacestate"
 	"github.com/snapcore/snapd/overlord/servicestate/servicestatetest"
@@ -168,3 +172,65 @@ func (s *handlersSuite) TestBuildConfinementOptionsWithLogNamespace(c *C) {
 	c.Check(opts.DevMode, Equals, flags.DevMode)
 	c.Check(opts.JailMode, Equals, flags.JailMode)
 }
+
+func (s *handlersSuite) TestBuildConfinementOptionsWithDNSOverride(c *C) {
+	s.st.Lock()
+	defer s.st.Unlock()
+
+	// the system's resolv.conf is typically a symlink to the one managed by
+	// systemd-resolved
+	stubResolvConf := filepath.Join(dirs.GlobalRootDir, "/run/systemd/resolve/stub-resolv.conf")
+	c.Assert(os.MkdirAll(filepath.Dir(stubResolvConf), 0755), IsNil)
+	c.Assert(os.WriteFile(stubResolvConf, nil, 0644), IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(dirs.GlobalRootDir, "/etc"), 0755), IsNil)
+	c.Assert(os.Symlink("../run/systemd/resolve/stub-resolv.conf", filepath.Join(dirs.GlobalRootDir, "/etc/resolv.conf")), IsNil)
+
+	snapInfo := mockInstalledSnap(c, s.st, snapAyaml)
+	err := snapstate.SetDNSOverride(s.st, snapInfo.InstanceName(), &snapstate.DNSSettings{Nameservers: []string{"10.0.0.53"}})
+	c.Assert(err, IsNil)
+
+	opts, err := ifacestate.BuildConfinementOptions(s.st, snapInfo, snapstate.Flags{})
+	c.Assert(err, IsNil)
+	c.Check(opts.ExtraLayouts, DeepEquals, []snap.Layout{{
+		BindFile: snapstate.DNSResolvConfFile(snapInfo.InstanceName()),
+		Path:     "/run/systemd/resolve/stub-resolv.conf",
+		Mode:     0644,
+	}})
+}
+
+func (s *handlersSuite) TestBuildConfinementOptionsWithSyscallProfiling(c *C) {
+	s.st.Lock()
+	defer s.st.Unlock()
+
+	snapInfo := mockInstalledSnap(c, s.st, snapAyaml)
+	opts, err := ifacestate.BuildConfinementOptions(s.st, snapInfo, snapstate.Flags{})
+	c.Assert(err, IsNil)
+	c.Check(opts.SyscallProfiling, Equals, false)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.st, snapInfo.InstanceName(), &snapst), IsNil)
+	since := time.Now()
+	snapst.SyscallProfilingSince = &since
+	snapstate.Set(s.st, snapInfo.InstanceName(), &snapst)
+
+	opts, err = ifacestate.BuildConfinementOptions(s.st, snapInfo, snapstate.Flags{})
+	c.Assert(err, IsNil)
+	c.Check(opts.SyscallProfiling, Equals, true)
+}
+
+func (s *handlersSuite) TestBuildConfinementOptionsWithHardening(c *C) {
+	s.st.Lock()
+	defer s.st.Unlock()
+
+	snapInfo := mockInstalledSnap(c, s.st, snapAyaml)
+	opts, err := ifacestate.BuildConfinementOptions(s.st, snapInfo, snapstate.Flags{})
+	c.Assert(err, IsNil)
+	c.Check(opts.Hardening, Equals, interfaces.HardeningOptions{})
+
+	err = snapstate.SetHardening(s.st, snapInfo.InstanceName(), &snapstate.HardeningSettings{NoPtrace: true, NoMemfdExec: true})
+	c.Assert(err, IsNil)
+
+	opts, err = ifacestate.BuildConfinementOptions(s.st, snapInfo, snapstate.Flags{})
+	c.Assert(err, IsNil)
+	c.Check(opts.Hardening, Equals, interfaces.HardeningOptions{NoPtrace: true, NoMemfdExec: true})
+}
@@ -30,6 +30,7 @@ import (
 
 	"github.com/snapcore/snapd/asserts"
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/gadget"
 	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/builtin"
 	"github.com/snapcore/snapd/interfaces/policy"
@@ -483,6 +484,51 @@ func (m *InterfaceManager) setupSecurityByBackend(task *state.Task, snaps []*sna
 	return nil
 }
 
+// pendingProfileSetups returns the names of the snaps whose security
+// profiles will be set up again by tasks of the same change as the given
+// task that are yet to run. Updating the mount namespace of those snaps
+// can be deferred, so that snap-update-ns runs once per namespace with
+// the combined profile instead of once for every task affecting it.
+func pendingProfileSetups(task *state.Task) map[string]bool {
+	pending := make(map[string]bool)
+	chg := task.Change()
+	if chg == nil {
+		return pending
+	}
+	for _, t := range chg.Tasks() {
+		if t == task || t.Status() != state.DoStatus {
+			continue
+		}
+		switch t.Kind() {
+		case "setup-profiles":
+			snapsup, err := snapstate.TaskSnapSetup(t)
+			if err != nil {
+				continue
+			}
+			pending[snapsup.InstanceName()] = true
+		case "connect", "disconnect":
+			var delayedSetupProfiles bool
+			if err := t.Get("delayed-setup-profiles", &delayedSetupProfiles); err != nil && !errors.Is(err, state.ErrNoState) {
+				continue
+			}
+			if delayedSetupProfiles {
+				continue
+			}
+			var plugRef interfaces.PlugRef
+			var slotRef interfaces.SlotRef
+			if err := t.Get("plug", &plugRef); err != nil {
+				continue
+			}
+			if err := t.Get("slot", &slotRef); err != nil {
+				continue
+			}
+			pending[plugRef.Snap] = true
+			pending[slotRef.Snap] = true
+		}
+	}
+	return pending
+}
+
 func (m *InterfaceManager) setupSnapSecurity(task *state.Task, snapInfo *snap.Info, opts interfaces.ConfinementOptions, tm timings.Measurer) error {
 	return m.setupSecurityByBackend(task, []*snap.Info{snapInfo}, []interfaces.ConfinementOptions{opts}, tm)
 }
@@ -556,10 +602,9 @@ func (gc *gadgetConnect) addGadgetConnections(newconns map[string]*interfaces.Co
 	if err != nil && !errors.Is(err, state.ErrNoState) {
 		return err
 	}
-	// we apply gadget connections only during seeding or a remodeling
-	if seeded && !gc.deviceCtx.ForRemodeling() {
-		return nil
-	}
+	// all gadget connections are applied during seeding or a remodeling,
+	// past that only the ones asking for it
+	onlyBeyondSeeding := seeded && !gc.deviceCtx.ForRemodeling()
 
 	task := gc.task
 	snapName := gc.instanceName
@@ -573,8 +618,11 @@ func (gc *gadgetConnect) addGadgetConnections(newconns map[string]*interfaces.Co
 	if err != nil {
 		return err
 	}
+	// when the gadget itself is refreshed its connections are
+	// re-evaluated regardless of the snaps involved
+	gadgetRefresh := onlyBeyondSeeding && snapInfo.Type() == snap.TypeGadget
 	snapID := snapInfo.SnapID
-	if snapID == "" {
+	if snapID == "" && !gadgetRefresh {
 		// not a snap-id identifiable snap, skip
 		return nil
 	}
@@ -590,6 +638,10 @@ func (gc *gadgetConnect) addGadgetConnections(newconns map[string]*interfaces.Co
 
 	// consider the gadget connect instructions
 	for _, gconn := range gconns {
+		if onlyBeyondSeeding && !gconn.Apply.BeyondSeeding() {
+			continue
+		}
+
 		var plugSnapName, slotSnapName string
 		if gconn.Plug.SnapID == snapID {
 			plugSnapName = snapName
@@ -598,7 +650,7 @@ func (gc *gadgetConnect) addGadgetConnections(newconns map[string]*interfaces.Co
 			slotSnapName = snapName
 		}
 
-		if plugSnapName == "" && slotSnapName == "" {
+		if plugSnapName == "" && slotSnapName == "" && !gadgetRefresh {
 			// no match, nothing to do
 			continue
 		}
@@ -610,12 +662,6 @@ func (gc *gadgetConnect) addGadgetConnections(newconns map[string]*interfaces.Co
 				return err
 			}
 		}
-		plug := gc.repo.Plug(plugSnapName, gconn.Plug.Plug)
-		if plug == nil {
-			task.Logf("gadget connections: ignoring missing plug %s:%s", gconn.Plug.SnapID, gconn.Plug.Plug)
-			continue
-		}
-
 		if slotSnapName == "" {
 			var err error
 			slotSnapName, err = resolveSnapIDToName(gc.st, gconn.Slot.SnapID)
@@ -623,13 +669,27 @@ func (gc *gadgetConnect) addGadgetConnections(newconns map[string]*interfaces.Co
 				return err
 			}
 		}
+
+		plug := gc.repo.Plug(plugSnapName, gconn.Plug.Plug)
 		slot := gc.repo.Slot(slotSnapName, gconn.Slot.Slot)
+		if gadgetRefresh && (plug == nil || slot == nil) {
+			// the snaps involved might just not be installed
+			// (yet), the connection will be made when they are
+			continue
+		}
+		if plug == nil {
+			task.Logf("gadget connections: ignoring missing plug %s:%s", gconn.Plug.SnapID, gconn.Plug.Plug)
+			continue
+		}
 		if slot == nil {
 			task.Logf("gadget connections: ignoring missing slot %s:%s", gconn.Slot.SnapID, gconn.Slot.Slot)
 			continue
 		}
 
-		if err := addNewConnection(gc.st, task, newconns, conns, plug, slot, conflictError); err != nil {
+		// enforced connections are made again even if they
+		// were removed by the user
+		reconnectUndesired := onlyBeyondSeeding && gconn.Apply == gadget.ConnectionApplyEnforce
+		if err := addNewConnection(gc.st, task, newconns, conns, plug, slot, reconnectUndesired, conflictError); err != nil {
 			return err
 		}
 	}
@@ -637,10 +697,10 @@ func (gc *gadgetConnect) addGadgetConnections(newconns map[string]*interfaces.Co
 	return nil
 }
 
-func addNewConnection(st *state.State, task *state.Task, newconns map[string]*interfaces.ConnRef, conns map[string]*schema.ConnState, plug *snap.PlugInfo, slot *snap.SlotInfo, conflictError func(*state.Retry, error) error) error {
+func addNewConnection(st *state.State, task *state.Task, newconns map[string]*interfaces.ConnRef, conns map[string]*schema.ConnState, plug *snap.PlugInfo, slot *snap.SlotInfo, reconnectUndesired bool, conflictError func(*state.Retry, error) error) error {
 	connRef := interfaces.NewConnRef(plug, slot)
 	key := connRef.ID()
-	if _, ok := conns[key]; ok {
+	if cstate, ok := conns[key]; ok && !(reconnectUndesired && cstate.Undesired) {
 		// Suggested connection already exist (or has
 		// Undesired flag set) so don't clobber it.
 		// NOTE: we don't log anything here as this is
@@ -683,6 +743,10 @@ type autoConnectChecker struct {
 	deviceCtx snapstate.DeviceContext
 	cache     map[string]*asserts.SnapDeclaration
 	baseDecl  *asserts.BaseDeclaration
+
+	// reasons records why a candidate was chosen when there were
+	// several, by connection ID
+	reasons map[string]string
 }
 
 func newAutoConnectChecker(s *state.State, task *state.Task, repo *interfaces.Repository, deviceCtx snapstate.DeviceContext) (*autoConnectChecker, error) {
@@ -697,6 +761,7 @@ func newAutoConnectChecker(s *state.State, task *state.Task, repo *interfaces.Re
 		deviceCtx: deviceCtx,
 		cache:     make(map[string]*asserts.SnapDeclaration),
 		baseDecl:  baseDecl,
+		reasons:   make(map[string]string),
 	}, nil
 }
 
@@ -713,7 +778,33 @@ func (c *autoConnectChecker) snapDeclaration(snapID string) (*asserts.SnapDeclar
 	return snapDecl, nil
 }
 
+func (c *autoConnectChecker) isQuarantined(sn *snap.Info) (bool, error) {
+	if sn.SnapID != "" {
+		// only unasserted snaps can be quarantined
+		return false, nil
+	}
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(c.st, sn.InstanceName(), &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return false, nil
+		}
+		return false, err
+	}
+	return snapst.Quarantined, nil
+}
+
 func (c *autoConnectChecker) check(plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) (bool, interfaces.SideArity, error) {
+	// quarantined snaps get no auto-connections until released
+	for _, sn := range []*snap.Info{plug.Snap(), slot.Snap()} {
+		quarantined, err := c.isQuarantined(sn)
+		if err != nil {
+			return false, nil, err
+		}
+		if quarantined {
+			return false, nil, nil
+		}
+	}
+
 	modelAs := c.deviceCtx.Model()
 
 	var storeAs *asserts.Store
@@ -800,6 +891,111 @@ func filterUbuntuCoreSlots(candidates []*snap.SlotInfo, arities []interfaces.Sid
 	return candidates, arities
 }
 
+// Auto-connection candidates are scored when a plug can be connected to
+// only one slot but more than one slot could satisfy it. Each criterion
+// outweighs all the following ones together, so the candidates are
+// effectively compared criterion by criterion:
+//
+//   - default-provider: the slot snap is the default-provider of the plug
+//   - gadget: the slot snap is the gadget of the device
+//   - same-publisher: the slot snap has the same publisher as the plug snap
+//   - brand-store: the slot snap is published by the brand of the device,
+//     which owns its store
+//
+// The candidate with the highest score is connected. If no candidate
+// matches any criterion, or if the highest score is shared, the choice is
+// ambiguous and no connection is made.
+const (
+	scoreBrandStore = 1 << iota
+	scoreSamePublisher
+	scoreGadget
+	scoreDefaultProvider
+)
+
+var scoreCriteria = []struct {
+	score int
+	name  string
+}{
+	{scoreDefaultProvider, "default-provider"},
+	{scoreGadget, "gadget"},
+	{scoreSamePublisher, "same-publisher"},
+	{scoreBrandStore, "brand-store"},
+}
+
+func (c *autoConnectChecker) publisherID(sn *snap.Info) string {
+	if sn.SnapID == "" {
+		return ""
+	}
+	snapDecl, err := c.snapDeclaration(sn.SnapID)
+	if err != nil {
+		return ""
+	}
+	return snapDecl.PublisherID()
+}
+
+// scoreCandidate scores slot as the auto-connection candidate of plug.
+func (c *autoConnectChecker) scoreCandidate(plug *snap.PlugInfo, slot *snap.SlotInfo) int {
+	score := 0
+
+	var dprovider string
+	if plug.Interface == "content" && plug.Attr("default-provider", &dprovider) == nil {
+		// usage can be "snap:slot" but slot is ignored
+		if strings.Split(dprovider, ":")[0] == slot.Snap.InstanceName() {
+			score |= scoreDefaultProvider
+		}
+	}
+
+	model := c.deviceCtx.Model()
+	if slot.Snap.Type() == snap.TypeGadget && slot.Snap.InstanceName() == model.Gadget() {
+		score |= scoreGadget
+	}
+
+	if slotPublisher := c.publisherID(slot.Snap); slotPublisher != "" {
+		if slotPublisher == c.publisherID(plug.Snap) {
+			score |= scoreSamePublisher
+		}
+		if slotPublisher == model.BrandID() {
+			score |= scoreBrandStore
+		}
+	}
+	return score
+}
+
+// pickCandidate chooses the slot to auto-connect plug to among several
+// candidates, see the scoring policy above. It returns nil if the choice
+// is ambiguous, otherwise the chosen slot and the reason it was chosen.
+func (c *autoConnectChecker) pickCandidate(plug *snap.PlugInfo, candidates []*snap.SlotInfo) (*snap.SlotInfo, string) {
+	var best *snap.SlotInfo
+	bestScore := 0
+	tied := false
+	for _, slot := range candidates {
+		score := c.scoreCandidate(plug, slot)
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = slot, score, false
+		case score == bestScore:
+			tied = true
+		}
+	}
+	if best == nil || tied {
+		return nil, ""
+	}
+
+	var criteria []string
+	for _, crit := range scoreCriteria {
+		if bestScore&crit.score != 0 {
+			criteria = append(criteria, crit.name)
+		}
+	}
+	var others []string
+	for _, slot := range candidates {
+		if slot != best {
+			others = append(others, slot.String())
+		}
+	}
+	return best, fmt.Sprintf("%s, preferred over %s", strings.Join(criteria, ","), strings.Join(others, ","))
+}
+
 // addAutoConnections adds to newconns any applicable auto-connections
 // from the given plugs to corresponding candidates slots after
 // filtering them with optional filter and against preexisting
@@ -822,12 +1018,17 @@ func (c *autoConnectChecker) addAutoConnections(newconns map[string]*interfaces.
 		candSlots, arities = filterUbuntuCoreSlots(candSlots, arities)
 
 		applicable := candSlots
+		var reason string
 		// candidate arity check
 		for _, arity := range arities {
 			if !arity.SlotsPerPlugAny() {
 				// ATM not any (*) => none or exactly one
 				if len(candSlots) != 1 {
 					applicable = nil
+					if best, why := c.pickCandidate(plug, candSlots); best != nil {
+						applicable = []*snap.SlotInfo{best}
+						reason = why
+					}
 				}
 				break
 			}
@@ -847,9 +1048,12 @@ func (c *autoConnectChecker) addAutoConnections(newconns map[string]*interfaces.
 		}
 
 		for _, slot := range applicable {
-			if err := addNewConnection(c.st, c.task, newconns, conns, plug, slot, conflictError); err != nil {
+			if err := addNewConnection(c.st, c.task, newconns, conns, plug, slot, false, conflictError); err != nil {
 				return err
 			}
+			if reason != "" {
+				c.reasons[interfaces.NewConnRef(plug, slot).ID()] = reason
+			}
 		}
 	}
 
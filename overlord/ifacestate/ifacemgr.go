@@ -32,6 +32,7 @@ import (
 	"github.com/snapcore/snapd/overlord/ifacestate/udevmonitor"
 	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/sandbox"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snapdenv"
 	"github.com/snapcore/snapd/timings"
@@ -53,6 +54,8 @@ type InterfaceManager struct {
 	udevMon             udevmonitor.Interface
 	udevRetryTimeout    time.Time
 	udevMonitorDisabled bool
+	// next time connections usage is recorded
+	nextUsageCheck time.Time
 	// indexed by interface name and device key. Reset to nil when enumeration is done.
 	enumeratedDeviceKeys map[string]map[snap.HotplugKey]bool
 	enumerationDone      bool
@@ -175,7 +178,7 @@ func (m *InterfaceManager) StartUp() error {
 		}
 	}
 	if snapdAppArmorServiceIsDisabled() {
-		s.Warnf(`the snapd.apparmor service is disabled; snap applications will likely not start.
+		s.WarnfWithSeverity(state.WarningSeverityCritical, `the snapd.apparmor service is disabled; snap applications will likely not start.
 Run "systemctl enable --now snapd.apparmor" to correct this.`)
 	}
 
@@ -196,6 +199,12 @@ func (m *InterfaceManager) Ensure() error {
 		return nil
 	}
 
+	m.ensureConnectionsUsage()
+
+	return m.ensureUDevMonitor()
+}
+
+func (m *InterfaceManager) ensureUDevMonitor() error {
 	if m.udevMonitorDisabled {
 		return nil
 	}
@@ -212,6 +221,11 @@ func (m *InterfaceManager) Ensure() error {
 		return nil
 	}
 
+	// there is no point in retrying without udev
+	if _, degraded := sandbox.Degraded(sandbox.FeatureHotplug); degraded {
+		return nil
+	}
+
 	// retry udev monitor initialization every 5 minutes
 	now := time.Now()
 	if now.After(m.udevRetryTimeout) {
@@ -258,6 +272,9 @@ type ConnectionState struct {
 	Auto bool
 	// ByGadget indicates whether the connection was trigged by the gadget
 	ByGadget bool
+	// AutoReason explains why the slot of an auto-connection was chosen
+	// among several candidates
+	AutoReason string
 	// Interface name of the connection
 	Interface string
 	// Undesired indicates whether the connection, otherwise established
@@ -268,6 +285,11 @@ type ConnectionState struct {
 	StaticSlotAttrs  map[string]interface{}
 	DynamicSlotAttrs map[string]interface{}
 	HotplugGone      bool
+	// PlugLastUsed and SlotLastUsed are when an app or hook bound to
+	// the plug or the slot was last seen running, they are zero if
+	// this never happened.
+	PlugLastUsed time.Time
+	SlotLastUsed time.Time
 }
 
 // Active returns true if connection is not undesired and not removed by
@@ -289,9 +311,10 @@ func ConnectionStates(st *state.State) (connStateByRef map[string]ConnectionStat
 
 	connStateByRef = make(map[string]ConnectionState, len(states))
 	for cref, cstate := range states {
-		connStateByRef[cref] = ConnectionState{
+		cs := ConnectionState{
 			Auto:             cstate.Auto,
 			ByGadget:         cstate.ByGadget,
+			AutoReason:       cstate.AutoReason,
 			Interface:        cstate.Interface,
 			Undesired:        cstate.Undesired,
 			StaticPlugAttrs:  cstate.StaticPlugAttrs,
@@ -300,6 +323,13 @@ func ConnectionStates(st *state.State) (connStateByRef map[string]ConnectionStat
 			DynamicSlotAttrs: cstate.DynamicSlotAttrs,
 			HotplugGone:      cstate.HotplugGone,
 		}
+		if cstate.PlugLastUsed != nil {
+			cs.PlugLastUsed = *cstate.PlugLastUsed
+		}
+		if cstate.SlotLastUsed != nil {
+			cs.SlotLastUsed = *cstate.SlotLastUsed
+		}
+		connStateByRef[cref] = cs
 	}
 	return connStateByRef, nil
 }
@@ -83,6 +83,9 @@ func findSymmetricAutoconnectTask(st *state.State, plugSnap, slotSnap string, in
 type connectOpts struct {
 	ByGadget    bool
 	AutoConnect bool
+	// AutoReason explains why the slot was chosen among several
+	// auto-connection candidates.
+	AutoReason string
 
 	DelayedSetupProfiles bool
 }
@@ -205,6 +208,9 @@ func connect(st *state.State, plugSnap, plugName, slotSnap, slotName string, fla
 	if flags.ByGadget {
 		connectInterface.Set("by-gadget", true)
 	}
+	if flags.AutoReason != "" {
+		connectInterface.Set("auto-reason", flags.AutoReason)
+	}
 	if flags.DelayedSetupProfiles {
 		connectInterface.Set("delayed-setup-profiles", true)
 	}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -254,6 +255,9 @@ func (s *interfaceManagerSuite) SetUpTest(c *C) {
 	s.log = buf
 
 	s.BaseTest.AddCleanup(ifacestate.MockConnectRetryTimeout(0))
+	s.BaseTest.AddCleanup(ifacestate.MockCgroupPidsOfSnap(func(string) (map[string][]int, error) {
+		return nil, nil
+	}))
 	restore = seccomp_compiler.MockCompilerVersionInfo("abcdef 1.2.3 1234abcd -")
 	s.BaseTest.AddCleanup(restore)
 
@@ -2594,6 +2598,46 @@ func (s *interfaceManagerSuite) TestDoSetupSnapSecurityAutoConnectsPlugs(c *C) {
 	c.Assert(ifaces.Connections, HasLen, 1) //FIXME add deep eq
 }
 
+// The auto-connect task will not auto-connect plugs of quarantined snaps.
+func (s *interfaceManagerSuite) TestDoSetupSnapSecurityNoAutoConnectQuarantined(c *C) {
+	s.MockModel(c, nil)
+
+	// Add an OS snap.
+	s.mockSnap(c, ubuntuCoreSnapYaml)
+
+	// Initialize the manager. This registers the OS snap.
+	mgr := s.manager(c)
+
+	// Add a sample snap with a "network" plug and quarantine it.
+	snapInfo := s.mockSnap(c, sampleSnapYaml)
+	s.state.Lock()
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, snapInfo.InstanceName(), &snapst), IsNil)
+	snapst.Quarantined = true
+	snapstate.Set(s.state, snapInfo.InstanceName(), &snapst)
+	s.state.Unlock()
+
+	// Run the setup-snap-security task and let it finish.
+	change := s.addSetupSnapSecurityChange(c, &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: snapInfo.SnapName(),
+			Revision: snapInfo.Revision,
+		},
+	})
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(change.Status(), Equals, state.DoneStatus)
+
+	// Ensure that "network" was not connected.
+	var conns map[string]interface{}
+	err := s.state.Get("conns", &conns)
+	c.Assert(err, testutil.ErrorIs, state.ErrNoState)
+	c.Check(mgr.Repository().Interfaces().Connections, HasLen, 0)
+}
+
 // The auto-connect task will auto-connect slots with viable candidates.
 func (s *interfaceManagerSuite) TestDoSetupSnapSecurityAutoConnectsSlots(c *C) {
 	s.MockModel(c, nil)
@@ -3702,6 +3746,78 @@ func (s *interfaceManagerSuite) TestSetupProfilesUsesFreshSnapInfo(c *C) {
 	c.Check(s.secBackend.SetupCalls[1].SnapInfo.Revision, Equals, coreSnapInfo.Revision)
 }
 
+func (s *interfaceManagerSuite) testSetupProfilesLayoutChanges(c *C, newLayout string) (discarded []string, logs []string) {
+	s.MockModel(c, nil)
+
+	const layoutSnapYaml = `name: snap
+version: 1
+apps:
+ app:
+  command: foo
+layout:
+ /usr/share/foo:
+  bind: $SNAP/usr/share/foo
+ /etc/foo.conf:
+  bind-file: $SNAP/etc/foo.conf
+`
+	s.mockSnap(c, layoutSnapYaml)
+	_ = s.manager(c)
+
+	restore := ifacestate.MockDiscardSnapNamespace(func(snapName string) error {
+		discarded = append(discarded, snapName)
+		return nil
+	})
+	defer restore()
+
+	newSnapInfo := s.mockUpdatedSnap(c, "name: snap\nversion: 2\napps:\n app:\n  command: foo\n"+newLayout, 42)
+	change := s.addSetupSnapSecurityChange(c, &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: newSnapInfo.SnapName(),
+			Revision: newSnapInfo.Revision,
+		},
+	})
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(change.Err(), IsNil)
+	for _, t := range change.Tasks() {
+		for _, l := range t.Log() {
+			logs = append(logs, l[strings.Index(l, " ")+1:])
+		}
+	}
+	return discarded, logs
+}
+
+func (s *interfaceManagerSuite) TestSetupProfilesLayoutChangesCompatible(c *C) {
+	discarded, logs := s.testSetupProfilesLayoutChanges(c, `layout:
+ /usr/share/foo:
+  bind: $SNAP/usr/share/foo
+ /opt/bar:
+  bind: $SNAP/opt/bar
+`)
+	c.Check(discarded, HasLen, 0)
+	c.Check(logs, DeepEquals, []string{
+		"INFO Layout removed /etc/foo.conf: bind-file $SNAP/etc/foo.conf",
+		"INFO Layout added /opt/bar: bind $SNAP/opt/bar",
+	})
+}
+
+func (s *interfaceManagerSuite) TestSetupProfilesLayoutChangesIncompatible(c *C) {
+	discarded, logs := s.testSetupProfilesLayoutChanges(c, `layout:
+ /usr/share/foo:
+  symlink: $SNAP/usr/share/foo
+ /etc/foo.conf:
+  bind-file: $SNAP/etc/foo.conf
+`)
+	c.Check(discarded, DeepEquals, []string{"snap"})
+	c.Check(logs, DeepEquals, []string{
+		"INFO Layout changed /usr/share/foo: symlink $SNAP/usr/share/foo (was bind $SNAP/usr/share/foo)",
+		`INFO Discarding the mount namespace of snap "snap" due to incompatible layout changes of "/usr/share/foo"`,
+	})
+}
+
 func (s *interfaceManagerSuite) TestSetupProfilesOnInstall(c *C) {
 	s.MockModel(c, nil)
 
@@ -4205,6 +4321,56 @@ func (s *interfaceManagerSuite) TestConnectSetsUpSecurity(c *C) {
 	c.Check(s.secBackend.SetupCalls[1].Options, DeepEquals, interfaces.ConfinementOptions{})
 }
 
+func (s *interfaceManagerSuite) TestConnectDefersNamespaceUpdateOfSnapsSetUpLater(c *C) {
+	s.MockModel(c, nil)
+
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
+
+	s.mockSnap(c, consumerYaml)
+	producerInfo := s.mockSnap(c, producerYaml)
+	_ = s.manager(c)
+
+	s.state.Lock()
+	ts, err := ifacestate.Connect(s.state, "consumer", "plug", "producer", "slot")
+	c.Assert(err, IsNil)
+	ts.Tasks()[0].Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "consumer",
+		},
+	})
+
+	// the profiles of producer are set up again later in the change
+	setupProfiles := s.state.NewTask("setup-profiles", "")
+	setupProfiles.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &producerInfo.SideInfo,
+	})
+	setupProfiles.WaitAll(ts)
+
+	change := s.state.NewChange("connect", "")
+	change.AddAll(ts)
+	change.AddTask(setupProfiles)
+	s.state.Unlock()
+
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(change.Err(), IsNil)
+	c.Check(change.Status(), Equals, state.DoneStatus)
+
+	c.Assert(s.secBackend.SetupCalls, HasLen, 4)
+	c.Check(s.secBackend.SetupCalls[0].SnapInfo.InstanceName(), Equals, "producer")
+	c.Check(s.secBackend.SetupCalls[0].Options, DeepEquals, interfaces.ConfinementOptions{DeferNamespaceUpdate: true, DeferDeviceRulesReload: true})
+	c.Check(s.secBackend.SetupCalls[1].SnapInfo.InstanceName(), Equals, "consumer")
+	c.Check(s.secBackend.SetupCalls[1].Options, DeepEquals, interfaces.ConfinementOptions{})
+	// the namespace of producer is updated by setup-profiles
+	c.Check(s.secBackend.SetupCalls[2].SnapInfo.InstanceName(), Equals, "producer")
+	c.Check(s.secBackend.SetupCalls[2].Options, DeepEquals, interfaces.ConfinementOptions{})
+	c.Check(s.secBackend.SetupCalls[3].SnapInfo.InstanceName(), Equals, "consumer")
+	c.Check(s.secBackend.SetupCalls[3].Options, DeepEquals, interfaces.ConfinementOptions{})
+}
+
 func (s *interfaceManagerSuite) TestConnectSetsHotplugKeyFromTheSlot(c *C) {
 	s.MockModel(c, nil)
 
@@ -6320,6 +6486,10 @@ func (s *interfaceManagerSuite) TestDisconnectInterfacesRetrySetupProfiles(c *C)
 }
 
 func (s *interfaceManagerSuite) setupAutoConnectGadget(c *C) {
+	s.setupAutoConnectGadgetWithApply(c, "")
+}
+
+func (s *interfaceManagerSuite) setupAutoConnectGadgetWithApply(c *C, apply string) {
 	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"})
 
 	r := assertstest.MockBuiltinBaseDeclaration([]byte(`
@@ -6345,11 +6515,15 @@ type: gadget
 connections:
    - plug: consumeridididididididididididid:plug
      slot: produceridididididididididididid:slot
-
+`)
+	if apply != "" {
+		gadgetYaml = append(gadgetYaml, fmt.Sprintf("     apply: %s\n", apply)...)
+	}
+	gadgetYaml = append(gadgetYaml, `
 volumes:
     volume-id:
         bootloader: grub
-`)
+`...)
 
 	err := os.WriteFile(filepath.Join(gadgetInfo.MountDir(), "meta", "gadget.yaml"), gadgetYaml, 0644)
 	c.Assert(err, IsNil)
@@ -6522,6 +6696,105 @@ func (s *interfaceManagerSuite) TestAutoConnectGadgetSeededNoop(c *C) {
 	c.Assert(tasks, HasLen, 1)
 }
 
+func (s *interfaceManagerSuite) testAutoConnectGadgetSeeded(c *C, apply, snapName string, conns map[string]interface{}) []*state.Task {
+	r1 := release.MockOnClassic(false)
+	defer r1()
+
+	s.setupAutoConnectGadgetWithApply(c, apply)
+	s.manager(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.state.Set("seeded", true)
+	if conns != nil {
+		s.state.Set("conns", conns)
+	}
+
+	chg := s.state.NewChange("setting-up", "...")
+	t := s.state.NewTask("auto-connect", "gadget connections")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: snapName},
+	})
+	chg.AddTask(t)
+
+	s.state.Unlock()
+	s.se.Ensure()
+	s.se.Wait()
+	s.state.Lock()
+
+	c.Assert(chg.Err(), IsNil)
+	return chg.Tasks()
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectGadgetSeededApplySeedingNoop(c *C) {
+	tasks := s.testAutoConnectGadgetSeeded(c, "seeding", "consumer", nil)
+	c.Assert(tasks, HasLen, 1)
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectGadgetSeededApplyAlways(c *C) {
+	tasks := s.testAutoConnectGadgetSeeded(c, "always", "consumer", nil)
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(tasks, HasLen, 7)
+	checkAutoConnectGadgetTasks(c, tasks)
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectGadgetSeededApplyEnforce(c *C) {
+	tasks := s.testAutoConnectGadgetSeeded(c, "enforce", "consumer", nil)
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(tasks, HasLen, 7)
+	checkAutoConnectGadgetTasks(c, tasks)
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectGadgetSeededApplyAlwaysUndesired(c *C) {
+	tasks := s.testAutoConnectGadgetSeeded(c, "always", "producer", map[string]interface{}{
+		"consumer:plug producer:slot": map[string]interface{}{
+			"interface": "test", "auto": true, "by-gadget": true, "undesired": true,
+		},
+	})
+	// the user disconnected it, nothing happens
+	c.Assert(tasks, HasLen, 1)
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectGadgetSeededApplyEnforceUndesired(c *C) {
+	tasks := s.testAutoConnectGadgetSeeded(c, "enforce", "producer", map[string]interface{}{
+		"consumer:plug producer:slot": map[string]interface{}{
+			"interface": "test", "auto": true, "by-gadget": true, "undesired": true,
+		},
+	})
+	// connected again
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(tasks, HasLen, 7)
+	checkAutoConnectGadgetTasks(c, tasks)
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectGadgetSeededApplyEnforceAlreadyConnected(c *C) {
+	tasks := s.testAutoConnectGadgetSeeded(c, "enforce", "producer", map[string]interface{}{
+		"consumer:plug producer:slot": map[string]interface{}{
+			"interface": "test", "auto": true, "by-gadget": true,
+		},
+	})
+	c.Assert(tasks, HasLen, 1)
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectGadgetRefreshApplyAlways(c *C) {
+	// the gadget is refreshed, its connections are re-evaluated
+	tasks := s.testAutoConnectGadgetSeeded(c, "always", "gadget", nil)
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Assert(tasks, HasLen, 7)
+	checkAutoConnectGadgetTasks(c, tasks)
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectGadgetRefreshApplySeedingNoop(c *C) {
+	tasks := s.testAutoConnectGadgetSeeded(c, "", "gadget", nil)
+	c.Assert(tasks, HasLen, 1)
+}
+
 func (s *interfaceManagerSuite) TestAutoConnectGadgetAlreadyConnected(c *C) {
 	r1 := release.MockOnClassic(false)
 	defer r1()
@@ -8151,6 +8424,78 @@ func (s *interfaceManagerSuite) TestConnectionStatesHotplugGone(c *C) {
 		}})
 }
 
+func (s *interfaceManagerSuite) TestEnsureRecordsConnectionsUsage(c *C) {
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
+	s.mockSnap(c, producerYaml)
+	s.mockSnap(c, consumerYaml)
+
+	s.state.Lock()
+	s.state.Set("conns", map[string]interface{}{
+		"consumer:plug producer:slot":      map[string]interface{}{"interface": "test"},
+		"consumer:otherplug producer:slot": map[string]interface{}{"interface": "test2", "undesired": true},
+	})
+	s.state.Unlock()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	restore := ifacestate.MockTimeNow(func() time.Time { return now })
+	defer restore()
+	running := map[string]map[string][]int{}
+	var scanned []string
+	restore = ifacestate.MockCgroupPidsOfSnap(func(snapName string) (map[string][]int, error) {
+		scanned = append(scanned, snapName)
+		return running[snapName], nil
+	})
+	defer restore()
+
+	mgr := s.manager(c)
+
+	// nothing is running
+	c.Assert(mgr.Ensure(), IsNil)
+	sort.Strings(scanned)
+	c.Check(scanned, DeepEquals, []string{"consumer", "producer"})
+	conns, err := mgr.ConnectionStates()
+	c.Assert(err, IsNil)
+	c.Check(conns["consumer:plug producer:slot"].PlugLastUsed.IsZero(), Equals, true)
+	c.Check(conns["consumer:plug producer:slot"].SlotLastUsed.IsZero(), Equals, true)
+
+	// not checked again before the interval elapsed
+	scanned = nil
+	running["consumer"] = map[string][]int{"snap.consumer.hook.connect-plug-plug": {42}}
+	now = now.Add(time.Minute)
+	c.Assert(mgr.Ensure(), IsNil)
+	c.Check(scanned, HasLen, 0)
+
+	// a hook bound to the plug is running
+	now = now.Add(10 * time.Minute)
+	c.Assert(mgr.Ensure(), IsNil)
+	conns, err = mgr.ConnectionStates()
+	c.Assert(err, IsNil)
+	c.Check(conns["consumer:plug producer:slot"].PlugLastUsed.Equal(now), Equals, true)
+	c.Check(conns["consumer:plug producer:slot"].SlotLastUsed.IsZero(), Equals, true)
+	// undesired connections are not in use
+	c.Check(conns["consumer:otherplug producer:slot"].PlugLastUsed.IsZero(), Equals, true)
+	pluggedAt := now
+
+	// only a process bound to the slot is running
+	running["consumer"] = map[string][]int{"snap.consumer.hook.configure": {42}}
+	running["producer"] = map[string][]int{"snap.producer.hook.connect-slot-slot": {43}}
+	now = now.Add(10 * time.Minute)
+	c.Assert(mgr.Ensure(), IsNil)
+	conns, err = mgr.ConnectionStates()
+	c.Assert(err, IsNil)
+	c.Check(conns["consumer:plug producer:slot"].PlugLastUsed.Equal(pluggedAt), Equals, true)
+	c.Check(conns["consumer:plug producer:slot"].SlotLastUsed.Equal(now), Equals, true)
+
+	// usage is persisted with the connection
+	s.state.Lock()
+	defer s.state.Unlock()
+	var rawConns map[string]map[string]interface{}
+	c.Assert(s.state.Get("conns", &rawConns), IsNil)
+	c.Check(rawConns["consumer:plug producer:slot"]["plug-last-used"], Equals, "2026-01-02T03:15:05Z")
+	c.Check(rawConns["consumer:plug producer:slot"]["slot-last-used"], Equals, "2026-01-02T03:25:05Z")
+	c.Check(rawConns["consumer:otherplug producer:slot"]["plug-last-used"], IsNil)
+}
+
 func (s *interfaceManagerSuite) TestResolveDisconnectFromConns(c *C) {
 	mgr := s.manager(c)
 
@@ -8521,6 +8866,139 @@ func (s *interfaceManagerSuite) TestDoSetupSnapSecurityAutoConnectsDeclBasedAnyS
 	s.testDoSetupSnapSecurityAutoConnectsDeclBasedAnySlotsPerPlug(c, check)
 }
 
+func (s *interfaceManagerSuite) testAutoConnectScoredCandidates(c *C, consumerYaml string) (map[string]interface{}, []*interfaces.ConnRef) {
+	const theme1Yaml = `
+name: theme1
+version: 1
+slots:
+  slot:
+    interface: content
+    content: themes
+`
+	s.mockSnap(c, theme1Yaml)
+	const theme2Yaml = `
+name: theme2
+version: 1
+slots:
+  slot:
+    interface: content
+    content: themes
+`
+	s.mockSnap(c, theme2Yaml)
+
+	mgr := s.manager(c)
+
+	snapInfo := s.mockSnap(c, consumerYaml)
+
+	// Run the setup-snap-security task and let it finish.
+	change := s.addSetupSnapSecurityChange(c, &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: snapInfo.SnapName(),
+			SnapID:   snapInfo.SnapID,
+			Revision: snapInfo.Revision,
+		},
+	})
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(change.Status(), Equals, state.DoneStatus)
+
+	var conns map[string]interface{}
+	_ = s.state.Get("conns", &conns)
+	return conns, mgr.Repository().Interfaces().Connections
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectScoredCandidatesDefaultProvider(c *C) {
+	s.MockModel(c, nil)
+
+	s.MockSnapDecl(c, "theme1", "one-publisher", nil)
+	s.MockSnapDecl(c, "theme2", "one-publisher", nil)
+	s.MockSnapDecl(c, "theme-consumer", "one-publisher", nil)
+
+	conns, repoConns := s.testAutoConnectScoredCandidates(c, `
+name: theme-consumer
+version: 1
+plugs:
+  plug:
+    interface: content
+    content: themes
+    default-provider: theme2
+`)
+	// both candidates are from the same publisher, theme2 is
+	// preferred as the default-provider
+	c.Check(repoConns, HasLen, 1)
+	c.Check(conns, DeepEquals, map[string]interface{}{
+		"theme-consumer:plug theme2:slot": map[string]interface{}{
+			"auto":        true,
+			"auto-reason": "default-provider,same-publisher, preferred over theme1:slot",
+			"interface":   "content",
+			"plug-static": map[string]interface{}{"content": "themes", "default-provider": "theme2"},
+			"slot-static": map[string]interface{}{"content": "themes"},
+		},
+	})
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectScoredCandidatesBrandStore(c *C) {
+	s.MockModel(c, nil)
+
+	// allow auto-connections across publishers
+	restore := assertstest.MockBuiltinBaseDeclaration([]byte(`
+type: base-declaration
+authority-id: canonical
+series: 16
+slots:
+  content:
+    allow-auto-connection: true
+`))
+	defer restore()
+
+	s.MockSnapDecl(c, "theme1", "other-publisher", nil)
+	// the brand of the mocked model
+	s.MockSnapDecl(c, "theme2", "my-brand", nil)
+	s.MockSnapDecl(c, "theme-consumer", "one-publisher", nil)
+
+	conns, repoConns := s.testAutoConnectScoredCandidates(c, `
+name: theme-consumer
+version: 1
+plugs:
+  plug:
+    interface: content
+    content: themes
+`)
+	c.Check(repoConns, HasLen, 1)
+	c.Check(conns, DeepEquals, map[string]interface{}{
+		"theme-consumer:plug theme2:slot": map[string]interface{}{
+			"auto":        true,
+			"auto-reason": "brand-store, preferred over theme1:slot",
+			"interface":   "content",
+			"plug-static": map[string]interface{}{"content": "themes"},
+			"slot-static": map[string]interface{}{"content": "themes"},
+		},
+	})
+}
+
+func (s *interfaceManagerSuite) TestAutoConnectScoredCandidatesTie(c *C) {
+	s.MockModel(c, nil)
+
+	s.MockSnapDecl(c, "theme1", "one-publisher", nil)
+	s.MockSnapDecl(c, "theme2", "one-publisher", nil)
+	s.MockSnapDecl(c, "theme-consumer", "one-publisher", nil)
+
+	conns, repoConns := s.testAutoConnectScoredCandidates(c, `
+name: theme-consumer
+version: 1
+plugs:
+  plug:
+    interface: content
+    content: themes
+`)
+	// both candidates score the same, nothing was connected
+	c.Check(repoConns, HasLen, 0)
+	c.Check(conns, HasLen, 0)
+}
+
 func (s *interfaceManagerSuite) TestDoSetupSnapSecurityAutoConnectsDeclBasedSlotNames(c *C) {
 	s.MockModel(c, nil)
 
@@ -20,7 +20,11 @@
 // Package schema holds structs for reading and writing interface-related state data.
 package schema
 
-import "github.com/snapcore/snapd/snap"
+import (
+	"time"
+
+	"github.com/snapcore/snapd/snap"
+)
 
 // ConnState holds properties of an interface connection.
 type ConnState struct {
@@ -41,4 +45,12 @@ type ConnState struct {
 	// slots.
 	HotplugGone bool            `json:"hotplug-gone,omitempty" yaml:"hotplug-gone,omitempty"`
 	HotplugKey  snap.HotplugKey `json:"hotplug-key,omitempty" yaml:"hotplug-key,omitempty"`
+	// AutoReason explains why the slot of an auto-connection was
+	// chosen among several candidates.
+	AutoReason string `json:"auto-reason,omitempty" yaml:"auto-reason,omitempty"`
+	// PlugLastUsed and SlotLastUsed record when an app or hook bound to
+	// the plug or the slot, respectively, was last seen running with
+	// the connection in place.
+	PlugLastUsed *time.Time `json:"plug-last-used,omitempty" yaml:"plug-last-used,omitempty"`
+	SlotLastUsed *time.Time `json:"slot-last-used,omitempty" yaml:"slot-last-used,omitempty"`
 }
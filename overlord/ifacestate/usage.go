@@ -0,0 +1,128 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ifacestate
+
+import (
+	"time"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/sandbox/cgroup"
+	"github.com/snapcore/snapd/snap"
+)
+
+var (
+	connectionsUsageInterval = 10 * time.Minute
+	cgroupPidsOfSnap         = cgroup.PidsOfSnap
+	timeNow                  = time.Now
+)
+
+// ensureConnectionsUsage records, at most once every
+// connectionsUsageInterval, when the established connections were last
+// used. A side of a connection is used when an app or hook bound to its
+// plug or slot is running, confined by a profile that carries the rules
+// of the connection. The data is kept only locally.
+func (m *InterfaceManager) ensureConnectionsUsage() {
+	now := timeNow()
+	if now.Before(m.nextUsageCheck) {
+		return
+	}
+	m.nextUsageCheck = now.Add(connectionsUsageInterval)
+
+	m.state.Lock()
+	conns, err := getConns(m.state)
+	m.state.Unlock()
+	if err != nil {
+		logger.Noticef("cannot record usage of connections: %v", err)
+		return
+	}
+
+	// scan the running processes without holding the state lock
+	runningTags := make(map[string]map[string]bool)
+	isRunning := func(snapInfo *snap.Info, apps map[string]*snap.AppInfo, hooks map[string]*snap.HookInfo) bool {
+		instanceName := snapInfo.InstanceName()
+		tags, ok := runningTags[instanceName]
+		if !ok {
+			pids, err := cgroupPidsOfSnap(instanceName)
+			if err != nil {
+				logger.Debugf("cannot find running processes of snap %q: %v", instanceName, err)
+			}
+			tags = make(map[string]bool, len(pids))
+			for tag := range pids {
+				tags[tag] = true
+			}
+			runningTags[instanceName] = tags
+		}
+		for _, app := range apps {
+			if tags[app.SecurityTag()] {
+				return true
+			}
+		}
+		for _, hook := range hooks {
+			if tags[hook.SecurityTag()] {
+				return true
+			}
+		}
+		return false
+	}
+
+	usedPlugs := make(map[string]bool)
+	usedSlots := make(map[string]bool)
+	for id, cstate := range conns {
+		if cstate.Undesired || cstate.HotplugGone {
+			continue
+		}
+		cref, err := interfaces.ParseConnRef(id)
+		if err != nil {
+			logger.Noticef("cannot record usage of connection %q: %v", id, err)
+			continue
+		}
+		if plug := m.repo.Plug(cref.PlugRef.Snap, cref.PlugRef.Name); plug != nil && isRunning(plug.Snap, plug.Apps, plug.Hooks) {
+			usedPlugs[id] = true
+		}
+		if slot := m.repo.Slot(cref.SlotRef.Snap, cref.SlotRef.Name); slot != nil && isRunning(slot.Snap, slot.Apps, slot.Hooks) {
+			usedSlots[id] = true
+		}
+	}
+	if len(usedPlugs) == 0 && len(usedSlots) == 0 {
+		return
+	}
+
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	// connections may have changed in the meantime
+	conns, err = getConns(m.state)
+	if err != nil {
+		logger.Noticef("cannot record usage of connections: %v", err)
+		return
+	}
+	for id, cstate := range conns {
+		if usedPlugs[id] {
+			lastUsed := now
+			cstate.PlugLastUsed = &lastUsed
+		}
+		if usedSlots[id] {
+			lastUsed := now
+			cstate.SlotLastUsed = &lastUsed
+		}
+	}
+	setConns(m.state, conns)
+}
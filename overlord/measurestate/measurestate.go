@@ -0,0 +1,362 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package measurestate implements the manager that measures the blobs and
+// the generated security profiles of installed snaps into the TPM and keeps
+// the list of the measurements for remote attestation.
+package measurestate
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/secboot"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
+)
+
+// Kinds of measured files.
+const (
+	// KindSnapBlob is the kind of the measurements of snap files.
+	KindSnapBlob = "snap-blob"
+	// KindSecurityProfile is the kind of the measurements of the security
+	// profiles generated for snaps.
+	KindSecurityProfile = "security-profile"
+)
+
+// Entry is a single measurement of the measurement list.
+type Entry struct {
+	Time     time.Time     `json:"time"`
+	Kind     string        `json:"kind"`
+	Snap     string        `json:"snap"`
+	Revision snap.Revision `json:"revision"`
+	Path     string        `json:"path"`
+	Digest   string        `json:"digest"`
+	// PCR is the TPM PCR that was extended with the event of the
+	// measurement, it is unset if there was no TPM to extend.
+	PCR int `json:"pcr,omitempty"`
+}
+
+// Event returns the event data the PCR is extended with for the
+// measurement, it is what a verifier needs to replay the measurement list.
+func (e *Entry) Event() []byte {
+	return []byte(fmt.Sprintf("%s %s %s", e.Kind, e.Digest, e.Path))
+}
+
+// MeasureManager measures the snaps installed or refreshed by changes, and
+// the security profiles regenerated for them, if measurements were enabled
+// through the measurements.enabled core option.
+type MeasureManager struct {
+	state *state.State
+}
+
+// Manager returns a new MeasureManager.
+func Manager(st *state.State) *MeasureManager {
+	m := &MeasureManager{state: st}
+
+	st.Lock()
+	defer st.Unlock()
+	st.AddChangeStatusChangedHandler(m.changeStatusChanged)
+
+	return m
+}
+
+func measurementsEnabled(st *state.State) bool {
+	var enabled bool
+	tr := config.NewTransaction(st)
+	if err := tr.Get("core", "measurements.enabled", &enabled); err != nil && !config.IsNoOption(err) {
+		logger.Noticef("cannot get measurements.enabled: %v", err)
+		return false
+	}
+	return enabled
+}
+
+// pendingMeasurement is a snap waiting to be measured.
+type pendingMeasurement struct {
+	Snap string `json:"snap"`
+	// Blob is set if the snap file needs to be measured too, and not only
+	// the security profiles.
+	Blob bool `json:"blob,omitempty"`
+}
+
+// changeStatusChanged marks the snaps affected by changes that completed
+// as pending to be measured, they are measured in Ensure as that involves
+// i/o.
+func (m *MeasureManager) changeStatusChanged(chg *state.Change, old, new state.Status) {
+	if new != state.DoneStatus || old == state.DoneStatus {
+		return
+	}
+	if !measurementsEnabled(m.state) {
+		return
+	}
+
+	affected := affectedSnaps(chg)
+	if len(affected) == 0 {
+		return
+	}
+	pending, err := pendingMeasurements(m.state)
+	if err != nil {
+		logger.Noticef("cannot get pending measurements: %v", err)
+		return
+	}
+	m.state.Set("pending-measurements", append(pending, affected...))
+	m.state.EnsureBefore(0)
+}
+
+// affectedSnaps returns the snaps whose file or security profiles were
+// written by the given change.
+func affectedSnaps(chg *state.Change) []pendingMeasurement {
+	var affected []pendingMeasurement
+	add := func(name string, blob bool) {
+		for i := range affected {
+			if affected[i].Snap == name {
+				affected[i].Blob = affected[i].Blob || blob
+				return
+			}
+		}
+		affected = append(affected, pendingMeasurement{Snap: name, Blob: blob})
+	}
+
+	for _, t := range chg.Tasks() {
+		if t.Status() != state.DoneStatus {
+			continue
+		}
+		switch t.Kind() {
+		case "link-snap", "setup-profiles":
+			snapsup, err := snapstate.TaskSnapSetup(t)
+			if err != nil {
+				continue
+			}
+			add(snapsup.InstanceName(), t.Kind() == "link-snap")
+		case "connect", "disconnect":
+			var plugRef interfaces.PlugRef
+			var slotRef interfaces.SlotRef
+			if err := t.Get("plug", &plugRef); err != nil {
+				continue
+			}
+			if err := t.Get("slot", &slotRef); err != nil {
+				continue
+			}
+			add(plugRef.Snap, false)
+			add(slotRef.Snap, false)
+		}
+	}
+	return affected
+}
+
+func pendingMeasurements(st *state.State) ([]pendingMeasurement, error) {
+	var pending []pendingMeasurement
+	if err := st.Get("pending-measurements", &pending); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// Ensure is part of the overlord.StateManager interface.
+func (m *MeasureManager) Ensure() error {
+	st := m.state
+	st.Lock()
+	pending, err := pendingMeasurements(st)
+	if err != nil || len(pending) == 0 {
+		st.Unlock()
+		return err
+	}
+
+	type toMeasure struct {
+		info *snap.Info
+		blob bool
+	}
+	var infos []toMeasure
+	for _, p := range pending {
+		var snapst snapstate.SnapState
+		if err := snapstate.Get(st, p.Snap, &snapst); err != nil || !snapst.IsInstalled() {
+			// removed in the meantime
+			continue
+		}
+		info, err := snapst.CurrentInfo()
+		if err != nil {
+			logger.Noticef("cannot measure snap %q: %v", p.Snap, err)
+			continue
+		}
+		infos = append(infos, toMeasure{info: info, blob: p.Blob})
+	}
+	st.Set("pending-measurements", nil)
+	st.Unlock()
+
+	for _, tm := range infos {
+		if err := measureSnap(tm.info, tm.blob); err != nil {
+			logger.Noticef("cannot measure snap %q: %v", tm.info.InstanceName(), err)
+		}
+	}
+	return nil
+}
+
+var (
+	timeNow                             = time.Now
+	secbootMeasureSnapEventWhenPossible = secboot.MeasureSnapEventWhenPossible
+)
+
+// securityProfileGlobs returns the patterns matching the security profiles
+// generated for the given snap.
+func securityProfileGlobs(instanceName string) []string {
+	return []string{
+		filepath.Join(dirs.SnapAppArmorDir, "snap."+instanceName+".*"),
+		filepath.Join(dirs.SnapAppArmorDir, "snap-update-ns."+instanceName),
+		filepath.Join(dirs.SnapSeccompDir, "snap."+instanceName+".*.bin2"),
+		filepath.Join(dirs.SnapMountPolicyDir, "snap."+instanceName+".*fstab"),
+		filepath.Join(dirs.SnapUdevRulesDir, "*-snap."+instanceName+".rules"),
+	}
+}
+
+func measureSnap(info *snap.Info, blob bool) error {
+	if blob {
+		if err := measureFile(KindSnapBlob, info, info.MountFile()); err != nil {
+			return err
+		}
+	}
+
+	var profiles []string
+	for _, glob := range securityProfileGlobs(info.InstanceName()) {
+		matches, err := filepath.Glob(glob)
+		if err != nil {
+			return err
+		}
+		profiles = append(profiles, matches...)
+	}
+	sort.Strings(profiles)
+	for _, profile := range profiles {
+		if err := measureFile(KindSecurityProfile, info, profile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func measureFile(kind string, info *snap.Info, path string) error {
+	digest, err := fileDigest(path)
+	if err != nil {
+		return err
+	}
+	entry := &Entry{
+		Time:     timeNow(),
+		Kind:     kind,
+		Snap:     info.InstanceName(),
+		Revision: info.Revision,
+		Path:     dirs.StripRootDir(path),
+		Digest:   digest,
+	}
+
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	// the entry is logged only after the PCR was extended with its event
+	// so that the log can be replayed against the PCR
+	measured, err := secbootMeasureSnapEventWhenPossible(entry.Event())
+	if err != nil {
+		return err
+	}
+	if measured {
+		entry.PCR = secboot.SnapMeasurementsPCR
+	}
+	return appendEntry(entry)
+}
+
+// logMu serializes the extension of the PCR and the writes to the log.
+var logMu sync.Mutex
+
+func appendEntry(entry *Entry) error {
+	if err := os.MkdirAll(filepath.Dir(dirs.SnapMeasurementsLog), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dirs.SnapMeasurementsLog, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Measurements returns the measurements done since boot, in the order they
+// extended the PCR. If names are given only the measurements of the snaps
+// with these names are returned.
+func Measurements(names []string) ([]*Entry, error) {
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	f, err := os.Open(dirs.SnapMeasurementsLog)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []*Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("cannot decode measurement: %v", err)
+		}
+		if len(names) > 0 && !strutil.ListContains(names, entry.Snap) {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,262 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package measurestate_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/measurestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func TestMeasureState(t *testing.T) { TestingT(t) }
+
+type measureSuite struct {
+	testutil.BaseTest
+
+	state    *state.State
+	mgr      *measurestate.MeasureManager
+	now      time.Time
+	events   []string
+	measured bool
+	eventErr error
+	logbuf   *bytes.Buffer
+}
+
+var _ = Suite(&measureSuite{})
+
+// sha256 of "foo"
+const fooDigest = "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func (s *measureSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	var restore func()
+
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+	s.AddCleanup(snap.MockSanitizePlugsSlots(func(snapInfo *snap.Info) {}))
+	s.logbuf, restore = logger.MockLogger()
+	s.AddCleanup(restore)
+
+	s.now = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.AddCleanup(measurestate.MockTimeNow(func() time.Time { return s.now }))
+	s.events = nil
+	s.measured = true
+	s.eventErr = nil
+	s.AddCleanup(measurestate.MockSecbootMeasureSnapEventWhenPossible(func(event []byte) (bool, error) {
+		if s.eventErr != nil {
+			return false, s.eventErr
+		}
+		s.events = append(s.events, string(event))
+		return s.measured, nil
+	}))
+
+	s.state = state.New(nil)
+	s.mgr = measurestate.Manager(s.state)
+}
+
+func (s *measureSuite) setConfig(c *C, key string, value interface{}) {
+	s.state.Lock()
+	defer s.state.Unlock()
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", key, value), IsNil)
+	tr.Commit()
+}
+
+func (s *measureSuite) mockSnap(c *C, name string) *snap.Info {
+	si := &snap.SideInfo{RealName: name, Revision: snap.R(7)}
+	info := snaptest.MockSnap(c, "name: "+name+"\nversion: 1.0\n", si)
+	snapstate.Set(s.state, name, &snapstate.SnapState{
+		Active:   true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  snap.R(7),
+		SnapType: "app",
+	})
+
+	c.Assert(os.MkdirAll(filepath.Dir(info.MountFile()), 0755), IsNil)
+	c.Assert(os.WriteFile(info.MountFile(), []byte("foo"), 0644), IsNil)
+	for _, profile := range []string{
+		filepath.Join(dirs.SnapAppArmorDir, "snap."+name+".app"),
+		filepath.Join(dirs.SnapAppArmorDir, "snap-update-ns."+name),
+		filepath.Join(dirs.SnapSeccompDir, "snap."+name+".app.bin2"),
+		// not a profile of the snap
+		filepath.Join(dirs.SnapAppArmorDir, "snap."+name+"_instance.app"),
+	} {
+		c.Assert(os.MkdirAll(filepath.Dir(profile), 0755), IsNil)
+		c.Assert(os.WriteFile(profile, []byte("foo"), 0644), IsNil)
+	}
+	return info
+}
+
+func (s *measureSuite) installChange(c *C) *state.Change {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnap(c, "foo")
+
+	chg := s.state.NewChange("install-snap", "Install snap \"foo\"")
+	t1 := s.state.NewTask("setup-profiles", "Setup snap \"foo\" security profiles")
+	t1.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{RealName: "foo", Revision: snap.R(7)},
+	})
+	chg.AddTask(t1)
+	t2 := s.state.NewTask("link-snap", "Make snap \"foo\" available")
+	t2.Set("snap-setup-task", t1.ID())
+	chg.AddTask(t2)
+	t1.SetStatus(state.DoneStatus)
+	t2.SetStatus(state.DoneStatus)
+	c.Assert(chg.Status(), Equals, state.DoneStatus)
+
+	return chg
+}
+
+func (s *measureSuite) TestNoMeasurementsWhenDisabled(c *C) {
+	s.installChange(c)
+
+	c.Assert(s.mgr.Ensure(), IsNil)
+
+	entries, err := measurestate.Measurements(nil)
+	c.Assert(err, IsNil)
+	c.Check(entries, HasLen, 0)
+	c.Check(s.events, HasLen, 0)
+}
+
+func (s *measureSuite) TestMeasureInstalledSnap(c *C) {
+	s.setConfig(c, "measurements.enabled", true)
+	s.installChange(c)
+
+	c.Assert(s.mgr.Ensure(), IsNil)
+
+	entries, err := measurestate.Measurements(nil)
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 4, Commentf(s.logbuf.String()))
+	for i, expected := range []struct {
+		kind, path string
+	}{
+		{measurestate.KindSnapBlob, "/var/lib/snapd/snaps/foo_7.snap"},
+		{measurestate.KindSecurityProfile, "/var/lib/snapd/apparmor/profiles/snap-update-ns.foo"},
+		{measurestate.KindSecurityProfile, "/var/lib/snapd/apparmor/profiles/snap.foo.app"},
+		{measurestate.KindSecurityProfile, "/var/lib/snapd/seccomp/bpf/snap.foo.app.bin2"},
+	} {
+		c.Check(entries[i], DeepEquals, &measurestate.Entry{
+			Time:     s.now,
+			Kind:     expected.kind,
+			Snap:     "foo",
+			Revision: snap.R(7),
+			Path:     expected.path,
+			Digest:   fooDigest,
+			PCR:      23,
+		})
+		c.Check(s.events[i], Equals, expected.kind+" "+fooDigest+" "+expected.path)
+	}
+
+	// only readable by root
+	st, err := os.Stat(dirs.SnapMeasurementsLog)
+	c.Assert(err, IsNil)
+	c.Check(st.Mode().Perm(), Equals, os.FileMode(0600))
+
+	// pending measurements were consumed
+	s.state.Lock()
+	var pending []interface{}
+	err = s.state.Get("pending-measurements", &pending)
+	s.state.Unlock()
+	c.Check(err, testutil.ErrorIs, state.ErrNoState)
+}
+
+func (s *measureSuite) TestMeasureWithoutTPM(c *C) {
+	s.measured = false
+	s.setConfig(c, "measurements.enabled", true)
+	s.installChange(c)
+
+	c.Assert(s.mgr.Ensure(), IsNil)
+
+	entries, err := measurestate.Measurements(nil)
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 4)
+	for _, entry := range entries {
+		c.Check(entry.PCR, Equals, 0)
+	}
+}
+
+func (s *measureSuite) TestMeasureError(c *C) {
+	s.eventErr = errors.New("tpm error")
+	s.setConfig(c, "measurements.enabled", true)
+	s.installChange(c)
+
+	c.Assert(s.mgr.Ensure(), IsNil)
+
+	// nothing is logged that was not measured
+	entries, err := measurestate.Measurements(nil)
+	c.Assert(err, IsNil)
+	c.Check(entries, HasLen, 0)
+	c.Check(s.logbuf.String(), Matches, `(?s).*cannot measure snap "foo": tpm error.*`)
+}
+
+func (s *measureSuite) TestMeasureConnectedSnaps(c *C) {
+	s.setConfig(c, "measurements.enabled", true)
+
+	s.state.Lock()
+	s.mockSnap(c, "foo")
+	s.mockSnap(c, "bar")
+	chg := s.state.NewChange("connect-snap", "Connect foo:plug to bar:slot")
+	t := s.state.NewTask("connect", "Connect foo:plug to bar:slot")
+	t.Set("plug", interfaces.PlugRef{Snap: "foo", Name: "plug"})
+	t.Set("slot", interfaces.SlotRef{Snap: "bar", Name: "slot"})
+	chg.AddTask(t)
+	t.SetStatus(state.DoneStatus)
+	s.state.Unlock()
+
+	c.Assert(s.mgr.Ensure(), IsNil)
+
+	entries, err := measurestate.Measurements(nil)
+	c.Assert(err, IsNil)
+	// only the profiles are measured
+	c.Check(entries, HasLen, 6)
+	for _, entry := range entries {
+		c.Check(entry.Kind, Equals, measurestate.KindSecurityProfile)
+	}
+
+	entries, err = measurestate.Measurements([]string{"bar"})
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 3)
+	c.Check(entries[0].Path, Equals, "/var/lib/snapd/apparmor/profiles/snap-update-ns.bar")
+}
+
+func (s *measureSuite) TestMeasurementsNoLog(c *C) {
+	entries, err := measurestate.Measurements(nil)
+	c.Assert(err, IsNil)
+	c.Check(entries, HasLen, 0)
+}
@@ -40,12 +40,15 @@ import (
 	"github.com/snapcore/snapd/overlord/assertstate"
 	"github.com/snapcore/snapd/overlord/cmdstate"
 	"github.com/snapcore/snapd/overlord/configstate"
+	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/configstate/proxyconf"
 	"github.com/snapcore/snapd/overlord/devicestate"
 	"github.com/snapcore/snapd/overlord/healthstate"
 	"github.com/snapcore/snapd/overlord/hookstate"
 	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/measurestate"
 	"github.com/snapcore/snapd/overlord/patch"
+	"github.com/snapcore/snapd/overlord/reportstate"
 	"github.com/snapcore/snapd/overlord/restart"
 	"github.com/snapcore/snapd/overlord/servicestate"
 	"github.com/snapcore/snapd/overlord/snapshotstate"
@@ -98,6 +101,8 @@ type Overlord struct {
 
 	startOfOperationTime time.Time
 
+	criticalStartedUp bool
+
 	// managers
 	inited     bool
 	startedUp  bool
@@ -170,6 +175,10 @@ func New(restartHandler restart.Handler) (*Overlord, error) {
 		return nil, err
 	}
 	o.addManager(ifaceMgr)
+	// the interface manager may have to regenerate the security profiles
+	// of all snaps, which can take a while on slow storage, but is not
+	// needed to start serving the API
+	o.stateEng.SetLazyStartUp(ifaceMgr)
 
 	deviceMgr, err := devicestate.Manager(s, hookMgr, o.runner, o.newStore)
 	if err != nil {
@@ -179,6 +188,8 @@ func New(restartHandler restart.Handler) (*Overlord, error) {
 
 	o.addManager(cmdstate.Manager(s, o.runner))
 	o.addManager(snapshotstate.Manager(s, o.runner))
+	o.addManager(reportstate.Manager(s))
+	o.addManager(measurestate.Manager(s))
 
 	if err := configstateInit(s, hookMgr); err != nil {
 		return nil, err
@@ -197,9 +208,33 @@ func New(restartHandler restart.Handler) (*Overlord, error) {
 
 	snapstate.ReplaceStore(s, sto)
 
+	applyTimingsRetention(s)
+
 	return o, nil
 }
 
+// applyTimingsRetention configures how long timings are kept in the state
+// according to the debug.timings.retention core option.
+// Note that the state must be locked by the caller.
+func applyTimingsRetention(st *state.State) {
+	var retention string
+	tr := config.NewTransaction(st)
+	if err := tr.Get("core", "debug.timings.retention", &retention); err != nil && !config.IsNoOption(err) {
+		logger.Noticef("cannot get debug.timings.retention: %v", err)
+		return
+	}
+	var maxAge time.Duration
+	if retention != "" {
+		var err error
+		maxAge, err = time.ParseDuration(retention)
+		if err != nil {
+			logger.Noticef("cannot parse debug.timings.retention: %v", err)
+			return
+		}
+	}
+	timings.MaxAge = maxAge
+}
+
 func (o *Overlord) addManager(mgr StateManager) {
 	switch x := mgr.(type) {
 	case *hookstate.HookManager:
@@ -350,12 +385,14 @@ func (o *Overlord) newStore(devBE storecontext.DeviceBackend) snapstate.StoreSer
 	return o.newStoreWithContext(stoCtx)
 }
 
-// StartUp proceeds to run any expensive Overlord or managers initialization. After this is done once it is a noop.
-func (o *Overlord) StartUp() error {
-	if o.startedUp {
+// StartUpCritical proceeds to run any expensive Overlord initialization and
+// that of the managers which is required before serving the API, see
+// StateEngine.StartUpCritical. After this is done once it is a noop.
+func (o *Overlord) StartUpCritical() error {
+	if o.criticalStartedUp {
 		return nil
 	}
-	o.startedUp = true
+	o.criticalStartedUp = true
 
 	// account for deviceMgr == nil as it's not always present in
 	// the tests.
@@ -379,9 +416,29 @@ func (o *Overlord) StartUp() error {
 		}
 	}
 
+	return o.stateEng.StartUpCritical()
+}
+
+// StartUp proceeds to run any expensive Overlord or managers initialization,
+// including the one performed by StartUpCritical. After this is done once it
+// is a noop.
+func (o *Overlord) StartUp() error {
+	if o.startedUp {
+		return nil
+	}
+	o.startedUp = true
+
+	if err := o.StartUpCritical(); err != nil {
+		return err
+	}
 	return o.stateEng.StartUp()
 }
 
+// StartUpStatus returns the status of the initialization of the managers.
+func (o *Overlord) StartUpStatus() []StartUpStatus {
+	return o.stateEng.StartUpStatus()
+}
+
 // StartupTimeout computes a usable timeout for the startup
 // initializations by using a pessimistic estimate.
 func (o *Overlord) StartupTimeout() (timeout time.Duration, reasoning string, err error) {
@@ -486,6 +543,8 @@ func (o *Overlord) Loop() {
 				st := o.State()
 				st.Lock()
 				st.Prune(o.startOfOperationTime, pruneWait, abortWait, pruneMaxChanges)
+				// pick up changes of the timings retention
+				applyTimingsRetention(st)
 				st.Unlock()
 			}
 		}
@@ -40,6 +40,7 @@ import (
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/overlord"
+	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/devicestate/devicestatetest"
 	"github.com/snapcore/snapd/overlord/hookstate"
 	"github.com/snapcore/snapd/overlord/ifacestate"
@@ -558,6 +559,36 @@ func (ovs *overlordSuite) TestEnsureLoopMediatedEnsureBeforeOutsideEnsure(c *C)
 	}
 }
 
+func (ovs *overlordSuite) TestApplyTimingsRetention(c *C) {
+	defer func(old time.Duration) { timings.MaxAge = old }(timings.MaxAge)
+
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	overlord.ApplyTimingsRetention(st)
+	c.Check(timings.MaxAge, Equals, time.Duration(0))
+
+	tr := config.NewTransaction(st)
+	c.Assert(tr.Set("core", "debug.timings.retention", "72h"), IsNil)
+	tr.Commit()
+	overlord.ApplyTimingsRetention(st)
+	c.Check(timings.MaxAge, Equals, 72*time.Hour)
+
+	// invalid values are ignored
+	tr = config.NewTransaction(st)
+	c.Assert(tr.Set("core", "debug.timings.retention", "invalid"), IsNil)
+	tr.Commit()
+	overlord.ApplyTimingsRetention(st)
+	c.Check(timings.MaxAge, Equals, 72*time.Hour)
+
+	tr = config.NewTransaction(st)
+	c.Assert(tr.Set("core", "debug.timings.retention", ""), IsNil)
+	tr.Commit()
+	overlord.ApplyTimingsRetention(st)
+	c.Check(timings.MaxAge, Equals, time.Duration(0))
+}
+
 func (ovs *overlordSuite) TestEnsureLoopPrune(c *C) {
 	restoreIntv := overlord.MockPruneInterval(200*time.Millisecond, 1000*time.Millisecond, 1000*time.Millisecond)
 	defer restoreIntv()
@@ -0,0 +1,69 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package reportstate
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/snapcore/snapd/httputil"
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+func MockConfinementDenials(f func(snapNames []string, since time.Time) ([]*denials.Denial, error)) (restore func()) {
+	old := confinementDenials
+	confinementDenials = f
+	return func() {
+		confinementDenials = old
+	}
+}
+
+func MockTimeNow(f func() time.Time) (restore func()) {
+	old := timeNow
+	timeNow = f
+	return func() {
+		timeNow = old
+	}
+}
+
+func MockMaxReports(n int) (restore func()) {
+	old := maxReports
+	maxReports = n
+	return func() {
+		maxReports = old
+	}
+}
+
+func MockNewHTTPClient(f func(opts *httputil.ClientOptions) *http.Client) (restore func()) {
+	old := newHTTPClient
+	newHTTPClient = f
+	return func() {
+		newHTTPClient = old
+	}
+}
+
+func MockSensitiveValues(f func(st *state.State) ([]string, error)) (restore func()) {
+	old := sensitiveValues
+	sensitiveValues = f
+	return func() {
+		sensitiveValues = old
+	}
+}
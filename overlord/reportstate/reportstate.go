@@ -0,0 +1,485 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package reportstate implements the manager that captures the context of
+// failed changes into local problem reports and optionally submits them.
+package reportstate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/httputil"
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/aspectstate"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/configstate/proxyconf"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
+)
+
+var (
+	// maxReports is the number of reports kept on disk, older reports
+	// are removed when new ones are captured.
+	maxReports = 50
+	// submitRetryInterval is the time to wait before trying to submit
+	// reports again after a submission failed.
+	submitRetryInterval = time.Hour
+	submitTimeout       = 30 * time.Second
+)
+
+// ErrReportNotFound is returned when there is no problem report with the
+// requested ID.
+var ErrReportNotFound = errors.New("problem report not found")
+
+// Report captures the context of a failed change.
+type Report struct {
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+
+	Change ChangeInfo `json:"change"`
+	Snaps  []SnapInfo `json:"snaps,omitempty"`
+	// Denials holds the confinement denials of the snaps in the change
+	// that were logged while the change was running.
+	Denials []*denials.Summary `json:"denials,omitempty"`
+
+	SubmittedAt *time.Time `json:"submitted-at,omitempty"`
+}
+
+// ChangeInfo holds the details of the failed change.
+type ChangeInfo struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"`
+	Summary   string     `json:"summary"`
+	Status    string     `json:"status"`
+	Err       string     `json:"err,omitempty"`
+	SpawnTime time.Time  `json:"spawn-time"`
+	ReadyTime time.Time  `json:"ready-time,omitempty"`
+	Tasks     []TaskInfo `json:"tasks"`
+}
+
+// TaskInfo holds the details of one task of the failed change.
+type TaskInfo struct {
+	Kind    string   `json:"kind"`
+	Summary string   `json:"summary"`
+	Status  string   `json:"status"`
+	Log     []string `json:"log,omitempty"`
+}
+
+// SnapInfo holds the version of a snap involved in the failed change.
+type SnapInfo struct {
+	Name        string        `json:"name"`
+	Version     string        `json:"version,omitempty"`
+	Revision    snap.Revision `json:"revision"`
+	Confinement string        `json:"confinement,omitempty"`
+}
+
+// ReportManager captures problem reports for failed changes if reporting
+// was enabled through the problem-reports.enabled core option, and submits
+// them to problem-reports.endpoint if that is set.
+type ReportManager struct {
+	state *state.State
+
+	lastSubmitFailure time.Time
+}
+
+// Manager returns a new ReportManager.
+func Manager(st *state.State) *ReportManager {
+	m := &ReportManager{state: st}
+
+	st.Lock()
+	defer st.Unlock()
+	st.AddChangeStatusChangedHandler(m.changeStatusChanged)
+
+	return m
+}
+
+func reportingEnabled(st *state.State) bool {
+	var enabled bool
+	tr := config.NewTransaction(st)
+	if err := tr.Get("core", "problem-reports.enabled", &enabled); err != nil && !config.IsNoOption(err) {
+		logger.Noticef("cannot get problem-reports.enabled: %v", err)
+		return false
+	}
+	return enabled
+}
+
+func submissionEndpoint(st *state.State) (string, error) {
+	var endpoint string
+	tr := config.NewTransaction(st)
+	if err := tr.Get("core", "problem-reports.endpoint", &endpoint); err != nil && !config.IsNoOption(err) {
+		return "", err
+	}
+	return endpoint, nil
+}
+
+// changeStatusChanged marks the changes that failed as pending to be
+// reported, the reports are captured in Ensure as that involves i/o.
+func (m *ReportManager) changeStatusChanged(chg *state.Change, old, new state.Status) {
+	if new != state.ErrorStatus || old == state.ErrorStatus {
+		return
+	}
+	if !reportingEnabled(m.state) {
+		return
+	}
+
+	pending, err := pendingReports(m.state)
+	if err != nil {
+		logger.Noticef("cannot get pending problem reports: %v", err)
+		return
+	}
+	if strutil.ListContains(pending, chg.ID()) {
+		return
+	}
+	m.state.Set("pending-problem-reports", append(pending, chg.ID()))
+	m.state.EnsureBefore(0)
+}
+
+func pendingReports(st *state.State) ([]string, error) {
+	var pending []string
+	if err := st.Get("pending-problem-reports", &pending); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// Ensure is part of the overlord.StateManager interface.
+func (m *ReportManager) Ensure() error {
+	if err := m.capturePending(); err != nil {
+		return err
+	}
+	return m.submitReports()
+}
+
+func (m *ReportManager) capturePending() error {
+	st := m.state
+	st.Lock()
+	pending, err := pendingReports(st)
+	if err != nil || len(pending) == 0 {
+		st.Unlock()
+		return err
+	}
+
+	reports := make([]*Report, 0, len(pending))
+	for _, chgID := range pending {
+		chg := st.Change(chgID)
+		if chg == nil {
+			continue
+		}
+		reports = append(reports, newReport(st, chg))
+	}
+	sensitive, err := sensitiveValues(st)
+	if err != nil {
+		// reports cannot be scrubbed reliably, drop them
+		logger.Noticef("cannot capture problem reports: %v", err)
+		reports = nil
+	}
+	st.Set("pending-problem-reports", nil)
+	st.Unlock()
+
+	for _, report := range reports {
+		names := make([]string, 0, len(report.Snaps))
+		for _, sn := range report.Snaps {
+			names = append(names, sn.Name)
+		}
+		snapDenials, err := confinementDenials(names, report.Change.SpawnTime)
+		if err != nil {
+			logger.Noticef("cannot get confinement denials for problem report %s: %v", report.ID, err)
+		}
+		report.Denials = denials.Aggregate(snapDenials)
+
+		scrubReport(report, sensitive)
+		if err := writeReport(report); err != nil {
+			logger.Noticef("cannot save problem report %s: %v", report.ID, err)
+			continue
+		}
+		logger.Noticef("Captured problem report %s for change %s", report.ID, report.Change.ID)
+	}
+
+	if err := pruneReports(); err != nil {
+		logger.Noticef("cannot remove old problem reports: %v", err)
+	}
+	return nil
+}
+
+var (
+	timeNow         = time.Now
+	newHTTPClient   = httputil.NewHTTPClient
+	sensitiveValues = aspectstate.SensitiveValues
+)
+
+func newReport(st *state.State, chg *state.Change) *Report {
+	report := &Report{
+		ID:   chg.ID(),
+		Time: timeNow(),
+		Change: ChangeInfo{
+			ID:        chg.ID(),
+			Kind:      chg.Kind(),
+			Summary:   chg.Summary(),
+			Status:    chg.Status().String(),
+			SpawnTime: chg.SpawnTime(),
+			ReadyTime: chg.ReadyTime(),
+		},
+	}
+	if err := chg.Err(); err != nil {
+		report.Change.Err = err.Error()
+	}
+
+	var names []string
+	for _, t := range chg.Tasks() {
+		report.Change.Tasks = append(report.Change.Tasks, TaskInfo{
+			Kind:    t.Kind(),
+			Summary: t.Summary(),
+			Status:  t.Status().String(),
+			Log:     t.Log(),
+		})
+
+		snapsup, err := snapstate.TaskSnapSetup(t)
+		if err != nil {
+			continue
+		}
+		if name := snapsup.InstanceName(); !strutil.ListContains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		sn := SnapInfo{Name: name}
+		var snapst snapstate.SnapState
+		if err := snapstate.Get(st, name, &snapst); err == nil && snapst.IsInstalled() {
+			sn.Revision = snapst.Current
+			if info, err := snapst.CurrentInfo(); err == nil {
+				sn.Version = info.Version
+				sn.Confinement = string(info.Confinement)
+			}
+		}
+		report.Snaps = append(report.Snaps, sn)
+	}
+
+	return report
+}
+
+// minSensitiveLen is the minimum length of the sensitive values that are
+// scrubbed from reports, shorter values would hide too much of the report
+// to be of use.
+const minSensitiveLen = 4
+
+var urlPasswordRx = regexp.MustCompile(`(://[^/:@\s]+):[^/@\s]+@`)
+
+// scrubReport removes sensitive data from the free form text of the report.
+func scrubReport(report *Report, sensitive []string) {
+	var oldnew []string
+	sort.Slice(sensitive, func(i, j int) bool { return len(sensitive[i]) > len(sensitive[j]) })
+	for _, value := range sensitive {
+		if len(value) >= minSensitiveLen {
+			oldnew = append(oldnew, value, "***")
+		}
+	}
+	replacer := strings.NewReplacer(oldnew...)
+	scrub := func(s string) string {
+		return urlPasswordRx.ReplaceAllString(replacer.Replace(s), "$1:***@")
+	}
+
+	report.Change.Summary = scrub(report.Change.Summary)
+	report.Change.Err = scrub(report.Change.Err)
+	for i := range report.Change.Tasks {
+		task := &report.Change.Tasks[i]
+		task.Summary = scrub(task.Summary)
+		for j := range task.Log {
+			task.Log[j] = scrub(task.Log[j])
+		}
+	}
+	for _, denial := range report.Denials {
+		denial.Target = scrub(denial.Target)
+	}
+}
+
+var confinementDenials = func(snapNames []string, since time.Time) ([]*denials.Denial, error) {
+	if len(snapNames) == 0 {
+		return nil, nil
+	}
+
+	all, err := denials.Read(since)
+	if err != nil {
+		return nil, err
+	}
+	var snapDenials []*denials.Denial
+	for _, denial := range all {
+		if strutil.ListContains(snapNames, denial.Snap) {
+			snapDenials = append(snapDenials, denial)
+		}
+	}
+	return snapDenials, nil
+}
+
+func reportPath(id string) string {
+	return filepath.Join(dirs.SnapProblemReportsDir, id+".json")
+}
+
+func writeReport(report *Report) error {
+	if err := os.MkdirAll(dirs.SnapProblemReportsDir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	return osutil.AtomicWriteFile(reportPath(report.ID), data, 0600, 0)
+}
+
+func readReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("cannot decode problem report %q: %v", path, err)
+	}
+	return &report, nil
+}
+
+// Reports returns the problem reports saved on disk, sorted by the time
+// they were captured.
+func Reports() ([]*Report, error) {
+	paths, err := filepath.Glob(filepath.Join(dirs.SnapProblemReportsDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*Report, 0, len(paths))
+	for _, path := range paths {
+		report, err := readReport(path)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].Time.Before(reports[j].Time) })
+	return reports, nil
+}
+
+// ReportByID returns the problem report with the given ID.
+func ReportByID(id string) (*Report, error) {
+	// change IDs are numeric, anything else cannot be a report
+	if id == "" || strings.ContainsAny(id, "/.") {
+		return nil, ErrReportNotFound
+	}
+	report, err := readReport(reportPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrReportNotFound
+	}
+	return report, err
+}
+
+func pruneReports() error {
+	reports, err := Reports()
+	if err != nil {
+		return err
+	}
+	for len(reports) > maxReports {
+		if err := os.Remove(reportPath(reports[0].ID)); err != nil {
+			return err
+		}
+		reports = reports[1:]
+	}
+	return nil
+}
+
+// submitReports submits the reports that were not submitted yet to the
+// configured endpoint.
+func (m *ReportManager) submitReports() error {
+	st := m.state
+	st.Lock()
+	endpoint, err := submissionEndpoint(st)
+	st.Unlock()
+	if err != nil {
+		return err
+	}
+	if endpoint == "" {
+		return nil
+	}
+
+	reports, err := Reports()
+	if err != nil {
+		return err
+	}
+	var unsubmitted []*Report
+	for _, report := range reports {
+		if report.SubmittedAt == nil {
+			unsubmitted = append(unsubmitted, report)
+		}
+	}
+	if len(unsubmitted) == 0 {
+		return nil
+	}
+
+	if timeNow().Sub(m.lastSubmitFailure) < submitRetryInterval {
+		return nil
+	}
+
+	client := newHTTPClient(&httputil.ClientOptions{
+		Timeout: submitTimeout,
+		Proxy:   proxyconf.New(st).Conf,
+	})
+	for _, report := range unsubmitted {
+		if err := submitReport(client, endpoint, report); err != nil {
+			logger.Noticef("cannot submit problem report %s: %v", report.ID, err)
+			// try again later
+			m.lastSubmitFailure = timeNow()
+			return nil
+		}
+		submittedAt := timeNow()
+		report.SubmittedAt = &submittedAt
+		if err := writeReport(report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func submitReport(client *http.Client, endpoint string, report *Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
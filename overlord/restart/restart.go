@@ -592,6 +592,12 @@ func processRestartForChange(chg *state.Change, old, new state.Status) {
 		return
 	}
 
+	// a system restart is scheduled and will serve this change too
+	if !release.OnClassic && rp.RestartType == RestartSystem && systemRestartsPostponed(chg.State()) {
+		logger.Noticef("Postponing restart of change %s until the scheduled system restart", chg.ID())
+		return
+	}
+
 	// clear out the restart context for this change before restarting
 	chg.Set("pending-system-restart", nil)
 
@@ -607,6 +613,61 @@ func processRestartForChange(chg *state.Change, old, new state.Status) {
 	Request(chg.State(), rp.RestartType, &boot.RebootInfo{RebootRequired: true, BootloaderOptions: rp.BootloaderOptions})
 }
 
+// PostponeSystemRestarts postpones the system restarts requested by changes
+// until either ResumeSystemRestarts or DropPostponedSystemRestarts is called,
+// e.g. because a system restart is scheduled that will serve them too. The
+// changes wait for a restart in the meantime. Immediate restarts are not
+// affected.
+func PostponeSystemRestarts(st *state.State) {
+	st.Set("postponed-system-restarts", true)
+}
+
+func systemRestartsPostponed(st *state.State) bool {
+	var postponed bool
+	if err := st.Get("postponed-system-restarts", &postponed); err != nil && !errors.Is(err, state.ErrNoState) {
+		logger.Noticef("internal error: cannot get postponed system restarts: %v", err)
+	}
+	return postponed
+}
+
+func changesWithPostponedRestart(st *state.State) []*state.Change {
+	var chgs []*state.Change
+	for _, chg := range st.Changes() {
+		if chg.IsReady() || chg.Status() != state.WaitStatus {
+			continue
+		}
+		if !chg.Has("pending-system-restart") {
+			continue
+		}
+		chgs = append(chgs, chg)
+	}
+	return chgs
+}
+
+// ResumeSystemRestarts stops postponing system restarts and requests the
+// restart that was postponed in the meantime, if any.
+func ResumeSystemRestarts(st *state.State) {
+	st.Set("postponed-system-restarts", nil)
+	for _, chg := range changesWithPostponedRestart(st) {
+		if pending, _ := Pending(st); pending {
+			// the restart requested already serves this change
+			chg.Set("pending-system-restart", nil)
+			continue
+		}
+		processRestartForChange(chg, state.WaitStatus, state.WaitStatus)
+	}
+}
+
+// DropPostponedSystemRestarts stops postponing system restarts without
+// requesting the ones postponed in the meantime, as the restart the caller is
+// about to request serves them too.
+func DropPostponedSystemRestarts(st *state.State) {
+	st.Set("postponed-system-restarts", nil)
+	for _, chg := range changesWithPostponedRestart(st) {
+		chg.Set("pending-system-restart", nil)
+	}
+}
+
 // MockAfterRestartForChange is added solely for unit test purposes, to help simulate restarts.
 func MockAfterRestartForChange(chg *state.Change) {
 	osutil.MustBeTestBinary("MockRestartForChange is only added for test purposes.")
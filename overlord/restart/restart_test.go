@@ -380,6 +380,100 @@ func (s *restartSuite) TestProcessRestartForChangeCore(c *C) {
 	c.Check(h.rebootInfo.RebootRequired, Equals, true)
 }
 
+func (s *restartSuite) mockChangeWaitingForRestart(c *C, st *state.State) *state.Change {
+	chg := st.NewChange("test", "...")
+	t := st.NewTask("waiting", "...")
+	chg.AddTask(t)
+
+	restart.MarkTaskAsRestartBoundary(t, restart.RestartBoundaryDirectionDo)
+	err := restart.FinishTaskWithRestart(t, state.DoneStatus, restart.RestartSystem, "some-snap", nil)
+	c.Assert(err, IsNil)
+	c.Check(t.Status(), Equals, state.WaitStatus)
+	return chg
+}
+
+func (s *restartSuite) TestPostponeAndResumeSystemRestarts(c *C) {
+	restore := release.MockOnClassic(false)
+	defer restore()
+
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	h := &testHandler{}
+	_, err := restart.Manager(st, "boot-id-1", h)
+	c.Assert(err, IsNil)
+
+	restart.PostponeSystemRestarts(st)
+
+	chg1 := s.mockChangeWaitingForRestart(c, st)
+	chg2 := s.mockChangeWaitingForRestart(c, st)
+	restart.ProcessRestartForChange(chg1, state.DefaultStatus, state.WaitStatus)
+	restart.ProcessRestartForChange(chg2, state.DefaultStatus, state.WaitStatus)
+	c.Check(h.restartRequested, Equals, false)
+	c.Check(chg1.Has("pending-system-restart"), Equals, true)
+	c.Check(chg2.Has("pending-system-restart"), Equals, true)
+
+	restart.ResumeSystemRestarts(st)
+	c.Check(h.restartRequested, Equals, true)
+	c.Check(h.restartType, Equals, restart.RestartSystem)
+	c.Check(chg1.Has("pending-system-restart"), Equals, false)
+	c.Check(chg2.Has("pending-system-restart"), Equals, false)
+	c.Check(st.Get("postponed-system-restarts", new(bool)), testutil.ErrorIs, state.ErrNoState)
+}
+
+func (s *restartSuite) TestPostponeSystemRestartsNotImmediate(c *C) {
+	restore := release.MockOnClassic(false)
+	defer restore()
+
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	h := &testHandler{}
+	_, err := restart.Manager(st, "boot-id-1", h)
+	c.Assert(err, IsNil)
+
+	restart.PostponeSystemRestarts(st)
+
+	chg := st.NewChange("test", "...")
+	t := st.NewTask("waiting", "...")
+	chg.AddTask(t)
+	err = restart.FinishTaskWithRestart(t, state.DoneStatus, restart.RestartSystemNow, "some-snap", nil)
+	c.Assert(err, IsNil)
+
+	restart.ProcessRestartForChange(chg, state.DefaultStatus, state.WaitStatus)
+	c.Check(h.restartRequested, Equals, true)
+	c.Check(h.restartType, Equals, restart.RestartSystemNow)
+}
+
+func (s *restartSuite) TestDropPostponedSystemRestarts(c *C) {
+	restore := release.MockOnClassic(false)
+	defer restore()
+
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	h := &testHandler{}
+	_, err := restart.Manager(st, "boot-id-1", h)
+	c.Assert(err, IsNil)
+
+	restart.PostponeSystemRestarts(st)
+
+	chg := s.mockChangeWaitingForRestart(c, st)
+	restart.ProcessRestartForChange(chg, state.DefaultStatus, state.WaitStatus)
+	c.Check(h.restartRequested, Equals, false)
+
+	restart.DropPostponedSystemRestarts(st)
+	c.Check(h.restartRequested, Equals, false)
+	c.Check(chg.Has("pending-system-restart"), Equals, false)
+	c.Check(st.Get("postponed-system-restarts", new(bool)), testutil.ErrorIs, state.ErrNoState)
+
+	// the change still waits for the restart to happen
+	c.Check(chg.Status(), Equals, state.WaitStatus)
+}
+
 func (s *restartSuite) TestProcessRestartForChangeMissingRebootContext(c *C) {
 	ml, restore := logger.MockLogger()
 	defer restore()
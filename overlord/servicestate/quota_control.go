@@ -29,6 +29,7 @@ import (
 	"github.com/snapcore/snapd/overlord/servicestate/internal"
 	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/sandbox"
 	"github.com/snapcore/snapd/snap/quota"
 	"github.com/snapcore/snapd/snapdenv"
 	"github.com/snapcore/snapd/systemd"
@@ -70,6 +71,9 @@ func quotaGroupsAvailable(st *state.State) error {
 	if systemdVersionError != nil {
 		return fmt.Errorf("cannot use quotas with incompatible systemd: %v", systemdVersionError)
 	}
+	if reason, degraded := sandbox.Degraded(sandbox.FeatureQuotaGroups); degraded {
+		return fmt.Errorf("cannot use quotas: %s", reason)
+	}
 	return nil
 }
 
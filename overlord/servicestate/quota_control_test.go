@@ -36,6 +36,7 @@ import (
 	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
 	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/sandbox"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/quota"
 	"github.com/snapcore/snapd/snap/snaptest"
@@ -319,6 +320,22 @@ func (s *quotaControlSuite) TestCreateQuotaSystemdTooOld(c *C) {
 	c.Assert(err, ErrorMatches, `cannot use quotas with incompatible systemd: systemd version 229 is too old \(expected at least 230\)`)
 }
 
+func (s *quotaControlSuite) TestCreateQuotaCgroupNotDelegated(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	r := sandbox.MockDegradedFeatures([]sandbox.DegradedFeature{{
+		Feature: sandbox.FeatureQuotaGroups,
+		Reason:  "the cgroup hierarchy is not delegated to the container",
+	}})
+	defer r()
+
+	_, err := servicestate.CreateQuota(s.state, "foo", servicestate.CreateQuotaOptions{
+		ResourceLimits: quota.NewResourcesBuilder().WithMemoryLimit(quantity.SizeGiB).Build(),
+	})
+	c.Assert(err, ErrorMatches, `cannot use quotas: the cgroup hierarchy is not delegated to the container`)
+}
+
 func (s *quotaControlSuite) TestCreateQuotaPerQuotaSystemdTooOld(c *C) {
 	s.state.Lock()
 	defer s.state.Unlock()
@@ -303,6 +303,20 @@ func EstimateSnapshotSize(si *snap.Info, usernames []string, dirOpts *dirs.SnapD
 
 // Save a snapshot
 func Save(ctx context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, dynSnapshotOpts *snap.SnapshotOptions, dirOpts *dirs.SnapDirOptions) (*client.Snapshot, error) {
+	return SaveCompressed(ctx, id, si, cfg, usernames, "", dynSnapshotOpts, dirOpts)
+}
+
+// SaveCompressed saves a snapshot whose archives use the given
+// compression, either "gzip" or "zstd". An empty compression means gzip.
+func SaveCompressed(ctx context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, compression string, dynSnapshotOpts *snap.SnapshotOptions, dirOpts *dirs.SnapDirOptions) (*client.Snapshot, error) {
+	if compression == "gzip" {
+		// gzip is the default and is not recorded, for compatibility
+		// with older versions of snapd
+		compression = ""
+	}
+	if _, err := compressionTarFlag(compression); err != nil {
+		return nil, err
+	}
 	if err := os.MkdirAll(dirs.SnapshotsDir, 0700); err != nil {
 		return nil, err
 	}
@@ -322,6 +336,8 @@ func Save(ctx context.Context, id uint64, si *snap.Info, cfg map[string]interfac
 		Size:     0,
 		Conf:     cfg,
 		// Note: Auto is no longer set in the Snapshot.
+
+		Compression: compression,
 	}
 
 	snapshotOptions, err := snapReadSnapshotYaml(si)
@@ -453,9 +469,13 @@ func addToZip(ctx context.Context, snapshot *client.Snapshot, w *zip.Writer, use
 		return err
 	}
 
+	compressionFlag, err := compressionTarFlag(snapshot.Compression)
+	if err != nil {
+		return err
+	}
 	tarArgs := []string{
 		"--create",
-		"--sparse", "--gzip",
+		"--sparse", compressionFlag,
 		"--format", "gnu",
 		"--anchored",
 		"--no-wildcards-match-slash",
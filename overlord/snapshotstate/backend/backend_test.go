@@ -874,6 +874,190 @@ func (s *snapshotSuite) TestRestoreRoundtripDifferentRevision(c *check.C) {
 	c.Check(diff().Run(), check.IsNil)
 }
 
+func (s *snapshotSuite) TestRestoreToInstance(c *check.C) {
+	if os.Geteuid() == 0 {
+		c.Skip("this test cannot run as root (runuser will fail)")
+	}
+	logger.SimpleSetup()
+
+	info := &snap.Info{SideInfo: snap.SideInfo{RealName: "hello-snap", Revision: snap.R(42), SnapID: "hello-id"}, Version: "v1.33"}
+	shw, err := backend.Save(context.TODO(), 12, info, nil, []string{"snapuser"}, nil, nil)
+	c.Assert(err, check.IsNil)
+
+	shr, err := backend.Open(backend.Filename(shw), backend.ExtractFnameSetID)
+	c.Assert(err, check.IsNil)
+	defer shr.Close()
+
+	target := &client.SnapshotRestoreTarget{Instance: "hello-snap_staging"}
+	rs, err := shr.RestoreTo(context.TODO(), snap.R(0), nil, target, logger.Debugf, nil)
+	c.Assert(err, check.IsNil)
+	rs.Cleanup()
+
+	staging := snap.MinimalPlaceInfo("hello-snap_staging", snap.R(42))
+	home := filepath.Join(dirs.GlobalRootDir, "home/snapuser")
+	for _, t := range table(staging, home) {
+		c.Check(filepath.Join(t.dir, t.name), testutil.FileEquals, t.content)
+	}
+	// the original instance is untouched
+	for _, t := range table(info, home) {
+		c.Check(filepath.Join(t.dir, t.name), testutil.FileEquals, t.content)
+	}
+}
+
+func (s *snapshotSuite) TestRestoreToUser(c *check.C) {
+	if os.Geteuid() == 0 {
+		c.Skip("this test cannot run as root (runuser will fail)")
+	}
+	logger.SimpleSetup()
+
+	cur, err := user.Current()
+	c.Assert(err, check.IsNil)
+	defer backend.MockUserLookup(func(username string) (*user.User, error) {
+		rv := *cur
+		rv.Username = username
+		rv.HomeDir = filepath.Join(dirs.GlobalRootDir, "home", username)
+		return &rv, nil
+	})()
+
+	info := &snap.Info{SideInfo: snap.SideInfo{RealName: "hello-snap", Revision: snap.R(42), SnapID: "hello-id"}, Version: "v1.33"}
+	shw, err := backend.Save(context.TODO(), 12, info, nil, []string{"snapuser"}, nil, nil)
+	c.Assert(err, check.IsNil)
+
+	shr, err := backend.Open(backend.Filename(shw), backend.ExtractFnameSetID)
+	c.Assert(err, check.IsNil)
+	defer shr.Close()
+
+	otherHome := filepath.Join(dirs.GlobalRootDir, "home/otheruser")
+	c.Assert(os.MkdirAll(otherHome, 0755), check.IsNil)
+	// dirty the system data, which is not touched by the restore
+	c.Assert(os.WriteFile(filepath.Join(info.DataDir(), "foo"), []byte("scribble\n"), 0644), check.IsNil)
+
+	target := &client.SnapshotRestoreTarget{User: "otheruser"}
+	rs, err := shr.RestoreTo(context.TODO(), snap.R(0), []string{"snapuser"}, target, logger.Debugf, nil)
+	c.Assert(err, check.IsNil)
+	rs.Cleanup()
+
+	c.Check(filepath.Join(info.UserDataDir(otherHome, nil), "ufoo"), testutil.FileEquals, "versioned user canary\n")
+	c.Check(filepath.Join(info.UserCommonDataDir(otherHome, nil), "ubar"), testutil.FileEquals, "common user canary\n")
+	c.Check(filepath.Join(info.DataDir(), "foo"), testutil.FileEquals, "scribble\n")
+}
+
+func (s *snapshotSuite) TestRestoreToErrors(c *check.C) {
+	shr := &backend.Reader{Snapshot: client.Snapshot{Snap: "hello-snap", Revision: snap.R(42)}}
+
+	_, err := shr.RestoreTo(context.TODO(), snap.R(0), nil, &client.SnapshotRestoreTarget{Instance: "other-snap_foo"}, logger.Debugf, nil)
+	c.Check(err, check.ErrorMatches, `cannot restore snapshot of "hello-snap" into "other-snap_foo": not an instance of the same snap`)
+
+	_, err = shr.RestoreTo(context.TODO(), snap.R(0), nil, &client.SnapshotRestoreTarget{User: "otheruser"}, logger.Debugf, nil)
+	c.Check(err, check.ErrorMatches, `cannot restore snapshot of "hello-snap" onto user "otheruser": exactly one user to restore must be given`)
+
+	_, err = shr.RestoreTo(context.TODO(), snap.R(0), []string{"a", "b"}, &client.SnapshotRestoreTarget{User: "otheruser"}, logger.Debugf, nil)
+	c.Check(err, check.ErrorMatches, `cannot restore snapshot of "hello-snap" onto user "otheruser": exactly one user to restore must be given`)
+}
+
+func (s *snapshotSuite) TestZstdRoundtrip(c *check.C) {
+	if os.Geteuid() == 0 {
+		c.Skip("this test cannot run as root (runuser will fail)")
+	}
+	if !osutil.ExecutableExists("zstd") {
+		c.Skip("zstd is not available")
+	}
+	logger.SimpleSetup()
+
+	info := &snap.Info{SideInfo: snap.SideInfo{RealName: "hello-snap", Revision: snap.R(42), SnapID: "hello-id"}, Version: "v1.33"}
+	shw, err := backend.SaveCompressed(context.TODO(), 12, info, nil, []string{"snapuser"}, "zstd", nil, nil)
+	c.Assert(err, check.IsNil)
+	c.Check(shw.Compression, check.Equals, "zstd")
+
+	shr, err := backend.Open(backend.Filename(shw), backend.ExtractFnameSetID)
+	c.Assert(err, check.IsNil)
+	defer shr.Close()
+	c.Check(shr.Compression, check.Equals, "zstd")
+	c.Check(shr.Check(context.TODO(), nil), check.IsNil)
+
+	files, err := shr.Files(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Check(files, check.HasLen, 4)
+
+	f, _, err := shr.OpenFile("snapuser", "common/ubar")
+	c.Assert(err, check.IsNil)
+	data, err := io.ReadAll(f)
+	c.Assert(err, check.IsNil)
+	c.Check(f.Close(), check.IsNil)
+	c.Check(string(data), check.Equals, "common user canary\n")
+
+	home := filepath.Join(dirs.GlobalRootDir, "home/snapuser")
+	for _, t := range table(info, home) {
+		c.Assert(os.WriteFile(filepath.Join(t.dir, t.name), []byte("changed\n"), 0644), check.IsNil)
+	}
+	rs, err := shr.Restore(context.TODO(), snap.R(0), nil, logger.Debugf, nil)
+	c.Assert(err, check.IsNil)
+	rs.Cleanup()
+	for _, t := range table(info, home) {
+		c.Check(filepath.Join(t.dir, t.name), testutil.FileEquals, t.content)
+	}
+}
+
+func (s *snapshotSuite) TestSaveCompressedUnsupported(c *check.C) {
+	info := &snap.Info{SideInfo: snap.SideInfo{RealName: "hello-snap", Revision: snap.R(42), SnapID: "hello-id"}, Version: "v1.33"}
+	_, err := backend.SaveCompressed(context.TODO(), 12, info, nil, nil, "xz", nil, nil)
+	c.Check(err, check.ErrorMatches, `unsupported snapshot compression "xz"`)
+}
+
+func (s *snapshotSuite) TestFilesAndOpenFile(c *check.C) {
+	if os.Geteuid() == 0 {
+		c.Skip("this test cannot run as root (runuser will fail)")
+	}
+	logger.SimpleSetup()
+
+	info := &snap.Info{SideInfo: snap.SideInfo{RealName: "hello-snap", Revision: snap.R(42), SnapID: "hello-id"}, Version: "v1.33"}
+	shw, err := backend.Save(context.TODO(), 12, info, nil, []string{"snapuser"}, nil, nil)
+	c.Assert(err, check.IsNil)
+
+	shr, err := backend.Open(backend.Filename(shw), backend.ExtractFnameSetID)
+	c.Assert(err, check.IsNil)
+	defer shr.Close()
+
+	files, err := shr.Files(context.TODO())
+	c.Assert(err, check.IsNil)
+	var found []string
+	for _, f := range files {
+		c.Check(f.Snap, check.Equals, "hello-snap")
+		c.Check(f.Mtime.IsZero(), check.Equals, false)
+		found = append(found, fmt.Sprintf("%s:%s:%d", f.User, f.Path, f.Size))
+	}
+	sort.Strings(found)
+	c.Check(found, check.DeepEquals, []string{
+		":42/foo:24",
+		":common/bar:21",
+		"snapuser:42/ufoo:22",
+		"snapuser:common/ubar:19",
+	})
+
+	f, size, err := shr.OpenFile("snapuser", "./common/ubar")
+	c.Assert(err, check.IsNil)
+	data, err := io.ReadAll(f)
+	c.Assert(err, check.IsNil)
+	c.Check(f.Close(), check.IsNil)
+	c.Check(size, check.Equals, int64(19))
+	c.Check(string(data), check.Equals, "common user canary\n")
+
+	f, _, err = shr.OpenFile("", "42/foo")
+	c.Assert(err, check.IsNil)
+	data, err = io.ReadAll(f)
+	c.Assert(err, check.IsNil)
+	c.Check(f.Close(), check.IsNil)
+	c.Check(string(data), check.Equals, "versioned system canary\n")
+
+	_, _, err = shr.OpenFile("", "42/ufoo")
+	c.Check(err, check.Equals, client.ErrSnapshotFileNotFound)
+	_, _, err = shr.OpenFile("otheruser", "42/ufoo")
+	c.Check(err, check.Equals, client.ErrSnapshotFileNotFound)
+	// directories are not files
+	_, _, err = shr.OpenFile("", "42")
+	c.Check(err, check.Equals, client.ErrSnapshotFileNotFound)
+}
+
 func (s *snapshotSuite) TestPickUserWrapperRunuser(c *check.C) {
 	n := 0
 	defer backend.MockExecLookPath(func(s string) (string, error) {
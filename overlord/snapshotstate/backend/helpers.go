@@ -21,6 +21,7 @@ package backend
 
 import (
 	"archive/zip"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
@@ -64,6 +65,55 @@ func zipMember(f *os.File, member string) (r io.ReadCloser, sz int64, err error)
 	return nil, -1, fmt.Errorf("missing archive member %q", member)
 }
 
+// compressionTarFlag returns the tar flag for creating or extracting
+// archives with the given snapshot compression.
+func compressionTarFlag(compression string) (string, error) {
+	switch compression {
+	case "", "gzip":
+		return "--gzip", nil
+	case "zstd":
+		return "--zstd", nil
+	default:
+		return "", fmt.Errorf("unsupported snapshot compression %q", compression)
+	}
+}
+
+type cmdOutput struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (o *cmdOutput) Close() error {
+	o.ReadCloser.Close()
+	// the rest of the output might not be needed, so don't wait for
+	// the command to finish on its own
+	o.cmd.Process.Kill()
+	o.cmd.Wait()
+	return nil
+}
+
+// decompress returns a reader of the decompressed data of an archive
+// with the given snapshot compression.
+func decompress(r io.Reader, compression string) (io.ReadCloser, error) {
+	switch compression {
+	case "", "gzip":
+		return gzip.NewReader(r)
+	case "zstd":
+		cmd := exec.Command("zstd", "--decompress", "--stdout", "--quiet")
+		cmd.Stdin = r
+		out, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("cannot decompress archive: %v", err)
+		}
+		return &cmdOutput{ReadCloser: out, cmd: cmd}, nil
+	default:
+		return nil, fmt.Errorf("unsupported snapshot compression %q", compression)
+	}
+}
+
 func userArchiveName(usr *user.User) string {
 	return filepath.Join(userArchivePrefix, usr.Username+userArchiveSuffix)
 }
@@ -20,6 +20,7 @@
 package backend
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
 	"crypto"
@@ -29,6 +30,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"syscall"
@@ -189,6 +191,148 @@ func (r *Reader) Check(ctx context.Context, usernames []string) error {
 	return nil
 }
 
+// isArchivedFile returns whether the archive entry with the given header is
+// a regular file (possibly stored sparsely).
+func isArchivedFile(hdr *tar.Header) bool {
+	return hdr.Typeflag == tar.TypeReg || hdr.Typeflag == tar.TypeGNUSparse
+}
+
+// walkArchive calls f for every entry of the given data archive of the
+// snapshot, until f returns false or an error.
+func (r *Reader) walkArchive(entry string, f func(hdr *tar.Header, body io.Reader) (more bool, err error)) error {
+	body, _, err := zipMember(r.File, entry)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	dec, err := decompress(body, r.Compression)
+	if err != nil {
+		return err
+	}
+	defer dec.Close()
+
+	tr := tar.NewReader(dec)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if more, err := f(hdr, tr); err != nil || !more {
+			return err
+		}
+	}
+}
+
+// Files lists the regular files contained in the data archives of the
+// snapshot, system data first and then that of each user, by username.
+func (r *Reader) Files(ctx context.Context) ([]client.SnapshotFile, error) {
+	entries := make([]string, 0, len(r.SHA3_384))
+	for entry := range r.SHA3_384 {
+		entries = append(entries, entry)
+	}
+	// archiveName sorts before all user archives
+	sort.Strings(entries)
+
+	files := []client.SnapshotFile{}
+	for _, entry := range entries {
+		var username string
+		if isUserArchive(entry) {
+			username = entryUsername(entry)
+		} else if entry != archiveName {
+			continue
+		}
+
+		err := r.walkArchive(entry, func(hdr *tar.Header, _ io.Reader) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+			if isArchivedFile(hdr) {
+				files = append(files, client.SnapshotFile{
+					Snap:  r.Snap,
+					User:  username,
+					Path:  path.Clean(hdr.Name),
+					Size:  hdr.Size,
+					Mtime: hdr.ModTime,
+				})
+			}
+			return true, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot list files of snapshot %q entry %q: %v", r.Name(), entry, err)
+		}
+	}
+
+	return files, nil
+}
+
+type archivedFile struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (f *archivedFile) Close() error {
+	var firstErr error
+	for _, c := range f.closers {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// OpenFile opens the regular file at the given path in the data archive of
+// the given user, or in the system data archive if username is empty, and
+// returns it along with its size. The caller must close the returned
+// file, which does not close the snapshot.
+func (r *Reader) OpenFile(username, filePath string) (file io.ReadCloser, size int64, e error) {
+	entry := archiveName
+	if username != "" {
+		entry = userArchivePrefix + username + userArchiveSuffix
+	}
+	if _, ok := r.SHA3_384[entry]; !ok {
+		return nil, -1, client.ErrSnapshotFileNotFound
+	}
+
+	body, _, err := zipMember(r.File, entry)
+	if err != nil {
+		return nil, -1, err
+	}
+	defer func() {
+		if e != nil {
+			body.Close()
+		}
+	}()
+
+	dec, err := decompress(body, r.Compression)
+	if err != nil {
+		return nil, -1, err
+	}
+	defer func() {
+		if e != nil {
+			dec.Close()
+		}
+	}()
+
+	filePath = path.Clean(filePath)
+	tr := tar.NewReader(dec)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, -1, client.ErrSnapshotFileNotFound
+		}
+		if err != nil {
+			return nil, -1, err
+		}
+		if isArchivedFile(hdr) && path.Clean(hdr.Name) == filePath {
+			return &archivedFile{Reader: tr, closers: []io.Closer{dec, body}}, hdr.Size, nil
+		}
+	}
+}
+
 // Logf is the type implemented by logging functions.
 type Logf func(format string, args ...interface{})
 
@@ -198,6 +342,39 @@ type Logf func(format string, args ...interface{})
 // or the one in the snapshot) with that contained in the snapshot. It keeps
 // track of the old data in the task so it can be undone (or cleaned up).
 func (r *Reader) Restore(ctx context.Context, current snap.Revision, usernames []string, logf Logf, opts *dirs.SnapDirOptions) (rs *RestoreState, e error) {
+	return r.RestoreTo(ctx, current, usernames, nil, logf, opts)
+}
+
+// RestoreTo restores the data from the snapshot like Restore, but into the
+// instance and/or for the user given by target, if not nil.
+//
+// A target instance must be an instance of the same snap; the snapshot data
+// is then placed in that instance's data directories. A target user requires
+// usernames to name exactly the one user whose data is restored; the system
+// data is left alone in this case, and the restored user data is owned by the
+// target user.
+func (r *Reader) RestoreTo(ctx context.Context, current snap.Revision, usernames []string, target *client.SnapshotRestoreTarget, logf Logf, opts *dirs.SnapDirOptions) (rs *RestoreState, e error) {
+	instanceName := r.Snap
+	var targetUser string
+	if target != nil {
+		if target.Instance != "" {
+			if snap.InstanceSnap(target.Instance) != snap.InstanceSnap(r.Snap) {
+				return nil, fmt.Errorf("cannot restore snapshot of %q into %q: not an instance of the same snap", r.Snap, target.Instance)
+			}
+			instanceName = target.Instance
+		}
+		if target.User != "" {
+			if len(usernames) != 1 {
+				return nil, fmt.Errorf("cannot restore snapshot of %q onto user %q: exactly one user to restore must be given", r.Snap, target.User)
+			}
+			targetUser = target.User
+		}
+	}
+	compressionFlag, err := compressionTarFlag(r.Compression)
+	if err != nil {
+		return nil, err
+	}
+
 	rs = &RestoreState{}
 	defer func() {
 		if e != nil {
@@ -209,7 +386,7 @@ func (r *Reader) Restore(ctx context.Context, current snap.Revision, usernames [
 
 	sort.Strings(usernames)
 	isRoot := sys.Geteuid() == 0
-	si := snap.MinimalPlaceInfo(r.Snap, r.Revision)
+	si := snap.MinimalPlaceInfo(instanceName, r.Revision)
 	hasher := crypto.SHA3_384.New()
 	var sz osutil.Sizer
 
@@ -235,6 +412,10 @@ func (r *Reader) Restore(ctx context.Context, current snap.Revision, usernames [
 				logf("Skipping restore of unknown entry %q.", entry)
 				continue
 			}
+			if targetUser != "" {
+				logger.Debugf("In restoring snapshot %q onto user %q, skipping system data.", r.Name(), targetUser)
+				continue
+			}
 			dest = si.DataDir()
 		} else {
 			username = entryUsername(entry)
@@ -242,6 +423,11 @@ func (r *Reader) Restore(ctx context.Context, current snap.Revision, usernames [
 				logger.Debugf("In restoring snapshot %q, skipping entry %q by user request.", r.Name(), username)
 				continue
 			}
+			if targetUser != "" {
+				// the data is unpacked by, and so owned by, the target user
+				logger.Debugf("In restoring snapshot %q, restoring data of user %q onto user %q.", r.Name(), username, targetUser)
+				username = targetUser
+			}
 			usr, err := userLookup(username)
 			if err != nil {
 				logf("Skipping restore of user %q: %v.", username, err)
@@ -325,7 +511,7 @@ func (r *Reader) Restore(ctx context.Context, current snap.Revision, usernames [
 		// special cases we'd need to consider otherwise
 		cmd := tarAsUser(username,
 			"--extract",
-			"--preserve-permissions", "--preserve-order", "--gunzip",
+			"--preserve-permissions", "--preserve-order", compressionFlag,
 			"--directory", tempdir)
 		cmd.Env = []string{}
 		cmd.Stdin = tr
@@ -67,6 +67,14 @@ func (summaries snapshotSnapSummaries) AsMaps() []map[string]string {
 	return out
 }
 
+func MockOsutilExecutableExists(f func(string) bool) (restore func()) {
+	old := osutilExecutableExists
+	osutilExecutableExists = f
+	return func() {
+		osutilExecutableExists = old
+	}
+}
+
 func MockOsRemove(f func(string) error) (restore func()) {
 	old := osRemove
 	osRemove = f
@@ -123,7 +131,7 @@ func MockBackendList(f func(ctx context.Context, setID uint64, snapNames []strin
 	}
 }
 
-func MockBackendRestore(f func(*backend.Reader, context.Context, snap.Revision, []string, backend.Logf, *dirs.SnapDirOptions) (*backend.RestoreState, error)) (restore func()) {
+func MockBackendRestore(f func(*backend.Reader, context.Context, snap.Revision, []string, *client.SnapshotRestoreTarget, backend.Logf, *dirs.SnapDirOptions) (*backend.RestoreState, error)) (restore func()) {
 	old := backendRestore
 	backendRestore = f
 	return func() {
@@ -139,6 +147,22 @@ func MockBackendCheck(f func(*backend.Reader, context.Context, []string) error)
 	}
 }
 
+func MockBackendFiles(f func(*backend.Reader, context.Context) ([]client.SnapshotFile, error)) (restore func()) {
+	old := backendFiles
+	backendFiles = f
+	return func() {
+		backendFiles = old
+	}
+}
+
+func MockBackendOpenFile(f func(*backend.Reader, string, string) (io.ReadCloser, int64, error)) (restore func()) {
+	old := backendOpenFile
+	backendOpenFile = f
+	return func() {
+		backendOpenFile = old
+	}
+}
+
 func MockBackendRevert(f func(*backend.RestoreState)) (restore func()) {
 	old := backendRevert
 	backendRevert = f
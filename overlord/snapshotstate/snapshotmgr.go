@@ -31,6 +31,7 @@ import (
 	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/snapshotstate/backend"
 	"github.com/snapcore/snapd/overlord/snapstate"
@@ -44,10 +45,12 @@ var (
 	configGetSnapConfig  = config.GetSnapConfig
 	configSetSnapConfig  = config.SetSnapConfig
 	backendOpen          = backend.Open
-	backendSave          = backend.Save
+	backendSave          = backend.SaveCompressed
 	backendImport        = backend.Import
-	backendRestore       = (*backend.Reader).Restore // TODO: look into using an interface instead
+	backendRestore       = (*backend.Reader).RestoreTo // TODO: look into using an interface instead
 	backendCheck         = (*backend.Reader).Check
+	backendFiles         = (*backend.Reader).Files
+	backendOpenFile      = (*backend.Reader).OpenFile
 	backendRevert        = (*backend.RestoreState).Revert // ditto
 	backendCleanup       = (*backend.RestoreState).Cleanup
 
@@ -56,6 +59,8 @@ var (
 	autoExpirationInterval = time.Hour * 24 // interval between forgetExpiredSnapshots runs as part of Ensure()
 
 	getSnapDirOpts = snapstate.GetSnapDirOpts
+
+	osutilExecutableExists = osutil.ExecutableExists
 )
 
 // SnapshotManager takes snapshots of active snaps
@@ -170,6 +175,18 @@ type snapshotSetup struct {
 	Filename string                `json:"filename,omitempty"`
 	Current  snap.Revision         `json:"current"`
 	Auto     bool                  `json:"auto,omitempty"`
+
+	// Target is set when restoring into a different instance or user
+	Target *client.SnapshotRestoreTarget `json:"target,omitempty"`
+}
+
+// instanceName returns the name of the snap instance whose data and
+// configuration are affected by the snapshot operation.
+func (s *snapshotSetup) instanceName() string {
+	if s.Target != nil && s.Target.Instance != "" {
+		return s.Target.Instance
+	}
+	return s.Snap
 }
 
 func filename(setID uint64, si *snap.Info) string {
@@ -228,12 +245,17 @@ func doSave(task *state.Task, tomb *tomb.Tomb) error {
 
 	st.Lock()
 	opts, err := getSnapDirOpts(st, snapshot.Snap)
+	if err != nil {
+		st.Unlock()
+		return err
+	}
+	compression, err := snapshotCompression(st)
 	st.Unlock()
 	if err != nil {
 		return err
 	}
 
-	_, err = backendSave(tomb.Context(nil), snapshot.SetID, cur, cfg, snapshot.Users, snapshot.Options, opts)
+	_, err = backendSave(tomb.Context(nil), snapshot.SetID, cur, cfg, snapshot.Users, compression, snapshot.Options, opts)
 	if err != nil {
 		st.Lock()
 		defer st.Unlock()
@@ -254,7 +276,7 @@ func prepareRestore(task *state.Task) (snapshot *snapshotSetup, oldCfg map[strin
 		return nil, nil, nil, taskGetErrMsg(task, err, "snapshot")
 	}
 
-	oldCfg, err = unmarshalSnapConfig(st, snapshot.Snap)
+	oldCfg, err = unmarshalSnapConfig(st, snapshot.instanceName())
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -312,13 +334,13 @@ func doRestore(task *state.Task, tomb *tomb.Tomb) error {
 	}
 
 	st.Lock()
-	opts, err := getSnapDirOpts(st, snapshot.Snap)
+	opts, err := getSnapDirOpts(st, snapshot.instanceName())
 	st.Unlock()
 	if err != nil {
 		return err
 	}
 
-	restoreState, err := backendRestore(reader, tomb.Context(nil), snapshot.Current, snapshot.Users, logf, opts)
+	restoreState, err := backendRestore(reader, tomb.Context(nil), snapshot.Current, snapshot.Users, snapshot.Target, logf, opts)
 	if err != nil {
 		return err
 	}
@@ -332,7 +354,7 @@ func doRestore(task *state.Task, tomb *tomb.Tomb) error {
 	st.Lock()
 	defer st.Unlock()
 
-	if err := configSetSnapConfig(st, snapshot.Snap, raw); err != nil {
+	if err := configSetSnapConfig(st, snapshot.instanceName(), raw); err != nil {
 		backendRevert(restoreState)
 		return fmt.Errorf("cannot set snap config: %v", err)
 	}
@@ -363,7 +385,7 @@ func undoRestore(task *state.Task, _ *tomb.Tomb) error {
 		return fmt.Errorf("cannot marshal saved config: %v", err)
 	}
 
-	if err := configSetSnapConfig(st, snapshot.Snap, raw); err != nil {
+	if err := configSetSnapConfig(st, snapshot.instanceName(), raw); err != nil {
 		return fmt.Errorf("cannot restore saved config: %v", err)
 	}
 
@@ -468,7 +490,7 @@ func delayedCrossMgrInit() {
 	snapstate.EstimateSnapshotSize = EstimateSnapshotSize
 }
 
-func MockBackendSave(f func(context.Context, uint64, *snap.Info, map[string]interface{}, []string, *snap.SnapshotOptions, *dirs.SnapDirOptions) (*client.Snapshot, error)) (restore func()) {
+func MockBackendSave(f func(context.Context, uint64, *snap.Info, map[string]interface{}, []string, string, *snap.SnapshotOptions, *dirs.SnapDirOptions) (*client.Snapshot, error)) (restore func()) {
 	old := backendSave
 	backendSave = f
 	return func() {
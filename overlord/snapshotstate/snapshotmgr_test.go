@@ -35,6 +35,7 @@ import (
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/overlord"
+	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/snapshotstate"
 	"github.com/snapcore/snapd/overlord/snapshotstate/backend"
 	"github.com/snapcore/snapd/overlord/state"
@@ -281,7 +282,7 @@ func (snapshotSuite) TestDoSave(c *check.C) {
 	})()
 
 	expectedOptions := &snap.SnapshotOptions{}
-	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string,
+	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ string,
 		options *snap.SnapshotOptions, _ *dirs.SnapDirOptions) (*client.Snapshot, error) {
 		c.Check(id, check.Equals, uint64(42))
 		c.Check(si, check.DeepEquals, &snapInfo)
@@ -338,7 +339,7 @@ func (snapshotSuite) TestDoSaveGetsSnapDirOpts(c *check.C) {
 	})()
 
 	var checkOpts bool
-	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ *snap.SnapshotOptions, opts *dirs.SnapDirOptions) (*client.Snapshot, error) {
+	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ string, _ *snap.SnapshotOptions, opts *dirs.SnapDirOptions) (*client.Snapshot, error) {
 		c.Check(opts.HiddenSnapDataDir, check.Equals, true)
 		checkOpts = true
 		return nil, nil
@@ -357,12 +358,66 @@ func (snapshotSuite) TestDoSaveGetsSnapDirOpts(c *check.C) {
 	c.Check(checkOpts, check.Equals, true)
 }
 
+func (snapshotSuite) TestDoSaveCompression(c *check.C) {
+	snapInfo := snap.Info{
+		SideInfo: snap.SideInfo{
+			RealName: "a-snap",
+			Revision: snap.R(-1),
+		},
+		Version: "1.33",
+	}
+	defer snapshotstate.MockSnapstateCurrentInfo(func(_ *state.State, snapname string) (*snap.Info, error) {
+		return &snapInfo, nil
+	})()
+
+	zstdAvailable := true
+	defer snapshotstate.MockOsutilExecutableExists(func(name string) bool {
+		c.Check(name, check.Equals, "zstd")
+		return zstdAvailable
+	})()
+
+	var compression string
+	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, comp string, _ *snap.SnapshotOptions, opts *dirs.SnapDirOptions) (*client.Snapshot, error) {
+		compression = comp
+		return nil, nil
+	})()
+
+	st := state.New(nil)
+	for _, t := range []struct {
+		config, available bool
+		expected          string
+	}{
+		{false, true, ""},
+		{true, true, "zstd"},
+		{true, false, "gzip"},
+	} {
+		zstdAvailable = t.available
+		st.Lock()
+		tr := config.NewTransaction(st)
+		if t.config {
+			tr.Set("core", "snapshots.compression", "zstd")
+		} else {
+			tr.Set("core", "snapshots.compression", nil)
+		}
+		tr.Commit()
+		task := st.NewTask("save-snapshot", "...")
+		task.Set("snapshot-setup", map[string]interface{}{
+			"snap": "a-snap",
+		})
+		st.Unlock()
+
+		err := snapshotstate.DoSave(task, &tomb.Tomb{})
+		c.Assert(err, check.IsNil)
+		c.Check(compression, check.Equals, t.expected)
+	}
+}
+
 func (snapshotSuite) TestDoSaveFailsWithNoSnap(c *check.C) {
 	defer snapshotstate.MockSnapstateCurrentInfo(func(*state.State, string) (*snap.Info, error) {
 		return nil, errors.New("bzzt")
 	})()
 	defer snapshotstate.MockConfigGetSnapConfig(func(*state.State, string) (*json.RawMessage, error) { return nil, nil })()
-	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
+	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
 		return nil, nil
 	})()
 
@@ -389,7 +444,7 @@ func (snapshotSuite) TestDoSaveFailsWithNoSnapshot(c *check.C) {
 	}
 	defer snapshotstate.MockSnapstateCurrentInfo(func(*state.State, string) (*snap.Info, error) { return &snapInfo, nil })()
 	defer snapshotstate.MockConfigGetSnapConfig(func(*state.State, string) (*json.RawMessage, error) { return nil, nil })()
-	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
+	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
 		return nil, nil
 	})()
 
@@ -413,7 +468,7 @@ func (snapshotSuite) TestDoSaveFailsBackendError(c *check.C) {
 	}
 	defer snapshotstate.MockSnapstateCurrentInfo(func(*state.State, string) (*snap.Info, error) { return &snapInfo, nil })()
 	defer snapshotstate.MockConfigGetSnapConfig(func(*state.State, string) (*json.RawMessage, error) { return nil, nil })()
-	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
+	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
 		return nil, errors.New("bzzt")
 	})()
 
@@ -442,7 +497,7 @@ func (snapshotSuite) TestDoSaveFailsConfigError(c *check.C) {
 	defer snapshotstate.MockConfigGetSnapConfig(func(*state.State, string) (*json.RawMessage, error) {
 		return nil, errors.New("bzzt")
 	})()
-	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
+	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
 		return nil, nil
 	})()
 
@@ -473,7 +528,7 @@ func (snapshotSuite) TestDoSaveFailsBadConfig(c *check.C) {
 		buf := json.RawMessage(`"hello-there"`)
 		return &buf, nil
 	})()
-	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
+	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
 		return nil, nil
 	})()
 
@@ -506,7 +561,7 @@ func (snapshotSuite) TestDoSaveFailureRemovesStateEntry(c *check.C) {
 	defer snapshotstate.MockConfigGetSnapConfig(func(_ *state.State, snapname string) (*json.RawMessage, error) {
 		return nil, nil
 	})()
-	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
+	defer snapshotstate.MockBackendSave(func(_ context.Context, id uint64, si *snap.Info, cfg map[string]interface{}, usernames []string, _ string, _ *snap.SnapshotOptions, options *dirs.SnapDirOptions) (*client.Snapshot, error) {
 		var expirations map[uint64]interface{}
 		st.Lock()
 		defer st.Unlock()
@@ -576,7 +631,7 @@ func (rs *readerSuite) SetUpTest(c *check.C) {
 			rs.calls = append(rs.calls, "open")
 			return &backend.Reader{}, nil
 		}),
-		snapshotstate.MockBackendRestore(func(*backend.Reader, context.Context, snap.Revision, []string, backend.Logf, *dirs.SnapDirOptions) (*backend.RestoreState, error) {
+		snapshotstate.MockBackendRestore(func(*backend.Reader, context.Context, snap.Revision, []string, *client.SnapshotRestoreTarget, backend.Logf, *dirs.SnapDirOptions) (*backend.RestoreState, error) {
 			rs.calls = append(rs.calls, "restore")
 			return &backend.RestoreState{}, nil
 		}),
@@ -615,7 +670,7 @@ func (rs *readerSuite) TestDoRestore(c *check.C) {
 			Snapshot: client.Snapshot{Conf: map[string]interface{}{"hello": "there"}},
 		}, nil
 	})()
-	defer snapshotstate.MockBackendRestore(func(_ *backend.Reader, _ context.Context, _ snap.Revision, users []string, _ backend.Logf, options *dirs.SnapDirOptions) (*backend.RestoreState, error) {
+	defer snapshotstate.MockBackendRestore(func(_ *backend.Reader, _ context.Context, _ snap.Revision, users []string, _ *client.SnapshotRestoreTarget, _ backend.Logf, options *dirs.SnapDirOptions) (*backend.RestoreState, error) {
 		rs.calls = append(rs.calls, "restore")
 		c.Check(users, check.DeepEquals, []string{"a-user", "b-user"})
 		return &backend.RestoreState{}, nil
@@ -639,6 +694,51 @@ func (rs *readerSuite) TestDoRestore(c *check.C) {
 	c.Check(v, check.DeepEquals, map[string]interface{}{"config": map[string]interface{}{"old": "conf"}})
 }
 
+func (rs *readerSuite) TestDoRestoreTarget(c *check.C) {
+	st := rs.task.State()
+	st.Lock()
+	rs.task.Set("snapshot-setup", map[string]interface{}{
+		"snap":     "a-snap",
+		"filename": "/some/1_file.zip",
+		"users":    []string{"a-user"},
+		"target":   map[string]interface{}{"instance": "a-snap_staging", "user": "b-user"},
+	})
+	st.Unlock()
+
+	defer snapshotstate.MockConfigGetSnapConfig(func(_ *state.State, snapname string) (*json.RawMessage, error) {
+		rs.calls = append(rs.calls, "get config")
+		c.Check(snapname, check.Equals, "a-snap_staging")
+		return nil, nil
+	})()
+	defer snapshotstate.MockBackendOpen(func(filename string, setID uint64) (*backend.Reader, error) {
+		rs.calls = append(rs.calls, "open")
+		return &backend.Reader{
+			Snapshot: client.Snapshot{Snap: "a-snap", Conf: map[string]interface{}{"hello": "there"}},
+		}, nil
+	})()
+	defer snapshotstate.MockBackendRestore(func(_ *backend.Reader, _ context.Context, _ snap.Revision, users []string, target *client.SnapshotRestoreTarget, _ backend.Logf, options *dirs.SnapDirOptions) (*backend.RestoreState, error) {
+		rs.calls = append(rs.calls, "restore")
+		c.Check(users, check.DeepEquals, []string{"a-user"})
+		c.Check(target, check.DeepEquals, &client.SnapshotRestoreTarget{Instance: "a-snap_staging", User: "b-user"})
+		return &backend.RestoreState{}, nil
+	})()
+	defer snapshotstate.MockConfigSetSnapConfig(func(_ *state.State, snapname string, conf *json.RawMessage) error {
+		rs.calls = append(rs.calls, "set config")
+		c.Check(snapname, check.Equals, "a-snap_staging")
+		return nil
+	})()
+
+	err := snapshotstate.DoRestore(rs.task, &tomb.Tomb{})
+	c.Assert(err, check.IsNil)
+	c.Check(rs.calls, check.DeepEquals, []string{"get config", "open", "restore", "set config"})
+
+	// undo restores the config of the target instance
+	rs.calls = nil
+	err = snapshotstate.UndoRestore(rs.task, &tomb.Tomb{})
+	c.Assert(err, check.IsNil)
+	c.Check(rs.calls, check.DeepEquals, []string{"set config", "revert"})
+}
+
 func (rs *readerSuite) TestDoRestoreNoConfig(c *check.C) {
 	defer snapshotstate.MockConfigGetSnapConfig(func(_ *state.State, snapname string) (*json.RawMessage, error) {
 		rs.calls = append(rs.calls, "get config")
@@ -657,7 +757,7 @@ func (rs *readerSuite) TestDoRestoreNoConfig(c *check.C) {
 			Snapshot: client.Snapshot{Snap: "a-snap", Conf: nil},
 		}, nil
 	})()
-	defer snapshotstate.MockBackendRestore(func(_ *backend.Reader, _ context.Context, _ snap.Revision, users []string, _ backend.Logf, options *dirs.SnapDirOptions) (*backend.RestoreState, error) {
+	defer snapshotstate.MockBackendRestore(func(_ *backend.Reader, _ context.Context, _ snap.Revision, users []string, _ *client.SnapshotRestoreTarget, _ backend.Logf, options *dirs.SnapDirOptions) (*backend.RestoreState, error) {
 		rs.calls = append(rs.calls, "restore")
 		c.Check(users, check.DeepEquals, []string{"a-user", "b-user"})
 		return &backend.RestoreState{}, nil
@@ -740,7 +840,7 @@ func (rs *readerSuite) TestDoRestoreFailsUnserialisableSnapshotConfigError(c *ch
 }
 
 func (rs *readerSuite) TestDoRestoreFailsOnRestoreError(c *check.C) {
-	defer snapshotstate.MockBackendRestore(func(*backend.Reader, context.Context, snap.Revision, []string, backend.Logf, *dirs.SnapDirOptions) (*backend.RestoreState, error) {
+	defer snapshotstate.MockBackendRestore(func(*backend.Reader, context.Context, snap.Revision, []string, *client.SnapshotRestoreTarget, backend.Logf, *dirs.SnapDirOptions) (*backend.RestoreState, error) {
 		rs.calls = append(rs.calls, "restore")
 		return nil, errors.New("bzzt")
 	})()
@@ -151,6 +151,23 @@ func AutomaticSnapshotExpiration(st *state.State) (time.Duration, error) {
 	return defaultAutomaticSnapshotExpiration, nil
 }
 
+// snapshotCompression returns the compression to use for the archives of
+// new snapshots, as set by the snapshots.compression option. It falls
+// back to gzip if zstd is requested but not available.
+func snapshotCompression(st *state.State) (string, error) {
+	var compression string
+	tr := config.NewTransaction(st)
+	err := tr.Get("core", "snapshots.compression", &compression)
+	if err != nil && !config.IsNoOption(err) {
+		return "", err
+	}
+	if compression == "zstd" && !osutilExecutableExists("zstd") {
+		logger.Noticef("Cannot find zstd, compressing snapshot with gzip instead.")
+		return "gzip", nil
+	}
+	return compression, nil
+}
+
 // saveExpiration saves expiration date of the given snapshot set, in the state.
 // The state needs to be locked by the caller.
 func saveExpiration(st *state.State, setID uint64, expiryTime time.Time) error {
@@ -470,6 +487,34 @@ func AutomaticSnapshot(st *state.State, snapName string) (ts *state.TaskSet, err
 // Restore creates a taskset for restoring a snapshot's data.
 // Note that the state must be locked by the caller.
 func Restore(st *state.State, setID uint64, snapNames []string, users []string) (snapsFound []string, ts *state.TaskSet, err error) {
+	return RestoreTo(st, setID, snapNames, users, nil)
+}
+
+// RestoreTo creates a taskset for restoring a snapshot's data like Restore,
+// but into the snap instance and/or for the user given by target, if not
+// nil. Restoring into a different instance requires a single snap to be
+// given, and restoring onto a different user requires a single user to be
+// given. The returned snap names are those of the instances whose data is
+// restored.
+// Note that the state must be locked by the caller.
+func RestoreTo(st *state.State, setID uint64, snapNames []string, users []string, target *client.SnapshotRestoreTarget) (snapsFound []string, ts *state.TaskSet, err error) {
+	if target != nil {
+		if target.Instance != "" {
+			if len(snapNames) != 1 {
+				return nil, nil, fmt.Errorf("cannot restore snapshot into instance %q: a single snap must be given", target.Instance)
+			}
+			if err := snap.ValidateInstanceName(target.Instance); err != nil {
+				return nil, nil, err
+			}
+			if snap.InstanceSnap(target.Instance) != snap.InstanceSnap(snapNames[0]) {
+				return nil, nil, fmt.Errorf("cannot restore snapshot of %q into %q: not an instance of the same snap", snapNames[0], target.Instance)
+			}
+		}
+		if target.User != "" && len(users) != 1 {
+			return nil, nil, fmt.Errorf("cannot restore snapshot onto user %q: a single user must be given", target.User)
+		}
+	}
+
 	summaries, err := snapSummariesInSnapshotSet(setID, snapNames)
 	if err != nil {
 		return nil, nil, err
@@ -480,6 +525,9 @@ func Restore(st *state.State, setID uint64, snapNames []string, users []string)
 	}
 
 	snapsFound = summaries.snapNames()
+	if target != nil && target.Instance != "" {
+		snapsFound = []string{target.Instance}
+	}
 
 	if err := snapstateCheckChangeConflictMany(st, snapsFound, ""); err != nil {
 		return nil, nil, err
@@ -493,8 +541,12 @@ func Restore(st *state.State, setID uint64, snapNames []string, users []string)
 	ts = state.NewTaskSet()
 
 	for _, summary := range summaries {
+		instanceName := summary.snap
+		if target != nil && target.Instance != "" {
+			instanceName = target.Instance
+		}
 		var current snap.Revision
-		if snapst, ok := all[summary.snap]; ok {
+		if snapst, ok := all[instanceName]; ok {
 			info, err := snapst.CurrentInfo()
 			if err != nil {
 				// how?
@@ -502,16 +554,22 @@ func Restore(st *state.State, setID uint64, snapNames []string, users []string)
 			}
 			if !info.Epoch.CanRead(summary.epoch) {
 				const tpl = "cannot restore snapshot for %q: current snap (epoch %s) cannot read snapshot data (epoch %s)"
-				return nil, nil, fmt.Errorf(tpl, summary.snap, &info.Epoch, &summary.epoch)
+				return nil, nil, fmt.Errorf(tpl, instanceName, &info.Epoch, &summary.epoch)
 			}
 			if summary.snapID != "" && info.SnapID != "" && info.SnapID != summary.snapID {
 				const tpl = "cannot restore snapshot for %q: current snap (ID %.7s…) does not match snapshot (ID %.7s…)"
-				return nil, nil, fmt.Errorf(tpl, summary.snap, info.SnapID, summary.snapID)
+				return nil, nil, fmt.Errorf(tpl, instanceName, info.SnapID, summary.snapID)
 			}
 			current = snapst.Current
 		}
 
 		desc := fmt.Sprintf("Restore data of snap %q from snapshot set #%d", summary.snap, setID)
+		if instanceName != summary.snap {
+			desc = fmt.Sprintf("Restore data of snap %q from snapshot set #%d into %q", summary.snap, setID, instanceName)
+		}
+		if target != nil && target.User != "" {
+			desc += fmt.Sprintf(" for user %q", target.User)
+		}
 		task := st.NewTask("restore-snapshot", desc)
 		snapshot := snapshotSetup{
 			SetID:    setID,
@@ -519,6 +577,7 @@ func Restore(st *state.State, setID uint64, snapNames []string, users []string)
 			Users:    users,
 			Filename: summary.filename,
 			Current:  current,
+			Target:   target,
 		}
 		task.Set("snapshot-setup", &snapshot)
 		// see the note about snapshots not using lanes, above.
@@ -569,6 +628,78 @@ func Check(st *state.State, setID uint64, snapNames []string, users []string) (s
 	return summaries.snapNames(), ts, nil
 }
 
+// Files lists the files stored in the snapshots of the given set, optionally
+// limited to those of the given snaps.
+// Note that the state must be locked by the caller.
+func Files(ctx context.Context, st *state.State, setID uint64, snapNames []string) ([]client.SnapshotFile, error) {
+	// the snapshots must not go away while being read
+	if err := checkSnapshotConflict(st, setID, "forget-snapshot"); err != nil {
+		return nil, err
+	}
+
+	summaries, err := snapSummariesInSnapshotSet(setID, snapNames)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []client.SnapshotFile{}
+	for _, summary := range summaries {
+		reader, err := backendOpen(summary.filename, setID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open snapshot: %v", err)
+		}
+		snapFiles, err := backendFiles(reader, ctx)
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, snapFiles...)
+	}
+
+	return files, nil
+}
+
+type snapshotFile struct {
+	io.ReadCloser
+	reader *backend.Reader
+}
+
+func (f *snapshotFile) Close() error {
+	err := f.ReadCloser.Close()
+	if err := f.reader.Close(); err != nil {
+		logger.Noticef("Cannot close snapshot %q: %v", f.reader.Name(), err)
+	}
+	return err
+}
+
+// OpenFile opens a file stored in the snapshot of the given snap in the
+// given set, from the data of the given user or from the system data if
+// username is empty. The path is relative to the snap's data directory, as
+// returned by Files. The caller must close the returned file.
+// Note that the state must be locked by the caller.
+func OpenFile(st *state.State, setID uint64, snapName, username, path string) (file io.ReadCloser, size int64, err error) {
+	if err := checkSnapshotConflict(st, setID, "forget-snapshot"); err != nil {
+		return nil, -1, err
+	}
+
+	summaries, err := snapSummariesInSnapshotSet(setID, []string{snapName})
+	if err != nil {
+		return nil, -1, err
+	}
+
+	reader, err := backendOpen(summaries[0].filename, setID)
+	if err != nil {
+		return nil, -1, fmt.Errorf("cannot open snapshot: %v", err)
+	}
+	f, size, err := backendOpenFile(reader, username, path)
+	if err != nil {
+		reader.Close()
+		return nil, -1, err
+	}
+
+	return &snapshotFile{ReadCloser: f, reader: reader}, size, nil
+}
+
 // Forget creates a taskset for deletinig a snapshot.
 // Note that the state must be locked by the caller.
 func Forget(st *state.State, setID uint64, snapNames []string) (snapsFound []string, ts *state.TaskSet, err error) {
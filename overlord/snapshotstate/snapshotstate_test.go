@@ -1199,6 +1199,88 @@ func (snapshotSuite) TestRestore(c *check.C) {
 	})
 }
 
+func (snapshotSuite) TestRestoreTo(c *check.C) {
+	shotfile, err := os.Create(filepath.Join(c.MkDir(), "yadda.zip"))
+	c.Assert(err, check.IsNil)
+	defer shotfile.Close()
+
+	sideInfo := &snap.SideInfo{RealName: "a-snap", Revision: snap.R(7)}
+	defer snapshotstate.MockSnapstateAll(func(*state.State) (map[string]*snapstate.SnapState, error) {
+		return map[string]*snapstate.SnapState{
+			"a-snap_staging": {
+				Active:      true,
+				Sequence:    snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{sideInfo}),
+				Current:     sideInfo.Revision,
+				InstanceKey: "staging",
+			},
+		}, nil
+	})()
+	snaptest.MockSnapInstance(c, "a-snap_staging", "{name: a-snap, version: v1}", sideInfo)
+
+	var conflictSnaps []string
+	defer snapshotstate.MockSnapstateCheckChangeConflictMany(func(_ *state.State, snapNames []string, _ string) error {
+		conflictSnaps = snapNames
+		return nil
+	})()
+
+	fakeIter := func(_ context.Context, f func(*backend.Reader) error) error {
+		c.Assert(f(&backend.Reader{
+			Snapshot: client.Snapshot{SetID: 42, Snap: "a-snap"},
+			File:     shotfile,
+		}), check.IsNil)
+
+		return nil
+	}
+	defer snapshotstate.MockBackendIter(fakeIter)()
+
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	target := &client.SnapshotRestoreTarget{Instance: "a-snap_staging", User: "b-user"}
+	found, taskset, err := snapshotstate.RestoreTo(st, 42, []string{"a-snap"}, []string{"a-user"}, target)
+	c.Assert(err, check.IsNil)
+	c.Check(found, check.DeepEquals, []string{"a-snap_staging"})
+	c.Check(conflictSnaps, check.DeepEquals, []string{"a-snap_staging"})
+	tasks := taskset.Tasks()
+	c.Assert(tasks, check.HasLen, 2)
+	c.Check(tasks[0].Kind(), check.Equals, "restore-snapshot")
+	c.Check(tasks[0].Summary(), check.Equals, `Restore data of snap "a-snap" from snapshot set #42 into "a-snap_staging" for user "b-user"`)
+	var snapshot map[string]interface{}
+	c.Check(tasks[0].Get("snapshot-setup", &snapshot), check.IsNil)
+	c.Check(snapshot, check.DeepEquals, map[string]interface{}{
+		"set-id":   42.,
+		"snap":     "a-snap",
+		"filename": shotfile.Name(),
+		"users":    []interface{}{"a-user"},
+		"current":  "7",
+		"target":   map[string]interface{}{"instance": "a-snap_staging", "user": "b-user"},
+	})
+}
+
+func (snapshotSuite) TestRestoreToErrors(c *check.C) {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	for _, t := range []struct {
+		snaps  []string
+		users  []string
+		target client.SnapshotRestoreTarget
+		err    string
+	}{
+		{nil, nil, client.SnapshotRestoreTarget{Instance: "a-snap_foo"}, `cannot restore snapshot into instance "a-snap_foo": a single snap must be given`},
+		{[]string{"a-snap", "b-snap"}, nil, client.SnapshotRestoreTarget{Instance: "a-snap_foo"}, `cannot restore snapshot into instance "a-snap_foo": a single snap must be given`},
+		{[]string{"a-snap"}, nil, client.SnapshotRestoreTarget{Instance: "a-snap_"}, `invalid instance key: ""`},
+		{[]string{"a-snap"}, nil, client.SnapshotRestoreTarget{Instance: "b-snap_foo"}, `cannot restore snapshot of "a-snap" into "b-snap_foo": not an instance of the same snap`},
+		{nil, nil, client.SnapshotRestoreTarget{User: "b-user"}, `cannot restore snapshot onto user "b-user": a single user must be given`},
+		{nil, []string{"a-user", "c-user"}, client.SnapshotRestoreTarget{User: "b-user"}, `cannot restore snapshot onto user "b-user": a single user must be given`},
+	} {
+		_, _, err := snapshotstate.RestoreTo(st, 42, t.snaps, t.users, &t.target)
+		c.Check(err, check.ErrorMatches, t.err)
+	}
+}
+
 func (snapshotSuite) TestRestoreIntegration(c *check.C) {
 	testRestoreIntegration(c, dirs.UserHomeSnapDir, nil)
 }
@@ -1482,6 +1564,96 @@ func (snapshotSuite) TestCheck(c *check.C) {
 	})
 }
 
+func (snapshotSuite) TestFiles(c *check.C) {
+	shotfile, err := os.Create(filepath.Join(c.MkDir(), "yadda.zip"))
+	c.Assert(err, check.IsNil)
+	defer shotfile.Close()
+	defer snapshotstate.MockBackendIter(func(_ context.Context, f func(*backend.Reader) error) error {
+		for _, name := range []string{"a-snap", "b-snap"} {
+			c.Assert(f(&backend.Reader{
+				Snapshot: client.Snapshot{SetID: 42, Snap: name},
+				File:     shotfile,
+			}), check.IsNil)
+		}
+		return nil
+	})()
+	defer snapshotstate.MockBackendOpen(func(filename string, setID uint64) (*backend.Reader, error) {
+		c.Check(filename, check.Equals, shotfile.Name())
+		c.Check(setID, check.Equals, uint64(42))
+		f, err := os.Open(filename)
+		c.Assert(err, check.IsNil)
+		return &backend.Reader{File: f, Snapshot: client.Snapshot{SetID: 42, Snap: "b-snap"}}, nil
+	})()
+	defer snapshotstate.MockBackendFiles(func(r *backend.Reader, _ context.Context) ([]client.SnapshotFile, error) {
+		return []client.SnapshotFile{
+			{Snap: r.Snap, Path: "common/foo", Size: 3},
+			{Snap: r.Snap, User: "a-user", Path: "42/bar", Size: 5},
+		}, nil
+	})()
+
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	files, err := snapshotstate.Files(context.TODO(), st, 42, []string{"b-snap"})
+	c.Assert(err, check.IsNil)
+	c.Check(files, check.DeepEquals, []client.SnapshotFile{
+		{Snap: "b-snap", Path: "common/foo", Size: 3},
+		{Snap: "b-snap", User: "a-user", Path: "42/bar", Size: 5},
+	})
+
+	_, err = snapshotstate.Files(context.TODO(), st, 43, nil)
+	c.Check(err, check.Equals, client.ErrSnapshotSetNotFound)
+	_, err = snapshotstate.Files(context.TODO(), st, 42, []string{"c-snap"})
+	c.Check(err, check.Equals, client.ErrSnapshotSnapsNotFound)
+}
+
+func (snapshotSuite) TestOpenFile(c *check.C) {
+	shotfile, err := os.Create(filepath.Join(c.MkDir(), "yadda.zip"))
+	c.Assert(err, check.IsNil)
+	defer shotfile.Close()
+	defer snapshotstate.MockBackendIter(func(_ context.Context, f func(*backend.Reader) error) error {
+		c.Assert(f(&backend.Reader{
+			Snapshot: client.Snapshot{SetID: 42, Snap: "a-snap"},
+			File:     shotfile,
+		}), check.IsNil)
+		return nil
+	})()
+	var opened *backend.Reader
+	defer snapshotstate.MockBackendOpen(func(filename string, setID uint64) (*backend.Reader, error) {
+		f, err := os.Open(filename)
+		c.Assert(err, check.IsNil)
+		opened = &backend.Reader{File: f, Snapshot: client.Snapshot{SetID: 42, Snap: "a-snap"}}
+		return opened, nil
+	})()
+	defer snapshotstate.MockBackendOpenFile(func(r *backend.Reader, username, path string) (io.ReadCloser, int64, error) {
+		c.Check(r, check.Equals, opened)
+		c.Check(username, check.Equals, "a-user")
+		if path != "42/bar" {
+			return nil, -1, client.ErrSnapshotFileNotFound
+		}
+		return io.NopCloser(strings.NewReader("hello")), 5, nil
+	})()
+
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	f, size, err := snapshotstate.OpenFile(st, 42, "a-snap", "a-user", "42/bar")
+	c.Assert(err, check.IsNil)
+	c.Check(size, check.Equals, int64(5))
+	data, err := io.ReadAll(f)
+	c.Assert(err, check.IsNil)
+	c.Check(string(data), check.Equals, "hello")
+	c.Check(f.Close(), check.IsNil)
+	// the snapshot was closed too
+	c.Check(opened.File.Close(), check.NotNil)
+
+	_, _, err = snapshotstate.OpenFile(st, 42, "a-snap", "a-user", "42/baz")
+	c.Check(err, check.Equals, client.ErrSnapshotFileNotFound)
+	c.Check(opened.File.Close(), check.NotNil)
+}
+
 func (snapshotSuite) TestForgetChecksIterError(c *check.C) {
 	defer snapshotstate.MockBackendIter(func(context.Context, func(*backend.Reader) error) error {
 		return errors.New("bzzt")
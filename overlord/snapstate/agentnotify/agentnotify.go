@@ -23,7 +23,9 @@ import (
 	"context"
 	"errors"
 
+	"github.com/snapcore/snapd/features"
 	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/state"
 	userclient "github.com/snapcore/snapd/usersession/client"
@@ -48,23 +50,70 @@ func notifyAgentOnLinkageChange(st *state.State, snapsup *snapstate.SnapSetup) e
 	// the only use-case right now is snaps going from inactive->active
 	// for continued-auto-refreshes
 	if snapst.Active {
-		return notifyLinkSnap(snapsup)
+		return notifyLinkSnap(st, &snapst, snapsup)
 	}
 	return nil
 }
 
-func notifyLinkSnap(snapsup *snapstate.SnapSetup) error {
+func notifyLinkSnap(st *state.State, snapst *snapstate.SnapState, snapsup *snapstate.SnapSetup) error {
 	// Note that we only show a notification here if the refresh was
 	// triggered by a "continued-auto-refresh", i.e. when the user
 	// closed an application that had a auto-refresh ready.
 	if snapsup.Flags.IsContinuedAutoRefresh {
 		logger.Debugf("notifying user client about continued refresh for %q", snapsup.InstanceName())
 		sendClientFinishRefreshNotification(snapsup)
+		return nil
 	}
 
+	// Refreshes which went ahead despite running applications leave them
+	// running the old revision until they are restarted.
+	if snapsup.Flags.IsAutoRefresh || snapsup.Flags.IgnoreRunning {
+		return notifyRestartAvailable(st, snapst)
+	}
 	return nil
 }
 
+func notifyRestartAvailable(st *state.State, snapst *snapstate.SnapState) error {
+	tr := config.NewTransaction(st)
+	refreshAppAwareness, err := features.Flag(tr, features.RefreshAppAwareness)
+	if err != nil && !config.IsNoOption(err) {
+		return err
+	}
+	if !refreshAppAwareness {
+		return nil
+	}
+
+	info, err := snapst.CurrentInfo()
+	if err != nil {
+		return err
+	}
+	refreshInfo := runningAppsRefreshInfo(info)
+	if refreshInfo == nil {
+		return nil
+	}
+	logger.Debugf("notifying user client about restart available for %q", info.InstanceName())
+	sendClientRestartAvailableNotification(refreshInfo)
+	return nil
+}
+
+var runningAppsRefreshInfo = snapstate.RunningAppsRefreshInfo
+
+var sendClientRestartAvailableNotification = func(refreshInfo *userclient.PendingSnapRefreshInfo) {
+	lifecycleInfo := &userclient.AppLifecycleInfo{
+		InstanceName:        refreshInfo.InstanceName,
+		Event:               userclient.AppLifecycleRestartAvailable,
+		BusyAppName:         refreshInfo.BusyAppName,
+		BusyAppDesktopEntry: refreshInfo.BusyAppDesktopEntry,
+	}
+	client := userclient.New()
+	// run in a go-routine to avoid potentially slow operation
+	go func() {
+		if err := client.AppLifecycleNotification(context.TODO(), lifecycleInfo); err != nil {
+			logger.Noticef("cannot send restart available notification: %v", err)
+		}
+	}()
+}
+
 var sendClientFinishRefreshNotification = func(snapsup *snapstate.SnapSetup) {
 	refreshInfo := &userclient.FinishedSnapRefreshInfo{
 		InstanceName: snapsup.InstanceName(),
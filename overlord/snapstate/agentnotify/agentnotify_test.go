@@ -24,11 +24,15 @@ import (
 
 	. "gopkg.in/check.v1"
 
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/snapstate/agentnotify"
 	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	userclient "github.com/snapcore/snapd/usersession/client"
 )
 
 func TestAgentNotify(t *testing.T) { TestingT(t) }
@@ -83,3 +87,70 @@ func (s *agentNotifySuite) TestNotifyAgentOnLinkChange(c *C) {
 		c.Check(callCount, Equals, tc.expectedCallCount)
 	}
 }
+
+func (s *agentNotifySuite) TestNotifyAgentRestartAvailable(c *C) {
+	dirs.SetRootDir(c.MkDir())
+	defer dirs.SetRootDir("")
+	defer snap.MockSanitizePlugsSlots(func(snapInfo *snap.Info) {})()
+
+	s.st.Lock()
+	defer s.st.Unlock()
+
+	si := &snap.SideInfo{RealName: "some-snap", Revision: snap.R(2)}
+	snaptest.MockSnap(c, "name: some-snap\nversion: 1\napps:\n  app:\n    command: foo\n", si)
+	snapstate.Set(s.st, "some-snap", &snapstate.SnapState{
+		Active:   true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:  snap.R(2),
+	})
+
+	var running *userclient.PendingSnapRefreshInfo
+	r := agentnotify.MockRunningAppsRefreshInfo(func(info *snap.Info) *userclient.PendingSnapRefreshInfo {
+		c.Check(info.InstanceName(), Equals, "some-snap")
+		return running
+	})
+	defer r()
+	var notified []*userclient.PendingSnapRefreshInfo
+	r = agentnotify.MockSendClientRestartAvailableNotification(func(refreshInfo *userclient.PendingSnapRefreshInfo) {
+		notified = append(notified, refreshInfo)
+	})
+	defer r()
+
+	autoRefresh := &snapstate.SnapSetup{
+		Flags:    snapstate.Flags{IsAutoRefresh: true},
+		SideInfo: si,
+	}
+
+	// nothing running
+	c.Assert(agentnotify.NotifyAgentOnLinkageChange(s.st, autoRefresh), IsNil)
+	c.Check(notified, HasLen, 0)
+
+	running = &userclient.PendingSnapRefreshInfo{
+		InstanceName:        "some-snap",
+		BusyAppName:         "app",
+		BusyAppDesktopEntry: "some-snap_app",
+	}
+	c.Assert(agentnotify.NotifyAgentOnLinkageChange(s.st, autoRefresh), IsNil)
+	c.Check(notified, DeepEquals, []*userclient.PendingSnapRefreshInfo{running})
+
+	// refreshes with --ignore-running notify as well
+	notified = nil
+	ignoreRunning := &snapstate.SnapSetup{
+		Flags:    snapstate.Flags{IgnoreRunning: true},
+		SideInfo: si,
+	}
+	c.Assert(agentnotify.NotifyAgentOnLinkageChange(s.st, ignoreRunning), IsNil)
+	c.Check(notified, HasLen, 1)
+
+	// but other linkage changes don't
+	notified = nil
+	c.Assert(agentnotify.NotifyAgentOnLinkageChange(s.st, &snapstate.SnapSetup{SideInfo: si}), IsNil)
+	c.Check(notified, HasLen, 0)
+
+	// nor when refresh-app-awareness is disabled
+	tr := config.NewTransaction(s.st)
+	c.Assert(tr.Set("core", "experimental.refresh-app-awareness", false), IsNil)
+	tr.Commit()
+	c.Assert(agentnotify.NotifyAgentOnLinkageChange(s.st, autoRefresh), IsNil)
+	c.Check(notified, HasLen, 0)
+}
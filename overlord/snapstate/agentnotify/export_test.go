@@ -21,7 +21,9 @@ package agentnotify
 
 import (
 	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/testutil"
+	userclient "github.com/snapcore/snapd/usersession/client"
 )
 
 var (
@@ -33,3 +35,15 @@ func MockSendClientFinishRefreshNotification(f func(*snapstate.SnapSetup)) (rest
 	sendClientFinishRefreshNotification = f
 	return r
 }
+
+func MockSendClientRestartAvailableNotification(f func(*userclient.PendingSnapRefreshInfo)) (restore func()) {
+	r := testutil.Backup(&sendClientRestartAvailableNotification)
+	sendClientRestartAvailableNotification = f
+	return r
+}
+
+func MockRunningAppsRefreshInfo(f func(*snap.Info) *userclient.PendingSnapRefreshInfo) (restore func()) {
+	r := testutil.Backup(&runningAppsRefreshInfo)
+	runningAppsRefreshInfo = f
+	return r
+}
@@ -725,6 +725,47 @@ var asyncPendingRefreshNotification = func(context context.Context, client *user
 	}()
 }
 
+// asyncAppLifecycleNotification broadcasts a lifecycle event of the running
+// applications of a snap to the session agents in a goroutine.
+var asyncAppLifecycleNotification = func(context context.Context, client *userclient.Client, lifecycleInfo *userclient.AppLifecycleInfo) {
+	logger.Debugf("notifying agents about %s for snap %q", lifecycleInfo.Event, lifecycleInfo.InstanceName)
+	go func() {
+		if err := client.AppLifecycleNotification(context, lifecycleInfo); err != nil {
+			logger.Noticef("Cannot send app lifecycle notification: %v", err)
+		}
+	}()
+}
+
+// notifyCloseRequest asks the session agents to prompt the user to close the
+// running graphical application of a snap with a pending refresh.
+func notifyCloseRequest(refreshInfo *userclient.PendingSnapRefreshInfo) {
+	if refreshInfo.BusyAppDesktopEntry == "" {
+		// nothing a desktop environment could prompt about
+		return
+	}
+	asyncAppLifecycleNotification(context.TODO(), userclient.New(), &userclient.AppLifecycleInfo{
+		InstanceName:        refreshInfo.InstanceName,
+		Event:               userclient.AppLifecycleCloseRequest,
+		TimeRemaining:       refreshInfo.TimeRemaining,
+		BusyAppName:         refreshInfo.BusyAppName,
+		BusyAppDesktopEntry: refreshInfo.BusyAppDesktopEntry,
+	})
+}
+
+// RunningAppsRefreshInfo returns information about the running graphical
+// application of the snap, or nil if none of them is running.
+func RunningAppsRefreshInfo(info *snap.Info) *userclient.PendingSnapRefreshInfo {
+	var busyErr *BusySnapError
+	if err := refreshAppsCheck(info); !errors.As(err, &busyErr) {
+		return nil
+	}
+	refreshInfo := busyErr.PendingSnapRefreshInfo()
+	if refreshInfo.BusyAppDesktopEntry == "" {
+		return nil
+	}
+	return refreshInfo
+}
+
 type timedBusySnapError struct {
 	err           *BusySnapError
 	timeRemaining time.Duration
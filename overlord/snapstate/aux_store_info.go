@@ -39,6 +39,28 @@ type auxStoreInfo struct {
 	// XXX this is now included in snap.SideInfo.EditedLinks but
 	// continue having this to support old snapd
 	Website string `json:"website,omitempty"`
+
+	// Publisher is the publisher of the snap as last seen in its
+	// snap-declaration and the publisher's account assertion.
+	Publisher *snap.StoreAccount `json:"publisher,omitempty"`
+	// PublisherChange records a security-relevant change of the
+	// publisher, see PublisherChanged and PublisherDowngraded.
+	PublisherChange string `json:"publisher-change,omitempty"`
+}
+
+const (
+	// PublisherChanged is recorded when a snap changes publisher.
+	PublisherChanged = "publisher-changed"
+	// PublisherDowngraded is recorded when the validation status of the
+	// publisher of a snap is downgraded, e.g. from starred to verified.
+	PublisherDowngraded = "publisher-downgraded"
+)
+
+// publisherValidationRank orders publisher validation statuses, anything
+// else being considered unproven.
+var publisherValidationRank = map[string]int{
+	"verified": 1,
+	"starred":  2,
 }
 
 func auxStoreInfoFilename(snapID string) string {
@@ -50,32 +72,42 @@ func retrieveAuxStoreInfo(info *snap.Info) error {
 	if info.SnapID == "" {
 		return nil
 	}
-	f, err := os.Open(auxStoreInfoFilename(info.SnapID))
+	aux, err := readAuxStoreInfo(info.SnapID, info.InstanceName())
+	if err != nil || aux == nil {
+		return err
+	}
+
+	info.Media = aux.Media
+	if len(info.EditedLinks) == 0 {
+		// XXX we set this to use old snapd info if it's all we have
+		info.LegacyWebsite = aux.Website
+	}
+	info.StoreURL = aux.StoreURL
+
+	return nil
+}
+
+// readAuxStoreInfo reads the stored auxiliary store info of the snap with
+// the given snap-id, returning nil if there is none.
+func readAuxStoreInfo(snapID, name string) (*auxStoreInfo, error) {
+	f, err := os.Open(auxStoreInfoFilename(snapID))
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil
+			return nil, nil
 		}
-		return err
+		return nil, err
 	}
 	defer f.Close()
 
 	var aux auxStoreInfo
 	dec := json.NewDecoder(f)
 	if err := dec.Decode(&aux); err != nil {
-		return fmt.Errorf("cannot decode auxiliary store info for snap %q: %v", info.InstanceName(), err)
+		return nil, fmt.Errorf("cannot decode auxiliary store info for snap %q: %v", name, err)
 	}
 	if dec.More() {
-		return fmt.Errorf("cannot decode auxiliary store info for snap %q: spurious content after document body", info.InstanceName())
-	}
-
-	info.Media = aux.Media
-	if len(info.EditedLinks) == 0 {
-		// XXX we set this to use old snapd info if it's all we have
-		info.LegacyWebsite = aux.Website
+		return nil, fmt.Errorf("cannot decode auxiliary store info for snap %q: spurious content after document body", name)
 	}
-	info.StoreURL = aux.StoreURL
-
-	return nil
+	return &aux, nil
 }
 
 // keepAuxStoreInfo saves the given auxiliary store info to disk.
@@ -114,3 +146,58 @@ func discardAuxStoreInfo(snapID string) error {
 	}
 	return nil
 }
+
+// RecordPublisher records the given publisher of the snap with the given
+// snap-id in its auxiliary store info. It returns the change detected with
+// respect to the publisher recorded previously, if it is a security-relevant
+// one: the snap changing publisher or the validation status of its publisher
+// being downgraded. Such a change stays recorded until the validation status
+// of the publisher is upgraded again.
+func RecordPublisher(snapID, name string, publisher snap.StoreAccount) (change string, err error) {
+	if snapID == "" {
+		return "", nil
+	}
+	aux, err := readAuxStoreInfo(snapID, name)
+	if err != nil {
+		return "", err
+	}
+	if aux == nil {
+		aux = &auxStoreInfo{}
+	}
+
+	if prev := aux.Publisher; prev != nil {
+		switch {
+		case prev.ID != publisher.ID:
+			change = PublisherChanged
+		case publisherValidationRank[publisher.Validation] < publisherValidationRank[prev.Validation]:
+			change = PublisherDowngraded
+		case publisherValidationRank[publisher.Validation] > publisherValidationRank[prev.Validation]:
+			aux.PublisherChange = ""
+		}
+		if *prev == publisher {
+			// nothing to update
+			return "", nil
+		}
+	}
+	if change != "" {
+		aux.PublisherChange = change
+	}
+	aux.Publisher = &publisher
+	if err := keepAuxStoreInfo(snapID, aux); err != nil {
+		return "", err
+	}
+	return change, nil
+}
+
+// PublisherChange returns the security-relevant change of the publisher of
+// the snap with the given snap-id recorded by RecordPublisher, if any.
+func PublisherChange(snapID, name string) (string, error) {
+	if snapID == "" {
+		return "", nil
+	}
+	aux, err := readAuxStoreInfo(snapID, name)
+	if err != nil || aux == nil {
+		return "", err
+	}
+	return aux.PublisherChange, nil
+}
@@ -96,3 +96,47 @@ func (s *auxInfoSuite) TestAuxStoreInfoRoundTrip(c *check.C) {
 
 	c.Check(snapstate.DiscardAuxStoreInfo(info.SnapID), check.IsNil)
 }
+
+func (s *auxInfoSuite) TestRecordPublisher(c *check.C) {
+	starred := snap.StoreAccount{ID: "pub-id", Username: "pub", DisplayName: "Pub", Validation: "starred"}
+	verified := starred
+	verified.Validation = "verified"
+	other := snap.StoreAccount{ID: "other-id", Username: "other", DisplayName: "Other", Validation: "verified"}
+
+	for _, t := range []struct {
+		publisher      snap.StoreAccount
+		change         string
+		recordedChange string
+	}{
+		// first seen
+		{starred, "", ""},
+		{starred, "", ""},
+		{verified, snapstate.PublisherDowngraded, snapstate.PublisherDowngraded},
+		// the change stays recorded
+		{verified, "", snapstate.PublisherDowngraded},
+		// until the status is upgraded again
+		{starred, "", ""},
+		{other, snapstate.PublisherChanged, snapstate.PublisherChanged},
+		{other, "", snapstate.PublisherChanged},
+	} {
+		change, err := snapstate.RecordPublisher("some-id", "some-snap", t.publisher)
+		c.Assert(err, check.IsNil)
+		c.Check(change, check.Equals, t.change, check.Commentf("%+v", t.publisher))
+		recordedChange, err := snapstate.PublisherChange("some-id", "some-snap")
+		c.Assert(err, check.IsNil)
+		c.Check(recordedChange, check.Equals, t.recordedChange, check.Commentf("%+v", t.publisher))
+	}
+
+	// the rest of the auxiliary store info is kept
+	info := &snap.Info{SuggestedName: "some-snap"}
+	info.SnapID = "some-id"
+	c.Assert(snapstate.KeepAuxStoreInfo(info.SnapID, &snapstate.AuxStoreInfo{StoreURL: "https://snapcraft.io/some-snap"}), check.IsNil)
+	_, err := snapstate.RecordPublisher("some-id", "some-snap", other)
+	c.Assert(err, check.IsNil)
+	c.Assert(snapstate.RetrieveAuxStoreInfo(info), check.IsNil)
+	c.Check(info.StoreURL, check.Equals, "https://snapcraft.io/some-snap")
+
+	change, err := snapstate.PublisherChange("", "some-snap")
+	c.Assert(err, check.IsNil)
+	c.Check(change, check.Equals, "")
+}
@@ -122,4 +122,9 @@ type managerBackend interface {
 	InitExposedSnapHome(snapName string, rev snap.Revision, opts *dirs.SnapDirOptions) (*backend.UndoInfo, error)
 	UndoInitExposedSnapHome(snapName string, undoInfo *backend.UndoInfo) error
 	InitXDGDirs(info *snap.Info) error
+
+	// data location related
+	RelocateSnapData(instanceName, oldLocation, newLocation string, meter progress.Meter) error
+	ClearRelocatedSnapData(instanceName, oldLocation string) error
+	RemoveSnapDataLocation(instanceName, location string, meter progress.Meter) error
 }
@@ -77,7 +77,7 @@ func (b Backend) CopySnapData(newSnap, oldSnap *snap.Info, opts *dirs.SnapDirOpt
 		return nil
 	}
 
-	return copySnapData(oldSnap, newSnap, opts)
+	return copySnapData(oldSnap, newSnap, opts, meter)
 }
 
 // UndoCopySnapData removes the copy that may have been done for newInfo snap of oldInfo snap data and also the data directories that may have been created for newInfo snap.
@@ -0,0 +1,140 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/progress"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/systemd"
+)
+
+// dataLocationOrigin identifies the mount units bind mounting the data of
+// snaps from their relocated location.
+const dataLocationOrigin = "data-location"
+
+// relocatedDataDir returns the directory holding the system data of the
+// snap, that is SNAP_DATA and SNAP_COMMON, at the given location. An empty
+// location is the default one, /var/snap.
+func relocatedDataDir(instanceName, location string) string {
+	if location == "" {
+		return snap.BaseDataDir(instanceName)
+	}
+	return filepath.Join(location, instanceName)
+}
+
+func dataLocationMountUnitOptions(instanceName, location string) *systemd.MountUnitOptions {
+	return &systemd.MountUnitOptions{
+		Lifetime:    systemd.Persistent,
+		Description: fmt.Sprintf("Mount unit for the data of snap %s", instanceName),
+		What:        dirs.StripRootDir(relocatedDataDir(instanceName, location)),
+		Where:       dirs.StripRootDir(snap.BaseDataDir(instanceName)),
+		Fstype:      "none",
+		Options:     []string{"bind"},
+		Origin:      dataLocationOrigin,
+	}
+}
+
+// RelocateSnapData moves the system data of the snap, that is SNAP_DATA and
+// SNAP_COMMON, from the old to the new location. When not in the default
+// location, the data is bind mounted onto /var/snap/<snap> by a persistent
+// mount unit. The data is copied and the previous copy is kept until
+// ClearRelocatedSnapData is called.
+func (b Backend) RelocateSnapData(instanceName, oldLocation, newLocation string, meter progress.Meter) error {
+	if oldLocation == newLocation {
+		return nil
+	}
+	dataDir := snap.BaseDataDir(instanceName)
+	sysd := systemd.New(systemd.SystemMode, meter)
+
+	if newLocation == "" {
+		// back to /var/snap, the mount point is left behind once the
+		// data is unmounted
+		if err := sysd.RemoveMountUnitFile(dataDir); err != nil {
+			return fmt.Errorf("cannot unmount data of snap %q: %v", instanceName, err)
+		}
+		if err := copySnapDataDirectory(relocatedDataDir(instanceName, oldLocation), dataDir); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// the data is visible in /var/snap from wherever it is located
+	newDir := relocatedDataDir(instanceName, newLocation)
+	if err := os.MkdirAll(newLocation, 0755); err != nil {
+		return err
+	}
+	if err := copySnapDataDirectory(dataDir, newDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(newDir, 0755); err != nil {
+		return err
+	}
+	if oldLocation != "" {
+		if err := sysd.RemoveMountUnitFile(dataDir); err != nil {
+			return fmt.Errorf("cannot unmount data of snap %q: %v", instanceName, err)
+		}
+	} else {
+		// keep the original data aside, leaving an empty mount point
+		if err := trash(dataDir); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return err
+	}
+	if _, err := sysd.EnsureMountUnitFileWithOptions(dataLocationMountUnitOptions(instanceName, newLocation)); err != nil {
+		return fmt.Errorf("cannot mount data of snap %q: %v", instanceName, err)
+	}
+	return nil
+}
+
+// ClearRelocatedSnapData removes the copy of the system data of the snap
+// left behind at the given location when it was relocated.
+func (b Backend) ClearRelocatedSnapData(instanceName, oldLocation string) error {
+	if err := clearTrash(snap.BaseDataDir(instanceName)); err != nil {
+		return err
+	}
+	if oldLocation == "" {
+		return nil
+	}
+	return os.RemoveAll(relocatedDataDir(instanceName, oldLocation))
+}
+
+// RemoveSnapDataLocation unmounts the relocated system data of the snap,
+// once the snap is removed, and removes its now empty directory at the
+// given location.
+func (b Backend) RemoveSnapDataLocation(instanceName, location string, meter progress.Meter) error {
+	if location == "" {
+		return nil
+	}
+	sysd := systemd.New(systemd.SystemMode, meter)
+	if err := sysd.RemoveMountUnitFile(snap.BaseDataDir(instanceName)); err != nil {
+		return fmt.Errorf("cannot unmount data of snap %q: %v", instanceName, err)
+	}
+	if err := os.Remove(relocatedDataDir(instanceName, location)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package backend_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/snapstate/backend"
+	"github.com/snapcore/snapd/progress"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/systemd"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type dataLocationSuite struct {
+	testutil.BaseTest
+
+	be   backend.Backend
+	sysd *FakeSystemd
+	vol  string
+}
+
+var _ = Suite(&dataLocationSuite{})
+
+func (s *dataLocationSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+
+	s.sysd = &FakeSystemd{}
+	s.AddCleanup(systemd.MockNewSystemd(func(be systemd.Backend, roodDir string, mode systemd.InstanceMode, meter systemd.Reporter) systemd.Systemd {
+		return s.sysd
+	}))
+	s.vol = filepath.Join(dirs.GlobalRootDir, "/mnt/data")
+
+	c.Assert(os.MkdirAll(filepath.Join(snap.BaseDataDir("foo"), "common"), 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(snap.BaseDataDir("foo"), "common", "db"), []byte("data"), 0644), IsNil)
+}
+
+func (s *dataLocationSuite) TestRelocateSnapData(c *C) {
+	err := s.be.RelocateSnapData("foo", "", s.vol, progress.Null)
+	c.Assert(err, IsNil)
+
+	c.Check(filepath.Join(s.vol, "foo/common/db"), testutil.FileEquals, "data")
+	// the original data is kept aside, behind an empty mount point
+	c.Check(filepath.Join(snap.BaseDataDir("foo")+".old", "common/db"), testutil.FileEquals, "data")
+	c.Check(filepath.Join(snap.BaseDataDir("foo"), "common"), testutil.FileAbsent)
+	c.Check(s.sysd.RemoveMountUnitFileCalls, HasLen, 0)
+	c.Check(s.sysd.EnsureMountUnitFileWithOptionsCalls, DeepEquals, []*systemd.MountUnitOptions{{
+		Lifetime:    systemd.Persistent,
+		Description: "Mount unit for the data of snap foo",
+		What:        "/mnt/data/foo",
+		Where:       "/var/snap/foo",
+		Fstype:      "none",
+		Options:     []string{"bind"},
+		Origin:      "data-location",
+	}})
+
+	c.Assert(s.be.ClearRelocatedSnapData("foo", ""), IsNil)
+	c.Check(snap.BaseDataDir("foo")+".old", testutil.FileAbsent)
+	c.Check(filepath.Join(s.vol, "foo/common/db"), testutil.FileEquals, "data")
+}
+
+func (s *dataLocationSuite) TestRelocateSnapDataToOtherVolume(c *C) {
+	other := filepath.Join(dirs.GlobalRootDir, "/mnt/other")
+	c.Assert(os.MkdirAll(filepath.Join(s.vol, "foo"), 0755), IsNil)
+
+	// the data in /var/snap/foo stands for the bind mounted one
+	err := s.be.RelocateSnapData("foo", s.vol, other, progress.Null)
+	c.Assert(err, IsNil)
+
+	c.Check(filepath.Join(other, "foo/common/db"), testutil.FileEquals, "data")
+	c.Check(s.sysd.RemoveMountUnitFileCalls, DeepEquals, []string{snap.BaseDataDir("foo")})
+	c.Assert(s.sysd.EnsureMountUnitFileWithOptionsCalls, HasLen, 1)
+	c.Check(s.sysd.EnsureMountUnitFileWithOptionsCalls[0].What, Equals, "/mnt/other/foo")
+
+	c.Assert(s.be.ClearRelocatedSnapData("foo", s.vol), IsNil)
+	c.Check(filepath.Join(s.vol, "foo"), testutil.FileAbsent)
+}
+
+func (s *dataLocationSuite) TestRelocateSnapDataBackToDefault(c *C) {
+	c.Assert(os.RemoveAll(snap.BaseDataDir("foo")), IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(s.vol, "foo/common"), 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(s.vol, "foo/common/db"), []byte("relocated"), 0644), IsNil)
+
+	err := s.be.RelocateSnapData("foo", s.vol, "", progress.Null)
+	c.Assert(err, IsNil)
+
+	c.Check(s.sysd.RemoveMountUnitFileCalls, DeepEquals, []string{snap.BaseDataDir("foo")})
+	c.Check(s.sysd.EnsureMountUnitFileWithOptionsCalls, HasLen, 0)
+	c.Check(filepath.Join(snap.BaseDataDir("foo"), "common/db"), testutil.FileEquals, "relocated")
+
+	c.Assert(s.be.ClearRelocatedSnapData("foo", s.vol), IsNil)
+	c.Check(filepath.Join(s.vol, "foo"), testutil.FileAbsent)
+	c.Check(filepath.Join(snap.BaseDataDir("foo"), "common/db"), testutil.FileEquals, "relocated")
+}
+
+func (s *dataLocationSuite) TestRelocateSnapDataMountError(c *C) {
+	s.sysd.EnsureMountUnitFileResult = ResultForEnsureMountUnitFile{"", errors.New("boom")}
+
+	err := s.be.RelocateSnapData("foo", "", s.vol, progress.Null)
+	c.Check(err, ErrorMatches, `cannot mount data of snap "foo": boom`)
+}
+
+func (s *dataLocationSuite) TestRemoveSnapDataLocation(c *C) {
+	c.Assert(os.MkdirAll(filepath.Join(s.vol, "foo"), 0755), IsNil)
+
+	c.Assert(s.be.RemoveSnapDataLocation("foo", s.vol, progress.Null), IsNil)
+	c.Check(s.sysd.RemoveMountUnitFileCalls, DeepEquals, []string{snap.BaseDataDir("foo")})
+	c.Check(filepath.Join(s.vol, "foo"), testutil.FileAbsent)
+
+	// nothing to do for data in the default location
+	s.sysd.RemoveMountUnitFileCalls = nil
+	c.Assert(s.be.RemoveSnapDataLocation("foo", "", progress.Null), IsNil)
+	c.Check(s.sysd.RemoveMountUnitFileCalls, HasLen, 0)
+}
@@ -20,26 +20,117 @@
 package backend
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/progress"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/integrity"
 	"github.com/snapcore/snapd/systemd"
 )
 
-func addMountUnit(c snap.ContainerPlaceInfo, preseed bool, meter progress.Meter) error {
+func addMountUnit(c snap.ContainerPlaceInfo, preseed, verity bool, meter progress.Meter) error {
 	squashfsPath := dirs.StripRootDir(c.MountFile())
 	whereDir := dirs.StripRootDir(c.MountDir())
 
-	var sysd systemd.Systemd
 	if preseed {
-		sysd = systemd.NewEmulationMode(dirs.GlobalRootDir)
-	} else {
-		sysd = systemd.New(systemd.SystemMode, meter)
+		sysd := systemd.NewEmulationMode(dirs.GlobalRootDir)
+		_, err := sysd.EnsureMountUnitFile(c.MountDescription(), squashfsPath, whereDir, "squashfs")
+		return err
+	}
+
+	sysd := systemd.New(systemd.SystemMode, meter)
+	if !verity {
+		_, err := sysd.EnsureMountUnitFile(c.MountDescription(), squashfsPath, whereDir, "squashfs")
+		return err
+	}
+	unitOptions, err := MountUnitOptions(c, verity)
+	if err != nil {
+		return err
 	}
-	_, err := sysd.EnsureMountUnitFile(c.MountDescription(), squashfsPath, whereDir, "squashfs")
+	_, err = sysd.EnsureMountUnitFileWithOptions(unitOptions)
 	return err
 }
 
+// MountUnitOptions returns the options of the mount unit of the given
+// container. If verity is set, the container is mounted through dm-verity
+// using the dm-verity data shipped with it or generated at installation.
+func MountUnitOptions(c snap.ContainerPlaceInfo, verity bool) (*systemd.MountUnitOptions, error) {
+	squashfsPath := dirs.StripRootDir(c.MountFile())
+	whereDir := dirs.StripRootDir(c.MountDir())
+
+	unitOptions := systemd.PersistentMountUnitOptions(c.MountDescription(), squashfsPath, whereDir, "squashfs")
+	// dm-verity is set up by libmount, which is not involved in fuse
+	// or bind mounts
+	if !verity || unitOptions.Fstype != "squashfs" {
+		return unitOptions, nil
+	}
+	vd, err := integrity.FindVerityData(c.MountFile())
+	if err != nil {
+		return nil, fmt.Errorf("cannot mount %s through dm-verity: %v", c.MountFile(), err)
+	}
+	vd.HashDevice = dirs.StripRootDir(vd.HashDevice)
+	unitOptions.Options = append(unitOptions.Options, vd.MountOptions()...)
+	return unitOptions, nil
+}
+
+// ensureVerityData generates dm-verity data for the given snap file unless
+// it ships its own.
+func ensureVerityData(snapPath string) error {
+	if osutil.IsDirectory(snapPath) {
+		return nil
+	}
+	_, err := integrity.FindVerityData(snapPath)
+	if err != integrity.ErrNoIntegrityData {
+		return err
+	}
+	if err := integrity.GenerateVerityFile(snapPath); err != nil {
+		return fmt.Errorf("cannot generate dm-verity data for %s: %v", snapPath, err)
+	}
+	return nil
+}
+
+// Verity status of a mounted snap as returned by VerityStatus.
+const (
+	// VerityShipped means that the snap is mounted through dm-verity
+	// using the dm-verity data shipped within the snap file.
+	VerityShipped = "shipped"
+	// VerityGenerated means that the snap is mounted through dm-verity
+	// using dm-verity data generated when it was installed.
+	VerityGenerated = "generated"
+)
+
+// VerityStatus returns how the given container is protected by dm-verity
+// according to its mount unit, or the empty string if it is not.
+func VerityStatus(c snap.ContainerPlaceInfo) string {
+	unit := systemd.ExistingMountUnitPath(dirs.StripRootDir(c.MountDir()))
+	if unit == "" {
+		return ""
+	}
+	content, err := os.ReadFile(unit)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if !strings.HasPrefix(line, "Options=") {
+			continue
+		}
+		for _, opt := range strings.Split(strings.TrimPrefix(line, "Options="), ",") {
+			if !strings.HasPrefix(opt, "verity.hashdevice=") {
+				continue
+			}
+			if strings.TrimPrefix(opt, "verity.hashdevice=") == dirs.StripRootDir(c.MountFile()) {
+				return VerityShipped
+			}
+			return VerityGenerated
+		}
+	}
+	return ""
+}
+
 func removeMountUnit(mountDir string, meter progress.Meter) error {
 	sysd := systemd.New(systemd.SystemMode, meter)
 	return sysd.RemoveMountUnitFile(mountDir)
@@ -22,13 +22,19 @@ package backend_test
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/osutil/squashfs"
 	"github.com/snapcore/snapd/overlord/snapstate/backend"
 	"github.com/snapcore/snapd/progress"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/integrity"
+	"github.com/snapcore/snapd/snap/integrity/dmverity"
 	"github.com/snapcore/snapd/systemd"
 	"github.com/snapcore/snapd/testutil"
 )
@@ -48,6 +54,8 @@ type FakeSystemd struct {
 	EnsureMountUnitFileCalls  []ParamsForEnsureMountUnitFile
 	EnsureMountUnitFileResult ResultForEnsureMountUnitFile
 
+	EnsureMountUnitFileWithOptionsCalls []*systemd.MountUnitOptions
+
 	RemoveMountUnitFileCalls  []string
 	RemoveMountUnitFileResult error
 
@@ -61,6 +69,11 @@ func (s *FakeSystemd) EnsureMountUnitFile(description, what, where, fstype strin
 	return s.EnsureMountUnitFileResult.path, s.EnsureMountUnitFileResult.err
 }
 
+func (s *FakeSystemd) EnsureMountUnitFileWithOptions(unitOptions *systemd.MountUnitOptions) (string, error) {
+	s.EnsureMountUnitFileWithOptionsCalls = append(s.EnsureMountUnitFileWithOptionsCalls, unitOptions)
+	return s.EnsureMountUnitFileResult.path, s.EnsureMountUnitFileResult.err
+}
+
 func (s *FakeSystemd) RemoveMountUnitFile(mountDir string) error {
 	s.RemoveMountUnitFileCalls = append(s.RemoveMountUnitFileCalls, mountDir)
 	return s.RemoveMountUnitFileResult
@@ -114,7 +127,7 @@ func (s *mountunitSuite) TestAddMountUnit(c *C) {
 		Version:       "1.1",
 		Architectures: []string{"all"},
 	}
-	err := backend.AddMountUnit(info, false, progress.Null)
+	err := backend.AddMountUnit(info, false, false, progress.Null)
 	c.Check(err, Equals, expectedErr)
 
 	// ensure correct parameters
@@ -129,6 +142,84 @@ func (s *mountunitSuite) TestAddMountUnit(c *C) {
 	})
 }
 
+func mockVerityFile(c *C, snapPath, rootHash string) {
+	header, err := integrity.IntegrityDataHeader{
+		Type:     "integrity",
+		Size:     integrity.HeaderSize + 4096,
+		DmVerity: dmverity.Info{RootHash: rootHash},
+	}.Encode()
+	c.Assert(err, IsNil)
+	c.Assert(os.MkdirAll(filepath.Dir(snapPath), 0755), IsNil)
+	c.Assert(os.WriteFile(integrity.VerityFilePath(snapPath), append(header, make([]byte, 4096)...), 0600), IsNil)
+}
+
+func (s *mountunitSuite) TestAddMountUnitVerity(c *C) {
+	restore := squashfs.MockNeedsFuse(false)
+	defer restore()
+	restore = osutil.MockMountInfo("")
+	defer restore()
+
+	var sysd *FakeSystemd
+	restore = systemd.MockNewSystemd(func(be systemd.Backend, roodDir string, mode systemd.InstanceMode, meter systemd.Reporter) systemd.Systemd {
+		sysd = &FakeSystemd{}
+		return sysd
+	})
+	defer restore()
+
+	info := &snap.Info{
+		SideInfo: snap.SideInfo{
+			RealName: "foo",
+			Revision: snap.R(13),
+		},
+	}
+	err := backend.AddMountUnit(info, false, true, progress.Null)
+	c.Check(err, ErrorMatches, `cannot mount .*/var/lib/snapd/snaps/foo_13.snap through dm-verity: open .*: no such file or directory`)
+
+	c.Assert(os.MkdirAll(dirs.SnapBlobDir, 0755), IsNil)
+	c.Assert(os.WriteFile(info.MountFile(), make([]byte, 16384), 0644), IsNil)
+	mockVerityFile(c, info.MountFile(), "abcd")
+
+	err = backend.AddMountUnit(info, false, true, progress.Null)
+	c.Assert(err, IsNil)
+	c.Check(sysd.EnsureMountUnitFileCalls, HasLen, 0)
+	c.Assert(sysd.EnsureMountUnitFileWithOptionsCalls, HasLen, 1)
+	unitOptions := sysd.EnsureMountUnitFileWithOptionsCalls[0]
+	c.Check(unitOptions.Lifetime, Equals, systemd.Persistent)
+	c.Check(unitOptions.What, Equals, "/var/lib/snapd/snaps/foo_13.snap")
+	c.Check(unitOptions.Fstype, Equals, "squashfs")
+	c.Check(unitOptions.Options[len(unitOptions.Options)-3:], DeepEquals, []string{
+		"verity.roothash=abcd",
+		"verity.hashdevice=/var/lib/snapd/snaps/foo_13.snap.verity",
+		"verity.hashoffset=4096",
+	})
+}
+
+func (s *mountunitSuite) TestVerityStatus(c *C) {
+	info := &snap.Info{
+		SideInfo: snap.SideInfo{
+			RealName: "foo",
+			Revision: snap.R(13),
+		},
+	}
+	unit := systemd.MountUnitPath(dirs.StripRootDir(info.MountDir()))
+	c.Assert(os.MkdirAll(filepath.Dir(unit), 0755), IsNil)
+
+	for _, tc := range []struct {
+		options string
+		status  string
+	}{
+		{"nodev,ro,x-gdu.hide,x-gvfs-hide", ""},
+		{"nodev,ro,verity.roothash=abcd,verity.hashdevice=/var/lib/snapd/snaps/foo_13.snap,verity.hashoffset=20480", backend.VerityShipped},
+		{"nodev,ro,verity.roothash=abcd,verity.hashdevice=/var/lib/snapd/snaps/foo_13.snap.verity,verity.hashoffset=4096", backend.VerityGenerated},
+	} {
+		c.Assert(os.WriteFile(unit, []byte("[Mount]\nWhat=/var/lib/snapd/snaps/foo_13.snap\nOptions="+tc.options+"\n"), 0644), IsNil)
+		c.Check(backend.VerityStatus(info), Equals, tc.status, Commentf("%s", tc.options))
+	}
+
+	c.Assert(os.Remove(unit), IsNil)
+	c.Check(backend.VerityStatus(info), Equals, "")
+}
+
 func (s *mountunitSuite) TestRemoveMountUnit(c *C) {
 	expectedErr := errors.New("removal error")
 
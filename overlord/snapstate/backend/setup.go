@@ -29,6 +29,7 @@ import (
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/progress"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/integrity"
 )
 
 // InstallRecord keeps a record of what installation effectively did as hints
@@ -41,6 +42,9 @@ type InstallRecord struct {
 
 type SetupSnapOptions struct {
 	SkipKernelExtraction bool
+	// Verity requests mounting the snap through dm-verity, generating
+	// the dm-verity data if the snap does not ship them.
+	Verity bool
 }
 
 // SetupSnap does prepare and mount the snap for further processing.
@@ -90,15 +94,28 @@ func (b Backend) SetupSnap(snapFilePath, instanceName string, sideInfo *snap.Sid
 		opts.MustNotCrossDevices = true
 	}
 
+	// the steps are installing the snap file, mounting it and extracting
+	// the kernel assets
+	meter.Start(fmt.Sprintf("Setup snap %q", s.InstanceName()), 3)
+
 	var didNothing bool
 	if didNothing, err = snapf.Install(s.MountFile(), instdir, opts); err != nil {
 		return snapType, nil, err
 	}
+	meter.Set(1)
+
+	verity := setupOpts.Verity && !b.preseed
+	if verity {
+		if err := ensureVerityData(s.MountFile()); err != nil {
+			return snapType, nil, err
+		}
+	}
 
 	// generate the mount unit for the squashfs
-	if err := addMountUnit(s, b.preseed, meter); err != nil {
+	if err := addMountUnit(s, b.preseed, verity, meter); err != nil {
 		return snapType, nil, err
 	}
+	meter.Set(2)
 
 	t := s.Type()
 	if !setupOpts.SkipKernelExtraction {
@@ -107,6 +124,8 @@ func (b Backend) SetupSnap(snapFilePath, instanceName string, sideInfo *snap.Sid
 		}
 	}
 
+	meter.Finished()
+
 	installRecord = &InstallRecord{TargetSnapExisted: didNothing}
 	return t, installRecord, nil
 }
@@ -153,7 +172,7 @@ func (b Backend) SetupComponent(compFilePath string, compPi snap.ContainerPlaceI
 	}
 
 	// generate the mount unit for the squashfs
-	if err := addMountUnit(compPi, b.preseed, meter); err != nil {
+	if err := addMountUnit(compPi, b.preseed, false, meter); err != nil {
 		return nil, err
 	}
 
@@ -193,6 +212,11 @@ func (b Backend) RemoveSnapFiles(s snap.PlaceInfo, typ snap.Type, installRecord
 		}
 	}
 
+	// remove the dm-verity data generated for the snap (if any)
+	if err := os.RemoveAll(integrity.VerityFilePath(snapPath)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
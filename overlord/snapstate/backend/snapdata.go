@@ -29,6 +29,7 @@ import (
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/progress"
 	"github.com/snapcore/snapd/snap"
 )
 
@@ -155,7 +156,7 @@ func snapCommonDataDirs(snap *snap.Info, opts *dirs.SnapDirOptions) ([]string, e
 
 // Copy all data for oldSnap to newSnap
 // (but never overwrite)
-func copySnapData(oldSnap, newSnap *snap.Info, opts *dirs.SnapDirOptions) (err error) {
+func copySnapData(oldSnap, newSnap *snap.Info, opts *dirs.SnapDirOptions, meter progress.Meter) (err error) {
 	oldDataDirs, err := snapDataDirs(oldSnap, opts)
 	if err != nil {
 		return err
@@ -176,15 +177,19 @@ func copySnapData(oldSnap, newSnap *snap.Info, opts *dirs.SnapDirOptions) (err e
 		}
 	}()
 
+	// each data directory is a step
+	meter.Start(fmt.Sprintf("Copy snap %q data", newSnap.InstanceName()), float64(len(oldDataDirs)))
 	newSuffix := filepath.Base(newSnap.DataDir())
-	for _, oldDir := range oldDataDirs {
+	for i, oldDir := range oldDataDirs {
 		// replace the trailing "../$old-suffix" with the "../$new-suffix"
 		newDir := filepath.Join(filepath.Dir(oldDir), newSuffix)
 		if err := copySnapDataDirectory(oldDir, newDir); err != nil {
 			return err
 		}
 		done = append(done, newDir)
+		meter.Set(float64(i + 1))
 	}
+	meter.Finished()
 
 	return nil
 }
@@ -26,9 +26,11 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	. "gopkg.in/check.v1"
 
@@ -72,6 +74,7 @@ type fakeOp struct {
 	otherInstances         bool
 	unlinkFirstInstallUndo bool
 	skipKernelExtraction   bool
+	verity                 bool
 
 	services         []string
 	disabledServices []string
@@ -163,6 +166,7 @@ type fakeStore struct {
 
 	downloads           []fakeDownload
 	refreshRevnos       map[string]snap.Revision
+	refreshCreatedAt    map[string]time.Time
 	fakeBackend         *fakeSnappyBackend
 	fakeCurrentProgress int
 	fakeTotalProgress   int
@@ -172,6 +176,9 @@ type fakeStore struct {
 	seenPrivacyKeys map[string]bool
 
 	downloadCallback func()
+
+	// channels without any revision released for refreshes
+	emptyChannels map[string]bool
 }
 
 func (f *fakeStore) pokeStateLock() {
@@ -434,6 +441,10 @@ func (f *fakeStore) lookupRefresh(cand refreshCand) (*snap.Info, error) {
 		panic(fmt.Sprintf("refresh: unknown snap-id: %s", cand.snapID))
 	}
 
+	if f.emptyChannels[cand.channel] {
+		return nil, &store.RevisionNotAvailableError{Action: "refresh", Channel: cand.channel}
+	}
+
 	revno := snap.R(11)
 	if r := f.refreshRevnos[cand.snapID]; !r.Unset() {
 		revno = r
@@ -467,6 +478,7 @@ func (f *fakeStore) lookupRefresh(cand refreshCand) (*snap.Info, error) {
 		Architectures: []string{"all"},
 		Epoch:         epoch,
 		Base:          base,
+		CreatedAt:     f.refreshCreatedAt[cand.snapID],
 	}
 
 	if name == "outdated-consumer" {
@@ -667,7 +679,7 @@ func (f *fakeStore) SnapAction(ctx context.Context, currentSnaps []*store.Curren
 			userID: userID,
 		})
 
-		if err == store.ErrNoUpdateAvailable {
+		if _, ok := err.(*store.RevisionNotAvailableError); ok || err == store.ErrNoUpdateAvailable {
 			refreshErrors[cur.InstanceName] = err
 			continue
 		}
@@ -723,7 +735,7 @@ func (f *fakeStore) Download(ctx context.Context, name, targetFn string, snapInf
 		macaroon = user.StoreMacaroon
 	}
 	// only add the options if they contain anything interesting
-	if dlOpts != nil && *dlOpts == (store.DownloadOptions{}) {
+	if dlOpts != nil && reflect.DeepEqual(*dlOpts, store.DownloadOptions{}) {
 		dlOpts = nil
 	}
 	f.downloads = append(f.downloads, fakeDownload{
@@ -884,6 +896,7 @@ func (f *fakeSnappyBackend) SetupSnap(snapFilePath, instanceName string, si *sna
 		revno: revno,
 
 		skipKernelExtraction: opts != nil && opts.SkipKernelExtraction,
+		verity:               opts != nil && opts.Verity,
 	})
 	snapType := snap.TypeApp
 	switch si.RealName {
@@ -1413,6 +1426,21 @@ func (f *fakeSnappyBackend) UndoInitExposedSnapHome(snapName string, undoInfo *b
 	return f.maybeErrForLastOp()
 }
 
+func (f *fakeSnappyBackend) RelocateSnapData(instanceName, oldLocation, newLocation string, meter progress.Meter) error {
+	f.appendOp(&fakeOp{op: "relocate-snap-data", name: instanceName, old: oldLocation, path: newLocation})
+	return f.maybeErrForLastOp()
+}
+
+func (f *fakeSnappyBackend) ClearRelocatedSnapData(instanceName, oldLocation string) error {
+	f.appendOp(&fakeOp{op: "clear-relocated-snap-data", name: instanceName, old: oldLocation})
+	return f.maybeErrForLastOp()
+}
+
+func (f *fakeSnappyBackend) RemoveSnapDataLocation(instanceName, location string, meter progress.Meter) error {
+	f.appendOp(&fakeOp{op: "remove-snap-data-location", name: instanceName, path: location})
+	return f.maybeErrForLastOp()
+}
+
 func (f *fakeSnappyBackend) InitXDGDirs(info *snap.Info) error {
 	f.appendOp(&fakeOp{op: "init-xdg-dirs", name: info.InstanceName()})
 	return f.maybeErrForLastOp()
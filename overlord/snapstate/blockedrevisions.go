@@ -0,0 +1,116 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"errors"
+	"time"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// BlockedRevision is a revision of a snap that refreshes must not move to,
+// typically because it is known to be broken.
+type BlockedRevision struct {
+	Revision snap.Revision `json:"revision"`
+	// Until is the time at which the block expires, if unset the revision
+	// stays blocked until explicitly unblocked.
+	Until *time.Time `json:"until,omitempty"`
+}
+
+func (b *BlockedRevision) expired(now time.Time) bool {
+	return b.Until != nil && !now.Before(*b.Until)
+}
+
+// IsRevisionBlocked returns whether refreshing to the given revision is
+// currently blocked.
+func (snapst *SnapState) IsRevisionBlocked(rev snap.Revision) bool {
+	now := timeNow()
+	for _, b := range snapst.BlockedRevisions {
+		if b.Revision == rev && !b.expired(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockRevision blocks refreshes of the given snap to the given revision
+// until the given time, or until unblocked if until is zero. Refreshes
+// following a channel skip the revision even if the channel points to it.
+// Blocking an already blocked revision updates the expiry of the block.
+func BlockRevision(st *state.State, instanceName string, rev snap.Revision, until time.Time) error {
+	if rev.Unset() {
+		return errors.New("cannot block an unset revision")
+	}
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return &snap.NotInstalledError{Snap: instanceName}
+		}
+		return err
+	}
+
+	block := &BlockedRevision{Revision: rev}
+	if !until.IsZero() {
+		block.Until = &until
+	}
+	now := timeNow()
+	blocked := []*BlockedRevision{block}
+	for _, b := range snapst.BlockedRevisions {
+		// drop expired blocks while at it
+		if b.Revision != rev && !b.expired(now) {
+			blocked = append(blocked, b)
+		}
+	}
+	snapst.BlockedRevisions = blocked
+	Set(st, instanceName, &snapst)
+	logger.Noticef("revision %s of snap %q blocked from refreshes", rev, instanceName)
+	return nil
+}
+
+// UnblockRevision allows refreshes of the given snap to the given revision
+// again. Unblocking a revision that is not blocked is not an error.
+func UnblockRevision(st *state.State, instanceName string, rev snap.Revision) error {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return &snap.NotInstalledError{Snap: instanceName}
+		}
+		return err
+	}
+
+	blocked := make([]*BlockedRevision, 0, len(snapst.BlockedRevisions))
+	for _, b := range snapst.BlockedRevisions {
+		if b.Revision != rev {
+			blocked = append(blocked, b)
+		}
+	}
+	if len(blocked) == len(snapst.BlockedRevisions) {
+		return nil
+	}
+	if len(blocked) == 0 {
+		blocked = nil
+	}
+	snapst.BlockedRevisions = blocked
+	Set(st, instanceName, &snapst)
+	return nil
+}
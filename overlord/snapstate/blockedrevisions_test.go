@@ -0,0 +1,151 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"context"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/store"
+)
+
+func (s *snapmgrTestSuite) mockSnapForBlockedRevisions(c *C) {
+	si := snap.SideInfo{
+		RealName: "some-snap",
+		Revision: snap.R(1),
+		SnapID:   "some-snap-id",
+	}
+	snaptest.MockSnap(c, `name: some-snap`, &si)
+
+	snapstate.Set(s.state, "some-snap", &snapstate.SnapState{
+		Active:          true,
+		Sequence:        snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{&si}),
+		Current:         si.Revision,
+		SnapType:        "app",
+		TrackingChannel: "latest/stable",
+	})
+}
+
+func (s *snapmgrTestSuite) TestBlockRevision(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	restore := snapstate.MockTimeNow(func() time.Time { return now })
+	defer restore()
+
+	s.mockSnapForBlockedRevisions(c)
+
+	until := now.Add(time.Hour)
+	c.Assert(snapstate.BlockRevision(s.state, "some-snap", snap.R(11), time.Time{}), IsNil)
+	c.Assert(snapstate.BlockRevision(s.state, "some-snap", snap.R(12), until), IsNil)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	c.Check(snapst.BlockedRevisions, DeepEquals, []*snapstate.BlockedRevision{
+		{Revision: snap.R(12), Until: &until},
+		{Revision: snap.R(11)},
+	})
+	c.Check(snapst.IsRevisionBlocked(snap.R(11)), Equals, true)
+	c.Check(snapst.IsRevisionBlocked(snap.R(12)), Equals, true)
+	c.Check(snapst.IsRevisionBlocked(snap.R(13)), Equals, false)
+
+	// blocking again updates the expiry
+	c.Assert(snapstate.BlockRevision(s.state, "some-snap", snap.R(11), until), IsNil)
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	c.Check(snapst.BlockedRevisions, DeepEquals, []*snapstate.BlockedRevision{
+		{Revision: snap.R(11), Until: &until},
+		{Revision: snap.R(12), Until: &until},
+	})
+
+	// blocks expire
+	now = until
+	c.Check(snapst.IsRevisionBlocked(snap.R(11)), Equals, false)
+	c.Check(snapst.IsRevisionBlocked(snap.R(12)), Equals, false)
+
+	// and expired blocks are dropped on the next change
+	c.Assert(snapstate.BlockRevision(s.state, "some-snap", snap.R(13), time.Time{}), IsNil)
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	c.Check(snapst.BlockedRevisions, DeepEquals, []*snapstate.BlockedRevision{
+		{Revision: snap.R(13)},
+	})
+
+	c.Assert(snapstate.UnblockRevision(s.state, "some-snap", snap.R(11)), IsNil)
+	c.Assert(snapstate.UnblockRevision(s.state, "some-snap", snap.R(13)), IsNil)
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	c.Check(snapst.BlockedRevisions, IsNil)
+}
+
+func (s *snapmgrTestSuite) TestBlockRevisionErrors(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	err := snapstate.BlockRevision(s.state, "some-snap", snap.R(11), time.Time{})
+	c.Check(err, DeepEquals, &snap.NotInstalledError{Snap: "some-snap"})
+	err = snapstate.UnblockRevision(s.state, "some-snap", snap.R(11))
+	c.Check(err, DeepEquals, &snap.NotInstalledError{Snap: "some-snap"})
+
+	s.mockSnapForBlockedRevisions(c)
+	err = snapstate.BlockRevision(s.state, "some-snap", snap.Revision{}, time.Time{})
+	c.Check(err, ErrorMatches, "cannot block an unset revision")
+}
+
+func (s *snapmgrTestSuite) TestUpdateSkipsBlockedRevision(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapForBlockedRevisions(c)
+	c.Assert(snapstate.BlockRevision(s.state, "some-snap", snap.R(11), time.Time{}), IsNil)
+
+	_, err := snapstate.Update(s.state, "some-snap", &snapstate.RevisionOptions{}, s.user.ID, snapstate.Flags{})
+	c.Check(err, Equals, store.ErrNoUpdateAvailable)
+
+	updates, _, err := snapstate.UpdateMany(context.Background(), s.state, []string{"some-snap"}, nil, s.user.ID, nil)
+	c.Assert(err, IsNil)
+	c.Check(updates, HasLen, 0)
+
+	// an explicitly requested revision is not subject to the block
+	ts, err := snapstate.Update(s.state, "some-snap", &snapstate.RevisionOptions{Revision: snap.R(11)}, s.user.ID, snapstate.Flags{})
+	c.Assert(err, IsNil)
+	c.Check(ts.Tasks(), Not(HasLen), 0)
+}
+
+func (s *snapmgrTestSuite) TestUpdateBlockedRevisionExpired(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	restore := snapstate.MockTimeNow(func() time.Time { return now })
+	defer restore()
+
+	s.mockSnapForBlockedRevisions(c)
+	c.Assert(snapstate.BlockRevision(s.state, "some-snap", snap.R(11), now.Add(time.Hour)), IsNil)
+
+	now = now.Add(2 * time.Hour)
+	updates, _, err := snapstate.UpdateMany(context.Background(), s.state, []string{"some-snap"}, nil, s.user.ID, nil)
+	c.Assert(err, IsNil)
+	c.Check(updates, DeepEquals, []string{"some-snap"})
+}
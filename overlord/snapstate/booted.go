@@ -35,7 +35,8 @@ import (
 // still has the "active" version set to "v2" which is
 // misleading. This code will check what kernel/os booted and set
 // those versions active. To do this it creates a Change and kicks
-// start it directly.
+// start it directly. It also records whether the boot was healthy,
+// i.e. didn't fall back to the previous versions.
 func UpdateBootRevisions(st *state.State) error {
 	const errorPrefix = "cannot update revisions after boot changes: "
 
@@ -45,7 +46,7 @@ func UpdateBootRevisions(st *state.State) error {
 		return fmt.Errorf(errorPrefix+"%s", err)
 	}
 	if !ok {
-		return nil
+		return recordBoot(st, true)
 	}
 
 	deviceCtx, err := DeviceCtx(st, nil, nil)
@@ -80,6 +81,12 @@ func UpdateBootRevisions(st *state.State) error {
 		}
 	}
 
+	// falling back to the previous revisions of the boot snaps makes for an
+	// unhealthy boot
+	if err := recordBoot(st, len(tsAll) == 0); err != nil {
+		return fmt.Errorf(errorPrefix+"%s", err)
+	}
+
 	if len(tsAll) == 0 {
 		return nil
 	}
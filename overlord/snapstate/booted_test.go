@@ -308,6 +308,41 @@ func (bs *bootedSuite) TestUpdateBootRevisionsOSErrorsLate(c *C) {
 	c.Assert(chg.Err(), ErrorMatches, `(?ms).*Make snap "core" \(1\) available to the system \(fail\).*`)
 }
 
+func (bs *bootedSuite) TestUpdateBootRevisionsRecordsBootHealth(c *C) {
+	st := bs.state
+	st.Lock()
+	defer st.Unlock()
+
+	bs.makeInstalledKernelOS(c, st)
+
+	bootID := "boot-id-1"
+	restore := snapstate.MockOsutilBootID(func() (string, error) { return bootID, nil })
+	defer restore()
+
+	bs.bootloader.SetBootBase("core_2.snap")
+	bs.bootloader.SetBootKernel("canonical-pc-linux_2.snap")
+	c.Assert(snapstate.UpdateBootRevisions(st), IsNil)
+	// the same boot is counted only once
+	c.Assert(snapstate.UpdateBootRevisions(st), IsNil)
+	bootID = "boot-id-2"
+	c.Assert(snapstate.UpdateBootRevisions(st), IsNil)
+	c.Check(st.Changes(), HasLen, 0)
+
+	streak, err := snapstate.HealthyBootStreak(st)
+	c.Assert(err, IsNil)
+	c.Check(streak, Equals, 2)
+
+	// falling back to the previous base resets the streak
+	bootID = "boot-id-3"
+	bs.bootloader.SetBootBase("core_1.snap")
+	c.Assert(snapstate.UpdateBootRevisions(st), IsNil)
+	c.Check(st.Changes(), HasLen, 1)
+
+	streak, err = snapstate.HealthyBootStreak(st)
+	c.Assert(err, IsNil)
+	c.Check(streak, Equals, 0)
+}
+
 func (bs *bootedSuite) TestFinishRestartCore(c *C) {
 	st := bs.state
 	st.Lock()
@@ -30,6 +30,7 @@ import (
 	"github.com/snapcore/snapd/asserts"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/osutil/squashfs"
 	"github.com/snapcore/snapd/overlord/snapstate/backend"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/release"
@@ -238,6 +239,23 @@ func validateContainer(c snap.Container, s *snap.Info, logf func(format string,
 	return fmt.Errorf("%v; contact developer", err)
 }
 
+// checkCompression ensures that the snap container, if it is a squashfs,
+// uses a compression that can be mounted on this system.
+func checkCompression(c snap.Container) error {
+	sq, ok := c.(interface{ Compression() (string, error) })
+	if !ok {
+		return nil
+	}
+	comp, err := sq.Compression()
+	if err != nil {
+		return err
+	}
+	if comp == "zstd" && !squashfs.SupportsZstd() {
+		return fmt.Errorf("cannot use snap compressed with zstd: not supported by the running kernel")
+	}
+	return nil
+}
+
 // checkSnap ensures that the snap can be installed.
 func checkSnap(st *state.State, snapFilePath, instanceName string, si *snap.SideInfo, curInfo *snap.Info, flags Flags, deviceCtx DeviceContext) error {
 	// This assumes that the snap was already verified or --dangerous was used.
@@ -255,6 +273,10 @@ func checkSnap(st *state.State, snapFilePath, instanceName string, si *snap.Side
 		return err
 	}
 
+	if err := checkCompression(c); err != nil {
+		return err
+	}
+
 	snapName, instanceKey := snap.SplitInstanceName(instanceName)
 	// update instance key to what was requested
 	s.InstanceKey = instanceKey
@@ -30,6 +30,7 @@ import (
 	"github.com/snapcore/snapd/asserts"
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/osutil/squashfs"
 	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
 	"github.com/snapcore/snapd/overlord/state"
@@ -93,6 +94,35 @@ architectures:
 	c.Assert(err.Error(), Equals, errorMsg)
 }
 
+type compressedContainer struct {
+	snap.Container
+	compression string
+}
+
+func (c compressedContainer) Compression() (string, error) {
+	return c.compression, nil
+}
+
+func (s *checkSnapSuite) TestCheckSnapZstdCompression(c *C) {
+	info, err := snap.InfoFromSnapYaml([]byte("name: hello\nversion: 1.10\n"))
+	c.Assert(err, IsNil)
+
+	restore := snapstate.MockOpenSnapFile(func(path string, si *snap.SideInfo) (*snap.Info, snap.Container, error) {
+		return info, compressedContainer{Container: emptyContainer(c), compression: "zstd"}, nil
+	})
+	defer restore()
+
+	restore = squashfs.MockSupportsZstd(false)
+	defer restore()
+	err = snapstate.CheckSnap(s.st, "snap-path", "hello", nil, nil, snapstate.Flags{}, nil)
+	c.Check(err, ErrorMatches, "cannot use snap compressed with zstd: not supported by the running kernel")
+
+	restore = squashfs.MockSupportsZstd(true)
+	defer restore()
+	err = snapstate.CheckSnap(s.st, "snap-path", "hello", nil, nil, snapstate.Flags{}, nil)
+	c.Check(err, IsNil)
+}
+
 var assumesTests = []struct {
 	version string
 	assumes string
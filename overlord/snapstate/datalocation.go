@@ -0,0 +1,202 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// DataLocation returns the directory holding the system data of the given
+// snap if it was relocated, or an empty string if it is in /var/snap.
+func DataLocation(st *state.State, instanceName string) (string, error) {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+		return "", err
+	}
+	return snapst.DataLocation, nil
+}
+
+// RelocateData returns the task set moving the system data of the given
+// snap, that is SNAP_DATA and SNAP_COMMON, to the given directory, usually
+// on another volume. The data is then bind mounted onto /var/snap/<snap>. An
+// empty location moves the data back to /var/snap.
+func RelocateData(st *state.State, instanceName, location string) (*state.TaskSet, error) {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return nil, &snap.NotInstalledError{Snap: instanceName}
+		}
+		return nil, err
+	}
+	if location != "" {
+		if !filepath.IsAbs(location) {
+			return nil, fmt.Errorf("cannot relocate data of snap %q: location %q is not absolute", instanceName, location)
+		}
+		location = filepath.Clean(location)
+		if !osutil.IsDirectory(location) {
+			return nil, fmt.Errorf("cannot relocate data of snap %q: location %q is not a directory", instanceName, location)
+		}
+	}
+	if location == snapst.DataLocation {
+		return nil, fmt.Errorf("cannot relocate data of snap %q: already in %q", instanceName, dataLocationOrDefault(location))
+	}
+	if !snapst.Active {
+		return nil, fmt.Errorf("cannot relocate data of inactive snap %q", instanceName)
+	}
+	if err := CheckChangeConflict(st, instanceName, nil); err != nil {
+		return nil, err
+	}
+
+	si := snapst.CurrentSideInfo()
+	snapsup := &SnapSetup{
+		SideInfo:    si,
+		InstanceKey: snapst.InstanceKey,
+		Type:        snap.Type(snapst.SnapType),
+	}
+
+	var tasks []*state.Task
+	prepare := st.NewTask("prepare-snap", fmt.Sprintf(i18n.G("Prepare snap %q (%s)"), instanceName, si.Revision))
+	prepare.Set("snap-setup", snapsup)
+	tasks = append(tasks, prepare)
+
+	prev := prepare
+	addTask := func(t *state.Task) {
+		t.Set("snap-setup-task", prepare.ID())
+		t.WaitFor(prev)
+		tasks = append(tasks, t)
+		prev = t
+	}
+
+	stop := st.NewTask("stop-snap-services", fmt.Sprintf(i18n.G("Stop snap %q services"), instanceName))
+	stop.Set("stop-reason", "data-relocation")
+	addTask(stop)
+
+	addTask(st.NewTask("unlink-current-snap", fmt.Sprintf(i18n.G("Make current revision for snap %q unavailable"), instanceName)))
+
+	relocate := st.NewTask("relocate-snap-data", fmt.Sprintf(i18n.G("Move data of snap %q to %s"), instanceName, dataLocationOrDefault(location)))
+	relocate.Set("data-location", location)
+	addTask(relocate)
+
+	addTask(st.NewTask("link-snap", fmt.Sprintf(i18n.G("Make snap %q (%s) available to the system"), instanceName, si.Revision)))
+	addTask(st.NewTask("start-snap-services", fmt.Sprintf(i18n.G("Start snap %q (%s) services"), instanceName, si.Revision)))
+
+	ts := state.NewTaskSet(tasks...)
+	ts.JoinLane(st.NewLane())
+	return ts, nil
+}
+
+func dataLocationOrDefault(location string) string {
+	if location == "" {
+		return dirs.StripRootDir(dirs.SnapDataDir)
+	}
+	return location
+}
+
+func (m *SnapManager) doRelocateSnapData(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	snapsup, snapst, err := snapSetupAndState(t)
+	if err != nil {
+		st.Unlock()
+		return err
+	}
+	var location string
+	err = t.Get("data-location", &location)
+	st.Unlock()
+	if err != nil {
+		return err
+	}
+
+	oldLocation := snapst.DataLocation
+	pb := NewTaskProgressAdapterUnlocked(t)
+	if err := m.backend.RelocateSnapData(snapsup.InstanceName(), oldLocation, location, pb); err != nil {
+		return err
+	}
+
+	st.Lock()
+	defer st.Unlock()
+	t.Set("old-data-location", oldLocation)
+	snapst.DataLocation = location
+	Set(st, snapsup.InstanceName(), snapst)
+	return nil
+}
+
+func (m *SnapManager) undoRelocateSnapData(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	snapsup, snapst, err := snapSetupAndState(t)
+	if err != nil {
+		st.Unlock()
+		return err
+	}
+	var oldLocation string
+	err = t.Get("old-data-location", &oldLocation)
+	st.Unlock()
+	if err != nil {
+		return err
+	}
+
+	location := snapst.DataLocation
+	pb := NewTaskProgressAdapterUnlocked(t)
+	if err := m.backend.RelocateSnapData(snapsup.InstanceName(), location, oldLocation, pb); err != nil {
+		return err
+	}
+	// the data is back in its old location, drop the copy made by do
+	if err := m.backend.ClearRelocatedSnapData(snapsup.InstanceName(), location); err != nil {
+		return err
+	}
+
+	st.Lock()
+	defer st.Unlock()
+	snapst.DataLocation = oldLocation
+	Set(st, snapsup.InstanceName(), snapst)
+	return nil
+}
+
+func (m *SnapManager) cleanupRelocateSnapData(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	defer st.Unlock()
+
+	if t.Status() != state.DoneStatus {
+		// it failed or was undone
+		return nil
+	}
+
+	snapsup, err := TaskSnapSetup(t)
+	if err != nil {
+		return err
+	}
+	var oldLocation string
+	if err := t.Get("old-data-location", &oldLocation); err != nil {
+		return err
+	}
+	return m.backend.ClearRelocatedSnapData(snapsup.InstanceName(), oldLocation)
+}
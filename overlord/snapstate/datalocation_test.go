@@ -0,0 +1,198 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/tomb.v2"
+
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+func (s *snapmgrTestSuite) setupDataLocationSnap(c *C, location string) string {
+	si := &snap.SideInfo{RealName: "some-snap", Revision: snap.R(3)}
+	snapstate.Set(s.state, "some-snap", &snapstate.SnapState{
+		Active:       true,
+		Sequence:     snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:      si.Revision,
+		SnapType:     "app",
+		DataLocation: location,
+	})
+
+	vol := filepath.Join(c.MkDir(), "data")
+	c.Assert(os.MkdirAll(vol, 0755), IsNil)
+	return vol
+}
+
+func (s *snapmgrTestSuite) TestRelocateData(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	vol := s.setupDataLocationSnap(c, "")
+
+	chg := s.state.NewChange("relocate-data", "...")
+	ts, err := snapstate.RelocateData(s.state, "some-snap", vol)
+	c.Assert(err, IsNil)
+	c.Check(taskKinds(ts.Tasks()), DeepEquals, []string{
+		"prepare-snap",
+		"stop-snap-services",
+		"unlink-current-snap",
+		"relocate-snap-data",
+		"link-snap",
+		"start-snap-services",
+	})
+	relocate := ts.Tasks()[3]
+	c.Check(relocate.Summary(), Equals, `Move data of snap "some-snap" to `+vol)
+	var reason string
+	c.Assert(ts.Tasks()[1].Get("stop-reason", &reason), IsNil)
+	c.Check(reason, Equals, "data-relocation")
+	chg.AddAll(ts)
+
+	defer s.se.Stop()
+	s.settle(c)
+
+	c.Assert(chg.Err(), IsNil)
+	c.Assert(chg.IsReady(), Equals, true)
+
+	op := s.fakeBackend.ops.MustFindOp(c, "relocate-snap-data")
+	c.Check(op.name, Equals, "some-snap")
+	c.Check(op.old, Equals, "")
+	c.Check(op.path, Equals, vol)
+	op = s.fakeBackend.ops.MustFindOp(c, "clear-relocated-snap-data")
+	c.Check(op.old, Equals, "")
+
+	location, err := snapstate.DataLocation(s.state, "some-snap")
+	c.Assert(err, IsNil)
+	c.Check(location, Equals, vol)
+}
+
+func (s *snapmgrTestSuite) TestRelocateDataUndo(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	vol := s.setupDataLocationSnap(c, "/srv/old")
+
+	chg := s.state.NewChange("relocate-data", "...")
+	ts, err := snapstate.RelocateData(s.state, "some-snap", vol)
+	c.Assert(err, IsNil)
+	chg.AddAll(ts)
+
+	s.o.TaskRunner().AddHandler("fail", func(*state.Task, *tomb.Tomb) error {
+		return errors.New("boom")
+	}, nil)
+	failingTask := s.state.NewTask("fail", "expected failure")
+	chg.AddTask(failingTask)
+	linkTask := findLastTask(chg, "link-snap")
+	failingTask.WaitFor(linkTask)
+	for _, lane := range linkTask.Lanes() {
+		failingTask.JoinLane(lane)
+	}
+
+	defer s.se.Stop()
+	s.settle(c)
+
+	c.Assert(chg.Err(), ErrorMatches, `(.|\s)* expected failure \(boom\)`)
+
+	var relocations []fakeOp
+	for _, op := range s.fakeBackend.ops {
+		if op.op == "relocate-snap-data" || op.op == "clear-relocated-snap-data" {
+			relocations = append(relocations, op)
+		}
+	}
+	c.Check(relocations, DeepEquals, []fakeOp{
+		{op: "relocate-snap-data", name: "some-snap", old: "/srv/old", path: vol},
+		{op: "relocate-snap-data", name: "some-snap", old: vol, path: "/srv/old"},
+		{op: "clear-relocated-snap-data", name: "some-snap", old: vol},
+	})
+
+	location, err := snapstate.DataLocation(s.state, "some-snap")
+	c.Assert(err, IsNil)
+	c.Check(location, Equals, "/srv/old")
+}
+
+func (s *snapmgrTestSuite) TestRelocateDataErrors(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	_, err := snapstate.RelocateData(s.state, "some-snap", "/srv")
+	c.Check(err, ErrorMatches, `snap "some-snap" is not installed`)
+
+	vol := s.setupDataLocationSnap(c, "")
+
+	_, err = snapstate.RelocateData(s.state, "some-snap", "srv")
+	c.Check(err, ErrorMatches, `cannot relocate data of snap "some-snap": location "srv" is not absolute`)
+	_, err = snapstate.RelocateData(s.state, "some-snap", filepath.Join(vol, "missing"))
+	c.Check(err, ErrorMatches, `cannot relocate data of snap "some-snap": location ".*/missing" is not a directory`)
+	_, err = snapstate.RelocateData(s.state, "some-snap", "")
+	c.Check(err, ErrorMatches, `cannot relocate data of snap "some-snap": already in "/var/snap"`)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	snapst.Active = false
+	snapstate.Set(s.state, "some-snap", &snapst)
+	_, err = snapstate.RelocateData(s.state, "some-snap", vol)
+	c.Check(err, ErrorMatches, `cannot relocate data of inactive snap "some-snap"`)
+
+	// the snap is busy
+	snapst.Active = true
+	snapstate.Set(s.state, "some-snap", &snapst)
+	chg := s.state.NewChange("refresh", "...")
+	task := s.state.NewTask("link-snap", "...")
+	task.Set("snap-setup", &snapstate.SnapSetup{SideInfo: &snap.SideInfo{RealName: "some-snap"}})
+	chg.AddTask(task)
+	_, err = snapstate.RelocateData(s.state, "some-snap", vol)
+	c.Check(err, ErrorMatches, `snap "some-snap" has "refresh" change in progress`)
+}
+
+func (s *snapmgrTestSuite) TestRemoveRelocatedData(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setupDataLocationSnap(c, "/srv/data")
+
+	chg := s.state.NewChange("remove", "...")
+	ts, err := snapstate.Remove(s.state, "some-snap", snap.R(0), nil)
+	c.Assert(err, IsNil)
+	chg.AddAll(ts)
+
+	defer s.se.Stop()
+	s.settle(c)
+
+	c.Assert(chg.Err(), IsNil)
+	op := s.fakeBackend.ops.MustFindOp(c, "remove-snap-data-location")
+	c.Check(op.name, Equals, "some-snap")
+	c.Check(op.path, Equals, "/srv/data")
+
+	// the relocated data is unmounted before the data dir is removed
+	var seen []string
+	for _, op := range s.fakeBackend.ops {
+		if op.op == "remove-snap-data-location" || op.op == "remove-snap-data-dir" {
+			seen = append(seen, op.op)
+		}
+	}
+	c.Check(seen, DeepEquals, []string{"remove-snap-data-location", "remove-snap-data-dir"})
+}
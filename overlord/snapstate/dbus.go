@@ -20,12 +20,17 @@
 package snapstate
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/wrappers"
 )
 
+var hostDBusActivationFile = wrappers.HostDBusActivationFile
+
 func getActivatableDBusServices(info *snap.Info) (session, system map[string]bool) {
 	session = make(map[string]bool)
 	system = make(map[string]bool)
@@ -56,6 +61,13 @@ func checkDBusServiceConflicts(st *state.State, info *snap.Info) error {
 		return nil
 	}
 
+	if err := checkHostDBusServiceConflicts(info.InstanceName(), "session", sessionServices); err != nil {
+		return err
+	}
+	if err := checkHostDBusServiceConflicts(info.InstanceName(), "system", systemServices); err != nil {
+		return err
+	}
+
 	stateMap, err := All(st)
 	if err != nil {
 		return err
@@ -84,3 +96,124 @@ func checkDBusServiceConflicts(st *state.State, info *snap.Info) error {
 	}
 	return nil
 }
+
+func checkHostDBusServiceConflicts(instanceName, bus string, services map[string]bool) error {
+	names := make([]string, 0, len(services))
+	for svc := range services {
+		names = append(names, svc)
+	}
+	sort.Strings(names)
+	for _, svc := range names {
+		if path := hostDBusActivationFile(bus, svc); path != "" {
+			return fmt.Errorf("snap %q requesting to activate on %s bus name %q conflicts with host service %q", instanceName, bus, svc, path)
+		}
+	}
+	return nil
+}
+
+// DBusActivation describes a D-Bus name a snap application is activated on.
+type DBusActivation struct {
+	Name string `json:"name"`
+	// Bus is either "session" or "system".
+	Bus  string `json:"bus"`
+	Snap string `json:"snap"`
+	App  string `json:"app"`
+}
+
+func dbusActivationsForSnap(info *snap.Info) ([]*DBusActivation, error) {
+	names, err := wrappers.SnapDBusActivationNames(info)
+	if err != nil {
+		return nil, err
+	}
+	activations := make([]*DBusActivation, 0, len(names))
+	for _, name := range names {
+		activations = append(activations, &DBusActivation{
+			Name: name.Name,
+			Bus:  name.Bus,
+			Snap: info.InstanceName(),
+			App:  name.App.Name,
+		})
+	}
+	sortDBusActivations(activations)
+	return activations, nil
+}
+
+func sortDBusActivations(activations []*DBusActivation) {
+	sort.Slice(activations, func(i, j int) bool {
+		a, b := activations[i], activations[j]
+		if a.Bus != b.Bus {
+			return a.Bus < b.Bus
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Snap < b.Snap
+	})
+}
+
+func dbusActivationsBySnap(st *state.State) (map[string][]*DBusActivation, error) {
+	var activations map[string][]*DBusActivation
+	if err := st.Get("dbus-activations", &activations); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	return activations, nil
+}
+
+// DBusActivations returns the D-Bus names the applications of the active
+// snaps are activated on, sorted by bus and name.
+func DBusActivations(st *state.State) ([]*DBusActivation, error) {
+	bySnap, err := dbusActivationsBySnap(st)
+	if err != nil {
+		return nil, err
+	}
+	var activations []*DBusActivation
+	for _, snapActivations := range bySnap {
+		activations = append(activations, snapActivations...)
+	}
+	sortDBusActivations(activations)
+	return activations, nil
+}
+
+// updateDBusActivations keeps track in the state of the D-Bus names the
+// applications of a snap are activated on, as the snap gets linked and
+// unlinked.
+func updateDBusActivations(st *state.State, snapsup *SnapSetup) error {
+	instanceName := snapsup.InstanceName()
+
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	var activations []*DBusActivation
+	if snapst.IsInstalled() && snapst.Active {
+		info, err := snapst.CurrentInfo()
+		if err != nil {
+			return err
+		}
+		if activations, err = dbusActivationsForSnap(info); err != nil {
+			return err
+		}
+	}
+
+	bySnap, err := dbusActivationsBySnap(st)
+	if err != nil {
+		return err
+	}
+	if len(activations) == 0 {
+		if _, ok := bySnap[instanceName]; !ok {
+			return nil
+		}
+		delete(bySnap, instanceName)
+	} else {
+		if bySnap == nil {
+			bySnap = make(map[string][]*DBusActivation)
+		}
+		bySnap[instanceName] = activations
+	}
+	st.Set("dbus-activations", bySnap)
+	return nil
+}
+
+func init() {
+	AddLinkSnapParticipant(LinkSnapParticipantFunc(updateDBusActivations))
+}
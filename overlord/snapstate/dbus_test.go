@@ -294,3 +294,102 @@ func (s *snapmgrTestSuite) TestInstallManyDBusActivationConflicts(c *C) {
 	// The order of installation is indeterminant, but one will fail
 	c.Check(chg.Err(), ErrorMatches, `cannot perform the following tasks:\n- Make snap "(some|other)-snap" \(11\) available to the system \(snap "(some|other)-snap" requesting to activate on system bus name "org.example.Foo" conflicts with snap "(some|other)-snap" use\)`)
 }
+
+func (s *snapmgrTestSuite) TestCheckDBusServiceConflictsHost(c *C) {
+	systemSnap, err := snap.InfoFromSnapYaml([]byte(fmt.Sprintf(dbusSystemYamlTemplate, "system-snap")))
+	c.Assert(err, IsNil)
+	sessionSnap, err := snap.InfoFromSnapYaml([]byte(fmt.Sprintf(dbusSessionYamlTemplate, "session-snap")))
+	c.Assert(err, IsNil)
+
+	var calls []string
+	restore := snapstate.MockHostDBusActivationFile(func(bus, name string) string {
+		calls = append(calls, bus+":"+name)
+		if bus == "system" {
+			return "/usr/share/dbus-1/system-services/" + name + ".service"
+		}
+		return ""
+	})
+	defer restore()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	err = snapstate.CheckDBusServiceConflicts(s.state, systemSnap)
+	c.Check(err, ErrorMatches, `snap "system-snap" requesting to activate on system bus name "org.example.Foo" conflicts with host service "/usr/share/dbus-1/system-services/org.example.Foo.service"`)
+	c.Check(calls, DeepEquals, []string{"system:org.example.Foo"})
+
+	calls = nil
+	err = snapstate.CheckDBusServiceConflicts(s.state, sessionSnap)
+	c.Check(err, IsNil)
+	c.Check(calls, DeepEquals, []string{"session:org.example.Foo"})
+}
+
+func (s *snapmgrTestSuite) TestUpdateDBusActivations(c *C) {
+	systemSnap, err := snap.InfoFromSnapYaml([]byte(fmt.Sprintf(dbusSystemYamlTemplate, "system-snap")))
+	c.Assert(err, IsNil)
+	sessionSnap, err := snap.InfoFromSnapYaml([]byte(fmt.Sprintf(dbusSessionYamlTemplate, "session-snap")))
+	c.Assert(err, IsNil)
+	restore := snapstate.MockSnapReadInfo(func(name string, si *snap.SideInfo) (*snap.Info, error) {
+		switch name {
+		case "system-snap":
+			return systemSnap, nil
+		case "session-snap":
+			return sessionSnap, nil
+		default:
+			return s.fakeBackend.ReadInfo(name, si)
+		}
+	})
+	defer restore()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	activations, err := snapstate.DBusActivations(s.state)
+	c.Assert(err, IsNil)
+	c.Check(activations, HasLen, 0)
+
+	for _, name := range []string{"system-snap", "session-snap"} {
+		si := &snap.SideInfo{
+			RealName: name,
+			Revision: snap.R(-42),
+		}
+		snapstate.Set(s.state, name, &snapstate.SnapState{
+			Active:   true,
+			Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+			Current:  si.Revision,
+			SnapType: "app",
+		})
+		err = snapstate.UpdateDBusActivations(s.state, &snapstate.SnapSetup{SideInfo: si})
+		c.Assert(err, IsNil)
+	}
+
+	activations, err = snapstate.DBusActivations(s.state)
+	c.Assert(err, IsNil)
+	c.Check(activations, DeepEquals, []*snapstate.DBusActivation{
+		{Name: "org.example.Foo", Bus: "session", Snap: "session-snap", App: "daemon"},
+		{Name: "org.example.Foo", Bus: "system", Snap: "system-snap", App: "daemon"},
+	})
+
+	// once unlinked the snap is no longer tracked
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "system-snap", &snapst), IsNil)
+	snapst.Active = false
+	snapstate.Set(s.state, "system-snap", &snapst)
+	err = snapstate.UpdateDBusActivations(s.state, &snapstate.SnapSetup{SideInfo: &snap.SideInfo{RealName: "system-snap"}})
+	c.Assert(err, IsNil)
+
+	activations, err = snapstate.DBusActivations(s.state)
+	c.Assert(err, IsNil)
+	c.Check(activations, DeepEquals, []*snapstate.DBusActivation{
+		{Name: "org.example.Foo", Bus: "session", Snap: "session-snap", App: "daemon"},
+	})
+
+	// removed snaps neither
+	snapstate.Set(s.state, "session-snap", nil)
+	err = snapstate.UpdateDBusActivations(s.state, &snapstate.SnapSetup{SideInfo: &snap.SideInfo{RealName: "session-snap"}})
+	c.Assert(err, IsNil)
+
+	activations, err = snapstate.DBusActivations(s.state)
+	c.Assert(err, IsNil)
+	c.Check(activations, HasLen, 0)
+}
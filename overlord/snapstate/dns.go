@@ -0,0 +1,141 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package snapstate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// DNSSettings holds the DNS settings of a snap, which replace the system's
+// name resolution configuration for the snap's applications.
+type DNSSettings struct {
+	// Nameservers are the addresses of the name servers used by the snap.
+	Nameservers []string `json:"nameservers,omitempty"`
+	// Disabled is set if the snap must not resolve names at all.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// resolvConf returns the resolv.conf(5) content conveying the DNS settings.
+func (d *DNSSettings) resolvConf() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by snapd, do not edit\n")
+	if d.Disabled {
+		// Without any name server the resolver falls back to the local
+		// host, point it instead to an address reserved for documentation
+		// that is never routed and fail quickly.
+		fmt.Fprintf(&buf, "# Name resolution is disabled\n")
+		fmt.Fprintf(&buf, "nameserver 192.0.2.1\n")
+		fmt.Fprintf(&buf, "options timeout:1 attempts:1\n")
+		return buf.Bytes()
+	}
+	for _, ns := range d.Nameservers {
+		fmt.Fprintf(&buf, "nameserver %s\n", ns)
+	}
+	return buf.Bytes()
+}
+
+// DNSResolvConfFile returns the path of the resolv.conf(5) file that is
+// mounted in the mount namespace of a snap with DNS settings.
+func DNSResolvConfFile(instanceName string) string {
+	return filepath.Join(dirs.SnapDNSDir, fmt.Sprintf("snap.%s.resolv.conf", instanceName))
+}
+
+// DNSOverride returns the DNS settings of the given snap, or nil if the snap
+// uses the system's name resolution configuration.
+func DNSOverride(st *state.State, instanceName string) (*DNSSettings, error) {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	return snapst.DNSOverride, nil
+}
+
+// SetDNSOverride sets the DNS settings of the given snap, overriding the
+// system's name resolution configuration. If settings is nil, the snap goes
+// back to using the system's configuration. The mount namespace of the snap
+// reflects the settings once its security profiles are set up again.
+func SetDNSOverride(st *state.State, instanceName string, settings *DNSSettings) error {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return &snap.NotInstalledError{Snap: instanceName}
+		}
+		return err
+	}
+
+	if err := writeResolvConf(instanceName, settings); err != nil {
+		return fmt.Errorf("cannot write DNS configuration of snap %q: %v", instanceName, err)
+	}
+
+	snapst.DNSOverride = settings
+	Set(st, instanceName, &snapst)
+	return nil
+}
+
+func writeResolvConf(instanceName string, settings *DNSSettings) error {
+	resolvConf := DNSResolvConfFile(instanceName)
+	if settings == nil {
+		if err := os.Remove(resolvConf); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(dirs.SnapDNSDir, 0755); err != nil {
+		return err
+	}
+	return osutil.AtomicWriteFile(resolvConf, settings.resolvConf(), 0644, 0)
+}
+
+// discardDNSOverride removes the DNS settings of a snap that is being
+// removed from the system.
+func discardDNSOverride(st *state.State, instanceName string) error {
+	if err := writeResolvConf(instanceName, nil); err != nil {
+		return err
+	}
+
+	// parallel instances cannot have their DNS set in the configuration
+	if _, instanceKey := snap.SplitInstanceName(instanceName); instanceKey != "" {
+		return nil
+	}
+
+	tr := config.NewTransaction(st)
+	var settings map[string]interface{}
+	if err := tr.Get("core", "dns.snaps."+instanceName, &settings); err != nil {
+		if config.IsNoOption(err) {
+			return nil
+		}
+		return err
+	}
+	if err := tr.Set("core", "dns.snaps."+instanceName, nil); err != nil {
+		return err
+	}
+	tr.Commit()
+	return nil
+}
@@ -106,7 +106,15 @@ func MockOsutilEnsureSnapUserGroup(mock func(name string, id uint32, extraUsers
 	return func() { osutilEnsureSnapUserGroup = old }
 }
 
+func MockOsutilBootID(mock func() (string, error)) (restore func()) {
+	old := osutilBootID
+	osutilBootID = mock
+	return func() { osutilBootID = old }
+}
+
 var (
+	HealthyBootStreak = healthyBootStreak
+
 	CoreInfoInternal       = coreInfo
 	CheckSnap              = checkSnap
 	CanRemove              = canRemove
@@ -162,8 +170,15 @@ var (
 // dbus
 var (
 	CheckDBusServiceConflicts = checkDBusServiceConflicts
+	UpdateDBusActivations     = updateDBusActivations
 )
 
+func MockHostDBusActivationFile(f func(bus, name string) string) (restore func()) {
+	old := hostDBusActivationFile
+	hostDBusActivationFile = f
+	return func() { hostDBusActivationFile = old }
+}
+
 // readme files
 var (
 	WriteSnapReadme = writeSnapReadme
@@ -273,6 +288,16 @@ func MockAsyncPendingRefreshNotification(fn func(context.Context, *userclient.Cl
 	}
 }
 
+func MockAsyncAppLifecycleNotification(fn func(context.Context, *userclient.Client, *userclient.AppLifecycleInfo)) (restore func()) {
+	old := asyncAppLifecycleNotification
+	asyncAppLifecycleNotification = fn
+	return func() {
+		asyncAppLifecycleNotification = old
+	}
+}
+
+var NotifyCloseRequest = notifyCloseRequest
+
 // re-refresh related
 var (
 	RefreshedSnaps     = refreshedSnaps
@@ -543,3 +568,5 @@ func SetRestoredMonitoring(snapmgr *SnapManager, value bool) {
 func SetPreseed(snapmgr *SnapManager, value bool) {
 	snapmgr.preseed = value
 }
+
+var FallbackChannel = fallbackChannel
@@ -113,6 +113,17 @@ type Flags struct {
 
 	// Lane is the lane that tasks should join if Transaction is set to "all-snaps".
 	Lane int `json:"lane,omitempty"`
+
+	// Quarantined is set for unasserted snaps installed while the
+	// unasserted-snaps system option is set to quarantine: their services
+	// are not started and they are not auto-connected until they are
+	// released.
+	Quarantined bool `json:"quarantined,omitempty"`
+
+	// Pinned is set for snaps kept at their current revision, which
+	// refreshes leave alone unless a revision is explicitly requested,
+	// until they are unpinned.
+	Pinned bool `json:"pinned,omitempty"`
 }
 
 // DevModeAllowed returns whether a snap can be installed with devmode
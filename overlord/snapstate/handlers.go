@@ -21,9 +21,12 @@ package snapstate
 
 import (
 	"context"
+	"crypto"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -730,6 +733,10 @@ func (m *SnapManager) doDownloadSnap(t *state.Task, tomb *tomb.Tomb) error {
 		// NOTE rate is never negative
 		rate = autoRefreshRateLimited(st)
 	}
+	var proxy func(*http.Request) (*url.URL, error)
+	if err == nil {
+		proxy, err = snapProxy(st, snapsup.InstanceName())
+	}
 	st.Unlock()
 	if err != nil {
 		return err
@@ -739,12 +746,13 @@ func (m *SnapManager) doDownloadSnap(t *state.Task, tomb *tomb.Tomb) error {
 		return err
 	}
 
-	meter := NewTaskProgressAdapterUnlocked(t)
+	meter := newTaskPhaseProgressAdapterUnlocked(t, "download", state.ProgressBytes)
 	targetFn := snapsup.MountFile()
 
 	dlOpts := &store.DownloadOptions{
 		Scheduled: snapsup.IsAutoRefresh,
 		RateLimit: rate,
+		Proxy:     proxy,
 	}
 	if snapsup.DownloadInfo == nil {
 		var storeInfo store.SnapActionResult
@@ -759,6 +767,8 @@ func (m *SnapManager) doDownloadSnap(t *state.Task, tomb *tomb.Tomb) error {
 			err = theStore.Download(tomb.Context(nil), snapsup.SnapName(), targetFn, &storeInfo.DownloadInfo, meter, user, dlOpts)
 		})
 		snapsup.SideInfo = &storeInfo.SideInfo
+	} else if reuseInstalledBlob(st, snapsup, targetFn) {
+		logger.Noticef("Reusing the blob of another instance of snap %q revision %s", snapsup.SnapName(), snapsup.Revision())
 	} else {
 		timings.Run(perfTimings, "download", fmt.Sprintf("download snap %q", snapsup.SnapName()), func(timings.Measurer) {
 			err = theStore.Download(tomb.Context(nil), snapsup.SnapName(), targetFn, snapsup.DownloadInfo, meter, user, dlOpts)
@@ -779,6 +789,90 @@ func (m *SnapManager) doDownloadSnap(t *state.Task, tomb *tomb.Tomb) error {
 	return nil
 }
 
+// installedBlobsOfRevision returns the blobs of the other instances of
+// the snap that have the revision being set up installed, either as
+// their current revision or as a retained one.
+func installedBlobsOfRevision(st *state.State, snapsup *SnapSetup) ([]string, error) {
+	rev := snapsup.Revision()
+	if snapsup.SideInfo.SnapID == "" || !rev.Store() {
+		return nil, nil
+	}
+
+	all, err := All(st)
+	if err != nil {
+		return nil, err
+	}
+	var blobs []string
+	for instanceName, snapst := range all {
+		if instanceName == snapsup.InstanceName() {
+			continue
+		}
+		for _, rss := range snapst.Sequence.Revisions {
+			if rss.Snap.SnapID == snapsup.SideInfo.SnapID && rss.Snap.Revision == rev {
+				blobs = append(blobs, snap.MinimalPlaceInfo(instanceName, rev).MountFile())
+			}
+		}
+	}
+	sort.Strings(blobs)
+	return blobs, nil
+}
+
+// reuseInstalledBlob sets up targetPath from the blob of another instance
+// with the same revision of the snap, so that parallel instances share
+// the data instead of downloading and storing it again. The blob is
+// reused only if it matches the size and digest the store expects. It
+// returns whether targetPath was set up. It must be called with the state
+// unlocked.
+func reuseInstalledBlob(st *state.State, snapsup *SnapSetup, targetPath string) bool {
+	dlInfo := snapsup.DownloadInfo
+	if dlInfo == nil || dlInfo.Sha3_384 == "" {
+		return false
+	}
+
+	st.Lock()
+	blobs, err := installedBlobsOfRevision(st, snapsup)
+	st.Unlock()
+	if err != nil {
+		logger.Noticef("cannot look for installed blobs of snap %q: %v", snapsup.SnapName(), err)
+		return false
+	}
+
+	for _, blob := range blobs {
+		fi, err := os.Stat(blob)
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+		if dlInfo.Size != 0 && fi.Size() != dlInfo.Size {
+			continue
+		}
+		digest, _, err := osutil.FileDigest(blob, crypto.SHA3_384)
+		if err != nil {
+			logger.Noticef("cannot verify blob %q: %v", blob, err)
+			continue
+		}
+		if fmt.Sprintf("%x", digest) != dlInfo.Sha3_384 {
+			logger.Noticef("blob %q does not match the expected digest, ignoring it", blob)
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return false
+		}
+		if err := os.Link(blob, targetPath); err == nil {
+			return true
+		}
+		// hard links are not possible on all filesystems, sharing the
+		// data blocks is still cheaper than downloading
+		err = osutil.CopyFile(blob, targetPath, osutil.CopyFlagPreserveAll|osutil.CopyFlagSync|osutil.CopyFlagReflink)
+		if err == nil {
+			return true
+		}
+		logger.Noticef("cannot reuse blob %q: %v", blob, err)
+		os.Remove(targetPath)
+	}
+	return false
+}
+
 func waitForPreDownload(task *state.Task, snapsup *SnapSetup) error {
 	st := task.State()
 	st.Lock()
@@ -823,11 +917,17 @@ func (m *SnapManager) doPreDownloadSnap(t *state.Task, tomb *tomb.Tomb) error {
 		return err
 	}
 
+	proxy, err := snapProxy(st, snapsup.InstanceName())
+	if err != nil {
+		return err
+	}
+
 	targetFn := snapsup.MountFile()
 	dlOpts := &store.DownloadOptions{
 		// pre-downloads are only triggered in auto-refreshes
 		Scheduled: true,
 		RateLimit: autoRefreshRateLimited(st),
+		Proxy:     proxy,
 	}
 
 	perfTimings := state.TimingsForTask(t)
@@ -900,6 +1000,7 @@ func asyncRefreshOnSnapClose(st *state.State, snapName string, refreshInfo *user
 	// there's already a goroutine waiting for this snap to close so just notify
 	if isSnapMonitored(st, snapName) {
 		asyncPendingRefreshNotification(context.TODO(), userclient.New(), refreshInfo)
+		notifyCloseRequest(refreshInfo)
 		return nil
 	}
 
@@ -920,6 +1021,7 @@ func asyncRefreshOnSnapClose(st *state.State, snapName string, refreshInfo *user
 
 	// notify the user about the blocked refresh
 	asyncPendingRefreshNotification(context.TODO(), userclient.New(), refreshInfo)
+	notifyCloseRequest(refreshInfo)
 
 	go continueRefreshOnSnapClose(st, snapName, done, refreshCtx)
 	return nil
@@ -1136,6 +1238,38 @@ func checkKernelHasUpdateAssetsTask(t *state.Task) error {
 	return ErrKernelGadgetUpdateTaskMissing
 }
 
+// snapSizeClass returns a coarse description of the given snap file size,
+// suitable for grouping snaps of similar sizes.
+func snapSizeClass(size int64) string {
+	const mb = 1000 * 1000
+	for _, limit := range []struct {
+		size  int64
+		label string
+	}{
+		{16 * mb, "<16MB"},
+		{64 * mb, "<64MB"},
+		{256 * mb, "<256MB"},
+		{1000 * mb, "<1GB"},
+	} {
+		if size < limit.size {
+			return limit.label
+		}
+	}
+	return ">=1GB"
+}
+
+// verityMountsEnabled returns whether snaps are mounted through dm-verity on
+// the given device, which is the case for devices with a secured model.
+func verityMountsEnabled(deviceCtx DeviceContext) bool {
+	return deviceCtx.Model().Grade() == asserts.ModelSecured
+}
+
+// VerityStatus returns how the given snap is protected by dm-verity when
+// mounted, "shipped" or "generated", or the empty string if it is not.
+func VerityStatus(info *snap.Info) string {
+	return backend.VerityStatus(info)
+}
+
 func (m *SnapManager) doMountSnap(t *state.Task, _ *tomb.Tomb) error {
 	st := t.State()
 	st.Lock()
@@ -1171,6 +1305,11 @@ func (m *SnapManager) doMountSnap(t *state.Task, _ *tomb.Tomb) error {
 		}
 	}
 
+	if fi, err := os.Stat(snapsup.SnapPath); err == nil {
+		// allow aggregating the timings by the size of the snap
+		perfTimings.AddTag("snap-size", snapSizeClass(fi.Size()))
+	}
+
 	timings.Run(perfTimings, "check-snap", fmt.Sprintf("check snap %q", snapsup.InstanceName()), func(timings.Measurer) {
 		err = checkSnap(st, snapsup.SnapPath, snapsup.InstanceName(), snapsup.SideInfo, curInfo, snapsup.Flags, deviceCtx)
 	})
@@ -1197,8 +1336,9 @@ func (m *SnapManager) doMountSnap(t *state.Task, _ *tomb.Tomb) error {
 
 	setupOpts := &backend.SetupSnapOptions{
 		SkipKernelExtraction: snapsup.SkipKernelExtraction,
+		Verity:               verityMountsEnabled(deviceCtx),
 	}
-	pb := NewTaskProgressAdapterUnlocked(t)
+	pb := newTaskPhaseProgressAdapterUnlocked(t, "unpack", state.ProgressSteps)
 	// TODO Use snapsup.Revision() to obtain the right info to mount
 	//      instead of assuming the candidate is the right one.
 	var snapType snap.Type
@@ -1569,7 +1709,7 @@ func (m *SnapManager) doCopySnapData(t *state.Task, _ *tomb.Tomb) (err error) {
 	}
 
 	dirOpts := opts.getSnapDirOpts()
-	pb := NewTaskProgressAdapterUnlocked(t)
+	pb := newTaskPhaseProgressAdapterUnlocked(t, "copy-data", state.ProgressSteps)
 	if copyDataErr := m.backend.CopySnapData(newInfo, oldInfo, dirOpts, pb); copyDataErr != nil {
 		if oldInfo != nil {
 			// there is another revision of the snap, cannot remove
@@ -2046,6 +2186,13 @@ func (m *SnapManager) doLinkSnap(t *state.Task, _ *tomb.Tomb) (err error) {
 	if snapsup.Required { // set only on install and left alone on refresh
 		snapst.Required = true
 	}
+	if snapsup.Quarantined { // set only on install, cleared when released
+		snapst.Quarantined = true
+	}
+	oldPinned := snapst.Pinned
+	if snapsup.Pinned { // left alone otherwise, cleared when unpinned
+		snapst.Pinned = true
+	}
 	oldRefreshInhibitedTime := snapst.RefreshInhibitedTime
 	oldLastRefreshTime := snapst.LastRefreshTime
 	// only set userID if unset or logged out in snapst and if we
@@ -2190,6 +2337,7 @@ func (m *SnapManager) doLinkSnap(t *state.Task, _ *tomb.Tomb) (err error) {
 	t.Set("old-candidate-index", oldCandidateIndex)
 	t.Set("old-refresh-inhibited-time", oldRefreshInhibitedTime)
 	t.Set("old-cohort-key", oldCohortKey)
+	t.Set("old-pinned", oldPinned)
 	t.Set("old-last-refresh-time", oldLastRefreshTime)
 	t.Set("old-revs-before-cand", oldRevsBeforeCand)
 	if snapsup.Revert {
@@ -2220,6 +2368,11 @@ func (m *SnapManager) doLinkSnap(t *state.Task, _ *tomb.Tomb) (err error) {
 			Media:   snapsup.Media,
 			Website: snapsup.Website,
 		}
+		// keep tracking the publisher across revisions
+		if oldAux, err := readAuxStoreInfo(cand.Snap.SnapID, snapsup.InstanceName()); err == nil && oldAux != nil {
+			aux.Publisher = oldAux.Publisher
+			aux.PublisherChange = oldAux.PublisherChange
+		}
 		if err := keepAuxStoreInfo(cand.Snap.SnapID, aux); err != nil {
 			return err
 		}
@@ -2624,6 +2777,10 @@ func (m *SnapManager) undoLinkSnap(t *state.Task, _ *tomb.Tomb) error {
 	if err := t.Get("old-cohort-key", &oldCohortKey); err != nil && !errors.Is(err, state.ErrNoState) {
 		return err
 	}
+	var oldPinned bool
+	if err := t.Get("old-pinned", &oldPinned); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
 	var oldRevsBeforeCand []snap.Revision
 	if err := t.Get("old-revs-before-cand", &oldRevsBeforeCand); err != nil && !errors.Is(err, state.ErrNoState) {
 		return err
@@ -2677,6 +2834,7 @@ func (m *SnapManager) undoLinkSnap(t *state.Task, _ *tomb.Tomb) error {
 	snapst.RefreshInhibitedTime = oldRefreshInhibitedTime
 	snapst.LastRefreshTime = oldLastRefreshTime
 	snapst.CohortKey = oldCohortKey
+	snapst.Pinned = oldPinned
 
 	if isRevert {
 		var oldRevertStatus map[int]RevertStatus
@@ -2966,6 +3124,13 @@ func (m *SnapManager) startSnapServices(t *state.Task, _ *tomb.Tomb) error {
 		return nil
 	}
 
+	if snapst.Quarantined {
+		// the services are neither enabled nor started until the
+		// snap is released from quarantine
+		t.Logf("Not starting services of quarantined snap %q", snapsup.InstanceName())
+		return nil
+	}
+
 	startupOrdered, err := snap.SortServices(svcs)
 	if err != nil {
 		return err
@@ -3309,6 +3474,12 @@ func (m *SnapManager) doClearSnapData(t *state.Task, _ *tomb.Tomb) error {
 		if err != nil {
 			return err
 		}
+		// the relocated data is not needed anymore either
+		if snapst.DataLocation != "" {
+			if err := m.backend.RemoveSnapDataLocation(snapsup.InstanceName(), snapst.DataLocation, progress.Null); err != nil {
+				return err
+			}
+		}
 		// Snap data directory can be removed now too
 		if err := m.backend.RemoveSnapDataDir(info, otherInstances); err != nil {
 			return err
@@ -3397,6 +3568,15 @@ func (m *SnapManager) doDiscardSnap(t *state.Task, _ *tomb.Tomb) error {
 		if err := m.removeSnapCookie(st, snapsup.InstanceName()); err != nil {
 			return fmt.Errorf("cannot remove snap cookie: %v", err)
 		}
+		if err := discardProxyOverride(st, snapsup.InstanceName()); err != nil {
+			return fmt.Errorf("cannot remove snap proxy settings: %v", err)
+		}
+		if err := discardDNSOverride(st, snapsup.InstanceName()); err != nil {
+			return fmt.Errorf("cannot remove snap DNS settings: %v", err)
+		}
+		if err := discardHardening(st, snapsup.InstanceName()); err != nil {
+			return fmt.Errorf("cannot remove snap hardening settings: %v", err)
+		}
 
 		otherInstances, err := hasOtherInstances(st, snapsup.InstanceName())
 		if err != nil {
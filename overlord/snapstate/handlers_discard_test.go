@@ -23,12 +23,14 @@ import (
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/ifacestate/ifacerepo"
 	"github.com/snapcore/snapd/overlord/servicestate"
 	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snapenv"
 	"github.com/snapcore/snapd/testutil"
 )
 
@@ -165,6 +167,197 @@ func (s *discardSnapSuite) TestDoDiscardSnapToEmpty(c *C) {
 	c.Assert(err, testutil.ErrorIs, state.ErrNoState)
 }
 
+func (s *discardSnapSuite) TestDoDiscardSnapRemovesProxyOverride(c *C) {
+	s.state.Lock()
+	snapstate.Set(s.state, "foo", &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "foo", Revision: snap.R(3)},
+		}),
+		Current:  snap.R(3),
+		SnapType: "app",
+	})
+	settings := &snapstate.ProxySettings{HTTP: "http://proxy.example.com:3128"}
+	c.Assert(snapstate.SetProxyOverride(s.state, "foo", settings), IsNil)
+	c.Assert(snapenv.ProxyEnvFile("foo"), testutil.FilePresent)
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "proxy.snaps.foo.http", "http://proxy.example.com:3128"), IsNil)
+	c.Assert(tr.Set("core", "proxy.snaps.bar.direct", true), IsNil)
+	tr.Commit()
+
+	t := s.state.NewTask("discard-snap", "test")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "foo",
+			Revision: snap.R(3),
+		},
+	})
+	s.state.NewChange("sample", "...").AddTask(t)
+
+	s.state.Unlock()
+
+	s.se.Ensure()
+	s.se.Wait()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Check(t.Status(), Equals, state.DoneStatus)
+	c.Check(snapenv.ProxyEnvFile("foo"), testutil.FileAbsent)
+
+	var proxies map[string]interface{}
+	tr = config.NewTransaction(s.state)
+	c.Assert(tr.Get("core", "proxy.snaps", &proxies), IsNil)
+	c.Check(proxies, DeepEquals, map[string]interface{}{
+		"bar": map[string]interface{}{"direct": true},
+	})
+}
+
+func (s *discardSnapSuite) TestDoDiscardSnapRemovesDNSOverride(c *C) {
+	s.state.Lock()
+	snapstate.Set(s.state, "foo", &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "foo", Revision: snap.R(3)},
+		}),
+		Current:  snap.R(3),
+		SnapType: "app",
+	})
+	settings := &snapstate.DNSSettings{Nameservers: []string{"192.168.1.1", "fd00::1"}}
+	c.Assert(snapstate.SetDNSOverride(s.state, "foo", settings), IsNil)
+	c.Assert(snapstate.DNSResolvConfFile("foo"), testutil.FileEquals, `# Generated by snapd, do not edit
+nameserver 192.168.1.1
+nameserver fd00::1
+`)
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "dns.snaps.foo.nameservers", "192.168.1.1,fd00::1"), IsNil)
+	c.Assert(tr.Set("core", "dns.snaps.bar.disabled", true), IsNil)
+	tr.Commit()
+
+	t := s.state.NewTask("discard-snap", "test")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "foo",
+			Revision: snap.R(3),
+		},
+	})
+	s.state.NewChange("sample", "...").AddTask(t)
+
+	s.state.Unlock()
+
+	s.se.Ensure()
+	s.se.Wait()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Check(t.Status(), Equals, state.DoneStatus)
+	c.Check(snapstate.DNSResolvConfFile("foo"), testutil.FileAbsent)
+
+	var dns map[string]interface{}
+	tr = config.NewTransaction(s.state)
+	c.Assert(tr.Get("core", "dns.snaps", &dns), IsNil)
+	c.Check(dns, DeepEquals, map[string]interface{}{
+		"bar": map[string]interface{}{"disabled": true},
+	})
+}
+
+func (s *discardSnapSuite) TestSetDNSOverrideDisabled(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	err := snapstate.SetDNSOverride(s.state, "foo", &snapstate.DNSSettings{Disabled: true})
+	c.Assert(err, ErrorMatches, `snap "foo" is not installed`)
+
+	snapstate.Set(s.state, "foo", &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "foo", Revision: snap.R(3)},
+		}),
+		Current:  snap.R(3),
+		SnapType: "app",
+	})
+	c.Assert(snapstate.SetDNSOverride(s.state, "foo", &snapstate.DNSSettings{Disabled: true}), IsNil)
+	c.Check(snapstate.DNSResolvConfFile("foo"), testutil.FileContains, "# Name resolution is disabled\n")
+	settings, err := snapstate.DNSOverride(s.state, "foo")
+	c.Assert(err, IsNil)
+	c.Check(settings, DeepEquals, &snapstate.DNSSettings{Disabled: true})
+
+	c.Assert(snapstate.SetDNSOverride(s.state, "foo", nil), IsNil)
+	c.Check(snapstate.DNSResolvConfFile("foo"), testutil.FileAbsent)
+	settings, err = snapstate.DNSOverride(s.state, "foo")
+	c.Assert(err, IsNil)
+	c.Check(settings, IsNil)
+}
+
+func (s *discardSnapSuite) TestDoDiscardSnapRemovesHardening(c *C) {
+	s.state.Lock()
+	snapstate.Set(s.state, "foo", &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "foo", Revision: snap.R(3)},
+		}),
+		Current:  snap.R(3),
+		SnapType: "app",
+	})
+	c.Assert(snapstate.SetHardening(s.state, "foo", &snapstate.HardeningSettings{NoPtrace: true}), IsNil)
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "hardening.snaps.foo.ptrace", false), IsNil)
+	c.Assert(tr.Set("core", "hardening.snaps.bar.core-dumps", false), IsNil)
+	tr.Commit()
+
+	t := s.state.NewTask("discard-snap", "test")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "foo",
+			Revision: snap.R(3),
+		},
+	})
+	s.state.NewChange("sample", "...").AddTask(t)
+
+	s.state.Unlock()
+
+	s.se.Ensure()
+	s.se.Wait()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Check(t.Status(), Equals, state.DoneStatus)
+
+	var hardening map[string]interface{}
+	tr = config.NewTransaction(s.state)
+	c.Assert(tr.Get("core", "hardening.snaps", &hardening), IsNil)
+	c.Check(hardening, DeepEquals, map[string]interface{}{
+		"bar": map[string]interface{}{"core-dumps": false},
+	})
+}
+
+func (s *discardSnapSuite) TestSetHardening(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	err := snapstate.SetHardening(s.state, "foo", &snapstate.HardeningSettings{NoPtrace: true})
+	c.Assert(err, ErrorMatches, `snap "foo" is not installed`)
+
+	snapstate.Set(s.state, "foo", &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "foo", Revision: snap.R(3)},
+		}),
+		Current:  snap.R(3),
+		SnapType: "app",
+	})
+	c.Assert(snapstate.SetHardening(s.state, "foo", &snapstate.HardeningSettings{NoPtrace: true, NoCoreDumps: true}), IsNil)
+	settings, err := snapstate.Hardening(s.state, "foo")
+	c.Assert(err, IsNil)
+	c.Check(settings, DeepEquals, &snapstate.HardeningSettings{NoPtrace: true, NoCoreDumps: true})
+
+	// no option enabled drops the settings
+	c.Assert(snapstate.SetHardening(s.state, "foo", &snapstate.HardeningSettings{}), IsNil)
+	settings, err = snapstate.Hardening(s.state, "foo")
+	c.Assert(err, IsNil)
+	c.Check(settings, IsNil)
+}
+
 func (s *discardSnapSuite) TestDoDiscardSnapErrorsForActive(c *C) {
 	s.state.Lock()
 	snapstate.Set(s.state, "foo", &snapstate.SnapState{
@@ -20,8 +20,12 @@
 package snapstate_test
 
 import (
+	"fmt"
+	"net/http"
+	"os"
 	"path/filepath"
 
+	"golang.org/x/crypto/sha3"
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/asserts"
@@ -175,6 +179,177 @@ func (s *downloadSnapSuite) TestDoDownloadSnapNormal(c *C) {
 	})
 }
 
+func (s *downloadSnapSuite) mockOtherInstanceBlob(c *C, content []byte) string {
+	si := &snap.SideInfo{
+		RealName: "foo",
+		SnapID:   "mySnapID",
+		Revision: snap.R(11),
+	}
+	snapstate.Set(s.state, "foo_other", &snapstate.SnapState{
+		Active:      true,
+		Sequence:    snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:     si.Revision,
+		InstanceKey: "other",
+	})
+
+	blob := filepath.Join(dirs.SnapBlobDir, "foo_other_11.snap")
+	c.Assert(os.MkdirAll(dirs.SnapBlobDir, 0755), IsNil)
+	c.Assert(os.WriteFile(blob, content, 0600), IsNil)
+	return blob
+}
+
+func (s *downloadSnapSuite) TestDoDownloadSnapReusesOtherInstanceBlob(c *C) {
+	s.state.Lock()
+
+	content := []byte("snap blob")
+	blob := s.mockOtherInstanceBlob(c, content)
+
+	si := &snap.SideInfo{
+		RealName: "foo",
+		SnapID:   "mySnapID",
+		Revision: snap.R(11),
+	}
+	t := s.state.NewTask("download-snap", "test")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: si,
+		DownloadInfo: &snap.DownloadInfo{
+			DownloadURL: "http://some-url.com/snap",
+			Size:        int64(len(content)),
+			Sha3_384:    fmt.Sprintf("%x", sha3.Sum384(content)),
+		},
+	})
+	chg := s.state.NewChange("sample", "...")
+	chg.AddTask(t)
+
+	s.state.Unlock()
+
+	s.se.Ensure()
+	s.se.Wait()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(chg.Err(), IsNil)
+	// the store was not hit
+	c.Check(s.fakeBackend.ops, HasLen, 0)
+
+	target := filepath.Join(dirs.SnapBlobDir, "foo_11.snap")
+	var snapsup snapstate.SnapSetup
+	t.Get("snap-setup", &snapsup)
+	c.Check(snapsup.SnapPath, Equals, target)
+
+	fi1, err := os.Stat(blob)
+	c.Assert(err, IsNil)
+	fi2, err := os.Stat(target)
+	c.Assert(err, IsNil)
+	c.Check(os.SameFile(fi1, fi2), Equals, true)
+}
+
+func (s *downloadSnapSuite) TestDoDownloadSnapOtherInstanceBlobMismatch(c *C) {
+	s.state.Lock()
+
+	content := []byte("snap blob")
+	s.mockOtherInstanceBlob(c, []byte("tampered blob"))
+
+	si := &snap.SideInfo{
+		RealName: "foo",
+		SnapID:   "mySnapID",
+		Revision: snap.R(11),
+	}
+	t := s.state.NewTask("download-snap", "test")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: si,
+		DownloadInfo: &snap.DownloadInfo{
+			DownloadURL: "http://some-url.com/snap",
+			Sha3_384:    fmt.Sprintf("%x", sha3.Sum384(content)),
+		},
+	})
+	chg := s.state.NewChange("sample", "...")
+	chg.AddTask(t)
+
+	s.state.Unlock()
+
+	s.se.Ensure()
+	s.se.Wait()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(chg.Err(), IsNil)
+	// the blob was not reused but downloaded
+	c.Check(s.fakeBackend.ops, DeepEquals, fakeOps{
+		{
+			op:   "storesvc-download",
+			name: "foo",
+		},
+	})
+}
+
+func (s *downloadSnapSuite) TestDoDownloadSnapProxyOverride(c *C) {
+	s.state.Lock()
+
+	si := &snap.SideInfo{
+		RealName: "foo",
+		SnapID:   "mySnapID",
+		Revision: snap.R(11),
+	}
+	snapstate.Set(s.state, "foo", &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{{RealName: "foo", SnapID: "mySnapID", Revision: snap.R(10)}}),
+		Current:  snap.R(10),
+		Active:   true,
+		ProxyOverride: &snapstate.ProxySettings{
+			HTTPS:   "http://proxy.example.com:3128",
+			NoProxy: "local.example.com",
+		},
+	})
+
+	t := s.state.NewTask("download-snap", "test")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: si,
+		DownloadInfo: &snap.DownloadInfo{
+			DownloadURL: "https://some-url.com/snap",
+		},
+	})
+	chg := s.state.NewChange("sample", "...")
+	chg.AddTask(t)
+
+	s.state.Unlock()
+
+	s.se.Ensure()
+	s.se.Wait()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(chg.Err(), IsNil)
+
+	// the store uses the proxy settings of the snap
+	c.Assert(s.fakeStore.downloads, HasLen, 1)
+	opts := s.fakeStore.downloads[0].opts
+	c.Assert(opts, NotNil)
+	c.Assert(opts.Proxy, NotNil)
+
+	for _, tc := range []struct {
+		url   string
+		proxy string
+	}{
+		{"https://some-url.com/snap", "http://proxy.example.com:3128"},
+		{"http://some-url.com/snap", ""},
+		{"https://local.example.com/snap", ""},
+		{"https://cdn.local.example.com/snap", ""},
+	} {
+		req, err := http.NewRequest("GET", tc.url, nil)
+		c.Assert(err, IsNil)
+		proxy, err := opts.Proxy(req)
+		c.Assert(err, IsNil)
+		if tc.proxy == "" {
+			c.Check(proxy, IsNil, Commentf("%s", tc.url))
+		} else {
+			c.Check(proxy.String(), Equals, tc.proxy, Commentf("%s", tc.url))
+		}
+	}
+}
+
 func (s *downloadSnapSuite) TestDoDownloadSnapWithDeviceContext(c *C) {
 	s.state.Lock()
 
@@ -20,6 +20,7 @@
 package snapstate_test
 
 import (
+	"os"
 	"path/filepath"
 	"time"
 
@@ -29,8 +30,10 @@ import (
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/timings"
 )
 
 type mountSnapSuite struct {
@@ -69,6 +72,89 @@ func (s *mountSnapSuite) TestDoMountSnapDoesNotRemovesSnaps(c *C) {
 	c.Assert(osutil.FileExists(testSnap), Equals, true)
 }
 
+func (s *mountSnapSuite) TestDoMountSnapTimingsSnapSize(c *C) {
+	oldDurationThreshold := timings.DurationThreshold
+	timings.DurationThreshold = 0
+	defer func() {
+		timings.DurationThreshold = oldDurationThreshold
+	}()
+
+	testSnap := filepath.Join(c.MkDir(), "foo_33.snap")
+	c.Assert(os.WriteFile(testSnap, make([]byte, 20*1000*1000), 0644), IsNil)
+	info := &snap.Info{SuggestedName: "foo", Version: "1.0", Architectures: []string{"all"}}
+	defer snapstate.MockOpenSnapFile(func(path string, si *snap.SideInfo) (*snap.Info, snap.Container, error) {
+		return info, emptyContainer(c), nil
+	})()
+
+	s.state.Lock()
+
+	t := s.state.NewTask("mount-snap", "test")
+	t.Set("snap-setup", &snapstate.SnapSetup{
+		SideInfo: &snap.SideInfo{
+			RealName: "foo",
+			Revision: snap.R(33),
+		},
+		SnapPath:     testSnap,
+		DownloadInfo: &snap.DownloadInfo{DownloadURL: "https://some"},
+	})
+	s.state.NewChange("sample", "...").AddTask(t)
+
+	s.state.Unlock()
+
+	s.se.Ensure()
+	s.se.Wait()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	c.Check(t.Status(), Equals, state.DoneStatus, Commentf("%v", t.Log()))
+	tms, err := timings.Get(s.state, -1, func(tags map[string]string) bool {
+		return tags["task-id"] == t.ID()
+	})
+	c.Assert(err, IsNil)
+	c.Assert(tms, HasLen, 1)
+	c.Check(tms[0].Tags["snap-size"], Equals, "<64MB")
+}
+
+func (s *mountSnapSuite) TestDoMountSnapVerityOnSecuredDevice(c *C) {
+	for _, grade := range []string{"dangerous", "signed", "secured"} {
+		s.fakeBackend.ops = nil
+		restore := snapstatetest.MockDeviceModel(MakeModel20("pc", map[string]interface{}{
+			"grade": grade,
+		}))
+		defer restore()
+
+		testSnap := filepath.Join(c.MkDir(), "foo_33.snap")
+		c.Assert(os.WriteFile(testSnap, nil, 0644), IsNil)
+		info := &snap.Info{SuggestedName: "foo", Version: "1.0", Architectures: []string{"all"}}
+		defer snapstate.MockOpenSnapFile(func(path string, si *snap.SideInfo) (*snap.Info, snap.Container, error) {
+			return info, emptyContainer(c), nil
+		})()
+
+		s.state.Lock()
+		t := s.state.NewTask("mount-snap", "test")
+		t.Set("snap-setup", &snapstate.SnapSetup{
+			SideInfo: &snap.SideInfo{
+				RealName: "foo",
+				Revision: snap.R(33),
+			},
+			SnapPath: testSnap,
+		})
+		s.state.NewChange("sample", "...").AddTask(t)
+		s.state.Unlock()
+
+		s.se.Ensure()
+		s.se.Wait()
+
+		s.state.Lock()
+		c.Check(t.Status(), Equals, state.DoneStatus, Commentf("%v", t.Log()))
+		s.state.Unlock()
+
+		op := s.fakeBackend.ops.First("setup-snap")
+		c.Assert(op, NotNil)
+		c.Check(op.verity, Equals, grade == "secured", Commentf("grade %s", grade))
+	}
+}
+
 func (s *mountSnapSuite) TestDoUndoMountSnap(c *C) {
 	v1 := "name: core\nversion: 1.0\nepoch: 1\n"
 	testSnap := snaptest.MakeTestSnapWithFiles(c, v1, nil)
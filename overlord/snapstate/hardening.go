@@ -0,0 +1,101 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"errors"
+
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// HardeningSettings holds the hardening options an administrator enabled for
+// a snap. They restrict the snap further than what its connected interfaces
+// grant.
+type HardeningSettings struct {
+	// NoPtrace is set if the snap must not trace or inspect the memory of
+	// other processes.
+	NoPtrace bool `json:"no-ptrace,omitempty"`
+	// NoCoreDumps is set if the processes of the snap must not dump core.
+	NoCoreDumps bool `json:"no-core-dumps,omitempty"`
+	// NoMemfdExec is set if the snap must not execute code from anonymous
+	// memory files.
+	NoMemfdExec bool `json:"no-memfd-exec,omitempty"`
+}
+
+// IsZero returns whether no hardening option is enabled.
+func (h *HardeningSettings) IsZero() bool {
+	return h == nil || *h == HardeningSettings{}
+}
+
+// Hardening returns the hardening settings of the given snap, or nil if
+// none are enabled.
+func Hardening(st *state.State, instanceName string) (*HardeningSettings, error) {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	return snapst.Hardening, nil
+}
+
+// SetHardening sets the hardening settings of the given snap. If settings is
+// nil or has no option enabled, the hardening of the snap is dropped. The
+// security profiles of the snap reflect the settings once they are set up
+// again.
+func SetHardening(st *state.State, instanceName string, settings *HardeningSettings) error {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return &snap.NotInstalledError{Snap: instanceName}
+		}
+		return err
+	}
+
+	if settings.IsZero() {
+		settings = nil
+	}
+	snapst.Hardening = settings
+	Set(st, instanceName, &snapst)
+	return nil
+}
+
+// discardHardening removes the hardening configuration of a snap that is
+// being removed from the system.
+func discardHardening(st *state.State, instanceName string) error {
+	// parallel instances cannot be hardened through the configuration
+	if _, instanceKey := snap.SplitInstanceName(instanceName); instanceKey != "" {
+		return nil
+	}
+
+	tr := config.NewTransaction(st)
+	var settings map[string]interface{}
+	if err := tr.Get("core", "hardening.snaps."+instanceName, &settings); err != nil {
+		if config.IsNoOption(err) {
+			return nil
+		}
+		return err
+	}
+	if err := tr.Set("core", "hardening.snaps."+instanceName, nil); err != nil {
+		return err
+	}
+	tr.Commit()
+	return nil
+}
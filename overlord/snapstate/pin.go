@@ -0,0 +1,53 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"errors"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// Unpin lets refreshes move the given snap away from the revision it was
+// pinned to when installed or refreshed with the Pinned flag. Unpinning a
+// snap that is not pinned does nothing.
+func Unpin(st *state.State, instanceName string) error {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return &snap.NotInstalledError{Snap: instanceName}
+		}
+		return err
+	}
+	if !snapst.Pinned {
+		return nil
+	}
+
+	if err := CheckChangeConflict(st, instanceName, nil); err != nil {
+		return err
+	}
+
+	snapst.Pinned = false
+	Set(st, instanceName, &snapst)
+	logger.Noticef("snap %q unpinned from revision %s", instanceName, snapst.Current)
+	return nil
+}
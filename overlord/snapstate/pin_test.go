@@ -0,0 +1,102 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/store"
+)
+
+func (s *snapmgrTestSuite) TestInstallPinned(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	_, err := snapstate.Install(context.Background(), s.state, "some-snap", nil, s.user.ID, snapstate.Flags{Pinned: true})
+	c.Check(err, ErrorMatches, `cannot pin snap "some-snap" without a revision`)
+
+	chg := s.state.NewChange("install", "install a pinned snap")
+	opts := &snapstate.RevisionOptions{Revision: snap.R(42)}
+	ts, err := snapstate.Install(context.Background(), s.state, "some-snap", opts, s.user.ID, snapstate.Flags{Pinned: true})
+	c.Assert(err, IsNil)
+	chg.AddAll(ts)
+
+	defer s.se.Stop()
+	s.settle(c)
+	c.Assert(chg.Err(), IsNil)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	c.Check(snapst.Current, Equals, snap.R(42))
+	c.Check(snapst.Pinned, Equals, true)
+}
+
+func (s *snapmgrTestSuite) TestUpdateSkipsPinned(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapForBlockedRevisions(c)
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	snapst.Pinned = true
+	snapstate.Set(s.state, "some-snap", &snapst)
+
+	_, err := snapstate.Update(s.state, "some-snap", nil, s.user.ID, snapstate.Flags{})
+	c.Check(err, Equals, store.ErrNoUpdateAvailable)
+
+	_, err = snapstate.Update(s.state, "some-snap", nil, s.user.ID, snapstate.Flags{Pinned: true})
+	c.Check(err, ErrorMatches, `cannot pin snap "some-snap" without a revision`)
+
+	updates, _, err := snapstate.UpdateMany(context.Background(), s.state, nil, nil, s.user.ID, nil)
+	c.Assert(err, IsNil)
+	c.Check(updates, HasLen, 0)
+
+	// an explicitly requested revision is still allowed
+	ts, err := snapstate.Update(s.state, "some-snap", &snapstate.RevisionOptions{Revision: snap.R(11)}, s.user.ID, snapstate.Flags{})
+	c.Assert(err, IsNil)
+	c.Check(ts.Tasks(), Not(HasLen), 0)
+}
+
+func (s *snapmgrTestSuite) TestUnpin(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	err := snapstate.Unpin(s.state, "some-snap")
+	c.Check(err, DeepEquals, &snap.NotInstalledError{Snap: "some-snap"})
+
+	s.mockSnapForBlockedRevisions(c)
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	snapst.Pinned = true
+	snapstate.Set(s.state, "some-snap", &snapst)
+
+	c.Assert(snapstate.Unpin(s.state, "some-snap"), IsNil)
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	c.Check(snapst.Pinned, Equals, false)
+
+	// refreshes pick the snap up again
+	updates, _, err := snapstate.UpdateMany(context.Background(), s.state, []string{"some-snap"}, nil, s.user.ID, nil)
+	c.Assert(err, IsNil)
+	c.Check(updates, DeepEquals, []string{"some-snap"})
+}
@@ -21,6 +21,7 @@ package snapstate
 
 import (
 	"math"
+	"time"
 
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/progress"
@@ -36,6 +37,11 @@ type taskProgressAdapter struct {
 	current  float64
 
 	lastReported float64
+
+	// phase and unit qualify the progress reported to the task, if set
+	phase     string
+	unit      state.ProgressUnit
+	startTime time.Time
 }
 
 // NewTaskProgressAdapterUnlocked creates an adapter of the task into a progress.Meter to use while the state is unlocked
@@ -48,13 +54,40 @@ func NewTaskProgressAdapterLocked(t *state.Task) progress.Meter {
 	return &taskProgressAdapter{task: t, unlocked: false}
 }
 
+// newTaskPhaseProgressAdapterUnlocked creates an adapter of the task into a
+// progress.Meter to use while the state is unlocked, which reports the given
+// phase and unit along with an estimated time of completion.
+func newTaskPhaseProgressAdapterUnlocked(t *state.Task, phase string, unit state.ProgressUnit) *taskProgressAdapter {
+	return &taskProgressAdapter{task: t, unlocked: true, phase: phase, unit: unit}
+}
+
 // Start sets total
 func (t *taskProgressAdapter) Start(label string, total float64) {
 	t.label = label
 	t.total = total
+	t.startTime = timeNow()
 	t.Set(0.0)
 }
 
+// details returns the details of the current progress, the estimated time
+// of completion is extrapolated from the progress so far.
+func (t *taskProgressAdapter) details() state.ProgressDetails {
+	if t.phase == "" {
+		return state.ProgressDetails{}
+	}
+	details := state.ProgressDetails{
+		Phase: t.phase,
+		Unit:  t.unit,
+	}
+	if t.current > 0 && t.current < t.total && !t.startTime.IsZero() {
+		now := timeNow()
+		elapsed := now.Sub(t.startTime)
+		remaining := time.Duration(float64(elapsed) * (t.total - t.current) / t.current)
+		details.ETA = now.Add(remaining)
+	}
+	return details
+}
+
 // Set sets the current progress
 func (t *taskProgressAdapter) Set(current float64) {
 	t.current = current
@@ -73,7 +106,7 @@ func (t *taskProgressAdapter) Set(current float64) {
 		t.task.State().Lock()
 		defer t.task.State().Unlock()
 	}
-	t.task.SetProgress(t.label, int(current), int(t.total))
+	t.task.SetProgressWithDetails(t.label, int(current), int(t.total), t.details())
 }
 
 // SetTotal sets the maximum progress
@@ -87,7 +120,8 @@ func (t *taskProgressAdapter) Finished() {
 		t.task.State().Lock()
 		defer t.task.State().Unlock()
 	}
-	t.task.SetProgress(t.label, int(t.total), int(t.total))
+	t.current = t.total
+	t.task.SetProgressWithDetails(t.label, int(t.total), int(t.total), t.details())
 }
 
 // Write sets the current write progress
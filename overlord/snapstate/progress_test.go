@@ -20,6 +20,8 @@
 package snapstate
 
 import (
+	"time"
+
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/overlord/state"
@@ -88,3 +90,68 @@ func (s *progressAdapterTestSuite) TestProgressAdapterSetTaskProgress(c *C) {
 	c.Check(done, Equals, 2)
 	c.Check(total, Equals, 1000)
 }
+
+func (s *progressAdapterTestSuite) TestProgressAdapterPhaseAndETA(c *C) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	restore := MockTimeNow(func() time.Time { return now })
+	defer restore()
+
+	st := state.New(nil)
+	st.Lock()
+	t := st.NewTask("op", "msg")
+	st.Unlock()
+	m := newTaskPhaseProgressAdapterUnlocked(t, "download", state.ProgressBytes)
+
+	m.Start("msg", 1000)
+
+	st.Lock()
+	c.Check(t.ProgressDetails(), Equals, state.ProgressDetails{
+		Phase: "download",
+		Unit:  state.ProgressBytes,
+	})
+	st.Unlock()
+
+	// a quarter took 10s, the rest is estimated to take 30s more
+	now = now.Add(10 * time.Second)
+	m.Set(250)
+
+	st.Lock()
+	_, done, total := t.Progress()
+	c.Check(done, Equals, 250)
+	c.Check(total, Equals, 1000)
+	c.Check(t.ProgressDetails(), Equals, state.ProgressDetails{
+		Phase: "download",
+		Unit:  state.ProgressBytes,
+		ETA:   now.Add(30 * time.Second),
+	})
+	st.Unlock()
+
+	m.Finished()
+
+	st.Lock()
+	_, done, total = t.Progress()
+	c.Check(done, Equals, 1000)
+	c.Check(total, Equals, 1000)
+	c.Check(t.ProgressDetails(), Equals, state.ProgressDetails{
+		Phase: "download",
+		Unit:  state.ProgressBytes,
+	})
+	st.Unlock()
+}
+
+func (s *progressAdapterTestSuite) TestProgressAdapterNoPhase(c *C) {
+	st := state.New(nil)
+	st.Lock()
+	t := st.NewTask("op", "msg")
+	m := NewTaskProgressAdapterUnlocked(t)
+	st.Unlock()
+
+	m.Start("msg", 1000)
+	m.Set(250)
+
+	st.Lock()
+	defer st.Unlock()
+	_, done, _ := t.Progress()
+	c.Check(done, Equals, 250)
+	c.Check(t.ProgressDetails(), Equals, state.ProgressDetails{})
+}
@@ -0,0 +1,194 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package snapstate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snapenv"
+)
+
+// ProxySettings holds the proxy settings of a snap, which replace the
+// system's proxy configuration for the snap's applications and for the store
+// operations done on behalf of the snap.
+type ProxySettings struct {
+	HTTP    string `json:"http,omitempty"`
+	HTTPS   string `json:"https,omitempty"`
+	FTP     string `json:"ftp,omitempty"`
+	NoProxy string `json:"no-proxy,omitempty"`
+	// Direct is set if the snap must access the network without going
+	// through any proxy.
+	Direct bool `json:"direct,omitempty"`
+}
+
+// environment returns the environment variables conveying the proxy settings
+// to the snap's applications. All the proxy variables are set so that none of
+// the system's settings leak through.
+func (p *ProxySettings) environment() []string {
+	proxies := map[string]string{
+		"http_proxy":  p.HTTP,
+		"https_proxy": p.HTTPS,
+		"ftp_proxy":   p.FTP,
+		"no_proxy":    p.NoProxy,
+	}
+	if p.Direct {
+		proxies = map[string]string{
+			"http_proxy":  "",
+			"https_proxy": "",
+			"ftp_proxy":   "",
+			"no_proxy":    "*",
+		}
+	}
+
+	var env []string
+	for _, key := range []string{"http_proxy", "https_proxy", "ftp_proxy", "no_proxy"} {
+		env = append(env, fmt.Sprintf("%s=%s", key, proxies[key]))
+		env = append(env, fmt.Sprintf("%s=%s", strings.ToUpper(key), proxies[key]))
+	}
+	return env
+}
+
+// proxyFor returns the proxy to use for the given request, or nil if the
+// request must not go through a proxy.
+func (p *ProxySettings) proxyFor(req *http.Request) (*url.URL, error) {
+	if p.Direct || p.bypassProxy(req.URL.Hostname()) {
+		return nil, nil
+	}
+
+	var proxy string
+	switch req.URL.Scheme {
+	case "http":
+		proxy = p.HTTP
+	case "https":
+		proxy = p.HTTPS
+	case "ftp":
+		proxy = p.FTP
+	}
+	if proxy == "" {
+		return nil, nil
+	}
+	return url.Parse(proxy)
+}
+
+// bypassProxy returns whether the host matches one of the entries of
+// no-proxy.
+func (p *ProxySettings) bypassProxy(host string) bool {
+	for _, entry := range strings.Split(p.NoProxy, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		entry = strings.TrimPrefix(entry, ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetProxyOverride sets the proxy settings of the given snap, overriding the
+// system's proxy configuration. If settings is nil, the snap goes back to
+// using the system's proxy configuration.
+func SetProxyOverride(st *state.State, instanceName string, settings *ProxySettings) error {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return &snap.NotInstalledError{Snap: instanceName}
+		}
+		return err
+	}
+
+	if err := writeProxyEnv(instanceName, settings); err != nil {
+		return fmt.Errorf("cannot write proxy environment of snap %q: %v", instanceName, err)
+	}
+
+	snapst.ProxyOverride = settings
+	Set(st, instanceName, &snapst)
+	return nil
+}
+
+func writeProxyEnv(instanceName string, settings *ProxySettings) error {
+	envFile := snapenv.ProxyEnvFile(instanceName)
+	if settings == nil {
+		if err := os.Remove(envFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(dirs.SnapProxyEnvDir, 0755); err != nil {
+		return err
+	}
+	content := strings.Join(settings.environment(), "\n") + "\n"
+	return osutil.AtomicWriteFile(envFile, []byte(content), 0644, 0)
+}
+
+// snapProxy returns the proxy function to use for the store operations done
+// on behalf of the given snap, or nil if the system's proxy configuration
+// applies.
+func snapProxy(st *state.State, instanceName string) (func(*http.Request) (*url.URL, error), error) {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	if snapst.ProxyOverride == nil {
+		return nil, nil
+	}
+	return snapst.ProxyOverride.proxyFor, nil
+}
+
+// discardProxyOverride removes the proxy settings of a snap that is being
+// removed from the system.
+func discardProxyOverride(st *state.State, instanceName string) error {
+	if err := writeProxyEnv(instanceName, nil); err != nil {
+		return err
+	}
+
+	// parallel instances cannot have their proxy set in the configuration
+	if _, instanceKey := snap.SplitInstanceName(instanceName); instanceKey != "" {
+		return nil
+	}
+
+	tr := config.NewTransaction(st)
+	var settings map[string]interface{}
+	if err := tr.Get("core", "proxy.snaps."+instanceName, &settings); err != nil {
+		if config.IsNoOption(err) {
+			return nil
+		}
+		return err
+	}
+	if err := tr.Set("core", "proxy.snaps."+instanceName, nil); err != nil {
+		return err
+	}
+	tr.Commit()
+	return nil
+}
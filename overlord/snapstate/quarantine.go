@@ -0,0 +1,140 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// shouldQuarantine returns whether the given snap about to be installed
+// must be quarantined, that is whether it is an unasserted app snap
+// installed on a seeded system with the unasserted-snaps system option
+// set to quarantine.
+func shouldQuarantine(st *state.State, info *snap.Info) (bool, error) {
+	if info.SnapID != "" || info.Type() != snap.TypeApp {
+		return false, nil
+	}
+
+	var seeded bool
+	if err := st.Get("seeded", &seeded); err != nil && !errors.Is(err, state.ErrNoState) {
+		return false, err
+	}
+	if !seeded {
+		// unasserted snaps from the seed are trusted
+		return false, nil
+	}
+
+	var policy string
+	tr := config.NewTransaction(st)
+	if err := tr.GetMaybe("core", "unasserted-snaps", &policy); err != nil {
+		return false, err
+	}
+	return policy == "quarantine", nil
+}
+
+// ReleaseQuarantine returns a set of tasks releasing the given snap from
+// quarantine, auto-connecting it and starting its services.
+// Note that the state must be locked by the caller.
+func ReleaseQuarantine(st *state.State, name string) (*state.TaskSet, error) {
+	var snapst SnapState
+	err := Get(st, name, &snapst)
+	if errors.Is(err, state.ErrNoState) {
+		return nil, &snap.NotInstalledError{Snap: name}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !snapst.Quarantined {
+		return nil, fmt.Errorf("snap %q is not quarantined", name)
+	}
+
+	if err := CheckChangeConflict(st, name, nil); err != nil {
+		return nil, err
+	}
+
+	info, err := snapst.CurrentInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	snapsup := &SnapSetup{
+		SideInfo:    snapst.CurrentSideInfo(),
+		Flags:       snapst.Flags.ForSnapSetup(),
+		Type:        info.Type(),
+		Version:     info.Version,
+		PlugsOnly:   len(info.Slots) == 0,
+		InstanceKey: snapst.InstanceKey,
+	}
+
+	release := st.NewTask("release-quarantine", fmt.Sprintf(i18n.G("Release snap %q (%s) from quarantine"), snapsup.InstanceName(), snapst.Current))
+	release.Set("snap-setup", &snapsup)
+	tasks := []*state.Task{release}
+
+	autoConnect := st.NewTask("auto-connect", fmt.Sprintf(i18n.G("Automatically connect eligible plugs and slots of snap %q"), snapsup.InstanceName()))
+	autoConnect.Set("snap-setup-task", release.ID())
+	autoConnect.WaitFor(release)
+	tasks = append(tasks, autoConnect)
+
+	if snapst.Active && len(info.Services()) > 0 {
+		startSnapServices := st.NewTask("start-snap-services", fmt.Sprintf(i18n.G("Start snap %q (%s) services"), snapsup.InstanceName(), snapst.Current))
+		startSnapServices.Set("snap-setup-task", release.ID())
+		startSnapServices.WaitFor(autoConnect)
+		tasks = append(tasks, startSnapServices)
+	}
+
+	return state.NewTaskSet(tasks...), nil
+}
+
+func (m *SnapManager) doReleaseQuarantine(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	defer st.Unlock()
+
+	snapsup, snapst, err := snapSetupAndState(t)
+	if err != nil {
+		return err
+	}
+	snapst.Quarantined = false
+	Set(st, snapsup.InstanceName(), snapst)
+	return nil
+}
+
+func (m *SnapManager) undoReleaseQuarantine(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	defer st.Unlock()
+
+	snapsup, snapst, err := snapSetupAndState(t)
+	if err != nil {
+		return err
+	}
+	snapst.Quarantined = true
+	Set(st, snapsup.InstanceName(), snapst)
+	return nil
+}
@@ -0,0 +1,172 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+)
+
+func (s *snapmgrTestSuite) installUnassertedServicesSnap(c *C) {
+	// use a snap directory so that no squashfs tooling is needed
+	snapPath := filepath.Join(c.MkDir(), "services-snap")
+	snaptest.PopulateDir(snapPath, [][]string{
+		{"meta/snap.yaml", "name: services-snap\nversion: 1.0\n"},
+	})
+	chg := s.state.NewChange("install", "install a local snap")
+	ts, _, err := snapstate.InstallPath(s.state, &snap.SideInfo{RealName: "services-snap"}, snapPath, "", "", snapstate.Flags{}, nil)
+	c.Assert(err, IsNil)
+	chg.AddAll(ts)
+
+	s.settle(c)
+	c.Assert(chg.Err(), IsNil)
+}
+
+func (s *snapmgrTestSuite) setUnassertedSnapsPolicy(c *C, policy string) {
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "unasserted-snaps", policy), IsNil)
+	tr.Commit()
+}
+
+func (s *snapmgrTestSuite) TestInstallPathUnassertedQuarantined(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setUnassertedSnapsPolicy(c, "quarantine")
+
+	defer s.se.Stop()
+	s.installUnassertedServicesSnap(c)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "services-snap", &snapst), IsNil)
+	c.Check(snapst.Active, Equals, true)
+	c.Check(snapst.Quarantined, Equals, true)
+
+	// services were not started
+	c.Check(s.fakeBackend.ops.First("start-snap-services"), IsNil)
+}
+
+func (s *snapmgrTestSuite) TestInstallPathUnassertedNotQuarantined(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setUnassertedSnapsPolicy(c, "allow")
+
+	defer s.se.Stop()
+	s.installUnassertedServicesSnap(c)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "services-snap", &snapst), IsNil)
+	c.Check(snapst.Quarantined, Equals, false)
+	c.Check(s.fakeBackend.ops.First("start-snap-services"), NotNil)
+}
+
+func (s *snapmgrTestSuite) TestInstallPathUnassertedNotQuarantinedBeforeSeeding(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setUnassertedSnapsPolicy(c, "quarantine")
+	s.state.Set("seeded", false)
+
+	defer s.se.Stop()
+	s.installUnassertedServicesSnap(c)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "services-snap", &snapst), IsNil)
+	c.Check(snapst.Quarantined, Equals, false)
+}
+
+func (s *snapmgrTestSuite) TestReleaseQuarantine(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setUnassertedSnapsPolicy(c, "quarantine")
+
+	defer s.se.Stop()
+	s.installUnassertedServicesSnap(c)
+
+	ts, err := snapstate.ReleaseQuarantine(s.state, "services-snap")
+	c.Assert(err, IsNil)
+	c.Assert(taskKinds(ts.Tasks()), DeepEquals, []string{
+		"release-quarantine",
+		"auto-connect",
+		"start-snap-services",
+	})
+
+	chg := s.state.NewChange("release-quarantine", "release a snap from quarantine")
+	chg.AddAll(ts)
+	s.settle(c)
+	c.Assert(chg.Err(), IsNil)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "services-snap", &snapst), IsNil)
+	c.Check(snapst.Quarantined, Equals, false)
+	c.Check(s.fakeBackend.ops.First("start-snap-services"), NotNil)
+}
+
+func (s *snapmgrTestSuite) TestReleaseQuarantineUndo(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setUnassertedSnapsPolicy(c, "quarantine")
+
+	defer s.se.Stop()
+	s.installUnassertedServicesSnap(c)
+
+	ts, err := snapstate.ReleaseQuarantine(s.state, "services-snap")
+	c.Assert(err, IsNil)
+	chg := s.state.NewChange("release-quarantine", "release a snap from quarantine")
+	chg.AddAll(ts)
+
+	terr := s.state.NewTask("error-trigger", "provoking total undo")
+	terr.WaitAll(ts)
+	chg.AddTask(terr)
+
+	s.settle(c)
+	c.Assert(chg.Err(), NotNil)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "services-snap", &snapst), IsNil)
+	c.Check(snapst.Quarantined, Equals, true)
+}
+
+func (s *snapmgrTestSuite) TestReleaseQuarantineErrors(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	_, err := snapstate.ReleaseQuarantine(s.state, "missing-snap")
+	c.Check(err, ErrorMatches, `snap "missing-snap" is not installed`)
+
+	snapstate.Set(s.state, "some-snap", &snapstate.SnapState{
+		Active:   true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{{RealName: "some-snap", Revision: snap.R(1)}}),
+		Current:  snap.R(1),
+		SnapType: "app",
+	})
+	_, err = snapstate.ReleaseQuarantine(s.state, "some-snap")
+	c.Check(err, ErrorMatches, `snap "some-snap" is not quarantined`)
+}
@@ -0,0 +1,90 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package snapstate
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/state"
+	userclient "github.com/snapcore/snapd/usersession/client"
+)
+
+// maxRefreshInhibitionOverrides is the number of overrides kept for auditing.
+const maxRefreshInhibitionOverrides = 50
+
+// RefreshInhibitionOverride records who forced the pending refresh of a snap
+// to go ahead despite running applications inhibiting it.
+type RefreshInhibitionOverride struct {
+	Snap string `json:"snap"`
+	// User is the name of the snapd user requesting the override, if any.
+	User string `json:"user,omitempty"`
+	// UID is the system user ID of the requester.
+	UID  uint32    `json:"uid"`
+	Time time.Time `json:"time"`
+}
+
+// OverrideRefreshInhibition records that the pending refresh of the snap,
+// inhibited by its running applications, is forced to go ahead, and notifies
+// the affected sessions that the snap is updating now. Monitoring waiting for
+// the applications to close is stopped as the refresh no longer waits for it.
+//
+// It returns false, recording nothing, if no inhibited refresh of the snap is
+// pending, which includes the snap not being installed.
+func OverrideRefreshInhibition(st *state.State, override *RefreshInhibitionOverride) (bool, error) {
+	var snapst SnapState
+	if err := Get(st, override.Snap, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+		return false, err
+	}
+	if snapst.RefreshInhibitedTime == nil && !isSnapMonitored(st, override.Snap) {
+		return false, nil
+	}
+
+	overrides, err := RefreshInhibitionOverrides(st)
+	if err != nil {
+		return false, err
+	}
+	if override.Time.IsZero() {
+		override.Time = timeNow()
+	}
+	overrides = append(overrides, override)
+	if len(overrides) > maxRefreshInhibitionOverrides {
+		overrides = overrides[len(overrides)-maxRefreshInhibitionOverrides:]
+	}
+	st.Set("refresh-inhibition-overrides", overrides)
+	logger.Noticef("refresh of snap %q inhibited by running applications forced by uid %d", override.Snap, override.UID)
+
+	abortMonitoring(st, override.Snap)
+	asyncPendingRefreshNotification(context.TODO(), userclient.New(), &userclient.PendingSnapRefreshInfo{
+		InstanceName: override.Snap,
+	})
+	return true, nil
+}
+
+// RefreshInhibitionOverrides returns the recorded overrides of refresh
+// inhibition, oldest first.
+func RefreshInhibitionOverrides(st *state.State) ([]*RefreshInhibitionOverride, error) {
+	var overrides []*RefreshInhibitionOverride
+	if err := st.Get("refresh-inhibition-overrides", &overrides); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	return overrides, nil
+}
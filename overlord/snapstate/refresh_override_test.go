@@ -0,0 +1,133 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+package snapstate_test
+
+import (
+	"context"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/snap"
+	userclient "github.com/snapcore/snapd/usersession/client"
+)
+
+func (s *snapmgrTestSuite) mockInhibitedSnap(c *C, inhibitedTime *time.Time) {
+	snapstate.Set(s.state, "some-snap", &snapstate.SnapState{
+		Active: true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "some-snap", Revision: snap.R(1)},
+		}),
+		Current:              snap.R(1),
+		RefreshInhibitedTime: inhibitedTime,
+	})
+}
+
+func (s *snapmgrTestSuite) TestOverrideRefreshInhibitionNothingPending(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockInhibitedSnap(c, nil)
+
+	var notified int
+	restore := snapstate.MockAsyncPendingRefreshNotification(func(context.Context, *userclient.Client, *userclient.PendingSnapRefreshInfo) {
+		notified++
+	})
+	defer restore()
+
+	overridden, err := snapstate.OverrideRefreshInhibition(s.state, &snapstate.RefreshInhibitionOverride{Snap: "some-snap", UID: 1000})
+	c.Assert(err, IsNil)
+	c.Check(overridden, Equals, false)
+	c.Check(notified, Equals, 0)
+
+	overrides, err := snapstate.RefreshInhibitionOverrides(s.state)
+	c.Assert(err, IsNil)
+	c.Check(overrides, HasLen, 0)
+
+	overridden, err = snapstate.OverrideRefreshInhibition(s.state, &snapstate.RefreshInhibitionOverride{Snap: "other-snap"})
+	c.Assert(err, IsNil)
+	c.Check(overridden, Equals, false)
+}
+
+func (s *snapmgrTestSuite) TestOverrideRefreshInhibition(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	restore := snapstate.MockTimeNow(func() time.Time { return now })
+	defer restore()
+
+	inhibited := now.Add(-time.Hour)
+	s.mockInhibitedSnap(c, &inhibited)
+
+	var notified []*userclient.PendingSnapRefreshInfo
+	restore = snapstate.MockAsyncPendingRefreshNotification(func(ctx context.Context, client *userclient.Client, refreshInfo *userclient.PendingSnapRefreshInfo) {
+		notified = append(notified, refreshInfo)
+	})
+	defer restore()
+
+	var aborted bool
+	s.state.Cache("monitored-snaps", map[string]context.CancelFunc{
+		"some-snap": func() { aborted = true },
+	})
+
+	overridden, err := snapstate.OverrideRefreshInhibition(s.state, &snapstate.RefreshInhibitionOverride{
+		Snap: "some-snap",
+		User: "admin",
+		UID:  1000,
+	})
+	c.Assert(err, IsNil)
+	c.Check(overridden, Equals, true)
+	c.Check(aborted, Equals, true)
+	// sessions are told the snap is updating now
+	c.Check(notified, DeepEquals, []*userclient.PendingSnapRefreshInfo{{InstanceName: "some-snap"}})
+
+	overrides, err := snapstate.RefreshInhibitionOverrides(s.state)
+	c.Assert(err, IsNil)
+	c.Assert(overrides, HasLen, 1)
+	c.Check(overrides[0].Snap, Equals, "some-snap")
+	c.Check(overrides[0].User, Equals, "admin")
+	c.Check(overrides[0].UID, Equals, uint32(1000))
+	c.Check(overrides[0].Time.Equal(now), Equals, true)
+}
+
+func (s *snapmgrTestSuite) TestOverrideRefreshInhibitionKeepsLatest(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	inhibited := time.Now()
+	s.mockInhibitedSnap(c, &inhibited)
+
+	for i := 0; i < 60; i++ {
+		overridden, err := snapstate.OverrideRefreshInhibition(s.state, &snapstate.RefreshInhibitionOverride{
+			Snap: "some-snap",
+			UID:  uint32(i),
+		})
+		c.Assert(err, IsNil)
+		c.Assert(overridden, Equals, true)
+	}
+
+	overrides, err := snapstate.RefreshInhibitionOverrides(s.state)
+	c.Assert(err, IsNil)
+	c.Assert(overrides, HasLen, 50)
+	c.Check(overrides[0].UID, Equals, uint32(10))
+	c.Check(overrides[49].UID, Equals, uint32(59))
+}
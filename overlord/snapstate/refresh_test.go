@@ -20,8 +20,10 @@
 package snapstate_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"time"
 
 	. "gopkg.in/check.v1"
 
@@ -33,6 +35,7 @@ import (
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/testutil"
+	userclient "github.com/snapcore/snapd/usersession/client"
 )
 
 type refreshSuite struct {
@@ -137,6 +140,60 @@ func (s *refreshSuite) TestPendingSnapRefreshInfo(c *C) {
 	c.Check(refreshInfo.BusyAppDesktopEntry, Equals, "pkg_app")
 }
 
+func (s *refreshSuite) TestRunningAppsRefreshInfo(c *C) {
+	// nothing running
+	c.Check(snapstate.RunningAppsRefreshInfo(s.info), IsNil)
+
+	// only services and hooks running
+	s.pids = map[string][]int{
+		"snap.pkg.daemon":         {100},
+		"snap.pkg.hook.configure": {105},
+	}
+	c.Check(snapstate.RunningAppsRefreshInfo(s.info), IsNil)
+
+	// an app without a desktop file is running
+	s.pids = map[string][]int{
+		"snap.pkg.app": {101},
+	}
+	c.Check(snapstate.RunningAppsRefreshInfo(s.info), IsNil)
+
+	// a graphical app is running
+	desktopFile := s.info.Apps["app"].DesktopFile()
+	c.Assert(os.MkdirAll(filepath.Dir(desktopFile), 0755), IsNil)
+	c.Assert(os.WriteFile(desktopFile, nil, 0644), IsNil)
+	refreshInfo := snapstate.RunningAppsRefreshInfo(s.info)
+	c.Assert(refreshInfo, NotNil)
+	c.Check(refreshInfo.InstanceName, Equals, "pkg")
+	c.Check(refreshInfo.BusyAppName, Equals, "app")
+	c.Check(refreshInfo.BusyAppDesktopEntry, Equals, "pkg_app")
+}
+
+func (s *refreshSuite) TestNotifyCloseRequest(c *C) {
+	var notified []*userclient.AppLifecycleInfo
+	restore := snapstate.MockAsyncAppLifecycleNotification(func(ctx context.Context, client *userclient.Client, lifecycleInfo *userclient.AppLifecycleInfo) {
+		notified = append(notified, lifecycleInfo)
+	})
+	defer restore()
+
+	// no graphical app, nothing to prompt about
+	snapstate.NotifyCloseRequest(&userclient.PendingSnapRefreshInfo{InstanceName: "pkg"})
+	c.Check(notified, HasLen, 0)
+
+	snapstate.NotifyCloseRequest(&userclient.PendingSnapRefreshInfo{
+		InstanceName:        "pkg",
+		TimeRemaining:       time.Hour,
+		BusyAppName:         "app",
+		BusyAppDesktopEntry: "pkg_app",
+	})
+	c.Check(notified, DeepEquals, []*userclient.AppLifecycleInfo{{
+		InstanceName:        "pkg",
+		Event:               userclient.AppLifecycleCloseRequest,
+		TimeRemaining:       time.Hour,
+		BusyAppName:         "app",
+		BusyAppDesktopEntry: "pkg_app",
+	}})
+}
+
 func (s *refreshSuite) addInstalledSnap(snapst *snapstate.SnapState) (*snapstate.SnapState, *snap.Info) {
 	snapName := snapst.Sequence.Revisions[0].Snap.RealName
 	snapstate.Set(s.state, snapName, snapst)
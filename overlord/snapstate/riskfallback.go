@@ -0,0 +1,150 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap/channel"
+	"github.com/snapcore/snapd/store"
+)
+
+// moreStableRisk maps each risk to the next more stable one.
+var moreStableRisk = map[string]string{
+	"edge":      "beta",
+	"beta":      "candidate",
+	"candidate": "stable",
+}
+
+// riskFallbackEnabled returns whether the snap follows the risk fallback
+// policy, set with the refresh.risk-fallback system option listing the
+// snaps for which it applies.
+func riskFallbackEnabled(st *state.State, instanceName string) (bool, error) {
+	tr := config.NewTransaction(st)
+	var snaps string
+	if err := tr.Get("core", "refresh.risk-fallback", &snaps); err != nil && !config.IsNoOption(err) {
+		return false, err
+	}
+	for _, name := range strings.Split(snaps, ",") {
+		if strings.TrimSpace(name) == instanceName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fallbackChannel returns the channel to fall back to when the given one
+// has no revision available: the channel itself for a branch, otherwise the
+// next more stable risk of the same track. It returns an empty string for
+// stable channels.
+func fallbackChannel(ch string) string {
+	c, err := channel.Parse(ch, "")
+	if err != nil {
+		return ""
+	}
+	next := channel.Channel{Track: c.Track, Risk: c.Risk}
+	if c.Branch == "" {
+		next.Risk = moreStableRisk[c.Risk]
+		if next.Risk == "" {
+			return ""
+		}
+	}
+	next = next.Clean()
+	return next.Full()
+}
+
+func isRevisionNotAvailable(err error) bool {
+	var rnaErr *store.RevisionNotAvailableError
+	return errors.As(err, &rnaErr)
+}
+
+// resolveWithRiskFallback applies the risk fallback policy, if the snap
+// follows it, to the outcome of resolving the refresh action of the snap
+// from its tracked channel, recording whether the snap falls back.
+func resolveWithRiskFallback(ctx context.Context, st *state.State, theStore StoreService, curSnaps []*store.CurrentSnap, action *store.SnapAction, snapst *SnapState, tracking string, sar store.SnapActionResult, err error, user *auth.UserState, opts *store.RefreshOptions) (store.SnapActionResult, error) {
+	if action.Action != "refresh" || !action.Revision.Unset() {
+		return sar, err
+	}
+	enabled, cfgErr := riskFallbackEnabled(st, action.InstanceName)
+	if cfgErr != nil {
+		return store.SnapActionResult{}, cfgErr
+	}
+	if !enabled {
+		return sar, err
+	}
+
+	var fallback string
+	if isRevisionNotAvailable(err) {
+		sar, fallback, err = refreshWithRiskFallback(ctx, st, theStore, curSnaps, action, tracking, err, user, opts)
+	}
+	if err == nil || errors.Is(err, store.ErrNoUpdateAvailable) {
+		if err := recordRiskFallback(st, action.InstanceName, snapst, fallback); err != nil {
+			return store.SnapActionResult{}, err
+		}
+	}
+	return sar, err
+}
+
+// refreshWithRiskFallback resolves the refresh of the snap through the
+// channels more stable than the tracked one, for which the store failed with
+// notAvailableErr. It returns the result along with the channel it was
+// resolved from, or the error from the most stable channel.
+func refreshWithRiskFallback(ctx context.Context, st *state.State, theStore StoreService, curSnaps []*store.CurrentSnap, action *store.SnapAction, tracking string, notAvailableErr error, user *auth.UserState, opts *store.RefreshOptions) (store.SnapActionResult, string, error) {
+	for ch := fallbackChannel(tracking); ch != ""; ch = fallbackChannel(ch) {
+		fallbackAction := *action
+		fallbackAction.Channel = ch
+
+		st.Unlock()
+		res, _, err := theStore.SnapAction(ctx, curSnaps, []*store.SnapAction{&fallbackAction}, nil, user, opts)
+		st.Lock()
+
+		sar, err := singleActionResult(action.InstanceName, "refresh", res, err)
+		if !isRevisionNotAvailable(err) {
+			return sar, ch, err
+		}
+		notAvailableErr = err
+	}
+	return store.SnapActionResult{}, "", notAvailableErr
+}
+
+// recordRiskFallback records the channel the snap falls back to from its
+// tracked channel, or that it does not fall back if ch is empty. The given
+// SnapState, read before resolving the refresh, is updated to match.
+func recordRiskFallback(st *state.State, instanceName string, snapst *SnapState, ch string) error {
+	var cur SnapState
+	if err := Get(st, instanceName, &cur); err != nil {
+		return err
+	}
+	if cur.RiskFallbackChannel != ch {
+		if ch != "" {
+			logger.Noticef("snap %q has no revision available in channel %q, falling back to %q", instanceName, cur.TrackingChannel, ch)
+		}
+		cur.RiskFallbackChannel = ch
+		Set(st, instanceName, &cur)
+	}
+	snapst.RiskFallbackChannel = ch
+	return nil
+}
@@ -0,0 +1,133 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/store"
+)
+
+func (s *snapmgrTestSuite) TestFallbackChannel(c *C) {
+	for _, tc := range []struct {
+		ch, fallback string
+	}{
+		{"latest/edge", "latest/beta"},
+		{"latest/beta", "latest/candidate"},
+		{"candidate", "latest/stable"},
+		{"2.0/candidate", "2.0/stable"},
+		{"2.0/candidate/fix-1", "2.0/candidate"},
+		{"latest/stable", ""},
+		{"stable", ""},
+		{"", ""},
+	} {
+		c.Check(snapstate.FallbackChannel(tc.ch), Equals, tc.fallback, Commentf(tc.ch))
+	}
+}
+
+func (s *snapmgrTestSuite) mockSnapTracking(c *C, tracking string) {
+	si := snap.SideInfo{
+		RealName: "some-snap",
+		Revision: snap.R(7),
+		SnapID:   "some-snap-id",
+	}
+	snaptest.MockSnap(c, `name: some-snap`, &si)
+
+	snapstate.Set(s.state, "some-snap", &snapstate.SnapState{
+		Active:          true,
+		Sequence:        snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{&si}),
+		Current:         si.Revision,
+		SnapType:        "app",
+		TrackingChannel: tracking,
+	})
+}
+
+func (s *snapmgrTestSuite) setRiskFallback(c *C, snaps string) {
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "refresh.risk-fallback", snaps), IsNil)
+	tr.Commit()
+}
+
+func (s *snapmgrTestSuite) TestUpdateRiskFallbackDisabled(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.fakeStore.emptyChannels = map[string]bool{"latest/candidate": true}
+	s.mockSnapTracking(c, "latest/candidate")
+	s.setRiskFallback(c, "other-snap")
+
+	_, err := snapstate.Update(s.state, "some-snap", nil, s.user.ID, snapstate.Flags{})
+	c.Check(err, FitsTypeOf, &store.RevisionNotAvailableError{})
+}
+
+func (s *snapmgrTestSuite) TestUpdateRiskFallback(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.fakeStore.emptyChannels = map[string]bool{"latest/beta": true, "latest/candidate": true}
+	s.mockSnapTracking(c, "latest/beta")
+	s.setRiskFallback(c, "other-snap,some-snap")
+
+	ts, err := snapstate.Update(s.state, "some-snap", nil, s.user.ID, snapstate.Flags{})
+	c.Assert(err, IsNil)
+
+	snapsup, err := snapstate.TaskSnapSetup(ts.Tasks()[0])
+	c.Assert(err, IsNil)
+	// the snap keeps tracking its channel
+	c.Check(snapsup.Channel, Equals, "latest/beta")
+	c.Check(snapsup.Revision(), Equals, snap.R(11))
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	c.Check(snapst.RiskFallbackChannel, Equals, "latest/stable")
+}
+
+func (s *snapmgrTestSuite) TestUpdateManyRiskFallback(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.fakeStore.emptyChannels = map[string]bool{"latest/candidate": true}
+	s.mockSnapTracking(c, "latest/candidate")
+	s.setRiskFallback(c, "some-snap")
+
+	updates, _, err := snapstate.UpdateMany(context.Background(), s.state, nil, nil, s.user.ID, nil)
+	c.Assert(err, IsNil)
+	c.Check(updates, DeepEquals, []string{"some-snap"})
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	c.Check(snapst.RiskFallbackChannel, Equals, "latest/stable")
+
+	// the fallback is cleared once the tracked channel has a revision
+	s.fakeStore.emptyChannels = nil
+	updates, _, err = snapstate.UpdateMany(context.Background(), s.state, nil, nil, s.user.ID, nil)
+	c.Assert(err, IsNil)
+	c.Check(updates, DeepEquals, []string{"some-snap"})
+
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	c.Check(snapst.RiskFallbackChannel, Equals, "")
+}
@@ -0,0 +1,189 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+var osutilBootID = osutil.BootID
+
+// snapdRollout holds the policy controlling how the snap providing snapd is
+// refreshed, so that fleets of devices can stagger snapd updates.
+type snapdRollout struct {
+	// cohortKey is the store cohort followed by the snapd refreshes
+	cohortKey string
+	// delay is how long a revision must have been in the store before
+	// auto-refreshing to it
+	delay time.Duration
+	// healthyBoots is how many consecutive healthy boots are required
+	// before auto-refreshing
+	healthyBoots int
+}
+
+func coreConfigInt(tr *config.Transaction, option string) (int, error) {
+	var val interface{}
+	if err := tr.Get("core", option, &val); err != nil {
+		if config.IsNoOption(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	n, err := strconv.Atoi(fmt.Sprintf("%v", val))
+	if err != nil {
+		return 0, fmt.Errorf("internal error: invalid %s value %v", option, val)
+	}
+	return n, nil
+}
+
+func getSnapdRollout(st *state.State) (*snapdRollout, error) {
+	tr := config.NewTransaction(st)
+
+	var rollout snapdRollout
+	if err := tr.GetMaybe("core", "refresh.snapd.cohort", &rollout.cohortKey); err != nil {
+		return nil, err
+	}
+	delayDays, err := coreConfigInt(tr, "refresh.snapd.delay-days")
+	if err != nil {
+		return nil, err
+	}
+	rollout.delay = time.Duration(delayDays) * 24 * time.Hour
+	rollout.healthyBoots, err = coreConfigInt(tr, "refresh.snapd.healthy-boots")
+	if err != nil {
+		return nil, err
+	}
+	return &rollout, nil
+}
+
+// snapdProvider returns the name of the snap providing snapd, i.e. the snapd
+// snap if installed, otherwise the core snap.
+func snapdProvider(snapStates map[string]*SnapState) string {
+	var provider string
+	for name, snapst := range snapStates {
+		switch typ, _ := snapst.Type(); typ {
+		case snap.TypeSnapd:
+			return name
+		case snap.TypeOS:
+			provider = name
+		}
+	}
+	return provider
+}
+
+// bootHealth tracks the boots of the system.
+type bootHealth struct {
+	// BootID is the id of the last recorded boot
+	BootID string `json:"boot-id"`
+	// Streak is the number of consecutive healthy boots
+	Streak int `json:"streak"`
+}
+
+// recordBoot records the current boot as healthy or not. A boot is counted
+// only once, but an unhealthy boot resets the streak of healthy boots in any
+// case.
+func recordBoot(st *state.State, healthy bool) error {
+	bootID, err := osutilBootID()
+	if err != nil {
+		return err
+	}
+
+	var health bootHealth
+	if err := st.Get("boot-health", &health); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+
+	switch {
+	case !healthy:
+		health.Streak = 0
+	case health.BootID != bootID:
+		health.Streak++
+	}
+	health.BootID = bootID
+	st.Set("boot-health", health)
+	return nil
+}
+
+func healthyBootStreak(st *state.State) (int, error) {
+	var health bootHealth
+	if err := st.Get("boot-health", &health); err != nil && !errors.Is(err, state.ErrNoState) {
+		return 0, err
+	}
+	return health.Streak, nil
+}
+
+// filterSnapdRollout filters out the update of the snap providing snapd from
+// an auto-refresh until the update satisfies the snapd rollout policy.
+func filterSnapdRollout(st *state.State, updates []*snap.Info) ([]*snap.Info, error) {
+	rollout, err := getSnapdRollout(st)
+	if err != nil {
+		return nil, err
+	}
+	if rollout.delay == 0 && rollout.healthyBoots == 0 {
+		return updates, nil
+	}
+
+	snapStates, err := All(st)
+	if err != nil {
+		return nil, err
+	}
+	provider := snapdProvider(snapStates)
+
+	filtered := make([]*snap.Info, 0, len(updates))
+	for _, update := range updates {
+		if update.InstanceName() != provider {
+			filtered = append(filtered, update)
+			continue
+		}
+
+		if rollout.delay != 0 {
+			// without knowing when the revision was created, err on
+			// the side of waiting
+			if update.CreatedAt.IsZero() || timeNow().Sub(update.CreatedAt) < rollout.delay {
+				logger.Noticef("Postponing auto-refresh of %q to revision %s: released less than %d days ago",
+					provider, update.Revision, rollout.delay/(24*time.Hour))
+				continue
+			}
+		}
+
+		if rollout.healthyBoots != 0 {
+			streak, err := healthyBootStreak(st)
+			if err != nil {
+				return nil, err
+			}
+			if streak < rollout.healthyBoots {
+				logger.Noticef("Postponing auto-refresh of %q to revision %s: %d of %d required healthy boots",
+					provider, update.Revision, streak, rollout.healthyBoots)
+				continue
+			}
+		}
+
+		filtered = append(filtered, update)
+	}
+	return filtered, nil
+}
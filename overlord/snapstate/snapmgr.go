@@ -294,6 +294,35 @@ type SnapState struct {
 	// their security profiles set up but are not active.
 	// It is managed by ifacestate.
 	PendingSecurity *PendingSecurityState `json:"pending-security,omitempty"`
+
+	// ProxyOverride holds the proxy settings of the snap if they
+	// override the system's proxy configuration.
+	ProxyOverride *ProxySettings `json:"proxy-override,omitempty"`
+
+	// DNSOverride holds the DNS settings of the snap if they override
+	// the system's name resolution configuration.
+	DNSOverride *DNSSettings `json:"dns-override,omitempty"`
+
+	// Hardening holds the hardening options enabled for the snap by the
+	// administrator, if any.
+	Hardening *HardeningSettings `json:"hardening,omitempty"`
+
+	// SyscallProfilingSince is set to when profiling of the system calls
+	// used by the snap started, if they are being profiled.
+	SyscallProfilingSince *time.Time `json:"syscall-profiling-since,omitempty"`
+
+	// DataLocation is set to the directory holding the system data of the
+	// snap, bind mounted onto /var/snap/<snap>, if it was relocated.
+	DataLocation string `json:"data-location,omitempty"`
+
+	// BlockedRevisions lists the revisions of the snap that refreshes
+	// following a channel must skip.
+	BlockedRevisions []*BlockedRevision `json:"blocked-revisions,omitempty"`
+
+	// RiskFallbackChannel is the more stable channel refreshes of the
+	// snap fall back to, following the risk fallback policy, because its
+	// tracked channel has no revision available.
+	RiskFallbackChannel string `json:"risk-fallback-channel,omitempty"`
 }
 
 // PendingSecurityState holds information about snaps that have
@@ -619,6 +648,7 @@ func Manager(st *state.State, runner *state.TaskRunner) (*SnapManager, error) {
 	runner.AddHandler("start-snap-services", m.startSnapServices, m.undoStartSnapServices)
 	runner.AddHandler("switch-snap-channel", m.doSwitchSnapChannel, nil)
 	runner.AddHandler("toggle-snap-flags", m.doToggleSnapFlags, nil)
+	runner.AddHandler("release-quarantine", m.doReleaseQuarantine, m.undoReleaseQuarantine)
 	runner.AddHandler("check-rerefresh", m.doCheckReRefresh, nil)
 	runner.AddHandler("conditional-auto-refresh", m.doConditionalAutoRefresh, nil)
 
@@ -648,6 +678,8 @@ func Manager(st *state.State, runner *state.TaskRunner) (*SnapManager, error) {
 	// misc
 	runner.AddHandler("switch-snap", m.doSwitchSnap, nil)
 	runner.AddHandler("migrate-snap-home", m.doMigrateSnapHome, m.undoMigrateSnapHome)
+	runner.AddHandler("relocate-snap-data", m.doRelocateSnapData, m.undoRelocateSnapData)
+	runner.AddCleanup("relocate-snap-data", m.cleanupRelocateSnapData)
 	// no undo for now since it's last task in valset auto-resolution change
 	runner.AddHandler("enforce-validation-sets", m.doEnforceValidationSets, nil)
 	runner.AddHandler("pre-download-snap", m.doPreDownloadSnap, nil)
@@ -1221,12 +1253,27 @@ func (m *SnapManager) ensureMountsUpdated() error {
 
 	if len(allStates) != 0 {
 		sysd := getSystemD()
+		// on secured devices, snaps mounted through dm-verity stay so
+		verity := false
+		if deviceCtx, err := DeviceCtx(m.state, nil, nil); err == nil {
+			verity = verityMountsEnabled(deviceCtx)
+		}
 
 		for _, snapSt := range allStates {
 			info, err := snapSt.CurrentInfo()
 			if err != nil {
 				return err
 			}
+			if verity && backend.VerityStatus(info) != "" {
+				unitOptions, err := backend.MountUnitOptions(info, true)
+				if err != nil {
+					return err
+				}
+				if _, err = sysd.EnsureMountUnitFileWithOptions(unitOptions); err != nil {
+					return err
+				}
+				continue
+			}
 			squashfsPath := dirs.StripRootDir(info.MountFile())
 			whereDir := dirs.StripRootDir(info.MountDir())
 			if _, err = sysd.EnsureMountUnitFile(info.MountDescription(), squashfsPath, whereDir, "squashfs"); err != nil {
@@ -50,6 +50,7 @@ import (
 	"github.com/snapcore/snapd/overlord/snapstate/backend"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/release"
+	"github.com/snapcore/snapd/sandbox/wsl"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/channel"
 	"github.com/snapcore/snapd/snapdenv"
@@ -1046,6 +1047,11 @@ func validateFeatureFlags(st *state.State, info *snap.Info) error {
 		if !release.SystemctlSupportsUserUnits() {
 			return fmt.Errorf("user session daemons are not supported on this release")
 		}
+		// WSL only runs systemd user instances when configured to
+		// boot with systemd
+		if wslInfo := wsl.Probe(); wslInfo != nil && !wslInfo.UserSessions {
+			return fmt.Errorf("user session daemons are not supported in WSL without systemd user sessions")
+		}
 	}
 
 	if usesDbusActivation {
@@ -1082,6 +1088,16 @@ func ensureInstallPreconditions(st *state.State, info *snap.Info, flags Flags, s
 		flags.Unaliased = true
 	}
 
+	if !snapst.IsInstalled() {
+		quarantine, err := shouldQuarantine(st, info)
+		if err != nil {
+			return flags, err
+		}
+		if quarantine {
+			flags.Quarantined = true
+		}
+	}
+
 	if err := validateInfoAndFlags(info, snapst, flags); err != nil {
 		return flags, err
 	}
@@ -1290,6 +1306,9 @@ func installWithDeviceContext(st *state.State, name string, opts *RevisionOption
 	if opts.CohortKey != "" && !opts.Revision.Unset() {
 		return nil, errors.New("cannot specify revision and cohort")
 	}
+	if flags.Pinned && opts.Revision.Unset() {
+		return nil, fmt.Errorf("cannot pin snap %q without a revision", name)
+	}
 
 	if flags.Lane != 0 {
 		return nil, fmt.Errorf("transaction lane is unsupported in InstallWithDeviceContext")
@@ -1802,6 +1821,14 @@ func updateManyFiltered(ctx context.Context, st *state.State, names []string, re
 		return nil, nil, err
 	}
 
+	// staggered rollout of snapd updates only applies to auto-refreshes
+	if flags.IsAutoRefresh {
+		updates, err = filterSnapdRollout(st, updates)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// save the candidates so the auto-refresh can be continued if it's inhibited
 	// by a running snap.
 	if flags.IsAutoRefresh {
@@ -2451,6 +2478,10 @@ func updateWithDeviceContext(st *state.State, name string, opts *RevisionOptions
 		return nil, fmt.Errorf("refreshing disabled snap %q not supported", name)
 	}
 
+	if flags.Pinned && opts.Revision.Unset() {
+		return nil, fmt.Errorf("cannot pin snap %q without a revision", name)
+	}
+
 	// make sure we have a model set
 	deviceCtx, err = DevicePastSeeding(st, deviceCtx)
 	if err != nil {
@@ -64,6 +64,8 @@ import (
 	"github.com/snapcore/snapd/release"
 	"github.com/snapcore/snapd/sandbox"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/integrity"
+	"github.com/snapcore/snapd/snap/integrity/dmverity"
 	"github.com/snapcore/snapd/snap/naming"
 	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/snapdenv"
@@ -336,6 +338,7 @@ SNAPD_APPARMOR_REEXEC=1
 	// mock so the actual notification code isn't called. It races with the SetRootDir
 	// call in the TearDown function. It's harmless but triggers go test -race
 	s.AddCleanup(snapstate.MockAsyncPendingRefreshNotification(func(context.Context, *userclient.Client, *userclient.PendingSnapRefreshInfo) {}))
+	s.AddCleanup(snapstate.MockAsyncAppLifecycleNotification(func(context.Context, *userclient.Client, *userclient.AppLifecycleInfo) {}))
 }
 
 func (s *snapmgrBaseTest) TearDownTest(c *C) {
@@ -8838,6 +8841,60 @@ WantedBy=multi-user.target
 	c.Assert(mountFile, testutil.FileEquals, expectedContent)
 }
 
+func (s *snapmgrTestSuite) TestEnsureSnapStateRewriteMountsKeepsVerity(c *C) {
+	restore := snapstate.MockEnsuredMountsUpdated(s.snapmgr, false)
+	defer restore()
+	restore = snapstatetest.MockDeviceModel(MakeModel20("pc", map[string]interface{}{
+		"grade": "secured",
+	}))
+	defer restore()
+
+	testSnapSideInfo := &snap.SideInfo{RealName: "test-snap", Revision: snap.R(42)}
+	testSnapState := &snapstate.SnapState{
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{testSnapSideInfo}),
+		Current:  snap.R(42),
+		Active:   true,
+		SnapType: "app",
+	}
+
+	s.state.Lock()
+	snapstate.Set(s.state, "test-snap", testSnapState)
+	info := snaptest.MockSnapCurrent(c, "name: test-snap\nversion: v1\n", testSnapSideInfo)
+	s.state.Unlock()
+
+	// dm-verity data generated when the snap was installed
+	header, err := integrity.IntegrityDataHeader{
+		Type:     "integrity",
+		Size:     integrity.HeaderSize + 4096,
+		DmVerity: dmverity.Info{RootHash: "abcd"},
+	}.Encode()
+	c.Assert(err, IsNil)
+	c.Assert(os.MkdirAll(dirs.SnapBlobDir, 0755), IsNil)
+	c.Assert(os.WriteFile(info.MountFile(), make([]byte, 16384), 0644), IsNil)
+	c.Assert(os.WriteFile(integrity.VerityFilePath(info.MountFile()), append(header, make([]byte, 4096)...), 0600), IsNil)
+
+	what := "/var/lib/snapd/snaps/test-snap_42.snap"
+	unitName := systemd.EscapeUnitNamePath(dirs.StripRootDir(filepath.Join(dirs.SnapMountDir, "test-snap", "42.mount")))
+	mountFile := filepath.Join(dirs.SnapServicesDir, unitName)
+	mountContent := fmt.Sprintf(`
+[Unit]
+Description=Mount unit for test-snap, revision 42
+
+[Mount]
+What=%s
+Where=%s/test-snap/42
+Type=squashfs
+Options=nodev,ro,verity.roothash=abcd,verity.hashdevice=%[1]s.verity,verity.hashoffset=4096
+`[1:], what, dirs.SnapMountDir)
+	c.Assert(os.MkdirAll(dirs.SnapServicesDir, 0755), IsNil)
+	c.Assert(os.WriteFile(mountFile, []byte(mountContent), 0644), IsNil)
+
+	err = s.snapmgr.Ensure()
+	c.Assert(err, IsNil)
+
+	c.Check(mountFile, testutil.FileContains, "\nOptions=nodev,ro,x-gdu.hide,x-gvfs-hide,verity.roothash=abcd,verity.hashdevice=/var/lib/snapd/snaps/test-snap_42.snap.verity,verity.hashoffset=4096\n")
+}
+
 func (s *snapmgrTestSuite) TestEnsureSnapStateRewriteMountsNoChange(c *C) {
 	restore := snapstate.MockEnsuredMountsUpdated(s.snapmgr, false)
 	defer restore()
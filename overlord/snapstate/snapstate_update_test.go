@@ -11757,3 +11757,116 @@ func (s *snapmgrTestSuite) TestUpdateManyFilteredNotAutoRefreshNoRetry(c *C) {
 	c.Check(storeSnapIDs["some-snap-id"], Equals, true)
 	c.Check(storeSnapIDs["some-other-snap-id"], Equals, true)
 }
+
+func (s *snapmgrTestSuite) mockSnapdAndSomeSnapInstalled(c *C) {
+	for _, si := range []*snap.SideInfo{
+		{RealName: "snapd", SnapID: "snapd-snap-id", Revision: snap.R(1)},
+		{RealName: "some-snap", SnapID: "some-snap-id", Revision: snap.R(1)},
+	} {
+		typ := "app"
+		if si.RealName == "snapd" {
+			typ = "snapd"
+		}
+		snapstate.Set(s.state, si.RealName, &snapstate.SnapState{
+			Active:   true,
+			Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+			Current:  si.Revision,
+			SnapType: typ,
+		})
+	}
+}
+
+func (s *snapmgrTestSuite) TestUpdateManyAutoRefreshSnapdRolloutDelay(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapdAndSomeSnapInstalled(c)
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "refresh.snapd.delay-days", 3), IsNil)
+	tr.Commit()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	restore := snapstate.MockTimeNow(func() time.Time { return now })
+	defer restore()
+
+	// the snapd revision was released too recently
+	s.fakeStore.refreshCreatedAt = map[string]time.Time{
+		"snapd-snap-id": now.Add(-2 * 24 * time.Hour),
+	}
+	updates, _, err := snapstate.UpdateMany(context.Background(), s.state, nil, nil, 0, &snapstate.Flags{IsAutoRefresh: true})
+	c.Assert(err, IsNil)
+	c.Check(updates, DeepEquals, []string{"some-snap"})
+
+	// the delay doesn't apply to manual refreshes
+	updates, _, err = snapstate.UpdateMany(context.Background(), s.state, nil, nil, 0, nil)
+	c.Assert(err, IsNil)
+	sort.Strings(updates)
+	c.Check(updates, DeepEquals, []string{"snapd", "some-snap"})
+
+	// the delay has passed
+	s.fakeStore.refreshCreatedAt["snapd-snap-id"] = now.Add(-4 * 24 * time.Hour)
+	updates, _, err = snapstate.UpdateMany(context.Background(), s.state, nil, nil, 0, &snapstate.Flags{IsAutoRefresh: true})
+	c.Assert(err, IsNil)
+	sort.Strings(updates)
+	c.Check(updates, DeepEquals, []string{"snapd", "some-snap"})
+}
+
+func (s *snapmgrTestSuite) TestUpdateManyAutoRefreshSnapdRolloutHealthyBoots(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapdAndSomeSnapInstalled(c)
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "refresh.snapd.healthy-boots", "2"), IsNil)
+	tr.Commit()
+
+	bootID := "boot-id-1"
+	restore := snapstate.MockOsutilBootID(func() (string, error) { return bootID, nil })
+	defer restore()
+
+	// no healthy boot recorded yet
+	updates, _, err := snapstate.UpdateMany(context.Background(), s.state, nil, nil, 0, &snapstate.Flags{IsAutoRefresh: true})
+	c.Assert(err, IsNil)
+	c.Check(updates, DeepEquals, []string{"some-snap"})
+
+	// a single boot is not enough
+	c.Assert(snapstate.UpdateBootRevisions(s.state), IsNil)
+	c.Assert(snapstate.UpdateBootRevisions(s.state), IsNil)
+	updates, _, err = snapstate.UpdateMany(context.Background(), s.state, nil, nil, 0, &snapstate.Flags{IsAutoRefresh: true})
+	c.Assert(err, IsNil)
+	c.Check(updates, DeepEquals, []string{"some-snap"})
+
+	bootID = "boot-id-2"
+	c.Assert(snapstate.UpdateBootRevisions(s.state), IsNil)
+	updates, _, err = snapstate.UpdateMany(context.Background(), s.state, nil, nil, 0, &snapstate.Flags{IsAutoRefresh: true})
+	c.Assert(err, IsNil)
+	sort.Strings(updates)
+	c.Check(updates, DeepEquals, []string{"snapd", "some-snap"})
+}
+
+func (s *snapmgrTestSuite) TestUpdateManySnapdRolloutCohort(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapdAndSomeSnapInstalled(c)
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("core", "refresh.snapd.cohort", "snapd-cohort"), IsNil)
+	tr.Commit()
+
+	_, _, err := snapstate.UpdateMany(context.Background(), s.state, nil, nil, 0, nil)
+	c.Assert(err, IsNil)
+
+	op := s.fakeBackend.ops.First("storesvc-snap-action")
+	c.Assert(op, NotNil)
+	cohorts := make(map[string]string)
+	for _, cur := range op.curSnaps {
+		cohorts[cur.InstanceName] = cur.CohortKey
+	}
+	c.Check(cohorts, DeepEquals, map[string]string{
+		"snapd":     "snapd-cohort",
+		"some-snap": "",
+	})
+}
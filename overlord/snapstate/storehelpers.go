@@ -420,6 +420,11 @@ func updateInfo(st *state.State, snapst *SnapState, opts *RevisionOptions, userI
 		}
 	}
 
+	if snapst.Pinned && action.Revision.Unset() {
+		logger.Noticef("skipping refresh of snap %q pinned to revision %s", curInfo.InstanceName(), snapst.Current)
+		return nil, store.ErrNoUpdateAvailable
+	}
+
 	// only set cohort if validation sets don't require a specific revision
 	if action.Revision.Unset() {
 		action.CohortKey = opts.CohortKey
@@ -444,6 +449,15 @@ func updateInfo(st *state.State, snapst *SnapState, opts *RevisionOptions, userI
 	st.Lock()
 
 	sar, err := singleActionResult(curInfo.InstanceName(), action.Action, res, err)
+	tracking := opts.Channel
+	if tracking == "" {
+		tracking = snapst.TrackingChannel
+	}
+	sar, err = resolveWithRiskFallback(context.TODO(), st, theStore, curSnaps, action, snapst, tracking, sar, err, user, refreshOpts)
+	if err == nil && action.Revision.Unset() && snapst.IsRevisionBlocked(sar.Info.Revision) {
+		logger.Noticef("skipping refresh of snap %q to blocked revision %s", curInfo.InstanceName(), sar.Info.Revision)
+		return nil, store.ErrNoUpdateAvailable
+	}
 	return sar.Info, err
 }
 
@@ -774,6 +788,7 @@ func refreshCandidatesCore(ctx context.Context, st *state.State, names []string,
 	actionsByUserID := make(map[int][]*store.SnapAction)
 	stateByInstanceName := make(map[string]*SnapState, len(snapStates))
 	ignoreValidationByInstanceName := make(map[string]bool)
+	mayBeBlocked := make(map[string]bool)
 	nCands := 0
 
 	var enforcedSets *snapasserts.ValidationSets
@@ -796,6 +811,13 @@ func refreshCandidatesCore(ctx context.Context, st *state.State, names []string,
 	// sorting MUST be done after revOptsByName is built to avoid misalignment
 	sort.Strings(names)
 
+	rollout, err := getSnapdRollout(st)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	snapdCohortKey := rollout.cohortKey
+	snapdProviderName := snapdProvider(snapStates)
+
 	addCand := func(installed *store.CurrentSnap, snapst *SnapState) error {
 		// FIXME: snaps that are not active are skipped for now
 		//        until we know what we want to do
@@ -843,12 +865,25 @@ func refreshCandidatesCore(ctx context.Context, st *state.State, names []string,
 			}
 		}
 
+		// pinned snaps are only refreshed to explicitly required revisions
+		if snapst.Pinned && action.Revision.Unset() {
+			logger.Debugf("snap %q is pinned to revision %s, skipping", installed.InstanceName, installed.Revision)
+			return nil
+		}
+
 		if !action.Revision.Unset() {
 			// ignore cohort if revision is specified
 			installed.CohortKey = ""
+		} else if installed.InstanceName == snapdProviderName && snapdCohortKey != "" {
+			// follow the cohort of the snapd rollout
+			installed.CohortKey = snapdCohortKey
 		}
 
 		stateByInstanceName[installed.InstanceName] = snapst
+		// revisions required explicitly are not subject to blocks
+		if action.Revision.Unset() && len(snapst.BlockedRevisions) > 0 {
+			mayBeBlocked[installed.InstanceName] = true
+		}
 
 		if len(names) == 0 {
 			installed.Block = snapst.Block()
@@ -914,6 +949,7 @@ func refreshCandidatesCore(ctx context.Context, st *state.State, names []string,
 		st.Unlock()
 		sarsForUser, _, err := theStore.SnapAction(ctx, curSnaps, actions, nil, u, opts)
 		st.Lock()
+		var refreshErrs map[string]error
 		if err != nil {
 			saErr, ok := err.(*store.SnapActionError)
 			if !ok {
@@ -921,9 +957,34 @@ func refreshCandidatesCore(ctx context.Context, st *state.State, names []string,
 			}
 			// TODO: use the warning infra here when we have it
 			logger.Noticef("%v", saErr)
+			refreshErrs = saErr.Refresh
+		}
+
+		resolved := make(map[string]bool, len(sarsForUser))
+		for _, sar := range sarsForUser {
+			resolved[sar.Info.InstanceName()] = true
+		}
+		for _, action := range actions {
+			actionErr := refreshErrs[action.InstanceName]
+			if !resolved[action.InstanceName] && actionErr == nil {
+				continue
+			}
+			snapst := stateByInstanceName[action.InstanceName]
+			sar, err := resolveWithRiskFallback(ctx, st, theStore, curSnaps, action, snapst, snapst.TrackingChannel, store.SnapActionResult{}, actionErr, u, opts)
+			if err != nil && !isRevisionNotAvailable(err) && !errors.Is(err, store.ErrNoUpdateAvailable) {
+				logger.Noticef("cannot resolve refresh of snap %q: %v", action.InstanceName, err)
+			}
+			if sar.Info != nil && !resolved[action.InstanceName] {
+				sarsForUser = append(sarsForUser, sar)
+			}
 		}
 
 		for _, sar := range sarsForUser {
+			instanceName := sar.Info.InstanceName()
+			if mayBeBlocked[instanceName] && stateByInstanceName[instanceName].IsRevisionBlocked(sar.Info.Revision) {
+				logger.Noticef("skipping refresh of snap %q to blocked revision %s", instanceName, sar.Info.Revision)
+				continue
+			}
 			updates = append(updates, sar.Info)
 		}
 	}
@@ -0,0 +1,104 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/sandbox/seccomp"
+	"github.com/snapcore/snapd/snap"
+)
+
+var seccompSupportsAction = seccomp.SupportsAction
+
+// SyscallProfilingSince returns when profiling of the system calls used by
+// the given snap started, or the zero time if they are not being profiled.
+func SyscallProfilingSince(st *state.State, instanceName string) (time.Time, error) {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+		return time.Time{}, err
+	}
+	if snapst.SyscallProfilingSince == nil {
+		return time.Time{}, nil
+	}
+	return *snapst.SyscallProfilingSince, nil
+}
+
+// SyscallProfiling starts or stops profiling the system calls used by the
+// given snap. While profiling, the seccomp filters of the snap allow and log
+// all system calls. It returns the task set updating the security profiles
+// of the snap accordingly.
+func SyscallProfiling(st *state.State, instanceName string, enable bool) (*state.TaskSet, error) {
+	var snapst SnapState
+	if err := Get(st, instanceName, &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return nil, &snap.NotInstalledError{Snap: instanceName}
+		}
+		return nil, err
+	}
+	if enable == (snapst.SyscallProfilingSince != nil) {
+		if enable {
+			return nil, fmt.Errorf("cannot start profiling system calls of snap %q: already profiling", instanceName)
+		}
+		return nil, fmt.Errorf("cannot stop profiling system calls of snap %q: not profiling", instanceName)
+	}
+	if enable {
+		if !snapst.Active {
+			return nil, fmt.Errorf("cannot profile system calls of inactive snap %q", instanceName)
+		}
+		if snapst.Classic {
+			return nil, fmt.Errorf("cannot profile system calls of classic snap %q", instanceName)
+		}
+		if !seccompSupportsAction("log") {
+			return nil, fmt.Errorf("cannot profile system calls of snap %q: seccomp cannot log system calls on this system", instanceName)
+		}
+	}
+	if err := CheckChangeConflict(st, instanceName, nil); err != nil {
+		return nil, err
+	}
+
+	if enable {
+		now := timeNow()
+		snapst.SyscallProfilingSince = &now
+	} else {
+		snapst.SyscallProfilingSince = nil
+	}
+	Set(st, instanceName, &snapst)
+
+	ts := state.NewTaskSet()
+	if !snapst.Active {
+		// the profiles are set up without profiling once the snap is
+		// enabled again
+		return ts, nil
+	}
+	si := snapst.CurrentSideInfo()
+	setupProfiles := st.NewTask("setup-profiles", fmt.Sprintf(i18n.G("Update snap %q (%s) security profiles"), instanceName, si.Revision))
+	setupProfiles.Set("snap-setup", &SnapSetup{
+		SideInfo:    si,
+		InstanceKey: snapst.InstanceKey,
+		Type:        snap.Type(snapst.SnapType),
+	})
+	ts.AddTask(setupProfiles)
+	return ts, nil
+}
@@ -0,0 +1,135 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/sandbox/seccomp"
+	"github.com/snapcore/snapd/snap"
+)
+
+func (s *snapmgrTestSuite) setupSyscallProfilingSnap(c *C, snapst *snapstate.SnapState) {
+	si := &snap.SideInfo{RealName: "some-snap", Revision: snap.R(3)}
+	snapst.Sequence = snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si})
+	snapst.Current = si.Revision
+	snapst.SnapType = "app"
+	snapstate.Set(s.state, "some-snap", snapst)
+}
+
+func (s *snapmgrTestSuite) TestSyscallProfiling(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	restore := seccomp.MockActions([]string{"allow", "errno", "log"})
+	defer restore()
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	restore = snapstate.MockTimeNow(func() time.Time { return now })
+	defer restore()
+
+	s.setupSyscallProfilingSnap(c, &snapstate.SnapState{Active: true})
+
+	since, err := snapstate.SyscallProfilingSince(s.state, "some-snap")
+	c.Assert(err, IsNil)
+	c.Check(since.IsZero(), Equals, true)
+
+	ts, err := snapstate.SyscallProfiling(s.state, "some-snap", true)
+	c.Assert(err, IsNil)
+	c.Assert(ts.Tasks(), HasLen, 1)
+	task := ts.Tasks()[0]
+	c.Check(task.Kind(), Equals, "setup-profiles")
+	c.Check(task.Summary(), Equals, `Update snap "some-snap" (3) security profiles`)
+	snapsup, err := snapstate.TaskSnapSetup(task)
+	c.Assert(err, IsNil)
+	c.Check(snapsup.InstanceName(), Equals, "some-snap")
+	c.Check(snapsup.Revision(), Equals, snap.R(3))
+
+	since, err = snapstate.SyscallProfilingSince(s.state, "some-snap")
+	c.Assert(err, IsNil)
+	c.Check(since.Equal(now), Equals, true)
+
+	_, err = snapstate.SyscallProfiling(s.state, "some-snap", true)
+	c.Check(err, ErrorMatches, `cannot start profiling system calls of snap "some-snap": already profiling`)
+
+	ts, err = snapstate.SyscallProfiling(s.state, "some-snap", false)
+	c.Assert(err, IsNil)
+	c.Check(taskKinds(ts.Tasks()), DeepEquals, []string{"setup-profiles"})
+
+	since, err = snapstate.SyscallProfilingSince(s.state, "some-snap")
+	c.Assert(err, IsNil)
+	c.Check(since.IsZero(), Equals, true)
+
+	_, err = snapstate.SyscallProfiling(s.state, "some-snap", false)
+	c.Check(err, ErrorMatches, `cannot stop profiling system calls of snap "some-snap": not profiling`)
+}
+
+func (s *snapmgrTestSuite) TestSyscallProfilingErrors(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	restore := seccomp.MockActions([]string{"allow", "errno"})
+	defer restore()
+
+	_, err := snapstate.SyscallProfiling(s.state, "some-snap", true)
+	c.Check(err, ErrorMatches, `snap "some-snap" is not installed`)
+
+	s.setupSyscallProfilingSnap(c, &snapstate.SnapState{Active: true})
+	_, err = snapstate.SyscallProfiling(s.state, "some-snap", true)
+	c.Check(err, ErrorMatches, `cannot profile system calls of snap "some-snap": seccomp cannot log system calls on this system`)
+
+	s.setupSyscallProfilingSnap(c, &snapstate.SnapState{Active: true, Flags: snapstate.Flags{Classic: true}})
+	_, err = snapstate.SyscallProfiling(s.state, "some-snap", true)
+	c.Check(err, ErrorMatches, `cannot profile system calls of classic snap "some-snap"`)
+
+	s.setupSyscallProfilingSnap(c, &snapstate.SnapState{})
+	_, err = snapstate.SyscallProfiling(s.state, "some-snap", true)
+	c.Check(err, ErrorMatches, `cannot profile system calls of inactive snap "some-snap"`)
+
+	// the snap is busy
+	restore = seccomp.MockActions([]string{"allow", "errno", "log"})
+	defer restore()
+	s.setupSyscallProfilingSnap(c, &snapstate.SnapState{Active: true})
+	chg := s.state.NewChange("refresh", "...")
+	task := s.state.NewTask("link-snap", "...")
+	task.Set("snap-setup", &snapstate.SnapSetup{SideInfo: &snap.SideInfo{RealName: "some-snap"}})
+	chg.AddTask(task)
+	_, err = snapstate.SyscallProfiling(s.state, "some-snap", true)
+	c.Check(err, ErrorMatches, `snap "some-snap" has "refresh" change in progress`)
+}
+
+func (s *snapmgrTestSuite) TestSyscallProfilingStopInactive(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	since := time.Now()
+	s.setupSyscallProfilingSnap(c, &snapstate.SnapState{SyscallProfilingSince: &since})
+
+	ts, err := snapstate.SyscallProfiling(s.state, "some-snap", false)
+	c.Assert(err, IsNil)
+	c.Check(ts.Tasks(), HasLen, 0)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	c.Check(snapst.SyscallProfilingSince, IsNil)
+}
@@ -145,7 +145,7 @@ type Change struct {
 	summary            string
 	status             Status
 	clean              bool
-	data               customData
+	data               lazyCustomData
 	taskIDs            []string
 	ready              chan struct{}
 	lastObservedStatus Status
@@ -166,7 +166,7 @@ func newChange(state *State, id, kind, summary string) *Change {
 		id:      id,
 		kind:    kind,
 		summary: summary,
-		data:    make(customData),
+		data:    lazyCustomData{data: make(customData)},
 		ready:   make(chan struct{}),
 
 		spawnTime: timeNow(),
@@ -174,13 +174,13 @@ func newChange(state *State, id, kind, summary string) *Change {
 }
 
 type marshalledChange struct {
-	ID      string                      `json:"id"`
-	Kind    string                      `json:"kind"`
-	Summary string                      `json:"summary"`
-	Status  Status                      `json:"status"`
-	Clean   bool                        `json:"clean,omitempty"`
-	Data    map[string]*json.RawMessage `json:"data,omitempty"`
-	TaskIDs []string                    `json:"task-ids,omitempty"`
+	ID      string          `json:"id"`
+	Kind    string          `json:"kind"`
+	Summary string          `json:"summary"`
+	Status  Status          `json:"status"`
+	Clean   bool            `json:"clean,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	TaskIDs []string        `json:"task-ids,omitempty"`
 
 	SpawnTime time.Time  `json:"spawn-time"`
 	ReadyTime *time.Time `json:"ready-time,omitempty"`
@@ -193,13 +193,17 @@ func (c *Change) MarshalJSON() ([]byte, error) {
 	if !c.readyTime.IsZero() {
 		readyTime = &c.readyTime
 	}
+	data, err := c.data.marshalled()
+	if err != nil {
+		return nil, err
+	}
 	return json.Marshal(marshalledChange{
 		ID:      c.id,
 		Kind:    c.kind,
 		Summary: c.summary,
 		Status:  c.status,
 		Clean:   c.clean,
-		Data:    c.data,
+		Data:    data,
 		TaskIDs: c.taskIDs,
 
 		SpawnTime: c.spawnTime,
@@ -222,11 +226,9 @@ func (c *Change) UnmarshalJSON(data []byte) error {
 	c.summary = unmarshalled.Summary
 	c.status = unmarshalled.Status
 	c.clean = unmarshalled.Clean
-	custData := unmarshalled.Data
-	if custData == nil {
-		custData = make(customData)
+	if err := c.data.setRaw(unmarshalled.Data); err != nil {
+		return err
 	}
-	c.data = custData
 	c.taskIDs = unmarshalled.TaskIDs
 	c.ready = make(chan struct{})
 	c.spawnTime = unmarshalled.SpawnTime
@@ -51,6 +51,7 @@ func (s *State) AddWarning(message string, lastAdded, lastShown time.Time, expir
 		lastShown:   lastShown,
 		expireAfter: expireAfter,
 		repeatAfter: repeatAfter,
+		severity:    WarningSeverityWarning,
 	}, lastAdded)
 }
 
@@ -72,6 +73,7 @@ var (
 	ErrNoWarningFirstAdded  = errNoWarningFirstAdded
 	ErrNoWarningExpireAfter = errNoWarningExpireAfter
 	ErrNoWarningRepeatAfter = errNoWarningRepeatAfter
+	ErrBadWarningSeverity   = errBadWarningSeverity
 )
 
 // NumNotices returns the total bumber of notices, including expired ones that
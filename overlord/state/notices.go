@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -190,11 +191,15 @@ const (
 	// Warnings are a subset of notices where the key is a human-readable
 	// warning message.
 	WarningNotice NoticeType = "warning"
+
+	// Recorded by snaps themselves through snapctl. The key for snap
+	// notices is namespaced by the snap, as "<snap instance name>/<key>".
+	SnapNotice NoticeType = "snap"
 )
 
 func (t NoticeType) Valid() bool {
 	switch t {
-	case ChangeUpdateNotice, WarningNotice:
+	case ChangeUpdateNotice, WarningNotice, SnapNotice:
 		return true
 	}
 	return false
@@ -277,6 +282,12 @@ func validateNotice(noticeType NoticeType, key string, options *AddNoticeOptions
 	if key == "" {
 		return fmt.Errorf("internal error: attempted to add %s notice with invalid key %q", noticeType, key)
 	}
+	if noticeType == SnapNotice {
+		snapName, name, ok := strings.Cut(key, "/")
+		if !ok || snapName == "" || name == "" {
+			return fmt.Errorf("internal error: attempted to add %s notice with key %q not namespaced by snap", noticeType, key)
+		}
+	}
 	return nil
 }
 
@@ -298,6 +309,10 @@ type NoticeFilter struct {
 	// Keys, if not empty, includes only notices whose key is one of these.
 	Keys []string
 
+	// Snaps, if not empty, includes only snap notices recorded by one of
+	// these snaps.
+	Snaps []string
+
 	// After, if set, includes only notices that were last repeated after this time.
 	After time.Time
 }
@@ -317,6 +332,15 @@ func (f *NoticeFilter) matches(n *Notice) bool {
 	if len(f.Keys) > 0 && !sliceContains(f.Keys, n.key) {
 		return false
 	}
+	if len(f.Snaps) > 0 {
+		if n.noticeType != SnapNotice {
+			return false
+		}
+		snapName, _, _ := strings.Cut(n.key, "/")
+		if !sliceContains(f.Snaps, snapName) {
+			return false
+		}
+	}
 	if !f.After.IsZero() && !n.lastRepeated.After(f.After) {
 		return false
 	}
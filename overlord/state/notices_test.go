@@ -321,6 +321,46 @@ func (s *noticesSuite) TestNoticesFilterType(c *C) {
 	c.Check(n["key"], Equals, "Warning 2!")
 }
 
+func (s *noticesSuite) TestAddSnapNoticeKey(c *C) {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	for _, key := range []string{"foo", "/bar", "foo/", "/"} {
+		_, err := st.AddNotice(nil, state.SnapNotice, key, nil)
+		c.Check(err, ErrorMatches, `internal error: attempted to add snap notice with key ".*" not namespaced by snap`)
+	}
+
+	id, err := st.AddNotice(nil, state.SnapNotice, "foo/bar/baz", nil)
+	c.Assert(err, IsNil)
+	c.Check(st.Notice(id), NotNil)
+}
+
+func (s *noticesSuite) TestNoticesFilterSnaps(c *C) {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	addNotice(c, st, nil, state.SnapNotice, "foo/ready", nil)
+	time.Sleep(time.Microsecond)
+	addNotice(c, st, nil, state.SnapNotice, "bar/ready", nil)
+	time.Sleep(time.Microsecond)
+	addNotice(c, st, nil, state.WarningNotice, "foo/ready", nil)
+	time.Sleep(time.Microsecond)
+	addNotice(c, st, nil, state.SnapNotice, "baz/done", nil)
+
+	notices := st.Notices(&state.NoticeFilter{Snaps: []string{"foo"}})
+	c.Assert(notices, HasLen, 1)
+	n := noticeToMap(c, notices[0])
+	c.Check(n["type"], Equals, "snap")
+	c.Check(n["key"], Equals, "foo/ready")
+
+	notices = st.Notices(&state.NoticeFilter{Snaps: []string{"foo", "baz"}})
+	c.Assert(notices, HasLen, 2)
+	c.Check(noticeToMap(c, notices[0])["key"], Equals, "foo/ready")
+	c.Check(noticeToMap(c, notices[1])["key"], Equals, "baz/done")
+}
+
 func (s *noticesSuite) TestNoticesFilterKey(c *C) {
 	st := state.New(nil)
 	st.Lock()
@@ -72,6 +72,92 @@ func (data customData) set(key string, value interface{}) {
 	data[key] = &entryJSON
 }
 
+// lazyCustomData keeps custom data as raw JSON until it is first
+// accessed. Most tasks and changes in the state are historical and
+// their data is never looked at again, so this avoids holding a map
+// and one allocation per entry for each of them.
+type lazyCustomData struct {
+	raw  json.RawMessage
+	data customData
+}
+
+func (d *lazyCustomData) setRaw(raw json.RawMessage) error {
+	d.data = nil
+	d.raw = nil
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if raw[0] != '{' {
+		return fmt.Errorf("cannot unmarshal custom data: expected a JSON object")
+	}
+	d.raw = raw
+	return nil
+}
+
+func (d *lazyCustomData) decoded() customData {
+	if d.data != nil {
+		return d.data
+	}
+	data := make(customData)
+	if len(d.raw) != 0 {
+		if err := json.Unmarshal(d.raw, &data); err != nil {
+			logger.Panicf("internal error: could not unmarshal custom data: %v", err)
+		}
+	}
+	d.data = data
+	d.raw = nil
+	return d.data
+}
+
+func (d *lazyCustomData) get(key string, value interface{}) error {
+	return d.decoded().get(key, value)
+}
+
+func (d *lazyCustomData) has(key string) bool {
+	return d.decoded().has(key)
+}
+
+func (d *lazyCustomData) set(key string, value interface{}) {
+	d.decoded().set(key, value)
+}
+
+func (d *lazyCustomData) delete(key string) {
+	delete(d.decoded(), key)
+}
+
+// marshalled returns the JSON form of the data, reusing the raw JSON
+// as is if the data was never accessed.
+func (d *lazyCustomData) marshalled() (json.RawMessage, error) {
+	if d.data == nil {
+		return d.raw, nil
+	}
+	if len(d.data) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(d.data)
+}
+
+// stringInterner deduplicates strings that are repeated across many
+// tasks and changes, such as kinds, summaries and task IDs.
+type stringInterner map[string]string
+
+func (in stringInterner) intern(s string) string {
+	if s == "" {
+		return s
+	}
+	if shared, ok := in[s]; ok {
+		return shared
+	}
+	in[s] = s
+	return s
+}
+
+func (in stringInterner) internAll(strs []string) {
+	for i, s := range strs {
+		strs[i] = in.intern(s)
+	}
+}
+
 // State represents an evolving system state that persists across restarts.
 //
 // The State is concurrency-safe, and all reads and writes to it must be
@@ -207,12 +293,23 @@ func (s *State) UnmarshalJSON(data []byte) error {
 	s.lastTaskId = unmarshalled.LastTaskId
 	s.lastLaneId = unmarshalled.LastLaneId
 	s.lastNoticeId = unmarshalled.LastNoticeId
-	// backlink state again
-	for _, t := range s.tasks {
+	// backlink state again, sharing the strings that tend to repeat
+	in := make(stringInterner)
+	for id, t := range s.tasks {
 		t.state = s
+		t.id = in.intern(id)
+		t.kind = in.intern(t.kind)
+		t.summary = in.intern(t.summary)
+		t.change = in.intern(t.change)
+		in.internAll(t.waitTasks)
+		in.internAll(t.haltTasks)
 	}
-	for _, chg := range s.changes {
+	for id, chg := range s.changes {
 		chg.state = s
+		chg.id = in.intern(id)
+		chg.kind = in.intern(chg.kind)
+		chg.summary = in.intern(chg.summary)
+		in.internAll(chg.taskIDs)
 		chg.finishUnmarshal()
 	}
 	return nil
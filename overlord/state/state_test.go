@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime"
 	"testing"
 	"time"
 
@@ -619,6 +620,63 @@ func (ss *stateSuite) TestEmptyTaskAndChangeDataAndCheckpointReadAndSet(c *C) {
 	t1_2.Set("t", 1)
 }
 
+func (ss *stateSuite) TestTaskAndChangeDataPreservedWithoutAccess(c *C) {
+	b := new(fakeStateBackend)
+	st := state.New(b)
+	st.Lock()
+
+	t1 := st.NewTask("download", "...")
+	t1.Set("a", map[string]int{"x": 1})
+	t1ID := t1.ID()
+	chg := st.NewChange("install", "...")
+	chg.Set("b", []string{"y"})
+	chgID := chg.ID()
+	chg.AddTask(t1)
+
+	// implicit checkpoint
+	st.Unlock()
+
+	c.Assert(b.checkpoints, HasLen, 1)
+
+	b2 := new(fakeStateBackend)
+	st2, err := state.ReadState(b2, bytes.NewBuffer(b.checkpoints[0]))
+	c.Assert(err, IsNil)
+
+	// checkpoint again without looking at the data
+	st2.Lock()
+	st2.Task(t1ID).SetStatus(state.DoneStatus)
+	st2.Unlock()
+	c.Assert(b2.checkpoints, HasLen, 1)
+
+	st3, err := state.ReadState(nil, bytes.NewBuffer(b2.checkpoints[0]))
+	c.Assert(err, IsNil)
+	st3.Lock()
+	defer st3.Unlock()
+
+	t3 := st3.Task(t1ID)
+	var a map[string]int
+	c.Assert(t3.Get("a", &a), IsNil)
+	c.Check(a, DeepEquals, map[string]int{"x": 1})
+	c.Check(t3.Has("nope"), Equals, false)
+
+	chg3 := st3.Change(chgID)
+	var bv []string
+	c.Assert(chg3.Get("b", &bv), IsNil)
+	c.Check(bv, DeepEquals, []string{"y"})
+
+	// modifications after the data was decoded are kept
+	t3.Clear("a")
+	t3.Set("c", 2)
+	data, err := t3.MarshalJSON()
+	c.Assert(err, IsNil)
+	c.Check(string(data), testutil.Contains, `"data":{"c":2}`)
+}
+
+func (ss *stateSuite) TestReadStateTaskDataNotObject(c *C) {
+	_, err := state.ReadState(nil, bytes.NewBufferString(`{"tasks":{"1":{"id":"1","kind":"k","data":[1]}}}`))
+	c.Check(err, ErrorMatches, `.*cannot unmarshal custom data: expected a JSON object`)
+}
+
 func (ss *stateSuite) TestEnsureBefore(c *C) {
 	b := new(fakeStateBackend)
 	st := state.New(b)
@@ -1275,3 +1333,83 @@ func (ss *stateSuite) TestChangeSetStatusChangedHandler(c *C) {
 		},
 	})
 }
+
+func makeHistoricalState(numChanges int) []byte {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+	for i := 0; i < numChanges; i++ {
+		chg := st.NewChange("install-snap", fmt.Sprintf("Install %q snap", "some-snap"))
+		var prev *state.Task
+		for _, kind := range []string{"prerequisites", "download-snap", "validate-snap", "mount-snap", "copy-snap-data", "setup-profiles", "link-snap", "run-hook", "start-snap-services"} {
+			t := st.NewTask(kind, fmt.Sprintf("Run %s for %q snap", kind, "some-snap"))
+			t.Set("snap-setup", map[string]interface{}{
+				"channel":   "stable",
+				"side-info": map[string]interface{}{"name": "some-snap", "revision": "42"},
+			})
+			t.Set("snap-setup-task", "1")
+			if prev != nil {
+				t.WaitFor(prev)
+			}
+			t.SetStatus(state.DoneStatus)
+			chg.AddTask(t)
+			prev = t
+		}
+	}
+	data, err := st.MarshalJSON()
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func benchmarkReadState(b *testing.B, numChanges int) {
+	data := makeHistoricalState(numChanges)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := state.ReadState(nil, bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadState100Changes(b *testing.B)  { benchmarkReadState(b, 100) }
+func BenchmarkReadState1000Changes(b *testing.B) { benchmarkReadState(b, 1000) }
+
+func BenchmarkReadStateRetainedHeap(b *testing.B) {
+	data := makeHistoricalState(1000)
+	states := make([]*state.State, 0, b.N)
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		st, err := state.ReadState(nil, bytes.NewReader(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+		states = append(states, st)
+	}
+	b.StopTimer()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/float64(len(states)), "retained-B/op")
+	runtime.KeepAlive(states)
+}
+
+func BenchmarkCheckpointUntouchedState(b *testing.B) {
+	st, err := state.ReadState(nil, bytes.NewReader(makeHistoricalState(1000)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	st.Lock()
+	defer st.Unlock()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := st.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package statequery implements a small query language to inspect the
+// snapd state without having to look at the whole of it.
+//
+// A query is a sequence of steps separated by dots. Each step names the
+// field to descend into, or is "*" to descend into all the fields or
+// elements, and can be followed by filters in square brackets. Filters
+// apply to the elements of the selected collection (the values of an
+// object, or the items of a list) and have the form [path=value],
+// [path!=value], [path~value] (the value is contained in the field) or
+// [path] (the field exists), where path is itself a dot separated list
+// of field names. For example:
+//
+//	changes[kind=auto-refresh][status=Error].summary
+//
+// Field names and values can be quoted with double quotes if they
+// contain any of the characters used by the syntax.
+package statequery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+type filterOp string
+
+const (
+	opExists   filterOp = ""
+	opEqual    filterOp = "="
+	opNotEqual filterOp = "!="
+	opContains filterOp = "~"
+)
+
+type filter struct {
+	path  []string
+	op    filterOp
+	value string
+}
+
+type step struct {
+	name    string
+	filters []filter
+}
+
+// Query is a parsed state query.
+type Query struct {
+	steps []step
+}
+
+// specialChars are the characters that end unquoted names and values.
+const specialChars = `.[]=!~"`
+
+type parser struct {
+	q   string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("cannot parse query %q: %s (at position %d)", p.q, fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.q)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.q[p.pos]
+}
+
+// token reads a possibly quoted run of characters, stopping at any of the
+// characters in stop when unquoted.
+func (p *parser) token(stop string) (string, error) {
+	if p.peek() == '"' {
+		end := strings.IndexByte(p.q[p.pos+1:], '"')
+		if end < 0 {
+			return "", p.errorf("unterminated quote")
+		}
+		tok := p.q[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return tok, nil
+	}
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(stop, rune(p.peek())) {
+		p.pos++
+	}
+	return p.q[start:p.pos], nil
+}
+
+func (p *parser) name() (string, error) {
+	name, err := p.token(specialChars)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", p.errorf("expected a field name")
+	}
+	return name, nil
+}
+
+func (p *parser) filter() (filter, error) {
+	var f filter
+	// skip the opening bracket
+	p.pos++
+	for {
+		name, err := p.name()
+		if err != nil {
+			return f, err
+		}
+		f.path = append(f.path, name)
+		if p.peek() != '.' {
+			break
+		}
+		p.pos++
+	}
+	switch {
+	case strings.HasPrefix(p.q[p.pos:], string(opNotEqual)):
+		f.op = opNotEqual
+	case strings.HasPrefix(p.q[p.pos:], string(opEqual)):
+		f.op = opEqual
+	case strings.HasPrefix(p.q[p.pos:], string(opContains)):
+		f.op = opContains
+	}
+	p.pos += len(f.op)
+	if f.op != opExists {
+		value, err := p.token(`]"`)
+		if err != nil {
+			return f, err
+		}
+		f.value = value
+	}
+	if p.peek() != ']' {
+		return f, p.errorf("expected ']'")
+	}
+	p.pos++
+	return f, nil
+}
+
+// Parse parses the given query.
+func Parse(q string) (*Query, error) {
+	p := &parser{q: q}
+	query := &Query{}
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		s := step{name: name}
+		for p.peek() == '[' {
+			f, err := p.filter()
+			if err != nil {
+				return nil, err
+			}
+			s.filters = append(s.filters, f)
+		}
+		query.steps = append(query.steps, s)
+		if p.eof() {
+			break
+		}
+		if p.peek() != '.' {
+			return nil, p.errorf("expected '.' or '['")
+		}
+		p.pos++
+	}
+	return query, nil
+}
+
+// sortedKeys returns the keys of the object, sorting numeric keys, like the
+// IDs of changes and tasks, numerically.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ni, erri := strconv.Atoi(keys[i])
+		nj, errj := strconv.Atoi(keys[j])
+		if erri == nil && errj == nil {
+			return ni < nj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// elements returns the values of an object or the items of a list.
+func elements(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		elems := make([]interface{}, 0, len(v))
+		for _, k := range sortedKeys(v) {
+			elems = append(elems, v[k])
+		}
+		return elems
+	case []interface{}:
+		return v
+	}
+	return nil
+}
+
+func lookup(v interface{}, path []string) (interface{}, bool) {
+	for _, name := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		v, ok = m[name]
+		if !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// scalarString returns the textual form of scalar values.
+func scalarString(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case nil:
+		return "null", true
+	}
+	return "", false
+}
+
+func (f *filter) matches(v interface{}) bool {
+	field, ok := lookup(v, f.path)
+	if f.op == opExists {
+		return ok
+	}
+	if !ok {
+		// missing fields match only inequality
+		return f.op == opNotEqual
+	}
+	str, ok := scalarString(field)
+	switch f.op {
+	case opEqual:
+		return ok && str == f.value
+	case opNotEqual:
+		return !ok || str != f.value
+	case opContains:
+		return ok && strings.Contains(str, f.value)
+	}
+	return false
+}
+
+// Eval evaluates the query over a generic JSON document, as decoded by
+// encoding/json, and returns the matching values.
+func (q *Query) Eval(doc interface{}) []interface{} {
+	current := []interface{}{doc}
+	for _, s := range q.steps {
+		var selected []interface{}
+		for _, v := range current {
+			if s.name == "*" {
+				selected = append(selected, elements(v)...)
+				continue
+			}
+			if m, ok := v.(map[string]interface{}); ok {
+				if field, ok := m[s.name]; ok {
+					selected = append(selected, field)
+				}
+			}
+		}
+		if len(s.filters) > 0 {
+			var candidates []interface{}
+			for _, v := range selected {
+				candidates = append(candidates, elements(v)...)
+			}
+			selected = nil
+		candidate:
+			for _, v := range candidates {
+				for i := range s.filters {
+					if !s.filters[i].matches(v) {
+						continue candidate
+					}
+				}
+				selected = append(selected, v)
+			}
+		}
+		current = selected
+	}
+	if current == nil {
+		current = []interface{}{}
+	}
+	return current
+}
+
+// Document returns the state as a generic JSON document to query, with the
+// status of changes and tasks as text, as they are shown by snap changes and
+// snap tasks. It must be called with the state lock held.
+func Document(st *state.State) (map[string]interface{}, error) {
+	buf, err := json.Marshal(st)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	if changes, ok := doc["changes"].(map[string]interface{}); ok {
+		for _, chg := range st.Changes() {
+			if m, ok := changes[chg.ID()].(map[string]interface{}); ok {
+				m["status"] = chg.Status().String()
+			}
+		}
+	}
+	if tasks, ok := doc["tasks"].(map[string]interface{}); ok {
+		for _, t := range st.Tasks() {
+			if m, ok := tasks[t.ID()].(map[string]interface{}); ok {
+				m["status"] = t.Status().String()
+			}
+		}
+	}
+	return doc, nil
+}
+
+// Run evaluates the query over the state. It must be called with the state
+// lock held.
+func Run(st *state.State, query string) ([]interface{}, error) {
+	q, err := Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := Document(st)
+	if err != nil {
+		return nil, err
+	}
+	return q.Eval(doc), nil
+}
@@ -0,0 +1,150 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package statequery_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/overlord/state/statequery"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type statequerySuite struct{}
+
+var _ = Suite(&statequerySuite{})
+
+const testDoc = `{
+	"data": {
+		"snaps": {
+			"core": {"type": "os", "active": true, "sequence": [{"revision": "1"}]},
+			"foo": {"type": "app", "active": false},
+			"bar": {"type": "app", "active": true, "tracking-channel": "latest/edge"}
+		},
+		"conns": {
+			"foo:network core:network": {"interface": "network", "auto": true}
+		}
+	},
+	"changes": {
+		"2": {"id": "2", "kind": "auto-refresh", "status": "Error", "summary": "Auto-refresh snaps \"foo\""},
+		"10": {"id": "10", "kind": "auto-refresh", "status": "Done", "summary": "Auto-refresh snaps \"bar\""},
+		"9": {"id": "9", "kind": "install-snap", "status": "Error", "summary": "Install \"baz\" snap"}
+	},
+	"last-change-id": 10
+}`
+
+func (s *statequerySuite) eval(c *C, query string) []interface{} {
+	var doc interface{}
+	c.Assert(json.Unmarshal([]byte(testDoc), &doc), IsNil)
+	q, err := statequery.Parse(query)
+	c.Assert(err, IsNil, Commentf("%s", query))
+	return q.Eval(doc)
+}
+
+func (s *statequerySuite) TestEval(c *C) {
+	for _, t := range []struct {
+		query    string
+		expected []interface{}
+	}{
+		{"last-change-id", []interface{}{10.0}},
+		{"missing", []interface{}{}},
+		{"data.snaps.core.type", []interface{}{"os"}},
+		{"data.snaps.*.type", []interface{}{"app", "os", "app"}},
+		{"changes.*.id", []interface{}{"2", "9", "10"}},
+		{"changes[kind=auto-refresh].id", []interface{}{"2", "10"}},
+		{"changes[kind=auto-refresh][status=Error].summary", []interface{}{`Auto-refresh snaps "foo"`}},
+		{"changes[status!=Error].id", []interface{}{"10"}},
+		{"changes[summary~baz].id", []interface{}{"9"}},
+		{`data.snaps[tracking-channel="latest/edge"].type`, []interface{}{"app"}},
+		{"data.snaps[active=true].type", []interface{}{"app", "os"}},
+		{"data.snaps[tracking-channel].tracking-channel", []interface{}{"latest/edge"}},
+		{"data.snaps[tracking-channel!=latest/edge].type", []interface{}{"os", "app"}},
+		{"data.snaps[sequence].sequence[revision=1].revision", []interface{}{"1"}},
+		{`data.conns."foo:network core:network".interface`, []interface{}{"network"}},
+		{"data.conns[auto=true].interface", []interface{}{"network"}},
+		{"last-change-id[foo]", []interface{}{}},
+	} {
+		c.Check(s.eval(c, t.query), DeepEquals, t.expected, Commentf("%s", t.query))
+	}
+}
+
+func (s *statequerySuite) TestEvalNestedFilterPath(c *C) {
+	res := s.eval(c, "data[snaps.core.type=os].core.type")
+	c.Check(res, DeepEquals, []interface{}{})
+
+	res = s.eval(c, "*[core.type=os].foo.type")
+	c.Check(res, DeepEquals, []interface{}{"app"})
+}
+
+func (s *statequerySuite) TestParseErrors(c *C) {
+	for _, t := range []struct {
+		query, err string
+	}{
+		{"", `cannot parse query "": expected a field name \(at position 0\)`},
+		{"changes.", `cannot parse query "changes.": expected a field name \(at position 8\)`},
+		{"changes[", `cannot parse query "changes\[": expected a field name \(at position 8\)`},
+		{"changes[kind=foo", `cannot parse query "changes\[kind=foo": expected '\]' \(at position 16\)`},
+		{"changes[kind!foo]", `cannot parse query .*: expected '\]' \(at position 12\)`},
+		{`changes."foo`, `cannot parse query .*: unterminated quote \(at position 8\)`},
+		{"changes]", `cannot parse query "changes\]": expected '.' or '\[' \(at position 7\)`},
+	} {
+		_, err := statequery.Parse(t.query)
+		c.Check(err, ErrorMatches, t.err, Commentf("%s", t.query))
+	}
+}
+
+func (s *statequerySuite) TestRun(c *C) {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	st.Set("seeded", true)
+	chg1 := st.NewChange("install-snap", "Install foo")
+	t1 := st.NewTask("download-snap", "Download foo")
+	chg1.AddTask(t1)
+	t1.SetStatus(state.ErrorStatus)
+	chg2 := st.NewChange("auto-refresh", "Refresh bar")
+	t2 := st.NewTask("download-snap", "Download bar")
+	chg2.AddTask(t2)
+	t2.SetStatus(state.DoneStatus)
+
+	res, err := statequery.Run(st, "data.seeded")
+	c.Assert(err, IsNil)
+	c.Check(res, DeepEquals, []interface{}{true})
+
+	res, err = statequery.Run(st, "changes[status=Error].summary")
+	c.Assert(err, IsNil)
+	c.Check(res, DeepEquals, []interface{}{"Install foo"})
+
+	res, err = statequery.Run(st, "tasks[status=Done][kind=download-snap].change")
+	c.Assert(err, IsNil)
+	c.Check(res, DeepEquals, []interface{}{chg2.ID()})
+
+	res, err = statequery.Run(st, "last-task-id")
+	c.Assert(err, IsNil)
+	c.Check(res, DeepEquals, []interface{}{json.Number("2")})
+
+	_, err = statequery.Run(st, "changes[")
+	c.Check(err, ErrorMatches, "cannot parse query .*")
+}
@@ -28,9 +28,32 @@ import (
 )
 
 type progress struct {
-	Label string `json:"label"`
-	Done  int    `json:"done"`
-	Total int    `json:"total"`
+	Label string       `json:"label"`
+	Done  int          `json:"done"`
+	Total int          `json:"total"`
+	Phase string       `json:"phase,omitempty"`
+	Unit  ProgressUnit `json:"unit,omitempty"`
+	ETA   *time.Time   `json:"eta,omitempty"`
+}
+
+// ProgressUnit is the unit in which the progress of a task is measured.
+type ProgressUnit string
+
+const (
+	// ProgressSteps measures progress in discrete steps.
+	ProgressSteps ProgressUnit = "steps"
+	// ProgressBytes measures progress in bytes, e.g. downloaded ones.
+	ProgressBytes ProgressUnit = "bytes"
+)
+
+// ProgressDetails qualifies the progress of a task.
+type ProgressDetails struct {
+	// Phase names what the task is currently doing, e.g. "download".
+	Phase string
+	// Unit is the unit of the done and total progress values.
+	Unit ProgressUnit
+	// ETA is the estimated time of completion, if known.
+	ETA time.Time
 }
 
 // Task represents an individual operation to be performed
@@ -48,7 +71,7 @@ type Task struct {
 	waitedStatus Status
 	clean        bool
 	progress     *progress
-	data         customData
+	data         lazyCustomData
 	waitTasks    []string
 	haltTasks    []string
 	lanes        []int
@@ -73,26 +96,26 @@ func newTask(state *State, id, kind, summary string) *Task {
 		id:      id,
 		kind:    kind,
 		summary: summary,
-		data:    make(customData),
+		data:    lazyCustomData{data: make(customData)},
 
 		spawnTime: timeNow(),
 	}
 }
 
 type marshalledTask struct {
-	ID           string                      `json:"id"`
-	Kind         string                      `json:"kind"`
-	Summary      string                      `json:"summary"`
-	Status       Status                      `json:"status"`
-	WaitedStatus Status                      `json:"waited-status"`
-	Clean        bool                        `json:"clean,omitempty"`
-	Progress     *progress                   `json:"progress,omitempty"`
-	Data         map[string]*json.RawMessage `json:"data,omitempty"`
-	WaitTasks    []string                    `json:"wait-tasks,omitempty"`
-	HaltTasks    []string                    `json:"halt-tasks,omitempty"`
-	Lanes        []int                       `json:"lanes,omitempty"`
-	Log          []string                    `json:"log,omitempty"`
-	Change       string                      `json:"change"`
+	ID           string          `json:"id"`
+	Kind         string          `json:"kind"`
+	Summary      string          `json:"summary"`
+	Status       Status          `json:"status"`
+	WaitedStatus Status          `json:"waited-status"`
+	Clean        bool            `json:"clean,omitempty"`
+	Progress     *progress       `json:"progress,omitempty"`
+	Data         json.RawMessage `json:"data,omitempty"`
+	WaitTasks    []string        `json:"wait-tasks,omitempty"`
+	HaltTasks    []string        `json:"halt-tasks,omitempty"`
+	Lanes        []int           `json:"lanes,omitempty"`
+	Log          []string        `json:"log,omitempty"`
+	Change       string          `json:"change"`
 
 	SpawnTime time.Time  `json:"spawn-time"`
 	ReadyTime *time.Time `json:"ready-time,omitempty"`
@@ -114,6 +137,10 @@ func (t *Task) MarshalJSON() ([]byte, error) {
 	if !t.atTime.IsZero() {
 		atTime = &t.atTime
 	}
+	data, err := t.data.marshalled()
+	if err != nil {
+		return nil, err
+	}
 	return json.Marshal(marshalledTask{
 		ID:           t.id,
 		Kind:         t.kind,
@@ -122,7 +149,7 @@ func (t *Task) MarshalJSON() ([]byte, error) {
 		WaitedStatus: t.waitedStatus,
 		Clean:        t.clean,
 		Progress:     t.progress,
-		Data:         t.data,
+		Data:         data,
 		WaitTasks:    t.waitTasks,
 		HaltTasks:    t.haltTasks,
 		Lanes:        t.lanes,
@@ -162,11 +189,9 @@ func (t *Task) UnmarshalJSON(data []byte) error {
 	}
 	t.clean = unmarshalled.Clean
 	t.progress = unmarshalled.Progress
-	custData := unmarshalled.Data
-	if custData == nil {
-		custData = make(customData)
+	if err := t.data.setRaw(unmarshalled.Data); err != nil {
+		return err
 	}
-	t.data = custData
 	t.waitTasks = unmarshalled.WaitTasks
 	t.haltTasks = unmarshalled.HaltTasks
 	t.lanes = unmarshalled.Lanes
@@ -351,8 +376,31 @@ func (t *Task) Progress() (label string, done, total int) {
 	return t.progress.Label, t.progress.Done, t.progress.Total
 }
 
+// ProgressDetails returns the phase, unit and estimated time of completion
+// of the current progress of the task, as set by SetProgressWithDetails.
+func (t *Task) ProgressDetails() ProgressDetails {
+	t.state.reading()
+	if t.progress == nil {
+		return ProgressDetails{}
+	}
+	details := ProgressDetails{
+		Phase: t.progress.Phase,
+		Unit:  t.progress.Unit,
+	}
+	if t.progress.ETA != nil {
+		details.ETA = *t.progress.ETA
+	}
+	return details
+}
+
 // SetProgress sets the task progress to cur out of total steps.
 func (t *Task) SetProgress(label string, done, total int) {
+	t.SetProgressWithDetails(label, done, total, ProgressDetails{})
+}
+
+// SetProgressWithDetails sets the task progress to cur out of total,
+// qualified by the given details.
+func (t *Task) SetProgressWithDetails(label string, done, total int, details ProgressDetails) {
 	// Only mark state for checkpointing if progress is final.
 	if total > 0 && done == total {
 		t.state.writing()
@@ -363,7 +411,17 @@ func (t *Task) SetProgress(label string, done, total int) {
 		// Doing math wrong is easy. Be conservative.
 		t.progress = nil
 	} else {
-		t.progress = &progress{Label: label, Done: done, Total: total}
+		t.progress = &progress{
+			Label: label,
+			Done:  done,
+			Total: total,
+			Phase: details.Phase,
+			Unit:  details.Unit,
+		}
+		if !details.ETA.IsZero() {
+			eta := details.ETA
+			t.progress.ETA = &eta
+		}
 	}
 }
 
@@ -483,7 +541,7 @@ func (t *Task) Has(key string) bool {
 // Clear disassociates the value from key.
 func (t *Task) Clear(key string) {
 	t.state.writing()
-	delete(t.data, key)
+	t.data.delete(key)
 }
 
 func addOnce(set []string, s string) []string {
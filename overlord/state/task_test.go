@@ -265,6 +265,41 @@ func (ts *taskSuite) TestProgressAndSetProgress(c *C) {
 	c.Check(tot, Equals, 42)
 }
 
+func (ts *taskSuite) TestSetProgressWithDetails(c *C) {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	t := st.NewTask("download", "1...")
+	c.Check(t.ProgressDetails(), Equals, state.ProgressDetails{})
+
+	eta := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	t.SetProgressWithDetails("snap", 2, 99, state.ProgressDetails{
+		Phase: "download",
+		Unit:  state.ProgressBytes,
+		ETA:   eta,
+	})
+	label, cur, tot := t.Progress()
+	c.Check(label, Equals, "snap")
+	c.Check(cur, Equals, 2)
+	c.Check(tot, Equals, 99)
+	c.Check(t.ProgressDetails(), DeepEquals, state.ProgressDetails{
+		Phase: "download",
+		Unit:  state.ProgressBytes,
+		ETA:   eta,
+	})
+	c.Check(jsonStr(t), testutil.Contains, `"progress":{"label":"snap","done":2,"total":99,"phase":"download","unit":"bytes","eta":"2026-01-02T03:04:05Z"}`)
+
+	// the details are reset with the progress
+	t.SetProgress("snap", 3, 99)
+	c.Check(t.ProgressDetails(), Equals, state.ProgressDetails{})
+	c.Check(jsonStr(t), testutil.Contains, `"progress":{"label":"snap","done":3,"total":99}`)
+
+	t.SetProgressWithDetails("", 0, 0, state.ProgressDetails{Phase: "download"})
+	c.Check(t.ProgressDetails(), Equals, state.ProgressDetails{})
+	c.Check(jsonStr(t), Not(testutil.Contains), "progress")
+}
+
 func (ts *taskSuite) TestProgressDefaults(c *C) {
 	st := state.New(nil)
 	st.Lock()
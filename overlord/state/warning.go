@@ -39,8 +39,60 @@ var (
 	errNoWarningFirstAdded  = errors.New("warning has no first-added timestamp")
 	errNoWarningExpireAfter = errors.New("warning has no expire-after duration")
 	errNoWarningRepeatAfter = errors.New("warning has no repeat-after duration")
+	errBadWarningSeverity   = errors.New("warning has an invalid severity")
 )
 
+// WarningSeverity is how important a warning is.
+type WarningSeverity string
+
+const (
+	// WarningSeverityInfo is for warnings that are only informational.
+	WarningSeverityInfo WarningSeverity = "info"
+	// WarningSeverityWarning is the severity of regular warnings.
+	WarningSeverityWarning WarningSeverity = "warning"
+	// WarningSeverityCritical is for warnings about problems that need the
+	// attention of the user, for example because they affect the security
+	// of the system.
+	WarningSeverityCritical WarningSeverity = "critical"
+)
+
+func (sev WarningSeverity) level() int {
+	switch sev {
+	case WarningSeverityInfo:
+		return 1
+	case WarningSeverityWarning:
+		return 2
+	case WarningSeverityCritical:
+		return 3
+	}
+	return 0
+}
+
+// Valid reports whether the severity is a known one.
+func (sev WarningSeverity) Valid() bool {
+	return sev.level() > 0
+}
+
+// AtLeast reports whether the severity is the same as or more severe than
+// the given one.
+func (sev WarningSeverity) AtLeast(other WarningSeverity) bool {
+	return sev.level() >= other.level()
+}
+
+// warningPolicy returns how long after being shown a warning of the given
+// severity is repeated, and how long after last being added it expires.
+// Critical warnings are repeated more often and kept for longer so that they
+// do not get lost among less important ones.
+func warningPolicy(sev WarningSeverity) (repeatAfter, expireAfter time.Duration) {
+	switch sev {
+	case WarningSeverityInfo:
+		return DefaultRepeatAfter, DefaultExpireAfter / 4
+	case WarningSeverityCritical:
+		return DefaultRepeatAfter / 6, DefaultExpireAfter * 3
+	}
+	return DefaultRepeatAfter, DefaultExpireAfter
+}
+
 type jsonWarning struct {
 	Message     string     `json:"message"`
 	FirstAdded  time.Time  `json:"first-added"`
@@ -48,6 +100,9 @@ type jsonWarning struct {
 	LastShown   *time.Time `json:"last-shown,omitempty"`
 	ExpireAfter string     `json:"expire-after,omitempty"`
 	RepeatAfter string     `json:"repeat-after,omitempty"`
+	Severity    string     `json:"severity,omitempty"`
+
+	LastShownBy map[uint32]time.Time `json:"last-shown-by,omitempty"`
 }
 
 type Warning struct {
@@ -63,12 +118,22 @@ type Warning struct {
 	expireAfter time.Duration
 	// how much time since one of these was last shown should we repeat it
 	repeatAfter time.Duration
+	// how important the warning is
+	severity WarningSeverity
+	// the last time one of these was shown to each of the users that
+	// acknowledged it
+	lastShownBy map[uint32]time.Time
 }
 
 func (w *Warning) String() string {
 	return w.message
 }
 
+// Severity returns how important the warning is.
+func (w *Warning) Severity() WarningSeverity {
+	return w.severity
+}
+
 func (w *Warning) MarshalJSON() ([]byte, error) {
 	jw := jsonWarning{
 		Message:     w.message,
@@ -76,6 +141,8 @@ func (w *Warning) MarshalJSON() ([]byte, error) {
 		LastAdded:   w.lastAdded,
 		ExpireAfter: w.expireAfter.String(),
 		RepeatAfter: w.repeatAfter.String(),
+		Severity:    string(w.severity),
+		LastShownBy: w.lastShownBy,
 	}
 	if !w.lastShown.IsZero() {
 		jw.LastShown = &w.lastShown
@@ -108,6 +175,12 @@ func (w *Warning) UnmarshalJSON(data []byte) error {
 			return err
 		}
 	}
+	// warnings recorded before severities were introduced
+	w.severity = WarningSeverityWarning
+	if jw.Severity != "" {
+		w.severity = WarningSeverity(jw.Severity)
+	}
+	w.lastShownBy = jw.LastShownBy
 
 	return w.validate()
 }
@@ -128,6 +201,9 @@ func (w *Warning) validate() (e error) {
 	if w.repeatAfter == 0 {
 		return errNoWarningRepeatAfter
 	}
+	if !w.severity.Valid() {
+		return errBadWarningSeverity
+	}
 	return nil
 }
 
@@ -136,12 +212,25 @@ func (w *Warning) ExpiredBefore(now time.Time) bool {
 }
 
 func (w *Warning) ShowAfter(t time.Time) bool {
-	if w.lastShown.IsZero() {
+	return w.showAfter(nil, t)
+}
+
+// showAfter is like ShowAfter, but also takes into account when the warning
+// was last shown to the given user, if any.
+func (w *Warning) showAfter(userID *uint32, t time.Time) bool {
+	lastShown := w.lastShown
+	if userID != nil {
+		if shown := w.lastShownBy[*userID]; shown.After(lastShown) {
+			lastShown = shown
+		}
+	}
+
+	if lastShown.IsZero() {
 		// warning was never shown before; was it added after the cutoff?
 		return !w.firstAdded.After(t)
 	}
 
-	return w.lastShown.Add(w.repeatAfter).Before(t)
+	return lastShown.Add(w.repeatAfter).Before(t)
 }
 
 // flattenWarning loops over the warnings map, and returns all
@@ -178,16 +267,26 @@ func (s *State) unflattenWarnings(flat []*Warning) {
 // current time), otherwise the existing one will have its lastAdded
 // updated.
 func (s *State) Warnf(template string, args ...interface{}) {
+	s.WarnfWithSeverity(WarningSeverityWarning, template, args...)
+}
+
+// WarnfWithSeverity records a warning of the given severity, like Warnf.
+// How long the warning is kept, and how often it's repeated, depends on
+// its severity. If the warning already exists with a lower severity it's
+// upgraded to the given one.
+func (s *State) WarnfWithSeverity(severity WarningSeverity, template string, args ...interface{}) {
 	var message string
 	if len(args) > 0 {
 		message = fmt.Sprintf(template, args...)
 	} else {
 		message = template
 	}
+	repeatAfter, expireAfter := warningPolicy(severity)
 	s.addWarning(Warning{
 		message:     message,
-		expireAfter: DefaultExpireAfter,
-		repeatAfter: DefaultRepeatAfter,
+		expireAfter: expireAfter,
+		repeatAfter: repeatAfter,
+		severity:    severity,
 	}, time.Now().UTC())
 }
 
@@ -202,6 +301,10 @@ func (s *State) addWarning(w Warning, t time.Time) {
 			return
 		}
 		s.warnings[w.message] = &w
+	} else if existing := s.warnings[w.message]; w.severity.level() > existing.severity.level() {
+		existing.severity = w.severity
+		existing.expireAfter = w.expireAfter
+		existing.repeatAfter = w.repeatAfter
 	}
 	s.warnings[w.message].lastAdded = t
 }
@@ -225,15 +328,30 @@ func (s *State) AllWarnings() []*Warning {
 
 // OkayWarnings marks warnings that were showable at the given time as shown.
 func (s *State) OkayWarnings(t time.Time) int {
+	return s.OkayUserWarnings(nil, t)
+}
+
+// OkayUserWarnings marks warnings that were showable to the given user at the
+// given time as shown to that user. A nil userID marks them as shown to
+// everybody.
+func (s *State) OkayUserWarnings(userID *uint32, t time.Time) int {
 	t = t.UTC()
 	s.writing()
 
 	n := 0
 	for _, w := range s.warnings {
-		if w.ShowAfter(t) {
+		if !w.showAfter(userID, t) {
+			continue
+		}
+		if userID == nil {
 			w.lastShown = t
-			n++
+		} else {
+			if w.lastShownBy == nil {
+				w.lastShownBy = make(map[uint32]time.Time)
+			}
+			w.lastShownBy[*userID] = t
 		}
+		n++
 	}
 
 	return n
@@ -245,12 +363,18 @@ func (s *State) OkayWarnings(t time.Time) int {
 // Warnings to show to the user are those that have not been shown before,
 // or that have been shown earlier than repeatAfter ago.
 func (s *State) PendingWarnings() ([]*Warning, time.Time) {
+	return s.PendingUserWarnings(nil)
+}
+
+// PendingUserWarnings is like PendingWarnings, but also leaves out the
+// warnings the given user acknowledged and that are not due to be repeated.
+func (s *State) PendingUserWarnings(userID *uint32) ([]*Warning, time.Time) {
 	s.reading()
 	now := time.Now().UTC()
 
 	var toShow []*Warning
 	for _, w := range s.warnings {
-		if !w.ShowAfter(now) {
+		if !w.showAfter(userID, now) {
 			continue
 		}
 		toShow = append(toShow, w)
@@ -265,13 +389,19 @@ func (s *State) PendingWarnings() ([]*Warning, time.Time) {
 // warning (useful for silencing the warning alerts, and OKing the
 // returned warnings).
 func (s *State) WarningsSummary() (int, time.Time) {
+	return s.UserWarningsSummary(nil)
+}
+
+// UserWarningsSummary is like WarningsSummary, but for the warnings pending
+// for the given user.
+func (s *State) UserWarningsSummary(userID *uint32) (int, time.Time) {
 	s.reading()
 	now := time.Now().UTC()
 	var last time.Time
 
 	var n int
 	for _, w := range s.warnings {
-		if w.ShowAfter(now) {
+		if w.showAfter(userID, now) {
 			n++
 			if w.lastAdded.After(last) {
 				last = w.lastAdded
@@ -288,5 +418,6 @@ func (s *State) UnshowAllWarnings() {
 	s.writing()
 	for _, w := range s.warnings {
 		w.lastShown = time.Time{}
+		w.lastShownBy = nil
 	}
 }
@@ -40,7 +40,7 @@ func (stateSuite) testMarshalWarning(shown bool, c *check.C) {
 	st.Warnf("hello")
 	now := time.Now()
 
-	expectedNumKeys := 5
+	expectedNumKeys := 6
 	if shown {
 		expectedNumKeys++ // last-shown
 		st.OkayWarnings(now)
@@ -59,6 +59,7 @@ func (stateSuite) testMarshalWarning(shown bool, c *check.C) {
 	c.Check(v[0]["message"], check.DeepEquals, "hello")
 	c.Check(v[0]["expire-after"], check.Equals, state.DefaultExpireAfter.String())
 	c.Check(v[0]["repeat-after"], check.Equals, state.DefaultRepeatAfter.String())
+	c.Check(v[0]["severity"], check.Equals, "warning")
 	c.Check(v[0]["first-added"], check.Equals, v[0]["last-added"])
 	t, err := time.Parse(time.RFC3339, v[0]["first-added"])
 	c.Assert(err, check.IsNil)
@@ -106,6 +107,8 @@ func (stateSuite) TestUnmarshalErrors(c *check.C) {
 		{`{"message": "x",                                        "expire-after": "1h", "repeat-after": "1h"}`, state.ErrNoWarningFirstAdded},
 		{`{"message": "x", "first-added": "2006-01-02T15:04:05Z",                       "repeat-after": "1h"}`, state.ErrNoWarningExpireAfter},
 		{`{"message": "x", "first-added": "2006-01-02T15:04:05Z", "expire-after": "1h"                      }`, state.ErrNoWarningRepeatAfter},
+		// bad severity
+		{`{"message": "x", "first-added": "2006-01-02T15:04:05Z", "expire-after": "1h", "repeat-after": "1h", "severity": "meh"}`, state.ErrBadWarningSeverity},
 	} {
 		var w state.Warning
 		c.Check(json.Unmarshal([]byte(t.b), &w), check.Equals, t.e)
@@ -265,3 +268,116 @@ func (stateSuite) TestShowAndOkayWithRepeats(c *check.C) {
 	c.Check(ws, check.HasLen, 1)
 	c.Check(fmt.Sprintf("%q", ws), check.Equals, `["hello"]`)
 }
+
+func (stateSuite) TestUnmarshalWarningWithoutSeverity(c *check.C) {
+	var w state.Warning
+	err := json.Unmarshal([]byte(`{"message": "x", "first-added": "2006-01-02T15:04:05Z", "expire-after": "1h", "repeat-after": "1h"}`), &w)
+	c.Assert(err, check.IsNil)
+	c.Check(w.Severity(), check.Equals, state.WarningSeverityWarning)
+}
+
+func (stateSuite) TestWarningSeverity(c *check.C) {
+	c.Check(state.WarningSeverityInfo.Valid(), check.Equals, true)
+	c.Check(state.WarningSeverityWarning.Valid(), check.Equals, true)
+	c.Check(state.WarningSeverityCritical.Valid(), check.Equals, true)
+	c.Check(state.WarningSeverity("").Valid(), check.Equals, false)
+	c.Check(state.WarningSeverity("meh").Valid(), check.Equals, false)
+
+	c.Check(state.WarningSeverityCritical.AtLeast(state.WarningSeverityWarning), check.Equals, true)
+	c.Check(state.WarningSeverityWarning.AtLeast(state.WarningSeverityWarning), check.Equals, true)
+	c.Check(state.WarningSeverityInfo.AtLeast(state.WarningSeverityWarning), check.Equals, false)
+}
+
+func (stateSuite) TestWarnfWithSeverityPolicies(c *check.C) {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	st.WarnfWithSeverity(state.WarningSeverityInfo, "just so you know")
+	st.Warnf("careful")
+	st.WarnfWithSeverity(state.WarningSeverityCritical, "the sky is %s", "falling")
+
+	ws := st.AllWarnings()
+	c.Assert(ws, check.HasLen, 3)
+	buf, err := json.Marshal(ws)
+	c.Assert(err, check.IsNil)
+	var v []map[string]string
+	c.Assert(json.Unmarshal(buf, &v), check.IsNil)
+
+	c.Check(v[0]["message"], check.Equals, "just so you know")
+	c.Check(v[0]["severity"], check.Equals, "info")
+	c.Check(v[0]["repeat-after"], check.Equals, state.DefaultRepeatAfter.String())
+	c.Check(v[0]["expire-after"], check.Equals, (state.DefaultExpireAfter / 4).String())
+
+	c.Check(v[1]["message"], check.Equals, "careful")
+	c.Check(v[1]["severity"], check.Equals, "warning")
+	c.Check(v[1]["repeat-after"], check.Equals, state.DefaultRepeatAfter.String())
+	c.Check(v[1]["expire-after"], check.Equals, state.DefaultExpireAfter.String())
+
+	c.Check(v[2]["message"], check.Equals, "the sky is falling")
+	c.Check(v[2]["severity"], check.Equals, "critical")
+	c.Check(v[2]["repeat-after"], check.Equals, (state.DefaultRepeatAfter / 6).String())
+	c.Check(v[2]["expire-after"], check.Equals, (state.DefaultExpireAfter * 3).String())
+}
+
+func (stateSuite) TestWarnfWithSeverityUpgrades(c *check.C) {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	st.WarnfWithSeverity(state.WarningSeverityInfo, "hello")
+	st.WarnfWithSeverity(state.WarningSeverityCritical, "hello")
+	// lower severities do not downgrade the warning
+	st.Warnf("hello")
+
+	ws := st.AllWarnings()
+	c.Assert(ws, check.HasLen, 1)
+	c.Check(ws[0].Severity(), check.Equals, state.WarningSeverityCritical)
+}
+
+func (stateSuite) TestShowAndOkayPerUser(c *check.C) {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	alice := uint32(1000)
+	bob := uint32(1001)
+
+	st.Warnf("number one")
+	ws, t1 := st.PendingUserWarnings(&alice)
+	c.Assert(ws, check.HasLen, 1)
+
+	n := st.OkayUserWarnings(&alice, t1)
+	c.Check(n, check.Equals, 1)
+
+	ws, _ = st.PendingUserWarnings(&alice)
+	c.Check(ws, check.HasLen, 0)
+	n, _ = st.UserWarningsSummary(&alice)
+	c.Check(n, check.Equals, 0)
+
+	// other users, and the global view, still see the warning
+	ws, _ = st.PendingUserWarnings(&bob)
+	c.Check(ws, check.HasLen, 1)
+	n, _ = st.UserWarningsSummary(&bob)
+	c.Check(n, check.Equals, 1)
+	ws, _ = st.PendingWarnings()
+	c.Check(ws, check.HasLen, 1)
+
+	// okaying for everybody silences it for all users
+	_, t2 := st.PendingWarnings()
+	n = st.OkayWarnings(t2)
+	c.Check(n, check.Equals, 1)
+	ws, _ = st.PendingUserWarnings(&bob)
+	c.Check(ws, check.HasLen, 0)
+
+	// per-user acknowledgements survive a round trip
+	buf, err := json.Marshal(st.AllWarnings())
+	c.Assert(err, check.IsNil)
+	var ws2 []*state.Warning
+	c.Assert(json.Unmarshal(buf, &ws2), check.IsNil)
+	c.Check(ws2, check.DeepEquals, st.AllWarnings())
+
+	st.UnshowAllWarnings()
+	ws, _ = st.PendingUserWarnings(&alice)
+	c.Check(ws, check.HasLen, 1)
+}
@@ -21,6 +21,8 @@ package overlord
 
 import (
 	"fmt"
+	"path"
+	"reflect"
 	"sync"
 
 	"github.com/snapcore/snapd/logger"
@@ -66,12 +68,50 @@ type StateStopper interface {
 // cope with Ensure calls in any order, coordinating among themselves
 // solely via the state.
 type StateEngine struct {
-	state     *state.State
-	stopped   bool
-	startedUp bool
+	state             *state.State
+	stopped           bool
+	startedUp         bool
+	criticalStartedUp bool
 	// managers in use
 	mgrLock  sync.Mutex
 	managers []StateManager
+
+	// startup status of the managers implementing StateStarterUp
+	statusLock sync.Mutex
+	startUps   []*managerStartUp
+}
+
+// Startup status of a manager.
+const (
+	StartUpPending  = "pending"
+	StartUpStarting = "starting"
+	StartUpDone     = "done"
+	StartUpError    = "error"
+)
+
+// StartUpStatus describes the status of the initialization of a manager.
+type StartUpStatus struct {
+	// Manager is the name of the manager.
+	Manager string
+	// Lazy is set when the manager is initialized only after the
+	// critical managers, see StateEngine.StartUpCritical.
+	Lazy   bool
+	Status string
+	Err    error
+}
+
+type managerStartUp struct {
+	mgr    StateManager
+	status StartUpStatus
+}
+
+// managerName returns the name of the package implementing the manager.
+func managerName(m StateManager) string {
+	t := reflect.TypeOf(m)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return path.Base(t.PkgPath())
 }
 
 // NewStateEngine returns a new state engine.
@@ -94,7 +134,26 @@ func (e *startupError) Error() string {
 	return fmt.Sprintf("state startup errors: %v", e.errs)
 }
 
-// StartUp asks all managers to perform any expensive initialization. It is a noop after the first invocation.
+// StartUpCritical asks the managers which are not set to start up lazily
+// to perform any expensive initialization. It is a noop after the first
+// invocation.
+func (se *StateEngine) StartUpCritical() error {
+	se.mgrLock.Lock()
+	defer se.mgrLock.Unlock()
+	return se.startUpCritical()
+}
+
+func (se *StateEngine) startUpCritical() error {
+	if se.criticalStartedUp {
+		return nil
+	}
+	se.criticalStartedUp = true
+	return se.startUpManagers(false)
+}
+
+// StartUp asks all managers to perform any expensive initialization, the
+// critical ones first, see StartUpCritical. It is a noop after the first
+// invocation.
 func (se *StateEngine) StartUp() error {
 	se.mgrLock.Lock()
 	defer se.mgrLock.Unlock()
@@ -102,14 +161,39 @@ func (se *StateEngine) StartUp() error {
 		return nil
 	}
 	se.startedUp = true
+	if err := se.startUpCritical(); err != nil {
+		return err
+	}
+	return se.startUpManagers(true)
+}
+
+func (se *StateEngine) setStartUpStatus(su *managerStartUp, status string, err error) {
+	se.statusLock.Lock()
+	defer se.statusLock.Unlock()
+	su.status.Status = status
+	su.status.Err = err
+}
+
+func (se *StateEngine) startUpManagers(lazy bool) error {
+	se.statusLock.Lock()
+	var startUps []*managerStartUp
+	for _, su := range se.startUps {
+		if su.status.Lazy == lazy {
+			startUps = append(startUps, su)
+		}
+	}
+	se.statusLock.Unlock()
+
 	var errs []error
-	for _, m := range se.managers {
-		if starterUp, ok := m.(StateStarterUp); ok {
-			err := starterUp.StartUp()
-			if err != nil {
-				errs = append(errs, err)
-			}
+	for _, su := range startUps {
+		se.setStartUpStatus(su, StartUpStarting, nil)
+		err := su.mgr.(StateStarterUp).StartUp()
+		if err != nil {
+			se.setStartUpStatus(su, StartUpError, err)
+			errs = append(errs, err)
+			continue
 		}
+		se.setStartUpStatus(su, StartUpDone, nil)
 	}
 	if len(errs) != 0 {
 		return &startupError{errs}
@@ -117,6 +201,34 @@ func (se *StateEngine) StartUp() error {
 	return nil
 }
 
+// SetLazyStartUp sets the given manager to be initialized only after the
+// critical managers, that is by StartUp but not by StartUpCritical.
+func (se *StateEngine) SetLazyStartUp(m StateManager) {
+	se.statusLock.Lock()
+	defer se.statusLock.Unlock()
+	for _, su := range se.startUps {
+		if su.mgr == m {
+			su.status.Lazy = true
+		}
+	}
+}
+
+// StartUpStatus returns the status of the initialization of the managers
+// that have any, in the order they are initialized.
+func (se *StateEngine) StartUpStatus() []StartUpStatus {
+	se.statusLock.Lock()
+	defer se.statusLock.Unlock()
+	var critical, lazy []StartUpStatus
+	for _, su := range se.startUps {
+		if su.status.Lazy {
+			lazy = append(lazy, su.status)
+		} else {
+			critical = append(critical, su.status)
+		}
+	}
+	return append(critical, lazy...)
+}
+
 type ensureError struct {
 	errs []error
 }
@@ -161,6 +273,17 @@ func (se *StateEngine) AddManager(m StateManager) {
 	se.mgrLock.Lock()
 	defer se.mgrLock.Unlock()
 	se.managers = append(se.managers, m)
+	if _, ok := m.(StateStarterUp); ok {
+		se.statusLock.Lock()
+		defer se.statusLock.Unlock()
+		se.startUps = append(se.startUps, &managerStartUp{
+			mgr: m,
+			status: StartUpStatus{
+				Manager: managerName(m),
+				Status:  StartUpPending,
+			},
+		})
+	}
 }
 
 // Wait waits for all managers current activities.
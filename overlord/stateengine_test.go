@@ -107,6 +107,100 @@ func (ses *stateEngineSuite) TestStartUpError(c *C) {
 	c.Check(calls, DeepEquals, []string{"startup:mgr1", "startup:mgr2"})
 }
 
+func (ses *stateEngineSuite) TestStartUpCriticalThenLazy(c *C) {
+	s := state.New(nil)
+	se := overlord.NewStateEngine(s)
+
+	calls := []string{}
+
+	mgr1 := &fakeManager{name: "mgr1", calls: &calls}
+	mgr2 := &fakeManager{name: "mgr2", calls: &calls}
+	mgr3 := &fakeManager{name: "mgr3", calls: &calls}
+
+	se.AddManager(mgr1)
+	se.AddManager(mgr2)
+	se.AddManager(mgr3)
+	se.SetLazyStartUp(mgr2)
+
+	// all the fake managers are named after their package
+	status := func(lazy bool, st string) overlord.StartUpStatus {
+		return overlord.StartUpStatus{Manager: "overlord_test", Lazy: lazy, Status: st}
+	}
+	// lazy managers are listed last
+	c.Check(se.StartUpStatus(), DeepEquals, []overlord.StartUpStatus{
+		status(false, overlord.StartUpPending),
+		status(false, overlord.StartUpPending),
+		status(true, overlord.StartUpPending),
+	})
+
+	err := se.StartUpCritical()
+	c.Assert(err, IsNil)
+	c.Check(calls, DeepEquals, []string{"startup:mgr1", "startup:mgr3"})
+	c.Check(se.StartUpStatus(), DeepEquals, []overlord.StartUpStatus{
+		status(false, overlord.StartUpDone),
+		status(false, overlord.StartUpDone),
+		status(true, overlord.StartUpPending),
+	})
+
+	// not fully started up yet
+	c.Check(se.Ensure(), ErrorMatches, "state engine skipped startup")
+
+	err = se.StartUp()
+	c.Assert(err, IsNil)
+	c.Check(calls, DeepEquals, []string{"startup:mgr1", "startup:mgr3", "startup:mgr2"})
+	c.Check(se.StartUpStatus(), DeepEquals, []overlord.StartUpStatus{
+		status(false, overlord.StartUpDone),
+		status(false, overlord.StartUpDone),
+		status(true, overlord.StartUpDone),
+	})
+
+	// noop
+	c.Assert(se.StartUpCritical(), IsNil)
+	c.Assert(se.StartUp(), IsNil)
+	c.Check(calls, HasLen, 3)
+}
+
+func (ses *stateEngineSuite) TestStartUpLazyError(c *C) {
+	s := state.New(nil)
+	se := overlord.NewStateEngine(s)
+
+	calls := []string{}
+
+	mgr1 := &fakeManager{name: "mgr1", calls: &calls}
+	mgr2 := &fakeManager{name: "mgr2", calls: &calls, startupError: errors.New("boom")}
+
+	se.AddManager(mgr1)
+	se.AddManager(mgr2)
+	se.SetLazyStartUp(mgr2)
+
+	c.Assert(se.StartUpCritical(), IsNil)
+	err := se.StartUp()
+	c.Check(err, ErrorMatches, `state startup errors: \[boom\]`)
+	c.Check(calls, DeepEquals, []string{"startup:mgr1", "startup:mgr2"})
+	c.Check(se.StartUpStatus(), DeepEquals, []overlord.StartUpStatus{
+		{Manager: "overlord_test", Status: overlord.StartUpDone},
+		{Manager: "overlord_test", Lazy: true, Status: overlord.StartUpError, Err: errors.New("boom")},
+	})
+}
+
+func (ses *stateEngineSuite) TestStartUpCriticalErrorSkipsLazy(c *C) {
+	s := state.New(nil)
+	se := overlord.NewStateEngine(s)
+
+	calls := []string{}
+
+	mgr1 := &fakeManager{name: "mgr1", calls: &calls, startupError: errors.New("boom")}
+	mgr2 := &fakeManager{name: "mgr2", calls: &calls}
+
+	se.AddManager(mgr1)
+	se.AddManager(mgr2)
+	se.SetLazyStartUp(mgr2)
+
+	err := se.StartUp()
+	c.Check(err, ErrorMatches, `state startup errors: \[boom\]`)
+	c.Check(calls, DeepEquals, []string{"startup:mgr1"})
+}
+
 func (ses *stateEngineSuite) TestEnsure(c *C) {
 	s := state.New(nil)
 	se := overlord.NewStateEngine(s)
@@ -225,6 +225,19 @@ func MockOnCoreDesktop(onCoreDesktop bool) (restore func()) {
 	return func() { OnCoreDesktop = old }
 }
 
+// MockWSLVersion forces the process to appear inside the given version of
+// the Windows Subsystem for Linux, or outside of it if version is 0, for
+// testing purposes.
+func MockWSLVersion(version int) (restore func()) {
+	oldOnWSL, oldVersion := OnWSL, WSLVersion
+	OnWSL = version != 0
+	WSLVersion = version
+	return func() {
+		OnWSL = oldOnWSL
+		WSLVersion = oldVersion
+	}
+}
+
 // MockReleaseInfo fakes a given information to appear in ReleaseInfo,
 // as if it was read /etc/os-release on startup.
 func MockReleaseInfo(osRelease *OS) (restore func()) {
@@ -40,6 +40,14 @@ func MockRuntimeNumCPU(new func() int) (restore func()) {
 	}
 }
 
+func MockTotalUsableMemory(new func() (uint64, error)) (restore func()) {
+	old := osutilTotalUsableMemory
+	osutilTotalUsableMemory = new
+	return func() {
+		osutilTotalUsableMemory = old
+	}
+}
+
 func MockMkdirAll(f func(string, os.FileMode) error) func() {
 	r := testutil.Backup(&osMkdirAll)
 	osMkdirAll = f
@@ -64,6 +72,12 @@ func MockSnapConfineDistroProfilePath(f func() string) func() {
 	return r
 }
 
+func MockIsUsingRemoteFilesystem(f func(path string) (bool, error)) func() {
+	r := testutil.Backup(&osutilIsUsingRemoteFilesystem)
+	osutilIsUsingRemoteFilesystem = f
+	return r
+}
+
 func MockLoadHomedirs(f func() ([]string, error)) func() {
 	r := testutil.Backup(&loadHomedirs)
 	loadHomedirs = f
@@ -0,0 +1,133 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package apparmor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil"
+)
+
+// loadedProfilesFile returns the path of the file tracking the content of
+// the profiles loaded into the kernel. It lives in /run so that it does not
+// outlive the loaded policy across reboots.
+func loadedProfilesFile() string {
+	return filepath.Join(dirs.SnapRunDir, "apparmor", "loaded-profiles.json")
+}
+
+// profileDigest returns the digest of the content of the profile at the
+// given path.
+func profileDigest(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(content)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// profileDigests returns the digests of those of the given profiles which
+// can be read, indexed by their path.
+func profileDigests(paths []string) map[string]string {
+	digests := make(map[string]string, len(paths))
+	for _, path := range paths {
+		if digest, err := profileDigest(path); err == nil {
+			digests[path] = digest
+		}
+	}
+	return digests
+}
+
+func readLoadedProfiles() (map[string]string, error) {
+	loaded := make(map[string]string)
+	content, err := os.ReadFile(loadedProfilesFile())
+	if os.IsNotExist(err) {
+		return loaded, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &loaded); err != nil {
+		return nil, err
+	}
+	return loaded, nil
+}
+
+// recordLoadedProfiles records the digests of the given profiles, which
+// were just loaded into the kernel.
+func recordLoadedProfiles(digests map[string]string) error {
+	if len(digests) == 0 {
+		return nil
+	}
+	fname := loadedProfilesFile()
+	if err := os.MkdirAll(filepath.Dir(fname), 0755); err != nil {
+		return err
+	}
+	lock, err := osutil.NewFileLock(fname + ".lock")
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+	if err := lock.Lock(); err != nil {
+		return err
+	}
+
+	loaded, err := readLoadedProfiles()
+	if err != nil {
+		// start over, the worst that can happen is that profiles get
+		// reloaded needlessly
+		loaded = make(map[string]string)
+	}
+	for path, digest := range digests {
+		loaded[path] = digest
+	}
+	content, err := json.Marshal(loaded)
+	if err != nil {
+		return err
+	}
+	return osutil.AtomicWriteFile(fname, content, 0644, 0)
+}
+
+// StaleProfiles returns those of the given profiles whose content on disk
+// differs from the one last loaded into the kernel, or which are not known
+// to have been loaded at all, preserving their order. Those are the only
+// profiles which need to be loaded again.
+func StaleProfiles(paths []string) []string {
+	if len(paths) == 0 {
+		return paths
+	}
+	loaded, err := readLoadedProfiles()
+	if err != nil || len(loaded) == 0 {
+		return paths
+	}
+	stale := make([]string, 0, len(paths))
+	for _, path := range paths {
+		digest, err := profileDigest(path)
+		if err != nil || loaded[path] != digest {
+			stale = append(stale, path)
+		}
+	}
+	return stale
+}
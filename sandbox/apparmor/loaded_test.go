@@ -0,0 +1,109 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package apparmor_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/sandbox/apparmor"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type loadedSuite struct {
+	testutil.BaseTest
+
+	profiles []string
+}
+
+var _ = Suite(&loadedSuite{})
+
+func (s *loadedSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+
+	c.Assert(os.MkdirAll(dirs.SnapAppArmorDir, 0755), IsNil)
+	s.profiles = nil
+	for _, name := range []string{"snap.foo.app", "snap.foo.hook.configure", "snap-update-ns.foo"} {
+		profile := filepath.Join(dirs.SnapAppArmorDir, name)
+		c.Assert(os.WriteFile(profile, []byte("profile "+name+" {}\n"), 0644), IsNil)
+		s.profiles = append(s.profiles, profile)
+	}
+}
+
+func (s *loadedSuite) mockParser(c *C, script string) {
+	cmd := testutil.MockCommand(c, "apparmor_parser", script)
+	s.AddCleanup(cmd.Restore)
+	s.AddCleanup(apparmor.MockParserSearchPath(cmd.BinDir()))
+}
+
+func (s *loadedSuite) TestStaleProfilesNothingLoaded(c *C) {
+	c.Check(apparmor.StaleProfiles(s.profiles), DeepEquals, s.profiles)
+	c.Check(apparmor.StaleProfiles(nil), HasLen, 0)
+}
+
+func (s *loadedSuite) TestLoadProfilesRecordsLoaded(c *C) {
+	s.mockParser(c, "")
+
+	c.Assert(apparmor.LoadProfiles(s.profiles[:2], apparmor.CacheDir, 0), IsNil)
+	c.Check(apparmor.StaleProfiles(s.profiles), DeepEquals, s.profiles[2:])
+
+	c.Assert(apparmor.LoadProfiles(s.profiles[2:], apparmor.CacheDir, apparmor.SkipReadCache), IsNil)
+	c.Check(apparmor.StaleProfiles(s.profiles), HasLen, 0)
+
+	// a profile modified on disk needs to be loaded again
+	c.Assert(os.WriteFile(s.profiles[1], []byte("profile snap.foo.hook.configure { capability, }\n"), 0644), IsNil)
+	c.Check(apparmor.StaleProfiles(s.profiles), DeepEquals, s.profiles[1:2])
+
+	// as does a profile that is gone
+	c.Assert(os.Remove(s.profiles[0]), IsNil)
+	c.Check(apparmor.StaleProfiles(s.profiles), DeepEquals, s.profiles[:2])
+}
+
+func (s *loadedSuite) TestLoadProfilesSkipKernelLoadNotRecorded(c *C) {
+	s.mockParser(c, "")
+
+	c.Assert(apparmor.LoadProfiles(s.profiles, apparmor.CacheDir, apparmor.SkipKernelLoad), IsNil)
+	c.Check(apparmor.StaleProfiles(s.profiles), DeepEquals, s.profiles)
+	c.Check(filepath.Join(dirs.SnapRunDir, "apparmor/loaded-profiles.json"), testutil.FileAbsent)
+}
+
+func (s *loadedSuite) TestLoadProfilesErrorNotRecorded(c *C) {
+	s.mockParser(c, "exit 1")
+
+	c.Assert(apparmor.LoadProfiles(s.profiles, apparmor.CacheDir, 0), NotNil)
+	c.Check(apparmor.StaleProfiles(s.profiles), DeepEquals, s.profiles)
+}
+
+func (s *loadedSuite) TestStaleProfilesCorruptedRecord(c *C) {
+	fname := filepath.Join(dirs.SnapRunDir, "apparmor/loaded-profiles.json")
+	c.Assert(os.MkdirAll(filepath.Dir(fname), 0755), IsNil)
+	c.Assert(os.WriteFile(fname, []byte("{"), 0644), IsNil)
+	c.Check(apparmor.StaleProfiles(s.profiles), DeepEquals, s.profiles)
+
+	// loading starts over with a new record
+	s.mockParser(c, "")
+	c.Assert(apparmor.LoadProfiles(s.profiles, apparmor.CacheDir, 0), IsNil)
+	c.Check(apparmor.StaleProfiles(s.profiles), HasLen, 0)
+}
@@ -55,10 +55,12 @@ const (
 )
 
 var (
-	runtimeNumCPU = runtime.NumCPU
+	runtimeNumCPU           = runtime.NumCPU
+	osutilTotalUsableMemory = osutil.TotalUsableMemory
 
-	osutilIsHomeUsingNFS        = osutil.IsHomeUsingNFS
-	osutilIsRootWritableOverlay = osutil.IsRootWritableOverlay
+	osutilIsHomeUsingNFS          = osutil.IsHomeUsingNFS
+	osutilIsUsingRemoteFilesystem = osutil.IsUsingRemoteFilesystem
+	osutilIsRootWritableOverlay   = osutil.IsRootWritableOverlay
 )
 
 // NfsSnippet contains extra permissions necessary for snaps and snap-confine
@@ -89,6 +91,10 @@ var capabilityBPFSnippet = `
 capability bpf,
 `
 
+// parserJobMemory is a rough estimate of the memory needed by a single
+// apparmor_parser compilation job for snap profiles.
+const parserJobMemory = 256 * 1024 * 1024
+
 func numberOfJobsParam() string {
 	cpus := runtimeNumCPU()
 	// Do not use all CPUs as this may have negative impact when booting.
@@ -105,6 +111,16 @@ func numberOfJobsParam() string {
 		// -jauto) and 3.x (compile everything in the main process).
 		cpus = 1
 	}
+	// Do not run more jobs than what fits in memory either, which is the
+	// limiting factor on Pi-class devices.
+	if mem, err := osutilTotalUsableMemory(); err == nil {
+		if memJobs := int(mem / parserJobMemory); memJobs < cpus {
+			cpus = memJobs
+		}
+	}
+	if cpus < 1 {
+		cpus = 1
+	}
 
 	return fmt.Sprintf("-j%d", cpus)
 }
@@ -113,6 +129,8 @@ func numberOfJobsParam() string {
 //
 // If no such profiles were previously loaded then they are simply added to the kernel.
 // If there were some profiles with the same name before, those profiles are replaced.
+// The content of the profiles loaded into the kernel is tracked, such that
+// StaleProfiles can tell which profiles need to be loaded again.
 var LoadProfiles = func(fnames []string, cacheDir string, flags AaParserFlags) error {
 	if len(fnames) == 0 {
 		return nil
@@ -139,6 +157,13 @@ var LoadProfiles = func(fnames []string, cacheDir string, flags AaParserFlags) e
 		return err
 	}
 
+	var digests map[string]string
+	if flags&SkipKernelLoad == 0 {
+		// compute the digests before loading, should a profile be
+		// modified meanwhile it is then considered stale
+		digests = profileDigests(fnames)
+	}
+
 	cmd.Args = append(cmd.Args, args...)
 	cmd.Args = append(cmd.Args, fnames...)
 	output, err := cmd.CombinedOutput()
@@ -149,6 +174,9 @@ var LoadProfiles = func(fnames []string, cacheDir string, flags AaParserFlags) e
 		}
 		return fmt.Errorf("cannot load apparmor profiles: %s\napparmor_parser output:\n%s", err, string(output))
 	}
+	if err := recordLoadedProfiles(digests); err != nil {
+		logger.Noticef("cannot record loaded apparmor profiles: %v", err)
+	}
 	return nil
 }
 
@@ -289,6 +317,35 @@ var loadHomedirs = func() ([]string, error) {
 	return strutil.CommaSeparatedList(ssp.Homedirs), nil
 }
 
+// IsHomeUsingRemoteFS returns true if a network filesystem or autofs is
+// mounted at or under /home, or at or under any of the home directories
+// configured with the homedirs option. Because such filesystems are not
+// transparent to AppArmor, extra permissions are needed for snaps to access
+// the homes of users.
+func IsHomeUsingRemoteFS() (bool, error) {
+	homedirs, err := loadHomedirs()
+	if err != nil {
+		return false, err
+	}
+	return isHomeUsingRemoteFS(homedirs)
+}
+
+func isHomeUsingRemoteFS(homedirs []string) (bool, error) {
+	if remote, err := osutilIsHomeUsingNFS(); err != nil || remote {
+		return remote, err
+	}
+	for _, dir := range homedirs {
+		remote, err := osutilIsUsingRemoteFilesystem(dir)
+		if err != nil {
+			return false, err
+		}
+		if remote {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // SetupSnapConfineSnippets inspects the system and sets up local apparmor
 // policy for snap-confine. Local policy is included by the system-wide policy.
 // Returns whether any modifications was made to the snap-confine snippets.
@@ -300,10 +357,15 @@ func SetupSnapConfineSnippets() (wasChanged bool, err error) {
 
 	policy := make(map[string]osutil.FileState)
 
-	// Check if NFS is mounted at or under $HOME. Because NFS is not
-	// transparent to apparmor we must alter our profile to counter that and
-	// allow snap-confine to work.
-	if nfs, err := osutilIsHomeUsingNFS(); err != nil {
+	homedirs, homedirsErr := loadHomedirs()
+	if homedirsErr != nil {
+		logger.Noticef("cannot determine if any homedirs are set: %v", homedirsErr)
+	}
+
+	// Check if NFS, or another remote filesystem, is mounted at or under
+	// $HOME. Because NFS is not transparent to apparmor we must alter our
+	// profile to counter that and allow snap-confine to work.
+	if nfs, err := isHomeUsingRemoteFS(homedirs); err != nil {
 		logger.Noticef("cannot determine if NFS is in use: %v", err)
 	} else if nfs {
 		policy["nfs-support"] = &osutil.MemoryFileState{
@@ -338,9 +400,7 @@ func SetupSnapConfineSnippets() (wasChanged bool, err error) {
 		}
 	}
 
-	if homedirs, err := loadHomedirs(); err != nil {
-		logger.Noticef("cannot determine if any homedirs are set: %v", err)
-	} else if len(homedirs) > 0 {
+	if len(homedirs) > 0 {
 		policy["homedirs"] = &osutil.MemoryFileState{
 			Content: []byte(snapConfineHomedirsSnippet(homedirs)),
 			Mode:    0644,
@@ -326,6 +326,10 @@ func (s *appArmorSuite) TestMaybeSetNumberOfJobs(c *C) {
 		return cpus
 	})
 	defer restore()
+	restore = apparmor.MockTotalUsableMemory(func() (uint64, error) {
+		return 16 * 1024 * 1024 * 1024, nil
+	})
+	defer restore()
 
 	cpus = 10
 	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j8")
@@ -337,6 +341,34 @@ func (s *appArmorSuite) TestMaybeSetNumberOfJobs(c *C) {
 	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j1")
 }
 
+func (s *appArmorSuite) TestNumberOfJobsBoundByMemory(c *C) {
+	restore := apparmor.MockRuntimeNumCPU(func() int {
+		return 10
+	})
+	defer restore()
+	var mem uint64
+	var memErr error
+	restore = apparmor.MockTotalUsableMemory(func() (uint64, error) {
+		return mem, memErr
+	})
+	defer restore()
+
+	mem = 16 * 1024 * 1024 * 1024
+	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j8")
+
+	// a 1GiB Pi
+	mem = 1024 * 1024 * 1024
+	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j4")
+
+	// always at least one job
+	mem = 128 * 1024 * 1024
+	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j1")
+
+	// the CPU bound applies when the memory cannot be determined
+	memErr = fmt.Errorf("boom")
+	c.Check(apparmor.NumberOfJobsParam(), Equals, "-j8")
+}
+
 func (s *appArmorSuite) TestSnapConfineDistroProfilePath(c *C) {
 	baseDir := c.MkDir()
 	restore := testutil.Backup(&apparmor.ConfDir)
@@ -416,6 +448,8 @@ func (s *appArmorSuite) TestSetupSnapConfineSnippetsHomedirs(c *C) {
 	defer restore()
 	restore = osutil.MockIsHomeUsingNFS(func() (bool, error) { return false, nil })
 	defer restore()
+	restore = apparmor.MockIsUsingRemoteFilesystem(func(path string) (bool, error) { return false, nil })
+	defer restore()
 
 	// Setup the system-params which is read by loadHomedirs in SetupSnapConfineSnippets
 	// to verify it's correctly read and loaded.
@@ -590,6 +624,74 @@ func (s *appArmorSuite) TestSetupSnapConfineSnippetsNFS(c *C) {
 	c.Assert(fn, testutil.FileContains, "network inet6,")
 }
 
+func (s *appArmorSuite) TestSetupSnapConfineSnippetsRemoteHomedirs(c *C) {
+	dirs.SetRootDir(c.MkDir())
+	defer dirs.SetRootDir("")
+
+	restore := osutil.MockIsHomeUsingNFS(func() (bool, error) { return false, nil })
+	defer restore()
+	restore = osutil.MockIsRootWritableOverlay(func() (string, error) { return "", nil })
+	defer restore()
+	restore = apparmor.MockLoadHomedirs(func() ([]string, error) { return []string{"/srv/homes", "/mnt/users"}, nil })
+	defer restore()
+	// the homes under /mnt/users are mounted from CIFS shares by autofs
+	var checked []string
+	restore = apparmor.MockIsUsingRemoteFilesystem(func(path string) (bool, error) {
+		checked = append(checked, path)
+		return path == "/mnt/users", nil
+	})
+	defer restore()
+
+	wasChanged, err := apparmor.SetupSnapConfineSnippets()
+	c.Check(err, IsNil)
+	c.Check(wasChanged, Equals, true)
+	c.Check(checked, DeepEquals, []string{"/srv/homes", "/mnt/users"})
+
+	files, err := ioutil.ReadDir(apparmor.SnapConfineAppArmorDir)
+	c.Assert(err, IsNil)
+	c.Assert(files, HasLen, 2)
+	c.Check(files[0].Name(), Equals, "homedirs")
+	c.Check(files[1].Name(), Equals, "nfs-support")
+
+	remote, err := apparmor.IsHomeUsingRemoteFS()
+	c.Check(err, IsNil)
+	c.Check(remote, Equals, true)
+}
+
+func (s *appArmorSuite) TestIsHomeUsingRemoteFS(c *C) {
+	nfs := false
+	restore := osutil.MockIsHomeUsingNFS(func() (bool, error) { return nfs, nil })
+	defer restore()
+	restore = apparmor.MockLoadHomedirs(func() ([]string, error) { return []string{"/srv/homes"}, nil })
+	defer restore()
+	remoteHomedirs := false
+	restore = apparmor.MockIsUsingRemoteFilesystem(func(path string) (bool, error) {
+		c.Check(path, Equals, "/srv/homes")
+		return remoteHomedirs, nil
+	})
+	defer restore()
+
+	remote, err := apparmor.IsHomeUsingRemoteFS()
+	c.Check(err, IsNil)
+	c.Check(remote, Equals, false)
+
+	remoteHomedirs = true
+	remote, err = apparmor.IsHomeUsingRemoteFS()
+	c.Check(err, IsNil)
+	c.Check(remote, Equals, true)
+
+	remoteHomedirs = false
+	nfs = true
+	remote, err = apparmor.IsHomeUsingRemoteFS()
+	c.Check(err, IsNil)
+	c.Check(remote, Equals, true)
+
+	restore = apparmor.MockLoadHomedirs(func() ([]string, error) { return nil, fmt.Errorf("failed to load") })
+	defer restore()
+	_, err = apparmor.IsHomeUsingRemoteFS()
+	c.Check(err, ErrorMatches, "failed to load")
+}
+
 // Test behavior when isHomeUsingNFS fails.
 func (s *appArmorSuite) TestSetupSnapConfineGeneratedPolicyError1(c *C) {
 	dirs.SetRootDir(c.MkDir())
@@ -0,0 +1,126 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package sandbox
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/sandbox/apparmor"
+)
+
+// The behaviors of snapd which can be disabled when the system lacks the
+// capabilities they require.
+const (
+	// FeatureStrictConfinement is the enforcement of the security
+	// profiles of strictly confined snaps.
+	FeatureStrictConfinement = "strict-confinement"
+	// FeatureDeviceAccess is the mediation of the access of snaps to
+	// devices through udev rules.
+	FeatureDeviceAccess = "device-access"
+	// FeatureHotplug is the creation of slots for hotplugged devices.
+	FeatureHotplug = "hotplug"
+	// FeatureQuotaGroups is the enforcement of the resource limits of
+	// quota groups.
+	FeatureQuotaGroups = "quota-groups"
+)
+
+// DegradedFeature describes a behavior of snapd that is disabled because the
+// system lacks a capability it requires.
+type DegradedFeature struct {
+	// Feature is the name of the disabled behavior.
+	Feature string `json:"feature"`
+	// Reason explains why the behavior is disabled.
+	Reason string `json:"reason"`
+}
+
+var isWritable = osutil.IsWritable
+
+// For testing only
+var mockedDegradedFeatures *[]DegradedFeature
+
+// Container returns the name of the container manager snapd runs under, as
+// reported by systemd, or an empty string when not running in a container.
+func Container() string {
+	buf, err := os.ReadFile(filepath.Join(dirs.GlobalRootDir, "/run/systemd/container"))
+	if err != nil {
+		return ""
+	}
+	return string(bytes.TrimSpace(buf))
+}
+
+// DegradedFeatures returns the behaviors of snapd that are disabled because
+// it runs in a container lacking the capabilities they require. Outside of a
+// container no behavior is disabled this way.
+func DegradedFeatures() []DegradedFeature {
+	if mockedDegradedFeatures != nil {
+		return *mockedDegradedFeatures
+	}
+	if Container() == "" {
+		return nil
+	}
+
+	var degraded []DegradedFeature
+	if ForceDevMode() {
+		degraded = append(degraded, DegradedFeature{
+			Feature: FeatureStrictConfinement,
+			Reason:  "AppArmor is not fully available in the container: " + apparmor.Summary(),
+		})
+	}
+	if !osutil.FileExists(filepath.Join(dirs.GlobalRootDir, "/run/udev/control")) {
+		for _, feature := range []string{FeatureDeviceAccess, FeatureHotplug} {
+			degraded = append(degraded, DegradedFeature{
+				Feature: feature,
+				Reason:  "udev is not running in the container",
+			})
+		}
+	}
+	if !isWritable(filepath.Join(dirs.GlobalRootDir, "/sys/fs/cgroup")) {
+		degraded = append(degraded, DegradedFeature{
+			Feature: FeatureQuotaGroups,
+			Reason:  "the cgroup hierarchy is not delegated to the container",
+		})
+	}
+	return degraded
+}
+
+// Degraded returns whether the given behavior of snapd is disabled, as listed
+// by DegradedFeatures, along with the reason.
+func Degraded(feature string) (reason string, degraded bool) {
+	for _, d := range DegradedFeatures() {
+		if d.Feature == feature {
+			return d.Reason, true
+		}
+	}
+	return "", false
+}
+
+// MockDegradedFeatures fakes the behaviors of snapd disabled on the system as
+// returned by DegradedFeatures.
+func MockDegradedFeatures(degraded []DegradedFeature) (restore func()) {
+	old := mockedDegradedFeatures
+	mockedDegradedFeatures = &degraded
+	return func() {
+		mockedDegradedFeatures = old
+	}
+}
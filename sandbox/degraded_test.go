@@ -0,0 +1,113 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package sandbox_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/sandbox"
+	"github.com/snapcore/snapd/sandbox/apparmor"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type degradedSuite struct {
+	testutil.BaseTest
+}
+
+var _ = Suite(&degradedSuite{})
+
+func (s *degradedSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+	s.AddCleanup(apparmor.MockLevel(apparmor.Full))
+	s.AddCleanup(sandbox.MockIsWritable(func(string) bool { return true }))
+}
+
+func (s *degradedSuite) mockFile(c *C, path, content string) {
+	path = filepath.Join(dirs.GlobalRootDir, path)
+	c.Assert(os.MkdirAll(filepath.Dir(path), 0755), IsNil)
+	c.Assert(os.WriteFile(path, []byte(content), 0644), IsNil)
+}
+
+func (s *degradedSuite) TestNotInContainer(c *C) {
+	s.AddCleanup(apparmor.MockLevel(apparmor.Unsupported))
+	s.AddCleanup(sandbox.MockIsWritable(func(string) bool { return false }))
+
+	c.Check(sandbox.Container(), Equals, "")
+	c.Check(sandbox.DegradedFeatures(), HasLen, 0)
+	_, degraded := sandbox.Degraded(sandbox.FeatureQuotaGroups)
+	c.Check(degraded, Equals, false)
+}
+
+func (s *degradedSuite) TestContainerWithAllCapabilities(c *C) {
+	s.mockFile(c, "/run/systemd/container", "lxc\n")
+	s.mockFile(c, "/run/udev/control", "")
+
+	c.Check(sandbox.Container(), Equals, "lxc")
+	c.Check(sandbox.DegradedFeatures(), HasLen, 0)
+}
+
+func (s *degradedSuite) TestContainerDegraded(c *C) {
+	s.mockFile(c, "/run/systemd/container", "docker\n")
+	s.AddCleanup(apparmor.MockLevel(apparmor.Unsupported))
+	var checked []string
+	s.AddCleanup(sandbox.MockIsWritable(func(path string) bool {
+		checked = append(checked, path)
+		return false
+	}))
+
+	c.Check(sandbox.DegradedFeatures(), DeepEquals, []sandbox.DegradedFeature{{
+		Feature: sandbox.FeatureStrictConfinement,
+		Reason:  "AppArmor is not fully available in the container: " + apparmor.Summary(),
+	}, {
+		Feature: sandbox.FeatureDeviceAccess,
+		Reason:  "udev is not running in the container",
+	}, {
+		Feature: sandbox.FeatureHotplug,
+		Reason:  "udev is not running in the container",
+	}, {
+		Feature: sandbox.FeatureQuotaGroups,
+		Reason:  "the cgroup hierarchy is not delegated to the container",
+	}})
+	c.Check(checked, DeepEquals, []string{filepath.Join(dirs.GlobalRootDir, "/sys/fs/cgroup")})
+
+	reason, degraded := sandbox.Degraded(sandbox.FeatureQuotaGroups)
+	c.Check(degraded, Equals, true)
+	c.Check(reason, Equals, "the cgroup hierarchy is not delegated to the container")
+}
+
+func (s *degradedSuite) TestMockDegradedFeatures(c *C) {
+	mocked := []sandbox.DegradedFeature{{Feature: sandbox.FeatureHotplug, Reason: "mocked"}}
+	restore := sandbox.MockDegradedFeatures(mocked)
+	c.Check(sandbox.DegradedFeatures(), DeepEquals, mocked)
+	reason, degraded := sandbox.Degraded(sandbox.FeatureHotplug)
+	c.Check(degraded, Equals, true)
+	c.Check(reason, Equals, "mocked")
+	_, degraded = sandbox.Degraded(sandbox.FeatureQuotaGroups)
+	c.Check(degraded, Equals, false)
+
+	restore()
+	c.Check(sandbox.DegradedFeatures(), HasLen, 0)
+}
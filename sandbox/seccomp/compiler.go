@@ -234,3 +234,26 @@ func (c *Compiler) Compile(in, out string) error {
 	}
 	return nil
 }
+
+// SyscallNames returns the names of the system calls with the given numbers
+// on the architecture identified by its audit value in hexadecimal, as it
+// appears in logged system calls, e.g. c000003e for x86_64.
+func (c *Compiler) SyscallNames(auditArch string, numbers []int) ([]string, error) {
+	args := []string{"syscall-names", auditArch}
+	for _, nr := range numbers {
+		args = append(args, strconv.Itoa(nr))
+	}
+	cmd := exec.Command(c.snapSeccomp, args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			output = exitErr.Stderr
+		}
+		return nil, osutil.OutputErr(output, err)
+	}
+	names := strings.Fields(string(output))
+	if len(names) != len(numbers) {
+		return nil, fmt.Errorf("cannot resolve system call names: expected %d names, got %d", len(numbers), len(names))
+	}
+	return names, nil
+}
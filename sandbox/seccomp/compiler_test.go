@@ -177,6 +177,39 @@ exit 0
 	})
 }
 
+func (s *compilerSuite) TestSyscallNames(c *C) {
+	cmd := testutil.MockCommand(c, "snap-seccomp", `
+if [ "$1" = "syscall-names" ]; then echo read; echo openat; exit 0; fi
+exit 1
+`)
+	defer cmd.Restore()
+	compiler, err := seccomp.NewCompiler(fromCmd(c, cmd))
+	c.Assert(err, IsNil)
+
+	names, err := compiler.SyscallNames("c000003e", []int{0, 257})
+	c.Assert(err, IsNil)
+	c.Check(names, DeepEquals, []string{"read", "openat"})
+	c.Check(cmd.Calls(), DeepEquals, [][]string{
+		{"snap-seccomp", "syscall-names", "c000003e", "0", "257"},
+	})
+}
+
+func (s *compilerSuite) TestSyscallNamesUnhappy(c *C) {
+	cmd := testutil.MockCommand(c, "snap-seccomp", `
+if [ "$1" = "syscall-names" ] && [ "$2" = "deadbeef" ]; then echo 'unsupported audit architecture "deadbeef"' >&2; exit 1; fi
+echo read
+`)
+	defer cmd.Restore()
+	compiler, err := seccomp.NewCompiler(fromCmd(c, cmd))
+	c.Assert(err, IsNil)
+
+	_, err = compiler.SyscallNames("deadbeef", []int{0})
+	c.Assert(err, ErrorMatches, `unsupported audit architecture "deadbeef"`)
+
+	_, err = compiler.SyscallNames("c000003e", []int{0, 1})
+	c.Assert(err, ErrorMatches, "cannot resolve system call names: expected 2 names, got 1")
+}
+
 func (s *compilerSuite) TestCompilerNewUnhappy(c *C) {
 	compiler, err := seccomp.NewCompiler(func(name string) (string, error) { return "", errors.New("failed") })
 	c.Assert(err, ErrorMatches, "failed")
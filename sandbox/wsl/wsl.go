@@ -0,0 +1,79 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package wsl probes the features of the Windows Subsystem for Linux that
+// affect how snapd manages snaps.
+package wsl
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/release"
+)
+
+// Info describes the Windows Subsystem for Linux instance snapd runs in.
+type Info struct {
+	// Version is the version of WSL, snapd only works on WSL2.
+	Version int
+	// Interop is set if Windows executables can be invoked from the
+	// instance.
+	Interop bool
+	// UserSessions is set if systemd manages user sessions in the
+	// instance, which is needed to run the user daemons of snaps.
+	UserSessions bool
+}
+
+// Probe returns the features of the WSL instance snapd runs in, or nil if it
+// does not run in WSL.
+func Probe() *Info {
+	if !release.OnWSL {
+		return nil
+	}
+	return &Info{
+		Version:      release.WSLVersion,
+		Interop:      interopEnabled(),
+		UserSessions: userSessionsAvailable(),
+	}
+}
+
+// interopEnabled returns whether the binfmt_misc handler running Windows
+// executables is registered and enabled.
+func interopEnabled() bool {
+	f, err := os.Open(filepath.Join(dirs.GlobalRootDir, "/proc/sys/fs/binfmt_misc/WSLInterop"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	// the first line of a binfmt_misc handler conveys its status
+	scanner := bufio.NewScanner(f)
+	return scanner.Scan() && strings.TrimSpace(scanner.Text()) == "enabled"
+}
+
+// userSessionsAvailable returns whether systemd runs the system and
+// logind tracks user sessions, which WSL only does if systemd is enabled in
+// its configuration.
+func userSessionsAvailable() bool {
+	return osutil.IsDirectory(filepath.Join(dirs.GlobalRootDir, "/run/systemd/system")) &&
+		osutil.IsDirectory(filepath.Join(dirs.GlobalRootDir, "/run/systemd/users"))
+}
@@ -0,0 +1,85 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package wsl_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/release"
+	"github.com/snapcore/snapd/sandbox/wsl"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type wslSuite struct {
+	testutil.BaseTest
+}
+
+var _ = Suite(&wslSuite{})
+
+func (s *wslSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+}
+
+func (s *wslSuite) mockFile(c *C, path, content string) {
+	path = filepath.Join(dirs.GlobalRootDir, path)
+	c.Assert(os.MkdirAll(filepath.Dir(path), 0755), IsNil)
+	c.Assert(os.WriteFile(path, []byte(content), 0644), IsNil)
+}
+
+func (s *wslSuite) TestProbeNotWSL(c *C) {
+	s.AddCleanup(release.MockWSLVersion(0))
+	s.mockFile(c, "/proc/sys/fs/binfmt_misc/WSLInterop", "enabled\n")
+
+	c.Check(wsl.Probe(), IsNil)
+}
+
+func (s *wslSuite) TestProbeBare(c *C) {
+	s.AddCleanup(release.MockWSLVersion(2))
+
+	c.Check(wsl.Probe(), DeepEquals, &wsl.Info{Version: 2})
+}
+
+func (s *wslSuite) TestProbeInteropDisabled(c *C) {
+	s.AddCleanup(release.MockWSLVersion(2))
+	s.mockFile(c, "/proc/sys/fs/binfmt_misc/WSLInterop", "disabled\ninterpreter /init\nflags: PF\n")
+
+	c.Check(wsl.Probe(), DeepEquals, &wsl.Info{Version: 2})
+}
+
+func (s *wslSuite) TestProbeFull(c *C) {
+	s.AddCleanup(release.MockWSLVersion(2))
+	s.mockFile(c, "/proc/sys/fs/binfmt_misc/WSLInterop", "enabled\ninterpreter /init\nflags: PF\n")
+	c.Assert(os.MkdirAll(filepath.Join(dirs.GlobalRootDir, "/run/systemd/system"), 0755), IsNil)
+
+	// systemd without logind does not track user sessions
+	c.Check(wsl.Probe(), DeepEquals, &wsl.Info{Version: 2, Interop: true})
+
+	c.Assert(os.MkdirAll(filepath.Join(dirs.GlobalRootDir, "/run/systemd/users"), 0755), IsNil)
+	c.Check(wsl.Probe(), DeepEquals, &wsl.Info{Version: 2, Interop: true, UserSessions: true})
+}
@@ -168,6 +168,22 @@ func MockSbMeasureSnapModelToTPM(f func(tpm *sb_tpm2.Connection, pcrIndex int, m
 	}
 }
 
+func MockTPMPCREvent(f func(tpm *sb_tpm2.Connection, pcrIndex int, event []byte) error) (restore func()) {
+	old := tpmPCREvent
+	tpmPCREvent = f
+	return func() {
+		tpmPCREvent = old
+	}
+}
+
+func MockTPMQuote(f func(tpm *sb_tpm2.Connection, qualifyingData []byte, pcrs []int) (*Quote, error)) (restore func()) {
+	old := tpmQuote
+	tpmQuote = f
+	return func() {
+		tpmQuote = old
+	}
+}
+
 func MockRandomKernelUUID(f func() (string, error)) (restore func()) {
 	old := randutilRandomKernelUUID
 	randutilRandomKernelUUID = f
@@ -46,6 +46,26 @@ const (
 	AltFallbackObjectPCRPolicyCounterHandle = uint32(0x01880004)
 )
 
+// SnapMeasurementsPCR is the TPM PCR extended with the measurements of
+// installed snaps, it is the PCR reserved for application support.
+const SnapMeasurementsPCR = 23
+
+// Quote is a TPM quote of PCR values, signed by an attestation key created
+// in the endorsement hierarchy of the TPM.
+type Quote struct {
+	// Attest is the TPMS_ATTEST structure produced by the TPM, in the TPM
+	// wire format. It holds the qualifying data and the digest of the
+	// quoted PCRs.
+	Attest []byte `json:"attest"`
+	// Signature is the TPMT_SIGNATURE of Attest, in the TPM wire format.
+	Signature []byte `json:"signature"`
+	// AttestationKey is the TPMT_PUBLIC area of the key that signed
+	// Attest, in the TPM wire format.
+	AttestationKey []byte `json:"attestation-key"`
+	// PCRs are the indexes of the quoted PCRs of the SHA-256 bank.
+	PCRs []int `json:"pcrs"`
+}
+
 // WithSecbootSupport is true if this package was built with githbu.com/snapcore/secboot.
 var WithSecbootSupport = false
 
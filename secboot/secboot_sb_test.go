@@ -196,6 +196,131 @@ func (s *secbootSuite) TestMeasureSnapSystemEpochWhenPossible(c *C) {
 	}
 }
 
+func (s *secbootSuite) TestMeasureSnapEventWhenPossible(c *C) {
+	for i, tc := range []struct {
+		tpmErr     error
+		tpmEnabled bool
+		eventErr   error
+		measured   bool
+		err        string
+	}{
+		{
+			// normal connection to the TPM device
+			tpmEnabled: true, measured: true,
+		},
+		{
+			// extending the PCR fails
+			tpmEnabled: true, eventErr: errors.New("pcr error"),
+			err: "cannot measure snap event: pcr error",
+		},
+		{
+			// TPM device exists but returns error
+			tpmErr: errors.New("tpm error"),
+			err:    "cannot measure snap event: cannot open TPM connection: tpm error",
+		},
+		{
+			// TPM device exists but is disabled
+			tpmEnabled: false,
+		},
+		{
+			// TPM device does not exist
+			tpmErr: sb_tpm2.ErrNoTPM2Device,
+		},
+	} {
+		c.Logf("%d: tpmErr:%v tpmEnabled:%v", i, tc.tpmErr, tc.tpmEnabled)
+		mockTpm, restore := mockSbTPMConnection(c, tc.tpmErr)
+		defer restore()
+
+		restore = secboot.MockIsTPMEnabled(func(tpm *sb_tpm2.Connection) bool {
+			return tc.tpmEnabled
+		})
+		defer restore()
+
+		restore = secboot.MockTPMPCREvent(func(tpm *sb_tpm2.Connection, pcrIndex int, event []byte) error {
+			c.Assert(tpm, Equals, mockTpm)
+			c.Assert(pcrIndex, Equals, 23)
+			c.Assert(string(event), Equals, "snap-blob sha256:1234 /foo")
+			return tc.eventErr
+		})
+		defer restore()
+
+		measured, err := secboot.MeasureSnapEventWhenPossible([]byte("snap-blob sha256:1234 /foo"))
+		if tc.err == "" {
+			c.Assert(err, IsNil)
+		} else {
+			c.Assert(err, ErrorMatches, tc.err)
+		}
+		c.Check(measured, Equals, tc.measured)
+	}
+}
+
+func (s *secbootSuite) TestQuoteWhenPossible(c *C) {
+	mockQuote := &secboot.Quote{
+		Attest:         []byte("attest"),
+		Signature:      []byte("signature"),
+		AttestationKey: []byte("key"),
+		PCRs:           []int{7, 12},
+	}
+	for i, tc := range []struct {
+		tpmErr     error
+		tpmEnabled bool
+		quoteErr   error
+		quote      *secboot.Quote
+		err        string
+	}{
+		{
+			// normal connection to the TPM device
+			tpmEnabled: true, quote: mockQuote,
+		},
+		{
+			// quoting fails
+			tpmEnabled: true, quoteErr: errors.New("quote error"),
+			err: "cannot quote PCRs: quote error",
+		},
+		{
+			// TPM device exists but returns error
+			tpmErr: errors.New("tpm error"),
+			err:    "cannot quote PCRs: cannot open TPM connection: tpm error",
+		},
+		{
+			// TPM device exists but is disabled
+			tpmEnabled: false,
+		},
+		{
+			// TPM device does not exist
+			tpmErr: sb_tpm2.ErrNoTPM2Device,
+		},
+	} {
+		c.Logf("%d: tpmErr:%v tpmEnabled:%v", i, tc.tpmErr, tc.tpmEnabled)
+		mockTpm, restore := mockSbTPMConnection(c, tc.tpmErr)
+		defer restore()
+
+		restore = secboot.MockIsTPMEnabled(func(tpm *sb_tpm2.Connection) bool {
+			return tc.tpmEnabled
+		})
+		defer restore()
+
+		restore = secboot.MockTPMQuote(func(tpm *sb_tpm2.Connection, qualifyingData []byte, pcrs []int) (*secboot.Quote, error) {
+			c.Assert(tpm, Equals, mockTpm)
+			c.Assert(string(qualifyingData), Equals, "nonce")
+			c.Assert(pcrs, DeepEquals, []int{7, 12})
+			if tc.quoteErr != nil {
+				return nil, tc.quoteErr
+			}
+			return mockQuote, nil
+		})
+		defer restore()
+
+		quote, err := secboot.QuoteWhenPossible([]byte("nonce"), []int{7, 12})
+		if tc.err == "" {
+			c.Assert(err, IsNil)
+		} else {
+			c.Assert(err, ErrorMatches, tc.err)
+		}
+		c.Check(quote, Equals, tc.quote)
+	}
+}
+
 func (s *secbootSuite) TestMeasureSnapModelWhenPossible(c *C) {
 	for i, tc := range []struct {
 		tpmErr     error
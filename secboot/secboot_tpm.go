@@ -188,6 +188,93 @@ func MeasureSnapModelWhenPossible(findModel func() (*asserts.Model, error)) erro
 	return nil
 }
 
+var tpmPCREvent = func(tpm *sb_tpm2.Connection, pcrIndex int, event []byte) error {
+	_, err := tpm.PCREvent(tpm.PCRHandleContext(pcrIndex), tpm2.Event(event), tpm.HmacSession())
+	return err
+}
+
+// MeasureSnapEventWhenPossible extends SnapMeasurementsPCR with the given
+// event only if the TPM device is available. It returns whether the event
+// was measured, if there's no TPM device false and success are returned.
+func MeasureSnapEventWhenPossible(event []byte) (measured bool, err error) {
+	measure := func(tpm *sb_tpm2.Connection) error {
+		if err := tpmPCREvent(tpm, SnapMeasurementsPCR, event); err != nil {
+			return err
+		}
+		measured = true
+		return nil
+	}
+
+	if err := measureWhenPossible(measure); err != nil {
+		return false, fmt.Errorf("cannot measure snap event: %v", err)
+	}
+
+	return measured, nil
+}
+
+// attestationKeyTemplate is the template of the restricted signing key that
+// is created in the endorsement hierarchy to sign quotes. The key is derived
+// from the endorsement primary seed so it is the same for every quote.
+var attestationKeyTemplate = tpm2.Public{
+	Type:    tpm2.ObjectTypeECC,
+	NameAlg: tpm2.HashAlgorithmSHA256,
+	Attrs:   tpm2.AttrFixedTPM | tpm2.AttrFixedParent | tpm2.AttrSensitiveDataOrigin | tpm2.AttrUserWithAuth | tpm2.AttrRestricted | tpm2.AttrSign,
+	Params: &tpm2.PublicParamsU{
+		ECCDetail: &tpm2.ECCParams{
+			Symmetric: tpm2.SymDefObject{Algorithm: tpm2.SymObjectAlgorithmNull},
+			Scheme: tpm2.ECCScheme{
+				Scheme:  tpm2.ECCSchemeECDSA,
+				Details: &tpm2.AsymSchemeU{ECDSA: &tpm2.SigSchemeECDSA{HashAlg: tpm2.HashAlgorithmSHA256}},
+			},
+			CurveID: tpm2.ECCCurveNIST_P256,
+			KDF:     tpm2.KDFScheme{Scheme: tpm2.KDFAlgorithmNull},
+		},
+	},
+}
+
+var tpmQuote = func(tpm *sb_tpm2.Connection, qualifyingData []byte, pcrs []int) (*Quote, error) {
+	ak, akPublic, _, _, _, err := tpm.CreatePrimary(tpm.EndorsementHandleContext(), nil, &attestationKeyTemplate, nil, nil, tpm.HmacSession())
+	if err != nil {
+		return nil, fmt.Errorf("cannot create attestation key: %v", err)
+	}
+	defer tpm.FlushContext(ak)
+
+	selection := tpm2.PCRSelectionList{{Hash: tpm2.HashAlgorithmSHA256, Select: pcrs}}
+	attest, signature, err := tpm.Quote(ak, qualifyingData, nil, selection, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &Quote{PCRs: pcrs}
+	if quote.Attest, err = mu.MarshalToBytes(attest); err != nil {
+		return nil, err
+	}
+	if quote.Signature, err = mu.MarshalToBytes(signature); err != nil {
+		return nil, err
+	}
+	if quote.AttestationKey, err = mu.MarshalToBytes(akPublic); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// QuoteWhenPossible produces a quote of the given PCRs of the SHA-256 bank
+// with the given qualifying data only if the TPM device is available. If
+// there's no TPM device no quote and success are returned.
+func QuoteWhenPossible(qualifyingData []byte, pcrs []int) (*Quote, error) {
+	var quote *Quote
+	doQuote := func(tpm *sb_tpm2.Connection) (err error) {
+		quote, err = tpmQuote(tpm, qualifyingData, pcrs)
+		return err
+	}
+
+	if err := measureWhenPossible(doQuote); err != nil {
+		return nil, fmt.Errorf("cannot quote PCRs: %v", err)
+	}
+
+	return quote, nil
+}
+
 func lockTPMSealedKeys() error {
 	tpm, tpmErr := sbConnectToDefaultTPM()
 	if tpmErr != nil {
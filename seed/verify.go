@@ -0,0 +1,243 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/channel"
+	"github.com/snapcore/snapd/snap/snapfile"
+	"github.com/snapcore/snapd/timings"
+)
+
+// The checks performed by Verify on each seed system, in order.
+const (
+	// VerifyCheckAssertions checks the assertions of the seed against
+	// each other and the trusted ones.
+	VerifyCheckAssertions = "assertions"
+	// VerifyCheckEssentialSnaps checks that the essential snaps
+	// required by the model are part of the seed.
+	VerifyCheckEssentialSnaps = "essential-snaps"
+	// VerifyCheckSnaps checks the snaps of the seed against the model
+	// and their assertions, including their hashes.
+	VerifyCheckSnaps = "snaps"
+	// VerifyCheckSnapMetadata checks that the snap files of the seed
+	// can be read.
+	VerifyCheckSnapMetadata = "snap-metadata"
+	// VerifyCheckChannels checks that the channels of the snaps are
+	// valid and consistent with the tracks pinned by the model.
+	VerifyCheckChannels = "channels"
+	// VerifyCheckBasesAndProviders checks that the bases and default
+	// content providers of the snaps are part of the seed.
+	VerifyCheckBasesAndProviders = "bases-and-providers"
+)
+
+// VerifyCheck is the outcome of one check of a seed system.
+type VerifyCheck struct {
+	Name string `json:"name"`
+	// Status is one of "ok", "failed" or "skipped", checks are skipped
+	// when an earlier check they depend on failed.
+	Status string   `json:"status"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// SystemVerification is the outcome of verifying one seed system.
+type SystemVerification struct {
+	// Label is the label of a UC20+ seed system, or empty for a
+	// UC16/18 or classic seed.
+	Label  string        `json:"label,omitempty"`
+	Model  string        `json:"model,omitempty"`
+	Brand  string        `json:"brand-id,omitempty"`
+	OK     bool          `json:"ok"`
+	Checks []VerifyCheck `json:"checks"`
+}
+
+// VerifyReport is the outcome of verifying a seed with Verify.
+type VerifyReport struct {
+	OK      bool                  `json:"ok"`
+	Systems []*SystemVerification `json:"systems"`
+}
+
+// Verify fully verifies all the systems of the seed at seedDir, UC20+
+// recovery systems as well as a UC16/18 or classic seed. The returned
+// report details the outcome of each check, an error is returned only if
+// the seed systems cannot be found at all.
+func Verify(seedDir string) (*VerifyReport, error) {
+	var labels []string
+	if osutil.FileExists(filepath.Join(seedDir, "seed.yaml")) {
+		labels = append(labels, "")
+	}
+	entries, err := os.ReadDir(filepath.Join(seedDir, "systems"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			labels = append(labels, entry.Name())
+		}
+	}
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("cannot find any seed system in %q", seedDir)
+	}
+	sort.Strings(labels)
+
+	report := &VerifyReport{OK: true}
+	for _, label := range labels {
+		sys := verifySystem(seedDir, label)
+		report.OK = report.OK && sys.OK
+		report.Systems = append(report.Systems, sys)
+	}
+	return report, nil
+}
+
+type systemVerifier struct {
+	sys    *SystemVerification
+	failed bool
+}
+
+// check runs f as the named check, unless an earlier check failed.
+func (v *systemVerifier) check(name string, f func() []error) {
+	if v.failed {
+		v.sys.Checks = append(v.sys.Checks, VerifyCheck{Name: name, Status: "skipped"})
+		return
+	}
+	chk := VerifyCheck{Name: name, Status: "ok"}
+	if errs := f(); len(errs) != 0 {
+		chk.Status = "failed"
+		for _, err := range errs {
+			chk.Errors = append(chk.Errors, err.Error())
+		}
+		v.failed = true
+	}
+	v.sys.Checks = append(v.sys.Checks, chk)
+}
+
+func verifySystem(seedDir, label string) *SystemVerification {
+	v := &systemVerifier{sys: &SystemVerification{Label: label}}
+
+	var sd Seed
+	v.check(VerifyCheckAssertions, func() []error {
+		var err error
+		sd, err = Open(seedDir, label)
+		if err != nil {
+			return []error{err}
+		}
+		if err := sd.LoadAssertions(nil, nil); err != nil {
+			return []error{err}
+		}
+		v.sys.Model = sd.Model().Model()
+		v.sys.Brand = sd.Model().BrandID()
+		return nil
+	})
+
+	// loading the metadata checks the snaps against the model and their
+	// assertions, failing early if an essential snap is missing
+	var loadErr error
+	if !v.failed {
+		loadErr = sd.LoadMeta(AllModes, nil, timings.New(nil))
+	}
+	v.check(VerifyCheckEssentialSnaps, func() []error {
+		var missingErr *essentialSnapMissingError
+		if errors.As(loadErr, &missingErr) {
+			if sd.Model().Classic() && missingErr.SnapName == "core" {
+				return []error{fmt.Errorf("essential snap core or snapd must be part of the seed")}
+			}
+			return []error{loadErr}
+		}
+		return nil
+	})
+	v.check(VerifyCheckSnaps, func() []error {
+		if loadErr != nil {
+			return []error{loadErr}
+		}
+		return nil
+	})
+
+	var snapInfos []*snap.Info
+	v.check(VerifyCheckSnapMetadata, func() []error {
+		var errs []error
+		sd.Iter(func(sn *Snap) error {
+			snapf, err := snapfile.Open(sn.Path)
+			if err != nil {
+				errs = append(errs, err)
+				return nil
+			}
+			info, err := snap.ReadInfoFromSnapFile(snapf, sn.SideInfo)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("cannot use snap %q: %v", sn.Path, err))
+				return nil
+			}
+			snapInfos = append(snapInfos, info)
+			return nil
+		})
+		return errs
+	})
+
+	v.check(VerifyCheckChannels, func() []error {
+		return verifyChannels(sd)
+	})
+
+	v.check(VerifyCheckBasesAndProviders, func() []error {
+		_, errs := snap.ValidateBasesAndProviders(snapInfos)
+		return errs
+	})
+
+	v.sys.OK = !v.failed
+	return v.sys
+}
+
+// verifyChannels checks that the channels of the seed snaps are valid and
+// within the tracks pinned by the model.
+func verifyChannels(sd Seed) []error {
+	model := sd.Model()
+	pinnedTracks := make(map[string]string)
+	for _, modSnap := range model.AllSnaps() {
+		if modSnap.PinnedTrack != "" {
+			pinnedTracks[modSnap.SnapName()] = modSnap.PinnedTrack
+		}
+	}
+
+	var errs []error
+	sd.Iter(func(sn *Snap) error {
+		if sn.Channel == "" {
+			return nil
+		}
+		ch, err := channel.ParseVerbatim(sn.Channel, model.Architecture())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("snap %q has invalid channel %q: %v", sn.SnapName(), sn.Channel, err))
+			return nil
+		}
+		pinned, ok := pinnedTracks[sn.SnapName()]
+		if !ok {
+			return nil
+		}
+		if ch.Track != "" && ch.Track != pinned {
+			errs = append(errs, fmt.Errorf("snap %q channel %q is not in the track %q pinned by the model", sn.SnapName(), sn.Channel, pinned))
+		}
+		return nil
+	})
+	return errs
+}
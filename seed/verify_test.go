@@ -0,0 +1,148 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package seed_test
+
+import (
+	"os"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/seed"
+)
+
+func (s *validateSuite) TestVerifyHappy(c *C) {
+	s.makeSnapInSeed(c, coreYaml)
+	s.makeSnapInSeed(c, `name: gtk-common-themes
+version: 19.04`)
+	s.makeSeedYaml(c, `
+snaps:
+ - name: core
+   channel: stable
+   file: core_1.snap
+ - name: gtk-common-themes
+   channel: stable/ubuntu-19.04
+   file: gtk-common-themes_1.snap
+`)
+
+	report, err := seed.Verify(s.SeedDir)
+	c.Assert(err, IsNil)
+	c.Check(report, DeepEquals, &seed.VerifyReport{
+		OK: true,
+		Systems: []*seed.SystemVerification{{
+			Model: "my-model",
+			Brand: "my-brand",
+			OK:    true,
+			Checks: []seed.VerifyCheck{
+				{Name: seed.VerifyCheckAssertions, Status: "ok"},
+				{Name: seed.VerifyCheckEssentialSnaps, Status: "ok"},
+				{Name: seed.VerifyCheckSnaps, Status: "ok"},
+				{Name: seed.VerifyCheckSnapMetadata, Status: "ok"},
+				{Name: seed.VerifyCheckChannels, Status: "ok"},
+				{Name: seed.VerifyCheckBasesAndProviders, Status: "ok"},
+			},
+		}},
+	})
+}
+
+func (s *validateSuite) TestVerifyMissingBase(c *C) {
+	s.makeSnapInSeed(c, `name: need-base
+base: some-base
+version: 1.0`)
+	s.makeSnapInSeed(c, coreYaml)
+	s.makeSeedYaml(c, `
+snaps:
+ - name: core
+   file: core_1.snap
+ - name: need-base
+   file: need-base_1.snap
+`)
+
+	report, err := seed.Verify(s.SeedDir)
+	c.Assert(err, IsNil)
+	c.Check(report.OK, Equals, false)
+	c.Assert(report.Systems, HasLen, 1)
+	c.Check(report.Systems[0].OK, Equals, false)
+	c.Check(report.Systems[0].Checks[4:], DeepEquals, []seed.VerifyCheck{
+		{Name: seed.VerifyCheckChannels, Status: "ok"},
+		{Name: seed.VerifyCheckBasesAndProviders, Status: "failed", Errors: []string{
+			`cannot use snap "need-base": base "some-base" is missing`,
+		}},
+	})
+}
+
+func (s *validateSuite) TestVerifyMissingSnapdOrCore(c *C) {
+	s.makeSnapInSeed(c, packageCore18)
+	s.makeSnapInSeed(c, `name: some-snap
+version: 1.0
+base: core18`)
+	s.makeSeedYaml(c, `
+snaps:
+ - name: some-snap
+   file: some-snap_1.snap
+ - name: core18
+   file: core18_1.snap
+`)
+
+	report, err := seed.Verify(s.SeedDir)
+	c.Assert(err, IsNil)
+	c.Check(report, DeepEquals, &seed.VerifyReport{
+		OK: false,
+		Systems: []*seed.SystemVerification{{
+			Model: "my-model",
+			Brand: "my-brand",
+			OK:    false,
+			Checks: []seed.VerifyCheck{
+				{Name: seed.VerifyCheckAssertions, Status: "ok"},
+				{Name: seed.VerifyCheckEssentialSnaps, Status: "failed", Errors: []string{
+					"essential snap core or snapd must be part of the seed",
+				}},
+				{Name: seed.VerifyCheckSnaps, Status: "skipped"},
+				{Name: seed.VerifyCheckSnapMetadata, Status: "skipped"},
+				{Name: seed.VerifyCheckChannels, Status: "skipped"},
+				{Name: seed.VerifyCheckBasesAndProviders, Status: "skipped"},
+			},
+		}},
+	})
+}
+
+func (s *validateSuite) TestVerifyNoAssertions(c *C) {
+	c.Assert(os.RemoveAll(s.AssertsDir()), IsNil)
+	s.makeSeedYaml(c, `
+snaps:
+`)
+
+	report, err := seed.Verify(s.SeedDir)
+	c.Assert(err, IsNil)
+	c.Check(report.OK, Equals, false)
+	c.Assert(report.Systems, HasLen, 1)
+	c.Check(report.Systems[0].Checks[0], DeepEquals, seed.VerifyCheck{
+		Name:   seed.VerifyCheckAssertions,
+		Status: "failed",
+		Errors: []string{"no seed assertions"},
+	})
+	for _, chk := range report.Systems[0].Checks[1:] {
+		c.Check(chk.Status, Equals, "skipped")
+	}
+}
+
+func (s *validateSuite) TestVerifyNoSystems(c *C) {
+	_, err := seed.Verify(s.SeedDir)
+	c.Assert(err, ErrorMatches, `cannot find any seed system in ".*"`)
+}
@@ -385,6 +385,13 @@ type Info struct {
 
 	StoreURL string
 
+	// CreatedAt is when the revision was created in the store
+	CreatedAt time.Time
+
+	// ReleaseNotes are the release notes published in the store for
+	// the revision
+	ReleaseNotes string
+
 	// The flattended channel map with $track/$risk
 	Channels map[string]*ChannelSnapInfo
 
@@ -428,6 +435,8 @@ type Layout struct {
 	Group    string      `json:"group,omitempty"`
 	Mode     os.FileMode `json:"mode,omitempty"`
 	Symlink  string      `json:"symlink,omitempty"`
+	// Size is the size limit, in bytes, of a tmpfs layout.
+	Size int64 `json:"size,omitempty"`
 }
 
 // String returns a simple textual representation of a layout.
@@ -455,9 +464,81 @@ func (l *Layout) String() string {
 	if l.Mode != 0755 {
 		fmt.Fprintf(&buf, ", mode: %#o", l.Mode)
 	}
+	if l.Size != 0 {
+		fmt.Fprintf(&buf, ", size: %d", l.Size)
+	}
 	return buf.String()
 }
 
+// Kind returns the kind of the layout, one of "bind", "bind-file",
+// "symlink" or "type".
+func (l *Layout) Kind() string {
+	switch {
+	case l.Bind != "":
+		return "bind"
+	case l.BindFile != "":
+		return "bind-file"
+	case l.Symlink != "":
+		return "symlink"
+	case l.Type != "":
+		return "type"
+	}
+	return ""
+}
+
+// LayoutChange describes how the layout of a path differs between two
+// revisions of a snap. Old is nil for added layouts and New is nil for
+// removed ones.
+type LayoutChange struct {
+	Path string
+	Old  *Layout
+	New  *Layout
+}
+
+// Incompatible returns true if the layout of the path changes its kind, for
+// example from a bind mount to a symlink. Such changes cannot be applied to
+// an existing mount namespace.
+func (c *LayoutChange) Incompatible() bool {
+	return c.Old != nil && c.New != nil && (c.Old.Kind() != c.New.Kind() || c.Old.Type != c.New.Type)
+}
+
+func (c *LayoutChange) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("added %s", c.New)
+	case c.New == nil:
+		return fmt.Sprintf("removed %s", c.Old)
+	}
+	return fmt.Sprintf("changed %s (was %s)", c.New, strings.TrimPrefix(c.Old.String(), c.Path+": "))
+}
+
+// DiffLayouts returns the changes, sorted by path, between the layouts of
+// two revisions of a snap.
+func DiffLayouts(oldInfo, newInfo *Info) []*LayoutChange {
+	var changes []*LayoutChange
+	for path, old := range oldInfo.Layout {
+		new := newInfo.Layout[path]
+		if new == nil {
+			changes = append(changes, &LayoutChange{Path: path, Old: old})
+			continue
+		}
+		// The string representation captures all the properties of the
+		// layout but not the snap it belongs to.
+		if old.String() != new.String() {
+			changes = append(changes, &LayoutChange{Path: path, Old: old, New: new})
+		}
+	}
+	for path, new := range newInfo.Layout {
+		if oldInfo.Layout[path] == nil {
+			changes = append(changes, &LayoutChange{Path: path, New: new})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
 // ChannelSnapInfo is the minimum information that can be used to clearly
 // distinguish different revisions of the same snap.
 type ChannelSnapInfo struct {
@@ -125,6 +125,7 @@ type layoutYaml struct {
 	Group    string `yaml:"group,omitempty"`
 	Mode     string `yaml:"mode,omitempty"`
 	Symlink  string `yaml:"symlink,omitempty"`
+	Size     string `yaml:"size,omitempty"`
 }
 
 type socketsYaml struct {
@@ -216,10 +217,18 @@ func infoFromSnapYaml(yamlData []byte, strk *scopedTracker) (*Info, error) {
 			if l.Group != "" {
 				group = l.Group
 			}
+			var size int64
+			if l.Size != "" {
+				var err error
+				size, err = strutil.ParseByteSize(l.Size)
+				if err != nil {
+					return nil, fmt.Errorf("layout %q uses invalid size: %v", path, err)
+				}
+			}
 			snap.Layout[path] = &Layout{
 				Snap: snap, Path: path,
 				Bind: l.Bind, Type: l.Type, Symlink: l.Symlink, BindFile: l.BindFile,
-				User: user, Group: group, Mode: mode,
+				User: user, Group: group, Mode: mode, Size: size,
 			}
 		}
 	}
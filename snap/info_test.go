@@ -1527,6 +1527,82 @@ layout:
 	})
 }
 
+func (s *infoSuite) TestLayoutParsingTmpfsSize(c *C) {
+	info, err := snap.InfoFromSnapYaml([]byte(`name: layout-demo
+layout:
+  /mytmp:
+    type: tmpfs
+    size: 64MB
+`))
+	c.Assert(err, IsNil)
+	c.Check(info.Layout["/mytmp"], DeepEquals, &snap.Layout{
+		Snap:  info,
+		Path:  "/mytmp",
+		Type:  "tmpfs",
+		User:  "root",
+		Group: "root",
+		Mode:  0755,
+		Size:  64 * 1000 * 1000,
+	})
+	c.Check(info.Layout["/mytmp"].String(), Equals, "/mytmp: type tmpfs, size: 64000000")
+
+	_, err = snap.InfoFromSnapYaml([]byte(`name: layout-demo
+layout:
+  /mytmp:
+    type: tmpfs
+    size: lots
+`))
+	c.Assert(err, ErrorMatches, `layout "/mytmp" uses invalid size: cannot parse "lots": .*`)
+}
+
+func (s *infoSuite) TestDiffLayouts(c *C) {
+	oldInfo, err := snap.InfoFromSnapYaml([]byte(`name: layout-demo
+layout:
+  /usr/share/foo:
+    bind: $SNAP/usr/share/foo
+  /usr/share/bar:
+    bind: $SNAP/usr/share/bar
+  /etc/froz:
+    bind-file: $SNAP/etc/froz
+  /mytmp:
+    type: tmpfs
+`))
+	c.Assert(err, IsNil)
+	newInfo, err := snap.InfoFromSnapYaml([]byte(`name: layout-demo
+layout:
+  /usr/share/foo:
+    bind: $SNAP/usr/share/foo
+  /usr/share/bar:
+    symlink: $SNAP/usr/share/bar
+  /mytmp:
+    type: tmpfs
+    size: 1MB
+  /opt/baz:
+    bind: $SNAP/opt/baz
+`))
+	c.Assert(err, IsNil)
+
+	changes := snap.DiffLayouts(oldInfo, newInfo)
+	c.Assert(changes, HasLen, 4)
+	var descs []string
+	var incompatible []string
+	for _, change := range changes {
+		descs = append(descs, change.String())
+		if change.Incompatible() {
+			incompatible = append(incompatible, change.Path)
+		}
+	}
+	c.Check(descs, DeepEquals, []string{
+		"removed /etc/froz: bind-file $SNAP/etc/froz",
+		"changed /mytmp: type tmpfs, size: 1000000 (was type tmpfs)",
+		"added /opt/baz: bind $SNAP/opt/baz",
+		"changed /usr/share/bar: symlink $SNAP/usr/share/bar (was bind $SNAP/usr/share/bar)",
+	})
+	c.Check(incompatible, DeepEquals, []string{"/usr/share/bar"})
+
+	c.Check(snap.DiffLayouts(oldInfo, oldInfo), HasLen, 0)
+}
+
 func (s *infoSuite) TestPlugInfoString(c *C) {
 	plug := &snap.PlugInfo{Snap: &snap.Info{SuggestedName: "snap"}, Name: "plug"}
 	c.Assert(plug.String(), Equals, "snap:plug")
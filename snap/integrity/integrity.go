@@ -21,12 +21,15 @@ package integrity
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 
 	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/snap/integrity/dmverity"
 )
 
@@ -161,3 +164,165 @@ func GenerateAndAppend(snapPath string) (err error) {
 
 	return err
 }
+
+// ErrNoIntegrityData is returned when a snap file has no integrity data.
+var ErrNoIntegrityData = errors.New("no integrity data found")
+
+// minimumSnapSize is the size squashfs snaps are grown to when packed, before
+// any integrity data is appended to them.
+const minimumSnapSize = 16384
+
+// squashfsDataSize returns the size of the squashfs filesystem at the start
+// of the given file as it was padded by mksquashfs.
+func squashfsDataSize(f *os.File) (uint64, error) {
+	// bytes_used is a little endian uint64 at offset 40 of the superblock
+	var bytesUsed [8]byte
+	if _, err := f.ReadAt(bytesUsed[:], 40); err != nil {
+		return 0, fmt.Errorf("cannot read squashfs superblock: %v", err)
+	}
+	size := align(binary.LittleEndian.Uint64(bytesUsed[:]))
+	if size < minimumSnapSize {
+		size = minimumSnapSize
+	}
+	return size, nil
+}
+
+// readIntegrityDataHeader reads and decodes the integrity data header at the
+// given offset of f, checking that the integrity data it describes ends at
+// fileSize.
+func readIntegrityDataHeader(f *os.File, offset, fileSize uint64) (*IntegrityDataHeader, error) {
+	if offset+HeaderSize > fileSize {
+		return nil, ErrNoIntegrityData
+	}
+	buf := make([]byte, HeaderSize)
+	if _, err := f.ReadAt(buf, int64(offset)); err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(buf, magic) {
+		return nil, ErrNoIntegrityData
+	}
+	var header IntegrityDataHeader
+	if err := header.Decode(buf); err != nil {
+		return nil, err
+	}
+	if header.Type != "integrity" || offset+header.Size != fileSize {
+		return nil, fmt.Errorf("invalid integrity data header: unexpected size or type")
+	}
+	return &header, nil
+}
+
+// FindIntegrityData returns the header of the integrity data appended to the
+// given snap file by GenerateAndAppend, together with the offset of the header
+// in the file. ErrNoIntegrityData is returned if the snap has none.
+func FindIntegrityData(snapPath string) (header *IntegrityDataHeader, offset uint64, err error) {
+	f, err := os.Open(snapPath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, err = squashfsDataSize(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	header, err = readIntegrityDataHeader(f, offset, uint64(fi.Size()))
+	if err != nil {
+		return nil, 0, err
+	}
+	return header, offset, nil
+}
+
+// VerityData describes how to mount a snap through dm-verity.
+type VerityData struct {
+	// RootHash is the root hash of the dm-verity hash tree.
+	RootHash string
+	// HashDevice is the file holding the dm-verity hash tree, it is
+	// either the snap file itself or the file from VerityFilePath.
+	HashDevice string
+	// HashOffset is the offset of the dm-verity superblock in HashDevice.
+	HashOffset uint64
+}
+
+// MountOptions returns the mount options asking libmount to set up a
+// dm-verity device for the mounted snap.
+func (v *VerityData) MountOptions() []string {
+	return []string{
+		"verity.roothash=" + v.RootHash,
+		"verity.hashdevice=" + v.HashDevice,
+		fmt.Sprintf("verity.hashoffset=%d", v.HashOffset),
+	}
+}
+
+// VerityFilePath returns the path of the file holding the dm-verity data
+// generated by GenerateVerityFile for the given snap file.
+func VerityFilePath(snapPath string) string {
+	return snapPath + ".verity"
+}
+
+// GenerateVerityFile generates dm-verity data for a snap file that does not
+// ship any and stores it next to the snap at VerityFilePath. The file uses
+// the same layout as the integrity data appended by GenerateAndAppend, a
+// header followed by the dm-verity hash tree.
+func GenerateVerityFile(snapPath string) error {
+	verityPath := VerityFilePath(snapPath)
+	hashFileName := verityPath + ".tmp"
+	defer os.Remove(hashFileName)
+
+	dmVerityBlock, err := dmverity.Format(snapPath, hashFileName)
+	if err != nil {
+		return err
+	}
+	hashData, err := os.ReadFile(hashFileName)
+	if err != nil {
+		return err
+	}
+	header, err := newIntegrityDataHeader(dmVerityBlock, uint64(len(hashData))).Encode()
+	if err != nil {
+		return err
+	}
+	return osutil.AtomicWriteFile(verityPath, append(header, hashData...), 0600, 0)
+}
+
+// FindVerityData returns the dm-verity data to mount the given snap file
+// with, either shipped within the snap or generated by GenerateVerityFile.
+// ErrNoIntegrityData is returned if there is none.
+func FindVerityData(snapPath string) (*VerityData, error) {
+	header, offset, err := FindIntegrityData(snapPath)
+	if err == nil {
+		return &VerityData{
+			RootHash:   header.DmVerity.RootHash,
+			HashDevice: snapPath,
+			HashOffset: offset + HeaderSize,
+		}, nil
+	}
+	if err != ErrNoIntegrityData {
+		return nil, err
+	}
+
+	verityPath := VerityFilePath(snapPath)
+	f, err := os.Open(verityPath)
+	if os.IsNotExist(err) {
+		return nil, ErrNoIntegrityData
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	header, err = readIntegrityDataHeader(f, 0, uint64(fi.Size()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot use %s: %v", verityPath, err)
+	}
+	return &VerityData{
+		RootHash:   header.DmVerity.RootHash,
+		HashDevice: verityPath,
+		HashOffset: HeaderSize,
+	}, nil
+}
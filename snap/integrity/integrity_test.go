@@ -20,10 +20,12 @@
 package integrity_test
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -278,3 +280,91 @@ esac
 	c.Check(vscmd.Calls()[0], DeepEquals, []string{"veritysetup", "--version"})
 	c.Check(vscmd.Calls()[1], DeepEquals, []string{"veritysetup", "format", snapPath, snapPath + ".verity"})
 }
+
+func mockSquashfsFile(c *C, bytesUsed uint64) string {
+	// only bytes_used of the squashfs superblock is looked at
+	data := make([]byte, 16384)
+	binary.LittleEndian.PutUint64(data[40:], bytesUsed)
+	snapPath := filepath.Join(c.MkDir(), "foo_1.snap")
+	c.Assert(os.WriteFile(snapPath, data, 0644), IsNil)
+	return snapPath
+}
+
+func mockVeritySetup(c *C) *testutil.MockCmd {
+	return testutil.MockCommand(c, "veritysetup", `
+case "$1" in
+	--version)
+		echo "veritysetup 2.2.6"
+		exit 0
+		;;
+	format)
+		truncate -s 8192 "$3"
+		echo "Hash algorithm:  	sha256"
+		echo "Root hash:      	e2926364a8b1242d92fb1b56081e1ddb86eba35411961252a103a1c083c2be6d"
+		;;
+esac
+`)
+}
+
+func (s *IntegrityTestSuite) TestFindVerityDataShipped(c *C) {
+	vscmd := mockVeritySetup(c)
+	defer vscmd.Restore()
+
+	snapPath := mockSquashfsFile(c, 5000)
+	c.Assert(integrity.GenerateAndAppend(snapPath), IsNil)
+
+	header, offset, err := integrity.FindIntegrityData(snapPath)
+	c.Assert(err, IsNil)
+	c.Check(offset, Equals, uint64(16384))
+	c.Check(header.Size, Equals, uint64(8192+integrity.HeaderSize))
+
+	vd, err := integrity.FindVerityData(snapPath)
+	c.Assert(err, IsNil)
+	c.Check(vd, DeepEquals, &integrity.VerityData{
+		RootHash:   "e2926364a8b1242d92fb1b56081e1ddb86eba35411961252a103a1c083c2be6d",
+		HashDevice: snapPath,
+		HashOffset: 16384 + integrity.HeaderSize,
+	})
+	c.Check(vd.MountOptions(), DeepEquals, []string{
+		"verity.roothash=e2926364a8b1242d92fb1b56081e1ddb86eba35411961252a103a1c083c2be6d",
+		"verity.hashdevice=" + snapPath,
+		"verity.hashoffset=20480",
+	})
+}
+
+func (s *IntegrityTestSuite) TestFindVerityDataGenerated(c *C) {
+	vscmd := mockVeritySetup(c)
+	defer vscmd.Restore()
+
+	snapPath := mockSquashfsFile(c, 5000)
+	_, err := integrity.FindVerityData(snapPath)
+	c.Check(err, Equals, integrity.ErrNoIntegrityData)
+
+	c.Assert(integrity.GenerateVerityFile(snapPath), IsNil)
+	verityPath := integrity.VerityFilePath(snapPath)
+	c.Check(vscmd.Calls()[1], DeepEquals, []string{"veritysetup", "format", snapPath, verityPath + ".tmp"})
+	c.Check(verityPath+".tmp", testutil.FileAbsent)
+
+	fi, err := os.Stat(verityPath)
+	c.Assert(err, IsNil)
+	c.Check(fi.Size(), Equals, int64(8192+integrity.HeaderSize))
+
+	vd, err := integrity.FindVerityData(snapPath)
+	c.Assert(err, IsNil)
+	c.Check(vd, DeepEquals, &integrity.VerityData{
+		RootHash:   "e2926364a8b1242d92fb1b56081e1ddb86eba35411961252a103a1c083c2be6d",
+		HashDevice: verityPath,
+		HashOffset: integrity.HeaderSize,
+	})
+}
+
+func (s *IntegrityTestSuite) TestFindVerityDataInvalidGenerated(c *C) {
+	snapPath := mockSquashfsFile(c, 20000)
+	verityPath := integrity.VerityFilePath(snapPath)
+	header := make([]byte, integrity.HeaderSize)
+	copy(header, append(integrity.Magic, `{"type":"integrity","size":"42"}`+"\x00"...))
+	c.Assert(os.WriteFile(verityPath, header, 0600), IsNil)
+
+	_, err := integrity.FindVerityData(snapPath)
+	c.Check(err, ErrorMatches, `cannot use .*/foo_1.snap.verity: invalid integrity data header: unexpected size or type`)
+}
@@ -20,10 +20,12 @@
 package snapenv
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
+	"strings"
 
 	"github.com/snapcore/snapd/arch"
 	"github.com/snapcore/snapd/dirs"
@@ -54,6 +56,49 @@ func ExtendEnvForRun(env osutil.Environment, info *snap.Info, opts *dirs.SnapDir
 	for k, v := range snapEnv(info, opts) {
 		env[k] = v
 	}
+	// The proxy settings of the snap, if any, override the ones of the
+	// system.
+	for k, v := range proxyEnv(info.InstanceName()) {
+		env[k] = v
+	}
+}
+
+// ProxyEnvFile returns the path of the file with the proxy environment of the
+// given snap instance.
+func ProxyEnvFile(instanceName string) string {
+	return filepath.Join(dirs.SnapProxyEnvDir, fmt.Sprintf("snap.%s.env", instanceName))
+}
+
+// proxyEnv returns the proxy environment variables set for the given snap
+// instance, or nil if the snap uses the system's proxy settings.
+func proxyEnv(instanceName string) osutil.Environment {
+	f, err := os.Open(ProxyEnvFile(instanceName))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Noticef("cannot read proxy environment of snap %q: %v", instanceName, err)
+		}
+		return nil
+	}
+	defer f.Close()
+
+	env := osutil.Environment{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		env[k] = v
+	}
+	if err := scanner.Err(); err != nil {
+		logger.Noticef("cannot read proxy environment of snap %q: %v", instanceName, err)
+		return nil
+	}
+	return env
 }
 
 func snapEnv(info *snap.Info, opts *dirs.SnapDirOptions) osutil.Environment {
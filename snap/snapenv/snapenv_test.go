@@ -314,6 +314,36 @@ func (s *HTestSuite) TestExtendEnvForRunForClassic(c *C) {
 	c.Assert(env["TMPDIR"], Equals, "/var/tmp")
 }
 
+func (s *HTestSuite) TestExtendEnvForRunProxyOverride(c *C) {
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+
+	c.Assert(os.MkdirAll(dirs.SnapProxyEnvDir, 0755), IsNil)
+	c.Assert(os.WriteFile(ProxyEnvFile("foo"), []byte("http_proxy=http://proxy:3128\nhttps_proxy=\n# comment\n\nno_proxy=*\n"), 0644), IsNil)
+
+	env := osutil.Environment{
+		"http_proxy":  "http://system-proxy:3128",
+		"https_proxy": "http://system-proxy:3128",
+		"TMPDIR":      "/var/tmp",
+	}
+	ExtendEnvForRun(env, mockSnapInfo, nil)
+
+	c.Check(env["http_proxy"], Equals, "http://proxy:3128")
+	c.Check(env["https_proxy"], Equals, "")
+	c.Check(env["no_proxy"], Equals, "*")
+	c.Check(env["TMPDIR"], Equals, "/var/tmp")
+}
+
+func (s *HTestSuite) TestExtendEnvForRunNoProxyOverride(c *C) {
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+
+	env := osutil.Environment{"http_proxy": "http://system-proxy:3128"}
+	ExtendEnvForRun(env, mockSnapInfo, nil)
+
+	c.Check(env["http_proxy"], Equals, "http://system-proxy:3128")
+}
+
 func (s *HTestSuite) TestHiddenDirEnv(c *C) {
 	usr, err := user.Current()
 	c.Assert(err, IsNil)
@@ -22,6 +22,7 @@ package squashfs
 import (
 	"bufio"
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -56,6 +57,17 @@ var (
 	isRootWritableOverlay = osutil.IsRootWritableOverlay
 )
 
+// compressionNames maps the compression IDs stored in the squashfs
+// superblock to the names used by mksquashfs.
+var compressionNames = map[uint16]string{
+	1: "gzip",
+	2: "lzma",
+	3: "lzo",
+	4: "xz",
+	5: "lz4",
+	6: "zstd",
+}
+
 func FileHasSquashfsHeader(path string) bool {
 	f, err := os.Open(path)
 	if err != nil {
@@ -77,6 +89,32 @@ type Snap struct {
 	path string
 }
 
+// Compression returns the name of the compression the snap was built
+// with, as read from its superblock.
+func (s *Snap) Compression() (string, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	header := make([]byte, superblockSize)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		return "", fmt.Errorf("cannot read squashfs superblock of %q: %v", s.path, err)
+	}
+	if !bytes.HasPrefix(header, magic) {
+		return "", fmt.Errorf("cannot read squashfs superblock of %q: bad magic", s.path)
+	}
+	// the compression ID follows the magic, inode count, modification
+	// time, block size and fragment count, all 32-bit
+	id := binary.LittleEndian.Uint16(header[20:22])
+	name, ok := compressionNames[id]
+	if !ok {
+		return "", fmt.Errorf("unknown compression %d in squashfs superblock of %q", id, s.path)
+	}
+	return name, nil
+}
+
 // Path returns the path of the backing file.
 func (s *Snap) Path() string {
 	return s.path
@@ -154,7 +192,9 @@ func (s *Snap) Install(targetPath, mountDir string, opts *snap.InstallOptions) (
 		}
 	}
 
-	return false, osutil.CopyFile(s.path, targetPath, osutil.CopyFlagPreserveAll|osutil.CopyFlagSync)
+	// on filesystems supporting it the copy shares the data blocks
+	// with the source, which is as cheap as a hard link
+	return false, osutil.CopyFile(s.path, targetPath, osutil.CopyFlagPreserveAll|osutil.CopyFlagSync|osutil.CopyFlagReflink)
 }
 
 // unsquashfsStderrWriter is a helper that captures errors from
@@ -21,6 +21,7 @@ package squashfs_test
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -141,6 +142,38 @@ func (s *SquashfsTestSuite) TestFileHasSquashfsHeader(c *C) {
 	c.Check(squashfs.FileHasSquashfsHeader(sn.Path()), Equals, true)
 }
 
+func (s *SquashfsTestSuite) TestCompression(c *C) {
+	for id, name := range map[uint16]string{1: "gzip", 3: "lzo", 4: "xz", 6: "zstd"} {
+		header := make([]byte, squashfs.SuperblockSize+1)
+		copy(header, "hsqs")
+		binary.LittleEndian.PutUint16(header[20:], id)
+		p := filepath.Join(c.MkDir(), "foo.snap")
+		c.Assert(os.WriteFile(p, header, 0644), IsNil)
+
+		comp, err := squashfs.New(p).Compression()
+		c.Assert(err, IsNil)
+		c.Check(comp, Equals, name)
+	}
+}
+
+func (s *SquashfsTestSuite) TestCompressionErrors(c *C) {
+	p := filepath.Join(c.MkDir(), "foo.snap")
+	c.Assert(os.WriteFile(p, []byte("hsqs"), 0644), IsNil)
+	_, err := squashfs.New(p).Compression()
+	c.Check(err, ErrorMatches, `cannot read squashfs superblock of ".*/foo.snap": EOF`)
+
+	c.Assert(os.WriteFile(p, make([]byte, squashfs.SuperblockSize), 0644), IsNil)
+	_, err = squashfs.New(p).Compression()
+	c.Check(err, ErrorMatches, `cannot read squashfs superblock of ".*/foo.snap": bad magic`)
+
+	header := make([]byte, squashfs.SuperblockSize)
+	copy(header, "hsqs")
+	binary.LittleEndian.PutUint16(header[20:], 42)
+	c.Assert(os.WriteFile(p, header, 0644), IsNil)
+	_, err = squashfs.New(p).Compression()
+	c.Check(err, ErrorMatches, `unknown compression 42 in squashfs superblock of ".*/foo.snap"`)
+}
+
 func (s *SquashfsTestSuite) TestNotFileHasSquashfsHeader(c *C) {
 	data := []string{
 		"hsqs",
@@ -237,7 +270,10 @@ exec /bin/cp "$@"
 	didNothing, err := sn.Install(targetPath, mountDir, nil)
 	c.Assert(err, IsNil)
 	c.Assert(didNothing, Equals, false)
-	c.Check(cmd.Calls(), HasLen, 1)
+	// the data is shared with the source where possible
+	c.Check(cmd.Calls(), DeepEquals, [][]string{
+		{"cp", "-av", "--reflink=auto", sn.Path(), targetPath},
+	})
 
 	didNothing, err = sn.Install(targetPath, mountDir, nil)
 	c.Assert(err, IsNil)
@@ -942,7 +978,7 @@ func (s *SquashfsTestSuite) TestBuildWithCompressionHappy(c *C) {
 	c.Assert(err, IsNil)
 
 	defaultComp := "xz"
-	for _, comp := range []string{"", "xz", "gzip", "lzo"} {
+	for _, comp := range []string{"", "xz", "gzip", "lzo", "zstd"} {
 		sn := squashfs.New(filepath.Join(c.MkDir(), "foo.snap"))
 		err = sn.Build(buildDir, &squashfs.BuildOpts{
 			Compression: comp,
@@ -1090,6 +1090,15 @@ func ValidateLayout(layout *Layout, constraints []LayoutConstraint) error {
 		return fmt.Errorf("layout %q uses invalid filesystem %q", layout.Path, layout.Type)
 	}
 
+	if layout.Size != 0 {
+		if layout.Type != "tmpfs" {
+			return fmt.Errorf("layout %q uses size but only tmpfs layouts can be limited in size", layout.Path)
+		}
+		if layout.Size < 0 {
+			return fmt.Errorf("layout %q uses invalid size %d", layout.Path, layout.Size)
+		}
+	}
+
 	if layout.Symlink != "" {
 		oldname := layout.Symlink
 		if err := ValidatePathVariables(oldname); err != nil {
@@ -959,6 +959,10 @@ func (s *ValidateSuite) TestValidateLayout(c *C) {
 		ErrorMatches, `layout "/foo" must define a bind mount, a filesystem mount or a symlink`)
 	c.Check(ValidateLayout(&Layout{Snap: si, Path: "/foo", Type: "ext4"}, nil),
 		ErrorMatches, `layout "/foo" uses invalid filesystem "ext4"`)
+	c.Check(ValidateLayout(&Layout{Snap: si, Path: "/foo", Bind: "$SNAP/bar", Size: 1024}, nil),
+		ErrorMatches, `layout "/foo" uses size but only tmpfs layouts can be limited in size`)
+	c.Check(ValidateLayout(&Layout{Snap: si, Path: "/foo", Type: "tmpfs", Size: -1}, nil),
+		ErrorMatches, `layout "/foo" uses invalid size -1`)
 	c.Check(ValidateLayout(&Layout{Snap: si, Path: "/foo/bar", Type: "tmpfs", User: "foo"}, nil),
 		ErrorMatches, `layout "/foo/bar" uses invalid user "foo"`)
 	c.Check(ValidateLayout(&Layout{Snap: si, Path: "/foo/bar", Type: "tmpfs", Group: "foo"}, nil),
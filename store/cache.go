@@ -222,3 +222,101 @@ func hardLinkCount(fi os.FileInfo) (uint64, error) {
 	}
 	return 0, fmt.Errorf("internal error: cannot read hardlink count from %s", fi.Name())
 }
+
+// CacheEntry describes an item in the download cache.
+type CacheEntry struct {
+	// Key is the name of the entry in the cache, i.e. the digest of
+	// its content.
+	Key     string
+	Size    int64
+	ModTime time.Time
+	// Links is the number of hard links to the content, including
+	// the cache entry itself. Entries with more than one link are
+	// referenced elsewhere and removing them reclaims no space.
+	Links uint64
+}
+
+// Entries returns the items in the cache ordered from the least to the
+// most recently used one. A missing cache directory yields no entries.
+func (cm *CacheManager) Entries() ([]CacheEntry, error) {
+	fil, err := ioutil.ReadDir(cm.cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sort.Sort(changesByMtime(fil))
+
+	entries := make([]CacheEntry, 0, len(fil))
+	for _, fi := range fil {
+		if !fi.Mode().IsRegular() {
+			continue
+		}
+		n, err := hardLinkCount(fi)
+		if err != nil {
+			logger.Noticef("cannot inspect cache: %s", err)
+		}
+		entries = append(entries, CacheEntry{
+			Key:     fi.Name(),
+			Size:    fi.Size(),
+			ModTime: fi.ModTime(),
+			Links:   n,
+		})
+	}
+	return entries, nil
+}
+
+// CachePruneOptions controls which entries CacheManager.Prune removes.
+type CachePruneOptions struct {
+	// OlderThan, if not zero, removes entries last used before it.
+	OlderThan time.Time
+	// MaxSize, if positive, removes the least recently used entries
+	// until the size of the unreferenced entries is at most MaxSize.
+	MaxSize int64
+}
+
+// Prune removes entries of the cache that are not referenced elsewhere
+// in the filesystem. Without any options all such entries are removed.
+// It returns the entries that were removed.
+func (cm *CacheManager) Prune(opts *CachePruneOptions) ([]CacheEntry, error) {
+	if opts == nil {
+		opts = &CachePruneOptions{}
+	}
+	entries, err := cm.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []CacheEntry
+	var ownedSize int64
+	for _, e := range entries {
+		if e.Links > 1 {
+			continue
+		}
+		owned = append(owned, e)
+		ownedSize += e.Size
+	}
+
+	all := opts.OlderThan.IsZero() && opts.MaxSize <= 0
+	var removed []CacheEntry
+	var lastErr error
+	// owned is sorted from the least recently used entry
+	for _, e := range owned {
+		expired := !opts.OlderThan.IsZero() && e.ModTime.Before(opts.OlderThan)
+		tooBig := opts.MaxSize > 0 && ownedSize > opts.MaxSize
+		if !all && !expired && !tooBig {
+			continue
+		}
+		if err := osRemove(cm.path(e.Key)); err != nil {
+			if !os.IsNotExist(err) {
+				logger.Noticef("cannot cleanup cache: %s", err)
+				lastErr = err
+				continue
+			}
+		}
+		ownedSize -= e.Size
+		removed = append(removed, e)
+	}
+	return removed, lastErr
+}
@@ -24,6 +24,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	. "gopkg.in/check.v1"
@@ -227,3 +228,111 @@ func (s *cacheSuite) TestCacheHitOnErrExist(c *C) {
 	cacheHit := s.cm.Get("foo", targetPath)
 	c.Assert(cacheHit, Equals, true)
 }
+
+func (s *cacheSuite) makeAgedTestFiles(c *C, ages ...time.Duration) (cacheKeys []string, testFiles []string) {
+	now := time.Now()
+	for i, age := range ages {
+		p := s.makeTestFile(c, fmt.Sprintf("f%d", i), strings.Repeat("x", 10*(i+1)))
+		cacheKey := fmt.Sprintf("cacheKey-%d", i)
+		c.Assert(s.cm.Put(cacheKey, p), IsNil)
+		mtime := now.Add(-age)
+		c.Assert(os.Chtimes(filepath.Join(s.cm.CacheDir(), cacheKey), mtime, mtime), IsNil)
+		cacheKeys = append(cacheKeys, cacheKey)
+		testFiles = append(testFiles, p)
+	}
+	return cacheKeys, testFiles
+}
+
+func (s *cacheSuite) TestEntries(c *C) {
+	cacheKeys, testFiles := s.makeAgedTestFiles(c, time.Hour, 3*time.Hour, 2*time.Hour)
+	c.Assert(os.Remove(testFiles[0]), IsNil)
+
+	entries, err := s.cm.Entries()
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 3)
+	// least recently used first
+	c.Check(entries[0].Key, Equals, cacheKeys[1])
+	c.Check(entries[0].Size, Equals, int64(20))
+	c.Check(entries[0].Links, Equals, uint64(2))
+	c.Check(entries[1].Key, Equals, cacheKeys[2])
+	c.Check(entries[2].Key, Equals, cacheKeys[0])
+	c.Check(entries[2].Size, Equals, int64(10))
+	c.Check(entries[2].Links, Equals, uint64(1))
+	c.Check(entries[1].ModTime.Before(entries[2].ModTime), Equals, true)
+}
+
+func (s *cacheSuite) TestEntriesNoCacheDir(c *C) {
+	cm := store.NewCacheManager(filepath.Join(c.MkDir(), "missing"), 1)
+	entries, err := cm.Entries()
+	c.Assert(err, IsNil)
+	c.Check(entries, HasLen, 0)
+}
+
+func (s *cacheSuite) TestPruneAll(c *C) {
+	cacheKeys, testFiles := s.makeAgedTestFiles(c, time.Hour, 2*time.Hour, 3*time.Hour)
+	// the first entry is still referenced
+	for _, p := range testFiles[1:] {
+		c.Assert(os.Remove(p), IsNil)
+	}
+
+	removed, err := s.cm.Prune(nil)
+	c.Assert(err, IsNil)
+	c.Assert(removed, HasLen, 2)
+	c.Check(removed[0].Key, Equals, cacheKeys[2])
+	c.Check(removed[1].Key, Equals, cacheKeys[1])
+	c.Check(s.cm.Count(), Equals, 1)
+	c.Check(filepath.Join(s.cm.CacheDir(), cacheKeys[0]), testutil.FilePresent)
+}
+
+func (s *cacheSuite) TestPruneOlderThan(c *C) {
+	cacheKeys, testFiles := s.makeAgedTestFiles(c, time.Hour, 48*time.Hour, 72*time.Hour, 96*time.Hour)
+	// the oldest entry is still referenced
+	for _, p := range testFiles[:3] {
+		c.Assert(os.Remove(p), IsNil)
+	}
+
+	removed, err := s.cm.Prune(&store.CachePruneOptions{
+		OlderThan: time.Now().Add(-24 * time.Hour),
+	})
+	c.Assert(err, IsNil)
+	c.Assert(removed, HasLen, 2)
+	c.Check(removed[0].Key, Equals, cacheKeys[2])
+	c.Check(removed[1].Key, Equals, cacheKeys[1])
+	c.Check(filepath.Join(s.cm.CacheDir(), cacheKeys[0]), testutil.FilePresent)
+	c.Check(filepath.Join(s.cm.CacheDir(), cacheKeys[3]), testutil.FilePresent)
+}
+
+func (s *cacheSuite) TestPruneMaxSize(c *C) {
+	// sizes are 10, 20, 30 and 40 bytes
+	cacheKeys, testFiles := s.makeAgedTestFiles(c, time.Hour, 2*time.Hour, 3*time.Hour, 4*time.Hour)
+	for _, p := range testFiles {
+		c.Assert(os.Remove(p), IsNil)
+	}
+
+	removed, err := s.cm.Prune(&store.CachePruneOptions{MaxSize: 35})
+	c.Assert(err, IsNil)
+	c.Assert(removed, HasLen, 2)
+	c.Check(removed[0].Key, Equals, cacheKeys[3])
+	c.Check(removed[1].Key, Equals, cacheKeys[2])
+	c.Check(s.cm.Count(), Equals, 2)
+}
+
+func (s *cacheSuite) TestPruneContinuesOnError(c *C) {
+	cacheKeys, testFiles := s.makeAgedTestFiles(c, time.Hour, 2*time.Hour)
+	for _, p := range testFiles {
+		c.Assert(os.Remove(p), IsNil)
+	}
+
+	restore := store.MockOsRemove(func(name string) error {
+		if strings.HasSuffix(name, cacheKeys[1]) {
+			return fmt.Errorf("simulated error")
+		}
+		return os.Remove(name)
+	})
+	defer restore()
+
+	removed, err := s.cm.Prune(nil)
+	c.Check(err, ErrorMatches, "simulated error")
+	c.Assert(removed, HasLen, 1)
+	c.Check(removed[0].Key, Equals, cacheKeys[0])
+}
@@ -47,7 +47,8 @@ type storeSnap struct {
 	Prices        map[string]string   `json:"prices"` // currency->price,  free: {"USD": "0"}
 	Private       bool                `json:"private"`
 	Publisher     snap.StoreAccount   `json:"publisher"`
-	Revision      int                 `json:"revision"` // store revisions are ints starting at 1
+	ReleaseNotes  safejson.Paragraph  `json:"release-notes"` // optional
+	Revision      int                 `json:"revision"`      // store revisions are ints starting at 1
 	SnapID        string              `json:"snap-id"`
 	SnapYAML      string              `json:"snap-yaml"` // optional
 	Summary       safejson.String     `json:"summary"`
@@ -229,6 +230,9 @@ func copyNonZeroFrom(src, dst *storeSnap) {
 	if src.Publisher.ID != "" {
 		dst.Publisher = src.Publisher
 	}
+	if src.ReleaseNotes.Clean() != "" {
+		dst.ReleaseNotes = src.ReleaseNotes
+	}
 	if src.Revision > 0 {
 		dst.Revision = src.Revision
 	}
@@ -285,6 +289,7 @@ func infoFromStoreSnap(d *storeSnap) (*snap.Info, error) {
 
 	info.EditedSummary = d.Summary.Clean()
 	info.EditedDescription = d.Description.Clean()
+	info.ReleaseNotes = d.ReleaseNotes.Clean()
 	info.Private = d.Private
 	// needs to be set for old snapd
 	info.LegacyEditedContact = d.Contact
@@ -326,6 +331,11 @@ func infoFromStoreSnap(d *storeSnap) (*snap.Info, error) {
 		info.LegacyWebsite = d.Website
 	}
 	info.StoreURL = d.StoreURL
+	if d.CreatedAt != "" {
+		if createdAt, err := time.Parse(time.RFC3339, d.CreatedAt); err == nil {
+			info.CreatedAt = createdAt.UTC()
+		}
+	}
 
 	// convert prices
 	if len(d.Prices) > 0 {
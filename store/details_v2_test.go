@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"reflect"
 	"strings"
+	"time"
 
 	. "gopkg.in/check.v1"
 
@@ -128,6 +129,7 @@ const (
     "issues": ["mailto:bugs@thingy.com"],
     "empty": []
   },
+  "release-notes": "Thinging is now faster",
   "revision": 21,
   "snap-id": "XYZEfjn4WJYnm0FzDKwqqRZZI77awQEV",
   "snap-yaml": "name: test-snapd-content-plug\nversion: 1.0\nassumes: [snapd2.49]\napps:\n    user-svc:\n        command: bin/user-svc\n        daemon-scope: user\n        daemon: simple\n    content-plug:\n        command: bin/content-plug\n        plugs: [shared-content-plug]\nplugs:\n    shared-content-plug:\n        interface: content\n        target: import\n        content: mylib\n        default-provider: test-snapd-content-slot\nslots:\n    shared-content-slot:\n        interface: content\n        content: mylib\n        read:\n            - /\nprovenance: prov\n",
@@ -193,6 +195,7 @@ func (s *detailsV2Suite) TestInfoFromStoreSnapSimpleAndLegacy(c *C) {
 		},
 		LegacyWebsite: "http://example.com/core",
 		StoreURL:      "https://snapcraft.io/core",
+		CreatedAt:     time.Date(2018, 1, 22, 7, 49, 19, 440720000, time.UTC),
 	})
 }
 
@@ -277,6 +280,8 @@ func (s *detailsV2Suite) TestInfoFromStoreSnap(c *C) {
 			{Featured: false, Name: "productivity"},
 		},
 		StoreURL:       "https://snapcraft.io/thingy",
+		CreatedAt:      time.Date(2018, 1, 26, 11, 38, 35, 536410000, time.UTC),
+		ReleaseNotes:   "Thinging is now faster",
 		SnapProvenance: "prov",
 		// empty
 		BadInterfaces:   map[string]string{},
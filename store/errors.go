@@ -37,6 +37,9 @@ var (
 	// ErrInvalidScope is returned from Find when an invalid scope is requested.
 	ErrInvalidScope = errors.New("invalid scope")
 
+	// ErrInvalidSort is returned from Find when an invalid sort order is requested.
+	ErrInvalidSort = errors.New("invalid sort order")
+
 	// ErrSnapNotFound is returned when a snap can not be found
 	ErrSnapNotFound = errors.New("snap not found")
 
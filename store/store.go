@@ -470,7 +470,9 @@ func (s *Store) newHTTPClient(opts *httputil.ClientOptions) *http.Client {
 	if opts == nil {
 		opts = &httputil.ClientOptions{}
 	}
-	opts.Proxy = s.cfg.Proxy
+	if opts.Proxy == nil {
+		opts.Proxy = s.cfg.Proxy
+	}
 	opts.ProxyConnectHeader = s.proxyConnectHeader
 	opts.ExtraSSLCerts = &httputil.ExtraSSLCertsFromDir{
 		Dir: dirs.SnapdStoreSSLCertsDir,
@@ -1052,6 +1054,10 @@ type Search struct {
 	Category string
 	Private  bool
 	Scope    string
+
+	// Sort is the order of the results, either "recent" or "popular",
+	// by relevance if unset. It is only supported by search v2.
+	Sort string
 }
 
 // Find finds  (installable) snaps from the store, matching the
@@ -1096,6 +1102,15 @@ func (s *Store) Find(ctx context.Context, search *Search, user *auth.UserState)
 		q.Set("category", search.Category)
 	}
 
+	switch search.Sort {
+	case "":
+		// by relevance
+	case "recent", "popular":
+		q.Set("sort", search.Sort)
+	default:
+		return nil, ErrInvalidSort
+	}
+
 	// with search v2 all risks are searched by default (same as scope=wide
 	// with v1) so we need to restrict channel if scope is not passed.
 	if search.Scope == "" {
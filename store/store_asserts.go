@@ -31,8 +31,10 @@ import (
 	"strconv"
 
 	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/asserts/sysdb"
 	"github.com/snapcore/snapd/httputil"
 	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/strutil"
 )
 
 func (s *Store) assertionsEndpointURL(p string, query url.Values) (*url.URL, error) {
@@ -257,3 +259,84 @@ func (s *Store) DownloadAssertions(streamURLs []string, b *asserts.Batch, user *
 	}
 	return nil
 }
+
+// AssertionQuerier is the subset of the store methods used to query
+// assertions, see QueryAssertion.
+type AssertionQuerier interface {
+	Assertion(assertType *asserts.AssertionType, primaryKey []string, user *auth.UserState) (asserts.Assertion, error)
+	SeqFormingAssertion(assertType *asserts.AssertionType, sequenceKey []string, sequence int, user *auth.UserState) (asserts.Assertion, error)
+}
+
+// QueryAssertion retrieves from the store the assertion of the given type
+// matching the given headers. The headers must include the primary key of
+// the assertion, except for the sequence of sequence-forming assertions, in
+// which case the latest sequence is retrieved. Any other header must match
+// the one of the retrieved assertion.
+//
+// With withPrerequisites the prerequisites of the assertion, such as the
+// account and account-key of its signer, are retrieved as well, recursively,
+// and returned before the assertion itself. Trusted assertions are omitted.
+func QueryAssertion(sto AssertionQuerier, assertType *asserts.AssertionType, headers map[string]string, withPrerequisites bool, user *auth.UserState) ([]asserts.Assertion, error) {
+	var a asserts.Assertion
+	if assertType.SequenceForming() && headers["sequence"] == "" {
+		n := len(assertType.PrimaryKey) - 1
+		seqKey := make([]string, n)
+		for i, k := range assertType.PrimaryKey[:n] {
+			seqKey[i] = headers[k]
+			if seqKey[i] == "" {
+				return nil, fmt.Errorf("cannot query remote assertion: must provide primary key: %v", k)
+			}
+		}
+		var err error
+		a, err = sto.SeqFormingAssertion(assertType, seqKey, 0, user)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		primaryKey, err := asserts.PrimaryKeyFromHeaders(assertType, headers)
+		if err != nil {
+			return nil, fmt.Errorf("cannot query remote assertion: %v", err)
+		}
+		a, err = sto.Assertion(assertType, primaryKey, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for k, v := range headers {
+		if strutil.ListContains(assertType.PrimaryKey, k) {
+			continue
+		}
+		if hv, ok := a.Header(k).(string); !ok || hv != v {
+			return nil, &asserts.NotFoundError{
+				Type:    assertType,
+				Headers: headers,
+			}
+		}
+	}
+
+	if !withPrerequisites {
+		return []asserts.Assertion{a}, nil
+	}
+
+	db, err := asserts.OpenDatabase(&asserts.DatabaseConfig{
+		Backstore: asserts.NewMemoryBackstore(),
+		Trusted:   sysdb.Trusted(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var assertions []asserts.Assertion
+	retrieve := func(ref *asserts.Ref) (asserts.Assertion, error) {
+		return sto.Assertion(ref.Type, ref.PrimaryKey, user)
+	}
+	save := func(a asserts.Assertion) error {
+		assertions = append(assertions, a)
+		return nil
+	}
+	f := asserts.NewFetcher(db, retrieve, save)
+	if err := f.Save(a); err != nil {
+		return nil, err
+	}
+	return assertions, nil
+}
@@ -32,6 +32,8 @@ import (
 
 	"github.com/snapcore/snapd/asserts"
 	"github.com/snapcore/snapd/asserts/assertstest"
+	"github.com/snapcore/snapd/asserts/sysdb"
+	"github.com/snapcore/snapd/overlord/auth"
 	"github.com/snapcore/snapd/store"
 )
 
@@ -623,3 +625,156 @@ func (s *storeAssertsSuite) TestSeqFormingAssertionNotFound(c *C) {
 		},
 	})
 }
+
+type fakeAssertionQuerier struct {
+	db *asserts.Database
+	// types of assertions to report as not found
+	missing []*asserts.AssertionType
+
+	queries []string
+}
+
+func (q *fakeAssertionQuerier) Assertion(assertType *asserts.AssertionType, primaryKey []string, user *auth.UserState) (asserts.Assertion, error) {
+	q.queries = append(q.queries, assertType.Name)
+	for _, t := range q.missing {
+		if t == assertType {
+			return nil, &asserts.NotFoundError{Type: assertType}
+		}
+	}
+	ref := &asserts.Ref{Type: assertType, PrimaryKey: primaryKey}
+	return ref.Resolve(q.db.Find)
+}
+
+func (q *fakeAssertionQuerier) SeqFormingAssertion(assertType *asserts.AssertionType, sequenceKey []string, sequence int, user *auth.UserState) (asserts.Assertion, error) {
+	q.queries = append(q.queries, assertType.Name+"[seq]")
+	hdrs, err := asserts.HeadersFromSequenceKey(assertType, sequenceKey)
+	if err != nil {
+		return nil, err
+	}
+	if sequence == 0 {
+		sequence = -1
+	}
+	return q.db.FindSequence(assertType, hdrs, sequence, -1)
+}
+
+func (s *storeAssertsSuite) fakeQuerier(c *C) *fakeAssertionQuerier {
+	c.Assert(s.db.Add(s.storeSigning.StoreAccountKey("")), IsNil)
+	c.Assert(s.db.Add(s.dev1Acct), IsNil)
+	c.Assert(s.db.Add(s.decl1), IsNil)
+	return &fakeAssertionQuerier{db: s.db}
+}
+
+func (s *storeAssertsSuite) TestQueryAssertion(c *C) {
+	q := s.fakeQuerier(c)
+
+	as, err := store.QueryAssertion(q, asserts.SnapDeclarationType, map[string]string{
+		"series":  "16",
+		"snap-id": "asnapid",
+	}, false, nil)
+	c.Assert(err, IsNil)
+	c.Check(as, DeepEquals, []asserts.Assertion{s.decl1})
+	c.Check(q.queries, DeepEquals, []string{"snap-declaration"})
+}
+
+func (s *storeAssertsSuite) TestQueryAssertionOtherHeaders(c *C) {
+	q := s.fakeQuerier(c)
+
+	as, err := store.QueryAssertion(q, asserts.SnapDeclarationType, map[string]string{
+		"series":    "16",
+		"snap-id":   "asnapid",
+		"snap-name": "asnap",
+	}, false, nil)
+	c.Assert(err, IsNil)
+	c.Check(as, DeepEquals, []asserts.Assertion{s.decl1})
+
+	_, err = store.QueryAssertion(q, asserts.SnapDeclarationType, map[string]string{
+		"series":    "16",
+		"snap-id":   "asnapid",
+		"snap-name": "other",
+	}, false, nil)
+	c.Check(errors.Is(err, &asserts.NotFoundError{}), Equals, true)
+}
+
+func (s *storeAssertsSuite) TestQueryAssertionMissingPrimaryKey(c *C) {
+	q := s.fakeQuerier(c)
+
+	_, err := store.QueryAssertion(q, asserts.SnapDeclarationType, map[string]string{
+		"series": "16",
+	}, false, nil)
+	c.Check(err, ErrorMatches, `cannot query remote assertion: must provide primary key: snap-id`)
+
+	_, err = store.QueryAssertion(q, asserts.ValidationSetType, map[string]string{
+		"series":     "16",
+		"account-id": "developer1",
+	}, false, nil)
+	c.Check(err, ErrorMatches, `cannot query remote assertion: must provide primary key: name`)
+	c.Check(q.queries, HasLen, 0)
+}
+
+func (s *storeAssertsSuite) TestQueryAssertionLatestSequence(c *C) {
+	q := s.fakeQuerier(c)
+
+	for _, seq := range []string{"1", "2"} {
+		vs, err := s.storeSigning.Sign(asserts.ValidationSetType, map[string]interface{}{
+			"authority-id": "can0nical",
+			"account-id":   "can0nical",
+			"series":       "16",
+			"name":         "base-set",
+			"sequence":     seq,
+			"snaps": []interface{}{
+				map[string]interface{}{
+					"name": "asnap",
+					"id":   "asnapidasnapidasnapidasnapidasna",
+				},
+			},
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		}, nil, "")
+		c.Assert(err, IsNil)
+		c.Assert(s.db.Add(vs), IsNil)
+	}
+
+	as, err := store.QueryAssertion(q, asserts.ValidationSetType, map[string]string{
+		"series":     "16",
+		"account-id": "can0nical",
+		"name":       "base-set",
+	}, false, nil)
+	c.Assert(err, IsNil)
+	c.Assert(as, HasLen, 1)
+	c.Check(as[0].(*asserts.ValidationSet).Sequence(), Equals, 2)
+	c.Check(q.queries, DeepEquals, []string{"validation-set[seq]"})
+}
+
+func (s *storeAssertsSuite) TestQueryAssertionWithPrerequisites(c *C) {
+	restore := sysdb.InjectTrusted(s.storeSigning.Trusted)
+	defer restore()
+
+	q := s.fakeQuerier(c)
+
+	as, err := store.QueryAssertion(q, asserts.SnapDeclarationType, map[string]string{
+		"series":  "16",
+		"snap-id": "asnapid",
+	}, true, nil)
+	c.Assert(err, IsNil)
+	c.Check(as, DeepEquals, []asserts.Assertion{
+		s.storeSigning.StoreAccountKey(""),
+		s.dev1Acct,
+		s.decl1,
+	})
+	// trusted assertions are not retrieved
+	c.Check(q.queries, DeepEquals, []string{"snap-declaration", "account", "account-key"})
+}
+
+func (s *storeAssertsSuite) TestQueryAssertionWithPrerequisitesNotFound(c *C) {
+	restore := sysdb.InjectTrusted(s.storeSigning.Trusted)
+	defer restore()
+
+	q := s.fakeQuerier(c)
+	// the publisher account is not available
+	q.missing = []*asserts.AssertionType{asserts.AccountType}
+
+	_, err := store.QueryAssertion(q, asserts.SnapDeclarationType, map[string]string{
+		"series":  "16",
+		"snap-id": "asnapid",
+	}, true, nil)
+	c.Check(errors.Is(err, &asserts.NotFoundError{}), Equals, true)
+}
@@ -185,6 +185,9 @@ type DownloadOptions struct {
 	RateLimit           int64
 	Scheduled           bool
 	LeavePartialOnError bool
+	// Proxy, if set, overrides the store's proxy configuration for the
+	// download.
+	Proxy func(*http.Request) (*url.URL, error)
 }
 
 // Download downloads the snap addressed by download info and returns its
@@ -280,7 +283,7 @@ func (s *Store) Download(ctx context.Context, name string, targetPath string, do
 		if err != nil {
 			return err
 		}
-		err = download(ctx, name, downloadInfo.Sha3_384, url, user, s, w, 0, pbar, nil)
+		err = download(ctx, name, downloadInfo.Sha3_384, url, user, s, w, 0, pbar, dlOpts)
 		if err != nil {
 			logger.Debugf("download of %q failed: %#v", url, err)
 		}
@@ -478,7 +481,7 @@ func downloadImpl(ctx context.Context, name, sha3_384, downloadURL string, user
 			return fmt.Errorf("the download has been cancelled: %s", downloadCtx.Err())
 		}
 		var resp *http.Response
-		cli := s.newHTTPClient(nil)
+		cli := s.newHTTPClient(&httputil.ClientOptions{Proxy: dlOpts.Proxy})
 		oldCheckRedirect := cli.CheckRedirect
 		if oldCheckRedirect == nil {
 			panic("internal error: the httputil.NewHTTPClient-produced http.Client must have CheckRedirect defined")
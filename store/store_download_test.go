@@ -994,6 +994,29 @@ func (s *storeDownloadSuite) TestDownloadRedirectHideAuthHeaders(c *C) {
 	c.Assert(targetFn, testutil.FileEquals, "test-download")
 }
 
+func (s *storeDownloadSuite) TestDownloadProxyOverride(c *C) {
+	mockProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Host, Equals, "store.example.com")
+		io.WriteString(w, "proxied-download")
+	}))
+	c.Assert(mockProxy, NotNil)
+	defer mockProxy.Close()
+
+	proxyURL, err := url.Parse(mockProxy.URL)
+	c.Assert(err, IsNil)
+
+	snap := &snap.Info{}
+	snap.DownloadURL = "http://store.example.com/download"
+
+	dlOpts := &store.DownloadOptions{
+		Proxy: func(*http.Request) (*url.URL, error) { return proxyURL, nil },
+	}
+	targetFn := filepath.Join(c.MkDir(), "foo_1.0_all.snap")
+	err = s.store.Download(s.ctx, "foo", targetFn, &snap.DownloadInfo, nil, s.user, dlOpts)
+	c.Assert(err, IsNil)
+	c.Assert(targetFn, testutil.FileEquals, "proxied-download")
+}
+
 func (s *storeDownloadSuite) TestDownloadNoCheckRedirectPanic(c *C) {
 	restore := store.MockHttputilNewHTTPClient(func(opts *httputil.ClientOptions) *http.Client {
 		client := httputil.NewHTTPClient(opts)
@@ -2767,7 +2767,7 @@ func (s *storeTestSuite) TestFindV2FindFields(c *C) {
 	c.Assert(findFields, DeepEquals, []string{
 		"base", "categories", "channel", "common-ids", "confinement", "contact",
 		"description", "download", "license", "links", "media", "prices", "private",
-		"publisher", "revision", "store-url", "summary", "title", "type",
+		"publisher", "release-notes", "revision", "store-url", "summary", "title", "type",
 		"version", "website"})
 }
 
@@ -2911,6 +2911,58 @@ func (s *storeTestSuite) TestFindInvalidScope(c *C) {
 	c.Check(err, Equals, store.ErrInvalidScope)
 }
 
+func (s *storeTestSuite) TestFindInvalidSort(c *C) {
+	sto := store.New(&store.Config{StoreBaseURL: new(url.URL)}, nil)
+	_, err := sto.Find(s.ctx, &store.Search{Query: "foo", Sort: "oldest"}, nil)
+	c.Check(err, Equals, store.ErrInvalidSort)
+}
+
+func (s *storeTestSuite) TestFindV2Sort(c *C) {
+	n := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertRequest(c, r, "GET", findPath)
+		query := r.URL.Query()
+
+		switch n {
+		case 0:
+			c.Check(query.Get("category"), Equals, "db")
+			c.Check(query.Get("sort"), Equals, "popular")
+		case 1:
+			c.Check(query.Get("q"), Equals, "hello")
+			c.Check(query.Get("sort"), Equals, "recent")
+		case 2:
+			c.Check(query.Get("q"), Equals, "hello")
+			c.Check(query["sort"], IsNil)
+		default:
+			c.Fatalf("expected 3 queries, now on %d", n+1)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		io.WriteString(w, mockSearchJSONv2)
+
+		n++
+	}))
+	c.Assert(mockServer, NotNil)
+	defer mockServer.Close()
+
+	serverURL, _ := url.Parse(mockServer.URL)
+	cfg := store.Config{
+		StoreBaseURL: serverURL,
+	}
+	sto := store.New(&cfg, nil)
+
+	for _, search := range []store.Search{
+		{Category: "db", Sort: "popular"},
+		{Query: "hello", Sort: "recent"},
+		{Query: "hello"},
+	} {
+		_, err := sto.Find(s.ctx, &search, nil)
+		c.Check(err, IsNil)
+	}
+	c.Check(n, Equals, 3)
+}
+
 func (s *storeTestSuite) testFindFails(c *C, apiV1 bool) {
 	var v1Fallback, v2Hit bool
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
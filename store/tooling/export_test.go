@@ -38,6 +38,18 @@ func (tsto *ToolingStore) StoreURL() *url.URL {
 	return tsto.cfg.StoreBaseURL
 }
 
+func (tsto *ToolingStore) Architecture() string {
+	return tsto.architecture()
+}
+
+func (tsto *ToolingStore) Series() string {
+	return tsto.cfg.Series
+}
+
+func MockArchitecture(tsto *ToolingStore, architecture string) {
+	tsto.cfg = &store.Config{Architecture: architecture}
+}
+
 func (opts *DownloadSnapOptions) Validate() error {
 	return opts.validate()
 }
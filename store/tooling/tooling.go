@@ -29,9 +29,11 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
 
+	"github.com/snapcore/snapd/arch"
 	"github.com/snapcore/snapd/asserts"
 	"github.com/snapcore/snapd/asserts/snapasserts"
 	"github.com/snapcore/snapd/logger"
@@ -78,9 +80,10 @@ type StoreImpl interface {
 	SetAssertionMaxFormats(maxFormats map[string]int)
 }
 
-func newToolingStore(arch, storeID string) (*ToolingStore, error) {
+func newToolingStore(arch, series, storeID string) (*ToolingStore, error) {
 	cfg := store.DefaultConfig()
 	cfg.Architecture = arch
+	cfg.Series = series
 	cfg.StoreID = storeID
 	creds, err := getAuthorizer()
 	if err != nil {
@@ -108,7 +111,7 @@ func NewToolingStoreFromModel(model *asserts.Model, fallbackArchitecture string)
 	if architecture == "" {
 		architecture = fallbackArchitecture
 	}
-	return newToolingStore(architecture, model.Store())
+	return newToolingStore(architecture, "", model.Store())
 }
 
 // NewToolingStore creates ToolingStore, with optional arch and store id
@@ -116,7 +119,74 @@ func NewToolingStoreFromModel(model *asserts.Model, fallbackArchitecture string)
 func NewToolingStore() (*ToolingStore, error) {
 	arch := os.Getenv("UBUNTU_STORE_ARCH")
 	storeID := os.Getenv("UBUNTU_STORE_ID")
-	return newToolingStore(arch, storeID)
+	return newToolingStore(arch, "", storeID)
+}
+
+// NewToolingStoreForArchitecture creates ToolingStore querying the store
+// for snaps of the given architecture and series, which need not be the
+// ones of the host, e.g. to fetch snaps when building images for other
+// architectures. If empty, the architecture is read from the
+// UBUNTU_STORE_ARCH environment variable and the series is the default one.
+// The store id is read from the UBUNTU_STORE_ID environment variable.
+func NewToolingStoreForArchitecture(architecture, series string) (*ToolingStore, error) {
+	if architecture == "" {
+		architecture = os.Getenv("UBUNTU_STORE_ARCH")
+	}
+	storeID := os.Getenv("UBUNTU_STORE_ID")
+	return newToolingStore(architecture, series, storeID)
+}
+
+// architecture returns the architecture the store is queried for.
+func (tsto *ToolingStore) architecture() string {
+	if tsto.cfg != nil && tsto.cfg.Architecture != "" {
+		return tsto.cfg.Architecture
+	}
+	return arch.DpkgArchitecture()
+}
+
+// ArchitectureNotAvailableError is returned when a snap is not published
+// for the architecture the store is queried for.
+type ArchitectureNotAvailableError struct {
+	SnapName     string
+	Architecture string
+	// Available lists the architectures the snap is published for.
+	Available []string
+}
+
+func (e *ArchitectureNotAvailableError) Error() string {
+	return fmt.Sprintf("snap %q is not available for architecture %q (available for: %s)", e.SnapName, e.Architecture, strings.Join(e.Available, ", "))
+}
+
+// snapActionError returns a more specific error than the store one if the
+// snap is not published for the architecture the store is queried for.
+func (tsto *ToolingStore) snapActionError(name string, err error) error {
+	saErr, ok := err.(*store.SnapActionError)
+	if !ok {
+		return err
+	}
+	_, _, opErr := saErr.SingleOpError()
+	rnaErr, ok := opErr.(*store.RevisionNotAvailableError)
+	if !ok || len(rnaErr.Releases) == 0 {
+		return err
+	}
+	thisArch := tsto.architecture()
+	var available []string
+	for _, release := range rnaErr.Releases {
+		if release.Architecture == thisArch {
+			// available for the architecture, the error is about
+			// something else, e.g. the channel
+			return err
+		}
+		if !strutil.ListContains(available, release.Architecture) {
+			available = append(available, release.Architecture)
+		}
+	}
+	sort.Strings(available)
+	return &ArchitectureNotAvailableError{
+		SnapName:     name,
+		Architecture: thisArch,
+		Available:    available,
+	}
 }
 
 // DownloadSnapOptions carries options for downloading snaps plus assertions.
@@ -209,7 +279,7 @@ func (tsto *ToolingStore) DownloadSnap(name string, opts DownloadSnapOptions) (d
 	sars, _, err := sto.SnapAction(context.TODO(), nil, actions, nil, nil, nil)
 	if err != nil {
 		// err will be 'cannot download snap "foo": <reasons>'
-		return nil, err
+		return nil, tsto.snapActionError(name, err)
 	}
 	sar := &sars[0]
 
@@ -22,6 +22,7 @@ package tooling_test
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -42,6 +43,8 @@ import (
 	"github.com/snapcore/snapd/progress"
 	"github.com/snapcore/snapd/seed/seedtest"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/channel"
+	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/store"
 	"github.com/snapcore/snapd/store/tooling"
 	"github.com/snapcore/snapd/testutil"
@@ -59,6 +62,8 @@ type toolingSuite struct {
 
 	assertMaxFormats map[string]int
 
+	snapActionErr error
+
 	tsto *tooling.ToolingStore
 
 	// SeedSnaps helps creating and making available seed snaps
@@ -124,6 +129,26 @@ func (s *toolingSuite) TestNewToolingStore(c *C) {
 	c.Check(tsto.StoreURL(), DeepEquals, u)
 }
 
+func (s *toolingSuite) TestNewToolingStoreForArchitecture(c *C) {
+	tsto, err := tooling.NewToolingStoreForArchitecture("riscv64", "18")
+	c.Assert(err, IsNil)
+	c.Check(tsto.Architecture(), Equals, "riscv64")
+	c.Check(tsto.Series(), Equals, "18")
+
+	os.Setenv("UBUNTU_STORE_ARCH", "s390x")
+	defer os.Unsetenv("UBUNTU_STORE_ARCH")
+
+	// the architecture given explicitly takes precedence
+	tsto, err = tooling.NewToolingStoreForArchitecture("arm64", "")
+	c.Assert(err, IsNil)
+	c.Check(tsto.Architecture(), Equals, "arm64")
+	c.Check(tsto.Series(), Equals, "")
+
+	tsto, err = tooling.NewToolingStoreForArchitecture("", "")
+	c.Assert(err, IsNil)
+	c.Check(tsto.Architecture(), Equals, "s390x")
+}
+
 func (s *toolingSuite) TestNewToolingStoreUbuntuStoreURL(c *C) {
 	u, err := url.Parse("https://api.other")
 	c.Assert(err, IsNil)
@@ -393,6 +418,48 @@ func (s *toolingSuite) TestDownloadSnap(c *C) {
 	c.Check(logbuf.String(), Matches, `.* DEBUG: Going to download snap "core" `+opts.String()+".\n")
 }
 
+func (s *toolingSuite) TestDownloadSnapArchitectureNotAvailable(c *C) {
+	tooling.MockArchitecture(s.tsto, "riscv64")
+
+	s.snapActionErr = &store.SnapActionError{
+		Download: map[string]error{
+			"foo": &store.RevisionNotAvailableError{
+				Action:  "download",
+				Channel: "stable",
+				Releases: []channel.Channel{
+					snaptest.MustParseChannel("stable", "arm64"),
+					snaptest.MustParseChannel("stable", "amd64"),
+					snaptest.MustParseChannel("beta", "amd64"),
+				},
+			},
+		},
+	}
+	_, err := s.tsto.DownloadSnap("foo", tooling.DownloadSnapOptions{TargetDir: c.MkDir()})
+	c.Assert(err, ErrorMatches, `snap "foo" is not available for architecture "riscv64" \(available for: amd64, arm64\)`)
+	var archErr *tooling.ArchitectureNotAvailableError
+	c.Assert(errors.As(err, &archErr), Equals, true)
+	c.Check(archErr.Available, DeepEquals, []string{"amd64", "arm64"})
+}
+
+func (s *toolingSuite) TestDownloadSnapChannelNotAvailable(c *C) {
+	tooling.MockArchitecture(s.tsto, "amd64")
+
+	s.snapActionErr = &store.SnapActionError{
+		Download: map[string]error{
+			"foo": &store.RevisionNotAvailableError{
+				Action:  "download",
+				Channel: "edge",
+				Releases: []channel.Channel{
+					snaptest.MustParseChannel("stable", "amd64"),
+				},
+			},
+		},
+	}
+	_, err := s.tsto.DownloadSnap("foo", tooling.DownloadSnapOptions{TargetDir: c.MkDir()})
+	// the store error is returned as is
+	c.Assert(err, Equals, s.snapActionErr)
+}
+
 func (s *toolingSuite) TestSetAssertionMaxFormats(c *C) {
 	c.Check(s.tsto.AssertionMaxFormats(), IsNil)
 
@@ -414,6 +481,10 @@ func (s *toolingSuite) SnapAction(_ context.Context, curSnaps []*store.CurrentSn
 		return nil, nil, fmt.Errorf("unexpected assertion query")
 	}
 
+	if s.snapActionErr != nil {
+		return nil, nil, s.snapActionErr
+	}
+
 	s.storeActionsBunchSizes = append(s.storeActionsBunchSizes, len(actions))
 	s.curSnaps = append(s.curSnaps, curSnaps)
 	sars := make([]store.SnapActionResult, 0, len(actions))
@@ -34,6 +34,7 @@ import (
 
 	"github.com/snapcore/snapd/asserts"
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/gadget"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/strutil"
@@ -104,22 +105,59 @@ var supportedFilteredDatasources = []string{
 	"MAAS",
 }
 
+// supportedFilteredCloudConfigKeys are the top-level keys of
+// supportedFilteredCloudConfig.
+var supportedFilteredCloudConfigKeys = []string{
+	"datasource",
+	"network",
+	"datasource_list",
+	"reporting",
+}
+
+// CloudInitFilteredFile describes what was dropped from a single cloud-init
+// config file when filtering it.
+type CloudInitFilteredFile struct {
+	// Name is the base name of the config file.
+	Name string `json:"name"`
+	// DroppedDatasources is the set of datasources whose config was removed.
+	DroppedDatasources []string `json:"dropped-datasources,omitempty"`
+	// DroppedKeys is the set of top-level keys (config modules) that were
+	// removed.
+	DroppedKeys []string `json:"dropped-keys,omitempty"`
+	// Skipped is true if nothing was left of the file after filtering and it
+	// was not installed at all.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// CloudInitFilterReport records how cloud-init config from ubuntu-seed was
+// filtered when it was installed.
+type CloudInitFilterReport struct {
+	// AllowedDatasources is the effective set of datasources config was
+	// allowed for.
+	AllowedDatasources []string `json:"allowed-datasources"`
+	// AllowedModules is the set of additional top-level keys that the gadget
+	// allowed.
+	AllowedModules []string `json:"allowed-modules,omitempty"`
+	// Files holds the details for each file that had anything filtered.
+	Files []CloudInitFilteredFile `json:"files,omitempty"`
+}
+
 // filterCloudCfg filters a cloud-init configuration struct parsed from a single
 // cloud-init configuration file. The config provided here may be a subset of
 // the full cloud-init configuration from the file in that there may be
 // top-level keys in the YAML file that we did not parse and as such they are
 // dropped and filtered automatically. For other keys, we must parse part of the
 // configuration struct and remove nested keys while keeping other parts of the
-// same section.
-func filterCloudCfg(cfg *supportedFilteredCloudConfig, allowedDatasources []string) error {
-	// TODO: should we track modifications / filters applied to log/notify about
-	//       what is dropped / not supported?
+// same section. The datasources that were dropped are returned.
+func filterCloudCfg(cfg *supportedFilteredCloudConfig, allowedDatasources []string) (dropped []string) {
+	droppedSet := map[string]bool{}
 
 	// first filter out the disallowed datasources
 	for dsName := range cfg.Datasource {
 		// remove unsupported or unrecognized datasources
 		if !strutil.ListContains(allowedDatasources, strings.ToUpper(dsName)) {
 			delete(cfg.Datasource, dsName)
+			droppedSet[dsName] = true
 			continue
 		}
 	}
@@ -127,6 +165,11 @@ func filterCloudCfg(cfg *supportedFilteredCloudConfig, allowedDatasources []stri
 	// next handle the datasource list setting, if it was not empty, reset it to
 	// the allowedDatasources we were provided
 	if cfg.DatasourceList != nil {
+		for _, dsName := range *cfg.DatasourceList {
+			if !strutil.ListContains(allowedDatasources, strings.ToUpper(dsName)) {
+				droppedSet[dsName] = true
+			}
+		}
 		deepCpy := make([]string, 0, len(allowedDatasources))
 		deepCpy = append(deepCpy, allowedDatasources...)
 		cfg.DatasourceList = &deepCpy
@@ -137,24 +180,35 @@ func filterCloudCfg(cfg *supportedFilteredCloudConfig, allowedDatasources []stri
 		// remove unsupported or unrecognized datasources
 		if !strutil.ListContains(allowedDatasources, strings.ToUpper(dsName)) {
 			delete(cfg.Reporting, dsName)
+			droppedSet[dsName] = true
 			continue
 		}
 	}
 
-	return nil
+	for dsName := range droppedSet {
+		dropped = append(dropped, dsName)
+	}
+	sort.Strings(dropped)
+
+	return dropped
 }
 
 // filterCloudCfgFile takes a cloud config file as input and filters out unknown
 // and unsupported keys from the config, returning a new file. It also will
 // filter out configuration that is specific to a datasource if that datasource
-// is not specified in the allowedDatasources argument. The empty string will be
-// returned if the input file was entirely filtered out and there is nothing
-// left.
-func filterCloudCfgFile(in string, allowedDatasources []string) (string, error) {
+// is not specified in the allowedDatasources argument. Top-level keys listed in
+// allowedModules are kept as they are. The empty string will be returned if
+// the input file was entirely filtered out and there is nothing left. Details
+// about what was filtered are returned as well.
+func filterCloudCfgFile(in string, allowedDatasources, allowedModules []string) (string, *CloudInitFilteredFile, error) {
+	dstFileName := filepath.Base(in)
+	filtered := &CloudInitFilteredFile{Name: dstFileName}
+
 	// we don't allow any files to be installed/filtered from ubuntu-seed if
 	// there are no datasources at all
 	if len(allowedDatasources) == 0 {
-		return "", nil
+		filtered.Skipped = true
+		return "", filtered, nil
 	}
 
 	// otherwise if there are datasources that are allowed, then we perform
@@ -168,51 +222,79 @@ func filterCloudCfgFile(in string, allowedDatasources []string) (string, error)
 	//   cloud-init files on ubuntu-seed which specify a datasource and
 	//   intersect with what we support
 
-	dstFileName := filepath.Base(in)
-	filteredFile, err := ioutil.TempFile("", dstFileName)
-	if err != nil {
-		return "", err
-	}
-	defer filteredFile.Close()
-
 	// open the source and unmarshal it as yaml
 	unfilteredFileBytes, err := ioutil.ReadFile(in)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	var cfg supportedFilteredCloudConfig
 	if err := yaml.Unmarshal(unfilteredFileBytes, &cfg); err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	if err := filterCloudCfg(&cfg, allowedDatasources); err != nil {
-		return "", err
+	// also look at all the top-level keys, to keep the allowed modules and
+	// to keep track of what is dropped
+	var allKeys yaml.MapSlice
+	if err := yaml.Unmarshal(unfilteredFileBytes, &allKeys); err != nil {
+		return "", nil, err
+	}
+	var modules yaml.MapSlice
+	for _, item := range allKeys {
+		key := fmt.Sprintf("%v", item.Key)
+		switch {
+		case strutil.ListContains(supportedFilteredCloudConfigKeys, key):
+			// handled by filterCloudCfg
+		case strutil.ListContains(allowedModules, key):
+			modules = append(modules, item)
+		default:
+			filtered.DroppedKeys = append(filtered.DroppedKeys, key)
+		}
 	}
+	sort.Strings(filtered.DroppedKeys)
+
+	filtered.DroppedDatasources = filterCloudCfg(&cfg, allowedDatasources)
 
 	// write out cfg to the filtered file now
 	b, err := yaml.Marshal(cfg)
 	if err != nil {
-		return "", err
+		return "", nil, err
+	}
+	if strings.TrimSpace(string(b)) == "{}" {
+		b = nil
+	}
+	if len(modules) != 0 {
+		mb, err := yaml.Marshal(modules)
+		if err != nil {
+			return "", nil, err
+		}
+		b = append(b, mb...)
 	}
 
 	// check if we need to write a file at all, if the yaml serialization was
 	// entirely filtered out, then we don't need to write anything
-	if strings.TrimSpace(string(b)) == "{}" {
-		return "", nil
+	if len(b) == 0 {
+		filtered.Skipped = true
+		return "", filtered, nil
 	}
 
+	filteredFile, err := ioutil.TempFile("", dstFileName)
+	if err != nil {
+		return "", nil, err
+	}
+	defer filteredFile.Close()
+
 	// add the #cloud-config prefix to all files we write
 	if _, err := filteredFile.Write([]byte("#cloud-config\n")); err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	if _, err := filteredFile.Write(b); err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	// use the newly filtered temp file as the source to copy
-	return filteredFile.Name(), nil
+	return filteredFile.Name(), filtered, nil
 }
 
 type cloudDatasourcesInUseResult struct {
@@ -365,12 +447,16 @@ type cloudInitConfigInstallOptions struct {
 	// specific to a datasource (such as networking config) is allowed to be
 	// installed.
 	AllowedDatasources []string
+	// AllowedModules is the set of additional top-level keys to keep when
+	// filtering.
+	AllowedModules []string
 }
 
 // installCloudInitCfgDir installs glob cfg files from the source directory to
 // the cloud config dir, optionally filtering the files for safe and supported
-// keys in the configuration before installing them.
-func installCloudInitCfgDir(src, targetdir string, opts *cloudInitConfigInstallOptions) (installedFiles []string, err error) {
+// keys in the configuration before installing them. When filtering, details
+// about the files that had anything filtered out are returned as well.
+func installCloudInitCfgDir(src, targetdir string, opts *cloudInitConfigInstallOptions) (installedFiles []string, filtered []CloudInitFilteredFile, err error) {
 	if opts == nil {
 		opts = &cloudInitConfigInstallOptions{}
 	}
@@ -378,15 +464,15 @@ func installCloudInitCfgDir(src, targetdir string, opts *cloudInitConfigInstallO
 	// TODO:UC20: enforce patterns on the glob files and their suffix ranges
 	ccl, err := filepath.Glob(filepath.Join(src, "*.cfg"))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if len(ccl) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	ubuntuDataCloudCfgDir := filepath.Join(ubuntuDataCloudDir(targetdir), "cloud.cfg.d/")
 	if err := os.MkdirAll(ubuntuDataCloudCfgDir, 0755); err != nil {
-		return nil, fmt.Errorf("cannot make cloud config dir: %v", err)
+		return nil, nil, fmt.Errorf("cannot make cloud config dir: %v", err)
 	}
 
 	for _, cc := range ccl {
@@ -395,11 +481,14 @@ func installCloudInitCfgDir(src, targetdir string, opts *cloudInitConfigInstallO
 		dst := filepath.Join(ubuntuDataCloudCfgDir, opts.Prefix+baseName)
 
 		if opts.Filter {
-			filteredFile, err := filterCloudCfgFile(cc, opts.AllowedDatasources)
+			filteredFile, details, err := filterCloudCfgFile(cc, opts.AllowedDatasources, opts.AllowedModules)
 			if err != nil {
-				return nil, fmt.Errorf("error while filtering cloud-config file %s: %v", baseName, err)
+				return nil, nil, fmt.Errorf("error while filtering cloud-config file %s: %v", baseName, err)
 			}
 			src = filteredFile
+			if details.Skipped || len(details.DroppedDatasources) != 0 || len(details.DroppedKeys) != 0 {
+				filtered = append(filtered, *details)
+			}
 		}
 
 		// src may be the empty string if we were copying a file that got
@@ -411,19 +500,19 @@ func installCloudInitCfgDir(src, targetdir string, opts *cloudInitConfigInstallO
 		}
 
 		if err := osutil.CopyFile(src, dst, 0); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// make sure that the new file is world readable, since cloud-init does
 		// not run as root (somehow?)
 		if err := os.Chmod(dst, 0644); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		installedFiles = append(installedFiles, dst)
 	}
 
-	return installedFiles, nil
+	return installedFiles, filtered, nil
 }
 
 // installGadgetCloudInitCfg installs a single cloud-init config file from the
@@ -448,7 +537,40 @@ func installGadgetCloudInitCfg(src, targetdir string) (*cloudDatasourcesInUseRes
 	return datasourcesRes, nil
 }
 
-func configureCloudInit(model *asserts.Model, opts *Options) (err error) {
+// writeCloudInitFilterReport saves the report about the filtered cloud-init
+// config under the given root dir, so that it can later be inspected with
+// "snap debug cloud-init".
+func writeCloudInitFilterReport(rootDir string, report *CloudInitFilterReport) error {
+	reportFile := dirs.CloudInitFilterReportFileUnder(rootDir)
+	if err := os.MkdirAll(filepath.Dir(reportFile), 0755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	return osutil.AtomicWriteFile(reportFile, b, 0644, 0)
+}
+
+// ReadCloudInitFilterReport returns the report about how cloud-init config
+// from ubuntu-seed was filtered during install. It returns nil if no
+// filtering took place.
+func ReadCloudInitFilterReport() (*CloudInitFilterReport, error) {
+	b, err := ioutil.ReadFile(dirs.CloudInitFilterReportFileUnder(dirs.GlobalRootDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var report CloudInitFilterReport
+	if err := json.Unmarshal(b, &report); err != nil {
+		return nil, fmt.Errorf("cannot decode cloud-init filter report: %v", err)
+	}
+	return &report, nil
+}
+
+func configureCloudInit(model *asserts.Model, policy *gadget.CloudInit, opts *Options) (err error) {
 	if opts.TargetRootDir == "" {
 		return fmt.Errorf("unable to configure cloud-init, missing target dir")
 	}
@@ -623,6 +745,19 @@ func configureCloudInit(model *asserts.Model, opts *Options) (err error) {
 			}
 		}
 
+		// the gadget can further restrict the datasources and can let
+		// through additional config modules
+		if policy != nil {
+			if len(policy.AllowedDatasources) != 0 {
+				gadgetAllowed := make([]string, 0, len(policy.AllowedDatasources))
+				for _, ds := range policy.AllowedDatasources {
+					gadgetAllowed = append(gadgetAllowed, strings.ToUpper(ds))
+				}
+				installOpts.AllowedDatasources = strutil.Intersection(installOpts.AllowedDatasources, gadgetAllowed)
+			}
+			installOpts.AllowedModules = policy.AllowedModules
+		}
+
 	case asserts.ModelDangerous:
 		// for grade dangerous we just install all the config from ubuntu-seed
 		installOpts.Filter = false
@@ -632,18 +767,41 @@ func configureCloudInit(model *asserts.Model, opts *Options) (err error) {
 
 	// check if we will actually be able to install anything
 	if installOpts.Filter && len(installOpts.AllowedDatasources) == 0 {
-		return nil
+		// still record that all the config was dropped
+		ccl, err := filepath.Glob(filepath.Join(opts.CloudInitSrcDir, "*.cfg"))
+		if err != nil {
+			return err
+		}
+		report := &CloudInitFilterReport{AllowedModules: installOpts.AllowedModules}
+		for _, cc := range ccl {
+			report.Files = append(report.Files, CloudInitFilteredFile{
+				Name:    filepath.Base(cc),
+				Skipped: true,
+			})
+		}
+		return writeCloudInitFilterReport(WritableDefaultsDir(opts.TargetRootDir), report)
 	}
 
 	// try installing the files, this is the case either where we are filtering
 	// and there are some files that will be filtered, or where we are not
 	// filtering and thus don't know anything about what files we might install,
 	// but we will install them all because we are in grade dangerous
-	installedFiles, err := installCloudInitCfgDir(opts.CloudInitSrcDir, WritableDefaultsDir(opts.TargetRootDir), installOpts)
+	installedFiles, filtered, err := installCloudInitCfgDir(opts.CloudInitSrcDir, WritableDefaultsDir(opts.TargetRootDir), installOpts)
 	if err != nil {
 		return err
 	}
 
+	if installOpts.Filter {
+		report := &CloudInitFilterReport{
+			AllowedDatasources: installOpts.AllowedDatasources,
+			AllowedModules:     installOpts.AllowedModules,
+			Files:              filtered,
+		}
+		if err := writeCloudInitFilterReport(WritableDefaultsDir(opts.TargetRootDir), report); err != nil {
+			return err
+		}
+	}
+
 	if installOpts.Filter && len(installedFiles) != 0 {
 		// we are filtering files and we installed some, so we also need to
 		// install a datasource restriction file at the end just as a paranoia
@@ -722,6 +880,27 @@ const (
 	CloudInitErrored
 )
 
+func (s CloudInitState) String() string {
+	switch s {
+	case CloudInitDisabledPermanently:
+		return "disabled"
+	case CloudInitRestrictedBySnapd:
+		return "restricted"
+	case CloudInitUntriggered:
+		return "untriggered"
+	case CloudInitDone:
+		return "done"
+	case CloudInitEnabled:
+		return "enabled"
+	case CloudInitNotFound:
+		return "not-found"
+	case CloudInitErrored:
+		return "error"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(s))
+	}
+}
+
 // CloudInitStatus returns the current status of cloud-init. Note that it will
 // first check for static file-based statuses first through the snapd
 // restriction file and the disabled file before consulting
@@ -837,6 +1016,23 @@ type CloudInitRestrictOptions struct {
 	// a local source, such as GCE or AWS EC2 it is merely restricted as
 	// described in the doc-comment on RestrictCloudInit.
 	DisableAfterLocalDatasourcesRun bool
+
+	// AllowedDatasources, if non-empty, is the set of datasources that
+	// cloud-init is allowed to keep using on subsequent boots, if the
+	// detected datasource is not one of them cloud-init is disabled instead
+	// of being restricted.
+	AllowedDatasources []string
+}
+
+// datasourceAllowed returns whether the datasource is in the list, datasource
+// names are compared case-insensitively like cloud-init does.
+func datasourceAllowed(allowed []string, datasource string) bool {
+	for _, ds := range allowed {
+		if strings.EqualFold(ds, datasource) {
+			return true
+		}
+	}
+	return false
 }
 
 // RestrictCloudInit will limit the operations of cloud-init on subsequent boots
@@ -917,6 +1113,11 @@ func RestrictCloudInit(state CloudInitState, opts *CloudInitRestrictOptions) (Cl
 	cloudInitRestrictFile := filepath.Join(dirs.GlobalRootDir, cloudInitSnapdRestrictFile)
 
 	switch {
+	case len(opts.AllowedDatasources) != 0 && !datasourceAllowed(opts.AllowedDatasources, res.DataSource):
+		// the gadget does not allow this datasource, so disable cloud-init
+		// entirely rather than keep using it
+		res.Action = "disable"
+		err = DisableCloudInit(dirs.GlobalRootDir)
 	case opts.DisableAfterLocalDatasourcesRun && strutil.ListContains(localDatasources, res.DataSource):
 		// On UC20, DisableAfterLocalDatasourcesRun will be set, where we want
 		// to disable local sources like NoCloud and None after first-boot
@@ -505,6 +505,88 @@ datasource:
 	}
 }
 
+func (s *sysconfigSuite) TestInstallModeCloudInitGadgetPolicy(c *C) {
+	cloudCfgSrcDir, _ := s.makeCloudCfgSrcDirFiles(c, maasCfg5+`users:
+- name: admin
+runcmd:
+- [touch, /tmp/foo]
+`, maasCfg3)
+
+	gadgetDir := s.makeGadgetCloudConfFile(c, maasCfg2)
+	c.Assert(os.MkdirAll(filepath.Join(gadgetDir, "meta"), 0755), IsNil)
+	err := os.WriteFile(filepath.Join(gadgetDir, "meta/gadget.yaml"), []byte(`cloud-init:
+  allowed-datasources: [maas]
+  allowed-modules: [users]
+`), 0644)
+	c.Assert(err, IsNil)
+
+	targetDir := filepath.Join(dirs.GlobalRootDir, "/run/mnt/ubuntu-data/system-data")
+	err = sysconfig.ConfigureTargetSystem(fake20Model("signed"), &sysconfig.Options{
+		AllowCloudInit:  true,
+		TargetRootDir:   targetDir,
+		CloudInitSrcDir: cloudCfgSrcDir,
+		GadgetDir:       gadgetDir,
+	})
+	c.Assert(err, IsNil)
+
+	ubuntuDataCloudCfg := filepath.Join(targetDir, "_writable_defaults/etc/cloud/cloud.cfg.d/")
+	c.Check(filepath.Join(ubuntuDataCloudCfg, "90_seed-config-0.cfg"), testutil.FileEquals, maasCfg5+`users:
+- name: admin
+`)
+	c.Check(filepath.Join(ubuntuDataCloudCfg, "90_seed-config-1.cfg"), testutil.FileAbsent)
+
+	// the report is saved on the target
+	dirs.SetRootDir(filepath.Join(targetDir, "_writable_defaults"))
+	report, err := sysconfig.ReadCloudInitFilterReport()
+	c.Assert(err, IsNil)
+	c.Check(report, DeepEquals, &sysconfig.CloudInitFilterReport{
+		AllowedDatasources: []string{"MAAS"},
+		AllowedModules:     []string{"users"},
+		Files: []sysconfig.CloudInitFilteredFile{
+			{Name: "seed-config-0.cfg", DroppedKeys: []string{"runcmd"}},
+			{Name: "seed-config-1.cfg", DroppedKeys: []string{"snappy"}, Skipped: true},
+		},
+	})
+}
+
+func (s *sysconfigSuite) TestInstallModeCloudInitGadgetPolicyNoDatasourceLeft(c *C) {
+	cloudCfgSrcDir, _ := s.makeCloudCfgSrcDirFiles(c, maasCfg5)
+
+	gadgetDir := c.MkDir()
+	c.Assert(os.MkdirAll(filepath.Join(gadgetDir, "meta"), 0755), IsNil)
+	err := os.WriteFile(filepath.Join(gadgetDir, "meta/gadget.yaml"), []byte(`cloud-init:
+  allowed-datasources: [GCE]
+`), 0644)
+	c.Assert(err, IsNil)
+
+	targetDir := filepath.Join(dirs.GlobalRootDir, "/run/mnt/ubuntu-data/system-data")
+	err = sysconfig.ConfigureTargetSystem(fake20Model("signed"), &sysconfig.Options{
+		AllowCloudInit:  true,
+		TargetRootDir:   targetDir,
+		CloudInitSrcDir: cloudCfgSrcDir,
+		GadgetDir:       gadgetDir,
+	})
+	c.Assert(err, IsNil)
+
+	ubuntuDataCloudCfg := filepath.Join(targetDir, "_writable_defaults/etc/cloud/cloud.cfg.d/")
+	c.Check(filepath.Join(ubuntuDataCloudCfg, "90_seed-config-0.cfg"), testutil.FileAbsent)
+
+	dirs.SetRootDir(filepath.Join(targetDir, "_writable_defaults"))
+	report, err := sysconfig.ReadCloudInitFilterReport()
+	c.Assert(err, IsNil)
+	c.Check(report, DeepEquals, &sysconfig.CloudInitFilterReport{
+		Files: []sysconfig.CloudInitFilteredFile{
+			{Name: "seed-config-0.cfg", Skipped: true},
+		},
+	})
+}
+
+func (s *sysconfigSuite) TestReadCloudInitFilterReportNone(c *C) {
+	report, err := sysconfig.ReadCloudInitFilterReport()
+	c.Assert(err, IsNil)
+	c.Check(report, IsNil)
+}
+
 func (s *sysconfigSuite) TestInstallModeCloudInitDisallowedGradeSecuredDoesDisable(c *C) {
 	err := sysconfig.ConfigureTargetSystem(fake20Model("secured"), &sysconfig.Options{
 		AllowCloudInit: false,
@@ -911,6 +993,29 @@ func (s *sysconfigSuite) TestRestrictCloudInit(c *C) {
 			expAction:      "disable",
 			expDisableFile: true,
 		},
+		{
+			comment:             "gce done, allowed by gadget",
+			state:               sysconfig.CloudInitDone,
+			cloudInitStatusJSON: gceCloudInitStatusJSON,
+			sysconfOpts: &sysconfig.CloudInitRestrictOptions{
+				AllowedDatasources: []string{"MAAS", "gce"},
+			},
+			expDatasource: "GCE",
+			expAction:     "restrict",
+			expRestrictYamlWritten: `datasource_list: [GCE]
+`,
+		},
+		{
+			comment:             "gce done, not allowed by gadget",
+			state:               sysconfig.CloudInitDone,
+			cloudInitStatusJSON: gceCloudInitStatusJSON,
+			sysconfOpts: &sysconfig.CloudInitRestrictOptions{
+				AllowedDatasources: []string{"MAAS"},
+			},
+			expDatasource:  "GCE",
+			expAction:      "disable",
+			expDisableFile: true,
+		},
 		{
 			comment:        "no cloud-init in $PATH",
 			state:          sysconfig.CloudInitNotFound,
@@ -1257,6 +1362,41 @@ func (s *sysconfigSuite) TestCloudDatasourcesInUseForDirInUse(c *C) {
 	}
 }
 
+func (s *sysconfigSuite) TestFilterCloudCfgFileDetails(c *C) {
+	inFile := filepath.Join(c.MkDir(), "foo.cfg")
+	err := os.WriteFile(inFile, []byte(`#cloud-config
+datasource:
+  MAAS:
+    consumer_key: fooooooo
+  NoCloud:
+    consumer_key: fooooooo
+datasource_list: [MAAS, GCE]
+ntp:
+  servers: [ntp.example.com]
+runcmd:
+- [touch, /tmp/foo]
+`), 0644)
+	c.Assert(err, IsNil)
+
+	out, details, err := sysconfig.FilterCloudCfgFileWithDetails(inFile, []string{"MAAS"}, []string{"ntp"})
+	c.Assert(err, IsNil)
+	c.Check(out, testutil.FileEquals, `#cloud-config
+datasource:
+  MAAS:
+    consumer_key: fooooooo
+datasource_list:
+- MAAS
+ntp:
+  servers:
+  - ntp.example.com
+`)
+	c.Check(details, DeepEquals, &sysconfig.CloudInitFilteredFile{
+		Name:               "foo.cfg",
+		DroppedDatasources: []string{"GCE", "NoCloud"},
+		DroppedKeys:        []string{"runcmd"},
+	})
+}
+
 const maasCfg1 = `#cloud-config
 reporting:
   maas:
@@ -20,9 +20,14 @@
 package sysconfig
 
 var (
-	FilterCloudCfgFile = filterCloudCfgFile
+	FilterCloudCfgFileWithDetails = filterCloudCfgFile
 )
 
+func FilterCloudCfgFile(in string, allowedDatasources []string) (string, error) {
+	out, _, err := filterCloudCfgFile(in, allowedDatasources, nil)
+	return out, err
+}
+
 func CloudDatasourcesInUse(configFile string) (*CloudDatasourcesInUseResult, error) {
 	res, err := cloudDatasourcesInUse(configFile)
 	if err != nil {
@@ -118,10 +118,6 @@ func ConfigureTargetSystem(model *asserts.Model, opts *Options) error {
 		return fmt.Errorf("internal error: ConfigureTargetSystem can only be used with a model with a grade")
 	}
 
-	if err := configureCloudInit(model, opts); err != nil {
-		return err
-	}
-
 	var gadgetInfo *gadget.Info
 	var err error
 	switch {
@@ -138,6 +134,14 @@ func ConfigureTargetSystem(model *asserts.Model, opts *Options) error {
 		return err
 	}
 
+	var cloudInitPolicy *gadget.CloudInit
+	if gadgetInfo != nil {
+		cloudInitPolicy = gadgetInfo.CloudInit
+	}
+	if err := configureCloudInit(model, cloudInitPolicy, opts); err != nil {
+		return err
+	}
+
 	if gadgetInfo != nil {
 		defaults := gadget.SystemDefaults(gadgetInfo.Defaults)
 		if len(defaults) > 0 {
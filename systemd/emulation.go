@@ -134,7 +134,7 @@ func (s *emulation) EnsureMountUnitFile(description, what, where, fstype string)
 	// This means that when preseeding in a lxd container, the snap will be
 	// mounted with fuse, but mount unit will use squashfs.
 	mountUnitOptions := append(fsMountOptions(fstype), squashfs.StandardOptions()...)
-	mountUnitName, modified, err := ensureMountUnitFile(&MountUnitOptions{
+	mountUnitPath, modified, err := ensureMountUnitFile(&MountUnitOptions{
 		Lifetime:    Persistent,
 		Description: description,
 		What:        what,
@@ -145,6 +145,7 @@ func (s *emulation) EnsureMountUnitFile(description, what, where, fstype string)
 	if err != nil {
 		return "", err
 	}
+	mountUnitName := filepath.Base(mountUnitPath)
 
 	if modified == mountUnchanged {
 		return mountUnitName, nil
@@ -159,8 +160,10 @@ func (s *emulation) EnsureMountUnitFile(description, what, where, fstype string)
 		return "", fmt.Errorf("cannot mount %s (%s) at %s in preseed mode: %s; %s", what, hostFsType, where, err, string(out))
 	}
 
-	if err := s.EnableNoReload([]string{mountUnitName}); err != nil {
-		return "", err
+	if !isGeneratedMountUnit(mountUnitPath) {
+		if err := s.EnableNoReload([]string{mountUnitName}); err != nil {
+			return "", err
+		}
 	}
 
 	return mountUnitName, nil
@@ -171,8 +174,8 @@ func (s *emulation) EnsureMountUnitFileWithOptions(unitOptions *MountUnitOptions
 }
 
 func (s *emulation) RemoveMountUnitFile(mountedDir string) error {
-	unit := MountUnitPath(dirs.StripRootDir(mountedDir))
-	if !osutil.FileExists(unit) {
+	unit := ExistingMountUnitPath(dirs.StripRootDir(mountedDir))
+	if unit == "" {
 		return nil
 	}
 
@@ -187,8 +190,10 @@ func (s *emulation) RemoveMountUnitFile(mountedDir string) error {
 		}
 	}
 
-	if err := s.DisableNoReload([]string{filepath.Base(unit)}); err != nil {
-		return err
+	if !isGeneratedMountUnit(unit) {
+		if err := s.DisableNoReload([]string{filepath.Base(unit)}); err != nil {
+			return err
+		}
 	}
 
 	if err := os.Remove(unit); err != nil {
@@ -21,6 +21,7 @@ package systemd
 
 import (
 	"io"
+	"time"
 )
 
 var (
@@ -78,3 +79,29 @@ func (e *Error) SetExitCode(i int) {
 func (e *Error) SetMsg(msg []byte) {
 	e.msg = msg
 }
+
+func MockSupervisorCmd(f func(name string, args ...string) ([]byte, error)) (restore func()) {
+	old := supervisorCmd
+	supervisorCmd = f
+	return func() {
+		supervisorCmd = old
+	}
+}
+
+func MockSupervisorScanTimeout(timeout time.Duration) (restore func()) {
+	old := supervisorScanTimeout
+	supervisorScanTimeout = timeout
+	return func() {
+		supervisorScanTimeout = old
+	}
+}
+
+func MockSupervisorInUse(inUse bool) (restore func()) {
+	old := supervisorInUse
+	supervisorInUse = func() bool { return inUse }
+	return func() {
+		supervisorInUse = old
+	}
+}
+
+var SupervisorInUse = supervisorInUse
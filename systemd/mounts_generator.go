@@ -0,0 +1,99 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package systemd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/snapcore/snapd/dirs"
+)
+
+// isSnapdMountUnit returns whether the given mount unit file was written by
+// snapd for a persistent mount.
+func isSnapdMountUnit(path string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Contains(content, []byte("\nAfter=snapd.mounts-pre.target\n")) &&
+		bytes.Contains(content, []byte("\nWantedBy=snapd.mounts.target\n")), nil
+}
+
+func snapdMountUnits(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.mount"))
+	if err != nil {
+		return nil, err
+	}
+	var units []string
+	for _, path := range paths {
+		ours, err := isSnapdMountUnit(path)
+		if err != nil {
+			return nil, err
+		}
+		if ours {
+			units = append(units, filepath.Base(path))
+		}
+	}
+	return units, nil
+}
+
+// MigrateMountUnits moves the persistent mount units written by snapd
+// between /etc/systemd/system, where they are enabled like any other unit,
+// and dirs.SnapMountUnitsDir, from where snapd-generator produces them at
+// boot. With toGenerator set the units are moved to the latter, otherwise
+// they are moved back. The mounts themselves are left untouched.
+func MigrateMountUnits(sysd Systemd, toGenerator bool) error {
+	from, to := dirs.SnapServicesDir, dirs.SnapMountUnitsDir
+	if !toGenerator {
+		from, to = to, from
+	}
+	units, err := snapdMountUnits(from)
+	if err != nil {
+		return fmt.Errorf("cannot list mount units: %v", err)
+	}
+	if len(units) == 0 {
+		return nil
+	}
+
+	if toGenerator {
+		// drop the symlinks from the targets, snapd-generator
+		// provides those for the units it produces
+		if err := sysd.DisableNoReload(units); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(to, 0755); err != nil {
+		return err
+	}
+	for _, unit := range units {
+		if err := os.Rename(filepath.Join(from, unit), filepath.Join(to, unit)); err != nil {
+			return fmt.Errorf("cannot move mount unit %s: %v", unit, err)
+		}
+	}
+	if !toGenerator {
+		if err := sysd.EnableNoReload(units); err != nil {
+			return err
+		}
+	}
+	return sysd.DaemonReload()
+}
@@ -0,0 +1,190 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package systemd_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/features"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/osutil/squashfs"
+	. "github.com/snapcore/snapd/systemd"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func enableMountsGenerator(c *C) {
+	c.Assert(os.MkdirAll(dirs.FeaturesDir, 0755), IsNil)
+	c.Assert(os.WriteFile(features.MountsGenerator.ControlFile(), nil, 0644), IsNil)
+}
+
+const snapdMountUnitTemplate = `[Unit]
+Description=Mount unit for %[1]s, revision 1
+After=snapd.mounts-pre.target
+Before=snapd.mounts.target
+Before=local-fs.target
+
+[Mount]
+What=/var/lib/snapd/snaps/%[1]s_1.snap
+Where=/snap/%[1]s/1
+Type=squashfs
+Options=nodev,ro,x-gdu.hide,x-gvfs-hide
+LazyUnmount=yes
+
+[Install]
+WantedBy=snapd.mounts.target
+WantedBy=multi-user.target
+`
+
+func (s *SystemdTestSuite) TestMountUnitPathWithLifetimeMountsGenerator(c *C) {
+	c.Check(MountUnitPathWithLifetime(Persistent, "/snap/foo/1"), Equals, filepath.Join(dirs.SnapServicesDir, "snap-foo-1.mount"))
+
+	enableMountsGenerator(c)
+	c.Check(MountUnitPathWithLifetime(Persistent, "/snap/foo/1"), Equals, filepath.Join(dirs.SnapMountUnitsDir, "snap-foo-1.mount"))
+	c.Check(MountUnitPathWithLifetime(Transient, "/snap/foo/1"), Equals, filepath.Join(dirs.SnapRuntimeServicesDir, "snap-foo-1.mount"))
+}
+
+func (s *SystemdTestSuite) TestAddMountUnitMountsGenerator(c *C) {
+	rootDir := dirs.GlobalRootDir
+	enableMountsGenerator(c)
+
+	restore := squashfs.MockNeedsFuse(false)
+	defer restore()
+
+	mockSnapPath := filepath.Join(c.MkDir(), "/var/lib/snappy/snaps/foo_1.0.snap")
+	makeMockFile(c, mockSnapPath)
+
+	mountUnitName, err := NewUnderRoot(rootDir, SystemMode, nil).EnsureMountUnitFile("Mount unit for foo, revision 42", mockSnapPath, "/snap/snapname/123", "squashfs")
+	c.Assert(err, IsNil)
+	c.Check(mountUnitName, Equals, "snap-snapname-123.mount")
+
+	c.Check(filepath.Join(dirs.SnapMountUnitsDir, mountUnitName), testutil.FileContains, "Where=/snap/snapname/123\n")
+	c.Check(filepath.Join(dirs.SnapServicesDir, mountUnitName), testutil.FileAbsent)
+	// the unit is not enabled, snapd-generator takes care of that
+	c.Check(s.argses, DeepEquals, [][]string{
+		{"daemon-reload"},
+		{"reload-or-restart", "snap-snapname-123.mount"},
+	})
+}
+
+func (s *SystemdTestSuite) TestRemoveMountUnitMountsGenerator(c *C) {
+	rootDir := dirs.GlobalRootDir
+	enableMountsGenerator(c)
+
+	restore := osutil.MockMountInfo("")
+	defer restore()
+
+	c.Assert(os.MkdirAll(dirs.SnapMountUnitsDir, 0755), IsNil)
+	mountUnit := filepath.Join(dirs.SnapMountUnitsDir, "snap-foo-42.mount")
+	c.Assert(os.WriteFile(mountUnit, nil, 0644), IsNil)
+
+	err := NewUnderRoot(rootDir, SystemMode, nil).RemoveMountUnitFile(rootDir + "/snap/foo/42")
+	c.Assert(err, IsNil)
+	c.Check(mountUnit, testutil.FileAbsent)
+	c.Check(s.argses, DeepEquals, [][]string{
+		{"daemon-reload"},
+	})
+}
+
+func (s *SystemdTestSuite) TestRemoveMountUnitMountsGeneratorNotMigrated(c *C) {
+	rootDir := dirs.GlobalRootDir
+	enableMountsGenerator(c)
+
+	restore := osutil.MockMountInfo("")
+	defer restore()
+
+	mountDir := rootDir + "/snap/foo/42"
+	mountUnit := makeMockMountUnit(c, mountDir)
+	err := NewUnderRoot(rootDir, SystemMode, nil).RemoveMountUnitFile(mountDir)
+	c.Assert(err, IsNil)
+	c.Check(mountUnit, testutil.FileAbsent)
+	c.Check(s.argses, DeepEquals, [][]string{
+		{"--root", rootDir, "disable", "snap-foo-42.mount"},
+		{"daemon-reload"},
+	})
+}
+
+func (s *SystemdTestSuite) TestMigrateMountUnits(c *C) {
+	rootDir := dirs.GlobalRootDir
+	sysd := NewUnderRoot(rootDir, SystemMode, nil)
+
+	for _, name := range []string{"foo", "bar"} {
+		unit := filepath.Join(dirs.SnapServicesDir, fmt.Sprintf("snap-%s-1.mount", name))
+		c.Assert(os.WriteFile(unit, []byte(fmt.Sprintf(snapdMountUnitTemplate, name)), 0644), IsNil)
+	}
+	// mount units not written by snapd are left alone
+	other := filepath.Join(dirs.SnapServicesDir, "media-data.mount")
+	c.Assert(os.WriteFile(other, []byte("[Mount]\nWhere=/media/data\n"), 0644), IsNil)
+
+	err := MigrateMountUnits(sysd, true)
+	c.Assert(err, IsNil)
+	c.Check(filepath.Join(dirs.SnapServicesDir, "snap-bar-1.mount"), testutil.FileAbsent)
+	c.Check(filepath.Join(dirs.SnapServicesDir, "snap-foo-1.mount"), testutil.FileAbsent)
+	c.Check(filepath.Join(dirs.SnapMountUnitsDir, "snap-bar-1.mount"), testutil.FileEquals, fmt.Sprintf(snapdMountUnitTemplate, "bar"))
+	c.Check(filepath.Join(dirs.SnapMountUnitsDir, "snap-foo-1.mount"), testutil.FileEquals, fmt.Sprintf(snapdMountUnitTemplate, "foo"))
+	c.Check(other, testutil.FilePresent)
+	c.Check(s.argses, DeepEquals, [][]string{
+		{"--root", rootDir, "disable", "snap-bar-1.mount", "snap-foo-1.mount"},
+		{"daemon-reload"},
+	})
+
+	// nothing left to migrate
+	s.argses = nil
+	err = MigrateMountUnits(sysd, true)
+	c.Assert(err, IsNil)
+	c.Check(s.argses, HasLen, 0)
+
+	// and back
+	err = MigrateMountUnits(sysd, false)
+	c.Assert(err, IsNil)
+	c.Check(filepath.Join(dirs.SnapMountUnitsDir, "snap-bar-1.mount"), testutil.FileAbsent)
+	c.Check(filepath.Join(dirs.SnapMountUnitsDir, "snap-foo-1.mount"), testutil.FileAbsent)
+	c.Check(filepath.Join(dirs.SnapServicesDir, "snap-bar-1.mount"), testutil.FileEquals, fmt.Sprintf(snapdMountUnitTemplate, "bar"))
+	c.Check(filepath.Join(dirs.SnapServicesDir, "snap-foo-1.mount"), testutil.FileEquals, fmt.Sprintf(snapdMountUnitTemplate, "foo"))
+	c.Check(s.argses, DeepEquals, [][]string{
+		{"--root", rootDir, "enable", "snap-bar-1.mount", "snap-foo-1.mount"},
+		{"daemon-reload"},
+	})
+}
+
+func (s *SystemdTestSuite) TestMigrateMountUnitsNothingToDo(c *C) {
+	sysd := NewUnderRoot(dirs.GlobalRootDir, SystemMode, nil)
+
+	c.Assert(MigrateMountUnits(sysd, true), IsNil)
+	c.Assert(MigrateMountUnits(sysd, false), IsNil)
+	c.Check(s.argses, HasLen, 0)
+}
+
+func (s *SystemdTestSuite) TestMigrateMountUnitsDisableError(c *C) {
+	sysd := NewUnderRoot(dirs.GlobalRootDir, SystemMode, nil)
+
+	unit := filepath.Join(dirs.SnapServicesDir, "snap-foo-1.mount")
+	c.Assert(os.WriteFile(unit, []byte(fmt.Sprintf(snapdMountUnitTemplate, "foo")), 0644), IsNil)
+
+	s.errors = []error{fmt.Errorf("boom")}
+	err := MigrateMountUnits(sysd, true)
+	c.Assert(err, ErrorMatches, "boom")
+	// the unit was not moved
+	c.Check(unit, testutil.FilePresent)
+}
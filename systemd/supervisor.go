@@ -0,0 +1,541 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package systemd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/gadget/quantity"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil"
+)
+
+// The supervisor backend manages the services of snaps with a
+// daemontools-style supervisor on systems where systemd is absent, e.g. in
+// containers without an init system. Each service unit is translated into a
+// service directory with a run script under dirs.SnapSupervisedServicesDir,
+// which is linked into the directory scanned by svscan once the service is
+// used. Following daemontools, a service directory with a "down" file is not
+// started by the supervisor on its own, which is how disabled services are
+// represented.
+//
+// Only simple long-running services can be supervised, other unit types
+// and features of systemd are not available.
+
+var (
+	// supervisorCmd runs the given supervisor tool with the given args
+	supervisorCmd = func(name string, args ...string) ([]byte, error) {
+		out, err := exec.Command(name, args...).CombinedOutput()
+		if err != nil {
+			return nil, osutil.OutputErr(out, err)
+		}
+		return out, nil
+	}
+
+	// how long to wait for the supervisor to pick up a newly linked
+	// service directory, svscan scans every five seconds
+	supervisorScanTimeout = 10 * time.Second
+	supervisorScanWait    = 100 * time.Millisecond
+)
+
+type supervisor struct {
+	rootDir string
+}
+
+type unsupportedError struct {
+	op string
+}
+
+func (e *unsupportedError) Error() string {
+	return fmt.Sprintf("%q is not supported without systemd", e.op)
+}
+
+// supervisorInUse returns whether the services of the system are managed by
+// a daemontools-style supervisor rather than by systemd.
+var supervisorInUse = func() bool {
+	if osutil.IsDirectory(filepath.Join(dirs.GlobalRootDir, "/run/systemd/system")) {
+		// systemd is running
+		return false
+	}
+	return osutil.IsDirectory(dirs.SupervisorScanDir) && osutil.ExecutableExists("svscan")
+}
+
+func (s *supervisor) Backend() Backend {
+	return SupervisorBackend
+}
+
+// supervisedServiceName returns the name of the service directory of the given unit
+// and whether the unit is a service at all.
+func supervisedServiceName(unit string) (string, bool) {
+	if !strings.HasSuffix(unit, ".service") {
+		return "", false
+	}
+	return strings.TrimSuffix(unit, ".service"), true
+}
+
+func supervisedServiceDir(name string) string {
+	return filepath.Join(dirs.SnapSupervisedServicesDir, name)
+}
+
+func supervisedServiceLink(name string) string {
+	return filepath.Join(dirs.SupervisorScanDir, name)
+}
+
+// serviceUnitSettings returns the settings of the [Service] section of the
+// given unit file relevant for supervising it.
+func serviceUnitSettings(unit string) (map[string]string, error) {
+	f, err := os.Open(filepath.Join(dirs.SnapServicesDir, unit))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	settings := make(map[string]string)
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+		if section != "[Service]" {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "ExecStart", "WorkingDirectory", "Type":
+			settings[kv[0]] = kv[1]
+		}
+	}
+	return settings, scanner.Err()
+}
+
+// ensureServiceDir writes the service directory of the given unit, with a
+// "down" file unless the service is to be started by the supervisor on its
+// own.
+func ensureServiceDir(unit, name string, enabled bool) error {
+	settings, err := serviceUnitSettings(unit)
+	if err != nil {
+		return fmt.Errorf("cannot supervise %s: %v", unit, err)
+	}
+	switch settings["Type"] {
+	case "", "simple", "exec", "notify", "dbus":
+	default:
+		return fmt.Errorf("cannot supervise %s: %s services are not supported without systemd", unit, settings["Type"])
+	}
+	if settings["ExecStart"] == "" {
+		return fmt.Errorf("cannot supervise %s: no command to start", unit)
+	}
+
+	var run bytes.Buffer
+	fmt.Fprintf(&run, "#!/bin/sh\n")
+	fmt.Fprintf(&run, "# Generated by snapd from %s, do not edit\n", unit)
+	if wd := settings["WorkingDirectory"]; wd != "" {
+		fmt.Fprintf(&run, "cd %s || exit 1\n", shellQuote(wd))
+	}
+	fmt.Fprintf(&run, "exec %s 2>&1\n", settings["ExecStart"])
+
+	dir := supervisedServiceDir(name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := osutil.AtomicWriteFile(filepath.Join(dir, "run"), run.Bytes(), 0755, 0); err != nil {
+		return err
+	}
+	down := filepath.Join(dir, "down")
+	if enabled {
+		if err := os.Remove(down); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if !osutil.FileExists(down) {
+		return os.WriteFile(down, nil, 0644)
+	}
+	return nil
+}
+
+// shellQuote quotes the string for use as a single argument in a shell
+// script.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// linkService links the service directory into the scan directory of the
+// supervisor.
+func linkService(name string) error {
+	link := supervisedServiceLink(name)
+	if _, err := os.Lstat(link); !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(dirs.SupervisorScanDir, 0755); err != nil {
+		return err
+	}
+	return os.Symlink(supervisedServiceDir(name), link)
+}
+
+// ensureSupervised links the service directory into the scan directory and
+// waits for the supervisor to pick it up.
+func ensureSupervised(name string) error {
+	if err := linkService(name); err != nil {
+		return err
+	}
+
+	ok := filepath.Join(supervisedServiceDir(name), "supervise", "ok")
+	for start := time.Now(); !osutil.FileExists(ok); time.Sleep(supervisorScanWait) {
+		if time.Since(start) > supervisorScanTimeout {
+			return fmt.Errorf("cannot supervise %s: supervisor did not pick up the service", name)
+		}
+	}
+	return nil
+}
+
+func (s *supervisor) DaemonReload() error {
+	// forget about the services whose unit is gone
+	entries, err := os.ReadDir(dirs.SnapSupervisedServicesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if osutil.FileExists(filepath.Join(dirs.SnapServicesDir, name+".service")) {
+			continue
+		}
+		link := supervisedServiceLink(name)
+		if _, err := os.Lstat(link); err == nil {
+			if err := os.Remove(link); err != nil {
+				return err
+			}
+			// stop the service and its supervise process
+			if _, err := supervisorCmd("svc", "-dx", supervisedServiceDir(name)); err != nil {
+				logger.Noticef("cannot stop supervision of %s: %v", name, err)
+			}
+		}
+		if err := os.RemoveAll(supervisedServiceDir(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *supervisor) DaemonReexec() error {
+	return &unsupportedError{"DaemonReexec"}
+}
+
+func (s *supervisor) EnableNoReload(units []string) error {
+	for _, unit := range units {
+		name, ok := supervisedServiceName(unit)
+		if !ok {
+			logger.Debugf("not enabling %s: only services are supported without systemd", unit)
+			continue
+		}
+		if err := ensureServiceDir(unit, name, true); err != nil {
+			return err
+		}
+		// services of daemontools-style supervisors start as soon as
+		// they are linked into the scan directory
+		if err := linkService(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *supervisor) DisableNoReload(units []string) error {
+	for _, unit := range units {
+		name, ok := supervisedServiceName(unit)
+		if !ok || !osutil.IsDirectory(supervisedServiceDir(name)) {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(supervisedServiceDir(name), "down"), nil, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// control sends the given svc command to the supervisor of the services,
+// supervising them first if needed.
+func (s *supervisor) control(units []string, cmd string) error {
+	for _, unit := range units {
+		name, ok := supervisedServiceName(unit)
+		if !ok {
+			logger.Debugf("ignoring %s: only services are supported without systemd", unit)
+			continue
+		}
+		if !osutil.FileExists(filepath.Join(supervisedServiceDir(name), "run")) {
+			if err := ensureServiceDir(unit, name, false); err != nil {
+				return err
+			}
+		}
+		if err := ensureSupervised(name); err != nil {
+			return err
+		}
+		if _, err := supervisorCmd("svc", cmd, supervisedServiceLink(name)); err != nil {
+			return fmt.Errorf("cannot control %s: %v", unit, err)
+		}
+	}
+	return nil
+}
+
+func (s *supervisor) Start(units []string) error {
+	return s.control(units, "-u")
+}
+
+func (s *supervisor) StartNoBlock(units []string) error {
+	return s.control(units, "-u")
+}
+
+func (s *supervisor) Stop(units []string) error {
+	for _, unit := range units {
+		name, ok := supervisedServiceName(unit)
+		if !ok {
+			continue
+		}
+		if _, err := os.Lstat(supervisedServiceLink(name)); err != nil {
+			// not supervised, hence not running
+			continue
+		}
+		if _, err := supervisorCmd("svc", "-d", supervisedServiceLink(name)); err != nil {
+			return fmt.Errorf("cannot stop %s: %v", unit, err)
+		}
+	}
+	return nil
+}
+
+// svcSignals maps signals to the options of svc sending them.
+var svcSignals = map[string]string{
+	"HUP":  "-h",
+	"INT":  "-i",
+	"TERM": "-t",
+	"KILL": "-k",
+	"ALRM": "-a",
+	"USR1": "-1",
+	"USR2": "-2",
+	"CONT": "-c",
+	"STOP": "-p",
+}
+
+func (s *supervisor) Kill(unit, signal, who string) error {
+	opt, ok := svcSignals[strings.TrimPrefix(signal, "SIG")]
+	if !ok {
+		return fmt.Errorf("cannot send signal %s to %s without systemd", signal, unit)
+	}
+	name, ok := supervisedServiceName(unit)
+	if !ok {
+		return &unsupportedError{"Kill"}
+	}
+	if _, err := supervisorCmd("svc", opt, supervisedServiceLink(name)); err != nil {
+		return fmt.Errorf("cannot send signal %s to %s: %v", signal, unit, err)
+	}
+	return nil
+}
+
+func (s *supervisor) Restart(units []string) error {
+	// terminate the service, which the supervisor restarts as it is
+	// required to be up
+	return s.control(units, "-tu")
+}
+
+func (s *supervisor) ReloadOrRestart(units []string) error {
+	return s.Restart(units)
+}
+
+func (s *supervisor) RestartAll(unit string) error {
+	return s.Restart([]string{unit})
+}
+
+func (s *supervisor) Status(units []string) ([]*UnitStatus, error) {
+	statuses := make([]*UnitStatus, len(units))
+	for i, unit := range units {
+		status := &UnitStatus{
+			Id:        unit,
+			Name:      unit,
+			Names:     []string{unit},
+			Installed: osutil.FileExists(filepath.Join(dirs.SnapServicesDir, unit)),
+		}
+		statuses[i] = status
+		name, ok := supervisedServiceName(unit)
+		if !ok || !status.Installed {
+			continue
+		}
+		settings, err := serviceUnitSettings(unit)
+		if err != nil {
+			return nil, err
+		}
+		status.Daemon = settings["Type"]
+		if status.Daemon == "" {
+			status.Daemon = "simple"
+		}
+		status.Enabled, status.Active, err = supervisedServiceState(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return statuses, nil
+}
+
+// supervisedServiceState returns whether the service is enabled and whether
+// it is running.
+func supervisedServiceState(name string) (enabled, active bool, err error) {
+	link := supervisedServiceLink(name)
+	if _, err := os.Lstat(link); err != nil {
+		return false, false, nil
+	}
+	enabled = !osutil.FileExists(filepath.Join(supervisedServiceDir(name), "down"))
+	// svstat reports e.g. "/etc/service/foo: up (pid 123) 42 seconds"
+	out, err := supervisorCmd("svstat", link)
+	if err != nil {
+		return false, false, fmt.Errorf("cannot get status of %s: %v", name, err)
+	}
+	active = strings.HasPrefix(strings.TrimPrefix(string(out), link+":"), " up ")
+	return enabled, active, nil
+}
+
+func (s *supervisor) InactiveEnterTimestamp(unit string) (time.Time, error) {
+	return time.Time{}, &unsupportedError{"InactiveEnterTimestamp"}
+}
+
+func (s *supervisor) CurrentMemoryUsage(unit string) (quantity.Size, error) {
+	return 0, &unsupportedError{"CurrentMemoryUsage"}
+}
+
+func (s *supervisor) CurrentTasksCount(unit string) (uint64, error) {
+	return 0, &unsupportedError{"CurrentTasksCount"}
+}
+
+func (s *supervisor) IsEnabled(unit string) (bool, error) {
+	name, ok := supervisedServiceName(unit)
+	if !ok {
+		return false, nil
+	}
+	enabled := osutil.IsDirectory(supervisedServiceDir(name)) &&
+		!osutil.FileExists(filepath.Join(supervisedServiceDir(name), "down"))
+	return enabled, nil
+}
+
+func (s *supervisor) IsActive(unit string) (bool, error) {
+	name, ok := supervisedServiceName(unit)
+	if !ok {
+		return false, nil
+	}
+	_, active, err := supervisedServiceState(name)
+	return active, err
+}
+
+func (s *supervisor) LogReader(units []string, n int, follow, namespaces bool) (io.ReadCloser, error) {
+	return nil, &unsupportedError{"LogReader"}
+}
+
+func (s *supervisor) EnsureMountUnitFile(description, what, where, fstype string) (string, error) {
+	return s.EnsureMountUnitFileWithOptions(PersistentMountUnitOptions(description, what, where, fstype))
+}
+
+func (s *supervisor) EnsureMountUnitFileWithOptions(unitOptions *MountUnitOptions) (string, error) {
+	// the mount unit is written for consistency with systemd systems,
+	// but the mount itself is performed directly
+	mountUnitPath, modified, err := ensureMountUnitFile(unitOptions)
+	if err != nil {
+		return "", err
+	}
+	mountUnitName := filepath.Base(mountUnitPath)
+	if modified == mountUnchanged {
+		return mountUnitName, nil
+	}
+
+	options := unitOptions.Options
+	if modified == mountUpdated {
+		options = append(options, "remount")
+	}
+	args := []string{"-t", unitOptions.Fstype, unitOptions.What, unitOptions.Where}
+	if len(options) > 0 {
+		args = append(args, "-o", strings.Join(options, ","))
+	}
+	if out, err := exec.Command("mount", args...).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("cannot mount %s (%s) at %s: %v", unitOptions.What, unitOptions.Fstype, unitOptions.Where, osutil.OutputErr(out, err))
+	}
+	return mountUnitName, nil
+}
+
+func (s *supervisor) RemoveMountUnitFile(mountedDir string) error {
+	unit := ExistingMountUnitPath(dirs.StripRootDir(mountedDir))
+	if unit == "" {
+		return nil
+	}
+
+	isMounted, err := osutilIsMounted(mountedDir)
+	if err != nil {
+		return err
+	}
+	if isMounted {
+		// use detach-loop and lazy unmount
+		if output, err := exec.Command("umount", "-d", "-l", mountedDir).CombinedOutput(); err != nil {
+			return osutil.OutputErr(output, err)
+		}
+	}
+	return os.Remove(unit)
+}
+
+func (s *supervisor) ListMountUnits(snapName, origin string) ([]string, error) {
+	return nil, &unsupportedError{"ListMountUnits"}
+}
+
+func (s *supervisor) Mask(unit string) error {
+	return s.DisableNoReload([]string{unit})
+}
+
+func (s *supervisor) Unmask(unit string) error {
+	return nil
+}
+
+func (s *supervisor) Mount(what, where string, options ...string) error {
+	args := append([]string{what, where}, options...)
+	if output, err := exec.Command("mount", args...).CombinedOutput(); err != nil {
+		return osutil.OutputErr(output, err)
+	}
+	return nil
+}
+
+func (s *supervisor) Umount(whatOrWhere string) error {
+	if output, err := exec.Command("umount", whatOrWhere).CombinedOutput(); err != nil {
+		return osutil.OutputErr(output, err)
+	}
+	return nil
+}
+
+func (s *supervisor) Run(command []string, opts *RunOptions) ([]byte, error) {
+	return nil, &unsupportedError{"Run"}
+}
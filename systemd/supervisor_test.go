@@ -0,0 +1,268 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package systemd_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/systemd"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type supervisorSuite struct {
+	testutil.BaseTest
+
+	calls   [][]string
+	svstat  map[string]string
+	sysd    systemd.Systemd
+	scanned bool
+}
+
+var _ = Suite(&supervisorSuite{})
+
+const supervisedUnit = `[Unit]
+Description=Service for snap application foo.bar
+X-Snappy=yes
+
+[Service]
+EnvironmentFile=-/etc/environment
+ExecStart=/usr/bin/snap run foo.bar
+SyslogIdentifier=foo.bar
+Restart=on-failure
+WorkingDirectory=/var/snap/foo/x1
+Type=simple
+
+[Install]
+WantedBy=multi-user.target
+`
+
+func (s *supervisorSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+	c.Assert(os.MkdirAll(dirs.SnapServicesDir, 0755), IsNil)
+	c.Assert(os.MkdirAll(dirs.SupervisorScanDir, 0755), IsNil)
+
+	s.calls = nil
+	s.svstat = make(map[string]string)
+	// the supervisor picks up linked services right away
+	s.scanned = true
+	s.AddCleanup(systemd.MockSupervisorCmd(func(name string, args ...string) ([]byte, error) {
+		s.calls = append(s.calls, append([]string{name}, args...))
+		if name == "svstat" {
+			return []byte(args[0] + ": " + s.svstat[filepath.Base(args[0])] + "\n"), nil
+		}
+		return nil, nil
+	}))
+	s.AddCleanup(systemd.MockSupervisorScanTimeout(50 * time.Millisecond))
+	s.AddCleanup(systemd.MockSupervisorInUse(true))
+
+	s.sysd = systemd.New(systemd.SystemMode, nil)
+}
+
+func (s *supervisorSuite) mockUnit(c *C, unit, content string) {
+	c.Assert(os.WriteFile(filepath.Join(dirs.SnapServicesDir, unit), []byte(content), 0644), IsNil)
+}
+
+func (s *supervisorSuite) mockScanned(c *C, name string) {
+	if !s.scanned {
+		return
+	}
+	ok := filepath.Join(dirs.SnapSupervisedServicesDir, name, "supervise", "ok")
+	c.Assert(os.MkdirAll(filepath.Dir(ok), 0755), IsNil)
+	c.Assert(os.WriteFile(ok, nil, 0644), IsNil)
+}
+
+func (s *supervisorSuite) TestBackend(c *C) {
+	c.Check(s.sysd.Backend(), Equals, systemd.SupervisorBackend)
+
+	// user instances are still handled by systemd
+	c.Check(systemd.New(systemd.GlobalUserMode, nil).Backend(), Equals, systemd.RunningSystemdBackend)
+
+	restore := systemd.MockSupervisorInUse(false)
+	defer restore()
+	c.Check(systemd.New(systemd.SystemMode, nil).Backend(), Equals, systemd.RunningSystemdBackend)
+}
+
+func (s *supervisorSuite) TestSupervisorInUse(c *C) {
+	c.Check(systemd.SupervisorInUse(), Equals, false)
+
+	cmd := testutil.MockCommand(c, "svscan", "")
+	defer cmd.Restore()
+	c.Check(systemd.SupervisorInUse(), Equals, true)
+
+	// systemd takes precedence
+	c.Assert(os.MkdirAll(filepath.Join(dirs.GlobalRootDir, "/run/systemd/system"), 0755), IsNil)
+	c.Check(systemd.SupervisorInUse(), Equals, false)
+}
+
+func (s *supervisorSuite) TestEnableWritesServiceDir(c *C) {
+	s.mockUnit(c, "snap.foo.bar.service", supervisedUnit)
+
+	c.Assert(s.sysd.EnableNoReload([]string{"snap.foo.bar.service", "snap.foo.baz.timer"}), IsNil)
+
+	dir := filepath.Join(dirs.SnapSupervisedServicesDir, "snap.foo.bar")
+	c.Check(filepath.Join(dir, "run"), testutil.FileEquals, `#!/bin/sh
+# Generated by snapd from snap.foo.bar.service, do not edit
+cd '/var/snap/foo/x1' || exit 1
+exec /usr/bin/snap run foo.bar 2>&1
+`)
+	c.Check(filepath.Join(dir, "down"), testutil.FileAbsent)
+	target, err := os.Readlink(filepath.Join(dirs.SupervisorScanDir, "snap.foo.bar"))
+	c.Assert(err, IsNil)
+	c.Check(target, Equals, dir)
+	c.Check(s.calls, HasLen, 0)
+
+	enabled, err := s.sysd.IsEnabled("snap.foo.bar.service")
+	c.Assert(err, IsNil)
+	c.Check(enabled, Equals, true)
+
+	c.Assert(s.sysd.DisableNoReload([]string{"snap.foo.bar.service"}), IsNil)
+	c.Check(filepath.Join(dir, "down"), testutil.FilePresent)
+	enabled, err = s.sysd.IsEnabled("snap.foo.bar.service")
+	c.Assert(err, IsNil)
+	c.Check(enabled, Equals, false)
+}
+
+func (s *supervisorSuite) TestEnableUnsupportedType(c *C) {
+	s.mockUnit(c, "snap.foo.bar.service", strings.Replace(supervisedUnit, "Type=simple", "Type=forking", 1))
+
+	err := s.sysd.EnableNoReload([]string{"snap.foo.bar.service"})
+	c.Check(err, ErrorMatches, "cannot supervise snap.foo.bar.service: forking services are not supported without systemd")
+}
+
+func (s *supervisorSuite) TestStartStopRestart(c *C) {
+	s.mockUnit(c, "snap.foo.bar.service", supervisedUnit)
+	s.mockScanned(c, "snap.foo.bar")
+	link := filepath.Join(dirs.SupervisorScanDir, "snap.foo.bar")
+
+	// starting a service that is not enabled supervises it without
+	// enabling it
+	c.Assert(s.sysd.Start([]string{"snap.foo.bar.service"}), IsNil)
+	c.Check(filepath.Join(dirs.SnapSupervisedServicesDir, "snap.foo.bar", "down"), testutil.FilePresent)
+	c.Check(s.calls, DeepEquals, [][]string{{"svc", "-u", link}})
+
+	c.Assert(s.sysd.Restart([]string{"snap.foo.bar.service"}), IsNil)
+	c.Assert(s.sysd.Stop([]string{"snap.foo.bar.service", "snap.foo.baz.service"}), IsNil)
+	c.Assert(s.sysd.Kill("snap.foo.bar.service", "SIGUSR1", ""), IsNil)
+	c.Check(s.calls[1:], DeepEquals, [][]string{
+		{"svc", "-tu", link},
+		{"svc", "-d", link},
+		{"svc", "-1", link},
+	})
+
+	err := s.sysd.Kill("snap.foo.bar.service", "SIGWINCH", "")
+	c.Check(err, ErrorMatches, "cannot send signal SIGWINCH to snap.foo.bar.service without systemd")
+}
+
+func (s *supervisorSuite) TestStartNotPickedUp(c *C) {
+	s.scanned = false
+	s.mockUnit(c, "snap.foo.bar.service", supervisedUnit)
+
+	err := s.sysd.Start([]string{"snap.foo.bar.service"})
+	c.Check(err, ErrorMatches, "cannot supervise snap.foo.bar: supervisor did not pick up the service")
+	c.Check(s.calls, HasLen, 0)
+}
+
+func (s *supervisorSuite) TestStatus(c *C) {
+	s.mockUnit(c, "snap.foo.bar.service", supervisedUnit)
+	s.mockUnit(c, "snap.foo.baz.service", strings.Replace(supervisedUnit, "Type=simple", "Type=notify", 1))
+	s.mockUnit(c, "snap.foo.baz.timer", "[Timer]\n")
+	c.Assert(s.sysd.EnableNoReload([]string{"snap.foo.bar.service"}), IsNil)
+	s.mockScanned(c, "snap.foo.baz")
+	c.Assert(s.sysd.Start([]string{"snap.foo.baz.service"}), IsNil)
+	s.svstat["snap.foo.bar"] = "up (pid 123) 42 seconds"
+	s.svstat["snap.foo.baz"] = "down 3 seconds, normally up"
+
+	statuses, err := s.sysd.Status([]string{"snap.foo.bar.service", "snap.foo.baz.service", "snap.foo.baz.timer", "snap.foo.other.service"})
+	c.Assert(err, IsNil)
+	c.Check(statuses, DeepEquals, []*systemd.UnitStatus{{
+		Daemon:    "simple",
+		Id:        "snap.foo.bar.service",
+		Name:      "snap.foo.bar.service",
+		Names:     []string{"snap.foo.bar.service"},
+		Enabled:   true,
+		Active:    true,
+		Installed: true,
+	}, {
+		Daemon:    "notify",
+		Id:        "snap.foo.baz.service",
+		Name:      "snap.foo.baz.service",
+		Names:     []string{"snap.foo.baz.service"},
+		Installed: true,
+	}, {
+		Id:        "snap.foo.baz.timer",
+		Name:      "snap.foo.baz.timer",
+		Names:     []string{"snap.foo.baz.timer"},
+		Installed: true,
+	}, {
+		Id:    "snap.foo.other.service",
+		Name:  "snap.foo.other.service",
+		Names: []string{"snap.foo.other.service"},
+	}})
+
+	active, err := s.sysd.IsActive("snap.foo.bar.service")
+	c.Assert(err, IsNil)
+	c.Check(active, Equals, true)
+}
+
+func (s *supervisorSuite) TestDaemonReloadForgetsRemovedServices(c *C) {
+	s.mockUnit(c, "snap.foo.bar.service", supervisedUnit)
+	s.mockUnit(c, "snap.foo.baz.service", supervisedUnit)
+	c.Assert(s.sysd.EnableNoReload([]string{"snap.foo.bar.service", "snap.foo.baz.service"}), IsNil)
+
+	c.Assert(os.Remove(filepath.Join(dirs.SnapServicesDir, "snap.foo.baz.service")), IsNil)
+	c.Assert(s.sysd.DaemonReload(), IsNil)
+
+	c.Check(filepath.Join(dirs.SnapSupervisedServicesDir, "snap.foo.bar"), testutil.FilePresent)
+	c.Check(filepath.Join(dirs.SupervisorScanDir, "snap.foo.bar"), testutil.FilePresent)
+	c.Check(filepath.Join(dirs.SnapSupervisedServicesDir, "snap.foo.baz"), testutil.FileAbsent)
+	c.Check(filepath.Join(dirs.SupervisorScanDir, "snap.foo.baz"), testutil.FileAbsent)
+	c.Check(s.calls, DeepEquals, [][]string{
+		{"svc", "-dx", filepath.Join(dirs.SnapSupervisedServicesDir, "snap.foo.baz")},
+	})
+}
+
+func (s *supervisorSuite) TestUnsupported(c *C) {
+	_, err := s.sysd.LogReader([]string{"snap.foo.bar.service"}, 10, false, false)
+	c.Check(err, ErrorMatches, `"LogReader" is not supported without systemd`)
+	_, err = s.sysd.CurrentMemoryUsage("snap.foo.slice")
+	c.Check(err, ErrorMatches, `"CurrentMemoryUsage" is not supported without systemd`)
+	c.Check(s.sysd.DaemonReexec(), ErrorMatches, `"DaemonReexec" is not supported without systemd`)
+}
+
+func (s *supervisorSuite) TestSvcError(c *C) {
+	s.mockUnit(c, "snap.foo.bar.service", supervisedUnit)
+	s.mockScanned(c, "snap.foo.bar")
+	restore := systemd.MockSupervisorCmd(func(name string, args ...string) ([]byte, error) {
+		return nil, errors.New("boom")
+	})
+	defer restore()
+
+	err := s.sysd.Start([]string{"snap.foo.bar.service"})
+	c.Check(err, ErrorMatches, "cannot control snap.foo.bar.service: boom")
+}
@@ -38,6 +38,7 @@ import (
 	"time"
 
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/features"
 	"github.com/snapcore/snapd/gadget/quantity"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/osutil/squashfs"
@@ -322,6 +323,10 @@ const (
 	// EmulationModeBackend identifies the implementation backend
 	// emulating a subset of systemd against a filesystem.
 	EmulationModeBackend
+	// SupervisorBackend identifies the implementation backend managing
+	// services with a daemontools-style supervisor on systems without
+	// systemd.
+	SupervisorBackend
 )
 
 type mountUpdateStatus int
@@ -476,6 +481,8 @@ func newSystemdReal(be Backend, rootDir string, mode InstanceMode, rep Reporter)
 		return &systemd{rootDir: rootDir, mode: mode, reporter: rep}
 	case EmulationModeBackend:
 		return &emulation{rootDir: rootDir}
+	case SupervisorBackend:
+		return &supervisor{rootDir: rootDir}
 	default:
 		panic(fmt.Sprintf("unsupported systemd backend %v", be))
 	}
@@ -483,7 +490,14 @@ func newSystemdReal(be Backend, rootDir string, mode InstanceMode, rep Reporter)
 
 // New returns a Systemd that uses the default root directory and omits
 // --root argument when executing systemctl.
+//
+// On systems without systemd where services are managed by a
+// daemontools-style supervisor, the returned Systemd manages the system
+// services through the supervisor instead.
 func New(mode InstanceMode, rep Reporter) Systemd {
+	if mode == SystemMode && supervisorInUse() {
+		return newSystemd(SupervisorBackend, "", mode, rep)
+	}
 	return newSystemd(RunningSystemdBackend, "", mode, rep)
 }
 
@@ -1343,7 +1357,7 @@ func MountUnitPathWithLifetime(lifetime UnitLifetime, mountPointDir string) stri
 	var servicesPath string
 	switch lifetime {
 	case Persistent:
-		servicesPath = dirs.SnapServicesDir
+		servicesPath = persistentMountUnitsDir()
 	case Transient:
 		servicesPath = dirs.SnapRuntimeServicesDir
 	default:
@@ -1354,9 +1368,9 @@ func MountUnitPathWithLifetime(lifetime UnitLifetime, mountPointDir string) stri
 
 // ExistingMountUnitPath finds the location of an existing mount unit
 func ExistingMountUnitPath(mountPointDir string) string {
-	lifetimes := []UnitLifetime{Persistent, Transient}
-	for _, lifetime := range lifetimes {
-		unit := MountUnitPathWithLifetime(lifetime, mountPointDir)
+	escapedPath := EscapeUnitNamePath(mountPointDir)
+	for _, dir := range []string{dirs.SnapServicesDir, dirs.SnapMountUnitsDir, dirs.SnapRuntimeServicesDir} {
+		unit := filepath.Join(dir, escapedPath+".mount")
 		if osutil.FileExists(unit) {
 			return unit
 		}
@@ -1364,6 +1378,27 @@ func ExistingMountUnitPath(mountPointDir string) string {
 	return ""
 }
 
+var mountsGeneratorEnabled = features.MountsGenerator.IsEnabled
+
+// persistentMountUnitsDir returns the directory where persistent mount units
+// are written. With the mounts-generator feature enabled they are kept in
+// dirs.SnapMountUnitsDir, from where snapd-generator hands them over to
+// systemd at boot, instead of being installed and enabled in
+// /etc/systemd/system.
+func persistentMountUnitsDir() string {
+	if mountsGeneratorEnabled() {
+		return dirs.SnapMountUnitsDir
+	}
+	return dirs.SnapServicesDir
+}
+
+// isGeneratedMountUnit returns whether the given mount unit file is
+// handed over to systemd by snapd-generator. Such units cannot be enabled
+// or disabled with systemctl, the generator adds the needed dependencies.
+func isGeneratedMountUnit(unitPath string) bool {
+	return filepath.Dir(unitPath) == dirs.SnapMountUnitsDir
+}
+
 var squashfsFsType = squashfs.FsType
 
 // Note that WantedBy=multi-user.target and Before=local-fs.target are
@@ -1396,7 +1431,7 @@ const (
 	snappyOriginModule = "X-SnapdOrigin"
 )
 
-func ensureMountUnitFile(u *MountUnitOptions) (mountUnitName string, modified mountUpdateStatus, err error) {
+func ensureMountUnitFile(u *MountUnitOptions) (mountUnitPath string, modified mountUpdateStatus, err error) {
 	if u == nil {
 		return "", mountUnchanged, errors.New("ensureMountUnitFile() expects valid mount options")
 	}
@@ -1429,7 +1464,7 @@ func ensureMountUnitFile(u *MountUnitOptions) (mountUnitName string, modified mo
 		return "", mountUnchanged, stateErr
 	}
 
-	return filepath.Base(mu), modified, nil
+	return mu, modified, nil
 }
 
 func fsMountOptions(fstype string) []string {
@@ -1458,30 +1493,38 @@ func hostFsTypeAndMountOptions(fstype string) (hostFsType string, options []stri
 	return hostFsType, options
 }
 
-func (s *systemd) EnsureMountUnitFile(description, what, where, fstype string) (string, error) {
+// PersistentMountUnitOptions returns the options of the persistent mount
+// unit EnsureMountUnitFile creates for the given mount, for callers that need
+// to tweak them before passing them to EnsureMountUnitFileWithOptions.
+func PersistentMountUnitOptions(description, what, where, fstype string) *MountUnitOptions {
 	hostFsType, options := hostFsTypeAndMountOptions(fstype)
 	if osutil.IsDirectory(what) {
 		options = append(options, "bind")
 		hostFsType = "none"
 	}
-	return s.EnsureMountUnitFileWithOptions(&MountUnitOptions{
+	return &MountUnitOptions{
 		Lifetime:    Persistent,
 		Description: description,
 		What:        what,
 		Where:       where,
 		Fstype:      hostFsType,
 		Options:     options,
-	})
+	}
+}
+
+func (s *systemd) EnsureMountUnitFile(description, what, where, fstype string) (string, error) {
+	return s.EnsureMountUnitFileWithOptions(PersistentMountUnitOptions(description, what, where, fstype))
 }
 
 func (s *systemd) EnsureMountUnitFileWithOptions(unitOptions *MountUnitOptions) (string, error) {
 	daemonReloadLock.Lock()
 	defer daemonReloadLock.Unlock()
 
-	mountUnitName, modified, err := ensureMountUnitFile(unitOptions)
+	mountUnitPath, modified, err := ensureMountUnitFile(unitOptions)
 	if err != nil {
 		return "", err
 	}
+	mountUnitName := filepath.Base(mountUnitPath)
 	if modified != mountUnchanged {
 		// we need to do a daemon-reload here to ensure that systemd really
 		// knows about this new mount unit file
@@ -1490,8 +1533,10 @@ func (s *systemd) EnsureMountUnitFileWithOptions(unitOptions *MountUnitOptions)
 		}
 
 		units := []string{mountUnitName}
-		if err := s.EnableNoReload(units); err != nil {
-			return "", err
+		if !isGeneratedMountUnit(mountUnitPath) {
+			if err := s.EnableNoReload(units); err != nil {
+				return "", err
+			}
 		}
 		// In the case of mountCreated, ReloadOrRestart
 		// has the same effect as just Start.
@@ -1532,8 +1577,10 @@ func (s *systemd) RemoveMountUnitFile(mountedDir string) error {
 			return err
 		}
 	}
-	if err := s.DisableNoReload(units); err != nil {
-		return err
+	if !isGeneratedMountUnit(unit) {
+		if err := s.DisableNoReload(units); err != nil {
+			return err
+		}
 	}
 	if err := os.Remove(unit); err != nil {
 		return err
@@ -0,0 +1,166 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package timings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Aggregate summarizes the durations of the timings with the same labels,
+// across all the executions of an activity.
+type Aggregate struct {
+	// Group is the value of the tag the timings were grouped by, if any.
+	Group string `json:"group,omitempty"`
+	// Labels are the labels of the timing and its parents, starting with
+	// the activity (the task kind, ensure or startup) the timing is part of.
+	Labels []string      `json:"labels"`
+	Count  int           `json:"count"`
+	Total  time.Duration `json:"total"`
+	Min    time.Duration `json:"min"`
+	Max    time.Duration `json:"max"`
+}
+
+// Average returns the average duration of the aggregated timings.
+func (a *Aggregate) Average() time.Duration {
+	if a.Count == 0 {
+		return 0
+	}
+	return a.Total / time.Duration(a.Count)
+}
+
+func (a *Aggregate) add(d time.Duration) {
+	if a.Count == 0 || d < a.Min {
+		a.Min = d
+	}
+	if d > a.Max {
+		a.Max = d
+	}
+	a.Count++
+	a.Total += d
+}
+
+// activityLabel returns the label describing the activity that the timings
+// with the given tags measured.
+func activityLabel(tags map[string]string) string {
+	if kind := tags["task-kind"]; kind != "" {
+		return kind
+	}
+	if ensure := tags["ensure"]; ensure != "" {
+		return "ensure:" + ensure
+	}
+	if startup := tags["startup"]; startup != "" {
+		return "startup:" + startup
+	}
+	return "unknown"
+}
+
+// AggregateTimings aggregates the given timings, as returned by Get with
+// no level filtering, by their activity and labels. If groupBy is not
+// empty, timings are additionally grouped by the value of that tag, and
+// timings without it are skipped. The result is sorted by group and labels.
+func AggregateTimings(infos []*TimingsInfo, groupBy string) []*Aggregate {
+	aggs := make(map[string]*Aggregate)
+	aggregate := func(group string, labels []string, d time.Duration) {
+		key := group + "\x00" + strings.Join(labels, "\x00")
+		agg := aggs[key]
+		if agg == nil {
+			agg = &Aggregate{
+				Group:  group,
+				Labels: append([]string(nil), labels...),
+			}
+			aggs[key] = agg
+		}
+		agg.add(d)
+	}
+
+	for _, info := range infos {
+		var group string
+		if groupBy != "" {
+			var ok bool
+			if group, ok = info.Tags[groupBy]; !ok {
+				continue
+			}
+		}
+		labels := []string{activityLabel(info.Tags)}
+		aggregate(group, labels, info.Duration)
+		for _, tm := range info.NestedTimings {
+			// timings are flattened depth first, so the parents of a
+			// timing are the last ones seen at each lower level
+			if tm.Level+1 < len(labels) {
+				labels = labels[:tm.Level+1]
+			}
+			labels = append(labels, tm.Label)
+			aggregate(group, labels, tm.Duration)
+		}
+	}
+
+	result := make([]*Aggregate, 0, len(aggs))
+	for _, agg := range aggs {
+		result = append(result, agg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Group != result[j].Group {
+			return result[i].Group < result[j].Group
+		}
+		return strings.Join(result[i].Labels, "\x00") < strings.Join(result[j].Labels, "\x00")
+	})
+	return result
+}
+
+// FoldedStacks returns the given aggregates in the folded stacks format
+// understood by flamegraph tools: one line per aggregate with its group and
+// labels separated by semicolons, followed by the total time in microseconds
+// spent in it but not in any of its children.
+func FoldedStacks(aggs []*Aggregate) []string {
+	childrenTotal := make(map[string]time.Duration)
+	for _, agg := range aggs {
+		if len(agg.Labels) > 1 {
+			childrenTotal[stackKey(agg.Group, agg.Labels[:len(agg.Labels)-1])] += agg.Total
+		}
+	}
+
+	var lines []string
+	for _, agg := range aggs {
+		key := stackKey(agg.Group, agg.Labels)
+		self := agg.Total - childrenTotal[key]
+		if self <= 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s %d", key, self/time.Microsecond))
+	}
+	return lines
+}
+
+// semicolons and spaces are meaningful in the folded format
+var frameReplacer = strings.NewReplacer(";", ":", " ", "_")
+
+func stackKey(group string, labels []string) string {
+	frames := make([]string, 0, len(labels)+1)
+	if group != "" {
+		frames = append(frames, frameReplacer.Replace(group))
+	}
+	for _, label := range labels {
+		frames = append(frames, frameReplacer.Replace(label))
+	}
+	return strings.Join(frames, ";")
+}
@@ -0,0 +1,104 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package timings_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/timings"
+)
+
+type aggregateSuite struct{}
+
+var _ = Suite(&aggregateSuite{})
+
+func (s *aggregateSuite) TestAggregateTimings(c *C) {
+	infos := []*timings.TimingsInfo{
+		{
+			Tags:     map[string]string{"task-kind": "mount-snap", "snap-size": "<16MB"},
+			Duration: 10 * time.Millisecond,
+			NestedTimings: []*timings.TimingJSON{
+				{Level: 0, Label: "check-snap", Duration: 2 * time.Millisecond},
+				{Level: 0, Label: "setup-snap", Duration: 6 * time.Millisecond},
+				{Level: 1, Label: "unpack", Duration: 4 * time.Millisecond},
+			},
+		}, {
+			Tags:     map[string]string{"task-kind": "mount-snap", "snap-size": "<16MB"},
+			Duration: 20 * time.Millisecond,
+			NestedTimings: []*timings.TimingJSON{
+				{Level: 0, Label: "setup-snap", Duration: 10 * time.Millisecond},
+				{Level: 1, Label: "unpack", Duration: 8 * time.Millisecond},
+			},
+		}, {
+			Tags:     map[string]string{"task-kind": "mount-snap", "snap-size": "<1GB"},
+			Duration: 100 * time.Millisecond,
+			NestedTimings: []*timings.TimingJSON{
+				{Level: 0, Label: "setup-snap", Duration: 90 * time.Millisecond},
+			},
+		}, {
+			Tags:     map[string]string{"ensure": "auto-refresh"},
+			Duration: 5 * time.Millisecond,
+		},
+	}
+
+	aggs := timings.AggregateTimings(infos, "")
+	c.Check(aggs, DeepEquals, []*timings.Aggregate{
+		{Labels: []string{"ensure:auto-refresh"}, Count: 1, Total: 5 * time.Millisecond, Min: 5 * time.Millisecond, Max: 5 * time.Millisecond},
+		{Labels: []string{"mount-snap"}, Count: 3, Total: 130 * time.Millisecond, Min: 10 * time.Millisecond, Max: 100 * time.Millisecond},
+		{Labels: []string{"mount-snap", "check-snap"}, Count: 1, Total: 2 * time.Millisecond, Min: 2 * time.Millisecond, Max: 2 * time.Millisecond},
+		{Labels: []string{"mount-snap", "setup-snap"}, Count: 3, Total: 106 * time.Millisecond, Min: 6 * time.Millisecond, Max: 90 * time.Millisecond},
+		{Labels: []string{"mount-snap", "setup-snap", "unpack"}, Count: 2, Total: 12 * time.Millisecond, Min: 4 * time.Millisecond, Max: 8 * time.Millisecond},
+	})
+	c.Check(aggs[3].Average(), Equals, 106*time.Millisecond/3)
+
+	aggs = timings.AggregateTimings(infos, "snap-size")
+	c.Check(aggs, DeepEquals, []*timings.Aggregate{
+		{Group: "<16MB", Labels: []string{"mount-snap"}, Count: 2, Total: 30 * time.Millisecond, Min: 10 * time.Millisecond, Max: 20 * time.Millisecond},
+		{Group: "<16MB", Labels: []string{"mount-snap", "check-snap"}, Count: 1, Total: 2 * time.Millisecond, Min: 2 * time.Millisecond, Max: 2 * time.Millisecond},
+		{Group: "<16MB", Labels: []string{"mount-snap", "setup-snap"}, Count: 2, Total: 16 * time.Millisecond, Min: 6 * time.Millisecond, Max: 10 * time.Millisecond},
+		{Group: "<16MB", Labels: []string{"mount-snap", "setup-snap", "unpack"}, Count: 2, Total: 12 * time.Millisecond, Min: 4 * time.Millisecond, Max: 8 * time.Millisecond},
+		{Group: "<1GB", Labels: []string{"mount-snap"}, Count: 1, Total: 100 * time.Millisecond, Min: 100 * time.Millisecond, Max: 100 * time.Millisecond},
+		{Group: "<1GB", Labels: []string{"mount-snap", "setup-snap"}, Count: 1, Total: 90 * time.Millisecond, Min: 90 * time.Millisecond, Max: 90 * time.Millisecond},
+	})
+
+	c.Check(timings.FoldedStacks(aggs), DeepEquals, []string{
+		"<16MB;mount-snap 12000",
+		"<16MB;mount-snap;check-snap 2000",
+		"<16MB;mount-snap;setup-snap 4000",
+		"<16MB;mount-snap;setup-snap;unpack 12000",
+		"<1GB;mount-snap 10000",
+		"<1GB;mount-snap;setup-snap 90000",
+	})
+}
+
+func (s *aggregateSuite) TestAggregateEmpty(c *C) {
+	c.Check(timings.AggregateTimings(nil, ""), HasLen, 0)
+	c.Check(timings.FoldedStacks(nil), HasLen, 0)
+	c.Check((&timings.Aggregate{}).Average(), Equals, time.Duration(0))
+}
+
+func (s *aggregateSuite) TestFoldedStacksEscaping(c *C) {
+	aggs := []*timings.Aggregate{
+		{Group: "a group", Labels: []string{"kind", "label;with separators"}, Count: 1, Total: time.Millisecond},
+	}
+	c.Check(timings.FoldedStacks(aggs), DeepEquals, []string{"a_group;kind;label:with_separators 1000"})
+}
@@ -54,6 +54,15 @@ type TimingsInfo struct {
 // Maximum number of timings to keep in state. It can be changed only while holding state lock.
 var MaxTimings = 100
 
+// Maximum age of timings kept in state. When non-zero, timings are retained
+// for MaxAge rather than limited to MaxTimings, but still up to
+// MaxRetainedTimings. It can be changed only while holding state lock.
+var MaxAge time.Duration
+
+// Maximum number of timings to keep in state when retaining them by age.
+// It can be changed only while holding state lock.
+var MaxRetainedTimings = 2000
+
 // Duration threshold - timings below the threshold will not be saved in the state.
 // It can be changed only while holding state lock.
 var DurationThreshold = 5 * time.Millisecond
@@ -149,12 +158,35 @@ func (t *Timings) Save(s GetSaver) {
 	entryJSON := json.RawMessage(serialized)
 
 	stateTimings = append(stateTimings, &entryJSON)
-	if len(stateTimings) > MaxTimings {
-		stateTimings = stateTimings[len(stateTimings)-MaxTimings:]
+	maxTimings := MaxTimings
+	if MaxAge > 0 {
+		stateTimings = purgeExpired(stateTimings, timeNow().Add(-MaxAge))
+		maxTimings = MaxRetainedTimings
+	}
+	if len(stateTimings) > maxTimings {
+		stateTimings = stateTimings[len(stateTimings)-maxTimings:]
 	}
 	s.SaveTimings(stateTimings)
 }
 
+// purgeExpired drops the timings which stopped before the given cutoff.
+func purgeExpired(stateTimings []*json.RawMessage, cutoff time.Time) []*json.RawMessage {
+	kept := stateTimings[:0]
+	for _, raw := range stateTimings {
+		var tm rootTimingsJSON
+		if err := json.Unmarshal(*raw, &tm); err != nil {
+			logger.Noticef("could not unmarshal timings: %v", err)
+			continue
+		}
+		// timings of ensures without measurements have no stop time
+		if !tm.StopTime.IsZero() && tm.StopTime.Before(cutoff) {
+			continue
+		}
+		kept = append(kept, raw)
+	}
+	return kept
+}
+
 // Get returns timings for which filter predicate is true and filters
 // out nested timings whose level is greater than maxLevel.
 // Negative maxLevel value disables filtering by level.
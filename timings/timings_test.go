@@ -327,6 +327,49 @@ func (s *timingsSuite) TestPurgeOnSave(c *C) {
 	c.Check(stateTimings[2].(map[string]interface{})["tags"], DeepEquals, map[string]interface{}{"number": "9"})
 }
 
+func (s *timingsSuite) TestPurgeByAgeOnSave(c *C) {
+	oldMaxTimings := timings.MaxTimings
+	oldMaxAge := timings.MaxAge
+	oldMaxRetainedTimings := timings.MaxRetainedTimings
+	timings.MaxTimings = 3
+	timings.MaxAge = time.Hour
+	timings.MaxRetainedTimings = 6
+	defer func() {
+		timings.MaxTimings = oldMaxTimings
+		timings.MaxAge = oldMaxAge
+		timings.MaxRetainedTimings = oldMaxRetainedTimings
+	}()
+
+	s.st.Lock()
+	defer s.st.Unlock()
+
+	for i := 0; i < 10; i++ {
+		if i == 2 {
+			// the first two timings are now older than MaxAge
+			s.fakeTime = s.fakeTime.Add(2 * time.Hour)
+		}
+		t := timings.New(map[string]string{"number": fmt.Sprintf("%d", i)})
+		m := t.StartSpan("...", "...")
+		m.Stop()
+		t.Save(s.st)
+		if i == 5 {
+			var stateTimings []interface{}
+			c.Assert(s.st.Get("timings", &stateTimings), IsNil)
+			// MaxTimings does not apply when retaining by age
+			c.Check(stateTimings, HasLen, 4)
+		}
+	}
+
+	var stateTimings []interface{}
+	c.Assert(s.st.Get("timings", &stateTimings), IsNil)
+
+	// expired timings got dropped, then the ones exceeding MaxRetainedTimings
+	c.Assert(stateTimings, HasLen, 6)
+	for i, tm := range stateTimings {
+		c.Check(tm.(map[string]interface{})["tags"], DeepEquals, map[string]interface{}{"number": fmt.Sprintf("%d", i+4)})
+	}
+}
+
 func (s *timingsSuite) TestGet(c *C) {
 	s.st.Lock()
 	defer s.st.Unlock()
@@ -25,10 +25,12 @@ import (
 
 var (
 	SessionInfoCmd                = sessionInfoCmd
+	HomeInfoCmd                   = homeInfoCmd
 	ServiceControlCmd             = serviceControlCmd
 	ServiceStatusCmd              = serviceStatusCmd
 	PendingRefreshNotificationCmd = pendingRefreshNotificationCmd
 	FinishRefreshNotificationCmd  = finishRefreshNotificationCmd
+	AppLifecycleNotificationCmd   = appLifecycleNotificationCmd
 )
 
 func MockUcred(ucred *syscall.Ucred, err error) (restore func()) {
@@ -49,3 +51,19 @@ func MockNoBus(agent *SessionAgent) (restore func()) {
 		agent.bus = bus
 	}
 }
+
+func MockHomeDir(f func() (string, error)) (restore func()) {
+	old := osUserHomeDir
+	osUserHomeDir = f
+	return func() {
+		osUserHomeDir = old
+	}
+}
+
+func MockMountedFilesystemType(f func(path string) (string, error)) (restore func()) {
+	old := osutilMountedFilesystemType
+	osutilMountedFilesystemType = f
+	return func() {
+		osutilMountedFilesystemType = old
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"mime"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -34,6 +35,8 @@ import (
 	"github.com/snapcore/snapd/desktop/notification"
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/sandbox/apparmor"
 	"github.com/snapcore/snapd/strutil"
 	"github.com/snapcore/snapd/systemd"
 	"github.com/snapcore/snapd/usersession/client"
@@ -42,10 +45,12 @@ import (
 var restApi = []*Command{
 	rootCmd,
 	sessionInfoCmd,
+	homeInfoCmd,
 	serviceControlCmd,
 	serviceStatusCmd,
 	pendingRefreshNotificationCmd,
 	finishRefreshNotificationCmd,
+	appLifecycleNotificationCmd,
 }
 
 var (
@@ -59,6 +64,11 @@ var (
 		GET:  sessionInfo,
 	}
 
+	homeInfoCmd = &Command{
+		Path: "/v1/home-info",
+		GET:  homeInfo,
+	}
+
 	serviceControlCmd = &Command{
 		Path: "/v1/service-control",
 		POST: postServiceControl,
@@ -78,6 +88,11 @@ var (
 		Path: "/v1/notifications/finish-refresh",
 		POST: postRefreshFinishedNotification,
 	}
+
+	appLifecycleNotificationCmd = &Command{
+		Path: "/v1/notifications/app-lifecycle",
+		POST: postAppLifecycleNotification,
+	}
 )
 
 func sessionInfo(c *Command, r *http.Request) Response {
@@ -87,6 +102,37 @@ func sessionInfo(c *Command, r *http.Request) Response {
 	return SyncResponse(m)
 }
 
+var (
+	osUserHomeDir               = os.UserHomeDir
+	osutilMountedFilesystemType = osutil.MountedFilesystemType
+)
+
+// homeInfo reports the filesystem holding the home directory of the user of
+// the session, as mounted when asked, and whether snaps can access it. Homes
+// on network filesystems, or mounted by autofs, are only accessible if
+// snapd granted snap-confine the permissions they need.
+func homeInfo(c *Command, r *http.Request) Response {
+	home, err := osUserHomeDir()
+	if err != nil {
+		return InternalError("cannot determine home directory: %v", err)
+	}
+	fsType, err := osutilMountedFilesystemType(home)
+	if err != nil {
+		return InternalError("cannot determine filesystem of %q: %v", home, err)
+	}
+	info := client.HomeInfo{
+		Home:       home,
+		Filesystem: fsType,
+		Remote:     osutil.IsRemoteFilesystem(fsType),
+		Supported:  true,
+	}
+	// the snap-confine policy only exists when AppArmor is used
+	if info.Remote && osutil.IsDirectory(apparmor.SnapConfineAppArmorDir) {
+		info.Supported = osutil.FileExists(filepath.Join(apparmor.SnapConfineAppArmorDir, "nfs-support"))
+	}
+	return SyncResponse(info)
+}
+
 type serviceInstruction struct {
 	Action   string   `json:"action"`
 	Services []string `json:"services"`
@@ -348,6 +394,20 @@ func serviceStatus(c *Command, r *http.Request) Response {
 	return SyncResponse(unitStatusToClientUnitStatus(stss))
 }
 
+// desktopEntryIcon returns the icon of the given snap desktop entry, if any.
+func desktopEntryIcon(desktopEntry string) string {
+	if desktopEntry == "" {
+		return ""
+	}
+	parser := goconfigparser.New()
+	desktopFilePath := filepath.Join(dirs.SnapDesktopFilesDir, desktopEntry+".desktop")
+	if err := parser.ReadFile(desktopFilePath); err != nil {
+		return ""
+	}
+	icon, _ := parser.Get("Desktop Entry", "Icon")
+	return icon
+}
+
 func postPendingRefreshNotification(c *Command, r *http.Request) Response {
 	if ok, resp := validateJSONRequest(r); !ok {
 		return resp
@@ -401,13 +461,7 @@ func postPendingRefreshNotification(c *Command, r *http.Request) Response {
 	// The notification is provided by snapd session agent.
 	hints = append(hints, notification.WithDesktopEntry("io.snapcraft.SessionAgent"))
 	// But if we have a desktop file of the busy application, use that apps's icon.
-	if refreshInfo.BusyAppDesktopEntry != "" {
-		parser := goconfigparser.New()
-		desktopFilePath := filepath.Join(dirs.SnapDesktopFilesDir, refreshInfo.BusyAppDesktopEntry+".desktop")
-		if err := parser.ReadFile(desktopFilePath); err == nil {
-			icon, _ = parser.Get("Desktop Entry", "Icon")
-		}
-	}
+	icon = desktopEntryIcon(refreshInfo.BusyAppDesktopEntry)
 
 	msg := &notification.Message{
 		AppName: refreshInfo.BusyAppName,
@@ -477,3 +531,80 @@ func postRefreshFinishedNotification(c *Command, r *http.Request) Response {
 	}
 	return SyncResponse(nil)
 }
+
+const (
+	sessionAgentObjectPath = "/io/snapcraft/SessionAgent"
+	appLifecycleSignalName = "io.snapcraft.SessionAgent.AppLifecycle"
+)
+
+func postAppLifecycleNotification(c *Command, r *http.Request) Response {
+	if ok, resp := validateJSONRequest(r); !ok {
+		return resp
+	}
+
+	decoder := json.NewDecoder(r.Body)
+
+	var lifecycleInfo client.AppLifecycleInfo
+	if err := decoder.Decode(&lifecycleInfo); err != nil {
+		return BadRequest("cannot decode request body into app lifecycle info: %v", err)
+	}
+	switch lifecycleInfo.Event {
+	case client.AppLifecycleCloseRequest, client.AppLifecycleRestartAvailable:
+	default:
+		return BadRequest("unknown app lifecycle event %q", lifecycleInfo.Event)
+	}
+
+	// Note that since the connection is shared, we are not closing it.
+	if c.s.bus == nil {
+		return SyncResponse(&resp{
+			Type:   ResponseTypeError,
+			Status: 500,
+			Result: &errorResult{
+				Message: "cannot connect to the session bus",
+			},
+		})
+	}
+
+	// Desktop environments listen for the signal to prompt the user in a
+	// way that fits the shell.
+	err := c.s.bus.Emit(sessionAgentObjectPath, appLifecycleSignalName,
+		lifecycleInfo.InstanceName, lifecycleInfo.Event, lifecycleInfo.BusyAppDesktopEntry,
+		int64(lifecycleInfo.TimeRemaining.Seconds()))
+	if err != nil {
+		return SyncResponse(&resp{
+			Type:   ResponseTypeError,
+			Status: 500,
+			Result: &errorResult{
+				Message: fmt.Sprintf("cannot emit app lifecycle signal: %v", err),
+			},
+		})
+	}
+
+	// The user is already told to close the application by the pending
+	// refresh notification, only the restart needs a notification of its
+	// own.
+	if lifecycleInfo.Event != client.AppLifecycleRestartAvailable {
+		return SyncResponse(nil)
+	}
+
+	msg := &notification.Message{
+		AppName: lifecycleInfo.BusyAppName,
+		Title:   fmt.Sprintf(i18n.G("%s was updated."), lifecycleInfo.InstanceName),
+		Icon:    desktopEntryIcon(lifecycleInfo.BusyAppDesktopEntry),
+		Body:    i18n.G("Restart the application to use the new version."),
+		Hints: []notification.Hint{
+			notification.WithDesktopEntry("io.snapcraft.SessionAgent"),
+			notification.WithUrgency(notification.NormalUrgency),
+		},
+	}
+	if err := c.s.notificationMgr.SendNotification(notification.ID(lifecycleInfo.InstanceName), msg); err != nil {
+		return SyncResponse(&resp{
+			Type:   ResponseTypeError,
+			Status: 500,
+			Result: &errorResult{
+				Message: fmt.Sprintf("cannot send notification message: %v", err),
+			},
+		})
+	}
+	return SyncResponse(nil)
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/snapcore/snapd/desktop/notification"
 	"github.com/snapcore/snapd/desktop/notification/notificationtest"
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/sandbox/apparmor"
 	"github.com/snapcore/snapd/systemd"
 	"github.com/snapcore/snapd/testutil"
 	"github.com/snapcore/snapd/usersession/agent"
@@ -112,6 +113,80 @@ func (s *restSuite) TestSessionInfo(c *C) {
 	})
 }
 
+func (s *restSuite) TestHomeInfo(c *C) {
+	// the agent.HomeInfo end point only supports GET requests
+	c.Check(agent.HomeInfoCmd.PUT, IsNil)
+	c.Check(agent.HomeInfoCmd.POST, IsNil)
+	c.Check(agent.HomeInfoCmd.DELETE, IsNil)
+	c.Assert(agent.HomeInfoCmd.GET, NotNil)
+
+	c.Check(agent.HomeInfoCmd.Path, Equals, "/v1/home-info")
+
+	restore := agent.MockHomeDir(func() (string, error) { return "/home/foo", nil })
+	defer restore()
+	fsType := "ext4"
+	restore = agent.MockMountedFilesystemType(func(path string) (string, error) {
+		c.Check(path, Equals, "/home/foo")
+		return fsType, nil
+	})
+	defer restore()
+
+	confineDir := apparmor.SnapConfineAppArmorDir
+	for _, tc := range []struct {
+		fsType      string
+		policy      []string
+		remote      bool
+		supported   bool
+		description string
+	}{
+		{"ext4", nil, false, true, "local home"},
+		{"nfs4", nil, true, true, "remote home without AppArmor"},
+		{"nfs4", []string{"homedirs"}, true, false, "remote home without NFS support"},
+		{"autofs", []string{"homedirs", "nfs-support"}, true, true, "remote home with NFS support"},
+	} {
+		c.Assert(os.RemoveAll(confineDir), IsNil)
+		for _, name := range tc.policy {
+			c.Assert(os.MkdirAll(confineDir, 0755), IsNil)
+			c.Assert(os.WriteFile(filepath.Join(confineDir, name), nil, 0644), IsNil)
+		}
+		fsType = tc.fsType
+
+		rec := httptest.NewRecorder()
+		agent.HomeInfoCmd.GET(agent.HomeInfoCmd, nil).ServeHTTP(rec, nil)
+		c.Check(rec.Code, Equals, 200, Commentf(tc.description))
+
+		var rsp resp
+		c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), IsNil)
+		c.Check(rsp.Type, Equals, agent.ResponseTypeSync)
+		c.Check(rsp.Result, DeepEquals, map[string]interface{}{
+			"home":       "/home/foo",
+			"filesystem": tc.fsType,
+			"remote":     tc.remote,
+			"supported":  tc.supported,
+		}, Commentf(tc.description))
+	}
+}
+
+func (s *restSuite) TestHomeInfoError(c *C) {
+	restore := agent.MockHomeDir(func() (string, error) { return "/home/foo", nil })
+	defer restore()
+	restore = agent.MockMountedFilesystemType(func(path string) (string, error) {
+		return "", errors.New("boom")
+	})
+	defer restore()
+
+	rec := httptest.NewRecorder()
+	agent.HomeInfoCmd.GET(agent.HomeInfoCmd, nil).ServeHTTP(rec, nil)
+	c.Check(rec.Code, Equals, 500)
+
+	var rsp resp
+	c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), IsNil)
+	c.Check(rsp.Type, Equals, agent.ResponseTypeError)
+	c.Check(rsp.Result, DeepEquals, map[string]interface{}{
+		"message": `cannot determine filesystem of "/home/foo": boom`,
+	})
+}
+
 func (s *restSuite) TestServiceControl(c *C) {
 	// the agent.Services end point only supports POST requests
 	c.Assert(agent.ServiceControlCmd.GET, IsNil)
@@ -903,3 +978,105 @@ func (s *restSuite) TestPostCloseRefreshNotification(c *C) {
 		"desktop-entry": dbus.MakeVariant("io.snapcraft.SessionAgent"),
 	})
 }
+
+func (s *restSuite) testPostAppLifecycleNotificationBody(c *C, lifecycleInfo *client.AppLifecycleInfo) *dbus.Signal {
+	c.Assert(s.SessionBus.AddMatchSignal(dbus.WithMatchInterface("io.snapcraft.SessionAgent"), dbus.WithMatchMember("AppLifecycle")), IsNil)
+	signals := make(chan *dbus.Signal, 10)
+	s.SessionBus.Signal(signals)
+	defer s.SessionBus.RemoveSignal(signals)
+
+	reqBody, err := json.Marshal(lifecycleInfo)
+	c.Assert(err, IsNil)
+	req := httptest.NewRequest("POST", "/v1/notifications/app-lifecycle", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	agent.AppLifecycleNotificationCmd.POST(agent.AppLifecycleNotificationCmd, req).ServeHTTP(rec, req)
+	c.Check(rec.Code, Equals, 200)
+
+	var rsp resp
+	c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), IsNil)
+	c.Check(rsp.Type, Equals, agent.ResponseTypeSync)
+	c.Check(rsp.Result, IsNil)
+
+	select {
+	case sig := <-signals:
+		return sig
+	case <-time.After(5 * time.Second):
+		c.Fatal("app lifecycle signal not received")
+	}
+	return nil
+}
+
+func (s *restSuite) TestPostAppLifecycleNotificationCloseRequest(c *C) {
+	sig := s.testPostAppLifecycleNotificationBody(c, &client.AppLifecycleInfo{
+		InstanceName:        "pkg",
+		Event:               client.AppLifecycleCloseRequest,
+		TimeRemaining:       2 * time.Hour,
+		BusyAppName:         "app",
+		BusyAppDesktopEntry: "pkg_app",
+	})
+	c.Check(string(sig.Path), Equals, "/io/snapcraft/SessionAgent")
+	c.Check(sig.Name, Equals, "io.snapcraft.SessionAgent.AppLifecycle")
+	c.Check(sig.Body, DeepEquals, []interface{}{"pkg", "close-request", "pkg_app", int64(7200)})
+
+	// the user is prompted by the pending refresh notification instead
+	c.Check(s.notify.GetAll(), HasLen, 0)
+}
+
+func (s *restSuite) TestPostAppLifecycleNotificationRestartAvailable(c *C) {
+	// add a desktop file
+	desktopFilePath := filepath.Join(dirs.SnapDesktopFilesDir, "pkg_app.desktop")
+	c.Assert(os.MkdirAll(filepath.Dir(desktopFilePath), 0755), IsNil)
+	c.Assert(os.WriteFile(desktopFilePath, []byte("[Desktop Entry]\nIcon=app.png"), 0644), IsNil)
+
+	sig := s.testPostAppLifecycleNotificationBody(c, &client.AppLifecycleInfo{
+		InstanceName:        "pkg",
+		Event:               client.AppLifecycleRestartAvailable,
+		BusyAppName:         "app",
+		BusyAppDesktopEntry: "pkg_app",
+	})
+	c.Check(sig.Body, DeepEquals, []interface{}{"pkg", "restart-available", "pkg_app", int64(0)})
+
+	notifications := s.notify.GetAll()
+	c.Assert(notifications, HasLen, 1)
+	n := notifications[0]
+	c.Check(n.AppName, Equals, "app")
+	c.Check(n.Icon, Equals, "app.png")
+	c.Check(n.Summary, Equals, `pkg was updated.`)
+	c.Check(n.Body, Equals, "Restart the application to use the new version.")
+	c.Check(n.Hints, DeepEquals, map[string]dbus.Variant{
+		"urgency":       dbus.MakeVariant(byte(notification.NormalUrgency)),
+		"desktop-entry": dbus.MakeVariant("io.snapcraft.SessionAgent"),
+	})
+}
+
+func (s *restSuite) TestPostAppLifecycleNotificationUnknownEvent(c *C) {
+	req := httptest.NewRequest("POST", "/v1/notifications/app-lifecycle",
+		bytes.NewBufferString(`{"instance-name":"pkg","event":"explode"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	agent.AppLifecycleNotificationCmd.POST(agent.AppLifecycleNotificationCmd, req).ServeHTTP(rec, req)
+	c.Check(rec.Code, Equals, 400)
+
+	var rsp resp
+	c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), IsNil)
+	c.Check(rsp.Type, Equals, agent.ResponseTypeError)
+	c.Check(rsp.Result, DeepEquals, map[string]interface{}{"message": `unknown app lifecycle event "explode"`})
+}
+
+func (s *restSuite) TestPostAppLifecycleNotificationNoSessionBus(c *C) {
+	restore := agent.MockNoBus(s.agent)
+	defer restore()
+
+	req := httptest.NewRequest("POST", "/v1/notifications/app-lifecycle",
+		bytes.NewBufferString(`{"instance-name":"pkg","event":"close-request"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	agent.AppLifecycleNotificationCmd.POST(agent.AppLifecycleNotificationCmd, req).ServeHTTP(rec, req)
+	c.Check(rec.Code, Equals, 500)
+
+	var rsp resp
+	c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), IsNil)
+	c.Check(rsp.Type, Equals, agent.ResponseTypeError)
+	c.Check(rsp.Result, DeepEquals, map[string]interface{}{"message": "cannot connect to the session bus"})
+}
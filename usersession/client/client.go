@@ -235,6 +235,43 @@ func (client *Client) SessionInfo(ctx context.Context) (info map[int]SessionInfo
 	return info, err
 }
 
+// HomeInfo describes the home directory of the user of a session, and
+// whether snaps can access it.
+type HomeInfo struct {
+	Home       string `json:"home"`
+	Filesystem string `json:"filesystem"`
+	Remote     bool   `json:"remote"`
+	Supported  bool   `json:"supported"`
+}
+
+// HomeInfo returns, for each user session, the filesystem holding the home
+// directory of the user and whether snaps can access it.
+func (client *Client) HomeInfo(ctx context.Context) (info map[int]HomeInfo, err error) {
+	responses, err := client.doMany(ctx, "GET", "/v1/home-info", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	info = make(map[int]HomeInfo)
+	for _, resp := range responses {
+		if resp.err != nil {
+			if err == nil {
+				err = resp.err
+			}
+			continue
+		}
+		var hi HomeInfo
+		if decodeErr := json.Unmarshal(resp.Result, &hi); decodeErr != nil {
+			if err == nil {
+				err = decodeErr
+			}
+			continue
+		}
+		info[resp.uid] = hi
+	}
+	return info, err
+}
+
 type ServiceFailure struct {
 	Uid     int
 	Service string
@@ -407,3 +444,35 @@ func (client *Client) FinishRefreshNotification(ctx context.Context, closeInfo *
 	_, err = client.doMany(ctx, "POST", "/v1/notifications/finish-refresh", nil, headers, reqBody)
 	return err
 }
+
+const (
+	// AppLifecycleCloseRequest asks for the running applications of a
+	// snap to be closed so that a pending refresh can proceed.
+	AppLifecycleCloseRequest = "close-request"
+	// AppLifecycleRestartAvailable indicates that a snap was refreshed
+	// while its applications kept running, and restarting them will
+	// pick up the new revision.
+	AppLifecycleRestartAvailable = "restart-available"
+)
+
+// AppLifecycleInfo holds information about a lifecycle event of the running
+// applications of a snap provided to userd.
+type AppLifecycleInfo struct {
+	InstanceName        string        `json:"instance-name"`
+	Event               string        `json:"event"`
+	TimeRemaining       time.Duration `json:"time-remaining,omitempty"`
+	BusyAppName         string        `json:"busy-app-name,omitempty"`
+	BusyAppDesktopEntry string        `json:"busy-app-desktop-entry,omitempty"`
+}
+
+// AppLifecycleNotification broadcasts information about a lifecycle event of
+// the running applications of a snap.
+func (client *Client) AppLifecycleNotification(ctx context.Context, lifecycleInfo *AppLifecycleInfo) error {
+	headers := map[string]string{"Content-Type": "application/json"}
+	reqBody, err := json.Marshal(lifecycleInfo)
+	if err != nil {
+		return err
+	}
+	_, err = client.doMany(ctx, "POST", "/v1/notifications/app-lifecycle", nil, headers, reqBody)
+	return err
+}
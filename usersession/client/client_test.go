@@ -192,6 +192,47 @@ func (s *clientSuite) TestSessionInfoWrongResultType(c *C) {
 	c.Check(err, ErrorMatches, `json: cannot unmarshal array into Go value of type client.SessionInfo`)
 }
 
+func (s *clientSuite) TestHomeInfo(c *C) {
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v1/home-info")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{
+  "type": "sync",
+  "result": {
+    "home": "/home/foo",
+    "filesystem": "nfs4",
+    "remote": true,
+    "supported": false
+  }
+}`))
+	})
+	hi, err := s.cli.HomeInfo(context.Background())
+	c.Assert(err, IsNil)
+	expected := client.HomeInfo{Home: "/home/foo", Filesystem: "nfs4", Remote: true, Supported: false}
+	c.Check(hi, DeepEquals, map[int]client.HomeInfo{
+		42:   expected,
+		1000: expected,
+	})
+}
+
+func (s *clientSuite) TestHomeInfoError(c *C) {
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(500)
+		w.Write([]byte(`{
+  "type": "error",
+  "result": {
+    "message": "cannot determine home directory"
+  }
+}`))
+	})
+	hi, err := s.cli.HomeInfo(context.Background())
+	c.Check(hi, DeepEquals, map[int]client.HomeInfo{})
+	c.Check(err, ErrorMatches, "cannot determine home directory")
+}
+
 func (s *clientSuite) TestServicesDaemonReload(c *C) {
 	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -692,6 +733,28 @@ func (s *clientSuite) TestFinishRefreshNotification(c *C) {
 	c.Check(atomic.LoadInt32(&n), Equals, int32(2))
 }
 
+func (s *clientSuite) TestAppLifecycleNotification(c *C) {
+	var n int32
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&n, 1)
+		c.Assert(r.URL.Path, Equals, "/v1/notifications/app-lifecycle")
+		body, err := ioutil.ReadAll(r.Body)
+		c.Check(err, IsNil)
+		c.Check(string(body), Equals, `{"instance-name":"some-snap","event":"close-request","busy-app-name":"app","busy-app-desktop-entry":"some-snap_app"}`)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"type": "sync"}`))
+	})
+	err := s.cli.AppLifecycleNotification(context.Background(), &client.AppLifecycleInfo{
+		InstanceName:        "some-snap",
+		Event:               client.AppLifecycleCloseRequest,
+		BusyAppName:         "app",
+		BusyAppDesktopEntry: "some-snap_app",
+	})
+	c.Assert(err, IsNil)
+	c.Check(atomic.LoadInt32(&n), Equals, int32(2))
+}
+
 func (s *clientSuite) TestPendingRefreshNotificationOneClient(c *C) {
 	cli := client.NewForUids(1000)
 	var n int32
@@ -0,0 +1,427 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package userlocal implements the experimental installation of snaps by
+// unprivileged users into their home directory, without snapd.
+//
+// User-local snaps are kept in ~/snap-managed. Their squashfs is mounted
+// with squashfuse by a unit of the systemd instance of the user, and their
+// apps are run by launchers in ~/snap-managed/bin, in a private user and
+// mount namespace. As nothing is mediated by snapd, only snaps that would
+// work with the limited confinement this provides can be installed: no
+// services, no layouts, no slots, and only plugs of interfaces that are
+// connected automatically and not super-privileged.
+package userlocal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/snapcore/snapd/features"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snapfile"
+	"github.com/snapcore/snapd/systemd"
+)
+
+// allowedInterfaces lists the interfaces user-local snaps can plug. They are
+// all connected automatically for regular snaps and only give access to
+// what the user can already access.
+var allowedInterfaces = map[string]bool{
+	"audio-playback": true,
+	"desktop":        true,
+	"desktop-legacy": true,
+	"gsettings":      true,
+	"home":           true,
+	"network":        true,
+	"network-bind":   true,
+	"opengl":         true,
+	"unity7":         true,
+	"wayland":        true,
+	"x11":            true,
+}
+
+var (
+	squashfuseCommand = "squashfuse"
+	fusermountCommand = "fusermount"
+)
+
+// RootDir returns the directory holding the user-local snaps of the user
+// with the given home directory.
+func RootDir(home string) string {
+	return filepath.Join(home, "snap-managed")
+}
+
+// BinDir returns the directory holding the launchers of the apps of the
+// user-local snaps, to be added to $PATH.
+func BinDir(home string) string {
+	return filepath.Join(RootDir(home), "bin")
+}
+
+func blobPath(home, name string, rev snap.Revision) string {
+	return filepath.Join(RootDir(home), "snaps", fmt.Sprintf("%s_%s.snap", name, rev))
+}
+
+func mountDir(home, name string, rev snap.Revision) string {
+	return filepath.Join(RootDir(home), "mount", name, rev.String())
+}
+
+func dataDir(home, name string) string {
+	return filepath.Join(RootDir(home), "data", name)
+}
+
+func unitsDir(home string) string {
+	return filepath.Join(home, ".config", "systemd", "user")
+}
+
+func unitName(name string, rev snap.Revision) string {
+	return fmt.Sprintf("snap-managed-%s-%s.service", name, rev)
+}
+
+// CheckSnap checks whether the given snap can be installed as a user-local
+// snap.
+func CheckSnap(info *snap.Info) error {
+	name := info.InstanceName()
+	if info.Type() != snap.TypeApp {
+		return fmt.Errorf("cannot install snap %q locally: snaps of type %q are not supported", name, info.Type())
+	}
+	if info.Confinement != snap.StrictConfinement {
+		return fmt.Errorf("cannot install snap %q locally: %s confinement is not supported", name, info.Confinement)
+	}
+	for _, app := range info.Apps {
+		if app.IsService() {
+			return fmt.Errorf("cannot install snap %q locally: app %q is a service", name, app.Name)
+		}
+	}
+	if len(info.Layout) > 0 {
+		return fmt.Errorf("cannot install snap %q locally: layouts are not supported", name)
+	}
+	if len(info.Slots) > 0 {
+		return fmt.Errorf("cannot install snap %q locally: slots are not supported", name)
+	}
+	for _, plug := range info.Plugs {
+		if !allowedInterfaces[plug.Interface] {
+			return fmt.Errorf("cannot install snap %q locally: interface %q is not supported", name, plug.Interface)
+		}
+	}
+	return nil
+}
+
+// LocalSnap describes an installed user-local snap.
+type LocalSnap struct {
+	Name     string
+	Revision snap.Revision
+	Version  string
+	Apps     []string
+}
+
+// Installed returns the user-local snaps installed by the user with the
+// given home directory, sorted by name.
+func Installed(home string) ([]*LocalSnap, error) {
+	blobs, err := filepath.Glob(filepath.Join(RootDir(home), "snaps", "*.snap"))
+	if err != nil {
+		return nil, err
+	}
+	var snaps []*LocalSnap
+	for _, blob := range blobs {
+		info, err := readInfo(blob)
+		if err != nil {
+			return nil, err
+		}
+		name, rev, err := parseBlobName(filepath.Base(blob))
+		if err != nil {
+			return nil, err
+		}
+		local := &LocalSnap{Name: name, Revision: rev, Version: info.Version}
+		for appName := range info.Apps {
+			local.Apps = append(local.Apps, snap.JoinSnapApp(name, appName))
+		}
+		sort.Strings(local.Apps)
+		snaps = append(snaps, local)
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Name < snaps[j].Name })
+	return snaps, nil
+}
+
+func parseBlobName(base string) (string, snap.Revision, error) {
+	name, revStr, ok := strings.Cut(strings.TrimSuffix(base, ".snap"), "_")
+	if !ok {
+		return "", snap.Revision{}, fmt.Errorf("unexpected user-local snap file %q", base)
+	}
+	rev, err := snap.ParseRevision(revStr)
+	if err != nil {
+		return "", snap.Revision{}, fmt.Errorf("unexpected user-local snap file %q: %v", base, err)
+	}
+	return name, rev, nil
+}
+
+var readInfo = func(path string) (*snap.Info, error) {
+	snapf, err := snapfile.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return snap.ReadInfoFromSnapFile(snapf, nil)
+}
+
+func installedRevision(home, name string) (snap.Revision, error) {
+	snaps, err := Installed(home)
+	if err != nil {
+		return snap.Revision{}, err
+	}
+	for _, local := range snaps {
+		if local.Name == name {
+			return local.Revision, nil
+		}
+	}
+	return snap.Revision{}, nil
+}
+
+// Install installs the snap in the given file for the user with the given
+// home directory, replacing the revision installed before, if any. The
+// snap is mounted by the systemd instance of the user.
+func Install(home, snapPath string) (*snap.Info, error) {
+	if !features.UserLocalSnaps.IsEnabled() {
+		_, confName := features.UserLocalSnaps.ConfigOption()
+		return nil, fmt.Errorf("cannot install snaps locally: experimental feature disabled - set config option %q to true", confName)
+	}
+
+	info, err := readInfo(snapPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckSnap(info); err != nil {
+		return nil, err
+	}
+	name := info.SnapName()
+
+	// user-local snaps are unasserted, like snaps installed in dangerous mode
+	oldRev, err := installedRevision(home, name)
+	if err != nil {
+		return nil, err
+	}
+	rev := snap.R(-1)
+	if oldRev.Local() {
+		rev = snap.R(oldRev.N - 1)
+	}
+	info.SideInfo = snap.SideInfo{RealName: name, Revision: rev}
+
+	blob := blobPath(home, name, rev)
+	if err := os.MkdirAll(filepath.Dir(blob), 0755); err != nil {
+		return nil, err
+	}
+	if err := osutil.CopyFile(snapPath, blob, osutil.CopyFlagOverwrite|osutil.CopyFlagSync); err != nil {
+		return nil, fmt.Errorf("cannot copy snap %q: %v", name, err)
+	}
+	mnt := mountDir(home, name, rev)
+	if err := os.MkdirAll(mnt, 0755); err != nil {
+		return nil, err
+	}
+	revData := filepath.Join(dataDir(home, name), rev.String())
+	if !oldRev.Unset() {
+		// like snapd does for SNAP_USER_DATA, the data of the new
+		// revision starts as a copy of the one of the old revision
+		if err := copyData(filepath.Join(dataDir(home, name), oldRev.String()), revData); err != nil {
+			return nil, err
+		}
+	}
+	for _, dir := range []string{revData, filepath.Join(dataDir(home, name), "common")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	sysd := systemd.New(systemd.UserMode, nil)
+	if !oldRev.Unset() {
+		if err := unmount(home, sysd, name, oldRev); err != nil {
+			return nil, err
+		}
+	}
+	if err := mount(home, sysd, name, rev, blob, mnt); err != nil {
+		return nil, err
+	}
+	if err := writeLaunchers(home, info); err != nil {
+		return nil, err
+	}
+	if !oldRev.Unset() {
+		if err := os.Remove(blobPath(home, name, oldRev)); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err := os.RemoveAll(filepath.Join(dataDir(home, name), oldRev.String())); err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
+}
+
+// copyData copies the data directory of a revision to the one of
+// another, replacing it. Nothing is done if there is no data to copy.
+func copyData(oldPath, newPath string) error {
+	if _, err := os.Stat(oldPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := os.RemoveAll(newPath); err != nil {
+		return err
+	}
+	if err := osutil.CopyFile(oldPath, newPath, osutil.CopyFlagPreserveAll|osutil.CopyFlagSync); err != nil {
+		return fmt.Errorf("cannot copy %q to %q: %v", oldPath, newPath, err)
+	}
+	return nil
+}
+
+// Remove removes the user-local snap with the given name, with its data,
+// for the user with the given home directory.
+func Remove(home, name string) error {
+	rev, err := installedRevision(home, name)
+	if err != nil {
+		return err
+	}
+	if rev.Unset() {
+		return &snap.NotInstalledError{Snap: name}
+	}
+	if err := unmount(home, systemd.New(systemd.UserMode, nil), name, rev); err != nil {
+		return err
+	}
+	launchers, err := filepath.Glob(filepath.Join(BinDir(home), name))
+	if err != nil {
+		return err
+	}
+	apps, err := filepath.Glob(filepath.Join(BinDir(home), name+".*"))
+	if err != nil {
+		return err
+	}
+	for _, launcher := range append(launchers, apps...) {
+		if err := os.Remove(launcher); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(blobPath(home, name, rev)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(RootDir(home), "mount", name)); err != nil {
+		return err
+	}
+	return os.RemoveAll(dataDir(home, name))
+}
+
+func mount(home string, sysd systemd.Systemd, name string, rev snap.Revision, blob, mnt string) error {
+	unit := unitName(name, rev)
+	content := fmt.Sprintf(`[Unit]
+Description=Mount unit for user-local snap %s, revision %s
+
+[Service]
+ExecStart=%s -f %s %s
+ExecStop=%s -u %s
+
+[Install]
+WantedBy=default.target
+`, name, rev, squashfuseCommand, blob, mnt, fusermountCommand, mnt)
+	if err := os.MkdirAll(unitsDir(home), 0755); err != nil {
+		return err
+	}
+	if err := osutil.AtomicWriteFile(filepath.Join(unitsDir(home), unit), []byte(content), 0644, 0); err != nil {
+		return err
+	}
+	if err := sysd.DaemonReload(); err != nil {
+		return err
+	}
+	if err := sysd.EnableNoReload([]string{unit}); err != nil {
+		return err
+	}
+	if err := sysd.Start([]string{unit}); err != nil {
+		return fmt.Errorf("cannot mount snap %q: %v", name, err)
+	}
+	return nil
+}
+
+func unmount(home string, sysd systemd.Systemd, name string, rev snap.Revision) error {
+	unit := unitName(name, rev)
+	unitPath := filepath.Join(unitsDir(home), unit)
+	if !osutil.FileExists(unitPath) {
+		return nil
+	}
+	if err := sysd.Stop([]string{unit}); err != nil {
+		return fmt.Errorf("cannot unmount snap %q: %v", name, err)
+	}
+	if err := sysd.DisableNoReload([]string{unit}); err != nil {
+		return err
+	}
+	if err := os.Remove(unitPath); err != nil {
+		return err
+	}
+	if err := sysd.DaemonReload(); err != nil {
+		return err
+	}
+	return os.RemoveAll(mountDir(home, name, rev))
+}
+
+// writeLaunchers writes the launchers of the apps of the snap. Apps are run
+// in a private user and mount namespace, with a private /tmp, and with the
+// environment of strictly confined snaps.
+func writeLaunchers(home string, info *snap.Info) error {
+	if err := os.MkdirAll(BinDir(home), 0755); err != nil {
+		return err
+	}
+	name := info.SnapName()
+	rev := info.Revision
+	env := []string{
+		"SNAP=" + mountDir(home, name, rev),
+		"SNAP_NAME=" + name,
+		"SNAP_INSTANCE_NAME=" + name,
+		"SNAP_REVISION=" + rev.String(),
+		"SNAP_VERSION=" + info.Version,
+		"SNAP_USER_DATA=" + filepath.Join(dataDir(home, name), rev.String()),
+		"SNAP_USER_COMMON=" + filepath.Join(dataDir(home, name), "common"),
+		"SNAP_DATA=" + filepath.Join(dataDir(home, name), rev.String()),
+		"SNAP_COMMON=" + filepath.Join(dataDir(home, name), "common"),
+		"SNAP_REAL_HOME=" + home,
+		"HOME=" + filepath.Join(dataDir(home, name), rev.String()),
+	}
+	for _, app := range info.Apps {
+		var command []string
+		for _, chain := range app.CommandChain {
+			command = append(command, `"$SNAP"/`+chain)
+		}
+		command = append(command, `"$SNAP"/`+app.Command)
+		script := fmt.Sprintf(`mount -t tmpfs tmpfs /tmp && exec %s "$@"`, strings.Join(command, " "))
+
+		var buf strings.Builder
+		fmt.Fprintf(&buf, "#!/bin/sh\n# launcher of user-local snap app %s, generated by snap\n", snap.JoinSnapApp(name, app.Name))
+		for _, kv := range env {
+			key, value, _ := strings.Cut(kv, "=")
+			fmt.Fprintf(&buf, "export %s=%s\n", key, shellQuote(value))
+		}
+		fmt.Fprintf(&buf, "exec unshare --user --map-current-user --mount --propagation private /bin/sh -c %s %s \"$@\"\n", shellQuote(script), snap.JoinSnapApp(name, app.Name))
+
+		launcher := filepath.Join(BinDir(home), snap.JoinSnapApp(name, app.Name))
+		if err := osutil.AtomicWriteFile(launcher, []byte(buf.String()), 0755, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,252 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package userlocal_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/features"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/systemd"
+	"github.com/snapcore/snapd/testutil"
+	"github.com/snapcore/snapd/usersession/userlocal"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type userLocalSuite struct {
+	testutil.BaseTest
+
+	home       string
+	systemctl  [][]string
+	snapYamls  map[string]string
+	snapSource string
+}
+
+var _ = Suite(&userLocalSuite{})
+
+const fooYaml = `name: foo
+version: 1.0
+apps:
+  foo:
+    command: bin/foo
+  tool:
+    command: bin/tool --verbose
+    command-chain: [bin/wrapper]
+plugs:
+  network:
+  desktop:
+`
+
+func (s *userLocalSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+
+	s.home = c.MkDir()
+	s.systemctl = nil
+	s.AddCleanup(systemd.MockSystemctl(func(args ...string) ([]byte, error) {
+		s.systemctl = append(s.systemctl, args)
+		return []byte("ActiveState=inactive\n"), nil
+	}))
+
+	// snap files are identified by their content in tests
+	s.snapYamls = map[string]string{"foo": fooYaml}
+	s.AddCleanup(snap.MockSanitizePlugsSlots(func(snapInfo *snap.Info) {}))
+	s.AddCleanup(userlocal.MockReadInfo(func(path string) (*snap.Info, error) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return snap.InfoFromSnapYaml([]byte(s.snapYamls[string(content)]))
+	}))
+	s.snapSource = filepath.Join(c.MkDir(), "foo_1.0_amd64.snap")
+	c.Assert(os.WriteFile(s.snapSource, []byte("foo"), 0644), IsNil)
+
+	c.Assert(os.MkdirAll(dirs.FeaturesDir, 0755), IsNil)
+	c.Assert(os.WriteFile(features.UserLocalSnaps.ControlFile(), nil, 0644), IsNil)
+}
+
+func (s *userLocalSuite) TestInstall(c *C) {
+	info, err := userlocal.Install(s.home, s.snapSource)
+	c.Assert(err, IsNil)
+	c.Check(info.SnapName(), Equals, "foo")
+	c.Check(info.Revision, Equals, snap.R(-1))
+
+	root := filepath.Join(s.home, "snap-managed")
+	c.Check(filepath.Join(root, "snaps/foo_x1.snap"), testutil.FileEquals, "foo")
+	c.Check(filepath.Join(root, "mount/foo/x1"), testutil.FilePresent)
+	c.Check(filepath.Join(root, "data/foo/x1"), testutil.FilePresent)
+	c.Check(filepath.Join(root, "data/foo/common"), testutil.FilePresent)
+
+	unit := filepath.Join(s.home, ".config/systemd/user/snap-managed-foo-x1.service")
+	c.Check(unit, testutil.FileEquals, fmt.Sprintf(`[Unit]
+Description=Mount unit for user-local snap foo, revision x1
+
+[Service]
+ExecStart=squashfuse -f %[1]s/snaps/foo_x1.snap %[1]s/mount/foo/x1
+ExecStop=fusermount -u %[1]s/mount/foo/x1
+
+[Install]
+WantedBy=default.target
+`, root))
+	c.Check(s.systemctl, DeepEquals, [][]string{
+		{"--user", "daemon-reload"},
+		{"--user", "--no-reload", "enable", "snap-managed-foo-x1.service"},
+		{"--user", "start", "snap-managed-foo-x1.service"},
+	})
+
+	launcher := filepath.Join(root, "bin/foo.tool")
+	c.Check(launcher, testutil.FileContains, fmt.Sprintf("export SNAP='%s/mount/foo/x1'\n", root))
+	c.Check(launcher, testutil.FileContains, fmt.Sprintf("export HOME='%s/data/foo/x1'\n", root))
+	c.Check(launcher, testutil.FileContains, `exec unshare --user --map-current-user --mount --propagation private /bin/sh -c 'mount -t tmpfs tmpfs /tmp && exec "$SNAP"/bin/wrapper "$SNAP"/bin/tool --verbose "$@"' foo.tool "$@"`)
+	st, err := os.Stat(launcher)
+	c.Assert(err, IsNil)
+	c.Check(st.Mode().Perm(), Equals, os.FileMode(0755))
+	c.Check(filepath.Join(root, "bin/foo"), testutil.FileContains, `exec "$SNAP"/bin/foo "$@"`)
+
+	snaps, err := userlocal.Installed(s.home)
+	c.Assert(err, IsNil)
+	c.Check(snaps, DeepEquals, []*userlocal.LocalSnap{{
+		Name:     "foo",
+		Revision: snap.R(-1),
+		Version:  "1.0",
+		Apps:     []string{"foo", "foo.tool"},
+	}})
+}
+
+func (s *userLocalSuite) TestInstallLauncherQuoting(c *C) {
+	s.snapYamls["foo"] = `name: foo
+version: 1.0
+apps:
+  foo:
+    command: bin/foo --name 'it'"'"'s'
+`
+	_, err := userlocal.Install(s.home, s.snapSource)
+	c.Assert(err, IsNil)
+
+	launcher := filepath.Join(s.home, "snap-managed/bin/foo")
+	c.Check(launcher, testutil.FileContains, `/bin/sh -c 'mount -t tmpfs tmpfs /tmp && exec "$SNAP"/bin/foo --name '\''it'\''"'\''"'\''s'\'' "$@"' foo "$@"`)
+}
+
+func (s *userLocalSuite) TestInstallReplacesRevision(c *C) {
+	_, err := userlocal.Install(s.home, s.snapSource)
+	c.Assert(err, IsNil)
+	s.systemctl = nil
+
+	root := filepath.Join(s.home, "snap-managed")
+	c.Assert(os.MkdirAll(filepath.Join(root, "data/foo/x1/.config"), 0755), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(root, "data/foo/x1/.config/foo.conf"), []byte("conf"), 0600), IsNil)
+	c.Assert(os.WriteFile(filepath.Join(root, "data/foo/common/cache"), []byte("cache"), 0644), IsNil)
+
+	info, err := userlocal.Install(s.home, s.snapSource)
+	c.Assert(err, IsNil)
+	c.Check(info.Revision, Equals, snap.R(-2))
+
+	// the data was carried over to the new revision
+	c.Check(filepath.Join(root, "data/foo/x1"), testutil.FileAbsent)
+	c.Check(filepath.Join(root, "data/foo/x2/.config/foo.conf"), testutil.FileEquals, "conf")
+	st, err := os.Stat(filepath.Join(root, "data/foo/x2/.config/foo.conf"))
+	c.Assert(err, IsNil)
+	c.Check(st.Mode().Perm(), Equals, os.FileMode(0600))
+	c.Check(filepath.Join(root, "data/foo/common/cache"), testutil.FileEquals, "cache")
+
+	c.Check(filepath.Join(root, "snaps/foo_x1.snap"), testutil.FileAbsent)
+	c.Check(filepath.Join(root, "snaps/foo_x2.snap"), testutil.FilePresent)
+	c.Check(filepath.Join(root, "mount/foo/x1"), testutil.FileAbsent)
+	c.Check(filepath.Join(s.home, ".config/systemd/user/snap-managed-foo-x1.service"), testutil.FileAbsent)
+	c.Check(s.systemctl, DeepEquals, [][]string{
+		{"--user", "stop", "snap-managed-foo-x1.service"},
+		{"--user", "show", "--property=ActiveState", "snap-managed-foo-x1.service"},
+		{"--user", "--no-reload", "disable", "snap-managed-foo-x1.service"},
+		{"--user", "daemon-reload"},
+		{"--user", "daemon-reload"},
+		{"--user", "--no-reload", "enable", "snap-managed-foo-x2.service"},
+		{"--user", "start", "snap-managed-foo-x2.service"},
+	})
+}
+
+func (s *userLocalSuite) TestRemove(c *C) {
+	_, err := userlocal.Install(s.home, s.snapSource)
+	c.Assert(err, IsNil)
+
+	c.Assert(userlocal.Remove(s.home, "foo"), IsNil)
+
+	root := filepath.Join(s.home, "snap-managed")
+	for _, path := range []string{"snaps/foo_x1.snap", "mount/foo", "data/foo", "bin/foo", "bin/foo.tool"} {
+		c.Check(filepath.Join(root, path), testutil.FileAbsent)
+	}
+	c.Check(filepath.Join(s.home, ".config/systemd/user/snap-managed-foo-x1.service"), testutil.FileAbsent)
+
+	snaps, err := userlocal.Installed(s.home)
+	c.Assert(err, IsNil)
+	c.Check(snaps, HasLen, 0)
+
+	err = userlocal.Remove(s.home, "foo")
+	c.Check(err, ErrorMatches, `snap "foo" is not installed`)
+}
+
+func (s *userLocalSuite) TestInstallFeatureDisabled(c *C) {
+	c.Assert(os.Remove(features.UserLocalSnaps.ControlFile()), IsNil)
+
+	_, err := userlocal.Install(s.home, s.snapSource)
+	c.Check(err, ErrorMatches, `cannot install snaps locally: experimental feature disabled - set config option "experimental.user-local-snaps" to true`)
+}
+
+func (s *userLocalSuite) TestCheckSnap(c *C) {
+	for _, tc := range []struct {
+		yaml string
+		err  string
+	}{
+		{fooYaml, ""},
+		{"name: foo\nversion: 1\ntype: base\n", `cannot install snap "foo" locally: snaps of type "base" are not supported`},
+		{"name: foo\nversion: 1\nconfinement: classic\n", `cannot install snap "foo" locally: classic confinement is not supported`},
+		{"name: foo\nversion: 1\nconfinement: devmode\n", `cannot install snap "foo" locally: devmode confinement is not supported`},
+		{"name: foo\nversion: 1\napps:\n  svc:\n    command: bin/svc\n    daemon: simple\n", `cannot install snap "foo" locally: app "svc" is a service`},
+		{"name: foo\nversion: 1\nlayout:\n  /etc/foo:\n    bind: $SNAP/etc/foo\n", `cannot install snap "foo" locally: layouts are not supported`},
+		{"name: foo\nversion: 1\nslots:\n  dbus-svc:\n    interface: dbus\n    bus: session\n    name: org.foo\n", `cannot install snap "foo" locally: slots are not supported`},
+		{"name: foo\nversion: 1\nplugs:\n  system-files:\n", `cannot install snap "foo" locally: interface "system-files" is not supported`},
+		{"name: foo\nversion: 1\nplugs:\n  docker-support:\n", `cannot install snap "foo" locally: interface "docker-support" is not supported`},
+	} {
+		info := snaptest.MockInfo(c, tc.yaml, nil)
+		err := userlocal.CheckSnap(info)
+		if tc.err == "" {
+			c.Check(err, IsNil)
+		} else {
+			c.Check(err, ErrorMatches, tc.err)
+		}
+	}
+}
+
+func (s *userLocalSuite) TestInstallRejectsUnsupportedSnap(c *C) {
+	s.snapYamls["foo"] = "name: foo\nversion: 1\napps:\n  svc:\n    command: bin/svc\n    daemon: simple\n"
+
+	_, err := userlocal.Install(s.home, s.snapSource)
+	c.Check(err, ErrorMatches, `cannot install snap "foo" locally: app "svc" is a service`)
+	c.Check(filepath.Join(s.home, "snap-managed"), testutil.FileAbsent)
+	c.Check(s.systemctl, HasLen, 0)
+}
@@ -98,6 +98,67 @@ func snapServiceActivationFiles(dir, snapName string) (services []string, err er
 	return services, nil
 }
 
+// DBusActivationName is a D-Bus name an application of a snap is activated
+// on.
+type DBusActivationName struct {
+	Name string
+	// Bus is either "session" or "system".
+	Bus string
+	App *snap.AppInfo
+}
+
+// SnapDBusActivationNames returns the D-Bus names the services of the given
+// snap are activated on.
+func SnapDBusActivationNames(s *snap.Info) ([]DBusActivationName, error) {
+	var names []DBusActivationName
+	for _, app := range s.Apps {
+		if !app.IsService() {
+			continue
+		}
+
+		for _, slot := range app.ActivatesOn {
+			var busName string
+			if err := slot.Attr("name", &busName); err != nil {
+				return nil, err
+			}
+			switch app.DaemonScope {
+			case snap.SystemDaemon:
+				names = append(names, DBusActivationName{Name: busName, Bus: "system", App: app})
+			case snap.UserDaemon:
+				names = append(names, DBusActivationName{Name: busName, Bus: "session", App: app})
+			}
+		}
+	}
+	return names, nil
+}
+
+func hostDBusServicesDirs(bus string) []string {
+	if bus == "system" {
+		return []string{
+			filepath.Join(dirs.GlobalRootDir, "/usr/local/share/dbus-1/system-services"),
+			filepath.Join(dirs.GlobalRootDir, "/usr/share/dbus-1/system-services"),
+			filepath.Join(dirs.GlobalRootDir, "/lib/dbus-1/system-services"),
+		}
+	}
+	return []string{
+		filepath.Join(dirs.GlobalRootDir, "/usr/local/share/dbus-1/services"),
+		filepath.Join(dirs.GlobalRootDir, "/usr/share/dbus-1/services"),
+	}
+}
+
+// HostDBusActivationFile returns the path of the D-Bus activation file of
+// the host providing the given name on the given bus, which is either
+// "session" or "system", or "" if there is none.
+func HostDBusActivationFile(bus, name string) string {
+	for _, dir := range hostDBusServicesDirs(bus) {
+		path := filepath.Join(dir, name+".service")
+		if osutil.FileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
 func AddSnapDBusActivationFiles(s *snap.Info) error {
 	if err := os.MkdirAll(dirs.SnapDBusSessionServicesDir, 0755); err != nil {
 		return err
@@ -120,34 +181,27 @@ func AddSnapDBusActivationFiles(s *snap.Info) error {
 	sessionContent := make(map[string]osutil.FileState)
 	systemContent := make(map[string]osutil.FileState)
 
-	for _, app := range s.Apps {
-		if !app.IsService() {
-			continue
+	names, err := SnapDBusActivationNames(s)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		content, err := generateDBusActivationFile(name.App, name.Name)
+		if err != nil {
+			return err
 		}
-
-		for _, slot := range app.ActivatesOn {
-			var busName string
-			if err := slot.Attr("name", &busName); err != nil {
-				return err
-			}
-
-			content, err := generateDBusActivationFile(app, busName)
-			if err != nil {
-				return err
-			}
-			filename := busName + ".service"
-			fileState := &osutil.MemoryFileState{
-				Content: content,
-				Mode:    0644,
-			}
-			switch app.DaemonScope {
-			case snap.SystemDaemon:
-				systemContent[filename] = fileState
-				systemServices = append(systemServices, filename)
-			case snap.UserDaemon:
-				sessionContent[filename] = fileState
-				sessionServices = append(sessionServices, filename)
-			}
+		filename := name.Name + ".service"
+		fileState := &osutil.MemoryFileState{
+			Content: content,
+			Mode:    0644,
+		}
+		switch name.Bus {
+		case "system":
+			systemContent[filename] = fileState
+			systemServices = append(systemServices, filename)
+		case "session":
+			sessionContent[filename] = fileState
+			sessionServices = append(sessionServices, filename)
 		}
 	}
 
@@ -22,6 +22,7 @@ package wrappers_test
 import (
 	"os"
 	"path/filepath"
+	"sort"
 
 	. "gopkg.in/check.v1"
 
@@ -213,3 +214,50 @@ apps:
 	c.Check(err, IsNil)
 	c.Check(matches, HasLen, 0)
 }
+
+func (s *dbusTestSuite) TestSnapDBusActivationNames(c *C) {
+	info := snaptest.MockSnap(c, dbusSnapYaml, &snap.SideInfo{Revision: snap.R(12)})
+
+	names, err := wrappers.SnapDBusActivationNames(info)
+	c.Assert(err, IsNil)
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Bus != names[j].Bus {
+			return names[i].Bus < names[j].Bus
+		}
+		return names[i].Name < names[j].Name
+	})
+	c.Check(names, DeepEquals, []wrappers.DBusActivationName{
+		{Name: "org.example.Bar", Bus: "session", App: info.Apps["session-svc"]},
+		{Name: "org.example.Foo", Bus: "session", App: info.Apps["session-svc"]},
+		{Name: "org.example.Bar", Bus: "system", App: info.Apps["system-svc"]},
+		{Name: "org.example.Foo", Bus: "system", App: info.Apps["system-svc"]},
+	})
+}
+
+func (s *dbusTestSuite) TestHostDBusActivationFile(c *C) {
+	c.Check(wrappers.HostDBusActivationFile("system", "org.example.Foo"), Equals, "")
+
+	for _, t := range []struct {
+		bus, dir string
+	}{
+		{"system", "/usr/share/dbus-1/system-services"},
+		{"system", "/usr/local/share/dbus-1/system-services"},
+		{"system", "/lib/dbus-1/system-services"},
+		{"session", "/usr/share/dbus-1/services"},
+		{"session", "/usr/local/share/dbus-1/services"},
+	} {
+		hostDir := filepath.Join(s.tempdir, t.dir)
+		c.Assert(os.MkdirAll(hostDir, 0755), IsNil)
+		path := filepath.Join(hostDir, "org.example.Foo.service")
+		c.Assert(os.WriteFile(path, nil, 0644), IsNil)
+		c.Check(wrappers.HostDBusActivationFile(t.bus, "org.example.Foo"), Equals, path)
+		c.Check(wrappers.HostDBusActivationFile(t.bus, "org.example.Bar"), Equals, "")
+		c.Assert(os.Remove(path), IsNil)
+	}
+
+	// activation files of snaps are not from the host
+	info := snaptest.MockSnap(c, dbusSnapYaml, &snap.SideInfo{Revision: snap.R(12)})
+	c.Assert(wrappers.AddSnapDBusActivationFiles(info), IsNil)
+	c.Check(wrappers.HostDBusActivationFile("system", "org.example.Foo"), Equals, "")
+	c.Check(wrappers.HostDBusActivationFile("session", "org.example.Foo"), Equals, "")
+}
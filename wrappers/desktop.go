@@ -34,6 +34,7 @@ import (
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
 )
 
 // From the freedesktop Desktop Entry Specification¹,
@@ -130,13 +131,8 @@ func rewriteExecLine(s *snap.Info, desktopFile, line string) (string, error) {
 
 	logger.Noticef("cannot use line %q for desktop file %q (snap %s)", line, desktopFile, s.InstanceName())
 	// The Exec= line in the desktop file is invalid. Instead of failing
-	// hard we rewrite the Exec= line. The convention is that the desktop
-	// file has the same name as the application we can use this fact here.
-	df := filepath.Base(desktopFile)
-	desktopFileApp := strings.TrimSuffix(df, filepath.Ext(df))
-	app, ok := s.Apps[desktopFileApp]
-	if ok {
-		newExec := fmt.Sprintf("Exec=%s%s", env, app.WrapperPath())
+	// hard we rewrite the Exec= line.
+	if newExec, ok := desktopFileAppExecLine(s, desktopFile); ok {
 		logger.Noticef("rewriting desktop file %q to %q", desktopFile, newExec)
 		return newExec, nil
 	}
@@ -144,6 +140,25 @@ func rewriteExecLine(s *snap.Info, desktopFile, line string) (string, error) {
 	return "", fmt.Errorf("invalid exec command: %q", cmd)
 }
 
+// desktopFileAppExecLine returns an "Exec=" line for the application the
+// desktop file is named after. The convention is that the desktop file has
+// the same name as the application.
+func desktopFileAppExecLine(s *snap.Info, desktopFile string) (string, bool) {
+	df := filepath.Base(desktopFile)
+	desktopFileApp := strings.TrimSuffix(df, filepath.Ext(df))
+	desktopFileApp = strings.TrimPrefix(desktopFileApp, s.DesktopPrefix()+"_")
+	app, ok := s.Apps[desktopFileApp]
+	if !ok {
+		return "", false
+	}
+	env := fmt.Sprintf("env BAMF_DESKTOP_FILE_HINT=%s ", desktopFile)
+	return fmt.Sprintf("Exec=%s%s", env, app.WrapperPath()), true
+}
+
+// iconFileExtensions are the icon file formats supported by the icon theme
+// specification.
+var iconFileExtensions = []string{".png", ".svg", ".xpm"}
+
 func rewriteIconLine(s *snap.Info, line string) (string, error) {
 	icon := strings.SplitN(line, "=", 2)[1]
 
@@ -155,9 +170,17 @@ func rewriteIconLine(s *snap.Info, line string) (string, error) {
 		if filepath.Clean(icon) != icon {
 			return "", fmt.Errorf("icon path %q is not canonicalized, did you mean %q?", icon, filepath.Clean(icon))
 		}
+		if ext := filepath.Ext(icon); ext != "" && !strutil.ListContains(iconFileExtensions, ext) {
+			return "", fmt.Errorf("icon path %q has unsupported format %q", icon, ext)
+		}
 		return line, nil
 	}
 
+	// Icon theme names are looked up without their extension.
+	if strutil.ListContains(iconFileExtensions, filepath.Ext(icon)) {
+		return "", fmt.Errorf("invalid icon name: %q, must not include the file extension", icon)
+	}
+
 	// If the icon is prefixed with "snap.${SNAP_NAME}.", rewrite
 	// to the instance name.
 	snapIconPrefix := fmt.Sprintf("snap.%s.", s.SnapName())
@@ -174,26 +197,77 @@ func rewriteIconLine(s *snap.Info, line string) (string, error) {
 	return line, nil
 }
 
+var desktopActionHeader = regexp.MustCompile(`^\[Desktop Action ([0-9A-Za-z-]+)\]$`)
+
+// desktopFileGroup holds the sanitized lines of one group of a desktop file.
+type desktopFileGroup struct {
+	lines [][]byte
+	// action is set for "[Desktop Action <action>]" groups.
+	action string
+	// hasExec is set when the group has a usable Exec= line, invalidExec
+	// when an Exec= line of the group had to be dropped.
+	hasExec     bool
+	invalidExec bool
+	// dbusActivatable is set for a "[Desktop Entry]" group that asked to
+	// be activated over D-Bus.
+	dbusActivatable bool
+}
+
+// filterActionsLine removes the dropped actions from an "Actions=" line. It
+// returns nil if no actions are left.
+func filterActionsLine(line []byte, dropped map[string]bool) []byte {
+	var actions []string
+	for _, action := range strings.Split(string(line[len("Actions="):]), ";") {
+		if action != "" && !dropped[action] {
+			actions = append(actions, action)
+		}
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+	return []byte("Actions=" + strings.Join(actions, ";") + ";")
+}
+
 func sanitizeDesktopFile(s *snap.Info, desktopFile string, rawcontent []byte) []byte {
-	var newContent bytes.Buffer
 	mountDir := []byte(s.MountDir())
+	group := &desktopFileGroup{}
+	groups := []*desktopFileGroup{group}
 	scanner := bufio.NewScanner(bytes.NewReader(rawcontent))
 	for i := 0; scanner.Scan(); i++ {
 		bline := scanner.Bytes()
 
+		// The installed desktop file is renamed, so its name no longer
+		// matches the D-Bus name of the application and D-Bus
+		// activation cannot work. The key is dropped and launchers fall
+		// back to the Exec= line instead.
+		if bytes.Equal(bline, []byte("DBusActivatable=true")) {
+			group.dbusActivatable = true
+			continue
+		}
+
 		if !isValidDesktopFileLine(bline) {
 			logger.Debugf("ignoring line %d (%q) in source of desktop file %q", i, bline, filepath.Base(desktopFile))
 			continue
 		}
 
+		if bytes.HasPrefix(bline, []byte("[")) {
+			group = &desktopFileGroup{}
+			if m := desktopActionHeader.FindSubmatch(bline); m != nil {
+				group.action = string(m[1])
+			}
+			groups = append(groups, group)
+		}
+
 		// rewrite exec lines to an absolute path for the binary
 		if bytes.HasPrefix(bline, []byte("Exec=")) {
 			var err error
 			line, err := rewriteExecLine(s, desktopFile, string(bline))
 			if err != nil {
 				// something went wrong, ignore the line
+				group.invalidExec = true
 				continue
 			}
+			group.hasExec = true
 			bline = []byte(line)
 		}
 
@@ -209,14 +283,44 @@ func sanitizeDesktopFile(s *snap.Info, desktopFile string, rawcontent []byte) []
 
 		// do variable substitution
 		bline = bytes.Replace(bline, []byte("${SNAP}"), mountDir, -1)
+		group.lines = append(group.lines, bline)
+	}
 
-		newContent.Grow(len(bline) + 1)
-		newContent.Write(bline)
-		newContent.WriteByte('\n')
+	// actions which cannot be launched are dropped altogether
+	dropped := make(map[string]bool)
+	for _, g := range groups {
+		if g.action != "" && g.invalidExec && !g.hasExec {
+			logger.Noticef("dropping action %q from desktop file %q", g.action, filepath.Base(desktopFile))
+			dropped[g.action] = true
+		}
+	}
 
-		// insert snap name
-		if bytes.Equal(bline, []byte("[Desktop Entry]")) {
+	var newContent bytes.Buffer
+	for _, g := range groups {
+		if dropped[g.action] {
+			continue
+		}
+		for _, bline := range g.lines {
+			if len(dropped) > 0 && bytes.HasPrefix(bline, []byte("Actions=")) {
+				if bline = filterActionsLine(bline, dropped); bline == nil {
+					continue
+				}
+			}
+
+			newContent.Grow(len(bline) + 1)
+			newContent.Write(bline)
+			newContent.WriteByte('\n')
+
+			if !bytes.Equal(bline, []byte("[Desktop Entry]")) {
+				continue
+			}
+			// insert snap name
 			newContent.Write([]byte("X-SnapInstanceName=" + s.InstanceName() + "\n"))
+			if g.dbusActivatable && !g.hasExec {
+				if line, ok := desktopFileAppExecLine(s, desktopFile); ok {
+					newContent.Write([]byte(line + "\n"))
+				}
+			}
 		}
 	}
 
@@ -332,3 +436,14 @@ func RemoveSnapDesktopFiles(s *snap.Info) error {
 
 	return nil
 }
+
+// InstalledSnapDesktopFiles returns the desktop files installed for the
+// applications of the snap.
+func InstalledSnapDesktopFiles(s *snap.Info) ([]string, error) {
+	desktopFilesGlob := fmt.Sprintf("%s_*.desktop", s.DesktopPrefix())
+	desktopFiles, err := filepath.Glob(filepath.Join(dirs.SnapDesktopFilesDir, desktopFilesGlob))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get desktop files of snap %q: %s", s.InstanceName(), err)
+	}
+	return desktopFiles, nil
+}
@@ -551,6 +551,12 @@ version: 1.0
 
 	_, err = wrappers.RewriteIconLine(snap, "Icon=${SNAP}/../outside/icon.png")
 	c.Check(err, ErrorMatches, `icon path "\${SNAP}/../outside/icon.png" is not canonicalized, did you mean "outside/icon.png"\?`)
+
+	_, err = wrappers.RewriteIconLine(snap, "Icon=${SNAP}/icon.bmp")
+	c.Check(err, ErrorMatches, `icon path "\${SNAP}/icon.bmp" has unsupported format ".bmp"`)
+
+	_, err = wrappers.RewriteIconLine(snap, "Icon=snap.snap.icon.png")
+	c.Check(err, ErrorMatches, `invalid icon name: "snap.snap.icon.png", must not include the file extension`)
 }
 
 func (s *sanitizeDesktopFileSuite) TestSanitizeParallelInstancesIconName(c *C) {
@@ -578,6 +584,131 @@ Exec=env BAMF_DESKTOP_FILE_HINT=snap+bar_app.desktop %s/bin/snap_bar.app
 `, dirs.SnapMountDir))
 }
 
+func (s *sanitizeDesktopFileSuite) TestSanitizeDesktopActionsRewritesExec(c *C) {
+	snap, err := snap.InfoFromSnapYaml([]byte(`
+name: snap
+version: 1.0
+apps:
+ app:
+  command: cmd
+`))
+	c.Assert(err, IsNil)
+	desktopContent := []byte(`[Desktop Entry]
+Name=foo
+Exec=snap.app
+Actions=new-window;evil;
+
+[Desktop Action new-window]
+Name=New Window
+Exec=snap.app --new-window
+
+[Desktop Action evil]
+Name=Evil
+Exec=/bin/evil
+`)
+
+	e := wrappers.SanitizeDesktopFile(snap, "foo.desktop", desktopContent)
+	c.Assert(string(e), Equals, fmt.Sprintf(`[Desktop Entry]
+X-SnapInstanceName=snap
+Name=foo
+Exec=env BAMF_DESKTOP_FILE_HINT=foo.desktop %[1]s/bin/snap.app
+Actions=new-window;
+
+[Desktop Action new-window]
+Name=New Window
+Exec=env BAMF_DESKTOP_FILE_HINT=foo.desktop %[1]s/bin/snap.app --new-window
+
+`, dirs.SnapMountDir))
+}
+
+func (s *sanitizeDesktopFileSuite) TestSanitizeDesktopActionsAllDropped(c *C) {
+	snap, err := snap.InfoFromSnapYaml([]byte(`
+name: snap
+version: 1.0
+apps:
+ app:
+  command: cmd
+`))
+	c.Assert(err, IsNil)
+	desktopContent := []byte(`[Desktop Entry]
+Name=foo
+Exec=snap.app
+Actions=evil;
+
+[Desktop Action evil]
+Name=Evil
+Exec=/bin/evil
+`)
+
+	e := wrappers.SanitizeDesktopFile(snap, "foo.desktop", desktopContent)
+	c.Assert(string(e), Equals, fmt.Sprintf(`[Desktop Entry]
+X-SnapInstanceName=snap
+Name=foo
+Exec=env BAMF_DESKTOP_FILE_HINT=foo.desktop %s/bin/snap.app
+
+`, dirs.SnapMountDir))
+}
+
+func (s *sanitizeDesktopFileSuite) TestSanitizeDBusActivatable(c *C) {
+	snap, err := snap.InfoFromSnapYaml([]byte(`
+name: snap
+version: 1.0
+apps:
+ app:
+  command: cmd
+`))
+	c.Assert(err, IsNil)
+	desktopContent := []byte(`[Desktop Entry]
+Name=foo
+DBusActivatable=true
+`)
+
+	// the desktop file named after the app gets a launchable Exec= line
+	e := wrappers.SanitizeDesktopFile(snap, "snap_app.desktop", desktopContent)
+	c.Assert(string(e), Equals, fmt.Sprintf(`[Desktop Entry]
+X-SnapInstanceName=snap
+Exec=env BAMF_DESKTOP_FILE_HINT=snap_app.desktop %s/bin/snap.app
+Name=foo
+`, dirs.SnapMountDir))
+
+	// an explicit Exec= line is preferred
+	desktopContent = []byte(`[Desktop Entry]
+Name=foo
+DBusActivatable=true
+Exec=snap.app %U
+`)
+	e = wrappers.SanitizeDesktopFile(snap, "snap_app.desktop", desktopContent)
+	c.Assert(string(e), Equals, fmt.Sprintf(`[Desktop Entry]
+X-SnapInstanceName=snap
+Name=foo
+Exec=env BAMF_DESKTOP_FILE_HINT=snap_app.desktop %s/bin/snap.app %%U
+`, dirs.SnapMountDir))
+
+	// no app to launch, the key is still dropped
+	e = wrappers.SanitizeDesktopFile(snap, "snap_other.desktop", []byte("[Desktop Entry]\nName=foo\nDBusActivatable=true\n"))
+	c.Assert(string(e), Equals, "[Desktop Entry]\nX-SnapInstanceName=snap\nName=foo\n")
+}
+
+func (s *desktopSuite) TestInstalledSnapDesktopFiles(c *C) {
+	info := snaptest.MockSnap(c, desktopAppYaml, &snap.SideInfo{Revision: snap.R(11)})
+
+	files, err := wrappers.InstalledSnapDesktopFiles(info)
+	c.Assert(err, IsNil)
+	c.Check(files, HasLen, 0)
+
+	c.Assert(os.MkdirAll(dirs.SnapDesktopFilesDir, 0755), IsNil)
+	for _, name := range []string{"foo_app.desktop", "foo_other.desktop", "foo+instance_app.desktop", "bar_app.desktop"} {
+		c.Assert(os.WriteFile(filepath.Join(dirs.SnapDesktopFilesDir, name), nil, 0644), IsNil)
+	}
+
+	files, err = wrappers.InstalledSnapDesktopFiles(info)
+	c.Assert(err, IsNil)
+	c.Check(files, DeepEquals, []string{
+		filepath.Join(dirs.SnapDesktopFilesDir, "foo_app.desktop"),
+		filepath.Join(dirs.SnapDesktopFilesDir, "foo_other.desktop"),
+	})
+}
+
 func (s *desktopSuite) TestAddRemoveDesktopFiles(c *C) {
 	var tests = []struct {
 		instance                string